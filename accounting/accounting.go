@@ -0,0 +1,250 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// EntryType is the kind of financial event an entry of the ledger records.
+type EntryType uint8
+
+const (
+	// EntryOnChain is an on-chain transaction of the wallet.
+	EntryOnChain EntryType = iota
+
+	// EntryForward is the fee earned by forwarding an HTLC.
+	EntryForward
+
+	// EntryInvoice is a payment received to one of our invoices.
+	EntryInvoice
+
+	// EntryPayment is a payment we sent.
+	EntryPayment
+)
+
+// String returns the name the entry type is exported under.
+func (t EntryType) String() string {
+	switch t {
+	case EntryOnChain:
+		return "on_chain"
+	case EntryForward:
+		return "forward"
+	case EntryInvoice:
+		return "invoice"
+	case EntryPayment:
+		return "payment"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON encodes the entry type as its name.
+func (t EntryType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// Entry is a single financial event within the ledger.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      EntryType `json:"type"`
+
+	// Reference identifies the event: the txid of an on-chain
+	// transaction, the payment hash of an invoice or payment, or the
+	// channels an HTLC was forwarded between.
+	Reference string `json:"reference"`
+
+	// AmountMsat is the change in our balance, positive if we were
+	// credited, and negative if we were debited. It excludes any fee.
+	AmountMsat int64 `json:"amount_msat"`
+
+	// FeeMsat is the fee we paid on top of the amount.
+	FeeMsat int64 `json:"fee_msat"`
+
+	// Note is a free form description of the event.
+	Note string `json:"note,omitempty"`
+}
+
+// Ledger is the financial events of the node, in the order they occurred.
+type Ledger []*Entry
+
+// NewLedger collates the entries of each source into a single ledger,
+// ordered by time.
+func NewLedger(sources ...[]*Entry) Ledger {
+	var ledger Ledger
+	for _, entries := range sources {
+		ledger = append(ledger, entries...)
+	}
+
+	sort.Stable(ledger)
+	return ledger
+}
+
+func (l Ledger) Len() int           { return len(l) }
+func (l Ledger) Less(i, j int) bool { return l[i].Timestamp.Before(l[j].Timestamp) }
+func (l Ledger) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+
+// Filter returns the entries within the time range, inclusive of start and
+// exclusive of end, of the passed types. A zero end places no limit on the
+// range, while no types includes every type.
+func (l Ledger) Filter(start, end time.Time, types ...EntryType) Ledger {
+	include := make(map[EntryType]struct{}, len(types))
+	for _, t := range types {
+		include[t] = struct{}{}
+	}
+
+	var filtered Ledger
+	for _, entry := range l {
+		if entry.Timestamp.Before(start) ||
+			(!end.IsZero() && !entry.Timestamp.Before(end)) {
+
+			continue
+		}
+		if _, ok := include[entry.Type]; !ok && len(include) != 0 {
+			continue
+		}
+
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
+
+// csvHeader is the header row of the CSV export.
+var csvHeader = []string{
+	"timestamp", "type", "reference", "amount_msat", "fee_msat", "note",
+}
+
+// WriteCSV writes the ledger to w as CSV, with a header row. Timestamps are
+// written in RFC 3339 format, in UTC.
+func (l Ledger) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range l {
+		err := csvWriter.Write([]string{
+			entry.Timestamp.UTC().Format(time.RFC3339),
+			entry.Type.String(),
+			entry.Reference,
+			strconv.FormatInt(entry.AmountMsat, 10),
+			strconv.FormatInt(entry.FeeMsat, 10),
+			entry.Note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// WriteJSON writes the ledger to w as a JSON array of entries.
+func (l Ledger) WriteJSON(w io.Writer) error {
+	entries := l
+	if entries == nil {
+		entries = Ledger{}
+	}
+
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// InvoiceEntries returns an entry for each payment received to the
+// invoices: the invoice itself once settled, or each settled HTLC set of an
+// AMP invoice.
+func InvoiceEntries(invoices []*channeldb.Invoice) []*Entry {
+	var entries []*Entry
+	for _, invoice := range invoices {
+		paymentHash := invoice.PaymentHash()
+		reference := hex.EncodeToString(paymentHash[:])
+
+		if !invoice.AMP {
+			if invoice.State != channeldb.InvoiceSettled {
+				continue
+			}
+			entries = append(entries, &Entry{
+				Timestamp:  invoice.StateChangeDate,
+				Type:       EntryInvoice,
+				Reference:  reference,
+				AmountMsat: int64(invoice.Value),
+				Note:       invoice.Memo,
+			})
+			continue
+		}
+
+		for setID, htlcSet := range invoice.HTLCSets {
+			if htlcSet.State != channeldb.InvoiceSettled {
+				continue
+			}
+			entries = append(entries, &Entry{
+				Timestamp:  htlcSet.StateChangeDate,
+				Type:       EntryInvoice,
+				Reference:  reference,
+				AmountMsat: int64(htlcSet.Total()),
+				Note: fmt.Sprintf("%v (set %x)", invoice.Memo,
+					setID[:]),
+			})
+		}
+	}
+
+	return entries
+}
+
+// PaymentEntries returns an entry for each of the payments which succeeded,
+// debiting the amount paid, and the fees of each of its settled parts.
+func PaymentEntries(payments []*channeldb.Payment) []*Entry {
+	var entries []*Entry
+	for _, payment := range payments {
+		if payment.Status != channeldb.PaymentSucceeded {
+			continue
+		}
+
+		var fee int64
+		timestamp := payment.CreationTime
+		for _, attempt := range payment.Attempts {
+			if attempt.Status != channeldb.PaymentSucceeded {
+				continue
+			}
+			fee += int64(attempt.Fee)
+			if attempt.AttemptTime.After(timestamp) {
+				timestamp = attempt.AttemptTime
+			}
+		}
+
+		entries = append(entries, &Entry{
+			Timestamp:  timestamp,
+			Type:       EntryPayment,
+			Reference:  hex.EncodeToString(payment.PaymentHash[:]),
+			AmountMsat: -int64(payment.Amount),
+			FeeMsat:    fee,
+		})
+	}
+
+	return entries
+}
+
+// ForwardEntries returns an entry for each forwarded HTLC, crediting the fee
+// it earned us.
+func ForwardEntries(events []*channeldb.ForwardingEvent) []*Entry {
+	entries := make([]*Entry, 0, len(events))
+	for _, event := range events {
+		entries = append(entries, &Entry{
+			Timestamp: event.Timestamp,
+			Type:      EntryForward,
+			Reference: fmt.Sprintf("%v->%v", event.IncomingChanID,
+				event.OutgoingChanID),
+			AmountMsat: int64(event.Fee()),
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,172 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var start = time.Unix(1500000000, 0)
+
+func testLedger() Ledger {
+	invoices := []*channeldb.Invoice{
+		{
+			Memo:            "coffee",
+			Value:           5000,
+			State:           channeldb.InvoiceSettled,
+			StateChangeDate: start.Add(2 * time.Hour),
+		},
+		{
+			Value:           7000,
+			State:           channeldb.InvoiceOpen,
+			StateChangeDate: start,
+		},
+	}
+
+	payments := []*channeldb.Payment{
+		{
+			Amount:       10000,
+			CreationTime: start,
+			Status:       channeldb.PaymentSucceeded,
+			Attempts: []*channeldb.PaymentAttempt{
+				{
+					Status:      channeldb.PaymentFailed,
+					AttemptTime: start,
+					Fee:         50,
+				},
+				{
+					Status:      channeldb.PaymentSucceeded,
+					AttemptTime: start.Add(time.Minute),
+					Fee:         20,
+				},
+			},
+		},
+		{
+			Amount:       20000,
+			CreationTime: start,
+			Status:       channeldb.PaymentFailed,
+		},
+	}
+
+	forwards := []*channeldb.ForwardingEvent{
+		{
+			Timestamp:      start.Add(time.Hour),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+			AmtIn:          10030,
+			AmtOut:         10000,
+		},
+	}
+
+	onChain := []*Entry{
+		{
+			Timestamp:  start.Add(-time.Hour),
+			Type:       EntryOnChain,
+			Reference:  "txid",
+			AmountMsat: 1000000,
+		},
+	}
+
+	return NewLedger(onChain, InvoiceEntries(invoices),
+		PaymentEntries(payments), ForwardEntries(forwards))
+}
+
+func TestLedger(t *testing.T) {
+	ledger := testLedger()
+
+	// Only the settled invoice and succeeded payment make their way into
+	// the ledger, which is ordered by time.
+	expected := []struct {
+		entryType EntryType
+		amount    int64
+		fee       int64
+	}{
+		{EntryOnChain, 1000000, 0},
+		{EntryPayment, -10000, 20},
+		{EntryForward, 30, 0},
+		{EntryInvoice, 5000, 0},
+	}
+	if len(ledger) != len(expected) {
+		t.Fatalf("expected %v entries, got %v", len(expected),
+			len(ledger))
+	}
+	for i, entry := range ledger {
+		if entry.Type != expected[i].entryType ||
+			entry.AmountMsat != expected[i].amount ||
+			entry.FeeMsat != expected[i].fee {
+
+			t.Fatalf("entry %v doesn't match: %v", i, entry)
+		}
+	}
+
+	// The payment is timestamped at the time of its settled attempt.
+	if !ledger[1].Timestamp.Equal(start.Add(time.Minute)) {
+		t.Fatalf("payment has the wrong timestamp: %v",
+			ledger[1].Timestamp)
+	}
+
+	filtered := ledger.Filter(start, start.Add(2*time.Hour))
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 entries, got %v", len(filtered))
+	}
+
+	filtered = ledger.Filter(time.Time{}, time.Time{}, EntryInvoice,
+		EntryOnChain)
+	if len(filtered) != 2 || filtered[0].Type != EntryOnChain ||
+		filtered[1].Type != EntryInvoice {
+
+		t.Fatalf("filtered on the wrong types: %v", filtered)
+	}
+}
+
+func TestLedgerExport(t *testing.T) {
+	ledger := testLedger()
+
+	var b bytes.Buffer
+	if err := ledger.WriteCSV(&b); err != nil {
+		t.Fatalf("unable to write csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != len(ledger)+1 {
+		t.Fatalf("expected %v lines, got %v", len(ledger)+1,
+			len(lines))
+	}
+	if lines[0] != strings.Join(csvHeader, ",") {
+		t.Fatalf("unexpected header: %v", lines[0])
+	}
+	if !strings.HasPrefix(lines[3], "2017-07-14T03:40:00Z,forward,") ||
+		!strings.HasSuffix(lines[3], ",30,0,") {
+
+		t.Fatalf("unexpected forward row: %v", lines[3])
+	}
+
+	b.Reset()
+	if err := ledger.WriteJSON(&b); err != nil {
+		t.Fatalf("unable to write json: %v", err)
+	}
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &entries); err != nil {
+		t.Fatalf("unable to parse json: %v", err)
+	}
+	if len(entries) != len(ledger) {
+		t.Fatalf("expected %v entries, got %v", len(ledger),
+			len(entries))
+	}
+	if entries[3]["type"] != "invoice" || entries[3]["note"] != "coffee" {
+		t.Fatalf("unexpected invoice entry: %v", entries[3])
+	}
+
+	// An empty ledger is exported as an empty array, rather than null.
+	b.Reset()
+	if err := Ledger(nil).WriteJSON(&b); err != nil {
+		t.Fatalf("unable to write json: %v", err)
+	}
+	if strings.TrimSpace(b.String()) != "[]" {
+		t.Fatalf("expected empty array, got %v", b.String())
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// aliasManager hands out alias short channel IDs for zero-conf channels.
+// Until the funding transaction of a zero-conf channel confirms, the channel
+// has no real short channel ID, so HTLCs are forwarded over it via its alias
+// instead. Once the funding transaction confirms, the alias is mapped to the
+// real short channel ID, allowing HTLCs forwarded using either to reach the
+// channel.
+type aliasManager struct {
+	cdb *channeldb.DB
+
+	sync.RWMutex
+
+	// baseScids maps each alias to the short channel ID HTLCs forwarded
+	// via the alias should be sent over. Until the channel confirms, an
+	// alias maps to itself.
+	baseScids map[lnwire.ShortChannelID]lnwire.ShortChannelID
+}
+
+// newAliasManager creates a new alias manager backed by the passed database.
+func newAliasManager(cdb *channeldb.DB) *aliasManager {
+	return &aliasManager{
+		cdb:       cdb,
+		baseScids: make(map[lnwire.ShortChannelID]lnwire.ShortChannelID),
+	}
+}
+
+// RequestAlias allocates a fresh alias for a new zero-conf channel.
+func (a *aliasManager) RequestAlias() (lnwire.ShortChannelID, error) {
+	index, err := a.cdb.NextAliasIndex()
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+
+	base := lnwire.ShortChannelID{BlockHeight: lnwire.AliasStartBlockHeight}
+	alias := lnwire.NewShortChanIDFromInt(base.ToUint64() + index)
+	if !alias.IsAlias() {
+		return lnwire.ShortChannelID{}, fmt.Errorf("alias range exhausted")
+	}
+
+	a.Lock()
+	a.baseScids[alias] = alias
+	a.Unlock()
+
+	return alias, nil
+}
+
+// ConfirmChannel records the real short channel ID of the channel known by
+// the passed alias, once its funding transaction has confirmed. Any HTLCs
+// forwarded via the alias are from now on sent over the confirmed channel.
+func (a *aliasManager) ConfirmChannel(alias,
+	realScid lnwire.ShortChannelID) error {
+
+	a.Lock()
+	defer a.Unlock()
+
+	if _, ok := a.baseScids[alias]; !ok {
+		return fmt.Errorf("unknown alias %v", alias)
+	}
+	a.baseScids[alias] = realScid
+
+	return nil
+}
+
+// FindBaseScid returns the short channel ID HTLCs forwarded to the passed
+// short channel ID should be sent over. Short channel IDs outside of the
+// alias range are returned as is.
+func (a *aliasManager) FindBaseScid(
+	scid lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	if !scid.IsAlias() {
+		return scid, nil
+	}
+
+	a.RLock()
+	defer a.RUnlock()
+
+	base, ok := a.baseScids[scid]
+	if !ok {
+		return lnwire.ShortChannelID{}, fmt.Errorf("unknown alias %v", scid)
+	}
+
+	return base, nil
+}
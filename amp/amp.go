@@ -0,0 +1,135 @@
+package amp
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/fastsha256"
+)
+
+// AMP (atomic multi-path) payments split a single payment into several
+// HTLCs, each locked to a distinct payment hash. The sender picks a random
+// root seed, and splits it into a set of shares which XOR back to the root.
+// Each HTLC carries one share along with its index within the set. Only once
+// the receiver holds every share can it reconstruct the root seed, and derive
+// the preimages required to settle the HTLCs, so either all of the HTLCs are
+// settled, or none of them are:
+//
+//   root = share_0 ^ share_1 ^ ... ^ share_n
+//   preimage_i = sha256(root || i)[:20]
+//   hash_i = hash160(preimage_i)
+//
+// As each payment uses a fresh root seed, a single AMP invoice can be paid
+// many times over.
+
+// Share is a single share of a root seed.
+type Share [32]byte
+
+// Xor returns the XOR of the share with the passed share.
+func (s Share) Xor(other Share) Share {
+	var result Share
+	for i := range s {
+		result[i] = s[i] ^ other[i]
+	}
+	return result
+}
+
+// SetID identifies the set of HTLCs making up a single AMP payment. It's
+// derived from the root seed of the payment.
+type SetID [32]byte
+
+// Child is a single HTLC of an AMP payment.
+type Child struct {
+	// Share is the share of the root seed carried by the HTLC.
+	Share Share
+
+	// Index is the index of the HTLC within the set.
+	Index uint32
+
+	// Preimage settles the HTLC. It's known to the receiver only once
+	// all shares have arrived.
+	Preimage [20]byte
+
+	// Hash is the payment hash the HTLC is locked to.
+	Hash [20]byte
+}
+
+// DeriveChild derives the child at the passed index given the root seed of
+// the payment.
+func DeriveChild(root Share, share Share, index uint32) *Child {
+	var indexBytes [4]byte
+	binary.BigEndian.PutUint32(indexBytes[:], index)
+
+	preimageSeed := fastsha256.Sum256(append(root[:], indexBytes[:]...))
+
+	child := &Child{
+		Share: share,
+		Index: index,
+	}
+	copy(child.Preimage[:], preimageSeed[:20])
+	copy(child.Hash[:], btcutil.Hash160(child.Preimage[:]))
+
+	return child
+}
+
+// NewSetID returns the set ID of the payment with the passed root seed.
+func NewSetID(root Share) SetID {
+	return SetID(fastsha256.Sum256(root[:]))
+}
+
+// NewRoot generates a fresh random root seed for a new payment.
+func NewRoot() (Share, error) {
+	var root Share
+	if _, err := rand.Read(root[:]); err != nil {
+		return root, err
+	}
+	return root, nil
+}
+
+// Split splits the root seed into numChildren children, each carrying a
+// single share of the root. This is used by the sender to construct the HTLCs
+// of a new payment.
+func Split(root Share, numChildren uint32) ([]*Child, error) {
+	if numChildren == 0 {
+		return nil, fmt.Errorf("payment must have at least one child")
+	}
+
+	// All shares but the last are random, with the final share chosen such
+	// that all the shares XOR back to the root.
+	shares := make([]Share, numChildren)
+	lastShare := root
+	for i := uint32(0); i < numChildren-1; i++ {
+		if _, err := rand.Read(shares[i][:]); err != nil {
+			return nil, err
+		}
+		lastShare = lastShare.Xor(shares[i])
+	}
+	shares[numChildren-1] = lastShare
+
+	children := make([]*Child, numChildren)
+	for i, share := range shares {
+		children[i] = DeriveChild(root, share, uint32(i))
+	}
+
+	return children, nil
+}
+
+// Reconstruct recovers the root seed from a complete set of shares, and
+// derives the children of the set. This is used by the receiver once all the
+// HTLCs of a payment have arrived. The index of each share within the slice
+// must match its child index.
+func Reconstruct(shares []Share) (Share, []*Child) {
+	var root Share
+	for _, share := range shares {
+		root = root.Xor(share)
+	}
+
+	children := make([]*Child, len(shares))
+	for i, share := range shares {
+		children[i] = DeriveChild(root, share, uint32(i))
+	}
+
+	return root, children
+}
@@ -0,0 +1,74 @@
+package amp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitReconstruct(t *testing.T) {
+	root, err := NewRoot()
+	if err != nil {
+		t.Fatalf("unable to generate root: %v", err)
+	}
+
+	children, err := Split(root, 5)
+	if err != nil {
+		t.Fatalf("unable to split root: %v", err)
+	}
+
+	// Each child should be locked to a distinct payment hash.
+	seenHashes := make(map[[20]byte]struct{})
+	for _, child := range children {
+		if _, ok := seenHashes[child.Hash]; ok {
+			t.Fatalf("duplicate child hash: %x", child.Hash)
+		}
+		seenHashes[child.Hash] = struct{}{}
+	}
+
+	// With every share in hand, the receiver should arrive at the same
+	// root and children as the sender.
+	shares := make([]Share, len(children))
+	for i, child := range children {
+		shares[i] = child.Share
+	}
+	newRoot, newChildren := Reconstruct(shares)
+	if newRoot != root {
+		t.Fatalf("reconstructed root doesn't match")
+	}
+	for i := range children {
+		if *children[i] != *newChildren[i] {
+			t.Fatalf("child %v doesn't match", i)
+		}
+	}
+
+	// Missing a single share, the receiver shouldn't be able to derive
+	// the preimages.
+	partialRoot, partialChildren := Reconstruct(shares[:len(shares)-1])
+	if partialRoot == root {
+		t.Fatalf("root reconstructed from partial set")
+	}
+	if bytes.Equal(partialChildren[0].Preimage[:], children[0].Preimage[:]) {
+		t.Fatalf("preimage derived from partial set")
+	}
+
+	if NewSetID(root) != NewSetID(newRoot) {
+		t.Fatalf("set ID mismatch")
+	}
+}
+
+func TestSplitSingleChild(t *testing.T) {
+	var root Share
+	root[0] = 1
+
+	children, err := Split(root, 1)
+	if err != nil {
+		t.Fatalf("unable to split root: %v", err)
+	}
+	if children[0].Share != root {
+		t.Fatalf("single share should equal the root")
+	}
+
+	if _, err := Split(root, 0); err == nil {
+		t.Fatalf("split into zero children should fail")
+	}
+}
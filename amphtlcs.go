@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/amp"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// heldHTLC is an HTLC of an AMP payment, held until the rest of its set
+// arrives.
+type heldHTLC struct {
+	peer       *peer
+	htlc       *lnwire.HTLCAddRequest
+	childIndex uint32
+}
+
+// ampHTLCs holds the HTLCs of the AMP payments we receive, across all of our
+// peers, as the preimage of each is only known once its set is complete.
+// Once the invoice registry reconstructs the root seed of a set, each of its
+// HTLCs is settled. Should the set be canceled instead, once its hold
+// passes, each of its HTLCs is failed.
+type ampHTLCs struct {
+	started  int32 // To be used atomically.
+	shutdown int32 // To be used atomically.
+
+	invoices *invoiceRegistry
+
+	// sets holds the HTLCs of each set which has yet to complete, guarded
+	// by mtx.
+	mtx  sync.Mutex
+	sets map[amp.SetID][]*heldHTLC
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newAMPHTLCs creates a holder of the HTLCs of AMP payments paying to the
+// invoices of the registry.
+func newAMPHTLCs(invoices *invoiceRegistry) *ampHTLCs {
+	return &ampHTLCs{
+		invoices: invoices,
+		sets:     make(map[amp.SetID][]*heldHTLC),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine failing the HTLCs of canceled sets.
+func (a *ampHTLCs) Start() error {
+	if atomic.AddInt32(&a.started, 1) != 1 {
+		return nil
+	}
+
+	a.wg.Add(1)
+	go a.cancelWatcher(a.invoices.SubscribeNotifications())
+
+	return nil
+}
+
+// Stop signals the cancel watcher to exit, and waits for it to do so. HTLCs
+// still held are left unresolved.
+func (a *ampHTLCs) Stop() error {
+	if atomic.AddInt32(&a.shutdown, 1) != 1 {
+		return nil
+	}
+
+	close(a.quit)
+	a.wg.Wait()
+
+	return nil
+}
+
+// acceptHTLC hands the HTLC, offered to us by the peer, to the invoice
+// registry as a part of the AMP payment its record identifies. The HTLC is
+// held until its set either completes, or is canceled, unless the registry
+// rejects it outright.
+func (a *ampHTLCs) acceptHTLC(p *peer, htlc *lnwire.HTLCAddRequest,
+	payload *lnwire.FinalHopPayload) {
+
+	record := payload.AMP
+	setID := amp.SetID(record.SetID)
+
+	// The HTLC is held before it's handed to the registry, so that it's
+	// settled along with the rest of the set should it complete the set.
+	held := &heldHTLC{
+		peer:       p,
+		htlc:       htlc,
+		childIndex: record.ChildIndex,
+	}
+	a.mtx.Lock()
+	a.sets[setID] = append(a.sets[setID], held)
+	a.mtx.Unlock()
+
+	preimages, err := a.invoices.AcceptAMPHTLC(record.InvoiceHash, setID,
+		&channeldb.InvoiceHTLC{
+			Share:      record.Share,
+			ChildIndex: record.ChildIndex,
+			Amount:     htlc.Amount,
+			AcceptTime: time.Now(),
+		}, payload)
+	switch {
+	case err != nil:
+		if a.release(setID, held) {
+			p.failHTLC(htlc, failCode(err))
+		}
+
+	// The set has yet to complete.
+	case preimages == nil:
+
+	default:
+		a.settleSet(setID, preimages)
+	}
+}
+
+// release removes the HTLC from its set, returning false if the set has
+// already been resolved.
+func (a *ampHTLCs) release(setID amp.SetID, held *heldHTLC) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	htlcs := a.sets[setID]
+	for i, h := range htlcs {
+		if h != held {
+			continue
+		}
+
+		htlcs = append(htlcs[:i], htlcs[i+1:]...)
+		if len(htlcs) == 0 {
+			delete(a.sets, setID)
+		} else {
+			a.sets[setID] = htlcs
+		}
+		return true
+	}
+
+	return false
+}
+
+// takeSet removes the HTLCs of the set, returning them.
+func (a *ampHTLCs) takeSet(setID amp.SetID) []*heldHTLC {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	htlcs := a.sets[setID]
+	delete(a.sets, setID)
+	return htlcs
+}
+
+// settleSet settles each HTLC of the completed set with the preimage of its
+// child index. An HTLC whose hash doesn't match its preimage lied about its
+// index, and is failed.
+func (a *ampHTLCs) settleSet(setID amp.SetID, preimages map[uint32][20]byte) {
+	for _, held := range a.takeSet(setID) {
+		preimage, ok := preimages[held.childIndex]
+		paymentHash := held.htlc.RedemptionHashes[0]
+		if !ok || !bytes.Equal(btcutil.Hash160(preimage[:]),
+			paymentHash[:]) {
+
+			held.peer.failHTLC(held.htlc,
+				lnwire.CodeIncorrectPaymentDetails)
+			continue
+		}
+
+		held.peer.queueMsg(&lnwire.HTLCSettleRequest{
+			ChannelID:        held.htlc.ChannelID,
+			HTLCKey:          held.htlc.HTLCKey,
+			RedemptionProofs: []*[20]byte{&preimage},
+		}, nil)
+	}
+}
+
+// cancelWatcher fails the HTLCs of each set the invoice registry cancels,
+// as the set failed to complete within its hold.
+//
+// NOTE: This MUST be run as a goroutine.
+func (a *ampHTLCs) cancelWatcher(sub *invoiceSubscription) {
+	defer a.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case event := <-sub.Events:
+			if event.setID == nil ||
+				event.state != channeldb.InvoiceCanceled {
+
+				continue
+			}
+
+			htlcs := a.takeSet(*event.setID)
+			if len(htlcs) != 0 {
+				fmt.Printf("failing %v htlcs of timed out amp "+
+					"set %x\n", len(htlcs), event.setID[:])
+			}
+			for _, held := range htlcs {
+				held.peer.failHTLC(held.htlc,
+					failCode(errMPPTimeout))
+			}
+
+		case <-a.quit:
+			return
+		}
+	}
+}
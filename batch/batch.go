@@ -0,0 +1,184 @@
+package batch
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// errSolo is sent to a request whose Update failed within a batch, signalling
+// that it's to be retried within a transaction of its own. This isolates the
+// failure from the other requests of the batch.
+var errSolo = errors.New("batch request to be run alone")
+
+// DB is the database batched transactions are run against, satisfied by a
+// walletdb.Namespace.
+type DB interface {
+	// Update runs the passed function within a read-write transaction,
+	// committing it if no error is returned.
+	Update(func(tx walletdb.Tx) error) error
+}
+
+// Request is a write to be run alongside others within a shared
+// transaction.
+type Request struct {
+	// Reset, if set, is called before each time Update is run. It undoes
+	// any changes to in-memory state made by a prior run of Update whose
+	// transaction was rolled back, due to another request of the batch
+	// failing.
+	Reset func()
+
+	// Update applies the request within the batch's transaction.
+	//
+	// NOTE: As it's run alongside other requests, Update MUST NOT
+	// acquire any mutexes.
+	Update func(tx walletdb.Tx) error
+
+	// OnCommit, if set, is called with the result of the transaction
+	// once it's committed, or rolled back. Its return value is returned
+	// to the caller of Execute.
+	OnCommit func(commitErr error) error
+}
+
+// Scheduler batches up requests into shared transactions.
+type Scheduler interface {
+	// Execute adds the request to the next batch, blocking until its
+	// transaction has been committed, or failed.
+	Execute(r *Request) error
+}
+
+// request is a Request within a batch, along with the channel its result is
+// sent on.
+type request struct {
+	*Request
+	errChan chan error
+}
+
+// batch is a set of requests to be run within a single transaction.
+type batch struct {
+	db    DB
+	reqs  []*request
+	clear func(b *batch)
+	start sync.Once
+}
+
+// trigger runs the batch, doing so only once however many times it's called.
+func (b *batch) trigger() {
+	b.start.Do(b.run)
+}
+
+// run applies each request of the batch within a single transaction. If any
+// request fails, it's removed from the batch to be run alone, with the rest
+// retried without it.
+func (b *batch) run() {
+	// No further requests may join the batch once it's running.
+	b.clear(b)
+
+	for len(b.reqs) > 0 {
+		failIdx := -1
+		err := b.db.Update(func(tx walletdb.Tx) error {
+			for i, req := range b.reqs {
+				if req.Reset != nil {
+					req.Reset()
+				}
+				if err := req.Update(tx); err != nil {
+					failIdx = i
+					return err
+				}
+			}
+			return nil
+		})
+
+		if failIdx >= 0 {
+			req := b.reqs[failIdx]
+			last := len(b.reqs) - 1
+			b.reqs[failIdx] = b.reqs[last]
+			b.reqs = b.reqs[:last]
+
+			req.errChan <- errSolo
+			continue
+		}
+
+		for _, req := range b.reqs {
+			req.errChan <- err
+		}
+		return
+	}
+}
+
+// TimeScheduler is a Scheduler which runs each batch once a fixed duration
+// has passed since its first request. Requests arriving within the duration
+// share the batch's transaction, so that many concurrent writes share a
+// single fsync.
+type TimeScheduler struct {
+	db       DB
+	duration time.Duration
+
+	mu sync.Mutex
+	b  *batch
+}
+
+// A compile time check to ensure TimeScheduler implements the Scheduler
+// interface.
+var _ Scheduler = (*TimeScheduler)(nil)
+
+// NewTimeScheduler creates a scheduler running batches against the passed
+// database, each once the passed duration has passed since its first request.
+func NewTimeScheduler(db DB, duration time.Duration) *TimeScheduler {
+	return &TimeScheduler{
+		db:       db,
+		duration: duration,
+	}
+}
+
+// Execute adds the request to the next batch, blocking until its
+// transaction has been committed, or failed.
+//
+// NOTE: This is part of the Scheduler interface.
+func (s *TimeScheduler) Execute(r *Request) error {
+	req := &request{
+		Request: r,
+		errChan: make(chan error, 1),
+	}
+
+	s.mu.Lock()
+	if s.b == nil {
+		s.b = &batch{
+			db:    s.db,
+			clear: s.clear,
+		}
+		time.AfterFunc(s.duration, s.b.trigger)
+	}
+	s.b.reqs = append(s.b.reqs, req)
+	s.mu.Unlock()
+
+	err := <-req.errChan
+
+	// A request which failed within the batch is run alone, so that its
+	// own error is returned.
+	if err == errSolo {
+		err = s.db.Update(func(tx walletdb.Tx) error {
+			if r.Reset != nil {
+				r.Reset()
+			}
+			return r.Update(tx)
+		})
+	}
+
+	if r.OnCommit != nil {
+		return r.OnCommit(err)
+	}
+	return err
+}
+
+// clear stops further requests joining the passed batch, if it's still the
+// one being filled.
+func (s *TimeScheduler) clear(b *batch) {
+	s.mu.Lock()
+	if s.b == b {
+		s.b = nil
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,150 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// mockDB is an in-memory DB whose transactions write to a copy of the store,
+// only replacing the store once committed.
+type mockDB struct {
+	mu      sync.Mutex
+	store   map[string]int
+	pending map[string]int
+	numTxns int
+}
+
+func newMockDB() *mockDB {
+	return &mockDB{store: make(map[string]int)}
+}
+
+func (d *mockDB) Update(f func(tx walletdb.Tx) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.numTxns++
+	d.pending = make(map[string]int)
+	for k, v := range d.store {
+		d.pending[k] = v
+	}
+	if err := f(nil); err != nil {
+		return err
+	}
+
+	d.store = d.pending
+	return nil
+}
+
+// put returns a request storing the value under the key. It fails if the
+// key is already stored.
+func (d *mockDB) put(key string, value int) *Request {
+	return &Request{
+		Update: func(walletdb.Tx) error {
+			if _, ok := d.pending[key]; ok {
+				return fmt.Errorf("%v already stored", key)
+			}
+			d.pending[key] = value
+			return nil
+		},
+	}
+}
+
+// executeAll executes the requests concurrently, returning their errors.
+func executeAll(s Scheduler, reqs []*Request) []error {
+	errs := make([]error, len(reqs))
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req *Request) {
+			defer wg.Done()
+			errs[i] = s.Execute(req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// TestTimeSchedulerBatch ensures concurrent requests are committed within a
+// single transaction.
+func TestTimeSchedulerBatch(t *testing.T) {
+	db := newMockDB()
+	s := NewTimeScheduler(db, 50*time.Millisecond)
+
+	const numReqs = 20
+	var reqs []*Request
+	for i := 0; i < numReqs; i++ {
+		reqs = append(reqs, db.put(fmt.Sprintf("key%d", i), i))
+	}
+	for i, err := range executeAll(s, reqs) {
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	if db.numTxns != 1 {
+		t.Fatalf("expected a single transaction, got %d", db.numTxns)
+	}
+	if len(db.store) != numReqs {
+		t.Fatalf("expected %d stored keys, got %d", numReqs,
+			len(db.store))
+	}
+}
+
+// TestTimeSchedulerFailure ensures a failing request doesn't take the rest
+// of its batch down with it, and that its own error is returned.
+func TestTimeSchedulerFailure(t *testing.T) {
+	db := newMockDB()
+	db.store["dup"] = 0
+	s := NewTimeScheduler(db, 50*time.Millisecond)
+
+	var resets, runs, commits int
+	good := db.put("good", 1)
+	put := good.Update
+	good.Update = func(tx walletdb.Tx) error {
+		runs++
+		return put(tx)
+	}
+	good.Reset = func() {
+		resets++
+	}
+	good.OnCommit = func(err error) error {
+		commits++
+		return err
+	}
+
+	errFail := errors.New("failed")
+	failing := &Request{
+		Update: func(walletdb.Tx) error {
+			return errFail
+		},
+	}
+
+	errs := executeAll(s, []*Request{good, failing, db.put("dup", 2)})
+	if errs[0] != nil {
+		t.Fatalf("request failed: %v", errs[0])
+	}
+	if errs[1] != errFail {
+		t.Fatalf("expected errFail, got %v", errs[1])
+	}
+	if errs[2] == nil {
+		t.Fatalf("duplicate key was stored")
+	}
+
+	if db.store["good"] != 1 || db.store["dup"] != 0 {
+		t.Fatalf("unexpected store: %v", db.store)
+	}
+	if commits != 1 {
+		t.Fatalf("OnCommit called %d times", commits)
+	}
+	if resets != runs {
+		t.Fatalf("request reset %d times, but run %d times", resets,
+			runs)
+	}
+}
@@ -0,0 +1,201 @@
+package blinding
+
+import (
+	"crypto/hmac"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/fastsha256"
+	"github.com/codahale/chacha20poly1305"
+	"github.com/lightningnetwork/lnd/ecdh"
+)
+
+// Blinded paths hide the tail of a route from the sender of a payment. The
+// receiver picks a path leading from an introduction node to itself, and
+// replaces the identity key of each node past the introduction node with a
+// blinded key. The forwarding instructions of each hop are encrypted to the
+// hop, so that only it learns where to forward the payment next:
+//
+//   e_0 = random session key, E_0 = e_0*G
+//   ss_i = sha256(x(e_i*N_i))
+//   B_i = HMAC256("blinded_node_id", ss_i)*N_i
+//   rho_i = HMAC256("rho", ss_i)
+//   encrypted_data_i = ChaCha20-Poly1305(rho_i, data_i)
+//   e_{i+1} = sha256(E_i || ss_i)*e_i, E_{i+1} = sha256(E_i || ss_i)*E_i
+//
+// The sender only learns the introduction node, the first blinding point
+// E_0, and the blinded key, and encrypted data, of each hop. It's handed E_0
+// by the introduction node, and E_i by each following hop, so that each is
+// able to derive ss_i from its own private key, decrypt its forwarding
+// instructions, and derive the blinding point to hand to the next hop.
+
+var (
+	// ErrNoHops is returned when building a blinded path without hops.
+	ErrNoHops = errors.New("blinded path must have at least one hop")
+
+	// ErrDecryptionFailed is returned when the encrypted data of a hop
+	// can't be decrypted with the key derived by the hop.
+	ErrDecryptionFailed = errors.New("unable to decrypt blinded hop data")
+)
+
+var (
+	blindedNodeIDTag = []byte("blinded_node_id")
+	rhoTag           = []byte("rho")
+)
+
+// HopInfo is a hop of a path to be blinded: the identity key of the node,
+// and the forwarding instructions to be encrypted to it.
+type HopInfo struct {
+	NodePub *btcec.PublicKey
+	Payload []byte
+}
+
+// BlindedHop is a hop of a blinded path.
+type BlindedHop struct {
+	// BlindedNodePub is the blinded identity key of the node.
+	BlindedNodePub *btcec.PublicKey
+
+	// EncryptedData is the forwarding instructions of the hop, which
+	// only the node is able to decrypt.
+	EncryptedData []byte
+}
+
+// BlindedPath is a path whose hops past the introduction node are hidden
+// from the sender.
+type BlindedPath struct {
+	// IntroductionPoint is the real identity key of the first node of the
+	// path, which the sender routes to.
+	IntroductionPoint *btcec.PublicKey
+
+	// BlindingPoint is the blinding point handed to the introduction
+	// node.
+	BlindingPoint *btcec.PublicKey
+
+	// BlindedHops are the hops of the path, starting with the
+	// introduction node.
+	BlindedHops []*BlindedHop
+}
+
+// BuildBlindedPath blinds the path through the passed hops, the first of
+// which is the introduction node, using the session key as e_0.
+func BuildBlindedPath(sessionKey *btcec.PrivateKey,
+	hops []*HopInfo) (*BlindedPath, error) {
+
+	if len(hops) == 0 {
+		return nil, ErrNoHops
+	}
+
+	path := &BlindedPath{
+		IntroductionPoint: hops[0].NodePub,
+		BlindingPoint:     sessionKey.PubKey(),
+	}
+
+	blindingKey := sessionKey
+	for _, hop := range hops {
+		ss := ecdh.SharedSecret(blindingKey, hop.NodePub)
+
+		encryptedData, err := encrypt(ss, hop.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops = append(path.BlindedHops, &BlindedHop{
+			BlindedNodePub: ecdh.TweakPubKey(hop.NodePub,
+				hmacTag(blindedNodeIDTag, ss)),
+			EncryptedData: encryptedData,
+		})
+
+		factor := ecdh.BlindingFactor(blindingKey.PubKey(), ss)
+		blindingKey = ecdh.TweakPrivKey(blindingKey, factor)
+	}
+
+	return path, nil
+}
+
+// DecryptBlindedHop decrypts the forwarding instructions of a hop of a
+// blinded path, given the private key of the node, and the blinding point it
+// was handed. The blinding point to hand to the next hop is returned along
+// with the instructions.
+func DecryptBlindedHop(nodeKey *btcec.PrivateKey,
+	blindingPoint *btcec.PublicKey,
+	encryptedData []byte) ([]byte, *btcec.PublicKey, error) {
+
+	ss := ecdh.SharedSecret(nodeKey, blindingPoint)
+
+	payload, err := decrypt(ss, encryptedData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nextBlindingPoint := ecdh.TweakPubKey(blindingPoint,
+		ecdh.BlindingFactor(blindingPoint, ss))
+
+	return payload, nextBlindingPoint, nil
+}
+
+// ProcessBlindedHop decrypts, and parses, the forwarding instructions a hop
+// of a blinded path receives within an HTLC, returning them along with the
+// blinding point to hand to the next hop.
+func ProcessBlindedHop(nodeKey *btcec.PrivateKey,
+	blindingPoint *btcec.PublicKey,
+	encryptedData []byte) (*HopData, *btcec.PublicKey, error) {
+
+	payload, nextBlindingPoint, err := DecryptBlindedHop(nodeKey,
+		blindingPoint, encryptedData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hopData, err := ParseHopData(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hopData, nextBlindingPoint, nil
+}
+
+// BlindedNodeKey returns the private key of the blinded identity of the node
+// within the path, so that it's able to act on the HTLCs sent to its blinded
+// identity.
+func BlindedNodeKey(nodeKey *btcec.PrivateKey,
+	blindingPoint *btcec.PublicKey) *btcec.PrivateKey {
+
+	ss := ecdh.SharedSecret(nodeKey, blindingPoint)
+	return ecdh.TweakPrivKey(nodeKey, hmacTag(blindedNodeIDTag, ss))
+}
+
+// hmacTag returns HMAC256(tag, ss).
+func hmacTag(tag []byte, ss [32]byte) []byte {
+	mac := hmac.New(fastsha256.New, tag)
+	mac.Write(ss[:])
+	return mac.Sum(nil)
+}
+
+// encrypt encrypts the payload under the rho key derived from the shared
+// secret. As each key only ever encrypts a single payload, the nonce is
+// zero.
+func encrypt(ss [32]byte, payload []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(hmacTag(rhoTag, ss))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(nil, nonce, payload, nil), nil
+}
+
+// decrypt decrypts the encrypted data under the rho key derived from the
+// shared secret.
+func decrypt(ss [32]byte, encryptedData []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(hmacTag(rhoTag, ss))
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	payload, err := aead.Open(nil, nonce, encryptedData, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return payload, nil
+}
@@ -0,0 +1,120 @@
+package blinding
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func TestBlindedPath(t *testing.T) {
+	var (
+		nodeKeys []*btcec.PrivateKey
+		hops     []*HopInfo
+		hopData  []*HopData
+	)
+	for i := byte(1); i <= 3; i++ {
+		priv, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{i}, 32))
+		nodeKeys = append(nodeKeys, priv)
+
+		data := &HopData{
+			NextChannelID:   lnwire.NewShortChanIDFromInt(uint64(i)),
+			BaseFee:         uint32(i) * 1000,
+			FeeRate:         uint32(i),
+			CltvExpiryDelta: uint16(i) * 10,
+		}
+		if i == 3 {
+			data = &HopData{PathID: [32]byte{0xaa}}
+		}
+		hopData = append(hopData, data)
+
+		var b bytes.Buffer
+		if err := data.Encode(&b); err != nil {
+			t.Fatalf("unable to encode hop data: %v", err)
+		}
+		hops = append(hops, &HopInfo{NodePub: pub, Payload: b.Bytes()})
+	}
+
+	sessionKey, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{9}, 32))
+	path, err := BuildBlindedPath(sessionKey, hops)
+	if err != nil {
+		t.Fatalf("unable to build blinded path: %v", err)
+	}
+	if !path.IntroductionPoint.IsEqual(hops[0].NodePub) {
+		t.Fatalf("wrong introduction point")
+	}
+
+	// Each hop, handed the blinding point by the hop before it, is able
+	// to decrypt its own forwarding instructions, and only its own.
+	blindingPoint := path.BlindingPoint
+	for i, hop := range path.BlindedHops {
+		if hop.BlindedNodePub.IsEqual(hops[i].NodePub) {
+			t.Fatalf("hop %v isn't blinded", i)
+		}
+		blindedKey := BlindedNodeKey(nodeKeys[i], blindingPoint)
+		if !blindedKey.PubKey().IsEqual(hop.BlindedNodePub) {
+			t.Fatalf("hop %v derived the wrong blinded key", i)
+		}
+
+		other := nodeKeys[(i+1)%len(nodeKeys)]
+		_, _, err := DecryptBlindedHop(other, blindingPoint,
+			hop.EncryptedData)
+		if err != ErrDecryptionFailed {
+			t.Fatalf("expected ErrDecryptionFailed, got %v", err)
+		}
+
+		data, nextBlindingPoint, err := ProcessBlindedHop(nodeKeys[i],
+			blindingPoint, hop.EncryptedData)
+		if err != nil {
+			t.Fatalf("hop %v unable to process: %v", i, err)
+		}
+		if !reflect.DeepEqual(data, hopData[i]) {
+			t.Fatalf("hop %v decrypted the wrong data", i)
+		}
+		if data.IsFinal() != (i == len(hops)-1) {
+			t.Fatalf("hop %v misidentified as final", i)
+		}
+
+		blindingPoint = nextBlindingPoint
+	}
+
+	// The first hop forwards what's left once its fees are deducted.
+	fwdAmt, err := hopData[0].ForwardAmount(2000 + 1000)
+	if err != nil || fwdAmt != 1999 {
+		t.Fatalf("expected to forward 1999, got %v: %v", fwdAmt, err)
+	}
+	if _, err := hopData[0].ForwardAmount(999); err == nil {
+		t.Fatalf("forwarded amount below base fee")
+	}
+	timeLock, err := hopData[0].ForwardTimeLock(100)
+	if err != nil || timeLock != 90 {
+		t.Fatalf("expected time lock 90, got %v: %v", timeLock, err)
+	}
+
+	if _, err := BuildBlindedPath(sessionKey, nil); err != ErrNoHops {
+		t.Fatalf("expected ErrNoHops, got %v", err)
+	}
+
+	// The path survives a round trip through its encoding.
+	paymentPath := &PaymentPath{
+		BlindedPath:     path,
+		BaseFee:         3000,
+		FeeRate:         3,
+		CltvExpiryDelta: 30,
+	}
+	var b bytes.Buffer
+	if err := paymentPath.Encode(&b); err != nil {
+		t.Fatalf("unable to encode payment path: %v", err)
+	}
+	newPath := &PaymentPath{}
+	if err := newPath.Decode(&b); err != nil {
+		t.Fatalf("unable to decode payment path: %v", err)
+	}
+	if !reflect.DeepEqual(paymentPath, newPath) {
+		t.Fatalf("payment path changed across encoding")
+	}
+}
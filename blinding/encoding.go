@@ -0,0 +1,252 @@
+package blinding
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// maxHops is the most hops a blinded path may have.
+const maxHops = 20
+
+// HopData is the forwarding instructions encrypted to a hop of a blinded
+// path. Each hop other than the last is told the channel to forward over,
+// and the fees, and time lock delta, to apply, as the sender only knows the
+// totals across the whole path. The last hop is instead handed the path ID,
+// by which the receiver recognizes the paths it built.
+type HopData struct {
+	// NextChannelID is the channel the hop forwards the payment over.
+	// It's unset for the last hop.
+	NextChannelID lnwire.ShortChannelID
+
+	// BaseFee, FeeRate, and CltvExpiryDelta are the fees, and time lock
+	// delta, the hop deducts from the payment before forwarding it.
+	BaseFee         uint32
+	FeeRate         uint32
+	CltvExpiryDelta uint16
+
+	// PathID is only set for the last hop.
+	PathID [32]byte
+}
+
+// Encode serializes the hop data into w.
+func (h *HopData) Encode(w io.Writer) error {
+	// NextChannelID(8)
+	// BaseFee(4)
+	// FeeRate(4)
+	// CltvExpiryDelta(2)
+	// PathID(32)
+	return writeElements(w,
+		h.NextChannelID.ToUint64(),
+		h.BaseFee,
+		h.FeeRate,
+		h.CltvExpiryDelta,
+		h.PathID,
+	)
+}
+
+// Decode deserializes hop data from r.
+func (h *HopData) Decode(r io.Reader) error {
+	var chanID uint64
+	err := readElements(r,
+		&chanID,
+		&h.BaseFee,
+		&h.FeeRate,
+		&h.CltvExpiryDelta,
+		&h.PathID,
+	)
+	if err != nil {
+		return err
+	}
+	h.NextChannelID = lnwire.NewShortChanIDFromInt(chanID)
+
+	return nil
+}
+
+// IsFinal returns true if the hop data is that of the last hop of the path.
+func (h *HopData) IsFinal() bool {
+	return h.NextChannelID.ToUint64() == 0
+}
+
+// ForwardAmount returns the amount the hop forwards, given the amount it
+// received, once its fee is deducted. As the fee is charged upon the amount
+// forwarded, the amount is the largest for which the fee fits within what
+// was received.
+func (h *HopData) ForwardAmount(
+	incoming lnwire.MilliSatoshi) (lnwire.MilliSatoshi, error) {
+
+	baseFee := lnwire.MilliSatoshi(h.BaseFee)
+	if incoming < baseFee {
+		return 0, fmt.Errorf("incoming amount %v below base fee %v",
+			incoming, baseFee)
+	}
+
+	return (incoming - baseFee) * 1000000 /
+		(1000000 + lnwire.MilliSatoshi(h.FeeRate)), nil
+}
+
+// ForwardTimeLock returns the time lock of the HTLC the hop forwards, given
+// that of the HTLC it received.
+func (h *HopData) ForwardTimeLock(incoming uint32) (uint32, error) {
+	delta := uint32(h.CltvExpiryDelta)
+	if incoming < delta {
+		return 0, fmt.Errorf("incoming time lock %v below cltv delta "+
+			"%v", incoming, delta)
+	}
+
+	return incoming - delta, nil
+}
+
+// ParseHopData parses the decrypted forwarding instructions of a hop.
+func ParseHopData(payload []byte) (*HopData, error) {
+	r := bytes.NewReader(payload)
+
+	hopData := &HopData{}
+	if err := hopData.Decode(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%v trailing bytes after blinded hop "+
+			"data", r.Len())
+	}
+
+	return hopData, nil
+}
+
+// PaymentPath is a blinded path included within an invoice, along with the
+// totals of the fees, and time lock deltas, of its hops, so that the sender
+// can pay into the path without learning any of its hops.
+type PaymentPath struct {
+	*BlindedPath
+
+	BaseFee         uint32
+	FeeRate         uint32
+	CltvExpiryDelta uint16
+}
+
+// Encode serializes the payment path into w.
+func (p *PaymentPath) Encode(w io.Writer) error {
+	if len(p.BlindedHops) > maxHops {
+		return fmt.Errorf("blinded path has %v hops, at most %v are "+
+			"allowed", len(p.BlindedHops), maxHops)
+	}
+
+	err := writeElements(w,
+		p.IntroductionPoint,
+		p.BlindingPoint,
+		uint8(len(p.BlindedHops)),
+	)
+	if err != nil {
+		return err
+	}
+	for _, hop := range p.BlindedHops {
+		if len(hop.EncryptedData) > 65535 {
+			return fmt.Errorf("blinded hop data too long")
+		}
+		err := writeElements(w,
+			hop.BlindedNodePub,
+			uint16(len(hop.EncryptedData)),
+			hop.EncryptedData,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeElements(w,
+		p.BaseFee,
+		p.FeeRate,
+		p.CltvExpiryDelta,
+	)
+}
+
+// Decode deserializes a payment path from r.
+func (p *PaymentPath) Decode(r io.Reader) error {
+	p.BlindedPath = &BlindedPath{}
+
+	var numHops uint8
+	err := readElements(r,
+		&p.IntroductionPoint,
+		&p.BlindingPoint,
+		&numHops,
+	)
+	if err != nil {
+		return err
+	}
+	if numHops == 0 || numHops > maxHops {
+		return fmt.Errorf("blinded path has %v hops", numHops)
+	}
+
+	for i := uint8(0); i < numHops; i++ {
+		hop := &BlindedHop{}
+
+		var dataLen uint16
+		if err := readElements(r, &hop.BlindedNodePub, &dataLen); err != nil {
+			return err
+		}
+		hop.EncryptedData = make([]byte, dataLen)
+		if err := readElements(r, hop.EncryptedData); err != nil {
+			return err
+		}
+
+		p.BlindedHops = append(p.BlindedHops, hop)
+	}
+
+	return readElements(r,
+		&p.BaseFee,
+		&p.FeeRate,
+		&p.CltvExpiryDelta,
+	)
+}
+
+// writeElements writes each element to w, public keys in compressed form,
+// and integers in big endian.
+func writeElements(w io.Writer, elements ...interface{}) error {
+	for _, element := range elements {
+		var err error
+		switch e := element.(type) {
+		case *btcec.PublicKey:
+			_, err = w.Write(e.SerializeCompressed())
+		case [32]byte:
+			_, err = w.Write(e[:])
+		case []byte:
+			_, err = w.Write(e)
+		default:
+			err = binary.Write(w, binary.BigEndian, e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readElements reads each element from r, in the encoding of
+// writeElements.
+func readElements(r io.Reader, elements ...interface{}) error {
+	for _, element := range elements {
+		var err error
+		switch e := element.(type) {
+		case **btcec.PublicKey:
+			var b [33]byte
+			if _, err = io.ReadFull(r, b[:]); err != nil {
+				return err
+			}
+			*e, err = btcec.ParsePubKey(b[:], btcec.S256())
+		case *[32]byte:
+			_, err = io.ReadFull(r, e[:])
+		case []byte:
+			_, err = io.ReadFull(r, e)
+		default:
+			err = binary.Read(r, binary.BigEndian, e)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package blockcache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Fetcher fetches the block of the passed hash from the backend.
+type Fetcher func(hash *wire.ShaHash) (*wire.MsgBlock, error)
+
+// Stats is a snapshot of the effectiveness of the cache.
+type Stats struct {
+	// Hits is the number of requests served without fetching from the
+	// backend, including those which waited on an identical request
+	// already in flight.
+	Hits uint64
+
+	// Misses is the number of requests which fetched from the backend.
+	Misses uint64
+
+	// Size is the number of blocks currently cached.
+	Size int
+}
+
+// HitRate returns the fraction of requests served without fetching from the
+// backend.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// fetchRequest is a fetch from the backend which is in flight. Any other
+// request for the same block waits on it, rather than fetching the block
+// again.
+type fetchRequest struct {
+	done  chan struct{}
+	block *wire.MsgBlock
+	err   error
+}
+
+// cacheEntry is a block within the LRU list.
+type cacheEntry struct {
+	hash  wire.ShaHash
+	block *wire.MsgBlock
+}
+
+// BlockCache is an LRU cache of blocks, shared by all subsystems fetching
+// blocks from the backend. Concurrent requests for the same block result in a
+// single fetch.
+type BlockCache struct {
+	hits   uint64 // To be used atomically.
+	misses uint64 // To be used atomically.
+
+	capacity int
+
+	sync.Mutex
+	blocks   map[wire.ShaHash]*list.Element
+	lru      *list.List
+	inFlight map[wire.ShaHash]*fetchRequest
+}
+
+// New creates a new cache holding up to capacity blocks.
+func New(capacity int) *BlockCache {
+	return &BlockCache{
+		capacity: capacity,
+		blocks:   make(map[wire.ShaHash]*list.Element),
+		lru:      list.New(),
+		inFlight: make(map[wire.ShaHash]*fetchRequest),
+	}
+}
+
+// GetBlock returns the block of the passed hash, using the passed fetcher to
+// fetch it from the backend if it isn't cached. Failed fetches aren't cached.
+func (c *BlockCache) GetBlock(hash *wire.ShaHash,
+	fetch Fetcher) (*wire.MsgBlock, error) {
+
+	c.Lock()
+	if elem, ok := c.blocks[*hash]; ok {
+		c.lru.MoveToFront(elem)
+		c.Unlock()
+
+		atomic.AddUint64(&c.hits, 1)
+		return elem.Value.(*cacheEntry).block, nil
+	}
+
+	// If the block is already being fetched, then wait for that fetch
+	// to complete instead.
+	if req, ok := c.inFlight[*hash]; ok {
+		c.Unlock()
+
+		atomic.AddUint64(&c.hits, 1)
+		<-req.done
+		return req.block, req.err
+	}
+
+	req := &fetchRequest{done: make(chan struct{})}
+	c.inFlight[*hash] = req
+	c.Unlock()
+
+	atomic.AddUint64(&c.misses, 1)
+	req.block, req.err = fetch(hash)
+
+	c.Lock()
+	delete(c.inFlight, *hash)
+	if req.err == nil {
+		c.add(*hash, req.block)
+	}
+	c.Unlock()
+
+	close(req.done)
+	return req.block, req.err
+}
+
+// Stats returns a snapshot of the effectiveness of the cache.
+func (c *BlockCache) Stats() Stats {
+	c.Lock()
+	size := c.lru.Len()
+	c.Unlock()
+
+	return Stats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Size:   size,
+	}
+}
+
+// add inserts the block into the cache, evicting the least recently used
+// block if the cache is full.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *BlockCache) add(hash wire.ShaHash, block *wire.MsgBlock) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	if c.lru.Len() >= c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.blocks, oldest.Value.(*cacheEntry).hash)
+	}
+
+	c.blocks[hash] = c.lru.PushFront(&cacheEntry{hash: hash, block: block})
+}
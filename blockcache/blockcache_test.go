@@ -0,0 +1,140 @@
+package blockcache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func testBlock(nonce uint32) (*wire.ShaHash, *wire.MsgBlock) {
+	block := wire.NewMsgBlock(&wire.BlockHeader{Nonce: nonce})
+	hash := block.BlockSha()
+	return &hash, block
+}
+
+func TestBlockCacheLRU(t *testing.T) {
+	cache := New(2)
+
+	var numFetches int
+	blocks := make(map[wire.ShaHash]*wire.MsgBlock)
+	fetch := func(hash *wire.ShaHash) (*wire.MsgBlock, error) {
+		numFetches++
+		block, ok := blocks[*hash]
+		if !ok {
+			return nil, fmt.Errorf("unknown block")
+		}
+		return block, nil
+	}
+
+	var hashes []*wire.ShaHash
+	for i := uint32(0); i < 3; i++ {
+		hash, block := testBlock(i)
+		blocks[*hash] = block
+		hashes = append(hashes, hash)
+	}
+
+	// Fetch the first two blocks, then the first again, which should be
+	// served from the cache.
+	for _, i := range []int{0, 1, 0} {
+		block, err := cache.GetBlock(hashes[i], fetch)
+		if err != nil {
+			t.Fatalf("unable to get block: %v", err)
+		}
+		if block != blocks[*hashes[i]] {
+			t.Fatalf("wrong block returned")
+		}
+	}
+	if numFetches != 2 {
+		t.Fatalf("expected 2 fetches, got %v", numFetches)
+	}
+
+	// Adding a third block evicts the second, as the first was used more
+	// recently.
+	if _, err := cache.GetBlock(hashes[2], fetch); err != nil {
+		t.Fatalf("unable to get block: %v", err)
+	}
+	if _, err := cache.GetBlock(hashes[0], fetch); err != nil {
+		t.Fatalf("unable to get block: %v", err)
+	}
+	if numFetches != 3 {
+		t.Fatalf("expected 3 fetches, got %v", numFetches)
+	}
+	if _, err := cache.GetBlock(hashes[1], fetch); err != nil {
+		t.Fatalf("unable to get block: %v", err)
+	}
+	if numFetches != 4 {
+		t.Fatalf("expected 4 fetches, got %v", numFetches)
+	}
+
+	// Failed fetches aren't cached.
+	unknown := &wire.ShaHash{0x01}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.GetBlock(unknown, fetch); err == nil {
+			t.Fatalf("expected fetch to fail")
+		}
+	}
+	if numFetches != 6 {
+		t.Fatalf("expected 6 fetches, got %v", numFetches)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 6 || stats.Size != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if stats.HitRate() != 0.25 {
+		t.Fatalf("expected hit rate of 0.25, got %v", stats.HitRate())
+	}
+}
+
+func TestBlockCacheDeduplicate(t *testing.T) {
+	cache := New(10)
+	hash, block := testBlock(0)
+
+	// The fetch blocks until all requests have been made, so each must
+	// wait on the single fetch in flight.
+	const numRequests = 10
+	var numFetches uint32
+	release := make(chan struct{})
+	fetch := func(*wire.ShaHash) (*wire.MsgBlock, error) {
+		atomic.AddUint32(&numFetches, 1)
+		<-release
+		return block, nil
+	}
+
+	var wg sync.WaitGroup
+	errChan := make(chan error, numRequests)
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, err := cache.GetBlock(hash, fetch)
+			if err == nil && b != block {
+				err = fmt.Errorf("wrong block returned")
+			}
+			errChan <- err
+		}()
+	}
+
+	// Wait for every request to either start the fetch, or wait on it.
+	for {
+		stats := cache.Stats()
+		if stats.Hits+stats.Misses == numRequests {
+			break
+		}
+	}
+	close(release)
+	wg.Wait()
+	close(errChan)
+
+	for err := range errChan {
+		if err != nil {
+			t.Fatalf("unable to get block: %v", err)
+		}
+	}
+	if n := atomic.LoadUint32(&numFetches); n != 1 {
+		t.Fatalf("expected a single fetch, got %v", n)
+	}
+}
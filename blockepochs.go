@@ -0,0 +1,150 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+// blockEpochBuffer is the number of blocks queued for each client of the
+// blockEpochHub. A client falling further behind is cancelled.
+const blockEpochBuffer = 20
+
+// blockEpochClient is sent each block connected to the main chain after it
+// subscribed.
+type blockEpochClient struct {
+	// epochs is sent each new block. It's closed once the client is
+	// cancelled, falls too far behind, or the hub is stopped.
+	epochs chan *chainntnfs.BlockEpoch
+
+	cancelOnce sync.Once
+	cancel     func()
+}
+
+// Cancel unsubscribes the client.
+func (c *blockEpochClient) Cancel() {
+	c.cancelOnce.Do(c.cancel)
+}
+
+// blockEpochHub fans the blocks we're notified of out to clients, such as rpc
+// streams, which come and go. The notifier itself keeps each registration
+// for good, and blocks until each block is delivered, so the hub registers
+// once, and never blocks on its clients.
+type blockEpochHub struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	wallet *lnwallet.LightningWallet
+
+	sync.Mutex
+	clients      map[uint64]*blockEpochClient
+	nextClientID uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newBlockEpochHub creates a new blockEpochHub, fed by the wallet's chain
+// notifier.
+func newBlockEpochHub(wallet *lnwallet.LightningWallet) *blockEpochHub {
+	return &blockEpochHub{
+		wallet:  wallet,
+		clients: make(map[uint64]*blockEpochClient),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start registers for block notifications, and launches the goroutine
+// dispatching them.
+func (h *blockEpochHub) Start() error {
+	if !atomic.CompareAndSwapUint32(&h.started, 0, 1) {
+		return nil
+	}
+
+	epochChan := make(chan *chainntnfs.BlockEpoch, blockEpochBuffer)
+	if err := h.wallet.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+
+	h.wg.Add(1)
+	go h.dispatcher(epochChan)
+
+	return nil
+}
+
+// Stop cancels every client, and waits for the dispatcher to exit.
+func (h *blockEpochHub) Stop() {
+	if !atomic.CompareAndSwapUint32(&h.stopped, 0, 1) {
+		return
+	}
+
+	close(h.quit)
+	h.wg.Wait()
+
+	h.Lock()
+	for clientID, client := range h.clients {
+		delete(h.clients, clientID)
+		close(client.epochs)
+	}
+	h.Unlock()
+}
+
+// Subscribe returns a new client, sent each block connected from now on.
+func (h *blockEpochHub) Subscribe() *blockEpochClient {
+	client := &blockEpochClient{
+		epochs: make(chan *chainntnfs.BlockEpoch, blockEpochBuffer),
+	}
+
+	h.Lock()
+	clientID := h.nextClientID
+	h.nextClientID++
+
+	// Once stopped, the client won't be sent any blocks.
+	if atomic.LoadUint32(&h.stopped) != 0 {
+		close(client.epochs)
+	} else {
+		h.clients[clientID] = client
+	}
+	h.Unlock()
+
+	client.cancel = func() {
+		h.Lock()
+		defer h.Unlock()
+
+		if _, ok := h.clients[clientID]; ok {
+			delete(h.clients, clientID)
+			close(client.epochs)
+		}
+	}
+
+	return client
+}
+
+// dispatcher sends each block we're notified of to every client, cancelling
+// those whose queue is full.
+//
+// NOTE: This MUST be run as a goroutine.
+func (h *blockEpochHub) dispatcher(epochChan chan *chainntnfs.BlockEpoch) {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case epoch := <-epochChan:
+			h.Lock()
+			for clientID, client := range h.clients {
+				select {
+				case client.epochs <- epoch:
+				default:
+					delete(h.clients, clientID)
+					close(client.epochs)
+				}
+			}
+			h.Unlock()
+
+		case <-h.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultTLSCertValidity is how long the TLS certificates we generate are
+// valid for.
+const defaultTLSCertValidity = 14 * 30 * 24 * time.Hour
+
+// certManager holds the TLS certificate served by the rpc server, which is
+// generated on startup if missing, expired, or not covering each of the
+// configured names. If a rotation interval is set, the certificate is
+// periodically replaced by a new one, after which clients must re-read the
+// certificate file. If client CAs are set, clients must present a
+// certificate signed by one of them.
+type certManager struct {
+	certPath string
+	keyPath  string
+
+	// The IPs, and domains, included in the certificate in addition to
+	// those of the local host.
+	extraIPs     []string
+	extraDomains []string
+
+	rotateInterval time.Duration
+
+	// clientCAs, if set, are the CAs the certificates of clients are
+	// verified against. Clients without a valid certificate are refused.
+	clientCAs *x509.CertPool
+
+	cert *tls.Certificate
+	sync.RWMutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newCertManager creates a new certManager, loading the certificate at the
+// passed path, or generating a new one in its place. Unless clientCAs is nil,
+// clients must authenticate with a certificate signed by one of them.
+func newCertManager(certPath, keyPath string, extraIPs, extraDomains []string,
+	rotateInterval time.Duration, clientCAs *x509.CertPool) (*certManager, error) {
+
+	c := &certManager{
+		certPath:       certPath,
+		keyPath:        keyPath,
+		extraIPs:       extraIPs,
+		extraDomains:   extraDomains,
+		rotateInterval: rotateInterval,
+		clientCAs:      clientCAs,
+		quit:           make(chan struct{}),
+	}
+
+	cert, err := c.loadCert()
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		if cert, err = c.genCert(); err != nil {
+			return nil, err
+		}
+	}
+	c.cert = cert
+
+	return c, nil
+}
+
+// Start launches the goroutine rotating the certificate, if enabled.
+func (c *certManager) Start() {
+	if c.rotateInterval == 0 {
+		return
+	}
+
+	c.wg.Add(1)
+	go c.rotator()
+}
+
+// Stop halts certificate rotation.
+func (c *certManager) Stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// TLSConfig returns the TLS config of the rpc server, which always serves
+// the current certificate, and requires client certificates if configured
+// to.
+func (c *certManager) TLSConfig() *tls.Config {
+	config := &tls.Config{
+		GetCertificate: c.getCertificate,
+	}
+	if c.clientCAs != nil {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.ClientCAs = c.clientCAs
+	}
+
+	return config
+}
+
+// loadClientCAs loads the PEM encoded CA certificates at the passed path,
+// which the certificates of rpc clients are verified against.
+func loadClientCAs(path string) (*x509.CertPool, error) {
+	pemCerts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("no certificates found in %v", path)
+	}
+
+	return pool, nil
+}
+
+// getCertificate returns the current certificate.
+func (c *certManager) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.cert, nil
+}
+
+// rotator replaces the certificate with a newly generated one each rotation
+// interval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *certManager) rotator() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.rotateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cert, err := c.genCert()
+			if err != nil {
+				fmt.Printf("unable to rotate tls cert: %v\n", err)
+				continue
+			}
+
+			c.Lock()
+			c.cert = cert
+			c.Unlock()
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// loadCert loads the certificate from disk. If it doesn't exist, has
+// expired, or doesn't cover each configured name, nil is returned.
+func (c *certManager) loadCert() (*tls.Certificate, error) {
+	if _, err := os.Stat(c.certPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+	if err != nil {
+		return nil, err
+	}
+	x509Cert, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(x509Cert.NotAfter) {
+		return nil, nil
+	}
+	for _, ip := range c.extraIPs {
+		if x509Cert.VerifyHostname(ip) != nil {
+			return nil, nil
+		}
+	}
+	for _, domain := range c.extraDomains {
+		if x509Cert.VerifyHostname(domain) != nil {
+			return nil, nil
+		}
+	}
+
+	return &cert, nil
+}
+
+// genCert generates a new self-signed certificate, valid for the local host
+// along with each configured name, writing it to disk.
+func (c *certManager) genCert() (*tls.Certificate, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	ips := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+	for _, ipStr := range c.extraIPs {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid tls ip %q", ipStr)
+		}
+		ips = append(ips, ip)
+	}
+
+	// Unix sockets are verified as either "unix" or "unixpacket".
+	domains := []string{host, "localhost", "unix", "unixpacket"}
+	domains = append(domains, c.extraDomains...)
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"lnd autogenerated cert"},
+			CommonName:   host,
+		},
+		NotBefore: now.Add(-time.Hour),
+		NotAfter:  now.Add(defaultTLSCertValidity),
+
+		KeyUsage: x509.KeyUsageKeyEncipherment |
+			x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+
+		DNSNames:    domains,
+		IPAddresses: ips,
+	}
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template,
+		&priv.PublicKey, priv)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: certDER,
+	})
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "EC PRIVATE KEY",
+		Bytes: keyDER,
+	})
+
+	if err := ioutil.WriteFile(c.keyPath, keyPEM, 0600); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(c.certPath, certPEM, 0644); err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
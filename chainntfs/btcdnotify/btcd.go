@@ -19,10 +19,16 @@ type BtcdNotifier struct {
 
 	notificationRegistry chan interface{}
 
-	spendNotifications map[wire.OutPoint]*spendNotification
-	confNotifications  map[wire.ShaHash]*confirmationsNotification
+	spendNotifications map[wire.OutPoint][]*spendNotification
+	confNotifications  map[wire.ShaHash][]*confirmationsNotification
 	confHeap           *confirmationHeap
 
+	// relevantTxClients are sent each relevant transaction.
+	relevantTxClients []chan *chainntnfs.RelevantTx
+
+	// blockEpochClients are sent each newly connected block.
+	blockEpochClients []chan *chainntnfs.BlockEpoch
+
 	connectedBlocks    <-chan wtxmgr.BlockMeta
 	disconnectedBlocks <-chan wtxmgr.BlockMeta
 	relevantTxs        <-chan chain.RelevantTx
@@ -43,8 +49,8 @@ func NewBtcdNotifier(c ChainConnection) (*BtcdNotifier, error) {
 		conn:                 c,
 		notificationRegistry: make(chan interface{}),
 
-		spendNotifications: make(map[wire.OutPoint]*spendNotification),
-		confNotifications:  make(map[wire.ShaHash]*confirmationsNotification),
+		spendNotifications: make(map[wire.OutPoint][]*spendNotification),
+		confNotifications:  make(map[wire.ShaHash][]*confirmationsNotification),
 		confHeap:           newConfirmationHeap(),
 
 		connectedBlocks:    make(chan wtxmgr.BlockMeta),
@@ -95,23 +101,67 @@ out:
 		case registerMsg := <-b.notificationRegistry:
 			switch msg := registerMsg.(type) {
 			case *spendNotification:
-				b.spendNotifications[*msg.outpoint] = msg
+				op := *msg.outpoint
+				b.spendNotifications[op] = append(
+					b.spendNotifications[op], msg)
 			case *confirmationsNotification:
-				b.confNotifications[*msg.txid] = msg
+				txid := *msg.txid
+				b.confNotifications[txid] = append(
+					b.confNotifications[txid], msg)
+			case *relevantTxNotification:
+				b.relevantTxClients = append(b.relevantTxClients,
+					msg.txChan)
+			case *blockEpochNotification:
+				b.blockEpochClients = append(b.blockEpochClients,
+					msg.epochChan)
 			}
 		case txNtfn := <-b.relevantTxs:
 			tx := txNtfn.TxRecord.MsgTx
 			txMined := txNtfn.Block != nil
 
+			relevantTx := &chainntnfs.RelevantTx{Tx: &tx}
+			if txMined {
+				relevantTx.BlockHeight = uint32(txNtfn.Block.Height)
+			}
+			for _, txChan := range b.relevantTxClients {
+				select {
+				case txChan <- relevantTx:
+				case <-b.quit:
+					break out
+				}
+			}
+
 			// First, check if this transaction spends an output
-			// that has an existing spend notification for it.
-			for _, txIn := range tx.TxIn {
+			// that has an existing spend notification for it. Those
+			// which only fire once the spend is mined are kept
+			// until then.
+			for i, txIn := range tx.TxIn {
 				prevOut := txIn.PreviousOutPoint
+				ntfns, ok := b.spendNotifications[prevOut]
+				if !ok {
+					continue
+				}
 
-				if ntfn, ok := b.spendNotifications[prevOut]; ok {
-					go triggerNtfn(ntfn.trigger)
+				detail := &chainntnfs.SpendDetail{
+					SpentOutPoint:     &prevOut,
+					SpendingTx:        &tx,
+					SpenderInputIndex: uint32(i),
+					SpendingHeight:    relevantTx.BlockHeight,
+				}
+
+				var remaining []*spendNotification
+				for _, ntfn := range ntfns {
+					if !ntfn.mempool && !txMined {
+						remaining = append(remaining, ntfn)
+						continue
+					}
+					go triggerSpendNtfn(ntfn.trigger, detail)
+				}
 
+				if len(remaining) == 0 {
 					delete(b.spendNotifications, prevOut)
+				} else {
+					b.spendNotifications[prevOut] = remaining
 				}
 			}
 
@@ -123,15 +173,16 @@ out:
 				break
 			}
 
-			// If a confirmation notification has been registered
-			// for this txid, then either trigger a notification
-			// event if only a single confirmation notification was
-			// requested, or place the notification on the
-			// confirmation heap for future usage.
-			if confNtfn, ok := b.confNotifications[tx.TxSha()]; ok {
+			// For each confirmation notification registered for
+			// this txid, either trigger a notification event if
+			// only a single confirmation was requested, or place
+			// the notification on the confirmation heap for future
+			// usage.
+			txid := tx.TxSha()
+			for _, confNtfn := range b.confNotifications[txid] {
 				if confNtfn.numConfirmations == 1 {
 					go triggerNtfn(confNtfn.trigger)
-					break
+					continue
 				}
 
 				// The registered notification requires more
@@ -147,23 +198,37 @@ out:
 				}
 				heap.Push(b.confHeap, heapEntry)
 			}
+			delete(b.confNotifications, txid)
 		case blockNtfn := <-b.connectedBlocks:
 			blockHeight := uint32(blockNtfn.Height)
 
+			epoch := &chainntnfs.BlockEpoch{
+				Hash:   blockNtfn.Hash,
+				Height: blockNtfn.Height,
+			}
+			for _, epochChan := range b.blockEpochClients {
+				select {
+				case epochChan <- epoch:
+				case <-b.quit:
+					break out
+				}
+			}
+
 			// Traverse our confirmation heap. The heap is a
 			// min-heap, so the confirmation notification which requires
 			// the smallest block-height will always be at the top
 			// of the heap. If a confirmation notification is eligible
 			// for triggering, then fire it off, and check if another
 			// is eligible until there are no more eligible entries.
-			nextConf := heap.Pop(b.confHeap).(*confEntry)
-			for nextConf.triggerHeight <= blockHeight {
-				triggerNtfn(nextConf.trigger)
+			for b.confHeap.Len() > 0 {
+				nextConf := b.confHeap.items[0]
+				if nextConf.triggerHeight > blockHeight {
+					break
+				}
 
-				nextConf = heap.Pop(b.confHeap).(*confEntry)
+				heap.Pop(b.confHeap)
+				triggerNtfn(nextConf.trigger)
 			}
-
-			heap.Push(b.confHeap, nextConf)
 		case delBlockNtfn := <-b.disconnectedBlocks:
 			// TODO(roasbeef): re-orgs
 			//  * second channel to notify of confirmation decrementing
@@ -199,6 +264,10 @@ func (b *BtcdNotifier) initAllNotifications() error {
 type spendNotification struct {
 	outpoint *wire.OutPoint
 
+	// mempool is true if the notification fires as soon as the spending
+	// transaction enters the mempool.
+	mempool bool
+
 	trigger *chainntnfs.NotificationTrigger
 }
 
@@ -216,12 +285,13 @@ type confirmationsNotification struct {
 // RegisterSpendNotification ...
 // NOTE: eventChan MUST be buffered
 func (b *BtcdNotifier) RegisterSpendNotification(outpoint *wire.OutPoint,
-	trigger *chainntnfs.NotificationTrigger) error {
+	mempool bool, trigger *chainntnfs.NotificationTrigger) error {
 
-	// TODO(roasbeef): also register with rpc client? bool?
+	// TODO: also register with rpc client?
 
 	ntfn := &spendNotification{
 		outpoint: outpoint,
+		mempool:  mempool,
 		trigger:  trigger,
 	}
 
@@ -245,6 +315,40 @@ func (b *BtcdNotifier) RegisterConfirmationsNotification(txid *wire.ShaHash,
 	return nil
 }
 
+// relevantTxNotification registers a client to receive all relevant
+// transactions.
+type relevantTxNotification struct {
+	txChan chan *chainntnfs.RelevantTx
+}
+
+// RegisterRelevantTxNotification ...
+// NOTE: txChan MUST be serviced promptly, as the dispatcher blocks until each
+// transaction has been delivered.
+func (b *BtcdNotifier) RegisterRelevantTxNotification(
+	txChan chan *chainntnfs.RelevantTx) error {
+
+	b.notificationRegistry <- &relevantTxNotification{txChan: txChan}
+
+	return nil
+}
+
+// blockEpochNotification registers a client to receive all newly connected
+// blocks.
+type blockEpochNotification struct {
+	epochChan chan *chainntnfs.BlockEpoch
+}
+
+// RegisterBlockEpochNotification ...
+// NOTE: epochChan MUST be serviced promptly, as the dispatcher blocks until
+// each block has been delivered.
+func (b *BtcdNotifier) RegisterBlockEpochNotification(
+	epochChan chan *chainntnfs.BlockEpoch) error {
+
+	b.notificationRegistry <- &blockEpochNotification{epochChan: epochChan}
+
+	return nil
+}
+
 func triggerNtfn(t *chainntnfs.NotificationTrigger) {
 	if t.Callback != nil {
 		go t.Callback()
@@ -252,3 +356,15 @@ func triggerNtfn(t *chainntnfs.NotificationTrigger) {
 
 	t.TriggerChan <- struct{}{}
 }
+
+// triggerSpendNtfn fires a spend notification, handing over the details of
+// the spend if requested.
+func triggerSpendNtfn(t *chainntnfs.NotificationTrigger,
+	detail *chainntnfs.SpendDetail) {
+
+	if t.SpendChan != nil {
+		t.SpendChan <- detail
+	}
+
+	triggerNtfn(t)
+}
@@ -15,14 +15,64 @@ import "github.com/btcsuite/btcd/wire"
 // ChainNotifier ...
 type ChainNotifier interface {
 	RegisterConfirmationsNotification(txid *wire.ShaHash, numConfs uint32, trigger *NotificationTrigger) error
-	RegisterSpendNotification(outpoint *wire.OutPoint, trigger *NotificationTrigger) error
+
+	// RegisterSpendNotification registers a trigger which fires once the
+	// outpoint is spent. By default it fires once the spending transaction
+	// is mined. If mempool is set, it instead fires as soon as a spending
+	// transaction is accepted to the mempool, allowing the caller to react
+	// to the spend before it confirms.
+	RegisterSpendNotification(outpoint *wire.OutPoint, mempool bool,
+		trigger *NotificationTrigger) error
+
+	// RegisterRelevantTxNotification registers a channel which is sent
+	// each transaction relevant to the wallet, first as it enters the
+	// mempool, then again once it's mined.
+	RegisterRelevantTxNotification(txChan chan *RelevantTx) error
+
+	// RegisterBlockEpochNotification registers a channel which is sent
+	// each new block as it's connected to the main chain.
+	RegisterBlockEpochNotification(epochChan chan *BlockEpoch) error
 
 	Start() error
 	Stop() error
 }
 
+// RelevantTx is a transaction relevant to the wallet.
+type RelevantTx struct {
+	Tx *wire.MsgTx
+
+	// BlockHeight is the height of the block the transaction was mined
+	// in, or zero if it's still unconfirmed.
+	BlockHeight uint32
+}
+
+// BlockEpoch is a block connected to the main chain.
+type BlockEpoch struct {
+	Hash   wire.ShaHash
+	Height int32
+}
+
+// SpendDetail describes the spend of an outpoint.
+type SpendDetail struct {
+	SpentOutPoint *wire.OutPoint
+	SpendingTx    *wire.MsgTx
+
+	// SpenderInputIndex is the index of the input of the spending
+	// transaction which spends the outpoint.
+	SpenderInputIndex uint32
+
+	// SpendingHeight is the height of the block the spending transaction
+	// was mined in, or zero if it's still in the mempool.
+	SpendingHeight uint32
+}
+
 // NotificationTrigger ...
 type NotificationTrigger struct {
 	TriggerChan chan struct{}
 	Callback    func()
+
+	// SpendChan, if set, is sent the details of the spend when a spend
+	// notification fires.
+	// NOTE: SpendChan MUST be buffered.
+	SpendChan chan *SpendDetail
 }
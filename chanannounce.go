@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// errInvalidNodeSig is returned when the node signature of the peer
+	// over the announcement of our channel fails to verify.
+	errInvalidNodeSig = errors.New("invalid node signature")
+
+	// errInvalidBitcoinSig is returned when the bitcoin signature of the
+	// peer over the announcement of our channel fails to verify.
+	errInvalidBitcoinSig = errors.New("invalid bitcoin signature")
+)
+
+// annExchange tracks the exchange of announcement signatures over our
+// channel with a peer. Either side may send its signatures first, so those
+// of the peer are held until our own are ready.
+type annExchange struct {
+	// ann is the announcement of the channel, without its signatures,
+	// set once we've signed it.
+	ann *lnwire.ChannelAnnouncement
+
+	ours   *lnwire.AnnouncementSignatures
+	theirs *lnwire.AnnouncementSignatures
+
+	// done is set once the signed announcement has been handed to the
+	// gossiper.
+	done bool
+}
+
+// newChanAnn returns the announcement of the channel between the two nodes,
+// funded by the two funding keys, without its signatures.
+func newChanAnn(chanID lnwire.ShortChannelID, ourNodeKey, theirNodeKey,
+	ourFundingKey, theirFundingKey *btcec.PublicKey) *lnwire.ChannelAnnouncement {
+
+	ann := &lnwire.ChannelAnnouncement{
+		ShortChannelID: chanID,
+		NodeID1:        ourNodeKey,
+		NodeID2:        theirNodeKey,
+		BitcoinKey1:    ourFundingKey,
+		BitcoinKey2:    theirFundingKey,
+	}
+	if bytes.Compare(ourNodeKey.SerializeCompressed(),
+		theirNodeKey.SerializeCompressed()) > 0 {
+
+		ann.NodeID1, ann.NodeID2 = theirNodeKey, ourNodeKey
+		ann.BitcoinKey1, ann.BitcoinKey2 = theirFundingKey, ourFundingKey
+	}
+
+	return ann
+}
+
+// assembleChanAnn returns the announcement signed by both our signatures
+// and the peer's, once each of the peer's is verified.
+func assembleChanAnn(ann *lnwire.ChannelAnnouncement, ourNodeKey *btcec.PublicKey,
+	ours, theirs *lnwire.AnnouncementSignatures) (*lnwire.ChannelAnnouncement, error) {
+
+	if theirs.ChannelID != ours.ChannelID {
+		return nil, fmt.Errorf("announcement signatures for unknown "+
+			"channel %v", theirs.ChannelID)
+	}
+	if theirs.ShortChannelID != ours.ShortChannelID {
+		return nil, fmt.Errorf("peer located channel at %v rather "+
+			"than %v", theirs.ShortChannelID, ours.ShortChannelID)
+	}
+
+	signed := *ann
+	theirNodeKey, theirFundingKey := ann.NodeID1, ann.BitcoinKey1
+	if ann.NodeID1.IsEqual(ourNodeKey) {
+		theirNodeKey, theirFundingKey = ann.NodeID2, ann.BitcoinKey2
+		signed.NodeSig1, signed.BitcoinSig1 = ours.NodeSignature,
+			ours.BitcoinSignature
+		signed.NodeSig2, signed.BitcoinSig2 = theirs.NodeSignature,
+			theirs.BitcoinSignature
+	} else {
+		signed.NodeSig1, signed.BitcoinSig1 = theirs.NodeSignature,
+			theirs.BitcoinSignature
+		signed.NodeSig2, signed.BitcoinSig2 = ours.NodeSignature,
+			ours.BitcoinSignature
+	}
+
+	data, err := ann.DataToSign()
+	if err != nil {
+		return nil, err
+	}
+	digest := wire.DoubleSha256(data)
+	if !theirs.NodeSignature.Verify(digest, theirNodeKey) {
+		return nil, errInvalidNodeSig
+	}
+	if !theirs.BitcoinSignature.Verify(digest, theirFundingKey) {
+		return nil, errInvalidBitcoinSig
+	}
+
+	return &signed, nil
+}
+
+// announceChannel waits for the funding transaction of the channel to reach
+// announcement depth, then sends the peer our announcement signatures. It's
+// launched once the funding workflow hands the channel over to the peer.
+// Private channels are instead added to our graph as soon as they confirm,
+// without exchanging any signatures.
+//
+// TODO: resend our signatures upon reconnecting, until the
+// peer's arrive
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *peer) announceChannel(res *lnwallet.ChannelReservation,
+	channel *lnwallet.LightningChannel) {
+
+	var err error
+	if channel.IsPrivate() {
+		res.WaitForChannelConfirmed()
+		err = p.addPrivateChannel(channel)
+	} else {
+		res.WaitForAnnouncementDepth()
+		err = p.sendAnnouncementSigs(channel)
+	}
+	if err != nil {
+		fmt.Printf("unable to announce channel with peer %v: %v\n",
+			p.peerID, err)
+	}
+}
+
+// addPrivateChannel adds our private channel with the peer to the graph,
+// so that we're able to route over it, then sends the peer our policy for
+// the channel.
+func (p *peer) addPrivateChannel(channel *lnwallet.LightningChannel) error {
+	ann, err := p.unsignedChanAnn(channel)
+	if err != nil {
+		return err
+	}
+
+	if err := p.server.gossiper.ProcessPrivateChannel(ann); err != nil {
+		return err
+	}
+
+	return p.server.chanStatus.setDisabled(ann.ShortChannelID, false)
+}
+
+// unsignedChanAnn returns the announcement of our channel with the peer,
+// without its signatures.
+func (p *peer) unsignedChanAnn(
+	channel *lnwallet.LightningChannel) (*lnwire.ChannelAnnouncement, error) {
+
+	theirNodeKey := p.remotePub()
+	if theirNodeKey == nil {
+		return nil, fmt.Errorf("peer %v isn't authenticated", p.peerID)
+	}
+	ourFundingKey, theirFundingKey, err := channel.FundingKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	chanID := channel.ShortChanID()
+	if chanID.ToUint64() == 0 {
+		return nil, fmt.Errorf("location of channel isn't known")
+	}
+
+	return newChanAnn(chanID, p.server.identity.PubKey(), theirNodeKey,
+		ourFundingKey, theirFundingKey), nil
+}
+
+// sendAnnouncementSigs signs the announcement of the channel with both our
+// node key, and our funding key, sending the signatures to the peer.
+func (p *peer) sendAnnouncementSigs(channel *lnwallet.LightningChannel) error {
+	chanPoint, err := channel.ChannelPoint()
+	if err != nil {
+		return err
+	}
+	ann, err := p.unsignedChanAnn(channel)
+	if err != nil {
+		return err
+	}
+
+	data, err := ann.DataToSign()
+	if err != nil {
+		return err
+	}
+	digest := wire.DoubleSha256(data)
+	nodeSig, err := p.server.identity.SignHash(digest)
+	if err != nil {
+		return err
+	}
+	bitcoinSig, err := channel.SignAnnouncement(digest)
+	if err != nil {
+		return err
+	}
+
+	ours := &lnwire.AnnouncementSignatures{
+		ChannelID:        lnwire.NewChanIDFromOutPoint(chanPoint),
+		ShortChannelID:   ann.ShortChannelID,
+		NodeSignature:    nodeSig,
+		BitcoinSignature: bitcoinSig,
+	}
+
+	p.annMtx.Lock()
+	p.annExchange.ann = ann
+	p.annExchange.ours = ours
+	p.annMtx.Unlock()
+
+	p.queueMsg(ours, nil)
+
+	return p.completeAnnouncement()
+}
+
+// handleAnnouncementSigs records the announcement signatures of the peer,
+// completing the announcement of our channel if we've sent our own. As we
+// never sign the announcement of a private channel, signatures sent for one
+// are left unused.
+func (p *peer) handleAnnouncementSigs(msg lnwire.Message) {
+	p.annMtx.Lock()
+	p.annExchange.theirs = msg.(*lnwire.AnnouncementSignatures)
+	p.annMtx.Unlock()
+
+	err := p.completeAnnouncement()
+	switch {
+	case err == errInvalidNodeSig || err == errInvalidBitcoinSig:
+		p.recordMisbehavior(channeldb.MisbehaviorBadSignature,
+			"announcement signatures: %v", err)
+
+	case err != nil:
+		fmt.Printf("unable to announce channel with peer %v: %v\n",
+			p.peerID, err)
+	}
+}
+
+// completeAnnouncement hands the announcement of our channel to the
+// gossiper once both we and the peer have signed it, then announces our
+// policy for the channel.
+func (p *peer) completeAnnouncement() error {
+	p.annMtx.Lock()
+	exchange := &p.annExchange
+	if exchange.done || exchange.ours == nil || exchange.theirs == nil {
+		p.annMtx.Unlock()
+		return nil
+	}
+	ann, err := assembleChanAnn(exchange.ann, p.server.identity.PubKey(),
+		exchange.ours, exchange.theirs)
+	if err != nil {
+		// Their signatures are discarded, so they may send them again.
+		exchange.theirs = nil
+		p.annMtx.Unlock()
+		return err
+	}
+	exchange.done = true
+	p.annMtx.Unlock()
+
+	if err := p.server.gossiper.ProcessLocalAnnouncement(ann); err != nil {
+		return err
+	}
+
+	return p.server.chanStatus.setDisabled(ann.ShortChannelID, false)
+}
@@ -0,0 +1,195 @@
+package chanbackup
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/fastsha256"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// multiVersion is the version of the encoding of a Multi.
+const multiVersion = 0
+
+// ErrUnknownVersion is returned when unpacking a backup encoded by a later
+// version of the software.
+var ErrUnknownVersion = errors.New("unknown backup version")
+
+var endian = binary.BigEndian
+
+// Single is the backup of a single channel, holding just enough to locate
+// the channel, and the peer it's with, after all other data is lost. It
+// holds no secrets of the channel's state, so restoring it never risks
+// broadcasting a revoked state. The funds of the channel are instead
+// recovered by having the peer force close it.
+type Single struct {
+	// RemoteLNID is the ID of the node the channel is with.
+	RemoteLNID [32]byte
+
+	// FundingTxid is the txid of the funding transaction of the channel.
+	FundingTxid wire.ShaHash
+
+	// ShortChanID locates the funding output within the chain, left zero
+	// if the funding transaction has yet to confirm.
+	ShortChanID lnwire.ShortChannelID
+
+	// Capacity is the total capacity of the channel.
+	Capacity btcutil.Amount
+}
+
+// NewSingle creates the backup of the open channel.
+func NewSingle(channel *channeldb.OpenChannel) Single {
+	return Single{
+		RemoteLNID:  channel.TheirLNID,
+		FundingTxid: wire.ShaHash(channel.ChanID),
+		ShortChanID: lnwire.NewShortChanIDFromInt(channel.ShortChanID),
+		Capacity:    channel.Capacity,
+	}
+}
+
+// Multi is the backup of each of our channels.
+type Multi struct {
+	Singles []Single
+}
+
+// NewMulti creates the backup of each of the open channels.
+func NewMulti(channels []*channeldb.OpenChannel) *Multi {
+	m := &Multi{Singles: make([]Single, 0, len(channels))}
+	for _, channel := range channels {
+		m.Singles = append(m.Singles, NewSingle(channel))
+	}
+
+	return m
+}
+
+// Encode...
+func (m *Multi) Encode(w io.Writer) error {
+	if len(m.Singles) > 65535 {
+		return fmt.Errorf("too many channels: %v", len(m.Singles))
+	}
+	if err := binary.Write(w, endian, uint8(multiVersion)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, uint16(len(m.Singles))); err != nil {
+		return err
+	}
+
+	for _, s := range m.Singles {
+		if _, err := w.Write(s.RemoteLNID[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(s.FundingTxid[:]); err != nil {
+			return err
+		}
+		if err := binary.Write(w, endian, s.ShortChanID.ToUint64()); err != nil {
+			return err
+		}
+		if err := binary.Write(w, endian, int64(s.Capacity)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode...
+func (m *Multi) Decode(r io.Reader) error {
+	var version uint8
+	if err := binary.Read(r, endian, &version); err != nil {
+		return err
+	}
+	if version != multiVersion {
+		return ErrUnknownVersion
+	}
+
+	var numSingles uint16
+	if err := binary.Read(r, endian, &numSingles); err != nil {
+		return err
+	}
+
+	m.Singles = make([]Single, numSingles)
+	for i := range m.Singles {
+		s := &m.Singles[i]
+		if _, err := io.ReadFull(r, s.RemoteLNID[:]); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, s.FundingTxid[:]); err != nil {
+			return err
+		}
+
+		var chanID uint64
+		if err := binary.Read(r, endian, &chanID); err != nil {
+			return err
+		}
+		s.ShortChanID = lnwire.NewShortChanIDFromInt(chanID)
+
+		var capacity int64
+		if err := binary.Read(r, endian, &capacity); err != nil {
+			return err
+		}
+		s.Capacity = btcutil.Amount(capacity)
+	}
+
+	return nil
+}
+
+// backupKey derives the key backups are encrypted under from our identity
+// key. It's the secret our identity key shares with itself, so that it's
+// recovered along with the identity key, and may be derived by a device
+// holding the identity key without it ever leaving the device.
+func backupKey(identity keychain.SingleKeyECDH) (*snacl.CryptoKey, error) {
+	secret, err := identity.ECDH(identity.PubKey())
+	if err != nil {
+		return nil, err
+	}
+
+	key := snacl.CryptoKey(fastsha256.Sum256(secret))
+	return &key, nil
+}
+
+// PackMulti encodes the backup, encrypting it under a key derived from our
+// identity key, so that it may be handed to our peers to store.
+func PackMulti(m *Multi, identity keychain.SingleKeyECDH) ([]byte, error) {
+	var b bytes.Buffer
+	if err := m.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	key, err := backupKey(identity)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+
+	return key.Encrypt(b.Bytes())
+}
+
+// UnpackMulti decrypts, then decodes the backup packed by PackMulti. An
+// error is returned if the backup wasn't packed under our identity key.
+func UnpackMulti(packed []byte, identity keychain.SingleKeyECDH) (*Multi, error) {
+	key, err := backupKey(identity)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+
+	plaintext, err := key.Decrypt(packed)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Multi{}
+	if err := m.Decode(bytes.NewReader(plaintext)); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
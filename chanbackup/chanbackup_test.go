@@ -0,0 +1,58 @@
+package chanbackup
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func testMulti() *Multi {
+	m := &Multi{}
+	for i := byte(1); i <= 3; i++ {
+		m.Singles = append(m.Singles, Single{
+			RemoteLNID:  [32]byte{i},
+			FundingTxid: [32]byte{i, i},
+			ShortChanID: lnwire.NewShortChanIDFromInt(uint64(i) << 40),
+			Capacity:    btcutil.Amount(100000 * int64(i)),
+		})
+	}
+
+	return m
+}
+
+// TestPackUnpackMulti ensures a backup survives being packed and unpacked
+// under the same identity key, and can't be unpacked under another.
+func TestPackUnpackMulti(t *testing.T) {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	identity := keychain.NewPrivKeySigner(priv)
+
+	m := testMulti()
+	packed, err := PackMulti(m, identity)
+	if err != nil {
+		t.Fatalf("unable to pack backup: %v", err)
+	}
+
+	unpacked, err := UnpackMulti(packed, identity)
+	if err != nil {
+		t.Fatalf("unable to unpack backup: %v", err)
+	}
+	if !reflect.DeepEqual(m, unpacked) {
+		t.Fatalf("backup doesn't match: %v vs %v", m, unpacked)
+	}
+
+	otherPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	other := keychain.NewPrivKeySigner(otherPriv)
+	if _, err := UnpackMulti(packed, other); err == nil {
+		t.Fatalf("backup unpacked under another identity key")
+	}
+}
@@ -0,0 +1,395 @@
+package chanfitness
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrChannelNotFound is returned when requesting the insights of a channel
+// which isn't tracked.
+var ErrChannelNotFound = errors.New("channel not tracked by event store")
+
+// Channel is one of our channels, along with the peer it's with.
+type Channel struct {
+	ChanID lnwire.ShortChannelID
+	Peer   *btcec.PublicKey
+}
+
+// Config is the configuration of the ChannelEventStore.
+type Config struct {
+	// OurKey is our identity key, picking out our channels from those
+	// added to the graph.
+	OurKey *btcec.PublicKey
+
+	// GetOpenChannels returns the channels we have on startup.
+	GetOpenChannels func() ([]*Channel, error)
+
+	// SubscribeTopology returns a client sent each change to the graph,
+	// from which our new channels, and our closed ones, are tracked.
+	SubscribeTopology func() *discovery.TopologyClient
+
+	// Now returns the current time. If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// PeerStatus is whether a peer is online, and how long it has been so.
+type PeerStatus struct {
+	// Online is true while we have a connection to the peer.
+	Online bool
+
+	// Since is the time the peer came online, or went offline. Peers
+	// which haven't connected since the store started have been offline
+	// since then.
+	Since time.Time
+
+	// FlapCount is the number of times the peer has gone offline.
+	FlapCount uint32
+}
+
+// ChannelInsights is the record of the uptime of a channel's peer since we
+// began tracking the channel.
+type ChannelInsights struct {
+	ChanID lnwire.ShortChannelID
+	Peer   *btcec.PublicKey
+
+	// Lifetime is how long we've tracked the channel for, which is since
+	// it opened, or we started, whichever is later.
+	Lifetime time.Duration
+
+	// Uptime is how long the peer has been online within the lifetime.
+	Uptime time.Duration
+
+	// FlapCount is the number of times the peer has gone offline within
+	// the lifetime.
+	FlapCount uint32
+
+	// LastFlap is the time the peer last went offline, or zero if it
+	// hasn't within the lifetime.
+	LastFlap time.Time
+
+	// Online is true while we have a connection to the peer.
+	Online bool
+}
+
+// peerMonitor tracks the connections to a peer.
+type peerMonitor struct {
+	numConns  int
+	since     time.Time
+	flapCount uint32
+	lastFlap  time.Time
+
+	channels map[lnwire.ShortChannelID]*chanMonitor
+}
+
+// chanMonitor tracks the uptime of the peer of a channel.
+type chanMonitor struct {
+	peer  *btcec.PublicKey
+	start time.Time
+
+	// uptime is the time the peer was online within the lifetime of the
+	// channel, up until the peer last went offline.
+	uptime time.Duration
+
+	// startFlaps is the flap count of the peer when the channel was
+	// added.
+	startFlaps uint32
+}
+
+// ChannelEventStore tracks the uptime, and flap rate, of the peers of each
+// of our channels. Peers which flap often, or are rarely online, make for
+// poor channels to route through, or to keep open.
+type ChannelEventStore struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *Config
+
+	// The mutex guards the tracked peers, and channels.
+	sync.Mutex
+	peers     map[[33]byte]*peerMonitor
+	channels  map[lnwire.ShortChannelID]*chanMonitor
+	startTime time.Time
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewChannelEventStore creates a new ChannelEventStore from the passed config.
+func NewChannelEventStore(cfg *Config) *ChannelEventStore {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return &ChannelEventStore{
+		cfg:      cfg,
+		peers:    make(map[[33]byte]*peerMonitor),
+		channels: make(map[lnwire.ShortChannelID]*chanMonitor),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start begins tracking our open channels, and launches the goroutine
+// tracking the channels we open, and close, from now on.
+func (c *ChannelEventStore) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	// We subscribe before fetching our channels, so none opened in
+	// between are missed.
+	client := c.cfg.SubscribeTopology()
+
+	channels, err := c.cfg.GetOpenChannels()
+	if err != nil {
+		client.Cancel()
+		return err
+	}
+
+	c.Lock()
+	c.startTime = c.cfg.Now()
+	for _, channel := range channels {
+		c.addChannel(channel.ChanID, channel.Peer)
+	}
+	c.Unlock()
+
+	c.wg.Add(1)
+	go c.topologyHandler(client)
+
+	return nil
+}
+
+// Stop signals the ChannelEventStore to exit, and waits for it to do so.
+func (c *ChannelEventStore) Stop() {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// topologyHandler tracks each of our channels added to the graph, and stops
+// tracking each of our channels closed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *ChannelEventStore) topologyHandler(client *discovery.TopologyClient) {
+	defer c.wg.Done()
+	defer client.Cancel()
+
+	for {
+		select {
+		case change, ok := <-client.TopologyChanges:
+			if !ok {
+				return
+			}
+
+			c.Lock()
+			for _, newChan := range change.NewChannels {
+				ann := newChan.Announcement
+				switch {
+				case ann.NodeID1.IsEqual(c.cfg.OurKey):
+					c.addChannel(ann.ShortChannelID, ann.NodeID2)
+				case ann.NodeID2.IsEqual(c.cfg.OurKey):
+					c.addChannel(ann.ShortChannelID, ann.NodeID1)
+				}
+			}
+			for _, closedChan := range change.ClosedChannels {
+				c.removeChannel(closedChan.ChanID)
+			}
+			c.Unlock()
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// PeerOnline records a new connection to the peer.
+func (c *ChannelEventStore) PeerOnline(pubKey *btcec.PublicKey) {
+	c.Lock()
+	defer c.Unlock()
+
+	peer := c.peer(pubKey)
+	peer.numConns++
+	if peer.numConns == 1 {
+		peer.since = c.cfg.Now()
+	}
+}
+
+// PeerOffline records the closing of a connection to the peer, which is
+// offline once none remain.
+func (c *ChannelEventStore) PeerOffline(pubKey *btcec.PublicKey) {
+	c.Lock()
+	defer c.Unlock()
+
+	key := serializeKey(pubKey)
+	peer, ok := c.peers[key]
+	if !ok || peer.numConns == 0 {
+		return
+	}
+	peer.numConns--
+	if peer.numConns > 0 {
+		return
+	}
+
+	now := c.cfg.Now()
+	for _, channel := range peer.channels {
+		channel.uptime += onlineWithin(peer.since, channel.start, now)
+	}
+	peer.since = now
+	peer.flapCount++
+	peer.lastFlap = now
+
+	// We've no need to remember peers we've no channels with.
+	if len(peer.channels) == 0 {
+		delete(c.peers, key)
+	}
+}
+
+// GetPeerStatus returns whether the peer is online, and how long it has been
+// so.
+func (c *ChannelEventStore) GetPeerStatus(pubKey *btcec.PublicKey) *PeerStatus {
+	c.Lock()
+	defer c.Unlock()
+
+	peer, ok := c.peers[serializeKey(pubKey)]
+	if !ok {
+		return &PeerStatus{Since: c.startTime}
+	}
+
+	return &PeerStatus{
+		Online:    peer.numConns > 0,
+		Since:     peer.since,
+		FlapCount: peer.flapCount,
+	}
+}
+
+// GetChanInsights returns the insights of the channel, or
+// ErrChannelNotFound if it isn't tracked.
+func (c *ChannelEventStore) GetChanInsights(chanID lnwire.ShortChannelID) (*ChannelInsights, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	channel, ok := c.channels[chanID]
+	if !ok {
+		return nil, ErrChannelNotFound
+	}
+	return c.chanInsights(chanID, channel), nil
+}
+
+// GetAllInsights returns the insights of each tracked channel.
+func (c *ChannelEventStore) GetAllInsights() []*ChannelInsights {
+	c.Lock()
+	defer c.Unlock()
+
+	insights := make([]*ChannelInsights, 0, len(c.channels))
+	for chanID, channel := range c.channels {
+		insights = append(insights, c.chanInsights(chanID, channel))
+	}
+	return insights
+}
+
+// chanInsights returns the insights of the tracked channel.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *ChannelEventStore) chanInsights(chanID lnwire.ShortChannelID,
+	channel *chanMonitor) *ChannelInsights {
+
+	now := c.cfg.Now()
+	peer := c.peers[serializeKey(channel.peer)]
+
+	insights := &ChannelInsights{
+		ChanID:    chanID,
+		Peer:      channel.peer,
+		Lifetime:  now.Sub(channel.start),
+		Uptime:    channel.uptime,
+		FlapCount: peer.flapCount - channel.startFlaps,
+		Online:    peer.numConns > 0,
+	}
+	if insights.FlapCount > 0 {
+		insights.LastFlap = peer.lastFlap
+	}
+	if insights.Online {
+		insights.Uptime += onlineWithin(peer.since, channel.start, now)
+	}
+
+	return insights
+}
+
+// addChannel begins tracking the channel, unless it's already tracked.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *ChannelEventStore) addChannel(chanID lnwire.ShortChannelID,
+	pubKey *btcec.PublicKey) {
+
+	if _, ok := c.channels[chanID]; ok {
+		return
+	}
+
+	peer := c.peer(pubKey)
+	channel := &chanMonitor{
+		peer:       pubKey,
+		start:      c.cfg.Now(),
+		startFlaps: peer.flapCount,
+	}
+	peer.channels[chanID] = channel
+	c.channels[chanID] = channel
+}
+
+// removeChannel stops tracking the channel, along with its peer if it was
+// the last channel with an offline peer.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *ChannelEventStore) removeChannel(chanID lnwire.ShortChannelID) {
+	channel, ok := c.channels[chanID]
+	if !ok {
+		return
+	}
+	delete(c.channels, chanID)
+
+	key := serializeKey(channel.peer)
+	peer := c.peers[key]
+	delete(peer.channels, chanID)
+	if len(peer.channels) == 0 && peer.numConns == 0 {
+		delete(c.peers, key)
+	}
+}
+
+// peer returns the monitor of the peer, creating it if the peer isn't yet
+// tracked.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *ChannelEventStore) peer(pubKey *btcec.PublicKey) *peerMonitor {
+	key := serializeKey(pubKey)
+	peer, ok := c.peers[key]
+	if !ok {
+		peer = &peerMonitor{
+			since:    c.startTime,
+			channels: make(map[lnwire.ShortChannelID]*chanMonitor),
+		}
+		c.peers[key] = peer
+	}
+	return peer
+}
+
+// onlineWithin returns the part of the online period, from since until now,
+// after the channel was added at start.
+func onlineWithin(since, start, now time.Time) time.Duration {
+	if since.Before(start) {
+		since = start
+	}
+	return now.Sub(since)
+}
+
+// serializeKey returns the compressed serialization of the public key.
+func serializeKey(pubKey *btcec.PublicKey) [33]byte {
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+	return key
+}
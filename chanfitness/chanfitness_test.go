@@ -0,0 +1,169 @@
+package chanfitness
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	_, ourKey  = btcec.PrivKeyFromBytes(btcec.S256(), bytes.Repeat([]byte{0x01}, 32))
+	_, peerKey = btcec.PrivKeyFromBytes(btcec.S256(), bytes.Repeat([]byte{0x02}, 32))
+
+	chanID1 = lnwire.ShortChannelID{BlockHeight: 1000, TxIndex: 1}
+	chanID2 = lnwire.ShortChannelID{BlockHeight: 1001, TxIndex: 1}
+)
+
+// testClock is a clock which only moves when told to.
+type testClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.Lock()
+	c.now = c.now.Add(d)
+	c.Unlock()
+}
+
+// startStore returns a started store tracking the passed channels, along
+// with its clock, and the channel it's sent topology changes on.
+func startStore(t *testing.T, channels ...*Channel) (*ChannelEventStore,
+	*testClock, chan *discovery.TopologyChange) {
+
+	clock := &testClock{now: time.Unix(1e9, 0)}
+	changes := make(chan *discovery.TopologyChange)
+	store := NewChannelEventStore(&Config{
+		OurKey: ourKey,
+		GetOpenChannels: func() ([]*Channel, error) {
+			return channels, nil
+		},
+		SubscribeTopology: func() *discovery.TopologyClient {
+			return &discovery.TopologyClient{
+				TopologyChanges: changes,
+				Cancel:          func() {},
+			}
+		},
+		Now: clock.Now,
+	})
+	if err := store.Start(); err != nil {
+		t.Fatalf("unable to start store: %v", err)
+	}
+	return store, clock, changes
+}
+
+// TestChannelEventStoreUptime ensures that the uptime, and flaps, of a
+// channel's peer are only counted while the channel is tracked.
+func TestChannelEventStoreUptime(t *testing.T) {
+	store, clock, _ := startStore(t, &Channel{ChanID: chanID1, Peer: peerKey})
+	defer store.Stop()
+
+	// The peer is online for an hour, offline for an hour, then online
+	// again for an hour, over two connections.
+	clock.advance(time.Hour)
+	store.PeerOnline(peerKey)
+	store.PeerOnline(peerKey)
+	clock.advance(time.Hour)
+	store.PeerOffline(peerKey)
+	if status := store.GetPeerStatus(peerKey); !status.Online {
+		t.Fatalf("peer offline with a connection remaining")
+	}
+	store.PeerOffline(peerKey)
+	clock.advance(time.Hour)
+	store.PeerOnline(peerKey)
+	clock.advance(time.Hour)
+
+	insights, err := store.GetChanInsights(chanID1)
+	if err != nil {
+		t.Fatalf("unable to get insights: %v", err)
+	}
+	if insights.Lifetime != 4*time.Hour {
+		t.Fatalf("expected lifetime of 4h, got %v", insights.Lifetime)
+	}
+	if insights.Uptime != 2*time.Hour {
+		t.Fatalf("expected uptime of 2h, got %v", insights.Uptime)
+	}
+	if insights.FlapCount != 1 || !insights.Online {
+		t.Fatalf("expected one flap while online, got %v, online=%v",
+			insights.FlapCount, insights.Online)
+	}
+	if insights.LastFlap != clock.Now().Add(-2*time.Hour) {
+		t.Fatalf("wrong last flap: %v", insights.LastFlap)
+	}
+
+	status := store.GetPeerStatus(peerKey)
+	if !status.Online || status.Since != clock.Now().Add(-time.Hour) {
+		t.Fatalf("wrong peer status: %v", status)
+	}
+}
+
+// TestChannelEventStoreTopology ensures that our channels added to the
+// graph are tracked from then on, and closed channels are forgotten.
+func TestChannelEventStoreTopology(t *testing.T) {
+	store, clock, changes := startStore(t)
+	defer store.Stop()
+
+	// The peer flaps before the channel opens, which isn't counted
+	// against the channel.
+	store.PeerOnline(peerKey)
+	clock.advance(time.Hour)
+	store.PeerOffline(peerKey)
+	store.PeerOnline(peerKey)
+
+	_, otherKey := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{0x03}, 32))
+	changes <- &discovery.TopologyChange{
+		NewChannels: []*discovery.NewChannel{
+			{Announcement: &lnwire.ChannelAnnouncement{
+				ShortChannelID: chanID1,
+				NodeID1:        peerKey,
+				NodeID2:        ourKey,
+			}},
+			{Announcement: &lnwire.ChannelAnnouncement{
+				ShortChannelID: chanID2,
+				NodeID1:        peerKey,
+				NodeID2:        otherKey,
+			}},
+		},
+	}
+
+	// Once the next change is received, the first has been handled.
+	changes <- &discovery.TopologyChange{}
+
+	clock.advance(time.Hour)
+	insights, err := store.GetChanInsights(chanID1)
+	if err != nil {
+		t.Fatalf("unable to get insights: %v", err)
+	}
+	if insights.Lifetime != time.Hour || insights.Uptime != time.Hour {
+		t.Fatalf("expected lifetime and uptime of 1h, got %v and %v",
+			insights.Lifetime, insights.Uptime)
+	}
+	if insights.FlapCount != 0 || !insights.Peer.IsEqual(peerKey) {
+		t.Fatalf("unexpected insights: %v", insights)
+	}
+	if _, err := store.GetChanInsights(chanID2); err != ErrChannelNotFound {
+		t.Fatalf("expected ErrChannelNotFound for channel of others, "+
+			"got %v", err)
+	}
+
+	changes <- &discovery.TopologyChange{
+		ClosedChannels: []*discovery.ClosedChannel{{ChanID: chanID1}},
+	}
+	changes <- &discovery.TopologyChange{}
+
+	if insights := store.GetAllInsights(); len(insights) != 0 {
+		t.Fatalf("closed channel still tracked")
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/chanfitness"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// defaultZombieOfflineTimeout is the default time the peer of a
+	// channel may remain offline before the channel is deemed a zombie.
+	defaultZombieOfflineTimeout = 14 * 24 * time.Hour
+
+	// defaultZombieInactiveTimeout is the default time a channel may go
+	// without forwarding an HTLC before it's deemed a zombie.
+	defaultZombieInactiveTimeout = 60 * 24 * time.Hour
+
+	// chanJanitorInterval is how often our channels are checked for
+	// zombies.
+	chanJanitorInterval = time.Hour
+)
+
+// zombiePolicy is what the channel janitor does with the zombie channels it
+// finds.
+type zombiePolicy uint8
+
+const (
+	// zombiePolicyNone disables the channel janitor.
+	zombiePolicyNone zombiePolicy = iota
+
+	// zombiePolicyFlag flags zombie channels for the user to review,
+	// leaving them open.
+	zombiePolicyFlag
+
+	// zombiePolicyClose cooperatively closes zombie channels whose peer
+	// is online, flagging those whose peer isn't, as they can't be closed
+	// cooperatively.
+	zombiePolicyClose
+)
+
+// parseZombiePolicy parses the policy of the channel janitor from its name.
+func parseZombiePolicy(s string) (zombiePolicy, error) {
+	switch s {
+	case "none":
+		return zombiePolicyNone, nil
+	case "flag":
+		return zombiePolicyFlag, nil
+	case "close":
+		return zombiePolicyClose, nil
+	default:
+		return 0, fmt.Errorf("unknown zombie channel policy: %v", s)
+	}
+}
+
+// ErrCoopCloseUnsupported is returned when attempting to cooperatively close
+// a channel, as we're yet to be able to negotiate a closing transaction.
+var ErrCoopCloseUnsupported = errors.New("cooperative close not yet " +
+	"supported")
+
+// chanJanitor finds the zombie channels among our own: those whose peer has
+// been offline for longer than the offline timeout, or which haven't
+// forwarded an HTLC within the inactive timeout. Depending on its policy,
+// the janitor either flags zombie channels for the user to review, or
+// cooperatively closes them.
+type chanJanitor struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	policy zombiePolicy
+
+	// offlineTimeout is how long the peer of a channel may remain offline
+	// before the channel is a zombie. If zero, channels are never zombies
+	// for their peer being offline.
+	offlineTimeout time.Duration
+
+	// inactiveTimeout is how long a channel may go without forwarding an
+	// HTLC before it's a zombie. If zero, channels are never zombies for
+	// their lack of activity.
+	inactiveTimeout time.Duration
+
+	db     *channeldb.DB
+	events *chanfitness.ChannelEventStore
+
+	// closeChannel cooperatively closes the channel with the peer.
+	closeChannel func(chanID lnwire.ShortChannelID,
+		peer *btcec.PublicKey) error
+
+	// The mutex guards the flagged channels.
+	sync.Mutex
+
+	// flagged maps each zombie channel to the reason it's a zombie.
+	flagged map[lnwire.ShortChannelID]string
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChanJanitor creates a new chanJanitor, dealing with the zombie channels
+// it finds according to the passed policy.
+func newChanJanitor(policy zombiePolicy, offlineTimeout,
+	inactiveTimeout time.Duration, db *channeldb.DB,
+	events *chanfitness.ChannelEventStore,
+	closeChannel func(lnwire.ShortChannelID, *btcec.PublicKey) error) *chanJanitor {
+
+	return &chanJanitor{
+		policy:          policy,
+		offlineTimeout:  offlineTimeout,
+		inactiveTimeout: inactiveTimeout,
+		db:              db,
+		events:          events,
+		closeChannel:    closeChannel,
+		flagged:         make(map[lnwire.ShortChannelID]string),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine checking our channels for zombies, unless
+// the janitor is disabled.
+func (j *chanJanitor) Start() {
+	if !atomic.CompareAndSwapUint32(&j.started, 0, 1) {
+		return
+	}
+
+	if j.policy == zombiePolicyNone {
+		return
+	}
+
+	j.wg.Add(1)
+	go j.janitor()
+}
+
+// Stop signals the chanJanitor to exit, and waits for it to do so.
+func (j *chanJanitor) Stop() {
+	if !atomic.CompareAndSwapUint32(&j.stopped, 0, 1) {
+		return
+	}
+
+	close(j.quit)
+	j.wg.Wait()
+}
+
+// ZombieReason returns the reason the channel was flagged as a zombie, or
+// the empty string if it isn't one.
+func (j *chanJanitor) ZombieReason(chanID lnwire.ShortChannelID) string {
+	j.Lock()
+	defer j.Unlock()
+
+	return j.flagged[chanID]
+}
+
+// janitor checks our channels for zombies each chanJanitorInterval.
+//
+// NOTE: This MUST be run as a goroutine.
+func (j *chanJanitor) janitor() {
+	defer j.wg.Done()
+
+	ticker := time.NewTicker(chanJanitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := j.sweepZombies(time.Now()); err != nil {
+				fmt.Printf("unable to check for zombie "+
+					"channels: %v\n", err)
+			}
+
+		case <-j.quit:
+			return
+		}
+	}
+}
+
+// sweepZombies finds our zombie channels, flagging or closing each as our
+// policy dictates, and unflags channels which are no longer zombies.
+func (j *chanJanitor) sweepZombies(now time.Time) error {
+	active := make(map[lnwire.ShortChannelID]struct{})
+	if j.inactiveTimeout != 0 {
+		events, err := j.db.FetchForwardingEvents(
+			now.Add(-j.inactiveTimeout), time.Time{})
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			active[event.IncomingChanID] = struct{}{}
+			active[event.OutgoingChanID] = struct{}{}
+		}
+	}
+
+	flagged := make(map[lnwire.ShortChannelID]string)
+	for _, insights := range j.events.GetAllInsights() {
+		status := j.events.GetPeerStatus(insights.Peer)
+		_, isActive := active[insights.ChanID]
+
+		reason := j.zombieReason(insights, status, isActive, now)
+		if reason == "" {
+			continue
+		}
+
+		// Channels can only be closed cooperatively while their peer
+		// is online, so those of offline peers are only flagged.
+		if j.policy == zombiePolicyClose && status.Online {
+			err := j.closeChannel(insights.ChanID, insights.Peer)
+			if err == nil {
+				continue
+			}
+
+			fmt.Printf("unable to close zombie channel %v: %v\n",
+				insights.ChanID, err)
+		}
+
+		flagged[insights.ChanID] = reason
+	}
+
+	j.Lock()
+	j.flagged = flagged
+	j.Unlock()
+
+	return nil
+}
+
+// zombieReason returns the reason the channel is a zombie, or the empty
+// string if it isn't one. Channels are only deemed inactive once we've
+// tracked them for the whole inactive timeout.
+func (j *chanJanitor) zombieReason(insights *chanfitness.ChannelInsights,
+	status *chanfitness.PeerStatus, active bool, now time.Time) string {
+
+	switch {
+	case j.offlineTimeout != 0 && !status.Online &&
+		now.Sub(status.Since) >= j.offlineTimeout:
+
+		return fmt.Sprintf("peer offline for over %v", j.offlineTimeout)
+
+	case j.inactiveTimeout != 0 && !active &&
+		insights.Lifetime >= j.inactiveTimeout:
+
+		return fmt.Sprintf("no htlcs forwarded in %v", j.inactiveTimeout)
+
+	default:
+		return ""
+	}
+}
+
+// coopCloseChannel cooperatively closes our channel with the peer.
+//
+// TODO: negotiate the closing transaction over CloseRequest, and
+// CloseComplete, once the wallet is able to sign one
+func (s *server) coopCloseChannel(chanID lnwire.ShortChannelID,
+	peer *btcec.PublicKey) error {
+
+	return ErrCoopCloseUnsupported
+}
@@ -0,0 +1,434 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// watchOnlyAccountBucket houses all watch-only accounts, keyed by
+	// their name.
+	watchOnlyAccountBucket = []byte("wa")
+
+	// watchOnlyUtxoBucket houses the unspent outputs of all watch-only
+	// accounts, keyed by their outpoint.
+	watchOnlyUtxoBucket = []byte("wu")
+)
+
+// WatchOnlyAccount is an account for which we only hold public keys. The
+// private keys are held by an external signer, so any transaction spending
+// the account's outputs must be signed elsewhere.
+type WatchOnlyAccount struct {
+	Name string
+
+	// ExtendedKey is the base58 encoded extended public key of the
+	// account. It's empty if the account only holds individually imported
+	// keys.
+	ExtendedKey string
+
+	// MasterKeyFingerprint and DerivationPath describe how the extended
+	// key was derived from the external signer's master key, allowing the
+	// signer to locate the keys of the account.
+	MasterKeyFingerprint uint32
+	DerivationPath       []uint32
+
+	// NextExternalIndex and NextInternalIndex are the indexes of the next
+	// unused receiving and change keys of the account.
+	NextExternalIndex uint32
+	NextInternalIndex uint32
+
+	// ImportedKeys are compressed public keys which were imported
+	// individually, rather than derived from the extended key.
+	ImportedKeys [][33]byte
+}
+
+// WatchOnlyUtxo is an unspent output paying to one of the keys of a watch-only
+// account.
+type WatchOnlyUtxo struct {
+	OutPoint wire.OutPoint
+
+	// Account is the name of the account the output belongs to.
+	Account string
+
+	Value    btcutil.Amount
+	PkScript []byte
+
+	// BlockHeight is the height of the block the output was confirmed
+	// in, or zero if it's unconfirmed.
+	BlockHeight uint32
+
+	// PubKey is the compressed public key the output pays to.
+	PubKey [33]byte
+
+	// DerivationPath is the full path from the master key to PubKey. It's
+	// empty for individually imported keys.
+	DerivationPath []uint32
+
+	// PrevTx is the transaction which created the output. Signers require
+	// it in order to verify the value of the input they're signing.
+	PrevTx *wire.MsgTx
+}
+
+// PutWatchOnlyAccount adds the account to the database, overwriting any
+// existing account of the same name.
+func (d *DB) PutWatchOnlyAccount(account *WatchOnlyAccount) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		accounts, err := tx.RootBucket().CreateBucketIfNotExists(
+			watchOnlyAccountBucket)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := account.Encode(&b); err != nil {
+			return err
+		}
+		return accounts.Put([]byte(account.Name), b.Bytes())
+	})
+}
+
+// FetchWatchOnlyAccount looks up the watch-only account of the passed name.
+func (d *DB) FetchWatchOnlyAccount(name string) (*WatchOnlyAccount, error) {
+	var account *WatchOnlyAccount
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		accounts := tx.RootBucket().Bucket(watchOnlyAccountBucket)
+		if accounts == nil {
+			return ErrAccountNotFound
+		}
+
+		serializedAccount := accounts.Get([]byte(name))
+		if serializedAccount == nil {
+			return ErrAccountNotFound
+		}
+
+		account = &WatchOnlyAccount{}
+		return account.Decode(bytes.NewReader(serializedAccount))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// FetchAllWatchOnlyAccounts returns all watch-only accounts.
+func (d *DB) FetchAllWatchOnlyAccounts() ([]*WatchOnlyAccount, error) {
+	var accounts []*WatchOnlyAccount
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		accountBucket := tx.RootBucket().Bucket(watchOnlyAccountBucket)
+		if accountBucket == nil {
+			return nil
+		}
+
+		return accountBucket.ForEach(func(k, v []byte) error {
+			account := &WatchOnlyAccount{}
+			if err := account.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+			accounts = append(accounts, account)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// PutWatchOnlyUtxo adds the unspent output to the database, overwriting any
+// existing entry for the same outpoint, such as when an unconfirmed output
+// confirms.
+func (d *DB) PutWatchOnlyUtxo(utxo *WatchOnlyUtxo) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		utxos, err := tx.RootBucket().CreateBucketIfNotExists(
+			watchOnlyUtxoBucket)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := utxo.Encode(&b); err != nil {
+			return err
+		}
+
+		var k bytes.Buffer
+		if err := writeOutpoint(&k, &utxo.OutPoint); err != nil {
+			return err
+		}
+		return utxos.Put(k.Bytes(), b.Bytes())
+	})
+}
+
+// DeleteWatchOnlyUtxo removes the output once it has been spent.
+func (d *DB) DeleteWatchOnlyUtxo(op *wire.OutPoint) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		utxos := tx.RootBucket().Bucket(watchOnlyUtxoBucket)
+		if utxos == nil {
+			return nil
+		}
+
+		var k bytes.Buffer
+		if err := writeOutpoint(&k, op); err != nil {
+			return err
+		}
+		return utxos.Delete(k.Bytes())
+	})
+}
+
+// FetchWatchOnlyUtxos returns the unspent outputs of the named account, or
+// of all watch-only accounts if the name is empty.
+func (d *DB) FetchWatchOnlyUtxos(account string) ([]*WatchOnlyUtxo, error) {
+	var utxos []*WatchOnlyUtxo
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		utxoBucket := tx.RootBucket().Bucket(watchOnlyUtxoBucket)
+		if utxoBucket == nil {
+			return nil
+		}
+
+		return utxoBucket.ForEach(func(k, v []byte) error {
+			utxo := &WatchOnlyUtxo{}
+			if err := utxo.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+			if account == "" || utxo.Account == account {
+				utxos = append(utxos, utxo)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return utxos, nil
+}
+
+// Encode...
+func (a *WatchOnlyAccount) Encode(w io.Writer) error {
+	if err := writeString(w, a.Name); err != nil {
+		return err
+	}
+	if err := writeString(w, a.ExtendedKey); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, a.MasterKeyFingerprint); err != nil {
+		return err
+	}
+	if err := writePath(w, a.DerivationPath); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, a.NextExternalIndex); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, a.NextInternalIndex); err != nil {
+		return err
+	}
+
+	if len(a.ImportedKeys) > 65535 {
+		return fmt.Errorf("too many imported keys")
+	}
+	if err := binary.Write(w, endian, uint16(len(a.ImportedKeys))); err != nil {
+		return err
+	}
+	for _, key := range a.ImportedKeys {
+		if _, err := w.Write(key[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode...
+func (a *WatchOnlyAccount) Decode(r io.Reader) error {
+	var err error
+	if a.Name, err = readString(r); err != nil {
+		return err
+	}
+	if a.ExtendedKey, err = readString(r); err != nil {
+		return err
+	}
+	if err := binary.Read(r, endian, &a.MasterKeyFingerprint); err != nil {
+		return err
+	}
+	if a.DerivationPath, err = readPath(r); err != nil {
+		return err
+	}
+	if err := binary.Read(r, endian, &a.NextExternalIndex); err != nil {
+		return err
+	}
+	if err := binary.Read(r, endian, &a.NextInternalIndex); err != nil {
+		return err
+	}
+
+	var numKeys uint16
+	if err := binary.Read(r, endian, &numKeys); err != nil {
+		return err
+	}
+	for i := uint16(0); i < numKeys; i++ {
+		var key [33]byte
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return err
+		}
+		a.ImportedKeys = append(a.ImportedKeys, key)
+	}
+
+	return nil
+}
+
+// Encode...
+func (u *WatchOnlyUtxo) Encode(w io.Writer) error {
+	if err := writeOutpoint(w, &u.OutPoint); err != nil {
+		return err
+	}
+	if err := writeString(w, u.Account); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, int64(u.Value)); err != nil {
+		return err
+	}
+
+	if len(u.PkScript) > 65535 {
+		return fmt.Errorf("pkscript too long")
+	}
+	if err := binary.Write(w, endian, uint16(len(u.PkScript))); err != nil {
+		return err
+	}
+	if _, err := w.Write(u.PkScript); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, endian, u.BlockHeight); err != nil {
+		return err
+	}
+	if _, err := w.Write(u.PubKey[:]); err != nil {
+		return err
+	}
+	if err := writePath(w, u.DerivationPath); err != nil {
+		return err
+	}
+
+	return u.PrevTx.Serialize(w)
+}
+
+// Decode...
+func (u *WatchOnlyUtxo) Decode(r io.Reader) error {
+	if err := readOutpoint(r, &u.OutPoint); err != nil {
+		return err
+	}
+
+	var err error
+	if u.Account, err = readString(r); err != nil {
+		return err
+	}
+
+	var value int64
+	if err := binary.Read(r, endian, &value); err != nil {
+		return err
+	}
+	u.Value = btcutil.Amount(value)
+
+	var scriptLen uint16
+	if err := binary.Read(r, endian, &scriptLen); err != nil {
+		return err
+	}
+	u.PkScript = make([]byte, scriptLen)
+	if _, err := io.ReadFull(r, u.PkScript); err != nil {
+		return err
+	}
+
+	if err := binary.Read(r, endian, &u.BlockHeight); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, u.PubKey[:]); err != nil {
+		return err
+	}
+	if u.DerivationPath, err = readPath(r); err != nil {
+		return err
+	}
+
+	u.PrevTx = wire.NewMsgTx()
+	return u.PrevTx.Deserialize(r)
+}
+
+// writeString writes the string to w, prefixed by its length.
+func writeString(w io.Writer, s string) error {
+	if len(s) > 65535 {
+		return fmt.Errorf("string too long")
+	}
+	if err := binary.Write(w, endian, uint16(len(s))); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte(s))
+	return err
+}
+
+// readString reads a length prefixed string from r.
+func readString(r io.Reader) (string, error) {
+	var strLen uint16
+	if err := binary.Read(r, endian, &strLen); err != nil {
+		return "", err
+	}
+	s := make([]byte, strLen)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// writePath writes the derivation path to w, prefixed by its length.
+func writePath(w io.Writer, path []uint32) error {
+	if len(path) > 255 {
+		return fmt.Errorf("derivation path too long")
+	}
+	if err := binary.Write(w, endian, uint8(len(path))); err != nil {
+		return err
+	}
+	for _, index := range path {
+		if err := binary.Write(w, endian, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPath reads a length prefixed derivation path from r.
+func readPath(r io.Reader) ([]uint32, error) {
+	var pathLen uint8
+	if err := binary.Read(r, endian, &pathLen); err != nil {
+		return nil, err
+	}
+
+	var path []uint32
+	for i := uint8(0); i < pathLen; i++ {
+		var index uint32
+		if err := binary.Read(r, endian, &index); err != nil {
+			return nil, err
+		}
+		path = append(path, index)
+	}
+	return path, nil
+}
+
+// writeOutpoint writes the outpoint to w: the txid followed by the index.
+func writeOutpoint(w io.Writer, op *wire.OutPoint) error {
+	if _, err := w.Write(op.Hash[:]); err != nil {
+		return err
+	}
+	return binary.Write(w, endian, op.Index)
+}
+
+// readOutpoint reads an outpoint from r.
+func readOutpoint(r io.Reader, op *wire.OutPoint) error {
+	if _, err := io.ReadFull(r, op.Hash[:]); err != nil {
+		return err
+	}
+	return binary.Read(r, endian, &op.Index)
+}
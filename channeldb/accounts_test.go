@@ -0,0 +1,71 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestWatchOnlyAccountEncodeDecode(t *testing.T) {
+	account := &WatchOnlyAccount{
+		Name:                 "cold",
+		ExtendedKey:          "tpubDC8msFGeGuwnKG9Upg7DM2b4DaRqg3CUZa5g8v2SRQ6K4NSkxUgd7HsL2XVWbVm39yBA4LAxysQAm397zwQSQoQgewGiYZqrA9DsP4zbQ1M",
+		MasterKeyFingerprint: 0xdeadbeef,
+		DerivationPath:       []uint32{0x8000002c, 0x80000001, 0x80000000},
+		NextExternalIndex:    5,
+		NextInternalIndex:    2,
+		ImportedKeys:         [][33]byte{{0x02, 0x01}, {0x03, 0x02}},
+	}
+
+	var b bytes.Buffer
+	if err := account.Encode(&b); err != nil {
+		t.Fatalf("unable to encode account: %v", err)
+	}
+
+	newAccount := &WatchOnlyAccount{}
+	if err := newAccount.Decode(&b); err != nil {
+		t.Fatalf("unable to decode account: %v", err)
+	}
+
+	if !reflect.DeepEqual(account, newAccount) {
+		t.Fatalf("account doesn't match: %v vs %v", account, newAccount)
+	}
+}
+
+func TestWatchOnlyUtxoEncodeDecode(t *testing.T) {
+	prevTx := wire.NewMsgTx()
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, []byte{0x51}))
+	prevTx.AddTxOut(wire.NewTxOut(5000, []byte{0x76, 0xa9}))
+
+	utxo := &WatchOnlyUtxo{
+		OutPoint:       wire.OutPoint{Hash: prevTx.TxSha(), Index: 0},
+		Account:        "cold",
+		Value:          btcutil.Amount(5000),
+		PkScript:       []byte{0x76, 0xa9},
+		BlockHeight:    100,
+		PubKey:         [33]byte{0x02, 0x05},
+		DerivationPath: []uint32{0x8000002c, 0, 3},
+		PrevTx:         prevTx,
+	}
+
+	var b bytes.Buffer
+	if err := utxo.Encode(&b); err != nil {
+		t.Fatalf("unable to encode utxo: %v", err)
+	}
+
+	newUtxo := &WatchOnlyUtxo{}
+	if err := newUtxo.Decode(&b); err != nil {
+		t.Fatalf("unable to decode utxo: %v", err)
+	}
+
+	if newUtxo.PrevTx.TxSha() != prevTx.TxSha() {
+		t.Fatalf("previous transaction doesn't match")
+	}
+	newUtxo.PrevTx = prevTx
+	if !reflect.DeepEqual(utxo, newUtxo) {
+		t.Fatalf("utxo doesn't match: %v vs %v", utxo, newUtxo)
+	}
+}
@@ -0,0 +1,214 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// addrBookBucket houses a nested bucket for each node we know the
+	// addresses of, keyed by its serialized public key. Each nested
+	// bucket maps an address to its NodeAddr.
+	addrBookBucket = []byte("ab")
+)
+
+// MaxAddrsPerNode is the number of addresses kept for each node. Beyond it,
+// the stalest address is evicted to make room for a new one.
+const MaxAddrsPerNode = 10
+
+// NodeAddr is an address a node is known to be reachable at.
+type NodeAddr struct {
+	// Addr is the host:port of the address.
+	Addr string
+
+	// LastSeen is the latest time we learned of the address.
+	LastSeen time.Time
+
+	// LastSuccess is the latest time we connected to the node at the
+	// address, or zero if we never have.
+	LastSuccess time.Time
+}
+
+// staler returns true if the address is less likely to reach the node than
+// the other address. Addresses we've connected to recently are preferred,
+// then those we've learned of recently.
+func (a *NodeAddr) staler(other *NodeAddr) bool {
+	if !a.LastSuccess.Equal(other.LastSuccess) {
+		return a.LastSuccess.Before(other.LastSuccess)
+	}
+	return a.LastSeen.Before(other.LastSeen)
+}
+
+// AddNodeAddr records that we've learned of the address of the node at the
+// passed time.
+func (d *DB) AddNodeAddr(pubKey [33]byte, addr string, seen time.Time) error {
+	return d.updateNodeAddr(pubKey, addr, func(a *NodeAddr) {
+		if seen.After(a.LastSeen) {
+			a.LastSeen = seen
+		}
+	})
+}
+
+// MarkNodeAddrSuccess records that we connected to the node at the address
+// at the passed time.
+func (d *DB) MarkNodeAddrSuccess(pubKey [33]byte, addr string,
+	connected time.Time) error {
+
+	return d.updateNodeAddr(pubKey, addr, func(a *NodeAddr) {
+		if connected.After(a.LastSeen) {
+			a.LastSeen = connected
+		}
+		if connected.After(a.LastSuccess) {
+			a.LastSuccess = connected
+		}
+	})
+}
+
+// updateNodeAddr applies the update to the address of the node, adding the
+// address if it's new, and evicting the node's stalest address should it
+// then have too many.
+func (d *DB) updateNodeAddr(pubKey [33]byte, addr string,
+	update func(*NodeAddr)) error {
+
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		book, err := tx.RootBucket().CreateBucketIfNotExists(
+			addrBookBucket)
+		if err != nil {
+			return err
+		}
+		nodeAddrs, err := book.CreateBucketIfNotExists(pubKey[:])
+		if err != nil {
+			return err
+		}
+
+		a := &NodeAddr{Addr: addr}
+		if addrBytes := nodeAddrs.Get([]byte(addr)); addrBytes != nil {
+			if err := a.Decode(bytes.NewReader(addrBytes)); err != nil {
+				return err
+			}
+		}
+		update(a)
+
+		var b bytes.Buffer
+		if err := a.Encode(&b); err != nil {
+			return err
+		}
+		if err := nodeAddrs.Put([]byte(addr), b.Bytes()); err != nil {
+			return err
+		}
+
+		addrs, err := fetchNodeAddrs(nodeAddrs)
+		if err != nil {
+			return err
+		}
+		for len(addrs) > MaxAddrsPerNode {
+			stalest := addrs[len(addrs)-1]
+			if err := nodeAddrs.Delete([]byte(stalest.Addr)); err != nil {
+				return err
+			}
+			addrs = addrs[:len(addrs)-1]
+		}
+
+		return nil
+	})
+}
+
+// FetchNodeAddrs returns each known address of the node, ordered from the
+// most to the least likely to reach it.
+func (d *DB) FetchNodeAddrs(pubKey [33]byte) ([]*NodeAddr, error) {
+	var addrs []*NodeAddr
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		book := tx.RootBucket().Bucket(addrBookBucket)
+		if book == nil {
+			return nil
+		}
+		nodeAddrs := book.Bucket(pubKey[:])
+		if nodeAddrs == nil {
+			return nil
+		}
+
+		var err error
+		addrs, err = fetchNodeAddrs(nodeAddrs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+// fetchNodeAddrs returns the addresses within the bucket of a node, ordered
+// from the most to the least likely to reach it.
+func fetchNodeAddrs(nodeAddrs walletdb.Bucket) ([]*NodeAddr, error) {
+	var addrs []*NodeAddr
+	err := nodeAddrs.ForEach(func(k, v []byte) error {
+		a := &NodeAddr{Addr: string(k)}
+		if err := a.Decode(bytes.NewReader(v)); err != nil {
+			return err
+		}
+		addrs = append(addrs, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(addrsByFreshness(addrs))
+	return addrs, nil
+}
+
+// addrsByFreshness implements sort.Interface, sorting addresses from the
+// most to the least likely to reach their node.
+type addrsByFreshness []*NodeAddr
+
+func (a addrsByFreshness) Len() int           { return len(a) }
+func (a addrsByFreshness) Less(i, j int) bool { return a[j].staler(a[i]) }
+func (a addrsByFreshness) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+
+// Encode writes the timestamps of the address, its Addr being the key it's
+// stored under.
+func (a *NodeAddr) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, unixOrZero(a.LastSeen)); err != nil {
+		return err
+	}
+	return binary.Write(w, endian, unixOrZero(a.LastSuccess))
+}
+
+// Decode...
+func (a *NodeAddr) Decode(r io.Reader) error {
+	var lastSeen, lastSuccess int64
+	if err := binary.Read(r, endian, &lastSeen); err != nil {
+		return err
+	}
+	if err := binary.Read(r, endian, &lastSuccess); err != nil {
+		return err
+	}
+	a.LastSeen = timeOrZero(lastSeen)
+	a.LastSuccess = timeOrZero(lastSuccess)
+
+	return nil
+}
+
+// unixOrZero returns the unix timestamp of the time, or zero for the zero
+// time.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// timeOrZero returns the time of the unix timestamp, or the zero time for a
+// timestamp of zero.
+func timeOrZero(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
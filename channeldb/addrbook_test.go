@@ -0,0 +1,93 @@
+package channeldb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNodeAddrOrdering(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	pubKey := [33]byte{0x02, 0x01}
+	now := time.Unix(time.Now().Unix(), 0)
+
+	// An address we've connected at is preferred over those we've only
+	// learned of, however recently.
+	if err := db.MarkNodeAddrSuccess(pubKey, "10.0.0.1:9735",
+		now.Add(-time.Hour)); err != nil {
+
+		t.Fatalf("unable to mark address success: %v", err)
+	}
+	if err := db.AddNodeAddr(pubKey, "10.0.0.2:9735", now); err != nil {
+		t.Fatalf("unable to add address: %v", err)
+	}
+	if err := db.AddNodeAddr(pubKey, "10.0.0.3:9735",
+		now.Add(-time.Minute)); err != nil {
+
+		t.Fatalf("unable to add address: %v", err)
+	}
+
+	addrs, err := db.FetchNodeAddrs(pubKey)
+	if err != nil {
+		t.Fatalf("unable to fetch addresses: %v", err)
+	}
+	expected := []string{"10.0.0.1:9735", "10.0.0.2:9735", "10.0.0.3:9735"}
+	if len(addrs) != len(expected) {
+		t.Fatalf("expected %v addresses, got %v", len(expected),
+			len(addrs))
+	}
+	for i, addr := range addrs {
+		if addr.Addr != expected[i] {
+			t.Fatalf("expected address %v at %v, got %v",
+				expected[i], i, addr.Addr)
+		}
+	}
+	if !addrs[0].LastSuccess.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected last success: %v", addrs[0].LastSuccess)
+	}
+	if !addrs[1].LastSuccess.IsZero() {
+		t.Fatalf("expected no last success, got %v",
+			addrs[1].LastSuccess)
+	}
+
+	// Other nodes have no addresses.
+	addrs, err = db.FetchNodeAddrs([33]byte{0x03})
+	if err != nil {
+		t.Fatalf("unable to fetch addresses: %v", err)
+	}
+	if len(addrs) != 0 {
+		t.Fatalf("expected no addresses, got %v", len(addrs))
+	}
+}
+
+func TestNodeAddrEviction(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	pubKey := [33]byte{0x02, 0x01}
+	start := time.Unix(time.Now().Unix(), 0)
+
+	for i := 0; i <= MaxAddrsPerNode; i++ {
+		addr := fmt.Sprintf("10.0.0.%v:9735", i)
+		seen := start.Add(time.Duration(i) * time.Second)
+		if err := db.AddNodeAddr(pubKey, addr, seen); err != nil {
+			t.Fatalf("unable to add address: %v", err)
+		}
+	}
+
+	addrs, err := db.FetchNodeAddrs(pubKey)
+	if err != nil {
+		t.Fatalf("unable to fetch addresses: %v", err)
+	}
+	if len(addrs) != MaxAddrsPerNode {
+		t.Fatalf("expected %v addresses, got %v", MaxAddrsPerNode,
+			len(addrs))
+	}
+	for _, addr := range addrs {
+		if addr.Addr == "10.0.0.0:9735" {
+			t.Fatalf("stalest address wasn't evicted")
+		}
+	}
+}
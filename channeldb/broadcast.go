@@ -0,0 +1,138 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// pendingBroadcastBucket houses all locally originated transactions
+	// which have yet to confirm, keyed by their txid.
+	pendingBroadcastBucket = []byte("pb")
+)
+
+// PendingBroadcast is a transaction we've published which has yet to
+// confirm, such as a funding transaction, a close, or a sweep. It's
+// re-published until it either confirms, or is double spent.
+type PendingBroadcast struct {
+	Tx *wire.MsgTx
+
+	// Label describes what the transaction is for, e.g. "funding".
+	Label string
+
+	// FirstBroadcast is the time the transaction was first published.
+	FirstBroadcast time.Time
+
+	// Attempts is the number of times the transaction has been
+	// published.
+	Attempts uint32
+
+	// LastError is the error returned when last publishing the
+	// transaction, or empty if it was accepted.
+	LastError string
+}
+
+// PutPendingBroadcast adds the transaction to the database, overwriting any
+// existing entry for it.
+func (d *DB) PutPendingBroadcast(p *PendingBroadcast) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		pending, err := tx.RootBucket().CreateBucketIfNotExists(
+			pendingBroadcastBucket)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := p.Encode(&b); err != nil {
+			return err
+		}
+		txid := p.Tx.TxSha()
+		return pending.Put(txid[:], b.Bytes())
+	})
+}
+
+// DeletePendingBroadcast removes the transaction once it has confirmed, or
+// been double spent.
+func (d *DB) DeletePendingBroadcast(txid *wire.ShaHash) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		pending := tx.RootBucket().Bucket(pendingBroadcastBucket)
+		if pending == nil {
+			return nil
+		}
+		return pending.Delete(txid[:])
+	})
+}
+
+// FetchPendingBroadcasts returns all transactions which have yet to confirm.
+func (d *DB) FetchPendingBroadcasts() ([]*PendingBroadcast, error) {
+	var broadcasts []*PendingBroadcast
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		pending := tx.RootBucket().Bucket(pendingBroadcastBucket)
+		if pending == nil {
+			return nil
+		}
+
+		return pending.ForEach(func(k, v []byte) error {
+			p := &PendingBroadcast{}
+			if err := p.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+			broadcasts = append(broadcasts, p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcasts, nil
+}
+
+// Encode...
+func (p *PendingBroadcast) Encode(w io.Writer) error {
+	if err := p.Tx.Serialize(w); err != nil {
+		return err
+	}
+	if err := writeString(w, p.Label); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, p.FirstBroadcast.Unix()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, p.Attempts); err != nil {
+		return err
+	}
+
+	return writeString(w, p.LastError)
+}
+
+// Decode...
+func (p *PendingBroadcast) Decode(r io.Reader) error {
+	p.Tx = wire.NewMsgTx()
+	if err := p.Tx.Deserialize(r); err != nil {
+		return err
+	}
+
+	var err error
+	if p.Label, err = readString(r); err != nil {
+		return err
+	}
+
+	var unixSecs int64
+	if err := binary.Read(r, endian, &unixSecs); err != nil {
+		return err
+	}
+	p.FirstBroadcast = time.Unix(unixSecs, 0)
+
+	if err := binary.Read(r, endian, &p.Attempts); err != nil {
+		return err
+	}
+
+	p.LastError, err = readString(r)
+	return err
+}
@@ -0,0 +1,42 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestPendingBroadcastEncodeDecode(t *testing.T) {
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 3}, []byte{0x51}))
+	tx.AddTxOut(wire.NewTxOut(5000, []byte{0x76, 0xa9}))
+
+	p := &PendingBroadcast{
+		Tx:             tx,
+		Label:          "funding",
+		FirstBroadcast: time.Unix(time.Now().Unix(), 0),
+		Attempts:       4,
+		LastError:      "connection refused",
+	}
+
+	var b bytes.Buffer
+	if err := p.Encode(&b); err != nil {
+		t.Fatalf("unable to encode broadcast: %v", err)
+	}
+
+	newP := &PendingBroadcast{}
+	if err := newP.Decode(&b); err != nil {
+		t.Fatalf("unable to decode broadcast: %v", err)
+	}
+
+	if newP.Tx.TxSha() != tx.TxSha() {
+		t.Fatalf("transaction doesn't match")
+	}
+	newP.Tx = tx
+	if !reflect.DeepEqual(p, newP) {
+		t.Fatalf("broadcast doesn't match: %v vs %v", p, newP)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/shachain"
 )
 
@@ -23,16 +25,49 @@ var (
 
 	identityKey = []byte("idkey")
 
+	// aliasIndexKey stores the index of the last alias short channel ID
+	// handed out.
+	aliasIndexKey = []byte("aliasidx")
+
 	// TODO(roasbeef): replace w/ tesnet-L also revisit dependancy...
 
 	// ActiveNetParams ...
 	ActiveNetParams = &chaincfg.TestNet3Params
 )
 
-// Payment ...
-type Payment struct {
-	// r [32]byte
-	// path *Route
+// CommitmentType denotes the format of the commitment transactions of a
+// channel.
+type CommitmentType uint8
+
+const (
+	// CommitmentLegacy is the original commitment format, with a single
+	// output paying to each party.
+	CommitmentLegacy CommitmentType = iota
+
+	// CommitmentAnchors adds a small anchor output for each party to the
+	// commitment transaction. Either party may spend their anchor to
+	// bump the fee of the commitment transaction via CPFP, should it get
+	// stuck after a force close.
+	CommitmentAnchors
+
+	// CommitmentStaticRemoteKey pays the output of the non-broadcasting
+	// party directly to their commitment key via P2PKH. They may then
+	// sweep it without any knowledge of the channel's state.
+	CommitmentStaticRemoteKey
+)
+
+// String returns a human readable version of the commitment type.
+func (c CommitmentType) String() string {
+	switch c {
+	case CommitmentLegacy:
+		return "legacy"
+	case CommitmentAnchors:
+		return "anchors"
+	case CommitmentStaticRemoteKey:
+		return "static_remote_key"
+	default:
+		return "unknown"
+	}
 }
 
 // ClosedChannel ...
@@ -62,8 +97,8 @@ type OpenChannel struct {
 	// Tracking total channel capacity, and the amount of funds allocated
 	// to each side.
 	Capacity     btcutil.Amount
-	OurBalance   btcutil.Amount
-	TheirBalance btcutil.Amount
+	OurBalance   lnwire.MilliSatoshi
+	TheirBalance lnwire.MilliSatoshi
 
 	// Commitment transactions for both sides (they're asymmetric). Our
 	// commitment transaction includes a valid sigScript, and is ready for
@@ -92,11 +127,57 @@ type OpenChannel struct {
 	// In blocks
 	CsvDelay uint32
 
+	// CommitType is the format of the commitment transactions agreed upon
+	// during funding.
+	CommitType CommitmentType
+
 	// TODO(roasbeef): track fees, other stats?
 	NumUpdates            uint64
 	TotalSatoshisSent     uint64
 	TotalSatoshisReceived uint64
 	CreationTime          time.Time
+
+	// ZeroConf marks a channel with a trusted peer which was usable
+	// immediately after the funding handshake, before the funding
+	// transaction confirmed.
+	ZeroConf bool
+
+	// ShortChanID is the integer encoding of the short channel ID locating
+	// the funding output within the chain. It's zero until the funding
+	// transaction confirms.
+	ShortChanID uint64
+
+	// AliasChanID is the integer encoding of the alias short channel ID we
+	// handed out for the channel, used for forwarding HTLCs before the
+	// funding transaction confirms.
+	AliasChanID uint64
+
+	// OurMaxValueInFlight, and OurMaxAcceptedHtlcs, limit the total value,
+	// and number, of pending HTLCs the counterparty may offer us. Their
+	// limits, negotiated during funding, do the same for the HTLCs we
+	// offer them.
+	OurMaxValueInFlight   lnwire.MilliSatoshi
+	TheirMaxValueInFlight lnwire.MilliSatoshi
+	OurMaxAcceptedHtlcs   uint16
+	TheirMaxAcceptedHtlcs uint16
+
+	// MinHTLC is the smallest HTLC we'll accept from the counterparty,
+	// announced as the htlc_minimum_msat of our channel updates.
+	MinHTLC lnwire.MilliSatoshi
+
+	// Private marks a channel which is never announced to the network.
+	// Only its peer learns of our channel updates for it.
+	Private bool
+
+	// IsInitiator is true if we opened the channel, and so pay the fee of
+	// the commitment transactions.
+	IsInitiator bool
+
+	// OurChanReserve is the reserve we require the counterparty to keep
+	// within the channel, and TheirChanReserve the reserve they require
+	// of us. Neither party may offer an HTLC dipping into their reserve.
+	OurChanReserve   btcutil.Amount
+	TheirChanReserve btcutil.Amount
 }
 
 // These don't really belong here but not sure which other file to put them yet.
@@ -129,17 +210,27 @@ func (c *DB) GetIDAdr() (*btcutil.AddressPubKeyHash, error) {
 }
 
 // PutOpenChannel ...
+// The write is batched with those of other channels updating at once.
 func (c *DB) PutOpenChannel(channel *OpenChannel) error {
-	return c.namespace.Update(func(tx walletdb.Tx) error {
-		// Get the bucket dedicated to storing the meta-data for open
-		// channels.
-		rootBucket := tx.RootBucket()
-		openChanBucket, err := rootBucket.CreateBucketIfNotExists(openChannelBucket)
-		if err != nil {
-			return err
-		}
+	// The channel is serialized up front, as the addrmgr is required to
+	// encrypt sensitive data, and takes its own lock to do so.
+	var b bytes.Buffer
+	if err := channel.Encode(&b, c.addrmgr); err != nil {
+		return err
+	}
 
-		return putOpenChannel(openChanBucket, channel, c.addrmgr)
+	return c.chanScheduler.Execute(&batch.Request{
+		Update: func(tx walletdb.Tx) error {
+			// Get the bucket dedicated to storing the meta-data
+			// for open channels.
+			rootBucket := tx.RootBucket()
+			openChanBucket, err := rootBucket.CreateBucketIfNotExists(openChannelBucket)
+			if err != nil {
+				return err
+			}
+
+			return putOpenChannel(openChanBucket, channel, b.Bytes())
+		},
 	})
 }
 
@@ -169,17 +260,83 @@ func (c *DB) FetchOpenChannel(nodeID [32]byte) (*OpenChannel, error) {
 	return channel, err
 }
 
-// putChannel ...
-func putOpenChannel(activeChanBucket walletdb.Bucket, channel *OpenChannel,
-	addrmgr *waddrmgr.Manager) error {
+// FetchAllChannels returns the open channel with each node we have one
+// with.
+func (c *DB) FetchAllChannels() ([]*OpenChannel, error) {
+	var channels []*OpenChannel
+	err := c.namespace.View(func(tx walletdb.Tx) error {
+		openChanBucket := tx.RootBucket().Bucket(openChannelBucket)
+		if openChanBucket == nil {
+			return nil
+		}
 
-	// Generate a serialized version of the open channel. The addrmgr is
-	// required in order to encrypt densitive data.
-	var b bytes.Buffer
-	if err := channel.Encode(&b, addrmgr); err != nil {
-		return err
+		return openChanBucket.ForEach(func(k, _ []byte) error {
+			nodeBucket := openChanBucket.Bucket(k)
+			if nodeBucket == nil || nodeBucket.Get(activeChanKey) == nil {
+				return nil
+			}
+
+			var nodeID [32]byte
+			copy(nodeID[:], k)
+			channel, err := fetchOpenChannel(openChanBucket, nodeID,
+				c.addrmgr)
+			if err != nil {
+				return err
+			}
+			channels = append(channels, channel)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return channels, nil
+}
+
+// DeleteOpenChannel removes the open channel with the node from the
+// database, without its state being decoded, so that even a corrupt channel
+// may be removed. No action is taken on-chain, so any funds within the
+// channel are abandoned unless it has been resolved by other means.
+func (c *DB) DeleteOpenChannel(nodeID [32]byte) error {
+	return c.namespace.Update(func(tx walletdb.Tx) error {
+		openChanBucket := tx.RootBucket().Bucket(openChannelBucket)
+		if openChanBucket == nil {
+			return ErrChannelNotFound
+		}
+		nodeBucket := openChanBucket.Bucket(nodeID[:])
+		if nodeBucket == nil || nodeBucket.Get(activeChanKey) == nil {
+			return ErrChannelNotFound
+		}
+
+		return nodeBucket.Delete(activeChanKey)
+	})
+}
+
+// NextAliasIndex increments, and returns the index used to derive the next
+// alias short channel ID. Indexes start at one.
+func (c *DB) NextAliasIndex() (uint64, error) {
+	var index uint64
+	err := c.namespace.Update(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+
+		if indexBytes := rootBucket.Get(aliasIndexKey); indexBytes != nil {
+			index = endian.Uint64(indexBytes)
+		}
+		index++
+
+		var b [8]byte
+		endian.PutUint64(b[:], index)
+		return rootBucket.Put(aliasIndexKey, b[:])
+	})
+
+	return index, err
+}
+
+// putChannel ...
+func putOpenChannel(activeChanBucket walletdb.Bucket, channel *OpenChannel,
+	serializedChannel []byte) error {
+
 	// Grab the bucket dedicated to storing data related to this particular
 	// node.
 	nodeBucket, err := activeChanBucket.CreateBucketIfNotExists(channel.TheirLNID[:])
@@ -187,7 +344,7 @@ func putOpenChannel(activeChanBucket walletdb.Bucket, channel *OpenChannel,
 		return err
 	}
 
-	return nodeBucket.Put(activeChanKey, b.Bytes())
+	return nodeBucket.Put(activeChanKey, serializedChannel)
 }
 
 // fetchOpenChannel
@@ -292,6 +449,9 @@ func (o *OpenChannel) Encode(b io.Writer, addrManager *waddrmgr.Manager) error {
 	if err := binary.Write(b, endian, o.CsvDelay); err != nil {
 		return err
 	}
+	if err := binary.Write(b, endian, o.CommitType); err != nil {
+		return err
+	}
 	if err := binary.Write(b, endian, o.NumUpdates); err != nil {
 		return err
 	}
@@ -306,6 +466,46 @@ func (o *OpenChannel) Encode(b io.Writer, addrManager *waddrmgr.Manager) error {
 		return err
 	}
 
+	if err := binary.Write(b, endian, o.ZeroConf); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, o.ShortChanID); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, o.AliasChanID); err != nil {
+		return err
+	}
+
+	if err := binary.Write(b, endian, uint64(o.OurMaxValueInFlight)); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, uint64(o.TheirMaxValueInFlight)); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, o.OurMaxAcceptedHtlcs); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, o.TheirMaxAcceptedHtlcs); err != nil {
+		return err
+	}
+
+	if err := binary.Write(b, endian, uint64(o.MinHTLC)); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, o.Private); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, o.IsInitiator); err != nil {
+		return err
+	}
+
+	if err := binary.Write(b, endian, uint64(o.OurChanReserve)); err != nil {
+		return err
+	}
+	if err := binary.Write(b, endian, uint64(o.TheirChanReserve)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -352,11 +552,11 @@ func (o *OpenChannel) Decode(b io.Reader, addrManager *waddrmgr.Manager) error {
 	if _, err := b.Read(scratch[:]); err != nil {
 		return err
 	}
-	o.OurBalance = btcutil.Amount(endian.Uint64(scratch[:]))
+	o.OurBalance = lnwire.MilliSatoshi(endian.Uint64(scratch[:]))
 	if _, err := b.Read(scratch[:]); err != nil {
 		return err
 	}
-	o.TheirBalance = btcutil.Amount(endian.Uint64(scratch[:]))
+	o.TheirBalance = lnwire.MilliSatoshi(endian.Uint64(scratch[:]))
 
 	o.TheirCommitTx = wire.NewMsgTx()
 	if err := o.TheirCommitTx.Deserialize(b); err != nil {
@@ -411,6 +611,9 @@ func (o *OpenChannel) Decode(b io.Reader, addrManager *waddrmgr.Manager) error {
 	if err := binary.Read(b, endian, &o.CsvDelay); err != nil {
 		return err
 	}
+	if err := binary.Read(b, endian, &o.CommitType); err != nil {
+		return err
+	}
 	if err := binary.Read(b, endian, &o.NumUpdates); err != nil {
 		return err
 	}
@@ -427,5 +630,50 @@ func (o *OpenChannel) Decode(b io.Reader, addrManager *waddrmgr.Manager) error {
 	}
 	o.CreationTime = time.Unix(unix, 0)
 
+	if err := binary.Read(b, endian, &o.ZeroConf); err != nil {
+		return err
+	}
+	if err := binary.Read(b, endian, &o.ShortChanID); err != nil {
+		return err
+	}
+	if err := binary.Read(b, endian, &o.AliasChanID); err != nil {
+		return err
+	}
+
+	if _, err := b.Read(scratch[:]); err != nil {
+		return err
+	}
+	o.OurMaxValueInFlight = lnwire.MilliSatoshi(endian.Uint64(scratch[:]))
+	if _, err := b.Read(scratch[:]); err != nil {
+		return err
+	}
+	o.TheirMaxValueInFlight = lnwire.MilliSatoshi(endian.Uint64(scratch[:]))
+	if err := binary.Read(b, endian, &o.OurMaxAcceptedHtlcs); err != nil {
+		return err
+	}
+	if err := binary.Read(b, endian, &o.TheirMaxAcceptedHtlcs); err != nil {
+		return err
+	}
+
+	if _, err := b.Read(scratch[:]); err != nil {
+		return err
+	}
+	o.MinHTLC = lnwire.MilliSatoshi(endian.Uint64(scratch[:]))
+	if err := binary.Read(b, endian, &o.Private); err != nil {
+		return err
+	}
+	if err := binary.Read(b, endian, &o.IsInitiator); err != nil {
+		return err
+	}
+
+	if _, err := b.Read(scratch[:]); err != nil {
+		return err
+	}
+	o.OurChanReserve = btcutil.Amount(endian.Uint64(scratch[:]))
+	if _, err := b.Read(scratch[:]); err != nil {
+		return err
+	}
+	o.TheirChanReserve = btcutil.Amount(endian.Uint64(scratch[:]))
+
 	return nil
 }
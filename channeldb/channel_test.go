@@ -16,6 +16,7 @@ import (
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 var (
@@ -144,8 +145,8 @@ func TestOpenChannelEncodeDecode(t *testing.T) {
 		OurCommitKey:           privKey,
 		TheirCommitKey:         pubKey,
 		Capacity:               btcutil.Amount(10000),
-		OurBalance:             btcutil.Amount(3000),
-		TheirBalance:           btcutil.Amount(7000),
+		OurBalance:             lnwire.MilliSatoshi(3000000),
+		TheirBalance:           lnwire.MilliSatoshi(7000500),
 		TheirCommitTx:          testTx,
 		OurCommitTx:            testTx,
 		FundingTx:              testTx,
@@ -155,10 +156,23 @@ func TestOpenChannelEncodeDecode(t *testing.T) {
 		OurDeliveryAddress:     addr,
 		TheirDeliveryAddress:   addr,
 		CsvDelay:               5,
+		CommitType:             CommitmentAnchors,
 		NumUpdates:             1,
 		TotalSatoshisSent:      1,
 		TotalSatoshisReceived:  2,
 		CreationTime:           time.Date(2009, time.November, 10, 23, 0, 0, 0, time.UTC),
+		ZeroConf:               true,
+		ShortChanID:            (432000 << 40) | (1 << 16),
+		AliasChanID:            16000000 << 40,
+		OurMaxValueInFlight:    lnwire.MilliSatoshi(500000000),
+		TheirMaxValueInFlight:  lnwire.MilliSatoshi(300000000),
+		OurMaxAcceptedHtlcs:    30,
+		TheirMaxAcceptedHtlcs:  483,
+		MinHTLC:                lnwire.MilliSatoshi(1000),
+		Private:                true,
+		IsInitiator:            true,
+		OurChanReserve:         btcutil.Amount(10000),
+		TheirChanReserve:       btcutil.Amount(20000),
 	}
 
 	var b bytes.Buffer
@@ -263,6 +277,10 @@ func TestOpenChannelEncodeDecode(t *testing.T) {
 		t.Fatalf("csv delay doesn't match: %v vs %v",
 			state.CsvDelay, newState.CsvDelay)
 	}
+	if state.CommitType != newState.CommitType {
+		t.Fatalf("commit type doesn't match: %v vs %v",
+			state.CommitType, newState.CommitType)
+	}
 	if state.TotalSatoshisSent != newState.TotalSatoshisSent {
 		t.Fatalf("satoshis sent doesn't match: %v vs %v",
 			state.TotalSatoshisSent, newState.TotalSatoshisSent)
@@ -274,6 +292,41 @@ func TestOpenChannelEncodeDecode(t *testing.T) {
 	if state.CreationTime.Unix() != newState.CreationTime.Unix() {
 		t.Fatalf("creation time doesn't match")
 	}
+
+	if state.ZeroConf != newState.ZeroConf {
+		t.Fatalf("zero conf doesn't match")
+	}
+	if state.ShortChanID != newState.ShortChanID {
+		t.Fatalf("short chan id doesn't match: %v vs %v",
+			state.ShortChanID, newState.ShortChanID)
+	}
+	if state.AliasChanID != newState.AliasChanID {
+		t.Fatalf("alias chan id doesn't match: %v vs %v",
+			state.AliasChanID, newState.AliasChanID)
+	}
+
+	if state.OurMaxValueInFlight != newState.OurMaxValueInFlight ||
+		state.TheirMaxValueInFlight != newState.TheirMaxValueInFlight {
+		t.Fatalf("max value in flight doesn't match")
+	}
+	if state.OurMaxAcceptedHtlcs != newState.OurMaxAcceptedHtlcs ||
+		state.TheirMaxAcceptedHtlcs != newState.TheirMaxAcceptedHtlcs {
+		t.Fatalf("max accepted htlcs doesn't match")
+	}
+	if state.MinHTLC != newState.MinHTLC {
+		t.Fatalf("min htlc doesn't match: %v vs %v", state.MinHTLC,
+			newState.MinHTLC)
+	}
+	if state.Private != newState.Private {
+		t.Fatalf("private doesn't match")
+	}
+	if state.IsInitiator != newState.IsInitiator {
+		t.Fatalf("initiator doesn't match")
+	}
+	if state.OurChanReserve != newState.OurChanReserve ||
+		state.TheirChanReserve != newState.TheirChanReserve {
+		t.Fatalf("channel reserves don't match")
+	}
 }
 
 func TestOpenChannelEncodeDecodeCorruption(t *testing.T) {
@@ -0,0 +1,51 @@
+package channeldb
+
+import (
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// disabledChanBucket houses the IDs of the channels disabled by the
+	// user, which remain disabled until the user re-enables them.
+	disabledChanBucket = []byte("cd")
+)
+
+// SetChanDisabled records whether the user has disabled the channel.
+func (d *DB) SetChanDisabled(chanID lnwire.ShortChannelID, disabled bool) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		chans, err := tx.RootBucket().CreateBucketIfNotExists(
+			disabledChanBucket)
+		if err != nil {
+			return err
+		}
+
+		key := chanIDKey(chanID)
+		if !disabled {
+			return chans.Delete(key[:])
+		}
+		return chans.Put(key[:], nil)
+	})
+}
+
+// FetchDisabledChans returns the IDs of each channel disabled by the user.
+func (d *DB) FetchDisabledChans() ([]lnwire.ShortChannelID, error) {
+	var chanIDs []lnwire.ShortChannelID
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		chans := tx.RootBucket().Bucket(disabledChanBucket)
+		if chans == nil {
+			return nil
+		}
+
+		return chans.ForEach(func(k, _ []byte) error {
+			chanIDs = append(chanIDs,
+				lnwire.NewShortChanIDFromInt(endian.Uint64(k)))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanIDs, nil
+}
@@ -4,15 +4,22 @@ import (
 	"bytes"
 	"encoding/binary"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/batch"
 )
 
 var (
 	endian = binary.BigEndian
 )
 
+// batchCommitInterval is the longest a write on the HTLC hot path waits for
+// others to share its transaction. It's kept short as channel updates block
+// on their writes.
+const batchCommitInterval = 10 * time.Millisecond
+
 var bufPool = &sync.Pool{
 	New: func() interface{} { return new(bytes.Buffer) },
 }
@@ -24,6 +31,15 @@ type DB struct {
 	addrmgr *waddrmgr.Manager
 
 	namespace walletdb.Namespace
+
+	// graphCache is an in-memory copy of the channel graph, serving
+	// reads of the graph without touching disk.
+	graphCache *GraphCache
+
+	// chanScheduler batches the writes made as HTLCs are added, and
+	// settled, so that those of concurrent channel updates share a
+	// single transaction.
+	chanScheduler batch.Scheduler
 }
 
 // Wipe ...
@@ -39,7 +55,12 @@ func (d *DB) Wipe() error {
 // TODO(roasbeef): re-visit this dependancy...
 func New(addrmgr *waddrmgr.Manager, namespace walletdb.Namespace) *DB {
 	// TODO(roasbeef): create buckets if not created?
-	return &DB{addrmgr, namespace}
+	return &DB{
+		addrmgr:       addrmgr,
+		namespace:     namespace,
+		graphCache:    newGraphCache(),
+		chanScheduler: batch.NewTimeScheduler(namespace, batchCommitInterval),
+	}
 }
 
 // Open ...
@@ -0,0 +1,489 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/btcsuite/btcwallet/snacl"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/btcsuite/fastsha256"
+)
+
+var (
+	// encryptionMetaBucket houses the key the values of an encrypted
+	// database are encrypted under, itself encrypted by the wallet. It's
+	// the only bucket whose values aren't encrypted under the key.
+	encryptionMetaBucket = []byte("em")
+
+	// dbKeyKey is the key of the encrypted database key.
+	dbKeyKey = []byte("k")
+)
+
+var (
+	// ErrDBKeyCorrupt is returned when the key of an encrypted database
+	// can't be decrypted into a key.
+	ErrDBKeyCorrupt = errors.New("database encryption key is corrupt")
+
+	// ErrValueCorrupt is returned by the transaction over an encrypted
+	// database within which a value failed to decrypt, either as it was
+	// corrupted, or moved from the key it was written under.
+	ErrValueCorrupt = errors.New("encrypted database value is corrupt")
+)
+
+// Cryptor encrypts, and decrypts, data under the crypto keys of the wallet,
+// which are themselves encrypted under keys derived from its passphrases.
+// It's implemented by *waddrmgr.Manager.
+type Cryptor interface {
+	Encrypt(keyType waddrmgr.CryptoKeyType, in []byte) ([]byte, error)
+	Decrypt(keyType waddrmgr.CryptoKeyType, in []byte) ([]byte, error)
+}
+
+// OpenEncryptedNamespace returns the namespace a DB is to be created around.
+// Should the namespace be encrypted, the returned namespace transparently
+// encrypts each value written, and decrypts each value read, under the key
+// of the database. Keys, and the layout of buckets, aren't encrypted, so
+// that the order of keys is preserved.
+//
+// If encrypt is set, and the namespace has yet to be encrypted, a new key is
+// generated, and the existing contents encrypted under it in place. Once
+// encrypted, a namespace remains so, whether or not encrypt is set.
+//
+// The key of the database is kept encrypted under the private crypto key of
+// the wallet, so the wallet must be unlocked before the namespace is opened.
+func OpenEncryptedNamespace(ns walletdb.Namespace, cryptor Cryptor,
+	encrypt bool) (walletdb.Namespace, error) {
+
+	var encryptedKey []byte
+	err := ns.View(func(tx walletdb.Tx) error {
+		meta := tx.RootBucket().Bucket(encryptionMetaBucket)
+		if meta == nil {
+			return nil
+		}
+
+		encryptedKey = append([]byte(nil), meta.Get(dbKeyKey)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case encryptedKey != nil:
+		keyBytes, err := cryptor.Decrypt(waddrmgr.CKTPrivate, encryptedKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(keyBytes) != snacl.KeySize {
+			return nil, ErrDBKeyCorrupt
+		}
+
+		var key snacl.CryptoKey
+		copy(key[:], keyBytes)
+		return &encryptedNamespace{Namespace: ns, key: &key}, nil
+
+	case !encrypt:
+		return ns, nil
+	}
+
+	key, err := snacl.GenerateCryptoKey()
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err = cryptor.Encrypt(waddrmgr.CKTPrivate, key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	err = ns.Update(func(tx walletdb.Tx) error {
+		err := encryptBucket(tx.RootBucket(), nil, key)
+		if err != nil {
+			return err
+		}
+
+		meta, err := tx.RootBucket().CreateBucket(encryptionMetaBucket)
+		if err != nil {
+			return err
+		}
+		return meta.Put(dbKeyKey, encryptedKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &encryptedNamespace{Namespace: ns, key: key}, nil
+}
+
+// encryptBucket encrypts each of the values within the bucket, and its
+// nested buckets, in place. The path is that of the keys of the buckets the
+// bucket is nested within.
+func encryptBucket(bucket walletdb.Bucket, path [][]byte,
+	key *snacl.CryptoKey) error {
+
+	var keys, nested [][]byte
+	err := bucket.ForEach(func(k, _ []byte) error {
+		k = append([]byte(nil), k...)
+		if bucket.Bucket(k) != nil {
+			nested = append(nested, k)
+		} else {
+			keys = append(keys, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		v, err := encryptValue(key, path, k, bucket.Get(k))
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(k, v); err != nil {
+			return err
+		}
+	}
+	for _, k := range nested {
+		if bytes.Equal(k, encryptionMetaBucket) {
+			continue
+		}
+		err := encryptBucket(bucket.Bucket(k), nestedPath(path, k), key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nestedPath returns the path of the bucket nested under the key, within the
+// bucket of the passed path.
+func nestedPath(path [][]byte, k []byte) [][]byte {
+	nested := make([][]byte, 0, len(path)+1)
+	nested = append(nested, path...)
+	return append(nested, append([]byte(nil), k...))
+}
+
+// valueDigest returns the digest of the key a value is stored under, along
+// with the path of the bucket it's stored within. As snacl doesn't take
+// associated data, the digest is encrypted along with each value in its
+// place, binding the value to its key, so that a value copied to another key
+// fails to decrypt rather than being read as that key's.
+func valueDigest(path [][]byte, k []byte) []byte {
+	h := fastsha256.New()
+	var size [4]byte
+	for _, elem := range append(path[:len(path):len(path)], k) {
+		endian.PutUint32(size[:], uint32(len(elem)))
+		h.Write(size[:])
+		h.Write(elem)
+	}
+	return h.Sum(nil)
+}
+
+// encryptValue encrypts the value to be written under the key, within the
+// bucket of the passed path.
+func encryptValue(key *snacl.CryptoKey, path [][]byte, k,
+	v []byte) ([]byte, error) {
+
+	plaintext := append(valueDigest(path, k), v...)
+	return key.Encrypt(plaintext)
+}
+
+// decryptValue decrypts the value read from under the key, within the bucket
+// of the passed path. Nested buckets are read as nil values, and are passed
+// through. As every value written is encrypted, even an empty one, a value
+// which fails to decrypt, or was written under another key, is corrupt, and
+// ErrValueCorrupt is returned.
+func decryptValue(key *snacl.CryptoKey, path [][]byte, k,
+	v []byte) ([]byte, error) {
+
+	if v == nil {
+		return nil, nil
+	}
+
+	plaintext, err := key.Decrypt(v)
+	if err != nil {
+		return nil, ErrValueCorrupt
+	}
+
+	digest := valueDigest(path, k)
+	if len(plaintext) < len(digest) ||
+		!bytes.Equal(plaintext[:len(digest)], digest) {
+
+		return nil, ErrValueCorrupt
+	}
+	return append([]byte{}, plaintext[len(digest):]...), nil
+}
+
+// encryptedNamespace is a namespace whose values are encrypted under the key
+// of the database.
+type encryptedNamespace struct {
+	walletdb.Namespace
+	key *snacl.CryptoKey
+}
+
+// Begin starts a transaction over the namespace. Should a value read within
+// the transaction fail to decrypt, committing it fails with ErrValueCorrupt.
+//
+// NOTE: Part of the walletdb.Namespace interface.
+func (n *encryptedNamespace) Begin(writable bool) (walletdb.Tx, error) {
+	tx, err := n.Namespace.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedTx{Tx: tx, key: n.key}, nil
+}
+
+// View runs fn within a read-only transaction. Should a value read within
+// the transaction fail to decrypt, ErrValueCorrupt is returned, whatever fn
+// returns.
+//
+// NOTE: Part of the walletdb.Namespace interface.
+func (n *encryptedNamespace) View(fn func(walletdb.Tx) error) error {
+	return n.Namespace.View(func(tx walletdb.Tx) error {
+		return (&encryptedTx{Tx: tx, key: n.key}).run(fn)
+	})
+}
+
+// Update runs fn within a read-write transaction. Should a value read within
+// the transaction fail to decrypt, ErrValueCorrupt is returned, whatever fn
+// returns, and the transaction is rolled back.
+//
+// NOTE: Part of the walletdb.Namespace interface.
+func (n *encryptedNamespace) Update(fn func(walletdb.Tx) error) error {
+	return n.Namespace.Update(func(tx walletdb.Tx) error {
+		return (&encryptedTx{Tx: tx, key: n.key}).run(fn)
+	})
+}
+
+// encryptedTx is a transaction over an encrypted namespace.
+type encryptedTx struct {
+	walletdb.Tx
+	key *snacl.CryptoKey
+
+	// err is set once a value read within the transaction fails to
+	// decrypt. As Get, and the methods of cursors, have no error to
+	// return, the corrupt value is read as missing, and the error is
+	// instead returned once the transaction completes.
+	err error
+}
+
+// run calls fn with the transaction, returning the error of any value which
+// failed to decrypt in place of that of fn.
+func (t *encryptedTx) run(fn func(walletdb.Tx) error) error {
+	err := fn(t)
+	if t.err != nil {
+		return t.err
+	}
+	return err
+}
+
+// decrypt decrypts the value read from under the key, within the bucket of
+// the passed path, recording the error should it fail to.
+func (t *encryptedTx) decrypt(path [][]byte, k, v []byte) ([]byte, error) {
+	plaintext, err := decryptValue(t.key, path, k, v)
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return plaintext, err
+}
+
+// RootBucket returns the root bucket of the namespace.
+//
+// NOTE: Part of the walletdb.Tx interface.
+func (t *encryptedTx) RootBucket() walletdb.Bucket {
+	return &encryptedBucket{raw: t.Tx.RootBucket(), tx: t}
+}
+
+// Commit commits the transaction, unless a value read within it failed to
+// decrypt, in which case it's rolled back, and ErrValueCorrupt returned.
+//
+// NOTE: Part of the walletdb.Tx interface.
+func (t *encryptedTx) Commit() error {
+	if t.err != nil {
+		t.Tx.Rollback()
+		return t.err
+	}
+	return t.Tx.Commit()
+}
+
+// encryptedBucket is a bucket of an encrypted namespace.
+type encryptedBucket struct {
+	raw walletdb.Bucket
+	tx  *encryptedTx
+
+	// path holds the keys of the buckets this bucket is nested within,
+	// starting from the root bucket, followed by its own.
+	path [][]byte
+}
+
+// wrapBucket wraps the bucket nested under the key, returning nil if it
+// doesn't exist.
+func (b *encryptedBucket) wrapBucket(key []byte,
+	bucket walletdb.Bucket) walletdb.Bucket {
+
+	if bucket == nil {
+		return nil
+	}
+	return &encryptedBucket{
+		raw:  bucket,
+		tx:   b.tx,
+		path: nestedPath(b.path, key),
+	}
+}
+
+// Bucket returns the nested bucket, or nil if it doesn't exist.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) Bucket(key []byte) walletdb.Bucket {
+	return b.wrapBucket(key, b.raw.Bucket(key))
+}
+
+// CreateBucket creates the nested bucket.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) CreateBucket(key []byte) (walletdb.Bucket, error) {
+	bucket, err := b.raw.CreateBucket(key)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrapBucket(key, bucket), nil
+}
+
+// CreateBucketIfNotExists creates the nested bucket, unless it exists.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) CreateBucketIfNotExists(key []byte) (walletdb.Bucket, error) {
+	bucket, err := b.raw.CreateBucketIfNotExists(key)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrapBucket(key, bucket), nil
+}
+
+// DeleteBucket removes the nested bucket.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) DeleteBucket(key []byte) error {
+	return b.raw.DeleteBucket(key)
+}
+
+// ForEach calls fn with each key, and decrypted value, within the bucket.
+// Iteration stops with ErrValueCorrupt at the first value which fails to
+// decrypt.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) ForEach(fn func(k, v []byte) error) error {
+	return b.raw.ForEach(func(k, v []byte) error {
+		plaintext, err := b.tx.decrypt(b.path, k, v)
+		if err != nil {
+			return err
+		}
+		return fn(k, plaintext)
+	})
+}
+
+// Writable returns whether the bucket may be written to.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) Writable() bool {
+	return b.raw.Writable()
+}
+
+// Put encrypts the value, storing it under the key.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) Put(key, value []byte) error {
+	ciphertext, err := encryptValue(b.tx.key, b.path, key, value)
+	if err != nil {
+		return err
+	}
+	return b.raw.Put(key, ciphertext)
+}
+
+// Get returns the decrypted value of the key, or nil if there's none. A
+// value which fails to decrypt is read as nil, and its transaction fails
+// with ErrValueCorrupt.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) Get(key []byte) []byte {
+	plaintext, _ := b.tx.decrypt(b.path, key, b.raw.Get(key))
+	return plaintext
+}
+
+// Delete removes the key.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) Delete(key []byte) error {
+	return b.raw.Delete(key)
+}
+
+// Cursor returns a cursor over the bucket, decrypting each value.
+//
+// NOTE: Part of the walletdb.Bucket interface.
+func (b *encryptedBucket) Cursor() walletdb.Cursor {
+	return &encryptedCursor{
+		Cursor: b.raw.Cursor(),
+		bucket: b,
+	}
+}
+
+// encryptedCursor is a cursor over an encrypted bucket.
+type encryptedCursor struct {
+	walletdb.Cursor
+	bucket *encryptedBucket
+}
+
+// Bucket returns the bucket the cursor was created over.
+//
+// NOTE: Part of the walletdb.Cursor interface.
+func (c *encryptedCursor) Bucket() walletdb.Bucket {
+	return c.bucket
+}
+
+// First moves to the first key, returning it and its decrypted value.
+//
+// NOTE: Part of the walletdb.Cursor interface.
+func (c *encryptedCursor) First() ([]byte, []byte) {
+	return c.decrypt(c.Cursor.First())
+}
+
+// Last moves to the last key, returning it and its decrypted value.
+//
+// NOTE: Part of the walletdb.Cursor interface.
+func (c *encryptedCursor) Last() ([]byte, []byte) {
+	return c.decrypt(c.Cursor.Last())
+}
+
+// Next moves to the next key, returning it and its decrypted value.
+//
+// NOTE: Part of the walletdb.Cursor interface.
+func (c *encryptedCursor) Next() ([]byte, []byte) {
+	return c.decrypt(c.Cursor.Next())
+}
+
+// Prev moves to the previous key, returning it and its decrypted value.
+//
+// NOTE: Part of the walletdb.Cursor interface.
+func (c *encryptedCursor) Prev() ([]byte, []byte) {
+	return c.decrypt(c.Cursor.Prev())
+}
+
+// Seek moves to the passed key, or the next key after it, returning it and
+// its decrypted value.
+//
+// NOTE: Part of the walletdb.Cursor interface.
+func (c *encryptedCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.decrypt(c.Cursor.Seek(seek))
+}
+
+// decrypt decrypts the value the cursor moved to. As with Get, a value which
+// fails to decrypt is read as nil, and its transaction fails with
+// ErrValueCorrupt.
+func (c *encryptedCursor) decrypt(k, v []byte) ([]byte, []byte) {
+	if k == nil {
+		return nil, nil
+	}
+	plaintext, _ := c.bucket.tx.decrypt(c.bucket.path, k, v)
+	return k, plaintext
+}
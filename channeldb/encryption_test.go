@@ -0,0 +1,218 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// mockCryptor stands in for the wallet, "encrypting" by reversing the
+// bytes passed.
+type mockCryptor struct{}
+
+func reverse(in []byte) []byte {
+	out := make([]byte, len(in))
+	for i, b := range in {
+		out[len(in)-1-i] = b
+	}
+	return out
+}
+
+func (mockCryptor) Encrypt(_ waddrmgr.CryptoKeyType, in []byte) ([]byte, error) {
+	return reverse(in), nil
+}
+
+func (mockCryptor) Decrypt(_ waddrmgr.CryptoKeyType, in []byte) ([]byte, error) {
+	return reverse(in), nil
+}
+
+func TestEncryptedNamespace(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+	rawNamespace := db.namespace
+
+	// Populate the database before it's encrypted, including a channel
+	// whose value is empty.
+	for i := byte(1); i <= 3; i++ {
+		if err := db.AddPayment(makeTestPayment(i, PaymentSucceeded)); err != nil {
+			t.Fatalf("unable to add payment: %v", err)
+		}
+	}
+	chanID := lnwire.NewShortChanIDFromInt(42)
+	if err := db.SetChanDisabled(chanID, true); err != nil {
+		t.Fatalf("unable to disable channel: %v", err)
+	}
+
+	// Without encrypt set, an unencrypted namespace is left as is.
+	ns, err := OpenEncryptedNamespace(rawNamespace, mockCryptor{}, false)
+	if err != nil {
+		t.Fatalf("unable to open namespace: %v", err)
+	}
+	if ns != rawNamespace {
+		t.Fatalf("namespace encrypted without encrypt set")
+	}
+
+	ns, err = OpenEncryptedNamespace(rawNamespace, mockCryptor{}, true)
+	if err != nil {
+		t.Fatalf("unable to encrypt namespace: %v", err)
+	}
+	encDB := New(nil, ns)
+
+	// The existing contents were encrypted in place, and remain readable
+	// through the encrypted namespace.
+	payments, err := encDB.FetchPayments(1, 10)
+	if err != nil {
+		t.Fatalf("unable to fetch payments: %v", err)
+	}
+	if len(payments) != 2 || payments[0].PaymentID != 2 {
+		t.Fatalf("unexpected payments: %v", payments)
+	}
+	chanIDs, err := encDB.FetchDisabledChans()
+	if err != nil {
+		t.Fatalf("unable to fetch disabled channels: %v", err)
+	}
+	if len(chanIDs) != 1 || chanIDs[0] != chanID {
+		t.Fatalf("unexpected disabled channels: %v", chanIDs)
+	}
+
+	// New values are written encrypted.
+	var pubKey [33]byte
+	if err := encDB.PutPeerLabel(pubKey, "secret label"); err != nil {
+		t.Fatalf("unable to put peer label: %v", err)
+	}
+	err = rawNamespace.View(func(tx walletdb.Tx) error {
+		v := tx.RootBucket().Bucket(peerLabelBucket).Get(pubKey[:])
+		if bytes.Contains(v, []byte("secret label")) {
+			t.Fatalf("label stored in plaintext")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read raw namespace: %v", err)
+	}
+
+	// Once encrypted, the namespace is opened as such even without
+	// encrypt set.
+	ns, err = OpenEncryptedNamespace(rawNamespace, mockCryptor{}, false)
+	if err != nil {
+		t.Fatalf("unable to open namespace: %v", err)
+	}
+	labels, err := New(nil, ns).FetchPeerLabels()
+	if err != nil {
+		t.Fatalf("unable to fetch peer labels: %v", err)
+	}
+	if labels[pubKey] != "secret label" {
+		t.Fatalf("unexpected label: %q", labels[pubKey])
+	}
+}
+
+// TestEncryptedValueCorrupt asserts a value which fails to decrypt, whether
+// it was corrupted, or moved to another key, fails the read of it with
+// ErrValueCorrupt, rather than being read as missing.
+func TestEncryptedValueCorrupt(t *testing.T) {
+	pubKey1 := [33]byte{1}
+	pubKey2 := [33]byte{2}
+	var paymentKey [8]byte
+	endian.PutUint64(paymentKey[:], 1)
+
+	tests := []struct {
+		name   string
+		bucket []byte
+
+		// corrupt alters the raw values of the bucket.
+		corrupt func(walletdb.Bucket) error
+
+		// fetch reads the altered value through the encrypted
+		// namespace.
+		fetch func(*DB) error
+	}{
+		{
+			name:   "flipped byte",
+			bucket: peerLabelBucket,
+			corrupt: func(b walletdb.Bucket) error {
+				v := append([]byte(nil), b.Get(pubKey1[:])...)
+				v[len(v)-1] ^= 1
+				return b.Put(pubKey1[:], v)
+			},
+			fetch: func(db *DB) error {
+				_, err := db.FetchPeerLabels()
+				return err
+			},
+		},
+		{
+			name:   "moved to another key",
+			bucket: peerLabelBucket,
+			corrupt: func(b walletdb.Bucket) error {
+				v := append([]byte(nil), b.Get(pubKey1[:])...)
+				return b.Put(pubKey2[:], v)
+			},
+			fetch: func(db *DB) error {
+				_, err := db.FetchPeerLabels()
+				return err
+			},
+		},
+		{
+			name:   "read by key",
+			bucket: paymentBucket,
+			corrupt: func(b walletdb.Bucket) error {
+				v := append([]byte(nil), b.Get(paymentKey[:])...)
+				v[0] ^= 1
+				return b.Put(paymentKey[:], v)
+			},
+			fetch: func(db *DB) error {
+				_, err := db.FetchPaymentByID(1)
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		db, cleanUp := createTestDB(t)
+		rawNamespace := db.namespace
+
+		ns, err := OpenEncryptedNamespace(rawNamespace, mockCryptor{},
+			true)
+		if err != nil {
+			cleanUp()
+			t.Fatalf("%v: unable to encrypt namespace: %v", test.name,
+				err)
+		}
+		encDB := New(nil, ns)
+
+		if err := encDB.AddPayment(makeTestPayment(1, PaymentSucceeded)); err != nil {
+			cleanUp()
+			t.Fatalf("%v: unable to add payment: %v", test.name, err)
+		}
+		for _, pubKey := range [][33]byte{pubKey1, pubKey2} {
+			if err := encDB.PutPeerLabel(pubKey, "label"); err != nil {
+				cleanUp()
+				t.Fatalf("%v: unable to put peer label: %v",
+					test.name, err)
+			}
+		}
+		if err := test.fetch(encDB); err != nil {
+			cleanUp()
+			t.Fatalf("%v: unable to read intact value: %v",
+				test.name, err)
+		}
+
+		err = rawNamespace.Update(func(tx walletdb.Tx) error {
+			return test.corrupt(tx.RootBucket().Bucket(test.bucket))
+		})
+		if err != nil {
+			cleanUp()
+			t.Fatalf("%v: unable to corrupt value: %v", test.name,
+				err)
+		}
+
+		err = test.fetch(encDB)
+		cleanUp()
+		if err != ErrValueCorrupt {
+			t.Fatalf("%v: expected ErrValueCorrupt, instead %v",
+				test.name, err)
+		}
+	}
+}
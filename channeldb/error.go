@@ -1 +1,23 @@
 package channeldb
+
+import "fmt"
+
+var (
+	ErrPaymentNotFound = fmt.Errorf("payment not found")
+	ErrPaymentExists   = fmt.Errorf("payment with hash already exists")
+	ErrPaymentInFlight = fmt.Errorf("payment with hash is already in flight")
+	ErrAlreadyPaid     = fmt.Errorf("payment with hash has already succeeded")
+
+	ErrInvoiceNotFound  = fmt.Errorf("unable to locate invoice")
+	ErrDuplicateInvoice = fmt.Errorf("invoice with payment hash already exists")
+	ErrNotAMPInvoice    = fmt.Errorf("invoice doesn't accept AMP payments")
+	ErrHTLCSetNotFound  = fmt.Errorf("unable to locate htlc set")
+
+	ErrAccountNotFound = fmt.Errorf("unable to locate watch-only account")
+
+	ErrEdgeNotFound = fmt.Errorf("unable to locate channel edge")
+
+	ErrChannelNotFound = fmt.Errorf("unable to locate open channel")
+
+	ErrLabelTooLong = fmt.Errorf("label exceeds the max length")
+)
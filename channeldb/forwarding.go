@@ -0,0 +1,158 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// forwardingLogBucket houses each HTLC we've forwarded, keyed by the
+	// time, in nanoseconds, it was settled, so iterating over the bucket
+	// returns the events in the order they occurred.
+	forwardingLogBucket = []byte("fwd")
+)
+
+// ForwardingEvent is an HTLC we forwarded from one of our channels to
+// another, which was settled, earning us its fee.
+type ForwardingEvent struct {
+	// Timestamp is the time the HTLC was settled.
+	Timestamp time.Time
+
+	IncomingChanID lnwire.ShortChannelID
+	OutgoingChanID lnwire.ShortChannelID
+
+	// AmtIn is the amount of the incoming HTLC, and AmtOut that of the
+	// outgoing, the difference being the fee we earned.
+	AmtIn  lnwire.MilliSatoshi
+	AmtOut lnwire.MilliSatoshi
+}
+
+// Fee returns the fee earned by forwarding the HTLC.
+func (f *ForwardingEvent) Fee() lnwire.MilliSatoshi {
+	return f.AmtIn - f.AmtOut
+}
+
+// AddForwardingEvents records the settled forwards within the log. Events
+// sharing a timestamp are nudged apart by a nanosecond, so none are
+// overwritten.
+func (d *DB) AddForwardingEvents(events []*ForwardingEvent) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		log, err := tx.RootBucket().CreateBucketIfNotExists(
+			forwardingLogBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			var key [8]byte
+			timestamp := event.Timestamp.UnixNano()
+			for {
+				endian.PutUint64(key[:], uint64(timestamp))
+				if log.Get(key[:]) == nil {
+					break
+				}
+				timestamp++
+			}
+
+			var b bytes.Buffer
+			if err := event.Encode(&b); err != nil {
+				return err
+			}
+			if err := log.Put(key[:], b.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchForwardingEvents returns the forwards settled within the passed time
+// range, inclusive of start and exclusive of end, in the order they occurred.
+// A zero end returns all events past start.
+func (d *DB) FetchForwardingEvents(start,
+	end time.Time) ([]*ForwardingEvent, error) {
+
+	var events []*ForwardingEvent
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		log := tx.RootBucket().Bucket(forwardingLogBucket)
+		if log == nil {
+			return nil
+		}
+
+		return log.ForEach(func(k, v []byte) error {
+			timestamp := time.Unix(0, int64(endian.Uint64(k)))
+			if timestamp.Before(start) ||
+				(!end.IsZero() && !timestamp.Before(end)) {
+
+				return nil
+			}
+
+			event := &ForwardingEvent{}
+			if err := event.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Encode...
+func (f *ForwardingEvent) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, f.Timestamp.UnixNano()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, f.IncomingChanID.ToUint64()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, f.OutgoingChanID.ToUint64()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, uint64(f.AmtIn)); err != nil {
+		return err
+	}
+
+	return binary.Write(w, endian, uint64(f.AmtOut))
+}
+
+// Decode...
+func (f *ForwardingEvent) Decode(r io.Reader) error {
+	var timestamp int64
+	if err := binary.Read(r, endian, &timestamp); err != nil {
+		return err
+	}
+	f.Timestamp = time.Unix(0, timestamp)
+
+	var incoming, outgoing uint64
+	if err := binary.Read(r, endian, &incoming); err != nil {
+		return err
+	}
+	if err := binary.Read(r, endian, &outgoing); err != nil {
+		return err
+	}
+	f.IncomingChanID = lnwire.NewShortChanIDFromInt(incoming)
+	f.OutgoingChanID = lnwire.NewShortChanIDFromInt(outgoing)
+
+	var amtIn, amtOut uint64
+	if err := binary.Read(r, endian, &amtIn); err != nil {
+		return err
+	}
+	if err := binary.Read(r, endian, &amtOut); err != nil {
+		return err
+	}
+	f.AmtIn = lnwire.MilliSatoshi(amtIn)
+	f.AmtOut = lnwire.MilliSatoshi(amtOut)
+
+	return nil
+}
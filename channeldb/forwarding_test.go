@@ -0,0 +1,67 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func TestForwardingLog(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	// Two of the events share a timestamp, and mustn't overwrite each
+	// other.
+	start := time.Unix(1500000000, 0)
+	var events []*ForwardingEvent
+	for i, offset := range []time.Duration{0, time.Minute, time.Minute,
+		time.Hour} {
+
+		events = append(events, &ForwardingEvent{
+			Timestamp:      start.Add(offset),
+			IncomingChanID: lnwire.NewShortChanIDFromInt(uint64(i)),
+			OutgoingChanID: lnwire.NewShortChanIDFromInt(uint64(i + 10)),
+			AmtIn:          lnwire.MilliSatoshi(10000 + i),
+			AmtOut:         10000,
+		})
+	}
+	if err := db.AddForwardingEvents(events); err != nil {
+		t.Fatalf("unable to add forwarding events: %v", err)
+	}
+
+	fetched, err := db.FetchForwardingEvents(start, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding events: %v", err)
+	}
+	if len(fetched) != len(events) {
+		t.Fatalf("expected %v events, got %v", len(events),
+			len(fetched))
+	}
+	for i, event := range fetched {
+		if event.Fee() != lnwire.MilliSatoshi(i) {
+			t.Fatalf("event %v has the wrong fee: %v", i,
+				event.Fee())
+		}
+		if event.IncomingChanID != events[i].IncomingChanID ||
+			event.AmtIn != events[i].AmtIn {
+
+			t.Fatalf("event %v doesn't match", i)
+		}
+	}
+	if !reflect.DeepEqual(fetched[0], events[0]) {
+		t.Fatalf("event doesn't match: %v vs %v", fetched[0],
+			events[0])
+	}
+
+	// The range excludes its end.
+	fetched, err = db.FetchForwardingEvents(start.Add(time.Minute),
+		start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding events: %v", err)
+	}
+	if len(fetched) != 2 {
+		t.Fatalf("expected 2 events, got %v", len(fetched))
+	}
+}
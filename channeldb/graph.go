@@ -0,0 +1,780 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// edgeBucket houses the funding outpoint, capacity, and announcement
+	// of each channel within the channel graph, keyed by the 8-byte
+	// integer encoding of its ShortChannelID. As the encoding is big
+	// endian, the channels are ordered by the height of the block
+	// containing their funding transaction.
+	edgeBucket = []byte("ge")
+
+	// edgePolicyBucket houses the latest channel update received for each
+	// direction of each channel, keyed by the ShortChannelID of the
+	// channel followed by the direction.
+	edgePolicyBucket = []byte("gp")
+
+	// chanPointBucket maps the funding outpoint of each channel within
+	// the channel graph to its ShortChannelID, so the channels closed by
+	// a block's spends can be found.
+	chanPointBucket = []byte("gc")
+
+	// pruneTipBucket holds the hash, and height, of the last block the
+	// channel graph was pruned with, under pruneTipKey.
+	pruneTipBucket = []byte("gt")
+	pruneTipKey    = []byte("tip")
+
+	// privateEdgeBucket holds the ShortChannelID of each of our private
+	// channels within the channel graph. Their announcements are never
+	// signed, nor sent to our peers.
+	privateEdgeBucket = []byte("gv")
+)
+
+// ZombieHorizon is how long a channel may go without a channel update from
+// either of its nodes before it's considered a zombie.
+const ZombieHorizon = 14 * 24 * time.Hour
+
+// ChannelEdge is a channel within the channel graph, along with the latest
+// channel update received for each direction.
+type ChannelEdge struct {
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint wire.OutPoint
+
+	// Capacity is the value of the funding output.
+	Capacity btcutil.Amount
+
+	// Announcement is the announcement the channel was added to the
+	// graph with.
+	Announcement *lnwire.ChannelAnnouncement
+
+	// Policy1 and Policy2 are the latest channel updates of NodeID1 and
+	// NodeID2 of the announcement respectively, or nil if none has been
+	// received.
+	Policy1 *lnwire.ChannelUpdate
+	Policy2 *lnwire.ChannelUpdate
+
+	// Private is true if the channel is one of our own which isn't
+	// announced to the network. Its announcement carries no signatures.
+	Private bool
+}
+
+// AddChannelEdge adds the announced channel, funded by the passed outpoint
+// of the passed value, to the channel graph. If the channel is already
+// known, the announcement is ignored.
+func (d *DB) AddChannelEdge(ann *lnwire.ChannelAnnouncement,
+	chanPoint *wire.OutPoint, capacity btcutil.Amount) error {
+
+	return d.addChannelEdge(ann, chanPoint, capacity, false)
+}
+
+// AddPrivateChannelEdge adds one of our private channels to the channel
+// graph, so that we're able to route over it. Its announcement carries no
+// signatures, and it's never handed out in reply to gossip queries.
+func (d *DB) AddPrivateChannelEdge(ann *lnwire.ChannelAnnouncement,
+	chanPoint *wire.OutPoint, capacity btcutil.Amount) error {
+
+	return d.addChannelEdge(ann, chanPoint, capacity, true)
+}
+
+// addChannelEdge adds the channel to the channel graph, unless it's already
+// known.
+func (d *DB) addChannelEdge(ann *lnwire.ChannelAnnouncement,
+	chanPoint *wire.OutPoint, capacity btcutil.Amount, private bool) error {
+
+	err := d.namespace.Update(func(tx walletdb.Tx) error {
+		edges, err := tx.RootBucket().CreateBucketIfNotExists(edgeBucket)
+		if err != nil {
+			return err
+		}
+		chanPoints, err := tx.RootBucket().CreateBucketIfNotExists(
+			chanPointBucket)
+		if err != nil {
+			return err
+		}
+
+		chanID := chanIDKey(ann.ShortChannelID)
+		if edges.Get(chanID[:]) != nil {
+			return nil
+		}
+
+		var b bytes.Buffer
+		if err := writeOutpoint(&b, chanPoint); err != nil {
+			return err
+		}
+		var amt [8]byte
+		endian.PutUint64(amt[:], uint64(capacity))
+		if _, err := b.Write(amt[:]); err != nil {
+			return err
+		}
+		if err := ann.Encode(&b, 0); err != nil {
+			return err
+		}
+		if err := edges.Put(chanID[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		var k bytes.Buffer
+		if err := writeOutpoint(&k, chanPoint); err != nil {
+			return err
+		}
+		if err := chanPoints.Put(k.Bytes(), chanID[:]); err != nil {
+			return err
+		}
+
+		if private {
+			privateEdges, err := tx.RootBucket().CreateBucketIfNotExists(
+				privateEdgeBucket)
+			if err != nil {
+				return err
+			}
+			if err := privateEdges.Put(chanID[:], nil); err != nil {
+				return err
+			}
+		}
+
+		d.graphCache.addChannelEdge(&ChannelEdge{
+			ChannelPoint: *chanPoint,
+			Capacity:     capacity,
+			Announcement: ann,
+			Private:      private,
+		})
+		return nil
+	})
+	if err != nil {
+		d.graphCache.invalidate()
+	}
+
+	return err
+}
+
+// PruneGraph removes every channel funded by one of the spent outpoints
+// from the channel graph, along with their channel updates, then records the
+// block as the new prune tip. The removed channels are returned.
+func (d *DB) PruneGraph(spentOutputs []*wire.OutPoint, blockHash *wire.ShaHash,
+	blockHeight uint32) ([]lnwire.ShortChannelID, error) {
+
+	var closedChans []lnwire.ShortChannelID
+	err := d.namespace.Update(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		edges := rootBucket.Bucket(edgeBucket)
+		chanPoints := rootBucket.Bucket(chanPointBucket)
+		policies := rootBucket.Bucket(edgePolicyBucket)
+		privateEdges := rootBucket.Bucket(privateEdgeBucket)
+
+		for _, op := range spentOutputs {
+			if chanPoints == nil {
+				break
+			}
+
+			var k bytes.Buffer
+			if err := writeOutpoint(&k, op); err != nil {
+				return err
+			}
+			chanIDBytes := chanPoints.Get(k.Bytes())
+			if chanIDBytes == nil {
+				continue
+			}
+			chanID := lnwire.NewShortChanIDFromInt(
+				endian.Uint64(chanIDBytes))
+
+			key := chanIDKey(chanID)
+			if err := edges.Delete(key[:]); err != nil {
+				return err
+			}
+			if policies != nil {
+				for direction := uint8(0); direction < 2; direction++ {
+					policyKey := edgePolicyKey(chanID, direction)
+					if err := policies.Delete(policyKey[:]); err != nil {
+						return err
+					}
+				}
+			}
+			if err := chanPoints.Delete(k.Bytes()); err != nil {
+				return err
+			}
+			if privateEdges != nil {
+				if err := privateEdges.Delete(key[:]); err != nil {
+					return err
+				}
+			}
+
+			d.graphCache.removeChannelEdge(chanID)
+			closedChans = append(closedChans, chanID)
+		}
+
+		pruneTip, err := rootBucket.CreateBucketIfNotExists(pruneTipBucket)
+		if err != nil {
+			return err
+		}
+		var tip [36]byte
+		copy(tip[:32], blockHash[:])
+		endian.PutUint32(tip[32:], blockHeight)
+		return pruneTip.Put(pruneTipKey, tip[:])
+	})
+	if err != nil {
+		d.graphCache.invalidate()
+		return nil, err
+	}
+
+	return closedChans, nil
+}
+
+// PruneTip returns the hash, and height, of the last block the channel graph
+// was pruned with. If the graph has never been pruned, the hash is nil.
+func (d *DB) PruneTip() (*wire.ShaHash, uint32, error) {
+	var (
+		blockHash   *wire.ShaHash
+		blockHeight uint32
+	)
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		pruneTip := tx.RootBucket().Bucket(pruneTipBucket)
+		if pruneTip == nil {
+			return nil
+		}
+		tip := pruneTip.Get(pruneTipKey)
+		if len(tip) != 36 {
+			return nil
+		}
+
+		blockHash = new(wire.ShaHash)
+		copy(blockHash[:], tip[:32])
+		blockHeight = endian.Uint32(tip[32:])
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return blockHash, blockHeight, nil
+}
+
+// GraphCache returns the in-memory copy of the channel graph, loading it from
+// disk on first use.
+func (d *DB) GraphCache() (*GraphCache, error) {
+	if d.graphCache.isLoaded() {
+		return d.graphCache, nil
+	}
+
+	err := d.namespace.Update(func(tx walletdb.Tx) error {
+		return d.graphCache.load(func() ([]*ChannelEdge, error) {
+			return readChannelEdges(tx, func(*ChannelEdge) bool {
+				return true
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.graphCache, nil
+}
+
+// HasChannelEdge returns true if the channel is within the channel graph.
+func (d *DB) HasChannelEdge(chanID lnwire.ShortChannelID) (bool, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return false, err
+	}
+
+	return cache.FetchChannelEdge(chanID) != nil, nil
+}
+
+// IsPrivateChannel returns true if the channel is one of our private
+// channels within the channel graph.
+func (d *DB) IsPrivateChannel(chanID lnwire.ShortChannelID) (bool, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return false, err
+	}
+
+	edge := cache.FetchChannelEdge(chanID)
+	return edge != nil && edge.Private, nil
+}
+
+// FilterKnownChanIDs returns the subset of the passed channels which aren't
+// yet within the channel graph.
+func (d *DB) FilterKnownChanIDs(chanIDs []lnwire.ShortChannelID) ([]lnwire.ShortChannelID, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []lnwire.ShortChannelID
+	for _, chanID := range chanIDs {
+		if cache.FetchChannelEdge(chanID) == nil {
+			unknown = append(unknown, chanID)
+		}
+	}
+
+	return unknown, nil
+}
+
+// FilterChannelRange returns the channels within the channel graph whose
+// funding transaction was confirmed between the start and end heights,
+// inclusive, in ascending order. Our private channels are omitted.
+func (d *DB) FilterChannelRange(startHeight, endHeight uint32) ([]lnwire.ShortChannelID, error) {
+	var chanIDs []lnwire.ShortChannelID
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		edges := tx.RootBucket().Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		privateEdges := tx.RootBucket().Bucket(privateEdgeBucket)
+
+		// TODO: seek to the start height with a cursor
+		// rather than scanning the entire graph.
+		return edges.ForEach(func(k, v []byte) error {
+			chanID := lnwire.NewShortChanIDFromInt(
+				endian.Uint64(k))
+			if chanID.BlockHeight < startHeight ||
+				chanID.BlockHeight > endHeight {
+				return nil
+			}
+			if privateEdges != nil && privateEdges.Get(k) != nil {
+				return nil
+			}
+
+			chanIDs = append(chanIDs, chanID)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanIDs, nil
+}
+
+// FetchChannelEdge returns the announcement of the channel, or nil if the
+// channel isn't within the channel graph.
+func (d *DB) FetchChannelEdge(chanID lnwire.ShortChannelID) (*lnwire.ChannelAnnouncement, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	edge := cache.FetchChannelEdge(chanID)
+	if edge == nil {
+		return nil, nil
+	}
+	return edge.Announcement, nil
+}
+
+// FetchChanAnns returns the announcement of each of the passed channels,
+// each followed by the latest channel update received for either direction.
+// Any channels not within the channel graph, or private, are skipped.
+func (d *DB) FetchChanAnns(chanIDs []lnwire.ShortChannelID) ([]lnwire.Message, error) {
+	var msgs []lnwire.Message
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		edges := tx.RootBucket().Bucket(edgeBucket)
+		if edges == nil {
+			return nil
+		}
+		policies := tx.RootBucket().Bucket(edgePolicyBucket)
+		privateEdges := tx.RootBucket().Bucket(privateEdgeBucket)
+
+		for _, chanID := range chanIDs {
+			if privateEdges != nil {
+				key := chanIDKey(chanID)
+				if privateEdges.Get(key[:]) != nil {
+					continue
+				}
+			}
+
+			ann, err := fetchChanAnn(edges, chanID)
+			if err != nil {
+				return err
+			}
+			if ann == nil {
+				continue
+			}
+			msgs = append(msgs, ann)
+
+			if policies == nil {
+				continue
+			}
+			for direction := uint8(0); direction < 2; direction++ {
+				update, err := fetchEdgePolicy(policies, chanID,
+					direction)
+				if err != nil {
+					return err
+				}
+				if update != nil {
+					msgs = append(msgs, update)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// FetchChannelEdgeInfo returns the channel, along with its latest channel
+// updates. ErrEdgeNotFound is returned if the channel isn't within the
+// channel graph.
+func (d *DB) FetchChannelEdgeInfo(chanID lnwire.ShortChannelID) (*ChannelEdge, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	edge := cache.FetchChannelEdge(chanID)
+	if edge == nil {
+		return nil, ErrEdgeNotFound
+	}
+	return edge, nil
+}
+
+// FetchChannelEdgeByOutpoint returns the channel funded by the outpoint,
+// along with its latest channel updates. ErrEdgeNotFound is returned if the
+// channel isn't within the channel graph.
+func (d *DB) FetchChannelEdgeByOutpoint(op *wire.OutPoint) (*ChannelEdge, error) {
+	var chanID lnwire.ShortChannelID
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		chanPoints := tx.RootBucket().Bucket(chanPointBucket)
+		if chanPoints == nil {
+			return ErrEdgeNotFound
+		}
+
+		var k bytes.Buffer
+		if err := writeOutpoint(&k, op); err != nil {
+			return err
+		}
+		chanIDBytes := chanPoints.Get(k.Bytes())
+		if chanIDBytes == nil {
+			return ErrEdgeNotFound
+		}
+
+		chanID = lnwire.NewShortChanIDFromInt(endian.Uint64(chanIDBytes))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.FetchChannelEdgeInfo(chanID)
+}
+
+// FetchAllChannelEdges returns every channel within the channel graph, along
+// with their latest channel updates, in ascending order of ShortChannelID.
+func (d *DB) FetchAllChannelEdges() ([]*ChannelEdge, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	return cache.FetchAllChannelEdges(), nil
+}
+
+// FetchNodeChannelEdges returns every channel within the channel graph the
+// node is a party to, along with their latest channel updates.
+func (d *DB) FetchNodeChannelEdges(nodeKey *btcec.PublicKey) ([]*ChannelEdge, error) {
+	cache, err := d.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	var edges []*ChannelEdge
+	err = cache.ForEachNodeChannel(nodeKey, func(edge *ChannelEdge) error {
+		edges = append(edges, edge)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edges, nil
+}
+
+// readChannelEdges returns each channel within the channel graph on disk
+// matching the filter.
+func readChannelEdges(tx walletdb.Tx, filter func(*ChannelEdge) bool) ([]*ChannelEdge, error) {
+	edges := tx.RootBucket().Bucket(edgeBucket)
+	if edges == nil {
+		return nil, nil
+	}
+
+	var channelEdges []*ChannelEdge
+	err := edges.ForEach(func(k, v []byte) error {
+		edge, err := fetchChannelEdge(tx, v)
+		if err != nil {
+			return err
+		}
+		if filter(edge) {
+			channelEdges = append(channelEdges, edge)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channelEdges, nil
+}
+
+// NetworkStats summarizes the channel graph.
+type NetworkStats struct {
+	// NumNodes is the number of nodes with at least one channel.
+	NumNodes uint32
+
+	// NumChannels is the number of channels within the graph.
+	NumChannels uint32
+
+	// TotalCapacity is the sum of the capacity of every channel.
+	TotalCapacity btcutil.Amount
+
+	// AvgChannelSize and MedianChannelSize are the mean, and median,
+	// capacity of the channels.
+	AvgChannelSize    float64
+	MedianChannelSize btcutil.Amount
+
+	// MaxOutDegree is the greatest number of channels of any one node.
+	MaxOutDegree uint32
+
+	// NumZombieChans is the number of channels neither node has sent a
+	// channel update for within the zombie horizon, including those
+	// without any updates.
+	NumZombieChans uint32
+}
+
+// FetchNetworkStats computes the statistics of the channel graph as of now.
+func (d *DB) FetchNetworkStats(now time.Time) (*NetworkStats, error) {
+	edges, err := d.FetchAllChannelEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	return ComputeNetworkStats(edges, now.Add(-ZombieHorizon)), nil
+}
+
+// ComputeNetworkStats computes the statistics of the channel graph made up
+// of the passed channels. Channels without a channel update newer than the
+// zombie cutoff are counted as zombies.
+func ComputeNetworkStats(edges []*ChannelEdge, zombieCutoff time.Time) *NetworkStats {
+	stats := &NetworkStats{NumChannels: uint32(len(edges))}
+	if len(edges) == 0 {
+		return stats
+	}
+
+	degrees := make(map[[33]byte]uint32)
+	incDegree := func(nodeKey []byte) {
+		var key [33]byte
+		copy(key[:], nodeKey)
+		degrees[key]++
+		if degrees[key] > stats.MaxOutDegree {
+			stats.MaxOutDegree = degrees[key]
+		}
+	}
+
+	capacities := make([]int64, 0, len(edges))
+	cutoff := zombieCutoff.Unix()
+	for _, edge := range edges {
+		stats.TotalCapacity += edge.Capacity
+		capacities = append(capacities, int64(edge.Capacity))
+
+		incDegree(edge.Announcement.NodeID1.SerializeCompressed())
+		incDegree(edge.Announcement.NodeID2.SerializeCompressed())
+
+		isZombie := true
+		policies := []*lnwire.ChannelUpdate{edge.Policy1, edge.Policy2}
+		for _, policy := range policies {
+			if policy != nil && int64(policy.Timestamp) >= cutoff {
+				isZombie = false
+			}
+		}
+		if isZombie {
+			stats.NumZombieChans++
+		}
+	}
+	stats.NumNodes = uint32(len(degrees))
+	stats.AvgChannelSize = float64(stats.TotalCapacity) / float64(len(edges))
+
+	sort.Sort(int64Slice(capacities))
+	mid := len(capacities) / 2
+	if len(capacities)%2 == 0 {
+		stats.MedianChannelSize = btcutil.Amount(
+			(capacities[mid-1] + capacities[mid]) / 2)
+	} else {
+		stats.MedianChannelSize = btcutil.Amount(capacities[mid])
+	}
+
+	return stats
+}
+
+// int64Slice implements sort.Interface, sorting in ascending order.
+type int64Slice []int64
+
+func (s int64Slice) Len() int           { return len(s) }
+func (s int64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s int64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// UpdateEdgePolicy stores the channel update as the latest for its channel
+// and direction, replacing any previous update. The caller is responsible
+// for ensuring the update is newer than the one it replaces.
+func (d *DB) UpdateEdgePolicy(update *lnwire.ChannelUpdate) error {
+	err := d.namespace.Update(func(tx walletdb.Tx) error {
+		policies, err := tx.RootBucket().CreateBucketIfNotExists(
+			edgePolicyBucket)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := update.Encode(&b, 0); err != nil {
+			return err
+		}
+		key := edgePolicyKey(update.ShortChannelID, update.Direction())
+		if err := policies.Put(key[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		d.graphCache.updatePolicy(update)
+		return nil
+	})
+	if err != nil {
+		d.graphCache.invalidate()
+	}
+
+	return err
+}
+
+// FetchEdgePolicy returns the latest channel update received for the
+// direction of the channel, or nil if none has been received.
+func (d *DB) FetchEdgePolicy(chanID lnwire.ShortChannelID,
+	direction uint8) (*lnwire.ChannelUpdate, error) {
+
+	cache, err := d.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	edge := cache.FetchChannelEdge(chanID)
+	switch {
+	case edge == nil:
+		return nil, nil
+	case direction == 1:
+		return edge.Policy2, nil
+	default:
+		return edge.Policy1, nil
+	}
+}
+
+// fetchChanAnn decodes the announcement of the channel from the edge
+// bucket, returning nil if it isn't known.
+func fetchChanAnn(edges walletdb.Bucket,
+	chanID lnwire.ShortChannelID) (*lnwire.ChannelAnnouncement, error) {
+
+	key := chanIDKey(chanID)
+	edgeBytes := edges.Get(key[:])
+	if edgeBytes == nil {
+		return nil, nil
+	}
+
+	edge, err := decodeChannelEdge(edgeBytes)
+	if err != nil {
+		return nil, err
+	}
+	return edge.Announcement, nil
+}
+
+// fetchChannelEdge decodes the channel from its value within the edge
+// bucket, then adds its latest channel updates from the edge policy bucket.
+func fetchChannelEdge(tx walletdb.Tx, edgeBytes []byte) (*ChannelEdge, error) {
+	edge, err := decodeChannelEdge(edgeBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	chanID := edge.Announcement.ShortChannelID
+	if privateEdges := tx.RootBucket().Bucket(privateEdgeBucket); privateEdges != nil {
+		key := chanIDKey(chanID)
+		edge.Private = privateEdges.Get(key[:]) != nil
+	}
+
+	policies := tx.RootBucket().Bucket(edgePolicyBucket)
+	if policies == nil {
+		return edge, nil
+	}
+
+	edge.Policy1, err = fetchEdgePolicy(policies, chanID, 0)
+	if err != nil {
+		return nil, err
+	}
+	edge.Policy2, err = fetchEdgePolicy(policies, chanID, 1)
+	if err != nil {
+		return nil, err
+	}
+	return edge, nil
+}
+
+// decodeChannelEdge decodes the funding outpoint, capacity, and
+// announcement of a channel from its value within the edge bucket.
+func decodeChannelEdge(edgeBytes []byte) (*ChannelEdge, error) {
+	r := bytes.NewReader(edgeBytes)
+
+	edge := &ChannelEdge{}
+	if err := readOutpoint(r, &edge.ChannelPoint); err != nil {
+		return nil, err
+	}
+	var amt [8]byte
+	if _, err := io.ReadFull(r, amt[:]); err != nil {
+		return nil, err
+	}
+	edge.Capacity = btcutil.Amount(endian.Uint64(amt[:]))
+
+	edge.Announcement = lnwire.NewChannelAnnouncement()
+	if err := edge.Announcement.Decode(r, 0); err != nil {
+		return nil, err
+	}
+	return edge, nil
+}
+
+// fetchEdgePolicy decodes the channel update of the channel's direction
+// from the edge policy bucket, returning nil if it isn't known.
+func fetchEdgePolicy(policies walletdb.Bucket, chanID lnwire.ShortChannelID,
+	direction uint8) (*lnwire.ChannelUpdate, error) {
+
+	key := edgePolicyKey(chanID, direction)
+	updateBytes := policies.Get(key[:])
+	if updateBytes == nil {
+		return nil, nil
+	}
+
+	update := lnwire.NewChannelUpdate()
+	if err := update.Decode(bytes.NewReader(updateBytes), 0); err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// chanIDKey returns the key of the channel within the edge bucket.
+func chanIDKey(chanID lnwire.ShortChannelID) [8]byte {
+	var key [8]byte
+	endian.PutUint64(key[:], chanID.ToUint64())
+	return key
+}
+
+// edgePolicyKey returns the key of the channel's direction within the edge
+// policy bucket.
+func edgePolicyKey(chanID lnwire.ShortChannelID, direction uint8) [9]byte {
+	var key [9]byte
+	endian.PutUint64(key[:8], chanID.ToUint64())
+	key[8] = direction
+	return key
+}
@@ -0,0 +1,327 @@
+package channeldb
+
+import (
+	"expvar"
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// The size of the graph cache, exposed under /debug/vars. The memory usage is
+// an estimate, see GraphCache.MemoryUsage.
+var (
+	graphCacheChannels = expvar.NewInt("graphCacheChannels")
+	graphCacheNodes    = expvar.NewInt("graphCacheNodes")
+	graphCacheBytes    = expvar.NewInt("graphCacheBytes")
+)
+
+const (
+	// cachedEdgeSize is the approximate memory used by each channel within
+	// the graph cache: the ChannelEdge itself, its decoded announcement,
+	// and the map entries indexing it.
+	cachedEdgeSize = 1200
+
+	// cachedPolicySize is the approximate memory used by each channel
+	// update within the graph cache.
+	cachedPolicySize = 250
+
+	// cachedNodeSize is the approximate memory used by the index of the
+	// channels of each node, excluding its entries.
+	cachedNodeSize = 100
+)
+
+// GraphCache is an in-memory copy of the channel graph, indexed by node, so
+// that pathfinding can walk the graph without reading from disk. It's kept in
+// sync with each write made to the channel graph within the database.
+//
+// The channels returned by the cache are shared with it, and MUST NOT be
+// modified.
+type GraphCache struct {
+	sync.RWMutex
+
+	// loaded is true once the cache has been populated from disk. Until
+	// then, writes to the graph are only made to the database.
+	loaded bool
+
+	edges     map[uint64]*ChannelEdge
+	nodeChans map[[33]byte]map[uint64]struct{}
+
+	numPolicies int
+}
+
+// newGraphCache creates an empty graph cache, to be loaded on first use.
+func newGraphCache() *GraphCache {
+	return &GraphCache{
+		edges:     make(map[uint64]*ChannelEdge),
+		nodeChans: make(map[[33]byte]map[uint64]struct{}),
+	}
+}
+
+// isLoaded returns true if the cache has been populated from disk.
+func (c *GraphCache) isLoaded() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.loaded
+}
+
+// load populates the cache with the channels returned by fetchEdges, unless
+// it's already loaded.
+//
+// NOTE: This MUST be called within a write transaction of the database, so
+// that no writes to the graph interleave with the load. As with the writes,
+// the transaction is always taken before the cache's mutex.
+func (c *GraphCache) load(fetchEdges func() ([]*ChannelEdge, error)) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.loaded {
+		return nil
+	}
+
+	edges, err := fetchEdges()
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		c.addEdge(edge)
+	}
+	c.loaded = true
+
+	c.updateMetrics()
+	return nil
+}
+
+// invalidate empties the cache, to be reloaded from disk on next use. It's
+// called when a write to the graph fails, in case the cache was updated
+// before the write was rolled back.
+func (c *GraphCache) invalidate() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.loaded = false
+	c.edges = make(map[uint64]*ChannelEdge)
+	c.nodeChans = make(map[[33]byte]map[uint64]struct{})
+	c.numPolicies = 0
+
+	c.updateMetrics()
+}
+
+// addChannelEdge adds the channel to the cache if it's loaded.
+func (c *GraphCache) addChannelEdge(edge *ChannelEdge) {
+	c.Lock()
+	defer c.Unlock()
+
+	if !c.loaded {
+		return
+	}
+
+	c.addEdge(edge)
+	c.updateMetrics()
+}
+
+// addEdge adds the channel to the cache, replacing any existing channel
+// with the same ShortChannelID.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *GraphCache) addEdge(edge *ChannelEdge) {
+	chanID := edge.Announcement.ShortChannelID.ToUint64()
+	if _, ok := c.edges[chanID]; ok {
+		c.removeEdge(chanID)
+	}
+
+	c.edges[chanID] = edge
+	c.numPolicies += numPolicies(edge)
+
+	for _, nodeID := range []*btcec.PublicKey{
+		edge.Announcement.NodeID1, edge.Announcement.NodeID2,
+	} {
+		nodeKey := cacheNodeKey(nodeID)
+		chans, ok := c.nodeChans[nodeKey]
+		if !ok {
+			chans = make(map[uint64]struct{})
+			c.nodeChans[nodeKey] = chans
+		}
+		chans[chanID] = struct{}{}
+	}
+}
+
+// removeChannelEdge removes the channel from the cache, if present.
+func (c *GraphCache) removeChannelEdge(chanID lnwire.ShortChannelID) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.removeEdge(chanID.ToUint64())
+	c.updateMetrics()
+}
+
+// removeEdge removes the channel from the cache, along with any nodes left
+// without channels.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *GraphCache) removeEdge(chanID uint64) {
+	edge, ok := c.edges[chanID]
+	if !ok {
+		return
+	}
+	delete(c.edges, chanID)
+	c.numPolicies -= numPolicies(edge)
+
+	for _, nodeID := range []*btcec.PublicKey{
+		edge.Announcement.NodeID1, edge.Announcement.NodeID2,
+	} {
+		nodeKey := cacheNodeKey(nodeID)
+		chans := c.nodeChans[nodeKey]
+		delete(chans, chanID)
+		if len(chans) == 0 {
+			delete(c.nodeChans, nodeKey)
+		}
+	}
+}
+
+// updatePolicy stores the channel update as the latest for its channel and
+// direction, unless the cached update is newer.
+func (c *GraphCache) updatePolicy(update *lnwire.ChannelUpdate) {
+	c.Lock()
+	defer c.Unlock()
+
+	chanID := update.ShortChannelID.ToUint64()
+	edge, ok := c.edges[chanID]
+	if !ok {
+		return
+	}
+
+	// The cached channel may be in use by our callers, so it's copied
+	// rather than modified in place.
+	newEdge := *edge
+	policy := &newEdge.Policy1
+	if update.Direction() == 1 {
+		policy = &newEdge.Policy2
+	}
+	if *policy != nil && (*policy).Timestamp > update.Timestamp {
+		return
+	}
+	*policy = update
+
+	c.numPolicies += numPolicies(&newEdge) - numPolicies(edge)
+	c.edges[chanID] = &newEdge
+
+	c.updateMetrics()
+}
+
+// FetchChannelEdge returns the channel, or nil if it isn't within the cache.
+func (c *GraphCache) FetchChannelEdge(chanID lnwire.ShortChannelID) *ChannelEdge {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.edges[chanID.ToUint64()]
+}
+
+// FetchAllChannelEdges returns every channel within the cache, in ascending
+// order of ShortChannelID.
+func (c *GraphCache) FetchAllChannelEdges() []*ChannelEdge {
+	c.RLock()
+	defer c.RUnlock()
+
+	chanIDs := make([]uint64, 0, len(c.edges))
+	for chanID := range c.edges {
+		chanIDs = append(chanIDs, chanID)
+	}
+	sort.Sort(uint64Slice(chanIDs))
+
+	edges := make([]*ChannelEdge, 0, len(chanIDs))
+	for _, chanID := range chanIDs {
+		edges = append(edges, c.edges[chanID])
+	}
+	return edges
+}
+
+// ForEachNodeChannel calls cb with each channel the node is a party to,
+// stopping at the first error returned.
+//
+// NOTE: The cache's read lock is held while cb is run, so cb MUST NOT
+// write to the channel graph.
+func (c *GraphCache) ForEachNodeChannel(nodeKey *btcec.PublicKey,
+	cb func(*ChannelEdge) error) error {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	for chanID := range c.nodeChans[cacheNodeKey(nodeKey)] {
+		if err := cb(c.edges[chanID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NumChannels returns the number of channels within the cache.
+func (c *GraphCache) NumChannels() int {
+	c.RLock()
+	defer c.RUnlock()
+
+	return len(c.edges)
+}
+
+// NumNodes returns the number of nodes with at least one channel within the
+// cache.
+func (c *GraphCache) NumNodes() int {
+	c.RLock()
+	defer c.RUnlock()
+
+	return len(c.nodeChans)
+}
+
+// MemoryUsage returns an estimate of the memory used by the cache, in bytes.
+func (c *GraphCache) MemoryUsage() uint64 {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.memoryUsage()
+}
+
+// memoryUsage returns an estimate of the memory used by the cache.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *GraphCache) memoryUsage() uint64 {
+	return uint64(len(c.edges)*cachedEdgeSize +
+		c.numPolicies*cachedPolicySize +
+		len(c.nodeChans)*cachedNodeSize)
+}
+
+// updateMetrics exposes the current size of the cache.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (c *GraphCache) updateMetrics() {
+	graphCacheChannels.Set(int64(len(c.edges)))
+	graphCacheNodes.Set(int64(len(c.nodeChans)))
+	graphCacheBytes.Set(int64(c.memoryUsage()))
+}
+
+// numPolicies returns the number of channel updates known for the channel.
+func numPolicies(edge *ChannelEdge) int {
+	var n int
+	if edge.Policy1 != nil {
+		n++
+	}
+	if edge.Policy2 != nil {
+		n++
+	}
+	return n
+}
+
+// cacheNodeKey returns the key of the node within the cache.
+func cacheNodeKey(nodeID *btcec.PublicKey) [33]byte {
+	var key [33]byte
+	copy(key[:], nodeID.SerializeCompressed())
+	return key
+}
+
+// uint64Slice implements sort.Interface, sorting in ascending order.
+type uint64Slice []uint64
+
+func (s uint64Slice) Len() int           { return len(s) }
+func (s uint64Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s uint64Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,106 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func TestGraphCache(t *testing.T) {
+	var nodeKeys []*btcec.PublicKey
+	for i := byte(1); i <= 3; i++ {
+		_, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{i}, 32))
+		nodeKeys = append(nodeKeys, pub)
+	}
+
+	newEdge := func(height uint32, node1, node2 int) *ChannelEdge {
+		return &ChannelEdge{
+			Capacity: 1000,
+			Announcement: &lnwire.ChannelAnnouncement{
+				ShortChannelID: lnwire.ShortChannelID{
+					BlockHeight: height,
+				},
+				NodeID1: nodeKeys[node1],
+				NodeID2: nodeKeys[node2],
+			},
+		}
+	}
+	edge1 := newEdge(200, 0, 1)
+	edge2 := newEdge(100, 1, 2)
+	chanID1 := edge1.Announcement.ShortChannelID
+	chanID2 := edge2.Announcement.ShortChannelID
+
+	// Until loaded, writes are ignored.
+	cache := newGraphCache()
+	cache.addChannelEdge(edge2)
+	if cache.NumChannels() != 0 {
+		t.Fatalf("unloaded cache was written to")
+	}
+
+	err := cache.load(func() ([]*ChannelEdge, error) {
+		return []*ChannelEdge{edge1}, nil
+	})
+	if err != nil {
+		t.Fatalf("unable to load cache: %v", err)
+	}
+	cache.addChannelEdge(edge2)
+
+	if cache.NumChannels() != 2 || cache.NumNodes() != 3 {
+		t.Fatalf("expected 2 channels across 3 nodes, got %v across %v",
+			cache.NumChannels(), cache.NumNodes())
+	}
+	edges := cache.FetchAllChannelEdges()
+	if len(edges) != 2 || edges[0] != edge2 || edges[1] != edge1 {
+		t.Fatalf("channels aren't in ascending order")
+	}
+
+	var numChans int
+	err = cache.ForEachNodeChannel(nodeKeys[1], func(*ChannelEdge) error {
+		numChans++
+		return nil
+	})
+	if err != nil || numChans != 2 {
+		t.Fatalf("expected 2 channels of node, got %v: %v", numChans,
+			err)
+	}
+
+	// A newer update replaces the cached one, but an older one doesn't.
+	usage := cache.MemoryUsage()
+	update := &lnwire.ChannelUpdate{
+		ShortChannelID: chanID1,
+		Timestamp:      2,
+		Flags:          lnwire.ChanUpdateDirection,
+	}
+	cache.updatePolicy(update)
+	cache.updatePolicy(&lnwire.ChannelUpdate{
+		ShortChannelID: chanID1,
+		Timestamp:      1,
+		Flags:          lnwire.ChanUpdateDirection,
+	})
+	cached := cache.FetchChannelEdge(chanID1)
+	if cached.Policy2 != update || cached.Policy1 != nil {
+		t.Fatalf("unexpected policies: %v, %v", cached.Policy1,
+			cached.Policy2)
+	}
+	if edge1.Policy2 != nil {
+		t.Fatalf("channel modified in place")
+	}
+	if cache.MemoryUsage() != usage+cachedPolicySize {
+		t.Fatalf("memory usage doesn't account for policy")
+	}
+
+	// Removing a channel drops any nodes left without channels.
+	cache.removeChannelEdge(chanID2)
+	if cache.FetchChannelEdge(chanID2) != nil || cache.NumNodes() != 2 {
+		t.Fatalf("channel wasn't removed")
+	}
+
+	cache.invalidate()
+	if cache.isLoaded() || cache.NumChannels() != 0 ||
+		cache.MemoryUsage() != 0 {
+		t.Fatalf("cache wasn't invalidated")
+	}
+}
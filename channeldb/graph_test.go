@@ -0,0 +1,76 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func TestComputeNetworkStats(t *testing.T) {
+	var nodeKeys []*btcec.PublicKey
+	for i := byte(1); i <= 4; i++ {
+		_, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{i}, 32))
+		nodeKeys = append(nodeKeys, pub)
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-ZombieHorizon)
+	fresh := &lnwire.ChannelUpdate{Timestamp: uint32(now.Unix())}
+	stale := &lnwire.ChannelUpdate{
+		Timestamp: uint32(cutoff.Add(-time.Hour).Unix()),
+	}
+
+	// The first node has a channel with each of the others, and the last
+	// two nodes a channel between themselves.
+	newEdge := func(node1, node2 int, capacity btcutil.Amount,
+		policy1, policy2 *lnwire.ChannelUpdate) *ChannelEdge {
+
+		return &ChannelEdge{
+			Capacity: capacity,
+			Announcement: &lnwire.ChannelAnnouncement{
+				NodeID1: nodeKeys[node1],
+				NodeID2: nodeKeys[node2],
+			},
+			Policy1: policy1,
+			Policy2: policy2,
+		}
+	}
+	edges := []*ChannelEdge{
+		newEdge(0, 1, 1000, fresh, stale),
+		newEdge(0, 2, 4000, stale, stale),
+		newEdge(0, 3, 2000, nil, fresh),
+		newEdge(2, 3, 5000, nil, nil),
+	}
+
+	stats := ComputeNetworkStats(edges, cutoff)
+	expected := &NetworkStats{
+		NumNodes:          4,
+		NumChannels:       4,
+		TotalCapacity:     12000,
+		AvgChannelSize:    3000,
+		MedianChannelSize: 3000,
+		MaxOutDegree:      3,
+		NumZombieChans:    2,
+	}
+	if *stats != *expected {
+		t.Fatalf("stats don't match: expected %v, got %v", expected,
+			stats)
+	}
+
+	// With an odd number of channels, the median is the middle channel.
+	stats = ComputeNetworkStats(edges[:3], cutoff)
+	if stats.MedianChannelSize != 2000 || stats.NumNodes != 4 {
+		t.Fatalf("expected median of 2000 across 4 nodes, got %v "+
+			"across %v", stats.MedianChannelSize, stats.NumNodes)
+	}
+
+	if stats := ComputeNetworkStats(nil, cutoff); stats.NumNodes != 0 ||
+		stats.AvgChannelSize != 0 {
+		t.Fatalf("empty graph has non-zero stats: %v", stats)
+	}
+}
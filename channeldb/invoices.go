@@ -0,0 +1,605 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/blinding"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// invoiceBucket houses all invoices, keyed by their payment hash.
+	invoiceBucket = []byte("i")
+)
+
+// InvoiceState describes where an invoice is within its lifecycle.
+type InvoiceState uint8
+
+const (
+	// InvoiceOpen indicates the invoice is still awaiting payment.
+	InvoiceOpen InvoiceState = iota
+
+	// InvoiceSettled indicates the invoice has been paid.
+	InvoiceSettled
+
+	// InvoiceCanceled indicates the invoice was canceled before being
+	// paid, either manually or due to expiring. Canceled invoices can no
+	// longer be paid.
+	InvoiceCanceled
+)
+
+// String returns a human readable version of the state.
+func (i InvoiceState) String() string {
+	switch i {
+	case InvoiceOpen:
+		return "open"
+	case InvoiceSettled:
+		return "settled"
+	case InvoiceCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// InvoiceHTLC is a single HTLC paying to an AMP invoice.
+type InvoiceHTLC struct {
+	// Share is the share of the payment's root seed carried by the HTLC.
+	Share [32]byte
+
+	// ChildIndex is the index of the HTLC within its set.
+	ChildIndex uint32
+
+	Amount     lnwire.MilliSatoshi
+	AcceptTime time.Time
+}
+
+// InvoiceHTLCSet tracks the HTLCs making up a single payment to an AMP
+// invoice.
+type InvoiceHTLCSet struct {
+	// State is open while HTLCs of the set are still arriving, and settled
+	// or canceled once the set has been resolved.
+	State InvoiceState
+
+	StateChangeDate time.Time
+
+	HTLCs []*InvoiceHTLC
+}
+
+// Total returns the sum of the amounts of all HTLCs within the set.
+func (s *InvoiceHTLCSet) Total() lnwire.MilliSatoshi {
+	var total lnwire.MilliSatoshi
+	for _, htlc := range s.HTLCs {
+		total += htlc.Amount
+	}
+	return total
+}
+
+// Invoice is a payment request we've generated for a remote party to pay.
+type Invoice struct {
+	// Memo is an optional description of the invoice.
+	Memo string
+
+	// Preimage is the value which settles HTLCs paying to this invoice.
+	// For AMP invoices, each payment derives its own preimages, so this is
+	// only a random value from which the key of the invoice is derived.
+	Preimage [20]byte
+
+	// PaymentSecret is a random value included in the invoice, which the
+	// payer must present within the final hop payload of each HTLC. As
+	// it's only known to the payer, it prevents intermediate nodes from
+	// probing whether we're the final destination of a payment hash.
+	PaymentSecret [32]byte
+
+	Value        lnwire.MilliSatoshi
+	CreationDate time.Time
+
+	// Expiry is how long after the creation date the invoice remains
+	// payable. An expiry of zero means the invoice never expires.
+	Expiry time.Duration
+
+	State InvoiceState
+
+	// StateChangeDate is the time the invoice was last settled or
+	// canceled.
+	StateChangeDate time.Time
+
+	// AMP marks the invoice as reusable, accepting any number of AMP
+	// payments. Rather than being settled as a whole, each payment is
+	// tracked as a separate HTLC set within HTLCSets.
+	AMP bool
+
+	// HTLCSets holds each payment to an AMP invoice, keyed by set ID.
+	HTLCSets map[[32]byte]*InvoiceHTLCSet
+
+	// BlindedPaths are the blinded paths included within the invoice,
+	// allowing it to be paid without the payer learning our identity.
+	BlindedPaths []*blinding.PaymentPath
+}
+
+// PaymentHash returns the hash which HTLCs paying to the invoice are locked
+// to.
+func (i *Invoice) PaymentHash() [20]byte {
+	var hash [20]byte
+	copy(hash[:], btcutil.Hash160(i.Preimage[:]))
+	return hash
+}
+
+// IsExpired returns true if the invoice is open, yet past its expiry.
+func (i *Invoice) IsExpired(now time.Time) bool {
+	if i.State != InvoiceOpen || i.Expiry == 0 {
+		return false
+	}
+
+	return now.After(i.CreationDate.Add(i.Expiry))
+}
+
+// AddInvoice stores a new invoice, keyed by its payment hash.
+func (d *DB) AddInvoice(invoice *Invoice) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		invoices, err := tx.RootBucket().CreateBucketIfNotExists(invoiceBucket)
+		if err != nil {
+			return err
+		}
+
+		paymentHash := invoice.PaymentHash()
+		if invoices.Get(paymentHash[:]) != nil {
+			return ErrDuplicateInvoice
+		}
+
+		return putInvoice(invoices, invoice)
+	})
+}
+
+// LookupInvoice returns the invoice paid to by the passed payment hash.
+func (d *DB) LookupInvoice(paymentHash [20]byte) (*Invoice, error) {
+	var invoice *Invoice
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		invoices := tx.RootBucket().Bucket(invoiceBucket)
+		if invoices == nil {
+			return ErrInvoiceNotFound
+		}
+
+		i, err := fetchInvoice(invoices, paymentHash)
+		if err != nil {
+			return err
+		}
+		invoice = i
+		return nil
+	})
+
+	return invoice, err
+}
+
+// FetchAllInvoices returns all stored invoices. If openOnly is true, then
+// only invoices which are still awaiting payment are returned.
+func (d *DB) FetchAllInvoices(openOnly bool) ([]*Invoice, error) {
+	var invoices []*Invoice
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		invoiceB := tx.RootBucket().Bucket(invoiceBucket)
+		if invoiceB == nil {
+			return nil
+		}
+
+		return invoiceB.ForEach(func(k, v []byte) error {
+			invoice := &Invoice{}
+			if err := invoice.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+
+			if openOnly && invoice.State != InvoiceOpen {
+				return nil
+			}
+
+			invoices = append(invoices, invoice)
+			return nil
+		})
+	})
+
+	return invoices, err
+}
+
+// SettleInvoice marks the invoice paid to by the passed payment hash as
+// settled.
+func (d *DB) SettleInvoice(paymentHash [20]byte) error {
+	return d.updateInvoiceState(paymentHash, InvoiceSettled)
+}
+
+// CancelInvoice marks the invoice paid to by the passed payment hash as
+// canceled. Only open invoices can be canceled.
+func (d *DB) CancelInvoice(paymentHash [20]byte) error {
+	return d.updateInvoiceState(paymentHash, InvoiceCanceled)
+}
+
+// DeleteInvoice removes the invoice paid to by the passed payment hash.
+func (d *DB) DeleteInvoice(paymentHash [20]byte) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		invoices := tx.RootBucket().Bucket(invoiceBucket)
+		if invoices == nil || invoices.Get(paymentHash[:]) == nil {
+			return ErrInvoiceNotFound
+		}
+
+		return invoices.Delete(paymentHash[:])
+	})
+}
+
+// updateInvoiceState transitions an open invoice to the passed terminal
+// state. As invoices are settled on the HTLC hot path, the write is
+// batched with those of other channel updates.
+func (d *DB) updateInvoiceState(paymentHash [20]byte, state InvoiceState) error {
+	return d.chanScheduler.Execute(&batch.Request{
+		Update: func(tx walletdb.Tx) error {
+			invoices := tx.RootBucket().Bucket(invoiceBucket)
+			if invoices == nil {
+				return ErrInvoiceNotFound
+			}
+
+			invoice, err := fetchInvoice(invoices, paymentHash)
+			if err != nil {
+				return err
+			}
+
+			if invoice.State != InvoiceOpen {
+				return fmt.Errorf("invoice %x is already %v",
+					paymentHash, invoice.State)
+			}
+
+			invoice.State = state
+			invoice.StateChangeDate = time.Now()
+
+			return putInvoice(invoices, invoice)
+		},
+	})
+}
+
+// AddInvoiceHTLC records a newly accepted HTLC as part of the passed set of
+// the AMP invoice, creating the set if this is its first HTLC. The updated
+// invoice is returned. The write is batched with those of other channel
+// updates.
+func (d *DB) AddInvoiceHTLC(paymentHash [20]byte, setID [32]byte,
+	htlc *InvoiceHTLC) (*Invoice, error) {
+
+	var invoice *Invoice
+	err := d.chanScheduler.Execute(&batch.Request{
+		Reset: func() {
+			invoice = nil
+		},
+		Update: func(tx walletdb.Tx) error {
+			invoices := tx.RootBucket().Bucket(invoiceBucket)
+			if invoices == nil {
+				return ErrInvoiceNotFound
+			}
+
+			i, err := fetchInvoice(invoices, paymentHash)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case !i.AMP:
+				return ErrNotAMPInvoice
+			case i.State != InvoiceOpen:
+				return fmt.Errorf("invoice %x is %v", paymentHash,
+					i.State)
+			}
+
+			if i.HTLCSets == nil {
+				i.HTLCSets = make(map[[32]byte]*InvoiceHTLCSet)
+			}
+			htlcSet, ok := i.HTLCSets[setID]
+			if !ok {
+				htlcSet = &InvoiceHTLCSet{State: InvoiceOpen}
+				i.HTLCSets[setID] = htlcSet
+			}
+			if htlcSet.State != InvoiceOpen {
+				return fmt.Errorf("htlc set %x is already %v",
+					setID, htlcSet.State)
+			}
+
+			htlcSet.HTLCs = append(htlcSet.HTLCs, htlc)
+
+			invoice = i
+			return putInvoice(invoices, i)
+		},
+	})
+
+	return invoice, err
+}
+
+// SettleHTLCSet marks the passed set of the AMP invoice as settled.
+func (d *DB) SettleHTLCSet(paymentHash [20]byte, setID [32]byte) error {
+	return d.updateHTLCSetState(paymentHash, setID, InvoiceSettled)
+}
+
+// CancelHTLCSet marks the passed set of the AMP invoice as canceled.
+func (d *DB) CancelHTLCSet(paymentHash [20]byte, setID [32]byte) error {
+	return d.updateHTLCSetState(paymentHash, setID, InvoiceCanceled)
+}
+
+// updateHTLCSetState transitions an open HTLC set to the passed terminal
+// state. The write is batched with those of other channel updates.
+func (d *DB) updateHTLCSetState(paymentHash [20]byte, setID [32]byte,
+	state InvoiceState) error {
+
+	return d.chanScheduler.Execute(&batch.Request{
+		Update: func(tx walletdb.Tx) error {
+			invoices := tx.RootBucket().Bucket(invoiceBucket)
+			if invoices == nil {
+				return ErrInvoiceNotFound
+			}
+
+			invoice, err := fetchInvoice(invoices, paymentHash)
+			if err != nil {
+				return err
+			}
+
+			htlcSet, ok := invoice.HTLCSets[setID]
+			if !ok {
+				return ErrHTLCSetNotFound
+			}
+			if htlcSet.State != InvoiceOpen {
+				return fmt.Errorf("htlc set %x is already %v",
+					setID, htlcSet.State)
+			}
+
+			htlcSet.State = state
+			htlcSet.StateChangeDate = time.Now()
+
+			return putInvoice(invoices, invoice)
+		},
+	})
+}
+
+// putInvoice writes the serialized invoice under its payment hash.
+func putInvoice(invoices walletdb.Bucket, invoice *Invoice) error {
+	var b bytes.Buffer
+	if err := invoice.Encode(&b); err != nil {
+		return err
+	}
+
+	paymentHash := invoice.PaymentHash()
+	return invoices.Put(paymentHash[:], b.Bytes())
+}
+
+// fetchInvoice reads the invoice stored under the passed payment hash.
+func fetchInvoice(invoices walletdb.Bucket, paymentHash [20]byte) (*Invoice, error) {
+	serializedInvoice := invoices.Get(paymentHash[:])
+	if serializedInvoice == nil {
+		return nil, ErrInvoiceNotFound
+	}
+
+	invoice := &Invoice{}
+	if err := invoice.Decode(bytes.NewReader(serializedInvoice)); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// Encode...
+func (i *Invoice) Encode(w io.Writer) error {
+	if len(i.Memo) > 65535 {
+		return fmt.Errorf("memo too long")
+	}
+	if err := binary.Write(w, endian, uint16(len(i.Memo))); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(i.Memo)); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(i.Preimage[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(i.PaymentSecret[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, int64(i.Value)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, i.CreationDate.Unix()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, int64(i.Expiry)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, i.State); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, i.StateChangeDate.Unix()); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, endian, i.AMP); err != nil {
+		return err
+	}
+	if len(i.HTLCSets) > 65535 {
+		return fmt.Errorf("too many htlc sets")
+	}
+	if err := binary.Write(w, endian, uint16(len(i.HTLCSets))); err != nil {
+		return err
+	}
+	for setID, htlcSet := range i.HTLCSets {
+		if _, err := w.Write(setID[:]); err != nil {
+			return err
+		}
+		if err := htlcSet.Encode(w); err != nil {
+			return err
+		}
+	}
+
+	if len(i.BlindedPaths) > 255 {
+		return fmt.Errorf("too many blinded paths")
+	}
+	if err := binary.Write(w, endian, uint8(len(i.BlindedPaths))); err != nil {
+		return err
+	}
+	for _, path := range i.BlindedPaths {
+		if err := path.Encode(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Encode...
+func (s *InvoiceHTLCSet) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, s.State); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, s.StateChangeDate.Unix()); err != nil {
+		return err
+	}
+
+	if len(s.HTLCs) > 65535 {
+		return fmt.Errorf("too many htlcs in set")
+	}
+	if err := binary.Write(w, endian, uint16(len(s.HTLCs))); err != nil {
+		return err
+	}
+	for _, htlc := range s.HTLCs {
+		if _, err := w.Write(htlc.Share[:]); err != nil {
+			return err
+		}
+		if err := binary.Write(w, endian, htlc.ChildIndex); err != nil {
+			return err
+		}
+		if err := binary.Write(w, endian, int64(htlc.Amount)); err != nil {
+			return err
+		}
+		if err := binary.Write(w, endian, htlc.AcceptTime.Unix()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode...
+func (s *InvoiceHTLCSet) Decode(r io.Reader) error {
+	if err := binary.Read(r, endian, &s.State); err != nil {
+		return err
+	}
+
+	var scratch int64
+	if err := binary.Read(r, endian, &scratch); err != nil {
+		return err
+	}
+	s.StateChangeDate = time.Unix(scratch, 0)
+
+	var numHTLCs uint16
+	if err := binary.Read(r, endian, &numHTLCs); err != nil {
+		return err
+	}
+	for i := uint16(0); i < numHTLCs; i++ {
+		htlc := &InvoiceHTLC{}
+		if _, err := io.ReadFull(r, htlc.Share[:]); err != nil {
+			return err
+		}
+		if err := binary.Read(r, endian, &htlc.ChildIndex); err != nil {
+			return err
+		}
+		if err := binary.Read(r, endian, &scratch); err != nil {
+			return err
+		}
+		htlc.Amount = lnwire.MilliSatoshi(scratch)
+		if err := binary.Read(r, endian, &scratch); err != nil {
+			return err
+		}
+		htlc.AcceptTime = time.Unix(scratch, 0)
+
+		s.HTLCs = append(s.HTLCs, htlc)
+	}
+
+	return nil
+}
+
+// Decode...
+func (i *Invoice) Decode(r io.Reader) error {
+	var memoLen uint16
+	if err := binary.Read(r, endian, &memoLen); err != nil {
+		return err
+	}
+	memo := make([]byte, memoLen)
+	if _, err := io.ReadFull(r, memo); err != nil {
+		return err
+	}
+	i.Memo = string(memo)
+
+	if _, err := io.ReadFull(r, i.Preimage[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, i.PaymentSecret[:]); err != nil {
+		return err
+	}
+
+	var scratch int64
+	if err := binary.Read(r, endian, &scratch); err != nil {
+		return err
+	}
+	i.Value = lnwire.MilliSatoshi(scratch)
+
+	if err := binary.Read(r, endian, &scratch); err != nil {
+		return err
+	}
+	i.CreationDate = time.Unix(scratch, 0)
+
+	if err := binary.Read(r, endian, &scratch); err != nil {
+		return err
+	}
+	i.Expiry = time.Duration(scratch)
+
+	if err := binary.Read(r, endian, &i.State); err != nil {
+		return err
+	}
+
+	if err := binary.Read(r, endian, &scratch); err != nil {
+		return err
+	}
+	i.StateChangeDate = time.Unix(scratch, 0)
+
+	if err := binary.Read(r, endian, &i.AMP); err != nil {
+		return err
+	}
+	var numSets uint16
+	if err := binary.Read(r, endian, &numSets); err != nil {
+		return err
+	}
+	if numSets > 0 {
+		i.HTLCSets = make(map[[32]byte]*InvoiceHTLCSet, numSets)
+	}
+	for j := uint16(0); j < numSets; j++ {
+		var setID [32]byte
+		if _, err := io.ReadFull(r, setID[:]); err != nil {
+			return err
+		}
+
+		htlcSet := &InvoiceHTLCSet{}
+		if err := htlcSet.Decode(r); err != nil {
+			return err
+		}
+		i.HTLCSets[setID] = htlcSet
+	}
+
+	var numPaths uint8
+	if err := binary.Read(r, endian, &numPaths); err != nil {
+		return err
+	}
+	for j := uint8(0); j < numPaths; j++ {
+		path := &blinding.PaymentPath{}
+		if err := path.Decode(r); err != nil {
+			return err
+		}
+		i.BlindedPaths = append(i.BlindedPaths, path)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/blinding"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func makeTestInvoice(i byte) *Invoice {
+	return &Invoice{
+		Memo:            "coffee",
+		Preimage:        [20]byte{i},
+		PaymentSecret:   [32]byte{i, i},
+		Value:           lnwire.MilliSatoshi(5000000),
+		CreationDate:    time.Unix(1000, 0),
+		Expiry:          time.Hour,
+		State:           InvoiceOpen,
+		StateChangeDate: time.Unix(0, 0),
+	}
+}
+
+func TestInvoiceEncodeDecode(t *testing.T) {
+	invoice := makeTestInvoice(1)
+
+	sessionKey, introNode := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{1}, 32))
+	path, err := blinding.BuildBlindedPath(sessionKey, []*blinding.HopInfo{
+		{NodePub: introNode, Payload: []byte{1, 2, 3}},
+	})
+	if err != nil {
+		t.Fatalf("unable to build blinded path: %v", err)
+	}
+	invoice.BlindedPaths = []*blinding.PaymentPath{{
+		BlindedPath:     path,
+		BaseFee:         1000,
+		FeeRate:         1,
+		CltvExpiryDelta: 40,
+	}}
+
+	var b bytes.Buffer
+	if err := invoice.Encode(&b); err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	newInvoice := &Invoice{}
+	if err := newInvoice.Decode(&b); err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	if !reflect.DeepEqual(invoice, newInvoice) {
+		t.Fatalf("invoice doesn't match: %v vs %v", invoice, newInvoice)
+	}
+}
+
+func TestInvoiceExpiry(t *testing.T) {
+	invoice := makeTestInvoice(1)
+
+	if invoice.IsExpired(invoice.CreationDate.Add(time.Minute)) {
+		t.Fatalf("invoice shouldn't be expired yet")
+	}
+	if !invoice.IsExpired(invoice.CreationDate.Add(2 * time.Hour)) {
+		t.Fatalf("invoice should be expired")
+	}
+
+	// Invoices with no expiry, or which are no longer open, never expire.
+	invoice.Expiry = 0
+	if invoice.IsExpired(invoice.CreationDate.Add(2 * time.Hour)) {
+		t.Fatalf("invoice without expiry shouldn't expire")
+	}
+	invoice.Expiry = time.Hour
+	invoice.State = InvoiceSettled
+	if invoice.IsExpired(invoice.CreationDate.Add(2 * time.Hour)) {
+		t.Fatalf("settled invoice shouldn't expire")
+	}
+}
+
+func TestInvoiceCancelDelete(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	invoice := makeTestInvoice(1)
+	if err := db.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	if err := db.AddInvoice(invoice); err != ErrDuplicateInvoice {
+		t.Fatalf("expected ErrDuplicateInvoice, got %v", err)
+	}
+
+	paymentHash := invoice.PaymentHash()
+	if err := db.CancelInvoice(paymentHash); err != nil {
+		t.Fatalf("unable to cancel invoice: %v", err)
+	}
+
+	dbInvoice, err := db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if dbInvoice.State != InvoiceCanceled {
+		t.Fatalf("invoice should be canceled, is %v", dbInvoice.State)
+	}
+
+	// A canceled invoice can't be settled.
+	if err := db.SettleInvoice(paymentHash); err == nil {
+		t.Fatalf("canceled invoice shouldn't be settled")
+	}
+
+	openInvoices, err := db.FetchAllInvoices(true)
+	if err != nil {
+		t.Fatalf("unable to fetch invoices: %v", err)
+	}
+	if len(openInvoices) != 0 {
+		t.Fatalf("expected no open invoices, got %v", len(openInvoices))
+	}
+
+	if err := db.DeleteInvoice(paymentHash); err != nil {
+		t.Fatalf("unable to delete invoice: %v", err)
+	}
+	if _, err := db.LookupInvoice(paymentHash); err != ErrInvoiceNotFound {
+		t.Fatalf("expected ErrInvoiceNotFound, got %v", err)
+	}
+}
+
+func TestAMPInvoiceHTLCSets(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	// HTLCs may only be added to AMP invoices.
+	invoice := makeTestInvoice(1)
+	if err := db.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	htlc := &InvoiceHTLC{
+		Share:      [32]byte{1},
+		Amount:     lnwire.MilliSatoshi(2500000),
+		AcceptTime: time.Unix(2000, 0),
+	}
+	_, err := db.AddInvoiceHTLC(invoice.PaymentHash(), [32]byte{1}, htlc)
+	if err != ErrNotAMPInvoice {
+		t.Fatalf("expected ErrNotAMPInvoice, got %v", err)
+	}
+
+	ampInvoice := makeTestInvoice(2)
+	ampInvoice.AMP = true
+	if err := db.AddInvoice(ampInvoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	paymentHash := ampInvoice.PaymentHash()
+
+	// Add two HTLCs to the first set, and one to the second.
+	for i, setID := range [][32]byte{{1}, {1}, {2}} {
+		htlc := &InvoiceHTLC{
+			Share:      [32]byte{byte(i)},
+			ChildIndex: uint32(i),
+			Amount:     lnwire.MilliSatoshi(2500000),
+			AcceptTime: time.Unix(2000, 0),
+		}
+		if _, err := db.AddInvoiceHTLC(paymentHash, setID, htlc); err != nil {
+			t.Fatalf("unable to add htlc: %v", err)
+		}
+	}
+
+	if err := db.SettleHTLCSet(paymentHash, [32]byte{1}); err != nil {
+		t.Fatalf("unable to settle htlc set: %v", err)
+	}
+	if err := db.SettleHTLCSet(paymentHash, [32]byte{3}); err != ErrHTLCSetNotFound {
+		t.Fatalf("expected ErrHTLCSetNotFound, got %v", err)
+	}
+
+	// Once settled, no further HTLCs may be added to the set.
+	if _, err := db.AddInvoiceHTLC(paymentHash, [32]byte{1}, htlc); err == nil {
+		t.Fatalf("htlc added to settled set")
+	}
+
+	// The invoice itself should remain open, allowing it to be paid
+	// again.
+	invoice, err = db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if invoice.State != InvoiceOpen {
+		t.Fatalf("expected invoice to be open, is %v", invoice.State)
+	}
+	if len(invoice.HTLCSets) != 2 {
+		t.Fatalf("expected 2 htlc sets, got %v", len(invoice.HTLCSets))
+	}
+	settledSet := invoice.HTLCSets[[32]byte{1}]
+	if settledSet.State != InvoiceSettled {
+		t.Fatalf("expected set to be settled, is %v", settledSet.State)
+	}
+	if settledSet.Total() != lnwire.MilliSatoshi(5000000) {
+		t.Fatalf("expected set total of 5000, got %v", settledSet.Total())
+	}
+	if invoice.HTLCSets[[32]byte{2}].State != InvoiceOpen {
+		t.Fatalf("expected second set to be open")
+	}
+}
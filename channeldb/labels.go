@@ -0,0 +1,98 @@
+package channeldb
+
+import (
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// MaxLabelLen is the longest label, or note, which may be attached to a peer
+// or channel.
+const MaxLabelLen = 500
+
+var (
+	// peerLabelBucket houses the label the user attached to each peer,
+	// keyed by its serialized public key.
+	peerLabelBucket = []byte("pl")
+
+	// chanNoteBucket houses the note the user attached to each channel,
+	// keyed by its ID.
+	chanNoteBucket = []byte("cn")
+)
+
+// PutPeerLabel attaches the label to the peer, replacing any label it had.
+// An empty label removes it.
+func (d *DB) PutPeerLabel(pubKey [33]byte, label string) error {
+	return d.putLabel(peerLabelBucket, pubKey[:], label)
+}
+
+// FetchPeerLabels returns the label of each labelled peer, keyed by its
+// serialized public key.
+func (d *DB) FetchPeerLabels() (map[[33]byte]string, error) {
+	labels := make(map[[33]byte]string)
+	err := d.forEachLabel(peerLabelBucket, func(k []byte, label string) {
+		var pubKey [33]byte
+		copy(pubKey[:], k)
+		labels[pubKey] = label
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// PutChannelNote attaches the note to the channel, replacing any note it
+// had. An empty note removes it.
+func (d *DB) PutChannelNote(chanID lnwire.ShortChannelID, note string) error {
+	key := chanIDKey(chanID)
+	return d.putLabel(chanNoteBucket, key[:], note)
+}
+
+// FetchChannelNotes returns the note of each annotated channel.
+func (d *DB) FetchChannelNotes() (map[lnwire.ShortChannelID]string, error) {
+	notes := make(map[lnwire.ShortChannelID]string)
+	err := d.forEachLabel(chanNoteBucket, func(k []byte, note string) {
+		chanID := lnwire.NewShortChanIDFromInt(endian.Uint64(k))
+		notes[chanID] = note
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// putLabel stores the label under the key within the bucket, or removes it
+// if empty.
+func (d *DB) putLabel(bucket, key []byte, label string) error {
+	if len(label) > MaxLabelLen {
+		return ErrLabelTooLong
+	}
+
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		labels, err := tx.RootBucket().CreateBucketIfNotExists(bucket)
+		if err != nil {
+			return err
+		}
+
+		if label == "" {
+			return labels.Delete(key)
+		}
+		return labels.Put(key, []byte(label))
+	})
+}
+
+// forEachLabel calls f with each key, and label, within the bucket.
+func (d *DB) forEachLabel(bucket []byte, f func([]byte, string)) error {
+	return d.namespace.View(func(tx walletdb.Tx) error {
+		labels := tx.RootBucket().Bucket(bucket)
+		if labels == nil {
+			return nil
+		}
+
+		return labels.ForEach(func(k, v []byte) error {
+			f(k, string(v))
+			return nil
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package channeldb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+func TestPeerLabelsAndChannelNotes(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	var pubKey [33]byte
+	pubKey[0] = 0x02
+	if err := db.PutPeerLabel(pubKey, "LSP client"); err != nil {
+		t.Fatalf("unable to put peer label: %v", err)
+	}
+	labels, err := db.FetchPeerLabels()
+	if err != nil {
+		t.Fatalf("unable to fetch peer labels: %v", err)
+	}
+	if labels[pubKey] != "LSP client" {
+		t.Fatalf("unexpected peer label: %q", labels[pubKey])
+	}
+
+	chanID := lnwire.NewShortChanIDFromInt(1234)
+	if err := db.PutChannelNote(chanID, "rebalance target"); err != nil {
+		t.Fatalf("unable to put channel note: %v", err)
+	}
+	notes, err := db.FetchChannelNotes()
+	if err != nil {
+		t.Fatalf("unable to fetch channel notes: %v", err)
+	}
+	if len(notes) != 1 || notes[chanID] != "rebalance target" {
+		t.Fatalf("unexpected channel notes: %v", notes)
+	}
+
+	// An empty note removes it, while one too long is refused.
+	if err := db.PutChannelNote(chanID, ""); err != nil {
+		t.Fatalf("unable to remove channel note: %v", err)
+	}
+	notes, err = db.FetchChannelNotes()
+	if err != nil {
+		t.Fatalf("unable to fetch channel notes: %v", err)
+	}
+	if len(notes) != 0 {
+		t.Fatalf("channel note wasn't removed: %v", notes)
+	}
+
+	err = db.PutPeerLabel(pubKey, strings.Repeat("a", MaxLabelLen+1))
+	if err != ErrLabelTooLong {
+		t.Fatalf("expected ErrLabelTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// MaxMisbehaviorsPerPeer is the number of misbehaviors kept for each peer.
+// Once exceeded, the oldest are dropped.
+const MaxMisbehaviorsPerPeer = 100
+
+var (
+	// misbehaviorBucket houses the misbehaviors of each peer, keyed by
+	// its serialized public key.
+	misbehaviorBucket = []byte("pm")
+)
+
+// MisbehaviorType is the kind of protocol violation a peer committed.
+type MisbehaviorType uint8
+
+const (
+	// MisbehaviorBadSignature is a signature of the peer which failed to
+	// verify.
+	MisbehaviorBadSignature MisbehaviorType = iota
+
+	// MisbehaviorInvalidState is a message which isn't valid in the state
+	// the peer, or our channel with it, is in.
+	MisbehaviorInvalidState
+
+	// MisbehaviorDecodeFailure is a message which failed to decode, or
+	// whose contents were invalid.
+	MisbehaviorDecodeFailure
+)
+
+// String returns a human readable version of the misbehavior type.
+func (m MisbehaviorType) String() string {
+	switch m {
+	case MisbehaviorBadSignature:
+		return "bad_signature"
+	case MisbehaviorInvalidState:
+		return "invalid_state"
+	case MisbehaviorDecodeFailure:
+		return "decode_failure"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(m))
+	}
+}
+
+// Misbehavior is a protocol violation committed by a peer.
+type Misbehavior struct {
+	Type MisbehaviorType
+
+	// Timestamp is the time the misbehavior was detected.
+	Timestamp time.Time
+
+	// Description details the misbehavior.
+	Description string
+}
+
+// AddMisbehavior appends the misbehavior to the log of the peer, dropping
+// the oldest should it exceed MaxMisbehaviorsPerPeer.
+func (d *DB) AddMisbehavior(pubKey [33]byte, m *Misbehavior) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		logs, err := tx.RootBucket().CreateBucketIfNotExists(
+			misbehaviorBucket)
+		if err != nil {
+			return err
+		}
+
+		var misbehaviors []*Misbehavior
+		if logBytes := logs.Get(pubKey[:]); logBytes != nil {
+			misbehaviors, err = decodeMisbehaviors(
+				bytes.NewReader(logBytes))
+			if err != nil {
+				return err
+			}
+		}
+
+		misbehaviors = append(misbehaviors, m)
+		if len(misbehaviors) > MaxMisbehaviorsPerPeer {
+			misbehaviors = misbehaviors[len(misbehaviors)-
+				MaxMisbehaviorsPerPeer:]
+		}
+
+		var b bytes.Buffer
+		if err := encodeMisbehaviors(&b, misbehaviors); err != nil {
+			return err
+		}
+		return logs.Put(pubKey[:], b.Bytes())
+	})
+}
+
+// FetchMisbehaviors returns the misbehaviors of each peer which has
+// misbehaved, oldest first, keyed by its serialized public key.
+func (d *DB) FetchMisbehaviors() (map[[33]byte][]*Misbehavior, error) {
+	misbehaviors := make(map[[33]byte][]*Misbehavior)
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		logs := tx.RootBucket().Bucket(misbehaviorBucket)
+		if logs == nil {
+			return nil
+		}
+
+		return logs.ForEach(func(k, v []byte) error {
+			log, err := decodeMisbehaviors(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			var pubKey [33]byte
+			copy(pubKey[:], k)
+			misbehaviors[pubKey] = log
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return misbehaviors, nil
+}
+
+// Encode...
+func (m *Misbehavior) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, m.Type); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, m.Timestamp.Unix()); err != nil {
+		return err
+	}
+	return writeString(w, m.Description)
+}
+
+// Decode...
+func (m *Misbehavior) Decode(r io.Reader) error {
+	if err := binary.Read(r, endian, &m.Type); err != nil {
+		return err
+	}
+
+	var unixSecs int64
+	if err := binary.Read(r, endian, &unixSecs); err != nil {
+		return err
+	}
+	m.Timestamp = time.Unix(unixSecs, 0)
+
+	var err error
+	m.Description, err = readString(r)
+	return err
+}
+
+// encodeMisbehaviors writes the misbehaviors to w, prefixed by their number.
+func encodeMisbehaviors(w io.Writer, misbehaviors []*Misbehavior) error {
+	err := binary.Write(w, endian, uint16(len(misbehaviors)))
+	if err != nil {
+		return err
+	}
+	for _, m := range misbehaviors {
+		if err := m.Encode(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMisbehaviors reads misbehaviors written by encodeMisbehaviors.
+func decodeMisbehaviors(r io.Reader) ([]*Misbehavior, error) {
+	var numMisbehaviors uint16
+	if err := binary.Read(r, endian, &numMisbehaviors); err != nil {
+		return nil, err
+	}
+
+	misbehaviors := make([]*Misbehavior, numMisbehaviors)
+	for i := range misbehaviors {
+		misbehaviors[i] = &Misbehavior{}
+		if err := misbehaviors[i].Decode(r); err != nil {
+			return nil, err
+		}
+	}
+	return misbehaviors, nil
+}
@@ -0,0 +1,47 @@
+package channeldb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMisbehaviorLog(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	pubKey := [33]byte{0x02, 0x01}
+
+	misbehaviors, err := db.FetchMisbehaviors()
+	if err != nil {
+		t.Fatalf("unable to fetch misbehaviors: %v", err)
+	}
+	if len(misbehaviors) != 0 {
+		t.Fatalf("expected no misbehaviors, got %v", len(misbehaviors))
+	}
+
+	// Adding more than the maximum drops the oldest.
+	var added []*Misbehavior
+	for i := 0; i < MaxMisbehaviorsPerPeer+5; i++ {
+		m := &Misbehavior{
+			Type:        MisbehaviorType(i % 3),
+			Timestamp:   time.Unix(int64(1500000000+i), 0),
+			Description: fmt.Sprintf("misbehavior %v", i),
+		}
+		if err := db.AddMisbehavior(pubKey, m); err != nil {
+			t.Fatalf("unable to add misbehavior: %v", err)
+		}
+		added = append(added, m)
+	}
+
+	misbehaviors, err = db.FetchMisbehaviors()
+	if err != nil {
+		t.Fatalf("unable to fetch misbehaviors: %v", err)
+	}
+	expected := added[len(added)-MaxMisbehaviorsPerPeer:]
+	if !reflect.DeepEqual(misbehaviors[pubKey], expected) {
+		t.Fatalf("misbehaviors don't match: expected %v, got %v",
+			len(expected), len(misbehaviors[pubKey]))
+	}
+}
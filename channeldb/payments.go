@@ -0,0 +1,631 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/batch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// paymentBucket houses all outgoing payments. Each payment is keyed by
+	// its sequence number, so iterating over the bucket returns payments
+	// in the order they were initiated.
+	paymentBucket = []byte("p")
+
+	// paymentIndexBucket maps a payment hash to the sequence number of its
+	// entry within the paymentBucket.
+	paymentIndexBucket = []byte("ph")
+
+	// paymentSeqKey stores the sequence number of the most recently added
+	// payment.
+	paymentSeqKey = []byte("pseq")
+)
+
+// PaymentStatus denotes the state of either an outgoing payment or a single
+// attempt to complete the payment.
+type PaymentStatus uint8
+
+const (
+	// PaymentInFlight indicates we're still awaiting the outcome.
+	PaymentInFlight PaymentStatus = iota
+
+	// PaymentSucceeded indicates the payment or attempt was settled.
+	PaymentSucceeded
+
+	// PaymentFailed indicates the payment or attempt failed, and won't
+	// be retried.
+	PaymentFailed
+)
+
+// String returns a human readable version of the status.
+func (p PaymentStatus) String() string {
+	switch p {
+	case PaymentInFlight:
+		return "in-flight"
+	case PaymentSucceeded:
+		return "succeeded"
+	case PaymentFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// PaymentAttempt records a single HTLC sent out in an attempt to complete a
+// payment, along with the route taken and the outcome.
+type PaymentAttempt struct {
+	// HTLCKey is the key of the HTLC within the first hop's channel.
+	HTLCKey uint64
+
+	// Route is the LN ID of each node the HTLC was routed through,
+	// starting with the first hop.
+	Route [][wire.HashSize]byte
+
+	Amount      lnwire.MilliSatoshi
+	AttemptTime time.Time
+
+	Status PaymentStatus
+
+	// FailureReason is populated if the attempt failed.
+	FailureReason string
+
+	// Fee is the fee paid to the hops of the route, should the attempt
+	// succeed.
+	Fee lnwire.MilliSatoshi
+
+	// ChanID is the channel the HTLC was added to, with the first hop.
+	ChanID lnwire.ShortChannelID
+
+	// Expiry is the absolute block height at which the HTLC times out.
+	Expiry uint32
+
+	// ResolveTime is the time the HTLC was settled or failed, left zero
+	// while it's in flight.
+	ResolveTime time.Time
+}
+
+// Payment is an outgoing payment, along with every attempt made to complete
+// it.
+type Payment struct {
+	// PaymentID is the sequence number of the payment, assigned by the
+	// database when the payment is first added.
+	PaymentID uint64
+
+	PaymentHash  [20]byte
+	Amount       lnwire.MilliSatoshi
+	CreationTime time.Time
+
+	Status PaymentStatus
+
+	Attempts []*PaymentAttempt
+}
+
+// AddPayment stores a new outgoing payment, assigning it the next sequence
+// number.
+func (d *DB) AddPayment(payment *Payment) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		return addPayment(tx.RootBucket(), payment)
+	})
+}
+
+// InitPayment guards against paying the same payment hash twice. If no
+// payment with the hash exists, the payment is added as in-flight. A payment
+// which previously failed may be retried, in which case it's marked as
+// in-flight once again, retaining the attempts made so far. Otherwise either
+// ErrPaymentInFlight, or ErrAlreadyPaid, is returned along with the existing
+// payment.
+func (d *DB) InitPayment(payment *Payment) (*Payment, error) {
+	var existing *Payment
+	err := d.namespace.Update(func(tx walletdb.Tx) error {
+		existing = nil
+
+		rootBucket := tx.RootBucket()
+		payments := rootBucket.Bucket(paymentBucket)
+		index := rootBucket.Bucket(paymentIndexBucket)
+		if payments == nil || index == nil {
+			payment.Status = PaymentInFlight
+			return addPayment(rootBucket, payment)
+		}
+
+		seqKey := index.Get(payment.PaymentHash[:])
+		if seqKey == nil {
+			payment.Status = PaymentInFlight
+			return addPayment(rootBucket, payment)
+		}
+
+		p, err := fetchPayment(payments, seqKey)
+		if err != nil {
+			return err
+		}
+
+		switch p.Status {
+		case PaymentInFlight:
+			existing = p
+			return ErrPaymentInFlight
+		case PaymentSucceeded:
+			existing = p
+			return ErrAlreadyPaid
+		}
+
+		payment.PaymentID = p.PaymentID
+		payment.Status = PaymentInFlight
+		payment.Attempts = p.Attempts
+		return putPayment(payments, payment)
+	})
+
+	return existing, err
+}
+
+// addPayment stores a new outgoing payment within the root bucket, assigning
+// it the next sequence number.
+func addPayment(rootBucket walletdb.Bucket, payment *Payment) error {
+	payments, err := rootBucket.CreateBucketIfNotExists(paymentBucket)
+	if err != nil {
+		return err
+	}
+	index, err := rootBucket.CreateBucketIfNotExists(paymentIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	if index.Get(payment.PaymentHash[:]) != nil {
+		return ErrPaymentExists
+	}
+
+	var seq uint64
+	if seqBytes := rootBucket.Get(paymentSeqKey); seqBytes != nil {
+		seq = endian.Uint64(seqBytes)
+	}
+	seq++
+
+	var seqKey [8]byte
+	endian.PutUint64(seqKey[:], seq)
+	if err := rootBucket.Put(paymentSeqKey, seqKey[:]); err != nil {
+		return err
+	}
+	if err := index.Put(payment.PaymentHash[:], seqKey[:]); err != nil {
+		return err
+	}
+
+	payment.PaymentID = seq
+	return putPayment(payments, payment)
+}
+
+// UpdatePayment overwrites the stored state of a payment which was
+// previously added via AddPayment. As attempts are recorded as their HTLCs
+// are sent, and settled, the write is batched with those of other channel
+// updates.
+func (d *DB) UpdatePayment(payment *Payment) error {
+	return d.chanScheduler.Execute(&batch.Request{
+		Update: func(tx walletdb.Tx) error {
+			rootBucket := tx.RootBucket()
+			payments := rootBucket.Bucket(paymentBucket)
+			if payments == nil {
+				return ErrPaymentNotFound
+			}
+
+			var seqKey [8]byte
+			endian.PutUint64(seqKey[:], payment.PaymentID)
+			if payments.Get(seqKey[:]) == nil {
+				return ErrPaymentNotFound
+			}
+
+			return putPayment(payments, payment)
+		},
+	})
+}
+
+// FetchPayment returns the payment identified by the passed payment hash.
+func (d *DB) FetchPayment(paymentHash [20]byte) (*Payment, error) {
+	var payment *Payment
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		payments := rootBucket.Bucket(paymentBucket)
+		index := rootBucket.Bucket(paymentIndexBucket)
+		if payments == nil || index == nil {
+			return ErrPaymentNotFound
+		}
+
+		seqKey := index.Get(paymentHash[:])
+		if seqKey == nil {
+			return ErrPaymentNotFound
+		}
+
+		p, err := fetchPayment(payments, seqKey)
+		if err != nil {
+			return err
+		}
+		payment = p
+		return nil
+	})
+
+	return payment, err
+}
+
+// FetchPaymentByID returns the payment with the passed sequence number.
+func (d *DB) FetchPaymentByID(paymentID uint64) (*Payment, error) {
+	var payment *Payment
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		payments := tx.RootBucket().Bucket(paymentBucket)
+		if payments == nil {
+			return ErrPaymentNotFound
+		}
+
+		var seqKey [8]byte
+		endian.PutUint64(seqKey[:], paymentID)
+		p, err := fetchPayment(payments, seqKey[:])
+		if err != nil {
+			return err
+		}
+		payment = p
+		return nil
+	})
+
+	return payment, err
+}
+
+// FetchPayments returns up to maxPayments payments, in the order they were
+// added, starting with the first payment after indexOffset. Passing an
+// indexOffset of zero starts from the very first payment. The PaymentID of
+// the last returned payment may be used as the offset for the next page.
+func (d *DB) FetchPayments(indexOffset, maxPayments uint64) ([]*Payment, error) {
+	var payments []*Payment
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		paymentsBucket := tx.RootBucket().Bucket(paymentBucket)
+		if paymentsBucket == nil {
+			return nil
+		}
+
+		var startKey [8]byte
+		endian.PutUint64(startKey[:], indexOffset+1)
+
+		cursor := paymentsBucket.Cursor()
+		for k, v := cursor.Seek(startKey[:]); k != nil; k, v = cursor.Next() {
+			if uint64(len(payments)) >= maxPayments {
+				break
+			}
+
+			payment := &Payment{}
+			if err := payment.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+			payments = append(payments, payment)
+		}
+
+		return nil
+	})
+
+	return payments, err
+}
+
+// DeletePayment removes the payment identified by the passed payment hash.
+// If failedAttemptsOnly is true, then only the failed attempts of the payment
+// are removed, and the payment itself is retained.
+func (d *DB) DeletePayment(paymentHash [20]byte, failedAttemptsOnly bool) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		payments := rootBucket.Bucket(paymentBucket)
+		index := rootBucket.Bucket(paymentIndexBucket)
+		if payments == nil || index == nil {
+			return ErrPaymentNotFound
+		}
+
+		seqKey := index.Get(paymentHash[:])
+		if seqKey == nil {
+			return ErrPaymentNotFound
+		}
+
+		payment, err := fetchPayment(payments, seqKey)
+		if err != nil {
+			return err
+		}
+
+		return deletePayment(payments, index, payment, failedAttemptsOnly)
+	})
+}
+
+// DeleteAllPayments removes all stored payments. If failedOnly is true, then
+// only payments which have failed are removed. If failedAttemptsOnly is true,
+// then rather than removing the payments themselves, only their failed
+// attempts are removed.
+func (d *DB) DeleteAllPayments(failedOnly, failedAttemptsOnly bool) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		payments := rootBucket.Bucket(paymentBucket)
+		index := rootBucket.Bucket(paymentIndexBucket)
+		if payments == nil || index == nil {
+			return nil
+		}
+
+		// Gather the payments to be deleted up front, as the bucket
+		// can't be modified while we're iterating over it.
+		var toDelete []*Payment
+		err := payments.ForEach(func(k, v []byte) error {
+			payment := &Payment{}
+			if err := payment.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+
+			if failedOnly && payment.Status != PaymentFailed {
+				return nil
+			}
+
+			toDelete = append(toDelete, payment)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, payment := range toDelete {
+			err := deletePayment(payments, index, payment,
+				failedAttemptsOnly)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// putPayment writes the serialized payment under its sequence number.
+func putPayment(payments walletdb.Bucket, payment *Payment) error {
+	var b bytes.Buffer
+	if err := payment.Encode(&b); err != nil {
+		return err
+	}
+
+	var seqKey [8]byte
+	endian.PutUint64(seqKey[:], payment.PaymentID)
+	return payments.Put(seqKey[:], b.Bytes())
+}
+
+// fetchPayment reads the payment stored under the passed sequence number.
+func fetchPayment(payments walletdb.Bucket, seqKey []byte) (*Payment, error) {
+	serializedPayment := payments.Get(seqKey)
+	if serializedPayment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	payment := &Payment{}
+	if err := payment.Decode(bytes.NewReader(serializedPayment)); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// deletePayment either removes the payment entirely along with its index
+// entry, or prunes its failed attempts if failedAttemptsOnly is true.
+func deletePayment(payments, index walletdb.Bucket, payment *Payment,
+	failedAttemptsOnly bool) error {
+
+	if failedAttemptsOnly {
+		var attempts []*PaymentAttempt
+		for _, attempt := range payment.Attempts {
+			if attempt.Status != PaymentFailed {
+				attempts = append(attempts, attempt)
+			}
+		}
+		payment.Attempts = attempts
+
+		return putPayment(payments, payment)
+	}
+
+	var seqKey [8]byte
+	endian.PutUint64(seqKey[:], payment.PaymentID)
+	if err := payments.Delete(seqKey[:]); err != nil {
+		return err
+	}
+
+	return index.Delete(payment.PaymentHash[:])
+}
+
+// Encode...
+func (p *Payment) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, p.PaymentID); err != nil {
+		return err
+	}
+	if _, err := w.Write(p.PaymentHash[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, int64(p.Amount)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, p.CreationTime.Unix()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, p.Status); err != nil {
+		return err
+	}
+
+	if len(p.Attempts) > 65535 {
+		return fmt.Errorf("too many payment attempts: %v", len(p.Attempts))
+	}
+	if err := binary.Write(w, endian, uint16(len(p.Attempts))); err != nil {
+		return err
+	}
+	for _, attempt := range p.Attempts {
+		if err := attempt.Encode(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Decode...
+func (p *Payment) Decode(r io.Reader) error {
+	if err := binary.Read(r, endian, &p.PaymentID); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, p.PaymentHash[:]); err != nil {
+		return err
+	}
+
+	var amt int64
+	if err := binary.Read(r, endian, &amt); err != nil {
+		return err
+	}
+	p.Amount = lnwire.MilliSatoshi(amt)
+
+	var unix int64
+	if err := binary.Read(r, endian, &unix); err != nil {
+		return err
+	}
+	p.CreationTime = time.Unix(unix, 0)
+
+	if err := binary.Read(r, endian, &p.Status); err != nil {
+		return err
+	}
+
+	var numAttempts uint16
+	if err := binary.Read(r, endian, &numAttempts); err != nil {
+		return err
+	}
+	for i := uint16(0); i < numAttempts; i++ {
+		attempt := &PaymentAttempt{}
+		if err := attempt.Decode(r); err != nil {
+			return err
+		}
+		p.Attempts = append(p.Attempts, attempt)
+	}
+
+	return nil
+}
+
+// Encode...
+func (a *PaymentAttempt) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, a.HTLCKey); err != nil {
+		return err
+	}
+
+	if len(a.Route) > 255 {
+		return fmt.Errorf("route too long: %v hops", len(a.Route))
+	}
+	if err := binary.Write(w, endian, uint8(len(a.Route))); err != nil {
+		return err
+	}
+	for _, hop := range a.Route {
+		if _, err := w.Write(hop[:]); err != nil {
+			return err
+		}
+	}
+
+	if err := binary.Write(w, endian, int64(a.Amount)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, a.AttemptTime.Unix()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, a.Status); err != nil {
+		return err
+	}
+
+	if len(a.FailureReason) > 65535 {
+		return fmt.Errorf("failure reason too long")
+	}
+	if err := binary.Write(w, endian, uint16(len(a.FailureReason))); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(a.FailureReason)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, endian, int64(a.Fee)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, endian, a.ChanID.ToUint64()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, a.Expiry); err != nil {
+		return err
+	}
+
+	// An unresolved attempt is written with a resolve time of zero.
+	var resolveTime int64
+	if !a.ResolveTime.IsZero() {
+		resolveTime = a.ResolveTime.Unix()
+	}
+	return binary.Write(w, endian, resolveTime)
+}
+
+// Decode...
+func (a *PaymentAttempt) Decode(r io.Reader) error {
+	if err := binary.Read(r, endian, &a.HTLCKey); err != nil {
+		return err
+	}
+
+	var numHops uint8
+	if err := binary.Read(r, endian, &numHops); err != nil {
+		return err
+	}
+	for i := uint8(0); i < numHops; i++ {
+		var hop [wire.HashSize]byte
+		if _, err := io.ReadFull(r, hop[:]); err != nil {
+			return err
+		}
+		a.Route = append(a.Route, hop)
+	}
+
+	var amt int64
+	if err := binary.Read(r, endian, &amt); err != nil {
+		return err
+	}
+	a.Amount = lnwire.MilliSatoshi(amt)
+
+	var unix int64
+	if err := binary.Read(r, endian, &unix); err != nil {
+		return err
+	}
+	a.AttemptTime = time.Unix(unix, 0)
+
+	if err := binary.Read(r, endian, &a.Status); err != nil {
+		return err
+	}
+
+	var reasonLen uint16
+	if err := binary.Read(r, endian, &reasonLen); err != nil {
+		return err
+	}
+	reason := make([]byte, reasonLen)
+	if _, err := io.ReadFull(r, reason); err != nil {
+		return err
+	}
+	a.FailureReason = string(reason)
+
+	var fee int64
+	if err := binary.Read(r, endian, &fee); err != nil {
+		return err
+	}
+	a.Fee = lnwire.MilliSatoshi(fee)
+
+	var chanID uint64
+	if err := binary.Read(r, endian, &chanID); err != nil {
+		return err
+	}
+	a.ChanID = lnwire.NewShortChanIDFromInt(chanID)
+
+	if err := binary.Read(r, endian, &a.Expiry); err != nil {
+		return err
+	}
+
+	var resolveTime int64
+	if err := binary.Read(r, endian, &resolveTime); err != nil {
+		return err
+	}
+	if resolveTime != 0 {
+		a.ResolveTime = time.Unix(resolveTime, 0)
+	}
+
+	return nil
+}
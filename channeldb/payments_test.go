@@ -0,0 +1,257 @@
+package channeldb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// createTestDB creates a new channeldb instance backed by a fresh
+// database, along with a function to clean up the database.
+func createTestDB(t *testing.T) (*DB, func()) {
+	dirName, err := ioutil.TempDir("", "paymentdb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	db, _, err := createDbNamespace(filepath.Join(dirName, "payment.db"))
+	if err != nil {
+		os.RemoveAll(dirName)
+		t.Fatalf("unable to create db: %v", err)
+	}
+	lnNamespace, err := db.Namespace([]byte("ld"))
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dirName)
+		t.Fatalf("unable to create namespace: %v", err)
+	}
+
+	cleanUp := func() {
+		db.Close()
+		os.RemoveAll(dirName)
+	}
+
+	return New(nil, lnNamespace), cleanUp
+}
+
+func makeTestPayment(i byte, status PaymentStatus) *Payment {
+	return &Payment{
+		PaymentHash:  [20]byte{i},
+		Amount:       lnwire.MilliSatoshi(1000 * int64(i)),
+		CreationTime: time.Unix(int64(i)*100, 0),
+		Status:       status,
+		Attempts: []*PaymentAttempt{
+			{
+				HTLCKey:       uint64(i),
+				Route:         [][wire.HashSize]byte{id, key},
+				Amount:        lnwire.MilliSatoshi(1000 * int64(i)),
+				AttemptTime:   time.Unix(int64(i)*100, 0),
+				Status:        PaymentFailed,
+				FailureReason: "no route",
+				ChanID:        lnwire.NewShortChanIDFromInt(uint64(i)),
+				Expiry:        uint32(i) + 144,
+				ResolveTime:   time.Unix(int64(i)*100+1, 0),
+			},
+			{
+				HTLCKey:     uint64(i) + 1,
+				Route:       [][wire.HashSize]byte{id},
+				Amount:      lnwire.MilliSatoshi(1000 * int64(i)),
+				AttemptTime: time.Unix(int64(i)*100+1, 0),
+				Status:      status,
+				Fee:         lnwire.MilliSatoshi(10 * int64(i)),
+				ChanID:      lnwire.NewShortChanIDFromInt(uint64(i)),
+				Expiry:      uint32(i) + 144,
+			},
+		},
+	}
+}
+
+func TestPaymentEncodeDecode(t *testing.T) {
+	payment := makeTestPayment(1, PaymentSucceeded)
+	payment.PaymentID = 5
+
+	var b bytes.Buffer
+	if err := payment.Encode(&b); err != nil {
+		t.Fatalf("unable to encode payment: %v", err)
+	}
+
+	newPayment := &Payment{}
+	if err := newPayment.Decode(&b); err != nil {
+		t.Fatalf("unable to decode payment: %v", err)
+	}
+
+	if !reflect.DeepEqual(payment, newPayment) {
+		t.Fatalf("payment doesn't match: %v vs %v", payment, newPayment)
+	}
+}
+
+func TestPaymentPagination(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	for i := byte(1); i <= 5; i++ {
+		if err := db.AddPayment(makeTestPayment(i, PaymentSucceeded)); err != nil {
+			t.Fatalf("unable to add payment: %v", err)
+		}
+	}
+
+	// A payment hash may only be added once.
+	err := db.AddPayment(makeTestPayment(1, PaymentSucceeded))
+	if err != ErrPaymentExists {
+		t.Fatalf("expected ErrPaymentExists, got %v", err)
+	}
+
+	// Fetch the payments two at a time, ensuring they're returned in the
+	// order they were added.
+	var offset uint64
+	for _, expectedIDs := range [][]uint64{{1, 2}, {3, 4}, {5}, nil} {
+		payments, err := db.FetchPayments(offset, 2)
+		if err != nil {
+			t.Fatalf("unable to fetch payments: %v", err)
+		}
+		if len(payments) != len(expectedIDs) {
+			t.Fatalf("expected %v payments, got %v",
+				len(expectedIDs), len(payments))
+		}
+		for i, payment := range payments {
+			if payment.PaymentID != expectedIDs[i] {
+				t.Fatalf("expected payment %v, got %v",
+					expectedIDs[i], payment.PaymentID)
+			}
+			offset = payment.PaymentID
+		}
+	}
+}
+
+func TestDeletePayments(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	for i := byte(1); i <= 4; i++ {
+		status := PaymentSucceeded
+		if i%2 == 0 {
+			status = PaymentFailed
+		}
+		if err := db.AddPayment(makeTestPayment(i, status)); err != nil {
+			t.Fatalf("unable to add payment: %v", err)
+		}
+	}
+
+	// Deleting only the failed attempts of the first payment should leave
+	// the payment with a single attempt.
+	if err := db.DeletePayment([20]byte{1}, true); err != nil {
+		t.Fatalf("unable to delete payment attempts: %v", err)
+	}
+	payment, err := db.FetchPayment([20]byte{1})
+	if err != nil {
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if len(payment.Attempts) != 1 {
+		t.Fatalf("expected 1 attempt, got %v", len(payment.Attempts))
+	}
+
+	// Next, delete the first payment outright.
+	if err := db.DeletePayment([20]byte{1}, false); err != nil {
+		t.Fatalf("unable to delete payment: %v", err)
+	}
+	if _, err := db.FetchPayment([20]byte{1}); err != ErrPaymentNotFound {
+		t.Fatalf("expected ErrPaymentNotFound, got %v", err)
+	}
+	if err := db.DeletePayment([20]byte{1}, false); err != ErrPaymentNotFound {
+		t.Fatalf("expected ErrPaymentNotFound, got %v", err)
+	}
+
+	// Deleting only the failed payments should leave only the third
+	// payment.
+	if err := db.DeleteAllPayments(true, false); err != nil {
+		t.Fatalf("unable to delete failed payments: %v", err)
+	}
+	payments, err := db.FetchPayments(0, 10)
+	if err != nil {
+		t.Fatalf("unable to fetch payments: %v", err)
+	}
+	if len(payments) != 1 || payments[0].PaymentHash != [20]byte{3} {
+		t.Fatalf("expected only payment 3 to remain, got %v", payments)
+	}
+
+	// Finally, wipe all remaining payments.
+	if err := db.DeleteAllPayments(false, false); err != nil {
+		t.Fatalf("unable to delete payments: %v", err)
+	}
+	payments, err = db.FetchPayments(0, 10)
+	if err != nil {
+		t.Fatalf("unable to fetch payments: %v", err)
+	}
+	if len(payments) != 0 {
+		t.Fatalf("expected no payments, got %v", len(payments))
+	}
+}
+
+func TestInitPayment(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	// Initiating a new payment adds it as in-flight.
+	payment := makeTestPayment(1, PaymentSucceeded)
+	payment.Attempts = nil
+	if _, err := db.InitPayment(payment); err != nil {
+		t.Fatalf("unable to init payment: %v", err)
+	}
+	if payment.Status != PaymentInFlight || payment.PaymentID != 1 {
+		t.Fatalf("expected in-flight payment 1, got %v payment %v",
+			payment.Status, payment.PaymentID)
+	}
+
+	// A second attempt to pay the same hash is refused while the first
+	// is in flight, returning the stored payment.
+	existing, err := db.InitPayment(makeTestPayment(1, PaymentInFlight))
+	if err != ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, got %v", err)
+	}
+	if existing.PaymentID != payment.PaymentID {
+		t.Fatalf("expected payment %v, got %v", payment.PaymentID,
+			existing.PaymentID)
+	}
+
+	// Once failed, the payment may be retried, keeping its sequence
+	// number and the attempts made so far.
+	payment.Status = PaymentFailed
+	payment.Attempts = makeTestPayment(1, PaymentFailed).Attempts
+	if err := db.UpdatePayment(payment); err != nil {
+		t.Fatalf("unable to update payment: %v", err)
+	}
+	retry := makeTestPayment(1, PaymentInFlight)
+	retry.Attempts = nil
+	if _, err := db.InitPayment(retry); err != nil {
+		t.Fatalf("unable to retry payment: %v", err)
+	}
+	if retry.PaymentID != payment.PaymentID || len(retry.Attempts) != 2 {
+		t.Fatalf("expected payment %v with 2 attempts, got payment "+
+			"%v with %v", payment.PaymentID, retry.PaymentID,
+			len(retry.Attempts))
+	}
+
+	// Once it succeeds, it may not be paid again.
+	retry.Status = PaymentSucceeded
+	if err := db.UpdatePayment(retry); err != nil {
+		t.Fatalf("unable to update payment: %v", err)
+	}
+	if _, err := db.InitPayment(retry); err != ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, got %v", err)
+	}
+
+	stored, err := db.FetchPaymentByID(retry.PaymentID)
+	if err != nil {
+		t.Fatalf("unable to fetch payment: %v", err)
+	}
+	if stored.Status != PaymentSucceeded {
+		t.Fatalf("expected succeeded payment, got %v", stored.Status)
+	}
+}
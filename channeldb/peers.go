@@ -0,0 +1,132 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// flapCountBucket houses the flap count of each peer we maintain a
+	// persistent connection to, keyed by its serialized public key.
+	flapCountBucket = []byte("pf")
+
+	// peerStorageBucket houses the blob each peer asked us to store for
+	// it, keyed by its serialized public key.
+	peerStorageBucket = []byte("ps")
+)
+
+// FlapCount records how often a connection to a peer has dropped soon after
+// being established.
+type FlapCount struct {
+	// Count is the number of flaps, less those forgiven since.
+	Count uint32
+
+	// LastFlap is the time of the latest flap.
+	LastFlap time.Time
+}
+
+// PutFlapCount stores the flap count of the peer, overwriting any existing
+// count.
+func (d *DB) PutFlapCount(pubKey [33]byte, f *FlapCount) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		counts, err := tx.RootBucket().CreateBucketIfNotExists(
+			flapCountBucket)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := f.Encode(&b); err != nil {
+			return err
+		}
+		return counts.Put(pubKey[:], b.Bytes())
+	})
+}
+
+// FetchFlapCount returns the flap count of the peer, or nil if none is
+// stored.
+func (d *DB) FetchFlapCount(pubKey [33]byte) (*FlapCount, error) {
+	var f *FlapCount
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		counts := tx.RootBucket().Bucket(flapCountBucket)
+		if counts == nil {
+			return nil
+		}
+
+		countBytes := counts.Get(pubKey[:])
+		if countBytes == nil {
+			return nil
+		}
+
+		f = &FlapCount{}
+		return f.Decode(bytes.NewReader(countBytes))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// PutPeerStorage stores the blob the peer asked us to store for it,
+// replacing any blob it asked us to store before.
+func (d *DB) PutPeerStorage(pubKey [33]byte, blob []byte) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		blobs, err := tx.RootBucket().CreateBucketIfNotExists(
+			peerStorageBucket)
+		if err != nil {
+			return err
+		}
+
+		return blobs.Put(pubKey[:], blob)
+	})
+}
+
+// FetchPeerStorage returns the blob the peer asked us to store for it, or
+// nil if it never has.
+func (d *DB) FetchPeerStorage(pubKey [33]byte) ([]byte, error) {
+	var blob []byte
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		blobs := tx.RootBucket().Bucket(peerStorageBucket)
+		if blobs == nil {
+			return nil
+		}
+
+		if b := blobs.Get(pubKey[:]); b != nil {
+			blob = append([]byte(nil), b...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// Encode...
+func (f *FlapCount) Encode(w io.Writer) error {
+	if err := binary.Write(w, endian, f.Count); err != nil {
+		return err
+	}
+	return binary.Write(w, endian, f.LastFlap.Unix())
+}
+
+// Decode...
+func (f *FlapCount) Decode(r io.Reader) error {
+	if err := binary.Read(r, endian, &f.Count); err != nil {
+		return err
+	}
+
+	var unixSecs int64
+	if err := binary.Read(r, endian, &unixSecs); err != nil {
+		return err
+	}
+	f.LastFlap = time.Unix(unixSecs, 0)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFlapCountEncodeDecode(t *testing.T) {
+	f := &FlapCount{
+		Count:    7,
+		LastFlap: time.Unix(time.Now().Unix(), 0),
+	}
+
+	var b bytes.Buffer
+	if err := f.Encode(&b); err != nil {
+		t.Fatalf("unable to encode flap count: %v", err)
+	}
+
+	newF := &FlapCount{}
+	if err := newF.Decode(&b); err != nil {
+		t.Fatalf("unable to decode flap count: %v", err)
+	}
+
+	if !reflect.DeepEqual(f, newF) {
+		t.Fatalf("flap count doesn't match: %v vs %v", f, newF)
+	}
+}
+
+func TestPeerStorage(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	pubKey := [33]byte{0x02, 0x01}
+
+	blob, err := db.FetchPeerStorage(pubKey)
+	if err != nil {
+		t.Fatalf("unable to fetch peer storage: %v", err)
+	}
+	if blob != nil {
+		t.Fatalf("expected no blob, got %x", blob)
+	}
+
+	// Each blob the peer stores replaces the one before it.
+	for _, b := range [][]byte{{1, 2, 3}, {4, 5}} {
+		if err := db.PutPeerStorage(pubKey, b); err != nil {
+			t.Fatalf("unable to put peer storage: %v", err)
+		}
+
+		blob, err := db.FetchPeerStorage(pubKey)
+		if err != nil {
+			t.Fatalf("unable to fetch peer storage: %v", err)
+		}
+		if !bytes.Equal(blob, b) {
+			t.Fatalf("expected blob %x, got %x", b, blob)
+		}
+	}
+}
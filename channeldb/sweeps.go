@@ -0,0 +1,126 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// completedSweepBucket houses each of our sweep transactions which has
+	// confirmed, keyed by their txid.
+	completedSweepBucket = []byte("sw")
+)
+
+// CompletedSweep is a transaction sweeping one of our outputs back to the
+// wallet which has confirmed.
+type CompletedSweep struct {
+	Tx *wire.MsgTx
+
+	// Fee is the fee paid by the sweep transaction.
+	Fee btcutil.Amount
+
+	// ConfirmedHeight is the height of the block the sweep confirmed in.
+	ConfirmedHeight uint32
+
+	// Attempts is the number of sweep transactions published for the
+	// swept output, each paying a higher fee than the last, before one
+	// confirmed.
+	Attempts uint32
+}
+
+// PutCompletedSweep adds the sweep to the database, overwriting any existing
+// entry for it.
+func (d *DB) PutCompletedSweep(s *CompletedSweep) error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		sweeps, err := tx.RootBucket().CreateBucketIfNotExists(
+			completedSweepBucket)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := s.Encode(&b); err != nil {
+			return err
+		}
+		txid := s.Tx.TxSha()
+		return sweeps.Put(txid[:], b.Bytes())
+	})
+}
+
+// FetchCompletedSweeps returns each sweep which has confirmed, in the order
+// they confirmed.
+func (d *DB) FetchCompletedSweeps() ([]*CompletedSweep, error) {
+	var completed []*CompletedSweep
+	err := d.namespace.View(func(tx walletdb.Tx) error {
+		sweeps := tx.RootBucket().Bucket(completedSweepBucket)
+		if sweeps == nil {
+			return nil
+		}
+
+		return sweeps.ForEach(func(k, v []byte) error {
+			s := &CompletedSweep{}
+			if err := s.Decode(bytes.NewReader(v)); err != nil {
+				return err
+			}
+			completed = append(completed, s)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Sort(sweepsByHeight(completed))
+	return completed, nil
+}
+
+// sweepsByHeight implements sort.Interface, sorting sweeps in ascending
+// order of the height they confirmed at.
+type sweepsByHeight []*CompletedSweep
+
+func (s sweepsByHeight) Len() int { return len(s) }
+func (s sweepsByHeight) Less(i, j int) bool {
+	return s[i].ConfirmedHeight < s[j].ConfirmedHeight
+}
+func (s sweepsByHeight) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// Encode...
+func (s *CompletedSweep) Encode(w io.Writer) error {
+	if err := s.Tx.Serialize(w); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, int64(s.Fee)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, endian, s.ConfirmedHeight); err != nil {
+		return err
+	}
+
+	return binary.Write(w, endian, s.Attempts)
+}
+
+// Decode...
+func (s *CompletedSweep) Decode(r io.Reader) error {
+	s.Tx = wire.NewMsgTx()
+	if err := s.Tx.Deserialize(r); err != nil {
+		return err
+	}
+
+	var fee int64
+	if err := binary.Read(r, endian, &fee); err != nil {
+		return err
+	}
+	s.Fee = btcutil.Amount(fee)
+
+	if err := binary.Read(r, endian, &s.ConfirmedHeight); err != nil {
+		return err
+	}
+
+	return binary.Read(r, endian, &s.Attempts)
+}
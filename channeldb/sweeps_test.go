@@ -0,0 +1,40 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestCompletedSweepEncodeDecode(t *testing.T) {
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, []byte{0x51}))
+	tx.AddTxOut(wire.NewTxOut(90000, []byte{0x76, 0xa9}))
+
+	s := &CompletedSweep{
+		Tx:              tx,
+		Fee:             10000,
+		ConfirmedHeight: 440000,
+		Attempts:        3,
+	}
+
+	var b bytes.Buffer
+	if err := s.Encode(&b); err != nil {
+		t.Fatalf("unable to encode sweep: %v", err)
+	}
+
+	newS := &CompletedSweep{}
+	if err := newS.Decode(&b); err != nil {
+		t.Fatalf("unable to decode sweep: %v", err)
+	}
+
+	if newS.Tx.TxSha() != tx.TxSha() {
+		t.Fatalf("transaction doesn't match")
+	}
+	newS.Tx = tx
+	if !reflect.DeepEqual(s, newS) {
+		t.Fatalf("sweep doesn't match: %v vs %v", s, newS)
+	}
+}
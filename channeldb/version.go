@@ -0,0 +1,123 @@
+package channeldb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+var (
+	// metaBucket houses the version of the database.
+	metaBucket = []byte("meta")
+
+	// dbVersionKey is the key of the version of the database.
+	dbVersionKey = []byte("dbv")
+)
+
+// ErrDBTooOld is returned when the database holds open channels written in a
+// format which predates versioning, and can't be migrated from. The channels
+// may be removed without touching the wallet, whose namespace the database
+// shares, by restarting with -wipechannels.
+var ErrDBTooOld = errors.New("channel database holds open channels " +
+	"predating millisatoshi amounts, which can't be migrated; close " +
+	"them with the version which wrote them, then restart with " +
+	"-wipechannels to remove them, keeping the wallet")
+
+// migration upgrades the contents of the database from the format of the
+// previous version to that of its own.
+type migration func(tx walletdb.Tx) error
+
+// version is a format of the database, along with the migration into it.
+type version struct {
+	number    uint32
+	migration migration
+}
+
+// dbVersions holds each format of the database, in order. Each new format
+// is appended along with the migration into it, so that databases written by
+// any earlier version are brought up to date as they're opened.
+var dbVersions = []version{
+	{
+		// The first version accounts for every off-chain amount in
+		// millisatoshis, whereas unversioned databases stored them in
+		// satoshis. Along with it, OpenChannel gained the zero-conf
+		// flag and alias short channel ID, the commitment type, the
+		// HTLC limits of each side, the minimum HTLC, the private
+		// flag, and whether we initiated the channel. Unversioned
+		// databases only ever held channels, along with our identity
+		// key, so those without open channels are already in this
+		// format, while those with are refused rather than migrated.
+		number:    1,
+		migration: nil,
+	},
+}
+
+// currentDBVersion is the version of the format this code writes.
+var currentDBVersion = dbVersions[len(dbVersions)-1].number
+
+// SyncVersion brings the database up to the current version, applying the
+// migration of each version since the one it was written with. A new
+// database, or one written before the database was versioned without open
+// channels, is stamped with the current version, while an unversioned one
+// with open channels is refused with ErrDBTooOld. A database written by a
+// newer version of lnd is refused too, rather than read in a format we don't
+// know.
+func (d *DB) SyncVersion() error {
+	return d.namespace.Update(func(tx walletdb.Tx) error {
+		meta, err := tx.RootBucket().CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		var dbVersion uint32
+		switch v := meta.Get(dbVersionKey); {
+		case v != nil:
+			if len(v) != 4 {
+				return fmt.Errorf("invalid database version %x", v)
+			}
+			dbVersion = endian.Uint32(v)
+
+		case hasOpenChannels(tx):
+			return ErrDBTooOld
+		}
+
+		if dbVersion > currentDBVersion {
+			return fmt.Errorf("channel database is of version %v, "+
+				"newer than the latest known version %v",
+				dbVersion, currentDBVersion)
+		}
+
+		// A new, or unversioned, database is already in the current
+		// format, so has nothing to migrate.
+		if dbVersion != 0 {
+			for _, v := range dbVersions {
+				if v.number <= dbVersion || v.migration == nil {
+					continue
+				}
+
+				if err := v.migration(tx); err != nil {
+					return err
+				}
+			}
+		}
+
+		var b [4]byte
+		endian.PutUint32(b[:], currentDBVersion)
+		return meta.Put(dbVersionKey, b[:])
+	})
+}
+
+// hasOpenChannels returns true if the database holds the state of any open
+// channel.
+func hasOpenChannels(tx walletdb.Tx) bool {
+	openChanBucket := tx.RootBucket().Bucket(openChannelBucket)
+	if openChanBucket == nil {
+		return false
+	}
+
+	errNotEmpty := errors.New("bucket not empty")
+	return openChanBucket.ForEach(func(_, _ []byte) error {
+		return errNotEmpty
+	}) == errNotEmpty
+}
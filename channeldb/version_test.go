@@ -0,0 +1,206 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// putDBVersion stores the version of the database directly.
+func putDBVersion(db *DB, dbVersion uint32) error {
+	return db.namespace.Update(func(tx walletdb.Tx) error {
+		meta, err := tx.RootBucket().CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		var b [4]byte
+		endian.PutUint32(b[:], dbVersion)
+		return meta.Put(dbVersionKey, b[:])
+	})
+}
+
+// putUnversionedChannel stores the state of an open channel as an
+// unversioned database would have.
+func putUnversionedChannel(db *DB) error {
+	return db.namespace.Update(func(tx walletdb.Tx) error {
+		rootBucket := tx.RootBucket()
+		if err := rootBucket.Put(identityKey, []byte{1}); err != nil {
+			return err
+		}
+
+		openChanBucket, err := rootBucket.CreateBucketIfNotExists(
+			openChannelBucket)
+		if err != nil {
+			return err
+		}
+		return openChanBucket.Put([]byte{2}, []byte{3})
+	})
+}
+
+// fetchDBVersion returns the version stored within the database.
+func fetchDBVersion(t *testing.T, db *DB) uint32 {
+	var dbVersion uint32
+	err := db.namespace.View(func(tx walletdb.Tx) error {
+		meta := tx.RootBucket().Bucket(metaBucket)
+		if meta == nil {
+			return nil
+		}
+		if v := meta.Get(dbVersionKey); v != nil {
+			dbVersion = endian.Uint32(v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to fetch db version: %v", err)
+	}
+	return dbVersion
+}
+
+// TestSyncVersion asserts new databases, and unversioned ones without open
+// channels, are stamped with the current version, while unversioned
+// databases with open channels, and those of newer versions, are refused.
+func TestSyncVersion(t *testing.T) {
+	tests := []struct {
+		name string
+
+		// setup populates the database before its version is synced.
+		setup func(*DB) error
+
+		err bool
+
+		// tooOld is set if the database is expected to be refused as
+		// unversioned.
+		tooOld bool
+	}{
+		{
+			name:  "new",
+			setup: func(*DB) error { return nil },
+		},
+		{
+			name: "new, encrypted",
+			setup: func(db *DB) error {
+				_, err := OpenEncryptedNamespace(db.namespace,
+					mockCryptor{}, true)
+				return err
+			},
+		},
+		{
+			name: "current version",
+			setup: func(db *DB) error {
+				if err := db.PutPeerLabel([33]byte{}, "label"); err != nil {
+					return err
+				}
+				return putDBVersion(db, currentDBVersion)
+			},
+		},
+		{
+			name: "unversioned, identity key only",
+			setup: func(db *DB) error {
+				return db.namespace.Update(func(tx walletdb.Tx) error {
+					return tx.RootBucket().Put(identityKey,
+						[]byte{1})
+				})
+			},
+		},
+		{
+			name: "unversioned, without open channels",
+			setup: func(db *DB) error {
+				return db.PutPeerLabel([33]byte{}, "label")
+			},
+		},
+		{
+			name:   "unversioned, with open channels",
+			setup:  putUnversionedChannel,
+			err:    true,
+			tooOld: true,
+		},
+		{
+			name: "unversioned, open channels wiped",
+			setup: func(db *DB) error {
+				if err := putUnversionedChannel(db); err != nil {
+					return err
+				}
+				return db.Wipe()
+			},
+		},
+		{
+			name: "newer version",
+			setup: func(db *DB) error {
+				return putDBVersion(db, currentDBVersion+1)
+			},
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		db, cleanUp := createTestDB(t)
+
+		if err := test.setup(db); err != nil {
+			cleanUp()
+			t.Fatalf("%v: unable to setup db: %v", test.name, err)
+		}
+
+		err := db.SyncVersion()
+		switch {
+		case test.tooOld && err != ErrDBTooOld:
+			t.Fatalf("%v: expected ErrDBTooOld, instead %v",
+				test.name, err)
+		case test.err && err == nil:
+			t.Fatalf("%v: expected db to be refused", test.name)
+		case !test.err && err != nil:
+			t.Fatalf("%v: unable to sync version: %v", test.name,
+				err)
+		case !test.err:
+			dbVersion := fetchDBVersion(t, db)
+			if dbVersion != currentDBVersion {
+				t.Fatalf("%v: expected version %v, instead %v",
+					test.name, currentDBVersion, dbVersion)
+			}
+		}
+
+		cleanUp()
+	}
+}
+
+// TestSyncVersionMigration asserts the migration of each version newer than
+// that of the database is applied, in order, exactly once.
+func TestSyncVersionMigration(t *testing.T) {
+	db, cleanUp := createTestDB(t)
+	defer cleanUp()
+
+	if err := putDBVersion(db, 1); err != nil {
+		t.Fatalf("unable to put db version: %v", err)
+	}
+
+	var applied []uint32
+	migrateTo := func(number uint32) migration {
+		return func(walletdb.Tx) error {
+			applied = append(applied, number)
+			return nil
+		}
+	}
+
+	oldVersions, oldCurrent := dbVersions, currentDBVersion
+	defer func() {
+		dbVersions, currentDBVersion = oldVersions, oldCurrent
+	}()
+	dbVersions = append(dbVersions[:1:1],
+		version{number: 2, migration: migrateTo(2)},
+		version{number: 3, migration: migrateTo(3)},
+	)
+	currentDBVersion = 3
+
+	for i := 0; i < 2; i++ {
+		if err := db.SyncVersion(); err != nil {
+			t.Fatalf("unable to sync version: %v", err)
+		}
+	}
+
+	if len(applied) != 2 || applied[0] != 2 || applied[1] != 3 {
+		t.Fatalf("expected migrations 2 and 3, instead %v", applied)
+	}
+	if dbVersion := fetchDBVersion(t, db); dbVersion != 3 {
+		t.Fatalf("expected version 3, instead %v", dbVersion)
+	}
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chanfitness"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// defaultChanDisableTimeout is how long a peer we've a channel with
+	// may remain offline before the channel is disabled.
+	defaultChanDisableTimeout = 20 * time.Minute
+
+	// defaultChanEnableTimeout is how long a peer must remain online
+	// before its channels are re-enabled, so flapping peers aren't
+	// announced as enabled each time they briefly reconnect.
+	defaultChanEnableTimeout = 19 * time.Minute
+
+	// chanStatusSampleInterval is how often the peers of our channels
+	// are checked.
+	chanStatusSampleInterval = time.Minute
+
+	// The forwarding policy announced for our channels, until we send a
+	// channel update of our own with a different one.
+	defaultTimeLockDelta = 144
+	defaultBaseFeeMsat   = 1000
+	defaultFeeRate       = 1
+)
+
+// chanStatusAction is a request to change the status of a channel.
+type chanStatusAction uint8
+
+const (
+	// chanStatusEnable enables a channel disabled by the user.
+	chanStatusEnable chanStatusAction = iota
+
+	// chanStatusDisable disables the channel until the user re-enables
+	// it, whether or not the peer is online.
+	chanStatusDisable
+
+	// chanStatusAuto hands the channel back to the automatic disabler,
+	// which enables it while the peer is online.
+	chanStatusAuto
+)
+
+// ErrEnableInactiveChan is returned when enabling a channel whose peer is
+// offline, as it would only be disabled again.
+var ErrEnableInactiveChan = fmt.Errorf("unable to enable channel of " +
+	"offline peer")
+
+// chanStatusManager announces whether each of our channels is enabled in
+// the channel updates we send. Channels may be disabled by the user, or are
+// disabled automatically once their peer has been offline for longer than
+// the disable timeout, being re-enabled once the peer has remained online
+// for the enable timeout.
+//
+// TODO: also refuse to forward HTLCs over disabled channels once
+// we forward HTLCs
+type chanStatusManager struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	nodeKey  keychain.NodeSigner
+	graph    *channeldb.DB
+	gossiper *discovery.Gossiper
+
+	// events tracks whether the peer of each channel is online.
+	events *chanfitness.ChannelEventStore
+
+	// disableTimeout is how long a peer may remain offline before its
+	// channels are disabled. If zero, channels are never automatically
+	// disabled.
+	disableTimeout time.Duration
+
+	// enableTimeout is how long a peer must remain online before its
+	// channels are re-enabled.
+	enableTimeout time.Duration
+
+	// The mutex guards the channels disabled by the user.
+	sync.Mutex
+	manuallyDisabled map[lnwire.ShortChannelID]struct{}
+
+	// updateMtx serializes the channel updates we send, so each replaces
+	// the one before.
+	updateMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChanStatusManager creates a new chanStatusManager, announcing the
+// status of the channels of the passed node.
+func newChanStatusManager(nodeKey keychain.NodeSigner, graph *channeldb.DB,
+	gossiper *discovery.Gossiper, events *chanfitness.ChannelEventStore,
+	disableTimeout, enableTimeout time.Duration) *chanStatusManager {
+
+	return &chanStatusManager{
+		nodeKey:          nodeKey,
+		graph:            graph,
+		gossiper:         gossiper,
+		events:           events,
+		disableTimeout:   disableTimeout,
+		enableTimeout:    enableTimeout,
+		manuallyDisabled: make(map[lnwire.ShortChannelID]struct{}),
+		quit:             make(chan struct{}),
+	}
+}
+
+// Start loads the channels disabled by the user, and launches the goroutine
+// disabling the channels of offline peers.
+func (c *chanStatusManager) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	chanIDs, err := c.graph.FetchDisabledChans()
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	for _, chanID := range chanIDs {
+		c.manuallyDisabled[chanID] = struct{}{}
+	}
+	c.Unlock()
+
+	if c.disableTimeout == 0 {
+		return nil
+	}
+
+	c.wg.Add(1)
+	go c.statusSampler()
+
+	return nil
+}
+
+// Stop signals the chanStatusManager to exit, and waits for it to do so.
+func (c *chanStatusManager) Stop() {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// UpdateChanStatus carries out the requested change of the channel's
+// status, sending a channel update if its announced status changes.
+func (c *chanStatusManager) UpdateChanStatus(chanID lnwire.ShortChannelID,
+	action chanStatusAction) error {
+
+	edge, err := c.graph.FetchChannelEdgeInfo(chanID)
+	if err != nil {
+		return err
+	}
+	if edge == nil {
+		return discovery.ErrUnknownChannel
+	}
+	peerKey, err := c.peerKey(edge)
+	if err != nil {
+		return err
+	}
+
+	online := c.events.GetPeerStatus(peerKey).Online
+
+	switch action {
+	case chanStatusEnable:
+		if !online {
+			return ErrEnableInactiveChan
+		}
+		if err := c.setManuallyDisabled(chanID, false); err != nil {
+			return err
+		}
+		return c.setDisabled(chanID, false)
+
+	case chanStatusDisable:
+		if err := c.setManuallyDisabled(chanID, true); err != nil {
+			return err
+		}
+		return c.setDisabled(chanID, true)
+
+	case chanStatusAuto:
+		// The automatic disabler enables, or disables, the channel in
+		// turn once the peer has been online, or offline, for long
+		// enough.
+		return c.setManuallyDisabled(chanID, false)
+
+	default:
+		return fmt.Errorf("unknown channel status action: %v", action)
+	}
+}
+
+// setManuallyDisabled records whether the user has disabled the channel.
+func (c *chanStatusManager) setManuallyDisabled(chanID lnwire.ShortChannelID,
+	disabled bool) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	if err := c.graph.SetChanDisabled(chanID, disabled); err != nil {
+		return err
+	}
+	if disabled {
+		c.manuallyDisabled[chanID] = struct{}{}
+	} else {
+		delete(c.manuallyDisabled, chanID)
+	}
+	return nil
+}
+
+// statusSampler periodically disables the channels of each peer offline for
+// longer than the disable timeout, and enables those of each peer online for
+// longer than the enable timeout, other than channels disabled by the user.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chanStatusManager) statusSampler() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(chanStatusSampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.sampleChanStatuses(); err != nil {
+				fmt.Printf("unable to update channel "+
+					"statuses: %v\n", err)
+			}
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// sampleChanStatuses announces each of our channels as enabled or disabled
+// according to how long its peer has been online, or offline, for.
+func (c *chanStatusManager) sampleChanStatuses() error {
+	edges, err := c.graph.FetchNodeChannelEdges(c.nodeKey.PubKey())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, edge := range edges {
+		peerKey, err := c.peerKey(edge)
+		if err != nil {
+			return err
+		}
+		status := c.events.GetPeerStatus(peerKey)
+
+		c.Lock()
+		chanID := edge.Announcement.ShortChannelID
+		_, manual := c.manuallyDisabled[chanID]
+		c.Unlock()
+
+		var disabled bool
+		switch {
+		case manual:
+			continue
+		case status.Online && now.Sub(status.Since) >= c.enableTimeout:
+			disabled = false
+		case !status.Online && now.Sub(status.Since) >= c.disableTimeout:
+			disabled = true
+		default:
+			continue
+		}
+
+		if err := c.setDisabled(chanID, disabled); err != nil {
+			fmt.Printf("unable to update status of channel %v: "+
+				"%v\n", chanID, err)
+		}
+	}
+
+	return nil
+}
+
+// setDisabled sends a channel update announcing the channel as disabled, or
+// enabled, unless our latest update already does. The new update otherwise
+// keeps the forwarding policy of our latest.
+func (c *chanStatusManager) setDisabled(chanID lnwire.ShortChannelID,
+	disabled bool) error {
+
+	c.updateMtx.Lock()
+	defer c.updateMtx.Unlock()
+
+	edge, err := c.graph.FetchChannelEdgeInfo(chanID)
+	if err != nil {
+		return err
+	}
+	if edge == nil {
+		return discovery.ErrUnknownChannel
+	}
+
+	ann := edge.Announcement
+	ourKey := c.nodeKey.PubKey()
+
+	var (
+		direction uint16
+		latest    *lnwire.ChannelUpdate
+	)
+	switch {
+	case ann.NodeID1.IsEqual(ourKey):
+		latest = edge.Policy1
+	case ann.NodeID2.IsEqual(ourKey):
+		direction = lnwire.ChanUpdateDirection
+		latest = edge.Policy2
+	default:
+		return fmt.Errorf("channel %v isn't ours", ann.ShortChannelID)
+	}
+
+	update := &lnwire.ChannelUpdate{
+		ShortChannelID: ann.ShortChannelID,
+		TimeLockDelta:  defaultTimeLockDelta,
+		BaseFee:        defaultBaseFeeMsat,
+		FeeRate:        defaultFeeRate,
+	}
+	timestamp := uint32(time.Now().Unix())
+	if latest != nil {
+		wasDisabled := latest.Flags&lnwire.ChanUpdateDisabled != 0
+		if wasDisabled == disabled {
+			return nil
+		}
+
+		update.TimeLockDelta = latest.TimeLockDelta
+		update.HtlcMinimumMsat = latest.HtlcMinimumMsat
+		update.BaseFee = latest.BaseFee
+		update.FeeRate = latest.FeeRate
+
+		// Our new update must replace the latest one, even if our
+		// clock has moved backwards.
+		if timestamp <= latest.Timestamp {
+			timestamp = latest.Timestamp + 1
+		}
+	}
+	update.Timestamp = timestamp
+	update.Flags = direction
+	if disabled {
+		update.Flags |= lnwire.ChanUpdateDisabled
+	}
+
+	data, err := update.DataToSign()
+	if err != nil {
+		return err
+	}
+	update.Signature, err = c.nodeKey.SignHash(wire.DoubleSha256(data))
+	if err != nil {
+		return err
+	}
+
+	return c.gossiper.ProcessLocalUpdate(update)
+}
+
+// peerKey returns the public key of the peer of our channel.
+func (c *chanStatusManager) peerKey(edge *channeldb.ChannelEdge) (*btcec.PublicKey, error) {
+	ann := edge.Announcement
+	ourKey := c.nodeKey.PubKey()
+
+	switch {
+	case ann.NodeID1.IsEqual(ourKey):
+		return ann.NodeID2, nil
+	case ann.NodeID2.IsEqual(ourKey):
+		return ann.NodeID1, nil
+	default:
+		return nil, fmt.Errorf("channel %v isn't ours", ann.ShortChannelID)
+	}
+}
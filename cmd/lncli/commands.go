@@ -2,9 +2,17 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/codegangsta/cli"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"golang.org/x/net/context"
@@ -49,6 +57,94 @@ func newAddress(ctx *cli.Context) {
 	printRespJSON(addr)
 }
 
+// GetRecoveryInfoCommand ...
+var GetRecoveryInfoCommand = cli.Command{
+	Name: "getrecoveryinfo",
+	Usage: "display the progress of the rescan recovering the wallet's " +
+		"funds after being restored from its seed",
+	Action: getRecoveryInfo,
+}
+
+func getRecoveryInfo(ctx *cli.Context) {
+	client := getClient(ctx)
+
+	ctxb := context.Background()
+	resp, err := client.GetRecoveryInfo(ctxb, &lnrpc.GetRecoveryInfoRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// WalletBalanceCommand ...
+var WalletBalanceCommand = cli.Command{
+	Name:  "walletbalance",
+	Usage: "display the total value of the wallet's confirmed outputs",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "min_confs",
+			Value: 1,
+			Usage: "the number of confirmations an output needs to be counted",
+		},
+	},
+	Action: walletBalance,
+}
+
+func walletBalance(ctx *cli.Context) {
+	client := getClient(ctx)
+
+	ctxb := context.Background()
+	resp, err := client.WalletBalance(ctxb, &lnrpc.WalletBalanceRequest{
+		MinConfs: int32(ctx.Int("min_confs")),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ChannelBalanceCommand ...
+var ChannelBalanceCommand = cli.Command{
+	Name: "channelbalance",
+	Usage: "display our balance, and that of our peers, across our " +
+		"channels, including the htlcs in flight, and channels yet to " +
+		"open, broken down by commitment type",
+	Action: channelBalance,
+}
+
+func channelBalance(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.ChannelBalance(ctxb, &lnrpc.ChannelBalanceRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// GetInfoCommand ...
+var GetInfoCommand = cli.Command{
+	Name:   "getinfo",
+	Usage:  "display our identity public key, and number of peers",
+	Action: getInfo,
+}
+
+func getInfo(ctx *cli.Context) {
+	client := getClient(ctx)
+
+	ctxb := context.Background()
+	resp, err := client.GetInfo(ctxb, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
 // SendManyCommand ...
 var SendManyCommand = cli.Command{
 	Name: "sendmany",
@@ -76,10 +172,112 @@ func sendMany(ctx *cli.Context) {
 	printRespJSON(txid)
 }
 
+// EstimateFeeCommand ...
+var EstimateFeeCommand = cli.Command{
+	Name: "estimatefee",
+	Usage: "estimate the fee of a transaction paying the specified " +
+		"amount(s) to the passed address(es), without broadcasting it",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "conf_target",
+			Usage: "the number of blocks the transaction should confirm within",
+		},
+		cli.IntFlag{
+			Name:  "min_confs",
+			Usage: "the number of confirmations a coin needs to be spent",
+		},
+	},
+	Action: estimateFee,
+}
+
+func estimateFee(ctx *cli.Context) {
+	var amountToAddr map[string]int64
+
+	jsonMap := ctx.Args().Get(0)
+	if err := json.Unmarshal([]byte(jsonMap), &amountToAddr); err != nil {
+		fatal(err)
+	}
+
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.EstimateFee(ctxb, &lnrpc.EstimateFeeRequest{
+		AddrToAmount: amountToAddr,
+		TargetConf:   uint32(ctx.Int("conf_target")),
+		MinConfs:     int32(ctx.Int("min_confs")),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// PublishTxCommand ...
+var PublishTxCommand = cli.Command{
+	Name:  "publishtx",
+	Usage: "publish a hex-encoded raw transaction through the backend",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "a label describing what the transaction is for",
+		},
+	},
+	Action: publishTx,
+}
+
+func publishTx(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	rawTx, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.PublishTransaction(ctxb, &lnrpc.PublishTransactionRequest{
+		RawTx: rawTx,
+		Label: ctx.String("label"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// GetTxCommand ...
+var GetTxCommand = cli.Command{
+	Name:   "gettx",
+	Usage:  "get the details of a transaction paying to, or spending from, the wallet",
+	Action: getTx,
+}
+
+func getTx(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.GetTransaction(ctxb, &lnrpc.GetTransactionRequest{
+		Txid: ctx.Args().First(),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
 // ConnectCommand ...
 var ConnectCommand = cli.Command{
-	Name:   "connect",
-	Usage:  "connect to a remote lnd peer: <lnid>@host",
+	Name:  "connect",
+	Usage: "connect to a remote lnd peer: <lnid>@host",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "perm",
+			Usage: "reconnect to the peer whenever the connection " +
+				"drops, which requires its public key",
+		},
+	},
 	Action: connectPeer,
 }
 
@@ -88,7 +286,10 @@ func connectPeer(ctx *cli.Context) {
 	client := getClient(ctx)
 
 	targetAddress := ctx.Args().Get(0)
-	req := &lnrpc.ConnectPeerRequest{targetAddress}
+	req := &lnrpc.ConnectPeerRequest{
+		IdAtHost: targetAddress,
+		Perm:     ctx.Bool("perm"),
+	}
 
 	lnid, err := client.ConnectPeer(ctxb, req)
 	if err != nil {
@@ -97,3 +298,1620 @@ func connectPeer(ctx *cli.Context) {
 
 	printRespJSON(lnid)
 }
+
+// DisconnectCommand ...
+var DisconnectCommand = cli.Command{
+	Name:  "disconnect",
+	Usage: "disconnect from a remote lnd peer: <pubkey>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "force",
+			Usage: "disconnect even if there's an open, or pending, " +
+				"channel with the peer",
+		},
+	},
+	Action: disconnectPeer,
+}
+
+func disconnectPeer(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.DisconnectPeerRequest{
+		PubKey: ctx.Args().Get(0),
+		Force:  ctx.Bool("force"),
+	}
+
+	resp, err := client.DisconnectPeer(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListPeersCommand ...
+var ListPeersCommand = cli.Command{
+	Name:   "listpeers",
+	Usage:  "list all connected peers",
+	Action: listPeers,
+}
+
+func listPeers(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.ListPeers(ctxb, &lnrpc.ListPeersRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SetPeerLabelCommand ...
+var SetPeerLabelCommand = cli.Command{
+	Name:  "setpeerlabel",
+	Usage: "attach a label to a peer, or remove it if empty",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pub_key",
+			Usage: "the hex encoded public key of the peer",
+		},
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "the label to attach to the peer",
+		},
+	},
+	Action: setPeerLabel,
+}
+
+func setPeerLabel(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.SetPeerLabel(ctxb, &lnrpc.SetPeerLabelRequest{
+		PubKey: ctx.String("pub_key"),
+		Label:  ctx.String("label"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListPeerBackupsCommand ...
+var ListPeerBackupsCommand = cli.Command{
+	Name:   "listpeerbackups",
+	Usage:  "list the backups of our channels recovered from our peers",
+	Action: listPeerBackups,
+}
+
+func listPeerBackups(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.ListPeerBackups(ctxb, &lnrpc.ListPeerBackupsRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// peerDataFlags are the flags identifying the peer, and the data, of the
+// signed peer data commands.
+var peerDataFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "pub_key",
+		Usage: "the hex encoded public key of the peer",
+	},
+	cli.IntFlag{
+		Name:  "type",
+		Usage: "the type of the data, identifying the application protocol it belongs to",
+	},
+	cli.StringFlag{
+		Name:  "data",
+		Usage: "the hex encoded data",
+	},
+}
+
+// SendSignedDataCommand ...
+var SendSignedDataCommand = cli.Command{
+	Name:   "sendsigneddata",
+	Usage:  "send data, signed by our identity key, to a connected peer",
+	Flags:  peerDataFlags,
+	Action: sendSignedData,
+}
+
+func sendSignedData(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	data, err := hex.DecodeString(ctx.String("data"))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.SendSignedData(ctxb, &lnrpc.SendSignedDataRequest{
+		PubKey: ctx.String("pub_key"),
+		Type:   uint32(ctx.Int("type")),
+		Data:   data,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SubscribeSignedDataCommand ...
+var SubscribeSignedDataCommand = cli.Command{
+	Name:   "subscribesigneddata",
+	Usage:  "print each piece of signed data our peers send us",
+	Action: subscribeSignedData,
+}
+
+func subscribeSignedData(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	stream, err := client.SubscribeSignedData(ctxb,
+		&lnrpc.SubscribeSignedDataRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	for {
+		data, err := stream.Recv()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			fatal(err)
+		}
+
+		printRespJSON(data)
+		fmt.Println()
+	}
+}
+
+// SignPeerDataCommand ...
+var SignPeerDataCommand = cli.Command{
+	Name:   "signpeerdata",
+	Usage:  "sign data with our identity key within our session with a connected peer",
+	Flags:  peerDataFlags,
+	Action: signPeerData,
+}
+
+func signPeerData(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	data, err := hex.DecodeString(ctx.String("data"))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.SignPeerData(ctxb, &lnrpc.SignPeerDataRequest{
+		PubKey: ctx.String("pub_key"),
+		Type:   uint32(ctx.Int("type")),
+		Data:   data,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// VerifyPeerDataCommand ...
+var VerifyPeerDataCommand = cli.Command{
+	Name:  "verifypeerdata",
+	Usage: "verify a connected peer signed data within our session with it",
+	Flags: append([]cli.Flag{
+		cli.StringFlag{
+			Name:  "sig",
+			Usage: "the hex encoded DER signature",
+		},
+	}, peerDataFlags...),
+	Action: verifyPeerData,
+}
+
+func verifyPeerData(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	data, err := hex.DecodeString(ctx.String("data"))
+	if err != nil {
+		fatal(err)
+	}
+	sig, err := hex.DecodeString(ctx.String("sig"))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.VerifyPeerData(ctxb, &lnrpc.VerifyPeerDataRequest{
+		PubKey:    ctx.String("pub_key"),
+		Type:      uint32(ctx.Int("type")),
+		Data:      data,
+		Signature: sig,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListMisbehaviorCommand ...
+var ListMisbehaviorCommand = cli.Command{
+	Name:  "listmisbehavior",
+	Usage: "list the protocol violations committed by our peers",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pub_key",
+			Usage: "only list the violations of the peer with this hex encoded public key",
+		},
+	},
+	Action: listMisbehavior,
+}
+
+func listMisbehavior(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.ListPeerMisbehavior(ctxb,
+		&lnrpc.ListPeerMisbehaviorRequest{
+			PubKey: ctx.String("pub_key"),
+		})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SendPaymentCommand ...
+var SendPaymentCommand = cli.Command{
+	Name:  "sendpayment",
+	Usage: "send a payment over lightning",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "dest",
+			Usage: "the hex encoded public key of the destination",
+		},
+		cli.IntFlag{
+			Name:  "amt",
+			Usage: "the number of satoshis to send",
+		},
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "the hex encoded hash to use within the htlc",
+		},
+		cli.StringFlag{
+			Name:  "payment_secret",
+			Usage: "the hex encoded payment secret of the invoice",
+		},
+		cli.IntFlag{
+			Name:  "timeout",
+			Usage: "the number of seconds to attempt the payment for",
+		},
+		cli.IntFlag{
+			Name:  "fee_limit",
+			Usage: "the most satoshis to pay in fees",
+		},
+		cli.IntFlag{
+			Name:  "fee_limit_percent",
+			Usage: "the most to pay in fees, as a percentage of amt",
+		},
+		cli.IntFlag{
+			Name:  "max_parts",
+			Usage: "the most htlcs to split the payment into",
+		},
+		cli.IntFlag{
+			Name:  "cltv_limit",
+			Usage: "the furthest the htlc time lock may be, in blocks",
+		},
+		cli.StringSliceFlag{
+			Name: "outgoing_chan_id",
+			Usage: "the channel the payment may leave over, may be " +
+				"repeated to allow several",
+			Value: &cli.StringSlice{},
+		},
+	},
+	Action: sendPayment,
+}
+
+func sendPayment(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	paymentHash, err := hex.DecodeString(ctx.String("payment_hash"))
+	if err != nil {
+		fatal(err)
+	}
+	paymentSecret, err := hex.DecodeString(ctx.String("payment_secret"))
+	if err != nil {
+		fatal(err)
+	}
+
+	outgoingChanIDs, _, err := parseRouteConstraints(ctx)
+	if err != nil {
+		fatal(err)
+	}
+
+	req := &lnrpc.SendPaymentRequest{
+		Dest:            ctx.String("dest"),
+		Amt:             int64(ctx.Int("amt")),
+		PaymentHash:     paymentHash,
+		PaymentSecret:   paymentSecret,
+		TimeoutSeconds:  uint32(ctx.Int("timeout")),
+		FeeLimit:        parseFeeLimit(ctx),
+		MaxParts:        uint32(ctx.Int("max_parts")),
+		CltvLimit:       uint32(ctx.Int("cltv_limit")),
+		OutgoingChanIds: outgoingChanIDs,
+	}
+
+	resp, err := client.SendPayment(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// parseFeeLimit returns the fee limit set by the fee_limit, or
+// fee_limit_percent, flags, or nil if neither is set.
+func parseFeeLimit(ctx *cli.Context) *lnrpc.FeeLimit {
+	if !ctx.IsSet("fee_limit") && !ctx.IsSet("fee_limit_percent") {
+		return nil
+	}
+
+	return &lnrpc.FeeLimit{
+		Fixed:   int64(ctx.Int("fee_limit")),
+		Percent: int64(ctx.Int("fee_limit_percent")),
+	}
+}
+
+// parseRouteConstraints returns the channels set by the outgoing_chan_id flag, and
+// the public key set by the last_hop flag.
+func parseRouteConstraints(ctx *cli.Context) ([]uint64, []byte, error) {
+	var outgoingChanIDs []uint64
+	for _, s := range ctx.StringSlice("outgoing_chan_id") {
+		chanID, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		outgoingChanIDs = append(outgoingChanIDs, chanID)
+	}
+
+	lastHop, err := hex.DecodeString(ctx.String("last_hop"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return outgoingChanIDs, lastHop, nil
+}
+
+// QueryRoutesCommand ...
+var QueryRoutesCommand = cli.Command{
+	Name:  "queryroutes",
+	Usage: "query the route a payment would take",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "dest",
+			Usage: "the hex encoded public key of the destination",
+		},
+		cli.IntFlag{
+			Name:  "amt",
+			Usage: "the number of satoshis to send",
+		},
+		cli.IntFlag{
+			Name:  "fee_limit",
+			Usage: "the most satoshis to pay in fees",
+		},
+		cli.IntFlag{
+			Name:  "fee_limit_percent",
+			Usage: "the most to pay in fees, as a percentage of amt",
+		},
+		cli.IntFlag{
+			Name:  "cltv_limit",
+			Usage: "the furthest the htlc time lock may be, in blocks",
+		},
+		cli.StringSliceFlag{
+			Name: "outgoing_chan_id",
+			Usage: "the channel the route may leave over, may be " +
+				"repeated to allow several",
+			Value: &cli.StringSlice{},
+		},
+		cli.StringFlag{
+			Name: "last_hop",
+			Usage: "the hex encoded public key of the node the " +
+				"route must arrive through",
+		},
+	},
+	Action: queryRoutes,
+}
+
+func queryRoutes(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	outgoingChanIDs, lastHop, err := parseRouteConstraints(ctx)
+	if err != nil {
+		fatal(err)
+	}
+
+	req := &lnrpc.QueryRoutesRequest{
+		PubKey:          ctx.String("dest"),
+		Amt:             int64(ctx.Int("amt")),
+		FeeLimit:        parseFeeLimit(ctx),
+		CltvLimit:       uint32(ctx.Int("cltv_limit")),
+		OutgoingChanIds: outgoingChanIDs,
+		LastHopPubkey:   lastHop,
+	}
+
+	resp, err := client.QueryRoutes(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// EstimateRouteFeeCommand ...
+var EstimateRouteFeeCommand = cli.Command{
+	Name: "estimateroutefee",
+	Usage: "estimate the fee, and time lock, of a payment by " +
+		"pathfinding",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "dest",
+			Usage: "the hex encoded public key of the destination",
+		},
+		cli.IntFlag{
+			Name:  "amt",
+			Usage: "the number of satoshis to send",
+		},
+	},
+	Action: estimateRouteFee,
+}
+
+func estimateRouteFee(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.EstimateRouteFeeRequest{
+		Dest: ctx.String("dest"),
+		Amt:  int64(ctx.Int("amt")),
+	}
+
+	resp, err := client.EstimateRouteFee(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListPaymentsCommand ...
+var ListPaymentsCommand = cli.Command{
+	Name:  "listpayments",
+	Usage: "list all outgoing payments",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "index_offset",
+			Usage: "only return payments after this payment index",
+		},
+		cli.IntFlag{
+			Name:  "max_payments",
+			Usage: "the max number of payments to return",
+		},
+	},
+	Action: listPayments,
+}
+
+func listPayments(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.ListPaymentsRequest{
+		IndexOffset: uint64(ctx.Int("index_offset")),
+		MaxPayments: uint64(ctx.Int("max_payments")),
+	}
+
+	payments, err := client.ListPayments(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(payments)
+}
+
+// DeletePaymentCommand ...
+var DeletePaymentCommand = cli.Command{
+	Name:  "deletepayment",
+	Usage: "delete a single payment: <payment_hash>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "failed_attempts_only",
+			Usage: "only delete the failed attempts of the payment",
+		},
+	},
+	Action: deletePayment,
+}
+
+func deletePayment(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	paymentHash, err := hex.DecodeString(ctx.Args().Get(0))
+	if err != nil {
+		fatal(err)
+	}
+
+	req := &lnrpc.DeletePaymentRequest{
+		PaymentHash:        paymentHash,
+		FailedAttemptsOnly: ctx.Bool("failed_attempts_only"),
+	}
+
+	resp, err := client.DeletePayment(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// DeleteAllPaymentsCommand ...
+var DeleteAllPaymentsCommand = cli.Command{
+	Name:  "deleteallpayments",
+	Usage: "delete all payments from the payment database",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "failed_payments_only",
+			Usage: "only delete payments which have failed",
+		},
+		cli.BoolFlag{
+			Name:  "failed_attempts_only",
+			Usage: "only delete failed attempts, retaining the payments",
+		},
+	},
+	Action: deleteAllPayments,
+}
+
+func deleteAllPayments(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.DeleteAllPaymentsRequest{
+		FailedPaymentsOnly: ctx.Bool("failed_payments_only"),
+		FailedAttemptsOnly: ctx.Bool("failed_attempts_only"),
+	}
+
+	resp, err := client.DeleteAllPayments(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListHtlcsCommand ...
+var ListHtlcsCommand = cli.Command{
+	Name:  "listhtlcs",
+	Usage: "list the htlcs currently locked in within our channels",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "only list the htlcs of the channel",
+		},
+	},
+	Action: listHtlcs,
+}
+
+func listHtlcs(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.ListHtlcsRequest{
+		ChanId: uint64(ctx.Int64("chan_id")),
+	}
+
+	resp, err := client.ListHtlcs(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// LookupHtlcResolutionCommand ...
+var LookupHtlcResolutionCommand = cli.Command{
+	Name:  "lookuphtlcresolution",
+	Usage: "look up how an htlc within one of our channels was resolved",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the channel the htlc was added to",
+		},
+		cli.Int64Flag{
+			Name:  "htlc_key",
+			Usage: "the key of the htlc within the channel",
+		},
+	},
+	Action: lookupHtlcResolution,
+}
+
+func lookupHtlcResolution(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.LookupHtlcResolutionRequest{
+		ChanId:  uint64(ctx.Int64("chan_id")),
+		HtlcKey: uint64(ctx.Int64("htlc_key")),
+	}
+
+	resp, err := client.LookupHtlcResolution(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ExportAccountingCommand ...
+var ExportAccountingCommand = cli.Command{
+	Name: "exportaccounting",
+	Usage: "export a ledger of our on-chain transactions, forwarding " +
+		"fees, invoices and payments",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "start_time",
+			Usage: "the unix timestamp to export from, inclusive",
+		},
+		cli.Int64Flag{
+			Name:  "end_time",
+			Usage: "the unix timestamp to export up to, exclusive",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "csv",
+			Usage: "the format to export in, either csv or json",
+		},
+		cli.StringSliceFlag{
+			Name: "type",
+			Usage: "only export entries of the type, one of " +
+				"on_chain, forward, invoice or payment, may be " +
+				"repeated",
+		},
+	},
+	Action: exportAccounting,
+}
+
+func exportAccounting(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	format, ok := lnrpc.AccountingFormat_value["ACCOUNTING_FORMAT_"+
+		strings.ToUpper(ctx.String("format"))]
+	if !ok {
+		fatal(fmt.Errorf("format must be one of csv or json"))
+	}
+
+	req := &lnrpc.ExportAccountingRequest{
+		StartTime: ctx.Int64("start_time"),
+		EndTime:   ctx.Int64("end_time"),
+		Format:    lnrpc.AccountingFormat(format),
+	}
+	for _, entryType := range ctx.StringSlice("type") {
+		t, ok := lnrpc.AccountingEntryType_value["ACCOUNTING_ENTRY_"+
+			strings.ToUpper(entryType)]
+		if !ok {
+			fatal(fmt.Errorf("unknown entry type: %v", entryType))
+		}
+		req.EntryTypes = append(req.EntryTypes,
+			lnrpc.AccountingEntryType(t))
+	}
+
+	resp, err := client.ExportAccounting(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	os.Stdout.Write(resp.Data)
+}
+
+// AddInvoiceCommand ...
+var AddInvoiceCommand = cli.Command{
+	Name:  "addinvoice",
+	Usage: "add a new invoice, to be paid to us",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "an optional description of the invoice",
+		},
+		cli.StringFlag{
+			Name: "preimage",
+			Usage: "the hex encoded preimage of the invoice, generated " +
+				"at random if not set",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of satoshis to be paid",
+		},
+		cli.Int64Flag{
+			Name:  "expiry",
+			Usage: "the number of seconds the invoice is payable for",
+		},
+		cli.BoolFlag{
+			Name:  "amp",
+			Usage: "accept any number of amp payments to the invoice",
+		},
+	},
+	Action: addInvoice,
+}
+
+func addInvoice(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	preimage, err := hex.DecodeString(ctx.String("preimage"))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.AddInvoice(ctxb, &lnrpc.Invoice{
+		Memo:      ctx.String("memo"),
+		RPreimage: preimage,
+		Value:     ctx.Int64("amt"),
+		Expiry:    ctx.Int64("expiry"),
+		Amp:       ctx.Bool("amp"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SubscribeInvoicesCommand ...
+var SubscribeInvoicesCommand = cli.Command{
+	Name:   "subscribeinvoices",
+	Usage:  "print each invoice as it's settled or canceled",
+	Action: subscribeInvoices,
+}
+
+func subscribeInvoices(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	stream, err := client.SubscribeInvoices(ctxb,
+		&lnrpc.InvoiceSubscription{})
+	if err != nil {
+		fatal(err)
+	}
+
+	for {
+		invoice, err := stream.Recv()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			fatal(err)
+		}
+
+		printRespJSON(invoice)
+		fmt.Println()
+	}
+}
+
+// ImportAccountCommand ...
+var ImportAccountCommand = cli.Command{
+	Name:  "importaccount",
+	Usage: "import a watch-only account: <name> <xpub>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "master_key_fingerprint",
+			Usage: "the hex encoded fingerprint of the signer's master key",
+		},
+		cli.StringFlag{
+			Name:  "derivation_path",
+			Usage: "the path the xpub was derived at, e.g. m/44'/1'/0'",
+		},
+		cli.BoolFlag{
+			Name:  "rescan",
+			Usage: "rescan the chain for existing outputs of the account",
+		},
+	},
+	Action: importAccount,
+}
+
+func importAccount(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	var fingerprint uint32
+	if fp := ctx.String("master_key_fingerprint"); fp != "" {
+		fpBytes, err := hex.DecodeString(fp)
+		if err != nil {
+			fatal(err)
+		}
+		if len(fpBytes) != 4 {
+			fatal(fmt.Errorf("fingerprint must be 4 bytes"))
+		}
+		fingerprint = binary.LittleEndian.Uint32(fpBytes)
+	}
+
+	path, err := parseDerivationPath(ctx.String("derivation_path"))
+	if err != nil {
+		fatal(err)
+	}
+
+	req := &lnrpc.ImportAccountRequest{
+		Name:                 ctx.Args().Get(0),
+		ExtendedPublicKey:    ctx.Args().Get(1),
+		MasterKeyFingerprint: fingerprint,
+		DerivationPath:       path,
+		Rescan:               ctx.Bool("rescan"),
+	}
+
+	resp, err := client.ImportAccount(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// parseDerivationPath parses a BIP32 derivation path such as m/44'/1'/0'.
+func parseDerivationPath(path string) ([]uint32, error) {
+	if path == "" || path == "m" {
+		return nil, nil
+	}
+
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("derivation path must start with m/")
+	}
+
+	indexes := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		var offset uint32
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			offset = hdkeychain.HardenedKeyStart
+			part = part[:len(part)-1]
+		}
+
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path element %v: %v",
+				part, err)
+		}
+		indexes = append(indexes, uint32(index)+offset)
+	}
+
+	return indexes, nil
+}
+
+// ImportPubKeyCommand ...
+var ImportPubKeyCommand = cli.Command{
+	Name:  "importpubkey",
+	Usage: "import a public key into a watch-only account: <account> <pubkey>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "rescan",
+			Usage: "rescan the chain for existing outputs of the key",
+		},
+	},
+	Action: importPubKey,
+}
+
+func importPubKey(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	pubKey, err := hex.DecodeString(ctx.Args().Get(1))
+	if err != nil {
+		fatal(err)
+	}
+
+	req := &lnrpc.ImportPublicKeyRequest{
+		Account:   ctx.Args().Get(0),
+		PublicKey: pubKey,
+		Rescan:    ctx.Bool("rescan"),
+	}
+
+	resp, err := client.ImportPublicKey(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// FundPsbtCommand ...
+var FundPsbtCommand = cli.Command{
+	Name: "fundpsbt",
+	Usage: "fund a psbt, from either a base64 encoded template psbt or a " +
+		"json map of addresses to amounts",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "template_psbt",
+			Usage: "the base64 encoded template psbt to fund",
+		},
+		cli.StringFlag{
+			Name:  "outputs",
+			Usage: "a json map of addresses to the amounts paid to them",
+		},
+		cli.StringFlag{
+			Name:  "account",
+			Usage: "the watch-only account to fund the psbt from",
+		},
+		cli.Int64Flag{
+			Name:  "sat_per_kb",
+			Usage: "the fee rate of the funded transaction",
+		},
+		cli.IntFlag{
+			Name:  "min_confs",
+			Value: 1,
+			Usage: "the number of confirmations coins must have",
+		},
+	},
+	Action: fundPsbt,
+}
+
+func fundPsbt(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.FundPsbtRequest{
+		Account:  ctx.String("account"),
+		SatPerKb: ctx.Int64("sat_per_kb"),
+		MinConfs: int32(ctx.Int("min_confs")),
+	}
+	if template := ctx.String("template_psbt"); template != "" {
+		packet, err := base64.StdEncoding.DecodeString(template)
+		if err != nil {
+			fatal(err)
+		}
+		req.Psbt = packet
+	}
+	if outputs := ctx.String("outputs"); outputs != "" {
+		if err := json.Unmarshal([]byte(outputs), &req.Outputs); err != nil {
+			fatal(err)
+		}
+	}
+
+	resp, err := client.FundPsbt(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SignPsbtCommand ...
+var SignPsbtCommand = cli.Command{
+	Name:   "signpsbt",
+	Usage:  "sign the inputs of a psbt controlled by the wallet: <psbt>",
+	Action: signPsbt,
+}
+
+func signPsbt(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	packet, err := base64.StdEncoding.DecodeString(ctx.Args().Get(0))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.SignPsbt(ctxb, &lnrpc.SignPsbtRequest{
+		FundedPsbt: packet,
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// FinalizePsbtCommand ...
+var FinalizePsbtCommand = cli.Command{
+	Name:  "finalizepsbt",
+	Usage: "finalize a psbt, extracting the signed transaction: <psbt>",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "publish",
+			Usage: "broadcast the signed transaction",
+		},
+	},
+	Action: finalizePsbt,
+}
+
+func finalizePsbt(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	packet, err := base64.StdEncoding.DecodeString(ctx.Args().Get(0))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.FinalizePsbt(ctxb, &lnrpc.FinalizePsbtRequest{
+		SignedPsbt: packet,
+		Publish:    ctx.Bool("publish"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// BumpFeeCommand ...
+var BumpFeeCommand = cli.Command{
+	Name: "bumpfee",
+	Usage: "raise the fee rate of an unconfirmed transaction by spending " +
+		"one of our outputs: <txid:output_index>",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "sat_per_kb",
+			Usage: "the fee rate the transactions should pay together",
+		},
+	},
+	Action: bumpFee,
+}
+
+func bumpFee(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	parts := strings.Split(ctx.Args().Get(0), ":")
+	if len(parts) != 2 {
+		fatal(fmt.Errorf("outpoint must be of the form txid:index"))
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.BumpFee(ctxb, &lnrpc.BumpFeeRequest{
+		Txid:        parts[0],
+		OutputIndex: uint32(index),
+		SatPerKb:    ctx.Int64("sat_per_kb"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// PendingSweepsCommand ...
+var PendingSweepsCommand = cli.Command{
+	Name:   "pendingsweeps",
+	Usage:  "list our outputs yet to be swept back to the wallet",
+	Action: pendingSweeps,
+}
+
+func pendingSweeps(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.PendingSweeps(ctxb, &lnrpc.PendingSweepsRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListSweepsCommand ...
+var ListSweepsCommand = cli.Command{
+	Name:   "listsweeps",
+	Usage:  "list our confirmed sweep transactions",
+	Action: listSweeps,
+}
+
+func listSweeps(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.ListSweeps(ctxb, &lnrpc.ListSweepsRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// AbandonChannelCommand ...
+var AbandonChannelCommand = cli.Command{
+	Name: "abandonchannel",
+	Usage: "remove the channel with a node from the database, without " +
+		"closing it on-chain, losing any funds within it: <lnid>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "only remove the channel if it has this funding txid",
+		},
+		cli.BoolFlag{
+			Name: "i_know_what_i_am_doing",
+			Usage: "confirm that abandoning the channel loses any " +
+				"funds within it, required unless lnd runs with --dev",
+		},
+	},
+	Action: abandonChannel,
+}
+
+func abandonChannel(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	lnID, err := hex.DecodeString(ctx.Args().Get(0))
+	if err != nil {
+		fatal(err)
+	}
+
+	resp, err := client.AbandonChannel(ctxb, &lnrpc.AbandonChannelRequest{
+		LnID:              lnID,
+		FundingTxid:       ctx.String("funding_txid"),
+		IKnowWhatIAmDoing: ctx.Bool("i_know_what_i_am_doing"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// UpdateChannelParamsCommand ...
+var UpdateChannelParamsCommand = cli.Command{
+	Name: "updatechanparams",
+	Usage: "renegotiate the parameters of the channel with a connected " +
+		"peer, without closing it: <pubkey>",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "max_accepted_htlcs",
+			Usage: "the number of pending htlcs we accept from the peer",
+		},
+		cli.Int64Flag{
+			Name: "max_value_in_flight_msat",
+			Usage: "the total value, in millisatoshis, of pending " +
+				"htlcs we accept from the peer",
+		},
+		cli.IntFlag{
+			Name: "csv_delay",
+			Usage: "the delay, in blocks, of the outputs paying " +
+				"either party within the commitment transactions",
+		},
+	},
+	Action: updateChannelParams,
+}
+
+func updateChannelParams(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.UpdateChannelParamsRequest{
+		PubKey:               ctx.Args().Get(0),
+		MaxAcceptedHtlcs:     uint32(ctx.Int("max_accepted_htlcs")),
+		MaxValueInFlightMsat: uint64(ctx.Int64("max_value_in_flight_msat")),
+		CsvDelay:             uint32(ctx.Int("csv_delay")),
+	}
+	resp, err := client.UpdateChannelParams(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ListPendingReservationsCommand ...
+var ListPendingReservationsCommand = cli.Command{
+	Name:   "listpendingreservations",
+	Usage:  "list the channel reservations which are yet to complete",
+	Action: listPendingReservations,
+}
+
+func listPendingReservations(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.ListPendingReservationsRequest{}
+	resp, err := client.ListPendingReservations(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// DescribeGraphCommand ...
+var DescribeGraphCommand = cli.Command{
+	Name:  "describegraph",
+	Usage: "dump every channel within the channel graph",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "include_unannounced",
+			Usage: "also include our private channels",
+		},
+	},
+	Action: describeGraph,
+}
+
+func describeGraph(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	req := &lnrpc.ChannelGraphRequest{
+		IncludeUnannounced: ctx.Bool("include_unannounced"),
+	}
+	resp, err := client.DescribeGraph(ctxb, req)
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// GetChanInfoCommand ...
+var GetChanInfoCommand = cli.Command{
+	Name:  "getchaninfo",
+	Usage: "look up a channel within the channel graph",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the 8-byte integer ID of the channel",
+		},
+		cli.StringFlag{
+			Name:  "chan_point",
+			Usage: "the funding outpoint of the channel: <txid:index>",
+		},
+	},
+	Action: getChanInfo,
+}
+
+func getChanInfo(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.GetChanInfo(ctxb, &lnrpc.ChanInfoRequest{
+		ChanId:    uint64(ctx.Int64("chan_id")),
+		ChanPoint: ctx.String("chan_point"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// GetNodeInfoCommand ...
+var GetNodeInfoCommand = cli.Command{
+	Name:   "getnodeinfo",
+	Usage:  "look up the channels of a node within the channel graph: <pubkey>",
+	Action: getNodeInfo,
+}
+
+func getNodeInfo(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.GetNodeInfo(ctxb, &lnrpc.NodeInfoRequest{
+		PubKey: ctx.Args().Get(0),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// GetNetworkInfoCommand ...
+var GetNetworkInfoCommand = cli.Command{
+	Name:   "getnetworkinfo",
+	Usage:  "show statistics of the channel graph",
+	Action: getNetworkInfo,
+}
+
+func getNetworkInfo(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.GetNetworkInfo(ctxb, &lnrpc.NetworkInfoRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// UpdateChanStatusCommand ...
+var UpdateChanStatusCommand = cli.Command{
+	Name:  "updatechanstatus",
+	Usage: "enable, or disable, forwarding over one of our channels",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the 8-byte integer ID of the channel",
+		},
+		cli.StringFlag{
+			Name: "action",
+			Usage: "enable, disable, or auto to enable the channel " +
+				"only while the peer is online",
+		},
+	},
+	Action: updateChanStatus,
+}
+
+func updateChanStatus(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	action, ok := lnrpc.ChanStatusAction_value[strings.ToUpper(
+		ctx.String("action"))]
+	if !ok {
+		fatal(fmt.Errorf("action must be one of enable, disable, or auto"))
+	}
+
+	resp, err := client.UpdateChanStatus(ctxb, &lnrpc.UpdateChanStatusRequest{
+		ChanId: uint64(ctx.Int64("chan_id")),
+		Action: lnrpc.ChanStatusAction(action),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// ChannelInsightsCommand ...
+var ChannelInsightsCommand = cli.Command{
+	Name:  "channelinsights",
+	Usage: "show the uptime, and flap count, of the peers of our channels",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "only show the channel of this 8-byte integer ID",
+		},
+	},
+	Action: channelInsights,
+}
+
+func channelInsights(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.ChannelInsights(ctxb, &lnrpc.ChannelInsightsRequest{
+		ChanId: uint64(ctx.Int64("chan_id")),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SetChannelNoteCommand ...
+var SetChannelNoteCommand = cli.Command{
+	Name:  "setchannelnote",
+	Usage: "attach a note to a channel, or remove it if empty",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the 8-byte integer ID of the channel",
+		},
+		cli.StringFlag{
+			Name:  "note",
+			Usage: "the note to attach to the channel",
+		},
+	},
+	Action: setChannelNote,
+}
+
+func setChannelNote(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.SetChannelNote(ctxb, &lnrpc.SetChannelNoteRequest{
+		ChanId: uint64(ctx.Int64("chan_id")),
+		Note:   ctx.String("note"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// FeeReportCommand ...
+var FeeReportCommand = cli.Command{
+	Name: "feereport",
+	Usage: "show the forwarding policy of each of our channels, and the " +
+		"fees earned over the last day, week and month",
+	Action: feeReport,
+}
+
+func feeReport(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.FeeReport(ctxb, &lnrpc.FeeReportRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SubscribeGraphCommand ...
+var SubscribeGraphCommand = cli.Command{
+	Name:   "subscribegraph",
+	Usage:  "print each change made to the channel graph as it happens",
+	Action: subscribeGraph,
+}
+
+func subscribeGraph(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	stream, err := client.SubscribeChannelGraph(ctxb,
+		&lnrpc.GraphTopologySubscription{})
+	if err != nil {
+		fatal(err)
+	}
+
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			fatal(err)
+		}
+
+		printRespJSON(update)
+		fmt.Println()
+	}
+}
+
+// GetBestBlockCommand ...
+var GetBestBlockCommand = cli.Command{
+	Name:   "getbestblock",
+	Usage:  "get the hash, and height, of the tip of the main chain",
+	Action: getBestBlock,
+}
+
+func getBestBlock(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	resp, err := client.GetBestBlock(ctxb, &lnrpc.GetBestBlockRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(resp)
+}
+
+// SubscribeBlocksCommand ...
+var SubscribeBlocksCommand = cli.Command{
+	Name:   "subscribeblocks",
+	Usage:  "print the tip of the main chain, then each new block as it's connected",
+	Action: subscribeBlocks,
+}
+
+func subscribeBlocks(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	stream, err := client.SubscribeBlockEpochs(ctxb,
+		&lnrpc.BlockEpochRequest{})
+	if err != nil {
+		fatal(err)
+	}
+
+	for {
+		epoch, err := stream.Recv()
+		if err == io.EOF {
+			return
+		} else if err != nil {
+			fatal(err)
+		}
+
+		printRespJSON(epoch)
+		fmt.Println()
+	}
+}
+
+// RegisterConfNtfnCommand ...
+var RegisterConfNtfnCommand = cli.Command{
+	Name:  "registerconfntfn",
+	Usage: "wait for a transaction to reach a number of confirmations",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "txid",
+			Usage: "the txid of the transaction",
+		},
+		cli.StringFlag{
+			Name:  "script",
+			Usage: "the hex-encoded script of one of the transaction's outputs",
+		},
+		cli.IntFlag{
+			Name:  "num_confs",
+			Value: 1,
+			Usage: "the number of confirmations to wait for",
+		},
+	},
+	Action: registerConfNtfn,
+}
+
+func registerConfNtfn(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	script, err := hex.DecodeString(ctx.String("script"))
+	if err != nil {
+		fatal(err)
+	}
+
+	stream, err := client.RegisterConfirmationsNtfn(ctxb, &lnrpc.ConfRequest{
+		Txid:     ctx.String("txid"),
+		Script:   script,
+		NumConfs: uint32(ctx.Int("num_confs")),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(event)
+}
+
+// RegisterSpendNtfnCommand ...
+var RegisterSpendNtfnCommand = cli.Command{
+	Name:  "registerspendntfn",
+	Usage: "wait for an outpoint to be spent",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "txid",
+			Usage: "the txid of the outpoint",
+		},
+		cli.IntFlag{
+			Name:  "output_index",
+			Usage: "the output index of the outpoint",
+		},
+		cli.BoolFlag{
+			Name:  "mempool",
+			Usage: "notify as soon as the spend enters the mempool, rather than once it's mined",
+		},
+	},
+	Action: registerSpendNtfn,
+}
+
+func registerSpendNtfn(ctx *cli.Context) {
+	ctxb := context.Background()
+	client := getClient(ctx)
+
+	stream, err := client.RegisterSpendNtfn(ctxb, &lnrpc.SpendRequest{
+		Txid:        ctx.String("txid"),
+		OutputIndex: uint32(ctx.Int("output_index")),
+		Mempool:     ctx.Bool("mempool"),
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		fatal(err)
+	}
+
+	printRespJSON(event)
+}
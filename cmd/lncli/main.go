@@ -1,14 +1,18 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 
 	"github.com/codegangsta/cli"
 	"github.com/lightningnetwork/lnd/lnrpc"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 var (
@@ -16,6 +20,10 @@ var (
 )
 
 func fatal(err error) {
+	if detail := lnrpc.ErrorDetailFromError(err); detail != nil {
+		fmt.Fprintf(os.Stderr, "[lncli] %v (%v)\n", err, detail.Code)
+		os.Exit(1)
+	}
 	fmt.Fprintf(os.Stderr, "[lncli] %v\n", err)
 	os.Exit(1)
 }
@@ -30,7 +38,31 @@ func getClientConn(ctx *cli.Context) *grpc.ClientConn {
 	// * http://www.grpc.io/docs/guides/auth.html
 	// * http://research.google.com/pubs/pub41892.html
 	// * https://github.com/go-macaroon/macaroon
-	opts := []grpc.DialOption{grpc.WithInsecure()}
+	pemCert, err := ioutil.ReadFile(ctx.GlobalString("tlscertpath"))
+	if err != nil {
+		fatal(err)
+	}
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(pemCert) {
+		fatal(fmt.Errorf("no certificate found in %v",
+			ctx.GlobalString("tlscertpath")))
+	}
+	tlsConfig := &tls.Config{RootCAs: rootCAs}
+
+	// Servers requiring client certificates are authenticated to with
+	// the configured certificate.
+	if ctx.GlobalString("tlsclientcert") != "" {
+		clientCert, err := tls.LoadX509KeyPair(
+			ctx.GlobalString("tlsclientcert"),
+			ctx.GlobalString("tlsclientkey"))
+		if err != nil {
+			fatal(err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	creds := credentials.NewTLS(tlsConfig)
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
 
 	conn, err := grpc.Dial(ctx.GlobalString("rpcserver"), opts...)
 	if err != nil {
@@ -51,11 +83,75 @@ func main() {
 			Value: "localhost:10000",
 			Usage: "host:port of ln daemon",
 		},
+		cli.StringFlag{
+			Name:  "tlscertpath",
+			Value: "test_wal/tls.cert",
+			Usage: "path to the TLS certificate of ln daemon",
+		},
+		cli.StringFlag{
+			Name:  "tlsclientcert",
+			Usage: "path to the TLS client certificate to authenticate with, if ln daemon requires one",
+		},
+		cli.StringFlag{
+			Name:  "tlsclientkey",
+			Usage: "path to the key of the TLS client certificate",
+		},
 	}
 	app.Commands = []cli.Command{
 		NewAddressCommand,
+		GetRecoveryInfoCommand,
+		WalletBalanceCommand,
+		ChannelBalanceCommand,
 		SendManyCommand,
+		EstimateFeeCommand,
+		PublishTxCommand,
+		GetTxCommand,
+		GetInfoCommand,
 		ConnectCommand,
+		DisconnectCommand,
+		ListPeersCommand,
+		SetPeerLabelCommand,
+		ListPeerBackupsCommand,
+		SendSignedDataCommand,
+		SubscribeSignedDataCommand,
+		SignPeerDataCommand,
+		VerifyPeerDataCommand,
+		ListMisbehaviorCommand,
+		SendPaymentCommand,
+		QueryRoutesCommand,
+		EstimateRouteFeeCommand,
+		ListPaymentsCommand,
+		DeletePaymentCommand,
+		DeleteAllPaymentsCommand,
+		ListHtlcsCommand,
+		LookupHtlcResolutionCommand,
+		ExportAccountingCommand,
+		AddInvoiceCommand,
+		SubscribeInvoicesCommand,
+		ImportAccountCommand,
+		ImportPubKeyCommand,
+		FundPsbtCommand,
+		SignPsbtCommand,
+		FinalizePsbtCommand,
+		BumpFeeCommand,
+		PendingSweepsCommand,
+		ListSweepsCommand,
+		AbandonChannelCommand,
+		UpdateChannelParamsCommand,
+		ListPendingReservationsCommand,
+		DescribeGraphCommand,
+		GetChanInfoCommand,
+		GetNodeInfoCommand,
+		GetNetworkInfoCommand,
+		UpdateChanStatusCommand,
+		ChannelInsightsCommand,
+		SetChannelNoteCommand,
+		FeeReportCommand,
+		SubscribeGraphCommand,
+		GetBestBlockCommand,
+		SubscribeBlocksCommand,
+		RegisterConfNtfnCommand,
+		RegisterSpendNtfnCommand,
 		ShellCommand,
 	}
 
@@ -44,7 +44,7 @@ func LnConnect(args []string) error {
 		return fmt.Errorf("need: lnc pubkeyhash@hostname or pkh (via pbx)")
 	}
 
-	req := &lnrpc.ConnectPeerRequest{args[0]}
+	req := &lnrpc.ConnectPeerRequest{IdAtHost: args[0]}
 	resp, err := z.ConnectPeer(stub, req)
 	if err != nil {
 		return err
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxPeers is the default number of peers we'll remain
+	// connected to at once.
+	defaultMaxPeers = 125
+
+	// defaultMaxDialsPerMinute is the default number of outbound
+	// connections we'll attempt each minute.
+	defaultMaxDialsPerMinute = 30
+
+	// defaultReservedChanPeerSlots is the default number of peer slots
+	// only peers we have channels with may take up by connecting to us.
+	defaultReservedChanPeerSlots = 25
+)
+
+var (
+	// ErrTooManyPeers is returned when connecting to a peer while we're
+	// already connected to the maximum number of peers.
+	ErrTooManyPeers = errors.New("connected to the maximum number of peers")
+
+	// ErrDialRateLimited is returned when connecting to a peer once we've
+	// made the maximum number of outbound connection attempts for the
+	// last minute.
+	ErrDialRateLimited = errors.New("too many recent connection attempts, " +
+		"try again later")
+)
+
+// connLimits bounds the number of peers we're connected to, and the rate at
+// which we dial them, so neither our resources, nor those of remote nodes,
+// are exhausted. Some of the peer slots are reserved for peers we have
+// channels with, so they're never crowded out by inbound connections from
+// peers which merely gossip with us.
+type connLimits struct {
+	maxPeers            int
+	reservedChanPeers   int
+	dialRate, dialBurst float64
+
+	mtx        sync.Mutex
+	dialTokens float64
+	lastDial   time.Time
+}
+
+// newConnLimits creates limits of maxPeers peers, of which
+// reservedChanPeers are reserved for channel peers, and of maxDialsPerMinute
+// outbound connection attempts. A limit of zero disables it.
+func newConnLimits(maxPeers, reservedChanPeers,
+	maxDialsPerMinute int) *connLimits {
+
+	return &connLimits{
+		maxPeers:          maxPeers,
+		reservedChanPeers: reservedChanPeers,
+		dialRate:          float64(maxDialsPerMinute) / 60,
+		dialBurst:         float64(maxDialsPerMinute),
+		dialTokens:        float64(maxDialsPerMinute),
+		lastDial:          time.Now(),
+	}
+}
+
+// admitPeer returns an error if a new peer would take us beyond our limit of
+// peers, given the number we're already connected to. Inbound peers we have
+// no channels with are limited to the unreserved slots. As finding our
+// channels with the peer takes a lookup, isChanPeer is only called once the
+// unreserved slots are taken.
+func (c *connLimits) admitPeer(numPeers int, inbound bool,
+	isChanPeer func() bool) error {
+
+	if c.maxPeers == 0 {
+		return nil
+	}
+	if numPeers >= c.maxPeers {
+		return ErrTooManyPeers
+	}
+	if inbound && numPeers >= c.maxPeers-c.reservedChanPeers &&
+		!isChanPeer() {
+
+		return ErrTooManyPeers
+	}
+
+	return nil
+}
+
+// atPeerLimit returns true if we're connected to the maximum number of
+// peers.
+func (c *connLimits) atPeerLimit(numPeers int) bool {
+	return c.maxPeers != 0 && numPeers >= c.maxPeers
+}
+
+// allowDial spends one of our outbound connection attempts, returning
+// ErrDialRateLimited if none remain. Attempts are refilled at a steady rate
+// up to those allowed each minute.
+func (c *connLimits) allowDial(now time.Time) error {
+	if c.dialBurst == 0 {
+		return nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if elapsed := now.Sub(c.lastDial); elapsed > 0 {
+		c.dialTokens = math.Min(c.dialBurst,
+			c.dialTokens+elapsed.Seconds()*c.dialRate)
+	}
+	c.lastDial = now
+
+	if c.dialTokens < 1 {
+		return ErrDialRateLimited
+	}
+	c.dialTokens--
+
+	return nil
+}
+
+// isChannelPeer returns true if we have a channel with the peer, whether
+// pending, or within the channel graph.
+func (s *server) isChannelPeer(p *peer) bool {
+	if p.hasChannel() {
+		return true
+	}
+
+	pubKey := p.remotePub()
+	if pubKey == nil {
+		return false
+	}
+
+	channels, err := s.fetchOurChannels()
+	if err != nil {
+		return false
+	}
+	for _, channel := range channels {
+		if channel.Peer.IsEqual(pubKey) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,7 @@
+// Package discovery synchronizes our view of the channel graph with that of
+// our peers. Rather than relying on each peer dumping its entire graph, a
+// GossipSyncer is created for each peer, which queries the range of channels
+// the peer knows of, and then requests the announcements of only those
+// channels we're missing. The SyncManager caps the number of peers we
+// actively synchronize with, with the rest only answering queries.
+package discovery
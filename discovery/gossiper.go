@@ -0,0 +1,878 @@
+package discovery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sigpool"
+)
+
+var (
+	// ErrGossipRateLimited is returned when a peer sends channel updates
+	// faster than its rate limit allows.
+	ErrGossipRateLimited = errors.New("peer exceeded its gossip rate limit")
+
+	// ErrUnknownChannel is returned when a channel update is received for
+	// a channel which isn't within the graph.
+	ErrUnknownChannel = errors.New("channel update for unknown channel")
+
+	// ErrStaleUpdate is returned when a channel update is no newer than
+	// the latest known for its channel and direction.
+	ErrStaleUpdate = errors.New("channel update is stale")
+
+	// ErrFutureUpdate is returned when the timestamp of a channel update
+	// is too far in the future.
+	ErrFutureUpdate = errors.New("channel update timestamp is too far " +
+		"in the future")
+
+	// ErrInvalidUpdateSig is returned when a channel update isn't signed
+	// by the node it originates from.
+	ErrInvalidUpdateSig = errors.New("invalid channel update signature")
+
+	// ErrInvalidAnnouncementSig is returned when any of the four
+	// signatures of a channel announcement is invalid.
+	ErrInvalidAnnouncementSig = errors.New("invalid channel announcement " +
+		"signature")
+
+	// ErrChainNotSynced is returned when an announcement is received
+	// before we've synced to the chain, once maxPrematureAnnouncements
+	// are already queued.
+	ErrChainNotSynced = errors.New("not yet synced to the chain")
+)
+
+const (
+	// DefaultTrickleDelay is the default interval at which accepted
+	// announcements are rebroadcast to our peers.
+	DefaultTrickleDelay = 90 * time.Second
+
+	// DefaultUpdateRate is the default number of channel updates per
+	// second we'll accept from each peer.
+	DefaultUpdateRate = 10
+
+	// DefaultUpdateBurst is the default number of channel updates a peer
+	// may send at once before being rate limited. It allows for both
+	// updates of each channel within a full reply to a gossip query.
+	DefaultUpdateBurst = 2 * defaultChunkSize
+
+	// maxFutureUpdate is how far beyond our own clock we'll accept the
+	// timestamp of a channel update, allowing for clock skew.
+	maxFutureUpdate = time.Hour
+
+	// localPeerID is the sender of the announcements we originate. Peer
+	// IDs start from one, so it's never that of a connected peer.
+	localPeerID = 0
+
+	// maxPrematureAnnouncements is the number of announcements received
+	// before we've synced to the chain which are queued to be processed
+	// once we have. Further announcements are rejected.
+	maxPrematureAnnouncements = 1000
+)
+
+// GossipGraph is the view of the channel graph required to validate, and
+// store, the announcements of our peers.
+type GossipGraph interface {
+	// AddChannelEdge adds the announced channel, funded by the passed
+	// outpoint of the passed value, to the graph.
+	AddChannelEdge(ann *lnwire.ChannelAnnouncement, chanPoint *wire.OutPoint,
+		capacity btcutil.Amount) error
+
+	// AddPrivateChannelEdge adds one of our private channels, funded by
+	// the passed outpoint of the passed value, to the graph. Its
+	// announcement carries no signatures.
+	AddPrivateChannelEdge(ann *lnwire.ChannelAnnouncement,
+		chanPoint *wire.OutPoint, capacity btcutil.Amount) error
+
+	// IsPrivateChannel returns true if the channel is one of our private
+	// channels.
+	IsPrivateChannel(chanID lnwire.ShortChannelID) (bool, error)
+
+	// FetchChannelEdge returns the announcement of the channel, or nil if
+	// it isn't within the graph.
+	FetchChannelEdge(chanID lnwire.ShortChannelID) (*lnwire.ChannelAnnouncement, error)
+
+	// FetchEdgePolicy returns the latest channel update for the direction
+	// of the channel, or nil if none is known.
+	FetchEdgePolicy(chanID lnwire.ShortChannelID, direction uint8) (*lnwire.ChannelUpdate, error)
+
+	// UpdateEdgePolicy stores the channel update as the latest for its
+	// channel and direction.
+	UpdateEdgePolicy(update *lnwire.ChannelUpdate) error
+}
+
+// GossiperCfg is the configuration of the Gossiper.
+type GossiperCfg struct {
+	// Graph is our view of the channel graph.
+	Graph GossipGraph
+
+	// FetchFundingOutput locates the funding output of the channel
+	// within the chain, returning its outpoint and value, or an error if
+	// it doesn't exist, or has been spent.
+	FetchFundingOutput func(chanID lnwire.ShortChannelID) (*wire.OutPoint, btcutil.Amount, error)
+
+	// Notifier is sent each change made to the graph. It may be nil.
+	Notifier *TopologyNotifier
+
+	// Broadcast sends the messages to all our peers, other than those
+	// within the skip set.
+	Broadcast func(skip map[int32]struct{}, msgs ...lnwire.Message) error
+
+	// SendToPeer sends the messages to the peer with the passed public
+	// key, if we're connected to them. Our channel updates for private
+	// channels are only ever sent to the channel's peer.
+	SendToPeer func(nodeKey *btcec.PublicKey, msgs ...lnwire.Message) error
+
+	// TrickleDelay is the interval at which accepted announcements are
+	// batched up to be rebroadcast.
+	TrickleDelay time.Duration
+
+	// UpdateRate is the number of channel updates per second we'll
+	// accept from each peer.
+	UpdateRate float64
+
+	// UpdateBurst is the number of channel updates a peer may send at
+	// once before being rate limited.
+	UpdateBurst int
+
+	// SigPool verifies the signatures of announcements in parallel. It
+	// MUST be started before any announcements are processed.
+	SigPool *sigpool.SigPool
+
+	// SigCacheSize is the number of announcements whose verified
+	// signatures are remembered, so that the same announcement relayed by
+	// several peers is only verified once. If zero, DefaultSigCacheSize
+	// is used.
+	SigCacheSize int
+
+	// ChainSynced is closed once we've synced to the chain. Until then,
+	// the funding outputs of announced channels can't be located, so the
+	// announcements of our peers are queued rather than validated, and
+	// processed in the order received once we've synced. If nil, we're
+	// deemed synced from the start.
+	ChainSynced <-chan struct{}
+}
+
+// Gossiper validates the channel announcements, and updates, sent by our
+// peers, adding them to the graph. Rather than relaying each as soon as it
+// arrives, accepted messages are batched up and rebroadcast on a trickle
+// timer, with any superseded updates dropped from the batch. Together with
+// per-peer rate limits, and the rejection of stale updates, this keeps gossip
+// storms from overwhelming small nodes.
+type Gossiper struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *GossiperCfg
+
+	// sigCache holds the hashes of the announcements whose signatures
+	// have been verified. It's safe for concurrent use.
+	sigCache *sigCache
+
+	// The mutex guards the rate limiters, the pending batch, and the
+	// announcements queued until we've synced to the chain.
+	sync.Mutex
+	limiters    map[int32]*rateLimiter
+	batch       *announcementBatch
+	chainSynced bool
+	premature   []*prematureMsg
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewGossiper creates a new Gossiper from the passed config.
+func NewGossiper(cfg *GossiperCfg) *Gossiper {
+	sigCacheSize := cfg.SigCacheSize
+	if sigCacheSize == 0 {
+		sigCacheSize = DefaultSigCacheSize
+	}
+
+	return &Gossiper{
+		cfg:         cfg,
+		sigCache:    newSigCache(sigCacheSize),
+		limiters:    make(map[int32]*rateLimiter),
+		batch:       newAnnouncementBatch(),
+		chainSynced: cfg.ChainSynced == nil,
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine rebroadcasting accepted announcements.
+func (d *Gossiper) Start() error {
+	if !atomic.CompareAndSwapUint32(&d.started, 0, 1) {
+		return nil
+	}
+
+	d.wg.Add(1)
+	go d.networkHandler()
+
+	return nil
+}
+
+// Stop signals the Gossiper to exit, and waits for it to do so.
+func (d *Gossiper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&d.stopped, 0, 1) {
+		return nil
+	}
+
+	close(d.quit)
+	d.wg.Wait()
+
+	return nil
+}
+
+// ProcessRemoteAnnouncement validates a channel announcement, or update,
+// sent by the peer, adding it to both the graph and the next batch to be
+// rebroadcast. An error is returned if the message is rejected.
+//
+// Signatures are verified by the sig pool without the mutex held, so that
+// the announcements of many peers are verified in parallel.
+//
+// Until we've synced to the chain, the message is instead queued, and nil
+// returned, or ErrChainNotSynced should the queue be full.
+func (d *Gossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
+	peerID int32) error {
+
+	queued, err := d.queuePremature(msg, peerID)
+	if err != nil || queued {
+		return err
+	}
+
+	return d.processRemoteAnnouncement(msg, peerID)
+}
+
+// processRemoteAnnouncement validates the announcement, or update, sent by
+// the peer once we've synced to the chain.
+func (d *Gossiper) processRemoteAnnouncement(msg lnwire.Message,
+	peerID int32) error {
+
+	switch msg := msg.(type) {
+	case *lnwire.ChannelAnnouncement:
+		// Channels already within the graph are likely to be announced
+		// by each of our peers, so they're skipped before verifying
+		// their signatures.
+		existing, err := d.cfg.Graph.FetchChannelEdge(msg.ShortChannelID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if err := d.verifyChanAnn(msg); err != nil {
+				return err
+			}
+		}
+
+		d.Lock()
+		defer d.Unlock()
+		return d.processChanAnn(msg, peerID)
+
+	case *lnwire.ChannelUpdate:
+		return d.processChanUpdate(msg, peerID)
+
+	default:
+		return fmt.Errorf("unknown announcement: %T", msg)
+	}
+}
+
+// ProcessLocalAnnouncement adds the announcement of one of our own channels,
+// assembled from the signatures exchanged with its peer, to both the graph
+// and the next batch to be rebroadcast. As half of its signatures are the
+// peer's, they're verified as those of any other announcement.
+func (d *Gossiper) ProcessLocalAnnouncement(ann *lnwire.ChannelAnnouncement) error {
+	if err := d.verifyChanAnn(ann); err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	return d.processChanAnn(ann, localPeerID)
+}
+
+// ProcessPrivateChannel adds one of our private channels to the graph, so
+// that we're able to route over it. As the channel is never announced, its
+// announcement carries no signatures, nor is it rebroadcast.
+func (d *Gossiper) ProcessPrivateChannel(ann *lnwire.ChannelAnnouncement) error {
+	d.Lock()
+	defer d.Unlock()
+
+	existing, err := d.cfg.Graph.FetchChannelEdge(ann.ShortChannelID)
+	if err != nil || existing != nil {
+		return err
+	}
+
+	chanPoint, capacity, err := d.cfg.FetchFundingOutput(ann.ShortChannelID)
+	if err != nil {
+		return err
+	}
+	err = d.cfg.Graph.AddPrivateChannelEdge(ann, chanPoint, capacity)
+	if err != nil {
+		return err
+	}
+
+	d.cfg.Notifier.notify(&TopologyChange{
+		NewChannels: []*NewChannel{{
+			Announcement: ann,
+			ChannelPoint: *chanPoint,
+			Capacity:     capacity,
+		}},
+	})
+
+	return nil
+}
+
+// ProcessLocalUpdate adds a channel update of our own to both the graph and
+// the next batch to be rebroadcast. As we signed the update ourselves, it's
+// neither rate limited, nor is its signature verified. Updates for private
+// channels are sent directly to the channel's peer instead.
+func (d *Gossiper) ProcessLocalUpdate(update *lnwire.ChannelUpdate) error {
+	d.Lock()
+	defer d.Unlock()
+
+	ann, err := d.cfg.Graph.FetchChannelEdge(update.ShortChannelID)
+	if err != nil {
+		return err
+	}
+	if ann == nil {
+		return ErrUnknownChannel
+	}
+
+	known, err := d.checkStaleUpdate(update)
+	if err != nil || known {
+		return err
+	}
+	if err := d.cfg.Graph.UpdateEdgePolicy(update); err != nil {
+		return err
+	}
+
+	d.cfg.Notifier.notify(&TopologyChange{
+		ChannelUpdates: []*ChannelEdgeUpdate{{
+			Announcement: ann,
+			Update:       update,
+		}},
+	})
+
+	private, err := d.cfg.Graph.IsPrivateChannel(update.ShortChannelID)
+	if err != nil {
+		return err
+	}
+	if private {
+		peerKey := ann.NodeID2
+		if update.Direction() == 1 {
+			peerKey = ann.NodeID1
+		}
+		return d.cfg.SendToPeer(peerKey, update)
+	}
+
+	d.batch.addChanUpdate(update, localPeerID)
+	return nil
+}
+
+// prematureMsg is an announcement received before we synced to the chain.
+type prematureMsg struct {
+	msg    lnwire.Message
+	peerID int32
+}
+
+// queuePremature queues the announcement should we be yet to sync to the
+// chain, returning true if it was queued. ErrChainNotSynced is returned if
+// the queue is full.
+func (d *Gossiper) queuePremature(msg lnwire.Message, peerID int32) (bool, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.chainSynced {
+		return false, nil
+	}
+	if len(d.premature) >= maxPrematureAnnouncements {
+		return false, ErrChainNotSynced
+	}
+
+	d.premature = append(d.premature, &prematureMsg{msg, peerID})
+	return true, nil
+}
+
+// processPremature processes the announcements queued before we synced to
+// the chain, in the order received. Announcements received meanwhile are
+// queued behind them, so updates never overtake the announcements of their
+// channels.
+func (d *Gossiper) processPremature() {
+	for {
+		d.Lock()
+		queued := d.premature
+		d.premature = nil
+		if len(queued) == 0 {
+			d.chainSynced = true
+		}
+		d.Unlock()
+
+		if len(queued) == 0 {
+			return
+		}
+
+		for _, premature := range queued {
+			err := d.processRemoteAnnouncement(premature.msg,
+				premature.peerID)
+			if err != nil {
+				fmt.Printf("rejected queued %v from peer %v: "+
+					"%v\n", premature.msg.Command(),
+					premature.peerID, err)
+			}
+		}
+	}
+}
+
+// RemovePeer drops the rate limiter of a disconnected peer.
+func (d *Gossiper) RemovePeer(peerID int32) {
+	d.Lock()
+	delete(d.limiters, peerID)
+	d.Unlock()
+}
+
+// verifyChanAnn verifies the signatures of both nodes, and both funding
+// keys, over the announcement, each by a worker of the sig pool. The
+// signatures of an announcement already verified aren't verified again.
+func (d *Gossiper) verifyChanAnn(ann *lnwire.ChannelAnnouncement) error {
+	hash, err := msgHash(ann)
+	if err != nil {
+		return err
+	}
+	if d.sigCache.contains(hash) {
+		return nil
+	}
+
+	data, err := ann.DataToSign()
+	if err != nil {
+		return err
+	}
+	digest := wire.DoubleSha256(data)
+	sigMsg := func() ([]byte, error) {
+		return digest, nil
+	}
+
+	newJob := func(pubKey *btcec.PublicKey, sig *btcec.Signature) *sigpool.VerifyJob {
+		return &sigpool.VerifyJob{
+			PubKey: pubKey,
+			Sig:    sig,
+			SigMsg: sigMsg,
+		}
+	}
+	err = d.cfg.SigPool.VerifySigs([]*sigpool.VerifyJob{
+		newJob(ann.NodeID1, ann.NodeSig1),
+		newJob(ann.NodeID2, ann.NodeSig2),
+		newJob(ann.BitcoinKey1, ann.BitcoinSig1),
+		newJob(ann.BitcoinKey2, ann.BitcoinSig2),
+	})
+	switch err {
+	case nil:
+		d.sigCache.add(hash)
+		return nil
+	case sigpool.ErrInvalidSig:
+		return ErrInvalidAnnouncementSig
+	default:
+		return err
+	}
+}
+
+// processChanAnn adds a newly announced channel to the graph. Its
+// signatures MUST already have been verified.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (d *Gossiper) processChanAnn(ann *lnwire.ChannelAnnouncement,
+	peerID int32) error {
+
+	existing, err := d.cfg.Graph.FetchChannelEdge(ann.ShortChannelID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		// If the announcement is yet to be rebroadcast, there's no
+		// need to send it back to this peer either.
+		d.batch.addSender(ann.ShortChannelID, peerID)
+		return nil
+	}
+
+	// Only channels which are still open are added, so that those we've
+	// pruned aren't added back by peers yet to prune them.
+	//
+	// TODO: verify that the funding output pays to the
+	// announced bitcoin keys
+	chanPoint, capacity, err := d.cfg.FetchFundingOutput(ann.ShortChannelID)
+	if err != nil {
+		return err
+	}
+	err = d.cfg.Graph.AddChannelEdge(ann, chanPoint, capacity)
+	if err != nil {
+		return err
+	}
+
+	d.cfg.Notifier.notify(&TopologyChange{
+		NewChannels: []*NewChannel{{
+			Announcement: ann,
+			ChannelPoint: *chanPoint,
+			Capacity:     capacity,
+		}},
+	})
+
+	d.batch.addChanAnn(ann, peerID)
+	return nil
+}
+
+// processChanUpdate validates a channel update, storing it as the latest
+// for its channel and direction. The mutex is released while the signature
+// of the update is verified.
+//
+// NOTE: The mutex MUST NOT be held when calling this method.
+func (d *Gossiper) processChanUpdate(update *lnwire.ChannelUpdate,
+	peerID int32) error {
+
+	d.Lock()
+	ann, err := d.checkChanUpdate(update, peerID)
+	d.Unlock()
+	if err != nil || ann == nil {
+		return err
+	}
+
+	if err := d.verifyChanUpdate(update, ann); err != nil {
+		return err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	// A newer update may have been stored while verifying this one, or
+	// the same update relayed by another peer.
+	known, err := d.checkStaleUpdate(update)
+	switch {
+	case err != nil:
+		return err
+	case known:
+		d.batch.addUpdateSender(update, peerID)
+		return nil
+	}
+	if err := d.cfg.Graph.UpdateEdgePolicy(update); err != nil {
+		return err
+	}
+
+	d.cfg.Notifier.notify(&TopologyChange{
+		ChannelUpdates: []*ChannelEdgeUpdate{{
+			Announcement: ann,
+			Update:       update,
+		}},
+	})
+
+	// The updates of our peers for our private channels are kept to
+	// ourselves.
+	private, err := d.cfg.Graph.IsPrivateChannel(update.ShortChannelID)
+	if err != nil || private {
+		return err
+	}
+
+	d.batch.addChanUpdate(update, peerID)
+	return nil
+}
+
+// verifyChanUpdate verifies the signature of the update by the node of its
+// direction within the announced channel. The signature of an update already
+// verified isn't verified again.
+func (d *Gossiper) verifyChanUpdate(update *lnwire.ChannelUpdate,
+	ann *lnwire.ChannelAnnouncement) error {
+
+	hash, err := msgHash(update)
+	if err != nil {
+		return err
+	}
+	if d.sigCache.contains(hash) {
+		return nil
+	}
+
+	nodeKey := ann.NodeID1
+	if update.Direction() == 1 {
+		nodeKey = ann.NodeID2
+	}
+	err = d.cfg.SigPool.VerifySigs([]*sigpool.VerifyJob{{
+		PubKey: nodeKey,
+		Sig:    update.Signature,
+		SigMsg: func() ([]byte, error) {
+			data, err := update.DataToSign()
+			if err != nil {
+				return nil, err
+			}
+			return wire.DoubleSha256(data), nil
+		},
+	}})
+	switch {
+	case err == sigpool.ErrInvalidSig:
+		return ErrInvalidUpdateSig
+	case err != nil:
+		return err
+	}
+
+	d.sigCache.add(hash)
+	return nil
+}
+
+// checkChanUpdate applies the checks of a channel update which don't
+// require its signature, returning the announcement of its channel. If the
+// update is already pending rebroadcast, a nil announcement is returned.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (d *Gossiper) checkChanUpdate(update *lnwire.ChannelUpdate,
+	peerID int32) (*lnwire.ChannelAnnouncement, error) {
+
+	limiter, ok := d.limiters[peerID]
+	if !ok {
+		limiter = newRateLimiter(d.cfg.UpdateRate, d.cfg.UpdateBurst)
+		d.limiters[peerID] = limiter
+	}
+	now := time.Now()
+	if !limiter.allow(now) {
+		return nil, ErrGossipRateLimited
+	}
+
+	timestamp := time.Unix(int64(update.Timestamp), 0)
+	if timestamp.After(now.Add(maxFutureUpdate)) {
+		return nil, ErrFutureUpdate
+	}
+
+	// The same update is likely to arrive from several peers before
+	// we've rebroadcast it, none of which need it sent back to them.
+	if d.batch.addUpdateSender(update, peerID) {
+		return nil, nil
+	}
+
+	ann, err := d.cfg.Graph.FetchChannelEdge(update.ShortChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if ann == nil {
+		return nil, ErrUnknownChannel
+	}
+
+	// An update already within the graph, relayed by a further peer, is
+	// accepted without being verified again.
+	known, err := d.checkStaleUpdate(update)
+	if err != nil || known {
+		return nil, err
+	}
+
+	return ann, nil
+}
+
+// checkStaleUpdate returns ErrStaleUpdate if the update is no newer than the
+// latest stored for its channel and direction. If the update is the one
+// stored, true is returned instead.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (d *Gossiper) checkStaleUpdate(update *lnwire.ChannelUpdate) (bool, error) {
+	latest, err := d.cfg.Graph.FetchEdgePolicy(update.ShortChannelID,
+		update.Direction())
+	if err != nil {
+		return false, err
+	}
+	if latest == nil || update.Timestamp > latest.Timestamp {
+		return false, nil
+	}
+
+	if update.Timestamp == latest.Timestamp {
+		latestHash, err := msgHash(latest)
+		if err != nil {
+			return false, err
+		}
+		hash, err := msgHash(update)
+		if err != nil {
+			return false, err
+		}
+		if hash == latestHash {
+			return true, nil
+		}
+	}
+
+	return false, ErrStaleUpdate
+}
+
+// networkHandler rebroadcasts the pending batch of accepted announcements
+// each time the trickle timer fires, and processes the announcements queued
+// until we synced to the chain once we have.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *Gossiper) networkHandler() {
+	defer d.wg.Done()
+
+	trickleTicker := time.NewTicker(d.cfg.TrickleDelay)
+	defer trickleTicker.Stop()
+
+	// Once we've synced to the chain, the queued announcements are
+	// processed, and the channel no longer selected.
+	chainSynced := d.cfg.ChainSynced
+	for {
+		select {
+		case <-chainSynced:
+			d.processPremature()
+			chainSynced = nil
+
+		case <-trickleTicker.C:
+			d.Lock()
+			msgs := d.batch.flush()
+			d.Unlock()
+
+			for _, msg := range msgs {
+				err := d.cfg.Broadcast(msg.senders, msg.msg)
+				if err != nil {
+					fmt.Printf("unable to broadcast %v: "+
+						"%v\n", msg.msg.Command(), err)
+				}
+			}
+
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// batchedMsg is an announcement awaiting rebroadcast, along with the peers
+// which sent it to us.
+type batchedMsg struct {
+	msg     lnwire.Message
+	senders map[int32]struct{}
+}
+
+// updateKey identifies the direction of a channel a channel update is for.
+type updateKey struct {
+	chanID    lnwire.ShortChannelID
+	direction uint8
+}
+
+// announcementBatch holds the announcements accepted since the last
+// rebroadcast. Only the latest update for each direction of each channel is
+// kept.
+type announcementBatch struct {
+	chanAnns    map[lnwire.ShortChannelID]*batchedMsg
+	chanUpdates map[updateKey]*batchedMsg
+}
+
+// newAnnouncementBatch returns an empty announcementBatch.
+func newAnnouncementBatch() *announcementBatch {
+	return &announcementBatch{
+		chanAnns:    make(map[lnwire.ShortChannelID]*batchedMsg),
+		chanUpdates: make(map[updateKey]*batchedMsg),
+	}
+}
+
+// addChanAnn adds the channel announcement to the batch.
+func (b *announcementBatch) addChanAnn(ann *lnwire.ChannelAnnouncement,
+	sender int32) {
+
+	b.chanAnns[ann.ShortChannelID] = &batchedMsg{
+		msg:     ann,
+		senders: map[int32]struct{}{sender: {}},
+	}
+}
+
+// addSender records that the peer also sent us the batched announcement of
+// the channel, if there is one.
+func (b *announcementBatch) addSender(chanID lnwire.ShortChannelID,
+	sender int32) {
+
+	if batched, ok := b.chanAnns[chanID]; ok {
+		batched.senders[sender] = struct{}{}
+	}
+}
+
+// addChanUpdate adds the channel update to the batch, replacing any older
+// update for the same channel and direction.
+func (b *announcementBatch) addChanUpdate(update *lnwire.ChannelUpdate,
+	sender int32) {
+
+	key := updateKey{update.ShortChannelID, update.Direction()}
+	b.chanUpdates[key] = &batchedMsg{
+		msg:     update,
+		senders: map[int32]struct{}{sender: {}},
+	}
+}
+
+// addUpdateSender records that the peer also sent us the batched channel
+// update, returning false if the batch doesn't hold the same update.
+func (b *announcementBatch) addUpdateSender(update *lnwire.ChannelUpdate,
+	sender int32) bool {
+
+	key := updateKey{update.ShortChannelID, update.Direction()}
+	batched, ok := b.chanUpdates[key]
+	if !ok {
+		return false
+	}
+
+	// Only an identical update counts, as this one has yet to be
+	// validated.
+	var batchedBytes, updateBytes bytes.Buffer
+	if err := batched.msg.Encode(&batchedBytes, 0); err != nil {
+		return false
+	}
+	if err := update.Encode(&updateBytes, 0); err != nil {
+		return false
+	}
+	if !bytes.Equal(batchedBytes.Bytes(), updateBytes.Bytes()) {
+		return false
+	}
+
+	batched.senders[sender] = struct{}{}
+	return true
+}
+
+// flush empties the batch, returning its contents. Channel announcements
+// come first, so no peer receives an update for a channel it doesn't yet
+// know of.
+func (b *announcementBatch) flush() []*batchedMsg {
+	msgs := make([]*batchedMsg, 0, len(b.chanAnns)+len(b.chanUpdates))
+	for _, batched := range b.chanAnns {
+		msgs = append(msgs, batched)
+	}
+	for _, batched := range b.chanUpdates {
+		msgs = append(msgs, batched)
+	}
+
+	b.chanAnns = make(map[lnwire.ShortChannelID]*batchedMsg)
+	b.chanUpdates = make(map[updateKey]*batchedMsg)
+
+	return msgs
+}
+
+// rateLimiter is a token bucket, refilled at a fixed rate up to its burst
+// size, with a token spent on each message.
+type rateLimiter struct {
+	rate      float64
+	burst     float64
+	tokens    float64
+	lastCheck time.Time
+}
+
+// newRateLimiter returns a full rateLimiter.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		lastCheck: time.Now(),
+	}
+}
+
+// allow spends a token, returning false if none remain.
+func (r *rateLimiter) allow(now time.Time) bool {
+	if elapsed := now.Sub(r.lastCheck); elapsed > 0 {
+		r.tokens = math.Min(r.burst,
+			r.tokens+elapsed.Seconds()*r.rate)
+	}
+	r.lastCheck = now
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
@@ -0,0 +1,630 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sigpool"
+)
+
+var (
+	testChanID   = lnwire.ShortChannelID{BlockHeight: 1000, TxIndex: 4}
+	testCapacity = btcutil.Amount(1e6)
+
+	nodePriv1, nodeKey1 = btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{0x01}, 32))
+	nodePriv2, nodeKey2 = btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{0x02}, 32))
+)
+
+// testFundingOutput returns a unique funding outpoint for each channel.
+func testFundingOutput(chanID lnwire.ShortChannelID) (*wire.OutPoint, btcutil.Amount, error) {
+	var op wire.OutPoint
+	binary.BigEndian.PutUint64(op.Hash[:], chanID.ToUint64())
+	return &op, testCapacity, nil
+}
+
+// testChanAnn returns a signed announcement of the test channel between the
+// two test nodes. For simplicity, each node's identity key is also its
+// funding key.
+func testChanAnn(t *testing.T) *lnwire.ChannelAnnouncement {
+	ann := &lnwire.ChannelAnnouncement{
+		ShortChannelID: testChanID,
+		NodeID1:        nodeKey1,
+		NodeID2:        nodeKey2,
+	}
+	if bytes.Compare(nodeKey1.SerializeCompressed(),
+		nodeKey2.SerializeCompressed()) > 0 {
+		ann.NodeID1, ann.NodeID2 = nodeKey2, nodeKey1
+	}
+	ann.BitcoinKey1, ann.BitcoinKey2 = ann.NodeID1, ann.NodeID2
+
+	data, err := ann.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to serialize announcement: %v", err)
+	}
+	digest := wire.DoubleSha256(data)
+	priv1, priv2 := nodePrivs(ann)
+	sig1, err := priv1.Sign(digest)
+	if err != nil {
+		t.Fatalf("unable to sign announcement: %v", err)
+	}
+	sig2, err := priv2.Sign(digest)
+	if err != nil {
+		t.Fatalf("unable to sign announcement: %v", err)
+	}
+
+	ann.NodeSig1, ann.BitcoinSig1 = sig1, sig1
+	ann.NodeSig2, ann.BitcoinSig2 = sig2, sig2
+	return ann
+}
+
+// startSigPool returns a started sig pool, which the caller must stop.
+func startSigPool(t *testing.T) *sigpool.SigPool {
+	pool := sigpool.NewSigPool(0)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start sig pool: %v", err)
+	}
+	return pool
+}
+
+// signedUpdate returns a channel update of the test channel, signed with
+// the passed key.
+func signedUpdate(t *testing.T, priv *btcec.PrivateKey, direction uint16,
+	timestamp time.Time, baseFee uint32) *lnwire.ChannelUpdate {
+
+	update := &lnwire.ChannelUpdate{
+		ShortChannelID: testChanID,
+		Timestamp:      uint32(timestamp.Unix()),
+		Flags:          direction,
+		TimeLockDelta:  144,
+		BaseFee:        baseFee,
+		FeeRate:        1,
+	}
+	data, err := update.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to serialize update: %v", err)
+	}
+	update.Signature, err = priv.Sign(wire.DoubleSha256(data))
+	if err != nil {
+		t.Fatalf("unable to sign update: %v", err)
+	}
+	return update
+}
+
+// nodePrivs returns the private keys of NodeID1 and NodeID2 of the test
+// channel announcement.
+func nodePrivs(ann *lnwire.ChannelAnnouncement) (*btcec.PrivateKey, *btcec.PrivateKey) {
+	if ann.NodeID1.IsEqual(nodeKey1) {
+		return nodePriv1, nodePriv2
+	}
+	return nodePriv2, nodePriv1
+}
+
+// TestGossiperChanUpdateValidation ensures that stale, future, unsigned, and
+// unknown channel updates are rejected.
+func TestGossiperChanUpdateValidation(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	graph := newMockGraph()
+	d := NewGossiper(&GossiperCfg{
+		Graph:              graph,
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		UpdateRate:         DefaultUpdateRate,
+		UpdateBurst:        DefaultUpdateBurst,
+		SigPool:            pool,
+	})
+
+	now := time.Now()
+	ann := testChanAnn(t)
+	priv1, priv2 := nodePrivs(ann)
+
+	// Until the channel is announced, updates for it are rejected.
+	update := signedUpdate(t, priv1, 0, now, 1000)
+	if err := d.ProcessRemoteAnnouncement(update, 1); err != ErrUnknownChannel {
+		t.Fatalf("expected ErrUnknownChannel, got %v", err)
+	}
+
+	// Nor are announcements with any invalid signature.
+	forgedAnn := *ann
+	forgedAnn.BitcoinSig2 = forgedAnn.BitcoinSig1
+	if err := d.ProcessRemoteAnnouncement(&forgedAnn, 1); err != ErrInvalidAnnouncementSig {
+		t.Fatalf("expected ErrInvalidAnnouncementSig, got %v", err)
+	}
+
+	// Nor are channels whose funding output can't be found.
+	errSpent := errors.New("funding output spent")
+	d.cfg.FetchFundingOutput = func(lnwire.ShortChannelID) (*wire.OutPoint, btcutil.Amount, error) {
+		return nil, 0, errSpent
+	}
+	if err := d.ProcessRemoteAnnouncement(ann, 1); err != errSpent {
+		t.Fatalf("expected errSpent, got %v", err)
+	}
+	d.cfg.FetchFundingOutput = testFundingOutput
+
+	if err := d.ProcessRemoteAnnouncement(ann, 1); err != nil {
+		t.Fatalf("unable to process announcement: %v", err)
+	}
+	if err := d.ProcessRemoteAnnouncement(update, 1); err != nil {
+		t.Fatalf("unable to process update: %v", err)
+	}
+
+	// The same update relayed by another peer is accepted, but an older
+	// one isn't.
+	if err := d.ProcessRemoteAnnouncement(update, 2); err != nil {
+		t.Fatalf("unable to process duplicate update: %v", err)
+	}
+	stale := signedUpdate(t, priv1, 0, now.Add(-time.Minute), 2000)
+	if err := d.ProcessRemoteAnnouncement(stale, 1); err != ErrStaleUpdate {
+		t.Fatalf("expected ErrStaleUpdate, got %v", err)
+	}
+
+	future := signedUpdate(t, priv1, 0, now.Add(2*maxFutureUpdate), 2000)
+	if err := d.ProcessRemoteAnnouncement(future, 1); err != ErrFutureUpdate {
+		t.Fatalf("expected ErrFutureUpdate, got %v", err)
+	}
+
+	// An update must be signed by the node of its direction.
+	forged := signedUpdate(t, priv2, 0, now.Add(time.Minute), 2000)
+	if err := d.ProcessRemoteAnnouncement(forged, 1); err != ErrInvalidUpdateSig {
+		t.Fatalf("expected ErrInvalidUpdateSig, got %v", err)
+	}
+	other := signedUpdate(t, priv2, lnwire.ChanUpdateDirection, now, 2000)
+	if err := d.ProcessRemoteAnnouncement(other, 1); err != nil {
+		t.Fatalf("unable to process update: %v", err)
+	}
+
+	latest, _ := graph.FetchEdgePolicy(testChanID, 0)
+	if latest.BaseFee != 1000 {
+		t.Fatalf("rejected update was stored")
+	}
+	latest, _ = graph.FetchEdgePolicy(testChanID, 1)
+	if latest.BaseFee != 2000 {
+		t.Fatalf("update for second direction wasn't stored")
+	}
+}
+
+// TestGossiperTrickleBatch ensures that accepted announcements are
+// rebroadcast together, with superseded updates dropped, and without being
+// sent back to the peers they came from.
+func TestGossiperTrickleBatch(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	type broadcast struct {
+		skip map[int32]struct{}
+		msgs []lnwire.Message
+	}
+	broadcasts := make(chan broadcast, 10)
+
+	d := NewGossiper(&GossiperCfg{
+		Graph:              newMockGraph(),
+		FetchFundingOutput: testFundingOutput,
+		Broadcast: func(skip map[int32]struct{}, msgs ...lnwire.Message) error {
+			broadcasts <- broadcast{skip, msgs}
+			return nil
+		},
+		TrickleDelay: 10 * time.Millisecond,
+		UpdateRate:   DefaultUpdateRate,
+		UpdateBurst:  DefaultUpdateBurst,
+		SigPool:      pool,
+	})
+
+	now := time.Now()
+	ann := testChanAnn(t)
+	priv1, _ := nodePrivs(ann)
+	first := signedUpdate(t, priv1, 0, now.Add(-time.Minute), 1000)
+	second := signedUpdate(t, priv1, 0, now, 2000)
+
+	for _, msg := range []struct {
+		msg    lnwire.Message
+		peerID int32
+	}{
+		{ann, 1}, {ann, 2}, {first, 1}, {second, 2}, {second, 3},
+	} {
+		if err := d.ProcessRemoteAnnouncement(msg.msg, msg.peerID); err != nil {
+			t.Fatalf("unable to process %T: %v", msg.msg, err)
+		}
+	}
+
+	d.Start()
+	defer d.Stop()
+
+	expect := func(msg lnwire.Message, skip ...int32) {
+		select {
+		case b := <-broadcasts:
+			if len(b.msgs) != 1 || b.msgs[0] != msg {
+				t.Fatalf("unexpected broadcast: %v", b.msgs)
+			}
+			if len(b.skip) != len(skip) {
+				t.Fatalf("expected to skip %v, skipped %v",
+					skip, b.skip)
+			}
+			for _, peerID := range skip {
+				if _, ok := b.skip[peerID]; !ok {
+					t.Fatalf("broadcast to sender %v",
+						peerID)
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no broadcast")
+		}
+	}
+	expect(ann, 1, 2)
+	expect(second, 2, 3)
+
+	select {
+	case b := <-broadcasts:
+		t.Fatalf("unexpected broadcast: %v", b.msgs)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestGossiperDuplicates ensures that announcements already verified, or
+// already within the graph, are accepted from further peers without their
+// signatures being verified again.
+func TestGossiperDuplicates(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	d := NewGossiper(&GossiperCfg{
+		Graph:              newMockGraph(),
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		UpdateRate:         DefaultUpdateRate,
+		UpdateBurst:        DefaultUpdateBurst,
+		SigPool:            pool,
+	})
+
+	now := time.Now()
+	ann := testChanAnn(t)
+	priv1, _ := nodePrivs(ann)
+	update := signedUpdate(t, priv1, 0, now, 1000)
+	for _, msg := range []lnwire.Message{ann, update} {
+		if err := d.ProcessRemoteAnnouncement(msg, 1); err != nil {
+			t.Fatalf("unable to process %T: %v", msg, err)
+		}
+	}
+
+	// Once rebroadcast, the messages are no longer batched, yet with the
+	// sig pool stopped they're still accepted from another peer.
+	d.Lock()
+	d.batch.flush()
+	d.Unlock()
+	pool.Stop()
+
+	for _, msg := range []lnwire.Message{ann, update} {
+		if err := d.ProcessRemoteAnnouncement(msg, 2); err != nil {
+			t.Fatalf("unable to process duplicate %T: %v", msg, err)
+		}
+	}
+
+	// An update with the same timestamp, but differing otherwise, is
+	// still stale.
+	conflict := signedUpdate(t, priv1, 0, now, 2000)
+	if err := d.ProcessRemoteAnnouncement(conflict, 2); err != ErrStaleUpdate {
+		t.Fatalf("expected ErrStaleUpdate, got %v", err)
+	}
+
+	// While a new update must be verified.
+	newer := signedUpdate(t, priv1, 0, now.Add(time.Minute), 2000)
+	if err := d.ProcessRemoteAnnouncement(newer, 2); err != sigpool.ErrPoolExiting {
+		t.Fatalf("expected ErrPoolExiting, got %v", err)
+	}
+}
+
+// TestGossiperRateLimit ensures that peers sending updates faster than
+// their rate limit are throttled, without affecting other peers.
+func TestGossiperRateLimit(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	graph := newMockGraph()
+	graph.addAnn(testChanAnn(t))
+	d := NewGossiper(&GossiperCfg{
+		Graph:              graph,
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		UpdateRate:         0.001,
+		UpdateBurst:        2,
+		SigPool:            pool,
+	})
+
+	ann := testChanAnn(t)
+	priv1, _ := nodePrivs(ann)
+	update := signedUpdate(t, priv1, 0, time.Now(), 1000)
+
+	for i := 0; i < 2; i++ {
+		if err := d.ProcessRemoteAnnouncement(update, 1); err != nil {
+			t.Fatalf("unable to process update: %v", err)
+		}
+	}
+	if err := d.ProcessRemoteAnnouncement(update, 1); err != ErrGossipRateLimited {
+		t.Fatalf("expected ErrGossipRateLimited, got %v", err)
+	}
+	if err := d.ProcessRemoteAnnouncement(update, 2); err != nil {
+		t.Fatalf("unable to process update: %v", err)
+	}
+
+	// Once disconnected, the peer starts afresh.
+	d.RemovePeer(1)
+	if err := d.ProcessRemoteAnnouncement(update, 1); err != nil {
+		t.Fatalf("unable to process update: %v", err)
+	}
+}
+
+// TestGossiperLocalUpdate ensures that our own updates are accepted without
+// being rate limited, or verified, while stale updates are still rejected.
+func TestGossiperLocalUpdate(t *testing.T) {
+	graph := newMockGraph()
+	ann := testChanAnn(t)
+	graph.addAnn(ann)
+	d := NewGossiper(&GossiperCfg{
+		Graph:              graph,
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		UpdateRate:         0.001,
+		UpdateBurst:        1,
+	})
+
+	// Without a sig pool, our own updates are still accepted, however
+	// many we make.
+	now := time.Now()
+	priv1, _ := nodePrivs(ann)
+	for i := 0; i < 3; i++ {
+		timestamp := now.Add(time.Duration(i) * time.Second)
+		update := signedUpdate(t, priv1, lnwire.ChanUpdateDisabled,
+			timestamp, 1000)
+		if err := d.ProcessLocalUpdate(update); err != nil {
+			t.Fatalf("unable to process local update: %v", err)
+		}
+
+		latest, err := graph.FetchEdgePolicy(testChanID, 0)
+		if err != nil {
+			t.Fatalf("unable to fetch policy: %v", err)
+		}
+		if latest != update {
+			t.Fatalf("local update not stored")
+		}
+	}
+
+	stale := signedUpdate(t, priv1, 0, now, 2000)
+	if err := d.ProcessLocalUpdate(stale); err != ErrStaleUpdate {
+		t.Fatalf("expected ErrStaleUpdate, got %v", err)
+	}
+
+	unknown := signedUpdate(t, priv1, 0, now.Add(time.Minute), 2000)
+	unknown.ShortChannelID.TxIndex++
+	if err := d.ProcessLocalUpdate(unknown); err != ErrUnknownChannel {
+		t.Fatalf("expected ErrUnknownChannel, got %v", err)
+	}
+
+	d.Lock()
+	batch := d.batch.flush()
+	d.Unlock()
+	if len(batch) != 1 {
+		t.Fatalf("expected one batched update, got %v", len(batch))
+	}
+}
+
+// TestGossiperLocalAnnouncement ensures that the announcement of our own
+// channel is added to the graph, and batched, only if all of its signatures
+// are valid.
+func TestGossiperLocalAnnouncement(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	graph := newMockGraph()
+	d := NewGossiper(&GossiperCfg{
+		Graph:              graph,
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		SigPool:            pool,
+	})
+
+	// An announcement with a bad signature from the peer is rejected.
+	ann := testChanAnn(t)
+	invalid := *ann
+	invalid.BitcoinSig2 = invalid.BitcoinSig1
+	err := d.ProcessLocalAnnouncement(&invalid)
+	if err != ErrInvalidAnnouncementSig {
+		t.Fatalf("expected ErrInvalidAnnouncementSig, got %v", err)
+	}
+
+	if err := d.ProcessLocalAnnouncement(ann); err != nil {
+		t.Fatalf("unable to process local announcement: %v", err)
+	}
+	stored, err := graph.FetchChannelEdge(testChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch channel: %v", err)
+	}
+	if stored != ann {
+		t.Fatalf("local announcement not stored")
+	}
+
+	d.Lock()
+	batch := d.batch.flush()
+	d.Unlock()
+	if len(batch) != 1 {
+		t.Fatalf("expected one batched announcement, got %v", len(batch))
+	}
+}
+
+// TestGossiperPrivateChannel ensures that a private channel is added to the
+// graph without signatures, and that channel updates for it are only ever
+// sent to its peer.
+func TestGossiperPrivateChannel(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	// We're NodeID1 of the channel, and our peer NodeID2.
+	ann := testChanAnn(t)
+
+	var sent []lnwire.Message
+	graph := newMockGraph()
+	d := NewGossiper(&GossiperCfg{
+		Graph:              graph,
+		FetchFundingOutput: testFundingOutput,
+		SendToPeer: func(nodeKey *btcec.PublicKey, msgs ...lnwire.Message) error {
+			if !nodeKey.IsEqual(ann.NodeID2) {
+				t.Fatalf("update sent to the wrong peer")
+			}
+			sent = append(sent, msgs...)
+			return nil
+		},
+		TrickleDelay: time.Hour,
+		UpdateRate:   DefaultUpdateRate,
+		UpdateBurst:  DefaultUpdateBurst,
+		SigPool:      pool,
+	})
+
+	unsigned := &lnwire.ChannelAnnouncement{
+		ShortChannelID: ann.ShortChannelID,
+		NodeID1:        ann.NodeID1,
+		NodeID2:        ann.NodeID2,
+		BitcoinKey1:    ann.BitcoinKey1,
+		BitcoinKey2:    ann.BitcoinKey2,
+	}
+	if err := d.ProcessPrivateChannel(unsigned); err != nil {
+		t.Fatalf("unable to process private channel: %v", err)
+	}
+	if private, _ := graph.IsPrivateChannel(testChanID); !private {
+		t.Fatalf("private channel not stored")
+	}
+
+	// Our own update is sent to the peer, rather than batched, as is
+	// theirs kept from the batch.
+	now := time.Now()
+	priv1, priv2 := nodePrivs(ann)
+	ours := signedUpdate(t, priv1, 0, now, 1000)
+	if err := d.ProcessLocalUpdate(ours); err != nil {
+		t.Fatalf("unable to process local update: %v", err)
+	}
+	if len(sent) != 1 || sent[0] != ours {
+		t.Fatalf("expected our update to be sent to the peer")
+	}
+
+	theirs := signedUpdate(t, priv2, lnwire.ChanUpdateDirection, now, 1000)
+	if err := d.ProcessRemoteAnnouncement(theirs, 1); err != nil {
+		t.Fatalf("unable to process remote update: %v", err)
+	}
+
+	d.Lock()
+	batch := d.batch.flush()
+	d.Unlock()
+	if len(batch) != 0 {
+		t.Fatalf("expected nothing batched, got %v", len(batch))
+	}
+}
+
+// TestGossiperChainSync ensures that announcements received before we've
+// synced to the chain are queued, then processed in order once we have.
+func TestGossiperChainSync(t *testing.T) {
+	pool := startSigPool(t)
+	defer pool.Stop()
+
+	graph := newMockGraph()
+	chainSynced := make(chan struct{})
+	d := NewGossiper(&GossiperCfg{
+		Graph:              graph,
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		UpdateRate:         DefaultUpdateRate,
+		UpdateBurst:        DefaultUpdateBurst,
+		SigPool:            pool,
+		ChainSynced:        chainSynced,
+	})
+	if err := d.Start(); err != nil {
+		t.Fatalf("unable to start gossiper: %v", err)
+	}
+	defer d.Stop()
+
+	ann := testChanAnn(t)
+	priv1, _ := nodePrivs(ann)
+	update := signedUpdate(t, priv1, 0, time.Now(), 1000)
+	for _, msg := range []lnwire.Message{ann, update} {
+		if err := d.ProcessRemoteAnnouncement(msg, 1); err != nil {
+			t.Fatalf("unable to queue %T: %v", msg, err)
+		}
+	}
+	if graph.numChans() != 0 {
+		t.Fatalf("announcement processed before chain sync")
+	}
+
+	// Once synced, the queued channel announcement is processed before
+	// its update.
+	close(chainSynced)
+	deadline := time.After(5 * time.Second)
+	for {
+		policy, err := graph.FetchEdgePolicy(testChanID, 0)
+		if err != nil {
+			t.Fatalf("unable to fetch policy: %v", err)
+		}
+		if policy != nil {
+			break
+		}
+
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("queued announcements weren't processed")
+		}
+	}
+	if graph.numChans() != 1 {
+		t.Fatalf("expected 1 channel, got %v", graph.numChans())
+	}
+}
+
+// TestGossiperPrematureLimit ensures that announcements received before
+// we've synced to the chain are rejected once the queue is full.
+func TestGossiperPrematureLimit(t *testing.T) {
+	d := NewGossiper(&GossiperCfg{
+		Graph:              newMockGraph(),
+		FetchFundingOutput: testFundingOutput,
+		TrickleDelay:       time.Hour,
+		UpdateRate:         DefaultUpdateRate,
+		UpdateBurst:        DefaultUpdateBurst,
+		ChainSynced:        make(chan struct{}),
+	})
+
+	ann := testChanAnn(t)
+	for i := 0; i < maxPrematureAnnouncements; i++ {
+		if err := d.ProcessRemoteAnnouncement(ann, 1); err != nil {
+			t.Fatalf("unable to queue announcement: %v", err)
+		}
+	}
+	if err := d.ProcessRemoteAnnouncement(ann, 1); err != ErrChainNotSynced {
+		t.Fatalf("expected ErrChainNotSynced, got %v", err)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	r := newRateLimiter(1, 2)
+	r.lastCheck = now
+
+	if !r.allow(now) || !r.allow(now) {
+		t.Fatalf("burst not allowed")
+	}
+	if r.allow(now) {
+		t.Fatalf("allowed beyond burst")
+	}
+
+	// Tokens are refilled at the rate, but never beyond the burst.
+	if !r.allow(now.Add(time.Second)) || r.allow(now.Add(time.Second)) {
+		t.Fatalf("expected a single token after a second")
+	}
+	later := now.Add(time.Hour)
+	if !r.allow(later) || !r.allow(later) || r.allow(later) {
+		t.Fatalf("tokens refilled beyond burst")
+	}
+}
@@ -0,0 +1,228 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ChainView is the access to the chain required to prune the channel graph.
+type ChainView interface {
+	// GetBestBlock returns the hash, and height, of the tip of the main
+	// chain.
+	GetBestBlock() (*wire.ShaHash, int32, error)
+
+	// GetBlockHash returns the hash of the block at the passed height
+	// within the main chain.
+	GetBlockHash(height int64) (*wire.ShaHash, error)
+
+	// GetBlock returns the block of the passed hash.
+	GetBlock(hash *wire.ShaHash) (*wire.MsgBlock, error)
+
+	// RegisterBlockEpochNotification registers a channel which is sent
+	// each new block as it's connected to the main chain.
+	RegisterBlockEpochNotification(epochChan chan *chainntnfs.BlockEpoch) error
+}
+
+// PrunableGraph is the view of the channel graph required to prune closed
+// channels from it.
+type PrunableGraph interface {
+	// PruneGraph removes every channel funded by one of the spent
+	// outpoints, recording the block as the new prune tip.
+	PruneGraph(spentOutputs []*wire.OutPoint, blockHash *wire.ShaHash,
+		blockHeight uint32) ([]lnwire.ShortChannelID, error)
+
+	// PruneTip returns the hash, and height, of the last block the graph
+	// was pruned with, or a nil hash if it has never been pruned.
+	PruneTip() (*wire.ShaHash, uint32, error)
+}
+
+// GraphPruner removes channels from the graph once their funding output is
+// spent. Each new block is scanned for spends of funding outputs, with the
+// last block scanned persisted as the prune tip, so any blocks connected
+// while we were offline are scanned on startup.
+type GraphPruner struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	graph    PrunableGraph
+	chain    ChainView
+	notifier *TopologyNotifier
+
+	// pruneHeight is the height of the last block the graph was pruned
+	// with. It's only accessed by the goroutine of the pruner once
+	// started.
+	pruneHeight uint32
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewGraphPruner returns a new GraphPruner, pruning the graph with blocks
+// from the passed chain. The notifier, which may be nil, is sent the
+// channels closed by each block.
+func NewGraphPruner(graph PrunableGraph, chain ChainView,
+	notifier *TopologyNotifier) *GraphPruner {
+
+	return &GraphPruner{
+		graph:    graph,
+		chain:    chain,
+		notifier: notifier,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start prunes the graph up to the tip of the main chain, then launches the
+// goroutine pruning it with each new block.
+func (p *GraphPruner) Start() error {
+	if !atomic.CompareAndSwapUint32(&p.started, 0, 1) {
+		return nil
+	}
+
+	// We register for new blocks before catching up, so none are missed
+	// in between. Any we've already caught up with are skipped.
+	epochChan := make(chan *chainntnfs.BlockEpoch, 20)
+	if err := p.chain.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+
+	if err := p.catchUp(); err != nil {
+		return err
+	}
+
+	p.wg.Add(1)
+	go p.pruneHandler(epochChan)
+
+	return nil
+}
+
+// Stop signals the pruner to exit, and waits for it to do so.
+func (p *GraphPruner) Stop() error {
+	if !atomic.CompareAndSwapUint32(&p.stopped, 0, 1) {
+		return nil
+	}
+
+	close(p.quit)
+	p.wg.Wait()
+
+	return nil
+}
+
+// catchUp prunes the graph with each block connected since the prune tip.
+// If the graph has never been pruned, the tip of the main chain becomes the
+// prune tip, as the graph can't yet contain any channels closed before it.
+func (p *GraphPruner) catchUp() error {
+	pruneHash, pruneHeight, err := p.graph.PruneTip()
+	if err != nil {
+		return err
+	}
+	bestHash, bestHeight, err := p.chain.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	if pruneHash == nil {
+		_, err := p.graph.PruneGraph(nil, bestHash, uint32(bestHeight))
+		if err != nil {
+			return err
+		}
+		p.pruneHeight = uint32(bestHeight)
+		return nil
+	}
+
+	// TODO: detect the prune tip being reorged out, restoring
+	// any channels closed within the stale blocks
+	p.pruneHeight = pruneHeight
+	return p.pruneToHeight(uint32(bestHeight))
+}
+
+// pruneToHeight prunes the graph with each block after the prune tip, up to
+// and including the block at the passed height.
+func (p *GraphPruner) pruneToHeight(height uint32) error {
+	for p.pruneHeight < height {
+		nextHeight := p.pruneHeight + 1
+		blockHash, err := p.chain.GetBlockHash(int64(nextHeight))
+		if err != nil {
+			return err
+		}
+		if err := p.pruneBlock(blockHash, nextHeight); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneBlock removes the channels whose funding outputs are spent within
+// the block from the graph.
+func (p *GraphPruner) pruneBlock(blockHash *wire.ShaHash, height uint32) error {
+	block, err := p.chain.GetBlock(blockHash)
+	if err != nil {
+		return err
+	}
+
+	var spentOutputs []*wire.OutPoint
+	for _, tx := range block.Transactions {
+		for _, txIn := range tx.TxIn {
+			op := txIn.PreviousOutPoint
+			spentOutputs = append(spentOutputs, &op)
+		}
+	}
+
+	closedChans, err := p.graph.PruneGraph(spentOutputs, blockHash, height)
+	if err != nil {
+		return err
+	}
+	p.pruneHeight = height
+
+	if len(closedChans) == 0 {
+		return nil
+	}
+
+	change := &TopologyChange{}
+	for _, chanID := range closedChans {
+		fmt.Printf("channel %v closed at height %v, pruned from "+
+			"graph\n", chanID, height)
+
+		change.ClosedChannels = append(change.ClosedChannels,
+			&ClosedChannel{ChanID: chanID, ClosedHeight: height})
+	}
+	p.notifier.notify(change)
+
+	return nil
+}
+
+// pruneHandler prunes the graph with each new block.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *GraphPruner) pruneHandler(epochChan chan *chainntnfs.BlockEpoch) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case epoch := <-epochChan:
+			height := uint32(epoch.Height)
+			if height <= p.pruneHeight {
+				continue
+			}
+
+			// Should we have somehow missed any blocks, they're
+			// pruned with before this one.
+			err := p.pruneToHeight(height - 1)
+			if err == nil {
+				err = p.pruneBlock(&epoch.Hash, height)
+			}
+			if err != nil {
+				fmt.Printf("unable to prune graph at height "+
+					"%v: %v\n", height, err)
+			}
+
+		case <-p.quit:
+			return
+		}
+	}
+}
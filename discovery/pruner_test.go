@@ -0,0 +1,183 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// mockChain is an in-memory ChainView.
+type mockChain struct {
+	sync.Mutex
+	blocks    []*wire.MsgBlock
+	epochChan chan *chainntnfs.BlockEpoch
+}
+
+// blockHash returns the hash of the mock block at the height.
+func blockHash(height int) *wire.ShaHash {
+	var hash wire.ShaHash
+	hash[0] = byte(height)
+	hash[1] = 0xff
+	return &hash
+}
+
+// addBlock adds a block to the chain spending the passed outpoints.
+func (c *mockChain) addBlock(spends ...wire.OutPoint) {
+	tx := wire.NewMsgTx()
+	for i := range spends {
+		tx.AddTxIn(wire.NewTxIn(&spends[i], nil))
+	}
+
+	c.Lock()
+	c.blocks = append(c.blocks, &wire.MsgBlock{
+		Transactions: []*wire.MsgTx{tx},
+	})
+	c.Unlock()
+}
+
+func (c *mockChain) GetBestBlock() (*wire.ShaHash, int32, error) {
+	c.Lock()
+	defer c.Unlock()
+	height := len(c.blocks) - 1
+	return blockHash(height), int32(height), nil
+}
+
+func (c *mockChain) GetBlockHash(height int64) (*wire.ShaHash, error) {
+	c.Lock()
+	defer c.Unlock()
+	if int(height) >= len(c.blocks) {
+		return nil, fmt.Errorf("no block at height %v", height)
+	}
+	return blockHash(int(height)), nil
+}
+
+func (c *mockChain) GetBlock(hash *wire.ShaHash) (*wire.MsgBlock, error) {
+	c.Lock()
+	defer c.Unlock()
+	for height, block := range c.blocks {
+		if *blockHash(height) == *hash {
+			return block, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown block %v", hash)
+}
+
+func (c *mockChain) RegisterBlockEpochNotification(epochChan chan *chainntnfs.BlockEpoch) error {
+	c.epochChan = epochChan
+	return nil
+}
+
+// TestGraphPruner ensures that channels are pruned from the graph once
+// their funding output is spent, including within blocks connected while
+// offline.
+func TestGraphPruner(t *testing.T) {
+	graph := newMockGraph()
+	var chanPoints []wire.OutPoint
+	for i := uint32(0); i < 3; i++ {
+		chanID := lnwire.ShortChannelID{BlockHeight: 1, TxIndex: i}
+		chanPoint, capacity, _ := testFundingOutput(chanID)
+		chanPoints = append(chanPoints, *chanPoint)
+		graph.AddChannelEdge(&lnwire.ChannelAnnouncement{
+			ShortChannelID: chanID,
+		}, chanPoint, capacity)
+	}
+
+	// The graph was last pruned at height 1, with the first channel
+	// closed by a later block.
+	chain := &mockChain{}
+	chain.addBlock()
+	chain.addBlock()
+	chain.addBlock(wire.OutPoint{Index: 7}, chanPoints[0])
+	chain.addBlock()
+	graph.pruneHash, graph.pruneHeight = blockHash(1), 1
+
+	notifier := NewTopologyNotifier()
+	defer notifier.Stop()
+	client := notifier.SubscribeTopology()
+
+	p := NewGraphPruner(graph, chain, notifier)
+	if err := p.Start(); err != nil {
+		t.Fatalf("unable to start pruner: %v", err)
+	}
+	defer p.Stop()
+
+	if graph.numChans() != 2 {
+		t.Fatalf("missed blocks weren't pruned with, %d channels "+
+			"remain", graph.numChans())
+	}
+	if _, height, _ := graph.PruneTip(); height != 3 {
+		t.Fatalf("expected prune tip at height 3, got %d", height)
+	}
+
+	// Each closed channel is sent to the client, along with the height
+	// of the block closing it.
+	select {
+	case change := <-client.TopologyChanges:
+		if len(change.ClosedChannels) != 1 {
+			t.Fatalf("expected 1 closed channel, got %d",
+				len(change.ClosedChannels))
+		}
+		closed := change.ClosedChannels[0]
+		if closed.ChanID.TxIndex != 0 || closed.ClosedHeight != 2 {
+			t.Fatalf("wrong channel closed: %v at height %d",
+				closed.ChanID, closed.ClosedHeight)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("closed channel wasn't notified")
+	}
+
+	// If a notification is missed, the block is still pruned with.
+	chain.addBlock(chanPoints[1])
+	chain.addBlock(chanPoints[2])
+	chain.epochChan <- &chainntnfs.BlockEpoch{
+		Hash:   *blockHash(5),
+		Height: 5,
+	}
+
+	timeout := time.After(5 * time.Second)
+	for graph.numChans() != 0 {
+		select {
+		case <-timeout:
+			t.Fatalf("channels weren't pruned, %d remain",
+				graph.numChans())
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	for {
+		if hash, height, _ := graph.PruneTip(); height == 5 &&
+			*hash == *blockHash(5) {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("prune tip wasn't updated")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// TestGraphPrunerFreshGraph ensures that a graph which has never been pruned
+// starts at the tip of the main chain.
+func TestGraphPrunerFreshGraph(t *testing.T) {
+	graph := newMockGraph()
+	chain := &mockChain{}
+	for i := 0; i < 10; i++ {
+		chain.addBlock()
+	}
+
+	p := NewGraphPruner(graph, chain, nil)
+	if err := p.Start(); err != nil {
+		t.Fatalf("unable to start pruner: %v", err)
+	}
+	defer p.Stop()
+
+	hash, height, _ := graph.PruneTip()
+	if height != 9 || *hash != *blockHash(9) {
+		t.Fatalf("expected prune tip at height 9, got %d", height)
+	}
+}
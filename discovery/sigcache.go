@@ -0,0 +1,82 @@
+package discovery
+
+import (
+	"bytes"
+	"container/list"
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// DefaultSigCacheSize is the default number of announcements whose verified
+// signatures are remembered. Each entry is a single hash, so the cache is
+// sized to cover a full exchange of the graph with a peer.
+const DefaultSigCacheSize = 50000
+
+// sigCache is an LRU set of the hashes of announcements whose signatures
+// have been verified. As the hash covers the entire message, signatures
+// included, any message with a matching hash carries the same valid
+// signatures, so they needn't be verified again when the message is relayed
+// by further peers.
+type sigCache struct {
+	capacity int
+
+	sync.Mutex
+	entries map[wire.ShaHash]*list.Element
+	lru     *list.List
+}
+
+// newSigCache creates a cache remembering up to capacity announcements.
+func newSigCache(capacity int) *sigCache {
+	return &sigCache{
+		capacity: capacity,
+		entries:  make(map[wire.ShaHash]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// contains returns true if the announcement of the passed hash has been
+// verified, marking it as recently used.
+func (c *sigCache) contains(hash wire.ShaHash) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[hash]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	return ok
+}
+
+// add records the announcement of the passed hash as verified, evicting the
+// least recently used announcement if the cache is full.
+func (c *sigCache) add(hash wire.ShaHash) {
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, ok := c.entries[hash]; ok {
+		c.lru.MoveToFront(elem)
+		return
+	}
+	if c.capacity <= 0 {
+		return
+	}
+
+	if c.lru.Len() >= c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(wire.ShaHash))
+	}
+	c.entries[hash] = c.lru.PushFront(hash)
+}
+
+// msgHash returns the hash of the serialized message, signatures included.
+func msgHash(msg lnwire.Message) (wire.ShaHash, error) {
+	var b bytes.Buffer
+	if err := msg.Encode(&b, 0); err != nil {
+		return wire.ShaHash{}, err
+	}
+
+	return wire.DoubleSha256SH(b.Bytes()), nil
+}
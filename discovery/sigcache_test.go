@@ -0,0 +1,38 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestSigCache(t *testing.T) {
+	hashes := make([]wire.ShaHash, 3)
+	for i := range hashes {
+		hashes[i] = wire.DoubleSha256SH([]byte{byte(i)})
+	}
+
+	c := newSigCache(2)
+	c.add(hashes[0])
+	c.add(hashes[1])
+
+	// Using the first hash leaves the second as least recently used, so
+	// it's the one evicted.
+	if !c.contains(hashes[0]) {
+		t.Fatalf("added hash not found")
+	}
+	c.add(hashes[2])
+	if c.contains(hashes[1]) {
+		t.Fatalf("least recently used hash wasn't evicted")
+	}
+	if !c.contains(hashes[0]) || !c.contains(hashes[2]) {
+		t.Fatalf("recently used hash was evicted")
+	}
+
+	// A cache without capacity remembers nothing.
+	c = newSigCache(-1)
+	c.add(hashes[0])
+	if c.contains(hashes[0]) {
+		t.Fatalf("hash added to cache without capacity")
+	}
+}
@@ -0,0 +1,132 @@
+package discovery
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// DefaultNumActiveSyncers is the default number of peers we'll actively
+// synchronize the channel graph with.
+const DefaultNumActiveSyncers = 3
+
+// SyncManagerCfg is the configuration of the SyncManager.
+type SyncManagerCfg struct {
+	// ChanGraph is our view of the channel graph.
+	ChanGraph ChannelGraph
+
+	// NumActiveSyncers is the number of peers we'll actively synchronize
+	// the channel graph with at any one time. The syncers of any other
+	// peers only answer their queries, until an active peer disconnects.
+	NumActiveSyncers int
+}
+
+// SyncManager creates, and tracks, the GossipSyncer of each peer, ensuring
+// that no more than NumActiveSyncers of them are querying their peer at
+// once. This keeps a fresh node from downloading the same channels from
+// every peer it connects to.
+type SyncManager struct {
+	cfg *SyncManagerCfg
+
+	sync.Mutex
+	activeSyncers  map[int32]*GossipSyncer
+	passiveSyncers map[int32]*GossipSyncer
+}
+
+// NewSyncManager creates a new SyncManager from the passed config.
+func NewSyncManager(cfg *SyncManagerCfg) *SyncManager {
+	return &SyncManager{
+		cfg:            cfg,
+		activeSyncers:  make(map[int32]*GossipSyncer),
+		passiveSyncers: make(map[int32]*GossipSyncer),
+	}
+}
+
+// InitSyncState creates, and starts, the GossipSyncer of a newly connected
+// peer, identified by the ID the server assigned it. If fewer than
+// NumActiveSyncers peers are being actively synchronized with, the syncer is
+// active.
+func (m *SyncManager) InitSyncState(peerID int32,
+	sendToPeer func(...lnwire.Message) error) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.activeSyncers[peerID]; ok {
+		return fmt.Errorf("peer %v already has a gossip syncer", peerID)
+	}
+	if _, ok := m.passiveSyncers[peerID]; ok {
+		return fmt.Errorf("peer %v already has a gossip syncer", peerID)
+	}
+
+	active := len(m.activeSyncers) < m.cfg.NumActiveSyncers
+	syncer := newGossipSyncer(gossipSyncerCfg{
+		graph:      m.cfg.ChanGraph,
+		sendToPeer: sendToPeer,
+		active:     active,
+	})
+	if active {
+		m.activeSyncers[peerID] = syncer
+	} else {
+		m.passiveSyncers[peerID] = syncer
+	}
+
+	return syncer.Start()
+}
+
+// PruneSyncState stops the GossipSyncer of a disconnected peer. If the
+// syncer was active, a passive syncer takes its place.
+func (m *SyncManager) PruneSyncState(peerID int32) {
+	m.Lock()
+	defer m.Unlock()
+
+	if syncer, ok := m.passiveSyncers[peerID]; ok {
+		delete(m.passiveSyncers, peerID)
+		syncer.Stop()
+		return
+	}
+
+	syncer, ok := m.activeSyncers[peerID]
+	if !ok {
+		return
+	}
+	delete(m.activeSyncers, peerID)
+	syncer.Stop()
+
+	for passiveID, passive := range m.passiveSyncers {
+		delete(m.passiveSyncers, passiveID)
+		m.activeSyncers[passiveID] = passive
+		passive.startActiveSync()
+		return
+	}
+}
+
+// ProcessQueryMsg hands a gossip query, or reply, sent by the peer to its
+// GossipSyncer.
+func (m *SyncManager) ProcessQueryMsg(peerID int32, msg lnwire.Message) error {
+	m.Lock()
+	syncer, ok := m.activeSyncers[peerID]
+	if !ok {
+		syncer, ok = m.passiveSyncers[peerID]
+	}
+	m.Unlock()
+	if !ok {
+		return fmt.Errorf("peer %v has no gossip syncer", peerID)
+	}
+
+	return syncer.ProcessQueryMsg(msg)
+}
+
+// Stop stops the GossipSyncer of every peer.
+func (m *SyncManager) Stop() {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, syncer := range m.activeSyncers {
+		syncer.Stop()
+	}
+	for _, syncer := range m.passiveSyncers {
+		syncer.Stop()
+	}
+}
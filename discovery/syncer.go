@@ -0,0 +1,414 @@
+package discovery
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// defaultChunkSize is the maximum number of channels included within a
+// single query or reply.
+const defaultChunkSize = 500
+
+// ChannelGraph is the view of the channel graph required to synchronize it
+// with our peers.
+type ChannelGraph interface {
+	// FilterKnownChanIDs returns the subset of the passed channels which
+	// aren't yet within the graph.
+	FilterKnownChanIDs(chanIDs []lnwire.ShortChannelID) ([]lnwire.ShortChannelID, error)
+
+	// FilterChannelRange returns the channels within the graph whose
+	// funding transaction was confirmed between the start and end
+	// heights, inclusive, in ascending order.
+	FilterChannelRange(startHeight, endHeight uint32) ([]lnwire.ShortChannelID, error)
+
+	// FetchChanAnns returns the announcement of each of the passed
+	// channels, followed by their latest channel updates, skipping any
+	// not within the graph.
+	FetchChanAnns(chanIDs []lnwire.ShortChannelID) ([]lnwire.Message, error)
+}
+
+// syncerState is the state of a GossipSyncer's synchronization with its
+// peer.
+type syncerState uint32
+
+const (
+	// syncingChans is the initial state of an active syncer, in which it
+	// queries the range of channels the remote peer knows of.
+	syncingChans syncerState = iota
+
+	// waitingQueryRangeReply is entered once we've sent our channel range
+	// query, and are waiting on the remote peer's replies.
+	waitingQueryRangeReply
+
+	// queryNewChannels is entered once we've learned of the channels the
+	// remote peer knows of which we don't, in which we request the
+	// announcement of the next chunk of them.
+	queryNewChannels
+
+	// waitingQueryChanReply is entered once we've requested the
+	// announcements of a chunk of channels, and are waiting for the
+	// remote peer to send them all.
+	waitingQueryChanReply
+
+	// chansSynced is entered once our graph contains all channels the
+	// remote peer knows of. Passive syncers remain in this state, as they
+	// never query the remote peer.
+	chansSynced
+)
+
+// String returns a human readable version of the syncerState.
+func (s syncerState) String() string {
+	switch s {
+	case syncingChans:
+		return "syncingChans"
+	case waitingQueryRangeReply:
+		return "waitingQueryRangeReply"
+	case queryNewChannels:
+		return "queryNewChannels"
+	case waitingQueryChanReply:
+		return "waitingQueryChanReply"
+	case chansSynced:
+		return "chansSynced"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// gossipSyncerCfg is the configuration of a GossipSyncer.
+type gossipSyncerCfg struct {
+	// graph is our view of the channel graph.
+	graph ChannelGraph
+
+	// sendToPeer sends the messages to the remote peer.
+	sendToPeer func(...lnwire.Message) error
+
+	// chunkSize is the maximum number of channels included within a
+	// single query or reply.
+	chunkSize int
+
+	// active is true if the syncer should query the remote peer for the
+	// channels we're missing, rather than only answering its queries.
+	active bool
+}
+
+// GossipSyncer synchronizes our view of the channel graph with that of a
+// single peer. Active syncers query the remote peer for the range of
+// channels it knows of, then request the announcements of those we're
+// missing in chunks. All syncers answer the queries of the remote peer.
+type GossipSyncer struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+	state   uint32 // To be used atomically.
+
+	cfg gossipSyncerCfg
+
+	// bufferedChanRangeReplies holds the channels the remote peer has
+	// replied with so far, until its final reply arrives.
+	bufferedChanRangeReplies []lnwire.ShortChannelID
+
+	// newChansToQuery holds the channels the remote peer knows of which
+	// we have yet to request the announcements of.
+	newChansToQuery []lnwire.ShortChannelID
+
+	// gossipMsgs receives the remote peer's replies to our queries.
+	gossipMsgs chan lnwire.Message
+
+	// queryMsgs receives the remote peer's queries.
+	queryMsgs chan lnwire.Message
+
+	// activeSyncReqs is used to request that a passive syncer begin
+	// querying the remote peer.
+	activeSyncReqs chan struct{}
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newGossipSyncer returns a new GossipSyncer for a peer.
+func newGossipSyncer(cfg gossipSyncerCfg) *GossipSyncer {
+	if cfg.chunkSize == 0 {
+		cfg.chunkSize = defaultChunkSize
+	}
+
+	state := chansSynced
+	if cfg.active {
+		state = syncingChans
+	}
+
+	return &GossipSyncer{
+		state:          uint32(state),
+		cfg:            cfg,
+		gossipMsgs:     make(chan lnwire.Message, 100),
+		queryMsgs:      make(chan lnwire.Message, 100),
+		activeSyncReqs: make(chan struct{}, 1),
+		quit:           make(chan struct{}),
+	}
+}
+
+// Start launches the goroutines of the syncer.
+func (g *GossipSyncer) Start() error {
+	if !atomic.CompareAndSwapUint32(&g.started, 0, 1) {
+		return nil
+	}
+
+	g.wg.Add(2)
+	go g.channelGraphSyncer()
+	go g.replyHandler()
+
+	return nil
+}
+
+// Stop signals the goroutines of the syncer to exit, and waits for them to
+// do so.
+func (g *GossipSyncer) Stop() error {
+	if !atomic.CompareAndSwapUint32(&g.stopped, 0, 1) {
+		return nil
+	}
+
+	close(g.quit)
+	g.wg.Wait()
+
+	return nil
+}
+
+// ProcessQueryMsg hands a gossip query, or reply, sent by the remote peer to
+// the syncer.
+func (g *GossipSyncer) ProcessQueryMsg(msg lnwire.Message) error {
+	var msgChan chan lnwire.Message
+	switch msg.(type) {
+	case *lnwire.QueryChannelRange, *lnwire.QueryShortChanIDs:
+		msgChan = g.queryMsgs
+	case *lnwire.ReplyChannelRange, *lnwire.ReplyShortChanIDsEnd:
+		msgChan = g.gossipMsgs
+	default:
+		return fmt.Errorf("unknown gossip query message: %T", msg)
+	}
+
+	select {
+	case msgChan <- msg:
+		return nil
+	case <-g.quit:
+		return fmt.Errorf("gossip syncer shutting down")
+	}
+}
+
+// startActiveSync requests that a passive syncer begin querying the remote
+// peer for the channels we're missing.
+func (g *GossipSyncer) startActiveSync() {
+	select {
+	case g.activeSyncReqs <- struct{}{}:
+	default:
+	}
+}
+
+// syncState returns the current state of the syncer.
+func (g *GossipSyncer) syncState() syncerState {
+	return syncerState(atomic.LoadUint32(&g.state))
+}
+
+// setSyncState transitions the syncer to the passed state.
+func (g *GossipSyncer) setSyncState(state syncerState) {
+	atomic.StoreUint32(&g.state, uint32(state))
+}
+
+// channelGraphSyncer drives the synchronization of our graph with that of
+// the remote peer.
+//
+// NOTE: This MUST be run as a goroutine.
+func (g *GossipSyncer) channelGraphSyncer() {
+	defer g.wg.Done()
+
+	// TODO: time out peers which never reply to our queries,
+	// handing the active slot to another peer
+	for {
+		switch g.syncState() {
+		// We'll start by querying every channel the remote peer knows
+		// of. Any channels we already know of are filtered out once
+		// it replies, so only the missing ones are requested.
+		case syncingChans:
+			query := &lnwire.QueryChannelRange{
+				FirstBlockHeight: 0,
+				NumBlocks:        math.MaxUint32,
+			}
+			if err := g.cfg.sendToPeer(query); err != nil {
+				fmt.Printf("unable to send channel range "+
+					"query: %v\n", err)
+				return
+			}
+
+			g.setSyncState(waitingQueryRangeReply)
+
+		case waitingQueryRangeReply:
+			select {
+			case msg := <-g.gossipMsgs:
+				reply, ok := msg.(*lnwire.ReplyChannelRange)
+				if !ok {
+					continue
+				}
+
+				if err := g.processChanRangeReply(reply); err != nil {
+					fmt.Printf("unable to process channel "+
+						"range reply: %v\n", err)
+					return
+				}
+
+			case <-g.quit:
+				return
+			}
+
+		case queryNewChannels:
+			if err := g.synchronizeChanIDs(); err != nil {
+				fmt.Printf("unable to query channels: %v\n", err)
+				return
+			}
+
+		// The remote peer sends the announcements we requested as
+		// regular gossip, so we only need to wait for its signal
+		// that they've all been sent.
+		case waitingQueryChanReply:
+			select {
+			case msg := <-g.gossipMsgs:
+				if _, ok := msg.(*lnwire.ReplyShortChanIDsEnd); ok {
+					g.setSyncState(queryNewChannels)
+				}
+
+			case <-g.quit:
+				return
+			}
+
+		case chansSynced:
+			select {
+			case <-g.activeSyncReqs:
+				g.setSyncState(syncingChans)
+
+			// Replies we're no longer waiting on are dropped.
+			case <-g.gossipMsgs:
+
+			case <-g.quit:
+				return
+			}
+		}
+	}
+}
+
+// replyHandler answers the queries of the remote peer.
+//
+// NOTE: This MUST be run as a goroutine.
+func (g *GossipSyncer) replyHandler() {
+	defer g.wg.Done()
+
+	for {
+		select {
+		case msg := <-g.queryMsgs:
+			var err error
+			switch query := msg.(type) {
+			case *lnwire.QueryChannelRange:
+				err = g.replyChanRangeQuery(query)
+			case *lnwire.QueryShortChanIDs:
+				err = g.replyShortChanIDs(query)
+			}
+			if err != nil {
+				fmt.Printf("unable to reply to gossip query: "+
+					"%v\n", err)
+			}
+
+		case <-g.quit:
+			return
+		}
+	}
+}
+
+// processChanRangeReply buffers the channels within a reply to our channel
+// range query. Once the final reply arrives, the channels we're missing are
+// queued up to be requested.
+func (g *GossipSyncer) processChanRangeReply(reply *lnwire.ReplyChannelRange) error {
+	g.bufferedChanRangeReplies = append(g.bufferedChanRangeReplies,
+		reply.ShortChanIDs...)
+	if !reply.Complete {
+		return nil
+	}
+
+	newChans, err := g.cfg.graph.FilterKnownChanIDs(
+		g.bufferedChanRangeReplies)
+	if err != nil {
+		return err
+	}
+	g.bufferedChanRangeReplies = nil
+
+	if len(newChans) == 0 {
+		g.setSyncState(chansSynced)
+		return nil
+	}
+
+	g.newChansToQuery = newChans
+	g.setSyncState(queryNewChannels)
+	return nil
+}
+
+// synchronizeChanIDs requests the announcements of the next chunk of
+// channels we're missing. Once none remain, the syncer is synced.
+func (g *GossipSyncer) synchronizeChanIDs() error {
+	if len(g.newChansToQuery) == 0 {
+		g.setSyncState(chansSynced)
+		return nil
+	}
+
+	numChans := len(g.newChansToQuery)
+	if numChans > g.cfg.chunkSize {
+		numChans = g.cfg.chunkSize
+	}
+	query := &lnwire.QueryShortChanIDs{
+		ShortChanIDs: g.newChansToQuery[:numChans],
+	}
+	g.newChansToQuery = g.newChansToQuery[numChans:]
+
+	g.setSyncState(waitingQueryChanReply)
+	return g.cfg.sendToPeer(query)
+}
+
+// replyChanRangeQuery sends the channels we know of within the queried
+// range, split over as many replies as needed.
+func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) error {
+	chanIDs, err := g.cfg.graph.FilterChannelRange(
+		query.FirstBlockHeight, query.LastBlockHeight())
+	if err != nil {
+		return err
+	}
+
+	for {
+		numChans := len(chanIDs)
+		if numChans > g.cfg.chunkSize {
+			numChans = g.cfg.chunkSize
+		}
+		reply := &lnwire.ReplyChannelRange{
+			FirstBlockHeight: query.FirstBlockHeight,
+			NumBlocks:        query.NumBlocks,
+			Complete:         numChans == len(chanIDs),
+			ShortChanIDs:     chanIDs[:numChans],
+		}
+		chanIDs = chanIDs[numChans:]
+
+		if err := g.cfg.sendToPeer(reply); err != nil {
+			return err
+		}
+		if reply.Complete {
+			return nil
+		}
+	}
+}
+
+// replyShortChanIDs sends the announcements, and updates, of the queried
+// channels we know of, signalling once they've all been sent.
+func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error {
+	msgs, err := g.cfg.graph.FetchChanAnns(query.ShortChanIDs)
+	if err != nil {
+		return err
+	}
+	msgs = append(msgs, &lnwire.ReplyShortChanIDsEnd{Complete: true})
+
+	return g.cfg.sendToPeer(msgs...)
+}
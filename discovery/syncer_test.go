@@ -0,0 +1,367 @@
+package discovery
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// mockGraph is an in-memory ChannelGraph, GossipGraph, and PrunableGraph.
+type mockGraph struct {
+	sync.Mutex
+	anns       map[lnwire.ShortChannelID]*lnwire.ChannelAnnouncement
+	policies   map[updateKey]*lnwire.ChannelUpdate
+	chanPoints map[wire.OutPoint]lnwire.ShortChannelID
+	private    map[lnwire.ShortChannelID]struct{}
+
+	pruneHash   *wire.ShaHash
+	pruneHeight uint32
+}
+
+func newMockGraph(chanIDs ...lnwire.ShortChannelID) *mockGraph {
+	g := &mockGraph{
+		anns:       make(map[lnwire.ShortChannelID]*lnwire.ChannelAnnouncement),
+		policies:   make(map[updateKey]*lnwire.ChannelUpdate),
+		chanPoints: make(map[wire.OutPoint]lnwire.ShortChannelID),
+		private:    make(map[lnwire.ShortChannelID]struct{}),
+	}
+	for _, chanID := range chanIDs {
+		g.addAnn(&lnwire.ChannelAnnouncement{ShortChannelID: chanID})
+	}
+	return g
+}
+
+func (g *mockGraph) addAnn(ann *lnwire.ChannelAnnouncement) {
+	g.Lock()
+	g.anns[ann.ShortChannelID] = ann
+	g.Unlock()
+}
+
+func (g *mockGraph) numChans() int {
+	g.Lock()
+	defer g.Unlock()
+	return len(g.anns)
+}
+
+func (g *mockGraph) FilterKnownChanIDs(chanIDs []lnwire.ShortChannelID) ([]lnwire.ShortChannelID, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	var unknown []lnwire.ShortChannelID
+	for _, chanID := range chanIDs {
+		if _, ok := g.anns[chanID]; !ok {
+			unknown = append(unknown, chanID)
+		}
+	}
+	return unknown, nil
+}
+
+func (g *mockGraph) FilterChannelRange(startHeight, endHeight uint32) ([]lnwire.ShortChannelID, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	var chanIDs []lnwire.ShortChannelID
+	for chanID := range g.anns {
+		if chanID.BlockHeight >= startHeight &&
+			chanID.BlockHeight <= endHeight {
+			chanIDs = append(chanIDs, chanID)
+		}
+	}
+	sort.Sort(sortableChanIDs(chanIDs))
+	return chanIDs, nil
+}
+
+func (g *mockGraph) FetchChanAnns(chanIDs []lnwire.ShortChannelID) ([]lnwire.Message, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	var anns []lnwire.Message
+	for _, chanID := range chanIDs {
+		if ann, ok := g.anns[chanID]; ok {
+			anns = append(anns, ann)
+		}
+	}
+	return anns, nil
+}
+
+func (g *mockGraph) AddChannelEdge(ann *lnwire.ChannelAnnouncement, chanPoint *wire.OutPoint,
+	capacity btcutil.Amount) error {
+
+	g.addAnn(ann)
+
+	g.Lock()
+	g.chanPoints[*chanPoint] = ann.ShortChannelID
+	g.Unlock()
+	return nil
+}
+
+func (g *mockGraph) AddPrivateChannelEdge(ann *lnwire.ChannelAnnouncement,
+	chanPoint *wire.OutPoint, capacity btcutil.Amount) error {
+
+	g.Lock()
+	g.private[ann.ShortChannelID] = struct{}{}
+	g.Unlock()
+
+	return g.AddChannelEdge(ann, chanPoint, capacity)
+}
+
+func (g *mockGraph) IsPrivateChannel(chanID lnwire.ShortChannelID) (bool, error) {
+	g.Lock()
+	defer g.Unlock()
+	_, ok := g.private[chanID]
+	return ok, nil
+}
+
+func (g *mockGraph) PruneGraph(spentOutputs []*wire.OutPoint, blockHash *wire.ShaHash,
+	blockHeight uint32) ([]lnwire.ShortChannelID, error) {
+
+	g.Lock()
+	defer g.Unlock()
+
+	var closedChans []lnwire.ShortChannelID
+	for _, op := range spentOutputs {
+		chanID, ok := g.chanPoints[*op]
+		if !ok {
+			continue
+		}
+		delete(g.chanPoints, *op)
+		delete(g.anns, chanID)
+		closedChans = append(closedChans, chanID)
+	}
+	g.pruneHash = blockHash
+	g.pruneHeight = blockHeight
+	return closedChans, nil
+}
+
+func (g *mockGraph) PruneTip() (*wire.ShaHash, uint32, error) {
+	g.Lock()
+	defer g.Unlock()
+	return g.pruneHash, g.pruneHeight, nil
+}
+
+func (g *mockGraph) FetchChannelEdge(chanID lnwire.ShortChannelID) (*lnwire.ChannelAnnouncement, error) {
+	g.Lock()
+	defer g.Unlock()
+	return g.anns[chanID], nil
+}
+
+func (g *mockGraph) FetchEdgePolicy(chanID lnwire.ShortChannelID, direction uint8) (*lnwire.ChannelUpdate, error) {
+	g.Lock()
+	defer g.Unlock()
+	return g.policies[updateKey{chanID, direction}], nil
+}
+
+func (g *mockGraph) UpdateEdgePolicy(update *lnwire.ChannelUpdate) error {
+	g.Lock()
+	defer g.Unlock()
+	g.policies[updateKey{update.ShortChannelID, update.Direction()}] = update
+	return nil
+}
+
+// sortableChanIDs sorts channels in ascending order.
+type sortableChanIDs []lnwire.ShortChannelID
+
+func (s sortableChanIDs) Len() int           { return len(s) }
+func (s sortableChanIDs) Less(i, j int) bool { return s[i].ToUint64() < s[j].ToUint64() }
+func (s sortableChanIDs) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// connectSyncers returns a sendToPeer function which delivers messages to
+// the remote syncer, adding any announcements to the remote graph.
+func connectSyncers(remote **GossipSyncer, remoteGraph *mockGraph) func(...lnwire.Message) error {
+	return func(msgs ...lnwire.Message) error {
+		for _, msg := range msgs {
+			if ann, ok := msg.(*lnwire.ChannelAnnouncement); ok {
+				remoteGraph.addAnn(ann)
+				continue
+			}
+			if err := (*remote).ProcessQueryMsg(msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func waitForState(t *testing.T, g *GossipSyncer, state syncerState) {
+	timeout := time.After(5 * time.Second)
+	for g.syncState() != state {
+		select {
+		case <-timeout:
+			t.Fatalf("syncer in state %v, expected %v",
+				g.syncState(), state)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// TestGossipSyncerSync ensures that an active syncer downloads each channel
+// the remote peer knows of which it doesn't, over several chunks.
+func TestGossipSyncerSync(t *testing.T) {
+	var remoteChans []lnwire.ShortChannelID
+	for i := uint32(0); i < 25; i++ {
+		remoteChans = append(remoteChans, lnwire.ShortChannelID{
+			BlockHeight: 1000 + i,
+			TxIndex:     i,
+		})
+	}
+
+	// We already know of a few of the channels, and one the remote peer
+	// doesn't.
+	localGraph := newMockGraph(append(remoteChans[:5:5],
+		lnwire.ShortChannelID{BlockHeight: 2000})...)
+	remoteGraph := newMockGraph(remoteChans...)
+
+	var local, remote *GossipSyncer
+	local = newGossipSyncer(gossipSyncerCfg{
+		graph:      localGraph,
+		sendToPeer: connectSyncers(&remote, remoteGraph),
+		chunkSize:  4,
+		active:     true,
+	})
+	remote = newGossipSyncer(gossipSyncerCfg{
+		graph:      remoteGraph,
+		sendToPeer: connectSyncers(&local, localGraph),
+		chunkSize:  4,
+	})
+	if remote.syncState() != chansSynced {
+		t.Fatalf("passive syncer shouldn't sync, in state %v",
+			remote.syncState())
+	}
+
+	remote.Start()
+	defer remote.Stop()
+	local.Start()
+	defer local.Stop()
+
+	waitForState(t, local, chansSynced)
+	if localGraph.numChans() != len(remoteChans)+1 {
+		t.Fatalf("expected %d channels, have %d", len(remoteChans)+1,
+			localGraph.numChans())
+	}
+
+	// The passive syncer never queries, so the remote peer doesn't learn
+	// of our extra channel.
+	if remoteGraph.numChans() != len(remoteChans) {
+		t.Fatalf("passive syncer queried for channels")
+	}
+}
+
+// TestGossipSyncerReplyChanRange ensures that channel range replies are
+// split into chunks, with only the last marked as complete.
+func TestGossipSyncerReplyChanRange(t *testing.T) {
+	var chanIDs []lnwire.ShortChannelID
+	for i := uint32(0); i < 10; i++ {
+		chanIDs = append(chanIDs, lnwire.ShortChannelID{
+			BlockHeight: 100 + i,
+		})
+	}
+
+	var replies []*lnwire.ReplyChannelRange
+	g := newGossipSyncer(gossipSyncerCfg{
+		graph: newMockGraph(chanIDs...),
+		sendToPeer: func(msgs ...lnwire.Message) error {
+			for _, msg := range msgs {
+				replies = append(replies,
+					msg.(*lnwire.ReplyChannelRange))
+			}
+			return nil
+		},
+		chunkSize: 3,
+	})
+
+	query := &lnwire.QueryChannelRange{
+		FirstBlockHeight: 102,
+		NumBlocks:        6,
+	}
+	if err := g.replyChanRangeQuery(query); err != nil {
+		t.Fatalf("unable to reply to query: %v", err)
+	}
+
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 replies, got %d", len(replies))
+	}
+	if replies[0].Complete || !replies[1].Complete {
+		t.Fatalf("only the final reply should be complete")
+	}
+	var replied []lnwire.ShortChannelID
+	for _, reply := range replies {
+		replied = append(replied, reply.ShortChanIDs...)
+	}
+	if len(replied) != 6 || replied[0] != chanIDs[2] ||
+		replied[5] != chanIDs[7] {
+		t.Fatalf("unexpected channels in reply: %v", replied)
+	}
+
+	// A query for a range we know nothing of still gets a reply.
+	replies = nil
+	query.FirstBlockHeight = 500
+	if err := g.replyChanRangeQuery(query); err != nil {
+		t.Fatalf("unable to reply to query: %v", err)
+	}
+	if len(replies) != 1 || !replies[0].Complete {
+		t.Fatalf("expected a single complete reply")
+	}
+}
+
+// TestSyncManagerActiveSyncers ensures that no more than the configured
+// number of syncers are active, and that a passive syncer is promoted once
+// an active peer disconnects.
+func TestSyncManagerActiveSyncers(t *testing.T) {
+	m := NewSyncManager(&SyncManagerCfg{
+		ChanGraph:        newMockGraph(),
+		NumActiveSyncers: 1,
+	})
+	defer m.Stop()
+
+	queries := make(chan int32, 10)
+	sendToPeer := func(peerID int32) func(...lnwire.Message) error {
+		return func(msgs ...lnwire.Message) error {
+			for _, msg := range msgs {
+				if _, ok := msg.(*lnwire.QueryChannelRange); ok {
+					queries <- peerID
+				}
+			}
+			return nil
+		}
+	}
+
+	for peerID := int32(1); peerID <= 2; peerID++ {
+		if err := m.InitSyncState(peerID, sendToPeer(peerID)); err != nil {
+			t.Fatalf("unable to init sync state: %v", err)
+		}
+	}
+	if err := m.InitSyncState(1, sendToPeer(1)); err == nil {
+		t.Fatalf("peer given a second syncer")
+	}
+
+	expectQuery := func(peerID int32) {
+		select {
+		case queryPeer := <-queries:
+			if queryPeer != peerID {
+				t.Fatalf("expected query to peer %v, got %v",
+					peerID, queryPeer)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no query sent to peer %v", peerID)
+		}
+	}
+
+	expectQuery(1)
+	select {
+	case peerID := <-queries:
+		t.Fatalf("passive syncer of peer %v sent query", peerID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	m.PruneSyncState(1)
+	expectQuery(2)
+
+	if err := m.ProcessQueryMsg(1, &lnwire.ReplyShortChanIDsEnd{}); err == nil {
+		t.Fatalf("message for disconnected peer accepted")
+	}
+}
@@ -0,0 +1,222 @@
+package discovery
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TopologyChange is a set of changes made to the channel graph at once.
+type TopologyChange struct {
+	// NewChannels are the channels added to the graph.
+	NewChannels []*NewChannel
+
+	// ChannelUpdates are the channel updates accepted into the graph.
+	ChannelUpdates []*ChannelEdgeUpdate
+
+	// ClosedChannels are the channels pruned from the graph.
+	ClosedChannels []*ClosedChannel
+}
+
+// NewChannel is a channel added to the graph.
+type NewChannel struct {
+	// Announcement is the announcement the channel was added with.
+	Announcement *lnwire.ChannelAnnouncement
+
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint wire.OutPoint
+
+	// Capacity is the value of the funding output.
+	Capacity btcutil.Amount
+}
+
+// ChannelEdgeUpdate is a channel update accepted into the graph, along with
+// the announcement of the channel it updates.
+type ChannelEdgeUpdate struct {
+	// Announcement is the announcement of the updated channel.
+	Announcement *lnwire.ChannelAnnouncement
+
+	// Update is the accepted channel update.
+	Update *lnwire.ChannelUpdate
+}
+
+// ClosedChannel is a channel pruned from the graph, as its funding output
+// was spent.
+type ClosedChannel struct {
+	// ChanID is the ShortChannelID of the channel.
+	ChanID lnwire.ShortChannelID
+
+	// ClosedHeight is the height of the block spending the funding
+	// output.
+	ClosedHeight uint32
+}
+
+// TopologyClient receives each change made to the channel graph after it
+// subscribed.
+type TopologyClient struct {
+	// TopologyChanges is sent each change to the graph, in the order
+	// they're made. It's closed once the client is cancelled, or the
+	// notifier is stopped.
+	TopologyChanges <-chan *TopologyChange
+
+	// Cancel unsubscribes the client.
+	Cancel func()
+}
+
+// TopologyNotifier dispatches the changes made to the channel graph by the
+// Gossiper, and the GraphPruner, to each subscribed client. Each client has
+// its own queue, so a slow client never blocks either the notifier or the
+// other clients. A nil TopologyNotifier may be used when no clients need
+// notifying.
+type TopologyNotifier struct {
+	stopped uint32 // To be used atomically.
+
+	sync.Mutex
+	clients      map[uint64]*topologyClient
+	nextClientID uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewTopologyNotifier returns a TopologyNotifier without any clients.
+func NewTopologyNotifier() *TopologyNotifier {
+	return &TopologyNotifier{
+		clients: make(map[uint64]*topologyClient),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Stop cancels every client, and waits for their goroutines to exit.
+func (n *TopologyNotifier) Stop() error {
+	if !atomic.CompareAndSwapUint32(&n.stopped, 0, 1) {
+		return nil
+	}
+
+	// The mutex ensures no client is added once we begin waiting.
+	n.Lock()
+	close(n.quit)
+	n.Unlock()
+
+	n.wg.Wait()
+
+	return nil
+}
+
+// SubscribeTopology returns a new client, sent each change made to the
+// channel graph from now on.
+func (n *TopologyNotifier) SubscribeTopology() *TopologyClient {
+	changes := make(chan *TopologyChange)
+
+	client := &topologyClient{
+		changes: changes,
+		signal:  make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+
+	n.Lock()
+	clientID := n.nextClientID
+	n.nextClientID++
+
+	// Once stopped, the client won't be sent any changes.
+	if atomic.LoadUint32(&n.stopped) != 0 {
+		close(changes)
+	} else {
+		n.clients[clientID] = client
+		n.wg.Add(1)
+		go n.clientHandler(client)
+	}
+	n.Unlock()
+
+	var cancelOnce sync.Once
+	return &TopologyClient{
+		TopologyChanges: changes,
+		Cancel: func() {
+			cancelOnce.Do(func() {
+				n.Lock()
+				delete(n.clients, clientID)
+				n.Unlock()
+
+				close(client.quit)
+			})
+		},
+	}
+}
+
+// notify queues the change for delivery to each client. It never blocks.
+func (n *TopologyNotifier) notify(change *TopologyChange) {
+	if n == nil {
+		return
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	for _, client := range n.clients {
+		client.enqueue(change)
+	}
+}
+
+// clientHandler delivers each change queued for the client, in order.
+//
+// NOTE: This MUST be run as a goroutine.
+func (n *TopologyNotifier) clientHandler(client *topologyClient) {
+	defer n.wg.Done()
+	defer close(client.changes)
+
+	for {
+		client.Lock()
+		if len(client.queue) == 0 {
+			client.Unlock()
+
+			select {
+			case <-client.signal:
+				continue
+			case <-client.quit:
+				return
+			case <-n.quit:
+				return
+			}
+		}
+		change := client.queue[0]
+		client.queue[0] = nil
+		client.queue = client.queue[1:]
+		client.Unlock()
+
+		select {
+		case client.changes <- change:
+		case <-client.quit:
+			return
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// topologyClient is the queue of changes yet to be delivered to a client.
+type topologyClient struct {
+	// The mutex guards the queue.
+	sync.Mutex
+	queue []*TopologyChange
+
+	// signal is sent on, without blocking, as changes are queued.
+	signal chan struct{}
+
+	changes chan *TopologyChange
+	quit    chan struct{}
+}
+
+// enqueue adds the change to the back of the queue.
+func (c *topologyClient) enqueue(change *TopologyChange) {
+	c.Lock()
+	c.queue = append(c.queue, change)
+	c.Unlock()
+
+	select {
+	case c.signal <- struct{}{}:
+	default:
+	}
+}
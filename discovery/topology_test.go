@@ -0,0 +1,89 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// closedChanChange returns a change closing the channel confirmed at the
+// passed height.
+func closedChanChange(height uint32) *TopologyChange {
+	return &TopologyChange{
+		ClosedChannels: []*ClosedChannel{{
+			ChanID:       lnwire.ShortChannelID{BlockHeight: height},
+			ClosedHeight: height + 1,
+		}},
+	}
+}
+
+// TestTopologyNotifier ensures that each client receives every change in
+// order, without a slow client holding up the others, and that cancelled
+// clients no longer receive changes.
+func TestTopologyNotifier(t *testing.T) {
+	n := NewTopologyNotifier()
+	defer n.Stop()
+
+	// A nil notifier is simply ignored.
+	var nilNotifier *TopologyNotifier
+	nilNotifier.notify(closedChanChange(0))
+
+	slowClient := n.SubscribeTopology()
+	fastClient := n.SubscribeTopology()
+
+	const numChanges = 100
+	for i := uint32(0); i < numChanges; i++ {
+		n.notify(closedChanChange(i))
+	}
+
+	recvChanges := func(client *TopologyClient) {
+		for i := uint32(0); i < numChanges; i++ {
+			select {
+			case change := <-client.TopologyChanges:
+				chanID := change.ClosedChannels[0].ChanID
+				if chanID.BlockHeight != i {
+					t.Fatalf("expected change %d, got %d",
+						i, chanID.BlockHeight)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("change %d wasn't received", i)
+			}
+		}
+	}
+	recvChanges(fastClient)
+	recvChanges(slowClient)
+
+	// Once cancelled, the channel of the client is closed.
+	fastClient.Cancel()
+	fastClient.Cancel()
+	n.notify(closedChanChange(numChanges))
+	select {
+	case _, ok := <-fastClient.TopologyChanges:
+		if ok {
+			t.Fatalf("cancelled client received change")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("cancelled client wasn't closed")
+	}
+
+	select {
+	case <-slowClient.TopologyChanges:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("change wasn't received")
+	}
+
+	// Stopping the notifier closes the channels of the remaining, and
+	// any later, clients.
+	if err := n.Stop(); err != nil {
+		t.Fatalf("unable to stop notifier: %v", err)
+	}
+	if _, ok := <-slowClient.TopologyChanges; ok {
+		t.Fatalf("client received change after stop")
+	}
+	lateClient := n.SubscribeTopology()
+	if _, ok := <-lateClient.TopologyChanges; ok {
+		t.Fatalf("client received change after stop")
+	}
+	lateClient.Cancel()
+}
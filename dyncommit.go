@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// dynParamsTimeout is how long we wait for our channel with the peer to
+// quiesce, and then for the peer to reply to the parameters we propose.
+const dynParamsTimeout = time.Minute
+
+// ErrParamsRejected is returned when the peer rejects the parameters we
+// proposed for our channel with it.
+var ErrParamsRejected = errors.New("peer rejected channel parameters")
+
+// dynProposal is the proposal of new parameters for our channel with the
+// peer, awaiting its reply.
+type dynProposal struct {
+	params *lnwallet.ChannelParams
+
+	// result is sent nil once the peer accepts the parameters, and
+	// ErrParamsRejected should it reject them.
+	result chan error
+}
+
+// UpdateChannelParams renegotiates the parameters of our channel with the
+// connected peer, quiescing the channel while doing so. Parameters which
+// are zero keep their current value.
+func (s *server) UpdateChannelParams(pubKey *btcec.PublicKey,
+	params *lnwallet.ChannelParams) error {
+
+	p, _, err := s.peerSession(pubKey)
+	if err != nil {
+		return err
+	}
+
+	return p.updateChannelParams(params)
+}
+
+// updateChannelParams proposes the parameters for our channel with the peer
+// once the channel is quiescent, applying them once the peer accepts.
+func (p *peer) updateChannelParams(params *lnwallet.ChannelParams) error {
+	p.RLock()
+	channel := p.lnChannel
+	p.RUnlock()
+	if channel == nil {
+		return fmt.Errorf("no open channel with peer %v", p.peerID)
+	}
+
+	current := channel.Params()
+	if params.MaxAcceptedHtlcs == 0 {
+		params.MaxAcceptedHtlcs = current.MaxAcceptedHtlcs
+	}
+	if params.MaxValueInFlight == 0 {
+		params.MaxValueInFlight = current.MaxValueInFlight
+	}
+	if params.CsvDelay == 0 {
+		params.CsvDelay = current.CsvDelay
+	}
+
+	proposal := &dynProposal{
+		params: params,
+		result: make(chan error, 1),
+	}
+	p.dynMtx.Lock()
+	if p.dynPending != nil {
+		p.dynMtx.Unlock()
+		return fmt.Errorf("already renegotiating channel with peer %v",
+			p.peerID)
+	}
+	p.dynPending = proposal
+	p.dynMtx.Unlock()
+
+	defer func() {
+		p.dynMtx.Lock()
+		p.dynPending = nil
+		p.dynMtx.Unlock()
+	}()
+
+	quiescent, err := p.quiesce()
+	if err != nil {
+		return err
+	}
+	defer p.resumeChannel()
+
+	timeout := time.After(dynParamsTimeout)
+	select {
+	case <-quiescent:
+	case <-timeout:
+		return fmt.Errorf("timed out quiescing channel with peer %v",
+			p.peerID)
+	case <-p.quit:
+		return ErrPeerNotConnected
+	}
+
+	chanID, err := p.channelID()
+	if err != nil {
+		return err
+	}
+	p.queueMsg(&lnwire.DynPropose{
+		ChannelID:        chanID,
+		MaxAcceptedHtlcs: params.MaxAcceptedHtlcs,
+		MaxValueInFlight: params.MaxValueInFlight,
+		CsvDelay:         params.CsvDelay,
+	}, nil)
+
+	select {
+	case err := <-proposal.result:
+		return err
+	case <-timeout:
+		return fmt.Errorf("peer %v didn't reply to channel parameters",
+			p.peerID)
+	case <-p.quit:
+		return ErrPeerNotConnected
+	}
+}
+
+// handleDynPropose processes the parameters the peer proposes for our
+// quiescent channel with it, accepting them if they're within our bounds.
+// Should we be awaiting the reply to our own proposal, the peer's is
+// rejected.
+func (p *peer) handleDynPropose(msg lnwire.Message) {
+	propose := msg.(*lnwire.DynPropose)
+
+	chanID, err := p.channelID()
+	if err != nil || chanID != propose.ChannelID {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent dyn_propose for unknown channel %v",
+			propose.ChannelID)
+		return
+	}
+	if !p.quiescer.isQuiescent() {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent dyn_propose for channel %v which isn't quiescent",
+			propose.ChannelID)
+		return
+	}
+
+	p.RLock()
+	channel := p.lnChannel
+	p.RUnlock()
+
+	params := &lnwallet.ChannelParams{
+		MaxAcceptedHtlcs: propose.MaxAcceptedHtlcs,
+		MaxValueInFlight: propose.MaxValueInFlight,
+		CsvDelay:         propose.CsvDelay,
+	}
+
+	p.dynMtx.Lock()
+	proposing := p.dynPending != nil
+	p.dynMtx.Unlock()
+
+	switch {
+	case proposing:
+		err = fmt.Errorf("awaiting reply to our own parameters")
+	default:
+		err = channel.ValidateRemoteParams(params)
+		if err == nil {
+			err = channel.UpdateParams(params, false)
+		}
+	}
+	if err != nil {
+		fmt.Printf("rejecting parameters for channel %v from peer "+
+			"%v: %v\n", propose.ChannelID, p.peerID, err)
+
+		p.queueMsg(&lnwire.DynReject{ChannelID: chanID}, nil)
+	} else {
+		p.queueMsg(&lnwire.DynAck{ChannelID: chanID}, nil)
+	}
+
+	// Either way, the renegotiation is over, so the channel no longer
+	// needs to be quiescent.
+	p.resumeChannel()
+}
+
+// handleDynReply processes the peer's reply to the parameters we proposed,
+// applying them should the peer accept them.
+func (p *peer) handleDynReply(msg lnwire.Message) {
+	p.dynMtx.Lock()
+	proposal := p.dynPending
+	p.dynMtx.Unlock()
+
+	if proposal == nil {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"replied to channel parameters we didn't propose")
+		return
+	}
+
+	var err error
+	switch msg.(type) {
+	case *lnwire.DynAck:
+		p.RLock()
+		channel := p.lnChannel
+		p.RUnlock()
+
+		err = channel.UpdateParams(proposal.params, true)
+
+	case *lnwire.DynReject:
+		err = ErrParamsRejected
+	}
+
+	select {
+	case proposal.result <- err:
+	default:
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"replied to our channel parameters twice")
+	}
+}
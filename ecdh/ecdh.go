@@ -0,0 +1,66 @@
+package ecdh
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/fastsha256"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// Both blinded paths, and the obfuscation of HTLC failures, have the sender
+// of a payment share a secret with each hop of its route, handing each hop
+// an ephemeral key derived from that of the hop before it:
+//
+//   e_0 = random session key, E_0 = e_0*G
+//   ss_i = sha256(x(e_i*N_i))
+//   e_{i+1} = sha256(E_i || ss_i)*e_i, E_{i+1} = sha256(E_i || ss_i)*E_i
+//
+// The secret is derived from the x coordinate of the shared point alone, as
+// returned by keychain.SingleKeyECDH, so that a hop keeping its identity key
+// within a device derives the same secret.
+
+// SharedSecret returns ss_i, the secret the private key shares with the
+// public key.
+func SharedSecret(priv *btcec.PrivateKey, pub *btcec.PublicKey) [32]byte {
+	// The in-memory signer only fails on a malformed point, which a
+	// scalar multiplication of a valid key never yields.
+	ss, _ := NodeSharedSecret(keychain.NewPrivKeySigner(priv), pub)
+	return ss
+}
+
+// NodeSharedSecret returns ss_i, the secret the key of the node shares with
+// the public key, without requiring the private key of the node.
+func NodeSharedSecret(nodeKey keychain.SingleKeyECDH,
+	pub *btcec.PublicKey) ([32]byte, error) {
+
+	x, err := nodeKey.ECDH(pub)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return fastsha256.Sum256(x), nil
+}
+
+// BlindingFactor returns sha256(E_i || ss_i), which both the ephemeral key,
+// and point, are tweaked by to derive those of the next hop.
+func BlindingFactor(ephemeralKey *btcec.PublicKey, ss [32]byte) []byte {
+	factor := fastsha256.Sum256(append(
+		ephemeralKey.SerializeCompressed(), ss[:]...))
+	return factor[:]
+}
+
+// TweakPubKey multiplies the public key by the tweak.
+func TweakPubKey(pub *btcec.PublicKey, tweak []byte) *btcec.PublicKey {
+	x, y := btcec.S256().ScalarMult(pub.X, pub.Y, tweak)
+	return &btcec.PublicKey{Curve: btcec.S256(), X: x, Y: y}
+}
+
+// TweakPrivKey multiplies the private key by the tweak.
+func TweakPrivKey(priv *btcec.PrivateKey, tweak []byte) *btcec.PrivateKey {
+	d := new(big.Int).Mul(priv.D, new(big.Int).SetBytes(tweak))
+	d.Mod(d, btcec.S256().N)
+
+	tweaked, _ := btcec.PrivKeyFromBytes(btcec.S256(), d.Bytes())
+	return tweaked
+}
@@ -0,0 +1,40 @@
+package ecdh
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+func TestSharedSecret(t *testing.T) {
+	ourKey, ourPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{1}, 32))
+	theirKey, theirPub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{2}, 32))
+
+	// Both ends derive the same secret, whether or not they hold their
+	// private key in memory.
+	ss := SharedSecret(ourKey, theirPub)
+	if SharedSecret(theirKey, ourPub) != ss {
+		t.Fatalf("secrets don't match")
+	}
+	nodeSS, err := NodeSharedSecret(keychain.NewPrivKeySigner(theirKey),
+		ourPub)
+	if err != nil {
+		t.Fatalf("unable to derive secret: %v", err)
+	}
+	if nodeSS != ss {
+		t.Fatalf("node secret doesn't match")
+	}
+
+	// Tweaking the ephemeral key, and point, by the blinding factor
+	// yields a matching key pair for the next hop.
+	factor := BlindingFactor(ourPub, ss)
+	nextKey := TweakPrivKey(ourKey, factor)
+	nextPub := TweakPubKey(ourPub, factor)
+	if !nextKey.PubKey().IsEqual(nextPub) {
+		t.Fatalf("tweaked keys don't match")
+	}
+}
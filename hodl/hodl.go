@@ -0,0 +1,125 @@
+package hodl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrHodl is returned in place of performing a step of the HTLC pipeline
+// which is being held by an active flag.
+var ErrHodl = errors.New("step held by hodl flag")
+
+// Flag is a step of the HTLC pipeline which may be held, for testing the
+// timeout, breach, and recovery paths which follow. A held step is dropped,
+// never being performed, so the HTLC it concerns remains pending.
+//
+// NOTE: These are for testing only, and are only honored in developer mode,
+// as any of them may lose funds.
+type Flag uint32
+
+const (
+	// ExitSettle holds the settlement of HTLCs paying our invoices, so
+	// their preimages are never released.
+	ExitSettle Flag = 1 << iota
+
+	// AddIncoming drops the HTLCs offered to us.
+	AddIncoming
+
+	// SettleIncoming holds the settlement of the HTLCs offered to us.
+	SettleIncoming
+
+	// AddOutgoing drops the HTLCs we offer.
+	AddOutgoing
+
+	// SettleOutgoing holds the settlement of the HTLCs we've offered.
+	SettleOutgoing
+
+	// Commit holds the commitment of each channel update, leaving it
+	// awaiting the counterparty's revocation.
+	Commit
+)
+
+// flagNames maps each flag to the name it's enabled by.
+var flagNames = map[Flag]string{
+	ExitSettle:     "exit-settle",
+	AddIncoming:    "add-incoming",
+	SettleIncoming: "settle-incoming",
+	AddOutgoing:    "add-outgoing",
+	SettleOutgoing: "settle-outgoing",
+	Commit:         "commit",
+}
+
+// String returns the name of the flag.
+func (f Flag) String() string {
+	if name, ok := flagNames[f]; ok {
+		return "hodl." + name
+	}
+	return fmt.Sprintf("hodl.unknown(%d)", uint32(f))
+}
+
+// Warning returns the message to log each time the flag holds a step.
+func (f Flag) Warning() string {
+	return fmt.Sprintf("%v is active, holding step of htlc pipeline", f)
+}
+
+// Mask is a set of active flags.
+type Mask uint32
+
+// MaskNone is the mask with no flags active.
+const MaskNone Mask = 0
+
+// MaskFromFlags returns the mask with the passed flags active.
+func MaskFromFlags(flags ...Flag) Mask {
+	var m Mask
+	for _, f := range flags {
+		m |= Mask(f)
+	}
+	return m
+}
+
+// ParseMask returns the mask with the flags named by the comma separated
+// list active, such as "exit-settle,commit".
+func ParseMask(s string) (Mask, error) {
+	var m Mask
+	if s == "" {
+		return m, nil
+	}
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "hodl.")
+
+		var found bool
+		for f, flagName := range flagNames {
+			if flagName == name {
+				m |= Mask(f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return MaskNone, fmt.Errorf("unknown hodl flag: %v", name)
+		}
+	}
+	return m, nil
+}
+
+// Active returns true if the flag is active within the mask.
+func (m Mask) Active(f Flag) bool {
+	return m&Mask(f) != 0
+}
+
+// String returns the names of the active flags, in the order they're
+// declared.
+func (m Mask) String() string {
+	var names []string
+	for f := ExitSettle; f <= Commit; f <<= 1 {
+		if m.Active(f) {
+			names = append(names, f.String())
+		}
+	}
+	if len(names) == 0 {
+		return "hodl.none"
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,54 @@
+package hodl
+
+import "testing"
+
+// TestParseMask ensures lists of flag names are parsed into masks with just
+// those flags active.
+func TestParseMask(t *testing.T) {
+	tests := []struct {
+		list string
+		mask Mask
+		err  bool
+	}{
+		{list: "", mask: MaskNone},
+		{list: "commit", mask: MaskFromFlags(Commit)},
+		{
+			list: "exit-settle, hodl.add-incoming",
+			mask: MaskFromFlags(ExitSettle, AddIncoming),
+		},
+		{list: "commit,bogus", err: true},
+	}
+
+	for _, test := range tests {
+		mask, err := ParseMask(test.list)
+		if test.err {
+			if err == nil {
+				t.Fatalf("expected error parsing %q", test.list)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unable to parse %q: %v", test.list, err)
+		}
+		if mask != test.mask {
+			t.Fatalf("parsing %q: expected %v, got %v", test.list,
+				test.mask, mask)
+		}
+	}
+}
+
+// TestMaskActive ensures only the flags a mask was created with are active.
+func TestMaskActive(t *testing.T) {
+	mask := MaskFromFlags(SettleIncoming, Commit)
+	for f := ExitSettle; f <= Commit; f <<= 1 {
+		want := f == SettleIncoming || f == Commit
+		if mask.Active(f) != want {
+			t.Fatalf("%v active=%v, expected %v", f, mask.Active(f),
+				want)
+		}
+	}
+
+	if s := mask.String(); s != "hodl.settle-incoming,hodl.commit" {
+		t.Fatalf("unexpected mask string: %v", s)
+	}
+}
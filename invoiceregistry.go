@@ -0,0 +1,551 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/amp"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// invoiceExpiryInterval is how often the expiry watcher scans for open
+	// invoices which are past their expiry.
+	invoiceExpiryInterval = time.Minute
+
+	// mppTimeout is the least time the first HTLC of an AMP set is held
+	// for, awaiting the rest of the set, before the set is canceled.
+	mppTimeout = time.Minute
+
+	// mppTimeoutJitter is the most time added at random to the hold of
+	// each set, so that probing nodes can't tell our partial sets apart
+	// by how long they're held.
+	mppTimeoutJitter = 30 * time.Second
+)
+
+var (
+	// errIncorrectPaymentDetails is returned for every HTLC we reject as
+	// the final hop: whether the invoice is unknown, expired, or already
+	// paid, the HTLC doesn't carry the payment secret of the invoice, or
+	// pays too little. As the same error is returned, after the same
+	// work, in every case, probing nodes learn nothing about the payment
+	// hash.
+	errIncorrectPaymentDetails = fmt.Errorf("incorrect or unknown " +
+		"payment details")
+
+	// errMPPTimeout is returned when the HTLC set of an AMP payment fails
+	// to complete before its hold passes.
+	errMPPTimeout = fmt.Errorf("htlc set timed out before completing")
+
+	// probeInvoice is checked against in place of an unknown invoice, so
+	// HTLCs paying to unknown payment hashes take as long to reject as
+	// those paying to known ones.
+	probeInvoice = &channeldb.Invoice{}
+)
+
+// invoiceEvent is sent to subscribers each time an invoice changes state.
+type invoiceEvent struct {
+	invoice *channeldb.Invoice
+	state   channeldb.InvoiceState
+
+	// setID is populated if the event is for a single payment to an AMP
+	// invoice, rather than the invoice as a whole.
+	setID *amp.SetID
+}
+
+// invoiceSubscription is a client subscribed to invoice state changes.
+type invoiceSubscription struct {
+	id       uint32
+	registry *invoiceRegistry
+
+	// Events is sent upon each time an invoice is settled or canceled.
+	Events chan *invoiceEvent
+}
+
+// Cancel unregisters the subscription, after which no further events will be
+// delivered.
+func (i *invoiceSubscription) Cancel() {
+	i.registry.clientMtx.Lock()
+	delete(i.registry.notificationClients, i.id)
+	i.registry.clientMtx.Unlock()
+}
+
+// invoiceRegistry is the central registry for all the invoices we've
+// created. In addition to wrapping the database, it watches for invoices
+// which have expired, canceling them, and garbage collects canceled invoices
+// once they're older than the configured retention period.
+type invoiceRegistry struct {
+	started  int32 // To be used atomically.
+	shutdown int32 // To be used atomically.
+
+	cdb *channeldb.DB
+
+	// canceledRetention is how long canceled invoices are kept around
+	// before being deleted. A value of zero retains them forever.
+	canceledRetention time.Duration
+
+	// hodlMask holds the settlement of invoices, for testing, if
+	// hodl.ExitSettle is active.
+	hodlMask hodl.Mask
+
+	// rejectZeroProbes rejects HTLCs paying to zero-value invoices whose
+	// final hop payload commits to no total amount, as only probes, and
+	// never real spontaneous payments, send those.
+	rejectZeroProbes bool
+
+	// checkInbound returns an error if an invoice of the amount can't
+	// possibly be paid over our channels.
+	checkInbound func(amt lnwire.MilliSatoshi) error
+
+	clientMtx           sync.Mutex
+	nextClientID        uint32
+	notificationClients map[uint32]*invoiceSubscription
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newInvoiceRegistry creates a new invoice registry backed by the passed
+// database.
+func newInvoiceRegistry(cdb *channeldb.DB, canceledRetention time.Duration,
+	hodlMask hodl.Mask, rejectZeroProbes bool,
+	checkInbound func(lnwire.MilliSatoshi) error) *invoiceRegistry {
+
+	return &invoiceRegistry{
+		cdb:                 cdb,
+		canceledRetention:   canceledRetention,
+		hodlMask:            hodlMask,
+		rejectZeroProbes:    rejectZeroProbes,
+		checkInbound:        checkInbound,
+		notificationClients: make(map[uint32]*invoiceSubscription),
+		quit:                make(chan struct{}),
+	}
+}
+
+// Start launches the expiry watcher.
+func (i *invoiceRegistry) Start() error {
+	if atomic.AddInt32(&i.started, 1) != 1 {
+		return nil
+	}
+
+	i.wg.Add(1)
+	go i.expiryWatcher()
+
+	return nil
+}
+
+// Stop signals the expiry watcher to exit, and waits for it to do so.
+func (i *invoiceRegistry) Stop() error {
+	if atomic.AddInt32(&i.shutdown, 1) != 1 {
+		return nil
+	}
+
+	close(i.quit)
+	i.wg.Wait()
+
+	return nil
+}
+
+// AddInvoice adds a new invoice to the registry. If the invoice doesn't yet
+// have a payment secret, a random one is generated. Invoices larger than our
+// peers are able to pay us over our channels are refused, as they'd never be
+// paid.
+func (i *invoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
+	if err := i.checkInbound(invoice.Value); err != nil {
+		return err
+	}
+
+	if invoice.PaymentSecret == [32]byte{} {
+		if _, err := rand.Read(invoice.PaymentSecret[:]); err != nil {
+			return err
+		}
+	}
+
+	return i.cdb.AddInvoice(invoice)
+}
+
+// LookupInvoice looks up the invoice paid to by the passed payment hash.
+func (i *invoiceRegistry) LookupInvoice(paymentHash [20]byte) (*channeldb.Invoice, error) {
+	return i.cdb.LookupInvoice(paymentHash)
+}
+
+// SettleInvoice marks the invoice as settled, notifying all subscribers.
+func (i *invoiceRegistry) SettleInvoice(paymentHash [20]byte) error {
+	if i.hodlMask.Active(hodl.ExitSettle) {
+		fmt.Println(hodl.ExitSettle.Warning())
+		return hodl.ErrHodl
+	}
+
+	if err := i.cdb.SettleInvoice(paymentHash); err != nil {
+		return err
+	}
+
+	return i.notifyClients(paymentHash)
+}
+
+// CancelInvoice marks the invoice as canceled, notifying all subscribers.
+func (i *invoiceRegistry) CancelInvoice(paymentHash [20]byte) error {
+	if err := i.cdb.CancelInvoice(paymentHash); err != nil {
+		return err
+	}
+
+	return i.notifyClients(paymentHash)
+}
+
+// AcceptHTLC validates an incoming HTLC for which we're the final hop,
+// returning the invoice it pays to. The HTLC is rejected unless its final hop
+// payload carries the payment secret of the invoice, and it pays at least the
+// invoice's value. Every rejection is the same errIncorrectPaymentDetails.
+func (i *invoiceRegistry) AcceptHTLC(paymentHash [20]byte, amt lnwire.MilliSatoshi,
+	payload *lnwire.FinalHopPayload) (*channeldb.Invoice, error) {
+
+	invoice, err := i.lookupHTLCInvoice(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each check is made regardless of the outcome of those before it,
+	// so the time taken doesn't reveal which failed.
+	valid := checkPaymentSecret(invoice, payload)
+	valid = i.checkProbe(invoice, payload) && valid
+	valid = checkInvoicePayable(invoice, time.Now()) && valid
+	valid = !invoice.AMP && amt >= invoice.Value && valid
+	if !valid || invoice == probeInvoice {
+		return nil, errIncorrectPaymentDetails
+	}
+
+	return invoice, nil
+}
+
+// lookupHTLCInvoice returns the invoice an incoming HTLC pays to, or
+// probeInvoice if there's no such invoice.
+func (i *invoiceRegistry) lookupHTLCInvoice(
+	paymentHash [20]byte) (*channeldb.Invoice, error) {
+
+	invoice, err := i.cdb.LookupInvoice(paymentHash)
+	switch {
+	case err == channeldb.ErrInvoiceNotFound:
+		return probeInvoice, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// checkPaymentSecret returns true if the final hop payload carries the
+// payment secret of the invoice.
+func checkPaymentSecret(invoice *channeldb.Invoice,
+	payload *lnwire.FinalHopPayload) bool {
+
+	var secret [32]byte
+	if payload != nil {
+		secret = payload.PaymentSecret
+	}
+
+	match := subtle.ConstantTimeCompare(invoice.PaymentSecret[:],
+		secret[:]) == 1
+
+	return match && payload != nil
+}
+
+// checkInvoicePayable returns true if the invoice is open, and has yet to
+// expire.
+func checkInvoicePayable(invoice *channeldb.Invoice, now time.Time) bool {
+	return invoice.State == channeldb.InvoiceOpen && !invoice.IsExpired(now)
+}
+
+// checkProbe returns false if probes are rejected, and the HTLC is a probe:
+// it pays to a zero-value invoice without committing to a total amount.
+func (i *invoiceRegistry) checkProbe(invoice *channeldb.Invoice,
+	payload *lnwire.FinalHopPayload) bool {
+
+	if !i.rejectZeroProbes || invoice.Value != 0 {
+		return true
+	}
+
+	return payload != nil && payload.TotalAmount != 0
+}
+
+// failCode returns the failure code an HTLC rejected as the final hop with
+// the passed error is failed back with. Rejections of the payment itself all
+// share CodeIncorrectPaymentDetails, while any other error, such as that of
+// the database, is a failure of our node.
+func failCode(err error) lnwire.FailCode {
+	switch err {
+	case errIncorrectPaymentDetails:
+		return lnwire.CodeIncorrectPaymentDetails
+	case errMPPTimeout:
+		return lnwire.CodeMPPTimeout
+	default:
+		return lnwire.CodeTemporaryNodeFailure
+	}
+}
+
+// AcceptAMPHTLC records an HTLC paying to an AMP invoice. Once the HTLCs of
+// the set add up to the value of the invoice, the receiver attempts to
+// reconstruct the root seed of the payment from their shares. If successful,
+// the set is settled and the preimages of each HTLC in the set are returned,
+// keyed by child index. Otherwise nil is returned, and we continue to wait for
+// further HTLCs of the set to arrive. As with regular HTLCs, each HTLC of the
+// set must carry the payment secret of the invoice. Should the set fail to
+// complete within its hold, a duration randomized for each set, the set is
+// canceled, and its HTLCs are to be failed with errMPPTimeout.
+func (i *invoiceRegistry) AcceptAMPHTLC(paymentHash [20]byte, setID amp.SetID,
+	htlc *channeldb.InvoiceHTLC,
+	payload *lnwire.FinalHopPayload) (map[uint32][20]byte, error) {
+
+	existingInvoice, err := i.lookupHTLCInvoice(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := checkPaymentSecret(existingInvoice, payload)
+	valid = i.checkProbe(existingInvoice, payload) && valid
+	valid = checkInvoicePayable(existingInvoice, time.Now()) && valid
+	valid = existingInvoice.AMP && valid
+	if !valid || existingInvoice == probeInvoice {
+		return nil, errIncorrectPaymentDetails
+	}
+
+	// A set that's already been canceled timed out before we received
+	// this HTLC of it.
+	if htlcSet, ok := existingInvoice.HTLCSets[setID]; ok &&
+		htlcSet.State == channeldb.InvoiceCanceled {
+
+		return nil, errMPPTimeout
+	}
+
+	invoice, err := i.cdb.AddInvoiceHTLC(paymentHash, setID, htlc)
+	if err != nil {
+		return nil, err
+	}
+
+	htlcSet := invoice.HTLCSets[setID]
+	if htlcSet.Total() < invoice.Value {
+		// The hold of the set starts with its first HTLC.
+		if len(htlcSet.HTLCs) == 1 {
+			hold, err := mppHoldDuration()
+			if err != nil {
+				return nil, err
+			}
+
+			i.wg.Add(1)
+			go i.holdHTLCSet(paymentHash, setID, hold)
+		}
+
+		return nil, nil
+	}
+
+	// Order the shares by their index within the set, ensuring we have no
+	// gaps, as the reconstruction relies on each share being at its child
+	// index.
+	htlcs := make([]*channeldb.InvoiceHTLC, len(htlcSet.HTLCs))
+	copy(htlcs, htlcSet.HTLCs)
+	sort.Sort(htlcsByIndex(htlcs))
+
+	shares := make([]amp.Share, len(htlcs))
+	for j, h := range htlcs {
+		if h.ChildIndex != uint32(j) {
+			return nil, nil
+		}
+		shares[j] = amp.Share(h.Share)
+	}
+
+	// The set ID commits to the root seed, so if the root we reconstruct
+	// doesn't match then we're still missing some shares.
+	root, children := amp.Reconstruct(shares)
+	if amp.NewSetID(root) != setID {
+		return nil, nil
+	}
+
+	if err := i.cdb.SettleHTLCSet(paymentHash, setID); err != nil {
+		return nil, err
+	}
+
+	preimages := make(map[uint32][20]byte, len(children))
+	for _, child := range children {
+		preimages[child.Index] = child.Preimage
+	}
+
+	settledInvoice, err := i.cdb.LookupInvoice(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+	i.dispatchEvent(&invoiceEvent{
+		invoice: settledInvoice,
+		state:   channeldb.InvoiceSettled,
+		setID:   &setID,
+	})
+
+	return preimages, nil
+}
+
+// mppHoldDuration returns how long a partial HTLC set is held for: at least
+// mppTimeout, plus a random jitter of up to mppTimeoutJitter.
+func mppHoldDuration() (time.Duration, error) {
+	maxJitter := big.NewInt(int64(mppTimeoutJitter))
+	jitter, err := rand.Int(rand.Reader, maxJitter)
+	if err != nil {
+		return 0, err
+	}
+
+	return mppTimeout + time.Duration(jitter.Int64()), nil
+}
+
+// holdHTLCSet cancels the HTLC set of the AMP invoice should it still be
+// open once the hold passes, notifying all subscribers so the HTLCs of the
+// set can be failed back.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *invoiceRegistry) holdHTLCSet(paymentHash [20]byte, setID amp.SetID,
+	hold time.Duration) {
+
+	defer i.wg.Done()
+
+	select {
+	case <-time.After(hold):
+	case <-i.quit:
+		return
+	}
+
+	invoice, err := i.cdb.LookupInvoice(paymentHash)
+	if err != nil {
+		fmt.Printf("unable to look up invoice %x: %v\n", paymentHash,
+			err)
+		return
+	}
+	htlcSet, ok := invoice.HTLCSets[setID]
+	if !ok || htlcSet.State != channeldb.InvoiceOpen {
+		return
+	}
+
+	if err := i.cdb.CancelHTLCSet(paymentHash, setID); err != nil {
+		fmt.Printf("unable to cancel htlc set %x: %v\n", setID[:], err)
+		return
+	}
+
+	canceledInvoice, err := i.cdb.LookupInvoice(paymentHash)
+	if err != nil {
+		fmt.Printf("unable to look up invoice %x: %v\n", paymentHash,
+			err)
+		return
+	}
+	i.dispatchEvent(&invoiceEvent{
+		invoice: canceledInvoice,
+		state:   channeldb.InvoiceCanceled,
+		setID:   &setID,
+	})
+}
+
+// htlcsByIndex sorts the HTLCs of a set by their child index.
+type htlcsByIndex []*channeldb.InvoiceHTLC
+
+func (h htlcsByIndex) Len() int           { return len(h) }
+func (h htlcsByIndex) Less(i, j int) bool { return h[i].ChildIndex < h[j].ChildIndex }
+func (h htlcsByIndex) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+// SubscribeNotifications returns a new subscription which receives an event
+// each time an invoice is settled or canceled.
+func (i *invoiceRegistry) SubscribeNotifications() *invoiceSubscription {
+	i.clientMtx.Lock()
+	defer i.clientMtx.Unlock()
+
+	client := &invoiceSubscription{
+		id:       i.nextClientID,
+		registry: i,
+		Events:   make(chan *invoiceEvent, 20),
+	}
+	i.notificationClients[client.id] = client
+	i.nextClientID++
+
+	return client
+}
+
+// notifyClients sends the latest state of the invoice to all subscribers.
+func (i *invoiceRegistry) notifyClients(paymentHash [20]byte) error {
+	invoice, err := i.cdb.LookupInvoice(paymentHash)
+	if err != nil {
+		return err
+	}
+
+	i.dispatchEvent(&invoiceEvent{invoice: invoice, state: invoice.State})
+	return nil
+}
+
+// dispatchEvent sends the event to all subscribers.
+func (i *invoiceRegistry) dispatchEvent(event *invoiceEvent) {
+	i.clientMtx.Lock()
+	defer i.clientMtx.Unlock()
+	for _, client := range i.notificationClients {
+		// Don't let a slow subscriber hold up the registry.
+		// TODO: queue per client instead of dropping
+		select {
+		case client.Events <- event:
+		default:
+		}
+	}
+}
+
+// expiryWatcher periodically cancels any open invoices which have expired,
+// and garbage collects old canceled invoices.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *invoiceRegistry) expiryWatcher() {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(invoiceExpiryInterval)
+	defer ticker.Stop()
+
+	// Sweep once at start up to catch any invoices which expired while we
+	// were offline.
+	i.sweepInvoices(time.Now())
+
+	for {
+		select {
+		case now := <-ticker.C:
+			i.sweepInvoices(now)
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// sweepInvoices cancels all expired invoices, and deletes canceled invoices
+// which have outlived the retention period.
+func (i *invoiceRegistry) sweepInvoices(now time.Time) {
+	invoices, err := i.cdb.FetchAllInvoices(false)
+	if err != nil {
+		fmt.Printf("unable to fetch invoices: %v\n", err)
+		return
+	}
+
+	for _, invoice := range invoices {
+		paymentHash := invoice.PaymentHash()
+
+		switch {
+		case invoice.IsExpired(now):
+			if err := i.CancelInvoice(paymentHash); err != nil {
+				fmt.Printf("unable to cancel expired invoice "+
+					"%x: %v\n", paymentHash, err)
+			}
+
+		case invoice.State == channeldb.InvoiceCanceled &&
+			i.canceledRetention != 0 &&
+			now.Sub(invoice.StateChangeDate) > i.canceledRetention:
+
+			if err := i.cdb.DeleteInvoice(paymentHash); err != nil {
+				fmt.Printf("unable to delete canceled invoice "+
+					"%x: %v\n", paymentHash, err)
+			}
+		}
+	}
+}
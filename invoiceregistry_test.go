@@ -0,0 +1,305 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// createTestRegistry creates an invoice registry backed by a fresh database,
+// which refuses invoices larger than maxInbound.
+func createTestRegistry(t *testing.T, canceledRetention time.Duration,
+	maxInbound lnwire.MilliSatoshi) (*invoiceRegistry, func()) {
+
+	cdb, cleanUp := createTestDB(t)
+	registry := newInvoiceRegistry(cdb, canceledRetention, hodl.MaskNone,
+		false, func(amt lnwire.MilliSatoshi) error {
+			if amt > maxInbound {
+				return ErrInsufficientInbound
+			}
+			return nil
+		})
+
+	return registry, func() {
+		registry.Stop()
+		cleanUp()
+	}
+}
+
+// TestAddInvoice asserts invoices which can't be paid over our channels are
+// refused, and that those added without a payment secret are given one.
+func TestAddInvoice(t *testing.T) {
+	registry, cleanUp := createTestRegistry(t, 0, 100000)
+	defer cleanUp()
+
+	tests := []struct {
+		name   string
+		value  lnwire.MilliSatoshi
+		secret [32]byte
+		err    error
+	}{
+		{
+			name:  "generated secret",
+			value: 100000,
+		},
+		{
+			name:   "given secret",
+			value:  1000,
+			secret: [32]byte{1},
+		},
+		{
+			name:  "insufficient inbound",
+			value: 100001,
+			err:   ErrInsufficientInbound,
+		},
+	}
+
+	for i, test := range tests {
+		invoice := &channeldb.Invoice{
+			Preimage:      [20]byte{byte(i + 1)},
+			PaymentSecret: test.secret,
+			Value:         test.value,
+			CreationDate:  time.Now(),
+		}
+		err := registry.AddInvoice(invoice)
+		if err != test.err {
+			t.Fatalf("%v: expected error %v, instead %v", test.name,
+				test.err, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		stored, err := registry.LookupInvoice(invoice.PaymentHash())
+		if err != nil {
+			t.Fatalf("%v: unable to lookup invoice: %v", test.name,
+				err)
+		}
+		switch {
+		case stored.PaymentSecret == [32]byte{}:
+			t.Fatalf("%v: invoice stored without payment secret",
+				test.name)
+		case test.secret != [32]byte{} &&
+			stored.PaymentSecret != test.secret:
+
+			t.Fatalf("%v: expected payment secret %x, instead %x",
+				test.name, test.secret, stored.PaymentSecret)
+		}
+	}
+}
+
+// TestAcceptHTLC asserts HTLCs are only accepted if they carry the payment
+// secret of an open invoice, and pay at least its value.
+func TestAcceptHTLC(t *testing.T) {
+	registry, cleanUp := createTestRegistry(t, 0, 1000000)
+	defer cleanUp()
+
+	const value = lnwire.MilliSatoshi(100000)
+
+	open := &channeldb.Invoice{
+		Preimage:     [20]byte{1},
+		Value:        value,
+		CreationDate: time.Now(),
+	}
+	ampInvoice := &channeldb.Invoice{
+		Preimage:     [20]byte{2},
+		Value:        value,
+		CreationDate: time.Now(),
+		AMP:          true,
+	}
+	for _, invoice := range []*channeldb.Invoice{open, ampInvoice} {
+		if err := registry.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		paymentHash [20]byte
+		amt         lnwire.MilliSatoshi
+		payload     *lnwire.FinalHopPayload
+		err         error
+	}{
+		{
+			name:        "exact value",
+			paymentHash: open.PaymentHash(),
+			amt:         value,
+			payload: &lnwire.FinalHopPayload{
+				PaymentSecret: open.PaymentSecret,
+			},
+		},
+		{
+			name:        "overpaid",
+			paymentHash: open.PaymentHash(),
+			amt:         value + 1,
+			payload: &lnwire.FinalHopPayload{
+				PaymentSecret: open.PaymentSecret,
+			},
+		},
+		{
+			name:        "underpaid",
+			paymentHash: open.PaymentHash(),
+			amt:         value - 1,
+			payload: &lnwire.FinalHopPayload{
+				PaymentSecret: open.PaymentSecret,
+			},
+			err: errIncorrectPaymentDetails,
+		},
+		{
+			name:        "no payload",
+			paymentHash: open.PaymentHash(),
+			amt:         value,
+			err:         errIncorrectPaymentDetails,
+		},
+		{
+			name:        "amp invoice",
+			paymentHash: ampInvoice.PaymentHash(),
+			amt:         value,
+			payload: &lnwire.FinalHopPayload{
+				PaymentSecret: ampInvoice.PaymentSecret,
+			},
+			err: errIncorrectPaymentDetails,
+		},
+		{
+			name:        "unknown payment hash",
+			paymentHash: [20]byte{0xff},
+			amt:         value,
+			payload: &lnwire.FinalHopPayload{
+				PaymentSecret: open.PaymentSecret,
+			},
+			err: errIncorrectPaymentDetails,
+		},
+	}
+
+	for _, test := range tests {
+		invoice, err := registry.AcceptHTLC(test.paymentHash, test.amt,
+			test.payload)
+		if err != test.err {
+			t.Fatalf("%v: expected error %v, instead %v", test.name,
+				test.err, err)
+		}
+		if err == nil && invoice.PaymentHash() != test.paymentHash {
+			t.Fatalf("%v: accepted htlc for wrong invoice %x",
+				test.name, invoice.PaymentHash())
+		}
+	}
+}
+
+// TestSweepInvoices asserts expired invoices are canceled, and canceled
+// invoices are deleted once they're older than the retention period.
+func TestSweepInvoices(t *testing.T) {
+	const retention = 24 * time.Hour
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		retention time.Duration
+		invoice   *channeldb.Invoice
+
+		// state is the state the invoice is expected to be left in,
+		// and deleted whether it's expected to be removed instead.
+		state   channeldb.InvoiceState
+		deleted bool
+	}{
+		{
+			name:      "unexpired",
+			retention: retention,
+			invoice: &channeldb.Invoice{
+				CreationDate: now,
+				Expiry:       time.Hour,
+				State:        channeldb.InvoiceOpen,
+			},
+			state: channeldb.InvoiceOpen,
+		},
+		{
+			name:      "never expires",
+			retention: retention,
+			invoice: &channeldb.Invoice{
+				CreationDate: now.Add(-48 * time.Hour),
+				State:        channeldb.InvoiceOpen,
+			},
+			state: channeldb.InvoiceOpen,
+		},
+		{
+			name:      "expired",
+			retention: retention,
+			invoice: &channeldb.Invoice{
+				CreationDate: now.Add(-2 * time.Hour),
+				Expiry:       time.Hour,
+				State:        channeldb.InvoiceOpen,
+			},
+			state: channeldb.InvoiceCanceled,
+		},
+		{
+			name:      "canceled within retention",
+			retention: retention,
+			invoice: &channeldb.Invoice{
+				CreationDate:    now.Add(-2 * time.Hour),
+				State:           channeldb.InvoiceCanceled,
+				StateChangeDate: now.Add(-time.Hour),
+			},
+			state: channeldb.InvoiceCanceled,
+		},
+		{
+			name:      "canceled past retention",
+			retention: retention,
+			invoice: &channeldb.Invoice{
+				CreationDate:    now.Add(-48 * time.Hour),
+				State:           channeldb.InvoiceCanceled,
+				StateChangeDate: now.Add(-retention - time.Hour),
+			},
+			deleted: true,
+		},
+		{
+			name: "canceled, retained forever",
+			invoice: &channeldb.Invoice{
+				CreationDate:    now.Add(-48 * time.Hour),
+				State:           channeldb.InvoiceCanceled,
+				StateChangeDate: now.Add(-retention - time.Hour),
+			},
+			state: channeldb.InvoiceCanceled,
+		},
+		{
+			name:      "settled",
+			retention: retention,
+			invoice: &channeldb.Invoice{
+				CreationDate:    now.Add(-48 * time.Hour),
+				Expiry:          time.Hour,
+				State:           channeldb.InvoiceSettled,
+				StateChangeDate: now.Add(-retention - time.Hour),
+			},
+			state: channeldb.InvoiceSettled,
+		},
+	}
+
+	for _, test := range tests {
+		registry, cleanUp := createTestRegistry(t, test.retention, 0)
+
+		paymentHash := test.invoice.PaymentHash()
+		if err := registry.cdb.AddInvoice(test.invoice); err != nil {
+			cleanUp()
+			t.Fatalf("%v: unable to add invoice: %v", test.name, err)
+		}
+
+		registry.sweepInvoices(now)
+
+		invoice, err := registry.LookupInvoice(paymentHash)
+		cleanUp()
+
+		switch {
+		case test.deleted && err == channeldb.ErrInvoiceNotFound:
+		case test.deleted:
+			t.Fatalf("%v: expected invoice to be deleted, instead "+
+				"err=%v", test.name, err)
+		case err != nil:
+			t.Fatalf("%v: unable to lookup invoice: %v", test.name,
+				err)
+		case invoice.State != test.state:
+			t.Fatalf("%v: expected state %v, instead %v", test.name,
+				test.state, invoice.State)
+		}
+	}
+}
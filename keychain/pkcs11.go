@@ -0,0 +1,279 @@
+//go:build pkcs11
+// +build pkcs11
+
+package keychain
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/miekg/pkcs11"
+)
+
+var (
+	// ErrKeyNotFound is returned when the device holds no secp256k1 key
+	// pair under the requested label.
+	ErrKeyNotFound = errors.New("no key pair with that label on the device")
+
+	// ErrNotSecp256k1 is returned when the key pair under the requested
+	// label is on a curve other than secp256k1.
+	ErrNotSecp256k1 = errors.New("key pair isn't on the secp256k1 curve")
+)
+
+// secp256k1OID is the DER encoded object identifier of the secp256k1 curve,
+// as found within the CKA_EC_PARAMS of a key.
+var secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+// PKCS11Signer is a NodeSigner backed by a key pair kept within a hardware
+// security module, or any other device accessed through a PKCS#11 module.
+// The private key never leaves the device: the shared secrets of the
+// handshake are derived, and hashes signed, by the device itself. The key
+// pair must be generated on the device beforehand, for instance with:
+//
+//	pkcs11-tool --keypairgen --key-type EC:secp256k1 --label lnd
+type PKCS11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	privKey pkcs11.ObjectHandle
+	pubKey  *btcec.PublicKey
+
+	// The mutex serializes our use of the session, as PKCS#11 sessions
+	// may not be used concurrently.
+	sync.Mutex
+}
+
+// NewPKCS11Signer loads the PKCS#11 module at modulePath, logs into the
+// token within the passed slot, and locates the key pair labelled label.
+func NewPKCS11Signer(modulePath string, slot uint, pin,
+	label string) (*PKCS11Signer, error) {
+
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("unable to load PKCS#11 module %v",
+			modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, err
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+
+	p := &PKCS11Signer{
+		ctx:     ctx,
+		session: session,
+	}
+
+	err = ctx.Login(session, pkcs11.CKU_USER, pin)
+	if err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		p.Close()
+		return nil, err
+	}
+
+	if err := p.loadKeyPair(label); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// loadKeyPair locates the private key labelled label, and reads the public
+// key of the pair.
+func (p *PKCS11Signer) loadKeyPair(label string) error {
+	privKey, err := p.findObject(pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return err
+	}
+	pubKey, err := p.findObject(pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return err
+	}
+
+	attrs, err := p.ctx.GetAttributeValue(p.session, pubKey,
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+		})
+	if err != nil {
+		return err
+	}
+
+	var params, point []byte
+	for _, attr := range attrs {
+		switch attr.Type {
+		case pkcs11.CKA_EC_PARAMS:
+			params = attr.Value
+		case pkcs11.CKA_EC_POINT:
+			point = attr.Value
+		}
+	}
+	if !bytes.Equal(params, secp256k1OID) {
+		return ErrNotSecp256k1
+	}
+
+	p.pubKey, err = parseECPoint(point)
+	if err != nil {
+		return err
+	}
+	p.privKey = privKey
+
+	return nil
+}
+
+// findObject returns the EC key of the passed class labelled label.
+func (p *PKCS11Signer) findObject(class uint,
+	label string) (pkcs11.ObjectHandle, error) {
+
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := p.ctx.FindObjectsInit(p.session, template); err != nil {
+		return 0, err
+	}
+	objects, _, err := p.ctx.FindObjects(p.session, 1)
+	if finalErr := p.ctx.FindObjectsFinal(p.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(objects) == 0 {
+		return 0, ErrKeyNotFound
+	}
+
+	return objects[0], nil
+}
+
+// parseECPoint parses the CKA_EC_POINT of a public key. The point is meant
+// to be wrapped within a DER octet string, though some modules return it
+// bare.
+func parseECPoint(point []byte) (*btcec.PublicKey, error) {
+	var unwrapped []byte
+	rest, err := asn1.Unmarshal(point, &unwrapped)
+	if err == nil && len(rest) == 0 {
+		point = unwrapped
+	}
+
+	return btcec.ParsePubKey(point, btcec.S256())
+}
+
+// PubKey returns the public key of the key pair.
+//
+// NOTE: Part of the NodeSigner interface.
+func (p *PKCS11Signer) PubKey() *btcec.PublicKey {
+	return p.pubKey
+}
+
+// ECDH has the device derive the secret the private key shares with the
+// public key.
+//
+// NOTE: Part of the NodeSigner interface.
+func (p *PKCS11Signer) ECDH(pub *btcec.PublicKey) ([]byte, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	params := pkcs11.NewECDH1DeriveParams(pkcs11.CKD_NULL, nil,
+		pub.SerializeUncompressed())
+	mechanism := []*pkcs11.Mechanism{
+		pkcs11.NewMechanism(pkcs11.CKM_ECDH1_DERIVE, params),
+	}
+
+	// The secret is derived as a session object, which we read, then
+	// destroy.
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_GENERIC_SECRET),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, true),
+	}
+	secret, err := p.ctx.DeriveKey(p.session, mechanism, p.privKey,
+		template)
+	if err != nil {
+		return nil, err
+	}
+	defer p.ctx.DestroyObject(p.session, secret)
+
+	attrs, err := p.ctx.GetAttributeValue(p.session, secret,
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(attrs) != 1 {
+		return nil, fmt.Errorf("device derived a malformed secret")
+	}
+
+	return padSecret(attrs[0].Value)
+}
+
+// SignHash has the device sign the hash with the private key.
+//
+// NOTE: Part of the NodeSigner interface.
+func (p *PKCS11Signer) SignHash(hash []byte) (*btcec.Signature, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	mechanism := []*pkcs11.Mechanism{
+		pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil),
+	}
+	if err := p.ctx.SignInit(p.session, mechanism, p.privKey); err != nil {
+		return nil, err
+	}
+	sig, err := p.ctx.Sign(p.session, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRawSignature(sig)
+}
+
+// parseRawSignature parses the r || s signature of CKM_ECDSA. Devices are
+// free to return a high S value, so S is normalized to the lower of its two
+// values, as bitcoin requires.
+func parseRawSignature(sig []byte) (*btcec.Signature, error) {
+	if len(sig) != 64 {
+		return nil, fmt.Errorf("device returned a %v byte signature",
+			len(sig))
+	}
+
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+
+	order := btcec.S256().N
+	if s.Cmp(new(big.Int).Rsh(order, 1)) > 0 {
+		s.Sub(order, s)
+	}
+
+	return &btcec.Signature{R: r, S: s}, nil
+}
+
+// Close logs out of the token, and unloads the PKCS#11 module.
+func (p *PKCS11Signer) Close() error {
+	p.Lock()
+	defer p.Unlock()
+
+	p.ctx.Logout(p.session)
+	p.ctx.CloseSession(p.session)
+	err := p.ctx.Finalize()
+	p.ctx.Destroy()
+
+	return err
+}
+
+var _ NodeSigner = (*PKCS11Signer)(nil)
@@ -0,0 +1,69 @@
+//go:build pkcs11
+// +build pkcs11
+
+package keychain
+
+import (
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestParseECPoint(t *testing.T) {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	point := key.PubKey().SerializeUncompressed()
+
+	// The point may be wrapped within an octet string, or bare.
+	wrapped, err := asn1.Marshal(point)
+	if err != nil {
+		t.Fatalf("unable to wrap point: %v", err)
+	}
+	for _, encoded := range [][]byte{wrapped, point} {
+		pub, err := parseECPoint(encoded)
+		if err != nil {
+			t.Fatalf("unable to parse point: %v", err)
+		}
+		if !pub.IsEqual(key.PubKey()) {
+			t.Fatalf("parsed the wrong point")
+		}
+	}
+}
+
+func TestParseRawSignature(t *testing.T) {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	hash := wire.DoubleSha256([]byte("hello"))
+	sig, err := key.Sign(hash)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+
+	// Encode the signature as a device might, with a high S value.
+	var raw [64]byte
+	highS := new(big.Int).Sub(btcec.S256().N, sig.S)
+	copy(raw[32-len(sig.R.Bytes()):32], sig.R.Bytes())
+	copy(raw[64-len(highS.Bytes()):], highS.Bytes())
+
+	parsed, err := parseRawSignature(raw[:])
+	if err != nil {
+		t.Fatalf("unable to parse signature: %v", err)
+	}
+	if parsed.S.Cmp(sig.S) != 0 {
+		t.Fatalf("S wasn't normalized")
+	}
+	if !parsed.Verify(hash, key.PubKey()) {
+		t.Fatalf("signature doesn't verify")
+	}
+
+	if _, err := parseRawSignature(raw[:63]); err == nil {
+		t.Fatalf("expected short signature to be rejected")
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+package keychain
+
+import (
+	"errors"
+)
+
+// ErrPKCS11Unsupported is returned when opening a PKCS#11 device, should
+// lnd have been built without the pkcs11 build tag.
+var ErrPKCS11Unsupported = errors.New("lnd was built without PKCS#11 " +
+	"support, rebuild it with the pkcs11 build tag")
+
+// PKCS11Signer stands in for the signer of a PKCS#11 device, which can't be
+// opened as lnd was built without the pkcs11 build tag.
+type PKCS11Signer struct {
+	NodeSigner
+}
+
+// NewPKCS11Signer always returns ErrPKCS11Unsupported.
+func NewPKCS11Signer(modulePath string, slot uint, pin,
+	label string) (*PKCS11Signer, error) {
+
+	return nil, ErrPKCS11Unsupported
+}
+
+// Close is a no-op.
+func (p *PKCS11Signer) Close() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package keychain
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// SingleKeyECDH performs ECDH with a single private key, which it never
+// exposes. It's all the handshake of a connection needs of our identity
+// key.
+type SingleKeyECDH interface {
+	// PubKey returns the public key of the private key.
+	PubKey() *btcec.PublicKey
+
+	// ECDH returns the x coordinate of the point the private key and the
+	// passed public key share, as 32 bytes, left padded with zeros.
+	ECDH(pub *btcec.PublicKey) ([]byte, error)
+}
+
+// NodeSigner performs each operation requiring the identity key of the
+// node, without exposing it, so the key may be kept within a hardware
+// security module.
+type NodeSigner interface {
+	SingleKeyECDH
+
+	// SignHash signs the 32-byte hash with the private key, returning a
+	// signature with a low S value.
+	SignHash(hash []byte) (*btcec.Signature, error)
+}
+
+// PrivKeySigner is a NodeSigner backed by a private key held in memory.
+type PrivKeySigner struct {
+	privKey *btcec.PrivateKey
+}
+
+// NewPrivKeySigner creates a NodeSigner of the passed private key.
+func NewPrivKeySigner(privKey *btcec.PrivateKey) *PrivKeySigner {
+	return &PrivKeySigner{privKey: privKey}
+}
+
+// PubKey returns the public key of the private key.
+//
+// NOTE: Part of the NodeSigner interface.
+func (p *PrivKeySigner) PubKey() *btcec.PublicKey {
+	return p.privKey.PubKey()
+}
+
+// ECDH returns the secret the private key shares with the public key.
+//
+// NOTE: Part of the NodeSigner interface.
+func (p *PrivKeySigner) ECDH(pub *btcec.PublicKey) ([]byte, error) {
+	return padSecret(btcec.GenerateSharedSecret(p.privKey, pub))
+}
+
+// SignHash signs the hash with the private key.
+//
+// NOTE: Part of the NodeSigner interface.
+func (p *PrivKeySigner) SignHash(hash []byte) (*btcec.Signature, error) {
+	return p.privKey.Sign(hash)
+}
+
+// padSecret left pads the x coordinate of a shared point to 32 bytes. Its
+// leading zero bytes are dropped by btcec, and may be by devices, which
+// would otherwise have either side of the exchange hash a different secret.
+func padSecret(secret []byte) ([]byte, error) {
+	if len(secret) == 0 || len(secret) > 32 {
+		return nil, fmt.Errorf("malformed shared secret of %v bytes",
+			len(secret))
+	}
+
+	padded := make([]byte, 32)
+	copy(padded[32-len(secret):], secret)
+	return padded, nil
+}
+
+var _ NodeSigner = (*PrivKeySigner)(nil)
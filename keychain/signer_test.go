@@ -0,0 +1,80 @@
+package keychain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestPrivKeySigner(t *testing.T) {
+	ourKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	theirKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	signer := NewPrivKeySigner(ourKey)
+	if !signer.PubKey().IsEqual(ourKey.PubKey()) {
+		t.Fatalf("signer has the wrong public key")
+	}
+
+	// Both ends must arrive at the same secret.
+	secret, err := signer.ECDH(theirKey.PubKey())
+	if err != nil {
+		t.Fatalf("unable to derive secret: %v", err)
+	}
+	theirSecret, err := NewPrivKeySigner(theirKey).ECDH(ourKey.PubKey())
+	if err != nil {
+		t.Fatalf("unable to derive secret: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Fatalf("expected secret of 32 bytes, got %v", len(secret))
+	}
+	if !bytes.Equal(secret, theirSecret) {
+		t.Fatalf("secrets don't match: %x vs %x", secret, theirSecret)
+	}
+
+	hash := wire.DoubleSha256([]byte("hello"))
+	sig, err := signer.SignHash(hash)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	if !sig.Verify(hash, ourKey.PubKey()) {
+		t.Fatalf("signature doesn't verify")
+	}
+}
+
+func TestPadSecret(t *testing.T) {
+	// Secrets whose x coordinate has leading zero bytes are left padded
+	// to 32 bytes.
+	short := bytes.Repeat([]byte{1}, 31)
+	padded, err := padSecret(short)
+	if err != nil {
+		t.Fatalf("unable to pad secret: %v", err)
+	}
+	expected := append([]byte{0}, short...)
+	if !bytes.Equal(padded, expected) {
+		t.Fatalf("expected %x, got %x", expected, padded)
+	}
+
+	full := bytes.Repeat([]byte{2}, 32)
+	padded, err = padSecret(full)
+	if err != nil {
+		t.Fatalf("unable to pad secret: %v", err)
+	}
+	if !bytes.Equal(padded, full) {
+		t.Fatalf("expected %x, got %x", full, padded)
+	}
+
+	for _, malformed := range [][]byte{nil, bytes.Repeat([]byte{3}, 33)} {
+		if _, err := padSecret(malformed); err == nil {
+			t.Fatalf("expected secret of %v bytes to be rejected",
+				len(malformed))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrInsufficientOutbound is returned when sending a payment larger
+	// than we're able to offer over our channels, once their reserves,
+	// and the HTLCs already in flight, are accounted for. Opening a new
+	// channel, or moving funds out of our channels, adds outbound
+	// liquidity.
+	ErrInsufficientOutbound = errors.New("insufficient outbound liquidity")
+
+	// ErrInsufficientInbound is returned when adding an invoice larger
+	// than our peers are able to offer us over our channels. Receiving a
+	// channel from a peer, or spending over our channels, adds inbound
+	// liquidity.
+	ErrInsufficientInbound = errors.New("insufficient inbound liquidity")
+)
+
+// channelLiquidity is how much we're able to send, and receive, over our
+// channels with connected peers.
+type channelLiquidity struct {
+	// sendable is the total we're able to offer over all our channels,
+	// and maxSendable the most we're able to offer over any one of them.
+	sendable    lnwire.MilliSatoshi
+	maxSendable lnwire.MilliSatoshi
+
+	// receivable is the total our peers are able to offer us over all our
+	// channels.
+	receivable lnwire.MilliSatoshi
+}
+
+// checkSend returns ErrInsufficientOutbound if a payment of the amount, split
+// into at most maxParts HTLCs, can't possibly be offered over our channels.
+// Payments which aren't split must fit within a single channel.
+func (c *channelLiquidity) checkSend(amt lnwire.MilliSatoshi,
+	maxParts uint32) error {
+
+	sendable := c.sendable
+	if maxParts <= 1 {
+		sendable = c.maxSendable
+	}
+	if amt > sendable {
+		return ErrInsufficientOutbound
+	}
+
+	return nil
+}
+
+// checkReceive returns ErrInsufficientInbound if an invoice of the amount
+// can't possibly be paid over our channels. Zero-value invoices leave the
+// amount to the payer, and so always pass.
+func (c *channelLiquidity) checkReceive(amt lnwire.MilliSatoshi) error {
+	if amt > c.receivable {
+		return ErrInsufficientInbound
+	}
+
+	return nil
+}
+
+// channelLiquidity returns how much we're able to send, and receive, over our
+// channels with connected peers.
+func (s *server) channelLiquidity() (*channelLiquidity, error) {
+	peers, err := s.ListPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	liquidity := &channelLiquidity{}
+	for _, p := range peers {
+		p.RLock()
+		channel := p.lnChannel
+		p.RUnlock()
+		if channel == nil {
+			continue
+		}
+
+		sendable := channel.SendableBalance()
+		liquidity.sendable += sendable
+		if sendable > liquidity.maxSendable {
+			liquidity.maxSendable = sendable
+		}
+		liquidity.receivable += channel.ReceivableBalance()
+	}
+
+	return liquidity, nil
+}
+
+// checkInboundLiquidity returns ErrInsufficientInbound if an invoice of the
+// amount can't possibly be paid over our channels.
+func (s *server) checkInboundLiquidity(amt lnwire.MilliSatoshi) error {
+	liquidity, err := s.channelLiquidity()
+	if err != nil {
+		return err
+	}
+
+	return liquidity.checkReceive(amt)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// netPorts are the default ports we listen on for a network, which differ
+// between networks so nodes on separate networks can run side by side.
+type netPorts struct {
+	peer int
+	rpc  int
+	rest int
+}
+
+// defaultNetPorts maps the name of each network to its default ports.
+var defaultNetPorts = map[string]netPorts{
+	chaincfg.MainNetParams.Name:       {peer: 9735, rpc: 10010, rest: 8080},
+	chaincfg.TestNet3Params.Name:      {peer: 10011, rpc: 10009, rest: 8081},
+	chaincfg.RegressionNetParams.Name: {peer: 10013, rpc: 10012, rest: 8082},
+	chaincfg.SimNetParams.Name:        {peer: 10015, rpc: 10014, rest: 8083},
+}
+
+// unixPrefix marks a listen address as being the path of a unix socket.
+const unixPrefix = "unix://"
+
+// addrFlag is a flag which may be passed multiple times, collecting each
+// address passed.
+type addrFlag []string
+
+// String returns the addresses, separated by commas.
+//
+// NOTE: Part of the flag.Value interface.
+func (a *addrFlag) String() string {
+	return strings.Join(*a, ",")
+}
+
+// Set adds the passed address.
+//
+// NOTE: Part of the flag.Value interface.
+func (a *addrFlag) Set(addr string) error {
+	*a = append(*a, addr)
+	return nil
+}
+
+// parseListenAddrs parses each of the passed listen addresses. Addresses are
+// either a unix socket, prefixed with "unix://", or an IPv4 or IPv6 host,
+// along with an optional port. If the port is omitted, the default port is
+// used, and an omitted host listens on all interfaces.
+func parseListenAddrs(addrs []string, defaultPort int) ([]net.Addr, error) {
+	parsed := make([]net.Addr, 0, len(addrs))
+	for _, addr := range addrs {
+		if strings.HasPrefix(addr, unixPrefix) {
+			path := strings.TrimPrefix(addr, unixPrefix)
+			if path == "" {
+				return nil, fmt.Errorf("unix socket %q has no "+
+					"path", addr)
+			}
+			parsed = append(parsed, &net.UnixAddr{
+				Name: path,
+				Net:  "unix",
+			})
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			host := strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+			addr = net.JoinHostPort(host, strconv.Itoa(defaultPort))
+		}
+
+		tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid listen address %q: %v",
+				addr, err)
+		}
+		parsed = append(parsed, tcpAddr)
+	}
+
+	return parsed, nil
+}
+
+// parseExternalAddrs normalizes each of the addresses we advertise to the
+// form host:port, using the default port for any which omit it. Hosts may be
+// either IPs or domains, and are left unresolved, as they're only resolved
+// from the outside.
+func parseExternalAddrs(addrs []string, defaultPort int) ([]string, error) {
+	parsed := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			host := strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+			addr = net.JoinHostPort(host, strconv.Itoa(defaultPort))
+		}
+
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || host == "" {
+			return nil, fmt.Errorf("invalid external address %q",
+				addr)
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, fmt.Errorf("invalid port of external "+
+				"address %q", addr)
+		}
+		parsed = append(parsed, addr)
+	}
+
+	return parsed, nil
+}
+
+// checkAddrConflicts returns an error if any two of the passed addresses,
+// within or across each set, can't both be listened on. Unix sockets
+// conflict if they share a path, and TCP addresses if they share a port,
+// and either share a host, or either listens on all interfaces. Port zero
+// picks a free port, so never conflicts.
+func checkAddrConflicts(addrSets ...[]net.Addr) error {
+	var addrs []net.Addr
+	for _, addrSet := range addrSets {
+		addrs = append(addrs, addrSet...)
+	}
+
+	for i := 0; i < len(addrs); i++ {
+		for j := i + 1; j < len(addrs); j++ {
+			if addrsConflict(addrs[i], addrs[j]) {
+				return fmt.Errorf("listen address %v conflicts "+
+					"with %v", addrs[i], addrs[j])
+			}
+		}
+	}
+
+	return nil
+}
+
+// addrsConflict returns true if both addresses can't be listened on at once.
+func addrsConflict(a, b net.Addr) bool {
+	switch a := a.(type) {
+	case *net.UnixAddr:
+		b, ok := b.(*net.UnixAddr)
+		return ok && a.Name == b.Name
+
+	case *net.TCPAddr:
+		b, ok := b.(*net.TCPAddr)
+		if !ok || a.Port != b.Port || a.Port == 0 {
+			return false
+		}
+		if isUnspecified(a.IP) || isUnspecified(b.IP) {
+			return true
+		}
+		return a.IP.Equal(b.IP)
+	}
+
+	return false
+}
+
+// isUnspecified returns true if the IP listens on all interfaces.
+func isUnspecified(ip net.IP) bool {
+	return ip == nil || ip.IsUnspecified()
+}
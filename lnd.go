@@ -1,31 +1,262 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"expvar"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/grpclog"
 
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 var (
-	rpcPort  = flag.Int("rpcport", 10009, "The port for the rpc server")
-	peerPort = flag.String("peerport", "10011", "The port to listen on for incoming p2p connections")
+	rpcPort  = flag.Int("rpcport", 0, "The default port for the rpc server, 0 uses the network's default")
+	restPort = flag.Int("restport", 0, "The default port for the REST proxy, 0 uses the network's default")
+	peerPort = flag.Int("peerport", 0, "The default port to listen on for incoming p2p connections, 0 uses the network's default")
 	dataDir  = flag.String("datadir", "test_wal", "The directory to store lnd's data within")
+
+	simNet  = flag.Bool("simnet", false, "Use the simulation test network, rather than testnet")
+	regTest = flag.Bool("regtest", false, "Use the regression test network, rather than testnet")
+
+	btcdHost = flag.String("btcdhost", "localhost:18334", "The host:port of the btcd rpc server to use as our chain backend")
+	btcdUser = flag.String("btcduser", "", "The username to authenticate with the btcd rpc server")
+	btcdPass = flag.String("btcdpass", "", "The password to authenticate with the btcd rpc server")
+	btcdCert = flag.String("btcdcert", filepath.Join(btcutil.AppDataDir("btcd", false), "rpc.cert"),
+		"The path of the TLS certificate of the btcd rpc server. May hold the certificates of the fallback servers too")
+	btcdHealthInterval = flag.Duration("btcdhealthinterval", 30*time.Second,
+		"How often the btcd rpc server we're connected to is checked, when fallback servers are configured")
+
+	invoiceRetention = flag.Duration("canceledinvoiceretention", 0,
+		"How long to keep canceled invoices before deleting them, 0 keeps them forever")
+	zeroConfPeers = flag.String("zeroconfpeers", "",
+		"Comma separated list of hex encoded public keys of peers trusted to open zero-conf channels with")
+	channelType = flag.String("channeltype", "legacy",
+		"The commitment format to propose for new channels: legacy, static_remote_key, or anchors")
+	blockCacheSize = flag.Int("blockcachesize", 20,
+		"The number of recently fetched blocks to keep in memory")
+	maxAcceptedHtlcs = flag.Int("maxacceptedhtlcs", lnwire.MaxHTLCNumber,
+		"The number of pending HTLCs to accept from the counterparty of each new channel")
+	minHTLCMsat = flag.Uint64("minhtlcmsat", 1000,
+		"The smallest HTLC, in millisatoshis, to accept from the counterparty of each new channel")
+	maxDustExposure = flag.Int64("maxdustexposure", 500000,
+		"The total value, in satoshis, of dust HTLCs allowed to be pending within a channel")
+	numGraphSyncPeers = flag.Int("numgraphsyncpeers", discovery.DefaultNumActiveSyncers,
+		"The number of peers to actively synchronize the channel graph with at once")
+	trickleDelay = flag.Duration("trickledelay", discovery.DefaultTrickleDelay,
+		"How often to rebroadcast batches of channel announcements and updates to our peers")
+	devMode = flag.Bool("dev", false,
+		"Enable the developer, and recovery, RPCs such as AbandonChannel, which may lose funds if misused")
+	hodlFlags = flag.String("hodl", "",
+		"Comma separated list of HTLC pipeline steps to drop, for testing, such as exit-settle, add-incoming, settle-incoming, add-outgoing, settle-outgoing, and commit. Requires dev")
+	restoreSeed = flag.String("restoreseed", "",
+		"The hex encoded seed to restore the wallet from, if the wallet is yet to be created")
+	tlsCertPath = flag.String("tlscertpath", "",
+		"The path of the rpc server's TLS certificate, generated if missing, defaults to tls.cert within the data directory")
+	tlsKeyPath = flag.String("tlskeypath", "",
+		"The path of the rpc server's TLS key, defaults to tls.key within the data directory")
+	tlsRotateInterval = flag.Duration("tlsrotateinterval", 0,
+		"How often to replace the rpc server's TLS certificate with a newly generated one, 0 disables rotation")
+	tlsClientCA = flag.String("tlsclientca", "",
+		"The path of the PEM encoded CA certificates rpc clients must present a certificate signed by. If unset, client certificates aren't required")
+	rpcServices = flag.String("rpcservices", "",
+		"Comma separated list of the rpc sub-servers to enable: walletkit, signer, router, invoices, and chainnotifier. All are enabled if empty")
+	wumbo = flag.Bool("wumbo", false,
+		"Support wumbo channels, lifting the limit on each side's contribution to channels with peers which support them too")
+	minChanSize = flag.Int64("minchansize", 20000,
+		"The smallest capacity, in satoshis, of channel to open or accept")
+	maxChanSize = flag.Int64("maxchansize", 0,
+		"The largest capacity, in satoshis, of channel to open or accept. 0 defaults to the limit without wumbo, or 10 BTC with wumbo")
+	maxCsvDelay = flag.Uint("maxcsvdelay", 2016,
+		"The largest CSV delay, in blocks, to accept a peer proposing for a new channel, bounding how long our funds may be locked up after a force close")
+	maxReservePercent = flag.Uint("maxreservepercent", 20,
+		"The largest reserve, as a percentage of the channel's capacity, to accept a peer requiring we keep within a new channel")
+	maxDustLimit = flag.Int64("maxdustlimit", 1638,
+		"The largest dust limit, in satoshis, to accept a peer proposing for a new channel")
+	reservationTimeout = flag.Duration("reservationtimeout", 10*time.Minute,
+		"How long a channel reservation may remain incomplete before it's cancelled, releasing its outputs")
+	fundingConfTimeout = flag.Uint("fundingconftimeout", 2016,
+		"The number of blocks to wait on the funding transaction of a channel we didn't fund to confirm, before forgetting the channel")
+	recoveryWindow = flag.Uint("recoverywindow", 2500,
+		"The number of addresses to rescan the chain for when restoring the wallet from a seed, 0 disables the rescan")
+	chanDisableTimeout = flag.Duration("chandisabletimeout", defaultChanDisableTimeout,
+		"How long a peer may remain offline before its channels are announced as disabled, 0 never disables them")
+	chanEnableTimeout = flag.Duration("chanenabletimeout", defaultChanEnableTimeout,
+		"How long a peer must remain online before its automatically disabled channels are announced as enabled again")
+	rejectZeroProbes = flag.Bool("rejectzeroprobes", false,
+		"Reject spontaneous payments to zero-value invoices which commit to no total amount, as sent by nodes probing whether we're the destination")
+	encryptDB = flag.Bool("encryptdb", false,
+		"Encrypt the channel database, protecting channel secrets and preimages should the disk be stolen. Once encrypted, the database remains so. Requires a wallet passphrase other than the default")
+	wipeChannels = flag.Bool("wipechannels", false,
+		"Remove the state of each open channel from the channel database, keeping the wallet, to recover from a database whose channels predate versioning. Close the channels with the version which opened them first, else their funds are lost")
+	walletPass = flag.String("walletpass", "",
+		"The private passphrase of the wallet, which protects the key of an encrypted channel database. May instead be set with the "+walletPassEnv+" environment variable, or else is prompted for if encryptdb is set")
+	pkcs11Module = flag.String("pkcs11module", "",
+		"The path of a PKCS#11 module, through which to use an identity key kept within a hardware security module, rather than the key within the wallet. The key never leaves the device. Requires lnd be built with the pkcs11 build tag")
+	pkcs11Slot = flag.Uint("pkcs11slot", 0,
+		"The slot of the token holding the identity key, if using a PKCS#11 module")
+	pkcs11PIN = flag.String("pkcs11pin", "",
+		"The PIN of the token holding the identity key, if using a PKCS#11 module")
+	pkcs11Label = flag.String("pkcs11label", "lnd",
+		"The label of the secp256k1 key pair to use as our identity key, if using a PKCS#11 module")
+	reachabilityProxy = flag.String("reachabilityproxy", "",
+		"The host:port of a SOCKS5 proxy, such as Tor, to dial back our external addresses through when checking they're reachable, rather than dialing them directly")
+	maxPeers = flag.Int("maxpeers", defaultMaxPeers,
+		"The maximum number of peers to remain connected to at once, beyond which connections are refused. 0 disables the limit")
+	reservedChanPeers = flag.Int("reservedchanpeers", defaultReservedChanPeerSlots,
+		"The number of the maxpeers slots only peers we have channels with may take up by connecting to us, so they aren't crowded out by peers which merely gossip")
+	maxDialsPerMinute = flag.Int("maxdialsperminute", defaultMaxDialsPerMinute,
+		"The maximum number of outbound connection attempts to make each minute, including reconnections to persistent peers. 0 disables the limit")
+	zombieChanPolicy = flag.String("zombiechanpolicy", "none",
+		"What to do with zombie channels, whose peer has been offline for too long, or which haven't forwarded an HTLC in too long: none, flag them for review, or close them cooperatively")
+	zombieOfflineTimeout = flag.Duration("zombieofflinetimeout", defaultZombieOfflineTimeout,
+		"How long the peer of a channel may remain offline before the channel is a zombie, 0 disables the check")
+	zombieInactiveTimeout = flag.Duration("zombieinactivetimeout", defaultZombieInactiveTimeout,
+		"How long a channel may go without forwarding an HTLC before it's a zombie, 0 disables the check")
 )
 
+var (
+	peerListen addrFlag
+	rpcListen  addrFlag
+	restListen addrFlag
+
+	restCORS addrFlag
+
+	tlsExtraIPs     addrFlag
+	tlsExtraDomains addrFlag
+
+	externalIPs addrFlag
+
+	btcdFallbacks addrFlag
+)
+
+func init() {
+	flag.Var(&peerListen, "listen",
+		"An interface to listen on for incoming p2p connections, as host, host:port, or unix://path. May be passed multiple times")
+	flag.Var(&rpcListen, "rpclisten",
+		"An interface for the rpc server to listen on, as host, host:port, or unix://path. May be passed multiple times")
+	flag.Var(&restListen, "restlisten",
+		"An interface for the REST proxy to listen on, as host, host:port, or unix://path. If unset, the REST proxy is disabled. May be passed multiple times")
+	flag.Var(&restCORS, "restcors",
+		"An origin, such as https://wallet.example.com, browsers may call the REST proxy from, or * to allow any. May be passed multiple times")
+	flag.Var(&tlsExtraIPs, "tlsextraip",
+		"An IP to include in the rpc server's TLS certificate. May be passed multiple times")
+	flag.Var(&tlsExtraDomains, "tlsextradomain",
+		"A domain to include in the rpc server's TLS certificate. May be passed multiple times")
+	flag.Var(&externalIPs, "externalip",
+		"An address peers may reach us at, as host or host:port, which is dialed back after startup to check it's reachable. May be passed multiple times")
+	flag.Var(&btcdFallbacks, "btcdfallback",
+		"A btcd rpc server to fail over to should the one we're connected to become unresponsive, as host:port, or user:pass@host:port to use credentials other than btcduser and btcdpass. Tried in the order passed. May be passed multiple times")
+}
+
 func main() {
 	flag.Parse()
 
+	// Resolve the interfaces to listen on, ensuring none conflict before
+	// we create the wallet.
+	activeNet := &chaincfg.TestNet3Params
+	switch {
+	case *simNet && *regTest:
+		fmt.Println("simnet and regtest are mutually exclusive")
+		os.Exit(1)
+	case *simNet:
+		activeNet = &chaincfg.SimNetParams
+	case *regTest:
+		activeNet = &chaincfg.RegressionNetParams
+	}
+	lnwallet.ActiveNetParams = activeNet
+	channeldb.ActiveNetParams = activeNet
+	ports := defaultNetPorts[activeNet.Name]
+	if *peerPort != 0 {
+		ports.peer = *peerPort
+	}
+	if *rpcPort != 0 {
+		ports.rpc = *rpcPort
+	}
+	if *restPort != 0 {
+		ports.rest = *restPort
+	}
+	if len(peerListen) == 0 {
+		peerListen = addrFlag{""}
+	}
+	if len(rpcListen) == 0 {
+		rpcListen = addrFlag{""}
+	}
+	peerAddrs, err := parseListenAddrs(peerListen, ports.peer)
+	if err != nil {
+		fmt.Printf("unable to parse p2p listen addresses: %v\n", err)
+		os.Exit(1)
+	}
+	rpcAddrs, err := parseListenAddrs(rpcListen, ports.rpc)
+	if err != nil {
+		fmt.Printf("unable to parse rpc listen addresses: %v\n", err)
+		os.Exit(1)
+	}
+	restAddrs, err := parseListenAddrs(restListen, ports.rest)
+	if err != nil {
+		fmt.Printf("unable to parse REST listen addresses: %v\n", err)
+		os.Exit(1)
+	}
+	err = checkAddrConflicts(peerAddrs, rpcAddrs, restAddrs)
+	if err != nil {
+		fmt.Printf("invalid listen addresses: %v\n", err)
+		os.Exit(1)
+	}
+	externalAddrs, err := parseExternalAddrs(externalIPs, ports.peer)
+	if err != nil {
+		fmt.Printf("unable to parse external addresses: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *maxPeers < 0 || *maxDialsPerMinute < 0 || *reservedChanPeers < 0 {
+		fmt.Println("peer and dial limits must not be negative")
+		os.Exit(1)
+	}
+	if *maxPeers != 0 && *reservedChanPeers >= *maxPeers {
+		fmt.Println("reservedchanpeers must be less than maxpeers")
+		os.Exit(1)
+	}
+	janitorPolicy, err := parseZombiePolicy(*zombieChanPolicy)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *zombieOfflineTimeout < 0 || *zombieInactiveTimeout < 0 {
+		fmt.Println("zombie channel timeouts must not be negative")
+		os.Exit(1)
+	}
+
+	var enabledServices []string
+	if *rpcServices != "" {
+		enabledServices = strings.Split(*rpcServices, ",")
+	}
+	filter, err := newSubServerFilter(enabledServices)
+	if err != nil {
+		fmt.Printf("invalid rpc services: %v\n", err)
+		os.Exit(1)
+	}
+
 	go func() {
 		listenAddr := net.JoinHostPort("", "5009")
 		profileRedirect := http.RedirectHandler("/debug/pprof",
@@ -38,7 +269,135 @@ func main() {
 	// logic, and exposes control via proxy state machines.
 	// TODO(roasbeef): accept config via cli flags, move to real config file
 	// afterwards
-	config := &lnwallet.Config{PrivatePass: []byte("hello"), DataDir: *dataDir}
+	privPass, err := walletPassphrase(*walletPass, *encryptDB,
+		promptWalletPassphrase)
+	if err != nil {
+		fmt.Printf("unable to get wallet passphrase: %v\n", err)
+		os.Exit(1)
+	}
+	config := &lnwallet.Config{
+		PrivatePass:    privPass,
+		DataDir:        *dataDir,
+		BlockCacheSize: *blockCacheSize,
+		RPCHost:        *btcdHost,
+		RPCUser:        *btcdUser,
+		RPCPass:        *btcdPass,
+	}
+	caCert, err := ioutil.ReadFile(*btcdCert)
+	if err != nil {
+		fmt.Printf("unable to read btcd tls cert: %v\n", err)
+		os.Exit(1)
+	}
+	config.CACert = caCert
+	if *btcdHealthInterval <= 0 {
+		fmt.Println("btcdhealthinterval must be positive")
+		os.Exit(1)
+	}
+	config.BackendHealthInterval = *btcdHealthInterval
+	for _, fallback := range btcdFallbacks {
+		backend := lnwallet.ChainBackend{
+			Host:   fallback,
+			User:   *btcdUser,
+			Pass:   *btcdPass,
+			CACert: caCert,
+		}
+		if i := strings.LastIndex(fallback, "@"); i != -1 {
+			creds := strings.SplitN(fallback[:i], ":", 2)
+			if len(creds) != 2 {
+				fmt.Printf("invalid btcd fallback: %v\n", fallback)
+				os.Exit(1)
+			}
+			backend.Host = fallback[i+1:]
+			backend.User, backend.Pass = creds[0], creds[1]
+		}
+		config.FallbackBackends = append(config.FallbackBackends, backend)
+	}
+	if *maxAcceptedHtlcs <= 0 || *maxAcceptedHtlcs > lnwire.MaxHTLCNumber {
+		fmt.Printf("maxacceptedhtlcs must be between 1 and %v\n",
+			lnwire.MaxHTLCNumber)
+		os.Exit(1)
+	}
+	config.MaxAcceptedHtlcs = uint16(*maxAcceptedHtlcs)
+	config.MinHTLC = lnwire.MilliSatoshi(*minHTLCMsat)
+	config.MaxDustExposure = btcutil.Amount(*maxDustExposure)
+	config.ReservationTimeout = *reservationTimeout
+
+	if *minChanSize <= 0 {
+		fmt.Println("minchansize must be positive")
+		os.Exit(1)
+	}
+	if *maxChanSize < 0 || (*maxChanSize != 0 && *maxChanSize < *minChanSize) {
+		fmt.Println("maxchansize must be at least minchansize")
+		os.Exit(1)
+	}
+
+	// Without wumbo, neither side may contribute more than
+	// lnwire.MaxFundingAmount.
+	// TODO: assumes balanced symmetric channels.
+	if !*wumbo && *maxChanSize > int64(2*lnwire.MaxFundingAmount) {
+		fmt.Printf("maxchansize above %v requires wumbo\n",
+			2*lnwire.MaxFundingAmount)
+		os.Exit(1)
+	}
+	if *maxCsvDelay == 0 {
+		fmt.Println("maxcsvdelay must be positive")
+		os.Exit(1)
+	}
+	if *maxReservePercent == 0 || *maxReservePercent > 100 {
+		fmt.Println("maxreservepercent must be between 1 and 100")
+		os.Exit(1)
+	}
+	if *maxDustLimit < int64(lnwallet.DefaultDustLimit) {
+		fmt.Printf("maxdustlimit must be at least %v\n",
+			lnwallet.DefaultDustLimit)
+		os.Exit(1)
+	}
+	config.MaxCsvDelay = uint32(*maxCsvDelay)
+	config.MaxReservePercent = uint32(*maxReservePercent)
+	config.MaxDustLimit = btcutil.Amount(*maxDustLimit)
+
+	config.MinChanSize = btcutil.Amount(*minChanSize)
+	config.MaxChanSize = btcutil.Amount(*maxChanSize)
+	config.Wumbo = *wumbo
+	config.FundingConfTimeout = uint32(*fundingConfTimeout)
+	config.EncryptChannelDB = *encryptDB
+	config.WipeChannelDB = *wipeChannels
+
+	hodlMask, err := hodl.ParseMask(*hodlFlags)
+	if err != nil {
+		fmt.Printf("invalid hodl flags: %v\n", err)
+		os.Exit(1)
+	}
+	if hodlMask != hodl.MaskNone {
+		if !*devMode {
+			fmt.Println("hodl flags may only be set in developer mode")
+			os.Exit(1)
+		}
+		fmt.Printf("WARNING: dropping htlc pipeline steps: %v\n", hodlMask)
+	}
+	config.HodlMask = hodlMask
+
+	if *restoreSeed != "" {
+		seed, err := hex.DecodeString(*restoreSeed)
+		if err != nil {
+			fmt.Printf("unable to decode restore seed: %v\n", err)
+			os.Exit(1)
+		}
+		config.HdSeed = seed
+		config.RecoveryWindow = uint32(*recoveryWindow)
+	}
+
+	switch *channelType {
+	case "legacy":
+		config.DefaultCommitType = channeldb.CommitmentLegacy
+	case "static_remote_key":
+		config.DefaultCommitType = channeldb.CommitmentStaticRemoteKey
+	case "anchors":
+		config.DefaultCommitType = channeldb.CommitmentAnchors
+	default:
+		fmt.Printf("unknown channel type: %v\n", *channelType)
+		os.Exit(1)
+	}
 
 	lnwallet, db, err := lnwallet.NewLightningWallet(config)
 	if err != nil {
@@ -51,32 +410,147 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Expose the effectiveness of the block cache alongside the profiling
+	// endpoints, under /debug/vars.
+	expvar.Publish("blockcache", expvar.Func(func() interface{} {
+		stats := lnwallet.BlockCacheStats()
+		return map[string]interface{}{
+			"hits":    stats.Hits,
+			"misses":  stats.Misses,
+			"size":    stats.Size,
+			"hitRate": stats.HitRate(),
+		}
+	}))
+
 	lnwallet.Unlock(config.PrivatePass, time.Duration(0))
 	fmt.Println("wallet open")
 	defer db.Close()
 
+	// If a PKCS#11 module is configured, then our identity key is kept
+	// within the device. Otherwise, the server falls back to the key
+	// within the wallet.
+	var identity keychain.NodeSigner
+	if *pkcs11Module != "" {
+		hsm, err := keychain.NewPKCS11Signer(*pkcs11Module,
+			*pkcs11Slot, *pkcs11PIN, *pkcs11Label)
+		if err != nil {
+			fmt.Printf("unable to open identity key: %v\n", err)
+			os.Exit(1)
+		}
+		defer hsm.Close()
+
+		identity = hsm
+	}
+
 	// Set up the core server which will listen for incoming peer
 	// connections.
-	defaultListenAddr := []string{net.JoinHostPort("", *peerPort)}
-	server, err := newServer(defaultListenAddr, &chaincfg.TestNet3Params,
-		lnwallet)
+	var trustedPeers []string
+	if *zeroConfPeers != "" {
+		trustedPeers = strings.Split(*zeroConfPeers, ",")
+	}
+	server, err := newServer(&serverConfig{
+		ListenAddrs:           peerAddrs,
+		ChainParams:           activeNet,
+		Wallet:                lnwallet,
+		Identity:              identity,
+		InvoiceRetention:      *invoiceRetention,
+		ZeroConfPeers:         trustedPeers,
+		NumActiveSyncers:      *numGraphSyncPeers,
+		TrickleDelay:          *trickleDelay,
+		ChanDisableTimeout:    *chanDisableTimeout,
+		ChanEnableTimeout:     *chanEnableTimeout,
+		DevMode:               *devMode,
+		HodlMask:              hodlMask,
+		RejectZeroProbes:      *rejectZeroProbes,
+		ExternalAddrs:         externalAddrs,
+		ReachabilityProxy:     *reachabilityProxy,
+		MaxPeers:              *maxPeers,
+		ReservedChanPeers:     *reservedChanPeers,
+		MaxDialsPerMinute:     *maxDialsPerMinute,
+		ZombiePolicy:          janitorPolicy,
+		ZombieOfflineTimeout:  *zombieOfflineTimeout,
+		ZombieInactiveTimeout: *zombieInactiveTimeout,
+	})
 	if err != nil {
 		fmt.Printf("unable to create server: %v\n", err)
 		os.Exit(1)
 	}
 	server.Start()
 
+	// Load the TLS certificate of the rpc server, generating a new one if
+	// it's missing or has expired.
+	certPath, keyPath := *tlsCertPath, *tlsKeyPath
+	if certPath == "" {
+		certPath = filepath.Join(*dataDir, "tls.cert")
+	}
+	if keyPath == "" {
+		keyPath = filepath.Join(*dataDir, "tls.key")
+	}
+	var clientCAs *x509.CertPool
+	if *tlsClientCA != "" {
+		clientCAs, err = loadClientCAs(*tlsClientCA)
+		if err != nil {
+			fmt.Printf("unable to load tls client CAs: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	certs, err := newCertManager(certPath, keyPath, tlsExtraIPs,
+		tlsExtraDomains, *tlsRotateInterval, clientCAs)
+	if err != nil {
+		fmt.Printf("unable to load tls cert: %v\n", err)
+		os.Exit(1)
+	}
+	certs.Start()
+	defer certs.Stop()
+
 	// Initialize, and register our implementation of the gRPC server.
-	var opts []grpc.ServerOption
+	// Calls to disabled sub-servers are rejected before reaching any
+	// middleware, while known errors are given their status codes last.
+	middleware := server.rpcServer.middleware
+	unaryInterceptor := chainUnaryInterceptors(
+		errorUnaryInterceptor, filter.unaryInterceptor,
+		middleware.unaryInterceptor,
+	)
+	opts := []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(certs.TLSConfig())),
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(chainStreamInterceptors(
+			errorStreamInterceptor, filter.streamInterceptor,
+			middleware.streamInterceptor,
+		)),
+	}
 	grpcServer := grpc.NewServer(opts...)
 	lnrpc.RegisterLightningServer(grpcServer, server.rpcServer)
 
-	// Finally, start the grpc server listening for HTTP/2 connections.
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *rpcPort))
-	if err != nil {
-		grpclog.Fatalf("failed to listen: %v", err)
-		fmt.Printf("failed to listen: %v", err)
-		os.Exit(1)
+	// The REST proxy calls through the same interceptors, behind the same
+	// TLS config, so its calls are held to those made over gRPC.
+	restProxy := newRESTProxy(server.rpcServer, unaryInterceptor, restCORS)
+
+	// Finally, start the grpc server listening for HTTP/2 connections on
+	// each of its interfaces, along with the REST proxy on each of its
+	// own, exiting once any fail.
+	errChan := make(chan error, len(rpcAddrs)+len(restAddrs))
+	for _, addr := range rpcAddrs {
+		lis, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			grpclog.Fatalf("failed to listen: %v", err)
+			fmt.Printf("failed to listen: %v", err)
+			os.Exit(1)
+		}
+		go func() {
+			errChan <- grpcServer.Serve(lis)
+		}()
+	}
+	for _, addr := range restAddrs {
+		lis, err := net.Listen(addr.Network(), addr.String())
+		if err != nil {
+			fmt.Printf("failed to listen: %v\n", err)
+			os.Exit(1)
+		}
+		lis = tls.NewListener(lis, certs.TLSConfig())
+		go func() {
+			errChan <- http.Serve(lis, restProxy)
+		}()
 	}
-	grpcServer.Serve(lis)
+	grpclog.Fatalf("rpc server exited: %v", <-errChan)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/codahale/chacha20poly1305"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/keychain"
 )
 
 // LNDConn ...
@@ -27,6 +28,11 @@ type LNDConn struct {
 	// once authed == true, remotePub is who you're actually talking to.
 	Authed bool
 
+	// SessionID identifies the session, being known only to us and the
+	// remote node. It's derived from the session key, which it reveals
+	// nothing about.
+	SessionID [32]byte
+
 	// chachaStream saves some time as you don't have to init it with
 	// the session key every time.  Make SessionKey redundant; remove later.
 	chachaStream cipher.AEAD
@@ -43,6 +49,10 @@ type LNDConn struct {
 
 	readBuf bytes.Buffer
 
+	// Dialer, if set, opens the TCP connection in place of net.Dial, so
+	// connections may be made through a proxy.
+	Dialer func(network, address string) (net.Conn, error)
+
 	Conn net.Conn
 }
 
@@ -53,7 +63,7 @@ func NewConn(conn net.Conn) *LNDConn {
 
 // Dial ...
 func (c *LNDConn) Dial(
-	myID *btcec.PrivateKey, address string, remoteID []byte) error {
+	myID keychain.SingleKeyECDH, address string, remoteID []byte) error {
 	var err error
 
 	if !c.ViaPbx {
@@ -62,7 +72,11 @@ func (c *LNDConn) Dial(
 		}
 
 		// First, open the TCP connection itself.
-		c.Conn, err = net.Dial("tcp", address)
+		dial := net.Dial
+		if c.Dialer != nil {
+			dial = c.Dialer
+		}
+		c.Conn, err = dial("tcp", address)
 		if err != nil {
 			return err
 		}
@@ -123,6 +137,8 @@ func (c *LNDConn) Dial(
 	// display private key for debug only
 	fmt.Printf("made session key %x\n", sessionKey)
 
+	c.SessionID = fastsha256.Sum256(sessionKey[:])
+
 	c.myNonceInt = 1 << 63
 	c.remoteNonceInt = 0
 
@@ -147,7 +163,7 @@ func (c *LNDConn) Dial(
 
 // authPubKey...
 func (c *LNDConn) authPubKey(
-	myID *btcec.PrivateKey, remotePubBytes, localEphPubBytes []byte) error {
+	myID keychain.SingleKeyECDH, remotePubBytes, localEphPubBytes []byte) error {
 	if c.Authed {
 		return fmt.Errorf("%s already authed", c.RemotePub)
 	}
@@ -159,7 +175,11 @@ func (c *LNDConn) authPubKey(
 		return err
 	}
 	theirPKH := btcutil.Hash160(remotePubBytes)
-	idDH := fastsha256.Sum256(btcec.GenerateSharedSecret(myID, theirPub))
+	secret, err := myID.ECDH(theirPub)
+	if err != nil {
+		return err
+	}
+	idDH := fastsha256.Sum256(secret)
 	myDHproof := btcutil.Hash160(append(c.RemotePub.SerializeCompressed(), idDH[:]...))
 
 	// Send over the 73 byte authentication message: my pubkey, their
@@ -196,7 +216,7 @@ func (c *LNDConn) authPubKey(
 
 // authPKH...
 func (c *LNDConn) authPKH(
-	myID *btcec.PrivateKey, theirPKH, localEphPubBytes []byte) error {
+	myID keychain.SingleKeyECDH, theirPKH, localEphPubBytes []byte) error {
 	if c.Authed {
 		return fmt.Errorf("%s already authed", c.RemotePub)
 	}
@@ -227,7 +247,11 @@ func (c *LNDConn) authPKH(
 	if err != nil {
 		return err
 	}
-	idDH := fastsha256.Sum256(btcec.GenerateSharedSecret(myID, theirPub))
+	secret, err := myID.ECDH(theirPub)
+	if err != nil {
+		return err
+	}
+	idDH := fastsha256.Sum256(secret)
 	fmt.Printf("made idDH %x\n", idDH)
 	theirDHproof := btcutil.Hash160(append(localEphPubBytes, idDH[:]...))
 
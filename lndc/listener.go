@@ -9,25 +9,32 @@ import (
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/fastsha256"
 	"github.com/codahale/chacha20poly1305"
+	"github.com/lightningnetwork/lnd/keychain"
 )
 
 // Listener ...
 type Listener struct {
-	longTermPriv *btcec.PrivateKey
+	longTermPriv keychain.SingleKeyECDH
 
-	tcp *net.TCPListener
+	listener net.Listener
 }
 
 var _ net.Listener = (*Listener)(nil)
 
 // NewListener ...
-func NewListener(localPriv *btcec.PrivateKey, listenAddr string) (*Listener, error) {
+func NewListener(localPriv keychain.SingleKeyECDH, listenAddr string) (*Listener, error) {
 	addr, err := net.ResolveTCPAddr("tcp", listenAddr)
 	if err != nil {
 		return nil, err
 	}
 
-	l, err := net.ListenTCP("tcp", addr)
+	return NewAddrListener(localPriv, addr)
+}
+
+// NewAddrListener creates a listener on the passed address, which may be
+// either a TCP address, or a unix socket.
+func NewAddrListener(localPriv keychain.SingleKeyECDH, addr net.Addr) (*Listener, error) {
+	l, err := net.Listen(addr.Network(), addr.String())
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +45,7 @@ func NewListener(localPriv *btcec.PrivateKey, listenAddr string) (*Listener, err
 // Accept waits for and returns the next connection to the listener.
 // Part of the net.Listener interface.
 func (l *Listener) Accept() (c net.Conn, err error) {
-	conn, err := l.tcp.Accept()
+	conn, err := l.listener.Accept()
 	if err != nil {
 		return nil, nil
 	}
@@ -104,6 +111,8 @@ func (l *Listener) createCipherConn(lnConn *LNDConn) (*btcec.PrivateKey, error)
 	// display private key for debug only
 	fmt.Printf("made session key %x\n", sessionKey)
 
+	lnConn.SessionID = fastsha256.Sum256(sessionKey[:])
+
 	lnConn.remoteNonceInt = 1 << 63
 	lnConn.myNonceInt = 0
 
@@ -115,7 +124,7 @@ func (l *Listener) createCipherConn(lnConn *LNDConn) (*btcec.PrivateKey, error)
 
 // AuthListen...
 func (l *Listener) authenticateConnection(
-	myID *btcec.PrivateKey, lnConn *LNDConn, localEphPubBytes []byte) error {
+	myID keychain.SingleKeyECDH, lnConn *LNDConn, localEphPubBytes []byte) error {
 	var err error
 
 	// TODO(roasbeef): should be using read/write clear here?
@@ -144,9 +153,11 @@ func (l *Listener) authenticateConnection(
 	if err != nil {
 		return err
 	}
-	idDH := fastsha256.Sum256(
-		btcec.GenerateSharedSecret(l.longTermPriv, theirPub),
-	)
+	secret, err := l.longTermPriv.ECDH(theirPub)
+	if err != nil {
+		return err
+	}
+	idDH := fastsha256.Sum256(secret)
 
 	myDHproof := btcutil.Hash160(
 		append(lnConn.RemotePub.SerializeCompressed(), idDH[:]...),
@@ -197,11 +208,11 @@ func (l *Listener) authenticateConnection(
 // Any blocked Accept operations will be unblocked and return errors.
 // Part of the net.Listener interface.
 func (l *Listener) Close() error {
-	return l.tcp.Close()
+	return l.listener.Close()
 }
 
 // Addr returns the listener's network address.
 // Part of the net.Listener interface.
 func (l *Listener) Addr() net.Addr {
-	return l.tcp.Addr()
+	return l.listener.Addr()
 }
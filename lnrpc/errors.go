@@ -0,0 +1,19 @@
+package lnrpc
+
+import "google.golang.org/grpc/status"
+
+// ErrorDetailFromError returns the structured detail attached to an error
+// returned by an rpc call, or nil if the error carries none, in which case
+// only its message describes it.
+func ErrorDetailFromError(err error) *ErrorDetail {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	for _, detail := range st.Details() {
+		if errDetail, ok := detail.(*ErrorDetail); ok {
+			return errDetail
+		}
+	}
+	return nil
+}
@@ -10,11 +10,138 @@ It is generated from these files:
 
 It has these top-level messages:
 	SendManyRequest
+	EstimateFeeRequest
+	EstimateFeeResponse
+	PublishTransactionRequest
+	PublishTransactionResponse
+	GetTransactionRequest
+	TransactionOutput
+	GetTransactionResponse
 	SendManyResponse
 	NewAddressRequest
 	NewAddressResponse
+	GetRecoveryInfoRequest
+	GetRecoveryInfoResponse
+	WalletBalanceRequest
+	WalletBalanceResponse
+	ChannelBalanceRequest
+	ChannelTypeBalance
+	ChannelBalanceResponse
+	GetInfoRequest
+	GetInfoResponse
+	AddressReachability
 	ConnectPeerRequest
 	ConnectPeerResponse
+	DisconnectPeerRequest
+	DisconnectPeerResponse
+	ListPeersRequest
+	Peer
+	ListPeersResponse
+	SetPeerLabelRequest
+	SetPeerLabelResponse
+	ListPeerBackupsRequest
+	ChannelBackup
+	PeerBackup
+	ListPeerBackupsResponse
+	SendSignedDataRequest
+	SendSignedDataResponse
+	SubscribeSignedDataRequest
+	SignedPeerData
+	SignPeerDataRequest
+	SignPeerDataResponse
+	VerifyPeerDataRequest
+	VerifyPeerDataResponse
+	ListPeerMisbehaviorRequest
+	Misbehavior
+	PeerMisbehavior
+	ListPeerMisbehaviorResponse
+	PaymentAttempt
+	Payment
+	FeeLimit
+	SendPaymentRequest
+	SendPaymentResponse
+	QueryRoutesRequest
+	Hop
+	Route
+	QueryRoutesResponse
+	EstimateRouteFeeRequest
+	EstimateRouteFeeResponse
+	ListPaymentsRequest
+	ListPaymentsResponse
+	DeletePaymentRequest
+	DeletePaymentResponse
+	DeleteAllPaymentsRequest
+	DeleteAllPaymentsResponse
+	Htlc
+	ListHtlcsRequest
+	ListHtlcsResponse
+	LookupHtlcResolutionRequest
+	LookupHtlcResolutionResponse
+	ExportAccountingRequest
+	ExportAccountingResponse
+	Invoice
+	AddInvoiceResponse
+	InvoiceSubscription
+	ImportAccountRequest
+	ImportAccountResponse
+	ImportPublicKeyRequest
+	ImportPublicKeyResponse
+	FundPsbtRequest
+	FundPsbtResponse
+	SignPsbtRequest
+	SignPsbtResponse
+	FinalizePsbtRequest
+	FinalizePsbtResponse
+	BumpFeeRequest
+	BumpFeeResponse
+	PendingSweepsRequest
+	PendingSweep
+	PendingSweepsResponse
+	ListSweepsRequest
+	SweepTransaction
+	ListSweepsResponse
+	AbandonChannelRequest
+	AbandonChannelResponse
+	UpdateChannelParamsRequest
+	UpdateChannelParamsResponse
+	ListPendingReservationsRequest
+	PendingReservation
+	ListPendingReservationsResponse
+	GetBestBlockRequest
+	GetBestBlockResponse
+	BlockEpochRequest
+	BlockEpoch
+	ConfRequest
+	ConfEvent
+	SpendRequest
+	SpendEvent
+	LightningNode
+	RoutingPolicy
+	ChannelEdge
+	ChannelGraphRequest
+	ChannelGraph
+	ChanInfoRequest
+	NodeInfoRequest
+	NodeInfo
+	GraphTopologySubscription
+	ChannelEdgeUpdate
+	ClosedChannelUpdate
+	GraphTopologyUpdate
+	NetworkInfoRequest
+	NetworkInfo
+	UpdateChanStatusRequest
+	UpdateChanStatusResponse
+	ChannelInsightsRequest
+	ChannelInsight
+	ChannelInsightsResponse
+	SetChannelNoteRequest
+	SetChannelNoteResponse
+	FeeReportRequest
+	ChannelFeeReport
+	FeeReportResponse
+	RPCMiddlewareRequest
+	RPCMiddlewareResponse
+	ErrorDetail
 */
 package lnrpc
 
@@ -32,6 +159,279 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+type PaymentStatus int32
+
+const (
+	PaymentStatus_IN_FLIGHT PaymentStatus = 0
+	PaymentStatus_SUCCEEDED PaymentStatus = 1
+	PaymentStatus_FAILED    PaymentStatus = 2
+)
+
+var PaymentStatus_name = map[int32]string{
+	0: "IN_FLIGHT",
+	1: "SUCCEEDED",
+	2: "FAILED",
+}
+var PaymentStatus_value = map[string]int32{
+	"IN_FLIGHT": 0,
+	"SUCCEEDED": 1,
+	"FAILED":    2,
+}
+
+func (x PaymentStatus) String() string {
+	return proto.EnumName(PaymentStatus_name, int32(x))
+}
+func (PaymentStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type AccountingFormat int32
+
+const (
+	AccountingFormat_ACCOUNTING_FORMAT_CSV  AccountingFormat = 0
+	AccountingFormat_ACCOUNTING_FORMAT_JSON AccountingFormat = 1
+)
+
+var AccountingFormat_name = map[int32]string{
+	0: "ACCOUNTING_FORMAT_CSV",
+	1: "ACCOUNTING_FORMAT_JSON",
+}
+var AccountingFormat_value = map[string]int32{
+	"ACCOUNTING_FORMAT_CSV":  0,
+	"ACCOUNTING_FORMAT_JSON": 1,
+}
+
+func (x AccountingFormat) String() string {
+	return proto.EnumName(AccountingFormat_name, int32(x))
+}
+func (AccountingFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+type AccountingEntryType int32
+
+const (
+	AccountingEntryType_ACCOUNTING_ENTRY_ON_CHAIN AccountingEntryType = 0
+	AccountingEntryType_ACCOUNTING_ENTRY_FORWARD  AccountingEntryType = 1
+	AccountingEntryType_ACCOUNTING_ENTRY_INVOICE  AccountingEntryType = 2
+	AccountingEntryType_ACCOUNTING_ENTRY_PAYMENT  AccountingEntryType = 3
+)
+
+var AccountingEntryType_name = map[int32]string{
+	0: "ACCOUNTING_ENTRY_ON_CHAIN",
+	1: "ACCOUNTING_ENTRY_FORWARD",
+	2: "ACCOUNTING_ENTRY_INVOICE",
+	3: "ACCOUNTING_ENTRY_PAYMENT",
+}
+var AccountingEntryType_value = map[string]int32{
+	"ACCOUNTING_ENTRY_ON_CHAIN": 0,
+	"ACCOUNTING_ENTRY_FORWARD":  1,
+	"ACCOUNTING_ENTRY_INVOICE":  2,
+	"ACCOUNTING_ENTRY_PAYMENT":  3,
+}
+
+func (x AccountingEntryType) String() string {
+	return proto.EnumName(AccountingEntryType_name, int32(x))
+}
+func (AccountingEntryType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type InvoiceState int32
+
+const (
+	InvoiceState_INVOICE_OPEN     InvoiceState = 0
+	InvoiceState_INVOICE_SETTLED  InvoiceState = 1
+	InvoiceState_INVOICE_CANCELED InvoiceState = 2
+)
+
+var InvoiceState_name = map[int32]string{
+	0: "INVOICE_OPEN",
+	1: "INVOICE_SETTLED",
+	2: "INVOICE_CANCELED",
+}
+var InvoiceState_value = map[string]int32{
+	"INVOICE_OPEN":     0,
+	"INVOICE_SETTLED":  1,
+	"INVOICE_CANCELED": 2,
+}
+
+func (x InvoiceState) String() string {
+	return proto.EnumName(InvoiceState_name, int32(x))
+}
+func (InvoiceState) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type ChanStatusAction int32
+
+const (
+	ChanStatusAction_ENABLE  ChanStatusAction = 0
+	ChanStatusAction_DISABLE ChanStatusAction = 1
+	ChanStatusAction_AUTO    ChanStatusAction = 2
+)
+
+var ChanStatusAction_name = map[int32]string{
+	0: "ENABLE",
+	1: "DISABLE",
+	2: "AUTO",
+}
+var ChanStatusAction_value = map[string]int32{
+	"ENABLE":  0,
+	"DISABLE": 1,
+	"AUTO":    2,
+}
+
+func (x ChanStatusAction) String() string {
+	return proto.EnumName(ChanStatusAction_name, int32(x))
+}
+func (ChanStatusAction) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNKNOWN                  ErrorCode = 0
+	ErrorCode_ERROR_CODE_PEER_ALREADY_CONNECTED   ErrorCode = 1
+	ErrorCode_ERROR_CODE_PEER_NOT_CONNECTED       ErrorCode = 2
+	ErrorCode_ERROR_CODE_SERVER_SHUTTING_DOWN     ErrorCode = 3
+	ErrorCode_ERROR_CODE_INSUFFICIENT_FUNDS       ErrorCode = 4
+	ErrorCode_ERROR_CODE_RESERVATION_NOT_FOUND    ErrorCode = 5
+	ErrorCode_ERROR_CODE_MUSIG2_SESSION_NOT_FOUND ErrorCode = 6
+	ErrorCode_ERROR_CODE_ACCOUNT_EXISTS           ErrorCode = 7
+	ErrorCode_ERROR_CODE_TX_CONFIRMED             ErrorCode = 8
+	ErrorCode_ERROR_CODE_PAYMENT_FAILED           ErrorCode = 9
+	ErrorCode_ERROR_CODE_CHANNEL_CONFLICT         ErrorCode = 10
+	ErrorCode_ERROR_CODE_PAYMENT_IN_FLIGHT        ErrorCode = 11
+	ErrorCode_ERROR_CODE_ALREADY_PAID             ErrorCode = 12
+	ErrorCode_ERROR_CODE_INSUFFICIENT_OUTBOUND    ErrorCode = 13
+	ErrorCode_ERROR_CODE_INSUFFICIENT_INBOUND     ErrorCode = 14
+	ErrorCode_ERROR_CODE_NOT_SYNCED               ErrorCode = 15
+	ErrorCode_ERROR_CODE_DOUBLE_SPEND             ErrorCode = 16
+	ErrorCode_ERROR_CODE_FEE_TOO_LOW              ErrorCode = 17
+	ErrorCode_ERROR_CODE_MISSING_INPUTS           ErrorCode = 18
+	ErrorCode_ERROR_CODE_TX_NOT_FOUND             ErrorCode = 19
+)
+
+var ErrorCode_name = map[int32]string{
+	0:  "ERROR_CODE_UNKNOWN",
+	1:  "ERROR_CODE_PEER_ALREADY_CONNECTED",
+	2:  "ERROR_CODE_PEER_NOT_CONNECTED",
+	3:  "ERROR_CODE_SERVER_SHUTTING_DOWN",
+	4:  "ERROR_CODE_INSUFFICIENT_FUNDS",
+	5:  "ERROR_CODE_RESERVATION_NOT_FOUND",
+	6:  "ERROR_CODE_MUSIG2_SESSION_NOT_FOUND",
+	7:  "ERROR_CODE_ACCOUNT_EXISTS",
+	8:  "ERROR_CODE_TX_CONFIRMED",
+	9:  "ERROR_CODE_PAYMENT_FAILED",
+	10: "ERROR_CODE_CHANNEL_CONFLICT",
+	11: "ERROR_CODE_PAYMENT_IN_FLIGHT",
+	12: "ERROR_CODE_ALREADY_PAID",
+	13: "ERROR_CODE_INSUFFICIENT_OUTBOUND",
+	14: "ERROR_CODE_INSUFFICIENT_INBOUND",
+	15: "ERROR_CODE_NOT_SYNCED",
+	16: "ERROR_CODE_DOUBLE_SPEND",
+	17: "ERROR_CODE_FEE_TOO_LOW",
+	18: "ERROR_CODE_MISSING_INPUTS",
+	19: "ERROR_CODE_TX_NOT_FOUND",
+}
+var ErrorCode_value = map[string]int32{
+	"ERROR_CODE_UNKNOWN":                  0,
+	"ERROR_CODE_PEER_ALREADY_CONNECTED":   1,
+	"ERROR_CODE_PEER_NOT_CONNECTED":       2,
+	"ERROR_CODE_SERVER_SHUTTING_DOWN":     3,
+	"ERROR_CODE_INSUFFICIENT_FUNDS":       4,
+	"ERROR_CODE_RESERVATION_NOT_FOUND":    5,
+	"ERROR_CODE_MUSIG2_SESSION_NOT_FOUND": 6,
+	"ERROR_CODE_ACCOUNT_EXISTS":           7,
+	"ERROR_CODE_TX_CONFIRMED":             8,
+	"ERROR_CODE_PAYMENT_FAILED":           9,
+	"ERROR_CODE_CHANNEL_CONFLICT":         10,
+	"ERROR_CODE_PAYMENT_IN_FLIGHT":        11,
+	"ERROR_CODE_ALREADY_PAID":             12,
+	"ERROR_CODE_INSUFFICIENT_OUTBOUND":    13,
+	"ERROR_CODE_INSUFFICIENT_INBOUND":     14,
+	"ERROR_CODE_NOT_SYNCED":               15,
+	"ERROR_CODE_DOUBLE_SPEND":             16,
+	"ERROR_CODE_FEE_TOO_LOW":              17,
+	"ERROR_CODE_MISSING_INPUTS":           18,
+	"ERROR_CODE_TX_NOT_FOUND":             19,
+}
+
+func (x ErrorCode) String() string {
+	return proto.EnumName(ErrorCode_name, int32(x))
+}
+func (ErrorCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type PaymentFailureReason int32
+
+const (
+	PaymentFailureReason_PAYMENT_FAILURE_NONE                      PaymentFailureReason = 0
+	PaymentFailureReason_PAYMENT_FAILURE_TOO_MANY_HTLCS            PaymentFailureReason = 1
+	PaymentFailureReason_PAYMENT_FAILURE_MAX_VALUE_IN_FLIGHT       PaymentFailureReason = 2
+	PaymentFailureReason_PAYMENT_FAILURE_BELOW_MIN_HTLC            PaymentFailureReason = 3
+	PaymentFailureReason_PAYMENT_FAILURE_MAX_DUST_EXPOSURE         PaymentFailureReason = 4
+	PaymentFailureReason_PAYMENT_FAILURE_UNKNOWN_PAYMENT_HASH      PaymentFailureReason = 5
+	PaymentFailureReason_PAYMENT_FAILURE_HELD                      PaymentFailureReason = 6
+	PaymentFailureReason_PAYMENT_FAILURE_TIMEOUT                   PaymentFailureReason = 7
+	PaymentFailureReason_PAYMENT_FAILURE_NO_ROUTE                  PaymentFailureReason = 8
+	PaymentFailureReason_PAYMENT_FAILURE_INCORRECT_PAYMENT_DETAILS PaymentFailureReason = 9
+	PaymentFailureReason_PAYMENT_FAILURE_MULTI_HOP_UNSUPPORTED     PaymentFailureReason = 10
+)
+
+var PaymentFailureReason_name = map[int32]string{
+	0:  "PAYMENT_FAILURE_NONE",
+	1:  "PAYMENT_FAILURE_TOO_MANY_HTLCS",
+	2:  "PAYMENT_FAILURE_MAX_VALUE_IN_FLIGHT",
+	3:  "PAYMENT_FAILURE_BELOW_MIN_HTLC",
+	4:  "PAYMENT_FAILURE_MAX_DUST_EXPOSURE",
+	5:  "PAYMENT_FAILURE_UNKNOWN_PAYMENT_HASH",
+	6:  "PAYMENT_FAILURE_HELD",
+	7:  "PAYMENT_FAILURE_TIMEOUT",
+	8:  "PAYMENT_FAILURE_NO_ROUTE",
+	9:  "PAYMENT_FAILURE_INCORRECT_PAYMENT_DETAILS",
+	10: "PAYMENT_FAILURE_MULTI_HOP_UNSUPPORTED",
+}
+var PaymentFailureReason_value = map[string]int32{
+	"PAYMENT_FAILURE_NONE":                      0,
+	"PAYMENT_FAILURE_TOO_MANY_HTLCS":            1,
+	"PAYMENT_FAILURE_MAX_VALUE_IN_FLIGHT":       2,
+	"PAYMENT_FAILURE_BELOW_MIN_HTLC":            3,
+	"PAYMENT_FAILURE_MAX_DUST_EXPOSURE":         4,
+	"PAYMENT_FAILURE_UNKNOWN_PAYMENT_HASH":      5,
+	"PAYMENT_FAILURE_HELD":                      6,
+	"PAYMENT_FAILURE_TIMEOUT":                   7,
+	"PAYMENT_FAILURE_NO_ROUTE":                  8,
+	"PAYMENT_FAILURE_INCORRECT_PAYMENT_DETAILS": 9,
+	"PAYMENT_FAILURE_MULTI_HOP_UNSUPPORTED":     10,
+}
+
+func (x PaymentFailureReason) String() string {
+	return proto.EnumName(PaymentFailureReason_name, int32(x))
+}
+func (PaymentFailureReason) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+type ChannelConflict int32
+
+const (
+	ChannelConflict_CHANNEL_CONFLICT_NONE              ChannelConflict = 0
+	ChannelConflict_CHANNEL_CONFLICT_PEER_HAS_CHANNELS ChannelConflict = 1
+	ChannelConflict_CHANNEL_CONFLICT_TYPE_MISMATCH     ChannelConflict = 2
+	ChannelConflict_CHANNEL_CONFLICT_UPDATE_CANCELLED  ChannelConflict = 3
+	ChannelConflict_CHANNEL_CONFLICT_NO_PENDING_UPDATE ChannelConflict = 4
+)
+
+var ChannelConflict_name = map[int32]string{
+	0: "CHANNEL_CONFLICT_NONE",
+	1: "CHANNEL_CONFLICT_PEER_HAS_CHANNELS",
+	2: "CHANNEL_CONFLICT_TYPE_MISMATCH",
+	3: "CHANNEL_CONFLICT_UPDATE_CANCELLED",
+	4: "CHANNEL_CONFLICT_NO_PENDING_UPDATE",
+}
+var ChannelConflict_value = map[string]int32{
+	"CHANNEL_CONFLICT_NONE":              0,
+	"CHANNEL_CONFLICT_PEER_HAS_CHANNELS": 1,
+	"CHANNEL_CONFLICT_TYPE_MISMATCH":     2,
+	"CHANNEL_CONFLICT_UPDATE_CANCELLED":  3,
+	"CHANNEL_CONFLICT_NO_PENDING_UPDATE": 4,
+}
+
+func (x ChannelConflict) String() string {
+	return proto.EnumName(ChannelConflict_name, int32(x))
+}
+func (ChannelConflict) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
 type SendManyRequest struct {
 	AddrToAmount map[string]int64 `protobuf:"bytes,1,rep,name=AddrToAmount" json:"AddrToAmount,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
 }
@@ -45,157 +445,3317 @@ func (m *SendManyRequest) GetAddrToAmount() map[string]int64 {
 	if m != nil {
 		return m.AddrToAmount
 	}
-	return nil
+	return nil
+}
+
+type EstimateFeeRequest struct {
+	AddrToAmount map[string]int64 `protobuf:"bytes,1,rep,name=addrToAmount" json:"addrToAmount,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	TargetConf   uint32           `protobuf:"varint,2,opt,name=targetConf" json:"targetConf,omitempty"`
+	MinConfs     int32            `protobuf:"varint,3,opt,name=minConfs" json:"minConfs,omitempty"`
+}
+
+func (m *EstimateFeeRequest) Reset()                    { *m = EstimateFeeRequest{} }
+func (m *EstimateFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeRequest) ProtoMessage()               {}
+func (*EstimateFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *EstimateFeeRequest) GetAddrToAmount() map[string]int64 {
+	if m != nil {
+		return m.AddrToAmount
+	}
+	return nil
+}
+
+type EstimateFeeResponse struct {
+	FeeSat    int64  `protobuf:"varint,1,opt,name=feeSat" json:"feeSat,omitempty"`
+	SatPerKb  int64  `protobuf:"varint,2,opt,name=satPerKb" json:"satPerKb,omitempty"`
+	NumInputs uint32 `protobuf:"varint,3,opt,name=numInputs" json:"numInputs,omitempty"`
+}
+
+func (m *EstimateFeeResponse) Reset()                    { *m = EstimateFeeResponse{} }
+func (m *EstimateFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeResponse) ProtoMessage()               {}
+func (*EstimateFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type PublishTransactionRequest struct {
+	RawTx []byte `protobuf:"bytes,1,opt,name=rawTx,proto3" json:"rawTx,omitempty"`
+	Label string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *PublishTransactionRequest) Reset()                    { *m = PublishTransactionRequest{} }
+func (m *PublishTransactionRequest) String() string            { return proto.CompactTextString(m) }
+func (*PublishTransactionRequest) ProtoMessage()               {}
+func (*PublishTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type PublishTransactionResponse struct {
+	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+}
+
+func (m *PublishTransactionResponse) Reset()                    { *m = PublishTransactionResponse{} }
+func (m *PublishTransactionResponse) String() string            { return proto.CompactTextString(m) }
+func (*PublishTransactionResponse) ProtoMessage()               {}
+func (*PublishTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type GetTransactionRequest struct {
+	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+}
+
+func (m *GetTransactionRequest) Reset()                    { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()               {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type TransactionOutput struct {
+	Address     string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	OutputIndex uint32 `protobuf:"varint,2,opt,name=outputIndex" json:"outputIndex,omitempty"`
+	Amount      int64  `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *TransactionOutput) Reset()                    { *m = TransactionOutput{} }
+func (m *TransactionOutput) String() string            { return proto.CompactTextString(m) }
+func (*TransactionOutput) ProtoMessage()               {}
+func (*TransactionOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+type GetTransactionResponse struct {
+	Txid          string               `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Confirmations int64                `protobuf:"varint,2,opt,name=confirmations" json:"confirmations,omitempty"`
+	BlockHash     string               `protobuf:"bytes,3,opt,name=blockHash" json:"blockHash,omitempty"`
+	Timestamp     int64                `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	TotalFees     int64                `protobuf:"varint,5,opt,name=totalFees" json:"totalFees,omitempty"`
+	Amount        int64                `protobuf:"varint,6,opt,name=amount" json:"amount,omitempty"`
+	Outputs       []*TransactionOutput `protobuf:"bytes,7,rep,name=outputs" json:"outputs,omitempty"`
+	Label         string               `protobuf:"bytes,8,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *GetTransactionResponse) Reset()                    { *m = GetTransactionResponse{} }
+func (m *GetTransactionResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetTransactionResponse) ProtoMessage()               {}
+func (*GetTransactionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *GetTransactionResponse) GetOutputs() []*TransactionOutput {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+type SendManyResponse struct {
+	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+}
+
+func (m *SendManyResponse) Reset()                    { *m = SendManyResponse{} }
+func (m *SendManyResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendManyResponse) ProtoMessage()               {}
+func (*SendManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+type NewAddressRequest struct {
+}
+
+func (m *NewAddressRequest) Reset()                    { *m = NewAddressRequest{} }
+func (m *NewAddressRequest) String() string            { return proto.CompactTextString(m) }
+func (*NewAddressRequest) ProtoMessage()               {}
+func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+type NewAddressResponse struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+}
+
+func (m *NewAddressResponse) Reset()                    { *m = NewAddressResponse{} }
+func (m *NewAddressResponse) String() string            { return proto.CompactTextString(m) }
+func (*NewAddressResponse) ProtoMessage()               {}
+func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+type GetRecoveryInfoRequest struct {
+}
+
+func (m *GetRecoveryInfoRequest) Reset()                    { *m = GetRecoveryInfoRequest{} }
+func (m *GetRecoveryInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetRecoveryInfoRequest) ProtoMessage()               {}
+func (*GetRecoveryInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+type GetRecoveryInfoResponse struct {
+	RecoveryMode     bool    `protobuf:"varint,1,opt,name=recoveryMode" json:"recoveryMode,omitempty"`
+	RecoveryFinished bool    `protobuf:"varint,2,opt,name=recoveryFinished" json:"recoveryFinished,omitempty"`
+	Progress         float64 `protobuf:"fixed64,3,opt,name=progress" json:"progress,omitempty"`
+}
+
+func (m *GetRecoveryInfoResponse) Reset()                    { *m = GetRecoveryInfoResponse{} }
+func (m *GetRecoveryInfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetRecoveryInfoResponse) ProtoMessage()               {}
+func (*GetRecoveryInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+type WalletBalanceRequest struct {
+	MinConfs int32 `protobuf:"varint,1,opt,name=minConfs" json:"minConfs,omitempty"`
+}
+
+func (m *WalletBalanceRequest) Reset()                    { *m = WalletBalanceRequest{} }
+func (m *WalletBalanceRequest) String() string            { return proto.CompactTextString(m) }
+func (*WalletBalanceRequest) ProtoMessage()               {}
+func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+type WalletBalanceResponse struct {
+	Balance int64 `protobuf:"varint,1,opt,name=balance" json:"balance,omitempty"`
+}
+
+func (m *WalletBalanceResponse) Reset()                    { *m = WalletBalanceResponse{} }
+func (m *WalletBalanceResponse) String() string            { return proto.CompactTextString(m) }
+func (*WalletBalanceResponse) ProtoMessage()               {}
+func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+type ChannelBalanceRequest struct {
+}
+
+func (m *ChannelBalanceRequest) Reset()                    { *m = ChannelBalanceRequest{} }
+func (m *ChannelBalanceRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelBalanceRequest) ProtoMessage()               {}
+func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+type ChannelTypeBalance struct {
+	CommitmentType        string `protobuf:"bytes,1,opt,name=commitmentType" json:"commitmentType,omitempty"`
+	NumChannels           uint32 `protobuf:"varint,2,opt,name=numChannels" json:"numChannels,omitempty"`
+	LocalBalanceMsat      uint64 `protobuf:"varint,3,opt,name=localBalanceMsat" json:"localBalanceMsat,omitempty"`
+	RemoteBalanceMsat     uint64 `protobuf:"varint,4,opt,name=remoteBalanceMsat" json:"remoteBalanceMsat,omitempty"`
+	UnsettledLocalMsat    uint64 `protobuf:"varint,5,opt,name=unsettledLocalMsat" json:"unsettledLocalMsat,omitempty"`
+	UnsettledRemoteMsat   uint64 `protobuf:"varint,6,opt,name=unsettledRemoteMsat" json:"unsettledRemoteMsat,omitempty"`
+	PendingOpenLocalMsat  uint64 `protobuf:"varint,7,opt,name=pendingOpenLocalMsat" json:"pendingOpenLocalMsat,omitempty"`
+	PendingOpenRemoteMsat uint64 `protobuf:"varint,8,opt,name=pendingOpenRemoteMsat" json:"pendingOpenRemoteMsat,omitempty"`
+}
+
+func (m *ChannelTypeBalance) Reset()                    { *m = ChannelTypeBalance{} }
+func (m *ChannelTypeBalance) String() string            { return proto.CompactTextString(m) }
+func (*ChannelTypeBalance) ProtoMessage()               {}
+func (*ChannelTypeBalance) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+type ChannelBalanceResponse struct {
+	Total          *ChannelTypeBalance   `protobuf:"bytes,1,opt,name=total" json:"total,omitempty"`
+	ByType         []*ChannelTypeBalance `protobuf:"bytes,2,rep,name=byType" json:"byType,omitempty"`
+	SpendableMsat  uint64                `protobuf:"varint,3,opt,name=spendableMsat" json:"spendableMsat,omitempty"`
+	ReceivableMsat uint64                `protobuf:"varint,4,opt,name=receivableMsat" json:"receivableMsat,omitempty"`
+}
+
+func (m *ChannelBalanceResponse) Reset()                    { *m = ChannelBalanceResponse{} }
+func (m *ChannelBalanceResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelBalanceResponse) ProtoMessage()               {}
+func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *ChannelBalanceResponse) GetTotal() *ChannelTypeBalance {
+	if m != nil {
+		return m.Total
+	}
+	return nil
+}
+
+func (m *ChannelBalanceResponse) GetByType() []*ChannelTypeBalance {
+	if m != nil {
+		return m.ByType
+	}
+	return nil
+}
+
+type GetInfoRequest struct {
+}
+
+func (m *GetInfoRequest) Reset()                    { *m = GetInfoRequest{} }
+func (m *GetInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetInfoRequest) ProtoMessage()               {}
+func (*GetInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+type GetInfoResponse struct {
+	IdentityPubkey    string                 `protobuf:"bytes,1,opt,name=identityPubkey" json:"identityPubkey,omitempty"`
+	NumPeers          uint32                 `protobuf:"varint,2,opt,name=numPeers" json:"numPeers,omitempty"`
+	ExternalAddresses []*AddressReachability `protobuf:"bytes,3,rep,name=externalAddresses" json:"externalAddresses,omitempty"`
+	SyncedToChain     bool                   `protobuf:"varint,4,opt,name=syncedToChain" json:"syncedToChain,omitempty"`
+}
+
+func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
+func (m *GetInfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetInfoResponse) ProtoMessage()               {}
+func (*GetInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *GetInfoResponse) GetExternalAddresses() []*AddressReachability {
+	if m != nil {
+		return m.ExternalAddresses
+	}
+	return nil
+}
+
+type AddressReachability struct {
+	Address   string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Reachable bool   `protobuf:"varint,2,opt,name=reachable" json:"reachable,omitempty"`
+	Error     string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	CheckedAt int64  `protobuf:"varint,4,opt,name=checkedAt" json:"checkedAt,omitempty"`
+}
+
+func (m *AddressReachability) Reset()                    { *m = AddressReachability{} }
+func (m *AddressReachability) String() string            { return proto.CompactTextString(m) }
+func (*AddressReachability) ProtoMessage()               {}
+func (*AddressReachability) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+type ConnectPeerRequest struct {
+	IdAtHost string `protobuf:"bytes,1,opt,name=idAtHost" json:"idAtHost,omitempty"`
+	Perm     bool   `protobuf:"varint,2,opt,name=perm" json:"perm,omitempty"`
+}
+
+func (m *ConnectPeerRequest) Reset()                    { *m = ConnectPeerRequest{} }
+func (m *ConnectPeerRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConnectPeerRequest) ProtoMessage()               {}
+func (*ConnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+type ConnectPeerResponse struct {
+	LnID []byte `protobuf:"bytes,1,opt,name=lnID,proto3" json:"lnID,omitempty"`
+}
+
+func (m *ConnectPeerResponse) Reset()                    { *m = ConnectPeerResponse{} }
+func (m *ConnectPeerResponse) String() string            { return proto.CompactTextString(m) }
+func (*ConnectPeerResponse) ProtoMessage()               {}
+func (*ConnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+type DisconnectPeerRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Force  bool   `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+}
+
+func (m *DisconnectPeerRequest) Reset()                    { *m = DisconnectPeerRequest{} }
+func (m *DisconnectPeerRequest) String() string            { return proto.CompactTextString(m) }
+func (*DisconnectPeerRequest) ProtoMessage()               {}
+func (*DisconnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+type DisconnectPeerResponse struct {
+}
+
+func (m *DisconnectPeerResponse) Reset()                    { *m = DisconnectPeerResponse{} }
+func (m *DisconnectPeerResponse) String() string            { return proto.CompactTextString(m) }
+func (*DisconnectPeerResponse) ProtoMessage()               {}
+func (*DisconnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+type ListPeersRequest struct {
+}
+
+func (m *ListPeersRequest) Reset()                    { *m = ListPeersRequest{} }
+func (m *ListPeersRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPeersRequest) ProtoMessage()               {}
+func (*ListPeersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+type Peer struct {
+	PubKey    string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Address   string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	PeerId    int32  `protobuf:"varint,3,opt,name=peerId" json:"peerId,omitempty"`
+	Inbound   bool   `protobuf:"varint,4,opt,name=inbound" json:"inbound,omitempty"`
+	Perm      bool   `protobuf:"varint,5,opt,name=perm" json:"perm,omitempty"`
+	FlapCount uint32 `protobuf:"varint,6,opt,name=flapCount" json:"flapCount,omitempty"`
+	Backoff   int64  `protobuf:"varint,7,opt,name=backoff" json:"backoff,omitempty"`
+	Label     string `protobuf:"bytes,8,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *Peer) Reset()                    { *m = Peer{} }
+func (m *Peer) String() string            { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()               {}
+func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+type ListPeersResponse struct {
+	Peers []*Peer `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *ListPeersResponse) Reset()                    { *m = ListPeersResponse{} }
+func (m *ListPeersResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPeersResponse) ProtoMessage()               {}
+func (*ListPeersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *ListPeersResponse) GetPeers() []*Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type SetPeerLabelRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Label  string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *SetPeerLabelRequest) Reset()                    { *m = SetPeerLabelRequest{} }
+func (m *SetPeerLabelRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetPeerLabelRequest) ProtoMessage()               {}
+func (*SetPeerLabelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+type SetPeerLabelResponse struct {
+}
+
+func (m *SetPeerLabelResponse) Reset()                    { *m = SetPeerLabelResponse{} }
+func (m *SetPeerLabelResponse) String() string            { return proto.CompactTextString(m) }
+func (*SetPeerLabelResponse) ProtoMessage()               {}
+func (*SetPeerLabelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+type ListPeerBackupsRequest struct {
+}
+
+func (m *ListPeerBackupsRequest) Reset()                    { *m = ListPeerBackupsRequest{} }
+func (m *ListPeerBackupsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPeerBackupsRequest) ProtoMessage()               {}
+func (*ListPeerBackupsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+type ChannelBackup struct {
+	LnID        []byte `protobuf:"bytes,1,opt,name=lnID,proto3" json:"lnID,omitempty"`
+	FundingTxid string `protobuf:"bytes,2,opt,name=fundingTxid" json:"fundingTxid,omitempty"`
+	ChanId      uint64 `protobuf:"varint,3,opt,name=chanId" json:"chanId,omitempty"`
+	Capacity    int64  `protobuf:"varint,4,opt,name=capacity" json:"capacity,omitempty"`
+}
+
+func (m *ChannelBackup) Reset()                    { *m = ChannelBackup{} }
+func (m *ChannelBackup) String() string            { return proto.CompactTextString(m) }
+func (*ChannelBackup) ProtoMessage()               {}
+func (*ChannelBackup) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+type PeerBackup struct {
+	PubKey   string           `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Channels []*ChannelBackup `protobuf:"bytes,2,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *PeerBackup) Reset()                    { *m = PeerBackup{} }
+func (m *PeerBackup) String() string            { return proto.CompactTextString(m) }
+func (*PeerBackup) ProtoMessage()               {}
+func (*PeerBackup) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *PeerBackup) GetChannels() []*ChannelBackup {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type ListPeerBackupsResponse struct {
+	Backups []*PeerBackup `protobuf:"bytes,1,rep,name=backups" json:"backups,omitempty"`
+}
+
+func (m *ListPeerBackupsResponse) Reset()                    { *m = ListPeerBackupsResponse{} }
+func (m *ListPeerBackupsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPeerBackupsResponse) ProtoMessage()               {}
+func (*ListPeerBackupsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *ListPeerBackupsResponse) GetBackups() []*PeerBackup {
+	if m != nil {
+		return m.Backups
+	}
+	return nil
+}
+
+type SendSignedDataRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Type   uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	Data   []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *SendSignedDataRequest) Reset()                    { *m = SendSignedDataRequest{} }
+func (m *SendSignedDataRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendSignedDataRequest) ProtoMessage()               {}
+func (*SendSignedDataRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+type SendSignedDataResponse struct {
+}
+
+func (m *SendSignedDataResponse) Reset()                    { *m = SendSignedDataResponse{} }
+func (m *SendSignedDataResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendSignedDataResponse) ProtoMessage()               {}
+func (*SendSignedDataResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+type SubscribeSignedDataRequest struct {
+}
+
+func (m *SubscribeSignedDataRequest) Reset()                    { *m = SubscribeSignedDataRequest{} }
+func (m *SubscribeSignedDataRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeSignedDataRequest) ProtoMessage()               {}
+func (*SubscribeSignedDataRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+type SignedPeerData struct {
+	PubKey    string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Type      uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	Data      []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedPeerData) Reset()                    { *m = SignedPeerData{} }
+func (m *SignedPeerData) String() string            { return proto.CompactTextString(m) }
+func (*SignedPeerData) ProtoMessage()               {}
+func (*SignedPeerData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+type SignPeerDataRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Type   uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	Data   []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *SignPeerDataRequest) Reset()                    { *m = SignPeerDataRequest{} }
+func (m *SignPeerDataRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignPeerDataRequest) ProtoMessage()               {}
+func (*SignPeerDataRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+type SignPeerDataResponse struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignPeerDataResponse) Reset()                    { *m = SignPeerDataResponse{} }
+func (m *SignPeerDataResponse) String() string            { return proto.CompactTextString(m) }
+func (*SignPeerDataResponse) ProtoMessage()               {}
+func (*SignPeerDataResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+type VerifyPeerDataRequest struct {
+	PubKey    string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Type      uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	Data      []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *VerifyPeerDataRequest) Reset()                    { *m = VerifyPeerDataRequest{} }
+func (m *VerifyPeerDataRequest) String() string            { return proto.CompactTextString(m) }
+func (*VerifyPeerDataRequest) ProtoMessage()               {}
+func (*VerifyPeerDataRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+type VerifyPeerDataResponse struct {
+	Valid bool `protobuf:"varint,1,opt,name=valid" json:"valid,omitempty"`
+}
+
+func (m *VerifyPeerDataResponse) Reset()                    { *m = VerifyPeerDataResponse{} }
+func (m *VerifyPeerDataResponse) String() string            { return proto.CompactTextString(m) }
+func (*VerifyPeerDataResponse) ProtoMessage()               {}
+func (*VerifyPeerDataResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+type ListPeerMisbehaviorRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+}
+
+func (m *ListPeerMisbehaviorRequest) Reset()                    { *m = ListPeerMisbehaviorRequest{} }
+func (m *ListPeerMisbehaviorRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPeerMisbehaviorRequest) ProtoMessage()               {}
+func (*ListPeerMisbehaviorRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+type Misbehavior struct {
+	Type        string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Timestamp   int64  `protobuf:"varint,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	Description string `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *Misbehavior) Reset()                    { *m = Misbehavior{} }
+func (m *Misbehavior) String() string            { return proto.CompactTextString(m) }
+func (*Misbehavior) ProtoMessage()               {}
+func (*Misbehavior) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+type PeerMisbehavior struct {
+	PubKey       string         `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Misbehaviors []*Misbehavior `protobuf:"bytes,2,rep,name=misbehaviors" json:"misbehaviors,omitempty"`
+}
+
+func (m *PeerMisbehavior) Reset()                    { *m = PeerMisbehavior{} }
+func (m *PeerMisbehavior) String() string            { return proto.CompactTextString(m) }
+func (*PeerMisbehavior) ProtoMessage()               {}
+func (*PeerMisbehavior) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *PeerMisbehavior) GetMisbehaviors() []*Misbehavior {
+	if m != nil {
+		return m.Misbehaviors
+	}
+	return nil
+}
+
+type ListPeerMisbehaviorResponse struct {
+	Peers []*PeerMisbehavior `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *ListPeerMisbehaviorResponse) Reset()                    { *m = ListPeerMisbehaviorResponse{} }
+func (m *ListPeerMisbehaviorResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPeerMisbehaviorResponse) ProtoMessage()               {}
+func (*ListPeerMisbehaviorResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *ListPeerMisbehaviorResponse) GetPeers() []*PeerMisbehavior {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type PaymentAttempt struct {
+	HtlcKey       uint64        `protobuf:"varint,1,opt,name=htlcKey" json:"htlcKey,omitempty"`
+	Route         [][]byte      `protobuf:"bytes,2,rep,name=route,proto3" json:"route,omitempty"`
+	Amount        int64         `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	AttemptTime   int64         `protobuf:"varint,4,opt,name=attemptTime" json:"attemptTime,omitempty"`
+	Status        PaymentStatus `protobuf:"varint,5,opt,name=status,enum=lnrpc.PaymentStatus" json:"status,omitempty"`
+	FailureReason string        `protobuf:"bytes,6,opt,name=failureReason" json:"failureReason,omitempty"`
+	AmountMsat    uint64        `protobuf:"varint,7,opt,name=amountMsat" json:"amountMsat,omitempty"`
+}
+
+func (m *PaymentAttempt) Reset()                    { *m = PaymentAttempt{} }
+func (m *PaymentAttempt) String() string            { return proto.CompactTextString(m) }
+func (*PaymentAttempt) ProtoMessage()               {}
+func (*PaymentAttempt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+type Payment struct {
+	PaymentIndex uint64            `protobuf:"varint,1,opt,name=paymentIndex" json:"paymentIndex,omitempty"`
+	PaymentHash  []byte            `protobuf:"bytes,2,opt,name=paymentHash,proto3" json:"paymentHash,omitempty"`
+	Amount       int64             `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	CreationTime int64             `protobuf:"varint,4,opt,name=creationTime" json:"creationTime,omitempty"`
+	Status       PaymentStatus     `protobuf:"varint,5,opt,name=status,enum=lnrpc.PaymentStatus" json:"status,omitempty"`
+	Attempts     []*PaymentAttempt `protobuf:"bytes,6,rep,name=attempts" json:"attempts,omitempty"`
+	AmountMsat   uint64            `protobuf:"varint,7,opt,name=amountMsat" json:"amountMsat,omitempty"`
+}
+
+func (m *Payment) Reset()                    { *m = Payment{} }
+func (m *Payment) String() string            { return proto.CompactTextString(m) }
+func (*Payment) ProtoMessage()               {}
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *Payment) GetAttempts() []*PaymentAttempt {
+	if m != nil {
+		return m.Attempts
+	}
+	return nil
+}
+
+type FeeLimit struct {
+	Fixed     int64 `protobuf:"varint,1,opt,name=fixed" json:"fixed,omitempty"`
+	FixedMsat int64 `protobuf:"varint,2,opt,name=fixedMsat" json:"fixedMsat,omitempty"`
+	Percent   int64 `protobuf:"varint,3,opt,name=percent" json:"percent,omitempty"`
+}
+
+func (m *FeeLimit) Reset()                    { *m = FeeLimit{} }
+func (m *FeeLimit) String() string            { return proto.CompactTextString(m) }
+func (*FeeLimit) ProtoMessage()               {}
+func (*FeeLimit) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+type SendPaymentRequest struct {
+	Dest            string    `protobuf:"bytes,1,opt,name=dest" json:"dest,omitempty"`
+	Amt             int64     `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+	PaymentHash     []byte    `protobuf:"bytes,3,opt,name=paymentHash,proto3" json:"paymentHash,omitempty"`
+	AmtMsat         uint64    `protobuf:"varint,4,opt,name=amtMsat" json:"amtMsat,omitempty"`
+	TimeoutSeconds  uint32    `protobuf:"varint,5,opt,name=timeoutSeconds" json:"timeoutSeconds,omitempty"`
+	FeeLimit        *FeeLimit `protobuf:"bytes,6,opt,name=feeLimit" json:"feeLimit,omitempty"`
+	MaxParts        uint32    `protobuf:"varint,7,opt,name=maxParts" json:"maxParts,omitempty"`
+	CltvLimit       uint32    `protobuf:"varint,8,opt,name=cltvLimit" json:"cltvLimit,omitempty"`
+	OutgoingChanIds []uint64  `protobuf:"varint,9,rep,packed,name=outgoingChanIds" json:"outgoingChanIds,omitempty"`
+	PaymentSecret   []byte    `protobuf:"bytes,12,opt,name=paymentSecret,proto3" json:"paymentSecret,omitempty"`
+}
+
+func (m *SendPaymentRequest) Reset()                    { *m = SendPaymentRequest{} }
+func (m *SendPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendPaymentRequest) ProtoMessage()               {}
+func (*SendPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *SendPaymentRequest) GetFeeLimit() *FeeLimit {
+	if m != nil {
+		return m.FeeLimit
+	}
+	return nil
+}
+
+type SendPaymentResponse struct {
+	PaymentPreimage []byte `protobuf:"bytes,1,opt,name=paymentPreimage,proto3" json:"paymentPreimage,omitempty"`
+}
+
+func (m *SendPaymentResponse) Reset()                    { *m = SendPaymentResponse{} }
+func (m *SendPaymentResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendPaymentResponse) ProtoMessage()               {}
+func (*SendPaymentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+type QueryRoutesRequest struct {
+	PubKey          string    `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	Amt             int64     `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+	AmtMsat         uint64    `protobuf:"varint,3,opt,name=amtMsat" json:"amtMsat,omitempty"`
+	FeeLimit        *FeeLimit `protobuf:"bytes,4,opt,name=feeLimit" json:"feeLimit,omitempty"`
+	CltvLimit       uint32    `protobuf:"varint,5,opt,name=cltvLimit" json:"cltvLimit,omitempty"`
+	OutgoingChanIds []uint64  `protobuf:"varint,6,rep,packed,name=outgoingChanIds" json:"outgoingChanIds,omitempty"`
+	LastHopPubkey   []byte    `protobuf:"bytes,7,opt,name=lastHopPubkey,proto3" json:"lastHopPubkey,omitempty"`
+}
+
+func (m *QueryRoutesRequest) Reset()                    { *m = QueryRoutesRequest{} }
+func (m *QueryRoutesRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryRoutesRequest) ProtoMessage()               {}
+func (*QueryRoutesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *QueryRoutesRequest) GetFeeLimit() *FeeLimit {
+	if m != nil {
+		return m.FeeLimit
+	}
+	return nil
+}
+
+type Hop struct {
+	ChanId           uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	PubKey           string `protobuf:"bytes,2,opt,name=pubKey" json:"pubKey,omitempty"`
+	AmtToForward     int64  `protobuf:"varint,3,opt,name=amtToForward" json:"amtToForward,omitempty"`
+	AmtToForwardMsat uint64 `protobuf:"varint,4,opt,name=amtToForwardMsat" json:"amtToForwardMsat,omitempty"`
+	Expiry           uint32 `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+}
+
+func (m *Hop) Reset()                    { *m = Hop{} }
+func (m *Hop) String() string            { return proto.CompactTextString(m) }
+func (*Hop) ProtoMessage()               {}
+func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+type Route struct {
+	TotalTimeLock uint32 `protobuf:"varint,1,opt,name=totalTimeLock" json:"totalTimeLock,omitempty"`
+	TotalFees     int64  `protobuf:"varint,2,opt,name=totalFees" json:"totalFees,omitempty"`
+	TotalAmt      int64  `protobuf:"varint,3,opt,name=totalAmt" json:"totalAmt,omitempty"`
+	TotalFeesMsat uint64 `protobuf:"varint,4,opt,name=totalFeesMsat" json:"totalFeesMsat,omitempty"`
+	TotalAmtMsat  uint64 `protobuf:"varint,5,opt,name=totalAmtMsat" json:"totalAmtMsat,omitempty"`
+	Hops          []*Hop `protobuf:"bytes,6,rep,name=hops" json:"hops,omitempty"`
+}
+
+func (m *Route) Reset()                    { *m = Route{} }
+func (m *Route) String() string            { return proto.CompactTextString(m) }
+func (*Route) ProtoMessage()               {}
+func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *Route) GetHops() []*Hop {
+	if m != nil {
+		return m.Hops
+	}
+	return nil
+}
+
+type QueryRoutesResponse struct {
+	Routes []*Route `protobuf:"bytes,1,rep,name=routes" json:"routes,omitempty"`
+}
+
+func (m *QueryRoutesResponse) Reset()                    { *m = QueryRoutesResponse{} }
+func (m *QueryRoutesResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryRoutesResponse) ProtoMessage()               {}
+func (*QueryRoutesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *QueryRoutesResponse) GetRoutes() []*Route {
+	if m != nil {
+		return m.Routes
+	}
+	return nil
+}
+
+type EstimateRouteFeeRequest struct {
+	Dest    string `protobuf:"bytes,1,opt,name=dest" json:"dest,omitempty"`
+	Amt     int64  `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+	AmtMsat uint64 `protobuf:"varint,3,opt,name=amtMsat" json:"amtMsat,omitempty"`
+}
+
+func (m *EstimateRouteFeeRequest) Reset()                    { *m = EstimateRouteFeeRequest{} }
+func (m *EstimateRouteFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*EstimateRouteFeeRequest) ProtoMessage()               {}
+func (*EstimateRouteFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+type EstimateRouteFeeResponse struct {
+	RoutingFeeMsat uint64 `protobuf:"varint,1,opt,name=routingFeeMsat" json:"routingFeeMsat,omitempty"`
+	TimeLockDelay  uint32 `protobuf:"varint,2,opt,name=timeLockDelay" json:"timeLockDelay,omitempty"`
+	NumHops        uint32 `protobuf:"varint,3,opt,name=numHops" json:"numHops,omitempty"`
+}
+
+func (m *EstimateRouteFeeResponse) Reset()                    { *m = EstimateRouteFeeResponse{} }
+func (m *EstimateRouteFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*EstimateRouteFeeResponse) ProtoMessage()               {}
+func (*EstimateRouteFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+type ListPaymentsRequest struct {
+	IndexOffset uint64 `protobuf:"varint,1,opt,name=indexOffset" json:"indexOffset,omitempty"`
+	MaxPayments uint64 `protobuf:"varint,2,opt,name=maxPayments" json:"maxPayments,omitempty"`
+}
+
+func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
+func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPaymentsRequest) ProtoMessage()               {}
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+type ListPaymentsResponse struct {
+	Payments        []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	LastIndexOffset uint64     `protobuf:"varint,2,opt,name=lastIndexOffset" json:"lastIndexOffset,omitempty"`
+}
+
+func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
+func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPaymentsResponse) ProtoMessage()               {}
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *ListPaymentsResponse) GetPayments() []*Payment {
+	if m != nil {
+		return m.Payments
+	}
+	return nil
+}
+
+type DeletePaymentRequest struct {
+	PaymentHash        []byte `protobuf:"bytes,1,opt,name=paymentHash,proto3" json:"paymentHash,omitempty"`
+	FailedAttemptsOnly bool   `protobuf:"varint,2,opt,name=failedAttemptsOnly" json:"failedAttemptsOnly,omitempty"`
+}
+
+func (m *DeletePaymentRequest) Reset()                    { *m = DeletePaymentRequest{} }
+func (m *DeletePaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeletePaymentRequest) ProtoMessage()               {}
+func (*DeletePaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+type DeletePaymentResponse struct {
+}
+
+func (m *DeletePaymentResponse) Reset()                    { *m = DeletePaymentResponse{} }
+func (m *DeletePaymentResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeletePaymentResponse) ProtoMessage()               {}
+func (*DeletePaymentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+type DeleteAllPaymentsRequest struct {
+	FailedPaymentsOnly bool `protobuf:"varint,1,opt,name=failedPaymentsOnly" json:"failedPaymentsOnly,omitempty"`
+	FailedAttemptsOnly bool `protobuf:"varint,2,opt,name=failedAttemptsOnly" json:"failedAttemptsOnly,omitempty"`
+}
+
+func (m *DeleteAllPaymentsRequest) Reset()                    { *m = DeleteAllPaymentsRequest{} }
+func (m *DeleteAllPaymentsRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteAllPaymentsRequest) ProtoMessage()               {}
+func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+type DeleteAllPaymentsResponse struct {
+}
+
+func (m *DeleteAllPaymentsResponse) Reset()                    { *m = DeleteAllPaymentsResponse{} }
+func (m *DeleteAllPaymentsResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeleteAllPaymentsResponse) ProtoMessage()               {}
+func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+type Htlc struct {
+	ChanId         uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	HtlcKey        uint64 `protobuf:"varint,2,opt,name=htlcKey" json:"htlcKey,omitempty"`
+	Incoming       bool   `protobuf:"varint,3,opt,name=incoming" json:"incoming,omitempty"`
+	AmountMsat     uint64 `protobuf:"varint,4,opt,name=amountMsat" json:"amountMsat,omitempty"`
+	Expiry         uint32 `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+	PaymentHash    []byte `protobuf:"bytes,6,opt,name=paymentHash,proto3" json:"paymentHash,omitempty"`
+	IncomingChanId uint64 `protobuf:"varint,7,opt,name=incomingChanId" json:"incomingChanId,omitempty"`
+	OutgoingChanId uint64 `protobuf:"varint,8,opt,name=outgoingChanId" json:"outgoingChanId,omitempty"`
+}
+
+func (m *Htlc) Reset()                    { *m = Htlc{} }
+func (m *Htlc) String() string            { return proto.CompactTextString(m) }
+func (*Htlc) ProtoMessage()               {}
+func (*Htlc) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+type ListHtlcsRequest struct {
+	ChanId uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+}
+
+func (m *ListHtlcsRequest) Reset()                    { *m = ListHtlcsRequest{} }
+func (m *ListHtlcsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListHtlcsRequest) ProtoMessage()               {}
+func (*ListHtlcsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+type ListHtlcsResponse struct {
+	Htlcs []*Htlc `protobuf:"bytes,1,rep,name=htlcs" json:"htlcs,omitempty"`
+}
+
+func (m *ListHtlcsResponse) Reset()                    { *m = ListHtlcsResponse{} }
+func (m *ListHtlcsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListHtlcsResponse) ProtoMessage()               {}
+func (*ListHtlcsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *ListHtlcsResponse) GetHtlcs() []*Htlc {
+	if m != nil {
+		return m.Htlcs
+	}
+	return nil
+}
+
+type LookupHtlcResolutionRequest struct {
+	ChanId  uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	HtlcKey uint64 `protobuf:"varint,2,opt,name=htlcKey" json:"htlcKey,omitempty"`
+}
+
+func (m *LookupHtlcResolutionRequest) Reset()                    { *m = LookupHtlcResolutionRequest{} }
+func (m *LookupHtlcResolutionRequest) String() string            { return proto.CompactTextString(m) }
+func (*LookupHtlcResolutionRequest) ProtoMessage()               {}
+func (*LookupHtlcResolutionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+type LookupHtlcResolutionResponse struct {
+	Htlc          *Htlc         `protobuf:"bytes,1,opt,name=htlc" json:"htlc,omitempty"`
+	Status        PaymentStatus `protobuf:"varint,2,opt,name=status,enum=lnrpc.PaymentStatus" json:"status,omitempty"`
+	FailureReason string        `protobuf:"bytes,3,opt,name=failureReason" json:"failureReason,omitempty"`
+	ResolveTime   int64         `protobuf:"varint,4,opt,name=resolveTime" json:"resolveTime,omitempty"`
+}
+
+func (m *LookupHtlcResolutionResponse) Reset()                    { *m = LookupHtlcResolutionResponse{} }
+func (m *LookupHtlcResolutionResponse) String() string            { return proto.CompactTextString(m) }
+func (*LookupHtlcResolutionResponse) ProtoMessage()               {}
+func (*LookupHtlcResolutionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *LookupHtlcResolutionResponse) GetHtlc() *Htlc {
+	if m != nil {
+		return m.Htlc
+	}
+	return nil
+}
+
+type ExportAccountingRequest struct {
+	StartTime  int64                 `protobuf:"varint,1,opt,name=startTime" json:"startTime,omitempty"`
+	EndTime    int64                 `protobuf:"varint,2,opt,name=endTime" json:"endTime,omitempty"`
+	Format     AccountingFormat      `protobuf:"varint,3,opt,name=format,enum=lnrpc.AccountingFormat" json:"format,omitempty"`
+	EntryTypes []AccountingEntryType `protobuf:"varint,4,rep,packed,name=entryTypes,enum=lnrpc.AccountingEntryType" json:"entryTypes,omitempty"`
+}
+
+func (m *ExportAccountingRequest) Reset()                    { *m = ExportAccountingRequest{} }
+func (m *ExportAccountingRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportAccountingRequest) ProtoMessage()               {}
+func (*ExportAccountingRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+type ExportAccountingResponse struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *ExportAccountingResponse) Reset()                    { *m = ExportAccountingResponse{} }
+func (m *ExportAccountingResponse) String() string            { return proto.CompactTextString(m) }
+func (*ExportAccountingResponse) ProtoMessage()               {}
+func (*ExportAccountingResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+type Invoice struct {
+	Memo          string       `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
+	RPreimage     []byte       `protobuf:"bytes,2,opt,name=rPreimage,proto3" json:"rPreimage,omitempty"`
+	RHash         []byte       `protobuf:"bytes,3,opt,name=rHash,proto3" json:"rHash,omitempty"`
+	Value         int64        `protobuf:"varint,4,opt,name=value" json:"value,omitempty"`
+	ValueMsat     uint64       `protobuf:"varint,5,opt,name=valueMsat" json:"valueMsat,omitempty"`
+	CreationDate  int64        `protobuf:"varint,6,opt,name=creationDate" json:"creationDate,omitempty"`
+	Expiry        int64        `protobuf:"varint,7,opt,name=expiry" json:"expiry,omitempty"`
+	State         InvoiceState `protobuf:"varint,8,opt,name=state,enum=lnrpc.InvoiceState" json:"state,omitempty"`
+	PaymentSecret []byte       `protobuf:"bytes,9,opt,name=paymentSecret,proto3" json:"paymentSecret,omitempty"`
+	Amp           bool         `protobuf:"varint,10,opt,name=amp" json:"amp,omitempty"`
+	SetId         []byte       `protobuf:"bytes,11,opt,name=setId,proto3" json:"setId,omitempty"`
+}
+
+func (m *Invoice) Reset()                    { *m = Invoice{} }
+func (m *Invoice) String() string            { return proto.CompactTextString(m) }
+func (*Invoice) ProtoMessage()               {}
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+
+type AddInvoiceResponse struct {
+	RHash         []byte `protobuf:"bytes,1,opt,name=rHash,proto3" json:"rHash,omitempty"`
+	PaymentSecret []byte `protobuf:"bytes,2,opt,name=paymentSecret,proto3" json:"paymentSecret,omitempty"`
+}
+
+func (m *AddInvoiceResponse) Reset()                    { *m = AddInvoiceResponse{} }
+func (m *AddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddInvoiceResponse) ProtoMessage()               {}
+func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+
+type InvoiceSubscription struct {
+}
+
+func (m *InvoiceSubscription) Reset()                    { *m = InvoiceSubscription{} }
+func (m *InvoiceSubscription) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceSubscription) ProtoMessage()               {}
+func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+type ImportAccountRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	ExtendedPublicKey    string   `protobuf:"bytes,2,opt,name=extendedPublicKey" json:"extendedPublicKey,omitempty"`
+	MasterKeyFingerprint uint32   `protobuf:"varint,3,opt,name=masterKeyFingerprint" json:"masterKeyFingerprint,omitempty"`
+	DerivationPath       []uint32 `protobuf:"varint,4,rep,packed,name=derivationPath" json:"derivationPath,omitempty"`
+	Rescan               bool     `protobuf:"varint,5,opt,name=rescan" json:"rescan,omitempty"`
+}
+
+func (m *ImportAccountRequest) Reset()                    { *m = ImportAccountRequest{} }
+func (m *ImportAccountRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportAccountRequest) ProtoMessage()               {}
+func (*ImportAccountRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+type ImportAccountResponse struct {
+}
+
+func (m *ImportAccountResponse) Reset()                    { *m = ImportAccountResponse{} }
+func (m *ImportAccountResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportAccountResponse) ProtoMessage()               {}
+func (*ImportAccountResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+type ImportPublicKeyRequest struct {
+	Account   string `protobuf:"bytes,1,opt,name=account" json:"account,omitempty"`
+	PublicKey []byte `protobuf:"bytes,2,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+	Rescan    bool   `protobuf:"varint,3,opt,name=rescan" json:"rescan,omitempty"`
+}
+
+func (m *ImportPublicKeyRequest) Reset()                    { *m = ImportPublicKeyRequest{} }
+func (m *ImportPublicKeyRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportPublicKeyRequest) ProtoMessage()               {}
+func (*ImportPublicKeyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+
+type ImportPublicKeyResponse struct {
+}
+
+func (m *ImportPublicKeyResponse) Reset()                    { *m = ImportPublicKeyResponse{} }
+func (m *ImportPublicKeyResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportPublicKeyResponse) ProtoMessage()               {}
+func (*ImportPublicKeyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+type FundPsbtRequest struct {
+	Psbt     []byte           `protobuf:"bytes,1,opt,name=psbt,proto3" json:"psbt,omitempty"`
+	Outputs  map[string]int64 `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Account  string           `protobuf:"bytes,3,opt,name=account" json:"account,omitempty"`
+	SatPerKb int64            `protobuf:"varint,4,opt,name=satPerKb" json:"satPerKb,omitempty"`
+	MinConfs int32            `protobuf:"varint,5,opt,name=minConfs" json:"minConfs,omitempty"`
+}
+
+func (m *FundPsbtRequest) Reset()                    { *m = FundPsbtRequest{} }
+func (m *FundPsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*FundPsbtRequest) ProtoMessage()               {}
+func (*FundPsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+func (m *FundPsbtRequest) GetOutputs() map[string]int64 {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+type FundPsbtResponse struct {
+	FundedPsbt        []byte `protobuf:"bytes,1,opt,name=fundedPsbt,proto3" json:"fundedPsbt,omitempty"`
+	ChangeOutputIndex int32  `protobuf:"varint,2,opt,name=changeOutputIndex" json:"changeOutputIndex,omitempty"`
+}
+
+func (m *FundPsbtResponse) Reset()                    { *m = FundPsbtResponse{} }
+func (m *FundPsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*FundPsbtResponse) ProtoMessage()               {}
+func (*FundPsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+type SignPsbtRequest struct {
+	FundedPsbt []byte `protobuf:"bytes,1,opt,name=fundedPsbt,proto3" json:"fundedPsbt,omitempty"`
+}
+
+func (m *SignPsbtRequest) Reset()                    { *m = SignPsbtRequest{} }
+func (m *SignPsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignPsbtRequest) ProtoMessage()               {}
+func (*SignPsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+type SignPsbtResponse struct {
+	SignedPsbt   []byte   `protobuf:"bytes,1,opt,name=signedPsbt,proto3" json:"signedPsbt,omitempty"`
+	SignedInputs []uint32 `protobuf:"varint,2,rep,packed,name=signedInputs" json:"signedInputs,omitempty"`
+}
+
+func (m *SignPsbtResponse) Reset()                    { *m = SignPsbtResponse{} }
+func (m *SignPsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*SignPsbtResponse) ProtoMessage()               {}
+func (*SignPsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+
+type FinalizePsbtRequest struct {
+	SignedPsbt []byte `protobuf:"bytes,1,opt,name=signedPsbt,proto3" json:"signedPsbt,omitempty"`
+	Publish    bool   `protobuf:"varint,2,opt,name=publish" json:"publish,omitempty"`
+}
+
+func (m *FinalizePsbtRequest) Reset()                    { *m = FinalizePsbtRequest{} }
+func (m *FinalizePsbtRequest) String() string            { return proto.CompactTextString(m) }
+func (*FinalizePsbtRequest) ProtoMessage()               {}
+func (*FinalizePsbtRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+type FinalizePsbtResponse struct {
+	SignedPsbt []byte `protobuf:"bytes,1,opt,name=signedPsbt,proto3" json:"signedPsbt,omitempty"`
+	RawFinalTx []byte `protobuf:"bytes,2,opt,name=rawFinalTx,proto3" json:"rawFinalTx,omitempty"`
+	Txid       string `protobuf:"bytes,3,opt,name=txid" json:"txid,omitempty"`
+}
+
+func (m *FinalizePsbtResponse) Reset()                    { *m = FinalizePsbtResponse{} }
+func (m *FinalizePsbtResponse) String() string            { return proto.CompactTextString(m) }
+func (*FinalizePsbtResponse) ProtoMessage()               {}
+func (*FinalizePsbtResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+type BumpFeeRequest struct {
+	Txid        string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	OutputIndex uint32 `protobuf:"varint,2,opt,name=outputIndex" json:"outputIndex,omitempty"`
+	SatPerKb    int64  `protobuf:"varint,3,opt,name=satPerKb" json:"satPerKb,omitempty"`
+}
+
+func (m *BumpFeeRequest) Reset()                    { *m = BumpFeeRequest{} }
+func (m *BumpFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeRequest) ProtoMessage()               {}
+func (*BumpFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+
+type BumpFeeResponse struct {
+	ChildTxid string `protobuf:"bytes,1,opt,name=childTxid" json:"childTxid,omitempty"`
+}
+
+func (m *BumpFeeResponse) Reset()                    { *m = BumpFeeResponse{} }
+func (m *BumpFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*BumpFeeResponse) ProtoMessage()               {}
+func (*BumpFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+type PendingSweepsRequest struct {
+}
+
+func (m *PendingSweepsRequest) Reset()                    { *m = PendingSweepsRequest{} }
+func (m *PendingSweepsRequest) String() string            { return proto.CompactTextString(m) }
+func (*PendingSweepsRequest) ProtoMessage()               {}
+func (*PendingSweepsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+type PendingSweep struct {
+	Outpoint            string `protobuf:"bytes,1,opt,name=outpoint" json:"outpoint,omitempty"`
+	Amount              int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	Deadline            int32  `protobuf:"varint,3,opt,name=deadline" json:"deadline,omitempty"`
+	Budget              int64  `protobuf:"varint,4,opt,name=budget" json:"budget,omitempty"`
+	SatPerKb            int64  `protobuf:"varint,5,opt,name=satPerKb" json:"satPerKb,omitempty"`
+	SweepTxid           string `protobuf:"bytes,6,opt,name=sweepTxid" json:"sweepTxid,omitempty"`
+	BroadcastAttempts   uint32 `protobuf:"varint,7,opt,name=broadcastAttempts" json:"broadcastAttempts,omitempty"`
+	LastError           string `protobuf:"bytes,8,opt,name=lastError" json:"lastError,omitempty"`
+	NextBroadcastHeight int32  `protobuf:"varint,9,opt,name=nextBroadcastHeight" json:"nextBroadcastHeight,omitempty"`
+}
+
+func (m *PendingSweep) Reset()                    { *m = PendingSweep{} }
+func (m *PendingSweep) String() string            { return proto.CompactTextString(m) }
+func (*PendingSweep) ProtoMessage()               {}
+func (*PendingSweep) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
+type PendingSweepsResponse struct {
+	PendingSweeps []*PendingSweep `protobuf:"bytes,1,rep,name=pendingSweeps" json:"pendingSweeps,omitempty"`
+}
+
+func (m *PendingSweepsResponse) Reset()                    { *m = PendingSweepsResponse{} }
+func (m *PendingSweepsResponse) String() string            { return proto.CompactTextString(m) }
+func (*PendingSweepsResponse) ProtoMessage()               {}
+func (*PendingSweepsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+
+func (m *PendingSweepsResponse) GetPendingSweeps() []*PendingSweep {
+	if m != nil {
+		return m.PendingSweeps
+	}
+	return nil
+}
+
+type ListSweepsRequest struct {
+}
+
+func (m *ListSweepsRequest) Reset()                    { *m = ListSweepsRequest{} }
+func (m *ListSweepsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListSweepsRequest) ProtoMessage()               {}
+func (*ListSweepsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
+
+type SweepTransaction struct {
+	Txid              string   `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Outpoints         []string `protobuf:"bytes,2,rep,name=outpoints" json:"outpoints,omitempty"`
+	Amount            int64    `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	Fee               int64    `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
+	ConfirmedHeight   uint32   `protobuf:"varint,5,opt,name=confirmedHeight" json:"confirmedHeight,omitempty"`
+	BroadcastAttempts uint32   `protobuf:"varint,6,opt,name=broadcastAttempts" json:"broadcastAttempts,omitempty"`
+}
+
+func (m *SweepTransaction) Reset()                    { *m = SweepTransaction{} }
+func (m *SweepTransaction) String() string            { return proto.CompactTextString(m) }
+func (*SweepTransaction) ProtoMessage()               {}
+func (*SweepTransaction) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
+
+type ListSweepsResponse struct {
+	Sweeps []*SweepTransaction `protobuf:"bytes,1,rep,name=sweeps" json:"sweeps,omitempty"`
+}
+
+func (m *ListSweepsResponse) Reset()                    { *m = ListSweepsResponse{} }
+func (m *ListSweepsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListSweepsResponse) ProtoMessage()               {}
+func (*ListSweepsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
+
+func (m *ListSweepsResponse) GetSweeps() []*SweepTransaction {
+	if m != nil {
+		return m.Sweeps
+	}
+	return nil
+}
+
+type AbandonChannelRequest struct {
+	LnID              []byte `protobuf:"bytes,1,opt,name=lnID,proto3" json:"lnID,omitempty"`
+	FundingTxid       string `protobuf:"bytes,2,opt,name=fundingTxid" json:"fundingTxid,omitempty"`
+	IKnowWhatIAmDoing bool   `protobuf:"varint,3,opt,name=iKnowWhatIAmDoing" json:"iKnowWhatIAmDoing,omitempty"`
+}
+
+func (m *AbandonChannelRequest) Reset()                    { *m = AbandonChannelRequest{} }
+func (m *AbandonChannelRequest) String() string            { return proto.CompactTextString(m) }
+func (*AbandonChannelRequest) ProtoMessage()               {}
+func (*AbandonChannelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
+
+type AbandonChannelResponse struct {
+}
+
+func (m *AbandonChannelResponse) Reset()                    { *m = AbandonChannelResponse{} }
+func (m *AbandonChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*AbandonChannelResponse) ProtoMessage()               {}
+func (*AbandonChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
+
+type UpdateChannelParamsRequest struct {
+	PubKey               string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+	MaxAcceptedHtlcs     uint32 `protobuf:"varint,2,opt,name=maxAcceptedHtlcs" json:"maxAcceptedHtlcs,omitempty"`
+	MaxValueInFlightMsat uint64 `protobuf:"varint,3,opt,name=maxValueInFlightMsat" json:"maxValueInFlightMsat,omitempty"`
+	CsvDelay             uint32 `protobuf:"varint,4,opt,name=csvDelay" json:"csvDelay,omitempty"`
+}
+
+func (m *UpdateChannelParamsRequest) Reset()                    { *m = UpdateChannelParamsRequest{} }
+func (m *UpdateChannelParamsRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateChannelParamsRequest) ProtoMessage()               {}
+func (*UpdateChannelParamsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
+
+type UpdateChannelParamsResponse struct {
+}
+
+func (m *UpdateChannelParamsResponse) Reset()                    { *m = UpdateChannelParamsResponse{} }
+func (m *UpdateChannelParamsResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateChannelParamsResponse) ProtoMessage()               {}
+func (*UpdateChannelParamsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
+
+type ListPendingReservationsRequest struct {
+}
+
+func (m *ListPendingReservationsRequest) Reset()         { *m = ListPendingReservationsRequest{} }
+func (m *ListPendingReservationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListPendingReservationsRequest) ProtoMessage()    {}
+func (*ListPendingReservationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{95}
+}
+
+type PendingReservation struct {
+	ReservationId    uint64 `protobuf:"varint,1,opt,name=reservationId" json:"reservationId,omitempty"`
+	LnID             []byte `protobuf:"bytes,2,opt,name=lnID,proto3" json:"lnID,omitempty"`
+	FundingAmount    int64  `protobuf:"varint,3,opt,name=fundingAmount" json:"fundingAmount,omitempty"`
+	Capacity         int64  `protobuf:"varint,4,opt,name=capacity" json:"capacity,omitempty"`
+	NumInputs        uint32 `protobuf:"varint,5,opt,name=numInputs" json:"numInputs,omitempty"`
+	HaveContribution bool   `protobuf:"varint,6,opt,name=haveContribution" json:"haveContribution,omitempty"`
+	CreationTime     int64  `protobuf:"varint,7,opt,name=creationTime" json:"creationTime,omitempty"`
+	Expiry           int64  `protobuf:"varint,8,opt,name=expiry" json:"expiry,omitempty"`
+}
+
+func (m *PendingReservation) Reset()                    { *m = PendingReservation{} }
+func (m *PendingReservation) String() string            { return proto.CompactTextString(m) }
+func (*PendingReservation) ProtoMessage()               {}
+func (*PendingReservation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+type ListPendingReservationsResponse struct {
+	Reservations []*PendingReservation `protobuf:"bytes,1,rep,name=reservations" json:"reservations,omitempty"`
+}
+
+func (m *ListPendingReservationsResponse) Reset()         { *m = ListPendingReservationsResponse{} }
+func (m *ListPendingReservationsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListPendingReservationsResponse) ProtoMessage()    {}
+func (*ListPendingReservationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{97}
+}
+
+func (m *ListPendingReservationsResponse) GetReservations() []*PendingReservation {
+	if m != nil {
+		return m.Reservations
+	}
+	return nil
+}
+
+type GetBestBlockRequest struct {
+}
+
+func (m *GetBestBlockRequest) Reset()                    { *m = GetBestBlockRequest{} }
+func (m *GetBestBlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetBestBlockRequest) ProtoMessage()               {}
+func (*GetBestBlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+type GetBestBlockResponse struct {
+	BlockHash   string `protobuf:"bytes,1,opt,name=blockHash" json:"blockHash,omitempty"`
+	BlockHeight int32  `protobuf:"varint,2,opt,name=blockHeight" json:"blockHeight,omitempty"`
+}
+
+func (m *GetBestBlockResponse) Reset()                    { *m = GetBestBlockResponse{} }
+func (m *GetBestBlockResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetBestBlockResponse) ProtoMessage()               {}
+func (*GetBestBlockResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+type BlockEpochRequest struct {
+}
+
+func (m *BlockEpochRequest) Reset()                    { *m = BlockEpochRequest{} }
+func (m *BlockEpochRequest) String() string            { return proto.CompactTextString(m) }
+func (*BlockEpochRequest) ProtoMessage()               {}
+func (*BlockEpochRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
+type BlockEpoch struct {
+	Hash   string `protobuf:"bytes,1,opt,name=hash" json:"hash,omitempty"`
+	Height int32  `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *BlockEpoch) Reset()                    { *m = BlockEpoch{} }
+func (m *BlockEpoch) String() string            { return proto.CompactTextString(m) }
+func (*BlockEpoch) ProtoMessage()               {}
+func (*BlockEpoch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{101} }
+
+type ConfRequest struct {
+	Txid     string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Script   []byte `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`
+	NumConfs uint32 `protobuf:"varint,3,opt,name=numConfs" json:"numConfs,omitempty"`
+}
+
+func (m *ConfRequest) Reset()                    { *m = ConfRequest{} }
+func (m *ConfRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConfRequest) ProtoMessage()               {}
+func (*ConfRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{102} }
+
+type ConfEvent struct {
+	Txid        string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	NumConfs    uint32 `protobuf:"varint,2,opt,name=numConfs" json:"numConfs,omitempty"`
+	BlockHeight int32  `protobuf:"varint,3,opt,name=blockHeight" json:"blockHeight,omitempty"`
+}
+
+func (m *ConfEvent) Reset()                    { *m = ConfEvent{} }
+func (m *ConfEvent) String() string            { return proto.CompactTextString(m) }
+func (*ConfEvent) ProtoMessage()               {}
+func (*ConfEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{103} }
+
+type SpendRequest struct {
+	Txid        string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	OutputIndex uint32 `protobuf:"varint,2,opt,name=outputIndex" json:"outputIndex,omitempty"`
+	Mempool     bool   `protobuf:"varint,3,opt,name=mempool" json:"mempool,omitempty"`
+}
+
+func (m *SpendRequest) Reset()                    { *m = SpendRequest{} }
+func (m *SpendRequest) String() string            { return proto.CompactTextString(m) }
+func (*SpendRequest) ProtoMessage()               {}
+func (*SpendRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{104} }
+
+type SpendEvent struct {
+	SpendingTxid       string `protobuf:"bytes,1,opt,name=spendingTxid" json:"spendingTxid,omitempty"`
+	RawSpendingTx      []byte `protobuf:"bytes,2,opt,name=rawSpendingTx,proto3" json:"rawSpendingTx,omitempty"`
+	SpendingInputIndex uint32 `protobuf:"varint,3,opt,name=spendingInputIndex" json:"spendingInputIndex,omitempty"`
+	SpendingHeight     uint32 `protobuf:"varint,4,opt,name=spendingHeight" json:"spendingHeight,omitempty"`
+}
+
+func (m *SpendEvent) Reset()                    { *m = SpendEvent{} }
+func (m *SpendEvent) String() string            { return proto.CompactTextString(m) }
+func (*SpendEvent) ProtoMessage()               {}
+func (*SpendEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{105} }
+
+type LightningNode struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+}
+
+func (m *LightningNode) Reset()                    { *m = LightningNode{} }
+func (m *LightningNode) String() string            { return proto.CompactTextString(m) }
+func (*LightningNode) ProtoMessage()               {}
+func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{106} }
+
+type RoutingPolicy struct {
+	TimeLockDelta    uint32 `protobuf:"varint,1,opt,name=timeLockDelta" json:"timeLockDelta,omitempty"`
+	MinHtlcMsat      uint64 `protobuf:"varint,2,opt,name=minHtlcMsat" json:"minHtlcMsat,omitempty"`
+	FeeBaseMsat      uint32 `protobuf:"varint,3,opt,name=feeBaseMsat" json:"feeBaseMsat,omitempty"`
+	FeeRateMilliMsat uint32 `protobuf:"varint,4,opt,name=feeRateMilliMsat" json:"feeRateMilliMsat,omitempty"`
+	Disabled         bool   `protobuf:"varint,5,opt,name=disabled" json:"disabled,omitempty"`
+	LastUpdate       int64  `protobuf:"varint,6,opt,name=lastUpdate" json:"lastUpdate,omitempty"`
+}
+
+func (m *RoutingPolicy) Reset()                    { *m = RoutingPolicy{} }
+func (m *RoutingPolicy) String() string            { return proto.CompactTextString(m) }
+func (*RoutingPolicy) ProtoMessage()               {}
+func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{107} }
+
+type ChannelEdge struct {
+	ChannelId   uint64         `protobuf:"varint,1,opt,name=channelId" json:"channelId,omitempty"`
+	ChanPoint   string         `protobuf:"bytes,2,opt,name=chanPoint" json:"chanPoint,omitempty"`
+	Node1Pub    string         `protobuf:"bytes,3,opt,name=node1Pub" json:"node1Pub,omitempty"`
+	Node2Pub    string         `protobuf:"bytes,4,opt,name=node2Pub" json:"node2Pub,omitempty"`
+	Capacity    int64          `protobuf:"varint,5,opt,name=capacity" json:"capacity,omitempty"`
+	Node1Policy *RoutingPolicy `protobuf:"bytes,6,opt,name=node1Policy" json:"node1Policy,omitempty"`
+	Node2Policy *RoutingPolicy `protobuf:"bytes,7,opt,name=node2Policy" json:"node2Policy,omitempty"`
+	Private     bool           `protobuf:"varint,8,opt,name=private" json:"private,omitempty"`
+}
+
+func (m *ChannelEdge) Reset()                    { *m = ChannelEdge{} }
+func (m *ChannelEdge) String() string            { return proto.CompactTextString(m) }
+func (*ChannelEdge) ProtoMessage()               {}
+func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{108} }
+
+func (m *ChannelEdge) GetNode1Policy() *RoutingPolicy {
+	if m != nil {
+		return m.Node1Policy
+	}
+	return nil
+}
+
+func (m *ChannelEdge) GetNode2Policy() *RoutingPolicy {
+	if m != nil {
+		return m.Node2Policy
+	}
+	return nil
+}
+
+type ChannelGraphRequest struct {
+	IncludeUnannounced bool `protobuf:"varint,1,opt,name=includeUnannounced" json:"includeUnannounced,omitempty"`
+}
+
+func (m *ChannelGraphRequest) Reset()                    { *m = ChannelGraphRequest{} }
+func (m *ChannelGraphRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelGraphRequest) ProtoMessage()               {}
+func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{109} }
+
+type ChannelGraph struct {
+	Nodes []*LightningNode `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
+	Edges []*ChannelEdge   `protobuf:"bytes,2,rep,name=edges" json:"edges,omitempty"`
+}
+
+func (m *ChannelGraph) Reset()                    { *m = ChannelGraph{} }
+func (m *ChannelGraph) String() string            { return proto.CompactTextString(m) }
+func (*ChannelGraph) ProtoMessage()               {}
+func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{110} }
+
+func (m *ChannelGraph) GetNodes() []*LightningNode {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *ChannelGraph) GetEdges() []*ChannelEdge {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
+}
+
+type ChanInfoRequest struct {
+	ChanId    uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	ChanPoint string `protobuf:"bytes,2,opt,name=chanPoint" json:"chanPoint,omitempty"`
+}
+
+func (m *ChanInfoRequest) Reset()                    { *m = ChanInfoRequest{} }
+func (m *ChanInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChanInfoRequest) ProtoMessage()               {}
+func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{111} }
+
+type NodeInfoRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pubKey" json:"pubKey,omitempty"`
+}
+
+func (m *NodeInfoRequest) Reset()                    { *m = NodeInfoRequest{} }
+func (m *NodeInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*NodeInfoRequest) ProtoMessage()               {}
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{112} }
+
+type NodeInfo struct {
+	Node          *LightningNode `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
+	NumChannels   uint32         `protobuf:"varint,2,opt,name=numChannels" json:"numChannels,omitempty"`
+	TotalCapacity int64          `protobuf:"varint,3,opt,name=totalCapacity" json:"totalCapacity,omitempty"`
+	Channels      []*ChannelEdge `protobuf:"bytes,4,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *NodeInfo) Reset()                    { *m = NodeInfo{} }
+func (m *NodeInfo) String() string            { return proto.CompactTextString(m) }
+func (*NodeInfo) ProtoMessage()               {}
+func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{113} }
+
+func (m *NodeInfo) GetNode() *LightningNode {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *NodeInfo) GetChannels() []*ChannelEdge {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type GraphTopologySubscription struct {
+}
+
+func (m *GraphTopologySubscription) Reset()                    { *m = GraphTopologySubscription{} }
+func (m *GraphTopologySubscription) String() string            { return proto.CompactTextString(m) }
+func (*GraphTopologySubscription) ProtoMessage()               {}
+func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{114} }
+
+type ChannelEdgeUpdate struct {
+	ChanId          uint64         `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	AdvertisingNode string         `protobuf:"bytes,2,opt,name=advertisingNode" json:"advertisingNode,omitempty"`
+	ConnectingNode  string         `protobuf:"bytes,3,opt,name=connectingNode" json:"connectingNode,omitempty"`
+	RoutingPolicy   *RoutingPolicy `protobuf:"bytes,4,opt,name=routingPolicy" json:"routingPolicy,omitempty"`
+}
+
+func (m *ChannelEdgeUpdate) Reset()                    { *m = ChannelEdgeUpdate{} }
+func (m *ChannelEdgeUpdate) String() string            { return proto.CompactTextString(m) }
+func (*ChannelEdgeUpdate) ProtoMessage()               {}
+func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{115} }
+
+func (m *ChannelEdgeUpdate) GetRoutingPolicy() *RoutingPolicy {
+	if m != nil {
+		return m.RoutingPolicy
+	}
+	return nil
+}
+
+type ClosedChannelUpdate struct {
+	ChanId       uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	ClosedHeight uint32 `protobuf:"varint,2,opt,name=closedHeight" json:"closedHeight,omitempty"`
+}
+
+func (m *ClosedChannelUpdate) Reset()                    { *m = ClosedChannelUpdate{} }
+func (m *ClosedChannelUpdate) String() string            { return proto.CompactTextString(m) }
+func (*ClosedChannelUpdate) ProtoMessage()               {}
+func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{116} }
+
+type GraphTopologyUpdate struct {
+	NewChannels    []*ChannelEdge         `protobuf:"bytes,1,rep,name=newChannels" json:"newChannels,omitempty"`
+	ChannelUpdates []*ChannelEdgeUpdate   `protobuf:"bytes,2,rep,name=channelUpdates" json:"channelUpdates,omitempty"`
+	ClosedChannels []*ClosedChannelUpdate `protobuf:"bytes,3,rep,name=closedChannels" json:"closedChannels,omitempty"`
+}
+
+func (m *GraphTopologyUpdate) Reset()                    { *m = GraphTopologyUpdate{} }
+func (m *GraphTopologyUpdate) String() string            { return proto.CompactTextString(m) }
+func (*GraphTopologyUpdate) ProtoMessage()               {}
+func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{117} }
+
+func (m *GraphTopologyUpdate) GetNewChannels() []*ChannelEdge {
+	if m != nil {
+		return m.NewChannels
+	}
+	return nil
+}
+
+func (m *GraphTopologyUpdate) GetChannelUpdates() []*ChannelEdgeUpdate {
+	if m != nil {
+		return m.ChannelUpdates
+	}
+	return nil
+}
+
+func (m *GraphTopologyUpdate) GetClosedChannels() []*ClosedChannelUpdate {
+	if m != nil {
+		return m.ClosedChannels
+	}
+	return nil
+}
+
+type NetworkInfoRequest struct {
+}
+
+func (m *NetworkInfoRequest) Reset()                    { *m = NetworkInfoRequest{} }
+func (m *NetworkInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*NetworkInfoRequest) ProtoMessage()               {}
+func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{118} }
+
+type NetworkInfo struct {
+	NumNodes             uint32  `protobuf:"varint,1,opt,name=numNodes" json:"numNodes,omitempty"`
+	NumChannels          uint32  `protobuf:"varint,2,opt,name=numChannels" json:"numChannels,omitempty"`
+	TotalNetworkCapacity int64   `protobuf:"varint,3,opt,name=totalNetworkCapacity" json:"totalNetworkCapacity,omitempty"`
+	AvgChannelSize       float64 `protobuf:"fixed64,4,opt,name=avgChannelSize" json:"avgChannelSize,omitempty"`
+	MedianChannelSize    int64   `protobuf:"varint,5,opt,name=medianChannelSize" json:"medianChannelSize,omitempty"`
+	MaxOutDegree         uint32  `protobuf:"varint,6,opt,name=maxOutDegree" json:"maxOutDegree,omitempty"`
+	NumZombieChans       uint32  `protobuf:"varint,7,opt,name=numZombieChans" json:"numZombieChans,omitempty"`
+}
+
+func (m *NetworkInfo) Reset()                    { *m = NetworkInfo{} }
+func (m *NetworkInfo) String() string            { return proto.CompactTextString(m) }
+func (*NetworkInfo) ProtoMessage()               {}
+func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{119} }
+
+type UpdateChanStatusRequest struct {
+	ChanId uint64           `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	Action ChanStatusAction `protobuf:"varint,2,opt,name=action,enum=lnrpc.ChanStatusAction" json:"action,omitempty"`
+}
+
+func (m *UpdateChanStatusRequest) Reset()                    { *m = UpdateChanStatusRequest{} }
+func (m *UpdateChanStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateChanStatusRequest) ProtoMessage()               {}
+func (*UpdateChanStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{120} }
+
+type UpdateChanStatusResponse struct {
+}
+
+func (m *UpdateChanStatusResponse) Reset()                    { *m = UpdateChanStatusResponse{} }
+func (m *UpdateChanStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateChanStatusResponse) ProtoMessage()               {}
+func (*UpdateChanStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{121} }
+
+type ChannelInsightsRequest struct {
+	ChanId uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+}
+
+func (m *ChannelInsightsRequest) Reset()                    { *m = ChannelInsightsRequest{} }
+func (m *ChannelInsightsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelInsightsRequest) ProtoMessage()               {}
+func (*ChannelInsightsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{122} }
+
+type ChannelInsight struct {
+	ChanId       uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	RemotePubkey string `protobuf:"bytes,2,opt,name=remotePubkey" json:"remotePubkey,omitempty"`
+	Lifetime     int64  `protobuf:"varint,3,opt,name=lifetime" json:"lifetime,omitempty"`
+	Uptime       int64  `protobuf:"varint,4,opt,name=uptime" json:"uptime,omitempty"`
+	FlapCount    uint32 `protobuf:"varint,5,opt,name=flapCount" json:"flapCount,omitempty"`
+	LastFlap     int64  `protobuf:"varint,6,opt,name=lastFlap" json:"lastFlap,omitempty"`
+	Online       bool   `protobuf:"varint,7,opt,name=online" json:"online,omitempty"`
+	Note         string `protobuf:"bytes,8,opt,name=note" json:"note,omitempty"`
+	Private      bool   `protobuf:"varint,9,opt,name=private" json:"private,omitempty"`
+	ZombieReason string `protobuf:"bytes,10,opt,name=zombieReason" json:"zombieReason,omitempty"`
+}
+
+func (m *ChannelInsight) Reset()                    { *m = ChannelInsight{} }
+func (m *ChannelInsight) String() string            { return proto.CompactTextString(m) }
+func (*ChannelInsight) ProtoMessage()               {}
+func (*ChannelInsight) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{123} }
+
+type ChannelInsightsResponse struct {
+	Channels []*ChannelInsight `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *ChannelInsightsResponse) Reset()                    { *m = ChannelInsightsResponse{} }
+func (m *ChannelInsightsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelInsightsResponse) ProtoMessage()               {}
+func (*ChannelInsightsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{124} }
+
+func (m *ChannelInsightsResponse) GetChannels() []*ChannelInsight {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type SetChannelNoteRequest struct {
+	ChanId uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	Note   string `protobuf:"bytes,2,opt,name=note" json:"note,omitempty"`
+}
+
+func (m *SetChannelNoteRequest) Reset()                    { *m = SetChannelNoteRequest{} }
+func (m *SetChannelNoteRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetChannelNoteRequest) ProtoMessage()               {}
+func (*SetChannelNoteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{125} }
+
+type SetChannelNoteResponse struct {
+}
+
+func (m *SetChannelNoteResponse) Reset()                    { *m = SetChannelNoteResponse{} }
+func (m *SetChannelNoteResponse) String() string            { return proto.CompactTextString(m) }
+func (*SetChannelNoteResponse) ProtoMessage()               {}
+func (*SetChannelNoteResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{126} }
+
+type FeeReportRequest struct {
+}
+
+func (m *FeeReportRequest) Reset()                    { *m = FeeReportRequest{} }
+func (m *FeeReportRequest) String() string            { return proto.CompactTextString(m) }
+func (*FeeReportRequest) ProtoMessage()               {}
+func (*FeeReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{127} }
+
+type ChannelFeeReport struct {
+	ChanId      uint64 `protobuf:"varint,1,opt,name=chanId" json:"chanId,omitempty"`
+	ChanPoint   string `protobuf:"bytes,2,opt,name=chanPoint" json:"chanPoint,omitempty"`
+	BaseFeeMsat uint32 `protobuf:"varint,3,opt,name=baseFeeMsat" json:"baseFeeMsat,omitempty"`
+	FeePerMil   uint32 `protobuf:"varint,4,opt,name=feePerMil" json:"feePerMil,omitempty"`
+	Disabled    bool   `protobuf:"varint,5,opt,name=disabled" json:"disabled,omitempty"`
+}
+
+func (m *ChannelFeeReport) Reset()                    { *m = ChannelFeeReport{} }
+func (m *ChannelFeeReport) String() string            { return proto.CompactTextString(m) }
+func (*ChannelFeeReport) ProtoMessage()               {}
+func (*ChannelFeeReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{128} }
+
+type FeeReportResponse struct {
+	ChannelFees     []*ChannelFeeReport `protobuf:"bytes,1,rep,name=channelFees" json:"channelFees,omitempty"`
+	DayFeeSumMsat   uint64              `protobuf:"varint,2,opt,name=dayFeeSumMsat" json:"dayFeeSumMsat,omitempty"`
+	WeekFeeSumMsat  uint64              `protobuf:"varint,3,opt,name=weekFeeSumMsat" json:"weekFeeSumMsat,omitempty"`
+	MonthFeeSumMsat uint64              `protobuf:"varint,4,opt,name=monthFeeSumMsat" json:"monthFeeSumMsat,omitempty"`
+}
+
+func (m *FeeReportResponse) Reset()                    { *m = FeeReportResponse{} }
+func (m *FeeReportResponse) String() string            { return proto.CompactTextString(m) }
+func (*FeeReportResponse) ProtoMessage()               {}
+func (*FeeReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{129} }
+
+func (m *FeeReportResponse) GetChannelFees() []*ChannelFeeReport {
+	if m != nil {
+		return m.ChannelFees
+	}
+	return nil
+}
+
+type RPCMiddlewareRequest struct {
+	RequestID  uint64 `protobuf:"varint,1,opt,name=requestID" json:"requestID,omitempty"`
+	FullMethod string `protobuf:"bytes,2,opt,name=fullMethod" json:"fullMethod,omitempty"`
+	StreamRPC  bool   `protobuf:"varint,3,opt,name=streamRPC" json:"streamRPC,omitempty"`
+	IsResponse bool   `protobuf:"varint,4,opt,name=isResponse" json:"isResponse,omitempty"`
+	TypeName   string `protobuf:"bytes,5,opt,name=typeName" json:"typeName,omitempty"`
+	Serialized []byte `protobuf:"bytes,6,opt,name=serialized,proto3" json:"serialized,omitempty"`
+}
+
+func (m *RPCMiddlewareRequest) Reset()                    { *m = RPCMiddlewareRequest{} }
+func (m *RPCMiddlewareRequest) String() string            { return proto.CompactTextString(m) }
+func (*RPCMiddlewareRequest) ProtoMessage()               {}
+func (*RPCMiddlewareRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{130} }
+
+type RPCMiddlewareResponse struct {
+	MiddlewareName string `protobuf:"bytes,1,opt,name=middlewareName" json:"middlewareName,omitempty"`
+	ReadOnly       bool   `protobuf:"varint,2,opt,name=readOnly" json:"readOnly,omitempty"`
+	RequestID      uint64 `protobuf:"varint,3,opt,name=requestID" json:"requestID,omitempty"`
+	Reject         bool   `protobuf:"varint,4,opt,name=reject" json:"reject,omitempty"`
+	Error          string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	Replacement    []byte `protobuf:"bytes,6,opt,name=replacement,proto3" json:"replacement,omitempty"`
+}
+
+func (m *RPCMiddlewareResponse) Reset()                    { *m = RPCMiddlewareResponse{} }
+func (m *RPCMiddlewareResponse) String() string            { return proto.CompactTextString(m) }
+func (*RPCMiddlewareResponse) ProtoMessage()               {}
+func (*RPCMiddlewareResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{131} }
+
+type ErrorDetail struct {
+	Code            ErrorCode            `protobuf:"varint,1,opt,name=code,enum=lnrpc.ErrorCode" json:"code,omitempty"`
+	PaymentFailure  PaymentFailureReason `protobuf:"varint,2,opt,name=paymentFailure,enum=lnrpc.PaymentFailureReason" json:"paymentFailure,omitempty"`
+	ChannelConflict ChannelConflict      `protobuf:"varint,3,opt,name=channelConflict,enum=lnrpc.ChannelConflict" json:"channelConflict,omitempty"`
+}
+
+func (m *ErrorDetail) Reset()                    { *m = ErrorDetail{} }
+func (m *ErrorDetail) String() string            { return proto.CompactTextString(m) }
+func (*ErrorDetail) ProtoMessage()               {}
+func (*ErrorDetail) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{132} }
+
+func init() {
+	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
+	proto.RegisterType((*EstimateFeeRequest)(nil), "lnrpc.EstimateFeeRequest")
+	proto.RegisterType((*EstimateFeeResponse)(nil), "lnrpc.EstimateFeeResponse")
+	proto.RegisterType((*PublishTransactionRequest)(nil), "lnrpc.PublishTransactionRequest")
+	proto.RegisterType((*PublishTransactionResponse)(nil), "lnrpc.PublishTransactionResponse")
+	proto.RegisterType((*GetTransactionRequest)(nil), "lnrpc.GetTransactionRequest")
+	proto.RegisterType((*TransactionOutput)(nil), "lnrpc.TransactionOutput")
+	proto.RegisterType((*GetTransactionResponse)(nil), "lnrpc.GetTransactionResponse")
+	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
+	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
+	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
+	proto.RegisterType((*GetRecoveryInfoRequest)(nil), "lnrpc.GetRecoveryInfoRequest")
+	proto.RegisterType((*GetRecoveryInfoResponse)(nil), "lnrpc.GetRecoveryInfoResponse")
+	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
+	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
+	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
+	proto.RegisterType((*ChannelTypeBalance)(nil), "lnrpc.ChannelTypeBalance")
+	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
+	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
+	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
+	proto.RegisterType((*AddressReachability)(nil), "lnrpc.AddressReachability")
+	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
+	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
+	proto.RegisterType((*DisconnectPeerRequest)(nil), "lnrpc.DisconnectPeerRequest")
+	proto.RegisterType((*DisconnectPeerResponse)(nil), "lnrpc.DisconnectPeerResponse")
+	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
+	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
+	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
+	proto.RegisterType((*SetPeerLabelRequest)(nil), "lnrpc.SetPeerLabelRequest")
+	proto.RegisterType((*SetPeerLabelResponse)(nil), "lnrpc.SetPeerLabelResponse")
+	proto.RegisterType((*ListPeerBackupsRequest)(nil), "lnrpc.ListPeerBackupsRequest")
+	proto.RegisterType((*ChannelBackup)(nil), "lnrpc.ChannelBackup")
+	proto.RegisterType((*PeerBackup)(nil), "lnrpc.PeerBackup")
+	proto.RegisterType((*ListPeerBackupsResponse)(nil), "lnrpc.ListPeerBackupsResponse")
+	proto.RegisterType((*SendSignedDataRequest)(nil), "lnrpc.SendSignedDataRequest")
+	proto.RegisterType((*SendSignedDataResponse)(nil), "lnrpc.SendSignedDataResponse")
+	proto.RegisterType((*SubscribeSignedDataRequest)(nil), "lnrpc.SubscribeSignedDataRequest")
+	proto.RegisterType((*SignedPeerData)(nil), "lnrpc.SignedPeerData")
+	proto.RegisterType((*SignPeerDataRequest)(nil), "lnrpc.SignPeerDataRequest")
+	proto.RegisterType((*SignPeerDataResponse)(nil), "lnrpc.SignPeerDataResponse")
+	proto.RegisterType((*VerifyPeerDataRequest)(nil), "lnrpc.VerifyPeerDataRequest")
+	proto.RegisterType((*VerifyPeerDataResponse)(nil), "lnrpc.VerifyPeerDataResponse")
+	proto.RegisterType((*ListPeerMisbehaviorRequest)(nil), "lnrpc.ListPeerMisbehaviorRequest")
+	proto.RegisterType((*Misbehavior)(nil), "lnrpc.Misbehavior")
+	proto.RegisterType((*PeerMisbehavior)(nil), "lnrpc.PeerMisbehavior")
+	proto.RegisterType((*ListPeerMisbehaviorResponse)(nil), "lnrpc.ListPeerMisbehaviorResponse")
+	proto.RegisterType((*PaymentAttempt)(nil), "lnrpc.PaymentAttempt")
+	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
+	proto.RegisterType((*FeeLimit)(nil), "lnrpc.FeeLimit")
+	proto.RegisterType((*SendPaymentRequest)(nil), "lnrpc.SendPaymentRequest")
+	proto.RegisterType((*SendPaymentResponse)(nil), "lnrpc.SendPaymentResponse")
+	proto.RegisterType((*QueryRoutesRequest)(nil), "lnrpc.QueryRoutesRequest")
+	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
+	proto.RegisterType((*Route)(nil), "lnrpc.Route")
+	proto.RegisterType((*QueryRoutesResponse)(nil), "lnrpc.QueryRoutesResponse")
+	proto.RegisterType((*EstimateRouteFeeRequest)(nil), "lnrpc.EstimateRouteFeeRequest")
+	proto.RegisterType((*EstimateRouteFeeResponse)(nil), "lnrpc.EstimateRouteFeeResponse")
+	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
+	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
+	proto.RegisterType((*DeletePaymentRequest)(nil), "lnrpc.DeletePaymentRequest")
+	proto.RegisterType((*DeletePaymentResponse)(nil), "lnrpc.DeletePaymentResponse")
+	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
+	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
+	proto.RegisterType((*Htlc)(nil), "lnrpc.Htlc")
+	proto.RegisterType((*ListHtlcsRequest)(nil), "lnrpc.ListHtlcsRequest")
+	proto.RegisterType((*ListHtlcsResponse)(nil), "lnrpc.ListHtlcsResponse")
+	proto.RegisterType((*LookupHtlcResolutionRequest)(nil), "lnrpc.LookupHtlcResolutionRequest")
+	proto.RegisterType((*LookupHtlcResolutionResponse)(nil), "lnrpc.LookupHtlcResolutionResponse")
+	proto.RegisterType((*ExportAccountingRequest)(nil), "lnrpc.ExportAccountingRequest")
+	proto.RegisterType((*ExportAccountingResponse)(nil), "lnrpc.ExportAccountingResponse")
+	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
+	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
+	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
+	proto.RegisterType((*ImportAccountRequest)(nil), "lnrpc.ImportAccountRequest")
+	proto.RegisterType((*ImportAccountResponse)(nil), "lnrpc.ImportAccountResponse")
+	proto.RegisterType((*ImportPublicKeyRequest)(nil), "lnrpc.ImportPublicKeyRequest")
+	proto.RegisterType((*ImportPublicKeyResponse)(nil), "lnrpc.ImportPublicKeyResponse")
+	proto.RegisterType((*FundPsbtRequest)(nil), "lnrpc.FundPsbtRequest")
+	proto.RegisterType((*FundPsbtResponse)(nil), "lnrpc.FundPsbtResponse")
+	proto.RegisterType((*SignPsbtRequest)(nil), "lnrpc.SignPsbtRequest")
+	proto.RegisterType((*SignPsbtResponse)(nil), "lnrpc.SignPsbtResponse")
+	proto.RegisterType((*FinalizePsbtRequest)(nil), "lnrpc.FinalizePsbtRequest")
+	proto.RegisterType((*FinalizePsbtResponse)(nil), "lnrpc.FinalizePsbtResponse")
+	proto.RegisterType((*BumpFeeRequest)(nil), "lnrpc.BumpFeeRequest")
+	proto.RegisterType((*BumpFeeResponse)(nil), "lnrpc.BumpFeeResponse")
+	proto.RegisterType((*PendingSweepsRequest)(nil), "lnrpc.PendingSweepsRequest")
+	proto.RegisterType((*PendingSweep)(nil), "lnrpc.PendingSweep")
+	proto.RegisterType((*PendingSweepsResponse)(nil), "lnrpc.PendingSweepsResponse")
+	proto.RegisterType((*ListSweepsRequest)(nil), "lnrpc.ListSweepsRequest")
+	proto.RegisterType((*SweepTransaction)(nil), "lnrpc.SweepTransaction")
+	proto.RegisterType((*ListSweepsResponse)(nil), "lnrpc.ListSweepsResponse")
+	proto.RegisterType((*AbandonChannelRequest)(nil), "lnrpc.AbandonChannelRequest")
+	proto.RegisterType((*AbandonChannelResponse)(nil), "lnrpc.AbandonChannelResponse")
+	proto.RegisterType((*UpdateChannelParamsRequest)(nil), "lnrpc.UpdateChannelParamsRequest")
+	proto.RegisterType((*UpdateChannelParamsResponse)(nil), "lnrpc.UpdateChannelParamsResponse")
+	proto.RegisterType((*ListPendingReservationsRequest)(nil), "lnrpc.ListPendingReservationsRequest")
+	proto.RegisterType((*PendingReservation)(nil), "lnrpc.PendingReservation")
+	proto.RegisterType((*ListPendingReservationsResponse)(nil), "lnrpc.ListPendingReservationsResponse")
+	proto.RegisterType((*GetBestBlockRequest)(nil), "lnrpc.GetBestBlockRequest")
+	proto.RegisterType((*GetBestBlockResponse)(nil), "lnrpc.GetBestBlockResponse")
+	proto.RegisterType((*BlockEpochRequest)(nil), "lnrpc.BlockEpochRequest")
+	proto.RegisterType((*BlockEpoch)(nil), "lnrpc.BlockEpoch")
+	proto.RegisterType((*ConfRequest)(nil), "lnrpc.ConfRequest")
+	proto.RegisterType((*ConfEvent)(nil), "lnrpc.ConfEvent")
+	proto.RegisterType((*SpendRequest)(nil), "lnrpc.SpendRequest")
+	proto.RegisterType((*SpendEvent)(nil), "lnrpc.SpendEvent")
+	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
+	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
+	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
+	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
+	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
+	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
+	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
+	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
+	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
+	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
+	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
+	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
+	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
+	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
+	proto.RegisterType((*UpdateChanStatusRequest)(nil), "lnrpc.UpdateChanStatusRequest")
+	proto.RegisterType((*UpdateChanStatusResponse)(nil), "lnrpc.UpdateChanStatusResponse")
+	proto.RegisterType((*ChannelInsightsRequest)(nil), "lnrpc.ChannelInsightsRequest")
+	proto.RegisterType((*ChannelInsight)(nil), "lnrpc.ChannelInsight")
+	proto.RegisterType((*ChannelInsightsResponse)(nil), "lnrpc.ChannelInsightsResponse")
+	proto.RegisterType((*SetChannelNoteRequest)(nil), "lnrpc.SetChannelNoteRequest")
+	proto.RegisterType((*SetChannelNoteResponse)(nil), "lnrpc.SetChannelNoteResponse")
+	proto.RegisterType((*FeeReportRequest)(nil), "lnrpc.FeeReportRequest")
+	proto.RegisterType((*ChannelFeeReport)(nil), "lnrpc.ChannelFeeReport")
+	proto.RegisterType((*FeeReportResponse)(nil), "lnrpc.FeeReportResponse")
+	proto.RegisterType((*RPCMiddlewareRequest)(nil), "lnrpc.RPCMiddlewareRequest")
+	proto.RegisterType((*RPCMiddlewareResponse)(nil), "lnrpc.RPCMiddlewareResponse")
+	proto.RegisterType((*ErrorDetail)(nil), "lnrpc.ErrorDetail")
+	proto.RegisterEnum("lnrpc.PaymentStatus", PaymentStatus_name, PaymentStatus_value)
+	proto.RegisterEnum("lnrpc.AccountingFormat", AccountingFormat_name, AccountingFormat_value)
+	proto.RegisterEnum("lnrpc.AccountingEntryType", AccountingEntryType_name, AccountingEntryType_value)
+	proto.RegisterEnum("lnrpc.InvoiceState", InvoiceState_name, InvoiceState_value)
+	proto.RegisterEnum("lnrpc.ChanStatusAction", ChanStatusAction_name, ChanStatusAction_value)
+	proto.RegisterEnum("lnrpc.ErrorCode", ErrorCode_name, ErrorCode_value)
+	proto.RegisterEnum("lnrpc.PaymentFailureReason", PaymentFailureReason_name, PaymentFailureReason_value)
+	proto.RegisterEnum("lnrpc.ChannelConflict", ChannelConflict_name, ChannelConflict_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// Client API for Lightning service
+
+type LightningClient interface {
+	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error)
+	PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error)
+	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	GetRecoveryInfo(ctx context.Context, in *GetRecoveryInfoRequest, opts ...grpc.CallOption) (*GetRecoveryInfoResponse, error)
+	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	SetPeerLabel(ctx context.Context, in *SetPeerLabelRequest, opts ...grpc.CallOption) (*SetPeerLabelResponse, error)
+	ListPeerBackups(ctx context.Context, in *ListPeerBackupsRequest, opts ...grpc.CallOption) (*ListPeerBackupsResponse, error)
+	SendSignedData(ctx context.Context, in *SendSignedDataRequest, opts ...grpc.CallOption) (*SendSignedDataResponse, error)
+	SubscribeSignedData(ctx context.Context, in *SubscribeSignedDataRequest, opts ...grpc.CallOption) (Lightning_SubscribeSignedDataClient, error)
+	SignPeerData(ctx context.Context, in *SignPeerDataRequest, opts ...grpc.CallOption) (*SignPeerDataResponse, error)
+	VerifyPeerData(ctx context.Context, in *VerifyPeerDataRequest, opts ...grpc.CallOption) (*VerifyPeerDataResponse, error)
+	ListPeerMisbehavior(ctx context.Context, in *ListPeerMisbehaviorRequest, opts ...grpc.CallOption) (*ListPeerMisbehaviorResponse, error)
+	SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*SendPaymentResponse, error)
+	QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error)
+	EstimateRouteFee(ctx context.Context, in *EstimateRouteFeeRequest, opts ...grpc.CallOption) (*EstimateRouteFeeResponse, error)
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	DeletePayment(ctx context.Context, in *DeletePaymentRequest, opts ...grpc.CallOption) (*DeletePaymentResponse, error)
+	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	ListHtlcs(ctx context.Context, in *ListHtlcsRequest, opts ...grpc.CallOption) (*ListHtlcsResponse, error)
+	LookupHtlcResolution(ctx context.Context, in *LookupHtlcResolutionRequest, opts ...grpc.CallOption) (*LookupHtlcResolutionResponse, error)
+	ExportAccounting(ctx context.Context, in *ExportAccountingRequest, opts ...grpc.CallOption) (*ExportAccountingResponse, error)
+	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error)
+	ImportPublicKey(ctx context.Context, in *ImportPublicKeyRequest, opts ...grpc.CallOption) (*ImportPublicKeyResponse, error)
+	FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error)
+	SignPsbt(ctx context.Context, in *SignPsbtRequest, opts ...grpc.CallOption) (*SignPsbtResponse, error)
+	FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error)
+	BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error)
+	PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error)
+	ListSweeps(ctx context.Context, in *ListSweepsRequest, opts ...grpc.CallOption) (*ListSweepsResponse, error)
+	AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error)
+	UpdateChannelParams(ctx context.Context, in *UpdateChannelParamsRequest, opts ...grpc.CallOption) (*UpdateChannelParamsResponse, error)
+	ListPendingReservations(ctx context.Context, in *ListPendingReservationsRequest, opts ...grpc.CallOption) (*ListPendingReservationsResponse, error)
+	GetBestBlock(ctx context.Context, in *GetBestBlockRequest, opts ...grpc.CallOption) (*GetBestBlockResponse, error)
+	SubscribeBlockEpochs(ctx context.Context, in *BlockEpochRequest, opts ...grpc.CallOption) (Lightning_SubscribeBlockEpochsClient, error)
+	RegisterConfirmationsNtfn(ctx context.Context, in *ConfRequest, opts ...grpc.CallOption) (Lightning_RegisterConfirmationsNtfnClient, error)
+	RegisterSpendNtfn(ctx context.Context, in *SpendRequest, opts ...grpc.CallOption) (Lightning_RegisterSpendNtfnClient, error)
+	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	ChannelInsights(ctx context.Context, in *ChannelInsightsRequest, opts ...grpc.CallOption) (*ChannelInsightsResponse, error)
+	SetChannelNote(ctx context.Context, in *SetChannelNoteRequest, opts ...grpc.CallOption) (*SetChannelNoteResponse, error)
+	FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error)
+	RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error)
+}
+
+type lightningClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLightningClient(cc *grpc.ClientConn) LightningClient {
+	return &lightningClient{cc}
+}
+
+func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
+	out := new(SendManyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) EstimateFee(ctx context.Context, in *EstimateFeeRequest, opts ...grpc.CallOption) (*EstimateFeeResponse, error) {
+	out := new(EstimateFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/EstimateFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PublishTransaction(ctx context.Context, in *PublishTransactionRequest, opts ...grpc.CallOption) (*PublishTransactionResponse, error) {
+	out := new(PublishTransactionResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PublishTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*GetTransactionResponse, error) {
+	out := new(GetTransactionResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetRecoveryInfo(ctx context.Context, in *GetRecoveryInfoRequest, opts ...grpc.CallOption) (*GetRecoveryInfoResponse, error) {
+	out := new(GetRecoveryInfoResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetRecoveryInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
+	out := new(WalletBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
+	out := new(ChannelBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	out := new(GetInfoResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
+	out := new(ConnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
+	out := new(DisconnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DisconnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
+	out := new(ListPeersResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SetPeerLabel(ctx context.Context, in *SetPeerLabelRequest, opts ...grpc.CallOption) (*SetPeerLabelResponse, error) {
+	out := new(SetPeerLabelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetPeerLabel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeerBackups(ctx context.Context, in *ListPeerBackupsRequest, opts ...grpc.CallOption) (*ListPeerBackupsResponse, error) {
+	out := new(ListPeerBackupsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeerBackups", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendSignedData(ctx context.Context, in *SendSignedDataRequest, opts ...grpc.CallOption) (*SendSignedDataResponse, error) {
+	out := new(SendSignedDataResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendSignedData", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeSignedData(ctx context.Context, in *SubscribeSignedDataRequest, opts ...grpc.CallOption) (Lightning_SubscribeSignedDataClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeSignedData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeSignedDataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeSignedDataClient interface {
+	Recv() (*SignedPeerData, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeSignedDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeSignedDataClient) Recv() (*SignedPeerData, error) {
+	m := new(SignedPeerData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SignPeerData(ctx context.Context, in *SignPeerDataRequest, opts ...grpc.CallOption) (*SignPeerDataResponse, error) {
+	out := new(SignPeerDataResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SignPeerData", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) VerifyPeerData(ctx context.Context, in *VerifyPeerDataRequest, opts ...grpc.CallOption) (*VerifyPeerDataResponse, error) {
+	out := new(VerifyPeerDataResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/VerifyPeerData", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeerMisbehavior(ctx context.Context, in *ListPeerMisbehaviorRequest, opts ...grpc.CallOption) (*ListPeerMisbehaviorResponse, error) {
+	out := new(ListPeerMisbehaviorResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeerMisbehavior", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (*SendPaymentResponse, error) {
+	out := new(SendPaymentResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) QueryRoutes(ctx context.Context, in *QueryRoutesRequest, opts ...grpc.CallOption) (*QueryRoutesResponse, error) {
+	out := new(QueryRoutesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoutes", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) EstimateRouteFee(ctx context.Context, in *EstimateRouteFeeRequest, opts ...grpc.CallOption) (*EstimateRouteFeeResponse, error) {
+	out := new(EstimateRouteFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/EstimateRouteFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeletePayment(ctx context.Context, in *DeletePaymentRequest, opts ...grpc.CallOption) (*DeletePaymentResponse, error) {
+	out := new(DeletePaymentResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeletePayment", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
+	out := new(DeleteAllPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListHtlcs(ctx context.Context, in *ListHtlcsRequest, opts ...grpc.CallOption) (*ListHtlcsResponse, error) {
+	out := new(ListHtlcsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListHtlcs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) LookupHtlcResolution(ctx context.Context, in *LookupHtlcResolutionRequest, opts ...grpc.CallOption) (*LookupHtlcResolutionResponse, error) {
+	out := new(LookupHtlcResolutionResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupHtlcResolution", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportAccounting(ctx context.Context, in *ExportAccountingRequest, opts ...grpc.CallOption) (*ExportAccountingResponse, error) {
+	out := new(ExportAccountingResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportAccounting", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+	out := new(AddInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeInvoicesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeInvoicesClient interface {
+	Recv() (*Invoice, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeInvoicesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
+	m := new(Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) ImportAccount(ctx context.Context, in *ImportAccountRequest, opts ...grpc.CallOption) (*ImportAccountResponse, error) {
+	out := new(ImportAccountResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ImportAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportPublicKey(ctx context.Context, in *ImportPublicKeyRequest, opts ...grpc.CallOption) (*ImportPublicKeyResponse, error) {
+	out := new(ImportPublicKeyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ImportPublicKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FundPsbt(ctx context.Context, in *FundPsbtRequest, opts ...grpc.CallOption) (*FundPsbtResponse, error) {
+	out := new(FundPsbtResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FundPsbt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SignPsbt(ctx context.Context, in *SignPsbtRequest, opts ...grpc.CallOption) (*SignPsbtResponse, error) {
+	out := new(SignPsbtResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SignPsbt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error) {
+	out := new(FinalizePsbtResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FinalizePsbt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BumpFee(ctx context.Context, in *BumpFeeRequest, opts ...grpc.CallOption) (*BumpFeeResponse, error) {
+	out := new(BumpFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BumpFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PendingSweeps(ctx context.Context, in *PendingSweepsRequest, opts ...grpc.CallOption) (*PendingSweepsResponse, error) {
+	out := new(PendingSweepsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingSweeps", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListSweeps(ctx context.Context, in *ListSweepsRequest, opts ...grpc.CallOption) (*ListSweepsResponse, error) {
+	out := new(ListSweepsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListSweeps", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannelRequest, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
+	out := new(AbandonChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateChannelParams(ctx context.Context, in *UpdateChannelParamsRequest, opts ...grpc.CallOption) (*UpdateChannelParamsResponse, error) {
+	out := new(UpdateChannelParamsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelParams", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPendingReservations(ctx context.Context, in *ListPendingReservationsRequest, opts ...grpc.CallOption) (*ListPendingReservationsResponse, error) {
+	out := new(ListPendingReservationsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPendingReservations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetBestBlock(ctx context.Context, in *GetBestBlockRequest, opts ...grpc.CallOption) (*GetBestBlockResponse, error) {
+	out := new(GetBestBlockResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetBestBlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeBlockEpochs(ctx context.Context, in *BlockEpochRequest, opts ...grpc.CallOption) (Lightning_SubscribeBlockEpochsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/SubscribeBlockEpochs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeBlockEpochsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeBlockEpochsClient interface {
+	Recv() (*BlockEpoch, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeBlockEpochsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeBlockEpochsClient) Recv() (*BlockEpoch, error) {
+	m := new(BlockEpoch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) RegisterConfirmationsNtfn(ctx context.Context, in *ConfRequest, opts ...grpc.CallOption) (Lightning_RegisterConfirmationsNtfnClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/RegisterConfirmationsNtfn", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningRegisterConfirmationsNtfnClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_RegisterConfirmationsNtfnClient interface {
+	Recv() (*ConfEvent, error)
+	grpc.ClientStream
+}
+
+type lightningRegisterConfirmationsNtfnClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningRegisterConfirmationsNtfnClient) Recv() (*ConfEvent, error) {
+	m := new(ConfEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) RegisterSpendNtfn(ctx context.Context, in *SpendRequest, opts ...grpc.CallOption) (Lightning_RegisterSpendNtfnClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/RegisterSpendNtfn", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningRegisterSpendNtfnClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_RegisterSpendNtfnClient interface {
+	Recv() (*SpendEvent, error)
+	grpc.ClientStream
+}
+
+type lightningRegisterSpendNtfnClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningRegisterSpendNtfnClient) Recv() (*SpendEvent, error) {
+	m := new(SpendEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
+	out := new(ChannelGraph)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
+	out := new(ChannelEdge)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelGraphClient interface {
+	Recv() (*GraphTopologyUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
+	m := new(GraphTopologyUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
+	out := new(NetworkInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error) {
+	out := new(UpdateChanStatusResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateChanStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelInsights(ctx context.Context, in *ChannelInsightsRequest, opts ...grpc.CallOption) (*ChannelInsightsResponse, error) {
+	out := new(ChannelInsightsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelInsights", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SetChannelNote(ctx context.Context, in *SetChannelNoteRequest, opts ...grpc.CallOption) (*SetChannelNoteResponse, error) {
+	out := new(SetChannelNoteResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetChannelNote", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FeeReport(ctx context.Context, in *FeeReportRequest, opts ...grpc.CallOption) (*FeeReportResponse, error) {
+	out := new(FeeReportResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FeeReport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[6], c.cc, "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningRegisterRPCMiddlewareClient{stream}
+	return x, nil
+}
+
+type Lightning_RegisterRPCMiddlewareClient interface {
+	Send(*RPCMiddlewareResponse) error
+	Recv() (*RPCMiddlewareRequest, error)
+	grpc.ClientStream
+}
+
+type lightningRegisterRPCMiddlewareClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningRegisterRPCMiddlewareClient) Send(m *RPCMiddlewareResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningRegisterRPCMiddlewareClient) Recv() (*RPCMiddlewareRequest, error) {
+	m := new(RPCMiddlewareRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Lightning service
+
+type LightningServer interface {
+	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	PublishTransaction(context.Context, *PublishTransactionRequest) (*PublishTransactionResponse, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*GetTransactionResponse, error)
+	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
+	GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error)
+	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
+	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
+	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
+	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
+	SetPeerLabel(context.Context, *SetPeerLabelRequest) (*SetPeerLabelResponse, error)
+	ListPeerBackups(context.Context, *ListPeerBackupsRequest) (*ListPeerBackupsResponse, error)
+	SendSignedData(context.Context, *SendSignedDataRequest) (*SendSignedDataResponse, error)
+	SubscribeSignedData(*SubscribeSignedDataRequest, Lightning_SubscribeSignedDataServer) error
+	SignPeerData(context.Context, *SignPeerDataRequest) (*SignPeerDataResponse, error)
+	VerifyPeerData(context.Context, *VerifyPeerDataRequest) (*VerifyPeerDataResponse, error)
+	ListPeerMisbehavior(context.Context, *ListPeerMisbehaviorRequest) (*ListPeerMisbehaviorResponse, error)
+	SendPayment(context.Context, *SendPaymentRequest) (*SendPaymentResponse, error)
+	QueryRoutes(context.Context, *QueryRoutesRequest) (*QueryRoutesResponse, error)
+	EstimateRouteFee(context.Context, *EstimateRouteFeeRequest) (*EstimateRouteFeeResponse, error)
+	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
+	DeletePayment(context.Context, *DeletePaymentRequest) (*DeletePaymentResponse, error)
+	DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error)
+	ListHtlcs(context.Context, *ListHtlcsRequest) (*ListHtlcsResponse, error)
+	LookupHtlcResolution(context.Context, *LookupHtlcResolutionRequest) (*LookupHtlcResolutionResponse, error)
+	ExportAccounting(context.Context, *ExportAccountingRequest) (*ExportAccountingResponse, error)
+	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
+	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
+	ImportAccount(context.Context, *ImportAccountRequest) (*ImportAccountResponse, error)
+	ImportPublicKey(context.Context, *ImportPublicKeyRequest) (*ImportPublicKeyResponse, error)
+	FundPsbt(context.Context, *FundPsbtRequest) (*FundPsbtResponse, error)
+	SignPsbt(context.Context, *SignPsbtRequest) (*SignPsbtResponse, error)
+	FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	BumpFee(context.Context, *BumpFeeRequest) (*BumpFeeResponse, error)
+	PendingSweeps(context.Context, *PendingSweepsRequest) (*PendingSweepsResponse, error)
+	ListSweeps(context.Context, *ListSweepsRequest) (*ListSweepsResponse, error)
+	AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error)
+	UpdateChannelParams(context.Context, *UpdateChannelParamsRequest) (*UpdateChannelParamsResponse, error)
+	ListPendingReservations(context.Context, *ListPendingReservationsRequest) (*ListPendingReservationsResponse, error)
+	GetBestBlock(context.Context, *GetBestBlockRequest) (*GetBestBlockResponse, error)
+	SubscribeBlockEpochs(*BlockEpochRequest, Lightning_SubscribeBlockEpochsServer) error
+	RegisterConfirmationsNtfn(*ConfRequest, Lightning_RegisterConfirmationsNtfnServer) error
+	RegisterSpendNtfn(*SpendRequest, Lightning_RegisterSpendNtfnServer) error
+	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
+	GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error)
+	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
+	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
+	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
+	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	ChannelInsights(context.Context, *ChannelInsightsRequest) (*ChannelInsightsResponse, error)
+	SetChannelNote(context.Context, *SetChannelNoteRequest) (*SetChannelNoteResponse, error)
+	FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error)
+	RegisterRPCMiddleware(Lightning_RegisterRPCMiddlewareServer) error
+}
+
+func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
+	s.RegisterService(&_Lightning_serviceDesc, srv)
+}
+
+func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SendManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SendMany(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(EstimateFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).EstimateFee(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_PublishTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(PublishTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).PublishTransaction(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).GetTransaction(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(NewAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).NewAddress(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(GetRecoveryInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).GetRecoveryInfo(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(WalletBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).WalletBalance(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ChannelBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ChannelBalance(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).GetInfo(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ConnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ConnectPeer(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(DisconnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).DisconnectPeer(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListPeers(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SetPeerLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SetPeerLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SetPeerLabel(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ListPeerBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListPeerBackupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListPeerBackups(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SendSignedData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SendSignedDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SendSignedData(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SubscribeSignedData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeSignedDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeSignedData(m, &lightningSubscribeSignedDataServer{stream})
+}
+
+type Lightning_SubscribeSignedDataServer interface {
+	Send(*SignedPeerData) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeSignedDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeSignedDataServer) Send(m *SignedPeerData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SignPeerData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SignPeerDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SignPeerData(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_VerifyPeerData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(VerifyPeerDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).VerifyPeerData(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ListPeerMisbehavior_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListPeerMisbehaviorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListPeerMisbehavior(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SendPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SendPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SendPayment(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(QueryRoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).QueryRoutes(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_EstimateRouteFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(EstimateRouteFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).EstimateRouteFee(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListPayments(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_DeletePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(DeletePaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).DeletePayment(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(DeleteAllPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).DeleteAllPayments(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ListHtlcs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListHtlcsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListHtlcs(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_LookupHtlcResolution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(LookupHtlcResolutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).LookupHtlcResolution(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ExportAccounting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ExportAccountingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ExportAccounting(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(Invoice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).AddInvoice(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvoiceSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
+}
+
+type Lightning_SubscribeInvoicesServer interface {
+	Send(*Invoice) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeInvoicesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_ImportAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ImportAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ImportAccount(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ImportPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ImportPublicKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ImportPublicKey(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_FundPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(FundPsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).FundPsbt(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SignPsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SignPsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SignPsbt(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(FinalizePsbtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).FinalizePsbt(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(BumpFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).BumpFee(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_PendingSweeps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(PendingSweepsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).PendingSweeps(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_ListSweeps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListSweepsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListSweeps(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type SendManyResponse struct {
-	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(AbandonChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).AbandonChannel(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *SendManyResponse) Reset()                    { *m = SendManyResponse{} }
-func (m *SendManyResponse) String() string            { return proto.CompactTextString(m) }
-func (*SendManyResponse) ProtoMessage()               {}
-func (*SendManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func _Lightning_UpdateChannelParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(UpdateChannelParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).UpdateChannelParams(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-type NewAddressRequest struct {
+func _Lightning_ListPendingReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ListPendingReservationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).ListPendingReservations(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (m *NewAddressRequest) Reset()                    { *m = NewAddressRequest{} }
-func (m *NewAddressRequest) String() string            { return proto.CompactTextString(m) }
-func (*NewAddressRequest) ProtoMessage()               {}
-func (*NewAddressRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func _Lightning_GetBestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(GetBestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).GetBestBlock(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-type NewAddressResponse struct {
-	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+func _Lightning_SubscribeBlockEpochs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlockEpochRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeBlockEpochs(m, &lightningSubscribeBlockEpochsServer{stream})
 }
 
-func (m *NewAddressResponse) Reset()                    { *m = NewAddressResponse{} }
-func (m *NewAddressResponse) String() string            { return proto.CompactTextString(m) }
-func (*NewAddressResponse) ProtoMessage()               {}
-func (*NewAddressResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+type Lightning_SubscribeBlockEpochsServer interface {
+	Send(*BlockEpoch) error
+	grpc.ServerStream
+}
 
-type ConnectPeerRequest struct {
-	IdAtHost string `protobuf:"bytes,1,opt,name=idAtHost" json:"idAtHost,omitempty"`
+type lightningSubscribeBlockEpochsServer struct {
+	grpc.ServerStream
 }
 
-func (m *ConnectPeerRequest) Reset()                    { *m = ConnectPeerRequest{} }
-func (m *ConnectPeerRequest) String() string            { return proto.CompactTextString(m) }
-func (*ConnectPeerRequest) ProtoMessage()               {}
-func (*ConnectPeerRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (x *lightningSubscribeBlockEpochsServer) Send(m *BlockEpoch) error {
+	return x.ServerStream.SendMsg(m)
+}
 
-type ConnectPeerResponse struct {
-	LnID []byte `protobuf:"bytes,1,opt,name=lnID,proto3" json:"lnID,omitempty"`
+func _Lightning_RegisterConfirmationsNtfn_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ConfRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).RegisterConfirmationsNtfn(m, &lightningRegisterConfirmationsNtfnServer{stream})
 }
 
-func (m *ConnectPeerResponse) Reset()                    { *m = ConnectPeerResponse{} }
-func (m *ConnectPeerResponse) String() string            { return proto.CompactTextString(m) }
-func (*ConnectPeerResponse) ProtoMessage()               {}
-func (*ConnectPeerResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+type Lightning_RegisterConfirmationsNtfnServer interface {
+	Send(*ConfEvent) error
+	grpc.ServerStream
+}
 
-func init() {
-	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
-	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
-	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
-	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
-	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
-	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
+type lightningRegisterConfirmationsNtfnServer struct {
+	grpc.ServerStream
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (x *lightningRegisterConfirmationsNtfnServer) Send(m *ConfEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
 
-// Client API for Lightning service
+func _Lightning_RegisterSpendNtfn_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SpendRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).RegisterSpendNtfn(m, &lightningRegisterSpendNtfnServer{stream})
+}
 
-type LightningClient interface {
-	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
-	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+type Lightning_RegisterSpendNtfnServer interface {
+	Send(*SpendEvent) error
+	grpc.ServerStream
 }
 
-type lightningClient struct {
-	cc *grpc.ClientConn
+type lightningRegisterSpendNtfnServer struct {
+	grpc.ServerStream
 }
 
-func NewLightningClient(cc *grpc.ClientConn) LightningClient {
-	return &lightningClient{cc}
+func (x *lightningRegisterSpendNtfnServer) Send(m *SpendEvent) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
-	out := new(SendManyResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
+func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ChannelGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).DescribeGraph(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
+func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ChanInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).GetChanInfo(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
-	out := new(ConnectPeerResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
+func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(NodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).GetNodeInfo(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for Lightning service
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+}
 
-type LightningServer interface {
-	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
-	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
-	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
+	grpc.ServerStream
 }
 
-func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
-	s.RegisterService(&_Lightning_serviceDesc, srv)
+type lightningSubscribeChannelGraphServer struct {
+	grpc.ServerStream
 }
 
-func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
-	in := new(SendManyRequest)
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(NetworkInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
-	out, err := srv.(LightningServer).SendMany(ctx, in)
+	out, err := srv.(LightningServer).GetNetworkInfo(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
-	in := new(NewAddressRequest)
+func _Lightning_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(UpdateChanStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
-	out, err := srv.(LightningServer).NewAddress(ctx, in)
+	out, err := srv.(LightningServer).UpdateChanStatus(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
-	in := new(ConnectPeerRequest)
+func _Lightning_ChannelInsights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(ChannelInsightsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
-	out, err := srv.(LightningServer).ConnectPeer(ctx, in)
+	out, err := srv.(LightningServer).ChannelInsights(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_SetChannelNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(SetChannelNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).SetChannelNote(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error) (interface{}, error) {
+	in := new(FeeReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	out, err := srv.(LightningServer).FeeReport(ctx, in)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
+func _Lightning_RegisterRPCMiddleware_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).RegisterRPCMiddleware(&lightningRegisterRPCMiddlewareServer{stream})
+}
+
+type Lightning_RegisterRPCMiddlewareServer interface {
+	Send(*RPCMiddlewareRequest) error
+	Recv() (*RPCMiddlewareResponse, error)
+	grpc.ServerStream
+}
+
+type lightningRegisterRPCMiddlewareServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningRegisterRPCMiddlewareServer) Send(m *RPCMiddlewareRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningRegisterRPCMiddlewareServer) Recv() (*RPCMiddlewareResponse, error) {
+	m := new(RPCMiddlewareResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Lightning_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "lnrpc.Lightning",
 	HandlerType: (*LightningServer)(nil),
@@ -204,37 +3764,591 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SendMany",
 			Handler:    _Lightning_SendMany_Handler,
 		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _Lightning_EstimateFee_Handler,
+		},
+		{
+			MethodName: "PublishTransaction",
+			Handler:    _Lightning_PublishTransaction_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Lightning_GetTransaction_Handler,
+		},
 		{
 			MethodName: "NewAddress",
 			Handler:    _Lightning_NewAddress_Handler,
 		},
+		{
+			MethodName: "GetRecoveryInfo",
+			Handler:    _Lightning_GetRecoveryInfo_Handler,
+		},
+		{
+			MethodName: "WalletBalance",
+			Handler:    _Lightning_WalletBalance_Handler,
+		},
+		{
+			MethodName: "ChannelBalance",
+			Handler:    _Lightning_ChannelBalance_Handler,
+		},
+		{
+			MethodName: "GetInfo",
+			Handler:    _Lightning_GetInfo_Handler,
+		},
 		{
 			MethodName: "ConnectPeer",
 			Handler:    _Lightning_ConnectPeer_Handler,
 		},
+		{
+			MethodName: "DisconnectPeer",
+			Handler:    _Lightning_DisconnectPeer_Handler,
+		},
+		{
+			MethodName: "ListPeers",
+			Handler:    _Lightning_ListPeers_Handler,
+		},
+		{
+			MethodName: "SetPeerLabel",
+			Handler:    _Lightning_SetPeerLabel_Handler,
+		},
+		{
+			MethodName: "ListPeerBackups",
+			Handler:    _Lightning_ListPeerBackups_Handler,
+		},
+		{
+			MethodName: "SendSignedData",
+			Handler:    _Lightning_SendSignedData_Handler,
+		},
+		{
+			MethodName: "SignPeerData",
+			Handler:    _Lightning_SignPeerData_Handler,
+		},
+		{
+			MethodName: "VerifyPeerData",
+			Handler:    _Lightning_VerifyPeerData_Handler,
+		},
+		{
+			MethodName: "ListPeerMisbehavior",
+			Handler:    _Lightning_ListPeerMisbehavior_Handler,
+		},
+		{
+			MethodName: "SendPayment",
+			Handler:    _Lightning_SendPayment_Handler,
+		},
+		{
+			MethodName: "QueryRoutes",
+			Handler:    _Lightning_QueryRoutes_Handler,
+		},
+		{
+			MethodName: "EstimateRouteFee",
+			Handler:    _Lightning_EstimateRouteFee_Handler,
+		},
+		{
+			MethodName: "ListPayments",
+			Handler:    _Lightning_ListPayments_Handler,
+		},
+		{
+			MethodName: "DeletePayment",
+			Handler:    _Lightning_DeletePayment_Handler,
+		},
+		{
+			MethodName: "DeleteAllPayments",
+			Handler:    _Lightning_DeleteAllPayments_Handler,
+		},
+		{
+			MethodName: "ListHtlcs",
+			Handler:    _Lightning_ListHtlcs_Handler,
+		},
+		{
+			MethodName: "LookupHtlcResolution",
+			Handler:    _Lightning_LookupHtlcResolution_Handler,
+		},
+		{
+			MethodName: "ExportAccounting",
+			Handler:    _Lightning_ExportAccounting_Handler,
+		},
+		{
+			MethodName: "AddInvoice",
+			Handler:    _Lightning_AddInvoice_Handler,
+		},
+		{
+			MethodName: "ImportAccount",
+			Handler:    _Lightning_ImportAccount_Handler,
+		},
+		{
+			MethodName: "ImportPublicKey",
+			Handler:    _Lightning_ImportPublicKey_Handler,
+		},
+		{
+			MethodName: "FundPsbt",
+			Handler:    _Lightning_FundPsbt_Handler,
+		},
+		{
+			MethodName: "SignPsbt",
+			Handler:    _Lightning_SignPsbt_Handler,
+		},
+		{
+			MethodName: "FinalizePsbt",
+			Handler:    _Lightning_FinalizePsbt_Handler,
+		},
+		{
+			MethodName: "BumpFee",
+			Handler:    _Lightning_BumpFee_Handler,
+		},
+		{
+			MethodName: "PendingSweeps",
+			Handler:    _Lightning_PendingSweeps_Handler,
+		},
+		{
+			MethodName: "ListSweeps",
+			Handler:    _Lightning_ListSweeps_Handler,
+		},
+		{
+			MethodName: "AbandonChannel",
+			Handler:    _Lightning_AbandonChannel_Handler,
+		},
+		{
+			MethodName: "UpdateChannelParams",
+			Handler:    _Lightning_UpdateChannelParams_Handler,
+		},
+		{
+			MethodName: "ListPendingReservations",
+			Handler:    _Lightning_ListPendingReservations_Handler,
+		},
+		{
+			MethodName: "GetBestBlock",
+			Handler:    _Lightning_GetBestBlock_Handler,
+		},
+		{
+			MethodName: "DescribeGraph",
+			Handler:    _Lightning_DescribeGraph_Handler,
+		},
+		{
+			MethodName: "GetChanInfo",
+			Handler:    _Lightning_GetChanInfo_Handler,
+		},
+		{
+			MethodName: "GetNodeInfo",
+			Handler:    _Lightning_GetNodeInfo_Handler,
+		},
+		{
+			MethodName: "GetNetworkInfo",
+			Handler:    _Lightning_GetNetworkInfo_Handler,
+		},
+		{
+			MethodName: "UpdateChanStatus",
+			Handler:    _Lightning_UpdateChanStatus_Handler,
+		},
+		{
+			MethodName: "ChannelInsights",
+			Handler:    _Lightning_ChannelInsights_Handler,
+		},
+		{
+			MethodName: "SetChannelNote",
+			Handler:    _Lightning_SetChannelNote_Handler,
+		},
+		{
+			MethodName: "FeeReport",
+			Handler:    _Lightning_FeeReport_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeSignedData",
+			Handler:       _Lightning_SubscribeSignedData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeInvoices",
+			Handler:       _Lightning_SubscribeInvoices_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeBlockEpochs",
+			Handler:       _Lightning_SubscribeBlockEpochs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RegisterConfirmationsNtfn",
+			Handler:       _Lightning_RegisterConfirmationsNtfn_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RegisterSpendNtfn",
+			Handler:       _Lightning_RegisterSpendNtfn_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeChannelGraph",
+			Handler:       _Lightning_SubscribeChannelGraph_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RegisterRPCMiddleware",
+			Handler:       _Lightning_RegisterRPCMiddleware_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams: []grpc.StreamDesc{},
 }
 
 var fileDescriptor0 = []byte{
-	// 297 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x6c, 0x92, 0xd1, 0x4e, 0xb3, 0x40,
-	0x10, 0x85, 0x43, 0xfb, 0xf7, 0xb7, 0x1d, 0x30, 0x6d, 0xb7, 0x89, 0x22, 0x57, 0x0d, 0x46, 0xc3,
-	0x15, 0x17, 0xed, 0x8d, 0xd1, 0xc4, 0x84, 0x58, 0x13, 0x4d, 0xd4, 0x18, 0xf5, 0x05, 0xb0, 0x4c,
-	0x2a, 0x11, 0x67, 0x91, 0x5d, 0x54, 0x5e, 0xc0, 0xf7, 0xf3, 0x8d, 0x44, 0x58, 0x02, 0x0a, 0x97,
-	0x73, 0xe6, 0x3b, 0x87, 0x33, 0x64, 0x61, 0x94, 0xc4, 0x6b, 0x37, 0x4e, 0xb8, 0xe4, 0x6c, 0x10,
-	0x51, 0x3e, 0xd8, 0x9f, 0x1a, 0x8c, 0xef, 0x91, 0x82, 0x6b, 0x9f, 0xb2, 0x3b, 0x7c, 0x4d, 0x51,
-	0x48, 0x76, 0x0a, 0x86, 0x17, 0x04, 0xc9, 0x03, 0xf7, 0x5e, 0x78, 0x4a, 0xd2, 0xd4, 0xe6, 0x7d,
-	0x47, 0x5f, 0x38, 0x6e, 0xe1, 0x70, 0xff, 0xd0, 0x6e, 0x13, 0x3d, 0x27, 0x99, 0x64, 0xd6, 0x12,
-	0xa6, 0x2d, 0x91, 0xe9, 0xd0, 0x7f, 0xc6, 0x2c, 0xcf, 0xd2, 0x9c, 0x11, 0xdb, 0x86, 0xc1, 0x9b,
-	0x1f, 0xa5, 0x68, 0xf6, 0xf2, 0xb1, 0x7f, 0xdc, 0x3b, 0xd2, 0xec, 0x39, 0x4c, 0xea, 0x64, 0x11,
-	0x73, 0x12, 0xc8, 0x0c, 0xf8, 0x27, 0x3f, 0xc2, 0xa0, 0x34, 0xd9, 0x33, 0x98, 0xde, 0xe0, 0xfb,
-	0x4f, 0x32, 0x0a, 0xa1, 0xbe, 0x6e, 0x1f, 0x00, 0x6b, 0x8a, 0xca, 0x38, 0x86, 0x2d, 0xbf, 0x94,
-	0x94, 0xf7, 0x10, 0xd8, 0x19, 0x27, 0xc2, 0xb5, 0xbc, 0x45, 0x4c, 0xaa, 0x43, 0x27, 0x30, 0x0c,
-	0x03, 0x4f, 0x5e, 0x70, 0x21, 0x15, 0xb7, 0x0f, 0xb3, 0x5f, 0x5c, 0x5d, 0x24, 0xa2, 0xcb, 0x55,
-	0x01, 0x19, 0x8b, 0x2f, 0x0d, 0x46, 0x57, 0xe1, 0xe6, 0x49, 0x52, 0x48, 0x1b, 0x76, 0x02, 0xc3,
-	0xaa, 0x38, 0xdb, 0xe9, 0xfe, 0x47, 0xd6, 0x6e, 0x4b, 0x57, 0xc1, 0x1e, 0x40, 0x5d, 0x9f, 0x99,
-	0x0a, 0x6b, 0x9d, 0x69, 0xed, 0x75, 0x6c, 0x54, 0xc4, 0x0a, 0xf4, 0x46, 0x65, 0x56, 0x91, 0xed,
-	0x73, 0x2d, 0xab, 0x6b, 0x55, 0xa6, 0x3c, 0xfe, 0x2f, 0x5e, 0xc5, 0xf2, 0x3b, 0x00, 0x00, 0xff,
-	0xff, 0x15, 0x2e, 0xcf, 0x06, 0x22, 0x02, 0x00, 0x00,
+	// 5681 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xa4, 0x3c, 0x5d, 0x6f, 0xe3, 0xda,
+	0x71, 0x97, 0x96, 0x64, 0xcb, 0x63, 0x59, 0xa6, 0x29, 0xd9, 0x96, 0x69, 0xef, 0xae, 0x97, 0x7b,
+	0x37, 0xeb, 0xbb, 0x49, 0x37, 0x9b, 0xbd, 0x37, 0x69, 0x3e, 0x7a, 0x6f, 0x22, 0x4b, 0xf4, 0x5a,
+	0x59, 0x5b, 0x52, 0xf4, 0xb1, 0x9b, 0x4d, 0x0a, 0x08, 0x14, 0x79, 0x6c, 0xb3, 0x4b, 0x91, 0x2a,
+	0x49, 0x79, 0xed, 0x3c, 0xb5, 0x40, 0x5b, 0xb4, 0x29, 0x50, 0x14, 0x28, 0xd0, 0x87, 0x22, 0x2f,
+	0x2d, 0x8a, 0xa2, 0xcf, 0x2d, 0xfa, 0x52, 0xa0, 0x40, 0x91, 0x97, 0xbe, 0xf6, 0xb1, 0x7f, 0xa0,
+	0x6f, 0xfd, 0x11, 0xc5, 0xf9, 0x22, 0x0f, 0x3f, 0xb4, 0xb7, 0x37, 0x7d, 0x33, 0xcf, 0xcc, 0x99,
+	0x33, 0x33, 0x67, 0xce, 0x9c, 0x39, 0x33, 0x23, 0xc3, 0xba, 0x3f, 0x37, 0x9f, 0xcd, 0x7d, 0x2f,
+	0xf4, 0x94, 0x92, 0xe3, 0xfa, 0x73, 0x53, 0xfb, 0x13, 0x09, 0xb6, 0x86, 0xc8, 0xb5, 0x2e, 0x0c,
+	0xf7, 0x6e, 0x80, 0x7e, 0x7f, 0x81, 0x82, 0x50, 0xf9, 0x02, 0x2a, 0x4d, 0xcb, 0xf2, 0x47, 0x5e,
+	0x73, 0xe6, 0x2d, 0xdc, 0xb0, 0x21, 0x1d, 0x15, 0x8e, 0x37, 0x5e, 0x1c, 0x3f, 0x23, 0x33, 0x9e,
+	0xa5, 0xb0, 0x9f, 0x89, 0xa8, 0xba, 0x1b, 0xfa, 0x77, 0xea, 0xa7, 0xb0, 0x9d, 0x19, 0x54, 0x36,
+	0xa0, 0xf0, 0x0e, 0xdd, 0x35, 0xa4, 0x23, 0xe9, 0x78, 0x5d, 0xd9, 0x84, 0xd2, 0x8d, 0xe1, 0x2c,
+	0x50, 0x63, 0xe5, 0x48, 0x3a, 0x2e, 0x7c, 0x7f, 0xe5, 0xbb, 0x92, 0xf6, 0x4f, 0x12, 0x28, 0x7a,
+	0x10, 0xda, 0x33, 0x23, 0x44, 0xa7, 0x08, 0x71, 0x5e, 0x9a, 0x50, 0x31, 0xb2, 0xbc, 0x7c, 0x9d,
+	0xf1, 0x92, 0x9d, 0x90, 0x65, 0x47, 0x51, 0x00, 0x42, 0xc3, 0xbf, 0x42, 0x61, 0xcb, 0x73, 0x2f,
+	0xc9, 0x8a, 0x9b, 0x8a, 0x0c, 0xe5, 0x99, 0xed, 0xe2, 0x81, 0xa0, 0x51, 0x38, 0x92, 0x8e, 0x4b,
+	0xbf, 0x19, 0xd3, 0x3f, 0x86, 0x5a, 0x82, 0x85, 0x60, 0xee, 0xb9, 0x01, 0x52, 0xaa, 0xb0, 0x7a,
+	0x89, 0xd0, 0xd0, 0x08, 0xc9, 0xcc, 0x02, 0x5e, 0x2d, 0x30, 0xc2, 0x3e, 0xf2, 0x5f, 0x4d, 0xe9,
+	0x64, 0x65, 0x1b, 0xd6, 0xdd, 0xc5, 0xac, 0xe3, 0xce, 0x17, 0x21, 0x65, 0x60, 0x53, 0xfb, 0x1e,
+	0xec, 0xf7, 0x17, 0x53, 0xc7, 0x0e, 0xae, 0x47, 0xbe, 0xe1, 0x06, 0x86, 0x19, 0xda, 0x9e, 0xcb,
+	0xd5, 0xb0, 0x09, 0x25, 0xdf, 0x78, 0x3f, 0xba, 0x25, 0x04, 0x2b, 0xf8, 0xd3, 0x31, 0xa6, 0xc8,
+	0x21, 0xd4, 0xd6, 0xb5, 0xa7, 0xa0, 0xe6, 0x4d, 0x65, 0xdc, 0x54, 0xa0, 0x18, 0xde, 0xda, 0x16,
+	0x95, 0x42, 0x7b, 0x0c, 0x3b, 0x2f, 0x51, 0x98, 0xb3, 0x44, 0x12, 0xad, 0x03, 0xdb, 0x02, 0x4e,
+	0x6f, 0x11, 0xce, 0x17, 0xa1, 0xb2, 0x05, 0x6b, 0x78, 0x33, 0x50, 0x10, 0x30, 0x95, 0xd4, 0x60,
+	0xc3, 0x23, 0xa0, 0x8e, 0x6b, 0xa1, 0x5b, 0xa6, 0xdb, 0x2a, 0xac, 0x1a, 0x74, 0xb3, 0xb0, 0x60,
+	0x05, 0xed, 0xdf, 0x25, 0xd8, 0x4d, 0x2f, 0x99, 0xc7, 0x9a, 0xb2, 0x03, 0x9b, 0xa6, 0xe7, 0x5e,
+	0xda, 0xfe, 0xcc, 0xc0, 0x58, 0x41, 0xac, 0xab, 0xa9, 0xe3, 0x99, 0xef, 0xce, 0x8c, 0xe0, 0x9a,
+	0x90, 0x5c, 0xc7, 0x43, 0xa1, 0x3d, 0x43, 0x41, 0x68, 0xcc, 0xe6, 0x8d, 0x22, 0xc7, 0x0a, 0xbd,
+	0xd0, 0x70, 0x4e, 0x11, 0x0a, 0x1a, 0x25, 0x32, 0x14, 0x33, 0xb2, 0x4a, 0xbe, 0x3f, 0x81, 0x35,
+	0xca, 0x6d, 0xd0, 0x58, 0x23, 0x66, 0xd4, 0x60, 0x66, 0x94, 0x95, 0x34, 0x52, 0x70, 0x99, 0x68,
+	0xe3, 0x08, 0xe4, 0xd8, 0xec, 0x73, 0xd5, 0x5a, 0x83, 0xed, 0x2e, 0x7a, 0xdf, 0xa4, 0xda, 0x61,
+	0x2a, 0xd5, 0x1e, 0x83, 0x22, 0x0e, 0xb2, 0x89, 0x69, 0x2d, 0x6a, 0x0d, 0xa2, 0x9f, 0x01, 0x32,
+	0xbd, 0x1b, 0xe4, 0xdf, 0x75, 0xdc, 0x4b, 0x8f, 0x13, 0xf8, 0x39, 0xec, 0x65, 0x20, 0x8c, 0x4a,
+	0x1d, 0x2a, 0x3e, 0x1b, 0xbf, 0xf0, 0x2c, 0x44, 0x48, 0x95, 0x95, 0x06, 0xc8, 0x7c, 0xf4, 0xd4,
+	0x76, 0xed, 0xe0, 0x1a, 0x59, 0x44, 0x8b, 0x65, 0x6c, 0x83, 0x73, 0xdf, 0xbb, 0x22, 0xcb, 0x62,
+	0x25, 0x4a, 0xda, 0x31, 0xd4, 0xdf, 0x18, 0x8e, 0x83, 0xc2, 0x13, 0xc3, 0x31, 0x5c, 0x33, 0x3a,
+	0x72, 0xe2, 0xd9, 0xc0, 0x54, 0x4b, 0xda, 0x31, 0xec, 0xa4, 0x30, 0x63, 0x51, 0xa6, 0x74, 0x88,
+	0x5a, 0xba, 0xb6, 0x07, 0x3b, 0xad, 0x6b, 0xc3, 0x75, 0x91, 0x93, 0x24, 0xaa, 0xfd, 0x8f, 0x04,
+	0x0a, 0x83, 0x8c, 0xee, 0xe6, 0x88, 0x41, 0x95, 0x5d, 0xa8, 0x9a, 0xde, 0x6c, 0x66, 0x87, 0x33,
+	0xe4, 0x86, 0x18, 0x10, 0x1b, 0x96, 0xbb, 0x98, 0xb1, 0x09, 0x01, 0x33, 0xac, 0x06, 0xc8, 0x8e,
+	0x67, 0x1a, 0x9c, 0xf4, 0x45, 0x60, 0x50, 0x13, 0x2b, 0x2a, 0xfb, 0xb0, 0xed, 0xa3, 0x99, 0x17,
+	0x22, 0x11, 0x54, 0x24, 0x20, 0x15, 0x94, 0x85, 0x1b, 0xa0, 0x30, 0x74, 0x90, 0x75, 0x8e, 0x67,
+	0x13, 0x58, 0x89, 0xc0, 0x0e, 0xa0, 0x16, 0xc1, 0x06, 0x64, 0x3e, 0x01, 0xae, 0x12, 0xe0, 0x21,
+	0xd4, 0xe7, 0xc8, 0xb5, 0x6c, 0xf7, 0xaa, 0x37, 0x47, 0x6e, 0x3c, 0x75, 0x8d, 0x40, 0xef, 0xc1,
+	0x8e, 0x00, 0x15, 0x26, 0x63, 0x83, 0x29, 0x6a, 0xbf, 0x92, 0x60, 0x37, 0xad, 0x08, 0xa6, 0xb3,
+	0x63, 0x28, 0x11, 0x43, 0x25, 0x92, 0x6e, 0xbc, 0xd8, 0x67, 0x36, 0x98, 0xa3, 0x9c, 0x4f, 0x60,
+	0x75, 0x7a, 0x47, 0x94, 0xb2, 0x72, 0x54, 0xf8, 0x30, 0xea, 0x0e, 0x6c, 0x06, 0x98, 0x1f, 0x63,
+	0xea, 0x88, 0x7a, 0xd9, 0x85, 0xaa, 0x8f, 0x4c, 0x64, 0xdf, 0x44, 0xe3, 0x44, 0x29, 0x9a, 0x0c,
+	0xd5, 0x97, 0x28, 0x14, 0x2d, 0xed, 0xcf, 0x24, 0xd8, 0x8a, 0x86, 0x18, 0xa7, 0xbb, 0x50, 0xb5,
+	0x2d, 0xe4, 0x86, 0x76, 0x78, 0xd7, 0x5f, 0x4c, 0x63, 0x47, 0x28, 0x43, 0xd9, 0x5d, 0xcc, 0xfa,
+	0x08, 0xf9, 0x7c, 0x67, 0xbe, 0x0d, 0xdb, 0xe8, 0x36, 0x44, 0xbe, 0x6b, 0x38, 0xcc, 0xda, 0x11,
+	0xb6, 0x32, 0xcc, 0xb4, 0xca, 0x98, 0x8e, 0x4e, 0x81, 0x61, 0x5e, 0x1b, 0x53, 0xdb, 0xb1, 0xc3,
+	0x3b, 0xc2, 0xf5, 0x9d, 0x6b, 0x22, 0x6b, 0xe4, 0xb5, 0xae, 0x0d, 0xdb, 0x25, 0xdc, 0x95, 0xb5,
+	0x9f, 0x43, 0x2d, 0x0f, 0x3b, 0xe3, 0x7d, 0xb6, 0x61, 0xdd, 0xa7, 0x08, 0x0e, 0x62, 0x56, 0xbe,
+	0x09, 0x25, 0xe4, 0xfb, 0x9e, 0x1f, 0xfb, 0x09, 0xf3, 0x1a, 0x99, 0xef, 0x90, 0xd5, 0xa4, 0xa2,
+	0x17, 0xb4, 0xcf, 0x40, 0x69, 0x79, 0xae, 0x8b, 0xcc, 0x10, 0x0b, 0x20, 0xd8, 0xbc, 0x6d, 0x35,
+	0xc3, 0x33, 0x2f, 0x08, 0x19, 0xf1, 0x0a, 0x14, 0xe7, 0xc8, 0x9f, 0x51, 0xba, 0xda, 0x23, 0xa8,
+	0x25, 0x66, 0xc5, 0x3e, 0xc0, 0x71, 0x3b, 0x6d, 0xea, 0x95, 0xb5, 0xef, 0xc0, 0x4e, 0xdb, 0x0e,
+	0xcc, 0x2c, 0xf5, 0x2a, 0xac, 0xce, 0x17, 0xd3, 0x57, 0xe2, 0x4d, 0x72, 0xe9, 0xf9, 0x26, 0x63,
+	0x1a, 0x9f, 0xff, 0xf4, 0x3c, 0x4a, 0x5f, 0x53, 0x40, 0x3e, 0xb7, 0x03, 0x32, 0x16, 0x08, 0x3b,
+	0x55, 0xc4, 0x03, 0x19, 0xaa, 0x82, 0x7e, 0xc8, 0xb5, 0x40, 0x10, 0x10, 0xf2, 0x3b, 0x16, 0xbd,
+	0xe2, 0x30, 0x82, 0xed, 0x4e, 0xbd, 0x85, 0x6b, 0x51, 0x45, 0x47, 0x32, 0x96, 0xc8, 0xd7, 0x36,
+	0xac, 0x5f, 0x3a, 0xc6, 0xbc, 0x15, 0x79, 0xcc, 0x4d, 0x7a, 0xbe, 0xcd, 0x77, 0xde, 0xe5, 0x25,
+	0x31, 0xfb, 0x42, 0xda, 0x2f, 0x7e, 0x13, 0xb6, 0x05, 0xfe, 0x98, 0x52, 0x54, 0x28, 0xe1, 0x65,
+	0x03, 0x76, 0x57, 0x6f, 0x30, 0x03, 0xc0, 0x48, 0xda, 0x67, 0x50, 0x1b, 0x22, 0x82, 0x7f, 0x8e,
+	0xc9, 0x7c, 0x40, 0x41, 0xe2, 0xfd, 0xb6, 0x0b, 0xf5, 0xe4, 0x2c, 0xa6, 0x9e, 0x06, 0xec, 0xf2,
+	0xe5, 0x4f, 0x0c, 0xf3, 0xdd, 0x62, 0x1e, 0x29, 0x69, 0x04, 0x9b, 0xd1, 0xf1, 0xc3, 0x80, 0xe4,
+	0x4e, 0x61, 0xf7, 0x72, 0xb9, 0x20, 0xa7, 0x77, 0x84, 0x5d, 0x78, 0xa4, 0x2e, 0xf3, 0xda, 0x70,
+	0x99, 0xba, 0x8a, 0xd8, 0x26, 0x4c, 0x63, 0x6e, 0x98, 0x76, 0x78, 0xc7, 0x6c, 0xa7, 0x0d, 0x10,
+	0xaf, 0x95, 0x61, 0xfa, 0x6b, 0x50, 0x36, 0x63, 0x87, 0x85, 0x45, 0xaf, 0x27, 0x0f, 0x2c, 0x9d,
+	0xa7, 0x7d, 0x0e, 0x7b, 0x19, 0xae, 0x99, 0xea, 0x34, 0xaa, 0xef, 0xc5, 0x9c, 0x2b, 0x6f, 0x5b,
+	0x50, 0x1e, 0x9b, 0xde, 0x82, 0x1d, 0x7c, 0x17, 0x0d, 0xed, 0x2b, 0x17, 0x59, 0x6d, 0x23, 0x34,
+	0x96, 0x29, 0x11, 0x5f, 0x50, 0xd4, 0x79, 0xe0, 0xad, 0xac, 0x40, 0xd1, 0x32, 0x42, 0x83, 0xc8,
+	0x56, 0xc1, 0x9a, 0x4b, 0x13, 0x61, 0x3a, 0x3d, 0x04, 0x75, 0xb8, 0x98, 0x06, 0xa6, 0x6f, 0x4f,
+	0x51, 0x66, 0x0d, 0xad, 0x07, 0x55, 0x3a, 0x88, 0x19, 0xc2, 0x80, 0xaf, 0xb2, 0x2a, 0xb6, 0xb0,
+	0xc0, 0xbe, 0x72, 0x8d, 0x70, 0xe1, 0x23, 0xa2, 0xd2, 0x8a, 0xd6, 0x84, 0x1a, 0x26, 0xc8, 0xc9,
+	0xfd, 0x26, 0xb2, 0x7c, 0x02, 0xf5, 0x24, 0x09, 0xa6, 0xcc, 0xc4, 0x6a, 0xf4, 0x84, 0xbe, 0x86,
+	0x9d, 0xd7, 0xc8, 0xb7, 0x2f, 0xef, 0xfe, 0x1f, 0xeb, 0xe5, 0x49, 0xf1, 0x04, 0x76, 0xd3, 0x74,
+	0x19, 0x13, 0x34, 0x68, 0x64, 0x61, 0x42, 0x59, 0xfb, 0x06, 0xa8, 0x7c, 0xef, 0x2f, 0xec, 0x60,
+	0x8a, 0xae, 0x8d, 0x1b, 0xdb, 0x5b, 0xe6, 0x27, 0xb4, 0x16, 0x6c, 0x08, 0x58, 0x11, 0x53, 0x52,
+	0x36, 0x06, 0xa2, 0x91, 0x52, 0x0d, 0x36, 0x2c, 0x84, 0xb7, 0x6e, 0x8e, 0x43, 0x19, 0xea, 0x03,
+	0xb5, 0x57, 0xb0, 0x95, 0x5a, 0x2e, 0x23, 0xed, 0x31, 0x54, 0x66, 0x31, 0x98, 0x5b, 0xaf, 0xc2,
+	0x6c, 0x4f, 0x98, 0xa9, 0xb5, 0xe1, 0x20, 0x97, 0x7f, 0x26, 0xed, 0xe3, 0xe4, 0xd1, 0xdf, 0x15,
+	0xac, 0x57, 0xa4, 0xf2, 0x0f, 0x12, 0x54, 0xfb, 0xc6, 0x1d, 0xbe, 0xf3, 0x9b, 0x61, 0x88, 0x66,
+	0x73, 0x12, 0x5a, 0x5e, 0x87, 0x8e, 0xc9, 0x79, 0x2a, 0x92, 0x88, 0xd7, 0x5b, 0x84, 0xf4, 0xee,
+	0xab, 0xa4, 0x83, 0x4a, 0x2c, 0xaa, 0x41, 0xa7, 0x8e, 0xec, 0x19, 0x62, 0x31, 0xe0, 0xc7, 0xb0,
+	0x1a, 0x84, 0x46, 0xb8, 0xa0, 0x01, 0x60, 0x35, 0x3a, 0x7f, 0x6c, 0xad, 0x21, 0x81, 0xe1, 0x5b,
+	0xe7, 0xd2, 0xb0, 0x9d, 0x85, 0x8f, 0x06, 0xc8, 0x08, 0x3c, 0x97, 0xf8, 0xba, 0x75, 0xfc, 0x4c,
+	0xa0, 0x2b, 0xc4, 0xb7, 0xbc, 0xf6, 0x6f, 0x12, 0xac, 0xb1, 0xc9, 0x38, 0xe0, 0x9a, 0xd3, 0x3f,
+	0x69, 0xb0, 0x4b, 0xd9, 0xac, 0xc1, 0x06, 0x1b, 0x25, 0xe1, 0xe9, 0xca, 0x91, 0x94, 0xc3, 0x6c,
+	0x1d, 0x2a, 0xa6, 0x8f, 0x48, 0x50, 0xfb, 0x95, 0xb9, 0x7d, 0x02, 0x65, 0x26, 0x68, 0xd0, 0x58,
+	0x25, 0x5a, 0xdd, 0x49, 0xe2, 0x71, 0x0d, 0xe6, 0xf1, 0xff, 0x39, 0x94, 0x4f, 0x11, 0x3a, 0xb7,
+	0x67, 0x36, 0x09, 0x69, 0x2f, 0xed, 0x5b, 0x64, 0xb1, 0x37, 0x09, 0xf6, 0xf6, 0xf8, 0x93, 0x60,
+	0x53, 0xf3, 0xd9, 0x82, 0xb5, 0x39, 0xf2, 0x4d, 0x14, 0x45, 0xee, 0xff, 0x2d, 0x81, 0x82, 0xdd,
+	0x04, 0x5b, 0x49, 0x78, 0x29, 0x58, 0x28, 0xba, 0x28, 0x37, 0xa0, 0x60, 0xcc, 0xc2, 0xd8, 0x02,
+	0x45, 0x75, 0xd0, 0x03, 0x83, 0x2f, 0xa6, 0x59, 0x28, 0xc4, 0x64, 0xbb, 0x50, 0xc5, 0xa6, 0xeb,
+	0x2d, 0xc2, 0x21, 0x32, 0x3d, 0xd7, 0xa2, 0x1a, 0xd8, 0x54, 0x1e, 0x42, 0xf9, 0x92, 0xb1, 0x4b,
+	0x36, 0x65, 0xe3, 0xc5, 0x16, 0x93, 0x35, 0x92, 0x02, 0x07, 0xa7, 0xc6, 0x6d, 0xdf, 0xf0, 0x49,
+	0x10, 0x8f, 0x27, 0xe1, 0x3b, 0xde, 0x09, 0x6f, 0xe8, 0xac, 0x32, 0x19, 0xda, 0x83, 0x2d, 0x6f,
+	0x11, 0x5e, 0x79, 0xb6, 0x7b, 0xd5, 0x22, 0x1e, 0x3d, 0x68, 0xac, 0x1f, 0x15, 0x8e, 0x8b, 0x78,
+	0xeb, 0x19, 0x7b, 0x43, 0x64, 0xfa, 0x28, 0x6c, 0x54, 0xc8, 0xf1, 0x7d, 0x06, 0xb5, 0x84, 0x98,
+	0xcc, 0x9a, 0xf7, 0x60, 0x8b, 0x61, 0xf7, 0x7d, 0x64, 0xcf, 0x8c, 0x2b, 0xee, 0x46, 0xfe, 0x51,
+	0x02, 0xe5, 0x27, 0x0b, 0xe4, 0xdf, 0x0d, 0xb0, 0x85, 0x06, 0xcb, 0x9c, 0x48, 0x42, 0x33, 0x82,
+	0x12, 0xe8, 0xf5, 0x22, 0x0a, 0x5b, 0xcc, 0x17, 0x36, 0x21, 0x5a, 0x69, 0x99, 0x68, 0xab, 0x5c,
+	0x34, 0xc7, 0x08, 0xc2, 0x33, 0x6f, 0xce, 0x62, 0xb5, 0x35, 0xc2, 0x2a, 0x82, 0xc2, 0x99, 0x37,
+	0x17, 0xee, 0x36, 0x6a, 0xb6, 0x31, 0xab, 0xf4, 0xee, 0xab, 0x43, 0xc5, 0x98, 0x85, 0x23, 0xef,
+	0xd4, 0xf3, 0xdf, 0x1b, 0xbe, 0xc5, 0xec, 0xb6, 0x01, 0xb2, 0x38, 0x2a, 0xec, 0x60, 0x15, 0x56,
+	0xd1, 0xed, 0xdc, 0xf6, 0xef, 0x28, 0x5b, 0xda, 0x2f, 0x25, 0x28, 0x11, 0x65, 0x60, 0x3e, 0x48,
+	0x78, 0x8b, 0x0d, 0xfd, 0xdc, 0x33, 0xdf, 0x35, 0x24, 0xbe, 0x4b, 0xf1, 0xf3, 0x6c, 0x85, 0xbf,
+	0x8a, 0xc9, 0x50, 0x73, 0xc6, 0xcf, 0x09, 0x9f, 0x8b, 0x91, 0x84, 0xc5, 0xea, 0x50, 0xe1, 0x88,
+	0x42, 0xf0, 0xde, 0x80, 0xe2, 0xb5, 0x37, 0xe7, 0x87, 0x02, 0x98, 0xee, 0xce, 0xbc, 0xb9, 0xf6,
+	0x29, 0xd4, 0x12, 0xbb, 0xc3, 0xb6, 0xf3, 0x10, 0x56, 0x89, 0x47, 0xe1, 0xde, 0xa9, 0xc2, 0xa6,
+	0x10, 0x34, 0x4d, 0x87, 0x3d, 0xfe, 0x94, 0x27, 0x03, 0x42, 0x0e, 0xe2, 0x03, 0xf6, 0x9e, 0xde,
+	0x55, 0xed, 0x67, 0xd0, 0xc8, 0x92, 0x89, 0xe3, 0x69, 0xcc, 0x80, 0xed, 0x5e, 0x9d, 0x22, 0x1a,
+	0x8d, 0xd3, 0xcd, 0xc0, 0x62, 0x33, 0x6d, 0xb5, 0x91, 0x63, 0xdc, 0xb1, 0x5b, 0x67, 0x0b, 0xd6,
+	0xdc, 0xc5, 0xec, 0x0c, 0xcb, 0x48, 0x33, 0x04, 0x3f, 0x84, 0x1a, 0x71, 0xbe, 0xd4, 0x26, 0x23,
+	0xb3, 0xab, 0xc1, 0x86, 0x8d, 0x3d, 0x52, 0xef, 0xf2, 0x32, 0x40, 0x61, 0xec, 0x97, 0xc8, 0x39,
+	0xa1, 0xa8, 0x84, 0x62, 0x51, 0xfb, 0x09, 0xd4, 0x93, 0x04, 0x18, 0x63, 0x47, 0x50, 0x9e, 0x73,
+	0x4c, 0xaa, 0x9b, 0x6a, 0xd2, 0xc7, 0x60, 0xb3, 0xc3, 0xd6, 0xd5, 0x11, 0xd6, 0xa1, 0x24, 0x5f,
+	0x42, 0xbd, 0x8d, 0x1c, 0x14, 0xa2, 0x94, 0x8f, 0x48, 0x39, 0x02, 0x1a, 0x76, 0xa9, 0xa0, 0x60,
+	0xcf, 0x8b, 0x2c, 0xe6, 0xb3, 0x82, 0x9e, 0xeb, 0xdc, 0xb1, 0x20, 0x78, 0x0f, 0x76, 0x52, 0x84,
+	0x58, 0x40, 0x32, 0x80, 0x06, 0x05, 0x34, 0x1d, 0x27, 0x2d, 0x7a, 0x44, 0x90, 0x03, 0x08, 0x41,
+	0xfa, 0x14, 0xfe, 0xd0, 0x62, 0x07, 0xb0, 0x9f, 0x43, 0x93, 0x2d, 0xf8, 0x77, 0x12, 0x14, 0xcf,
+	0x42, 0xc7, 0xcc, 0x1c, 0x1a, 0xe1, 0x8e, 0x5a, 0xe1, 0x11, 0xa2, 0xed, 0x9a, 0xde, 0xcc, 0x76,
+	0xaf, 0xc8, 0x16, 0x95, 0x53, 0x4e, 0x38, 0xf7, 0xac, 0xa4, 0x55, 0xb3, 0x4a, 0x54, 0x83, 0xdf,
+	0x5a, 0x8c, 0x14, 0x3d, 0xd7, 0xec, 0x9d, 0xb9, 0x0b, 0xd5, 0xe4, 0x79, 0x67, 0x0f, 0x4c, 0x8d,
+	0xbe, 0x0c, 0x30, 0x9f, 0xa2, 0xff, 0x11, 0xf9, 0xe5, 0xd1, 0x39, 0xc3, 0x89, 0xa3, 0x73, 0x2c,
+	0x44, 0x3a, 0x3a, 0xc7, 0x48, 0xda, 0x17, 0x70, 0x70, 0xee, 0x79, 0xef, 0x16, 0x73, 0xfc, 0x35,
+	0x40, 0x81, 0xe7, 0x2c, 0xc4, 0x0c, 0xd1, 0x97, 0xe9, 0x43, 0xfb, 0x73, 0x09, 0x0e, 0xf3, 0x09,
+	0xb0, 0xc5, 0xf7, 0xa1, 0x88, 0x67, 0xb0, 0xa7, 0xaf, 0xb8, 0xb6, 0x70, 0x1b, 0xae, 0x7c, 0x95,
+	0xbb, 0xbb, 0xc0, 0xd3, 0x05, 0x3e, 0x5e, 0xed, 0x06, 0xc5, 0xf7, 0xab, 0xf6, 0xd7, 0x12, 0xec,
+	0xe9, 0xb7, 0x73, 0xcf, 0x0f, 0x9b, 0xa6, 0x89, 0xf7, 0xc4, 0x76, 0xaf, 0xb8, 0x28, 0x38, 0x86,
+	0x0b, 0x0d, 0x9f, 0x06, 0x0f, 0x12, 0x3f, 0xca, 0xc8, 0xb5, 0xc8, 0x00, 0x3d, 0xdb, 0x4f, 0x60,
+	0xf5, 0xd2, 0xc3, 0xb9, 0x28, 0xb2, 0x48, 0xf5, 0xc5, 0x1e, 0x7f, 0xc9, 0x46, 0xd4, 0x4e, 0x09,
+	0x58, 0x79, 0x06, 0x80, 0x70, 0xba, 0x10, 0x3f, 0xc8, 0x83, 0x46, 0xf1, 0xa8, 0x70, 0x5c, 0x7d,
+	0xa1, 0x66, 0x90, 0x75, 0x8e, 0xa2, 0x1d, 0x43, 0x23, 0xcb, 0x57, 0xfc, 0xa2, 0x24, 0xa1, 0x26,
+	0xbd, 0x68, 0xfe, 0x4b, 0x82, 0xb5, 0x8e, 0x7b, 0xe3, 0xd9, 0x26, 0x81, 0xcc, 0xd0, 0xcc, 0x13,
+	0xde, 0xbe, 0xd1, 0xad, 0xb4, 0xc2, 0x93, 0x82, 0xbe, 0x70, 0xeb, 0x46, 0xe9, 0xca, 0x28, 0x3f,
+	0x46, 0x3e, 0x05, 0x0f, 0x2a, 0x84, 0x25, 0x6d, 0x23, 0x44, 0x2c, 0x4b, 0x16, 0x9b, 0x2b, 0x7d,
+	0xf2, 0x69, 0x50, 0xc2, 0x1b, 0x83, 0x88, 0xe1, 0x55, 0x5f, 0xd4, 0x98, 0x60, 0x8c, 0x2d, 0xbc,
+	0x2f, 0x28, 0x7b, 0xaf, 0xae, 0x13, 0x16, 0x88, 0xab, 0x9c, 0x37, 0x80, 0x3f, 0xcd, 0x03, 0x14,
+	0x76, 0xac, 0xc6, 0x06, 0x11, 0xed, 0xfb, 0xa0, 0x34, 0x2d, 0x8b, 0x51, 0x11, 0xc3, 0x65, 0x5f,
+	0x70, 0x18, 0x19, 0xba, 0x44, 0x52, 0x6d, 0x07, 0x6a, 0x7c, 0xf9, 0xc5, 0x34, 0x8a, 0x77, 0xb5,
+	0x3f, 0x96, 0xa0, 0xde, 0x99, 0x09, 0x8a, 0x15, 0x1c, 0xb8, 0x6b, 0xcc, 0x78, 0xe0, 0xbc, 0x4f,
+	0x93, 0x15, 0xae, 0x85, 0x2c, 0x92, 0x35, 0x35, 0xe3, 0x6b, 0xf0, 0x10, 0xea, 0x33, 0x23, 0x08,
+	0x91, 0xff, 0x0a, 0xe1, 0xfc, 0xd9, 0x15, 0xf2, 0xe7, 0xbe, 0xcd, 0xc2, 0xa1, 0x4d, 0x7c, 0x16,
+	0x2d, 0xe4, 0xdb, 0x37, 0x44, 0x63, 0x7d, 0x23, 0xbc, 0x26, 0x7b, 0x4d, 0x12, 0x9e, 0x3e, 0x0a,
+	0x4c, 0xc3, 0x6d, 0x94, 0xb8, 0x2b, 0x4b, 0xb1, 0xc1, 0x3c, 0xcb, 0x39, 0xec, 0x52, 0x40, 0xb4,
+	0x2e, 0xe7, 0x10, 0xdf, 0x23, 0x14, 0x39, 0xde, 0xdf, 0x79, 0x82, 0xb9, 0x8a, 0xb0, 0x0c, 0xf1,
+	0x35, 0xda, 0x3e, 0xec, 0x65, 0xa8, 0xb1, 0x85, 0xfe, 0x55, 0x82, 0xad, 0xd3, 0x85, 0x6b, 0xf5,
+	0x83, 0xa9, 0xa8, 0x84, 0x79, 0x30, 0x0d, 0x99, 0x66, 0x3f, 0x8b, 0x73, 0xa1, 0x34, 0xda, 0x7f,
+	0xc4, 0x83, 0x8f, 0xe4, 0xb4, 0x67, 0x34, 0x21, 0x1a, 0xd0, 0x7c, 0xb8, 0xc0, 0x66, 0x81, 0xa7,
+	0x82, 0xa2, 0xcc, 0x76, 0x91, 0xdf, 0xea, 0x51, 0xf6, 0xb0, 0x44, 0x32, 0xeb, 0xcf, 0xa0, 0x92,
+	0x20, 0xf2, 0x65, 0x49, 0xf5, 0x26, 0xc8, 0x31, 0x13, 0xcc, 0x2e, 0x14, 0x00, 0xfc, 0x60, 0x47,
+	0x64, 0x94, 0x89, 0xb0, 0x0f, 0xdb, 0xd8, 0x1d, 0x5d, 0xa1, 0x5e, 0x2a, 0x05, 0x5d, 0xd2, 0x1e,
+	0xc3, 0x16, 0x79, 0x12, 0x0a, 0xe2, 0xe7, 0x50, 0xd0, 0x7e, 0x07, 0xe4, 0x18, 0x2d, 0x5e, 0x29,
+	0xa0, 0x2f, 0xdc, 0x78, 0xa5, 0x3a, 0x54, 0xe8, 0x58, 0xc7, 0x8d, 0x34, 0xb6, 0xa9, 0x7d, 0x1f,
+	0x6a, 0xa7, 0xb6, 0x6b, 0x38, 0xf6, 0x2f, 0x50, 0x6a, 0xa1, 0x0c, 0x01, 0x1c, 0x59, 0xd3, 0x04,
+	0x3d, 0xbb, 0x80, 0xce, 0xa1, 0x9e, 0x9c, 0xfb, 0x81, 0xd5, 0x15, 0x00, 0xdf, 0x78, 0x4f, 0xd0,
+	0x47, 0xb7, 0xcc, 0x16, 0x78, 0xf2, 0x99, 0x3e, 0xf1, 0x74, 0xa8, 0x9e, 0x2c, 0x66, 0xf3, 0x64,
+	0xc8, 0x22, 0x24, 0xd6, 0x73, 0xd3, 0xf4, 0xe2, 0xd6, 0xd1, 0x70, 0xff, 0x63, 0xd8, 0x8a, 0xc8,
+	0xc4, 0x6f, 0x68, 0xf3, 0xda, 0x76, 0xac, 0x51, 0x9c, 0xe9, 0xde, 0x85, 0x7a, 0x9f, 0x66, 0x3e,
+	0x87, 0xef, 0x11, 0x8a, 0x53, 0x2e, 0xbf, 0x96, 0xa0, 0x22, 0x02, 0xf0, 0x02, 0x78, 0x55, 0xcf,
+	0x8e, 0x8c, 0x3a, 0x7e, 0x17, 0x45, 0x11, 0xa0, 0x85, 0x0c, 0xcb, 0xb1, 0x5d, 0xc4, 0x52, 0x54,
+	0x55, 0x58, 0x9d, 0x2e, 0xac, 0x2b, 0x14, 0xc6, 0xd6, 0x14, 0x31, 0x59, 0xe2, 0x7e, 0x2c, 0xc0,
+	0xe4, 0x09, 0x47, 0xab, 0xfc, 0x40, 0x4f, 0x7d, 0xcf, 0xb0, 0x4c, 0x23, 0xe0, 0xaf, 0x21, 0xe1,
+	0x71, 0x80, 0xe3, 0x16, 0x9d, 0xe4, 0x04, 0x49, 0xce, 0x0a, 0x27, 0x7d, 0x5d, 0x74, 0x1b, 0x9e,
+	0xf0, 0x19, 0x67, 0xc8, 0xbe, 0xba, 0xa6, 0x1e, 0xab, 0x84, 0x93, 0x2b, 0x29, 0xe1, 0x98, 0x22,
+	0x9e, 0xc2, 0xe6, 0x5c, 0x04, 0xb0, 0xeb, 0xb3, 0x16, 0xbd, 0x70, 0x63, 0x98, 0x56, 0xa3, 0xf7,
+	0x6e, 0x52, 0x3d, 0x7f, 0x24, 0x81, 0x4c, 0x46, 0x84, 0x62, 0x43, 0x6a, 0x9b, 0xb6, 0x61, 0x9d,
+	0x2b, 0x8c, 0xda, 0xd8, 0x7a, 0xe6, 0x25, 0xb9, 0x01, 0x85, 0x4b, 0xc4, 0x5d, 0xfa, 0x1e, 0x6c,
+	0xb1, 0x7a, 0x09, 0xb2, 0x98, 0x14, 0x34, 0xc2, 0xc8, 0x55, 0x08, 0xc9, 0xe8, 0x69, 0x9f, 0x83,
+	0x22, 0xf2, 0xc6, 0xa4, 0x7b, 0x02, 0xab, 0x81, 0x28, 0x16, 0xbf, 0xea, 0xd2, 0x0c, 0x6b, 0x63,
+	0xd8, 0x69, 0x4e, 0x0d, 0xd7, 0xf2, 0x5c, 0x96, 0xd3, 0x12, 0x0c, 0xee, 0xcb, 0xf2, 0x6b, 0xfb,
+	0xb0, 0x6d, 0xbf, 0x72, 0xbd, 0xf7, 0x6f, 0xae, 0x8d, 0xb0, 0xd3, 0x9c, 0xb5, 0xbd, 0x28, 0x6c,
+	0xc2, 0xe9, 0xa8, 0x34, 0x59, 0xe6, 0xc9, 0x6e, 0x40, 0x1d, 0xcf, 0x2d, 0x23, 0x44, 0x0c, 0xd0,
+	0x37, 0x7c, 0x63, 0xb6, 0xf4, 0xc5, 0xd5, 0x00, 0x79, 0x66, 0xdc, 0x36, 0x4d, 0x13, 0xcd, 0x43,
+	0x64, 0x91, 0xc0, 0x87, 0x59, 0x3b, 0xf1, 0xec, 0xb7, 0xaf, 0xb1, 0xab, 0xe9, 0xb8, 0xa7, 0x0e,
+	0x56, 0x96, 0xf0, 0x16, 0xc3, 0xa9, 0xbe, 0xe0, 0x86, 0x06, 0xdf, 0x45, 0xa2, 0xa7, 0x7b, 0x70,
+	0x90, 0xbb, 0x2e, 0x63, 0xeb, 0x08, 0xee, 0xd3, 0x3c, 0x08, 0x11, 0x72, 0x80, 0x02, 0xe4, 0xd3,
+	0x6b, 0x21, 0xda, 0xef, 0x7f, 0x91, 0x40, 0xc9, 0x82, 0xf1, 0x8d, 0xe6, 0xc7, 0x9f, 0x51, 0x28,
+	0xc5, 0xd5, 0xb7, 0xc2, 0xaf, 0x3d, 0xa6, 0xbe, 0xa6, 0xb8, 0xf9, 0x99, 0x84, 0x64, 0xb2, 0x8c,
+	0x58, 0xe2, 0x45, 0x92, 0x6b, 0xe3, 0x06, 0xb5, 0x3c, 0x37, 0xf4, 0xed, 0x29, 0x09, 0xbf, 0xc8,
+	0xd6, 0x97, 0x33, 0x59, 0x88, 0xb5, 0xd4, 0x75, 0x5f, 0x26, 0x4e, 0x60, 0x00, 0x0f, 0x96, 0x4a,
+	0xc6, 0xac, 0xe5, 0x9b, 0xb8, 0xf4, 0x14, 0x8f, 0x37, 0xa4, 0x44, 0x75, 0x22, 0x3b, 0x13, 0xdf,
+	0xd7, 0x2f, 0x51, 0x78, 0x82, 0x82, 0xf0, 0x04, 0x17, 0xf2, 0xb8, 0x8a, 0xbe, 0x80, 0x7a, 0x72,
+	0x38, 0x76, 0x3a, 0x71, 0xc1, 0x2f, 0xf2, 0x60, 0x74, 0x88, 0x9a, 0x39, 0xf5, 0xf2, 0x35, 0xd8,
+	0x26, 0x13, 0xf5, 0xb9, 0x67, 0x5e, 0x73, 0xa2, 0x4f, 0x01, 0xe2, 0x41, 0xac, 0xd7, 0xeb, 0x98,
+	0x4a, 0x15, 0x56, 0xaf, 0x45, 0x02, 0x9f, 0xc3, 0x06, 0xbe, 0xa8, 0xf2, 0x9d, 0x66, 0x15, 0x56,
+	0x69, 0x68, 0xc1, 0x36, 0x85, 0x56, 0x3d, 0xe2, 0x92, 0xf1, 0xa6, 0xf6, 0x23, 0x58, 0xc7, 0x9f,
+	0xfa, 0x0d, 0x72, 0xd3, 0x93, 0x45, 0xe4, 0x15, 0x1e, 0xf5, 0x8b, 0x12, 0x10, 0x77, 0xa7, 0x9d,
+	0x40, 0x65, 0x88, 0xdd, 0xca, 0x57, 0x70, 0xdb, 0x5b, 0xb0, 0x36, 0x43, 0xb3, 0xb9, 0xe7, 0x39,
+	0xec, 0xec, 0xcc, 0x00, 0x08, 0x0d, 0xca, 0x06, 0xbe, 0xaa, 0xe6, 0x28, 0x3e, 0x7a, 0x51, 0x65,
+	0xd5, 0x37, 0xde, 0x0f, 0x23, 0x00, 0x13, 0x49, 0x05, 0x85, 0x23, 0x77, 0xdc, 0x68, 0x9d, 0x28,
+	0xd8, 0xe1, 0x30, 0xc6, 0x32, 0x3d, 0x18, 0x0f, 0x60, 0xf3, 0x1c, 0x7f, 0xba, 0xb6, 0x7b, 0xd5,
+	0xf5, 0x2c, 0x94, 0x49, 0x5a, 0xfe, 0xa5, 0x04, 0x9b, 0x03, 0xfa, 0xcc, 0xed, 0x7b, 0x8e, 0x6d,
+	0xde, 0xa5, 0xde, 0xb7, 0x2c, 0xb8, 0x25, 0x1a, 0x99, 0xd9, 0x2e, 0x3e, 0xa4, 0x51, 0x0e, 0x8a,
+	0xbc, 0x5b, 0x2f, 0x11, 0x3a, 0x31, 0x82, 0xb8, 0x8c, 0x45, 0x6c, 0xfa, 0x12, 0xa1, 0x81, 0x11,
+	0xa2, 0x0b, 0xdb, 0x71, 0xec, 0xe8, 0x6d, 0x45, 0x2e, 0x31, 0xcb, 0x0e, 0x70, 0x01, 0xc8, 0x62,
+	0x55, 0x0c, 0x05, 0x00, 0x7b, 0x7c, 0x7a, 0x78, 0x69, 0x48, 0xab, 0xfd, 0xa7, 0x04, 0x1b, 0xec,
+	0x1c, 0xeb, 0xd6, 0x15, 0xbb, 0xd5, 0xc8, 0x67, 0x74, 0x00, 0xd9, 0x50, 0x9f, 0xdc, 0x56, 0x2b,
+	0xd1, 0x1e, 0x7a, 0x16, 0xfa, 0x56, 0x7f, 0x31, 0x6d, 0x14, 0xc4, 0x91, 0x17, 0x78, 0xa4, 0xc8,
+	0x47, 0xa2, 0x23, 0x59, 0x62, 0x45, 0xe6, 0x0d, 0x3a, 0x8b, 0xc8, 0xce, 0xd2, 0x58, 0x75, 0x21,
+	0xd5, 0x10, 0xeb, 0x85, 0xa1, 0xbe, 0x60, 0xa8, 0x6b, 0x1f, 0x40, 0xc5, 0x01, 0x04, 0x89, 0x3c,
+	0x69, 0x18, 0x5e, 0xd6, 0xbe, 0x05, 0x35, 0x26, 0xd1, 0x4b, 0xdf, 0x98, 0x5f, 0x0b, 0x0f, 0x62,
+	0xdb, 0x35, 0x9d, 0x85, 0x85, 0xc6, 0xae, 0xe1, 0xba, 0xde, 0x02, 0x57, 0xd7, 0x58, 0xee, 0xf9,
+	0x35, 0x54, 0xc4, 0x29, 0xca, 0x23, 0x28, 0xe1, 0xe5, 0xf9, 0xf9, 0xe5, 0x0b, 0x27, 0x77, 0xf7,
+	0x21, 0x94, 0x90, 0x75, 0x85, 0xd2, 0x39, 0x61, 0x41, 0x9b, 0xda, 0x67, 0xb0, 0x85, 0x3f, 0x85,
+	0x6a, 0x62, 0xe6, 0xa5, 0x98, 0xd5, 0xae, 0xf6, 0x10, 0xb6, 0xf0, 0x02, 0xa9, 0x59, 0x09, 0x4b,
+	0xfa, 0x03, 0x09, 0xca, 0x1c, 0x47, 0xd1, 0xa0, 0xe8, 0xf2, 0x3a, 0xf7, 0x32, 0x66, 0x73, 0xab,
+	0xc6, 0x3c, 0xa9, 0xd4, 0xe2, 0xfb, 0x54, 0x60, 0xd9, 0xd7, 0xb8, 0x5a, 0x53, 0x5c, 0x2a, 0xdb,
+	0x01, 0xec, 0x13, 0x65, 0x8d, 0xbc, 0xb9, 0xe7, 0x78, 0x57, 0x77, 0x89, 0xf7, 0xc6, 0x1f, 0x4a,
+	0xb0, 0x2d, 0x20, 0x53, 0x93, 0xcb, 0xc8, 0xbe, 0x07, 0x5b, 0x86, 0x75, 0x83, 0xfc, 0xd0, 0x0e,
+	0x18, 0x9f, 0xcc, 0xbe, 0x48, 0xed, 0x9b, 0xd4, 0xfc, 0xf8, 0x38, 0xb5, 0xb2, 0xaf, 0xc3, 0xa6,
+	0x2f, 0x6e, 0x7e, 0xa3, 0x98, 0x10, 0x39, 0x61, 0x18, 0xda, 0x0f, 0xa0, 0xd6, 0x72, 0xbc, 0x00,
+	0x59, 0x8c, 0x91, 0x25, 0x4c, 0x60, 0xdf, 0x4f, 0xd0, 0x04, 0x07, 0xba, 0xa9, 0xfd, 0xbd, 0x04,
+	0xb5, 0x84, 0x78, 0x6c, 0xf6, 0x13, 0xd8, 0x70, 0xd1, 0xfb, 0x48, 0x8f, 0xd2, 0x32, 0xf5, 0x28,
+	0xcf, 0xa1, 0x6a, 0x8a, 0xeb, 0x72, 0x33, 0x69, 0x64, 0x71, 0x19, 0xe9, 0x17, 0x50, 0x35, 0x45,
+	0x7e, 0xd3, 0x65, 0xe2, 0x1c, 0x61, 0xb4, 0x3a, 0x6e, 0xa3, 0x08, 0xdf, 0x7b, 0xfe, 0x3b, 0xb1,
+	0x62, 0xfd, 0xcf, 0x12, 0x6c, 0x08, 0xc3, 0xcc, 0xe5, 0x76, 0x99, 0x45, 0x33, 0x07, 0x93, 0x35,
+	0x87, 0x43, 0xa8, 0x13, 0x73, 0x60, 0x53, 0x53, 0x56, 0xb1, 0x0b, 0x55, 0xe3, 0xe6, 0x8a, 0x4d,
+	0x19, 0xda, 0xbf, 0xa0, 0xa1, 0x96, 0x84, 0x63, 0x97, 0x19, 0xb2, 0x6c, 0xc3, 0x15, 0x41, 0x25,
+	0x9e, 0xdc, 0x9f, 0x19, 0xb7, 0xbd, 0x45, 0xd8, 0x46, 0x57, 0x3e, 0x42, 0xac, 0x72, 0xba, 0x0b,
+	0x55, 0x77, 0x31, 0xfb, 0x99, 0x37, 0x9b, 0xda, 0x24, 0x84, 0x60, 0x01, 0xa9, 0x36, 0x80, 0xbd,
+	0x38, 0xae, 0xa0, 0x49, 0x8d, 0x65, 0x87, 0xe6, 0x09, 0xac, 0xd2, 0xa8, 0x8b, 0x65, 0x44, 0xf6,
+	0x04, 0xa5, 0xd2, 0x99, 0x4d, 0x02, 0xd6, 0x54, 0x68, 0x64, 0x69, 0xb2, 0x40, 0xe5, 0x38, 0xea,
+	0x43, 0xe8, 0xb8, 0x01, 0xde, 0xfa, 0xa5, 0xd9, 0xa2, 0x5f, 0x4b, 0x50, 0x4d, 0xa2, 0xe6, 0x59,
+	0x11, 0x6d, 0xb3, 0x60, 0x29, 0xe6, 0xc8, 0x4f, 0x3a, 0xf6, 0x25, 0xc2, 0x2e, 0x9e, 0x69, 0xb1,
+	0x0a, 0xab, 0x8b, 0x79, 0x18, 0x57, 0x3a, 0x12, 0x95, 0xe5, 0x12, 0x77, 0xdc, 0xd8, 0x4d, 0x9f,
+	0x3a, 0xc6, 0x3c, 0xce, 0x3b, 0x78, 0x2e, 0x79, 0x0a, 0xac, 0xf1, 0xe2, 0xb4, 0xeb, 0x31, 0x7f,
+	0xb7, 0x2e, 0x3a, 0xc0, 0x75, 0x1e, 0xcd, 0xfc, 0x82, 0x68, 0x97, 0x25, 0x82, 0x80, 0xb8, 0x8c,
+	0x13, 0xd8, 0xcb, 0x88, 0x1b, 0xc5, 0xb8, 0x65, 0x33, 0x69, 0xd1, 0x3b, 0x49, 0x2b, 0x65, 0x33,
+	0xb4, 0x6f, 0xe3, 0x02, 0x6b, 0xc8, 0x06, 0xbb, 0x5e, 0x88, 0x96, 0x6d, 0x10, 0xe7, 0x70, 0x85,
+	0x77, 0xf1, 0xa4, 0xa7, 0xc5, 0x55, 0x7c, 0xf2, 0xa6, 0xc2, 0x6f, 0x75, 0x6e, 0xbd, 0x1e, 0xc8,
+	0x0c, 0x35, 0x02, 0xfd, 0x1f, 0xbc, 0x26, 0x89, 0x22, 0x8c, 0x00, 0xf1, 0xfc, 0x71, 0x81, 0x3f,
+	0x72, 0x2e, 0x11, 0xea, 0xe3, 0x1a, 0x9b, 0xb3, 0xec, 0x5e, 0xc4, 0x6d, 0x03, 0xdb, 0x02, 0x17,
+	0x4c, 0x29, 0xdf, 0x80, 0x0d, 0x33, 0x62, 0x23, 0x1d, 0xfd, 0x67, 0x18, 0xdc, 0x81, 0x4d, 0xcb,
+	0xb8, 0x3b, 0x45, 0x68, 0xb8, 0x98, 0x09, 0x77, 0xf6, 0x2e, 0x54, 0xdf, 0x23, 0xf4, 0x4e, 0x18,
+	0x2f, 0x70, 0xcf, 0x37, 0xf3, 0xdc, 0xf0, 0x5a, 0x00, 0xd0, 0xf6, 0x93, 0x5f, 0x4a, 0x50, 0x1f,
+	0xf4, 0x5b, 0x17, 0xb6, 0x65, 0x39, 0xe8, 0xbd, 0xe1, 0x23, 0x21, 0x2d, 0xe7, 0xd3, 0x3f, 0xd9,
+	0x53, 0xa2, 0x48, 0xdf, 0xed, 0x8e, 0x73, 0x81, 0xc2, 0x6b, 0x8f, 0xbf, 0x24, 0x48, 0xf6, 0xce,
+	0x47, 0xc6, 0x6c, 0xd0, 0x6f, 0xc5, 0x89, 0x57, 0x3b, 0xda, 0x6b, 0xd6, 0xde, 0x80, 0x0b, 0x0c,
+	0x77, 0x73, 0xd4, 0xc5, 0xa9, 0x9f, 0x12, 0xaf, 0xf1, 0x05, 0xc8, 0xb7, 0xc9, 0xbb, 0x9b, 0xbe,
+	0x1e, 0x2b, 0xda, 0x9f, 0x4a, 0xb0, 0x93, 0x62, 0x26, 0xce, 0xd7, 0xcf, 0xa2, 0xd1, 0x6e, 0x9c,
+	0x40, 0x92, 0xa1, 0xec, 0x23, 0xc3, 0x8a, 0xf3, 0xc9, 0x49, 0xbe, 0x0b, 0x3c, 0xeb, 0xeb, 0xa3,
+	0xdf, 0x43, 0x66, 0xd8, 0x28, 0x26, 0x3b, 0x53, 0x4a, 0x71, 0xc6, 0x72, 0xee, 0x18, 0x26, 0x9a,
+	0x21, 0xd6, 0x6e, 0x51, 0xd1, 0xfe, 0x4a, 0x82, 0x0d, 0xf2, 0x54, 0x6d, 0xa3, 0xd0, 0xb0, 0x1d,
+	0xe5, 0x3e, 0x14, 0x4d, 0x7e, 0xe7, 0x55, 0x5f, 0xc8, 0xbc, 0xe9, 0x11, 0x63, 0xb4, 0xf0, 0x7d,
+	0xf7, 0x29, 0x54, 0x59, 0x7a, 0xec, 0x94, 0x26, 0x45, 0x99, 0xa7, 0x38, 0x48, 0xe6, 0x4e, 0x4f,
+	0xc5, 0x8c, 0xa9, 0xf2, 0x4d, 0xd8, 0x62, 0x5b, 0x8e, 0xc3, 0x53, 0xc7, 0x36, 0x79, 0x7e, 0x73,
+	0x37, 0xb9, 0xed, 0x1c, 0xfa, 0xf4, 0x7b, 0xb0, 0x99, 0x4c, 0xc2, 0x6e, 0xc2, 0x7a, 0xa7, 0x3b,
+	0x39, 0x3d, 0xef, 0xbc, 0x3c, 0x1b, 0xc9, 0x1f, 0xe1, 0xcf, 0xe1, 0xb8, 0xd5, 0xd2, 0xf5, 0xb6,
+	0xde, 0x96, 0x25, 0x05, 0x60, 0xf5, 0xb4, 0xd9, 0x39, 0xd7, 0xdb, 0xf2, 0xca, 0xd3, 0x0e, 0xc8,
+	0x99, 0x6c, 0xe9, 0x3e, 0xec, 0x34, 0x5b, 0xad, 0xde, 0xb8, 0x3b, 0xea, 0x74, 0x5f, 0x4e, 0x4e,
+	0x7b, 0x83, 0x8b, 0xe6, 0x68, 0xd2, 0x1a, 0xbe, 0x96, 0x3f, 0x52, 0x54, 0xd8, 0xcd, 0x82, 0x7e,
+	0x3c, 0xec, 0x75, 0x65, 0xe9, 0xe9, 0x5f, 0x48, 0x50, 0xcb, 0x49, 0xa6, 0x2a, 0xf7, 0x60, 0x5f,
+	0x98, 0xa3, 0x77, 0x47, 0x83, 0xb7, 0x93, 0x5e, 0x77, 0xd2, 0x3a, 0x6b, 0x76, 0xba, 0xf2, 0x47,
+	0xca, 0x21, 0x34, 0x32, 0xe0, 0xd3, 0xde, 0xe0, 0x4d, 0x73, 0x80, 0x79, 0xcd, 0x83, 0x76, 0xba,
+	0xaf, 0x7b, 0x9d, 0x96, 0x2e, 0xaf, 0xe4, 0x42, 0xfb, 0xcd, 0xb7, 0x17, 0x7a, 0x77, 0x24, 0x17,
+	0x9e, 0xbe, 0x82, 0x4a, 0x22, 0x07, 0x2a, 0x43, 0x85, 0x4d, 0x9d, 0xf4, 0xfa, 0x3a, 0x5e, 0xbb,
+	0x06, 0x5b, 0x7c, 0x64, 0xa8, 0x8f, 0x46, 0xe7, 0x44, 0x3d, 0x75, 0x90, 0xf9, 0x60, 0xab, 0xd9,
+	0x6d, 0xe9, 0x54, 0x51, 0xdf, 0xa6, 0xee, 0x40, 0x74, 0xeb, 0x58, 0x91, 0x7a, 0xb7, 0x79, 0x72,
+	0xae, 0xcb, 0x1f, 0x29, 0x1b, 0xb0, 0xd6, 0xee, 0x0c, 0xc9, 0x87, 0xa4, 0x94, 0xa1, 0xd8, 0x1c,
+	0x8f, 0x7a, 0xf2, 0xca, 0xd3, 0xbf, 0x29, 0xc1, 0x7a, 0x6c, 0x0e, 0xbb, 0xa0, 0xe8, 0x83, 0x41,
+	0x6f, 0x30, 0x69, 0xf5, 0xda, 0xfa, 0x64, 0xdc, 0x7d, 0xd5, 0xed, 0xbd, 0xc1, 0x7c, 0x3c, 0x86,
+	0x87, 0xc2, 0x78, 0x5f, 0xd7, 0x07, 0x93, 0xe6, 0xf9, 0x40, 0x6f, 0xb6, 0xdf, 0x4e, 0x5a, 0xbd,
+	0x6e, 0x57, 0x6f, 0x8d, 0x08, 0x67, 0x0f, 0xe1, 0x5e, 0x1a, 0xad, 0xdb, 0x1b, 0x09, 0x28, 0x2b,
+	0xca, 0x23, 0x78, 0x20, 0xa0, 0x0c, 0xf5, 0xc1, 0x6b, 0x7d, 0x30, 0x19, 0x9e, 0x8d, 0x47, 0x44,
+	0x43, 0x6d, 0xbc, 0x5c, 0x21, 0x45, 0xa7, 0xd3, 0x1d, 0x8e, 0x4f, 0x4f, 0x3b, 0xad, 0x8e, 0xde,
+	0x1d, 0x4d, 0x4e, 0xc7, 0xdd, 0xf6, 0x50, 0x2e, 0x2a, 0x1f, 0xc3, 0x91, 0x80, 0x32, 0xd0, 0x31,
+	0xa5, 0xe6, 0xa8, 0xd3, 0xeb, 0x92, 0x15, 0x4f, 0x7b, 0xe3, 0x6e, 0x5b, 0x2e, 0x29, 0x4f, 0xe0,
+	0x91, 0x80, 0x75, 0x31, 0x1e, 0x76, 0x5e, 0xbe, 0x98, 0x0c, 0xf5, 0xe1, 0x30, 0x89, 0xb8, 0x8a,
+	0x6d, 0x40, 0x40, 0x64, 0x7b, 0x36, 0xd1, 0x7f, 0xda, 0x19, 0x8e, 0x86, 0xf2, 0x9a, 0x72, 0x00,
+	0x7b, 0x02, 0x78, 0xf4, 0x53, 0x2c, 0xd2, 0x69, 0x67, 0x70, 0xa1, 0xb7, 0xe5, 0x72, 0x6a, 0x2e,
+	0xdb, 0xde, 0x09, 0xb3, 0xe0, 0x75, 0xe5, 0x01, 0x1c, 0x08, 0xe0, 0xd6, 0x59, 0xb3, 0xdb, 0xd5,
+	0xcf, 0x09, 0x81, 0xf3, 0x4e, 0x6b, 0x24, 0x83, 0x72, 0x04, 0x87, 0x39, 0xf3, 0xe3, 0xf3, 0xb1,
+	0x91, 0x5a, 0x9e, 0x6b, 0xbe, 0xdf, 0xec, 0xb4, 0xe5, 0x4a, 0x4a, 0x13, 0x09, 0x65, 0xf5, 0xc6,
+	0xa3, 0x13, 0x22, 0xe0, 0x66, 0x4a, 0xef, 0x09, 0xac, 0x4e, 0x97, 0x22, 0x55, 0xf1, 0xc1, 0x12,
+	0x90, 0xb0, 0x7e, 0x86, 0x6f, 0xbb, 0x2d, 0xbd, 0x2d, 0x6f, 0xa5, 0x58, 0x68, 0xf7, 0xc6, 0x27,
+	0xe7, 0xfa, 0x64, 0xd8, 0xd7, 0xbb, 0x6d, 0x59, 0xc6, 0xa7, 0x4e, 0x00, 0x9e, 0xea, 0xfa, 0x64,
+	0xd4, 0xeb, 0x4d, 0xce, 0x7b, 0x6f, 0xe4, 0xed, 0x94, 0x76, 0x2e, 0x3a, 0xc3, 0x21, 0xde, 0xe8,
+	0x4e, 0xb7, 0x3f, 0x1e, 0x0d, 0x65, 0x25, 0xab, 0xd9, 0x78, 0x57, 0x6a, 0x4f, 0xff, 0xb6, 0x00,
+	0xf5, 0x5c, 0x0f, 0xd4, 0x80, 0xba, 0xa8, 0xe7, 0xf1, 0x00, 0x73, 0xdb, 0xc5, 0x66, 0xae, 0xc1,
+	0xfd, 0x34, 0x04, 0xf3, 0x72, 0xd1, 0xec, 0xbe, 0x9d, 0x9c, 0x8d, 0xce, 0x5b, 0x43, 0x59, 0xc2,
+	0x56, 0x91, 0xc6, 0xb9, 0x68, 0xfe, 0x74, 0xf2, 0xba, 0x79, 0x3e, 0xd6, 0x05, 0xbd, 0xaf, 0xe4,
+	0x11, 0x3b, 0xd1, 0xcf, 0x7b, 0x6f, 0x26, 0x17, 0x9d, 0x2e, 0xa1, 0x26, 0x17, 0xf0, 0xd1, 0xc8,
+	0x23, 0xd6, 0x1e, 0x0f, 0xb1, 0xfd, 0xf4, 0x7b, 0xc3, 0xf1, 0x40, 0x97, 0x8b, 0xca, 0x31, 0x7c,
+	0x9c, 0x46, 0x63, 0xc7, 0x2b, 0xda, 0xf1, 0xb3, 0xe6, 0xf0, 0x4c, 0x2e, 0xe5, 0xc9, 0x76, 0xa6,
+	0x9f, 0x63, 0x23, 0x3d, 0x80, 0xbd, 0x8c, 0x6c, 0x9d, 0x0b, 0xbd, 0x37, 0x1e, 0xc9, 0x6b, 0xd8,
+	0xd5, 0x64, 0x55, 0x32, 0x19, 0xf4, 0xc6, 0x23, 0x5d, 0x2e, 0x2b, 0xbf, 0x05, 0x9f, 0xa4, 0xa1,
+	0x9d, 0x6e, 0xab, 0x37, 0x18, 0xe8, 0xad, 0x51, 0xc4, 0x40, 0x5b, 0x1f, 0x35, 0x3b, 0xe7, 0x43,
+	0x79, 0x5d, 0xf9, 0x04, 0x1e, 0x67, 0x84, 0x1a, 0x9f, 0x8f, 0x3a, 0x93, 0xb3, 0x5e, 0x7f, 0x32,
+	0xee, 0x0e, 0xc7, 0xfd, 0x7e, 0x6f, 0x80, 0x0f, 0x34, 0x3c, 0xfd, 0x0f, 0x09, 0xb6, 0x52, 0xfe,
+	0x1e, 0xdb, 0x51, 0xda, 0xce, 0xf9, 0xfe, 0x7c, 0x0d, 0xb4, 0x0c, 0x88, 0x38, 0x8a, 0xb3, 0xe6,
+	0x90, 0x1f, 0x0e, 0xbc, 0x47, 0x1a, 0xdc, 0xcf, 0xe0, 0x8d, 0xde, 0xf6, 0x89, 0x05, 0x5d, 0x34,
+	0x47, 0xad, 0x33, 0x79, 0x05, 0xab, 0x3e, 0x83, 0x33, 0xee, 0xb7, 0x9b, 0x23, 0xee, 0x18, 0xf1,
+	0x01, 0x2c, 0xe4, 0x2e, 0xd9, 0xed, 0x4d, 0xb0, 0xed, 0x62, 0x53, 0xa4, 0x33, 0xe4, 0xe2, 0x8b,
+	0x5f, 0x1d, 0xc1, 0x7a, 0xf4, 0x1a, 0x54, 0x7e, 0x00, 0x65, 0xde, 0xb0, 0xad, 0xec, 0xe6, 0xff,
+	0x70, 0x41, 0xdd, 0xcb, 0x8c, 0xb3, 0x7b, 0xbf, 0x0d, 0x1b, 0x42, 0x57, 0xbf, 0xb2, 0xbf, 0xf4,
+	0xc7, 0x06, 0xaa, 0x9a, 0x07, 0x62, 0x54, 0xde, 0x82, 0x92, 0x6d, 0xca, 0x57, 0x8e, 0xf8, 0xd5,
+	0xbc, 0xac, 0xd5, 0x5f, 0x7d, 0xf8, 0x01, 0x0c, 0x46, 0xfa, 0x82, 0xb4, 0xef, 0x8a, 0x64, 0x0f,
+	0xd9, 0xa4, 0xdc, 0xd6, 0x7e, 0xf5, 0xde, 0x12, 0x28, 0x23, 0xd7, 0x04, 0x88, 0xdb, 0xd4, 0x15,
+	0xfe, 0x76, 0xcb, 0xb4, 0xb3, 0xab, 0xfb, 0x39, 0x10, 0x46, 0xa2, 0x0f, 0x5b, 0xa9, 0x46, 0x75,
+	0x45, 0x58, 0x34, 0xa7, 0xb5, 0x5d, 0xbd, 0xbf, 0x0c, 0xcc, 0x28, 0xfe, 0x18, 0x36, 0x13, 0x3d,
+	0xe7, 0x0a, 0x0f, 0x6a, 0xf2, 0x7a, 0xd6, 0xd5, 0xc3, 0x7c, 0x60, 0xac, 0xaf, 0x64, 0x33, 0x76,
+	0xa4, 0xaf, 0xdc, 0x66, 0x75, 0xf5, 0xde, 0x12, 0x28, 0x23, 0xf7, 0x5d, 0x58, 0x63, 0xad, 0xd2,
+	0xca, 0x4e, 0x2c, 0x85, 0x28, 0xdc, 0x6e, 0x7a, 0x38, 0xb6, 0x2c, 0xa1, 0x8d, 0x38, 0xb2, 0xac,
+	0x6c, 0x43, 0xb2, 0xaa, 0xe6, 0x81, 0x62, 0x71, 0x92, 0xfd, 0xc2, 0x91, 0x38, 0xb9, 0xed, 0xc7,
+	0xea, 0xbd, 0x25, 0x50, 0x46, 0xee, 0x0b, 0x58, 0xa7, 0x19, 0x5f, 0xe4, 0x07, 0xca, 0x5e, 0x94,
+	0x58, 0x49, 0xb6, 0x1d, 0xab, 0x8d, 0x2c, 0x80, 0xcd, 0x7f, 0x09, 0x15, 0xb1, 0x3b, 0x57, 0x51,
+	0xa3, 0x73, 0x95, 0x69, 0xf4, 0x55, 0x0f, 0x72, 0x61, 0xb1, 0x11, 0xa5, 0x1a, 0x63, 0x23, 0x23,
+	0xca, 0x6f, 0xf3, 0x55, 0xef, 0x2f, 0x03, 0xc7, 0x9a, 0x4a, 0xb6, 0xb9, 0x46, 0x9a, 0xca, 0x6d,
+	0xa1, 0x55, 0xef, 0x2d, 0x81, 0x32, 0x72, 0x3f, 0x81, 0x5a, 0x4e, 0x6f, 0xac, 0xc2, 0x4f, 0xec,
+	0xf2, 0xbe, 0x59, 0x95, 0xdb, 0x49, 0xb2, 0x79, 0xf6, 0xb9, 0x44, 0x94, 0x27, 0x34, 0xaf, 0xc6,
+	0xca, 0xcb, 0x36, 0xc5, 0xaa, 0x07, 0xb9, 0xb0, 0x58, 0xd4, 0x64, 0x0b, 0x6a, 0x24, 0x6a, 0x6e,
+	0xc7, 0xab, 0x7a, 0x6f, 0x09, 0x94, 0x91, 0xfb, 0x5d, 0xd6, 0x6b, 0x94, 0xea, 0x1c, 0x7d, 0x98,
+	0x52, 0x78, 0xb6, 0x89, 0x55, 0xd5, 0x3e, 0x84, 0x12, 0x9f, 0x03, 0xa1, 0xe1, 0x2e, 0x3a, 0x07,
+	0xd9, 0x5e, 0x43, 0x55, 0xcd, 0x03, 0xc5, 0x54, 0x84, 0x3e, 0xaf, 0x88, 0x4a, 0xb6, 0x33, 0x4f,
+	0x55, 0xf3, 0x40, 0x8c, 0xca, 0x10, 0xe4, 0x74, 0xc7, 0x96, 0x72, 0x3f, 0xe5, 0xd7, 0x53, 0x1d,
+	0x61, 0xea, 0x83, 0xa5, 0xf0, 0xf8, 0x4c, 0x88, 0x9d, 0x56, 0xd1, 0xb6, 0xe6, 0xf4, 0x6f, 0xa9,
+	0x07, 0xb9, 0xb0, 0xd8, 0x0d, 0x26, 0xda, 0xa2, 0x22, 0x37, 0x98, 0xd7, 0x75, 0xa5, 0x1e, 0xe6,
+	0x03, 0x19, 0xad, 0xd7, 0xb0, 0x9d, 0xe9, 0x7a, 0x52, 0x1e, 0x24, 0xa6, 0x64, 0x7b, 0xac, 0xd4,
+	0xa3, 0xe5, 0x08, 0x49, 0x07, 0x42, 0xca, 0x6d, 0x09, 0x07, 0x22, 0x76, 0x27, 0xa9, 0x8d, 0x2c,
+	0x80, 0xcd, 0x9f, 0x40, 0x3d, 0xaf, 0x6b, 0x48, 0x89, 0x2c, 0x69, 0x79, 0x4f, 0x92, 0xfa, 0xe8,
+	0x83, 0x38, 0xc2, 0x16, 0xa7, 0x1a, 0x6e, 0xe2, 0x2d, 0xce, 0xef, 0x10, 0x52, 0x1f, 0x2c, 0x85,
+	0x33, 0xa2, 0xbf, 0x0d, 0x10, 0x37, 0xb0, 0x28, 0xd5, 0x64, 0x5b, 0x4c, 0x74, 0x57, 0xe6, 0xf4,
+	0xb8, 0x34, 0x61, 0x3b, 0xf2, 0x14, 0x0c, 0x16, 0x1b, 0x48, 0x4e, 0x5f, 0x8b, 0x9a, 0xa2, 0xfd,
+	0x5c, 0xc2, 0x56, 0x91, 0xe8, 0x30, 0x89, 0xac, 0x22, 0xaf, 0xfd, 0x45, 0x3d, 0xcc, 0x07, 0xc6,
+	0x5e, 0x37, 0xd5, 0x46, 0x12, 0x79, 0xdd, 0xfc, 0x66, 0x15, 0xf5, 0xfe, 0x32, 0x30, 0xa3, 0xf8,
+	0x03, 0x28, 0xf3, 0x06, 0x8e, 0x28, 0xf8, 0x4a, 0xb5, 0x95, 0xa8, 0x7b, 0x99, 0xf1, 0x78, 0x32,
+	0xef, 0xc9, 0x88, 0x23, 0xb7, 0x64, 0x2f, 0x87, 0xba, 0x97, 0x19, 0x8f, 0x8f, 0x9d, 0xd8, 0x56,
+	0x11, 0x69, 0x35, 0xa7, 0x4f, 0x43, 0x3d, 0xc8, 0x85, 0xc5, 0x57, 0x3c, 0x6b, 0x85, 0x88, 0xae,
+	0xf8, 0x64, 0x87, 0x85, 0xba, 0x9b, 0x1e, 0x8e, 0x0f, 0x6c, 0xa2, 0x83, 0x20, 0xda, 0x9a, 0xbc,
+	0xa6, 0x09, 0xf5, 0x30, 0x1f, 0x18, 0x07, 0x66, 0x71, 0xb1, 0x5e, 0x11, 0x0f, 0x50, 0x92, 0xca,
+	0x7e, 0x0e, 0x24, 0xbe, 0x16, 0x92, 0x95, 0xf5, 0xe8, 0x5a, 0xc8, 0xad, 0xe3, 0xab, 0xf7, 0x96,
+	0x40, 0xe3, 0x6b, 0x21, 0xa7, 0x2c, 0x1e, 0x5d, 0x0b, 0xcb, 0x4b, 0xf5, 0xaa, 0xf6, 0x21, 0x14,
+	0x46, 0xfd, 0x9a, 0xff, 0x32, 0x26, 0x53, 0x7b, 0x56, 0x1e, 0x27, 0x6e, 0x95, 0x65, 0x55, 0x77,
+	0xf5, 0x6b, 0x5f, 0x86, 0x16, 0x1b, 0x8a, 0x58, 0x7a, 0x8e, 0x0c, 0x25, 0xa7, 0x4c, 0xad, 0x1e,
+	0xe4, 0xc2, 0x18, 0x21, 0x1d, 0xea, 0xd1, 0x61, 0x8e, 0xeb, 0xce, 0xf1, 0x66, 0x65, 0x0a, 0xd4,
+	0xea, 0x76, 0x06, 0xf2, 0x5c, 0x52, 0x5a, 0xb0, 0x3f, 0x40, 0x57, 0x76, 0x10, 0x22, 0xbf, 0x25,
+	0xfe, 0x04, 0xb6, 0x1b, 0x5e, 0xba, 0x8a, 0x12, 0xc7, 0x82, 0xbc, 0x56, 0xad, 0xca, 0xc2, 0x18,
+	0xa9, 0xfc, 0x3e, 0x97, 0x94, 0xcf, 0x61, 0x9b, 0x13, 0x21, 0xa5, 0x5e, 0x32, 0x99, 0x77, 0xa8,
+	0x88, 0x75, 0x66, 0x75, 0x5b, 0x1c, 0xe4, 0xd3, 0x7f, 0x84, 0xaf, 0x1a, 0x2a, 0x09, 0x2d, 0x10,
+	0xaa, 0xc9, 0x30, 0x58, 0x2c, 0x34, 0xaa, 0xb5, 0x1c, 0x98, 0xf2, 0x3d, 0xd8, 0x78, 0x49, 0x53,
+	0xe0, 0x24, 0x38, 0x16, 0x13, 0x8a, 0x62, 0x74, 0x9c, 0x57, 0x49, 0xfa, 0x0e, 0x99, 0x1a, 0x55,
+	0xfb, 0xf8, 0xd4, 0x54, 0x89, 0x50, 0xdd, 0x4a, 0x8d, 0x2b, 0x6f, 0x60, 0x27, 0xd2, 0x7f, 0x82,
+	0x17, 0x7e, 0x6d, 0x2d, 0x2d, 0xdf, 0xa9, 0x6a, 0x1e, 0x06, 0x35, 0xcf, 0xe7, 0x92, 0xf2, 0x43,
+	0xf2, 0xc6, 0x12, 0x0b, 0x4c, 0xf1, 0xf3, 0x27, 0x5d, 0x8b, 0x52, 0x95, 0x2c, 0x08, 0x5f, 0x3a,
+	0xe9, 0xaa, 0x4c, 0x74, 0xe9, 0x2c, 0x29, 0x01, 0xa9, 0x0f, 0x96, 0xc2, 0x63, 0x67, 0x9d, 0xaa,
+	0x6f, 0x28, 0xf7, 0x72, 0xab, 0x18, 0x99, 0x10, 0x79, 0x59, 0x59, 0x84, 0x84, 0xc8, 0x62, 0xd9,
+	0x42, 0x08, 0x91, 0x73, 0x8a, 0x20, 0xea, 0xbd, 0x25, 0xd0, 0x38, 0x16, 0x88, 0xeb, 0x05, 0x7b,
+	0xf1, 0x0f, 0x1a, 0x12, 0xd5, 0x0f, 0xb5, 0x91, 0x05, 0x44, 0x31, 0xca, 0x0e, 0xb7, 0xe1, 0x44,
+	0x52, 0x3e, 0xe2, 0x2a, 0x37, 0x55, 0xaf, 0x1e, 0xe4, 0x43, 0xc9, 0x6a, 0xc7, 0xd2, 0x73, 0x69,
+	0xba, 0x4a, 0xfe, 0xeb, 0xc1, 0xa7, 0xff, 0x3b, 0x00, 0xd2, 0xf3, 0x1f, 0x79, 0x02, 0x41, 0x00,
+	0x00,
 }
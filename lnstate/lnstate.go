@@ -107,7 +107,7 @@ type LNChannel struct {
 	fundingTxIn *wire.TxIn
 	channelDB   *channeldb.DB
 
-	channelID uint64
+	channelID lnwire.ShortChannelID
 
 	//The person who set up the channel is even, the person who responded
 	//is odd. All HTLCKeys use even/odd numbering.
@@ -154,12 +154,12 @@ type LNChannel struct {
 
 // PaymentDescriptor ...
 type PaymentDescriptor struct {
-	RHashes       []*[20]byte
-	Timeout       uint32
-	CreditsAmount lnwire.CreditsAmount
-	Revocation    []*[20]byte
-	Blob          []byte //next hop data
-	PayToUs       bool
+	RHashes    []*[20]byte
+	Timeout    uint32
+	Amount     lnwire.MilliSatoshi
+	Revocation []*[20]byte
+	Blob       []byte //next hop data
+	PayToUs    bool
 
 	State uint32 //Current state
 
@@ -283,7 +283,7 @@ func (l *LNChannel) recvHTLCAddRequest(p *lnwire.HTLCAddRequest) error {
 	//Populate the entries
 	htlc.RHashes = p.RedemptionHashes
 	htlc.Timeout = p.Expiry
-	htlc.CreditsAmount = p.Amount
+	htlc.Amount = p.Amount
 	htlc.Blob = p.Blob
 	htlc.State = AddStaged //mark as staged by both parties
 	htlc.PayToUs = true    //assume this is paid to us, may change in the future
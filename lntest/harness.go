@@ -0,0 +1,267 @@
+package lntest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpctest"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// numInitialOutputs is the number of outputs Alice and Bob are each
+	// funded with when the network is set up.
+	numInitialOutputs = 10
+
+	// initialOutputValue is the value of each of those outputs.
+	initialOutputValue = btcutil.SatoshiPerBitcoin
+
+	// pollInterval is how often a condition awaited across the network is
+	// checked.
+	pollInterval = 200 * time.Millisecond
+)
+
+// NetworkHarness is a network of lnd nodes, all using the same btcd simnet
+// node as their chain backend, for testing the daemon end to end. The
+// network starts out with two funded nodes, Alice and Bob, while further
+// nodes may be added by each test.
+//
+// TODO: add helpers opening, paying over, and closing channels
+// once the daemon exposes them over rpc.
+type NetworkHarness struct {
+	// Miner is the btcd node backing the network, through which blocks
+	// are mined, and the nodes funded.
+	Miner *rpctest.Harness
+
+	Alice *HarnessNode
+	Bob   *HarnessNode
+
+	lndBinary string
+
+	// errChan is sent the error of any node which exits unexpectedly.
+	errChan chan error
+
+	activeNodes map[string]*HarnessNode
+	mtx         sync.Mutex
+}
+
+// NewNetworkHarness creates a network backed by the passed miner, whose
+// nodes are each launched from the lnd binary at the passed path.
+//
+// NOTE: The miner MUST be on simnet, as the nodes are.
+func NewNetworkHarness(miner *rpctest.Harness, lndBinary string) *NetworkHarness {
+	return &NetworkHarness{
+		Miner:       miner,
+		lndBinary:   lndBinary,
+		errChan:     make(chan error, 10),
+		activeNodes: make(map[string]*HarnessNode),
+	}
+}
+
+// ProcessErrors returns the channel each node which exits without being
+// stopped sends its error on.
+func (n *NetworkHarness) ProcessErrors() <-chan error {
+	return n.errChan
+}
+
+// SetUp launches Alice and Bob, funding each with numInitialOutputs outputs
+// of initialOutputValue.
+func (n *NetworkHarness) SetUp() error {
+	var err error
+	n.Alice, err = n.NewNode(nil)
+	if err != nil {
+		return err
+	}
+	n.Bob, err = n.NewNode(nil)
+	if err != nil {
+		return err
+	}
+
+	ctxb := context.Background()
+	for _, node := range []*HarnessNode{n.Alice, n.Bob} {
+		for i := 0; i < numInitialOutputs; i++ {
+			err := n.SendCoins(ctxb, initialOutputValue, node)
+			if err != nil {
+				return fmt.Errorf("unable to fund %v: %v",
+					node.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewNode creates, and launches, a new node within the network, launching
+// lnd with the passed extra arguments.
+func (n *NetworkHarness) NewNode(extraArgs []string) (*HarnessNode, error) {
+	node, err := newNode(n.Miner.RPCConfig(), extraArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := node.start(n.lndBinary, n.errChan); err != nil {
+		node.cleanup()
+		return nil, err
+	}
+
+	n.mtx.Lock()
+	n.activeNodes[node.Name()] = node
+	n.mtx.Unlock()
+
+	return node, nil
+}
+
+// RestartNode stops the node, runs the callback if non-nil, then launches
+// the node again with its existing data.
+func (n *NetworkHarness) RestartNode(node *HarnessNode, callback func() error) error {
+	if err := node.stop(); err != nil {
+		return err
+	}
+
+	if callback != nil {
+		if err := callback(); err != nil {
+			return err
+		}
+	}
+
+	return node.start(n.lndBinary, n.errChan)
+}
+
+// ShutdownNode stops the node, removing it from the network along with its
+// data.
+func (n *NetworkHarness) ShutdownNode(node *HarnessNode) error {
+	if err := node.stop(); err != nil {
+		return err
+	}
+
+	n.mtx.Lock()
+	delete(n.activeNodes, node.Name())
+	n.mtx.Unlock()
+
+	return node.cleanup()
+}
+
+// TearDown stops every node within the network, removing their data.
+func (n *NetworkHarness) TearDown() error {
+	n.mtx.Lock()
+	nodes := make([]*HarnessNode, 0, len(n.activeNodes))
+	for _, node := range n.activeNodes {
+		nodes = append(nodes, node)
+	}
+	n.mtx.Unlock()
+
+	var firstErr error
+	for _, node := range nodes {
+		if err := n.ShutdownNode(node); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ConnectNodes connects node a to node b, waiting until each lists the other
+// as a peer.
+func (n *NetworkHarness) ConnectNodes(ctx context.Context, a, b *HarnessNode) error {
+	req := &lnrpc.ConnectPeerRequest{IdAtHost: b.P2PAddr()}
+	if _, err := a.ConnectPeer(ctx, req); err != nil {
+		return err
+	}
+
+	return n.WaitForPeers(ctx, a, b)
+}
+
+// WaitForPeers waits until nodes a and b each list the other as a peer.
+func (n *NetworkHarness) WaitForPeers(ctx context.Context, a, b *HarnessNode) error {
+	return WaitPredicate(ctx, func() (bool, error) {
+		aHasB, err := hasPeer(ctx, a, b)
+		if err != nil || !aHasB {
+			return false, err
+		}
+		return hasPeer(ctx, b, a)
+	})
+}
+
+// hasPeer returns true if the node lists the peer as connected.
+func hasPeer(ctx context.Context, node, peer *HarnessNode) (bool, error) {
+	resp, err := node.ListPeers(ctx, &lnrpc.ListPeersRequest{})
+	if err != nil {
+		return false, err
+	}
+	for _, p := range resp.Peers {
+		if p.PubKey == peer.PubKeyStr {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SendCoins pays the amount to a new address of the target node from the
+// miner's coinbase outputs, then mines a block confirming the payment,
+// waiting until it's reflected within the node's balance.
+func (n *NetworkHarness) SendCoins(ctx context.Context, amt btcutil.Amount,
+	target *HarnessNode) error {
+
+	balReq := &lnrpc.WalletBalanceRequest{MinConfs: 1}
+	initialBalance, err := target.WalletBalance(ctx, balReq)
+	if err != nil {
+		return err
+	}
+
+	addrResp, err := target.NewAddress(ctx, &lnrpc.NewAddressRequest{})
+	if err != nil {
+		return err
+	}
+	addr, err := btcutil.DecodeAddress(addrResp.Address,
+		&chaincfg.SimNetParams)
+	if err != nil {
+		return err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+
+	output := wire.NewTxOut(int64(amt), pkScript)
+	if _, err := n.Miner.CoinbaseSpend([]*wire.TxOut{output}); err != nil {
+		return err
+	}
+	if _, err := n.Miner.Node.Generate(1); err != nil {
+		return err
+	}
+
+	expectedBalance := initialBalance.Balance + int64(amt)
+	return WaitPredicate(ctx, func() (bool, error) {
+		resp, err := target.WalletBalance(ctx, balReq)
+		if err != nil {
+			return false, err
+		}
+		return resp.Balance == expectedBalance, nil
+	})
+}
+
+// WaitPredicate polls the predicate until it returns true, an error, or the
+// context is done.
+func WaitPredicate(ctx context.Context, pred func() (bool, error)) error {
+	for {
+		ok, err := pred()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
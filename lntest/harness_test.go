@@ -0,0 +1,165 @@
+package lntest
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpctest"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+var (
+	// lndExecutable is the path of the lnd binary the network's nodes are
+	// launched from. The tests are skipped unless it's set, as they also
+	// need btcd to be installed.
+	lndExecutable = flag.String("lndexec", "",
+		"The path of the lnd binary to run the network tests against")
+)
+
+// defaultTimeout is how long each step of a test may take.
+const defaultTimeout = 30 * time.Second
+
+// testFunding ensures that coins sent to a new node are reflected within
+// its balance once confirmed.
+func testFunding(net *NetworkHarness, t *testing.T) {
+	ctxt, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	carol, err := net.NewNode(nil)
+	if err != nil {
+		t.Fatalf("unable to create carol: %v", err)
+	}
+	defer net.ShutdownNode(carol)
+
+	amt := btcutil.Amount(btcutil.SatoshiPerBitcoin / 2)
+	if err := net.SendCoins(ctxt, amt, carol); err != nil {
+		t.Fatalf("unable to fund carol: %v", err)
+	}
+
+	resp, err := carol.WalletBalance(ctxt,
+		&lnrpc.WalletBalanceRequest{MinConfs: 1})
+	if err != nil {
+		t.Fatalf("unable to get carol's balance: %v", err)
+	}
+	if resp.Balance != int64(amt) {
+		t.Fatalf("expected balance of %v, got %v", int64(amt),
+			resp.Balance)
+	}
+}
+
+// testConnectDisconnect ensures that Alice and Bob each list the other as a
+// peer once connected, and neither does once Alice disconnects.
+func testConnectDisconnect(net *NetworkHarness, t *testing.T) {
+	ctxt, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	if err := net.ConnectNodes(ctxt, net.Alice, net.Bob); err != nil {
+		t.Fatalf("unable to connect alice to bob: %v", err)
+	}
+
+	info, err := net.Bob.GetInfo(ctxt, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		t.Fatalf("unable to get bob's info: %v", err)
+	}
+	if info.NumPeers != 1 {
+		t.Fatalf("expected bob to have 1 peer, has %v", info.NumPeers)
+	}
+
+	_, err = net.Alice.DisconnectPeer(ctxt, &lnrpc.DisconnectPeerRequest{
+		PubKey: net.Bob.PubKeyStr,
+	})
+	if err != nil {
+		t.Fatalf("unable to disconnect alice from bob: %v", err)
+	}
+
+	err = WaitPredicate(ctxt, func() (bool, error) {
+		aliceHasBob, err := hasPeer(ctxt, net.Alice, net.Bob)
+		if err != nil || aliceHasBob {
+			return false, err
+		}
+		bobHasAlice, err := hasPeer(ctxt, net.Bob, net.Alice)
+		return !bobHasAlice, err
+	})
+	if err != nil {
+		t.Fatalf("peers still connected after disconnecting: %v", err)
+	}
+}
+
+// testPersistentReconnect ensures that Alice reconnects to Bob, a
+// persistent peer, once he restarts.
+func testPersistentReconnect(net *NetworkHarness, t *testing.T) {
+	ctxt, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	_, err := net.Alice.ConnectPeer(ctxt, &lnrpc.ConnectPeerRequest{
+		IdAtHost: net.Bob.P2PAddr(),
+		Perm:     true,
+	})
+	if err != nil {
+		t.Fatalf("unable to connect alice to bob: %v", err)
+	}
+	if err := net.WaitForPeers(ctxt, net.Alice, net.Bob); err != nil {
+		t.Fatalf("alice and bob never connected: %v", err)
+	}
+
+	if err := net.RestartNode(net.Bob, nil); err != nil {
+		t.Fatalf("unable to restart bob: %v", err)
+	}
+	if err := net.WaitForPeers(ctxt, net.Alice, net.Bob); err != nil {
+		t.Fatalf("alice never reconnected to bob: %v", err)
+	}
+
+	_, err = net.Alice.DisconnectPeer(ctxt, &lnrpc.DisconnectPeerRequest{
+		PubKey: net.Bob.PubKeyStr,
+	})
+	if err != nil {
+		t.Fatalf("unable to disconnect alice from bob: %v", err)
+	}
+}
+
+var networkTests = []func(net *NetworkHarness, t *testing.T){
+	testFunding,
+	testConnectDisconnect,
+	testPersistentReconnect,
+}
+
+// TestNetwork runs each of the network tests against a fresh network of
+// Alice and Bob, backed by a simnet btcd node.
+func TestNetwork(t *testing.T) {
+	if *lndExecutable == "" {
+		t.Skip("set -lndexec to the path of an lnd binary to run the " +
+			"network tests")
+	}
+
+	// The miner is given some mature coinbase outputs to fund the nodes
+	// with.
+	miner, err := rpctest.New(&chaincfg.SimNetParams, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to create miner: %v", err)
+	}
+	if err := miner.SetUp(true, 50); err != nil {
+		t.Fatalf("unable to set up miner: %v", err)
+	}
+	defer miner.TearDown()
+
+	net := NewNetworkHarness(miner, *lndExecutable)
+	defer net.TearDown()
+	if err := net.SetUp(); err != nil {
+		t.Fatalf("unable to set up network: %v", err)
+	}
+
+	for _, networkTest := range networkTests {
+		networkTest(net, t)
+
+		select {
+		case err := <-net.ProcessErrors():
+			t.Fatalf("node exited: %v", err)
+		default:
+		}
+	}
+}
@@ -0,0 +1,408 @@
+package mock
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntfs"
+)
+
+// Chain is an in-memory chain backend for unit tests, in place of btcd. No
+// blocks are mined, and no transactions accepted, other than those the test
+// passes it, so the notifications dispatched are entirely deterministic. It
+// implements chainntnfs.ChainNotifier, along with the GetBestBlock and
+// GetBlock methods of the chain views used by the sweeper, and others.
+//
+// Notifications are dispatched within the goroutine of the test, before
+// AddToMempool, or MineBlock, return. As such, the trigger channels of any
+// notifications registered MUST be buffered, and the transaction, and block
+// epoch, channels serviced by another goroutine.
+type Chain struct {
+	mtx sync.Mutex
+
+	height   int32
+	bestHash wire.ShaHash
+	blocks   map[wire.ShaHash]*wire.MsgBlock
+
+	mempool []*wire.MsgTx
+
+	// txHeights is the height each transaction mined was mined at, while
+	// spends are the details of the spend of each outpoint, in the
+	// mempool or mined.
+	txHeights map[wire.ShaHash]int32
+	spends    map[wire.OutPoint]*chainntnfs.SpendDetail
+
+	confNtfns  map[wire.ShaHash][]*confNtfn
+	spendNtfns map[wire.OutPoint][]*spendNtfn
+	txClients  []chan *chainntnfs.RelevantTx
+	epochChans []chan *chainntnfs.BlockEpoch
+}
+
+// A compile time check to ensure Chain implements the ChainNotifier
+// interface.
+var _ chainntnfs.ChainNotifier = (*Chain)(nil)
+
+// confNtfn is a registered confirmation notification yet to fire.
+type confNtfn struct {
+	numConfs uint32
+	trigger  *chainntnfs.NotificationTrigger
+}
+
+// spendNtfn is a registered spend notification yet to fire.
+type spendNtfn struct {
+	mempool bool
+	trigger *chainntnfs.NotificationTrigger
+}
+
+// NewChain returns a chain consisting of only its genesis block, at height
+// zero.
+func NewChain() *Chain {
+	genesis := wire.NewMsgBlock(&wire.BlockHeader{})
+	genesisHash := genesis.BlockSha()
+
+	return &Chain{
+		bestHash:   genesisHash,
+		blocks:     map[wire.ShaHash]*wire.MsgBlock{genesisHash: genesis},
+		txHeights:  make(map[wire.ShaHash]int32),
+		spends:     make(map[wire.OutPoint]*chainntnfs.SpendDetail),
+		confNtfns:  make(map[wire.ShaHash][]*confNtfn),
+		spendNtfns: make(map[wire.OutPoint][]*spendNtfn),
+	}
+}
+
+// Start does nothing, as the chain has no backend to connect to.
+func (c *Chain) Start() error {
+	return nil
+}
+
+// Stop does nothing, as the chain has no backend to disconnect from.
+func (c *Chain) Stop() error {
+	return nil
+}
+
+// RegisterConfirmationsNotification registers a trigger which fires once
+// the transaction has been mined, and buried under numConfs-1 further
+// blocks. Should it already be buried that deep, the trigger fires at once.
+func (c *Chain) RegisterConfirmationsNotification(txid *wire.ShaHash,
+	numConfs uint32, trigger *chainntnfs.NotificationTrigger) error {
+
+	c.mtx.Lock()
+	if c.numConfs(*txid) >= numConfs && numConfs > 0 {
+		c.mtx.Unlock()
+		triggerNtfn(trigger)
+		return nil
+	}
+	c.confNtfns[*txid] = append(c.confNtfns[*txid], &confNtfn{
+		numConfs: numConfs,
+		trigger:  trigger,
+	})
+	c.mtx.Unlock()
+
+	return nil
+}
+
+// RegisterSpendNotification registers a trigger which fires once the
+// outpoint is spent, either being mined, or entering the mempool if mempool
+// is set. Should the outpoint already be spent, the trigger fires at once.
+func (c *Chain) RegisterSpendNotification(outpoint *wire.OutPoint,
+	mempool bool, trigger *chainntnfs.NotificationTrigger) error {
+
+	c.mtx.Lock()
+	detail, ok := c.spends[*outpoint]
+	if ok && (mempool || detail.SpendingHeight != 0) {
+		c.mtx.Unlock()
+		triggerSpendNtfn(trigger, detail)
+		return nil
+	}
+	c.spendNtfns[*outpoint] = append(c.spendNtfns[*outpoint], &spendNtfn{
+		mempool: mempool,
+		trigger: trigger,
+	})
+	c.mtx.Unlock()
+
+	return nil
+}
+
+// RegisterRelevantTxNotification registers a channel which is sent every
+// transaction passed to the chain, first as it enters the mempool, then
+// again once it's mined.
+func (c *Chain) RegisterRelevantTxNotification(txChan chan *chainntnfs.RelevantTx) error {
+	c.mtx.Lock()
+	c.txClients = append(c.txClients, txChan)
+	c.mtx.Unlock()
+
+	return nil
+}
+
+// RegisterBlockEpochNotification registers a channel which is sent each
+// block mined.
+func (c *Chain) RegisterBlockEpochNotification(epochChan chan *chainntnfs.BlockEpoch) error {
+	c.mtx.Lock()
+	c.epochChans = append(c.epochChans, epochChan)
+	c.mtx.Unlock()
+
+	return nil
+}
+
+// GetBestBlock returns the hash, and height, of the last block mined.
+func (c *Chain) GetBestBlock() (*wire.ShaHash, int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	hash := c.bestHash
+	return &hash, c.height, nil
+}
+
+// GetBlock returns the block of the passed hash.
+func (c *Chain) GetBlock(hash *wire.ShaHash) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	block, ok := c.blocks[*hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %v", hash)
+	}
+	return block, nil
+}
+
+// TxHeight returns the height the transaction was mined at, or false if it
+// hasn't been mined.
+func (c *Chain) TxHeight(txid *wire.ShaHash) (int32, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	height, ok := c.txHeights[*txid]
+	return height, ok
+}
+
+// Mempool returns the transactions waiting to be mined by the next block.
+func (c *Chain) Mempool() []*wire.MsgTx {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	mempool := make([]*wire.MsgTx, len(c.mempool))
+	copy(mempool, c.mempool)
+	return mempool
+}
+
+// AddToMempool accepts the transaction to the mempool, to be mined by the
+// next block. Any spend notifications of the outpoints it spends registered
+// with mempool set fire, with relevant transaction clients sent it
+// unconfirmed. An error is returned if the transaction double spends one
+// already accepted.
+func (c *Chain) AddToMempool(tx *wire.MsgTx) error {
+	c.mtx.Lock()
+	if err := c.checkDoubleSpend(tx); err != nil {
+		c.mtx.Unlock()
+		return err
+	}
+	c.mempool = append(c.mempool, tx)
+	dispatch := c.processTx(tx, 0)
+	c.mtx.Unlock()
+
+	dispatch()
+	return nil
+}
+
+// MineBlock mines a block of the transactions within the mempool, followed
+// by those passed, which are added to the mempool beforehand. Spends of the
+// outpoints the block spends, and transactions it confirms, are notified
+// along with the block itself, in that order.
+//
+// NOTE: MineBlock fails should a transaction passed double spend one
+// already accepted, without mining a block.
+func (c *Chain) MineBlock(txs ...*wire.MsgTx) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	for _, tx := range txs {
+		if err := c.checkDoubleSpend(tx); err != nil {
+			c.mtx.Unlock()
+			return nil, err
+		}
+	}
+
+	var dispatches []func()
+	for _, tx := range txs {
+		c.mempool = append(c.mempool, tx)
+		dispatches = append(dispatches, c.processTx(tx, 0))
+	}
+
+	c.height++
+	header := &wire.BlockHeader{
+		PrevBlock: c.bestHash,
+		Nonce:     uint32(c.height),
+	}
+	block := wire.NewMsgBlock(header)
+	for _, tx := range c.mempool {
+		block.AddTransaction(tx)
+		c.txHeights[tx.TxSha()] = c.height
+		dispatches = append(dispatches, c.processTx(tx, c.height))
+	}
+	c.mempool = nil
+
+	c.bestHash = block.BlockSha()
+	c.blocks[c.bestHash] = block
+	dispatches = append(dispatches, c.processConfs())
+
+	epoch := &chainntnfs.BlockEpoch{Hash: c.bestHash, Height: c.height}
+	epochChans := make([]chan *chainntnfs.BlockEpoch, len(c.epochChans))
+	copy(epochChans, c.epochChans)
+	c.mtx.Unlock()
+
+	for _, dispatch := range dispatches {
+		dispatch()
+	}
+	for _, epochChan := range epochChans {
+		epochChan <- epoch
+	}
+
+	return block, nil
+}
+
+// MineBlocks mines numBlocks blocks, the first of which includes the
+// transactions within the mempool.
+func (c *Chain) MineBlocks(numBlocks int) ([]*wire.MsgBlock, error) {
+	blocks := make([]*wire.MsgBlock, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		block, err := c.MineBlock()
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// checkDoubleSpend returns an error if the transaction spends an outpoint
+// already spent by another, in the mempool or mined.
+//
+// NOTE: This MUST be called with the mutex held.
+func (c *Chain) checkDoubleSpend(tx *wire.MsgTx) error {
+	txid := tx.TxSha()
+	for _, txIn := range tx.TxIn {
+		detail, ok := c.spends[txIn.PreviousOutPoint]
+		if ok && detail.SpendingTx.TxSha() != txid {
+			return fmt.Errorf("tx %v double spends %v", txid,
+				txIn.PreviousOutPoint)
+		}
+	}
+	return nil
+}
+
+// processTx records the spends of the transaction, which is in the mempool
+// if height is zero, or otherwise mined at height. The returned closure
+// fires the spend notifications due, and sends out the transaction, and
+// MUST be run once the mutex is released.
+//
+// NOTE: This MUST be called with the mutex held.
+func (c *Chain) processTx(tx *wire.MsgTx, height int32) func() {
+	relevantTx := &chainntnfs.RelevantTx{Tx: tx, BlockHeight: uint32(height)}
+	txClients := make([]chan *chainntnfs.RelevantTx, len(c.txClients))
+	copy(txClients, c.txClients)
+
+	type spendTrigger struct {
+		trigger *chainntnfs.NotificationTrigger
+		detail  *chainntnfs.SpendDetail
+	}
+	var triggers []spendTrigger
+
+	for i, txIn := range tx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		detail := &chainntnfs.SpendDetail{
+			SpentOutPoint:     &prevOut,
+			SpendingTx:        tx,
+			SpenderInputIndex: uint32(i),
+			SpendingHeight:    uint32(height),
+		}
+		c.spends[prevOut] = detail
+
+		var remaining []*spendNtfn
+		for _, ntfn := range c.spendNtfns[prevOut] {
+			if !ntfn.mempool && height == 0 {
+				remaining = append(remaining, ntfn)
+				continue
+			}
+			triggers = append(triggers, spendTrigger{ntfn.trigger, detail})
+		}
+		if len(remaining) == 0 {
+			delete(c.spendNtfns, prevOut)
+		} else {
+			c.spendNtfns[prevOut] = remaining
+		}
+	}
+
+	return func() {
+		for _, txChan := range txClients {
+			txChan <- relevantTx
+		}
+		for _, t := range triggers {
+			triggerSpendNtfn(t.trigger, t.detail)
+		}
+	}
+}
+
+// processConfs removes the confirmation notifications due as of the latest
+// block, returning the closure firing them, which MUST be run once the mutex
+// is released.
+//
+// NOTE: This MUST be called with the mutex held.
+func (c *Chain) processConfs() func() {
+	var triggers []*chainntnfs.NotificationTrigger
+	for txid, ntfns := range c.confNtfns {
+		confs := c.numConfs(txid)
+
+		var remaining []*confNtfn
+		for _, ntfn := range ntfns {
+			if confs < ntfn.numConfs || confs == 0 {
+				remaining = append(remaining, ntfn)
+				continue
+			}
+			triggers = append(triggers, ntfn.trigger)
+		}
+		if len(remaining) == 0 {
+			delete(c.confNtfns, txid)
+		} else {
+			c.confNtfns[txid] = remaining
+		}
+	}
+
+	return func() {
+		for _, trigger := range triggers {
+			triggerNtfn(trigger)
+		}
+	}
+}
+
+// numConfs returns the number of confirmations of the transaction, zero if
+// it hasn't been mined.
+//
+// NOTE: This MUST be called with the mutex held.
+func (c *Chain) numConfs(txid wire.ShaHash) uint32 {
+	height, ok := c.txHeights[txid]
+	if !ok {
+		return 0
+	}
+	return uint32(c.height - height + 1)
+}
+
+// triggerNtfn fires the notification, running its callback within its own
+// goroutine as the btcd notifier does.
+func triggerNtfn(t *chainntnfs.NotificationTrigger) {
+	if t.Callback != nil {
+		go t.Callback()
+	}
+	if t.TriggerChan != nil {
+		t.TriggerChan <- struct{}{}
+	}
+}
+
+// triggerSpendNtfn fires a spend notification, handing over the details of
+// the spend if requested.
+func triggerSpendNtfn(t *chainntnfs.NotificationTrigger,
+	detail *chainntnfs.SpendDetail) {
+
+	if t.SpendChan != nil {
+		t.SpendChan <- detail
+	}
+	triggerNtfn(t)
+}
@@ -0,0 +1,170 @@
+package mock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// A compile time check to ensure Chain may back the sweeper.
+var _ sweep.ChainView = (*Chain)(nil)
+
+// spendingTx returns a transaction spending the outpoint.
+func spendingTx(prevOut wire.OutPoint, value int64) *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&prevOut, nil))
+	tx.AddTxOut(wire.NewTxOut(value, nil))
+	return tx
+}
+
+// assertFired asserts whether the trigger has fired.
+func assertFired(t *testing.T, trigger *chainntnfs.NotificationTrigger,
+	fired bool, desc string) {
+
+	select {
+	case <-trigger.TriggerChan:
+		if !fired {
+			t.Fatalf("%v fired unexpectedly", desc)
+		}
+	default:
+		if fired {
+			t.Fatalf("%v didn't fire", desc)
+		}
+	}
+}
+
+func newTrigger() *chainntnfs.NotificationTrigger {
+	return &chainntnfs.NotificationTrigger{
+		TriggerChan: make(chan struct{}, 1),
+		SpendChan:   make(chan *chainntnfs.SpendDetail, 1),
+	}
+}
+
+func TestConfirmations(t *testing.T) {
+	chain := NewChain()
+	epochChan := make(chan *chainntnfs.BlockEpoch, 10)
+	chain.RegisterBlockEpochNotification(epochChan)
+
+	tx := spendingTx(wire.OutPoint{Index: 1}, 1000)
+	txid := tx.TxSha()
+
+	trigger := newTrigger()
+	chain.RegisterConfirmationsNotification(&txid, 3, trigger)
+
+	if _, err := chain.MineBlock(tx); err != nil {
+		t.Fatalf("unable to mine block: %v", err)
+	}
+	if height, ok := chain.TxHeight(&txid); !ok || height != 1 {
+		t.Fatalf("expected tx mined at height 1, got %v", height)
+	}
+	assertFired(t, trigger, false, "conf ntfn after 1 conf")
+
+	if _, err := chain.MineBlocks(2); err != nil {
+		t.Fatalf("unable to mine blocks: %v", err)
+	}
+	assertFired(t, trigger, true, "conf ntfn after 3 confs")
+
+	// Each block mined is sent out, and the latest is the best block.
+	for i := int32(1); i <= 3; i++ {
+		epoch := <-epochChan
+		if epoch.Height != i {
+			t.Fatalf("expected epoch at height %v, got %v", i,
+				epoch.Height)
+		}
+	}
+	hash, height, _ := chain.GetBestBlock()
+	if height != 3 {
+		t.Fatalf("expected best height 3, got %v", height)
+	}
+	if _, err := chain.GetBlock(hash); err != nil {
+		t.Fatalf("unable to fetch best block: %v", err)
+	}
+
+	// A notification registered once the tx is buried deep enough fires
+	// at once.
+	late := newTrigger()
+	chain.RegisterConfirmationsNotification(&txid, 2, late)
+	assertFired(t, late, true, "late conf ntfn")
+}
+
+func TestSpendNotifications(t *testing.T) {
+	chain := NewChain()
+
+	prevOut := wire.OutPoint{Index: 7}
+	mempoolTrigger := newTrigger()
+	minedTrigger := newTrigger()
+	chain.RegisterSpendNotification(&prevOut, true, mempoolTrigger)
+	chain.RegisterSpendNotification(&prevOut, false, minedTrigger)
+
+	spend := spendingTx(prevOut, 1000)
+	if err := chain.AddToMempool(spend); err != nil {
+		t.Fatalf("unable to add tx to mempool: %v", err)
+	}
+	assertFired(t, mempoolTrigger, true, "mempool spend ntfn")
+	assertFired(t, minedTrigger, false, "mined spend ntfn")
+
+	detail := <-mempoolTrigger.SpendChan
+	if detail.SpendingTx != spend || detail.SpendingHeight != 0 {
+		t.Fatalf("unexpected mempool spend detail: %v", detail)
+	}
+
+	// A conflicting spend is rejected.
+	if err := chain.AddToMempool(spendingTx(prevOut, 500)); err == nil {
+		t.Fatalf("double spend accepted")
+	}
+
+	if _, err := chain.MineBlock(); err != nil {
+		t.Fatalf("unable to mine block: %v", err)
+	}
+	assertFired(t, mempoolTrigger, false, "mempool spend ntfn twice")
+	assertFired(t, minedTrigger, true, "mined spend ntfn")
+
+	detail = <-minedTrigger.SpendChan
+	if detail.SpendingHeight != 1 {
+		t.Fatalf("expected spend at height 1, got %v",
+			detail.SpendingHeight)
+	}
+	if len(chain.Mempool()) != 0 {
+		t.Fatalf("mempool not cleared by block")
+	}
+}
+
+func TestWalletPublish(t *testing.T) {
+	chain := NewChain()
+	wallet := NewWallet(chain)
+
+	tx := spendingTx(wire.OutPoint{Index: 2}, 1000)
+	if err := wallet.PublishTransaction(tx, "sweep"); err != nil {
+		t.Fatalf("unable to publish tx: %v", err)
+	}
+	txid := tx.TxSha()
+	if label, ok := wallet.Label(&txid); !ok || label != "sweep" {
+		t.Fatalf("expected label sweep, got %v", label)
+	}
+	if len(chain.Mempool()) != 1 {
+		t.Fatalf("published tx not in mempool")
+	}
+
+	errPublish := errors.New("publish failed")
+	wallet.SetPublishError(errPublish)
+	if err := wallet.PublishTransaction(spendingTx(wire.OutPoint{}, 1), ""); err != errPublish {
+		t.Fatalf("expected publish error, got %v", err)
+	}
+	if len(wallet.Published()) != 1 {
+		t.Fatalf("failed publish recorded")
+	}
+
+	// Scripts are deterministic across wallets, yet unique within one.
+	script1, _ := wallet.NewScript()
+	script2, _ := wallet.NewScript()
+	otherScript, _ := NewWallet(chain).NewScript()
+	if string(script1) == string(script2) {
+		t.Fatalf("scripts repeated")
+	}
+	if string(script1) != string(otherScript) {
+		t.Fatalf("scripts not deterministic")
+	}
+}
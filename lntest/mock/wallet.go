@@ -0,0 +1,102 @@
+package mock
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// Wallet stands in for the wallet within unit tests, publishing transactions
+// to a Chain, and handing out deterministic output scripts. Its methods are
+// passed in place of those of the wallet, such as the publishing, and sweep
+// script generation, functions of the sweeper.
+type Wallet struct {
+	mtx sync.Mutex
+
+	chain *Chain
+
+	// publishErr, if set, is returned by each attempt to publish a
+	// transaction, which is then neither recorded, nor passed to the
+	// chain.
+	publishErr error
+
+	published  []*wire.MsgTx
+	labels     map[wire.ShaHash]string
+	numScripts uint64
+}
+
+// NewWallet returns a wallet publishing transactions to the mempool of the
+// passed chain.
+func NewWallet(chain *Chain) *Wallet {
+	return &Wallet{
+		chain:  chain,
+		labels: make(map[wire.ShaHash]string),
+	}
+}
+
+// PublishTransaction records the transaction under the label, then adds it
+// to the mempool of the chain.
+func (w *Wallet) PublishTransaction(tx *wire.MsgTx, label string) error {
+	w.mtx.Lock()
+	if w.publishErr != nil {
+		err := w.publishErr
+		w.mtx.Unlock()
+		return err
+	}
+	w.published = append(w.published, tx)
+	w.labels[tx.TxSha()] = label
+	w.mtx.Unlock()
+
+	return w.chain.AddToMempool(tx)
+}
+
+// SetPublishError causes each later attempt to publish a transaction to
+// fail with err, until reset with nil.
+func (w *Wallet) SetPublishError(err error) {
+	w.mtx.Lock()
+	w.publishErr = err
+	w.mtx.Unlock()
+}
+
+// Published returns each transaction published, in the order they were
+// published.
+func (w *Wallet) Published() []*wire.MsgTx {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	published := make([]*wire.MsgTx, len(w.published))
+	copy(published, w.published)
+	return published
+}
+
+// Label returns the label the transaction was published with, or false if
+// it hasn't been published.
+func (w *Wallet) Label(txid *wire.ShaHash) (string, bool) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	label, ok := w.labels[*txid]
+	return label, ok
+}
+
+// NewScript returns a new pay-to-pubkey-hash output script, derived from the
+// number of scripts handed out so far, so that each test run sees the same
+// scripts in the same order.
+func (w *Wallet) NewScript() ([]byte, error) {
+	w.mtx.Lock()
+	w.numScripts++
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], w.numScripts)
+	w.mtx.Unlock()
+
+	addr, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(seed[:]),
+		&chaincfg.SimNetParams)
+	if err != nil {
+		return nil, err
+	}
+	return txscript.PayToAddrScript(addr)
+}
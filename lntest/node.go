@@ -0,0 +1,267 @@
+package lntest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcrpcclient"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// basePort is the port from which the listening ports of each node
+	// are allocated.
+	basePort = 19555
+
+	// nodeStartTimeout is how long a node has to start serving rpc
+	// requests once launched.
+	nodeStartTimeout = 30 * time.Second
+)
+
+var (
+	// numNodes is the number of nodes created so far, used to allocate
+	// each a unique name, and ports.
+	numNodes int32
+)
+
+// nodeConfig is the configuration of a node, from which the arguments lnd
+// is launched with are derived.
+type nodeConfig struct {
+	name    string
+	dataDir string
+
+	p2pPort int
+	rpcPort int
+
+	// The btcd rpc server the node uses as its chain backend.
+	backend btcrpcclient.ConnConfig
+
+	extraArgs []string
+}
+
+// certPath returns the path of the TLS certificate of the node's rpc server.
+func (c *nodeConfig) certPath() string {
+	return filepath.Join(c.dataDir, "tls.cert")
+}
+
+// backendCertPath returns the path the TLS certificate of the chain backend
+// is written to for the node.
+func (c *nodeConfig) backendCertPath() string {
+	return filepath.Join(c.dataDir, "btcd.cert")
+}
+
+// args returns the arguments lnd is launched with.
+func (c *nodeConfig) args() []string {
+	args := []string{
+		"-simnet",
+		"-datadir=" + c.dataDir,
+		"-peerport=" + strconv.Itoa(c.p2pPort),
+		"-rpcport=" + strconv.Itoa(c.rpcPort),
+		"-btcdhost=" + c.backend.Host,
+		"-btcduser=" + c.backend.User,
+		"-btcdpass=" + c.backend.Pass,
+		"-btcdcert=" + c.backendCertPath(),
+	}
+	return append(args, c.extraArgs...)
+}
+
+// HarnessNode is an lnd process within the test network, along with an rpc
+// client connected to it.
+type HarnessNode struct {
+	cfg *nodeConfig
+
+	// PubKey is the identity public key of the node, and PubKeyStr its
+	// hex encoding.
+	PubKey    *btcec.PublicKey
+	PubKeyStr string
+
+	cmd *exec.Cmd
+
+	conn *grpc.ClientConn
+	lnrpc.LightningClient
+
+	// stopping is set once the node is being stopped, so its exit isn't
+	// reported as an error.
+	stopping int32
+
+	// processExit is closed once the lnd process has exited.
+	processExit chan struct{}
+}
+
+// newNode creates a node using the passed chain backend, without launching
+// it.
+func newNode(backend btcrpcclient.ConnConfig,
+	extraArgs []string) (*HarnessNode, error) {
+
+	id := atomic.AddInt32(&numNodes, 1)
+	name := fmt.Sprintf("node-%d", id)
+
+	dataDir, err := ioutil.TempDir("", "lntest-"+name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &nodeConfig{
+		name:      name,
+		dataDir:   dataDir,
+		p2pPort:   basePort + int(id)*2,
+		rpcPort:   basePort + int(id)*2 + 1,
+		backend:   backend,
+		extraArgs: extraArgs,
+	}
+	if err := ioutil.WriteFile(cfg.backendCertPath(),
+		backend.Certificates, 0644); err != nil {
+		os.RemoveAll(dataDir)
+		return nil, err
+	}
+
+	return &HarnessNode{cfg: cfg}, nil
+}
+
+// Name returns the name of the node within the test network.
+func (n *HarnessNode) Name() string {
+	return n.cfg.name
+}
+
+// P2PAddr returns the address other nodes connect to the node with, in the
+// pubkey@host form expected by ConnectPeer.
+func (n *HarnessNode) P2PAddr() string {
+	host := net.JoinHostPort("127.0.0.1", strconv.Itoa(n.cfg.p2pPort))
+	return n.PubKeyStr + "@" + host
+}
+
+// start launches the lnd process, waits for its rpc server to come up, then
+// connects to it. Should the process exit before being stopped, the error
+// is sent on errChan.
+func (n *HarnessNode) start(lndBinary string, errChan chan<- error) error {
+	atomic.StoreInt32(&n.stopping, 0)
+	n.processExit = make(chan struct{})
+
+	logFile, err := os.Create(filepath.Join(n.cfg.dataDir, "output.log"))
+	if err != nil {
+		return err
+	}
+
+	n.cmd = exec.Command(lndBinary, n.cfg.args()...)
+	n.cmd.Stdout = logFile
+	n.cmd.Stderr = logFile
+	if err := n.cmd.Start(); err != nil {
+		logFile.Close()
+		return err
+	}
+
+	go func() {
+		err := n.cmd.Wait()
+		logFile.Close()
+		if atomic.LoadInt32(&n.stopping) == 0 {
+			select {
+			case errChan <- fmt.Errorf("%v exited unexpectedly: "+
+				"%v, see %v", n.cfg.name, err, logFile.Name()):
+			default:
+			}
+		}
+		close(n.processExit)
+	}()
+
+	if err := n.connectRPC(); err != nil {
+		n.stop()
+		return err
+	}
+
+	info, err := n.GetInfo(context.Background(), &lnrpc.GetInfoRequest{})
+	if err != nil {
+		n.stop()
+		return err
+	}
+	pubKeyBytes, err := hex.DecodeString(info.IdentityPubkey)
+	if err != nil {
+		n.stop()
+		return err
+	}
+	n.PubKey, err = btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		n.stop()
+		return err
+	}
+	n.PubKeyStr = info.IdentityPubkey
+
+	return nil
+}
+
+// connectRPC waits for the node to generate its TLS certificate, then
+// connects to its rpc server, retrying until it's up.
+func (n *HarnessNode) connectRPC() error {
+	deadline := time.After(nodeStartTimeout)
+	for {
+		if _, err := os.Stat(n.cfg.certPath()); err == nil {
+			break
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-n.processExit:
+			return fmt.Errorf("%v exited before starting", n.cfg.name)
+		case <-deadline:
+			return fmt.Errorf("%v didn't create its tls cert in "+
+				"time", n.cfg.name)
+		}
+	}
+
+	creds, err := credentials.NewClientTLSFromFile(n.cfg.certPath(), "")
+	if err != nil {
+		return err
+	}
+	rpcAddr := net.JoinHostPort("localhost", strconv.Itoa(n.cfg.rpcPort))
+	conn, err := grpc.Dial(rpcAddr, grpc.WithTransportCredentials(creds),
+		grpc.WithBlock(), grpc.WithTimeout(nodeStartTimeout))
+	if err != nil {
+		return fmt.Errorf("unable to connect to %v: %v", n.cfg.name, err)
+	}
+
+	n.conn = conn
+	n.LightningClient = lnrpc.NewLightningClient(conn)
+	return nil
+}
+
+// stop kills the lnd process, waiting for it to exit.
+func (n *HarnessNode) stop() error {
+	if n.cmd == nil || n.processExit == nil {
+		return nil
+	}
+	atomic.StoreInt32(&n.stopping, 1)
+
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+
+	select {
+	case <-n.processExit:
+		return nil
+	default:
+	}
+
+	if err := n.cmd.Process.Kill(); err != nil {
+		return err
+	}
+	<-n.processExit
+
+	return nil
+}
+
+// cleanup removes the data directory of the node.
+func (n *HarnessNode) cleanup() error {
+	return os.RemoveAll(n.cfg.dataDir)
+}
@@ -0,0 +1,150 @@
+package lnwallet
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	btcwallet "github.com/btcsuite/btcwallet/wallet"
+)
+
+// BumpCommitmentFee creates a child transaction which spends our anchor
+// output on the passed commitment transaction, along with enough of our own
+// coins to pay the passed fee. Once broadcast, the fee paid by the child will
+// pull the stuck commitment transaction into a block (CPFP). The returned
+// transaction is fully signed.
+func (l *LightningWallet) BumpCommitmentFee(channel *channeldb.OpenChannel,
+	commitTx *wire.MsgTx, fee btcutil.Amount) (*wire.MsgTx, error) {
+
+	if channel.CommitType != channeldb.CommitmentAnchors {
+		return nil, fmt.Errorf("channel has no anchor outputs")
+	}
+
+	// Our anchor is locked to our commitment key, whether this is our
+	// commitment transaction or theirs.
+	anchorRedeemScript, err := anchorScript(channel.OurCommitKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	anchorPkScript, err := scriptHashPkScript(anchorRedeemScript)
+	if err != nil {
+		return nil, err
+	}
+	found, anchorIndex := findScriptOutputIndex(commitTx, anchorPkScript)
+	if !found {
+		return nil, fmt.Errorf("unable to find anchor output")
+	}
+
+	// Select enough coins to pay the fee, less the value of the anchor
+	// itself.
+	// TODO: account for the size of the parent when picking the
+	// fee
+	l.coinSelectMtx.Lock()
+	unspentOutputs, err := l.ListUnspent(1, math.MaxInt32, nil)
+	if err != nil {
+		l.coinSelectMtx.Unlock()
+		return nil, err
+	}
+	coins, err := outputsToCoins(unspentOutputs)
+	if err != nil {
+		l.coinSelectMtx.Unlock()
+		return nil, err
+	}
+	selector := &coinset.MaxValueAgeCoinSelector{
+		MaxInputs:       10,
+		MinChangeAmount: 10000,
+	}
+	selectedCoins, err := selector.CoinSelect(fee, coins)
+	if err != nil {
+		l.coinSelectMtx.Unlock()
+		return nil, err
+	}
+	for _, coin := range selectedCoins.Coins() {
+		l.LockOutpoint(*wire.NewOutPoint(coin.Hash(), coin.Index()))
+	}
+	l.coinSelectMtx.Unlock()
+
+	commitTxID := commitTx.TxSha()
+	sweepTx := wire.NewMsgTx()
+	sweepTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&commitTxID, anchorIndex), nil))
+	for _, coin := range selectedCoins.Coins() {
+		sweepTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(coin.Hash(),
+			coin.Index()), nil))
+	}
+
+	// Everything besides the fee is sent back to ourselves.
+	changeAddr, err := l.NewChangeAddress(waddrmgr.DefaultAccountNum)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, err
+	}
+	selectedTotal := coinset.NewCoinSet(selectedCoins.Coins()).TotalValue()
+	changeAmt := selectedTotal + anchorSize - fee
+	sweepTx.AddTxOut(wire.NewTxOut(int64(changeAmt), changeScript))
+
+	// Sign the anchor input with our commitment key, then the remaining
+	// inputs with the keys held by the wallet.
+	anchorSig, err := txscript.RawTxInSignature(sweepTx, 0,
+		anchorRedeemScript, txscript.SigHashAll, channel.OurCommitKey)
+	if err != nil {
+		return nil, err
+	}
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(anchorSig)
+	builder.AddData(anchorRedeemScript)
+	sweepTx.TxIn[0].SignatureScript, err = builder.Script()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, coin := range selectedCoins.Coins() {
+		sigScript, err := l.signWalletInput(sweepTx, i+1, coin.PkScript())
+		if err != nil {
+			return nil, err
+		}
+		sweepTx.TxIn[i+1].SignatureScript = sigScript
+	}
+
+	return sweepTx, nil
+}
+
+// signWalletInput generates a signature script for the input at the passed
+// index, which spends the wallet controlled P2PKH output with the passed
+// public key script.
+func (l *LightningWallet) signWalletInput(tx *wire.MsgTx, idx int,
+	pkScript []byte) ([]byte, error) {
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, ActiveNetParams)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) != 1 {
+		return nil, btcwallet.ErrUnsupportedTransactionType
+	}
+	apkh, ok := addrs[0].(*btcutil.AddressPubKeyHash)
+	if !ok {
+		return nil, btcwallet.ErrUnsupportedTransactionType
+	}
+
+	ai, err := l.Manager.Address(apkh)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get address info: %v", err)
+	}
+	pka := ai.(waddrmgr.ManagedPubKeyAddress)
+	privkey, err := pka.PrivKey()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get private key: %v", err)
+	}
+
+	return txscript.SignatureScript(tx, idx, pkScript, txscript.SigHashAll,
+		privkey, ai.Compressed())
+}
@@ -0,0 +1,166 @@
+package lnwallet
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// AnnouncementConfs is the number of confirmations the funding transaction
+// of a channel must have before the channel is announced to the network, so
+// that the announcement survives all but the deepest of reorgs.
+const AnnouncementConfs = 6
+
+// locateFundingTx returns the short channel ID locating the funding output
+// of the channel within the chain, once its funding transaction has
+// confirmed.
+func (l *LightningWallet) locateFundingTx(
+	state *channeldb.OpenChannel) (lnwire.ShortChannelID, error) {
+
+	var scid lnwire.ShortChannelID
+
+	txid := state.FundingTx.TxSha()
+	txDetail, err := l.TxStore.TxDetails(&txid)
+	if err != nil {
+		return scid, err
+	}
+	if txDetail == nil || txDetail.Block.Height == -1 {
+		return scid, fmt.Errorf("funding tx %v isn't confirmed", txid)
+	}
+
+	block, err := l.GetBlock(&txDetail.Block.Hash)
+	if err != nil {
+		return scid, err
+	}
+	txIndex := -1
+	for i, tx := range block.Transactions {
+		if tx.TxSha() == txid {
+			txIndex = i
+			break
+		}
+	}
+	if txIndex == -1 {
+		return scid, fmt.Errorf("funding tx %v isn't within block %v",
+			txid, txDetail.Block.Hash)
+	}
+
+	chanPoint, err := fundingOutPoint(state)
+	if err != nil {
+		return scid, err
+	}
+
+	return lnwire.ShortChannelID{
+		BlockHeight: uint32(txDetail.Block.Height),
+		TxIndex:     uint32(txIndex),
+		TxPosition:  uint16(chanPoint.Index),
+	}, nil
+}
+
+// fundingOutPoint returns the outpoint of the funding output of the channel.
+func fundingOutPoint(state *channeldb.OpenChannel) (*wire.OutPoint, error) {
+	pkScript, err := scriptHashPkScript(state.FundingRedeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	txid := state.FundingTx.TxSha()
+	found, index := findScriptOutputIndex(state.FundingTx, pkScript)
+	if !found {
+		return nil, fmt.Errorf("funding tx %v has no funding output",
+			txid)
+	}
+
+	return wire.NewOutPoint(&txid, index), nil
+}
+
+// waitForAnnouncementDepth signals the reservation once its funding
+// transaction has reached AnnouncementConfs confirmations.
+func (l *LightningWallet) waitForAnnouncementDepth(res *ChannelReservation) {
+	trigger := &chainntnfs.NotificationTrigger{
+		TriggerChan: make(chan struct{}, 1),
+	}
+	txid := res.partialState.FundingTx.TxSha()
+	err := l.chainNotifier.RegisterConfirmationsNotification(&txid,
+		AnnouncementConfs, trigger)
+	if err != nil {
+		fmt.Printf("unable to wait for announcement depth of %v: %v\n",
+			txid, err)
+		return
+	}
+
+	select {
+	case <-trigger.TriggerChan:
+		res.chanAnnounceable <- struct{}{}
+	case <-l.quit:
+	}
+}
+
+// setShortChanID records the location of the funding output of the channel,
+// once its funding transaction has confirmed.
+func (lc *LightningChannel) setShortChanID(scid lnwire.ShortChannelID) error {
+	lc.stateMtx.Lock()
+	defer lc.stateMtx.Unlock()
+
+	lc.channelState.ShortChanID = scid.ToUint64()
+	return lc.channelDB.PutOpenChannel(lc.channelState)
+}
+
+// ChannelPoint returns the outpoint of the funding output of the channel.
+func (lc *LightningChannel) ChannelPoint() (*wire.OutPoint, error) {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return fundingOutPoint(lc.channelState)
+}
+
+// IsPrivate returns true if the channel is never announced to the network.
+func (lc *LightningChannel) IsPrivate() bool {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return lc.channelState.Private
+}
+
+// FundingKeys returns our key, and the counterparty's, within the 2-of-2
+// multisig funding output of the channel.
+func (lc *LightningChannel) FundingKeys() (*btcec.PublicKey, *btcec.PublicKey, error) {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	pushes, err := txscript.PushedData(lc.channelState.FundingRedeemScript)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pushes) != 2 {
+		return nil, nil, fmt.Errorf("funding script pushes %v keys",
+			len(pushes))
+	}
+
+	ourKey := lc.channelState.MultiSigKey.PubKey()
+	theirKeyBytes := pushes[0]
+	if bytes.Equal(theirKeyBytes, ourKey.SerializeCompressed()) {
+		theirKeyBytes = pushes[1]
+	}
+	theirKey, err := btcec.ParsePubKey(theirKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ourKey, theirKey, nil
+}
+
+// SignAnnouncement signs the digest of the announcement of the channel with
+// our key within its funding output, proving we control half of it.
+func (lc *LightningChannel) SignAnnouncement(digest []byte) (*btcec.Signature, error) {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return lc.channelState.MultiSigKey.Sign(digest)
+}
@@ -0,0 +1,241 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/chain"
+)
+
+const (
+	// defaultBackendHealthInterval is how often the backend we're
+	// connected to is checked, should the config not specify an interval.
+	defaultBackendHealthInterval = 30 * time.Second
+
+	// backendHealthTimeout is how long a backend has to report its best
+	// block before the check fails.
+	backendHealthTimeout = 10 * time.Second
+
+	// maxBackendFailures is the number of consecutive failed checks after
+	// which we fail over to another backend.
+	maxBackendFailures = 3
+)
+
+// errBackendTimeout is returned when a backend fails to respond to a health
+// check in time.
+var errBackendTimeout = errors.New("chain backend timed out")
+
+// ChainBackend is the rpc endpoint of a full node we're able to use as our
+// chain backend.
+type ChainBackend struct {
+	Host string
+	User string
+	Pass string
+
+	// CACert is the PEM encoded certificate, or certificates, the TLS
+	// certificate of the backend is verified against.
+	CACert []byte
+}
+
+// chainBackends returns the backends we may connect to, in order of
+// preference: the primary backend of the config, followed by its fallbacks.
+func (c *Config) chainBackends() []ChainBackend {
+	primary := ChainBackend{
+		Host:   c.RPCHost,
+		User:   c.RPCUser,
+		Pass:   c.RPCPass,
+		CACert: c.CACert,
+	}
+	return append([]ChainBackend{primary}, c.FallbackBackends...)
+}
+
+// chainClient returns the client of the backend we're connected to, or nil if
+// the wallet is yet to be started.
+func (l *LightningWallet) chainClient() *chain.Client {
+	l.rpcMtx.RLock()
+	defer l.rpcMtx.RUnlock()
+
+	return l.rpc
+}
+
+// ActiveBackend returns the host of the chain backend we're connected to.
+func (l *LightningWallet) ActiveBackend() string {
+	l.rpcMtx.RLock()
+	defer l.rpcMtx.RUnlock()
+
+	return l.cfg.chainBackends()[l.activeBackend].Host
+}
+
+// connectBackend connects to the first of our backends which responds,
+// trying each in order of preference, starting from the one at index start.
+// The client of the backend is returned along with its index.
+func (l *LightningWallet) connectBackend(start int) (*chain.Client, int, error) {
+	backends := l.cfg.chainBackends()
+
+	var lastErr error
+	for i := 0; i < len(backends); i++ {
+		index := (start + i) % len(backends)
+		backend := backends[index]
+
+		client, err := chain.NewClient(ActiveNetParams, backend.Host,
+			backend.User, backend.Pass, backend.CACert, false)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := client.Start(); err != nil {
+			lastErr = err
+			continue
+		}
+		if err := checkBackend(client); err != nil {
+			client.Shutdown()
+			lastErr = err
+			continue
+		}
+
+		return client, index, nil
+	}
+
+	return nil, 0, fmt.Errorf("unable to connect to any chain backend: %v",
+		lastErr)
+}
+
+// checkBackend returns an error if the backend fails to report its best block
+// within backendHealthTimeout.
+func checkBackend(client *chain.Client) error {
+	errChan := make(chan error, 1)
+	go func() {
+		_, _, err := client.GetBestBlock()
+		errChan <- err
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(backendHealthTimeout):
+		return errBackendTimeout
+	}
+}
+
+// startBackendMonitor launches the goroutine checking the health of the
+// backend we're connected to, should we have others to fail over to.
+func (l *LightningWallet) startBackendMonitor() {
+	if len(l.cfg.FallbackBackends) == 0 {
+		return
+	}
+
+	l.wg.Add(1)
+	go l.backendMonitor()
+}
+
+// backendMonitor checks the backend we're connected to periodically, failing
+// over to another once maxBackendFailures consecutive checks fail.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) backendMonitor() {
+	defer l.wg.Done()
+
+	interval := l.cfg.BackendHealthInterval
+	if interval == 0 {
+		interval = defaultBackendHealthInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ticker.C:
+			err := checkBackend(l.chainClient())
+			if err == nil {
+				failures = 0
+				continue
+			}
+
+			failures++
+			fmt.Printf("chain backend %v failed health check "+
+				"(%v/%v): %v\n", l.ActiveBackend(), failures,
+				maxBackendFailures, err)
+			if failures < maxBackendFailures {
+				continue
+			}
+
+			// Should no backend respond, we try again with the
+			// next check.
+			if err := l.failover(); err != nil {
+				fmt.Printf("unable to fail over: %v\n", err)
+				continue
+			}
+			failures = 0
+
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// failover switches us over to the next of our backends which responds,
+// resynchronizing the wallet, and with it our chain notifications, against
+// it.
+func (l *LightningWallet) failover() error {
+	l.rpcMtx.RLock()
+	next := l.activeBackend + 1
+	l.rpcMtx.RUnlock()
+
+	client, index, err := l.connectBackend(next)
+	if err != nil {
+		return err
+	}
+
+	l.rpcMtx.Lock()
+	old := l.rpc
+	l.rpc = client
+	l.activeBackend = index
+	l.rpcMtx.Unlock()
+
+	old.Shutdown()
+
+	fmt.Printf("failed over to chain backend %v\n", l.ActiveBackend())
+
+	// The wallet registers its own addresses, and the funding outputs
+	// we've imported, with the new backend as it resynchronizes. Those of
+	// our watch-only accounts, and those watched on behalf of rpc
+	// clients, are registered by us.
+	l.SynchronizeRPC(client)
+	if err := l.renotifyWatchOnly(); err != nil {
+		return err
+	}
+	if err := l.renotifyChainWatches(); err != nil {
+		return err
+	}
+
+	// The new backend may never have seen our pending transactions, so
+	// they're published to it straight away.
+	l.broadcastMtx.Lock()
+	l.rebroadcastAll()
+	l.broadcastMtx.Unlock()
+
+	return nil
+}
+
+// renotifyWatchOnly registers the addresses of all keys of our watch-only
+// accounts with the backend we're connected to.
+func (l *LightningWallet) renotifyWatchOnly() error {
+	l.watchOnlyMtx.Lock()
+	defer l.watchOnlyMtx.Unlock()
+
+	var addrs []btcutil.Address
+	for pkScript := range l.watchedScripts {
+		_, scriptAddrs, _, err := txscript.ExtractPkScriptAddrs(
+			[]byte(pkScript), ActiveNetParams)
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, scriptAddrs...)
+	}
+
+	return l.notifyWatchOnlyAddrs(addrs, false)
+}
@@ -0,0 +1,56 @@
+package lnwallet
+
+import (
+	"github.com/lightningnetwork/lnd/blockcache"
+	"github.com/lightningnetwork/lnd/chainntfs"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// GetBlock returns the block of the passed hash. Blocks are fetched through
+// the wallet's block cache, so subsystems concurrently processing the same
+// blocks only fetch each block from the backend once.
+func (l *LightningWallet) GetBlock(hash *wire.ShaHash) (*wire.MsgBlock, error) {
+	return l.blockCache.GetBlock(hash, l.fetchBlock)
+}
+
+// GetBestBlock returns the hash, and height, of the tip of the main chain.
+func (l *LightningWallet) GetBestBlock() (*wire.ShaHash, int32, error) {
+	return l.chainClient().GetBestBlock()
+}
+
+// GetBlockHash returns the hash of the block at the passed height within the
+// main chain.
+func (l *LightningWallet) GetBlockHash(height int64) (*wire.ShaHash, error) {
+	return l.chainClient().GetBlockHash(height)
+}
+
+// IsUnspent returns true if the outpoint exists, and is yet to be spent by
+// either a confirmed or an unconfirmed transaction.
+func (l *LightningWallet) IsUnspent(op *wire.OutPoint) (bool, error) {
+	txOut, err := l.chainClient().GetTxOut(&op.Hash, op.Index, true)
+	if err != nil {
+		return false, err
+	}
+	return txOut != nil, nil
+}
+
+// RegisterBlockEpochNotification registers a channel which is sent each new
+// block as it's connected to the main chain.
+func (l *LightningWallet) RegisterBlockEpochNotification(epochChan chan *chainntnfs.BlockEpoch) error {
+	return l.chainNotifier.RegisterBlockEpochNotification(epochChan)
+}
+
+// BlockCacheStats returns the hit rate, and size of the block cache.
+func (l *LightningWallet) BlockCacheStats() blockcache.Stats {
+	return l.blockCache.Stats()
+}
+
+// fetchBlock fetches the block of the passed hash from the backend.
+func (l *LightningWallet) fetchBlock(hash *wire.ShaHash) (*wire.MsgBlock, error) {
+	block, err := l.chainClient().GetBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	return block.MsgBlock(), nil
+}
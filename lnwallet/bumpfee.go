@@ -0,0 +1,173 @@
+package lnwallet
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// BumpFee raises the effective fee rate of the unconfirmed transaction which
+// created the passed wallet controlled output, such as the change of a
+// funding transaction, or our output of a cooperative close. A child
+// transaction spending the output back to the wallet is broadcast, pulling
+// in more of our coins if the output alone can't pay the fee. The child pays
+// enough for the parent and child together to reach the passed fee rate
+// (CPFP). The child transaction is returned, and is re-published until it
+// confirms.
+//
+// If the parent spends outputs which don't belong to the wallet, its own fee
+// is unknown, and is assumed to be zero.
+//
+// TODO: replace the parent instead (RBF) once the backend relays
+// replacements
+func (l *LightningWallet) BumpFee(op *wire.OutPoint,
+	feePerKb btcutil.Amount) (*wire.MsgTx, error) {
+
+	txDetail, err := l.TxStore.TxDetails(&op.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if txDetail == nil {
+		return nil, fmt.Errorf("unable to find transaction %v", op.Hash)
+	}
+	if txDetail.Block.Height != -1 {
+		return nil, ErrTxConfirmed
+	}
+
+	parentTx := &txDetail.TxRecord.MsgTx
+	if int(op.Index) >= len(parentTx.TxOut) {
+		return nil, fmt.Errorf("transaction %v has no output %v",
+			op.Hash, op.Index)
+	}
+	parentOut := parentTx.TxOut[op.Index]
+
+	var parentFee btcutil.Amount
+	if len(txDetail.Debits) == len(parentTx.TxIn) {
+		for _, debit := range txDetail.Debits {
+			parentFee += debit.Amount
+		}
+		for _, txOut := range parentTx.TxOut {
+			parentFee -= btcutil.Amount(txOut.Value)
+		}
+	}
+
+	// The child must pay for the size of both transactions, less what the
+	// parent already pays, but never less than its own share.
+	parentSize := parentTx.SerializeSize()
+	childFee := func(numInputs int) btcutil.Amount {
+		childSize := txOverheadSize + numInputs*p2pkhInputSize +
+			p2pkhOutputSize
+		fee := feePerKb*btcutil.Amount(parentSize+childSize)/1000 -
+			parentFee
+		if minFee := feePerKb * btcutil.Amount(childSize) / 1000; fee < minFee {
+			fee = minFee
+		}
+		return fee
+	}
+
+	l.coinSelectMtx.Lock()
+	if l.LockedOutpoint(*op) {
+		l.coinSelectMtx.Unlock()
+		return nil, fmt.Errorf("output %v is already being spent", op)
+	}
+
+	// Only if the output can't cover the fee by itself do we add more of
+	// our coins.
+	var selectedCoins []coinset.Coin
+	parentValue := btcutil.Amount(parentOut.Value)
+	if parentValue < childFee(1)+minChangeAmount {
+		unspentOutputs, err := l.ListUnspent(1, math.MaxInt32, nil)
+		if err != nil {
+			l.coinSelectMtx.Unlock()
+			return nil, err
+		}
+		coins, err := outputsToCoins(unspentOutputs)
+		if err != nil {
+			l.coinSelectMtx.Unlock()
+			return nil, err
+		}
+
+		selector := &coinset.MaxValueAgeCoinSelector{
+			MaxInputs:       100,
+			MinChangeAmount: 0,
+		}
+		for numInputs := 1; ; {
+			target := childFee(numInputs+1) + minChangeAmount - parentValue
+			selected, err := selector.CoinSelect(target, coins)
+			if err != nil {
+				l.coinSelectMtx.Unlock()
+				return nil, ErrInsufficientFunds
+			}
+			selectedCoins = selected.Coins()
+			if len(selectedCoins) <= numInputs {
+				break
+			}
+			numInputs = len(selectedCoins)
+		}
+	}
+
+	childTx := wire.NewMsgTx()
+	childTx.AddTxIn(wire.NewTxIn(op, nil))
+	for _, coin := range selectedCoins {
+		childTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(coin.Hash(),
+			coin.Index()), nil))
+	}
+	for _, txIn := range childTx.TxIn {
+		l.LockOutpoint(txIn.PreviousOutPoint)
+	}
+	l.coinSelectMtx.Unlock()
+
+	// If we fail to broadcast the child, release its inputs so they may be
+	// spent again.
+	success := false
+	defer func() {
+		if success {
+			return
+		}
+		l.coinSelectMtx.Lock()
+		for _, txIn := range childTx.TxIn {
+			l.UnlockOutpoint(txIn.PreviousOutPoint)
+		}
+		l.coinSelectMtx.Unlock()
+	}()
+
+	changeAddr, err := l.NewChangeAddress(waddrmgr.DefaultAccountNum)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, err
+	}
+	inputTotal := parentValue +
+		coinset.NewCoinSet(selectedCoins).TotalValue()
+	changeAmt := inputTotal - childFee(len(childTx.TxIn))
+	childTx.AddTxOut(wire.NewTxOut(int64(changeAmt), changeScript))
+
+	pkScripts := [][]byte{parentOut.PkScript}
+	for _, coin := range selectedCoins {
+		pkScripts = append(pkScripts, coin.PkScript())
+	}
+	for i, pkScript := range pkScripts {
+		sigScript, err := l.signWalletInput(childTx, i, pkScript)
+		if err != nil {
+			return nil, err
+		}
+		childTx.TxIn[i].SignatureScript = sigScript
+	}
+
+	// If the backend can't be reached, the child is published once it
+	// reconnects, so its inputs must remain locked.
+	err = l.PublishTransaction(childTx, "cpfp")
+	if err != nil && !isBackendError(err) {
+		return nil, err
+	}
+	success = true
+
+	return childTx, nil
+}
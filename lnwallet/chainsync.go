@@ -0,0 +1,114 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+)
+
+// chainSyncCheckInterval is how often we check whether we've caught up to the
+// backend's best block, while syncing.
+const chainSyncCheckInterval = 5 * time.Second
+
+// ErrNotSynced is returned when attempting to open a channel, or send a
+// payment, before the wallet, and our chain notifications, have caught up to
+// the best block of the backend.
+var ErrNotSynced = errors.New("not yet synced to the chain")
+
+// ChainSynced returns a channel which is closed once the wallet, and our chain
+// notifications, have caught up to the best block of the backend after
+// starting.
+func (l *LightningWallet) ChainSynced() <-chan struct{} {
+	return l.chainSynced
+}
+
+// IsSynced returns true once the wallet, and our chain notifications, have
+// caught up to the best block of the backend after starting.
+func (l *LightningWallet) IsSynced() bool {
+	select {
+	case <-l.chainSynced:
+		return true
+	default:
+		return false
+	}
+}
+
+// startChainSync launches the goroutine tracking whether we've caught up to
+// the backend. Should we already have caught up, we're synced before it
+// returns.
+func (l *LightningWallet) startChainSync() error {
+	epochChan := make(chan *chainntnfs.BlockEpoch, 20)
+	if err := l.chainNotifier.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+
+	// Our notifications are driven by the wallet, so until the notifier
+	// hands us a block, it's seen as much of the chain as the wallet.
+	notifiedHeight := l.Manager.SyncedTo().Height
+	synced, err := l.checkChainSync(notifiedHeight)
+	if err != nil {
+		return err
+	}
+	if synced {
+		close(l.chainSynced)
+	}
+
+	l.wg.Add(1)
+	go l.chainSyncWatcher(epochChan, notifiedHeight, synced)
+
+	return nil
+}
+
+// chainSyncWatcher checks whether we've caught up to the backend with each
+// block we're notified of, and each chainSyncCheckInterval, until we have.
+// Blocks are drained from the notifier thereafter.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) chainSyncWatcher(epochChan chan *chainntnfs.BlockEpoch,
+	notifiedHeight int32, synced bool) {
+
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(chainSyncCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case epoch := <-epochChan:
+			notifiedHeight = epoch.Height
+
+		case <-ticker.C:
+
+		case <-l.quit:
+			return
+		}
+
+		if synced {
+			continue
+		}
+
+		var err error
+		synced, err = l.checkChainSync(notifiedHeight)
+		if err != nil {
+			fmt.Printf("unable to check chain sync: %v\n", err)
+			continue
+		}
+		if synced {
+			close(l.chainSynced)
+		}
+	}
+}
+
+// checkChainSync returns true if both the wallet, and our notifications, as
+// of the passed height, have caught up to the best block of the backend.
+func (l *LightningWallet) checkChainSync(notifiedHeight int32) (bool, error) {
+	_, bestHeight, err := l.GetBestBlock()
+	if err != nil {
+		return false, err
+	}
+
+	walletHeight := l.Manager.SyncedTo().Height
+	return walletHeight >= bestHeight && notifiedHeight >= bestHeight, nil
+}
@@ -0,0 +1,84 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/chainntfs"
+)
+
+// RegisterConfirmationsNtfn fires the trigger once the transaction reaches
+// the number of confirmations. The backend only notifies us of transactions
+// paying to addresses we've registered, so the script of one of the
+// transaction's outputs must be passed. Only confirmations of transactions
+// mined after registering are detected.
+func (l *LightningWallet) RegisterConfirmationsNtfn(txid *wire.ShaHash,
+	pkScript []byte, numConfs uint32,
+	trigger *chainntnfs.NotificationTrigger) error {
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript,
+		ActiveNetParams)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("unable to watch non-standard script")
+	}
+
+	if err := l.chainClient().NotifyReceived(addrs); err != nil {
+		return err
+	}
+	l.chainWatchMtx.Lock()
+	l.watchedAddrs = append(l.watchedAddrs, addrs...)
+	l.chainWatchMtx.Unlock()
+
+	return l.chainNotifier.RegisterConfirmationsNotification(txid,
+		numConfs, trigger)
+}
+
+// RegisterSpendNtfn fires the trigger once the outpoint is spent, either as
+// soon as the spending transaction enters the mempool, or once it's mined.
+func (l *LightningWallet) RegisterSpendNtfn(outpoint *wire.OutPoint,
+	mempool bool, trigger *chainntnfs.NotificationTrigger) error {
+
+	outpoints := []*wire.OutPoint{outpoint}
+	if err := l.chainClient().NotifySpent(outpoints); err != nil {
+		return err
+	}
+	l.chainWatchMtx.Lock()
+	l.watchedOutPoints = append(l.watchedOutPoints, outpoint)
+	l.chainWatchMtx.Unlock()
+
+	return l.chainNotifier.RegisterSpendNotification(outpoint, mempool,
+		trigger)
+}
+
+// renotifyChainWatches registers the addresses, and outpoints, watched on
+// behalf of RegisterConfirmationsNtfn and RegisterSpendNtfn with the backend
+// we're connected to.
+//
+// TODO: forget each once its notification fires
+func (l *LightningWallet) renotifyChainWatches() error {
+	l.chainWatchMtx.Lock()
+	addrs := make([]btcutil.Address, len(l.watchedAddrs))
+	copy(addrs, l.watchedAddrs)
+	outpoints := make([]*wire.OutPoint, len(l.watchedOutPoints))
+	copy(outpoints, l.watchedOutPoints)
+	l.chainWatchMtx.Unlock()
+
+	rpc := l.chainClient()
+	if len(addrs) != 0 {
+		if err := rpc.NotifyReceived(addrs); err != nil {
+			return err
+		}
+	}
+	if len(outpoints) != 0 {
+		if err := rpc.NotifySpent(outpoints); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
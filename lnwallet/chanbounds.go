@@ -0,0 +1,98 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	// defaultMaxCsvDelay is the largest CSV delay we'll accept a
+	// counterparty proposing, if the config doesn't specify.
+	defaultMaxCsvDelay = 2016
+
+	// defaultMaxReservePercent is the largest reserve, as a percentage of
+	// the channel's capacity, we'll accept a counterparty requiring, if
+	// the config doesn't specify.
+	defaultMaxReservePercent = 20
+
+	// defaultMaxDustLimit is the largest dust limit we'll accept a
+	// counterparty proposing, if the config doesn't specify.
+	defaultMaxDustLimit = 3 * DefaultDustLimit
+
+	// defaultReservePercent is the reserve, as a percentage of the
+	// channel's capacity, we require the counterparty to keep.
+	defaultReservePercent = 1
+)
+
+// defaultChanReserve returns the reserve we require the counterparty to keep
+// within a channel of the passed capacity, which is never below the dust
+// limit.
+func defaultChanReserve(capacity btcutil.Amount) btcutil.Amount {
+	reserve := capacity * defaultReservePercent / 100
+	if reserve < DefaultDustLimit {
+		return DefaultDustLimit
+	}
+	return reserve
+}
+
+// maxCsvDelay returns the largest CSV delay we'll accept a counterparty
+// proposing.
+func (l *LightningWallet) maxCsvDelay() uint32 {
+	if l.cfg.MaxCsvDelay == 0 {
+		return defaultMaxCsvDelay
+	}
+	return l.cfg.MaxCsvDelay
+}
+
+// maxChanReserve returns the largest reserve we'll accept a counterparty
+// requiring we keep within a channel of the passed capacity.
+func (l *LightningWallet) maxChanReserve(capacity btcutil.Amount) btcutil.Amount {
+	percent := l.cfg.MaxReservePercent
+	if percent == 0 {
+		percent = defaultMaxReservePercent
+	}
+	return capacity * btcutil.Amount(percent) / 100
+}
+
+// maxDustLimit returns the largest dust limit we'll accept a counterparty
+// proposing.
+func (l *LightningWallet) maxDustLimit() btcutil.Amount {
+	if l.cfg.MaxDustLimit == 0 {
+		return defaultMaxDustLimit
+	}
+	return l.cfg.MaxDustLimit
+}
+
+// validateRemoteParams returns an error if the CSV delay, reserve, or dust
+// limit the counterparty proposes within their contribution to a channel of
+// the passed capacity are outside our bounds.
+func (l *LightningWallet) validateRemoteParams(c *ChannelContribution,
+	capacity btcutil.Amount) error {
+
+	if c.CsvDelay == 0 {
+		return fmt.Errorf("csv delay must be positive")
+	}
+	if maxDelay := l.maxCsvDelay(); c.CsvDelay > maxDelay {
+		return fmt.Errorf("csv delay of %v blocks exceeds our maximum "+
+			"of %v", c.CsvDelay, maxDelay)
+	}
+
+	if c.DustLimit < DefaultDustLimit {
+		return fmt.Errorf("dust limit of %v is below the minimum of %v, "+
+			"making their commitment non-standard", c.DustLimit,
+			DefaultDustLimit)
+	}
+	if maxDust := l.maxDustLimit(); c.DustLimit > maxDust {
+		return fmt.Errorf("dust limit of %v exceeds our maximum of %v",
+			c.DustLimit, maxDust)
+	}
+
+	if maxReserve := l.maxChanReserve(capacity); c.ChanReserve > maxReserve {
+		return fmt.Errorf("channel reserve of %v exceeds our maximum "+
+			"of %v for a channel of %v", c.ChanReserve, maxReserve,
+			capacity)
+	}
+
+	return nil
+}
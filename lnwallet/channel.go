@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/lightningnetwork/lnd/chainntfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sigpool"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/txscript"
@@ -20,6 +24,52 @@ const (
 
 	// MaxPendingPayments ...
 	MaxPendingPayments = 10
+
+	// DefaultDustLimit is the value below which an HTLC is dust. Dust
+	// HTLCs aren't given an output within the commitment transactions,
+	// their value instead going towards the commitment fee.
+	DefaultDustLimit = btcutil.Amount(546)
+)
+
+var (
+	// ErrMaxHTLCNumber is returned when adding an HTLC would exceed the
+	// number of pending HTLCs the receiving party accepts.
+	ErrMaxHTLCNumber = fmt.Errorf("commitment transaction exceed max " +
+		"htlc number")
+
+	// ErrMaxPendingAmount is returned when adding an HTLC would exceed the
+	// total value of pending HTLCs the receiving party accepts.
+	ErrMaxPendingAmount = fmt.Errorf("commitment transaction exceed max " +
+		"pending amount")
+
+	// ErrBelowMinHTLC is returned when the counterparty offers an HTLC
+	// smaller than the minimum we accept.
+	ErrBelowMinHTLC = fmt.Errorf("htlc is below the channel's minimum " +
+		"htlc")
+
+	// ErrMaxDustExposure is returned when adding a dust HTLC would take
+	// the total value of dust HTLCs pending within the channel beyond the
+	// configured maximum.
+	ErrMaxDustExposure = fmt.Errorf("commitment transaction exceed max " +
+		"dust exposure")
+
+	// ErrUpdateCancelled is returned when committing a channel update
+	// which was cancelled after stalling.
+	ErrUpdateCancelled = fmt.Errorf("channel update was cancelled")
+
+	// ErrNoPendingUpdate is returned when cancelling the update of a
+	// channel which has none in progress.
+	ErrNoPendingUpdate = fmt.Errorf("no channel update in progress")
+
+	// ErrUnknownPaymentHash is returned when settling an HTLC with a
+	// preimage whose hash matches none of those pending.
+	ErrUnknownPaymentHash = fmt.Errorf("r-hash for preimage not found")
+
+	// ErrForceCloseUnimplemented is returned when force closing a channel,
+	// as we don't yet store the counterparty's signature for our latest
+	// commitment, so have nothing to broadcast.
+	ErrForceCloseUnimplemented = fmt.Errorf("force close not yet " +
+		"implemented")
 )
 
 // PaymentHash presents the hash160 of a random value. This hash is used to
@@ -45,6 +95,12 @@ type LightningChannel struct {
 
 	updateTotem chan struct{}
 
+	// pendingUpdate is the update holding the updateTotem, along with
+	// when it was created, so updates stalled awaiting the counterparty
+	// can be detected. Both are protected by the stateMtx.
+	pendingUpdate *ChannelUpdate
+	updateStarted time.Time
+
 	// Uncleared HTLC's.
 	pendingPayments map[PaymentHash]*PaymentDescriptor
 
@@ -54,6 +110,23 @@ type LightningChannel struct {
 	fundingTxIn *wire.TxIn
 	fundingP2SH []byte
 
+	// maxDustExposure is the total value of dust HTLCs allowed to be
+	// pending within the channel.
+	maxDustExposure btcutil.Amount
+
+	// expiryGraceDelta is the number of blocks before the expiry of an
+	// unsettled incoming HTLC at which the channel is force closed.
+	expiryGraceDelta uint32
+
+	// expiryClosed is set while the channel is being force closed due to
+	// an expiring HTLC, and remains set once it has been, so it's only
+	// done once.
+	expiryClosed int32
+
+	// hodlMask holds the steps of updating the channel which are to be
+	// dropped, for testing.
+	hodlMask hodl.Mask
+
 	// TODO(roasbeef): create and embed 'Service' interface w/ below?
 	started  int32
 	shutdown int32
@@ -62,8 +135,9 @@ type LightningChannel struct {
 	wg   sync.WaitGroup
 }
 
-// newLightningChannel ...
-func newLightningChannel(wallet *LightningWallet, events chainntnfs.ChainNotifier,
+// NewLightningChannel creates a channel around its stored state. The channel
+// is only watched for expiring HTLCs once started.
+func NewLightningChannel(wallet *LightningWallet, events chainntnfs.ChainNotifier,
 	chanDB *channeldb.DB, state *channeldb.OpenChannel) (*LightningChannel, error) {
 
 	lc := &LightningChannel{
@@ -74,11 +148,24 @@ func newLightningChannel(wallet *LightningWallet, events chainntnfs.ChainNotifie
 		updateTotem:        make(chan struct{}, 1),
 		pendingPayments:    make(map[PaymentHash]*PaymentDescriptor),
 		unfufilledPayments: make(map[PaymentHash]*PaymentRequest),
+		quit:               make(chan struct{}),
 	}
 
 	// TODO(roasbeef): do a NotifySpent for the funding input, and
 	// NotifyReceived for all commitment outputs.
 
+	if cfg := wallet.cfg; cfg != nil {
+		lc.maxDustExposure = cfg.MaxDustExposure
+		lc.hodlMask = cfg.HodlMask
+		lc.expiryGraceDelta = cfg.ExpiryGraceDelta
+	}
+	if lc.maxDustExposure == 0 {
+		lc.maxDustExposure = defaultMaxDustExposure
+	}
+	if lc.expiryGraceDelta == 0 {
+		lc.expiryGraceDelta = defaultExpiryGraceDelta
+	}
+
 	// Populate the totem.
 	lc.updateTotem <- struct{}{}
 
@@ -94,11 +181,73 @@ func newLightningChannel(wallet *LightningWallet, events chainntnfs.ChainNotifie
 	return lc, nil
 }
 
+// validateHtlcLimits returns an error if adding an HTLC of the passed value,
+// in the passed direction, would exceed either the number, or total value, of
+// pending HTLCs accepted by the receiving party. A zero limit is treated as
+// unlimited, as channels opened before the limits were negotiated lack them.
+// HTLCs offered to us must also meet our minimum, and a dust HTLC mustn't
+// take the channel beyond its max dust exposure.
+func (lc *LightningChannel) validateHtlcLimits(value lnwire.MilliSatoshi,
+	payToUs bool) error {
+
+	if payToUs && value < lc.channelState.MinHTLC {
+		return ErrBelowMinHTLC
+	}
+
+	if isDustHTLC(value) {
+		dustExposure := value
+		for _, paymentDesc := range lc.pendingPayments {
+			if isDustHTLC(paymentDesc.Value) {
+				dustExposure += paymentDesc.Value
+			}
+		}
+		if dustExposure > lnwire.NewMSatFromSatoshis(lc.maxDustExposure) {
+			return ErrMaxDustExposure
+		}
+	}
+
+	maxAccepted := lc.channelState.TheirMaxAcceptedHtlcs
+	maxValue := lc.channelState.TheirMaxValueInFlight
+	if payToUs {
+		maxAccepted = lc.channelState.OurMaxAcceptedHtlcs
+		maxValue = lc.channelState.OurMaxValueInFlight
+	}
+
+	numHtlcs := 1
+	valueInFlight := value
+	for _, paymentDesc := range lc.pendingPayments {
+		if paymentDesc.PayToUs != payToUs {
+			continue
+		}
+		numHtlcs++
+		valueInFlight += paymentDesc.Value
+	}
+
+	if maxAccepted != 0 && numHtlcs > int(maxAccepted) {
+		return ErrMaxHTLCNumber
+	}
+	if maxValue != 0 && valueInFlight > maxValue {
+		return ErrMaxPendingAmount
+	}
+
+	return nil
+}
+
+// isDustHTLC returns true if an HTLC of the passed value is too small to be
+// given an output within the commitment transactions.
+func isDustHTLC(value lnwire.MilliSatoshi) bool {
+	return value.ToSatoshis() < DefaultDustLimit
+}
+
 // PaymentDescriptor ...
 type PaymentDescriptor struct {
-	RHash   [20]byte
+	// RHash is the payment hash of the HTLC, of the type HashType. The
+	// zero value of HashType is the original HASH160.
+	RHash    [20]byte
+	HashType lnwire.HTLCHashType
+
 	Timeout uint32
-	Value   btcutil.Amount
+	Value   lnwire.MilliSatoshi
 
 	OurRevocation   [20]byte // TODO(roasbeef): don't need these?
 	TheirRevocation [20]byte
@@ -149,17 +298,25 @@ func (c *ChannelUpdate) SignCounterPartyCommitment() ([]byte, error) {
 		return c.sigTheirNewCommit, nil
 	}
 
-	// Sign their version of the commitment transaction.
-	sig, err := txscript.RawTxInSignature(c.theirPendingCommitTx, 0,
-		c.lnChannel.channelState.FundingRedeemScript, txscript.SigHashAll,
-		c.lnChannel.channelState.MultiSigKey)
-	if err != nil {
-		return nil, err
+	// Sign their version of the commitment transaction. The signature is
+	// generated by the wallet's sig pool, alongside those of any other
+	// channels updating at once.
+	job := &sigpool.SignJob{
+		Tx:        c.theirPendingCommitTx,
+		SubScript: c.lnChannel.channelState.FundingRedeemScript,
+		HashType:  txscript.SigHashAll,
+		PrivKey:   c.lnChannel.channelState.MultiSigKey,
+		Resp:      make(chan sigpool.SignJobResp, 1),
+	}
+	c.lnChannel.lnwallet.SigPool.SubmitSignBatch([]*sigpool.SignJob{job})
+	resp := <-job.Resp
+	if resp.Err != nil {
+		return nil, resp.Err
 	}
 
-	c.sigTheirNewCommit = sig
+	c.sigTheirNewCommit = resp.Sig
 
-	return sig, nil
+	return resp.Sig, nil
 }
 
 // PreviousRevocationPreImage ...
@@ -223,6 +380,19 @@ func (c *ChannelUpdate) Commit(pastRevokePreimage []byte) error {
 	c.lnChannel.stateMtx.Lock()
 	defer c.lnChannel.stateMtx.Unlock()
 
+	// The update may have been cancelled after stalling, in which case
+	// the totem has already been returned.
+	if c.lnChannel.pendingUpdate != c {
+		return ErrUpdateCancelled
+	}
+
+	// Holding the commitment leaves the update pending, as though the
+	// revocation never arrived.
+	if c.lnChannel.hodlMask.Active(hodl.Commit) {
+		fmt.Println(hodl.Commit.Warning())
+		return hodl.ErrHodl
+	}
+
 	// First, ensure that the pre-image properly links into the shachain.
 	theirShaChain := c.lnChannel.channelState.TheirShaChain
 	var preImage [32]byte
@@ -256,13 +426,17 @@ func (c *ChannelUpdate) Commit(pastRevokePreimage []byte) error {
 		delete(c.lnChannel.pendingPayments, c.pendingDesc.RHash)
 	}
 
-	// TODO(roasbeef): db writes, checkpoints, and such
+	// Persist the new state of the channel. The write is batched with
+	// those of any other channels updating at once.
+	// TODO: checkpoints, and such
+	err := c.lnChannel.channelDB.PutOpenChannel(channelState)
 
 	// Return the updateTotem, allowing another update to be created now
 	// that this pending update has been commited, and finalized.
+	c.lnChannel.pendingUpdate = nil
 	c.lnChannel.updateTotem <- struct{}{}
 
-	return nil
+	return err
 }
 
 // AddHTLC ...
@@ -281,14 +455,37 @@ func (c *ChannelUpdate) Commit(pastRevokePreimage []byte) error {
 //    * the pre-image to our old commitment tx
 // 5. they complete
 //    * the pre-image to their old commitment tx (verify is part of their chain, is pre-image)
-func (lc *LightningChannel) AddHTLC(timeout uint32, value btcutil.Amount,
+func (lc *LightningChannel) AddHTLC(timeout uint32, value lnwire.MilliSatoshi,
 	rHash, revocation PaymentHash, payToUs bool) (*ChannelUpdate, error) {
 
+	addFlag := hodl.AddOutgoing
+	if payToUs {
+		addFlag = hodl.AddIncoming
+	}
+	if lc.hodlMask.Active(addFlag) {
+		fmt.Println(addFlag.Warning())
+		return nil, hodl.ErrHodl
+	}
+
 	// Grab the updateTotem, this acts as a barrier upholding the invariant
 	// that only one channel update transaction should exist at any moment.
 	// This aides in ensuring the channel updates are atomic, and consistent.
 	<-lc.updateTotem
 
+	// The new HTLC mustn't take the HTLCs pending to the receiving party
+	// beyond the limits they negotiated when opening the channel.
+	if err := lc.validateHtlcLimits(value, payToUs); err != nil {
+		lc.updateTotem <- struct{}{}
+		return nil, err
+	}
+
+	// Nor may it leave the initiator, should they be offering it, unable
+	// to pay the commitment fee were the feerate to rise.
+	if err := lc.validateFeeBuffer(value, payToUs); err != nil {
+		lc.updateTotem <- struct{}{}
+		return nil, err
+	}
+
 	chanUpdate := &ChannelUpdate{
 		pendingDesc: &PaymentDescriptor{
 			RHash:           rHash,
@@ -300,6 +497,7 @@ func (lc *LightningChannel) AddHTLC(timeout uint32, value btcutil.Amount,
 		pendingRevocation: revocation,
 		lnChannel:         lc,
 	}
+	lc.setPendingUpdate(chanUpdate)
 
 	// Get next revocation hash, updating the number of updates in the
 	// channel as a result.
@@ -312,7 +510,7 @@ func (lc *LightningChannel) AddHTLC(timeout uint32, value btcutil.Amount,
 	copy(chanUpdate.pendingDesc.OurRevocation[:], btcutil.Hash160(nextPreimage[:]))
 
 	// Re-calculate the amount of cleared funds for each side.
-	var amountToUs, amountToThem btcutil.Amount
+	var amountToUs, amountToThem lnwire.MilliSatoshi
 	if payToUs {
 		amountToUs = lc.channelState.OurBalance
 		amountToThem = lc.channelState.TheirBalance - value
@@ -340,7 +538,9 @@ func (lc *LightningChannel) AddHTLC(timeout uint32, value btcutil.Amount,
 	if err := lc.addHTLC(ourNewCommitTx, theirNewCommitTx, chanUpdate.pendingDesc); err != nil {
 		return nil, err
 	}
+	lc.stateMtx.Lock()
 	lc.pendingPayments[rHash] = chanUpdate.pendingDesc // TODO(roasbeef): check for dups?
+	lc.stateMtx.Unlock()
 
 	// Sort both transactions according to the agreed upon cannonical
 	// ordering. This lets us skip sending the entire transaction over,
@@ -379,18 +579,26 @@ func (lc *LightningChannel) addHTLC(ourCommitTx, theirCommitTx *wire.MsgTx,
 		receiverRevocation = paymentDesc.TheirRevocation[:]
 	}
 
+	// Dust HTLCs are trimmed from both commitment transactions. As their
+	// value has already been deducted from the balance of the sender, it
+	// goes towards the fee.
+	if isDustHTLC(paymentDesc.Value) {
+		return nil
+	}
+
 	// Generate the proper redeem scripts for the HTLC output for both the
 	// sender and the receiver.
 	timeout := paymentDesc.Timeout
 	rHash := paymentDesc.RHash
 	delay := lc.channelState.CsvDelay
+	hashType := paymentDesc.HashType
 	senderPKScript, err := senderHTLCScript(timeout, delay, senderKey,
-		receiverKey, senderRevocation[:], rHash[:])
+		receiverKey, senderRevocation[:], hashType, rHash[:])
 	if err != nil {
 		return nil
 	}
 	receiverPKScript, err := receiverHTLCScript(timeout, delay, senderKey,
-		receiverKey, receiverRevocation[:], rHash[:])
+		receiverKey, receiverRevocation[:], hashType, rHash[:])
 	if err != nil {
 		return nil
 	}
@@ -407,7 +615,7 @@ func (lc *LightningChannel) addHTLC(ourCommitTx, theirCommitTx *wire.MsgTx,
 	}
 
 	// Add the new HTLC outputs to the respective commitment transactions.
-	amountPending := int64(paymentDesc.Value)
+	amountPending := int64(paymentDesc.Value.ToSatoshis())
 	if paymentDesc.PayToUs {
 		ourCommitTx.AddTxOut(wire.NewTxOut(amountPending, receiverP2SH))
 		theirCommitTx.AddTxOut(wire.NewTxOut(amountPending, senderP2SH))
@@ -434,7 +642,18 @@ func (lc *LightningChannel) SettleHTLC(rValue [20]byte, newRevocation [20]byte)
 	copy(rHash[:], btcutil.Hash160(rValue[:]))
 	payDesc, ok := lc.pendingPayments[rHash]
 	if !ok {
-		return nil, fmt.Errorf("r-hash for preimage not found")
+		lc.updateTotem <- struct{}{}
+		return nil, ErrUnknownPaymentHash
+	}
+
+	settleFlag := hodl.SettleOutgoing
+	if payDesc.PayToUs {
+		settleFlag = hodl.SettleIncoming
+	}
+	if lc.hodlMask.Active(settleFlag) {
+		lc.updateTotem <- struct{}{}
+		fmt.Println(settleFlag.Warning())
+		return nil, hodl.ErrHodl
 	}
 
 	chanUpdate := &ChannelUpdate{
@@ -443,6 +662,7 @@ func (lc *LightningChannel) SettleHTLC(rValue [20]byte, newRevocation [20]byte)
 		pendingRevocation: newRevocation,
 		lnChannel:         lc,
 	}
+	lc.setPendingUpdate(chanUpdate)
 
 	// TODO(roasbeef): such copy pasta, make into func ...
 	// Get next revocation hash, updating the number of updates in the
@@ -455,8 +675,12 @@ func (lc *LightningChannel) SettleHTLC(rValue [20]byte, newRevocation [20]byte)
 	}
 	copy(chanUpdate.pendingDesc.OurRevocation[:], btcutil.Hash160(nextPreimage[:]))
 
+	// Their new commitment is revocable with the hash they just gave us,
+	// rather than that of the commitment the HTLC was added in.
+	chanUpdate.pendingDesc.TheirRevocation = newRevocation
+
 	// Re-calculate the amount of cleared funds for each side.
-	var amountToUs, amountToThem btcutil.Amount
+	var amountToUs, amountToThem lnwire.MilliSatoshi
 	if payDesc.PayToUs {
 		amountToUs = lc.channelState.OurBalance + payDesc.Value
 		amountToThem = lc.channelState.TheirBalance
@@ -501,14 +725,19 @@ func (lc *LightningChannel) SettleHTLC(rValue [20]byte, newRevocation [20]byte)
 }
 
 // createNewCommitmentTxns ....
+// Each balance is rounded down to the satoshi when creating its output, with
+// the remaining fraction going towards the fee.
 // NOTE: This MUST be called with stateMtx held.
 func createNewCommitmentTxns(fundingTxIn *wire.TxIn, state *channeldb.OpenChannel,
-	chanUpdate *ChannelUpdate, amountToUs, amountToThem btcutil.Amount) (*wire.MsgTx, *wire.MsgTx, error) {
+	chanUpdate *ChannelUpdate, balanceToUs, balanceToThem lnwire.MilliSatoshi) (*wire.MsgTx, *wire.MsgTx, error) {
+
+	amountToUs := balanceToUs.ToSatoshis()
+	amountToThem := balanceToThem.ToSatoshis()
 
 	ourNewCommitTx, err := createCommitTx(fundingTxIn,
 		state.OurCommitKey.PubKey(), state.TheirCommitKey,
 		chanUpdate.pendingDesc.OurRevocation[:], state.CsvDelay,
-		amountToUs, amountToThem)
+		amountToUs, amountToThem, state.CommitType)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -516,7 +745,7 @@ func createNewCommitmentTxns(fundingTxIn *wire.TxIn, state *channeldb.OpenChanne
 	theirNewCommitTx, err := createCommitTx(fundingTxIn,
 		state.TheirCommitKey, state.OurCommitKey.PubKey(),
 		chanUpdate.pendingDesc.TheirRevocation[:], state.CsvDelay,
-		amountToThem, amountToUs)
+		amountToThem, amountToUs, state.CommitType)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -530,26 +759,35 @@ func (lc *LightningChannel) CancelHTLC() error {
 }
 
 // OurBalance ...
-func (lc *LightningChannel) OurBalance() btcutil.Amount {
+func (lc *LightningChannel) OurBalance() lnwire.MilliSatoshi {
 	lc.stateMtx.RLock()
 	defer lc.stateMtx.RUnlock()
 	return lc.channelState.OurBalance
 }
 
 // TheirBalance ...
-func (lc *LightningChannel) TheirBalance() btcutil.Amount {
+func (lc *LightningChannel) TheirBalance() lnwire.MilliSatoshi {
 	lc.stateMtx.RLock()
 	defer lc.stateMtx.RUnlock()
 	return lc.channelState.TheirBalance
 }
 
-// ForceClose ...
+// TheirLNID returns the ID of the node the channel is with.
+func (lc *LightningChannel) TheirLNID() [32]byte {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+	return lc.channelState.TheirLNID
+}
+
+// ForceClose broadcasts our latest commitment transaction, unilaterally
+// closing the channel. As we're yet to store the counterparty's signature for
+// it, ErrForceCloseUnimplemented is returned for now.
 func (lc *LightningChannel) ForceClose() error {
-	return nil
+	return ErrForceCloseUnimplemented
 }
 
 // RequestPayment ...
-func (lc *LightningChannel) RequestPayment(amount btcutil.Amount) error {
+func (lc *LightningChannel) RequestPayment(amount lnwire.MilliSatoshi) error {
 	// Validate amount
 	return nil
 }
@@ -559,14 +797,22 @@ func (lc *LightningChannel) RequestPayment(amount btcutil.Amount) error {
 //  * routing handled by upper layer
 type PaymentRequest struct {
 	PaymentPreImage [20]byte
-	Value           btcutil.Amount
+	Value           lnwire.MilliSatoshi
 }
 
 // createCommitTx ...
 // TODO(roasbeef): fix inconsistency of 32 vs 20 byte revocation hashes everywhere ...
 func createCommitTx(fundingOutput *wire.TxIn, selfKey, theirKey *btcec.PublicKey,
 	revokeHash []byte, csvTimeout uint32, amountToSelf,
-	amountToThem btcutil.Amount) (*wire.MsgTx, error) {
+	amountToThem btcutil.Amount,
+	commitType channeldb.CommitmentType) (*wire.MsgTx, error) {
+
+	// With anchors, each party funds their own anchor output from their
+	// balance.
+	if commitType == channeldb.CommitmentAnchors {
+		amountToSelf -= anchorSize
+		amountToThem -= anchorSize
+	}
 
 	// First, we create the script for the delayed "pay-to-self" output.
 	ourRedeemScript, err := commitScriptToSelf(csvTimeout, selfKey, theirKey,
@@ -581,14 +827,37 @@ func createCommitTx(fundingOutput *wire.TxIn, selfKey, theirKey *btcec.PublicKey
 
 	// Next, we create the script paying to them. This is just a regular
 	// P2PKH-like output, without any added CSV delay. However, we instead
-	// use P2SH.
-	theirRedeemScript, err := commitScriptUnencumbered(theirKey)
-	if err != nil {
-		return nil, err
-	}
-	payToThemScriptHash, err := scriptHashPkScript(theirRedeemScript)
-	if err != nil {
-		return nil, err
+	// use P2SH. With anchors, their output requires a single confirmation,
+	// leaving the anchors as the only way to CPFP the commitment.
+	var payToThemScriptHash []byte
+	switch commitType {
+	case channeldb.CommitmentAnchors:
+		theirRedeemScript, err := commitScriptToRemoteConfirmed(theirKey)
+		if err != nil {
+			return nil, err
+		}
+		payToThemScriptHash, err = scriptHashPkScript(theirRedeemScript)
+		if err != nil {
+			return nil, err
+		}
+
+	// With a static remote key, their output is a regular P2PKH output,
+	// which they can sweep with their key alone.
+	case channeldb.CommitmentStaticRemoteKey:
+		payToThemScriptHash, err = commitScriptUnencumbered(theirKey)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		theirRedeemScript, err := commitScriptUnencumbered(theirKey)
+		if err != nil {
+			return nil, err
+		}
+		payToThemScriptHash, err = scriptHashPkScript(theirRedeemScript)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Now that both output scripts have been created, we can finally create
@@ -601,6 +870,22 @@ func createCommitTx(fundingOutput *wire.TxIn, selfKey, theirKey *btcec.PublicKey
 	commitTx.AddTxOut(wire.NewTxOut(int64(amountToSelf), payToUsScriptHash))
 	commitTx.AddTxOut(wire.NewTxOut(int64(amountToThem), payToThemScriptHash))
 
+	// Finally, add an anchor output for each party.
+	if commitType == channeldb.CommitmentAnchors {
+		for _, key := range []*btcec.PublicKey{selfKey, theirKey} {
+			anchorRedeemScript, err := anchorScript(key)
+			if err != nil {
+				return nil, err
+			}
+			anchorScriptHash, err := scriptHashPkScript(anchorRedeemScript)
+			if err != nil {
+				return nil, err
+			}
+			commitTx.AddTxOut(wire.NewTxOut(int64(anchorSize),
+				anchorScriptHash))
+		}
+	}
+
 	return commitTx, nil
 }
 
@@ -0,0 +1,458 @@
+package lnwallet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/lightningnetwork/lnd/sigpool"
+)
+
+const (
+	// testChannelCapacity is the capacity of the channels created for
+	// tests, split evenly between Alice and Bob.
+	testChannelCapacity = 10 * btcutil.SatoshiPerBitcoin
+
+	testCsvDelay = 144
+)
+
+// createTestChannelDB creates a channeldb instance backed by a fresh
+// database, along with the unlocked addrmgr needed to store channels, and a
+// function to clean up the database.
+func createTestChannelDB() (*channeldb.DB, func(), error) {
+	dirName, err := ioutil.TempDir("", "lnchannel")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := walletdb.Create("bdb", filepath.Join(dirName, "channel.db"))
+	if err != nil {
+		os.RemoveAll(dirName)
+		return nil, nil, err
+	}
+	cleanUp := func() {
+		db.Close()
+		os.RemoveAll(dirName)
+	}
+
+	addrNamespace, err := db.Namespace(waddrmgrNamespaceKey)
+	if err != nil {
+		cleanUp()
+		return nil, nil, err
+	}
+	lnNamespace, err := db.Namespace(lightningNamespaceKey)
+	if err != nil {
+		cleanUp()
+		return nil, nil, err
+	}
+
+	mgr, err := waddrmgr.Create(addrNamespace, testHdSeed[:], privPass,
+		privPass, ActiveNetParams, nil)
+	if err != nil {
+		cleanUp()
+		return nil, nil, err
+	}
+	if err := mgr.Unlock(privPass); err != nil {
+		mgr.Close()
+		cleanUp()
+		return nil, nil, err
+	}
+
+	return channeldb.New(mgr, lnNamespace), func() {
+		mgr.Close()
+		cleanUp()
+	}, nil
+}
+
+// createTestChannels returns a pair of channels between Alice and Bob, each
+// funded with half of the capacity. The channels aren't backed by a chain,
+// but store each new state within a shared database.
+func createTestChannels(pool *sigpool.SigPool) (*LightningChannel,
+	*LightningChannel, func(), error) {
+
+	aliceKey, alicePub := btcec.PrivKeyFromBytes(btcec.S256(), testWalletPrivKey)
+	bobKey, bobPub := btcec.PrivKeyFromBytes(btcec.S256(), bobsPrivKey)
+
+	redeemScript, fundingOutput, err := fundMultiSigOut(
+		alicePub.SerializeCompressed(), bobPub.SerializeCompressed(),
+		int64(testChannelCapacity))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	fundingTx := wire.NewMsgTx()
+	fundingTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&wire.ShaHash{}, 0), nil))
+	fundingTx.AddTxOut(fundingOutput)
+
+	aliceChain, err := shachain.NewFromSeed(&[32]byte{0xaa}, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	bobChain, err := shachain.NewFromSeed(&[32]byte{0xbb}, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cdb, cleanUp, err := createTestChannelDB()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	wallet := &LightningWallet{cfg: &Config{}, SigPool: pool}
+	halfBalance := lnwire.NewMSatFromSatoshis(testChannelCapacity / 2)
+	newChannel := func(ourKey *btcec.PrivateKey, theirKey *btcec.PublicKey,
+		ourChain, theirChain *shachain.HyperShaChain,
+		theirLNID [32]byte) (*LightningChannel, error) {
+
+		ourAddr, err := btcutil.NewAddressPubKey(
+			ourKey.PubKey().SerializeCompressed(), ActiveNetParams)
+		if err != nil {
+			return nil, err
+		}
+		theirAddr, err := btcutil.NewAddressPubKey(
+			theirKey.SerializeCompressed(), ActiveNetParams)
+		if err != nil {
+			return nil, err
+		}
+
+		// The counterparty's first commitment is revocable with the
+		// hash of the first preimage of their chain.
+		theirPreimage, err := theirChain.GetHash(0)
+		if err != nil {
+			return nil, err
+		}
+		var theirRevocation [20]byte
+		copy(theirRevocation[:], btcutil.Hash160(theirPreimage[:]))
+
+		state := &channeldb.OpenChannel{
+			TheirLNID:              theirLNID,
+			OurCommitKey:           ourKey,
+			TheirCommitKey:         theirKey,
+			Capacity:               testChannelCapacity,
+			OurBalance:             halfBalance,
+			TheirBalance:           halfBalance,
+			FundingTx:              fundingTx,
+			MultiSigKey:            ourKey,
+			FundingRedeemScript:    redeemScript,
+			TheirCurrentRevocation: theirRevocation,
+			OurShaChain:            ourChain,
+			TheirShaChain:          shachain.New(),
+			OurDeliveryAddress:     ourAddr,
+			TheirDeliveryAddress:   theirAddr,
+			CsvDelay:               testCsvDelay,
+		}
+		return NewLightningChannel(wallet, nil, cdb, state)
+	}
+
+	alice, err := newChannel(aliceKey, bobPub, aliceChain, bobChain,
+		[32]byte{0xbb})
+	if err != nil {
+		cleanUp()
+		return nil, nil, nil, err
+	}
+	bob, err := newChannel(bobKey, alicePub, bobChain, aliceChain,
+		[32]byte{0xaa})
+	if err != nil {
+		cleanUp()
+		return nil, nil, nil, err
+	}
+	return alice, bob, cleanUp, nil
+}
+
+// nextRevocation returns the revocation hash of the next commitment of
+// the channel, which the counterparty is given to build it with.
+func nextRevocation(lc *LightningChannel) (PaymentHash, error) {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	var revocation PaymentHash
+	state := lc.channelState
+	preimage, err := state.OurShaChain.GetHash(state.NumUpdates + 1)
+	if err != nil {
+		return revocation, err
+	}
+	copy(revocation[:], btcutil.Hash160(preimage[:]))
+	return revocation, nil
+}
+
+// signOwnCommitment signs our new commitment of the update, which is
+// otherwise signed by the wallet once the commitment is broadcast.
+func signOwnCommitment(u *ChannelUpdate) ([]byte, error) {
+	job := &sigpool.SignJob{
+		Tx:        u.ourPendingCommitTx,
+		SubScript: u.lnChannel.channelState.FundingRedeemScript,
+		HashType:  txscript.SigHashAll,
+		PrivKey:   u.lnChannel.channelState.MultiSigKey,
+		Resp:      make(chan sigpool.SignJobResp, 1),
+	}
+	u.lnChannel.lnwallet.SigPool.SubmitSignBatch([]*sigpool.SignJob{job})
+	resp := <-job.Resp
+	return resp.Sig, resp.Err
+}
+
+// verifyUpdate has the remote side sign the new commitment of the local
+// side, which the local side then verifies.
+func verifyUpdate(local, remote *ChannelUpdate) error {
+	theirSig, err := remote.SignCounterPartyCommitment()
+	if err != nil {
+		return err
+	}
+	ourSig, err := signOwnCommitment(local)
+	if err != nil {
+		return err
+	}
+	return local.VerifyNewCommitmentSigs(ourSig, theirSig)
+}
+
+// exchangeUpdates has Alice and Bob each verify their new commitment of the
+// update is signed by the other, then commit it with the preimage revoking
+// the other's previous commitment.
+func exchangeUpdates(aliceUpdate, bobUpdate *ChannelUpdate) error {
+	if err := verifyUpdate(aliceUpdate, bobUpdate); err != nil {
+		return fmt.Errorf("alice's commitment invalid: %v", err)
+	}
+	if err := verifyUpdate(bobUpdate, aliceUpdate); err != nil {
+		return fmt.Errorf("bob's commitment invalid: %v", err)
+	}
+
+	alicePreimage, err := aliceUpdate.PreviousRevocationPreImage()
+	if err != nil {
+		return err
+	}
+	bobPreimage, err := bobUpdate.PreviousRevocationPreImage()
+	if err != nil {
+		return err
+	}
+
+	// Both sides commit at once, so their writes share a batch.
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- bobUpdate.Commit(alicePreimage)
+	}()
+	if err := aliceUpdate.Commit(bobPreimage); err != nil {
+		<-errChan
+		return fmt.Errorf("alice unable to commit: %v", err)
+	}
+	if err := <-errChan; err != nil {
+		return fmt.Errorf("bob unable to commit: %v", err)
+	}
+
+	return nil
+}
+
+// TestSettleHTLCRevocation asserts the settle of an HTLC builds the new
+// commitment of the counterparty with the revocation hash they gave for it,
+// rather than that of the commitment the HTLC was added in, so each side
+// signs the commitment the other builds.
+func TestSettleHTLCRevocation(t *testing.T) {
+	pool := sigpool.NewSigPool(0)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start sig pool: %v", err)
+	}
+	defer pool.Stop()
+
+	alice, bob, cleanUp, err := createTestChannels(pool)
+	if err != nil {
+		t.Fatalf("unable to create channels: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := [20]byte{1}
+	var rHash PaymentHash
+	copy(rHash[:], btcutil.Hash160(preimage[:]))
+	value := lnwire.NewMSatFromSatoshis(10000)
+
+	// Alice offers Bob an HTLC, which each side commits.
+	aliceRevocation, err := nextRevocation(alice)
+	if err != nil {
+		t.Fatalf("unable to get alice's revocation: %v", err)
+	}
+	bobRevocation, err := nextRevocation(bob)
+	if err != nil {
+		t.Fatalf("unable to get bob's revocation: %v", err)
+	}
+	aliceUpdate, err := alice.AddHTLC(500, value, rHash, bobRevocation,
+		false)
+	if err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	bobUpdate, err := bob.AddHTLC(500, value, rHash, aliceRevocation, true)
+	if err != nil {
+		t.Fatalf("bob unable to add htlc: %v", err)
+	}
+	if err := exchangeUpdates(aliceUpdate, bobUpdate); err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+
+	// Bob then settles it, with each side giving the other the
+	// revocation hash of its next commitment.
+	aliceRevocation, err = nextRevocation(alice)
+	if err != nil {
+		t.Fatalf("unable to get alice's revocation: %v", err)
+	}
+	bobRevocation, err = nextRevocation(bob)
+	if err != nil {
+		t.Fatalf("unable to get bob's revocation: %v", err)
+	}
+	aliceUpdate, err = alice.SettleHTLC(preimage, bobRevocation)
+	if err != nil {
+		t.Fatalf("alice unable to settle htlc: %v", err)
+	}
+	bobUpdate, err = bob.SettleHTLC(preimage, aliceRevocation)
+	if err != nil {
+		t.Fatalf("bob unable to settle htlc: %v", err)
+	}
+	if aliceUpdate.pendingDesc.TheirRevocation != bobRevocation {
+		t.Fatalf("expected bob's commitment revocable with %x, "+
+			"instead %x", bobRevocation[:],
+			aliceUpdate.pendingDesc.TheirRevocation[:])
+	}
+	if bobUpdate.pendingDesc.TheirRevocation != aliceRevocation {
+		t.Fatalf("expected alice's commitment revocable with %x, "+
+			"instead %x", aliceRevocation[:],
+			bobUpdate.pendingDesc.TheirRevocation[:])
+	}
+	if err := exchangeUpdates(aliceUpdate, bobUpdate); err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+
+	halfBalance := lnwire.NewMSatFromSatoshis(testChannelCapacity / 2)
+	if alice.channelState.OurBalance != halfBalance-value {
+		t.Fatalf("expected alice's balance %v, instead %v",
+			halfBalance-value, alice.channelState.OurBalance)
+	}
+	if bob.channelState.OurBalance != halfBalance+value {
+		t.Fatalf("expected bob's balance %v, instead %v",
+			halfBalance+value, bob.channelState.OurBalance)
+	}
+}
+
+const (
+	// benchHTLCValue is the value of each HTLC sent across the channels,
+	// well above the dust limit so an output is created for it.
+	benchHTLCValue = 10000
+
+	benchHTLCTimeout = 500
+)
+
+// exchangeBenchUpdate carries out an update adding, or settling if settle is
+// set, an HTLC from Alice to Bob across both channels.
+func exchangeBenchUpdate(alice, bob *LightningChannel, preimage [20]byte,
+	settle bool) error {
+
+	aliceRevocation, err := nextRevocation(alice)
+	if err != nil {
+		return err
+	}
+	bobRevocation, err := nextRevocation(bob)
+	if err != nil {
+		return err
+	}
+
+	var aliceUpdate, bobUpdate *ChannelUpdate
+	if settle {
+		aliceUpdate, err = alice.SettleHTLC(preimage, bobRevocation)
+		if err != nil {
+			return err
+		}
+		bobUpdate, err = bob.SettleHTLC(preimage, aliceRevocation)
+		if err != nil {
+			return err
+		}
+	} else {
+		var rHash PaymentHash
+		copy(rHash[:], btcutil.Hash160(preimage[:]))
+		value := lnwire.NewMSatFromSatoshis(benchHTLCValue)
+
+		aliceUpdate, err = alice.AddHTLC(benchHTLCTimeout, value, rHash,
+			bobRevocation, false)
+		if err != nil {
+			return err
+		}
+
+		// Bob receives the HTLC Alice offers, so it's added to his
+		// channel as paying to him.
+		bobUpdate, err = bob.AddHTLC(benchHTLCTimeout, value, rHash,
+			aliceRevocation, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return exchangeUpdates(aliceUpdate, bobUpdate)
+}
+
+// benchPreimage returns the payment preimage of the i-th HTLC of the
+// benchmark.
+func benchPreimage(i uint64) [20]byte {
+	var preimage [20]byte
+	binary.BigEndian.PutUint64(preimage[:], i)
+	return preimage
+}
+
+// BenchmarkHTLCAddSettle measures the throughput of the channel state machine
+// by adding, then settling, an HTLC across a pair of in-process channels with
+// each iteration, on top of a number of HTLCs left pending throughout. Each
+// update is driven through AddHTLC, or SettleHTLC, then Commit, on both
+// sides, so the write of each new state is measured too, and bounds the
+// throughput by the database's batch interval. Along with the allocations
+// of each cycle, the number of HTLCs cleared per second is reported. A fixed
+// number of cycles, and an allocation profile, may be run with:
+//
+//	go test -run=^$ -bench=HTLCAddSettle -benchtime=1000x -memprofile=mem.out
+func BenchmarkHTLCAddSettle(b *testing.B) {
+	pool := sigpool.NewSigPool(0)
+	if err := pool.Start(); err != nil {
+		b.Fatalf("unable to start sig pool: %v", err)
+	}
+	defer pool.Stop()
+
+	for _, numPending := range []int{0, MaxPendingPayments / 2, MaxPendingPayments - 1} {
+		b.Run(fmt.Sprintf("pending=%d", numPending), func(b *testing.B) {
+			alice, bob, cleanUp, err := createTestChannels(pool)
+			if err != nil {
+				b.Fatalf("unable to create channels: %v", err)
+			}
+			defer cleanUp()
+
+			// The HTLCs left pending are numbered beyond those
+			// cycled, so their payment hashes never collide.
+			for i := 0; i < numPending; i++ {
+				preimage := benchPreimage(uint64(1<<32 + i))
+				if err := exchangeBenchUpdate(alice, bob, preimage, false); err != nil {
+					b.Fatalf("unable to add pending htlc: %v", err)
+				}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			start := time.Now()
+
+			for i := 0; i < b.N; i++ {
+				preimage := benchPreimage(uint64(i))
+				if err := exchangeBenchUpdate(alice, bob, preimage, false); err != nil {
+					b.Fatalf("unable to add htlc: %v", err)
+				}
+				if err := exchangeBenchUpdate(alice, bob, preimage, true); err != nil {
+					b.Fatalf("unable to settle htlc: %v", err)
+				}
+			}
+
+			b.StopTimer()
+			elapsed := time.Since(start).Seconds()
+			b.ReportMetric(float64(b.N)/elapsed, "htlcs/sec")
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// CommitTypeFromWire converts the channel type proposed within a funding
+// request into the commitment format stored for the channel.
+func CommitTypeFromWire(chanType lnwire.ChannelType) (channeldb.CommitmentType, error) {
+	switch chanType {
+	case lnwire.ChannelTypeLegacy:
+		return channeldb.CommitmentLegacy, nil
+	case lnwire.ChannelTypeStaticRemoteKey:
+		return channeldb.CommitmentStaticRemoteKey, nil
+	case lnwire.ChannelTypeAnchors:
+		return channeldb.CommitmentAnchors, nil
+	default:
+		return 0, fmt.Errorf("unknown channel type: %v", chanType)
+	}
+}
+
+// CommitTypeToWire converts the commitment format of a channel into the
+// channel type sent within the funding messages.
+func CommitTypeToWire(commitType channeldb.CommitmentType) lnwire.ChannelType {
+	switch commitType {
+	case channeldb.CommitmentStaticRemoteKey:
+		return lnwire.ChannelTypeStaticRemoteKey
+	case channeldb.CommitmentAnchors:
+		return lnwire.ChannelTypeAnchors
+	default:
+		return lnwire.ChannelTypeLegacy
+	}
+}
@@ -0,0 +1,92 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrUpdatePending is returned when renegotiating the parameters of a
+// channel which has an update in progress.
+var ErrUpdatePending = fmt.Errorf("channel has an update in progress")
+
+// ChannelParams are the parameters of a channel which may be renegotiated
+// with the counterparty while the channel is open, rather than requiring it
+// be closed and re-opened.
+//
+// NOTE: The dust limit isn't yet tracked per channel, so can't be
+// renegotiated.
+type ChannelParams struct {
+	// MaxAcceptedHtlcs, and MaxValueInFlight, limit the number, and total
+	// value, of pending HTLCs the proposing party accepts.
+	MaxAcceptedHtlcs uint16
+	MaxValueInFlight lnwire.MilliSatoshi
+
+	// CsvDelay is the delay of the outputs paying either party within
+	// the commitment transactions.
+	CsvDelay uint32
+}
+
+// Params returns the current parameters of the channel, with the HTLC limits
+// being those we accept.
+func (lc *LightningChannel) Params() *ChannelParams {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return &ChannelParams{
+		MaxAcceptedHtlcs: lc.channelState.OurMaxAcceptedHtlcs,
+		MaxValueInFlight: lc.channelState.OurMaxValueInFlight,
+		CsvDelay:         lc.channelState.CsvDelay,
+	}
+}
+
+// ValidateRemoteParams returns an error if the parameters proposed by the
+// counterparty are outside our bounds.
+func (lc *LightningChannel) ValidateRemoteParams(params *ChannelParams) error {
+	if params.MaxAcceptedHtlcs == 0 ||
+		params.MaxAcceptedHtlcs > lnwire.MaxHTLCNumber {
+
+		return fmt.Errorf("max accepted htlcs must be between 1 and %v",
+			lnwire.MaxHTLCNumber)
+	}
+	if params.MaxValueInFlight == 0 {
+		return fmt.Errorf("max value in flight must be positive")
+	}
+
+	if params.CsvDelay == 0 {
+		return fmt.Errorf("csv delay must be positive")
+	}
+	if maxDelay := lc.lnwallet.maxCsvDelay(); params.CsvDelay > maxDelay {
+		return fmt.Errorf("csv delay of %v blocks exceeds our maximum "+
+			"of %v", params.CsvDelay, maxDelay)
+	}
+
+	return nil
+}
+
+// UpdateParams applies the renegotiated parameters to the channel, which
+// mustn't have an update in progress, persisting them. The HTLC limits are
+// those we accept if we proposed the parameters, and those the
+// counterparty accepts otherwise. The parameters only apply to outputs
+// created from now on.
+func (lc *LightningChannel) UpdateParams(params *ChannelParams,
+	proposedByUs bool) error {
+
+	lc.stateMtx.Lock()
+	defer lc.stateMtx.Unlock()
+
+	if lc.pendingUpdate != nil {
+		return ErrUpdatePending
+	}
+
+	if proposedByUs {
+		lc.channelState.OurMaxAcceptedHtlcs = params.MaxAcceptedHtlcs
+		lc.channelState.OurMaxValueInFlight = params.MaxValueInFlight
+	} else {
+		lc.channelState.TheirMaxAcceptedHtlcs = params.MaxAcceptedHtlcs
+		lc.channelState.TheirMaxValueInFlight = params.MaxValueInFlight
+	}
+	lc.channelState.CsvDelay = params.CsvDelay
+
+	return lc.channelDB.PutOpenChannel(lc.channelState)
+}
@@ -0,0 +1,62 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// defaultMinChanSize is the smallest capacity of channel we'll open,
+	// or accept, if the config doesn't specify.
+	defaultMinChanSize = btcutil.Amount(20000)
+
+	// defaultWumboMaxChanSize is the largest capacity of wumbo channel
+	// we'll open, or accept, if the config doesn't specify.
+	defaultWumboMaxChanSize = btcutil.Amount(10 * btcutil.SatoshiPerBitcoin)
+)
+
+// minChanSize returns the smallest capacity of channel we'll open, or accept.
+func (l *LightningWallet) minChanSize() btcutil.Amount {
+	if l.cfg.MinChanSize == 0 {
+		return defaultMinChanSize
+	}
+	return l.cfg.MinChanSize
+}
+
+// maxChanSize returns the largest capacity of channel we'll open, or accept,
+// with a peer. Unless both we, and the peer, support wumbo channels, neither
+// side may contribute more than lnwire.MaxFundingAmount.
+func (l *LightningWallet) maxChanSize(peerWumbo bool) btcutil.Amount {
+	if l.cfg.Wumbo && peerWumbo {
+		if l.cfg.MaxChanSize == 0 {
+			return defaultWumboMaxChanSize
+		}
+		return l.cfg.MaxChanSize
+	}
+
+	// TODO: assumes balanced symmetric channels.
+	maxSize := 2 * lnwire.MaxFundingAmount
+	if l.cfg.MaxChanSize != 0 && l.cfg.MaxChanSize < maxSize {
+		maxSize = l.cfg.MaxChanSize
+	}
+	return maxSize
+}
+
+// validateChanSize returns an error if a channel of the passed capacity with
+// the peer is too small, or too large, to open or accept.
+func (l *LightningWallet) validateChanSize(capacity btcutil.Amount,
+	peerWumbo bool) error {
+
+	if minSize := l.minChanSize(); capacity < minSize {
+		return fmt.Errorf("channel capacity of %v is below the minimum "+
+			"of %v", capacity, minSize)
+	}
+	if maxSize := l.maxChanSize(peerWumbo); capacity > maxSize {
+		return fmt.Errorf("channel capacity of %v exceeds the maximum "+
+			"of %v", capacity, maxSize)
+	}
+
+	return nil
+}
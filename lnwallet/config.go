@@ -2,8 +2,12 @@ package lnwallet
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 var (
@@ -45,9 +49,109 @@ type Config struct {
 
 	CACert []byte
 
+	// FallbackBackends are the backends we fail over to, in order, should
+	// the one we're connected to become unresponsive. Our notifications,
+	// fee estimates, and broadcasts all follow the backend we're
+	// connected to.
+	FallbackBackends []ChainBackend
+
+	// BackendHealthInterval is how often the backend we're connected to is
+	// checked, when we have fallbacks. If zero,
+	// defaultBackendHealthInterval is used.
+	BackendHealthInterval time.Duration
+
 	PrivatePass []byte
 	PublicPass  []byte
 	HdSeed      []byte
+
+	// DefaultCommitType is the commitment format we propose for new
+	// channels.
+	DefaultCommitType channeldb.CommitmentType
+
+	// BlockCacheSize is the number of blocks fetched from the backend
+	// which are kept in memory. If zero, defaultBlockCacheSize is used.
+	BlockCacheSize int
+
+	// MaxAcceptedHtlcs is the number of pending HTLCs we'll accept from
+	// the counterparty of each new channel. If zero, the protocol limit
+	// of lnwire.MaxHTLCNumber is used.
+	MaxAcceptedHtlcs uint16
+
+	// MinHTLC is the smallest HTLC we'll accept from the counterparty of
+	// each new channel.
+	MinHTLC lnwire.MilliSatoshi
+
+	// MaxDustExposure caps the total value of dust HTLCs pending within a
+	// channel, all of which is lost to fees should the channel be force
+	// closed. If zero, defaultMaxDustExposure is used.
+	MaxDustExposure btcutil.Amount
+
+	// ExpiryGraceDelta is the number of blocks before the expiry of an
+	// incoming HTLC at which its channel is force closed, should the HTLC
+	// remain unsettled, so our claim confirms before the counterparty may
+	// reclaim it. If zero, defaultExpiryGraceDelta is used.
+	ExpiryGraceDelta uint32
+
+	// HodlMask holds the steps of updating channels which are to be
+	// dropped, for testing the timeout, breach, and recovery paths. It may
+	// lose funds, so it's only to be set in developer mode.
+	HodlMask hodl.Mask
+
+	// RecoveryWindow is the number of addresses of each branch of the
+	// default account rescanned for when restoring a wallet from HdSeed.
+	// If zero, no rescan is performed.
+	RecoveryWindow uint32
+
+	// ReservationTimeout is how long a channel reservation may remain
+	// incomplete before it's cancelled. If zero, defaultReservationTimeout
+	// is used.
+	ReservationTimeout time.Duration
+
+	// FundingConfTimeout is the number of blocks we wait on the funding
+	// transaction of a channel we didn't fund to confirm, before
+	// forgetting the channel. If zero, defaultFundingConfTimeout is used.
+	FundingConfTimeout uint32
+
+	// MinChanSize is the smallest capacity of channel we'll open, or
+	// accept. If zero, defaultMinChanSize is used.
+	MinChanSize btcutil.Amount
+
+	// MaxChanSize is the largest capacity of channel we'll open, or
+	// accept. Unless Wumbo is set, it's capped by the MaxFundingAmount
+	// limit of each side's contribution. If zero, the cap is used, or
+	// defaultWumboMaxChanSize with Wumbo set.
+	MaxChanSize btcutil.Amount
+
+	// Wumbo lifts the MaxFundingAmount limit of each side's contribution
+	// to a channel, for channels with peers which support wumbo channels
+	// too.
+	Wumbo bool
+
+	// MaxCsvDelay is the largest CSV delay we'll accept a counterparty
+	// proposing for a new channel, bounding how long our funds may be
+	// locked up should the channel be force closed. If zero,
+	// defaultMaxCsvDelay is used.
+	MaxCsvDelay uint32
+
+	// MaxReservePercent is the largest reserve, as a percentage of the
+	// channel's capacity, we'll accept a counterparty requiring we keep
+	// within a new channel. If zero, defaultMaxReservePercent is used.
+	MaxReservePercent uint32
+
+	// MaxDustLimit is the largest dust limit we'll accept a counterparty
+	// proposing for a new channel. If zero, defaultMaxDustLimit is used.
+	MaxDustLimit btcutil.Amount
+
+	// EncryptChannelDB encrypts the values of the channel database under
+	// a key protected by the wallet's private passphrase. Once encrypted,
+	// the database remains so, whether or not this is set.
+	EncryptChannelDB bool
+
+	// WipeChannelDB removes the state of each open channel from the
+	// channel database as it's opened, recovering from a database whose
+	// channels can't be migrated. The funds of the channels are lost
+	// unless they've been closed beforehand.
+	WipeChannelDB bool
 }
 
 // setDefaults...
@@ -0,0 +1,148 @@
+package lnwallet
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+)
+
+// Start begins watching the incoming HTLCs of the channel, force closing it
+// should any near expiry without being settled.
+func (lc *LightningChannel) Start() {
+	if !atomic.CompareAndSwapInt32(&lc.started, 0, 1) {
+		return
+	}
+
+	lc.lnwallet.activeChanMtx.Lock()
+	lc.lnwallet.activeChannels[lc] = struct{}{}
+	lc.lnwallet.activeChanMtx.Unlock()
+}
+
+// Stop ceases watching the incoming HTLCs of the channel.
+func (lc *LightningChannel) Stop() {
+	if !atomic.CompareAndSwapInt32(&lc.shutdown, 0, 1) {
+		return
+	}
+
+	lc.lnwallet.activeChanMtx.Lock()
+	delete(lc.lnwallet.activeChannels, lc)
+	lc.lnwallet.activeChanMtx.Unlock()
+
+	// The quit channel is closed under the state lock, so no force close
+	// is launched once we wait on those already running.
+	lc.stateMtx.Lock()
+	close(lc.quit)
+	lc.stateMtx.Unlock()
+
+	lc.wg.Wait()
+}
+
+// expiringHTLC returns the first unsettled incoming HTLC which expires
+// within the grace delta of the passed height, or nil if there's none.
+func (lc *LightningChannel) expiringHTLC(height uint32) *PaymentDescriptor {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	for _, htlc := range lc.pendingPayments {
+		if htlc.PayToUs && htlc.Timeout <= height+lc.expiryGraceDelta {
+			return htlc
+		}
+	}
+	return nil
+}
+
+// checkHTLCExpiry force closes the channel if an incoming HTLC nears expiry
+// at the passed height. Any later and the counterparty could claim the HTLC
+// back with its timeout clause first, as our claim with the preimage would
+// race theirs. Should the force close fail, it's attempted again with the
+// next block, unless force closes are unimplemented.
+func (lc *LightningChannel) checkHTLCExpiry(height uint32) {
+	htlc := lc.expiringHTLC(height)
+	if htlc == nil {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&lc.expiryClosed, 0, 1) {
+		return
+	}
+
+	// The force close is only launched while the channel is running,
+	// checked under the lock Stop closes the quit channel with, so Stop
+	// never waits while one is being added.
+	lc.stateMtx.Lock()
+	select {
+	case <-lc.quit:
+		lc.stateMtx.Unlock()
+		atomic.StoreInt32(&lc.expiryClosed, 0)
+		return
+	default:
+	}
+	lc.wg.Add(1)
+	lc.stateMtx.Unlock()
+
+	go func() {
+		defer lc.wg.Done()
+
+		if err := lc.ForceClose(); err != nil {
+			fmt.Printf("unable to force close channel %x, incoming "+
+				"htlc %x expires at height %v, current height "+
+				"%v: %v\n", lc.channelState.ChanID[:],
+				htlc.RHash[:], htlc.Timeout, height, err)
+
+			// Retrying is pointless if force closes are yet to
+			// be implemented, so the channel stays marked, and
+			// the failure is only logged once.
+			if err != ErrForceCloseUnimplemented {
+				atomic.StoreInt32(&lc.expiryClosed, 0)
+			}
+			return
+		}
+
+		fmt.Printf("force closed channel %x, incoming htlc %x expires "+
+			"at height %v, current height %v\n",
+			lc.channelState.ChanID[:], htlc.RHash[:], htlc.Timeout,
+			height)
+	}()
+}
+
+// startExpiryWatcher launches the goroutine checking the incoming HTLCs of
+// each started channel for approaching expiry with every new block.
+func (l *LightningWallet) startExpiryWatcher() error {
+	epochChan := make(chan *chainntnfs.BlockEpoch, 1)
+	if err := l.chainNotifier.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+	go l.expiryWatcher(epochChan)
+
+	return nil
+}
+
+// expiryWatcher force closes each started channel with an unsettled incoming
+// HTLC expiring within the grace delta of the latest block.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) expiryWatcher(epochChan chan *chainntnfs.BlockEpoch) {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case epoch := <-epochChan:
+			l.activeChanMtx.Lock()
+			channels := make([]*LightningChannel, 0, len(l.activeChannels))
+			for channel := range l.activeChannels {
+				channels = append(channels, channel)
+			}
+			l.activeChanMtx.Unlock()
+
+			for _, channel := range channels {
+				channel.checkHTLCExpiry(uint32(epoch.Height))
+			}
+
+		case <-l.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package lnwallet
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// newExpiryTestChannel returns a started channel, backed by neither a chain
+// nor a database, with the HTLCs passed pending within it.
+func newExpiryTestChannel(htlcs ...*PaymentDescriptor) *LightningChannel {
+	lc := &LightningChannel{
+		lnwallet: &LightningWallet{
+			activeChannels: make(map[*LightningChannel]struct{}),
+		},
+		channelState:     &channeldb.OpenChannel{},
+		pendingPayments:  make(map[PaymentHash]*PaymentDescriptor),
+		expiryGraceDelta: defaultExpiryGraceDelta,
+		quit:             make(chan struct{}),
+	}
+	for _, htlc := range htlcs {
+		lc.pendingPayments[htlc.RHash] = htlc
+	}
+
+	lc.Start()
+	return lc
+}
+
+// TestCheckHTLCExpiryForceCloseUnimplemented asserts a channel with an
+// incoming HTLC nearing expiry is only marked for a force close once, as
+// force closes are yet to be implemented, so retrying each block is
+// pointless.
+func TestCheckHTLCExpiryForceCloseUnimplemented(t *testing.T) {
+	const height = 1000
+	lc := newExpiryTestChannel(&PaymentDescriptor{
+		RHash:   PaymentHash{1},
+		Timeout: height + defaultExpiryGraceDelta + 1,
+		PayToUs: true,
+	})
+	defer lc.Stop()
+
+	// The HTLC isn't yet within the grace delta, so nothing happens.
+	lc.checkHTLCExpiry(height - 1)
+	lc.wg.Wait()
+	if atomic.LoadInt32(&lc.expiryClosed) != 0 {
+		t.Fatalf("channel closed before htlc neared expiry")
+	}
+
+	// Once it is, the force close is attempted, and the channel stays
+	// marked after it fails.
+	lc.checkHTLCExpiry(height + 1)
+	lc.wg.Wait()
+	if atomic.LoadInt32(&lc.expiryClosed) != 1 {
+		t.Fatalf("channel unmarked after unimplemented force close")
+	}
+}
+
+// TestCheckHTLCExpiryStopped asserts no force close is launched once the
+// channel is stopped.
+func TestCheckHTLCExpiryStopped(t *testing.T) {
+	const height = 1000
+	lc := newExpiryTestChannel(&PaymentDescriptor{
+		RHash:   PaymentHash{1},
+		Timeout: height,
+		PayToUs: true,
+	})
+	lc.Stop()
+
+	lc.checkHTLCExpiry(height)
+	if atomic.LoadInt32(&lc.expiryClosed) != 0 {
+		t.Fatalf("force close launched on stopped channel")
+	}
+}
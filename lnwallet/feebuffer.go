@@ -0,0 +1,91 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// commitBaseSize is the estimated size, in bytes, of a commitment
+	// transaction without any HTLC outputs: the signed 2-of-2 multi-sig
+	// input, and an output for each party.
+	commitBaseSize = 340
+
+	// htlcOutputSize is the size, in bytes, of each HTLC output of a
+	// commitment transaction.
+	htlcOutputSize = 32
+
+	// defaultCommitFeePerKb is the fee rate, in satoshis per kB, commitment
+	// fees are estimated at for channels which didn't negotiate one.
+	defaultCommitFeePerKb = btcutil.Amount(10000)
+
+	// feeBufferMultiplier is the factor the feerate may increase by, while
+	// the initiator of a channel, who pays the commitment fee, remains
+	// able to pay it. The initiator may only add an HTLC should they keep
+	// enough of their balance to pay the commitment fee at this multiple
+	// of the current feerate, with an extra HTLC pending beyond the new
+	// one, so a jump in feerates never leaves the channel unusable, or
+	// forces it to close.
+	feeBufferMultiplier = 2
+)
+
+// ErrBelowFeeBuffer is returned when adding an HTLC would leave the initiator
+// of the channel without the fee buffer they must keep.
+var ErrBelowFeeBuffer = fmt.Errorf("htlc would leave the channel " +
+	"initiator unable to pay the commitment fee should the feerate rise")
+
+// commitFee returns the fee of a commitment transaction with the passed
+// number of HTLC outputs, at the passed fee rate in satoshis per kB.
+func commitFee(feePerKb btcutil.Amount, numHtlcs int) btcutil.Amount {
+	size := btcutil.Amount(commitBaseSize + numHtlcs*htlcOutputSize)
+	return size * feePerKb / 1000
+}
+
+// feeBuffer returns the balance the initiator of the channel must keep, should
+// the commitment have the passed number of HTLC outputs after adding a new
+// HTLC.
+func (lc *LightningChannel) feeBuffer(numHtlcs int) lnwire.MilliSatoshi {
+	feePerKb := lc.channelState.MinFeePerKb
+	if feePerKb == 0 {
+		feePerKb = defaultCommitFeePerKb
+	}
+
+	fee := commitFee(feeBufferMultiplier*feePerKb, numHtlcs+1)
+	return lnwire.NewMSatFromSatoshis(fee)
+}
+
+// validateFeeBuffer returns ErrBelowFeeBuffer if adding an HTLC of the passed
+// value, in the passed direction, would leave the initiator of the channel,
+// should they be the one offering it, with less than their fee buffer.
+func (lc *LightningChannel) validateFeeBuffer(value lnwire.MilliSatoshi,
+	payToUs bool) error {
+
+	// Only the initiator pays the commitment fee, so only HTLCs they offer
+	// eat into their buffer.
+	if payToUs == lc.channelState.IsInitiator {
+		return nil
+	}
+
+	balance := lc.channelState.OurBalance
+	if payToUs {
+		balance = lc.channelState.TheirBalance
+	}
+
+	numHtlcs := 0
+	if !isDustHTLC(value) {
+		numHtlcs++
+	}
+	for _, paymentDesc := range lc.pendingPayments {
+		if !isDustHTLC(paymentDesc.Value) {
+			numHtlcs++
+		}
+	}
+
+	if balance < value || balance-value < lc.feeBuffer(numHtlcs) {
+		return ErrBelowFeeBuffer
+	}
+
+	return nil
+}
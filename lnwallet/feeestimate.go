@@ -0,0 +1,88 @@
+package lnwallet
+
+import (
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+)
+
+const (
+	// DefaultConfTarget is the number of blocks within which transactions
+	// are targeted to confirm, should no target be given.
+	DefaultConfTarget = 6
+
+	// fallbackFeePerKb is the fee rate, in satoshis per kB, used should the
+	// backend lack the data to estimate one.
+	fallbackFeePerKb = btcutil.Amount(10000)
+)
+
+// SendFeeEstimate is the fee a transaction paying a set of outputs is
+// expected to pay, should it be sent now.
+type SendFeeEstimate struct {
+	// Fee is the total fee of the transaction, and FeePerKb the fee rate
+	// it was estimated at. Change too small to be worth an output of its
+	// own is added to the fee.
+	Fee      btcutil.Amount
+	FeePerKb btcutil.Amount
+
+	// NumInputs is the number of our coins the transaction would spend.
+	NumInputs int
+}
+
+// EstimateFeePerKb returns the fee rate, in satoshis per kB, a transaction
+// should pay to confirm within the target number of blocks, as estimated by
+// the backend. Should the backend lack the data to estimate a fee rate,
+// fallbackFeePerKb is returned.
+func (l *LightningWallet) EstimateFeePerKb(confTarget uint32) (btcutil.Amount, error) {
+	if confTarget == 0 {
+		confTarget = DefaultConfTarget
+	}
+
+	btcPerKb, err := l.chainClient().EstimateFee(int64(confTarget))
+	if err != nil {
+		return 0, err
+	}
+	if btcPerKb <= 0 {
+		return fallbackFeePerKb, nil
+	}
+
+	return btcutil.NewAmount(btcPerKb)
+}
+
+// EstimateSendFee selects the coins of the wallet with at least minConfs
+// confirmations which would pay the outputs, just as when funding a
+// transaction, returning the fee the transaction would pay at the passed fee
+// rate. Nothing is locked, nor broadcast.
+func (l *LightningWallet) EstimateSendFee(outputs []*wire.TxOut,
+	feePerKb btcutil.Amount, minConfs int32) (*SendFeeEstimate, error) {
+
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	var outputTotal btcutil.Amount
+	for _, txOut := range outputs {
+		outputTotal += btcutil.Amount(txOut.Value)
+	}
+
+	source := &walletCoinSource{l: l}
+	coins, err := source.coins(minConfs)
+	if err != nil {
+		return nil, err
+	}
+	selected, fee, err := selectCoins(coins, outputTotal, len(outputs),
+		feePerKb)
+	if err != nil {
+		return nil, err
+	}
+
+	inputTotal := coinset.NewCoinSet(selected.Coins()).TotalValue()
+	if change := inputTotal - outputTotal - fee; change < minChangeAmount {
+		fee += change
+	}
+
+	return &SendFeeEstimate{
+		Fee:       fee,
+		FeePerKb:  feePerKb,
+		NumInputs: len(selected.Coins()),
+	}, nil
+}
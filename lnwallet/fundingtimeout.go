@@ -0,0 +1,223 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+
+	"github.com/btcsuite/btcd/wire"
+	"golang.org/x/net/context"
+)
+
+const (
+	// defaultReservationTimeout is how long a reservation may remain
+	// incomplete before it's cancelled, if the config doesn't specify.
+	defaultReservationTimeout = 10 * time.Minute
+
+	// defaultFundingConfTimeout is the number of blocks we wait on the
+	// funding transaction of a channel we didn't fund to confirm before
+	// forgetting the channel, if the config doesn't specify.
+	defaultFundingConfTimeout = 2016
+
+	// reservationCheckInterval is the longest we wait between checking
+	// for reservations which have timed out.
+	reservationCheckInterval = time.Minute
+)
+
+// ErrReservationTimedOut is returned when continuing a reservation which
+// was cancelled after the counterparty failed to complete it in time.
+var ErrReservationTimedOut = errors.New("channel reservation timed out")
+
+// expireReservationsMsg is a message requesting the cancellation of each
+// reservation which expired before the passed cutoff.
+type expireReservationsMsg struct {
+	cutoff time.Time
+}
+
+// pendingFunding is a channel which we didn't fund, whose funding
+// transaction is yet to confirm.
+type pendingFunding struct {
+	res *ChannelReservation
+
+	// blocksLeft is the number of blocks remaining until the channel is
+	// forgotten.
+	blocksLeft uint32
+
+	// timedOut is closed once the channel is forgotten.
+	timedOut chan struct{}
+}
+
+// reservationTimeout returns how long a reservation may remain incomplete.
+func (l *LightningWallet) reservationTimeout() time.Duration {
+	if l.cfg.ReservationTimeout == 0 {
+		return defaultReservationTimeout
+	}
+	return l.cfg.ReservationTimeout
+}
+
+// startFundingTimeouts launches the goroutine cancelling reservations, and
+// forgetting pending channels, which have timed out.
+func (l *LightningWallet) startFundingTimeouts() error {
+	epochChan := make(chan *chainntnfs.BlockEpoch, 1)
+	if err := l.chainNotifier.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+	go l.fundingTimeoutHandler(epochChan)
+
+	return nil
+}
+
+// fundingTimeoutHandler periodically cancels each reservation which has
+// remained incomplete for too long, and with each new block, forgets each
+// pending channel whose funding transaction has failed to confirm in time.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) fundingTimeoutHandler(epochChan chan *chainntnfs.BlockEpoch) {
+	defer l.wg.Done()
+
+	timeout := l.reservationTimeout()
+	interval := timeout
+	if interval > reservationCheckInterval {
+		interval = reservationCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			msg := &expireReservationsMsg{
+				cutoff: time.Now(),
+			}
+			select {
+			case l.msgChan <- msg:
+			case <-l.quit:
+				return
+			}
+
+		case <-epochChan:
+			l.expirePendingFundings()
+
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// handleExpireReservations cancels each reservation which expired before the
+// cutoff, releasing the outputs selected for its funding transaction.
+func (l *LightningWallet) handleExpireReservations(req *expireReservationsMsg) {
+	l.limboMtx.Lock()
+	defer l.limboMtx.Unlock()
+
+	for id, res := range l.fundingLimbo {
+		res.Lock()
+		if !res.expiry.Before(req.cutoff) {
+			res.Unlock()
+			continue
+		}
+
+		for _, unusedInput := range res.ourContribution.Inputs {
+			l.UnlockOutpoint(unusedInput.PreviousOutPoint)
+		}
+		atomic.StoreInt32(&res.timedOut, 1)
+		res.finish(ErrReservationTimedOut)
+		res.Unlock()
+
+		delete(l.fundingLimbo, id)
+	}
+}
+
+// cancelReservationOnDone cancels the reservation, releasing its coins,
+// should the context it was created under be done before the reservation is
+// no longer pending.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) cancelReservationOnDone(ctx context.Context,
+	res *ChannelReservation) {
+
+	defer l.wg.Done()
+
+	select {
+	case <-ctx.Done():
+	case <-res.Done():
+		return
+	case <-l.quit:
+		return
+	}
+
+	// The reservation may have completed concurrently, in which case it's
+	// no longer in limbo, and there's nothing to release.
+	err := res.cancel(ctx.Err())
+	if err != nil && err != ErrReservationNotFound {
+		fmt.Printf("unable to cancel reservation %v: %v\n",
+			res.reservationID, err)
+	}
+}
+
+// trackPendingFunding starts the countdown to forgetting the channel of the
+// reservation, unless its funding transaction confirms first. The returned
+// channel is closed once the channel is forgotten.
+func (l *LightningWallet) trackPendingFunding(res *ChannelReservation) <-chan struct{} {
+	l.pendingFundingMtx.Lock()
+	defer l.pendingFundingMtx.Unlock()
+
+	blocksLeft := l.cfg.FundingConfTimeout
+	if blocksLeft == 0 {
+		blocksLeft = defaultFundingConfTimeout
+	}
+
+	pending := &pendingFunding{
+		res:        res,
+		blocksLeft: blocksLeft,
+		timedOut:   make(chan struct{}),
+	}
+	l.pendingFundings[res.partialState.FundingTx.TxSha()] = pending
+
+	return pending.timedOut
+}
+
+// confirmPendingFunding stops tracking the channel of the funding
+// transaction now that it's confirmed, returning false if the channel was
+// already forgotten.
+func (l *LightningWallet) confirmPendingFunding(txid *wire.ShaHash) bool {
+	l.pendingFundingMtx.Lock()
+	defer l.pendingFundingMtx.Unlock()
+
+	if _, ok := l.pendingFundings[*txid]; !ok {
+		return false
+	}
+	delete(l.pendingFundings, *txid)
+	return true
+}
+
+// expirePendingFundings counts down each pending channel by a block,
+// forgetting those whose funding transaction has failed to confirm in time.
+// As we didn't fund these channels, we've nothing to lose by forgetting
+// them, while the funder may well have double spent the funding
+// transaction.
+func (l *LightningWallet) expirePendingFundings() {
+	l.pendingFundingMtx.Lock()
+	defer l.pendingFundingMtx.Unlock()
+
+	for txid, pending := range l.pendingFundings {
+		pending.blocksLeft--
+		if pending.blocksLeft > 0 {
+			continue
+		}
+
+		nodeID := pending.res.partialState.TheirLNID
+		if err := l.ChannelDB.DeleteOpenChannel(nodeID); err != nil {
+			fmt.Printf("unable to forget pending channel with "+
+				"funding txid %v: %v\n", txid, err)
+		}
+
+		delete(l.pendingFundings, txid)
+		close(pending.timedOut)
+	}
+}
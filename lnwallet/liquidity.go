@@ -0,0 +1,79 @@
+package lnwallet
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// SendableBalance returns the most we're able to offer the counterparty in a
+// new HTLC: our balance, less the reserve they require of us, our HTLCs
+// already in flight, and, if we're the initiator, the fee buffer we must
+// keep.
+func (lc *LightningChannel) SendableBalance() lnwire.MilliSatoshi {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return lc.availableBalance(false)
+}
+
+// ReceivableBalance returns the most the counterparty is able to offer us in
+// a new HTLC: their balance, less the reserve we require of them, their
+// HTLCs already in flight, and, if they're the initiator, the fee buffer
+// they must keep.
+func (lc *LightningChannel) ReceivableBalance() lnwire.MilliSatoshi {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return lc.availableBalance(true)
+}
+
+// UnsettledBalances returns the total value of the HTLCs pending within the
+// channel which we've offered, and of those the counterparty has offered us.
+func (lc *LightningChannel) UnsettledBalances() (ours, theirs lnwire.MilliSatoshi) {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	for _, paymentDesc := range lc.pendingPayments {
+		if paymentDesc.PayToUs {
+			theirs += paymentDesc.Value
+		} else {
+			ours += paymentDesc.Value
+		}
+	}
+	return ours, theirs
+}
+
+// availableBalance returns the most the offering party is able to offer in a
+// new HTLC, with payToUs set should the counterparty be the one offering it.
+//
+// NOTE: This MUST be called with stateMtx held.
+func (lc *LightningChannel) availableBalance(payToUs bool) lnwire.MilliSatoshi {
+	balance := lc.channelState.OurBalance
+	reserve := lc.channelState.TheirChanReserve
+	if payToUs {
+		balance = lc.channelState.TheirBalance
+		reserve = lc.channelState.OurChanReserve
+	}
+
+	unavailable := lnwire.NewMSatFromSatoshis(reserve)
+	numHtlcs := 0
+	for _, paymentDesc := range lc.pendingPayments {
+		if paymentDesc.PayToUs == payToUs {
+			unavailable += paymentDesc.Value
+		}
+		if !isDustHTLC(paymentDesc.Value) {
+			numHtlcs++
+		}
+	}
+
+	// Only the initiator pays the commitment fee, and so keeps a buffer
+	// for it. We assume the new HTLC isn't dust, and so has an output of
+	// its own.
+	if payToUs != lc.channelState.IsInitiator {
+		unavailable += lc.feeBuffer(numHtlcs + 1)
+	}
+
+	if balance <= unavailable {
+		return 0
+	}
+	return balance - unavailable
+}
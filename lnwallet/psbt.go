@@ -0,0 +1,521 @@
+package lnwallet
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/psbt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/coinset"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+const (
+	// The estimated sizes of the components of a transaction spending
+	// P2PKH outputs, used to compute the fee when funding a PSBT.
+	p2pkhInputSize  = 148
+	p2pkhOutputSize = 34
+	txOverheadSize  = 10
+
+	// minChangeAmount is the smallest change output we'll create. Any
+	// less is added to the fee instead.
+	minChangeAmount = 5460
+)
+
+// psbtCoinSource is a set of coins which may be used to fund a PSBT, either
+// those of the wallet's default account, or those of a watch-only account.
+type psbtCoinSource interface {
+	// coins returns the unlocked coins of the source with at least
+	// minConfs confirmations.
+	coins(minConfs int32) ([]coinset.Coin, error)
+
+	// populateInput adds the information a signer requires to spend the
+	// passed coin to the input.
+	populateInput(pIn *psbt.PInput, op *wire.OutPoint) error
+
+	// changeOutput returns the output script of a fresh change output,
+	// along with the information allowing a signer to recognize it.
+	changeOutput() ([]byte, *psbt.POutput, error)
+
+	// lockCoin prevents the coin from being selected again.
+	lockCoin(op *wire.OutPoint)
+}
+
+// walletCoinSource funds PSBTs from the default account of the wallet.
+type walletCoinSource struct {
+	l *LightningWallet
+}
+
+func (w *walletCoinSource) coins(minConfs int32) ([]coinset.Coin, error) {
+	unspentOutputs, err := w.l.ListUnspent(minConfs, math.MaxInt32, nil)
+	if err != nil {
+		return nil, err
+	}
+	return outputsToCoins(unspentOutputs)
+}
+
+func (w *walletCoinSource) populateInput(pIn *psbt.PInput, op *wire.OutPoint) error {
+	txDetail, err := w.l.TxStore.TxDetails(&op.Hash)
+	if err != nil {
+		return err
+	}
+	if txDetail == nil {
+		return fmt.Errorf("unable to find transaction %v", op.Hash)
+	}
+
+	prevTx := txDetail.TxRecord.MsgTx
+	pIn.NonWitnessUtxo = &prevTx
+	pIn.SighashType = uint32(txscript.SigHashAll)
+	return nil
+}
+
+func (w *walletCoinSource) changeOutput() ([]byte, *psbt.POutput, error) {
+	changeAddr, err := w.l.NewChangeAddress(waddrmgr.DefaultAccountNum)
+	if err != nil {
+		return nil, nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return changeScript, &psbt.POutput{}, nil
+}
+
+func (w *walletCoinSource) lockCoin(op *wire.OutPoint) {
+	w.l.LockOutpoint(*op)
+}
+
+// watchOnlyCoinSource funds PSBTs from a watch-only account. The derivation
+// of each key is included, so the external signer may find its keys.
+type watchOnlyCoinSource struct {
+	l       *LightningWallet
+	account *channeldb.WatchOnlyAccount
+	utxos   map[wire.OutPoint]*channeldb.WatchOnlyUtxo
+}
+
+func (w *watchOnlyCoinSource) coins(minConfs int32) ([]coinset.Coin, error) {
+	utxos, err := w.l.ChannelDB.FetchWatchOnlyUtxos(w.account.Name)
+	if err != nil {
+		return nil, err
+	}
+	_, bestHeight, err := w.l.chainClient().GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var coins []coinset.Coin
+	for _, utxo := range utxos {
+		if _, ok := w.l.lockedWatchOnly[utxo.OutPoint]; ok {
+			continue
+		}
+
+		var numConfs int64
+		if utxo.BlockHeight != 0 {
+			numConfs = int64(bestHeight) - int64(utxo.BlockHeight) + 1
+		}
+		if numConfs < int64(minConfs) {
+			continue
+		}
+
+		hash := utxo.OutPoint.Hash
+		coins = append(coins, &lnCoin{
+			hash:     &hash,
+			index:    utxo.OutPoint.Index,
+			value:    utxo.Value,
+			pkScript: utxo.PkScript,
+			numConfs: numConfs,
+			valueAge: numConfs * int64(utxo.Value),
+		})
+		w.utxos[utxo.OutPoint] = utxo
+	}
+
+	return coins, nil
+}
+
+func (w *watchOnlyCoinSource) populateInput(pIn *psbt.PInput, op *wire.OutPoint) error {
+	utxo, ok := w.utxos[*op]
+	if !ok {
+		return fmt.Errorf("output %v isn't controlled by account %v",
+			op, w.account.Name)
+	}
+
+	pIn.NonWitnessUtxo = utxo.PrevTx
+	pIn.SighashType = uint32(txscript.SigHashAll)
+	pIn.Bip32Derivation = []*psbt.Bip32Derivation{
+		{
+			PubKey:               utxo.PubKey[:],
+			MasterKeyFingerprint: w.account.MasterKeyFingerprint,
+			Path:                 utxo.DerivationPath,
+		},
+	}
+	return nil
+}
+
+func (w *watchOnlyCoinSource) changeOutput() ([]byte, *psbt.POutput, error) {
+	changeAddr, key, err := w.l.nextWatchOnlyKey(w.account.Name, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pOut := &psbt.POutput{
+		Bip32Derivation: []*psbt.Bip32Derivation{
+			{
+				PubKey:               key.pubKey[:],
+				MasterKeyFingerprint: w.account.MasterKeyFingerprint,
+				Path:                 key.path,
+			},
+		},
+	}
+	return changeScript, pOut, nil
+}
+
+func (w *watchOnlyCoinSource) lockCoin(op *wire.OutPoint) {
+	w.l.lockedWatchOnly[*op] = struct{}{}
+}
+
+// FundPsbt funds the passed template packet at the passed fee rate. If the
+// template has no inputs, enough coins with at least minConfs confirmations
+// are selected to pay for its outputs. Otherwise its inputs are used as is,
+// and must all be controlled by the funding account. Any change is sent to a
+// fresh change address. The template may pay to the funding output of a
+// channel, allowing channels to be funded from an external signer.
+//
+// Coins are taken from the default account of the wallet if account is
+// empty, or from the named watch-only account otherwise. The inputs of the
+// packet are locked, so they won't be used to fund anything else in the
+// meantime. The index of the change output is returned, or -1 if there's no
+// change.
+func (l *LightningWallet) FundPsbt(packet *psbt.Packet, account string,
+	feePerKb btcutil.Amount, minConfs int32) (int, error) {
+
+	if err := packet.SanityCheck(); err != nil {
+		return -1, err
+	}
+
+	var source psbtCoinSource
+	if account == "" {
+		l.coinSelectMtx.Lock()
+		defer l.coinSelectMtx.Unlock()
+
+		source = &walletCoinSource{l: l}
+	} else {
+		l.watchOnlyMtx.Lock()
+		defer l.watchOnlyMtx.Unlock()
+
+		watchOnlyAccount, err := l.ChannelDB.FetchWatchOnlyAccount(account)
+		if err != nil {
+			return -1, err
+		}
+		source = &watchOnlyCoinSource{
+			l:       l,
+			account: watchOnlyAccount,
+			utxos:   make(map[wire.OutPoint]*channeldb.WatchOnlyUtxo),
+		}
+	}
+
+	tx := packet.UnsignedTx
+	var outputTotal btcutil.Amount
+	for _, txOut := range tx.TxOut {
+		outputTotal += btcutil.Amount(txOut.Value)
+	}
+
+	var (
+		inputTotal btcutil.Amount
+		fee        btcutil.Amount
+	)
+	if len(tx.TxIn) == 0 {
+		coins, err := source.coins(minConfs)
+		if err != nil {
+			return -1, err
+		}
+
+		var selected coinset.Coins
+		selected, fee, err = selectCoins(coins, outputTotal,
+			len(tx.TxOut), feePerKb)
+		if err != nil {
+			return -1, err
+		}
+
+		for _, coin := range selected.Coins() {
+			tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(coin.Hash(),
+				coin.Index()), nil))
+			packet.Inputs = append(packet.Inputs, psbt.PInput{})
+		}
+		inputTotal = coinset.NewCoinSet(selected.Coins()).TotalValue()
+	} else {
+		// Each input of the template must be one of our unlocked
+		// coins.
+		coins, err := source.coins(0)
+		if err != nil {
+			return -1, err
+		}
+		coinValues := make(map[wire.OutPoint]btcutil.Amount)
+		for _, coin := range coins {
+			coinValues[*wire.NewOutPoint(coin.Hash(), coin.Index())] = coin.Value()
+		}
+
+		for _, txIn := range tx.TxIn {
+			value, ok := coinValues[txIn.PreviousOutPoint]
+			if !ok {
+				return -1, fmt.Errorf("input %v is unknown or "+
+					"locked", txIn.PreviousOutPoint)
+			}
+			inputTotal += value
+		}
+
+		fee = estimateTxFee(len(tx.TxIn), len(tx.TxOut), feePerKb)
+		if inputTotal < outputTotal+fee {
+			return -1, ErrInsufficientFunds
+		}
+	}
+
+	for i, txIn := range tx.TxIn {
+		if packet.Inputs[i].NonWitnessUtxo != nil {
+			continue
+		}
+		err := source.populateInput(&packet.Inputs[i],
+			&txIn.PreviousOutPoint)
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	changeIndex := -1
+	changeAmt := inputTotal - outputTotal - fee
+	if changeAmt >= minChangeAmount {
+		changeScript, pOut, err := source.changeOutput()
+		if err != nil {
+			return -1, err
+		}
+
+		changeIndex = len(tx.TxOut)
+		tx.AddTxOut(wire.NewTxOut(int64(changeAmt), changeScript))
+		packet.Outputs = append(packet.Outputs, *pOut)
+	}
+
+	for _, txIn := range tx.TxIn {
+		source.lockCoin(&txIn.PreviousOutPoint)
+	}
+
+	return changeIndex, nil
+}
+
+// estimateTxFee returns the fee, at the passed fee rate, of a transaction
+// spending the number of P2PKH inputs to the number of outputs, along with a
+// change output.
+func estimateTxFee(numInputs, numOutputs int,
+	feePerKb btcutil.Amount) btcutil.Amount {
+
+	size := txOverheadSize + numInputs*p2pkhInputSize +
+		(numOutputs+1)*p2pkhOutputSize
+	return feePerKb * btcutil.Amount(size) / 1000
+}
+
+// selectCoins selects enough of the coins to pay the output total, along
+// with the fee, at the passed fee rate, of a transaction spending them to
+// the number of outputs. The selected coins are returned with that fee.
+func selectCoins(coins []coinset.Coin, outputTotal btcutil.Amount,
+	numOutputs int, feePerKb btcutil.Amount) (coinset.Coins,
+	btcutil.Amount, error) {
+
+	// The fee depends on the number of inputs selected, so keep selecting
+	// until the fee estimated for the selected inputs is covered.
+	selector := &coinset.MaxValueAgeCoinSelector{
+		MaxInputs:       100,
+		MinChangeAmount: 0,
+	}
+	for numInputs := 1; ; {
+		fee := estimateTxFee(numInputs, numOutputs, feePerKb)
+		selected, err := selector.CoinSelect(outputTotal+fee, coins)
+		if err != nil {
+			return nil, 0, ErrInsufficientFunds
+		}
+		if len(selected.Coins()) <= numInputs {
+			return selected, fee, nil
+		}
+		numInputs = len(selected.Coins())
+	}
+}
+
+// SignPsbt adds signatures to each input of the packet spending an output
+// which the wallet holds the keys for. P2PKH inputs, and P2SH inputs with a
+// multisig redeem script, such as channel funding outputs, are signed.
+// Inputs the wallet can't sign for are skipped. The indexes of the inputs
+// signed are returned.
+func (l *LightningWallet) SignPsbt(packet *psbt.Packet) ([]int, error) {
+	if err := packet.SanityCheck(); err != nil {
+		return nil, err
+	}
+
+	var signedInputs []int
+	tx := packet.UnsignedTx
+	for i, txIn := range tx.TxIn {
+		pIn := &packet.Inputs[i]
+		if pIn.FinalScriptSig != nil || pIn.NonWitnessUtxo == nil {
+			continue
+		}
+
+		hashType := txscript.SigHashAll
+		if pIn.SighashType != 0 {
+			hashType = txscript.SigHashType(pIn.SighashType)
+		}
+
+		prevOut := txIn.PreviousOutPoint
+		pkScript := pIn.NonWitnessUtxo.TxOut[prevOut.Index].PkScript
+
+		// The script signed, along with the keys which may sign it,
+		// depends on the type of output spent.
+		var (
+			signScript []byte
+			addrs      []btcutil.Address
+			err        error
+		)
+		switch txscript.GetScriptClass(pkScript) {
+		case txscript.PubKeyHashTy:
+			signScript = pkScript
+			_, addrs, _, err = txscript.ExtractPkScriptAddrs(pkScript,
+				ActiveNetParams)
+		case txscript.ScriptHashTy:
+			if pIn.RedeemScript == nil {
+				continue
+			}
+			signScript = pIn.RedeemScript
+			_, addrs, _, err = txscript.ExtractPkScriptAddrs(
+				pIn.RedeemScript, ActiveNetParams)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		signed := false
+		for _, addr := range addrs {
+			pubKey, privKey, err := l.psbtSigningKey(addr)
+			if err != nil {
+				return nil, err
+			}
+			if privKey == nil || hasPartialSig(pIn, pubKey) {
+				continue
+			}
+
+			sig, err := txscript.RawTxInSignature(tx, i, signScript,
+				hashType, privKey)
+			if err != nil {
+				return nil, err
+			}
+			pIn.PartialSigs = append(pIn.PartialSigs, &psbt.PartialSig{
+				PubKey:    pubKey,
+				Signature: sig,
+			})
+			signed = true
+		}
+		if signed {
+			signedInputs = append(signedInputs, i)
+		}
+	}
+
+	return signedInputs, nil
+}
+
+// FinalizePsbt signs any inputs of the packet the wallet can sign for, then
+// finalizes the packet, returning the signed transaction ready for
+// broadcast. An error is returned if any input lacks the signatures
+// required, or doesn't validate.
+func (l *LightningWallet) FinalizePsbt(packet *psbt.Packet) (*wire.MsgTx, error) {
+	if _, err := l.SignPsbt(packet); err != nil {
+		return nil, err
+	}
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return nil, err
+	}
+	finalTx, err := psbt.Extract(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure each input is fully valid before handing the transaction
+	// back for broadcast.
+	for i, txIn := range finalTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		utxo := packet.Inputs[i].NonWitnessUtxo
+		if utxo == nil {
+			return nil, fmt.Errorf("input %d has no utxo", i)
+		}
+
+		vm, err := txscript.NewEngine(utxo.TxOut[prevOut.Index].PkScript,
+			finalTx, i, txscript.StandardVerifyFlags, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := vm.Execute(); err != nil {
+			return nil, fmt.Errorf("input %d is invalid: %v", i, err)
+		}
+	}
+
+	return finalTx, nil
+}
+
+// psbtSigningKey returns the serialized public key, and the private key of
+// the passed address if it belongs to the wallet. A nil private key is
+// returned for addresses the wallet doesn't know of.
+func (l *LightningWallet) psbtSigningKey(addr btcutil.Address) ([]byte, *btcec.PrivateKey, error) {
+	var (
+		pkh    *btcutil.AddressPubKeyHash
+		pubKey []byte
+	)
+	switch a := addr.(type) {
+	case *btcutil.AddressPubKeyHash:
+		pkh = a
+	case *btcutil.AddressPubKey:
+		pkh = a.AddressPubKeyHash()
+		pubKey = a.ScriptAddress()
+	default:
+		return nil, nil, nil
+	}
+
+	ai, err := l.Manager.Address(pkh)
+	if err != nil {
+		// TODO: distinguish unknown addresses from other
+		// errors
+		return nil, nil, nil
+	}
+	pka, ok := ai.(waddrmgr.ManagedPubKeyAddress)
+	if !ok {
+		return nil, nil, nil
+	}
+	privKey, err := pka.PrivKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get private key: %v", err)
+	}
+
+	if pubKey == nil {
+		if ai.Compressed() {
+			pubKey = privKey.PubKey().SerializeCompressed()
+		} else {
+			pubKey = privKey.PubKey().SerializeUncompressed()
+		}
+	}
+
+	return pubKey, privKey, nil
+}
+
+// hasPartialSig returns true if the input already carries a signature from
+// the passed key.
+func hasPartialSig(pIn *psbt.PInput, pubKey []byte) bool {
+	for _, sig := range pIn.PartialSigs {
+		if string(sig.PubKey) == string(pubKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// rebroadcastRetryInterval is how often we attempt to re-publish our pending
+// transactions after failing to reach the backend, so they're re-published
+// soon after it reconnects rather than only once the next block arrives.
+const rebroadcastRetryInterval = time.Minute
+
+var (
+	// ErrDoubleSpend is returned when publishing a transaction spending an
+	// output already spent by another transaction, either within the
+	// mempool or the chain.
+	ErrDoubleSpend = errors.New("transaction double spends an output")
+
+	// ErrFeeTooLow is returned when publishing a transaction whose fee is
+	// below what the backend requires to relay it.
+	ErrFeeTooLow = errors.New("transaction fee too low")
+
+	// ErrMissingInputs is returned when publishing a transaction spending
+	// outputs unknown to the backend.
+	ErrMissingInputs = errors.New("transaction spends unknown outputs")
+)
+
+// publishErrors maps fragments of the messages btcd, and bitcoind, reject
+// transactions from their mempool with to the error they're returned as.
+var publishErrors = []struct {
+	fragment string
+	err      error
+}{
+	{"already spent", ErrDoubleSpend},
+	{"double spend", ErrDoubleSpend},
+	{"txn-mempool-conflict", ErrDoubleSpend},
+	{"orphan transaction", ErrMissingInputs},
+	{"missingorspent", ErrMissingInputs},
+	{"missing inputs", ErrMissingInputs},
+	{"insufficient priority", ErrFeeTooLow},
+	{"under the required amount", ErrFeeTooLow},
+	{"min relay fee not met", ErrFeeTooLow},
+	{"mempool min fee not met", ErrFeeTooLow},
+	{"insufficient fee", ErrFeeTooLow},
+}
+
+// PublishTransaction broadcasts the passed transaction to the network. The
+// transaction is then tracked until it confirms, being re-published with
+// each new block, and once the backend reconnects after being unreachable.
+// The label describes what the transaction is for, e.g. "funding".
+//
+// If the backend rejects the transaction, it isn't tracked, and the error is
+// returned: ErrDoubleSpend, ErrFeeTooLow, or ErrMissingInputs for the common
+// reasons of rejection. If the backend can't be reached, the transaction is
+// still tracked, but the error is returned.
+func (l *LightningWallet) PublishTransaction(tx *wire.MsgTx, label string) error {
+	l.broadcastMtx.Lock()
+	defer l.broadcastMtx.Unlock()
+
+	txid := tx.TxSha()
+	if _, ok := l.pendingBroadcasts[txid]; ok {
+		return nil
+	}
+
+	pending := &channeldb.PendingBroadcast{
+		Tx:             tx,
+		Label:          label,
+		FirstBroadcast: time.Now(),
+	}
+
+	err := l.broadcast(pending)
+	if err != nil && !isBackendError(err) {
+		return mapPublishError(err)
+	}
+
+	if err := l.ChannelDB.PutPendingBroadcast(pending); err != nil {
+		return err
+	}
+	l.trackBroadcast(pending)
+
+	return err
+}
+
+// PendingBroadcasts returns all transactions we've published which have yet
+// to confirm, along with the error returned when each was last published.
+func (l *LightningWallet) PendingBroadcasts() []*channeldb.PendingBroadcast {
+	l.broadcastMtx.Lock()
+	defer l.broadcastMtx.Unlock()
+
+	pending := make([]*channeldb.PendingBroadcast, 0,
+		len(l.pendingBroadcasts))
+	for _, p := range l.pendingBroadcasts {
+		pendingCopy := *p
+		pending = append(pending, &pendingCopy)
+	}
+	return pending
+}
+
+// startRebroadcaster loads all pending transactions, and launches the
+// goroutine which re-publishes them.
+func (l *LightningWallet) startRebroadcaster() error {
+	l.broadcastMtx.Lock()
+	defer l.broadcastMtx.Unlock()
+
+	broadcasts, err := l.ChannelDB.FetchPendingBroadcasts()
+	if err != nil {
+		return err
+	}
+	for _, pending := range broadcasts {
+		l.trackBroadcast(pending)
+	}
+
+	epochChan := make(chan *chainntnfs.BlockEpoch, 20)
+	if err := l.chainNotifier.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+	txChan := make(chan *chainntnfs.RelevantTx, 20)
+	if err := l.chainNotifier.RegisterRelevantTxNotification(txChan); err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+	go l.rebroadcaster(epochChan, txChan)
+
+	// Anything left over from before we were shut down may have been
+	// dropped from the mempool since, so publish it all straight away.
+	l.rebroadcastAll()
+
+	return nil
+}
+
+// rebroadcaster re-publishes our pending transactions with each new block,
+// and removes them once confirmed or double spent.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) rebroadcaster(epochChan chan *chainntnfs.BlockEpoch,
+	txChan chan *chainntnfs.RelevantTx) {
+
+	defer l.wg.Done()
+
+	retryTicker := time.NewTicker(rebroadcastRetryInterval)
+	defer retryTicker.Stop()
+
+	for {
+		select {
+		case <-epochChan:
+			l.broadcastMtx.Lock()
+			l.rebroadcastAll()
+			l.broadcastMtx.Unlock()
+
+		case <-retryTicker.C:
+			l.broadcastMtx.Lock()
+			if l.broadcastRetry {
+				l.rebroadcastAll()
+			}
+			l.broadcastMtx.Unlock()
+
+		case relevantTx := <-txChan:
+			if err := l.processBroadcastTx(relevantTx); err != nil {
+				fmt.Printf("unable to process relevant tx: "+
+					"%v\n", err)
+			}
+
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// processBroadcastTx stops tracking any pending transaction which the passed
+// relevant transaction either confirms, or double spends.
+func (l *LightningWallet) processBroadcastTx(relevantTx *chainntnfs.RelevantTx) error {
+	l.broadcastMtx.Lock()
+	defer l.broadcastMtx.Unlock()
+
+	tx := relevantTx.Tx
+	txid := tx.TxSha()
+	mined := relevantTx.BlockHeight != 0
+
+	if _, ok := l.pendingBroadcasts[txid]; ok {
+		if !mined {
+			return nil
+		}
+		return l.untrackBroadcast(&txid)
+	}
+
+	for _, txIn := range tx.TxIn {
+		pendingTxid, ok := l.broadcastInputs[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+		pending := l.pendingBroadcasts[pendingTxid]
+
+		// Until the conflicting transaction is mined, ours may still
+		// confirm instead, so we keep publishing it.
+		if !mined {
+			pending.LastError = fmt.Sprintf("conflicts with "+
+				"unconfirmed transaction %v", txid)
+			if err := l.ChannelDB.PutPendingBroadcast(pending); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// TODO: log, and notify the owner of the transaction
+		fmt.Printf("%v transaction %v double spent by %v\n",
+			pending.Label, pendingTxid, txid)
+		if err := l.untrackBroadcast(&pendingTxid); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rebroadcastAll re-publishes each pending transaction, recording the result
+// of each attempt.
+//
+// NOTE: The broadcastMtx MUST be held when calling this method.
+func (l *LightningWallet) rebroadcastAll() {
+	retry := false
+	for _, pending := range l.pendingBroadcasts {
+		if err := l.broadcast(pending); isBackendError(err) {
+			retry = true
+		}
+		if err := l.ChannelDB.PutPendingBroadcast(pending); err != nil {
+			fmt.Printf("unable to record broadcast: %v\n", err)
+		}
+	}
+	l.broadcastRetry = retry
+}
+
+// broadcast publishes the pending transaction, recording the attempt.
+func (l *LightningWallet) broadcast(pending *channeldb.PendingBroadcast) error {
+	pending.Attempts++
+
+	// We may not yet be connected to the backend if called before the
+	// wallet is started.
+	rpc := l.chainClient()
+	if rpc == nil {
+		pending.LastError = "not connected to backend"
+		return nil
+	}
+
+	_, err := rpc.SendRawTransaction(pending.Tx, false)
+	switch {
+	case err == nil || isAlreadyKnown(err):
+		pending.LastError = ""
+		return nil
+	default:
+		pending.LastError = err.Error()
+		return err
+	}
+}
+
+// trackBroadcast starts tracking the pending transaction.
+//
+// NOTE: The broadcastMtx MUST be held when calling this method.
+func (l *LightningWallet) trackBroadcast(pending *channeldb.PendingBroadcast) {
+	txid := pending.Tx.TxSha()
+	l.pendingBroadcasts[txid] = pending
+	for _, txIn := range pending.Tx.TxIn {
+		l.broadcastInputs[txIn.PreviousOutPoint] = txid
+	}
+}
+
+// untrackBroadcast stops tracking the pending transaction, removing it from
+// the database.
+//
+// NOTE: The broadcastMtx MUST be held when calling this method.
+func (l *LightningWallet) untrackBroadcast(txid *wire.ShaHash) error {
+	pending, ok := l.pendingBroadcasts[*txid]
+	if !ok {
+		return nil
+	}
+
+	for _, txIn := range pending.Tx.TxIn {
+		delete(l.broadcastInputs, txIn.PreviousOutPoint)
+	}
+	delete(l.pendingBroadcasts, *txid)
+
+	return l.ChannelDB.DeletePendingBroadcast(txid)
+}
+
+// isBackendError returns true if the error was caused by failing to reach the
+// backend, rather than the backend rejecting the transaction.
+func isBackendError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*btcjson.RPCError)
+	return !ok
+}
+
+// mapPublishError maps the error the backend rejected a transaction with to
+// ErrDoubleSpend, ErrFeeTooLow, or ErrMissingInputs, returning any other
+// error as is.
+func mapPublishError(err error) error {
+	rpcErr, ok := err.(*btcjson.RPCError)
+	if !ok {
+		return err
+	}
+
+	msg := strings.ToLower(rpcErr.Message)
+	for _, publishErr := range publishErrors {
+		if strings.Contains(msg, publishErr.fragment) {
+			return publishErr.err
+		}
+	}
+	return err
+}
+
+// isAlreadyKnown returns true if the backend rejected the transaction only
+// because it already has it, either in its mempool or in the chain.
+func isAlreadyKnown(err error) bool {
+	rpcErr, ok := err.(*btcjson.RPCError)
+	if !ok {
+		return false
+	}
+	return strings.Contains(rpcErr.Message, "already have transaction") ||
+		strings.Contains(rpcErr.Message, "transaction already exists")
+}
@@ -0,0 +1,179 @@
+package lnwallet
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+)
+
+// recoveryBatchSize is the number of blocks rescanned at once when
+// recovering funds, bounding how long a shutdown waits on the rescan, and
+// how often recovery progress is updated.
+const recoveryBatchSize = 2000
+
+// RecoveryInfo describes the progress of the rescan recovering the on-chain
+// funds of a wallet restored from its seed.
+type RecoveryInfo struct {
+	// RecoveryMode is true if the wallet was restored from its seed with a
+	// non-zero recovery window.
+	RecoveryMode bool
+
+	// RecoveryFinished is true once the rescan has reached the tip of the
+	// chain at the time recovery started.
+	RecoveryFinished bool
+
+	// Progress is the fraction of the chain rescanned so far, between 0
+	// and 1.
+	Progress float64
+
+	// Err is the error which halted the rescan, if any.
+	Err error
+}
+
+// RecoveryInfo returns the progress of the rescan recovering the wallet's
+// funds after being restored from its seed.
+func (l *LightningWallet) RecoveryInfo() *RecoveryInfo {
+	l.recoveryMtx.Lock()
+	defer l.recoveryMtx.Unlock()
+
+	info := &RecoveryInfo{
+		RecoveryMode:     l.recoveryMode,
+		RecoveryFinished: l.recoveryFinished,
+		Err:              l.recoveryErr,
+	}
+	switch {
+	case l.recoveryFinished:
+		info.Progress = 1
+	case l.recoveryTarget > 0:
+		info.Progress = float64(l.recoveryHeight) /
+			float64(l.recoveryTarget)
+	}
+
+	return info
+}
+
+// startRecovery derives the addresses within the recovery window of both
+// the external and internal branches of the default account, then launches
+// the goroutine rescanning the chain for payments to them. It's a no-op
+// unless the wallet was just restored from its seed.
+//
+// TODO: persist recovery state so an interrupted rescan resumes
+// on restart
+func (l *LightningWallet) startRecovery() error {
+	if !l.recoveryMode {
+		return nil
+	}
+
+	window := l.cfg.RecoveryWindow
+	external, err := l.Manager.NextExternalAddresses(
+		waddrmgr.DefaultAccountNum, window)
+	if err != nil {
+		return err
+	}
+	internal, err := l.Manager.NextInternalAddresses(
+		waddrmgr.DefaultAccountNum, window)
+	if err != nil {
+		return err
+	}
+
+	addrs := make([]btcutil.Address, 0, len(external)+len(internal))
+	for _, addr := range append(external, internal...) {
+		addrs = append(addrs, addr.Address())
+	}
+
+	// Register the addresses first, so payments to them within blocks
+	// connected after the rescan's target are also found.
+	if err := l.chainClient().NotifyReceived(addrs); err != nil {
+		return err
+	}
+
+	_, bestHeight, err := l.chainClient().GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	l.recoveryMtx.Lock()
+	l.recoveryTarget = bestHeight
+	l.recoveryMtx.Unlock()
+
+	l.wg.Add(1)
+	go l.recoveryRescan(addrs, bestHeight)
+
+	return nil
+}
+
+// recoveryRescan rescans the chain from genesis up to the target height, in
+// batches of recoveryBatchSize blocks, for payments to the passed addresses.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) recoveryRescan(addrs []btcutil.Address,
+	target int32) {
+
+	defer l.wg.Done()
+
+	var height int32
+	for height <= target {
+		select {
+		case <-l.quit:
+			return
+		default:
+		}
+
+		endHeight := height + recoveryBatchSize - 1
+		if endHeight > target {
+			endHeight = target
+		}
+
+		if err := l.rescanBatch(addrs, height, endHeight); err != nil {
+			fmt.Printf("unable to rescan for recovery: %v\n", err)
+
+			l.recoveryMtx.Lock()
+			l.recoveryErr = err
+			l.recoveryMtx.Unlock()
+			return
+		}
+		height = endHeight + 1
+
+		l.recoveryMtx.Lock()
+		l.recoveryHeight = endHeight
+		l.recoveryMtx.Unlock()
+	}
+
+	l.recoveryMtx.Lock()
+	l.recoveryFinished = true
+	l.recoveryMtx.Unlock()
+}
+
+// rescanBatch rescans the blocks from startHeight up to endHeight for
+// payments to the passed addresses. All unspent outputs of the wallet are
+// included, so spends of outputs recovered by earlier batches are also found.
+func (l *LightningWallet) rescanBatch(addrs []btcutil.Address,
+	startHeight, endHeight int32) error {
+
+	startHash, err := l.chainClient().GetBlockHash(int64(startHeight))
+	if err != nil {
+		return err
+	}
+	endHash, err := l.chainClient().GetBlockHash(int64(endHeight))
+	if err != nil {
+		return err
+	}
+
+	unspent, err := l.ListUnspent(0, math.MaxInt32, nil)
+	if err != nil {
+		return err
+	}
+	outPoints := make([]*wire.OutPoint, 0, len(unspent))
+	for _, output := range unspent {
+		txid, err := wire.NewShaHashFromStr(output.TxID)
+		if err != nil {
+			return err
+		}
+		outPoints = append(outPoints, wire.NewOutPoint(txid, output.Vout))
+	}
+
+	return l.chainClient().RescanEndBlock(startHash, addrs, outPoints, endHash)
+}
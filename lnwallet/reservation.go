@@ -1,13 +1,18 @@
 package lnwallet
 
 import (
+	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
+	"golang.org/x/net/context"
 )
 
 // ChannelContribution is the primary constituent of the funding workflow within
@@ -46,6 +51,22 @@ type ChannelContribution struct {
 	// The delay (in blocks) to be used for the pay-to-self output in this
 	// party's version of the commitment transaction.
 	CsvDelay uint32
+
+	// The commitment format of the channel. The initiator proposes the
+	// format, which the responder must either adopt exactly, or reject the
+	// channel.
+	CommitType channeldb.CommitmentType
+
+	// The limits on the total value, and number, of pending HTLCs this
+	// party will accept from the other within the channel.
+	MaxValueInFlight lnwire.MilliSatoshi
+	MaxAcceptedHtlcs uint16
+
+	// The reserve this party requires the other to keep within the
+	// channel, and the value below which outputs of this party's version
+	// of the commitment transaction are trimmed as dust.
+	ChanReserve btcutil.Amount
+	DustLimit   btcutil.Amount
 }
 
 // ChannelReservation represents an intent to open a lightning payment channel
@@ -55,6 +76,7 @@ type ChannelContribution struct {
 // prevents a number of race conditions such as two funding transactions
 // double-spending the same input. A reservation can also be cancelled, which
 // removes the resources from limbo, allowing another reservation to claim them.
+// The same happens once the context a reservation was created under is done.
 //
 // The reservation workflow consists of the following three steps:
 //  1. lnwallet.InitChannelReservation
@@ -108,11 +130,40 @@ type ChannelReservation struct {
 	// throughout its lifetime.
 	reservationID uint64
 
+	// The time the reservation was created, and the time after which it's
+	// cancelled should it remain incomplete. Unless the reservation was
+	// created under a context with an earlier deadline, it expires once
+	// the reservation timeout has elapsed since its creation.
+	creationTime time.Time
+	expiry       time.Time
+
+	// timedOut is set once the reservation has been cancelled after
+	// timing out.
+	timedOut int32 // To be used atomically.
+
+	// done is closed once the reservation is no longer pending, having
+	// been completed, cancelled, or timed out. doneErr is the reason it was
+	// cancelled, or nil if it completed.
+	done     chan struct{}
+	doneOnce sync.Once
+	doneErr  error
+
 	// A channel which will be sent on once the channel is considered
 	// 'open'. A channel is open once the funding transaction has reached
 	// a sufficient number of confirmations.
 	chanOpen chan *LightningChannel
 
+	// A channel which will be sent on once the funding transaction has
+	// reached a sufficient number of confirmations. For regular channels
+	// this coincides with the channel opening, however zero-conf channels
+	// open before the funding transaction confirms.
+	chanConfirmed chan struct{}
+
+	// A channel which will be sent on once the funding transaction has
+	// reached AnnouncementConfs confirmations, and the channel may be
+	// announced to the network.
+	chanAnnounceable chan struct{}
+
 	wallet *LightningWallet
 }
 
@@ -134,15 +185,35 @@ func newChannelReservation(t FundingType, fundingAmt btcutil.Amount,
 		partialState: &channeldb.OpenChannel{
 			// TODO(roasbeef): assumes balanced symmetric channels.
 			Capacity:     fundingAmt * 2,
-			OurBalance:   fundingAmt,
-			TheirBalance: fundingAmt,
+			OurBalance:   lnwire.NewMSatFromSatoshis(fundingAmt),
+			TheirBalance: lnwire.NewMSatFromSatoshis(fundingAmt),
 			MinFeePerKb:  minFeeRate,
 		},
 		reservationID: id,
+		creationTime:  time.Now(),
+		chanOpen:      make(chan *LightningChannel, 1),
+		chanConfirmed: make(chan struct{}, 1),
+		done:          make(chan struct{}),
 		wallet:        wallet,
+
+		chanAnnounceable: make(chan struct{}, 1),
 	}
 }
 
+// ReservationID returns the ID the wallet tracks the reservation under. The
+// initiator of a channel requests it under this ID on the wire.
+func (r *ChannelReservation) ReservationID() uint64 {
+	return r.reservationID
+}
+
+// Expiry returns the time after which the reservation is cancelled should it
+// remain incomplete.
+func (r *ChannelReservation) Expiry() time.Time {
+	r.RLock()
+	defer r.RUnlock()
+	return r.expiry
+}
+
 // OurContribution returns the wallet's fully populated contribution to the
 // pending payment channel. See 'ChannelContribution' for further details
 // regarding the contents of a contribution.
@@ -161,13 +232,26 @@ func (r *ChannelReservation) OurContribution() *ChannelContribution {
 // transaction belonging to the wallet are available. Additionally, the wallet
 // will generate a signature to the counterparty's version of the commitment
 // transaction.
-func (r *ChannelReservation) ProcessContribution(theirContribution *ChannelContribution) error {
+func (r *ChannelReservation) ProcessContribution(ctx context.Context,
+	theirContribution *ChannelContribution) error {
+
+	if atomic.LoadInt32(&r.timedOut) == 1 {
+		return ErrReservationTimedOut
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	errChan := make(chan error, 1)
 
-	r.wallet.msgChan <- &addContributionMsg{
+	select {
+	case r.wallet.msgChan <- &addContributionMsg{
 		pendingFundingID: r.reservationID,
 		contribution:     theirContribution,
 		err:              errChan,
+	}:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
 	return <-errChan
@@ -209,16 +293,27 @@ func (r *ChannelReservation) OurSignatures() ([][]byte, []byte) {
 // which will block until the funding transaction obtains the configured number
 // of confirmations. Once the method unblocks, a LightningChannel instance is
 // returned, marking the channel available for updates.
-func (r *ChannelReservation) CompleteReservation(fundingSigs [][]byte,
-	commitmentSig []byte) error {
+func (r *ChannelReservation) CompleteReservation(ctx context.Context,
+	fundingSigs [][]byte, commitmentSig []byte) error {
+
+	if atomic.LoadInt32(&r.timedOut) == 1 {
+		return ErrReservationTimedOut
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	errChan := make(chan error, 1)
 
-	r.wallet.msgChan <- &addCounterPartySigsMsg{
+	select {
+	case r.wallet.msgChan <- &addCounterPartySigsMsg{
 		pendingFundingID:   r.reservationID,
 		theirFundingSigs:   fundingSigs,
 		theirCommitmentSig: commitmentSig,
 		err:                errChan,
+	}:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
 	return <-errChan
@@ -248,23 +343,173 @@ func (r *ChannelReservation) FinalFundingTx() *wire.MsgTx {
 // the scenario that communications with the counterparty break down. Upon
 // cancellation, all resources previously reserved for this pending payment
 // channel are returned to the free pool, allowing subsequent reservations to
-// utilize the now freed resources.
+// utilize the now freed resources. If the reservation already timed out,
+// its resources have already been freed.
 func (r *ChannelReservation) Cancel() error {
+	return r.cancel(ErrReservationCancelled)
+}
+
+// cancel abandons the reservation, with reason becoming the error returned by
+// Err.
+func (r *ChannelReservation) cancel(reason error) error {
+	if atomic.LoadInt32(&r.timedOut) == 1 {
+		return nil
+	}
+
 	errChan := make(chan error, 1)
 	r.wallet.msgChan <- &fundingReserveCancelMsg{
 		pendingFundingID: r.reservationID,
+		reason:           reason,
 		err:              errChan,
 	}
 
 	return <-errChan
 }
 
+// Done returns a channel which is closed once the reservation is no longer
+// pending, having been completed, cancelled, or timed out. Whoever is driving
+// the funding handshake with the counterparty should abort it once the
+// channel is closed, and Err returns non-nil.
+func (r *ChannelReservation) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err returns the reason the reservation was cancelled: ErrReservationCancelled
+// if Cancel was called, ErrReservationTimedOut if it timed out, or the
+// context's error if the context it was created under was done. While the
+// reservation is pending, or once it has completed, nil is returned.
+func (r *ChannelReservation) Err() error {
+	select {
+	case <-r.done:
+		return r.doneErr
+	default:
+		return nil
+	}
+}
+
+// finish marks the reservation as no longer pending, for the passed reason,
+// which is nil if it completed.
+//
+// NOTE: The reservation's mutex MUST be held when calling this method.
+func (r *ChannelReservation) finish(reason error) {
+	r.doneOnce.Do(func() {
+		r.doneErr = reason
+		close(r.done)
+	})
+}
+
+// SetCommitType sets the commitment format of the channel. The responder uses
+// this to adopt the format proposed by the initiator within their funding
+// request.
+// NOTE: This MUST be called before .ProcessContribution().
+func (r *ChannelReservation) SetCommitType(commitType channeldb.CommitmentType) error {
+	switch commitType {
+	case channeldb.CommitmentLegacy, channeldb.CommitmentStaticRemoteKey,
+		channeldb.CommitmentAnchors:
+	default:
+		return fmt.Errorf("unsupported commitment type: %v", commitType)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.ourContribution.CommitType = commitType
+	return nil
+}
+
+// SetHtlcLimits sets the limits on the total value, and number, of pending
+// HTLCs we'll accept from the counterparty within the channel.
+// NOTE: This MUST be called before .ProcessContribution().
+func (r *ChannelReservation) SetHtlcLimits(maxValueInFlight lnwire.MilliSatoshi,
+	maxAcceptedHtlcs uint16) error {
+
+	if err := validateHtlcLimits(maxValueInFlight, maxAcceptedHtlcs); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.ourContribution.MaxValueInFlight = maxValueInFlight
+	r.ourContribution.MaxAcceptedHtlcs = maxAcceptedHtlcs
+	return nil
+}
+
+// validateHtlcLimits returns an error if HTLC limits are unable to be
+// satisfied, or exceed the protocol limit.
+func validateHtlcLimits(maxValueInFlight lnwire.MilliSatoshi,
+	maxAcceptedHtlcs uint16) error {
+
+	if maxValueInFlight == 0 {
+		return fmt.Errorf("max value in flight must be positive, "+
+			"instead got %v", maxValueInFlight)
+	}
+	if maxAcceptedHtlcs == 0 || maxAcceptedHtlcs > lnwire.MaxHTLCNumber {
+		return fmt.Errorf("max accepted htlcs must be between 1 and "+
+			"%d, instead got %d", lnwire.MaxHTLCNumber,
+			maxAcceptedHtlcs)
+	}
+
+	return nil
+}
+
+// SetZeroConf marks the reservation as a zero-conf channel, allowing the
+// channel to be used as soon as the funding handshake completes, rather than
+// once the funding transaction confirms. This should only be done for trusted
+// peers, as until the funding transaction confirms they're able to double
+// spend it.
+// NOTE: This MUST be called before .CompleteReservation().
+func (r *ChannelReservation) SetZeroConf() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.ZeroConf = true
+}
+
+// SetPrivate marks the reservation as a private channel, which is never
+// announced to the network. Once confirmed, the channel is usable by us, and
+// our peer, just as any other.
+// NOTE: This MUST be called before .CompleteReservation().
+func (r *ChannelReservation) SetPrivate() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.Private = true
+}
+
+// SetInitiator marks us as the initiator of the channel, and so responsible
+// for paying the commitment fee. While offering HTLCs, we'll then keep a
+// buffer of our balance to pay the fee should the feerate rise.
+// NOTE: This MUST be called before .CompleteReservation().
+func (r *ChannelReservation) SetInitiator() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.IsInitiator = true
+}
+
+// WaitForChannelConfirmed blocks until the funding transaction for this
+// payment channel obtains the configured number of confirmations. For
+// zero-conf channels, this happens some time after the channel has opened.
+func (r *ChannelReservation) WaitForChannelConfirmed() {
+	<-r.chanConfirmed
+}
+
+// WaitForAnnouncementDepth blocks until the funding transaction for this
+// payment channel obtains AnnouncementConfs confirmations, after which the
+// channel may be announced to the network. Private channels are never
+// announced, so for them it blocks indefinitely.
+func (r *ChannelReservation) WaitForAnnouncementDepth() {
+	<-r.chanAnnounceable
+}
+
 // WaitForChannelOpen blocks until the funding transaction for this pending
 // payment channel obtains the configured number of confirmations. Once
 // confirmations have been obtained, a fully initialized LightningChannel
 // instance is returned, allowing for channel updates.
 // NOTE: If this method is called before .CompleteReservation(), it will block
-// indefinitely.
+// indefinitely. If we didn't fund the channel, and the funding transaction
+// fails to confirm in time, nil is returned.
 func (r *ChannelReservation) WaitForChannelOpen() *LightningChannel {
 	return <-r.chanOpen
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 var (
@@ -22,6 +23,16 @@ var (
 	OP_CHECKSEQUENCEVERIFY byte = txscript.OP_NOP3
 )
 
+const (
+	// anchorSize is the value of each anchor output on an anchors
+	// commitment transaction.
+	anchorSize = btcutil.Amount(330)
+
+	// anchorCsvDelay is the number of confirmations after which anyone may
+	// sweep an anchor output.
+	anchorCsvDelay = 16
+)
+
 // scriptHashPkScript generates a pay-to-script-hash public key script paying
 // to the hash160 of the passed redeem script.
 func scriptHashPkScript(redeemScript []byte) ([]byte, error) {
@@ -117,15 +128,37 @@ func findScriptOutputIndex(tx *wire.MsgTx, script []byte) (bool, uint32) {
 	return found, index
 }
 
+// htlcHashOp returns the opcode hashing the pre-image presented to the script
+// of an HTLC of the passed hash type.
+func htlcHashOp(hashType lnwire.HTLCHashType) (byte, error) {
+	switch hashType {
+	case lnwire.HTLCHashTypeHash160:
+		return txscript.OP_HASH160, nil
+	default:
+		return 0, fmt.Errorf("unsupported HTLC hash type: %v", hashType)
+	}
+}
+
 // senderHTLCScript constructs the public key script for an outgoing HTLC
-// output payment for the sender's commitment transaction.
+// output payment for the sender's commitment transaction. The payment hash
+// is of the passed hash type.
 func senderHTLCScript(absoluteTimeout, relativeTimeout uint32, senderKey,
-	receiverKey *btcec.PublicKey, revokeHash, paymentHash []byte) ([]byte, error) {
+	receiverKey *btcec.PublicKey, revokeHash []byte,
+	hashType lnwire.HTLCHashType, paymentHash []byte) ([]byte, error) {
+
+	hashOp, err := htlcHashOp(hashType)
+	if err != nil {
+		return nil, err
+	}
 
 	builder := txscript.NewScriptBuilder()
 
 	// Was the pre-image to the payment hash presented?
-	builder.AddOp(txscript.OP_HASH160)
+	//
+	// TODO: the hash is compared against the revocation hash
+	// as well, which is always a HASH160, so other hash types need the
+	// two pre-images hashed separately
+	builder.AddOp(hashOp)
 	builder.AddOp(txscript.OP_DUP)
 	builder.AddData(paymentHash)
 	builder.AddOp(txscript.OP_EQUAL)
@@ -160,15 +193,22 @@ func senderHTLCScript(absoluteTimeout, relativeTimeout uint32, senderKey,
 	return builder.Script()
 }
 
-// senderHTLCScript constructs the public key script for an incoming HTLC
-// output payment for the receiver's commitment transaction.
+// receiverHTLCScript constructs the public key script for an incoming HTLC
+// output payment for the receiver's commitment transaction. The payment hash
+// is of the passed hash type.
 func receiverHTLCScript(absoluteTimeout, relativeTimeout uint32, senderKey,
-	receiverKey *btcec.PublicKey, revokeHash, paymentHash []byte) ([]byte, error) {
+	receiverKey *btcec.PublicKey, revokeHash []byte,
+	hashType lnwire.HTLCHashType, paymentHash []byte) ([]byte, error) {
+
+	hashOp, err := htlcHashOp(hashType)
+	if err != nil {
+		return nil, err
+	}
 
 	builder := txscript.NewScriptBuilder()
 
 	// Was the pre-image to the payment hash presented?
-	builder.AddOp(txscript.OP_HASH160)
+	builder.AddOp(hashOp)
 	builder.AddOp(txscript.OP_DUP)
 	builder.AddData(paymentHash)
 	builder.AddOp(txscript.OP_EQUAL)
@@ -253,3 +293,37 @@ func commitScriptUnencumbered(key *btcec.PublicKey) ([]byte, error) {
 
 	return builder.Script()
 }
+
+// anchorScript constructs the redeem script for an anchor output on an anchors
+// commitment transaction. The anchor may be spent immediately by the owner
+// of the key in order to bump the fee of the commitment transaction via CPFP.
+// Once the commitment transaction has been confirmed for anchorCsvDelay
+// blocks, anyone may sweep the anchor, ensuring the UTXO set isn't left
+// littered with dust.
+func anchorScript(key *btcec.PublicKey) ([]byte, error) {
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(key.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_IFDUP)
+	builder.AddOp(txscript.OP_NOTIF)
+	builder.AddInt64(anchorCsvDelay)
+	builder.AddOp(OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_ENDIF)
+
+	return builder.Script()
+}
+
+// commitScriptToRemoteConfirmed constructs the public key script on an anchors
+// commitment transaction paying to the "other" party. Unlike
+// commitScriptUnencumbered, the output may only be spent once the commitment
+// transaction has a confirmation. This ensures the anchors are the only
+// outputs which can be used to CPFP the commitment transaction.
+func commitScriptToRemoteConfirmed(key *btcec.PublicKey) ([]byte, error) {
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(key.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIGVERIFY)
+	builder.AddOp(txscript.OP_1)
+	builder.AddOp(OP_CHECKSEQUENCEVERIFY)
+
+	return builder.Script()
+}
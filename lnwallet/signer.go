@@ -0,0 +1,279 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/schnorr"
+	"github.com/lightningnetwork/lnd/schnorr/musig2"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/fastsha256"
+)
+
+// MuSig2SessionID uniquely identifies an active MuSig2 signing session.
+type MuSig2SessionID [32]byte
+
+// MuSig2SessionInfo describes a newly created MuSig2 signing session.
+type MuSig2SessionInfo struct {
+	// SessionID is used to refer to the session in all later calls.
+	SessionID MuSig2SessionID
+
+	// PublicNonce is our public nonce for the session, which must be sent
+	// to all other signers.
+	PublicNonce [musig2.PubNonceSize]byte
+
+	// CombinedKey is the aggregate key of all signers, with all tweaks
+	// applied. The final signature is valid under this key.
+	CombinedKey *btcec.PublicKey
+}
+
+// Signer is implemented by a wallet which is able to create schnorr
+// signatures with the keys it controls, either alone or jointly with others
+// via MuSig2. The keys are identified by their public key.
+type Signer interface {
+	// SignSchnorr signs the 32-byte digest with the private key of the
+	// passed public key. If taprootTweak is set, the key is first tweaked
+	// with the passed script root, allowing a taproot output with the
+	// key as its internal key to be spent via the key path.
+	SignSchnorr(pub *btcec.PublicKey, digest [32]byte, taprootTweak bool,
+		scriptRoot []byte) (*schnorr.Signature, error)
+
+	// MuSig2CreateSession starts a new MuSig2 signing session between
+	// the passed signers, signing with the private key of signingKey,
+	// which must be one of the signers.
+	MuSig2CreateSession(signingKey *btcec.PublicKey,
+		signers []*btcec.PublicKey,
+		opts *musig2.SessionOptions) (*MuSig2SessionInfo, error)
+
+	// MuSig2RegisterNonces records the public nonces of the other
+	// signers. True is returned once the nonces of all signers are known.
+	MuSig2RegisterNonces(id MuSig2SessionID,
+		nonces [][musig2.PubNonceSize]byte) (bool, error)
+
+	// MuSig2Sign creates our partial signature of the digest. Each
+	// session may only sign once.
+	MuSig2Sign(id MuSig2SessionID,
+		digest [32]byte) (*musig2.PartialSignature, error)
+
+	// MuSig2CombineSig combines the partial signatures of the other
+	// signers with our own. Once the partial signatures of all signers
+	// are known the final signature is returned, and the session is
+	// removed.
+	MuSig2CombineSig(id MuSig2SessionID,
+		sigs []*musig2.PartialSignature) (*schnorr.Signature, bool, error)
+
+	// MuSig2Cleanup removes the session, for example if signing was
+	// aborted by one of the other signers.
+	MuSig2Cleanup(id MuSig2SessionID) error
+}
+
+// A compile time check to ensure LightningWallet implements the Signer
+// interface.
+var _ Signer = (*LightningWallet)(nil)
+
+// TaprootOutput is a BIP86 taproot output paying to a key of the wallet.
+type TaprootOutput struct {
+	// InternalKey is the wallet controlled key the output commits to.
+	InternalKey *btcec.PublicKey
+
+	// OutputKey is the tweaked key which appears within the output.
+	OutputKey *btcec.PublicKey
+
+	// PkScript is the witness v1 script paying to the output key.
+	PkScript []byte
+}
+
+// NewTaprootOutput creates a new key-path only taproot output, using the next
+// external key of the wallet as its internal key. The output is spent with a
+// signature from SignSchnorr, with the taproot tweak applied.
+// TODO: the underlying wallet doesn't yet recognize witness v1
+// outputs, so funds sent here won't show up in the wallet's balance.
+func (l *LightningWallet) NewTaprootOutput() (*TaprootOutput, error) {
+	priv, err := l.getNextRawKey()
+	if err != nil {
+		return nil, err
+	}
+	internalKey := priv.PubKey()
+
+	outputKey, err := schnorr.ComputeTaprootOutputKey(internalKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := schnorr.PayToTaprootScript(outputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TaprootOutput{
+		InternalKey: internalKey,
+		OutputKey:   outputKey,
+		PkScript:    pkScript,
+	}, nil
+}
+
+// SignSchnorr signs the 32-byte digest with the private key of the passed
+// public key.
+//
+// This is a part of the Signer interface.
+func (l *LightningWallet) SignSchnorr(pub *btcec.PublicKey, digest [32]byte,
+	taprootTweak bool, scriptRoot []byte) (*schnorr.Signature, error) {
+
+	priv, err := l.fetchPrivKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	if taprootTweak {
+		priv, err = schnorr.TweakTaprootPrivKey(priv, scriptRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return schnorr.Sign(priv, digest[:])
+}
+
+// MuSig2CreateSession starts a new MuSig2 signing session between the passed
+// signers.
+//
+// This is a part of the Signer interface.
+func (l *LightningWallet) MuSig2CreateSession(signingKey *btcec.PublicKey,
+	signers []*btcec.PublicKey,
+	opts *musig2.SessionOptions) (*MuSig2SessionInfo, error) {
+
+	priv, err := l.fetchPrivKey(signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := musig2.NewSession(priv, signers, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Our nonce is freshly generated for each session, so it also serves
+	// as a unique identifier for the session.
+	nonce := session.PublicNonce()
+	id := MuSig2SessionID(fastsha256.Sum256(nonce[:]))
+
+	l.musig2Mtx.Lock()
+	l.musig2Sessions[id] = session
+	l.musig2Mtx.Unlock()
+
+	return &MuSig2SessionInfo{
+		SessionID:   id,
+		PublicNonce: nonce,
+		CombinedKey: session.AggregateKey().PubKey(),
+	}, nil
+}
+
+// MuSig2RegisterNonces records the public nonces of the other signers.
+//
+// This is a part of the Signer interface.
+func (l *LightningWallet) MuSig2RegisterNonces(id MuSig2SessionID,
+	nonces [][musig2.PubNonceSize]byte) (bool, error) {
+
+	l.musig2Mtx.Lock()
+	defer l.musig2Mtx.Unlock()
+
+	session, ok := l.musig2Sessions[id]
+	if !ok {
+		return false, ErrMuSig2SessionNotFound
+	}
+
+	var haveAll bool
+	for _, nonce := range nonces {
+		var err error
+		haveAll, err = session.RegisterPubNonce(nonce)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return haveAll, nil
+}
+
+// MuSig2Sign creates our partial signature of the digest.
+//
+// This is a part of the Signer interface.
+func (l *LightningWallet) MuSig2Sign(id MuSig2SessionID,
+	digest [32]byte) (*musig2.PartialSignature, error) {
+
+	l.musig2Mtx.Lock()
+	defer l.musig2Mtx.Unlock()
+
+	session, ok := l.musig2Sessions[id]
+	if !ok {
+		return nil, ErrMuSig2SessionNotFound
+	}
+
+	return session.Sign(digest)
+}
+
+// MuSig2CombineSig combines the partial signatures of the other signers with
+// our own, returning the final signature once all are known.
+//
+// This is a part of the Signer interface.
+func (l *LightningWallet) MuSig2CombineSig(id MuSig2SessionID,
+	sigs []*musig2.PartialSignature) (*schnorr.Signature, bool, error) {
+
+	l.musig2Mtx.Lock()
+	defer l.musig2Mtx.Unlock()
+
+	session, ok := l.musig2Sessions[id]
+	if !ok {
+		return nil, false, ErrMuSig2SessionNotFound
+	}
+
+	var done bool
+	for _, sig := range sigs {
+		var err error
+		done, err = session.CombineSig(sig)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if !done {
+		return nil, false, nil
+	}
+
+	delete(l.musig2Sessions, id)
+	return session.FinalSig(), true, nil
+}
+
+// MuSig2Cleanup removes the session.
+//
+// This is a part of the Signer interface.
+func (l *LightningWallet) MuSig2Cleanup(id MuSig2SessionID) error {
+	l.musig2Mtx.Lock()
+	defer l.musig2Mtx.Unlock()
+
+	if _, ok := l.musig2Sessions[id]; !ok {
+		return ErrMuSig2SessionNotFound
+	}
+	delete(l.musig2Sessions, id)
+
+	return nil
+}
+
+// fetchPrivKey looks up the private key of the passed public key within the
+// wallet.
+func (l *LightningWallet) fetchPrivKey(pub *btcec.PublicKey) (*btcec.PrivateKey, error) {
+	pkh, err := btcutil.NewAddressPubKeyHash(
+		btcutil.Hash160(pub.SerializeCompressed()), ActiveNetParams)
+	if err != nil {
+		return nil, err
+	}
+
+	ai, err := l.Manager.Address(pkh)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get address info: %v", err)
+	}
+	pka, ok := ai.(waddrmgr.ManagedPubKeyAddress)
+	if !ok {
+		return nil, fmt.Errorf("address %v has no private key", pkh)
+	}
+
+	return pka.PrivKey()
+}
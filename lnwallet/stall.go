@@ -0,0 +1,86 @@
+package lnwallet
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// UpdateStatus describes the channel update in progress, awaiting the
+// counterparty's signatures, or revocation.
+type UpdateStatus struct {
+	// Started is when the update was created.
+	Started time.Time
+
+	// CurrentUpdateNum is the number of the last committed state, while
+	// PendingUpdateNum is that of the state the update would commit.
+	CurrentUpdateNum uint64
+	PendingUpdateNum uint64
+
+	// RHash is the payment hash of the HTLC added, or settled if Deletion
+	// is set, by the update.
+	RHash    PaymentHash
+	Deletion bool
+}
+
+// setPendingUpdate records the update as holding the updateTotem.
+func (lc *LightningChannel) setPendingUpdate(update *ChannelUpdate) {
+	lc.stateMtx.Lock()
+	lc.pendingUpdate = update
+	lc.updateStarted = time.Now()
+	lc.stateMtx.Unlock()
+}
+
+// PendingUpdate returns the status of the channel update in progress, or nil
+// if there's none.
+func (lc *LightningChannel) PendingUpdate() *UpdateStatus {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	update := lc.pendingUpdate
+	if update == nil {
+		return nil
+	}
+	return &UpdateStatus{
+		Started:          lc.updateStarted,
+		CurrentUpdateNum: update.currentUpdateNum,
+		PendingUpdateNum: update.pendingUpdateNum,
+		RHash:            update.pendingDesc.RHash,
+		Deletion:         update.deletion,
+	}
+}
+
+// CancelUpdate abandons the channel update in progress, rolling the channel
+// back to its last committed state so the update may be retried, once the
+// channel is resumed with the counterparty. Any later attempt to commit the
+// abandoned update fails with ErrUpdateCancelled.
+func (lc *LightningChannel) CancelUpdate() error {
+	lc.stateMtx.Lock()
+	defer lc.stateMtx.Unlock()
+
+	update := lc.pendingUpdate
+	if update == nil {
+		return ErrNoPendingUpdate
+	}
+
+	// An HTLC being added was made pending as the update was created, so
+	// it's removed. The committed state itself is left untouched until
+	// the update commits, so there's nothing else to undo.
+	if !update.deletion {
+		delete(lc.pendingPayments, update.pendingDesc.RHash)
+	}
+
+	lc.pendingUpdate = nil
+	lc.updateTotem <- struct{}{}
+
+	return nil
+}
+
+// ShortChanID returns the short channel ID locating the funding output of
+// the channel within the chain.
+func (lc *LightningChannel) ShortChanID() lnwire.ShortChannelID {
+	lc.stateMtx.RLock()
+	defer lc.stateMtx.RUnlock()
+
+	return lnwire.NewShortChanIDFromInt(lc.channelState.ShortChanID)
+}
@@ -0,0 +1,109 @@
+package lnwallet
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// ErrTxNotFound is returned when looking up a transaction which neither pays
+// to, nor spends from, the wallet.
+var ErrTxNotFound = errors.New("transaction not found within wallet")
+
+// TxOutputDetail is an output of a transaction paying to the wallet, or one
+// paid by a transaction spending from it.
+type TxOutputDetail struct {
+	Address     string
+	OutputIndex uint32
+
+	// Amount is positive for outputs paying to the wallet, and negative
+	// for those paid by it.
+	Amount btcutil.Amount
+}
+
+// TransactionDetail describes a transaction relevant to the wallet.
+type TransactionDetail struct {
+	Hash wire.ShaHash
+
+	// Confirmations is zero while the transaction is unconfirmed, in
+	// which case BlockHash is empty.
+	Confirmations int64
+	BlockHash     string
+	Timestamp     time.Time
+
+	// Fee is the fee paid by the transaction, known only if the wallet
+	// funded it.
+	Fee btcutil.Amount
+
+	// NetAmount is the net change in the wallet's balance caused by the
+	// transaction, excluding the fee.
+	NetAmount btcutil.Amount
+
+	Outputs []*TxOutputDetail
+
+	// Label describes what the transaction is for, should we have
+	// published it, and it be yet to confirm.
+	Label string
+}
+
+// GetTransaction returns the details of the transaction, or ErrTxNotFound if
+// it isn't relevant to the wallet.
+func (l *LightningWallet) GetTransaction(txid *wire.ShaHash) (*TransactionDetail, error) {
+	txns, err := l.ListAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	// The wallet reports a transaction once for each of our outputs it
+	// pays, or spends.
+	var detail *TransactionDetail
+	txidStr := txid.String()
+	for _, tx := range txns {
+		if tx.TxID != txidStr {
+			continue
+		}
+
+		if detail == nil {
+			detail = &TransactionDetail{
+				Hash:          *txid,
+				Confirmations: tx.Confirmations,
+				BlockHash:     tx.BlockHash,
+				Timestamp:     time.Unix(tx.Time, 0),
+			}
+		}
+
+		amt, err := btcutil.NewAmount(tx.Amount)
+		if err != nil {
+			return nil, err
+		}
+		detail.NetAmount += amt
+		detail.Outputs = append(detail.Outputs, &TxOutputDetail{
+			Address:     tx.Address,
+			OutputIndex: tx.Vout,
+			Amount:      amt,
+		})
+
+		// The fee is repeated for each output spent to, and reported
+		// as negative.
+		if tx.Fee != nil && detail.Fee == 0 {
+			fee, err := btcutil.NewAmount(-*tx.Fee)
+			if err != nil {
+				return nil, err
+			}
+			detail.Fee = fee
+		}
+	}
+	if detail == nil {
+		return nil, ErrTxNotFound
+	}
+
+	l.broadcastMtx.Lock()
+	if pending, ok := l.pendingBroadcasts[*txid]; ok {
+		detail.Label = pending.Label
+	}
+	l.broadcastMtx.Unlock()
+
+	return detail, nil
+}
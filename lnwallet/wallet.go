@@ -9,13 +9,19 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/lightningnetwork/lnd/blockcache"
 	"github.com/lightningnetwork/lnd/chainntfs"
 	"github.com/lightningnetwork/lnd/chainntfs/btcdnotify"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/schnorr/musig2"
 	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/lightningnetwork/lnd/sigpool"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -28,20 +34,61 @@ import (
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	btcwallet "github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/walletdb"
+	"golang.org/x/net/context"
 )
 
 const (
 	// The size of the buffered queue of request to the wallet from the
 	// outside word.
 	msgBufferSize = 100
+
+	// defaultBlockCacheSize is the number of blocks cached if the config
+	// doesn't specify otherwise.
+	defaultBlockCacheSize = 20
+
+	// defaultMaxDustExposure is the total value of dust HTLCs allowed
+	// within a channel if the config doesn't specify otherwise.
+	defaultMaxDustExposure = btcutil.Amount(500000)
+
+	// defaultExpiryGraceDelta is the number of blocks before the expiry
+	// of an unsettled incoming HTLC at which its channel is force closed,
+	// if the config doesn't specify otherwise.
+	defaultExpiryGraceDelta = 10
 )
 
 var (
-	// ErrInsufficientFunds ...
-	// Error types
+	// ErrInsufficientFunds is returned when the outputs available to the
+	// wallet don't cover the amount it's asked to fund.
 	ErrInsufficientFunds = errors.New("not enough available outputs to " +
 		"create funding transaction")
 
+	// ErrReservationNotFound is returned when referring to a funding
+	// reservation which doesn't exist, or has already been completed, or
+	// cancelled.
+	ErrReservationNotFound = errors.New("funding reservation not found")
+
+	// ErrReservationCancelled is returned when continuing a reservation
+	// which was explicitly cancelled.
+	ErrReservationCancelled = errors.New("channel reservation cancelled")
+
+	// ErrChannelTypeMismatch is returned when the counterparty's
+	// contribution doesn't use the commitment format proposed for the
+	// channel.
+	ErrChannelTypeMismatch = errors.New("counterparty's channel type " +
+		"doesn't match")
+
+	// ErrMuSig2SessionNotFound is returned when referring to a MuSig2
+	// signing session which doesn't exist, or has already completed.
+	ErrMuSig2SessionNotFound = errors.New("musig2 session not found")
+
+	// ErrAccountExists is returned when importing a watch-only account
+	// under a name which is already taken.
+	ErrAccountExists = errors.New("watch-only account already exists")
+
+	// ErrTxConfirmed is returned when attempting to bump the fee of a
+	// transaction which has already confirmed.
+	ErrTxConfirmed = errors.New("transaction already confirmed")
+
 	// Which bitcoin network are we using?
 	// TODO(roasbeef): config
 
@@ -104,6 +151,11 @@ type initFundingReserveMsg struct {
 	// The delay on the "pay-to-self" output(s) of the commitment transaction.
 	csvDelay uint32
 
+	// peerWumbo is true if the remote node supports wumbo channels, in
+	// which case the channel may exceed the MaxFundingAmount limit should
+	// we support them too.
+	peerWumbo bool
+
 	// A channel in which all errors will be sent accross. Will be nil if
 	// this initial set is succesful.
 	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
@@ -122,6 +174,10 @@ type initFundingReserveMsg struct {
 type fundingReserveCancelMsg struct {
 	pendingFundingID uint64
 
+	// reason is the error returned by the reservation's Err method once
+	// it's cancelled.
+	reason error
+
 	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
 	err chan error // Buffered
 }
@@ -200,6 +256,11 @@ type LightningWallet struct {
 	ChannelDB *channeldb.DB
 	db        walletdb.DB
 
+	// SigPool generates, and verifies, signatures in parallel. It's
+	// shared with the rest of the daemon, such as the gossiper, so that
+	// a single pool is sized to the number of usable CPUs.
+	SigPool *sigpool.SigPool
+
 	// Used by in order to obtain notifications about funding transaction
 	// reaching a specified confirmation depth, and to catch
 	// counterparty's broadcasting revoked commitment states.
@@ -213,8 +274,15 @@ type LightningWallet struct {
 	// An active RPC connection to a full-node. In the case of a btcd node,
 	// websockets are used for notifications. If using Bitcoin Core,
 	// notifications are either generated via long-polling or the usage of
-	// ZeroMQ.
-	rpc *chain.Client
+	// ZeroMQ. Should we fail over to another backend, the client is
+	// replaced, so it's only to be accessed via chainClient.
+	rpc           *chain.Client
+	activeBackend int
+	rpcMtx        sync.RWMutex
+
+	// blockCache holds recently fetched blocks, shared by all subsystems
+	// fetching blocks from the backend.
+	blockCache *blockcache.BlockCache
 
 	// All messages to the wallet are to be sent accross this channel.
 	msgChan chan interface{}
@@ -231,6 +299,57 @@ type LightningWallet struct {
 	// TODO(roasbeef): zombie garbage collection routine to solve
 	// lost-object/starvation problem/attack.
 
+	// Active MuSig2 signing sessions, keyed by their session ID.
+	musig2Sessions map[MuSig2SessionID]*musig2.Session
+	musig2Mtx      sync.Mutex
+
+	// The scripts of all keys of our watch-only accounts which we watch
+	// for payments, the outputs of those accounts which are yet to be
+	// spent, and those outputs locked while funding a PSBT.
+	watchedScripts  map[string]*watchedKey
+	watchOnlyUtxos  map[wire.OutPoint]struct{}
+	lockedWatchOnly map[wire.OutPoint]struct{}
+	watchOnlyMtx    sync.Mutex
+
+	// Transactions we've published which have yet to confirm, along with
+	// the inputs they spend, used to detect double spends. If the backend
+	// couldn't be reached when last publishing, broadcastRetry is set.
+	pendingBroadcasts map[wire.ShaHash]*channeldb.PendingBroadcast
+	broadcastInputs   map[wire.OutPoint]wire.ShaHash
+	broadcastRetry    bool
+	broadcastMtx      sync.Mutex
+
+	// The progress of the rescan recovering our funds after being
+	// restored from a seed. The heights are those of the last block
+	// rescanned, and of the tip of the chain when recovery started.
+	recoveryMode     bool
+	recoveryFinished bool
+	recoveryHeight   int32
+	recoveryTarget   int32
+	recoveryErr      error
+	recoveryMtx      sync.Mutex
+
+	// Channels we didn't fund whose funding transaction is yet to
+	// confirm, keyed by the txid of the funding transaction.
+	pendingFundings   map[wire.ShaHash]*pendingFunding
+	pendingFundingMtx sync.Mutex
+
+	// Started channels, whose incoming HTLCs are checked for approaching
+	// expiry with each new block.
+	activeChannels map[*LightningChannel]struct{}
+	activeChanMtx  sync.Mutex
+
+	// The addresses, and outpoints, watched on behalf of rpc clients
+	// awaiting confirmations, or spends.
+	watchedAddrs     []btcutil.Address
+	watchedOutPoints []*wire.OutPoint
+	chainWatchMtx    sync.Mutex
+
+	// chainSynced is closed once the wallet, and our chain notifications,
+	// have caught up to the best block of the backend after starting.
+	// Until then, channels may not be opened.
+	chainSynced chan struct{}
+
 	cfg *Config
 
 	started  int32
@@ -291,11 +410,30 @@ func NewLightningWallet(config *Config) (*LightningWallet, walletdb.DB, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	cdb := channeldb.New(wallet.Manager, lnNamespace)
 
+	// The wallet is unlocked first, as the key of an encrypted channel
+	// database is itself encrypted by the wallet.
 	if err := wallet.Manager.Unlock(config.PrivatePass); err != nil {
 		return nil, nil, err
 	}
+	lnNamespace, err = channeldb.OpenEncryptedNamespace(lnNamespace,
+		wallet.Manager, config.EncryptChannelDB)
+	if err != nil {
+		return nil, nil, err
+	}
+	cdb := channeldb.New(wallet.Manager, lnNamespace)
+
+	// Open channels written in a format we can't migrate from may be
+	// removed, leaving the rest of the database, and the wallet, intact.
+	if config.WipeChannelDB {
+		err := cdb.Wipe()
+		if err != nil && err != walletdb.ErrBucketNotFound {
+			return nil, nil, err
+		}
+	}
+	if err := cdb.SyncVersion(); err != nil {
+		return nil, nil, err
+	}
 
 	// If we just created the wallet, then reserve, and store a key for
 	// our ID within the Lightning Network.
@@ -317,20 +455,44 @@ func NewLightningWallet(config *Config) (*LightningWallet, walletdb.DB, error) {
 		return nil, nil, err
 	}
 
+	blockCacheSize := config.BlockCacheSize
+	if blockCacheSize == 0 {
+		blockCacheSize = defaultBlockCacheSize
+	}
+
 	// TODO(roasbeef): logging
 	return &LightningWallet{
 		db:            db,
 		chainNotifier: chainNotifier,
+		blockCache:    blockcache.New(blockCacheSize),
 		Wallet:        wallet,
 		ChannelDB:     cdb,
+		SigPool:       sigpool.NewSigPool(0),
 		msgChan:       make(chan interface{}, msgBufferSize),
 		// TODO(roasbeef): make this atomic.Uint32 instead? Which is
 		// faster, locks or CAS? I'm guessing CAS because assembly:
 		//  * https://golang.org/src/sync/atomic/asm_amd64.s
-		nextFundingID: 0,
-		cfg:           config,
-		fundingLimbo:  make(map[uint64]*ChannelReservation),
-		quit:          make(chan struct{}),
+		nextFundingID:  0,
+		cfg:            config,
+		fundingLimbo:   make(map[uint64]*ChannelReservation),
+		musig2Sessions: make(map[MuSig2SessionID]*musig2.Session),
+		quit:           make(chan struct{}),
+
+		watchedScripts:  make(map[string]*watchedKey),
+		watchOnlyUtxos:  make(map[wire.OutPoint]struct{}),
+		lockedWatchOnly: make(map[wire.OutPoint]struct{}),
+
+		pendingBroadcasts: make(map[wire.ShaHash]*channeldb.PendingBroadcast),
+		broadcastInputs:   make(map[wire.OutPoint]wire.ShaHash),
+
+		pendingFundings: make(map[wire.ShaHash]*pendingFunding),
+
+		activeChannels: make(map[*LightningChannel]struct{}),
+
+		chainSynced: make(chan struct{}),
+
+		recoveryMode: createID && config.HdSeed != nil &&
+			config.RecoveryWindow > 0,
 	}, db, nil
 }
 
@@ -343,19 +505,62 @@ func (l *LightningWallet) Startup() error {
 	}
 	// TODO(roasbeef): config...
 
-	rpcc, err := chain.NewClient(ActiveNetParams,
-		l.cfg.RPCHost, l.cfg.RPCUser, l.cfg.RPCPass, l.cfg.CACert, false)
+	// Connect to the first of our backends which responds, preferring
+	// the primary.
+	rpcc, activeBackend, err := l.connectBackend(0)
 	if err != nil {
 		return err
 	}
+	l.rpcMtx.Lock()
+	l.rpc = rpcc
+	l.activeBackend = activeBackend
+	l.rpcMtx.Unlock()
 
 	// Start the goroutines in the underlying wallet.
-	l.rpc = rpcc
-	if err := l.rpc.Start(); err != nil {
+	l.Start(rpcc)
+
+	if err := l.chainNotifier.Start(); err != nil {
 		return err
 	}
 
-	l.Start(rpcc)
+	// Resume tracking the outputs of our watch-only accounts.
+	if err := l.startWatchOnly(); err != nil {
+		return err
+	}
+
+	// Resume re-publishing any of our transactions yet to confirm.
+	if err := l.startRebroadcaster(); err != nil {
+		return err
+	}
+
+	// Cancel reservations, and forget pending channels, which time out.
+	if err := l.startFundingTimeouts(); err != nil {
+		return err
+	}
+
+	// TODO: start the expiry watcher, force closing channels whose
+	// incoming HTLCs near expiry unsettled, once ForceClose is
+	// implemented.
+
+	// If we were just restored from a seed, rescan the chain for our
+	// funds.
+	if err := l.startRecovery(); err != nil {
+		return err
+	}
+
+	if err := l.SigPool.Start(); err != nil {
+		return err
+	}
+
+	// Track whether we've caught up to the backend, refusing to open
+	// channels until we have.
+	if err := l.startChainSync(); err != nil {
+		return err
+	}
+
+	// Fail over to another backend should the one we're connected to
+	// become unresponsive.
+	l.startBackendMonitor()
 
 	l.wg.Add(1)
 	// TODO(roasbeef): multiple request handlers?
@@ -371,10 +576,14 @@ func (l *LightningWallet) Shutdown() error {
 	}
 
 	l.Stop()
-	l.rpc.Shutdown()
+	l.chainNotifier.Stop()
 
 	close(l.quit)
 	l.wg.Wait()
+
+	l.chainClient().Shutdown()
+
+	l.SigPool.Stop()
 	return nil
 }
 
@@ -394,6 +603,8 @@ out:
 				l.handleContributionMsg(msg)
 			case *addCounterPartySigsMsg:
 				l.handleFundingCounterPartySigs(msg)
+			case *expireReservationsMsg:
+				l.handleExpireReservations(msg)
 			}
 		case <-l.quit:
 			// TODO: do some clean up
@@ -421,27 +632,79 @@ out:
 // contribution. The third, and final step verifies all signatures for the inputs
 // of the funding transaction, and that the signature we records for our version
 // of the commitment transaction is valid.
-func (l *LightningWallet) InitChannelReservation(a btcutil.Amount, t FundingType,
-	theirID [32]byte, csvDelay uint32) (*ChannelReservation, error) {
+//
+// The capacity of the channel must be within our configured limits, which
+// only exceed lnwire.MaxFundingAmount per side should we, and the remote node
+// as signalled by peerWumbo, both support wumbo channels.
+//
+// The passed context bounds the lifetime of the reservation. Should it be
+// cancelled, or its deadline pass, before the reservation completes, such as
+// when the RPC client which requested the channel disconnects, the
+// reservation is cancelled, releasing its coins.
+//
+// Until we've caught up to the best block of the backend, ErrNotSynced is
+// returned, as we can't yet tell whether our coins are spent.
+func (l *LightningWallet) InitChannelReservation(ctx context.Context,
+	a btcutil.Amount, t FundingType, theirID [32]byte, csvDelay uint32,
+	peerWumbo bool) (*ChannelReservation, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !l.IsSynced() {
+		return nil, ErrNotSynced
+	}
 
 	errChan := make(chan error, 1)
 	respChan := make(chan *ChannelReservation, 1)
 
-	l.msgChan <- &initFundingReserveMsg{
+	select {
+	case l.msgChan <- &initFundingReserveMsg{
 		fundingAmount: a,
 		fundingType:   t,
 		csvDelay:      csvDelay,
 		nodeID:        theirID,
+		peerWumbo:     peerWumbo,
 		err:           errChan,
 		resp:          respChan,
+	}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 
-	return <-respChan, <-errChan
+	res, err := <-respChan, <-errChan
+	if err != nil {
+		return nil, err
+	}
+
+	// A deadline on the context which comes before the reservation would
+	// otherwise expire brings its expiry forward, so it's reported
+	// accurately.
+	if deadline, ok := ctx.Deadline(); ok {
+		res.Lock()
+		if deadline.Before(res.expiry) {
+			res.expiry = deadline
+		}
+		res.Unlock()
+	}
+
+	l.wg.Add(1)
+	go l.cancelReservationOnDone(ctx, res)
+
+	return res, nil
 }
 
 // handleFundingReserveRequest processes a message intending to create, and
 // validate a funding reservation request.
 func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg) {
+	// TODO: assumes balanced symmetric channels.
+	err := l.validateChanSize(2*req.fundingAmount, req.peerWumbo)
+	if err != nil {
+		req.err <- err
+		req.resp <- nil
+		return
+	}
+
 	// Create a limbo and record entry for this newly pending funding request.
 	l.limboMtx.Lock()
 
@@ -456,9 +719,26 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 	reservation.Lock()
 	defer reservation.Unlock()
 
+	reservation.expiry = reservation.creationTime.Add(l.reservationTimeout())
 	reservation.partialState.TheirLNID = req.nodeID
 	ourContribution := reservation.ourContribution
 	ourContribution.CsvDelay = req.csvDelay
+	ourContribution.CommitType = l.cfg.DefaultCommitType
+
+	// Unless overridden, we'll accept HTLCs up to the full capacity of the
+	// channel.
+	ourContribution.MaxValueInFlight = lnwire.NewMSatFromSatoshis(
+		reservation.partialState.Capacity,
+	)
+	ourContribution.MaxAcceptedHtlcs = l.cfg.MaxAcceptedHtlcs
+	if ourContribution.MaxAcceptedHtlcs == 0 {
+		ourContribution.MaxAcceptedHtlcs = lnwire.MaxHTLCNumber
+	}
+	reservation.partialState.MinHTLC = l.cfg.MinHTLC
+	ourContribution.ChanReserve = defaultChanReserve(
+		reservation.partialState.Capacity,
+	)
+	ourContribution.DustLimit = DefaultDustLimit
 
 	// We hold the coin select mutex while querying for outputs, and
 	// performing coin selection in order to avoid inadvertent double spends
@@ -501,6 +781,9 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 		MinChangeAmount: 10000,
 	}
 	selectedCoins, err := selector.CoinSelect(req.fundingAmount, coins)
+	if err == coinset.ErrCoinsNoSelectionAvailable {
+		err = ErrInsufficientFunds
+	}
 	if err != nil {
 		l.coinSelectMtx.Unlock()
 		req.err <- err
@@ -614,8 +897,7 @@ func (l *LightningWallet) handleFundingCancelRequest(req *fundingReserveCancelMs
 
 	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
 	if !ok {
-		// TODO(roasbeef): make new error, "unkown funding state" or something
-		req.err <- fmt.Errorf("attempted to cancel non-existant funding state")
+		req.err <- ErrReservationNotFound
 		return
 	}
 
@@ -635,10 +917,77 @@ func (l *LightningWallet) handleFundingCancelRequest(req *fundingReserveCancelMs
 	// available?
 
 	delete(l.fundingLimbo, req.pendingFundingID)
+	pendingReservation.finish(req.reason)
 
 	req.err <- nil
 }
 
+// PendingReservation is a snapshot of a reservation which is yet to complete,
+// used to debug reservations which fail to progress.
+type PendingReservation struct {
+	// ReservationID is the ID the wallet tracks the reservation under.
+	ReservationID uint64
+
+	// TheirLNID is the ID of the node the channel is with.
+	TheirLNID [32]byte
+
+	// FundingAmount is the amount we've contributed to the channel, and
+	// Capacity the total capacity of the channel.
+	FundingAmount btcutil.Amount
+	Capacity      btcutil.Amount
+
+	// NumInputs is the number of our outputs locked as inputs to the
+	// funding transaction.
+	NumInputs int
+
+	// CommitType is the commitment format proposed for the channel.
+	CommitType channeldb.CommitmentType
+
+	// HaveContribution is true once the counterparty's contribution has
+	// been processed, the reservation then awaiting their signatures.
+	HaveContribution bool
+
+	// CreationTime is the time the reservation was created, and Expiry the
+	// time after which it's cancelled should it remain incomplete.
+	CreationTime time.Time
+	Expiry       time.Time
+}
+
+// PendingReservations returns a snapshot of each reservation which is yet to
+// complete, ordered by reservation ID.
+func (l *LightningWallet) PendingReservations() []*PendingReservation {
+	l.limboMtx.RLock()
+	defer l.limboMtx.RUnlock()
+
+	pending := make([]*PendingReservation, 0, len(l.fundingLimbo))
+	for _, res := range l.fundingLimbo {
+		res.RLock()
+		pending = append(pending, &PendingReservation{
+			ReservationID:    res.reservationID,
+			TheirLNID:        res.partialState.TheirLNID,
+			FundingAmount:    res.ourContribution.FundingAmount,
+			Capacity:         res.partialState.Capacity,
+			NumInputs:        len(res.ourContribution.Inputs),
+			CommitType:       res.partialState.CommitType,
+			HaveContribution: res.theirContribution.MultiSigKey != nil,
+			CreationTime:     res.creationTime,
+			Expiry:           res.expiry,
+		})
+		res.RUnlock()
+	}
+	sort.Sort(pendingByID(pending))
+
+	return pending
+}
+
+// pendingByID implements sort.Interface, sorting pending reservations by
+// their ID.
+type pendingByID []*PendingReservation
+
+func (p pendingByID) Len() int           { return len(p) }
+func (p pendingByID) Less(i, j int) bool { return p[i].ReservationID < p[j].ReservationID }
+func (p pendingByID) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
 // handleFundingCounterPartyFunds processes the second workflow step for the
 // lifetime of a channel reservation. Upon completion, the reservation will
 // carry a completed funding transaction (minus the counterparty's input
@@ -649,7 +998,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
 	l.limboMtx.Unlock()
 	if !ok {
-		req.err <- fmt.Errorf("attempted to update non-existant funding state")
+		req.err <- ErrReservationNotFound
 		return
 	}
 
@@ -711,7 +1060,7 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 		req.err <- err
 		return
 	}
-	if err := l.rpc.NotifyReceived([]btcutil.Address{scriptAddr.Address()}); err != nil {
+	if err := l.chainClient().NotifyReceived([]btcutil.Address{scriptAddr.Address()}); err != nil {
 		req.err <- err
 		return
 	}
@@ -791,16 +1140,48 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	pendingReservation.fundingLockTime = theirContribution.CsvDelay
 	ourCommitKey := ourContribution.CommitKey
 	theirCommitKey := theirContribution.CommitKey
+	// Both sides must have agreed upon the exact same commitment format.
+	commitType := ourContribution.CommitType
+	if theirContribution.CommitType != commitType {
+		req.err <- ErrChannelTypeMismatch
+		return
+	}
+	pendingReservation.partialState.CommitType = commitType
+
+	// The counterparty's limits on the HTLCs we may offer them must be
+	// satisfiable.
+	if err := validateHtlcLimits(theirContribution.MaxValueInFlight,
+		theirContribution.MaxAcceptedHtlcs); err != nil {
+		req.err <- err
+		return
+	}
+
+	// The parameters the counterparty proposes must be within our bounds,
+	// so we aren't bound to an arbitrarily punitive channel.
+	if err := l.validateRemoteParams(theirContribution,
+		pendingReservation.partialState.Capacity); err != nil {
+		req.err <- err
+		return
+	}
+
+	partialState := pendingReservation.partialState
+	partialState.OurMaxValueInFlight = ourContribution.MaxValueInFlight
+	partialState.OurMaxAcceptedHtlcs = ourContribution.MaxAcceptedHtlcs
+	partialState.TheirMaxValueInFlight = theirContribution.MaxValueInFlight
+	partialState.TheirMaxAcceptedHtlcs = theirContribution.MaxAcceptedHtlcs
+	partialState.OurChanReserve = ourContribution.ChanReserve
+	partialState.TheirChanReserve = theirContribution.ChanReserve
+
 	ourCommitTx, err := createCommitTx(fundingTxIn, ourCommitKey, theirCommitKey,
 		ourCurrentRevokeHash[:], theirContribution.CsvDelay,
-		initialBalance, initialBalance)
+		initialBalance, initialBalance, commitType)
 	if err != nil {
 		req.err <- err
 		return
 	}
 	theirCommitTx, err := createCommitTx(fundingTxIn, theirCommitKey, ourCommitKey,
 		theirContribution.RevocationHash[:], theirContribution.CsvDelay,
-		initialBalance, initialBalance)
+		initialBalance, initialBalance, commitType)
 	if err != nil {
 		req.err <- err
 		return
@@ -845,7 +1226,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	pendingReservation, ok := l.fundingLimbo[msg.pendingFundingID]
 	l.limboMtx.RUnlock()
 	if !ok {
-		msg.err <- fmt.Errorf("attempted to update non-existant funding state")
+		msg.err <- ErrReservationNotFound
 		return
 	}
 
@@ -862,7 +1243,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 			// Fetch the alleged previous output along with the
 			// pkscript referenced by this input.
 			prevOut := txin.PreviousOutPoint
-			output, err := l.rpc.GetTxOut(&prevOut.Hash, prevOut.Index, false)
+			output, err := l.chainClient().GetTxOut(&prevOut.Hash, prevOut.Index, false)
 			if output == nil {
 				// TODO(roasbeef): do this at the start to avoid wasting out time?
 				//  8 or a set of nodes "we" run with exposed unauthenticated RPC?
@@ -953,6 +1334,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	// Funding complete, this entry can be removed from limbo.
 	l.limboMtx.Lock()
 	delete(l.fundingLimbo, pendingReservation.reservationID)
+	pendingReservation.finish(nil)
 	// TODO(roasbeef): unlock outputs here, Store.InsertTx will handle marking
 	// input in unconfirmed tx, so future coin selects don't pick it up
 	//  * also record location of change address so can use AddCredit
@@ -961,6 +1343,16 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	// Add the complete funding transaction to the DB, in it's open bucket
 	// which will be used for the lifetime of this channel.
 	err = l.ChannelDB.PutOpenChannel(pendingReservation.partialState)
+	if err != nil {
+		msg.err <- err
+		return
+	}
+
+	// Publish the funding transaction. It's re-published until confirmed
+	// if it fails to propagate, so any error is only logged.
+	if err := l.PublishTransaction(fundingTx, "funding"); err != nil {
+		fmt.Printf("unable to publish funding tx: %v\n", err)
+	}
 
 	// Create a goroutine to watch the chain so we can open the channel once
 	// the funding tx has enough confirmations.
@@ -972,7 +1364,9 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 
 // openChannelAfterConfirmations creates, and opens a payment channel after
 // the funding transaction created within the passed channel reservation
-// obtains the specified number of confirmations.
+// obtains the specified number of confirmations. If we didn't fund the
+// channel, and the funding transaction fails to confirm within the funding
+// confirmation timeout, the channel is forgotten instead.
 func (l *LightningWallet) openChannelAfterConfirmations(res *ChannelReservation, numConfs uint32) {
 	// Register with the ChainNotifier for a notification once the funding
 	// transaction reaches `numConfs` confirmations.
@@ -982,14 +1376,71 @@ func (l *LightningWallet) openChannelAfterConfirmations(res *ChannelReservation,
 	txid := res.partialState.FundingTx.TxSha()
 	l.chainNotifier.RegisterConfirmationsNotification(&txid, numConfs, trigger)
 
+	// Zero-conf channels are usable right away, so there's no need to
+	// wait for any confirmations before handing out the channel.
+	var channel *LightningChannel
+	zeroConf := res.partialState.ZeroConf
+	if zeroConf {
+		var err error
+		channel, err = NewLightningChannel(l, l.chainNotifier,
+			l.ChannelDB, res.partialState)
+		if err == nil {
+			channel.Start()
+		}
+		res.chanOpen <- channel
+	}
+
 	// Wait until the specified number of confirmations has been reached.
-	<-trigger.TriggerChan
+	var timedOut <-chan struct{}
+	weFunded := len(res.ourContribution.Inputs) != 0
+	if !weFunded {
+		timedOut = l.trackPendingFunding(res)
+	}
+	select {
+	case <-trigger.TriggerChan:
+		if !weFunded && !l.confirmPendingFunding(&txid) {
+			if !zeroConf {
+				res.chanOpen <- nil
+			}
+			return
+		}
+	case <-timedOut:
+		if !zeroConf {
+			res.chanOpen <- nil
+		}
+		return
+	case <-l.quit:
+		return
+	}
 
 	// Finally, create and officially open the payment channel!
 	// TODO(roasbeef): CreationTime once tx is 'open'
-	channel, _ := newLightningChannel(l, l.chainNotifier, l.ChannelDB,
-		res.partialState)
-	res.chanOpen <- channel
+	if !zeroConf {
+		var err error
+		channel, err = NewLightningChannel(l, l.chainNotifier,
+			l.ChannelDB, res.partialState)
+		if err == nil {
+			channel.Start()
+		}
+		res.chanOpen <- channel
+	}
+
+	// Record the location of the funding output, now that it's
+	// confirmed, so that the channel may be announced.
+	scid, err := l.locateFundingTx(res.partialState)
+	if err == nil && channel != nil {
+		err = channel.setShortChanID(scid)
+	}
+	if err != nil {
+		fmt.Printf("unable to record location of funding tx %v: %v\n",
+			txid, err)
+	}
+
+	res.chanConfirmed <- struct{}{}
+
+	if !res.partialState.Private {
+		l.waitForAnnouncementDepth(res)
+	}
 }
 
 // getNextRawKey retrieves the next key within our HD key-chain for use within
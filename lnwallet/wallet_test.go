@@ -3,24 +3,30 @@ package lnwallet
 import (
 	"bytes"
 	"crypto/sha256"
+	"errors"
 	"io/ioutil"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/coinset"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
 	"github.com/btcsuite/btcwallet/wtxmgr"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"golang.org/x/net/context"
 )
 
 var (
 	privPass = []byte("private-test")
 
+	ctxb = context.Background()
+
 	// For simplicity a single priv key controls all of our test outputs.
 	testWalletPrivKey = []byte{
 		0x2b, 0xd8, 0x06, 0xc9, 0x7f, 0x0e, 0x00, 0xaf,
@@ -81,13 +87,17 @@ type bobNode struct {
 // channel with Alice.
 func (b *bobNode) Contribution() *ChannelContribution {
 	return &ChannelContribution{
-		Inputs:          b.availableOutputs,
-		ChangeOutputs:   b.changeOutputs,
-		MultiSigKey:     b.channelKey,
-		CommitKey:       b.channelKey,
-		DeliveryAddress: b.deliveryAddress,
-		RevocationHash:  b.revocation,
-		CsvDelay:        b.delay,
+		Inputs:           b.availableOutputs,
+		ChangeOutputs:    b.changeOutputs,
+		MultiSigKey:      b.channelKey,
+		CommitKey:        b.channelKey,
+		DeliveryAddress:  b.deliveryAddress,
+		RevocationHash:   b.revocation,
+		CsvDelay:         b.delay,
+		MaxValueInFlight: lnwire.NewMSatFromSatoshis(btcutil.MaxSatoshi),
+		MaxAcceptedHtlcs: lnwire.MaxHTLCNumber,
+		ChanReserve:      btcutil.Amount(1e6),
+		DustLimit:        DefaultDustLimit,
 	}
 }
 
@@ -300,7 +310,8 @@ func createTestWallet() (string, *LightningWallet, error) {
 	}
 
 	config := &Config{PrivatePass: privPass, HdSeed: testHdSeed[:],
-		DataDir: tempTestDir}
+		DataDir: tempTestDir, Wumbo: true,
+		MaxChanSize: btcutil.Amount(50 * 1e8)}
 	wallet, _, err := NewLightningWallet(config)
 	if err != nil {
 		return "", nil, err
@@ -328,8 +339,8 @@ func testBasicWalletReservationWorkFlow(lnwallet *LightningWallet, t *testing.T)
 	// Bob initiates a channel funded with 5 BTC for each side, so 10
 	// BTC total. He also generates 2 BTC in change.
 	fundingAmount := btcutil.Amount(5 * 1e8)
-	chanReservation, err := lnwallet.InitChannelReservation(fundingAmount,
-		SIGHASH, bobNode.id, 4)
+	chanReservation, err := lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, bobNode.id, 4, true)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation: %v", err)
 	}
@@ -366,7 +377,8 @@ func testBasicWalletReservationWorkFlow(lnwallet *LightningWallet, t *testing.T)
 	// Bob sends over his output, change addr, pub keys, initial revocation,
 	// final delivery address, and his accepted csv delay for the commitmen
 	// t transactions.
-	if err := chanReservation.ProcessContribution(bobNode.Contribution()); err != nil {
+	err = chanReservation.ProcessContribution(ctxb, bobNode.Contribution())
+	if err != nil {
 		t.Fatalf("unable to add bob's funds to the funding tx: %v", err)
 	}
 
@@ -420,7 +432,8 @@ func testBasicWalletReservationWorkFlow(lnwallet *LightningWallet, t *testing.T)
 	if err != nil {
 		t.Fatalf("bob is unable to sign alice's commit tx: %v", err)
 	}
-	if err := chanReservation.CompleteReservation(bobsSigs, commitSig); err != nil {
+	err = chanReservation.CompleteReservation(ctxb, bobsSigs, commitSig)
+	if err != nil {
 		t.Fatalf("unable to complete funding tx: %v", err)
 	}
 
@@ -476,13 +489,13 @@ func testFundingTransactionLockedOutputs(lnwallet *LightningWallet, t *testing.T
 	// TODO(roasbeef): tests for concurrent funding.
 	//  * also func for below
 	fundingAmount := btcutil.Amount(8 * 1e8)
-	chanReservation1, err := lnwallet.InitChannelReservation(fundingAmount,
-		SIGHASH, testHdSeed, 4)
+	chanReservation1, err := lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation 1: %v", err)
 	}
-	chanReservation2, err := lnwallet.InitChannelReservation(fundingAmount,
-		SIGHASH, testHdSeed, 4)
+	chanReservation2, err := lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation 2: %v", err)
 	}
@@ -512,12 +525,12 @@ func testFundingTransactionLockedOutputs(lnwallet *LightningWallet, t *testing.T
 	// 5 BTC. We only have 4BTC worth of outpoints that aren't locked, so
 	// this should fail.
 	amt := btcutil.Amount(8 * 1e8)
-	failedReservation, err := lnwallet.InitChannelReservation(amt,
-		SIGHASH, testHdSeed, 4)
+	failedReservation, err := lnwallet.InitChannelReservation(ctxb,
+		amt, SIGHASH, testHdSeed, 4, true)
 	if err == nil {
 		t.Fatalf("not error returned, should fail on coin selection")
 	}
-	if err != coinset.ErrCoinsNoSelectionAvailable {
+	if err != ErrInsufficientFunds {
 		t.Fatalf("error not coinselect error: %v", err)
 	}
 	if failedReservation != nil {
@@ -528,8 +541,8 @@ func testFundingTransactionLockedOutputs(lnwallet *LightningWallet, t *testing.T
 func testFundingCancellationNotEnoughFunds(lnwallet *LightningWallet, t *testing.T) {
 	// Create a reservation for 12 BTC.
 	fundingAmount := btcutil.Amount(12 * 1e8)
-	chanReservation, err := lnwallet.InitChannelReservation(fundingAmount,
-		SIGHASH, testHdSeed, 4)
+	chanReservation, err := lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation: %v", err)
 	}
@@ -542,9 +555,9 @@ func testFundingCancellationNotEnoughFunds(lnwallet *LightningWallet, t *testing
 	}
 
 	// Attempt to create another channel with 12 BTC, this should fail.
-	failedReservation, err := lnwallet.InitChannelReservation(fundingAmount,
-		SIGHASH, testHdSeed, 4)
-	if err != coinset.ErrCoinsNoSelectionAvailable {
+	failedReservation, err := lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
+	if err != ErrInsufficientFunds {
 		t.Fatalf("coin selection succeded should have insufficient funds: %+v",
 			failedReservation)
 	}
@@ -571,8 +584,8 @@ func testFundingCancellationNotEnoughFunds(lnwallet *LightningWallet, t *testing
 	// attempting coin selection.
 
 	// Request to fund a new channel should now succeeed.
-	_, err = lnwallet.InitChannelReservation(fundingAmount,
-		SIGHASH, testHdSeed, 4)
+	_, err = lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
 	if err != nil {
 		t.Fatalf("unable to initialize funding reservation: %v", err)
 	}
@@ -584,8 +597,104 @@ func testCancelNonExistantReservation(lnwallet *LightningWallet, t *testing.T) {
 
 	// Attempt to cancel this reservation. This should fail, we know
 	// nothing of it.
-	if err := res.Cancel(); err == nil {
-		t.Fatalf("cancelled non-existant reservation")
+	if err := res.Cancel(); err != ErrReservationNotFound {
+		t.Fatalf("expected ErrReservationNotFound cancelling "+
+			"non-existant reservation, got %v", err)
+	}
+}
+
+func testFundingReservationTimeout(lnwallet *LightningWallet, t *testing.T) {
+	fundingAmount := btcutil.Amount(8 * 1e8)
+	chanReservation, err := lnwallet.InitChannelReservation(ctxb,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
+	if err != nil {
+		t.Fatalf("unable to initialize funding reservation: %v", err)
+	}
+	if len(lnwallet.LockedOutpoints()) == 0 {
+		t.Fatalf("no outpoints locked for reservation")
+	}
+
+	// The reservation should be reported as pending, expiring once the
+	// reservation timeout has elapsed.
+	pending := lnwallet.PendingReservations()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending reservation, got %v", len(pending))
+	}
+	if pending[0].ReservationID != chanReservation.ReservationID() {
+		t.Fatalf("expected reservation %v, got %v",
+			chanReservation.ReservationID(), pending[0].ReservationID)
+	}
+	expiry := pending[0].CreationTime.Add(lnwallet.reservationTimeout())
+	if !pending[0].Expiry.Equal(expiry) {
+		t.Fatalf("expected expiry %v, got %v", expiry, pending[0].Expiry)
+	}
+
+	// Expire all reservations which expire up until just after ours,
+	// which should cancel it, releasing its outpoints.
+	lnwallet.handleExpireReservations(&expireReservationsMsg{
+		cutoff: chanReservation.Expiry().Add(time.Second),
+	})
+
+	if len(lnwallet.LockedOutpoints()) != 0 {
+		t.Fatalf("outpoints still locked")
+	}
+	if _, ok := lnwallet.fundingLimbo[chanReservation.reservationID]; ok {
+		t.Fatalf("funding reservation still in map")
+	}
+	if len(lnwallet.PendingReservations()) != 0 {
+		t.Fatalf("expired reservation still pending")
+	}
+	if err := chanReservation.Err(); err != ErrReservationTimedOut {
+		t.Fatalf("expected ErrReservationTimedOut, got %v", err)
+	}
+
+	// Continuing the reservation should fail, while cancelling it is a
+	// no-op.
+	err = chanReservation.ProcessContribution(ctxb, &ChannelContribution{})
+	if err != ErrReservationTimedOut {
+		t.Fatalf("expected ErrReservationTimedOut, got %v", err)
+	}
+	if err := chanReservation.Cancel(); err != nil {
+		t.Fatalf("unable to cancel timed out reservation: %v", err)
+	}
+}
+
+func testFundingReservationContextCancel(lnwallet *LightningWallet, t *testing.T) {
+	ctx, cancel := context.WithCancel(ctxb)
+
+	fundingAmount := btcutil.Amount(8 * 1e8)
+	chanReservation, err := lnwallet.InitChannelReservation(ctx,
+		fundingAmount, SIGHASH, testHdSeed, 4, true)
+	if err != nil {
+		t.Fatalf("unable to initialize funding reservation: %v", err)
+	}
+	if len(lnwallet.LockedOutpoints()) == 0 {
+		t.Fatalf("no outpoints locked for reservation")
+	}
+
+	// Cancelling the context the reservation was created under, as when
+	// the RPC client requesting the channel disconnects, should cancel
+	// the reservation, releasing its outpoints.
+	cancel()
+
+	select {
+	case <-chanReservation.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("reservation not cancelled with its context")
+	}
+	if err := chanReservation.Err(); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(lnwallet.LockedOutpoints()) != 0 {
+		t.Fatalf("outpoints still locked")
+	}
+
+	// A reservation can't be created under a context which is already
+	// done.
+	_, err = lnwallet.InitChannelReservation(ctx, fundingAmount,
+		SIGHASH, testHdSeed, 4, true)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
 	}
 }
 
@@ -599,6 +708,8 @@ var walletTests = []func(w *LightningWallet, test *testing.T){
 	testBasicWalletReservationWorkFlow,
 	testFundingTransactionLockedOutputs,
 	testFundingCancellationNotEnoughFunds,
+	testFundingReservationTimeout,
+	testFundingReservationContextCancel,
 	testFundingReservationInvalidCounterpartySigs,
 	testFundingTransactionLockedOutputs,
 }
@@ -642,3 +753,254 @@ func TestLightningWallet(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateChanSize(t *testing.T) {
+	tests := []struct {
+		cfg       *Config
+		capacity  btcutil.Amount
+		peerWumbo bool
+		valid     bool
+	}{
+		// Channels below the default minimum are rejected.
+		{&Config{}, defaultMinChanSize - 1, false, false},
+		{&Config{}, defaultMinChanSize, false, true},
+
+		// Without wumbo, neither side may contribute more than
+		// MaxFundingAmount, whether or not the peer supports it.
+		{&Config{}, 2 * lnwire.MaxFundingAmount, false, true},
+		{&Config{}, 2*lnwire.MaxFundingAmount + 1, true, false},
+		{&Config{MaxChanSize: 1e8}, 2*lnwire.MaxFundingAmount + 1, false, false},
+
+		// A lower maximum applies without wumbo.
+		{&Config{MaxChanSize: 1e6}, 1e6 + 1, false, false},
+
+		// Wumbo channels are only allowed should the peer support
+		// them too.
+		{&Config{Wumbo: true}, 1e8, false, false},
+		{&Config{Wumbo: true}, 1e8, true, true},
+		{&Config{Wumbo: true}, defaultWumboMaxChanSize + 1, true, false},
+		{&Config{Wumbo: true, MaxChanSize: 20e8}, 20e8, true, true},
+
+		// A configured minimum applies.
+		{&Config{MinChanSize: 1e6}, 1e6 - 1, false, false},
+	}
+
+	for i, test := range tests {
+		wallet := &LightningWallet{cfg: test.cfg}
+		err := wallet.validateChanSize(test.capacity, test.peerWumbo)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: valid channel size rejected: %v", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: invalid channel size of %v accepted",
+				i, test.capacity)
+		}
+	}
+}
+
+func TestValidateRemoteParams(t *testing.T) {
+	const capacity = btcutil.Amount(1e8)
+
+	valid := func() *ChannelContribution {
+		return &ChannelContribution{
+			CsvDelay:    144,
+			ChanReserve: capacity / 100,
+			DustLimit:   DefaultDustLimit,
+		}
+	}
+
+	tests := []struct {
+		cfg    *Config
+		modify func(c *ChannelContribution)
+		valid  bool
+	}{
+		{&Config{}, func(c *ChannelContribution) {}, true},
+
+		// The CSV delay must be positive, and within our maximum.
+		{&Config{}, func(c *ChannelContribution) { c.CsvDelay = 0 }, false},
+		{&Config{}, func(c *ChannelContribution) {
+			c.CsvDelay = defaultMaxCsvDelay
+		}, true},
+		{&Config{}, func(c *ChannelContribution) {
+			c.CsvDelay = defaultMaxCsvDelay + 1
+		}, false},
+		{&Config{MaxCsvDelay: 100}, func(c *ChannelContribution) {}, false},
+
+		// The dust limit mustn't make their commitment non-standard,
+		// nor exceed our maximum.
+		{&Config{}, func(c *ChannelContribution) {
+			c.DustLimit = DefaultDustLimit - 1
+		}, false},
+		{&Config{}, func(c *ChannelContribution) {
+			c.DustLimit = defaultMaxDustLimit + 1
+		}, false},
+		{&Config{MaxDustLimit: 1e4}, func(c *ChannelContribution) {
+			c.DustLimit = 1e4
+		}, true},
+
+		// The reserve mustn't exceed our maximum share of the
+		// capacity.
+		{&Config{}, func(c *ChannelContribution) {
+			c.ChanReserve = capacity * defaultMaxReservePercent / 100
+		}, true},
+		{&Config{}, func(c *ChannelContribution) {
+			c.ChanReserve = capacity*defaultMaxReservePercent/100 + 1
+		}, false},
+		{&Config{MaxReservePercent: 50}, func(c *ChannelContribution) {
+			c.ChanReserve = capacity / 2
+		}, true},
+	}
+
+	for i, test := range tests {
+		wallet := &LightningWallet{cfg: test.cfg}
+		contribution := valid()
+		test.modify(contribution)
+
+		err := wallet.validateRemoteParams(contribution, capacity)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: valid params rejected: %v", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: invalid params accepted: %+v", i,
+				contribution)
+		}
+	}
+}
+
+func TestValidateFeeBuffer(t *testing.T) {
+	const htlcValue = lnwire.MilliSatoshi(1e7)
+
+	// With a single non-dust HTLC added, the buffer at the default
+	// feerate covers a commitment with two HTLC outputs.
+	buffer := lnwire.NewMSatFromSatoshis(
+		commitFee(feeBufferMultiplier*defaultCommitFeePerKb, 2))
+
+	tests := []struct {
+		initiator bool
+		payToUs   bool
+		balance   lnwire.MilliSatoshi
+		valid     bool
+	}{
+		// The initiator must keep their buffer after offering the
+		// HTLC.
+		{true, false, htlcValue + buffer, true},
+		{true, false, htlcValue + buffer - 1, false},
+		{false, true, htlcValue + buffer, true},
+		{false, true, htlcValue + buffer - 1, false},
+
+		// HTLCs offered to the initiator never eat into their buffer.
+		{true, true, 0, true},
+		{false, false, 0, true},
+	}
+
+	for i, test := range tests {
+		channel := &LightningChannel{
+			channelState: &channeldb.OpenChannel{
+				IsInitiator:  test.initiator,
+				OurBalance:   test.balance,
+				TheirBalance: test.balance,
+			},
+			pendingPayments: make(map[PaymentHash]*PaymentDescriptor),
+		}
+
+		err := channel.validateFeeBuffer(htlcValue, test.payToUs)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: valid htlc rejected: %v", i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: htlc below fee buffer accepted", i)
+		}
+	}
+}
+
+func TestAvailableBalance(t *testing.T) {
+	channel := &LightningChannel{
+		channelState: &channeldb.OpenChannel{
+			IsInitiator:      true,
+			OurBalance:       lnwire.NewMSatFromSatoshis(1e6),
+			TheirBalance:     lnwire.NewMSatFromSatoshis(5e5),
+			OurChanReserve:   1e4,
+			TheirChanReserve: 2e4,
+		},
+		pendingPayments: map[PaymentHash]*PaymentDescriptor{
+			{1}: {Value: lnwire.NewMSatFromSatoshis(1e5)},
+			{2}: {Value: lnwire.NewMSatFromSatoshis(5e4), PayToUs: true},
+		},
+	}
+
+	// As the initiator, we keep a fee buffer on top of our reserve, and
+	// the HTLCs we've offered.
+	buffer := channel.feeBuffer(3)
+	sendable := lnwire.NewMSatFromSatoshis(1e6-2e4-1e5) - buffer
+	if channel.SendableBalance() != sendable {
+		t.Fatalf("expected sendable balance of %v, got %v", sendable,
+			channel.SendableBalance())
+	}
+
+	receivable := lnwire.NewMSatFromSatoshis(5e5 - 1e4 - 5e4)
+	if channel.ReceivableBalance() != receivable {
+		t.Fatalf("expected receivable balance of %v, got %v",
+			receivable, channel.ReceivableBalance())
+	}
+
+	// Balances within the reserve leave nothing to offer.
+	channel.channelState.TheirBalance = lnwire.NewMSatFromSatoshis(1e4)
+	if channel.ReceivableBalance() != 0 {
+		t.Fatalf("expected no receivable balance, got %v",
+			channel.ReceivableBalance())
+	}
+}
+
+func TestMapPublishError(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected error
+	}{
+		{
+			msg: "output 1234:0 already spent by transaction " +
+				"5678 in the memory pool",
+			expected: ErrDoubleSpend,
+		},
+		{
+			msg:      "txn-mempool-conflict (code 18)",
+			expected: ErrDoubleSpend,
+		},
+		{
+			msg: "orphan transaction 1234 references outputs of " +
+				"unknown or fully-spent transaction 5678",
+			expected: ErrMissingInputs,
+		},
+		{
+			msg:      "Missing inputs",
+			expected: ErrMissingInputs,
+		},
+		{
+			msg: "transaction 1234 has 100 fees which is under " +
+				"the required amount of 1000",
+			expected: ErrFeeTooLow,
+		},
+		{
+			msg:      "min relay fee not met",
+			expected: ErrFeeTooLow,
+		},
+	}
+
+	for _, test := range tests {
+		err := mapPublishError(&btcjson.RPCError{Message: test.msg})
+		if err != test.expected {
+			t.Fatalf("%q: expected %v, got %v", test.msg,
+				test.expected, err)
+		}
+	}
+
+	// Other rejections, and failures to reach the backend, are returned
+	// as is.
+	rejection := &btcjson.RPCError{Message: "transaction is not standard"}
+	if err := mapPublishError(rejection); err != rejection {
+		t.Fatalf("expected rejection returned as is, got %v", err)
+	}
+	connErr := errors.New("connection refused")
+	if err := mapPublishError(connErr); err != connErr {
+		t.Fatalf("expected error returned as is, got %v", err)
+	}
+}
@@ -0,0 +1,484 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+const (
+	// watchOnlyLookahead is the number of keys past the next unused key of
+	// each branch of a watch-only account which we watch for payments.
+	watchOnlyLookahead = 20
+
+	// externalBranch and internalBranch are the BIP44 branches used for
+	// receiving and change keys respectively.
+	externalBranch = 0
+	internalBranch = 1
+)
+
+// watchedKey is a key of a watch-only account which we watch for payments.
+type watchedKey struct {
+	account string
+	pubKey  [33]byte
+
+	// derived is true if the key was derived from the extended key of
+	// the account, rather than individually imported.
+	derived bool
+	branch  uint32
+	index   uint32
+	path    []uint32
+}
+
+// ImportAccount adds a new watch-only account backed by the passed extended
+// public key. The master key fingerprint and derivation path of the key are
+// included in funded PSBTs, allowing the external signer holding the private
+// keys to sign for them. If rescan is set, the chain is rescanned for any
+// existing outputs paying to the account.
+func (l *LightningWallet) ImportAccount(name, xpub string,
+	masterKeyFingerprint uint32, derivationPath []uint32, rescan bool) error {
+
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return err
+	}
+	if key.IsPrivate() {
+		return fmt.Errorf("watch-only accounts must be imported as " +
+			"an extended public key")
+	}
+	if !key.IsForNet(ActiveNetParams) {
+		return fmt.Errorf("extended key is for a different network")
+	}
+
+	l.watchOnlyMtx.Lock()
+	defer l.watchOnlyMtx.Unlock()
+
+	if _, err := l.ChannelDB.FetchWatchOnlyAccount(name); err == nil {
+		return ErrAccountExists
+	} else if err != channeldb.ErrAccountNotFound {
+		return err
+	}
+
+	account := &channeldb.WatchOnlyAccount{
+		Name:                 name,
+		ExtendedKey:          xpub,
+		MasterKeyFingerprint: masterKeyFingerprint,
+		DerivationPath:       derivationPath,
+	}
+	if err := l.ChannelDB.PutWatchOnlyAccount(account); err != nil {
+		return err
+	}
+
+	addrs, err := l.watchAccount(account)
+	if err != nil {
+		return err
+	}
+
+	return l.notifyWatchOnlyAddrs(addrs, rescan)
+}
+
+// ImportPubKey adds a single public key to the named watch-only account,
+// creating the account if it doesn't yet exist. If rescan is set, the chain
+// is rescanned for any existing outputs paying to the key.
+func (l *LightningWallet) ImportPubKey(name string, pub *btcec.PublicKey,
+	rescan bool) error {
+
+	l.watchOnlyMtx.Lock()
+	defer l.watchOnlyMtx.Unlock()
+
+	account, err := l.ChannelDB.FetchWatchOnlyAccount(name)
+	switch {
+	case err == channeldb.ErrAccountNotFound:
+		account = &channeldb.WatchOnlyAccount{Name: name}
+	case err != nil:
+		return err
+	}
+
+	var key [33]byte
+	copy(key[:], pub.SerializeCompressed())
+	for _, imported := range account.ImportedKeys {
+		if imported == key {
+			return fmt.Errorf("key already imported")
+		}
+	}
+	account.ImportedKeys = append(account.ImportedKeys, key)
+	if err := l.ChannelDB.PutWatchOnlyAccount(account); err != nil {
+		return err
+	}
+
+	addr, err := l.watchKey(&watchedKey{account: name, pubKey: key})
+	if err != nil {
+		return err
+	}
+
+	return l.notifyWatchOnlyAddrs([]btcutil.Address{addr}, rescan)
+}
+
+// NewWatchOnlyAddress returns the next unused address of the watch-only
+// account, from the change branch if change is set.
+func (l *LightningWallet) NewWatchOnlyAddress(name string,
+	change bool) (btcutil.Address, error) {
+
+	l.watchOnlyMtx.Lock()
+	defer l.watchOnlyMtx.Unlock()
+
+	addr, _, err := l.nextWatchOnlyKey(name, change)
+	return addr, err
+}
+
+// ListWatchOnlyUnspent returns the unspent outputs of the named watch-only
+// account, or of all watch-only accounts if the name is empty.
+func (l *LightningWallet) ListWatchOnlyUnspent(name string) ([]*channeldb.WatchOnlyUtxo, error) {
+	return l.ChannelDB.FetchWatchOnlyUtxos(name)
+}
+
+// ReleaseWatchOnlyOutput unlocks an output of a watch-only account locked
+// when funding a PSBT, allowing it to be selected again.
+func (l *LightningWallet) ReleaseWatchOnlyOutput(op *wire.OutPoint) {
+	l.watchOnlyMtx.Lock()
+	delete(l.lockedWatchOnly, *op)
+	l.watchOnlyMtx.Unlock()
+}
+
+// startWatchOnly loads all watch-only accounts, registers their addresses
+// with the chain backend, and launches the goroutine which tracks their
+// outputs.
+func (l *LightningWallet) startWatchOnly() error {
+	l.watchOnlyMtx.Lock()
+	defer l.watchOnlyMtx.Unlock()
+
+	accounts, err := l.ChannelDB.FetchAllWatchOnlyAccounts()
+	if err != nil {
+		return err
+	}
+
+	var addrs []btcutil.Address
+	for _, account := range accounts {
+		accountAddrs, err := l.watchAccount(account)
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, accountAddrs...)
+	}
+
+	utxos, err := l.ChannelDB.FetchWatchOnlyUtxos("")
+	if err != nil {
+		return err
+	}
+	for _, utxo := range utxos {
+		l.watchOnlyUtxos[utxo.OutPoint] = struct{}{}
+	}
+
+	txChan := make(chan *chainntnfs.RelevantTx, 20)
+	if err := l.chainNotifier.RegisterRelevantTxNotification(txChan); err != nil {
+		return err
+	}
+
+	l.wg.Add(1)
+	go l.watchOnlyTracker(txChan)
+
+	return l.notifyWatchOnlyAddrs(addrs, false)
+}
+
+// watchOnlyTracker records outputs paying to watch-only accounts, and removes
+// them once spent.
+//
+// NOTE: This MUST be run as a goroutine.
+func (l *LightningWallet) watchOnlyTracker(txChan chan *chainntnfs.RelevantTx) {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case relevantTx := <-txChan:
+			if err := l.processWatchOnlyTx(relevantTx); err != nil {
+				fmt.Printf("unable to process watch-only tx: "+
+					"%v\n", err)
+			}
+		case <-l.quit:
+			return
+		}
+	}
+}
+
+// processWatchOnlyTx updates the outputs of our watch-only accounts given a
+// new relevant transaction.
+func (l *LightningWallet) processWatchOnlyTx(relevantTx *chainntnfs.RelevantTx) error {
+	l.watchOnlyMtx.Lock()
+	defer l.watchOnlyMtx.Unlock()
+
+	tx := relevantTx.Tx
+	for _, txIn := range tx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if _, ok := l.watchOnlyUtxos[prevOut]; !ok {
+			continue
+		}
+
+		if err := l.ChannelDB.DeleteWatchOnlyUtxo(&prevOut); err != nil {
+			return err
+		}
+		delete(l.watchOnlyUtxos, prevOut)
+		delete(l.lockedWatchOnly, prevOut)
+	}
+
+	var newAddrs []btcutil.Address
+	txid := tx.TxSha()
+	for i, txOut := range tx.TxOut {
+		key, ok := l.watchedScripts[string(txOut.PkScript)]
+		if !ok {
+			continue
+		}
+
+		utxo := &channeldb.WatchOnlyUtxo{
+			OutPoint:       *wire.NewOutPoint(&txid, uint32(i)),
+			Account:        key.account,
+			Value:          btcutil.Amount(txOut.Value),
+			PkScript:       txOut.PkScript,
+			BlockHeight:    relevantTx.BlockHeight,
+			PubKey:         key.pubKey,
+			DerivationPath: key.path,
+			PrevTx:         tx,
+		}
+		if err := l.ChannelDB.PutWatchOnlyUtxo(utxo); err != nil {
+			return err
+		}
+		l.watchOnlyUtxos[utxo.OutPoint] = struct{}{}
+
+		// If a key past the next unused key was paid to, then advance
+		// the account, extending the set of keys we watch.
+		if !key.derived {
+			continue
+		}
+		addrs, err := l.markKeyUsed(key)
+		if err != nil {
+			return err
+		}
+		newAddrs = append(newAddrs, addrs...)
+	}
+
+	return l.notifyWatchOnlyAddrs(newAddrs, false)
+}
+
+// markKeyUsed advances the next index of the key's branch past the key if
+// required, returning the addresses of any keys newly watched as a result.
+//
+// NOTE: The watchOnlyMtx MUST be held when calling this method.
+func (l *LightningWallet) markKeyUsed(key *watchedKey) ([]btcutil.Address, error) {
+	account, err := l.ChannelDB.FetchWatchOnlyAccount(key.account)
+	if err != nil {
+		return nil, err
+	}
+
+	nextIndex := &account.NextExternalIndex
+	if key.branch == internalBranch {
+		nextIndex = &account.NextInternalIndex
+	}
+	if key.index < *nextIndex {
+		return nil, nil
+	}
+	*nextIndex = key.index + 1
+
+	if err := l.ChannelDB.PutWatchOnlyAccount(account); err != nil {
+		return nil, err
+	}
+
+	return l.watchAccount(account)
+}
+
+// nextWatchOnlyKey derives the next unused key of the watch-only account,
+// advancing the account.
+//
+// NOTE: The watchOnlyMtx MUST be held when calling this method.
+func (l *LightningWallet) nextWatchOnlyKey(name string,
+	change bool) (btcutil.Address, *watchedKey, error) {
+
+	account, err := l.ChannelDB.FetchWatchOnlyAccount(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if account.ExtendedKey == "" {
+		return nil, nil, fmt.Errorf("account %v has no extended key "+
+			"to derive addresses from", name)
+	}
+
+	branch := uint32(externalBranch)
+	nextIndex := &account.NextExternalIndex
+	if change {
+		branch = internalBranch
+		nextIndex = &account.NextInternalIndex
+	}
+
+	accountKey, err := hdkeychain.NewKeyFromString(account.ExtendedKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := deriveWatchOnlyKey(account, accountKey, branch, *nextIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+	*nextIndex++
+	if err := l.ChannelDB.PutWatchOnlyAccount(account); err != nil {
+		return nil, nil, err
+	}
+
+	// Extend the lookahead window to account for the key handed out.
+	addrs, err := l.watchAccount(account)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := l.notifyWatchOnlyAddrs(addrs, false); err != nil {
+		return nil, nil, err
+	}
+
+	addr, err := watchOnlyAddr(key.pubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return addr, key, nil
+}
+
+// watchAccount starts watching all imported keys of the account, along with
+// the keys of each branch up to the lookahead window. The addresses of any
+// keys which weren't previously watched are returned.
+//
+// NOTE: The watchOnlyMtx MUST be held when calling this method.
+func (l *LightningWallet) watchAccount(account *channeldb.WatchOnlyAccount) ([]btcutil.Address, error) {
+	var keys []*watchedKey
+	for _, key := range account.ImportedKeys {
+		keys = append(keys, &watchedKey{
+			account: account.Name,
+			pubKey:  key,
+		})
+	}
+
+	if account.ExtendedKey != "" {
+		accountKey, err := hdkeychain.NewKeyFromString(account.ExtendedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		branches := map[uint32]uint32{
+			externalBranch: account.NextExternalIndex,
+			internalBranch: account.NextInternalIndex,
+		}
+		for branch, nextIndex := range branches {
+			for i := uint32(0); i < nextIndex+watchOnlyLookahead; i++ {
+				key, err := deriveWatchOnlyKey(account,
+					accountKey, branch, i)
+				if err != nil {
+					return nil, err
+				}
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var newAddrs []btcutil.Address
+	for _, key := range keys {
+		addr, err := watchOnlyAddr(key.pubKey)
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := l.watchedScripts[string(pkScript)]; ok {
+			continue
+		}
+
+		l.watchedScripts[string(pkScript)] = key
+		newAddrs = append(newAddrs, addr)
+	}
+
+	return newAddrs, nil
+}
+
+// watchKey starts watching a single key, returning its address.
+//
+// NOTE: The watchOnlyMtx MUST be held when calling this method.
+func (l *LightningWallet) watchKey(key *watchedKey) (btcutil.Address, error) {
+	addr, err := watchOnlyAddr(key.pubKey)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	l.watchedScripts[string(pkScript)] = key
+	return addr, nil
+}
+
+// notifyWatchOnlyAddrs asks the chain backend to send us any transactions
+// paying to the passed addresses, optionally rescanning the chain for past
+// payments.
+func (l *LightningWallet) notifyWatchOnlyAddrs(addrs []btcutil.Address,
+	rescan bool) error {
+
+	// Until the wallet is started, we have no connection to the chain
+	// backend. All addresses are registered once it starts.
+	rpc := l.chainClient()
+	if len(addrs) == 0 || rpc == nil {
+		return nil
+	}
+
+	if err := rpc.NotifyReceived(addrs); err != nil {
+		return err
+	}
+	if !rescan {
+		return nil
+	}
+
+	// TODO: allow a birthday to be passed to avoid scanning the
+	// entire chain
+	return rpc.Rescan(ActiveNetParams.GenesisHash, addrs, nil)
+}
+
+// deriveWatchOnlyKey derives the key at the passed branch and index of the
+// account's extended key.
+func deriveWatchOnlyKey(account *channeldb.WatchOnlyAccount,
+	accountKey *hdkeychain.ExtendedKey, branch,
+	index uint32) (*watchedKey, error) {
+
+	branchKey, err := accountKey.Child(branch)
+	if err != nil {
+		return nil, err
+	}
+	childKey, err := branchKey.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := childKey.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &watchedKey{
+		account: account.Name,
+		derived: true,
+		branch:  branch,
+		index:   index,
+	}
+	copy(key.pubKey[:], pub.SerializeCompressed())
+
+	key.path = make([]uint32, 0, len(account.DerivationPath)+2)
+	key.path = append(key.path, account.DerivationPath...)
+	key.path = append(key.path, branch, index)
+
+	return key, nil
+}
+
+// watchOnlyAddr returns the P2PKH address of the passed key.
+func watchOnlyAddr(pubKey [33]byte) (btcutil.Address, error) {
+	return btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey[:]),
+		ActiveNetParams)
+}
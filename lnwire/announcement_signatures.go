@@ -0,0 +1,100 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// AnnouncementSignatures is sent by each side of a public channel once its
+// funding transaction is buried deep enough to be announced. It carries the
+// signatures of the sender's node key, and of its key within the funding
+// output, over the channel's announcement, allowing the receiver to
+// assemble the fully signed ChannelAnnouncement.
+type AnnouncementSignatures struct {
+	// ChannelID identifies the channel by its funding outpoint.
+	ChannelID ChannelID
+
+	// ShortChannelID locates the funding output within the chain, as
+	// seen by the sender.
+	ShortChannelID ShortChannelID
+
+	// NodeSignature is the signature of the sender's node key over the
+	// announcement.
+	NodeSignature *btcec.Signature
+
+	// BitcoinSignature is the signature of the sender's key within the
+	// funding output over the announcement.
+	BitcoinSignature *btcec.Signature
+}
+
+// Decode ...
+func (c *AnnouncementSignatures) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	// ShortChannelID (8)
+	// NodeSignature (64)
+	// BitcoinSignature (64)
+	err := readElements(r,
+		&c.ChannelID,
+		&c.ShortChannelID,
+		&c.NodeSignature,
+		&c.BitcoinSignature)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewAnnouncementSignatures creates a new AnnouncementSignatures
+func NewAnnouncementSignatures() *AnnouncementSignatures {
+	return &AnnouncementSignatures{}
+}
+
+// Encode serializes the item from the AnnouncementSignatures struct
+// Writes the data to w
+func (c *AnnouncementSignatures) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID,
+		c.ShortChannelID,
+		c.NodeSignature,
+		c.BitcoinSignature)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *AnnouncementSignatures) Command() uint32 {
+	return CmdAnnouncementSignatures
+}
+
+// MaxPayloadLength ...
+func (c *AnnouncementSignatures) MaxPayloadLength(uint32) uint32 {
+	// 32 + 8 + 64 + 64
+	return 168
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *AnnouncementSignatures) Validate() error {
+	if c.NodeSignature == nil || c.BitcoinSignature == nil {
+		return fmt.Errorf("announcement signatures are missing")
+	}
+	if c.ShortChannelID.IsAlias() {
+		return fmt.Errorf("%v is an alias, rather than the location "+
+			"of the funding output", c.ShortChannelID)
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *AnnouncementSignatures) String() string {
+	return fmt.Sprintf("\n--- Begin AnnouncementSignatures ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("ShortChannelID:\t\t%v\n", c.ShortChannelID) +
+		fmt.Sprintf("--- End AnnouncementSignatures ---\n")
+}
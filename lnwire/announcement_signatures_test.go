@@ -0,0 +1,38 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	announcementSignatures = &AnnouncementSignatures{
+		ChannelID: NewChanIDFromOutPoint(outpoint1),
+		ShortChannelID: ShortChannelID{
+			BlockHeight: 432000,
+			TxIndex:     12,
+			TxPosition:  1,
+		},
+		NodeSignature:    commitSig,
+		BitcoinSignature: commitSig1,
+	}
+	announcementSignaturesSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b85506978000000c0001333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1dfe7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db692fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb"
+	announcementSignaturesSerializedMessage = "0709110b0000139c000000a8e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b85506978000000c0001333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1dfe7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db692fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb"
+)
+
+func TestAnnouncementSignaturesEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, announcementSignatures, announcementSignaturesSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewAnnouncementSignatures()
+	DeserializeTest(t, s, newMessage, announcementSignatures)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, announcementSignatures, announcementSignaturesSerializedMessage)
+}
@@ -0,0 +1,154 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// ChannelAnnouncement is broadcast to the network once a channel's funding
+// transaction is sufficiently buried, announcing the channel for use in
+// routing. Both the node keys, and the keys of the 2-of-2 multisig funding
+// output, sign the announcement, proving that the channel's funding output
+// is controlled by the two nodes.
+type ChannelAnnouncement struct {
+	// The signatures of the two nodes.
+	NodeSig1 *btcec.Signature
+	NodeSig2 *btcec.Signature
+
+	// The signatures of the two keys within the funding output.
+	BitcoinSig1 *btcec.Signature
+	BitcoinSig2 *btcec.Signature
+
+	// ShortChannelID locates the funding output within the chain.
+	ShortChannelID ShortChannelID
+
+	// NodeID1 and NodeID2 are the identity keys of the two nodes. NodeID1
+	// MUST be the lesser of the two when serialized.
+	NodeID1 *btcec.PublicKey
+	NodeID2 *btcec.PublicKey
+
+	// BitcoinKey1 and BitcoinKey2 are the keys of the funding output
+	// belonging to NodeID1 and NodeID2 respectively.
+	BitcoinKey1 *btcec.PublicKey
+	BitcoinKey2 *btcec.PublicKey
+}
+
+// Decode ...
+func (c *ChannelAnnouncement) Decode(r io.Reader, pver uint32) error {
+	// NodeSig1 (64)
+	// NodeSig2 (64)
+	// BitcoinSig1 (64)
+	// BitcoinSig2 (64)
+	// ShortChannelID (8)
+	// NodeID1 (33)
+	// NodeID2 (33)
+	// BitcoinKey1 (33)
+	// BitcoinKey2 (33)
+	err := readElements(r,
+		&c.NodeSig1,
+		&c.NodeSig2,
+		&c.BitcoinSig1,
+		&c.BitcoinSig2,
+		&c.ShortChannelID,
+		&c.NodeID1,
+		&c.NodeID2,
+		&c.BitcoinKey1,
+		&c.BitcoinKey2)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewChannelAnnouncement creates a new ChannelAnnouncement
+func NewChannelAnnouncement() *ChannelAnnouncement {
+	return &ChannelAnnouncement{}
+}
+
+// Encode serializes the item from the ChannelAnnouncement struct
+// Writes the data to w
+func (c *ChannelAnnouncement) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.NodeSig1,
+		c.NodeSig2,
+		c.BitcoinSig1,
+		c.BitcoinSig2)
+	if err != nil {
+		return err
+	}
+
+	return c.encodeSignedData(w)
+}
+
+// encodeSignedData serializes the fields of the announcement covered by its
+// signatures.
+func (c *ChannelAnnouncement) encodeSignedData(w io.Writer) error {
+	err := writeElements(w,
+		c.ShortChannelID,
+		c.NodeID1,
+		c.NodeID2,
+		c.BitcoinKey1,
+		c.BitcoinKey2)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DataToSign returns the serialized fields of the announcement which are
+// signed by each of the four keys, being every field but the signatures.
+func (c *ChannelAnnouncement) DataToSign() ([]byte, error) {
+	var b bytes.Buffer
+	if err := c.encodeSignedData(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Command ...
+func (c *ChannelAnnouncement) Command() uint32 {
+	return CmdChannelAnnouncement
+}
+
+// MaxPayloadLength ...
+func (c *ChannelAnnouncement) MaxPayloadLength(uint32) uint32 {
+	// 64*4 + 8 + 33*4
+	return 396
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *ChannelAnnouncement) Validate() error {
+	if c.NodeSig1 == nil || c.NodeSig2 == nil || c.BitcoinSig1 == nil ||
+		c.BitcoinSig2 == nil {
+		return fmt.Errorf("announcement is missing signatures")
+	}
+	if c.NodeID1 == nil || c.NodeID2 == nil || c.BitcoinKey1 == nil ||
+		c.BitcoinKey2 == nil {
+		return fmt.Errorf("announcement is missing keys")
+	}
+
+	// The nodes are ordered so that each channel has a single encoding.
+	if bytes.Compare(c.NodeID1.SerializeCompressed(),
+		c.NodeID2.SerializeCompressed()) >= 0 {
+		return fmt.Errorf("node ids aren't in ascending order")
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *ChannelAnnouncement) String() string {
+	return fmt.Sprintf("\n--- Begin ChannelAnnouncement ---\n") +
+		fmt.Sprintf("ShortChannelID:\t\t%v\n", c.ShortChannelID) +
+		fmt.Sprintf("NodeID1:\t\t%x\n", c.NodeID1.SerializeCompressed()) +
+		fmt.Sprintf("NodeID2:\t\t%x\n", c.NodeID2.SerializeCompressed()) +
+		fmt.Sprintf("BitcoinKey1:\t\t%x\n", c.BitcoinKey1.SerializeCompressed()) +
+		fmt.Sprintf("BitcoinKey2:\t\t%x\n", c.BitcoinKey2.SerializeCompressed()) +
+		fmt.Sprintf("--- End ChannelAnnouncement ---\n")
+}
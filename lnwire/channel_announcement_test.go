@@ -0,0 +1,56 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	channelAnnouncement = &ChannelAnnouncement{
+		NodeSig1:    commitSig,
+		NodeSig2:    commitSig1,
+		BitcoinSig1: commitSig2,
+		BitcoinSig2: commitSig,
+		ShortChannelID: ShortChannelID{
+			BlockHeight: 432000,
+			TxIndex:     12,
+			TxPosition:  1,
+		},
+		NodeID1:     sig2privKey.PubKey(),
+		NodeID2:     pubKey,
+		BitcoinKey1: sig1privKey.PubKey(),
+		BitcoinKey2: pubKey,
+	}
+	channelAnnouncementSerializedString  = "333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1dfe7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db692fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb235ee55fed634080089953048c3e3f7dc3a154fd7ad18f31dc08e05b7864608a3bdd7d4e4d9a8162d4b511faf161f0bb16c45181187125017cd0c620c53876ca333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df06978000000c000102da4b89353c26928e51a14dfb7e7c3356bfc9ea53d3fea11b7d53a7a2dc2d1d9a02f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee03111f3a5444c0115b1ca4ba9ff3879e736160a1de613db4bf566b119da81a5a6e02f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+	channelAnnouncementSerializedMessage = "0709110b000013880000018c333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1dfe7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db692fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb235ee55fed634080089953048c3e3f7dc3a154fd7ad18f31dc08e05b7864608a3bdd7d4e4d9a8162d4b511faf161f0bb16c45181187125017cd0c620c53876ca333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df06978000000c000102da4b89353c26928e51a14dfb7e7c3356bfc9ea53d3fea11b7d53a7a2dc2d1d9a02f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee03111f3a5444c0115b1ca4ba9ff3879e736160a1de613db4bf566b119da81a5a6e02f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+)
+
+func TestChannelAnnouncementEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, channelAnnouncement, channelAnnouncementSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewChannelAnnouncement()
+	DeserializeTest(t, s, newMessage, channelAnnouncement)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, channelAnnouncement, channelAnnouncementSerializedMessage)
+}
+
+func TestChannelAnnouncementDataToSign(t *testing.T) {
+	data, err := channelAnnouncement.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to serialize announcement: %v", err)
+	}
+
+	// Every field but the four signatures is signed.
+	maxLen := int(channelAnnouncement.MaxPayloadLength(0))
+	if len(data) != maxLen-4*SignatureSize {
+		t.Fatalf("signed data is %d bytes", len(data))
+	}
+}
@@ -0,0 +1,98 @@
+package lnwire
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// ChannelID uniquely identifies a channel by its funding outpoint. It's the
+// txid of the funding transaction, with the last two bytes XOR'd with the
+// index of the funding output. Unlike a ShortChannelID, it's known as soon as
+// the funding transaction has been constructed.
+type ChannelID [32]byte
+
+// NewChanIDFromOutPoint converts the funding outpoint of a channel into its
+// ChannelID.
+func NewChanIDFromOutPoint(op *wire.OutPoint) ChannelID {
+	var cid ChannelID
+	copy(cid[:], op.Hash[:])
+
+	cid[30] ^= byte(op.Index >> 8)
+	cid[31] ^= byte(op.Index)
+
+	return cid
+}
+
+// IsChanPoint returns true if the ChannelID was derived from the passed
+// outpoint.
+func (c ChannelID) IsChanPoint(op *wire.OutPoint) bool {
+	return NewChanIDFromOutPoint(op) == c
+}
+
+// String returns the hex encoding of the ChannelID.
+func (c ChannelID) String() string {
+	return hex.EncodeToString(c[:])
+}
+
+const (
+	// AliasStartBlockHeight is the first block height within the range
+	// reserved for alias short channel IDs. Block heights this large won't
+	// be reached for centuries, so an alias can never collide with the
+	// location of a real funding output.
+	AliasStartBlockHeight uint32 = 16000000
+
+	// AliasEndBlockHeight is the end of the range reserved for alias short
+	// channel IDs, exclusive.
+	AliasEndBlockHeight uint32 = 16250000
+)
+
+// ShortChannelID locates the funding output of a confirmed channel within
+// the chain. It's a more compact form of identification than the ChannelID,
+// and is used to refer to channels when forwarding HTLCs, and within channel
+// announcements. It's serialized as 8 bytes:
+//
+//	BlockHeight (3) || TxIndex (3) || TxPosition (2)
+type ShortChannelID struct {
+	// BlockHeight is the height of the block containing the funding
+	// transaction.
+	BlockHeight uint32
+
+	// TxIndex is the index of the funding transaction within the block.
+	TxIndex uint32
+
+	// TxPosition is the index of the funding output within the
+	// transaction.
+	TxPosition uint16
+}
+
+// NewShortChanIDFromInt unpacks a ShortChannelID from its integer encoding.
+func NewShortChanIDFromInt(chanID uint64) ShortChannelID {
+	return ShortChannelID{
+		BlockHeight: uint32(chanID >> 40),
+		TxIndex:     uint32(chanID>>16) & 0xFFFFFF,
+		TxPosition:  uint16(chanID),
+	}
+}
+
+// ToUint64 packs the ShortChannelID into its integer encoding.
+func (c ShortChannelID) ToUint64() uint64 {
+	// Both the block height and tx index are limited to 3 bytes.
+	return (uint64(c.BlockHeight&0xFFFFFF) << 40) |
+		(uint64(c.TxIndex&0xFFFFFF) << 16) |
+		uint64(c.TxPosition)
+}
+
+// String returns a human readable version of the ShortChannelID, in the form
+// height:txindex:position.
+func (c ShortChannelID) String() string {
+	return fmt.Sprintf("%d:%d:%d", c.BlockHeight, c.TxIndex, c.TxPosition)
+}
+
+// IsAlias returns true if the ShortChannelID falls within the range reserved
+// for aliases, rather than locating a funding output within the chain.
+func (c ShortChannelID) IsAlias() bool {
+	return c.BlockHeight >= AliasStartBlockHeight &&
+		c.BlockHeight < AliasEndBlockHeight
+}
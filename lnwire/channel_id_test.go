@@ -0,0 +1,46 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestChannelIDOutPoint(t *testing.T) {
+	op := wire.NewOutPoint(shaHash1, 0x0102)
+	cid := NewChanIDFromOutPoint(op)
+
+	// Only the last two bytes of the txid should be altered by the output
+	// index.
+	if cid[29] != shaHash1[29] || cid[30] != shaHash1[30]^0x01 ||
+		cid[31] != shaHash1[31]^0x02 {
+
+		t.Fatalf("channel id not derived from outpoint: %v", cid)
+	}
+
+	if !cid.IsChanPoint(op) {
+		t.Fatalf("channel id doesn't match its outpoint")
+	}
+	if cid.IsChanPoint(wire.NewOutPoint(shaHash1, 0x0103)) {
+		t.Fatalf("channel id matches the wrong outpoint")
+	}
+}
+
+func TestShortChannelIDEncoding(t *testing.T) {
+	sid := ShortChannelID{
+		BlockHeight: 432000,
+		TxIndex:     1234,
+		TxPosition:  2,
+	}
+
+	packed := sid.ToUint64()
+	if packed != (432000<<40)|(1234<<16)|2 {
+		t.Fatalf("incorrect packing: %x", packed)
+	}
+	if NewShortChanIDFromInt(packed) != sid {
+		t.Fatalf("short channel id doesn't survive round trip")
+	}
+	if sid.String() != "432000:1234:2" {
+		t.Fatalf("unexpected string: %v", sid.String())
+	}
+}
@@ -0,0 +1,48 @@
+package lnwire
+
+import "fmt"
+
+// ChannelType denotes the commitment format of a channel. It's proposed by
+// the initiator within the FundingRequest, and must be echoed back by the
+// responder within the FundingResponse, ensuring both sides unambiguously
+// agree upon the format. A responder which doesn't support the proposed type
+// rejects the channel, rather than falling back to some other format.
+type ChannelType uint8
+
+const (
+	// ChannelTypeLegacy is the original commitment format.
+	ChannelTypeLegacy ChannelType = iota
+
+	// ChannelTypeStaticRemoteKey pays the output of the non-broadcasting
+	// party directly to a key they control, requiring no knowledge of the
+	// channel's state in order to sweep it.
+	ChannelTypeStaticRemoteKey
+
+	// ChannelTypeAnchors adds an anchor output for each party, allowing
+	// either to bump the fee of the commitment transaction via CPFP.
+	ChannelTypeAnchors
+)
+
+// IsKnown returns true if the channel type is one we understand.
+func (c ChannelType) IsKnown() bool {
+	switch c {
+	case ChannelTypeLegacy, ChannelTypeStaticRemoteKey, ChannelTypeAnchors:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns a human readable version of the channel type.
+func (c ChannelType) String() string {
+	switch c {
+	case ChannelTypeLegacy:
+		return "legacy"
+	case ChannelTypeStaticRemoteKey:
+		return "static_remote_key"
+	case ChannelTypeAnchors:
+		return "anchors"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
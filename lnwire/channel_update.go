@@ -0,0 +1,163 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+const (
+	// ChanUpdateDirection is the bit of the Flags of a ChannelUpdate
+	// which selects the node the update originates from. If unset, it's
+	// from NodeID1 of the channel announcement, otherwise NodeID2.
+	ChanUpdateDirection uint16 = 1 << 0
+
+	// ChanUpdateDisabled is the bit of the Flags of a ChannelUpdate which
+	// signals that the originating node won't forward HTLCs over the
+	// channel.
+	ChanUpdateDisabled uint16 = 1 << 1
+)
+
+// ChannelUpdate is broadcast by each node of an announced channel to set
+// the fees, and time lock delta, it requires in order to forward HTLCs over
+// the channel in its direction. Nodes may send new updates at any time, with
+// the one of the latest Timestamp replacing any before it.
+type ChannelUpdate struct {
+	// Signature is the signature of the originating node over the rest
+	// of the update.
+	Signature *btcec.Signature
+
+	ShortChannelID ShortChannelID
+
+	// Timestamp orders updates from the same node, allowing stale ones
+	// to be discarded.
+	Timestamp uint32
+
+	// Flags holds the direction of the update, and whether the channel
+	// is disabled.
+	Flags uint16
+
+	// TimeLockDelta is the number of blocks the node subtracts from the
+	// time lock of incoming HTLCs when forwarding them.
+	TimeLockDelta uint16
+
+	// HtlcMinimumMsat is the smallest HTLC the node will forward.
+	HtlcMinimumMsat MilliSatoshi
+
+	// BaseFee is the fixed fee, in millisatoshi, charged for each HTLC.
+	BaseFee uint32
+
+	// FeeRate is the fee charged per millionth of the HTLC's amount.
+	FeeRate uint32
+}
+
+// Decode ...
+func (c *ChannelUpdate) Decode(r io.Reader, pver uint32) error {
+	// Signature (64)
+	// ShortChannelID (8)
+	// Timestamp (4)
+	// Flags (2)
+	// TimeLockDelta (2)
+	// HtlcMinimumMsat (8)
+	// BaseFee (4)
+	// FeeRate (4)
+	err := readElements(r,
+		&c.Signature,
+		&c.ShortChannelID,
+		&c.Timestamp,
+		&c.Flags,
+		&c.TimeLockDelta,
+		&c.HtlcMinimumMsat,
+		&c.BaseFee,
+		&c.FeeRate)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewChannelUpdate creates a new ChannelUpdate
+func NewChannelUpdate() *ChannelUpdate {
+	return &ChannelUpdate{}
+}
+
+// Encode serializes the item from the ChannelUpdate struct
+// Writes the data to w
+func (c *ChannelUpdate) Encode(w io.Writer, pver uint32) error {
+	if err := writeElement(w, c.Signature); err != nil {
+		return err
+	}
+
+	return c.encodeSignedData(w)
+}
+
+// encodeSignedData serializes the fields of the update covered by its
+// signature.
+func (c *ChannelUpdate) encodeSignedData(w io.Writer) error {
+	err := writeElements(w,
+		c.ShortChannelID,
+		c.Timestamp,
+		c.Flags,
+		c.TimeLockDelta,
+		c.HtlcMinimumMsat,
+		c.BaseFee,
+		c.FeeRate)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DataToSign returns the serialized fields of the update which are signed by
+// the originating node, being every field but the signature itself.
+func (c *ChannelUpdate) DataToSign() ([]byte, error) {
+	var b bytes.Buffer
+	if err := c.encodeSignedData(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// Direction returns 0 if the update originates from NodeID1 of the channel
+// announcement, and 1 if from NodeID2.
+func (c *ChannelUpdate) Direction() uint8 {
+	return uint8(c.Flags & ChanUpdateDirection)
+}
+
+// Command ...
+func (c *ChannelUpdate) Command() uint32 {
+	return CmdChannelUpdate
+}
+
+// MaxPayloadLength ...
+func (c *ChannelUpdate) MaxPayloadLength(uint32) uint32 {
+	// 64 + 8 + 4 + 2 + 2 + 8 + 4 + 4
+	return 96
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *ChannelUpdate) Validate() error {
+	if c.Signature == nil {
+		return fmt.Errorf("update is missing signature")
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *ChannelUpdate) String() string {
+	return fmt.Sprintf("\n--- Begin ChannelUpdate ---\n") +
+		fmt.Sprintf("ShortChannelID:\t\t%v\n", c.ShortChannelID) +
+		fmt.Sprintf("Timestamp:\t\t%d\n", c.Timestamp) +
+		fmt.Sprintf("Flags:\t\t\t%016b\n", c.Flags) +
+		fmt.Sprintf("TimeLockDelta:\t\t%d\n", c.TimeLockDelta) +
+		fmt.Sprintf("HtlcMinimumMsat:\t%d\n", c.HtlcMinimumMsat) +
+		fmt.Sprintf("BaseFee:\t\t%d\n", c.BaseFee) +
+		fmt.Sprintf("FeeRate:\t\t%d\n", c.FeeRate) +
+		fmt.Sprintf("--- End ChannelUpdate ---\n")
+}
@@ -0,0 +1,58 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	channelUpdate = &ChannelUpdate{
+		Signature: commitSig,
+		ShortChannelID: ShortChannelID{
+			BlockHeight: 432000,
+			TxIndex:     12,
+			TxPosition:  1,
+		},
+		Timestamp:       1470000000,
+		Flags:           ChanUpdateDirection,
+		TimeLockDelta:   144,
+		HtlcMinimumMsat: 1000,
+		BaseFee:         1000,
+		FeeRate:         1,
+	}
+	channelUpdateSerializedString  = "333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df06978000000c0001579e6b800001009000000000000003e8000003e800000001"
+	channelUpdateSerializedMessage = "0709110b0000139200000060333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df06978000000c0001579e6b800001009000000000000003e8000003e800000001"
+)
+
+func TestChannelUpdateEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, channelUpdate, channelUpdateSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewChannelUpdate()
+	DeserializeTest(t, s, newMessage, channelUpdate)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, channelUpdate, channelUpdateSerializedMessage)
+}
+
+func TestChannelUpdateDataToSign(t *testing.T) {
+	data, err := channelUpdate.DataToSign()
+	if err != nil {
+		t.Fatalf("unable to serialize update: %v", err)
+	}
+
+	// Every field but the signature is signed.
+	if len(data) != int(channelUpdate.MaxPayloadLength(0))-SignatureSize {
+		t.Fatalf("signed data is %d bytes", len(data))
+	}
+	if channelUpdate.Direction() != 1 {
+		t.Fatalf("expected direction 1, got %d",
+			channelUpdate.Direction())
+	}
+}
@@ -10,7 +10,7 @@ import (
 
 // CloseComplete ...
 type CloseComplete struct {
-	ReservationID uint64
+	ChannelID ChannelID
 
 	ResponderCloseSig *btcec.Signature // Requester's Commitment
 	CloseShaHash      *wire.ShaHash    // TxID of the Close Tx
@@ -18,12 +18,12 @@ type CloseComplete struct {
 
 // Decode ...
 func (c *CloseComplete) Decode(r io.Reader, pver uint32) error {
-	// ReservationID (8)
+	// ChannelID (32)
 	// ResponderCloseSig (73)
 	// 	First byte length then sig
 	// CloseShaHash (32)
 	err := readElements(r,
-		&c.ReservationID,
+		&c.ChannelID,
 		&c.ResponderCloseSig,
 		&c.CloseShaHash)
 	if err != nil {
@@ -41,11 +41,11 @@ func NewCloseComplete() *CloseComplete {
 // Encode serializes the item from the CloseComplete struct
 // Writes the data to w
 func (c *CloseComplete) Encode(w io.Writer, pver uint32) error {
-	// ReservationID
+	// ChannelID
 	// ResponderCloseSig
 	// CloseShaHash
 	err := writeElements(w,
-		c.ReservationID,
+		c.ChannelID,
 		c.ResponderCloseSig,
 		c.CloseShaHash)
 	if err != nil {
@@ -62,8 +62,8 @@ func (c *CloseComplete) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *CloseComplete) MaxPayloadLength(uint32) uint32 {
-	// 8 + 73 + 32
-	return 113
+	// 32 + 64 + 32
+	return 128
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
@@ -83,7 +83,7 @@ func (c *CloseComplete) String() string {
 	}
 
 	return fmt.Sprintf("\n--- Begin CloseComplete ---\n") +
-		fmt.Sprintf("ReservationID:\t\t%d\n", c.ReservationID) +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
 		fmt.Sprintf("ResponderCloseSig:\t%x\n", serializedSig) +
 		fmt.Sprintf("CloseShaHash:\t\t%s\n", shaString) +
 		fmt.Sprintf("--- End CloseComplete ---\n")
@@ -10,7 +10,7 @@ import (
 
 // CloseRequest ...
 type CloseRequest struct {
-	ReservationID uint64
+	ChannelID ChannelID
 
 	RequesterCloseSig *btcec.Signature // Requester's Commitment
 	Fee               btcutil.Amount
@@ -18,12 +18,12 @@ type CloseRequest struct {
 
 // Decode ...
 func (c *CloseRequest) Decode(r io.Reader, pver uint32) error {
-	// ReservationID (8)
+	// ChannelID (32)
 	// RequesterCloseSig (73)
 	// 	First byte length then sig
 	// Fee (8)
 	err := readElements(r,
-		&c.ReservationID,
+		&c.ChannelID,
 		&c.RequesterCloseSig,
 		&c.Fee)
 	if err != nil {
@@ -41,11 +41,11 @@ func NewCloseRequest() *CloseRequest {
 // Encode serializes the item from the CloseRequest struct
 // Writes the data to w
 func (c *CloseRequest) Encode(w io.Writer, pver uint32) error {
-	// ReservationID
+	// ChannelID
 	// RequesterCloseSig
 	// Fee
 	err := writeElements(w,
-		c.ReservationID,
+		c.ChannelID,
 		c.RequesterCloseSig,
 		c.Fee)
 	if err != nil {
@@ -62,8 +62,8 @@ func (c *CloseRequest) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *CloseRequest) MaxPayloadLength(uint32) uint32 {
-	// 8 + 73 + 8
-	return 89
+	// 32 + 64 + 8
+	return 104
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
@@ -83,7 +83,7 @@ func (c *CloseRequest) String() string {
 	}
 
 	return fmt.Sprintf("\n--- Begin CloseRequest ---\n") +
-		fmt.Sprintf("ReservationID:\t\t%d\n", c.ReservationID) +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
 		fmt.Sprintf("CloseSig\t\t%x\n", serializedSig) +
 		fmt.Sprintf("Fee:\t\t\t%d\n", c.Fee) +
 		fmt.Sprintf("--- End CloseRequest ---\n")
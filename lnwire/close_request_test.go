@@ -8,12 +8,12 @@ import (
 
 var (
 	closeRequest = &CloseRequest{
-		ReservationID:     uint64(12345678),
+		ChannelID:         NewChanIDFromOutPoint(outpoint1),
 		RequesterCloseSig: commitSig,
 		Fee:               btcutil.Amount(12345),
 	}
-	closeRequestSerializedString  = "0000000000bc614e4630440220333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb481022057483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df0000000000003039"
-	closeRequestSerializedMessage = "0709110b0000012c000000570000000000bc614e4630440220333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb481022057483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df0000000000003039"
+	closeRequestSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df0000000000003039"
+	closeRequestSerializedMessage = "0709110b0000012c00000068e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df0000000000003039"
 )
 
 func TestCloseRequestEncodeDecode(t *testing.T) {
@@ -10,7 +10,7 @@ import (
 // clearing requests
 type CommitRevocation struct {
 	// We can use a different data type for this if necessary...
-	ChannelID uint64
+	ChannelID ShortChannelID
 
 	// Height of the commitment
 	// You should have the most recent commitment height stored locally
@@ -79,7 +79,7 @@ func (c *CommitRevocation) Validate() error {
 
 func (c *CommitRevocation) String() string {
 	return fmt.Sprintf("\n--- Begin CommitRevocation ---\n") +
-		fmt.Sprintf("ChannelID:\t\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
 		fmt.Sprintf("CommitmentHeight:\t%d\n", c.CommitmentHeight) +
 		fmt.Sprintf("RevocationProof:\t%x\n", c.RevocationProof) +
 		fmt.Sprintf("--- End CommitRevocation ---\n")
@@ -9,7 +9,7 @@ var (
 	_ = copy(revocationHash[:], revocationHashBytes)
 
 	commitRevocation = &CommitRevocation{
-		ChannelID:        uint64(12345678),
+		ChannelID:        NewShortChanIDFromInt(12345678),
 		CommitmentHeight: uint64(12345),
 		RevocationProof:  revocationHash, // technically it's not a hash... fix later
 	}
@@ -13,7 +13,7 @@ import (
 // clearing requests
 type CommitSignature struct {
 	// We can use a different data type for this if necessary...
-	ChannelID uint64
+	ChannelID ShortChannelID
 
 	// Height of the commitment
 	// You should have the most recent commitment height stored locally
@@ -120,7 +120,7 @@ func (c *CommitSignature) String() string {
 	}
 
 	return fmt.Sprintf("\n--- Begin CommitSignature ---\n") +
-		fmt.Sprintf("ChannelID:\t\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
 		fmt.Sprintf("CommitmentHeight:\t%d\n", c.CommitmentHeight) +
 		fmt.Sprintf("UpdatedHTLCKeys:\t%s\n", items) +
 		fmt.Sprintf("RevocationHash:\t\t%x\n", c.RevocationHash) +
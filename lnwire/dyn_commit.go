@@ -0,0 +1,211 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// DynPropose is sent by either side of a quiescent channel to propose new
+// parameters for it, which take effect once the receiver replies with a
+// DynAck. The receiver replies with a DynReject should the parameters be
+// outside its bounds.
+type DynPropose struct {
+	ChannelID ChannelID
+
+	// MaxAcceptedHtlcs, and MaxValueInFlight, limit the number, and total
+	// value, of pending HTLCs the sender accepts.
+	MaxAcceptedHtlcs uint16
+	MaxValueInFlight MilliSatoshi
+
+	// CsvDelay is the delay of the outputs paying either party within the
+	// commitment transactions.
+	CsvDelay uint32
+}
+
+// Decode ...
+func (c *DynPropose) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	// MaxAcceptedHtlcs (2)
+	// MaxValueInFlight (8)
+	// CsvDelay (4)
+	err := readElements(r,
+		&c.ChannelID,
+		&c.MaxAcceptedHtlcs,
+		&c.MaxValueInFlight,
+		&c.CsvDelay)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewDynPropose creates a new DynPropose
+func NewDynPropose() *DynPropose {
+	return &DynPropose{}
+}
+
+// Encode serializes the item from the DynPropose struct
+// Writes the data to w
+func (c *DynPropose) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID,
+		c.MaxAcceptedHtlcs,
+		c.MaxValueInFlight,
+		c.CsvDelay)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *DynPropose) Command() uint32 {
+	return CmdDynPropose
+}
+
+// MaxPayloadLength ...
+func (c *DynPropose) MaxPayloadLength(uint32) uint32 {
+	// 32 + 2 + 8 + 4
+	return 46
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *DynPropose) Validate() error {
+	if c.MaxAcceptedHtlcs == 0 || c.MaxAcceptedHtlcs > MaxHTLCNumber {
+		return fmt.Errorf("MaxAcceptedHtlcs must be between 1 and %v",
+			MaxHTLCNumber)
+	}
+	if c.CsvDelay == 0 {
+		return fmt.Errorf("CsvDelay must be positive")
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *DynPropose) String() string {
+	return fmt.Sprintf("\n--- Begin DynPropose ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("MaxAcceptedHtlcs:\t%v\n", c.MaxAcceptedHtlcs) +
+		fmt.Sprintf("MaxValueInFlight:\t%v\n", c.MaxValueInFlight) +
+		fmt.Sprintf("CsvDelay:\t\t%v\n", c.CsvDelay) +
+		fmt.Sprintf("--- End DynPropose ---\n")
+}
+
+// DynAck is sent in reply to a DynPropose, accepting the proposed
+// parameters.
+type DynAck struct {
+	ChannelID ChannelID
+}
+
+// Decode ...
+func (c *DynAck) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	err := readElements(r,
+		&c.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewDynAck creates a new DynAck
+func NewDynAck() *DynAck {
+	return &DynAck{}
+}
+
+// Encode serializes the item from the DynAck struct
+// Writes the data to w
+func (c *DynAck) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *DynAck) Command() uint32 {
+	return CmdDynAck
+}
+
+// MaxPayloadLength ...
+func (c *DynAck) MaxPayloadLength(uint32) uint32 {
+	// 32
+	return 32
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *DynAck) Validate() error {
+	// We're good!
+	return nil
+}
+
+func (c *DynAck) String() string {
+	return fmt.Sprintf("\n--- Begin DynAck ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("--- End DynAck ---\n")
+}
+
+// DynReject is sent in reply to a DynPropose, rejecting the proposed
+// parameters. The channel keeps its current parameters.
+type DynReject struct {
+	ChannelID ChannelID
+}
+
+// Decode ...
+func (c *DynReject) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	err := readElements(r,
+		&c.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewDynReject creates a new DynReject
+func NewDynReject() *DynReject {
+	return &DynReject{}
+}
+
+// Encode serializes the item from the DynReject struct
+// Writes the data to w
+func (c *DynReject) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *DynReject) Command() uint32 {
+	return CmdDynReject
+}
+
+// MaxPayloadLength ...
+func (c *DynReject) MaxPayloadLength(uint32) uint32 {
+	// 32
+	return 32
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *DynReject) Validate() error {
+	// We're good!
+	return nil
+}
+
+func (c *DynReject) String() string {
+	return fmt.Sprintf("\n--- Begin DynReject ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("--- End DynReject ---\n")
+}
@@ -0,0 +1,55 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	dynPropose = &DynPropose{
+		ChannelID:        NewChanIDFromOutPoint(outpoint1),
+		MaxAcceptedHtlcs: 30,
+		MaxValueInFlight: MilliSatoshi(5000000000),
+		CsvDelay:         144,
+	}
+	dynProposeSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855001e000000012a05f20000000090"
+	dynProposeSerializedMessage = "0709110b000008fc0000002ee3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855001e000000012a05f20000000090"
+
+	dynAck = &DynAck{
+		ChannelID: NewChanIDFromOutPoint(outpoint1),
+	}
+	dynAckSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	dynAckSerializedMessage = "0709110b0000090600000020e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	dynReject = &DynReject{
+		ChannelID: NewChanIDFromOutPoint(outpoint1),
+	}
+	dynRejectSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	dynRejectSerializedMessage = "0709110b0000091000000020e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+func TestDynProposeEncodeDecode(t *testing.T) {
+	s := SerializeTest(t, dynPropose, dynProposeSerializedString, filename)
+
+	newMessage := NewDynPropose()
+	DeserializeTest(t, s, newMessage, dynPropose)
+
+	MessageSerializeDeserializeTest(t, dynPropose, dynProposeSerializedMessage)
+}
+
+func TestDynAckEncodeDecode(t *testing.T) {
+	s := SerializeTest(t, dynAck, dynAckSerializedString, filename)
+
+	newMessage := NewDynAck()
+	DeserializeTest(t, s, newMessage, dynAck)
+
+	MessageSerializeDeserializeTest(t, dynAck, dynAckSerializedMessage)
+}
+
+func TestDynRejectEncodeDecode(t *testing.T) {
+	s := SerializeTest(t, dynReject, dynRejectSerializedString, filename)
+
+	newMessage := NewDynReject()
+	DeserializeTest(t, s, newMessage, dynReject)
+
+	MessageSerializeDeserializeTest(t, dynReject, dynRejectSerializedMessage)
+}
@@ -10,7 +10,7 @@ import (
 // clearing requests
 type ErrorGeneric struct {
 	// We can use a different data type for this if necessary...
-	ChannelID uint64
+	ChannelID ShortChannelID
 	// Some kind of message
 	// Max length 8192
 	Problem string
@@ -72,7 +72,7 @@ func (c *ErrorGeneric) Validate() error {
 
 func (c *ErrorGeneric) String() string {
 	return fmt.Sprintf("\n--- Begin ErrorGeneric ---\n") +
-		fmt.Sprintf("ChannelID:\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t%v\n", c.ChannelID) +
 		fmt.Sprintf("Problem:\t%s\n", c.Problem) +
 		fmt.Sprintf("--- End ErrorGeneric ---\n")
 }
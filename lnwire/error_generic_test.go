@@ -6,7 +6,7 @@ import (
 
 var (
 	errorGeneric = &ErrorGeneric{
-		ChannelID: uint64(12345678),
+		ChannelID: NewShortChanIDFromInt(12345678),
 		Problem:   "Hello world!",
 	}
 	errorGenericSerializedString  = "0000000000bc614e000c48656c6c6f20776f726c6421"
@@ -0,0 +1,85 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxFundingCancelReason is the longest reason a FundingCancel may carry.
+const MaxFundingCancelReason = 8192
+
+// FundingCancel is sent by either side of a funding workflow to abandon the
+// reservation, such as once it has expired, or the user requesting the
+// channel has gone away. The receiver cancels its side of the reservation,
+// releasing the resources it had set aside for the channel, rather than
+// waiting for its own reservation to expire.
+type FundingCancel struct {
+	// ReservationID is the ID the reservation was requested under, within
+	// the initiator's FundingRequest.
+	ReservationID uint64
+
+	// Reason is a human readable description of why the reservation was
+	// cancelled.
+	Reason string
+}
+
+// Decode ...
+func (c *FundingCancel) Decode(r io.Reader, pver uint32) error {
+	// ReservationID (8)
+	// Reason (2+reasonlen)
+	err := readElements(r,
+		&c.ReservationID,
+		&c.Reason)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewFundingCancel creates a new FundingCancel
+func NewFundingCancel() *FundingCancel {
+	return &FundingCancel{}
+}
+
+// Encode serializes the item from the FundingCancel struct
+// Writes the data to w
+func (c *FundingCancel) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ReservationID,
+		c.Reason)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *FundingCancel) Command() uint32 {
+	return CmdFundingCancel
+}
+
+// MaxPayloadLength ...
+func (c *FundingCancel) MaxPayloadLength(uint32) uint32 {
+	// 8 + 2 + 8192
+	return 8202
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *FundingCancel) Validate() error {
+	if len(c.Reason) > MaxFundingCancelReason {
+		return fmt.Errorf("reason of %v bytes exceeds the maximum of %v",
+			len(c.Reason), MaxFundingCancelReason)
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *FundingCancel) String() string {
+	return fmt.Sprintf("\n--- Begin FundingCancel ---\n") +
+		fmt.Sprintf("ReservationID:\t\t%d\n", c.ReservationID) +
+		fmt.Sprintf("Reason:\t\t\t%s\n", c.Reason) +
+		fmt.Sprintf("--- End FundingCancel ---\n")
+}
@@ -0,0 +1,46 @@
+package lnwire
+
+import (
+	"strings"
+	"testing"
+)
+
+var (
+	fundingCancel = &FundingCancel{
+		ReservationID: 1,
+		Reason:        "Reservation timed out",
+	}
+	fundingCancelSerializedString  = "000000000000000100155265736572766174696f6e2074696d6564206f7574"
+	fundingCancelSerializedMessage = "0709110b000000fa0000001f000000000000000100155265736572766174696f6e2074696d6564206f7574"
+)
+
+func TestFundingCancelEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, fundingCancel, fundingCancelSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewFundingCancel()
+	DeserializeTest(t, s, newMessage, fundingCancel)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, fundingCancel, fundingCancelSerializedMessage)
+}
+
+func TestFundingCancelValidate(t *testing.T) {
+	if err := fundingCancel.Validate(); err != nil {
+		t.Fatalf("valid message rejected: %v", err)
+	}
+
+	invalid := &FundingCancel{
+		Reason: strings.Repeat("a", MaxFundingCancelReason+1),
+	}
+	if err := invalid.Validate(); err == nil {
+		t.Fatalf("overlong reason accepted")
+	}
+}
@@ -0,0 +1,81 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// FundingLocked is sent by both sides once they consider the channel usable.
+// Normally this is only once the funding transaction has the required number
+// of confirmations, but for zero-conf channels with trusted peers it's sent
+// as soon as the funding handshake completes. Before the funding transaction
+// confirms the channel has no ShortChannelID, so each side also hands out an
+// alias which the other may use to route HTLCs over the channel in the
+// meantime.
+type FundingLocked struct {
+	ChannelID ChannelID
+
+	// AliasScid is the alias the sender wishes the channel to be known by
+	// for forwarding purposes until the funding transaction confirms.
+	AliasScid ShortChannelID
+}
+
+// Decode ...
+func (c *FundingLocked) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	// AliasScid (8)
+	err := readElements(r,
+		&c.ChannelID,
+		&c.AliasScid)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewFundingLocked creates a new FundingLocked
+func NewFundingLocked() *FundingLocked {
+	return &FundingLocked{}
+}
+
+// Encode serializes the item from the FundingLocked struct
+// Writes the data to w
+func (c *FundingLocked) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID,
+		c.AliasScid)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *FundingLocked) Command() uint32 {
+	return CmdFundingLocked
+}
+
+// MaxPayloadLength ...
+func (c *FundingLocked) MaxPayloadLength(uint32) uint32 {
+	// 32 + 8
+	return 40
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *FundingLocked) Validate() error {
+	if !c.AliasScid.IsAlias() {
+		return fmt.Errorf("%v is not within the alias range", c.AliasScid)
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *FundingLocked) String() string {
+	return fmt.Sprintf("\n--- Begin FundingLocked ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("AliasScid:\t\t%v\n", c.AliasScid) +
+		fmt.Sprintf("--- End FundingLocked ---\n")
+}
@@ -0,0 +1,48 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	fundingLocked = &FundingLocked{
+		ChannelID: NewChanIDFromOutPoint(outpoint1),
+		AliasScid: ShortChannelID{
+			BlockHeight: AliasStartBlockHeight,
+			TxIndex:     1,
+		},
+	}
+	fundingLockedSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855f424000000010000"
+	fundingLockedSerializedMessage = "0709110b000000f000000028e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855f424000000010000"
+)
+
+func TestFundingLockedEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, fundingLocked, fundingLockedSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewFundingLocked()
+	DeserializeTest(t, s, newMessage, fundingLocked)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, fundingLocked, fundingLockedSerializedMessage)
+}
+
+func TestFundingLockedValidate(t *testing.T) {
+	if err := fundingLocked.Validate(); err != nil {
+		t.Fatalf("valid message rejected: %v", err)
+	}
+
+	invalid := &FundingLocked{
+		AliasScid: ShortChannelID{BlockHeight: 432000},
+	}
+	if err := invalid.Validate(); err == nil {
+		t.Fatalf("non-alias scid accepted")
+	}
+}
@@ -9,11 +9,33 @@ import (
 	"github.com/btcsuite/btcutil"
 )
 
+// MaxHTLCNumber is the greatest number of pending HTLCs either party may
+// accept within a channel, keeping the commitment transaction within the
+// standard size limit.
+const MaxHTLCNumber = 483
+
+// FundingFlagPrivate is the bit of the ChannelFlags of a FundingRequest
+// which asks that the channel never be announced to the network. Neither
+// side sends its announcement signatures, and channel updates are only
+// exchanged between the two.
+const FundingFlagPrivate uint8 = 1 << 0
+
+// FundingFlagWumbo is the bit of the ChannelFlags of a FundingRequest
+// signalling that the requester supports "wumbo" channels, which lift the
+// MaxFundingAmount limit. The requester may only contribute more than
+// MaxFundingAmount while setting it, and a responder which doesn't support
+// wumbo channels rejects such a request.
+const FundingFlagWumbo uint8 = 1 << 1
+
+// MaxFundingAmount is the most either side may contribute to a channel,
+// unless both support wumbo channels.
+const MaxFundingAmount = btcutil.Amount(1<<24 - 1)
+
 // FundingRequest ...
 type FundingRequest struct {
 	ReservationID uint64
 
-	ChannelType uint8
+	ChannelType ChannelType
 
 	RequesterFundingAmount btcutil.Amount
 	RequesterReserveAmount btcutil.Amount
@@ -47,6 +69,15 @@ type FundingRequest struct {
 	// 2: channel responder
 	FeePayer uint8
 
+	// MaxValueInFlight, and MaxAcceptedHtlcs, limit the total value, and
+	// number, of pending HTLCs the sender of this message may be offered.
+	MaxValueInFlight MilliSatoshi
+	MaxAcceptedHtlcs uint16
+
+	// ChannelFlags signal how the requester would like the channel to be
+	// treated, such as FundingFlagPrivate.
+	ChannelFlags uint8
+
 	RevocationHash   [20]byte
 	Pubkey           *btcec.PublicKey
 	DeliveryPkScript PkScript // *MUST* be either P2PKH or P2SH
@@ -69,6 +100,9 @@ func (c *FundingRequest) Decode(r io.Reader, pver uint32) error {
 	// MinDepth (4)
 	// LockTime (4)
 	// FeePayer (1)
+	// MaxValueInFlight (8)
+	// MaxAcceptedHtlcs (2)
+	// ChannelFlags (1)
 	// DeliveryPkScript (final delivery)
 	// 	First byte length then pkscript
 	// ChangePkScript (change for extra from inputs)
@@ -89,6 +123,9 @@ func (c *FundingRequest) Decode(r io.Reader, pver uint32) error {
 		&c.MinDepth,
 		&c.LockTime,
 		&c.FeePayer,
+		&c.MaxValueInFlight,
+		&c.MaxAcceptedHtlcs,
+		&c.ChannelFlags,
 		&c.DeliveryPkScript,
 		&c.ChangePkScript,
 		&c.Inputs)
@@ -116,6 +153,9 @@ func (c *FundingRequest) Encode(w io.Writer, pver uint32) error {
 	// Minimum Transaction Fee Per KB
 	// LockTime
 	// FeePayer
+	// MaxValueInFlight
+	// MaxAcceptedHtlcs
+	// ChannelFlags
 	// DeliveryPkScript
 	// ChangePkScript
 	// Inputs: Append the actual Txins
@@ -132,6 +172,9 @@ func (c *FundingRequest) Encode(w io.Writer, pver uint32) error {
 		c.MinDepth,
 		c.LockTime,
 		c.FeePayer,
+		c.MaxValueInFlight,
+		c.MaxAcceptedHtlcs,
+		c.ChannelFlags,
 		c.DeliveryPkScript,
 		c.ChangePkScript,
 		c.Inputs)
@@ -149,14 +192,18 @@ func (c *FundingRequest) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *FundingRequest) MaxPayloadLength(uint32) uint32 {
-	// 110 (base size) + 26 (pkscript) + 26 (pkscript) + 1 (numTxes) + 127*36(127 inputs * sha256+idx)
-	return 4735
+	// 121 (base size) + 35 (pkscript) + 35 (pkscript) + 1 (numTxes) + 127*36(127 inputs * sha256+idx)
+	return 4764
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
 func (c *FundingRequest) Validate() error {
 	var err error
 
+	if !c.ChannelType.IsKnown() {
+		return fmt.Errorf("unknown channel type: %v", c.ChannelType)
+	}
+
 	// No negative values
 	if c.RequesterFundingAmount < 0 {
 		return fmt.Errorf("RequesterFundingAmount cannot be negative")
@@ -180,12 +227,27 @@ func (c *FundingRequest) Validate() error {
 	if c.RequesterFundingAmount < c.RequesterReserveAmount {
 		return fmt.Errorf("Reserve must be below Funding Amount")
 	}
+	if c.RequesterFundingAmount > MaxFundingAmount &&
+		c.ChannelFlags&FundingFlagWumbo == 0 {
+
+		return fmt.Errorf("RequesterFundingAmount of %v exceeds %v "+
+			"without signalling wumbo", c.RequesterFundingAmount,
+			MaxFundingAmount)
+	}
 
 	// This wallet only allows payment from the requester to responder
 	if c.PaymentAmount < 0 {
 		return fmt.Errorf("This wallet requieres payment to be greater than zero.")
 	}
 
+	if c.MaxValueInFlight == 0 {
+		return fmt.Errorf("MaxValueInFlight must be greater than zero")
+	}
+	if c.MaxAcceptedHtlcs == 0 || c.MaxAcceptedHtlcs > MaxHTLCNumber {
+		return fmt.Errorf("MaxAcceptedHtlcs must be between 1 and %d",
+			MaxHTLCNumber)
+	}
+
 	// Make sure there's not more than 127 inputs
 	if len(c.Inputs) > 127 {
 		return fmt.Errorf("Too many inputs")
@@ -224,7 +286,7 @@ func (c *FundingRequest) String() string {
 
 	return fmt.Sprintf("\n--- Begin FundingRequest ---\n") +
 		fmt.Sprintf("ReservationID:\t\t\t%d\n", c.ReservationID) +
-		fmt.Sprintf("ChannelType:\t\t\t%x\n", c.ChannelType) +
+		fmt.Sprintf("ChannelType:\t\t\t%v\n", c.ChannelType) +
 		fmt.Sprintf("RequesterFundingAmount:\t\t%s\n", c.RequesterFundingAmount.String()) +
 		fmt.Sprintf("RequesterReserveAmount:\t\t%s\n", c.RequesterReserveAmount.String()) +
 		fmt.Sprintf("MinFeePerKb:\t\t\t%s\n", c.MinFeePerKb.String()) +
@@ -233,6 +295,9 @@ func (c *FundingRequest) String() string {
 		fmt.Sprintf("MinTotalFundingAmount\t\t%s\n", c.MinTotalFundingAmount.String()) +
 		fmt.Sprintf("LockTime\t\t\t%d\n", c.LockTime) +
 		fmt.Sprintf("FeePayer\t\t\t%x\n", c.FeePayer) +
+		fmt.Sprintf("MaxValueInFlight\t\t%s\n", c.MaxValueInFlight.String()) +
+		fmt.Sprintf("MaxAcceptedHtlcs\t\t%d\n", c.MaxAcceptedHtlcs) +
+		fmt.Sprintf("ChannelFlags\t\t\t%x\n", c.ChannelFlags) +
 		fmt.Sprintf("RevocationHash\t\t\t%x\n", c.RevocationHash) +
 		fmt.Sprintf("Pubkey\t\t\t\t%x\n", serializedPubkey) +
 		fmt.Sprintf("DeliveryPkScript\t\t%x\n", c.DeliveryPkScript) +
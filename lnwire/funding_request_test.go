@@ -13,7 +13,7 @@ var (
 	// funding request
 	fundingRequest = &FundingRequest{
 		ReservationID:          uint64(12345678),
-		ChannelType:            uint8(0),
+		ChannelType:            ChannelTypeLegacy,
 		RequesterFundingAmount: btcutil.Amount(100000000),
 		RequesterReserveAmount: btcutil.Amount(131072),
 		MinFeePerKb:            btcutil.Amount(20000),
@@ -24,12 +24,15 @@ var (
 		MinDepth:               uint32(6),
 		RevocationHash:         revocationHash,
 		Pubkey:                 pubKey,
+		MaxValueInFlight:       MilliSatoshi(50000000),
+		MaxAcceptedHtlcs:       483,
+		ChannelFlags:           FundingFlagPrivate | FundingFlagWumbo,
 		DeliveryPkScript:       deliveryPkScript,
 		ChangePkScript:         changePkScript,
 		Inputs:                 inputs,
 	}
-	fundingRequestSerializedString  = "0000000000bc614e000000000005f5e1000000000008f0d1804132b6b48371f7b022a16eacb9b2b0ebee134d4102f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee00000000000200000000000000004e20000000000012d68700000006000010e0001976a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd488ac1976a914238ee44bb5c8c1314dd03974a17ec6c406fdcb8388ac02e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b8550000000001ba4719c80b6fe911b091a7c05124b64eeece964e09c058ef8f9805daca546b00000001"
-	fundingRequestSerializedMessage = "0709110b000000c8000000ec0000000000bc614e000000000005f5e1000000000008f0d1804132b6b48371f7b022a16eacb9b2b0ebee134d4102f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee00000000000200000000000000004e20000000000012d68700000006000010e0001976a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd488ac1976a914238ee44bb5c8c1314dd03974a17ec6c406fdcb8388ac02e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b8550000000001ba4719c80b6fe911b091a7c05124b64eeece964e09c058ef8f9805daca546b00000001"
+	fundingRequestSerializedString  = "0000000000bc614e000000000005f5e1000000000008f0d1804132b6b48371f7b022a16eacb9b2b0ebee134d4102f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee00000000000200000000000000004e20000000000012d68700000006000010e0000000000002faf08001e3031976a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd488ac1976a914238ee44bb5c8c1314dd03974a17ec6c406fdcb8388ac02e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b8550000000001ba4719c80b6fe911b091a7c05124b64eeece964e09c058ef8f9805daca546b00000001"
+	fundingRequestSerializedMessage = "0709110b000000c8000000f70000000000bc614e000000000005f5e1000000000008f0d1804132b6b48371f7b022a16eacb9b2b0ebee134d4102f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee00000000000200000000000000004e20000000000012d68700000006000010e0000000000002faf08001e3031976a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd488ac1976a914238ee44bb5c8c1314dd03974a17ec6c406fdcb8388ac02e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b8550000000001ba4719c80b6fe911b091a7c05124b64eeece964e09c058ef8f9805daca546b00000001"
 )
 
 func TestFundingRequestEncodeDecode(t *testing.T) {
@@ -49,3 +52,49 @@ func TestFundingRequestEncodeDecode(t *testing.T) {
 	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
 	MessageSerializeDeserializeTest(t, fundingRequest, fundingRequestSerializedMessage)
 }
+
+func TestFundingRequestUnknownChannelType(t *testing.T) {
+	req := *fundingRequest
+	req.ChannelType = ChannelType(3)
+	if err := req.Validate(); err == nil {
+		t.Fatalf("unknown channel type accepted")
+	}
+
+	req.ChannelType = ChannelTypeAnchors
+	if err := req.Validate(); err != nil {
+		t.Fatalf("anchors channel type rejected: %v", err)
+	}
+}
+
+func TestFundingRequestHtlcLimits(t *testing.T) {
+	req := *fundingRequest
+	req.MaxAcceptedHtlcs = 0
+	if err := req.Validate(); err == nil {
+		t.Fatalf("zero max accepted htlcs accepted")
+	}
+
+	req.MaxAcceptedHtlcs = MaxHTLCNumber + 1
+	if err := req.Validate(); err == nil {
+		t.Fatalf("max accepted htlcs above protocol limit accepted")
+	}
+
+	req.MaxAcceptedHtlcs = MaxHTLCNumber
+	req.MaxValueInFlight = 0
+	if err := req.Validate(); err == nil {
+		t.Fatalf("zero max value in flight accepted")
+	}
+}
+
+func TestFundingRequestWumbo(t *testing.T) {
+	req := *fundingRequest
+	req.ChannelFlags = FundingFlagPrivate
+	if err := req.Validate(); err == nil {
+		t.Fatalf("funding amount above MaxFundingAmount accepted " +
+			"without wumbo")
+	}
+
+	req.RequesterFundingAmount = MaxFundingAmount
+	if err := req.Validate(); err != nil {
+		t.Fatalf("funding amount of MaxFundingAmount rejected: %v", err)
+	}
+}
@@ -11,7 +11,7 @@ import (
 
 // FundingResponse ...
 type FundingResponse struct {
-	ChannelType uint8
+	ChannelType ChannelType
 
 	ReservationID uint64
 
@@ -31,6 +31,11 @@ type FundingResponse struct {
 	// 2: channel responder
 	FeePayer uint8
 
+	// MaxValueInFlight, and MaxAcceptedHtlcs, limit the total value, and
+	// number, of pending HTLCs the sender of this message may be offered.
+	MaxValueInFlight MilliSatoshi
+	MaxAcceptedHtlcs uint16
+
 	RevocationHash   [20]byte
 	Pubkey           *btcec.PublicKey
 	CommitSig        *btcec.Signature // Requester's Commitment
@@ -52,6 +57,8 @@ func (c *FundingResponse) Decode(r io.Reader, pver uint32) error {
 	// MinDepth (4)
 	// LockTime (4)
 	// FeePayer (1)
+	// MaxValueInFlight (8)
+	// MaxAcceptedHtlcs (2)
 	// DeliveryPkScript (final delivery)
 	// 	First byte length then pkscript
 	// ChangePkScript (change for extra from inputs)
@@ -72,6 +79,8 @@ func (c *FundingResponse) Decode(r io.Reader, pver uint32) error {
 		&c.MinDepth,
 		&c.LockTime,
 		&c.FeePayer,
+		&c.MaxValueInFlight,
+		&c.MaxAcceptedHtlcs,
 		&c.DeliveryPkScript,
 		&c.ChangePkScript,
 		&c.CommitSig,
@@ -100,6 +109,8 @@ func (c *FundingResponse) Encode(w io.Writer, pver uint32) error {
 	// Minimum Transaction Fee Per Kb (8)
 	// LockTime (4)
 	// FeePayer (1)
+	// MaxValueInFlight (8)
+	// MaxAcceptedHtlcs (2)
 	// DeliveryPkScript (final delivery)
 	// ChangePkScript (change for extra from inputs)
 	// CommitSig
@@ -115,6 +126,8 @@ func (c *FundingResponse) Encode(w io.Writer, pver uint32) error {
 		c.MinDepth,
 		c.LockTime,
 		c.FeePayer,
+		c.MaxValueInFlight,
+		c.MaxAcceptedHtlcs,
 		c.DeliveryPkScript,
 		c.ChangePkScript,
 		c.CommitSig,
@@ -133,14 +146,18 @@ func (c *FundingResponse) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *FundingResponse) MaxPayloadLength(uint32) uint32 {
-	// 86 (base size) + 26 (pkscript) + 26 (pkscript) + 74sig + 1 (numTxes) + 127*36(127 inputs * sha256+idx)
-	return 4785
+	// 96 (base size) + 35 (pkscript) + 35 (pkscript) + 64sig + 1 (numTxes) + 127*36(127 inputs * sha256+idx)
+	return 4803
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
 func (c *FundingResponse) Validate() error {
 	var err error
 
+	if !c.ChannelType.IsKnown() {
+		return fmt.Errorf("unknown channel type: %v", c.ChannelType)
+	}
+
 	// No negative values
 	if c.ResponderFundingAmount < 0 {
 		return fmt.Errorf("ResponderFundingAmount cannot be negative")
@@ -159,6 +176,14 @@ func (c *FundingResponse) Validate() error {
 		return fmt.Errorf("Reserve must be below Funding Amount")
 	}
 
+	if c.MaxValueInFlight == 0 {
+		return fmt.Errorf("MaxValueInFlight must be greater than zero")
+	}
+	if c.MaxAcceptedHtlcs == 0 || c.MaxAcceptedHtlcs > MaxHTLCNumber {
+		return fmt.Errorf("MaxAcceptedHtlcs must be between 1 and %d",
+			MaxHTLCNumber)
+	}
+
 	// Make sure there's not more than 127 inputs
 	if len(c.Inputs) > 127 {
 		return fmt.Errorf("Too many inputs")
@@ -196,7 +221,7 @@ func (c *FundingResponse) String() string {
 	}
 
 	return fmt.Sprintf("\n--- Begin FundingResponse ---\n") +
-		fmt.Sprintf("ChannelType:\t\t\t%x\n", c.ChannelType) +
+		fmt.Sprintf("ChannelType:\t\t\t%v\n", c.ChannelType) +
 		fmt.Sprintf("ReservationID:\t\t\t%d\n", c.ReservationID) +
 		fmt.Sprintf("ResponderFundingAmount:\t\t%s\n", c.ResponderFundingAmount.String()) +
 		fmt.Sprintf("ResponderReserveAmount:\t\t%s\n", c.ResponderReserveAmount.String()) +
@@ -204,6 +229,8 @@ func (c *FundingResponse) String() string {
 		fmt.Sprintf("MinDepth:\t\t\t%d\n", c.MinDepth) +
 		fmt.Sprintf("LockTime\t\t\t%d\n", c.LockTime) +
 		fmt.Sprintf("FeePayer\t\t\t%x\n", c.FeePayer) +
+		fmt.Sprintf("MaxValueInFlight\t\t%s\n", c.MaxValueInFlight.String()) +
+		fmt.Sprintf("MaxAcceptedHtlcs\t\t%d\n", c.MaxAcceptedHtlcs) +
 		fmt.Sprintf("RevocationHash\t\t\t%x\n", c.RevocationHash) +
 		fmt.Sprintf("Pubkey\t\t\t\t%x\n", serializedPubkey) +
 		fmt.Sprintf("CommitSig\t\t\t%x\n", c.CommitSig.Serialize()) +
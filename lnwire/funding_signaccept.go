@@ -64,8 +64,8 @@ func (c *FundingSignAccept) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *FundingSignAccept) MaxPayloadLength(uint32) uint32 {
-	// 8 (base size) + 73 + (73maxSigSize*127maxInputs)
-	return 9352
+	// 8 (base size) + 64 + 1 + (64sigSize*127maxInputs)
+	return 8201
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
@@ -61,8 +61,8 @@ func (c *FundingSignComplete) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *FundingSignComplete) MaxPayloadLength(uint32) uint32 {
-	// 8 (base size) + 32 + (73maxSigSize*127maxInputs)
-	return 9311
+	// 8 (base size) + 32 + 1 + (64sigSize*127maxInputs)
+	return 8169
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
@@ -11,8 +11,8 @@ var (
 		TxID:          txid,
 		FundingTXSigs: ptrFundingTXSigs,
 	}
-	fundingSignCompleteSerializedString  = "0000000000bc614efd95c6e5c9d5bcf9cfc7231b6a438e46c518c724d0b04b75cc8fddf84a254e3a02473045022100e7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db6902202fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb4630440220235ee55fed634080089953048c3e3f7dc3a154fd7ad18f31dc08e05b7864608a02203bdd7d4e4d9a8162d4b511faf161f0bb16c45181187125017cd0c620c53876ca"
-	fundingSignCompleteSerializedMessage = "0709110b000000e6000000b80000000000bc614efd95c6e5c9d5bcf9cfc7231b6a438e46c518c724d0b04b75cc8fddf84a254e3a02473045022100e7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db6902202fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb4630440220235ee55fed634080089953048c3e3f7dc3a154fd7ad18f31dc08e05b7864608a02203bdd7d4e4d9a8162d4b511faf161f0bb16c45181187125017cd0c620c53876ca"
+	fundingSignCompleteSerializedString  = "0000000000bc614efd95c6e5c9d5bcf9cfc7231b6a438e46c518c724d0b04b75cc8fddf84a254e3a02e7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db692fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb235ee55fed634080089953048c3e3f7dc3a154fd7ad18f31dc08e05b7864608a3bdd7d4e4d9a8162d4b511faf161f0bb16c45181187125017cd0c620c53876ca"
+	fundingSignCompleteSerializedMessage = "0709110b000000e6000000a90000000000bc614efd95c6e5c9d5bcf9cfc7231b6a438e46c518c724d0b04b75cc8fddf84a254e3a02e7946d057c0b4cc4d3ea525ba156b429796858ebc543d75a6c6c2cbca732db692fea377c1f9fb98cd103cf5a4fba276a074b378d4227d15f5fa6439f1a6685bb235ee55fed634080089953048c3e3f7dc3a154fd7ad18f31dc08e05b7864608a3bdd7d4e4d9a8162d4b511faf161f0bb16c45181187125017cd0c620c53876ca"
 )
 
 func TestFundingSignCompleteEncodeDecode(t *testing.T) {
@@ -0,0 +1,118 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// FinalHopPayload is the portion of an HTLC's blob intended for the final
+// node of the route. It carries the payment secret from the invoice being
+// paid, proving to the receiver that the sender knows the invoice rather
+// than just its payment hash.
+type FinalHopPayload struct {
+	// PaymentSecret is the payment secret taken from the invoice.
+	PaymentSecret [32]byte
+
+	// TotalAmount is the total amount of the payment. If the payment is
+	// split across several HTLCs, this is the sum of all of them.
+	TotalAmount MilliSatoshi
+
+	// AMP is only set for the HTLCs of AMP payments.
+	AMP *AMPRecord
+}
+
+// AMPRecord is carried by each HTLC of an AMP payment, handing the receiver
+// the share of the root seed of the payment needed to settle it.
+type AMPRecord struct {
+	// InvoiceHash is the payment hash of the AMP invoice being paid. As
+	// each HTLC of the payment is locked to its own hash, it's needed to
+	// find the invoice.
+	InvoiceHash [20]byte
+
+	// SetID identifies the payment the HTLC is a part of.
+	SetID [32]byte
+
+	// Share is the share of the root seed the HTLC carries, and
+	// ChildIndex the index of the HTLC within its set.
+	Share      [32]byte
+	ChildIndex uint32
+}
+
+// Encode serializes the payload into w.
+func (f *FinalHopPayload) Encode(w io.Writer) error {
+	// PaymentSecret(32)
+	// TotalAmount(8)
+	err := writeElements(w,
+		f.PaymentSecret,
+		f.TotalAmount,
+	)
+	if err != nil || f.AMP == nil {
+		return err
+	}
+
+	// InvoiceHash(20)
+	// SetID(32)
+	// Share(32)
+	// ChildIndex(4)
+	return writeElements(w,
+		f.AMP.InvoiceHash,
+		f.AMP.SetID,
+		f.AMP.Share,
+		f.AMP.ChildIndex,
+	)
+}
+
+// Decode deserializes a payload from r. The AMP record is read if r holds
+// more than the payment secret and total amount.
+func (f *FinalHopPayload) Decode(r io.Reader) error {
+	err := readElements(r,
+		&f.PaymentSecret,
+		&f.TotalAmount,
+	)
+	if err != nil {
+		return err
+	}
+
+	var invoiceHash [20]byte
+	switch _, err := io.ReadFull(r, invoiceHash[:]); err {
+	case io.EOF:
+		return nil
+	case nil:
+	default:
+		return err
+	}
+
+	f.AMP = &AMPRecord{InvoiceHash: invoiceHash}
+	return readElements(r,
+		&f.AMP.SetID,
+		&f.AMP.Share,
+		&f.AMP.ChildIndex,
+	)
+}
+
+// ParseFinalHopPayload parses the final hop payload from the blob of an
+// HTLCAddRequest.
+func ParseFinalHopPayload(blob []byte) (*FinalHopPayload, error) {
+	r := bytes.NewReader(blob)
+
+	payload := &FinalHopPayload{}
+	if err := payload.Decode(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%v trailing bytes after final hop "+
+			"payload", r.Len())
+	}
+
+	return payload, nil
+}
+
+// String returns a human readable version of the payload.
+func (f *FinalHopPayload) String() string {
+	return fmt.Sprintf("\n--- Begin FinalHopPayload ---\n") +
+		fmt.Sprintf("PaymentSecret:\t%x\n", f.PaymentSecret) +
+		fmt.Sprintf("TotalAmount:\t%d\n", f.TotalAmount) +
+		fmt.Sprintf("AMP:\t\t%v\n", f.AMP != nil) +
+		fmt.Sprintf("--- End FinalHopPayload ---\n")
+}
@@ -0,0 +1,66 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestFinalHopPayloadEncodeDecode(t *testing.T) {
+	payload := &FinalHopPayload{
+		PaymentSecret: [32]byte{1, 2, 3},
+		TotalAmount:   MilliSatoshi(123456000),
+	}
+
+	var b bytes.Buffer
+	if err := payload.Encode(&b); err != nil {
+		t.Fatalf("unable to encode payload: %v", err)
+	}
+
+	newPayload, err := ParseFinalHopPayload(b.Bytes())
+	if err != nil {
+		t.Fatalf("unable to parse payload: %v", err)
+	}
+	if !reflect.DeepEqual(payload, newPayload) {
+		t.Fatalf("payload doesn't match: %v vs %v", payload, newPayload)
+	}
+
+	// Trailing bytes, or a truncated payload, should be rejected.
+	if _, err := ParseFinalHopPayload(append(b.Bytes(), 0)); err == nil {
+		t.Fatalf("payload with trailing bytes accepted")
+	}
+	if _, err := ParseFinalHopPayload(b.Bytes()[:10]); err == nil {
+		t.Fatalf("truncated payload accepted")
+	}
+}
+
+func TestFinalHopPayloadAMPRecord(t *testing.T) {
+	payload := &FinalHopPayload{
+		PaymentSecret: [32]byte{1, 2, 3},
+		TotalAmount:   MilliSatoshi(123456000),
+		AMP: &AMPRecord{
+			InvoiceHash: [20]byte{4},
+			SetID:       [32]byte{5},
+			Share:       [32]byte{6},
+			ChildIndex:  7,
+		},
+	}
+
+	var b bytes.Buffer
+	if err := payload.Encode(&b); err != nil {
+		t.Fatalf("unable to encode payload: %v", err)
+	}
+
+	newPayload, err := ParseFinalHopPayload(b.Bytes())
+	if err != nil {
+		t.Fatalf("unable to parse payload: %v", err)
+	}
+	if !reflect.DeepEqual(payload, newPayload) {
+		t.Fatalf("payload doesn't match: %v vs %v", payload, newPayload)
+	}
+
+	// A truncated AMP record should be rejected.
+	if _, err := ParseFinalHopPayload(b.Bytes()[:b.Len()-1]); err == nil {
+		t.Fatalf("truncated amp record accepted")
+	}
+}
@@ -7,7 +7,7 @@ import (
 
 // HTLCAddAccept ...
 type HTLCAddAccept struct {
-	ChannelID uint64
+	ChannelID ShortChannelID
 	HTLCKey   HTLCKey
 }
 
@@ -65,7 +65,7 @@ func (c *HTLCAddAccept) Validate() error {
 
 func (c *HTLCAddAccept) String() string {
 	return fmt.Sprintf("\n--- Begin HTLCAddAccept ---\n") +
-		fmt.Sprintf("ChannelID:\t\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
 		fmt.Sprintf("HTLCKey:\t\t%d\n", c.HTLCKey) +
 		fmt.Sprintf("--- End HTLCAddAccept ---\n")
 }
@@ -6,7 +6,7 @@ import (
 
 var (
 	htlcAddAccept = &HTLCAddAccept{
-		ChannelID: uint64(12345678),
+		ChannelID: NewShortChanIDFromInt(12345678),
 		HTLCKey:   HTLCKey(12345),
 	}
 	htlcAddAcceptSerializedString  = "0000000000bc614e0000000000003039"
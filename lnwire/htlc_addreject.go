@@ -7,8 +7,13 @@ import (
 
 // HTLCAddReject ...
 type HTLCAddReject struct {
-	ChannelID uint64
+	ChannelID ShortChannelID
 	HTLCKey   HTLCKey
+
+	// Reason is the encrypted failure packet of the HTLC, which only the
+	// sender of the payment is able to read. Each hop the packet passes
+	// through on its way back to the sender adds a layer of encryption.
+	Reason []byte
 }
 
 // Decode ...
@@ -18,9 +23,11 @@ func (c *HTLCAddReject) Decode(r io.Reader, pver uint32) error {
 	// NextResponderCommitmentRevocationHash(20)
 	// ResponderRevocationPreimage(20)
 	// ResponderCommitSig(2+73max)
+	// Reason(2+reasonsize)
 	err := readElements(r,
 		&c.ChannelID,
 		&c.HTLCKey,
+		&c.Reason,
 	)
 	if err != nil {
 		return err
@@ -40,6 +47,7 @@ func (c *HTLCAddReject) Encode(w io.Writer, pver uint32) error {
 	err := writeElements(w,
 		c.ChannelID,
 		c.HTLCKey,
+		c.Reason,
 	)
 
 	if err != nil {
@@ -56,8 +64,9 @@ func (c *HTLCAddReject) Command() uint32 {
 
 // MaxPayloadLength ...
 func (c *HTLCAddReject) MaxPayloadLength(uint32) uint32 {
-	// 16 base size
-	return 16
+	// 16 base size, plus the failure packet, which shouldn't be bigger
+	// than 1K
+	return 16 + 2 + 1024
 }
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
@@ -68,7 +77,8 @@ func (c *HTLCAddReject) Validate() error {
 
 func (c *HTLCAddReject) String() string {
 	return fmt.Sprintf("\n--- Begin HTLCAddReject ---\n") +
-		fmt.Sprintf("ChannelID:\t\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
 		fmt.Sprintf("HTLCKey:\t\t%d\n", c.HTLCKey) +
+		fmt.Sprintf("Reason:\t\t%x\n", c.Reason) +
 		fmt.Sprintf("--- End HTLCAddReject ---\n")
 }
@@ -6,11 +6,12 @@ import (
 
 var (
 	htlcAddReject = &HTLCAddReject{
-		ChannelID: uint64(12345678),
+		ChannelID: NewShortChanIDFromInt(12345678),
 		HTLCKey:   HTLCKey(12345),
+		Reason:    []byte{255, 0, 255, 0},
 	}
-	htlcAddRejectSerializedString  = "0000000000bc614e0000000000003039"
-	htlcAddRejectSerializedMessage = "0709110b000003fc000000100000000000bc614e0000000000003039"
+	htlcAddRejectSerializedString  = "0000000000bc614e00000000000030390004ff00ff00"
+	htlcAddRejectSerializedMessage = "0709110b000003fc000000160000000000bc614e00000000000030390004ff00ff00"
 )
 
 func TestHTLCAddRejectEncodeDecode(t *testing.T) {
@@ -3,6 +3,8 @@ package lnwire
 import (
 	"fmt"
 	"io"
+
+	"github.com/btcsuite/btcd/btcec"
 )
 
 // HTLCAddRequest ...
@@ -10,7 +12,7 @@ import (
 // clearing requests
 type HTLCAddRequest struct {
 	// We can use a different data type for this if necessary...
-	ChannelID uint64
+	ChannelID ShortChannelID
 
 	// ID of this request
 	HTLCKey HTLCKey
@@ -20,7 +22,7 @@ type HTLCAddRequest struct {
 
 	// Amount to pay in the hop
 	// Difference between hop and first item in blob is the fee to complete
-	Amount CreditsAmount
+	Amount MilliSatoshi
 
 	// RefundContext is for payment cancellation
 	// TODO (j): not currently in use, add later
@@ -30,12 +32,21 @@ type HTLCAddRequest struct {
 	// first 4 bits is n, second for is m, in n-of-m "multisig"
 	ContractType uint8
 
+	// HashType is the hash function the redemption hashes commit to
+	// their preimages with.
+	HashType HTLCHashType
+
 	// Redemption Hashes
 	RedemptionHashes []*[20]byte
 
 	// Data to parse&pass on to the next node
 	// Eventually, we need to make this into a group of 2 nested structs?
 	Blob []byte
+
+	// EphemeralKey is the session point the receiving hop derives the
+	// secret it shares with the sender from, so that should it fail the
+	// HTLC, the failure can be encrypted for the sender alone.
+	EphemeralKey *btcec.PublicKey
 }
 
 // Decode ...
@@ -43,18 +54,22 @@ func (c *HTLCAddRequest) Decode(r io.Reader, pver uint32) error {
 	// ChannelID(8)
 	// HTLCKey(8)
 	// Expiry(4)
-	// Amount(4)
+	// Amount(8)
 	// ContractType(1)
+	// HashType(1)
 	// RedemptionHashes (numOfHashes * 20 + numOfHashes)
 	// Blob(2+blobsize)
+	// EphemeralKey(33)
 	err := readElements(r,
 		&c.ChannelID,
 		&c.HTLCKey,
 		&c.Expiry,
 		&c.Amount,
 		&c.ContractType,
+		&c.HashType,
 		&c.RedemptionHashes,
 		&c.Blob,
+		&c.EphemeralKey,
 	)
 	if err != nil {
 		return err
@@ -77,8 +92,10 @@ func (c *HTLCAddRequest) Encode(w io.Writer, pver uint32) error {
 		c.Expiry,
 		c.Amount,
 		c.ContractType,
+		c.HashType,
 		c.RedemptionHashes,
 		c.Blob,
+		c.EphemeralKey,
 	)
 	if err != nil {
 		return err
@@ -101,10 +118,14 @@ func (c *HTLCAddRequest) MaxPayloadLength(uint32) uint32 {
 
 // Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
 func (c *HTLCAddRequest) Validate() error {
-	if c.Amount < 0 {
-		// While fees can be negative, it's too confusing to allow
-		// negative payments. Maybe for some wallets, but not this one!
-		return fmt.Errorf("Amount paid cannot be negative.")
+	if c.Amount == 0 {
+		return fmt.Errorf("Amount paid must be greater than zero.")
+	}
+	if c.EphemeralKey == nil {
+		return fmt.Errorf("HTLC must carry an ephemeral key")
+	}
+	if !c.HashType.IsKnown() {
+		return fmt.Errorf("unknown HTLC hash type: %v", c.HashType)
 	}
 	// We're good!
 	return nil
@@ -118,13 +139,15 @@ func (c *HTLCAddRequest) String() string {
 	}
 
 	return fmt.Sprintf("\n--- Begin HTLCAddRequest ---\n") +
-		fmt.Sprintf("ChannelID:\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t%v\n", c.ChannelID) +
 		fmt.Sprintf("HTLCKey:\t%d\n", c.HTLCKey) +
 		fmt.Sprintf("Expiry:\t\t%d\n", c.Expiry) +
 		fmt.Sprintf("Amount\t\t%d\n", c.Amount) +
 		fmt.Sprintf("ContractType:\t%d (%b)\n", c.ContractType, c.ContractType) +
+		fmt.Sprintf("HashType:\t%v\n", c.HashType) +
 		fmt.Sprintf("RedemptionHashes:") +
 		redemptionHashes +
 		fmt.Sprintf("Blob:\t\t\t\t%x\n", c.Blob) +
+		fmt.Sprintf("EphemeralKey:\t%x\n", c.EphemeralKey.SerializeCompressed()) +
 		fmt.Sprintf("--- End HTLCAddRequest ---\n")
 }
@@ -12,17 +12,19 @@ var (
 	redemptionHashes      = append(emptyRedemptionHashes, &redemptionHash)
 
 	htlcAddRequest = &HTLCAddRequest{
-		ChannelID:        uint64(12345678),
+		ChannelID:        NewShortChanIDFromInt(12345678),
 		HTLCKey:          HTLCKey(12345),
 		Expiry:           uint32(144),
-		Amount:           CreditsAmount(123456000),
+		Amount:           MilliSatoshi(123456000),
 		ContractType:     uint8(17),
+		HashType:         HTLCHashTypeHash160,
 		RedemptionHashes: redemptionHashes,
 
-		Blob: []byte{255, 0, 255, 0, 255, 0, 255, 0},
+		Blob:         []byte{255, 0, 255, 0, 255, 0, 255, 0},
+		EphemeralKey: pubKey,
 	}
-	htlcAddRequestSerializedString  = "0000000000bc614e000000000000303900000090075bca001100015b315ebabb0d8c0d94281caa2dfee69a1a00436e0008ff00ff00ff00ff00"
-	htlcAddRequestSerializedMessage = "0709110b000003e8000000390000000000bc614e000000000000303900000090075bca001100015b315ebabb0d8c0d94281caa2dfee69a1a00436e0008ff00ff00ff00ff00"
+	htlcAddRequestSerializedString  = "0000000000bc614e00000000000030390000009000000000075bca00110000015b315ebabb0d8c0d94281caa2dfee69a1a00436e0008ff00ff00ff00ff0002f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+	htlcAddRequestSerializedMessage = "0709110b000003e80000005f0000000000bc614e00000000000030390000009000000000075bca00110000015b315ebabb0d8c0d94281caa2dfee69a1a00436e0008ff00ff00ff00ff0002f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
 )
 
 func TestHTLCAddRequestEncodeDecode(t *testing.T) {
@@ -42,3 +44,16 @@ func TestHTLCAddRequestEncodeDecode(t *testing.T) {
 	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
 	MessageSerializeDeserializeTest(t, htlcAddRequest, htlcAddRequestSerializedMessage)
 }
+
+func TestHTLCAddRequestUnknownHashType(t *testing.T) {
+	req := *htlcAddRequest
+	req.HashType = HTLCHashType(1)
+	if err := req.Validate(); err == nil {
+		t.Fatalf("unknown hash type accepted")
+	}
+
+	req.HashType = HTLCHashTypeHash160
+	if err := req.Validate(); err != nil {
+		t.Fatalf("hash160 hash type rejected: %v", err)
+	}
+}
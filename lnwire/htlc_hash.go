@@ -0,0 +1,60 @@
+package lnwire
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+)
+
+// HTLCHashType denotes the hash function the redemption hashes of an HTLC
+// commit to their preimages with. It's set by the sender within the
+// HTLCAddRequest, so constructions other than the original may be introduced
+// later on. A receiver which doesn't support the hash type rejects the HTLC.
+type HTLCHashType uint8
+
+const (
+	// HTLCHashTypeHash160 is RIPEMD160(SHA256(preimage)), the original
+	// hash of the redemption hashes.
+	HTLCHashTypeHash160 HTLCHashType = iota
+)
+
+// IsKnown returns true if the hash type is one we understand.
+func (h HTLCHashType) IsKnown() bool {
+	switch h {
+	case HTLCHashTypeHash160:
+		return true
+	default:
+		return false
+	}
+}
+
+// HashSize returns the size of the hashes of the type, or zero if the type
+// is unknown.
+func (h HTLCHashType) HashSize() int {
+	switch h {
+	case HTLCHashTypeHash160:
+		return 20
+	default:
+		return 0
+	}
+}
+
+// Hash returns the hash of the preimage, or nil if the type is unknown.
+func (h HTLCHashType) Hash(preimage []byte) []byte {
+	switch h {
+	case HTLCHashTypeHash160:
+		return btcutil.Hash160(preimage)
+	default:
+		return nil
+	}
+}
+
+// String returns a human readable version of the hash type.
+func (h HTLCHashType) String() string {
+	switch h {
+	case HTLCHashTypeHash160:
+		return "hash160"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(h))
+	}
+}
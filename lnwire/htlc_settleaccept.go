@@ -10,7 +10,7 @@ import (
 // clearing requests
 type HTLCSettleAccept struct {
 	// We can use a different data type for this if necessary...
-	ChannelID uint64
+	ChannelID ShortChannelID
 
 	// ID of this request
 	HTLCKey HTLCKey
@@ -69,7 +69,7 @@ func (c *HTLCSettleAccept) Validate() error {
 
 func (c *HTLCSettleAccept) String() string {
 	return fmt.Sprintf("\n--- Begin HTLCSettleAccept ---\n") +
-		fmt.Sprintf("ChannelID:\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t%v\n", c.ChannelID) +
 		fmt.Sprintf("HTLCKey:\t%d\n", c.HTLCKey) +
 		fmt.Sprintf("--- End HTLCSettleAccept ---\n")
 }
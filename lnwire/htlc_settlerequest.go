@@ -10,7 +10,7 @@ import (
 // clearing requests
 type HTLCSettleRequest struct {
 	// We can use a different data type for this if necessary...
-	ChannelID uint64
+	ChannelID ShortChannelID
 
 	// ID of this request
 	HTLCKey HTLCKey
@@ -86,7 +86,7 @@ func (c *HTLCSettleRequest) String() string {
 	}
 
 	return fmt.Sprintf("\n--- Begin HTLCSettleRequest ---\n") +
-		fmt.Sprintf("ChannelID:\t%d\n", c.ChannelID) +
+		fmt.Sprintf("ChannelID:\t%v\n", c.ChannelID) +
 		fmt.Sprintf("HTLCKey:\t%d\n", c.HTLCKey) +
 		fmt.Sprintf("RedemptionHashes:") +
 		redemptionProofs +
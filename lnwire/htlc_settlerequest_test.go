@@ -11,7 +11,7 @@ var (
 	redemptionProofs      = append(emptyRedemptionProofs, &redemptionHash)
 
 	htlcSettleRequest = &HTLCSettleRequest{
-		ChannelID:        uint64(12345678),
+		ChannelID:        NewShortChanIDFromInt(12345678),
 		HTLCKey:          HTLCKey(12345),
 		RedemptionProofs: redemptionProofs,
 	}
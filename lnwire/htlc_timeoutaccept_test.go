@@ -6,7 +6,7 @@ import (
 
 var (
 	htlcTimeoutAccept = &HTLCTimeoutAccept{
-		ChannelID: uint64(12345678),
+		ChannelID: NewShortChanIDFromInt(12345678),
 		HTLCKey:   HTLCKey(12345),
 	}
 	htlcTimeoutAcceptSerializedString  = "0000000000bc614e0000000000003039"
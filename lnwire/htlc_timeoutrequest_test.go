@@ -6,7 +6,7 @@ import (
 
 var (
 	htlcTimeoutRequest = &HTLCTimeoutRequest{
-		ChannelID: uint64(12345678),
+		ChannelID: NewShortChanIDFromInt(12345678),
 		HTLCKey:   HTLCKey(12345),
 	}
 	htlcTimeoutRequestSerializedString  = "0000000000bc614e0000000000003039"
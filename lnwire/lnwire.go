@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math/big"
 
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/wire"
@@ -15,6 +16,25 @@ import (
 // MaxSliceLength ...
 var MaxSliceLength = 65535
 
+// MaxPkScriptSize is the largest PkScript which may be encoded on the wire.
+// 34 bytes is large enough to hold a P2WSH output script, the largest of the
+// standard script templates.
+const MaxPkScriptSize = 34
+
+// errInvalidPkScript is returned when a PkScript isn't one of the standard
+// script templates we pay to.
+var errInvalidPkScript = fmt.Errorf("PkScript only allows P2PKH, P2SH, " +
+	"P2WPKH or P2WSH")
+
+// SignatureSize is the size of a signature on the wire. Rather than using the
+// variable length DER encoding, signatures are encoded as the fixed size
+// 32-byte R value followed by the 32-byte S value.
+const SignatureSize = 64
+
+// halfCurveOrder is half the order of the secp256k1 curve. The S value of a
+// canonical signature must not exceed it.
+var halfCurveOrder = new(big.Int).Rsh(btcec.S256().N, 1)
+
 // PkScript is the actual PkScript, not redeemScript
 type PkScript []byte
 
@@ -24,22 +44,6 @@ type HTLCKey uint64
 // CommitHeight ...
 type CommitHeight uint64
 
-// CreditsAmount ...
-// Subsatoshi amount (Micro-Satoshi, 1/1000th)
-// Should be a signed int to account for negative fees
-//
-// "In any science-fiction movie, anywhere in the galaxy, currency is referred
-// to as 'credits.'"
-// 	--Sam Humphries. Ebert, Roger (1999). Ebert's bigger little movie
-// 	glossary. Andrews McMeel. p. 172.
-//
-// https:// en.wikipedia.org/wiki/List_of_fictional_currencies
-// https:// en.wikipedia.org/wiki/Fictional_currency#Trends_in_the_use_of_fictional_currencies
-// http:// tvtropes.org/pmwiki/pmwiki.php/Main/WeWillSpendCreditsInTheFuture
-type CreditsAmount int32 // Credits (XCB, accountants should use XCB :^)
-// US Display format: 1 BTC = 100,000,000'000 XCB
-// Or in BTC = 1.00000000'000
-
 // Writes the big endian representation of element
 // Unified function to call when writing different types
 // Pre-allocate a byte-array of the correct size for cargo-cult security
@@ -63,12 +67,6 @@ func writeElement(w io.Writer, element interface{}) error {
 			return err
 		}
 		return nil
-	case CreditsAmount:
-		err = binary.Write(w, binary.BigEndian, int32(e))
-		if err != nil {
-			return err
-		}
-		return nil
 	case uint32:
 		var b [4]byte
 		binary.BigEndian.PutUint32(b[:], uint32(e))
@@ -85,11 +83,69 @@ func writeElement(w io.Writer, element interface{}) error {
 			return err
 		}
 		return nil
+	case MilliSatoshi:
+		err = writeElement(w, uint64(e))
+		if err != nil {
+			return err
+		}
+		return nil
 	case HTLCKey:
 		err = writeElement(w, uint64(e))
 		if err != nil {
 			return err
 		}
+	case ChannelType:
+		err = writeElement(w, uint8(e))
+		if err != nil {
+			return err
+		}
+		return nil
+	case HTLCHashType:
+		err = writeElement(w, uint8(e))
+		if err != nil {
+			return err
+		}
+		return nil
+	case ShortChannelID:
+		err = writeElement(w, e.ToUint64())
+		if err != nil {
+			return err
+		}
+		return nil
+	case []ShortChannelID:
+		numItems := len(e)
+		if numItems > 65535 {
+			return fmt.Errorf("Too many []ShortChannelIDs")
+		}
+		// Write the size
+		err = writeElement(w, uint16(numItems))
+		if err != nil {
+			return err
+		}
+		// Write the data
+		for i := 0; i < numItems; i++ {
+			err = writeElement(w, e[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case bool:
+		var b uint8
+		if e {
+			b = 1
+		}
+		err = writeElement(w, b)
+		if err != nil {
+			return err
+		}
+		return nil
+	case ChannelID:
+		_, err = w.Write(e[:])
+		if err != nil {
+			return err
+		}
+		return nil
 	case btcutil.Amount:
 		err = binary.Write(w, binary.BigEndian, int64(e))
 		if err != nil {
@@ -145,18 +201,11 @@ func writeElement(w io.Writer, element interface{}) error {
 		}
 		return nil
 	case *btcec.Signature:
-		sig := e.Serialize()
-		sigLength := len(sig)
-		if sigLength > 73 {
-			return fmt.Errorf("Signature too long!")
-		}
-		// Write the size
-		err = writeElement(w, uint8(sigLength))
-		if err != nil {
+		var b [SignatureSize]byte
+		if err := serializeSigToWire(&b, e); err != nil {
 			return err
 		}
-		// Write the data
-		_, err = w.Write(sig)
+		_, err = w.Write(b[:])
 		if err != nil {
 			return err
 		}
@@ -170,6 +219,9 @@ func writeElement(w io.Writer, element interface{}) error {
 	case []*[20]byte:
 		// Get size of slice and dump in slice
 		sliceSize := len(e)
+		if sliceSize > 65535 {
+			return fmt.Errorf("Too many [20]byte elements")
+		}
 		err = writeElement(w, uint16(sliceSize))
 		if err != nil {
 			return err
@@ -193,6 +245,12 @@ func writeElement(w io.Writer, element interface{}) error {
 			return err
 		}
 		return nil
+	case [32]byte:
+		_, err = w.Write(e[:])
+		if err != nil {
+			return err
+		}
+		return nil
 	case wire.BitcoinNet:
 		var b [4]byte
 		binary.BigEndian.PutUint32(b[:], uint32(e))
@@ -219,8 +277,8 @@ func writeElement(w io.Writer, element interface{}) error {
 		return nil
 	case PkScript:
 		scriptLength := len(e)
-		// Make sure it's P2PKH or P2SH size or less
-		if scriptLength > 25 {
+		// Make sure it's no larger than the largest standard script
+		if scriptLength > MaxPkScriptSize {
 			return fmt.Errorf("PkScript too long!")
 		}
 		// Write the size (1-byte)
@@ -307,7 +365,7 @@ func readElement(r io.Reader, element interface{}) error {
 	switch e := element.(type) {
 	case *uint8:
 		var b [1]uint8
-		_, err = r.Read(b[:])
+		_, err = io.ReadFull(r, b[:])
 		if err != nil {
 			return err
 		}
@@ -321,29 +379,29 @@ func readElement(r io.Reader, element interface{}) error {
 		}
 		*e = binary.BigEndian.Uint16(b[:])
 		return nil
-	case *CreditsAmount:
+	case *uint32:
 		var b [4]byte
 		_, err = io.ReadFull(r, b[:])
 		if err != nil {
 			return err
 		}
-		*e = CreditsAmount(int32(binary.BigEndian.Uint32(b[:])))
+		*e = binary.BigEndian.Uint32(b[:])
 		return nil
-	case *uint32:
-		var b [4]byte
+	case *uint64:
+		var b [8]byte
 		_, err = io.ReadFull(r, b[:])
 		if err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint32(b[:])
+		*e = binary.BigEndian.Uint64(b[:])
 		return nil
-	case *uint64:
+	case *MilliSatoshi:
 		var b [8]byte
 		_, err = io.ReadFull(r, b[:])
 		if err != nil {
 			return err
 		}
-		*e = binary.BigEndian.Uint64(b[:])
+		*e = MilliSatoshi(binary.BigEndian.Uint64(b[:]))
 		return nil
 	case *HTLCKey:
 		var b [8]byte
@@ -353,6 +411,66 @@ func readElement(r io.Reader, element interface{}) error {
 		}
 		*e = HTLCKey(binary.BigEndian.Uint64(b[:]))
 		return nil
+	case *ChannelType:
+		var b [1]byte
+		_, err = io.ReadFull(r, b[:])
+		if err != nil {
+			return err
+		}
+		*e = ChannelType(b[0])
+		return nil
+	case *HTLCHashType:
+		var b [1]byte
+		_, err = io.ReadFull(r, b[:])
+		if err != nil {
+			return err
+		}
+		*e = HTLCHashType(b[0])
+		return nil
+	case *ShortChannelID:
+		var b [8]byte
+		_, err = io.ReadFull(r, b[:])
+		if err != nil {
+			return err
+		}
+		*e = NewShortChanIDFromInt(binary.BigEndian.Uint64(b[:]))
+		return nil
+	case *[]ShortChannelID:
+		var numItems uint16
+		err = readElement(r, &numItems)
+		if err != nil {
+			return err
+		}
+
+		// Read the number of items
+		var items []ShortChannelID
+		for i := uint16(0); i < numItems; i++ {
+			var item ShortChannelID
+			err = readElement(r, &item)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		*e = items
+		return nil
+	case *bool:
+		var b uint8
+		err = readElement(r, &b)
+		if err != nil {
+			return err
+		}
+		if b > 1 {
+			return fmt.Errorf("invalid bool encoding: %d", b)
+		}
+		*e = b == 1
+		return nil
+	case *ChannelID:
+		_, err = io.ReadFull(r, e[:])
+		if err != nil {
+			return err
+		}
+		return nil
 	case *btcutil.Amount:
 		var b [8]byte
 		_, err = io.ReadFull(r, b[:])
@@ -426,30 +544,16 @@ func readElement(r io.Reader, element interface{}) error {
 		*e = *&sigs
 		return nil
 	case **btcec.Signature:
-		var sigLength uint8
-		err = readElement(r, &sigLength)
+		var b [SignatureSize]byte
+		_, err = io.ReadFull(r, b[:])
 		if err != nil {
 			return err
 		}
-
-		if sigLength > 73 {
-			return fmt.Errorf("Signature too long!")
-		}
-
-		// Read the sig length
-		l := io.LimitReader(r, int64(sigLength))
-		sig, err := ioutil.ReadAll(l)
+		sig, err := deserializeSigFromWire(&b)
 		if err != nil {
 			return err
 		}
-		if len(sig) != int(sigLength) {
-			return fmt.Errorf("EOF: Signature length mismatch")
-		}
-		btcecSig, err := btcec.ParseSignature(sig, btcec.S256())
-		if err != nil {
-			return err
-		}
-		*e = &*btcecSig
+		*e = sig
 		return nil
 	case *[]*[20]byte:
 		// How many to read
@@ -476,6 +580,12 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 		return nil
+	case *[32]byte:
+		_, err = io.ReadFull(r, e[:])
+		if err != nil {
+			return err
+		}
+		return nil
 	case *wire.BitcoinNet:
 		var b [4]byte
 		_, err := io.ReadFull(r, b[:])
@@ -516,7 +626,7 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 
-		if scriptLength > 25 {
+		if scriptLength > MaxPkScriptSize {
 			return fmt.Errorf("PkScript too long!")
 		}
 
@@ -527,7 +637,7 @@ func readElement(r io.Reader, element interface{}) error {
 			return err
 		}
 		if len(*e) != int(scriptLength) {
-			return fmt.Errorf("EOF: Signature length mismatch")
+			return fmt.Errorf("EOF: PkScript length mismatch")
 		}
 		return nil
 	case *string:
@@ -540,13 +650,13 @@ func readElement(r io.Reader, element interface{}) error {
 		// Read the string for the length
 		l := io.LimitReader(r, int64(strlen))
 		b, err := ioutil.ReadAll(l)
+		if err != nil {
+			return err
+		}
 		if len(b) != int(strlen) {
 			return fmt.Errorf("EOF: String length mismatch")
 		}
 		*e = string(b)
-		if err != nil {
-			return err
-		}
 		return nil
 	case *[]*wire.TxIn:
 		// Read the size (1-byte number of txins)
@@ -609,7 +719,49 @@ func readElements(r io.Reader, elements ...interface{}) error {
 	return nil
 }
 
-// ValidatePkScript validates whether a PkScript byte array is P2SH or P2PKH
+// serializeSigToWire encodes the signature into its fixed size wire format:
+// the R and S values each left-padded to 32 bytes. As the remote node would
+// reject it, a signature with a high S value can't be encoded.
+func serializeSigToWire(b *[SignatureSize]byte, sig *btcec.Signature) error {
+	rBytes := sig.R.Bytes()
+	sBytes := sig.S.Bytes()
+	if len(rBytes) > 32 || len(sBytes) > 32 {
+		return fmt.Errorf("Signature R or S value too large!")
+	}
+	if sig.S.Cmp(halfCurveOrder) > 0 {
+		return fmt.Errorf("Signature S value must be low-S")
+	}
+
+	copy(b[32-len(rBytes):32], rBytes)
+	copy(b[64-len(sBytes):], sBytes)
+	return nil
+}
+
+// deserializeSigFromWire decodes a signature from its fixed size wire format.
+// Only canonical signatures are accepted: both R and S must be non-zero and
+// less than the order of the curve, and S must be in its lower half. The
+// latter rules out malleated signatures, which the network won't relay within
+// transactions.
+func deserializeSigFromWire(b *[SignatureSize]byte) (*btcec.Signature, error) {
+	curveOrder := btcec.S256().N
+
+	r := new(big.Int).SetBytes(b[:32])
+	if r.Sign() == 0 || r.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("Signature R value out of range")
+	}
+	s := new(big.Int).SetBytes(b[32:])
+	if s.Sign() == 0 || s.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("Signature S value out of range")
+	}
+	if s.Cmp(halfCurveOrder) > 0 {
+		return nil, fmt.Errorf("Signature S value must be low-S")
+	}
+
+	return &btcec.Signature{R: r, S: s}, nil
+}
+
+// ValidatePkScript validates whether a PkScript byte array is P2PKH, P2SH,
+// P2WPKH or P2WSH
 func ValidatePkScript(pkScript PkScript) error {
 	if &pkScript == nil {
 		return fmt.Errorf("PkScript should not be empty!")
@@ -621,7 +773,7 @@ func ValidatePkScript(pkScript PkScript) error {
 			// Ends with OP_EQUALVERIFY OP_CHECKSIG
 			!bytes.Equal(pkScript[23:25], []byte{136, 172}) {
 			// If it's not correct, return error
-			return fmt.Errorf("PkScript only allows P2SH or P2PKH")
+			return errInvalidPkScript
 		}
 	} else if len(pkScript) == 23 {
 		// P2SH
@@ -630,11 +782,23 @@ func ValidatePkScript(pkScript PkScript) error {
 			// Ends with OP_EQUAL
 			!bytes.Equal(pkScript[22:23], []byte{135}) {
 			// If it's not correct, return error
-			return fmt.Errorf("PkScript only allows P2SH or P2PKH")
+			return errInvalidPkScript
+		}
+	} else if len(pkScript) == 22 {
+		// P2WPKH
+		// Begins with OP_0 PUSHDATA(20)
+		if !bytes.Equal(pkScript[0:2], []byte{0, 20}) {
+			return errInvalidPkScript
+		}
+	} else if len(pkScript) == 34 {
+		// P2WSH
+		// Begins with OP_0 PUSHDATA(32)
+		if !bytes.Equal(pkScript[0:2], []byte{0, 32}) {
+			return errInvalidPkScript
 		}
 	} else {
-		// Length not 23 or 25
-		return fmt.Errorf("PkScript only allows P2SH or P2PKH")
+		// Length not 22, 23, 25 or 34
+		return errInvalidPkScript
 	}
 
 	return nil
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"io/ioutil"
+	"math/big"
 	"reflect"
 	"testing"
 
@@ -153,3 +154,132 @@ func MessageSerializeDeserializeTest(t *testing.T, message Message, expectedStri
 		t.Logf(newMsg.String())
 	}
 }
+
+func TestSignatureWireEncoding(t *testing.T) {
+	// Signatures should always be encoded using exactly 64 bytes.
+	var b bytes.Buffer
+	if err := writeElement(&b, commitSig); err != nil {
+		t.Fatalf("unable to write sig: %v", err)
+	}
+	if b.Len() != SignatureSize {
+		t.Fatalf("sig encoded to %d bytes, expected %d", b.Len(),
+			SignatureSize)
+	}
+
+	var sig *btcec.Signature
+	if err := readElement(bytes.NewReader(b.Bytes()), &sig); err != nil {
+		t.Fatalf("unable to read sig: %v", err)
+	}
+	if !reflect.DeepEqual(sig, commitSig) {
+		t.Fatalf("sig doesn't match after decoding")
+	}
+
+	// An S value equal to the order of the curve isn't canonical, and
+	// should be rejected.
+	var rawSig [SignatureSize]byte
+	copy(rawSig[:32], b.Bytes()[:32])
+	nBytes := btcec.S256().N.Bytes()
+	copy(rawSig[64-len(nBytes):], nBytes)
+	if err := readElement(bytes.NewReader(rawSig[:]), &sig); err == nil {
+		t.Fatalf("non-canonical sig should be rejected")
+	}
+
+	// As should the high-S form of a valid signature, along with any
+	// attempt to encode one.
+	highS := new(big.Int).Sub(btcec.S256().N, commitSig.S)
+	var highSSig [SignatureSize]byte
+	copy(highSSig[:32], b.Bytes()[:32])
+	highSBytes := highS.Bytes()
+	copy(highSSig[64-len(highSBytes):], highSBytes)
+	if err := readElement(bytes.NewReader(highSSig[:]), &sig); err == nil {
+		t.Fatalf("high-S sig should be rejected")
+	}
+	err := writeElement(new(bytes.Buffer), &btcec.Signature{
+		R: commitSig.R,
+		S: highS,
+	})
+	if err == nil {
+		t.Fatalf("high-S sig shouldn't be encoded")
+	}
+
+	// Likewise for a zero R value.
+	var zeroSig [SignatureSize]byte
+	copy(zeroSig[32:], b.Bytes()[32:])
+	if err := readElement(bytes.NewReader(zeroSig[:]), &sig); err == nil {
+		t.Fatalf("sig with zero R value should be rejected")
+	}
+}
+
+// TestValidatePkScript asserts only the standard script templates we pay to
+// are accepted, up to MaxPkScriptSize.
+func TestValidatePkScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		valid  bool
+	}{
+		{
+			name:   "p2pkh",
+			script: "76a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd488ac",
+			valid:  true,
+		},
+		{
+			name:   "p2sh",
+			script: "a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd487",
+			valid:  true,
+		},
+		{
+			name:   "p2wpkh",
+			script: "0014e8048c0fb75bdecc91ebfb99c174f4ece29ffbd4",
+			valid:  true,
+		},
+		{
+			name: "p2wsh",
+			script: "0020e8048c0fb75bdecc91ebfb99c174f4ece29ffbd4" +
+				"238ee44bb5c8c1314dd03974",
+			valid: true,
+		},
+		{
+			// A witness v1 program of 32 bytes, the length of P2WSH.
+			name: "p2tr",
+			script: "5120e8048c0fb75bdecc91ebfb99c174f4ece29ffbd4" +
+				"238ee44bb5c8c1314dd03974",
+			valid: false,
+		},
+		{
+			// A 22 byte script pushing 20 bytes, but not witness v0.
+			name:   "p2wpkh wrong version",
+			script: "5114e8048c0fb75bdecc91ebfb99c174f4ece29ffbd4",
+			valid:  false,
+		},
+		{
+			name:   "p2pkh wrong suffix",
+			script: "76a914e8048c0fb75bdecc91ebfb99c174f4ece29ffbd48787",
+			valid:  false,
+		},
+		{
+			name:   "empty",
+			script: "",
+			valid:  false,
+		},
+	}
+
+	for _, test := range tests {
+		script, err := hex.DecodeString(test.script)
+		if err != nil {
+			t.Fatalf("%v: unable to decode script: %v", test.name, err)
+		}
+		if len(script) > MaxPkScriptSize {
+			t.Fatalf("%v: script exceeds MaxPkScriptSize", test.name)
+		}
+
+		err = ValidatePkScript(script)
+		if test.valid && err != nil {
+			t.Fatalf("%v: expected script to be valid, instead %v",
+				test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%v: expected script to be invalid", test.name)
+		}
+	}
+}
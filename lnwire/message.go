@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/btcsuite/btcd/wire"
 )
@@ -27,8 +28,12 @@ const (
 // 4-byte network + 4-byte message id + payload-length 4-byte
 const MessageHeaderSize = 12
 
-// 32MB
-const MaxMessagePayload = 1024 * 1024 * 32
+// MaxMessageSize is the maximum size of any message on the wire, including
+// the header.
+const MaxMessageSize = 65535
+
+// MaxMessagePayload is the maximum payload of any message on the wire.
+const MaxMessagePayload = MaxMessageSize - MessageHeaderSize
 
 // constants ...
 const (
@@ -38,6 +43,8 @@ const (
 	CmdFundingResponse     = uint32(210)
 	CmdFundingSignAccept   = uint32(220)
 	CmdFundingSignComplete = uint32(230)
+	CmdFundingLocked       = uint32(240)
+	CmdFundingCancel       = uint32(250)
 
 	// Close channel
 
@@ -67,9 +74,46 @@ const (
 	CmdCommitSignature  = uint32(2000)
 	CmdCommitRevocation = uint32(2010)
 
+	// Quiescence
+
+	CmdStfu = uint32(2100)
+
+	// Splicing
+
+	CmdSpliceInit = uint32(2200)
+	CmdSpliceAck  = uint32(2210)
+
+	// Dynamic commitments
+
+	CmdDynPropose = uint32(2300)
+	CmdDynAck     = uint32(2310)
+	CmdDynReject  = uint32(2320)
+
 	// Error
 
 	CmdErrorGeneric = uint32(4000)
+
+	// Routing gossip
+
+	CmdChannelAnnouncement    = uint32(5000)
+	CmdChannelUpdate          = uint32(5010)
+	CmdAnnouncementSignatures = uint32(5020)
+
+	// Gossip queries
+
+	CmdQueryChannelRange    = uint32(5100)
+	CmdReplyChannelRange    = uint32(5110)
+	CmdQueryShortChanIDs    = uint32(5120)
+	CmdReplyShortChanIDsEnd = uint32(5130)
+
+	// Peer storage
+
+	CmdPeerStorage          = uint32(6000)
+	CmdPeerStorageRetrieval = uint32(6010)
+
+	// Signed application data
+
+	CmdSignedData = uint32(7000)
 )
 
 // A Message has these functions:
@@ -82,47 +126,78 @@ type Message interface {
 	String() string
 }
 
+// messageRegistry maps each command ID to a constructor which returns a
+// fresh, empty instance of the corresponding message type. All decoding of
+// incoming messages is driven by this table, so adding a new message type only
+// requires a single call to RegisterMessage.
+var messageRegistry = map[uint32]func() Message{
+	CmdFundingRequest:      func() Message { return NewFundingRequest() },
+	CmdFundingResponse:     func() Message { return NewFundingResponse() },
+	CmdFundingSignAccept:   func() Message { return NewFundingSignAccept() },
+	CmdFundingSignComplete: func() Message { return NewFundingSignComplete() },
+	CmdFundingLocked:       func() Message { return NewFundingLocked() },
+	CmdFundingCancel:       func() Message { return NewFundingCancel() },
+	CmdCloseRequest:        func() Message { return NewCloseRequest() },
+	CmdCloseComplete:       func() Message { return NewCloseComplete() },
+	CmdHTLCAddRequest:      func() Message { return NewHTLCAddRequest() },
+	CmdHTLCAddAccept:       func() Message { return NewHTLCAddAccept() },
+	CmdHTLCAddReject:       func() Message { return NewHTLCAddReject() },
+	CmdHTLCSettleRequest:   func() Message { return NewHTLCSettleRequest() },
+	CmdHTLCSettleAccept:    func() Message { return NewHTLCSettleAccept() },
+	CmdHTLCTimeoutRequest:  func() Message { return NewHTLCTimeoutRequest() },
+	CmdHTLCTimeoutAccept:   func() Message { return NewHTLCTimeoutAccept() },
+	CmdCommitSignature:     func() Message { return NewCommitSignature() },
+	CmdCommitRevocation:    func() Message { return NewCommitRevocation() },
+	CmdStfu:                func() Message { return NewStfu() },
+	CmdSpliceInit:          func() Message { return NewSpliceInit() },
+	CmdSpliceAck:           func() Message { return NewSpliceAck() },
+	CmdDynPropose:          func() Message { return NewDynPropose() },
+	CmdDynAck:              func() Message { return NewDynAck() },
+	CmdDynReject:           func() Message { return NewDynReject() },
+	CmdErrorGeneric:        func() Message { return NewErrorGeneric() },
+
+	CmdChannelAnnouncement:    func() Message { return NewChannelAnnouncement() },
+	CmdChannelUpdate:          func() Message { return NewChannelUpdate() },
+	CmdAnnouncementSignatures: func() Message { return NewAnnouncementSignatures() },
+	CmdQueryChannelRange:      func() Message { return NewQueryChannelRange() },
+	CmdReplyChannelRange:      func() Message { return NewReplyChannelRange() },
+	CmdQueryShortChanIDs:      func() Message { return NewQueryShortChanIDs() },
+	CmdReplyShortChanIDsEnd:   func() Message { return NewReplyShortChanIDsEnd() },
+	CmdPeerStorage:            func() Message { return NewPeerStorage() },
+	CmdPeerStorageRetrieval:   func() Message { return NewPeerStorageRetrieval() },
+	CmdSignedData:             func() Message { return NewSignedData() },
+}
+
+// registryMtx guards concurrent access to the messageRegistry.
+var registryMtx sync.RWMutex
+
+// RegisterMessage adds a new message type to the registry, making it
+// available for decoding via ReadMessage. The passed constructor MUST return
+// a new instance on each call. An error is returned if a message type has
+// already been registered for the command.
+func RegisterMessage(command uint32, newMsg func() Message) error {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	if _, ok := messageRegistry[command]; ok {
+		return fmt.Errorf("command [%d] already registered", command)
+	}
+
+	messageRegistry[command] = newMsg
+	return nil
+}
+
+// makeEmptyMessage creates a new empty message of the type identified by the
+// passed command, consulting the message registry.
 func makeEmptyMessage(command uint32) (Message, error) {
-	var msg Message
-
-	switch command {
-	case CmdFundingRequest:
-		msg = &FundingRequest{}
-	case CmdFundingResponse:
-		msg = &FundingResponse{}
-	case CmdFundingSignAccept:
-		msg = &FundingSignAccept{}
-	case CmdFundingSignComplete:
-		msg = &FundingSignComplete{}
-	case CmdCloseRequest:
-		msg = &CloseRequest{}
-	case CmdCloseComplete:
-		msg = &CloseComplete{}
-	case CmdHTLCAddRequest:
-		msg = &HTLCAddRequest{}
-	case CmdHTLCAddAccept:
-		msg = &HTLCAddAccept{}
-	case CmdHTLCAddReject:
-		msg = &HTLCAddReject{}
-	case CmdHTLCSettleRequest:
-		msg = &HTLCSettleRequest{}
-	case CmdHTLCSettleAccept:
-		msg = &HTLCSettleAccept{}
-	case CmdHTLCTimeoutRequest:
-		msg = &HTLCTimeoutRequest{}
-	case CmdHTLCTimeoutAccept:
-		msg = &HTLCTimeoutAccept{}
-	case CmdCommitSignature:
-		msg = &CommitSignature{}
-	case CmdCommitRevocation:
-		msg = &CommitRevocation{}
-	case CmdErrorGeneric:
-		msg = &ErrorGeneric{}
-	default:
+	registryMtx.RLock()
+	newMsg, ok := messageRegistry[command]
+	registryMtx.RUnlock()
+	if !ok {
 		return nil, fmt.Errorf("unhandled command [%d]", command)
 	}
 
-	return msg, nil
+	return newMsg(), nil
 }
 
 type messageHeader struct {
@@ -230,6 +305,23 @@ func WriteMessage(w io.Writer, msg Message, pver uint32, btcnet wire.BitcoinNet)
 	return totalBytes, nil
 }
 
+// DecodeError is returned by ReadMessage when a message was read in full, yet
+// failed to decode, or its contents were invalid. Unlike failing to read the
+// message, this is always the fault of the sender.
+type DecodeError struct {
+	// Command is the command of the message.
+	Command uint32
+
+	// Err is the reason the message was rejected.
+	Err error
+}
+
+// Error returns the reason the message was rejected.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("invalid message of command %v: %v", e.Command,
+		e.Err)
+}
+
 // ReadMessage ...
 func ReadMessage(r io.Reader, pver uint32, btcnet wire.BitcoinNet) (int, Message, []byte, error) {
 	totalBytes := 0
@@ -277,13 +369,20 @@ func ReadMessage(r io.Reader, pver uint32, btcnet wire.BitcoinNet) (int, Message
 	pr := bytes.NewBuffer(payload)
 	err = msg.Decode(pr, pver)
 	if err != nil {
-		return totalBytes, nil, nil, err
+		return totalBytes, nil, nil, &DecodeError{command, err}
+	}
+
+	// A canonical encoding leaves no bytes unread, so reject any payload
+	// with trailing data.
+	if pr.Len() != 0 {
+		return totalBytes, nil, nil, &DecodeError{command, fmt.Errorf(
+			"payload has %v trailing bytes", pr.Len())}
 	}
 
 	// Validate the data
 	err = msg.Validate()
 	if err != nil {
-		return totalBytes, nil, nil, err
+		return totalBytes, nil, nil, &DecodeError{command, err}
 	}
 
 	// We're good!
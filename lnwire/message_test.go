@@ -0,0 +1,76 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestMessageRegistry(t *testing.T) {
+	// Every registered constructor should produce a message which reports
+	// the command it was registered under.
+	for command := range messageRegistry {
+		msg, err := makeEmptyMessage(command)
+		if err != nil {
+			t.Fatalf("unable to create message for command %d: %v",
+				command, err)
+		}
+		if msg.Command() != command {
+			t.Fatalf("message registered under command %d reports "+
+				"command %d", command, msg.Command())
+		}
+	}
+
+	// Unknown commands should be rejected.
+	if _, err := makeEmptyMessage(uint32(1)); err == nil {
+		t.Fatalf("unknown command should fail to create message")
+	}
+
+	// Registering a message for a command already in use should fail.
+	err := RegisterMessage(CmdErrorGeneric, func() Message {
+		return NewErrorGeneric()
+	})
+	if err == nil {
+		t.Fatalf("duplicate registration should fail")
+	}
+}
+
+func TestReadMessageTrailingBytes(t *testing.T) {
+	var b bytes.Buffer
+	if _, err := WriteMessage(&b, errorGeneric, 1, wire.TestNet3); err != nil {
+		t.Fatalf("unable to write message: %v", err)
+	}
+
+	// Append a single extra byte to the payload, adjusting the length
+	// within the header accordingly.
+	rawMsg := append(b.Bytes(), 0x00)
+	payloadLength := binary.BigEndian.Uint32(rawMsg[8:MessageHeaderSize])
+	binary.BigEndian.PutUint32(rawMsg[8:MessageHeaderSize], payloadLength+1)
+
+	_, _, _, err := ReadMessage(bytes.NewReader(rawMsg), 1, wire.TestNet3)
+	if err == nil {
+		t.Fatalf("message with trailing bytes should be rejected")
+	}
+
+	// The sender is at fault, so the message is rejected as invalid.
+	if _, ok := err.(*DecodeError); !ok {
+		t.Fatalf("expected DecodeError, got %T: %v", err, err)
+	}
+}
+
+func TestReadMessageTooLarge(t *testing.T) {
+	// Craft a header which claims a payload larger than the maximum
+	// message size.
+	var b bytes.Buffer
+	err := writeElements(&b, wire.TestNet3, CmdErrorGeneric,
+		uint32(MaxMessagePayload+1))
+	if err != nil {
+		t.Fatalf("unable to write header: %v", err)
+	}
+
+	if _, _, _, err := ReadMessage(&b, 1, wire.TestNet3); err == nil {
+		t.Fatalf("oversized message should be rejected")
+	}
+}
@@ -0,0 +1,33 @@
+package lnwire
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+)
+
+// mSatScale is the number of millisatoshis in a satoshi.
+const mSatScale = 1000
+
+// MilliSatoshi is an amount of bitcoin denominated in thousandths of a
+// satoshi. HTLCs, invoices, and fees are all accounted for in millisatoshis,
+// allowing for sub-satoshi fees and payments. Amounts are only converted to
+// satoshis at the chain boundary, when creating transaction outputs.
+type MilliSatoshi uint64
+
+// NewMSatFromSatoshis returns the MilliSatoshi amount equal to the passed
+// amount of satoshis.
+func NewMSatFromSatoshis(sat btcutil.Amount) MilliSatoshi {
+	return MilliSatoshi(uint64(sat) * mSatScale)
+}
+
+// ToSatoshis converts the amount to satoshis, rounding down any fraction of
+// a satoshi.
+func (m MilliSatoshi) ToSatoshis() btcutil.Amount {
+	return btcutil.Amount(uint64(m) / mSatScale)
+}
+
+// String returns a human readable representation of the amount.
+func (m MilliSatoshi) String() string {
+	return fmt.Sprintf("%v mSAT", uint64(m))
+}
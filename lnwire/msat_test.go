@@ -0,0 +1,34 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcutil"
+)
+
+func TestMilliSatoshiConversion(t *testing.T) {
+	tests := []struct {
+		mSat MilliSatoshi
+		sat  btcutil.Amount
+	}{
+		{0, 0},
+		{999, 0},
+		{1000, 1},
+		{1999, 1},
+		{123456789, 123456},
+	}
+
+	for _, test := range tests {
+		if sat := test.mSat.ToSatoshis(); sat != test.sat {
+			t.Fatalf("%v should floor to %v satoshis, got %v",
+				test.mSat, test.sat, sat)
+		}
+	}
+
+	if mSat := NewMSatFromSatoshis(123456); mSat != 123456000 {
+		t.Fatalf("123456 satoshis should be 123456000 mSAT, got %v", mSat)
+	}
+	if s := MilliSatoshi(1500).String(); s != "1500 mSAT" {
+		t.Fatalf("unexpected string: %v", s)
+	}
+}
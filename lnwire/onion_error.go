@@ -0,0 +1,141 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// FailCode is the reason an HTLC failed, as told to the sender of the
+// payment by the hop the failure arose at. The high bits of the code flag
+// the kind of failure it is.
+type FailCode uint16
+
+const (
+	// FlagPerm marks failures which retrying won't fix.
+	FlagPerm FailCode = 0x4000
+
+	// FlagNode marks failures of the node itself, rather than of one of
+	// its channels.
+	FlagNode FailCode = 0x2000
+
+	// FlagUpdate marks failures caused by the policy of the channel the
+	// HTLC was to be forwarded over.
+	FlagUpdate FailCode = 0x1000
+)
+
+// The failures a hop may hand back to the sender.
+const (
+	CodeTemporaryNodeFailure     = FlagNode | 2
+	CodePermanentNodeFailure     = FlagPerm | FlagNode | 2
+	CodePermanentChannelFailure  = FlagPerm | 8
+	CodeUnknownNextPeer          = FlagPerm | 10
+	CodeTemporaryChannelFailure  = FlagUpdate | 7
+	CodeAmountBelowMinimum       = FlagUpdate | 11
+	CodeFeeInsufficient          = FlagUpdate | 12
+	CodeIncorrectCltvExpiry      = FlagUpdate | 13
+	CodeExpiryTooSoon            = FlagUpdate | 14
+	CodeChannelDisabled          = FlagUpdate | 20
+	CodeIncorrectPaymentDetails  = FlagPerm | 15
+	CodeFinalIncorrectCltvExpiry = FailCode(18)
+	CodeFinalIncorrectHtlcAmount = FailCode(19)
+	CodeMPPTimeout               = FailCode(23)
+	CodeInvalidBlinding          = FlagPerm | 24
+)
+
+// failCodeNames are the human readable names of the failure codes.
+var failCodeNames = map[FailCode]string{
+	CodeTemporaryNodeFailure:     "TemporaryNodeFailure",
+	CodePermanentNodeFailure:     "PermanentNodeFailure",
+	CodePermanentChannelFailure:  "PermanentChannelFailure",
+	CodeUnknownNextPeer:          "UnknownNextPeer",
+	CodeTemporaryChannelFailure:  "TemporaryChannelFailure",
+	CodeAmountBelowMinimum:       "AmountBelowMinimum",
+	CodeFeeInsufficient:          "FeeInsufficient",
+	CodeIncorrectCltvExpiry:      "IncorrectCltvExpiry",
+	CodeExpiryTooSoon:            "ExpiryTooSoon",
+	CodeChannelDisabled:          "ChannelDisabled",
+	CodeIncorrectPaymentDetails:  "IncorrectPaymentDetails",
+	CodeFinalIncorrectCltvExpiry: "FinalIncorrectCltvExpiry",
+	CodeFinalIncorrectHtlcAmount: "FinalIncorrectHtlcAmount",
+	CodeMPPTimeout:               "MPPTimeout",
+	CodeInvalidBlinding:          "InvalidBlinding",
+}
+
+// String returns the name of the failure code.
+func (c FailCode) String() string {
+	if name, ok := failCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("UnknownFailCode(%#04x)", uint16(c))
+}
+
+// IsPermanent returns true if retrying won't fix the failure.
+func (c FailCode) IsPermanent() bool {
+	return c&FlagPerm != 0
+}
+
+// IsNodeFailure returns true if the failure is that of the node itself,
+// rather than of one of its channels.
+func (c FailCode) IsNodeFailure() bool {
+	return c&FlagNode != 0
+}
+
+// FailureMessage is the failure a hop hands back to the sender of a payment
+// when it fails the HTLC of the payment. It's only readable by the sender,
+// as it's encrypted to the sender by each hop it passes through on its way
+// back.
+type FailureMessage struct {
+	// Code is the reason the HTLC failed.
+	Code FailCode
+
+	// ChannelID is the channel the failure applies to, if any. It's set
+	// by the failing hop for failures of its outgoing channel.
+	ChannelID ShortChannelID
+}
+
+// Encode serializes the failure message into w.
+func (f *FailureMessage) Encode(w io.Writer) error {
+	// Code(2)
+	// ChannelID(8)
+	return writeElements(w,
+		uint16(f.Code),
+		f.ChannelID,
+	)
+}
+
+// Decode deserializes a failure message from r.
+func (f *FailureMessage) Decode(r io.Reader) error {
+	var code uint16
+	if err := readElements(r, &code, &f.ChannelID); err != nil {
+		return err
+	}
+	f.Code = FailCode(code)
+
+	return nil
+}
+
+// ParseFailureMessage parses a failure message decrypted by the sender of
+// the payment.
+func ParseFailureMessage(b []byte) (*FailureMessage, error) {
+	r := bytes.NewReader(b)
+
+	failure := &FailureMessage{}
+	if err := failure.Decode(r); err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%v trailing bytes after failure "+
+			"message", r.Len())
+	}
+
+	return failure, nil
+}
+
+// String returns a human readable version of the failure message.
+func (f *FailureMessage) String() string {
+	return fmt.Sprintf("\n--- Begin FailureMessage ---\n") +
+		fmt.Sprintf("Code:\t\t%v\n", f.Code) +
+		fmt.Sprintf("ChannelID:\t%v\n", f.ChannelID) +
+		fmt.Sprintf("--- End FailureMessage ---\n")
+}
@@ -0,0 +1,78 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxPeerStorageSize is the largest blob a peer may ask us to store for it,
+// or we'll ask a peer to store for us.
+const MaxPeerStorageSize = 8192
+
+// PeerStorage asks the receiver to store the blob for the sender, replacing
+// any blob it stored for the sender before. The blob is opaque to the
+// receiver, which hands it back within a PeerStorageRetrieval each time the
+// sender connects. Nodes use it to keep an encrypted backup of their
+// channels with their peers, so the backup survives the loss of their own
+// data.
+type PeerStorage struct {
+	// Blob is the data to store, encrypted by the sender.
+	Blob []byte
+}
+
+// Decode ...
+func (c *PeerStorage) Decode(r io.Reader, pver uint32) error {
+	// Blob (2+blobsize)
+	err := readElements(r,
+		&c.Blob)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewPeerStorage creates a new PeerStorage
+func NewPeerStorage() *PeerStorage {
+	return &PeerStorage{}
+}
+
+// Encode serializes the item from the PeerStorage struct
+// Writes the data to w
+func (c *PeerStorage) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.Blob)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *PeerStorage) Command() uint32 {
+	return CmdPeerStorage
+}
+
+// MaxPayloadLength ...
+func (c *PeerStorage) MaxPayloadLength(uint32) uint32 {
+	// 2 + MaxPeerStorageSize
+	return 2 + MaxPeerStorageSize
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *PeerStorage) Validate() error {
+	if len(c.Blob) > MaxPeerStorageSize {
+		return fmt.Errorf("peer storage blob of %v bytes exceeds the "+
+			"maximum of %v", len(c.Blob), MaxPeerStorageSize)
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *PeerStorage) String() string {
+	return fmt.Sprintf("\n--- Begin PeerStorage ---\n") +
+		fmt.Sprintf("Blob:\t\t%v bytes\n", len(c.Blob)) +
+		fmt.Sprintf("--- End PeerStorage ---\n")
+}
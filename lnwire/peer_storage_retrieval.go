@@ -0,0 +1,71 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// PeerStorageRetrieval hands the receiver back the blob it last asked the
+// sender to store within a PeerStorage. It's sent upon each connection, so
+// a node which has lost its data recovers its backup from its peers.
+type PeerStorageRetrieval struct {
+	// Blob is the data the receiver asked the sender to store.
+	Blob []byte
+}
+
+// Decode ...
+func (c *PeerStorageRetrieval) Decode(r io.Reader, pver uint32) error {
+	// Blob (2+blobsize)
+	err := readElements(r,
+		&c.Blob)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewPeerStorageRetrieval creates a new PeerStorageRetrieval
+func NewPeerStorageRetrieval() *PeerStorageRetrieval {
+	return &PeerStorageRetrieval{}
+}
+
+// Encode serializes the item from the PeerStorageRetrieval struct
+// Writes the data to w
+func (c *PeerStorageRetrieval) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.Blob)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *PeerStorageRetrieval) Command() uint32 {
+	return CmdPeerStorageRetrieval
+}
+
+// MaxPayloadLength ...
+func (c *PeerStorageRetrieval) MaxPayloadLength(uint32) uint32 {
+	// 2 + MaxPeerStorageSize
+	return 2 + MaxPeerStorageSize
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *PeerStorageRetrieval) Validate() error {
+	if len(c.Blob) > MaxPeerStorageSize {
+		return fmt.Errorf("peer storage blob of %v bytes exceeds the "+
+			"maximum of %v", len(c.Blob), MaxPeerStorageSize)
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *PeerStorageRetrieval) String() string {
+	return fmt.Sprintf("\n--- Begin PeerStorageRetrieval ---\n") +
+		fmt.Sprintf("Blob:\t\t%v bytes\n", len(c.Blob)) +
+		fmt.Sprintf("--- End PeerStorageRetrieval ---\n")
+}
@@ -0,0 +1,31 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	peerStorage = &PeerStorage{
+		Blob: []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+	}
+	peerStorageSerializedString  = "00050102030405"
+	peerStorageSerializedMessage = "0709110b000017700000000700050102030405"
+)
+
+func TestPeerStorageEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, peerStorage, peerStorageSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewPeerStorage()
+	DeserializeTest(t, s, newMessage, peerStorage)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, peerStorage, peerStorageSerializedMessage)
+}
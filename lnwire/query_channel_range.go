@@ -0,0 +1,91 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// QueryChannelRange is sent by a node wishing to learn of the channels
+// within a range of blocks. The remote node responds with one or more
+// ReplyChannelRange messages holding the ShortChannelIDs of every channel it
+// knows of which was funded within the range. Paired with QueryShortChanIDs,
+// this lets a node synchronize its view of the graph by downloading only the
+// channels it's missing, rather than the remote node's entire graph.
+type QueryChannelRange struct {
+	// FirstBlockHeight is the height of the first block in the range.
+	FirstBlockHeight uint32
+
+	// NumBlocks is the number of blocks within the range.
+	NumBlocks uint32
+}
+
+// Decode ...
+func (c *QueryChannelRange) Decode(r io.Reader, pver uint32) error {
+	// FirstBlockHeight (4)
+	// NumBlocks (4)
+	err := readElements(r,
+		&c.FirstBlockHeight,
+		&c.NumBlocks)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewQueryChannelRange creates a new QueryChannelRange
+func NewQueryChannelRange() *QueryChannelRange {
+	return &QueryChannelRange{}
+}
+
+// Encode serializes the item from the QueryChannelRange struct
+// Writes the data to w
+func (c *QueryChannelRange) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.FirstBlockHeight,
+		c.NumBlocks)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *QueryChannelRange) Command() uint32 {
+	return CmdQueryChannelRange
+}
+
+// MaxPayloadLength ...
+func (c *QueryChannelRange) MaxPayloadLength(uint32) uint32 {
+	// 4 + 4
+	return 8
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *QueryChannelRange) Validate() error {
+	if c.NumBlocks == 0 {
+		return fmt.Errorf("query range must span at least one block")
+	}
+
+	// We're good!
+	return nil
+}
+
+// LastBlockHeight returns the height of the last block within the range. As
+// a query may extend beyond the maximum height, the result is capped.
+func (c *QueryChannelRange) LastBlockHeight() uint32 {
+	lastHeight := uint64(c.FirstBlockHeight) + uint64(c.NumBlocks) - 1
+	if lastHeight > math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(lastHeight)
+}
+
+func (c *QueryChannelRange) String() string {
+	return fmt.Sprintf("\n--- Begin QueryChannelRange ---\n") +
+		fmt.Sprintf("FirstBlockHeight:\t%d\n", c.FirstBlockHeight) +
+		fmt.Sprintf("NumBlocks:\t\t%d\n", c.NumBlocks) +
+		fmt.Sprintf("--- End QueryChannelRange ---\n")
+}
@@ -0,0 +1,32 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	queryChannelRange = &QueryChannelRange{
+		FirstBlockHeight: 432000,
+		NumBlocks:        1000,
+	}
+	queryChannelRangeSerializedString  = "00069780000003e8"
+	queryChannelRangeSerializedMessage = "0709110b000013ec0000000800069780000003e8"
+)
+
+func TestQueryChannelRangeEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, queryChannelRange, queryChannelRangeSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewQueryChannelRange()
+	DeserializeTest(t, s, newMessage, queryChannelRange)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, queryChannelRange, queryChannelRangeSerializedMessage)
+}
@@ -0,0 +1,74 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// QueryShortChanIDs requests the announcements of specific channels, usually
+// those learned of through a ReplyChannelRange which the sender doesn't yet
+// know of. The remote node sends the ChannelAnnouncement of each channel it
+// knows of, followed by a ReplyShortChanIDsEnd.
+type QueryShortChanIDs struct {
+	ShortChanIDs []ShortChannelID
+}
+
+// Decode ...
+func (c *QueryShortChanIDs) Decode(r io.Reader, pver uint32) error {
+	// ShortChanIDs (2 + 8*N)
+	err := readElements(r,
+		&c.ShortChanIDs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewQueryShortChanIDs creates a new QueryShortChanIDs
+func NewQueryShortChanIDs() *QueryShortChanIDs {
+	return &QueryShortChanIDs{}
+}
+
+// Encode serializes the item from the QueryShortChanIDs struct
+// Writes the data to w
+func (c *QueryShortChanIDs) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ShortChanIDs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *QueryShortChanIDs) Command() uint32 {
+	return CmdQueryShortChanIDs
+}
+
+// MaxPayloadLength ...
+func (c *QueryShortChanIDs) MaxPayloadLength(uint32) uint32 {
+	// 2 + 8*MaxShortChanIDsPerMsg
+	return 2 + 8*MaxShortChanIDsPerMsg
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *QueryShortChanIDs) Validate() error {
+	if len(c.ShortChanIDs) == 0 {
+		return fmt.Errorf("query must include at least one channel")
+	}
+	if len(c.ShortChanIDs) > MaxShortChanIDsPerMsg {
+		return fmt.Errorf("too many short channel ids: %d",
+			len(c.ShortChanIDs))
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *QueryShortChanIDs) String() string {
+	return fmt.Sprintf("\n--- Begin QueryShortChanIDs ---\n") +
+		fmt.Sprintf("ShortChanIDs:\t\t%v\n", c.ShortChanIDs) +
+		fmt.Sprintf("--- End QueryShortChanIDs ---\n")
+}
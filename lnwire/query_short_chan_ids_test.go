@@ -0,0 +1,33 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	queryShortChanIDs = &QueryShortChanIDs{
+		ShortChanIDs: []ShortChannelID{
+			{BlockHeight: 432001, TxIndex: 3, TxPosition: 0},
+		},
+	}
+	queryShortChanIDsSerializedString  = "00010697810000030000"
+	queryShortChanIDsSerializedMessage = "0709110b000014000000000a00010697810000030000"
+)
+
+func TestQueryShortChanIDsEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, queryShortChanIDs, queryShortChanIDsSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewQueryShortChanIDs()
+	DeserializeTest(t, s, newMessage, queryShortChanIDs)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, queryShortChanIDs, queryShortChanIDsSerializedMessage)
+}
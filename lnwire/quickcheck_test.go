@@ -0,0 +1,579 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// goldenVectors maps each command to a fully populated message, along with the
+// expected serialization of the message (header included) as produced by
+// WriteMessage. Every message type within the registry MUST have an entry
+// here.
+var goldenVectors = map[uint32]struct {
+	msg        Message
+	serialized string
+}{
+	CmdFundingRequest:      {fundingRequest, fundingRequestSerializedMessage},
+	CmdFundingResponse:     {fundingResponse, fundingResponseSerializedMessage},
+	CmdFundingSignAccept:   {fundingSignAccept, fundingSignAcceptSerializedMessage},
+	CmdFundingSignComplete: {fundingSignComplete, fundingSignCompleteSerializedMessage},
+	CmdFundingLocked:       {fundingLocked, fundingLockedSerializedMessage},
+	CmdFundingCancel:       {fundingCancel, fundingCancelSerializedMessage},
+	CmdCloseRequest:        {closeRequest, closeRequestSerializedMessage},
+	CmdCloseComplete:       {closeComplete, closeCompleteSerializedMessage},
+	CmdHTLCAddRequest:      {htlcAddRequest, htlcAddRequestSerializedMessage},
+	CmdHTLCAddAccept:       {htlcAddAccept, htlcAddAcceptSerializedMessage},
+	CmdHTLCAddReject:       {htlcAddReject, htlcAddRejectSerializedMessage},
+	CmdHTLCSettleRequest:   {htlcSettleRequest, htlcSettleRequestSerializedMessage},
+	CmdHTLCSettleAccept:    {htlcSettleAccept, htlcSettleAcceptSerializedMessage},
+	CmdHTLCTimeoutRequest:  {htlcTimeoutRequest, htlcTimeoutRequestSerializedMessage},
+	CmdHTLCTimeoutAccept:   {htlcTimeoutAccept, htlcTimeoutAcceptSerializedMessage},
+	CmdCommitSignature:     {commitSignature, commitSignatureSerializedMessage},
+	CmdCommitRevocation:    {commitRevocation, commitRevocationSerializedMessage},
+	CmdStfu:                {stfu, stfuSerializedMessage},
+	CmdSpliceInit:          {spliceInit, spliceInitSerializedMessage},
+	CmdSpliceAck:           {spliceAck, spliceAckSerializedMessage},
+	CmdDynPropose:          {dynPropose, dynProposeSerializedMessage},
+	CmdDynAck:              {dynAck, dynAckSerializedMessage},
+	CmdDynReject:           {dynReject, dynRejectSerializedMessage},
+	CmdErrorGeneric:        {errorGeneric, errorGenericSerializedMessage},
+
+	CmdChannelAnnouncement:    {channelAnnouncement, channelAnnouncementSerializedMessage},
+	CmdChannelUpdate:          {channelUpdate, channelUpdateSerializedMessage},
+	CmdAnnouncementSignatures: {announcementSignatures, announcementSignaturesSerializedMessage},
+	CmdQueryChannelRange:      {queryChannelRange, queryChannelRangeSerializedMessage},
+	CmdReplyChannelRange:      {replyChannelRange, replyChannelRangeSerializedMessage},
+	CmdQueryShortChanIDs:      {queryShortChanIDs, queryShortChanIDsSerializedMessage},
+	CmdReplyShortChanIDsEnd:   {replyShortChanIDsEnd, replyShortChanIDsEndSerializedMessage},
+	CmdPeerStorage:            {peerStorage, peerStorageSerializedMessage},
+	CmdPeerStorageRetrieval:   {peerStorageRetrieval, peerStorageRetrievalSerializedMessage},
+	CmdSignedData:             {signedData, signedDataSerializedMessage},
+}
+
+func TestMessageGoldenVectors(t *testing.T) {
+	for command := range messageRegistry {
+		vector, ok := goldenVectors[command]
+		if !ok {
+			t.Fatalf("no golden vector for command %d", command)
+		}
+
+		var b bytes.Buffer
+		if _, err := WriteMessage(&b, vector.msg, 1, wire.TestNet3); err != nil {
+			t.Fatalf("unable to write command %d: %v", command, err)
+		}
+		if hex.EncodeToString(b.Bytes()) != vector.serialized {
+			t.Fatalf("command %d serialization doesn't match golden "+
+				"vector: got %x", command, b.Bytes())
+		}
+
+		_, msg, _, err := ReadMessage(&b, 1, wire.TestNet3)
+		if err != nil {
+			t.Fatalf("unable to read command %d: %v", command, err)
+		}
+		if !reflect.DeepEqual(msg, vector.msg) {
+			t.Fatalf("command %d decoding doesn't match golden vector",
+				command)
+		}
+	}
+}
+
+func TestMessageRoundTripQuick(t *testing.T) {
+	config := &quick.Config{MaxCount: 50}
+
+	for command, newMsg := range messageRegistry {
+		msgType := reflect.TypeOf(newMsg())
+		if _, ok := newMsg().(quick.Generator); !ok {
+			t.Fatalf("no generator for %v", msgType)
+		}
+
+		// For each randomly generated message, ensure that it survives
+		// a full trip through WriteMessage and ReadMessage unaltered.
+		roundTrip := func(v []reflect.Value, r *rand.Rand) {
+			v[0], _ = quick.Value(msgType, r)
+		}
+		check := func(msg Message) bool {
+			var b bytes.Buffer
+			if _, err := WriteMessage(&b, msg, 1, wire.TestNet3); err != nil {
+				t.Logf("unable to write %v: %v", msgType, err)
+				return false
+			}
+
+			_, newMsg, _, err := ReadMessage(&b, 1, wire.TestNet3)
+			if err != nil {
+				t.Logf("unable to read %v: %v", msgType, err)
+				return false
+			}
+
+			return reflect.DeepEqual(msg, newMsg)
+		}
+
+		config.Values = roundTrip
+		if err := quick.Check(check, config); err != nil {
+			t.Fatalf("command %d failed round trip: %v", command, err)
+		}
+	}
+}
+
+// The functions below produce random, yet valid, instances of each element
+// used within the messages. Empty slices are generated as nil in order to
+// mirror the behavior of readElement.
+
+func randPubKey(r *rand.Rand) *btcec.PublicKey {
+	var b [32]byte
+	r.Read(b[:])
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), b[:])
+	return pub
+}
+
+func randSig(r *rand.Rand) *btcec.Signature {
+	var b, hash [32]byte
+	r.Read(b[:])
+	r.Read(hash[:])
+	priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), b[:])
+	sig, _ := priv.Sign(hash[:])
+	return sig
+}
+
+func randSigs(r *rand.Rand) []*btcec.Signature {
+	var sigs []*btcec.Signature
+	for i, n := 0, r.Intn(5); i < n; i++ {
+		sigs = append(sigs, randSig(r))
+	}
+	return sigs
+}
+
+func randShaHash(r *rand.Rand) *wire.ShaHash {
+	var hash wire.ShaHash
+	r.Read(hash[:])
+	return &hash
+}
+
+func randChannelID(r *rand.Rand) ChannelID {
+	var cid ChannelID
+	r.Read(cid[:])
+	return cid
+}
+
+func randHash20(r *rand.Rand) [20]byte {
+	var hash [20]byte
+	r.Read(hash[:])
+	return hash
+}
+
+func randHashes20(r *rand.Rand) []*[20]byte {
+	var hashes []*[20]byte
+	for i, n := 0, r.Intn(5); i < n; i++ {
+		hash := randHash20(r)
+		hashes = append(hashes, &hash)
+	}
+	return hashes
+}
+
+func randPkScript(r *rand.Rand) PkScript {
+	hash := randHash20(r)
+	script := append([]byte{118, 169, 20}, hash[:]...)
+	return PkScript(append(script, 136, 172))
+}
+
+func randTxIns(r *rand.Rand) []*wire.TxIn {
+	var txIns []*wire.TxIn
+	for i, n := 0, r.Intn(10); i < n; i++ {
+		outPoint := wire.NewOutPoint(randShaHash(r), r.Uint32())
+		txIns = append(txIns, wire.NewTxIn(outPoint, nil))
+	}
+	return txIns
+}
+
+func randAmount(r *rand.Rand) btcutil.Amount {
+	return btcutil.Amount(r.Int63n(btcutil.MaxSatoshi))
+}
+
+func randShortChanIDs(r *rand.Rand) []ShortChannelID {
+	var chanIDs []ShortChannelID
+	for i, n := 0, r.Intn(20); i < n; i++ {
+		chanIDs = append(chanIDs, NewShortChanIDFromInt(r.Uint64()))
+	}
+	return chanIDs
+}
+
+func randString(r *rand.Rand) string {
+	b := make([]byte, r.Intn(100))
+	for i := range b {
+		b[i] = byte('a' + r.Intn(26))
+	}
+	return string(b)
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *FundingRequest) Generate(r *rand.Rand, size int) reflect.Value {
+	reserve := randAmount(r)
+	funding := reserve + btcutil.Amount(r.Int63n(btcutil.SatoshiPerBitcoin))
+	flags := uint8(r.Intn(4))
+	if funding > MaxFundingAmount {
+		flags |= FundingFlagWumbo
+	}
+	return reflect.ValueOf(&FundingRequest{
+		ReservationID:          r.Uint64(),
+		ChannelType:            ChannelType(r.Intn(3)),
+		RequesterFundingAmount: funding,
+		RequesterReserveAmount: reserve,
+		MinFeePerKb:            randAmount(r),
+		PaymentAmount:          randAmount(r),
+		MinDepth:               r.Uint32(),
+		MinTotalFundingAmount:  funding + randAmount(r),
+		LockTime:               r.Uint32(),
+		FeePayer:               uint8(r.Intn(3)),
+		MaxValueInFlight:       MilliSatoshi(r.Uint64()),
+		MaxAcceptedHtlcs:       uint16(1 + r.Intn(MaxHTLCNumber)),
+		ChannelFlags:           flags,
+		RevocationHash:         randHash20(r),
+		Pubkey:                 randPubKey(r),
+		DeliveryPkScript:       randPkScript(r),
+		ChangePkScript:         randPkScript(r),
+		Inputs:                 randTxIns(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *FundingResponse) Generate(r *rand.Rand, size int) reflect.Value {
+	reserve := randAmount(r)
+	return reflect.ValueOf(&FundingResponse{
+		ChannelType:            ChannelType(r.Intn(3)),
+		ReservationID:          r.Uint64(),
+		ResponderFundingAmount: reserve + btcutil.Amount(r.Int63n(btcutil.SatoshiPerBitcoin)),
+		ResponderReserveAmount: reserve,
+		MinFeePerKb:            randAmount(r),
+		MinDepth:               r.Uint32(),
+		LockTime:               r.Uint32(),
+		FeePayer:               uint8(r.Intn(3)),
+		MaxValueInFlight:       MilliSatoshi(r.Uint64()),
+		MaxAcceptedHtlcs:       uint16(1 + r.Intn(MaxHTLCNumber)),
+		RevocationHash:         randHash20(r),
+		Pubkey:                 randPubKey(r),
+		CommitSig:              randSig(r),
+		DeliveryPkScript:       randPkScript(r),
+		ChangePkScript:         randPkScript(r),
+		Inputs:                 randTxIns(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *FundingSignAccept) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&FundingSignAccept{
+		ReservationID: r.Uint64(),
+		CommitSig:     randSig(r),
+		FundingTXSigs: randSigs(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *FundingSignComplete) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&FundingSignComplete{
+		ReservationID: r.Uint64(),
+		TxID:          randShaHash(r),
+		FundingTXSigs: randSigs(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *FundingLocked) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&FundingLocked{
+		ChannelID: randChannelID(r),
+		AliasScid: ShortChannelID{
+			BlockHeight: AliasStartBlockHeight + uint32(r.Int63n(
+				int64(AliasEndBlockHeight-AliasStartBlockHeight))),
+			TxIndex:    r.Uint32() & 0xFFFFFF,
+			TxPosition: uint16(r.Uint32()),
+		},
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *FundingCancel) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&FundingCancel{
+		ReservationID: r.Uint64(),
+		Reason:        randString(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *CloseRequest) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&CloseRequest{
+		ChannelID:         randChannelID(r),
+		RequesterCloseSig: randSig(r),
+		Fee:               randAmount(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *CloseComplete) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&CloseComplete{
+		ChannelID:         randChannelID(r),
+		ResponderCloseSig: randSig(r),
+		CloseShaHash:      randShaHash(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCAddRequest) Generate(r *rand.Rand, size int) reflect.Value {
+	blob := make([]byte, r.Intn(1000))
+	r.Read(blob)
+	return reflect.ValueOf(&HTLCAddRequest{
+		ChannelID:        NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:          HTLCKey(r.Uint64()),
+		Expiry:           r.Uint32(),
+		Amount:           MilliSatoshi(r.Uint64()),
+		ContractType:     uint8(r.Intn(256)),
+		HashType:         HTLCHashTypeHash160,
+		RedemptionHashes: randHashes20(r),
+		Blob:             blob,
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCAddAccept) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&HTLCAddAccept{
+		ChannelID: NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:   HTLCKey(r.Uint64()),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCAddReject) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&HTLCAddReject{
+		ChannelID: NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:   HTLCKey(r.Uint64()),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCSettleRequest) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&HTLCSettleRequest{
+		ChannelID:        NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:          HTLCKey(r.Uint64()),
+		RedemptionProofs: randHashes20(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCSettleAccept) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&HTLCSettleAccept{
+		ChannelID: NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:   HTLCKey(r.Uint64()),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCTimeoutRequest) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&HTLCTimeoutRequest{
+		ChannelID: NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:   HTLCKey(r.Uint64()),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *HTLCTimeoutAccept) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&HTLCTimeoutAccept{
+		ChannelID: NewShortChanIDFromInt(r.Uint64()),
+		HTLCKey:   HTLCKey(r.Uint64()),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *CommitSignature) Generate(r *rand.Rand, size int) reflect.Value {
+	var keys []uint64
+	for i, n := 0, r.Intn(100); i < n; i++ {
+		keys = append(keys, r.Uint64())
+	}
+	return reflect.ValueOf(&CommitSignature{
+		ChannelID:        NewShortChanIDFromInt(r.Uint64()),
+		CommitmentHeight: r.Uint64(),
+		UpdatedHTLCKeys:  keys,
+		RevocationHash:   randHash20(r),
+		Fee:              randAmount(r),
+		CommitSig:        randSig(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *CommitRevocation) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&CommitRevocation{
+		ChannelID:        NewShortChanIDFromInt(r.Uint64()),
+		CommitmentHeight: r.Uint64(),
+		RevocationProof:  randHash20(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *ErrorGeneric) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&ErrorGeneric{
+		ChannelID: NewShortChanIDFromInt(r.Uint64()),
+		Problem:   randString(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *ChannelAnnouncement) Generate(r *rand.Rand, size int) reflect.Value {
+	nodeID1, nodeID2 := randPubKey(r), randPubKey(r)
+	if bytes.Compare(nodeID1.SerializeCompressed(),
+		nodeID2.SerializeCompressed()) > 0 {
+		nodeID1, nodeID2 = nodeID2, nodeID1
+	}
+	return reflect.ValueOf(&ChannelAnnouncement{
+		NodeSig1:       randSig(r),
+		NodeSig2:       randSig(r),
+		BitcoinSig1:    randSig(r),
+		BitcoinSig2:    randSig(r),
+		ShortChannelID: NewShortChanIDFromInt(r.Uint64()),
+		NodeID1:        nodeID1,
+		NodeID2:        nodeID2,
+		BitcoinKey1:    randPubKey(r),
+		BitcoinKey2:    randPubKey(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *ChannelUpdate) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&ChannelUpdate{
+		Signature:       randSig(r),
+		ShortChannelID:  NewShortChanIDFromInt(r.Uint64()),
+		Timestamp:       r.Uint32(),
+		Flags:           uint16(r.Uint32()),
+		TimeLockDelta:   uint16(r.Uint32()),
+		HtlcMinimumMsat: MilliSatoshi(r.Uint64()),
+		BaseFee:         r.Uint32(),
+		FeeRate:         r.Uint32(),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *AnnouncementSignatures) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&AnnouncementSignatures{
+		ChannelID: randChannelID(r),
+		ShortChannelID: ShortChannelID{
+			BlockHeight: uint32(r.Int63n(int64(AliasStartBlockHeight))),
+			TxIndex:     r.Uint32() & 0xFFFFFF,
+			TxPosition:  uint16(r.Uint32()),
+		},
+		NodeSignature:    randSig(r),
+		BitcoinSignature: randSig(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *QueryChannelRange) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&QueryChannelRange{
+		FirstBlockHeight: r.Uint32(),
+		NumBlocks:        r.Uint32() + 1,
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *ReplyChannelRange) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&ReplyChannelRange{
+		FirstBlockHeight: r.Uint32(),
+		NumBlocks:        r.Uint32(),
+		Complete:         r.Intn(2) == 1,
+		ShortChanIDs:     randShortChanIDs(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *QueryShortChanIDs) Generate(r *rand.Rand, size int) reflect.Value {
+	chanIDs := randShortChanIDs(r)
+	chanIDs = append(chanIDs, NewShortChanIDFromInt(r.Uint64()))
+	return reflect.ValueOf(&QueryShortChanIDs{
+		ShortChanIDs: chanIDs,
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *ReplyShortChanIDsEnd) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&ReplyShortChanIDsEnd{
+		Complete: r.Intn(2) == 1,
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *PeerStorage) Generate(r *rand.Rand, size int) reflect.Value {
+	blob := make([]byte, r.Intn(MaxPeerStorageSize+1))
+	r.Read(blob)
+	return reflect.ValueOf(&PeerStorage{
+		Blob: blob,
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *PeerStorageRetrieval) Generate(r *rand.Rand, size int) reflect.Value {
+	blob := make([]byte, r.Intn(MaxPeerStorageSize+1))
+	r.Read(blob)
+	return reflect.ValueOf(&PeerStorageRetrieval{
+		Blob: blob,
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *SignedData) Generate(r *rand.Rand, size int) reflect.Value {
+	data := make([]byte, r.Intn(MaxSignedDataSize+1))
+	r.Read(data)
+	return reflect.ValueOf(&SignedData{
+		Type:      uint16(r.Uint32()),
+		Data:      data,
+		Signature: randSig(r),
+	})
+}
+
+// Generate is part of the quick.Generator interface.
+func (c *Stfu) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&Stfu{
+		ChannelID: randChannelID(r),
+		Initiator: r.Intn(2) == 1,
+	})
+}
+
+func (c *SpliceInit) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&SpliceInit{
+		ChannelID:           randChannelID(r),
+		FundingContribution: randAmount(r) - randAmount(r),
+		FeePerKb:            randAmount(r),
+		LockTime:            r.Uint32(),
+		FundingPubKey:       randPubKey(r),
+	})
+}
+
+func (c *SpliceAck) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&SpliceAck{
+		ChannelID:           randChannelID(r),
+		FundingContribution: randAmount(r) - randAmount(r),
+		FundingPubKey:       randPubKey(r),
+	})
+}
+
+func (c *DynPropose) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&DynPropose{
+		ChannelID:        randChannelID(r),
+		MaxAcceptedHtlcs: uint16(1 + r.Intn(MaxHTLCNumber)),
+		MaxValueInFlight: MilliSatoshi(r.Uint64()),
+		CsvDelay:         1 + uint32(r.Int31()),
+	})
+}
+
+func (c *DynAck) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&DynAck{
+		ChannelID: randChannelID(r),
+	})
+}
+
+func (c *DynReject) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(&DynReject{
+		ChannelID: randChannelID(r),
+	})
+}
@@ -0,0 +1,98 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxShortChanIDsPerMsg is the maximum number of ShortChannelIDs included
+// within a single ReplyChannelRange or QueryShortChanIDs message, keeping
+// each within the maximum message payload.
+const MaxShortChanIDsPerMsg = 8000
+
+// ReplyChannelRange is sent in response to a QueryChannelRange, holding the
+// ShortChannelIDs of the channels the sender knows of within the queried
+// range. Large ranges are split over several replies, with Complete set on
+// the final one.
+type ReplyChannelRange struct {
+	// FirstBlockHeight and NumBlocks describe the range of blocks this
+	// reply covers.
+	FirstBlockHeight uint32
+	NumBlocks        uint32
+
+	// Complete is set within the final reply to a query.
+	Complete bool
+
+	// ShortChanIDs are the channels known within the range, in ascending
+	// order.
+	ShortChanIDs []ShortChannelID
+}
+
+// Decode ...
+func (c *ReplyChannelRange) Decode(r io.Reader, pver uint32) error {
+	// FirstBlockHeight (4)
+	// NumBlocks (4)
+	// Complete (1)
+	// ShortChanIDs (2 + 8*N)
+	err := readElements(r,
+		&c.FirstBlockHeight,
+		&c.NumBlocks,
+		&c.Complete,
+		&c.ShortChanIDs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewReplyChannelRange creates a new ReplyChannelRange
+func NewReplyChannelRange() *ReplyChannelRange {
+	return &ReplyChannelRange{}
+}
+
+// Encode serializes the item from the ReplyChannelRange struct
+// Writes the data to w
+func (c *ReplyChannelRange) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.FirstBlockHeight,
+		c.NumBlocks,
+		c.Complete,
+		c.ShortChanIDs)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *ReplyChannelRange) Command() uint32 {
+	return CmdReplyChannelRange
+}
+
+// MaxPayloadLength ...
+func (c *ReplyChannelRange) MaxPayloadLength(uint32) uint32 {
+	// 4 + 4 + 1 + 2 + 8*MaxShortChanIDsPerMsg
+	return 11 + 8*MaxShortChanIDsPerMsg
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *ReplyChannelRange) Validate() error {
+	if len(c.ShortChanIDs) > MaxShortChanIDsPerMsg {
+		return fmt.Errorf("too many short channel ids: %d",
+			len(c.ShortChanIDs))
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *ReplyChannelRange) String() string {
+	return fmt.Sprintf("\n--- Begin ReplyChannelRange ---\n") +
+		fmt.Sprintf("FirstBlockHeight:\t%d\n", c.FirstBlockHeight) +
+		fmt.Sprintf("NumBlocks:\t\t%d\n", c.NumBlocks) +
+		fmt.Sprintf("Complete:\t\t%v\n", c.Complete) +
+		fmt.Sprintf("ShortChanIDs:\t\t%v\n", c.ShortChanIDs) +
+		fmt.Sprintf("--- End ReplyChannelRange ---\n")
+}
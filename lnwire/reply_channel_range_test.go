@@ -0,0 +1,37 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	replyChannelRange = &ReplyChannelRange{
+		FirstBlockHeight: 432000,
+		NumBlocks:        1000,
+		Complete:         true,
+		ShortChanIDs: []ShortChannelID{
+			{BlockHeight: 432001, TxIndex: 3, TxPosition: 0},
+			{BlockHeight: 432500, TxIndex: 1, TxPosition: 2},
+		},
+	}
+	replyChannelRangeSerializedString  = "00069780000003e801000206978100000300000699740000010002"
+	replyChannelRangeSerializedMessage = "0709110b000013f60000001b00069780000003e801000206978100000300000699740000010002"
+)
+
+func TestReplyChannelRangeEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, replyChannelRange, replyChannelRangeSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewReplyChannelRange()
+	DeserializeTest(t, s, newMessage, replyChannelRange)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, replyChannelRange, replyChannelRangeSerializedMessage)
+}
@@ -0,0 +1,66 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// ReplyShortChanIDsEnd is sent once all announcements requested by a
+// QueryShortChanIDs have been sent. Complete is false if the sender doesn't
+// maintain up to date information about the graph, in which case the
+// querying node should look elsewhere for any channels it didn't receive.
+type ReplyShortChanIDsEnd struct {
+	Complete bool
+}
+
+// Decode ...
+func (c *ReplyShortChanIDsEnd) Decode(r io.Reader, pver uint32) error {
+	// Complete (1)
+	err := readElements(r,
+		&c.Complete)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewReplyShortChanIDsEnd creates a new ReplyShortChanIDsEnd
+func NewReplyShortChanIDsEnd() *ReplyShortChanIDsEnd {
+	return &ReplyShortChanIDsEnd{}
+}
+
+// Encode serializes the item from the ReplyShortChanIDsEnd struct
+// Writes the data to w
+func (c *ReplyShortChanIDsEnd) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.Complete)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *ReplyShortChanIDsEnd) Command() uint32 {
+	return CmdReplyShortChanIDsEnd
+}
+
+// MaxPayloadLength ...
+func (c *ReplyShortChanIDsEnd) MaxPayloadLength(uint32) uint32 {
+	// 1
+	return 1
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *ReplyShortChanIDsEnd) Validate() error {
+	// We're good!
+	return nil
+}
+
+func (c *ReplyShortChanIDsEnd) String() string {
+	return fmt.Sprintf("\n--- Begin ReplyShortChanIDsEnd ---\n") +
+		fmt.Sprintf("Complete:\t\t%v\n", c.Complete) +
+		fmt.Sprintf("--- End ReplyShortChanIDsEnd ---\n")
+}
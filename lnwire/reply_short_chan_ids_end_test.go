@@ -0,0 +1,31 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	replyShortChanIDsEnd = &ReplyShortChanIDsEnd{
+		Complete: true,
+	}
+	replyShortChanIDsEndSerializedString  = "01"
+	replyShortChanIDsEndSerializedMessage = "0709110b0000140a0000000101"
+)
+
+func TestReplyShortChanIDsEndEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, replyShortChanIDsEnd, replyShortChanIDsEndSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewReplyShortChanIDsEnd()
+	DeserializeTest(t, s, newMessage, replyShortChanIDsEnd)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, replyShortChanIDsEnd, replyShortChanIDsEndSerializedMessage)
+}
@@ -0,0 +1,96 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// MaxSignedDataSize is the largest payload a SignedData message may carry.
+const MaxSignedDataSize = 8192
+
+// SignedData carries data of an application layered on top of the
+// connection between two nodes, signed by the identity key of the sender.
+// The signature commits to the session the message is sent within, so it
+// can't be replayed to another node, nor within a later session. Nodes don't
+// interpret the data, only verifying the signature before handing it to the
+// application.
+type SignedData struct {
+	// Type identifies the application protocol the data belongs to.
+	Type uint16
+
+	// Data is the payload of the application.
+	Data []byte
+
+	// Signature is the signature of the sender's identity key over the
+	// type, and data, within the session.
+	Signature *btcec.Signature
+}
+
+// Decode ...
+func (c *SignedData) Decode(r io.Reader, pver uint32) error {
+	// Type (2)
+	// Data (2+datasize)
+	// Signature (64)
+	err := readElements(r,
+		&c.Type,
+		&c.Data,
+		&c.Signature)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewSignedData creates a new SignedData
+func NewSignedData() *SignedData {
+	return &SignedData{}
+}
+
+// Encode serializes the item from the SignedData struct
+// Writes the data to w
+func (c *SignedData) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.Type,
+		c.Data,
+		c.Signature)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *SignedData) Command() uint32 {
+	return CmdSignedData
+}
+
+// MaxPayloadLength ...
+func (c *SignedData) MaxPayloadLength(uint32) uint32 {
+	// 2 + 2 + MaxSignedDataSize + 64
+	return 68 + MaxSignedDataSize
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *SignedData) Validate() error {
+	if len(c.Data) > MaxSignedDataSize {
+		return fmt.Errorf("signed data of %v bytes exceeds the maximum "+
+			"of %v", len(c.Data), MaxSignedDataSize)
+	}
+	if c.Signature == nil {
+		return fmt.Errorf("signed data is missing its signature")
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *SignedData) String() string {
+	return fmt.Sprintf("\n--- Begin SignedData ---\n") +
+		fmt.Sprintf("Type:\t\t%d\n", c.Type) +
+		fmt.Sprintf("Data:\t\t%v bytes\n", len(c.Data)) +
+		fmt.Sprintf("--- End SignedData ---\n")
+}
@@ -0,0 +1,33 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	signedData = &SignedData{
+		Type:      42,
+		Data:      []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+		Signature: commitSig,
+	}
+	signedDataSerializedString  = "002a00050102030405333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df"
+	signedDataSerializedMessage = "0709110b00001b5800000049002a00050102030405333835e58e958f5e92b4ff4e6fa2470dac88094c97506b4d6d1f4e23e52cb48157483ac18d6b9c9c14f0c626694c9ccf8b27b3dbbedfdf6b6c9a9fa9f427a1df"
+)
+
+func TestSignedDataEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, signedData, signedDataSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewSignedData()
+	DeserializeTest(t, s, newMessage, signedData)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, signedData, signedDataSerializedMessage)
+}
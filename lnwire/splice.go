@@ -0,0 +1,176 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+)
+
+// SpliceInit is sent by either side of a quiescent channel to propose
+// splicing funds into, or out of, it. The splice spends the current funding
+// output within a new funding transaction, to which the commitments are
+// re-anchored, so the channel remains open throughout.
+type SpliceInit struct {
+	ChannelID ChannelID
+
+	// FundingContribution is the amount the sender adds to the channel.
+	// It's negative should the sender splice funds out of the channel,
+	// which may not exceed its balance.
+	FundingContribution btcutil.Amount
+
+	// FeePerKb is the fee rate of the splice transaction, paid by the
+	// sender.
+	FeePerKb btcutil.Amount
+
+	// LockTime is the lock time of the splice transaction.
+	LockTime uint32
+
+	// FundingPubKey is the key of the sender within the multi-sig output
+	// of the splice transaction.
+	FundingPubKey *btcec.PublicKey
+}
+
+// Decode ...
+func (c *SpliceInit) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	// FundingContribution (8)
+	// FeePerKb (8)
+	// LockTime (4)
+	// FundingPubKey (33)
+	err := readElements(r,
+		&c.ChannelID,
+		&c.FundingContribution,
+		&c.FeePerKb,
+		&c.LockTime,
+		&c.FundingPubKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewSpliceInit creates a new SpliceInit
+func NewSpliceInit() *SpliceInit {
+	return &SpliceInit{}
+}
+
+// Encode serializes the item from the SpliceInit struct
+// Writes the data to w
+func (c *SpliceInit) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID,
+		c.FundingContribution,
+		c.FeePerKb,
+		c.LockTime,
+		c.FundingPubKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *SpliceInit) Command() uint32 {
+	return CmdSpliceInit
+}
+
+// MaxPayloadLength ...
+func (c *SpliceInit) MaxPayloadLength(uint32) uint32 {
+	// 32 + 8 + 8 + 4 + 33
+	return 85
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *SpliceInit) Validate() error {
+	if c.FeePerKb < 0 {
+		return fmt.Errorf("FeePerKb cannot be negative")
+	}
+
+	// We're good!
+	return nil
+}
+
+func (c *SpliceInit) String() string {
+	return fmt.Sprintf("\n--- Begin SpliceInit ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("FundingContribution:\t%v\n", c.FundingContribution) +
+		fmt.Sprintf("FeePerKb:\t\t%v\n", c.FeePerKb) +
+		fmt.Sprintf("LockTime:\t\t%v\n", c.LockTime) +
+		fmt.Sprintf("--- End SpliceInit ---\n")
+}
+
+// SpliceAck is sent in reply to a SpliceInit, accepting the splice.
+type SpliceAck struct {
+	ChannelID ChannelID
+
+	// FundingContribution is the amount the sender adds to the channel,
+	// which is negative should it splice funds out of the channel.
+	FundingContribution btcutil.Amount
+
+	// FundingPubKey is the key of the sender within the multi-sig output
+	// of the splice transaction.
+	FundingPubKey *btcec.PublicKey
+}
+
+// Decode ...
+func (c *SpliceAck) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	// FundingContribution (8)
+	// FundingPubKey (33)
+	err := readElements(r,
+		&c.ChannelID,
+		&c.FundingContribution,
+		&c.FundingPubKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewSpliceAck creates a new SpliceAck
+func NewSpliceAck() *SpliceAck {
+	return &SpliceAck{}
+}
+
+// Encode serializes the item from the SpliceAck struct
+// Writes the data to w
+func (c *SpliceAck) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID,
+		c.FundingContribution,
+		c.FundingPubKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *SpliceAck) Command() uint32 {
+	return CmdSpliceAck
+}
+
+// MaxPayloadLength ...
+func (c *SpliceAck) MaxPayloadLength(uint32) uint32 {
+	// 32 + 8 + 33
+	return 73
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *SpliceAck) Validate() error {
+	// We're good!
+	return nil
+}
+
+func (c *SpliceAck) String() string {
+	return fmt.Sprintf("\n--- Begin SpliceAck ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("FundingContribution:\t%v\n", c.FundingContribution) +
+		fmt.Sprintf("--- End SpliceAck ---\n")
+}
@@ -0,0 +1,46 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcutil"
+)
+
+var (
+	spliceInit = &SpliceInit{
+		ChannelID:           NewChanIDFromOutPoint(outpoint1),
+		FundingContribution: btcutil.Amount(500000),
+		FeePerKb:            btcutil.Amount(5000),
+		LockTime:            0,
+		FundingPubKey:       pubKey,
+	}
+	spliceInitSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855000000000007a12000000000000013880000000002f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+	spliceInitSerializedMessage = "0709110b0000089800000055e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855000000000007a12000000000000013880000000002f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+
+	// spliceAck splices funds out of the channel.
+	spliceAck = &SpliceAck{
+		ChannelID:           NewChanIDFromOutPoint(outpoint1),
+		FundingContribution: btcutil.Amount(-100000),
+		FundingPubKey:       pubKey,
+	}
+	spliceAckSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855fffffffffffe796002f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+	spliceAckSerializedMessage = "0709110b000008a200000049e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855fffffffffffe796002f977808cb9577897582d7524b562691e180953dd0008eb44e09594c539d6daee"
+)
+
+func TestSpliceInitEncodeDecode(t *testing.T) {
+	s := SerializeTest(t, spliceInit, spliceInitSerializedString, filename)
+
+	newMessage := NewSpliceInit()
+	DeserializeTest(t, s, newMessage, spliceInit)
+
+	MessageSerializeDeserializeTest(t, spliceInit, spliceInitSerializedMessage)
+}
+
+func TestSpliceAckEncodeDecode(t *testing.T) {
+	s := SerializeTest(t, spliceAck, spliceAckSerializedString, filename)
+
+	newMessage := NewSpliceAck()
+	DeserializeTest(t, s, newMessage, spliceAck)
+
+	MessageSerializeDeserializeTest(t, spliceAck, spliceAckSerializedMessage)
+}
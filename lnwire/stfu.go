@@ -0,0 +1,76 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+)
+
+// Stfu is sent by either side to quiesce the channel, pausing updates to it
+// for protocols which require its state be fixed, such as splicing. The
+// sender must not send any updates after it, and sends it only once it has
+// no updates of its own pending. The receiver replies with its own Stfu once
+// its updates are settled, at which point the channel is quiescent until the
+// protocol requiring it completes, or the peers disconnect.
+type Stfu struct {
+	ChannelID ChannelID
+
+	// Initiator is true if the sender requested quiescence, and false if
+	// it's replying to the request of the receiver.
+	Initiator bool
+}
+
+// Decode ...
+func (c *Stfu) Decode(r io.Reader, pver uint32) error {
+	// ChannelID (32)
+	// Initiator (1)
+	err := readElements(r,
+		&c.ChannelID,
+		&c.Initiator)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NewStfu creates a new Stfu
+func NewStfu() *Stfu {
+	return &Stfu{}
+}
+
+// Encode serializes the item from the Stfu struct
+// Writes the data to w
+func (c *Stfu) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.ChannelID,
+		c.Initiator)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Command ...
+func (c *Stfu) Command() uint32 {
+	return CmdStfu
+}
+
+// MaxPayloadLength ...
+func (c *Stfu) MaxPayloadLength(uint32) uint32 {
+	// 32 + 1
+	return 33
+}
+
+// Validate makes sure the struct data is valid (e.g. no negatives or invalid pkscripts)
+func (c *Stfu) Validate() error {
+	// We're good!
+	return nil
+}
+
+func (c *Stfu) String() string {
+	return fmt.Sprintf("\n--- Begin Stfu ---\n") +
+		fmt.Sprintf("ChannelID:\t\t%v\n", c.ChannelID) +
+		fmt.Sprintf("Initiator:\t\t%v\n", c.Initiator) +
+		fmt.Sprintf("--- End Stfu ---\n")
+}
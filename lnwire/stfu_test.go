@@ -0,0 +1,32 @@
+package lnwire
+
+import (
+	"testing"
+)
+
+var (
+	stfu = &Stfu{
+		ChannelID: NewChanIDFromOutPoint(outpoint1),
+		Initiator: true,
+	}
+	stfuSerializedString  = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b85501"
+	stfuSerializedMessage = "0709110b0000083400000021e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b85501"
+)
+
+func TestStfuEncodeDecode(t *testing.T) {
+	// All of these types being passed are of the message interface type
+	// Test serialization, runs: message.Encode(b, 0)
+	// Returns bytes
+	// Compares the expected serialized string from the original
+	s := SerializeTest(t, stfu, stfuSerializedString, filename)
+
+	// Test deserialization, runs: message.Decode(s, 0)
+	// Makes sure the deserialized struct is the same as the original
+	newMessage := NewStfu()
+	DeserializeTest(t, s, newMessage, stfu)
+
+	// Test message using Message interface
+	// Serializes into buf: WriteMessage(buf, message, uint32(1), wire.TestNet3)
+	// Deserializes into msg: _, msg, _ , err := ReadMessage(buf, uint32(1), wire.TestNet3)
+	MessageSerializeDeserializeTest(t, stfu, stfuSerializedMessage)
+}
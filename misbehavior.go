@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// recordMisbehavior adds the protocol violation to the misbehavior log of
+// the peer, so operators can decide whether to ban the peer, or close our
+// channels with it.
+func (p *peer) recordMisbehavior(mType channeldb.MisbehaviorType,
+	format string, args ...interface{}) {
+
+	description := fmt.Sprintf(format, args...)
+
+	fmt.Printf("peer %v misbehaved (%v): %v\n", p.peerID, mType,
+		description)
+
+	pubKey := p.remotePub()
+	if pubKey == nil {
+		return
+	}
+
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+
+	err := p.server.lnwallet.ChannelDB.AddMisbehavior(key,
+		&channeldb.Misbehavior{
+			Type:        mType,
+			Timestamp:   time.Now(),
+			Description: description,
+		})
+	if err != nil {
+		fmt.Printf("unable to record misbehavior of peer %v: %v\n",
+			p.peerID, err)
+	}
+}
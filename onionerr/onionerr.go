@@ -0,0 +1,214 @@
+package onionerr
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/fastsha256"
+	"github.com/codahale/chacha20"
+	"github.com/lightningnetwork/lnd/ecdh"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// When a hop fails an HTLC, the failure is handed back along the route to
+// the sender. Each hop of the route shares a secret with the sender, derived
+// from the ephemeral key the HTLC carried to the hop, as described within
+// the ecdh package.
+//
+// The hop the failure arises at authenticates it under um_i, and encrypts
+// it under ammag_i. Each hop it passes through on its way back encrypts it
+// again under its own ammag_i, so that only the sender, knowing every
+// shared secret, is able to peel the layers, and learn, from the first
+// layer whose HMAC checks out, which hop the failure arose at:
+//
+//   um_i = HMAC256("um", ss_i), ammag_i = HMAC256("ammag", ss_i)
+//   packet = ChaCha20(ammag_i, HMAC256(um_i, payload) || payload)
+//   payload = len(failure) || failure || padding
+//
+// The payload is padded to a fixed size, so a hop can't tell the length of
+// the failure, and thus its kind, from the packet.
+
+const (
+	// MaxFailureLen is the longest failure a hop may hand back.
+	MaxFailureLen = 256
+
+	// payloadLen is the length of the padded failure.
+	payloadLen = 2 + MaxFailureLen
+
+	// PacketLen is the length of every failure packet.
+	PacketLen = fastsha256.Size + payloadLen
+)
+
+// ErrUnreadableFailure is returned when none of the hops of the route
+// authenticate the failure packet, so it was either garbled by a hop along
+// the way, or wasn't sent by a hop of the route at all.
+var ErrUnreadableFailure = errors.New("unable to decrypt failure packet")
+
+var (
+	umTag    = []byte("um")
+	ammagTag = []byte("ammag")
+)
+
+// SharedSecret is the secret a hop of a route shares with the sender.
+type SharedSecret [32]byte
+
+// GenerateSharedSecrets returns the secret the sender shares with each of
+// the hops of the route, using the session key as e_0.
+func GenerateSharedSecrets(sessionKey *btcec.PrivateKey,
+	hops []*btcec.PublicKey) []SharedSecret {
+
+	secrets := make([]SharedSecret, 0, len(hops))
+
+	ephemeralKey := sessionKey
+	for _, hop := range hops {
+		ss := SharedSecret(ecdh.SharedSecret(ephemeralKey, hop))
+		secrets = append(secrets, ss)
+
+		factor := ecdh.BlindingFactor(ephemeralKey.PubKey(), ss)
+		ephemeralKey = ecdh.TweakPrivKey(ephemeralKey, factor)
+	}
+
+	return secrets
+}
+
+// DeriveSharedSecret returns the secret the hop shares with the sender,
+// given the identity key of the hop, and the ephemeral key the HTLC carried
+// to it. The ephemeral key to hand to the next hop is returned along with
+// the secret.
+func DeriveSharedSecret(nodeKey keychain.SingleKeyECDH,
+	ephemeralKey *btcec.PublicKey) (SharedSecret, *btcec.PublicKey, error) {
+
+	ss, err := ecdh.NodeSharedSecret(nodeKey, ephemeralKey)
+	if err != nil {
+		return SharedSecret{}, nil, err
+	}
+	nextEphemeralKey := ecdh.TweakPubKey(ephemeralKey,
+		ecdh.BlindingFactor(ephemeralKey, ss))
+
+	return ss, nextEphemeralKey, nil
+}
+
+// ErrorEncrypter is held by a hop for each HTLC it forwards, or receives,
+// to encrypt the failure of the HTLC for the sender.
+type ErrorEncrypter struct {
+	sharedSecret SharedSecret
+}
+
+// NewErrorEncrypter creates an encrypter of the failures of the HTLC the
+// hop shares the passed secret with the sender of.
+func NewErrorEncrypter(ss SharedSecret) *ErrorEncrypter {
+	return &ErrorEncrypter{sharedSecret: ss}
+}
+
+// EncryptError creates the failure packet of a failure which arose at this
+// hop.
+func (e *ErrorEncrypter) EncryptError(failure []byte) ([]byte, error) {
+	if len(failure) > MaxFailureLen {
+		return nil, fmt.Errorf("failure of %v bytes exceeds the max "+
+			"of %v", len(failure), MaxFailureLen)
+	}
+
+	payload := make([]byte, payloadLen)
+	binary.BigEndian.PutUint16(payload[:2], uint16(len(failure)))
+	copy(payload[2:], failure)
+
+	packet := make([]byte, 0, PacketLen)
+	packet = append(packet, hmac256(hmac256(umTag, e.sharedSecret[:]),
+		payload)...)
+	packet = append(packet, payload...)
+
+	return xorStream(e.sharedSecret, packet)
+}
+
+// IntermediateEncrypt adds this hop's layer of encryption to a failure
+// packet handed back by the next hop.
+func (e *ErrorEncrypter) IntermediateEncrypt(packet []byte) ([]byte, error) {
+	if len(packet) != PacketLen {
+		return nil, fmt.Errorf("failure packet is %v bytes, expected "+
+			"%v", len(packet), PacketLen)
+	}
+
+	return xorStream(e.sharedSecret, packet)
+}
+
+// DecryptedError is a failure packet decrypted by the sender.
+type DecryptedError struct {
+	// SenderIdx is the index within the route of the hop the failure
+	// arose at.
+	SenderIdx int
+
+	// Failure is the failure the hop handed back.
+	Failure []byte
+}
+
+// ErrorDecrypter is held by the sender for each HTLC it sends out, to
+// decrypt the failure of the HTLC.
+type ErrorDecrypter struct {
+	sharedSecrets []SharedSecret
+}
+
+// NewErrorDecrypter creates a decrypter of the failures of an HTLC sent
+// over a route whose hops the sender shares the passed secrets with.
+func NewErrorDecrypter(secrets []SharedSecret) *ErrorDecrypter {
+	return &ErrorDecrypter{sharedSecrets: secrets}
+}
+
+// DecryptError peels the layers of encryption off the failure packet, one
+// hop at a time, until the hop the failure arose at is found.
+func (d *ErrorDecrypter) DecryptError(packet []byte) (*DecryptedError, error) {
+	if len(packet) != PacketLen {
+		return nil, ErrUnreadableFailure
+	}
+
+	for i, ss := range d.sharedSecrets {
+		var err error
+		packet, err = xorStream(ss, packet)
+		if err != nil {
+			return nil, err
+		}
+
+		mac := packet[:fastsha256.Size]
+		payload := packet[fastsha256.Size:]
+		expectedMac := hmac256(hmac256(umTag, ss[:]), payload)
+		if !hmac.Equal(mac, expectedMac) {
+			continue
+		}
+
+		failureLen := binary.BigEndian.Uint16(payload[:2])
+		if int(failureLen) > MaxFailureLen {
+			return nil, ErrUnreadableFailure
+		}
+
+		return &DecryptedError{
+			SenderIdx: i,
+			Failure:   payload[2 : 2+failureLen],
+		}, nil
+	}
+
+	return nil, ErrUnreadableFailure
+}
+
+// xorStream encrypts, or decrypts, the packet under the ammag key derived
+// from the shared secret. As each key only ever encrypts a single packet,
+// the nonce is zero.
+func xorStream(ss SharedSecret, packet []byte) ([]byte, error) {
+	var nonce [8]byte
+	stream, err := chacha20.New(hmac256(ammagTag, ss[:]), nonce[:])
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, len(packet))
+	stream.XORKeyStream(out, packet)
+	return out, nil
+}
+
+// hmac256 returns HMAC256(key, msg).
+func hmac256(key, msg []byte) []byte {
+	mac := hmac.New(fastsha256.New, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,98 @@
+package onionerr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+func TestFailurePacket(t *testing.T) {
+	var (
+		nodeKeys []*btcec.PrivateKey
+		hops     []*btcec.PublicKey
+	)
+	for i := byte(1); i <= 4; i++ {
+		priv, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{i}, 32))
+		nodeKeys = append(nodeKeys, priv)
+		hops = append(hops, pub)
+	}
+
+	sessionKey, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{9}, 32))
+	secrets := GenerateSharedSecrets(sessionKey, hops)
+
+	// Each hop, handed the ephemeral key by the hop before it, derives
+	// the secret it shares with the sender.
+	encrypters := make([]*ErrorEncrypter, len(hops))
+	ephemeralKey := sessionKey.PubKey()
+	for i, nodeKey := range nodeKeys {
+		ss, nextKey, err := DeriveSharedSecret(
+			keychain.NewPrivKeySigner(nodeKey), ephemeralKey)
+		if err != nil {
+			t.Fatalf("unable to derive shared secret: %v", err)
+		}
+		if ss != secrets[i] {
+			t.Fatalf("hop %v derived the wrong shared secret", i)
+		}
+		encrypters[i] = NewErrorEncrypter(ss)
+		ephemeralKey = nextKey
+	}
+
+	decrypter := NewErrorDecrypter(secrets)
+
+	// The failure of each hop is attributed to it once it has made its
+	// way back through the hops before it.
+	failure := []byte("temporary channel failure")
+	for failingIdx := range hops {
+		packet, err := encrypters[failingIdx].EncryptError(failure)
+		if err != nil {
+			t.Fatalf("unable to encrypt failure: %v", err)
+		}
+		if len(packet) != PacketLen {
+			t.Fatalf("expected packet of %v bytes, got %v",
+				PacketLen, len(packet))
+		}
+		for i := failingIdx - 1; i >= 0; i-- {
+			packet, err = encrypters[i].IntermediateEncrypt(packet)
+			if err != nil {
+				t.Fatalf("unable to wrap failure: %v", err)
+			}
+		}
+
+		decrypted, err := decrypter.DecryptError(packet)
+		if err != nil {
+			t.Fatalf("unable to decrypt failure: %v", err)
+		}
+		if decrypted.SenderIdx != failingIdx {
+			t.Fatalf("failure attributed to hop %v, expected %v",
+				decrypted.SenderIdx, failingIdx)
+		}
+		if !bytes.Equal(decrypted.Failure, failure) {
+			t.Fatalf("expected failure %q, got %q", failure,
+				decrypted.Failure)
+		}
+	}
+
+	// A packet garbled along the way can't be attributed to any hop.
+	packet, err := encrypters[2].EncryptError(failure)
+	if err != nil {
+		t.Fatalf("unable to encrypt failure: %v", err)
+	}
+	packet[0] ^= 1
+	for i := 1; i >= 0; i-- {
+		packet, _ = encrypters[i].IntermediateEncrypt(packet)
+	}
+	_, err = decrypter.DecryptError(packet)
+	if err != ErrUnreadableFailure {
+		t.Fatalf("expected ErrUnreadableFailure, got %v", err)
+	}
+
+	if _, err := encrypters[0].EncryptError(
+		make([]byte, MaxFailureLen+1)); err == nil {
+
+		t.Fatalf("failure exceeding the max length was encrypted")
+	}
+}
@@ -0,0 +1,799 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/fastsha256"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionerr"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+var (
+	// ErrPaymentTimeout is returned when a payment fails to succeed
+	// before its timeout passes.
+	ErrPaymentTimeout = errors.New("payment timed out")
+
+	// ErrPaymentRejected is returned when the destination of a payment
+	// fails its HTLC for a reason retrying won't fix, such as not knowing
+	// the payment hash, or being paid the wrong amount.
+	ErrPaymentRejected = errors.New("payment rejected by destination")
+
+	// ErrMultiHopUnsupported is returned when the only route found for a
+	// payment passes through other nodes. Until the forwarding
+	// instructions of each hop are wrapped within an onion, only the
+	// peers we have channels with are able to make sense of our HTLCs.
+	ErrMultiHopUnsupported = errors.New("multi-hop payments not yet " +
+		"supported")
+
+	// ErrHTLCsUncommitted is returned when offering an HTLC to a peer.
+	// Until the peer drives the update protocol of our channel, the HTLC
+	// can't be locked into its commitments, so it isn't sent at all.
+	ErrHTLCsUncommitted = errors.New("htlcs can't yet be committed to " +
+		"channels")
+
+	// ErrUnknownHTLC is returned when an HTLC is resolved by a peer we
+	// didn't send it to, or over a channel it wasn't sent over.
+	ErrUnknownHTLC = errors.New("htlc not sent to peer over channel")
+)
+
+const (
+	// paymentFinalCltvDelta is the number of blocks past the current
+	// height the HTLCs of our payments expire at once they reach their
+	// destination.
+	paymentFinalCltvDelta = 144
+
+	// attemptIndexBits is the number of low bits of an HTLC key holding
+	// the index of the attempt within its payment. The remaining bits hold
+	// the sequence number of the payment, so that the payment an HTLC
+	// belongs to can be found even across restarts.
+	attemptIndexBits = 16
+
+	// htlcContractType marks an HTLC as redeemable with a single preimage.
+	htlcContractType = 0x11
+
+	// defaultPaymentTimeout is how long a payment is given to succeed if
+	// the sender doesn't set a timeout. Once it passes, no further
+	// attempts are made.
+	defaultPaymentTimeout = 60 * time.Second
+
+	// paymentRetryDelay is how long we wait after an attempt fails before
+	// making the next.
+	paymentRetryDelay = time.Second
+
+	// minShardAmt is the smallest part a payment may be split into.
+	minShardAmt = lnwire.MilliSatoshi(10000)
+)
+
+// paymentRequest is a payment to be sent, along with the limits it must be
+// completed within.
+type paymentRequest struct {
+	dest        *btcec.PublicKey
+	amt         lnwire.MilliSatoshi
+	paymentHash [20]byte
+
+	// paymentSecret is the payment secret of the invoice being paid,
+	// which the destination requires the HTLCs of the payment to carry.
+	paymentSecret [32]byte
+
+	// timeout is how long the payment may be attempted for. If zero,
+	// defaultPaymentTimeout is used.
+	timeout time.Duration
+
+	// feeLimit is the most the payment may pay in fees, across all of its
+	// parts. routing.NoFeeLimit places no limit.
+	feeLimit lnwire.MilliSatoshi
+
+	// maxParts is the most HTLCs the payment may be split into at once.
+	// If zero, the payment isn't split.
+	maxParts uint32
+
+	// cltvLimit is the furthest the time lock of any HTLC of the payment
+	// may be from the current height. If zero, there's no limit.
+	cltvLimit uint32
+
+	// outgoingChanIDs, if set, are the only channels of ours the payment
+	// may leave over.
+	outgoingChanIDs map[lnwire.ShortChannelID]struct{}
+}
+
+// circuitKey identifies an HTLC of ours by the peer, and the channel, it was
+// offered over, along with its key within the channel. Only the peer the HTLC
+// was offered to may resolve it.
+type circuitKey struct {
+	peer    [33]byte
+	chanID  lnwire.ShortChannelID
+	htlcKey uint64
+}
+
+// newCircuitKey returns the key of the HTLC offered to the peer over the
+// channel.
+func newCircuitKey(peer *btcec.PublicKey, chanID lnwire.ShortChannelID,
+	htlcKey uint64) circuitKey {
+
+	key := circuitKey{chanID: chanID, htlcKey: htlcKey}
+	copy(key.peer[:], peer.SerializeCompressed())
+	return key
+}
+
+// htlcResult is the outcome of an HTLC of a payment, handed to the
+// lifecycle of the payment.
+type htlcResult struct {
+	htlcKey uint64
+
+	// preimage is set if the HTLC was settled, otherwise reason holds why
+	// it failed.
+	preimage *[20]byte
+	reason   string
+
+	// failurePacket, if set, is the encrypted failure handed back by the
+	// hop the HTLC failed at.
+	failurePacket []byte
+}
+
+// htlcCircuit is an HTLC of a payment in flight, along with the decrypter
+// of the failure it may be handed back.
+type htlcCircuit struct {
+	route     *routing.Route
+	decrypter *onionerr.ErrorDecrypter
+}
+
+// pendingPayment is a payment we're awaiting the outcome of. Each send of the
+// payment hash made while the payment is in flight waits upon the same
+// outcome, rather than sending out another HTLC.
+type pendingPayment struct {
+	req *paymentRequest
+
+	// results hands the outcome of each HTLC of the payment to its
+	// lifecycle.
+	results chan *htlcResult
+
+	// preimage and err are only to be read once done is closed.
+	preimage [20]byte
+	err      error
+	done     chan struct{}
+}
+
+// paymentRegistry drives our outgoing payments through their lifecycle,
+// persisting each attempt as it's made, and ensuring each payment hash is
+// only ever paid once.
+type paymentRegistry struct {
+	shutdown int32 // To be used atomically.
+
+	cdb *channeldb.DB
+
+	// sendHTLC hands the HTLC to the peer with the passed public key, to be
+	// offered over our channel with them.
+	sendHTLC func(*btcec.PublicKey, *lnwire.HTLCAddRequest) error
+
+	// findRoute finds a route to the destination for the amount, within
+	// the passed restrictions.
+	findRoute func(*btcec.PublicKey, lnwire.MilliSatoshi,
+		*routing.RestrictParams) (*routing.Route, error)
+
+	// pending holds the payments in flight, keyed by payment hash. A
+	// payment is added while holding mtx, along with its persisted state,
+	// so concurrent sends of the same hash can't both be dispatched. Each
+	// HTLC in flight is indexed within htlcs by its circuit key, so its
+	// outcome can be handed to its payment, but only by the peer it was
+	// offered to.
+	mtx     sync.Mutex
+	pending map[[20]byte]*pendingPayment
+	htlcs   map[circuitKey]*pendingPayment
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newPaymentRegistry creates a new payment registry backed by the passed
+// database.
+func newPaymentRegistry(cdb *channeldb.DB,
+	sendHTLC func(*btcec.PublicKey, *lnwire.HTLCAddRequest) error,
+	findRoute func(*btcec.PublicKey, lnwire.MilliSatoshi,
+		*routing.RestrictParams) (*routing.Route, error)) *paymentRegistry {
+
+	return &paymentRegistry{
+		cdb:       cdb,
+		sendHTLC:  sendHTLC,
+		findRoute: findRoute,
+		pending:   make(map[[20]byte]*pendingPayment),
+		htlcs:     make(map[circuitKey]*pendingPayment),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Stop releases all callers awaiting the outcome of a payment, and waits for
+// the lifecycle of each payment to exit. Payments still in flight are
+// resolved as their HTLCs are, once we restart.
+func (p *paymentRegistry) Stop() error {
+	if atomic.AddInt32(&p.shutdown, 1) != 1 {
+		return nil
+	}
+
+	close(p.quit)
+	p.wg.Wait()
+
+	return nil
+}
+
+// SendPayment pays the destination, returning the preimage of the payment
+// hash once the payment succeeds. Only destinations we have a channel with
+// are able to be paid for now, others failing with ErrMultiHopUnsupported.
+// Until HTLCs are committed to our channels, our peers refuse to send them,
+// failing each payment with ErrHTLCsUncommitted, so no payment is reported
+// as succeeded without its HTLC being committed.
+// Sending is idempotent: should the payment hash already be in flight, the
+// call attaches to the outstanding payment, and waits for its outcome,
+// rather than sending it twice. A payment which failed may be retried, while
+// one which succeeded is refused with channeldb.ErrAlreadyPaid.
+func (p *paymentRegistry) SendPayment(req *paymentRequest) ([20]byte, error) {
+	p.mtx.Lock()
+	pending, ok := p.pending[req.paymentHash]
+	if !ok {
+		payment := &channeldb.Payment{
+			PaymentHash:  req.paymentHash,
+			Amount:       req.amt,
+			CreationTime: time.Now(),
+		}
+
+		// If the payment is persisted as in-flight, yet isn't pending,
+		// it was sent before we restarted, and we can only wait for
+		// its HTLCs to be resolved.
+		if _, err := p.cdb.InitPayment(payment); err != nil {
+			p.mtx.Unlock()
+			return [20]byte{}, err
+		}
+
+		pending = &pendingPayment{
+			req:     req,
+			results: make(chan *htlcResult),
+			done:    make(chan struct{}),
+		}
+		p.pending[req.paymentHash] = pending
+		p.mtx.Unlock()
+
+		p.wg.Add(1)
+		go p.paymentLifecycle(payment, pending)
+	} else {
+		p.mtx.Unlock()
+
+		// A send of the same hash to another destination, or for
+		// another amount, isn't a retry of the payment in flight.
+		if !pending.req.dest.IsEqual(req.dest) ||
+			pending.req.amt != req.amt {
+
+			return [20]byte{}, channeldb.ErrPaymentInFlight
+		}
+	}
+
+	select {
+	case <-pending.done:
+		return pending.preimage, pending.err
+	case <-p.quit:
+		return [20]byte{}, ErrServerShuttingDown
+	}
+}
+
+// IsPending returns true if the payment hash is in flight, such that sending
+// it attaches to the outstanding payment.
+func (p *paymentRegistry) IsPending(paymentHash [20]byte) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	_, ok := p.pending[paymentHash]
+	return ok
+}
+
+// paymentLifecycle sends out the HTLCs of the payment, splitting it into as
+// many as maxParts, and retrying the parts which fail, until it either
+// succeeds or its limits are exhausted. Once the timeout passes, or the
+// destination rejects the payment, no further attempts are made, and the
+// payment fails as soon as it has no HTLCs left in flight.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *paymentRegistry) paymentLifecycle(payment *channeldb.Payment,
+	pending *pendingPayment) {
+
+	defer p.wg.Done()
+
+	req := pending.req
+	timeout := req.timeout
+	if timeout == 0 {
+		timeout = defaultPaymentTimeout
+	}
+	maxParts := req.maxParts
+	if maxParts == 0 {
+		maxParts = 1
+	}
+
+	var (
+		timedOut = time.After(timeout)
+		expired  bool
+
+		// rejected is set once the destination rejects the payment.
+		rejected bool
+
+		// retry is set while waiting to retry a failed attempt.
+		retry <-chan time.Time
+
+		// The circuit of each HTLC in flight, keyed by HTLC key, along
+		// with the sum of the amounts they carry.
+		inFlight    = make(map[uint64]*htlcCircuit)
+		amtInFlight lnwire.MilliSatoshi
+
+		// shardAmt is the largest part we'll attempt. It's halved each
+		// time a part fails.
+		shardAmt = req.amt
+
+		lastErr error
+	)
+
+	for {
+		// Send out parts until the full amount is in flight, unless
+		// we're out of time, or waiting to retry.
+		for !expired && !rejected && retry == nil &&
+			amtInFlight < req.amt && uint32(len(inFlight)) < maxParts {
+
+			// The last part we may send carries all that remains.
+			remaining := req.amt - amtInFlight
+			lastPart := uint32(len(inFlight))+1 == maxParts
+			amt := shardAmt
+			if amt > remaining || lastPart {
+				amt = remaining
+			}
+
+			// Only direct routes are sent, which pay no fees, so
+			// the fee limit isn't shared out between the parts.
+			route, err := p.findRoute(req.dest, amt,
+				&routing.RestrictParams{
+					FeeLimit:           req.feeLimit,
+					CltvLimit:          req.cltvLimit,
+					OutgoingChannelIDs: req.outgoingChanIDs,
+				})
+			if err != nil {
+				lastErr = err
+
+				// A smaller part may find a route where the
+				// larger didn't.
+				if !lastPart && amt/2 >= minShardAmt {
+					shardAmt = amt / 2
+					continue
+				}
+				break
+			}
+			if len(route.Hops) > 1 {
+				lastErr = ErrMultiHopUnsupported
+				break
+			}
+
+			// Each attempt is sent with a fresh session key, from
+			// which the secrets shared with the hops of its route,
+			// and thus the decrypter of its failure, are derived.
+			sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+			if err != nil {
+				p.failPayment(payment, err)
+				return
+			}
+			hopKeys := make([]*btcec.PublicKey, 0, len(route.Hops))
+			for _, hop := range route.Hops {
+				hopKeys = append(hopKeys, hop.PubKey)
+			}
+			secrets := onionerr.GenerateSharedSecrets(sessionKey,
+				hopKeys)
+			decrypter := onionerr.NewErrorDecrypter(secrets)
+
+			attempt, err := p.sendAttempt(payment,
+				req.paymentSecret, route, sessionKey)
+			if err == nil {
+				inFlight[attempt.HTLCKey] = &htlcCircuit{
+					route:     route,
+					decrypter: decrypter,
+				}
+				amtInFlight += amt
+				continue
+			}
+
+			// Retrying an HTLC which can't be committed is
+			// pointless.
+			lastErr = err
+			if attempt == nil || err == ErrHTLCsUncommitted {
+				p.failPayment(payment, err)
+				return
+			}
+			retry = time.After(paymentRetryDelay)
+		}
+
+		// With nothing in flight, and nothing left to attempt, the
+		// payment has failed.
+		if len(inFlight) == 0 && retry == nil {
+			switch {
+			case expired && !rejected:
+				p.failPayment(payment, ErrPaymentTimeout)
+				return
+			case lastErr != nil:
+				p.failPayment(payment, lastErr)
+				return
+			}
+		}
+
+		select {
+		case result := <-pending.results:
+			circuit, ok := inFlight[result.htlcKey]
+			if !ok {
+				continue
+			}
+			delete(inFlight, result.htlcKey)
+			amtInFlight -= circuit.route.FinalHop().AmtToForward
+
+			attempt := findAttempt(payment, result.htlcKey)
+
+			attempt.ResolveTime = time.Now()
+			if result.preimage != nil {
+				attempt.Status = channeldb.PaymentSucceeded
+				payment.Status = channeldb.PaymentSucceeded
+				err := p.cdb.UpdatePayment(payment)
+				p.resolve(payment, *result.preimage, err)
+				return
+			}
+
+			reason, err := p.processFailure(circuit, result)
+
+			attempt.Status = channeldb.PaymentFailed
+			attempt.FailureReason = reason
+			if err := p.cdb.UpdatePayment(payment); err != nil {
+				p.resolve(payment, [20]byte{}, err)
+				return
+			}
+
+			// Once the destination rejects the payment, the
+			// parts still in flight are left to fail along with
+			// it.
+			if err != nil {
+				lastErr = err
+				rejected = true
+				continue
+			}
+
+			lastErr = errors.New(reason)
+			if maxParts > 1 && shardAmt/2 >= minShardAmt {
+				shardAmt /= 2
+			}
+			retry = time.After(paymentRetryDelay)
+
+		case <-retry:
+			retry = nil
+
+		case <-timedOut:
+			timedOut = nil
+			expired = true
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// sendAttempt records a new attempt to complete the payment over the route,
+// then sends out its HTLC, carrying the public key of the session key, and
+// the payment secret, to the first hop. If the attempt couldn't be recorded,
+// it's nil along with the error, and the payment can't proceed. Otherwise,
+// should the HTLC fail to be sent, the attempt is returned as failed.
+func (p *paymentRegistry) sendAttempt(payment *channeldb.Payment,
+	paymentSecret [32]byte, route *routing.Route,
+	sessionKey *btcec.PrivateKey) (*channeldb.PaymentAttempt, error) {
+
+	attemptIndex := uint64(len(payment.Attempts))
+	if attemptIndex >= 1<<attemptIndexBits {
+		return nil, fmt.Errorf("too many payment attempts")
+	}
+
+	hopIDs := make([][wire.HashSize]byte, 0, len(route.Hops))
+	for _, hop := range route.Hops {
+		hopIDs = append(hopIDs, routeHopID(hop.PubKey))
+	}
+
+	attempt := &channeldb.PaymentAttempt{
+		HTLCKey:     payment.PaymentID<<attemptIndexBits | attemptIndex,
+		Route:       hopIDs,
+		Amount:      route.FinalHop().AmtToForward,
+		AttemptTime: time.Now(),
+		Status:      channeldb.PaymentInFlight,
+		Fee:         route.TotalFees,
+		ChanID:      route.FirstHop().ChannelID,
+		Expiry:      route.TotalTimeLock,
+	}
+	payment.Attempts = append(payment.Attempts, attempt)
+	if err := p.cdb.UpdatePayment(payment); err != nil {
+		return nil, err
+	}
+
+	// Only routes direct to the destination are sent, so rather than an
+	// onion, the HTLC carries the final hop payload itself.
+	var b bytes.Buffer
+	payload := &lnwire.FinalHopPayload{
+		PaymentSecret: paymentSecret,
+		TotalAmount:   payment.Amount,
+	}
+	if err := payload.Encode(&b); err != nil {
+		return nil, err
+	}
+	paymentHash := payment.PaymentHash
+	htlc := &lnwire.HTLCAddRequest{
+		HTLCKey:          lnwire.HTLCKey(attempt.HTLCKey),
+		Expiry:           route.TotalTimeLock,
+		Amount:           route.TotalAmount,
+		ContractType:     htlcContractType,
+		HashType:         lnwire.HTLCHashTypeHash160,
+		RedemptionHashes: []*[20]byte{&paymentHash},
+		EphemeralKey:     sessionKey.PubKey(),
+		Blob:             b.Bytes(),
+	}
+
+	// The HTLC is indexed before it's sent, so its outcome can't arrive
+	// before we're able to route it to the payment.
+	circuit := newCircuitKey(route.FirstHop().PubKey,
+		route.FirstHop().ChannelID, attempt.HTLCKey)
+	p.mtx.Lock()
+	p.htlcs[circuit] = p.pending[payment.PaymentHash]
+	p.mtx.Unlock()
+
+	err := p.sendHTLC(route.FirstHop().PubKey, htlc)
+	if err == nil {
+		return attempt, nil
+	}
+
+	p.mtx.Lock()
+	delete(p.htlcs, circuit)
+	p.mtx.Unlock()
+
+	attempt.Status = channeldb.PaymentFailed
+	attempt.FailureReason = err.Error()
+	attempt.ResolveTime = time.Now()
+	if dbErr := p.cdb.UpdatePayment(payment); dbErr != nil {
+		return nil, dbErr
+	}
+
+	return attempt, err
+}
+
+// SettleHTLC marks the payment the HTLC was sent out for as succeeded,
+// handing the preimage to all callers awaiting the payment. ErrUnknownHTLC is
+// returned if the HTLC wasn't offered to the peer over the channel.
+func (p *paymentRegistry) SettleHTLC(circuit circuitKey,
+	preimage [20]byte) error {
+
+	result := &htlcResult{htlcKey: circuit.htlcKey, preimage: &preimage}
+	if p.deliverResult(circuit, result) {
+		return nil
+	}
+
+	// The payment isn't in flight within this process, so the HTLC was
+	// sent out before we restarted, or is a part of a payment which has
+	// already succeeded.
+	payment, attempt, err := p.fetchAttempt(circuit)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(btcutil.Hash160(preimage[:]), payment.PaymentHash[:]) {
+		return fmt.Errorf("preimage %x doesn't match payment hash %x",
+			preimage[:], payment.PaymentHash[:])
+	}
+
+	attempt.Status = channeldb.PaymentSucceeded
+	attempt.ResolveTime = time.Now()
+	payment.Status = channeldb.PaymentSucceeded
+
+	return p.cdb.UpdatePayment(payment)
+}
+
+// FailHTLC marks the attempt the HTLC was sent out for as failed, for the
+// passed reason. As only direct routes are sent, an encrypted failure packet
+// can only have arisen at the destination, which it's decrypted from. The
+// payment is retried, unless its limits are exhausted. ErrUnknownHTLC is
+// returned if the HTLC wasn't offered to the peer over the channel.
+func (p *paymentRegistry) FailHTLC(circuit circuitKey, reason string,
+	failurePacket []byte) error {
+
+	result := &htlcResult{
+		htlcKey:       circuit.htlcKey,
+		reason:        reason,
+		failurePacket: failurePacket,
+	}
+	if p.deliverResult(circuit, result) {
+		return nil
+	}
+
+	payment, attempt, err := p.fetchAttempt(circuit)
+	if err != nil {
+		return err
+	}
+
+	attempt.Status = channeldb.PaymentFailed
+	attempt.FailureReason = reason
+	attempt.ResolveTime = time.Now()
+
+	// Without a lifecycle to retry it, the payment fails once none of its
+	// HTLCs remain in flight.
+	if payment.Status == channeldb.PaymentInFlight {
+		payment.Status = channeldb.PaymentFailed
+		for _, a := range payment.Attempts {
+			if a.Status == channeldb.PaymentInFlight {
+				payment.Status = channeldb.PaymentInFlight
+				break
+			}
+		}
+	}
+
+	return p.cdb.UpdatePayment(payment)
+}
+
+// processFailure decrypts the failure of the HTLC. As our routes lead
+// straight to the destination, only the destination could have failed it.
+// The reason the HTLC failed is returned, along with ErrPaymentRejected
+// should the destination have rejected the payment.
+func (p *paymentRegistry) processFailure(circuit *htlcCircuit,
+	result *htlcResult) (string, error) {
+
+	// Without the session key of the attempt, which isn't persisted, the
+	// failure of an HTLC sent before a restart can't be decrypted.
+	if result.failurePacket == nil || circuit.decrypter == nil {
+		return result.reason, nil
+	}
+
+	// A packet the destination doesn't authenticate was garbled on its
+	// way back to us.
+	decrypted, err := circuit.decrypter.DecryptError(result.failurePacket)
+	if err != nil {
+		return fmt.Sprintf("%v: %v", result.reason, err), nil
+	}
+
+	failure, err := lnwire.ParseFailureMessage(decrypted.Failure)
+	if err != nil {
+		return fmt.Sprintf("invalid failure from destination: %v",
+			err), nil
+	}
+	reason := fmt.Sprintf("%v at destination (%x)", failure.Code,
+		circuit.route.FinalHop().PubKey.SerializeCompressed())
+
+	// The destination failing the HTLC is final, unless it merely gave
+	// up waiting for the rest of the parts of the payment.
+	if failure.Code == lnwire.CodeMPPTimeout {
+		return reason, nil
+	}
+	return reason, ErrPaymentRejected
+}
+
+// deliverResult hands the outcome of the HTLC to the lifecycle of its
+// payment, returning false if the payment has no lifecycle running, or the
+// HTLC wasn't offered over the circuit.
+func (p *paymentRegistry) deliverResult(circuit circuitKey,
+	result *htlcResult) bool {
+
+	p.mtx.Lock()
+	pending, ok := p.htlcs[circuit]
+	if ok {
+		delete(p.htlcs, circuit)
+	}
+	p.mtx.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	// A forged preimage is of no use to the payment, and mustn't count
+	// as the outcome of its HTLC.
+	if result.preimage != nil {
+		hash := btcutil.Hash160(result.preimage[:])
+		if !bytes.Equal(hash, pending.req.paymentHash[:]) {
+			result.reason = fmt.Sprintf("invalid preimage %x",
+				result.preimage[:])
+			result.preimage = nil
+		}
+	}
+
+	select {
+	case pending.results <- result:
+		return true
+	case <-pending.done:
+		return false
+	case <-p.quit:
+		return true
+	}
+}
+
+// fetchAttempt returns the attempt the HTLC was sent out for, along with its
+// payment. ErrUnknownHTLC is returned if the attempt wasn't sent to the peer
+// over the channel of the circuit.
+func (p *paymentRegistry) fetchAttempt(
+	circuit circuitKey) (*channeldb.Payment, *channeldb.PaymentAttempt,
+	error) {
+
+	htlcKey := circuit.htlcKey
+	payment, err := p.cdb.FetchPaymentByID(htlcKey >> attemptIndexBits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attempt := findAttempt(payment, htlcKey)
+	if attempt == nil {
+		return nil, nil, fmt.Errorf("no payment attempt with htlc "+
+			"key %v", htlcKey)
+	}
+
+	// The route of the attempt records its first hop by ID, which the
+	// peer resolving the HTLC must match.
+	peerID := fastsha256.Sum256(circuit.peer[:])
+	if len(attempt.Route) == 0 || attempt.Route[0] != peerID ||
+		attempt.ChanID != circuit.chanID {
+
+		return nil, nil, ErrUnknownHTLC
+	}
+
+	return payment, attempt, nil
+}
+
+// findAttempt returns the attempt of the payment the HTLC was sent out for,
+// or nil if there's none.
+func findAttempt(payment *channeldb.Payment,
+	htlcKey uint64) *channeldb.PaymentAttempt {
+
+	for _, attempt := range payment.Attempts {
+		if attempt.HTLCKey == htlcKey {
+			return attempt
+		}
+	}
+
+	return nil
+}
+
+// failPayment marks the payment as terminally failed, handing the error to
+// all callers awaiting the payment.
+func (p *paymentRegistry) failPayment(payment *channeldb.Payment, err error) {
+	payment.Status = channeldb.PaymentFailed
+
+	if dbErr := p.cdb.UpdatePayment(payment); dbErr != nil {
+		err = dbErr
+	}
+	p.resolve(payment, [20]byte{}, err)
+}
+
+// resolve hands the outcome of the payment to all callers awaiting it. The
+// HTLCs of the payment still in flight are no longer routed to it, and are
+// instead resolved directly within the database.
+func (p *paymentRegistry) resolve(payment *channeldb.Payment,
+	preimage [20]byte, err error) {
+
+	p.mtx.Lock()
+	pending, ok := p.pending[payment.PaymentHash]
+	delete(p.pending, payment.PaymentHash)
+	for circuit, htlcPayment := range p.htlcs {
+		if htlcPayment == pending {
+			delete(p.htlcs, circuit)
+		}
+	}
+	p.mtx.Unlock()
+
+	if !ok {
+		return
+	}
+
+	pending.preimage = preimage
+	pending.err = err
+	close(pending.done)
+}
+
+// routeHopID returns the ID a node is recorded under within the route of a
+// payment attempt.
+func routeHopID(pubKey *btcec.PublicKey) [wire.HashSize]byte {
+	return fastsha256.Sum256(pubKey.SerializeCompressed())
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionerr"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+var (
+	// testDestKey is the identity key of the destination of the payments
+	// sent within the tests.
+	testDestKey, testDestPub = btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{2}, 32))
+
+	// testHopPub is the identity key of a node between us and the
+	// destination.
+	_, testHopPub = btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{3}, 32))
+
+	testPreimage    = [20]byte{1}
+	testPaymentHash = hash160(testPreimage)
+	testPaymentAmt  = lnwire.MilliSatoshi(100000)
+
+	errTestSend = errors.New("peer not connected")
+)
+
+// hash160 returns the payment hash of the preimage.
+func hash160(preimage [20]byte) [20]byte {
+	var hash [20]byte
+	copy(hash[:], btcutil.Hash160(preimage[:]))
+	return hash
+}
+
+// directRoute is a route over our channel with the destination.
+func directRoute() *routing.Route {
+	return &routing.Route{
+		TotalAmount:   testPaymentAmt,
+		TotalTimeLock: paymentFinalCltvDelta,
+		Hops: []*routing.Hop{
+			{
+				PubKey:           testDestPub,
+				AmtToForward:     testPaymentAmt,
+				OutgoingTimeLock: paymentFinalCltvDelta,
+			},
+		},
+	}
+}
+
+// multiHopRoute is a route reaching the destination through another node.
+func multiHopRoute() *routing.Route {
+	return &routing.Route{
+		TotalAmount:   testPaymentAmt + 1000,
+		TotalFees:     1000,
+		TotalTimeLock: paymentFinalCltvDelta + 40,
+		Hops: []*routing.Hop{
+			{
+				PubKey:           testHopPub,
+				AmtToForward:     testPaymentAmt,
+				OutgoingTimeLock: paymentFinalCltvDelta,
+			},
+			{
+				PubKey:           testDestPub,
+				ChannelID:        lnwire.ShortChannelID{BlockHeight: 1},
+				AmtToForward:     testPaymentAmt,
+				OutgoingTimeLock: paymentFinalCltvDelta,
+			},
+		},
+	}
+}
+
+// createTestPaymentRegistry creates a payment registry backed by a fresh
+// database, which finds the passed route to every destination, or none if
+// it's nil. Each HTLC the registry sends is handed over the returned
+// channel, and fails to send with sendErr if set.
+func createTestPaymentRegistry(t *testing.T, route *routing.Route,
+	sendErr error) (*paymentRegistry, <-chan *lnwire.HTLCAddRequest,
+	func()) {
+
+	cdb, cleanUp := createTestDB(t)
+
+	htlcs := make(chan *lnwire.HTLCAddRequest, 10)
+	registry := newPaymentRegistry(cdb,
+		func(_ *btcec.PublicKey, htlc *lnwire.HTLCAddRequest) error {
+			htlcs <- htlc
+			return sendErr
+		},
+		func(*btcec.PublicKey, lnwire.MilliSatoshi,
+			*routing.RestrictParams) (*routing.Route, error) {
+
+			if route == nil {
+				return nil, routing.ErrNoPathFound
+			}
+			return route, nil
+		})
+
+	return registry, htlcs, func() {
+		registry.Stop()
+		cleanUp()
+	}
+}
+
+// testCircuit returns the circuit key of the HTLC sent over the direct route
+// to the destination.
+func testCircuit(htlc *lnwire.HTLCAddRequest) circuitKey {
+	return newCircuitKey(testDestPub, lnwire.ShortChannelID{},
+		uint64(htlc.HTLCKey))
+}
+
+// nextHTLC returns the next HTLC the payment registry sends.
+func nextHTLC(t *testing.T,
+	htlcs <-chan *lnwire.HTLCAddRequest) *lnwire.HTLCAddRequest {
+
+	select {
+	case htlc := <-htlcs:
+		return htlc
+	case <-time.After(5 * time.Second):
+		t.Fatalf("htlc not sent")
+		return nil
+	}
+}
+
+// rejectHTLC fails the HTLC as the destination would, handing back the
+// failure encrypted to the sender.
+func rejectHTLC(t *testing.T, registry *paymentRegistry,
+	htlc *lnwire.HTLCAddRequest, code lnwire.FailCode) {
+
+	ss, _, err := onionerr.DeriveSharedSecret(
+		keychain.NewPrivKeySigner(testDestKey), htlc.EphemeralKey)
+	if err != nil {
+		t.Fatalf("unable to derive shared secret: %v", err)
+	}
+
+	var b bytes.Buffer
+	failure := &lnwire.FailureMessage{Code: code}
+	if err := failure.Encode(&b); err != nil {
+		t.Fatalf("unable to encode failure: %v", err)
+	}
+	packet, err := onionerr.NewErrorEncrypter(ss).EncryptError(b.Bytes())
+	if err != nil {
+		t.Fatalf("unable to encrypt failure: %v", err)
+	}
+
+	err = registry.FailHTLC(testCircuit(htlc), code.String(), packet)
+	if err != nil {
+		t.Fatalf("unable to fail htlc: %v", err)
+	}
+}
+
+// TestSendPayment asserts the outcome of a payment, both as returned to the
+// sender and as persisted, for each way the payment may end.
+func TestSendPayment(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   *routing.Route
+		sendErr error
+		timeout time.Duration
+
+		// resolve, if set, resolves the first HTLC the payment sends.
+		resolve func(*testing.T, *paymentRegistry,
+			*lnwire.HTLCAddRequest)
+
+		err    error
+		status channeldb.PaymentStatus
+	}{
+		{
+			name:  "settled",
+			route: directRoute(),
+			resolve: func(t *testing.T, r *paymentRegistry,
+				htlc *lnwire.HTLCAddRequest) {
+
+				err := r.SettleHTLC(testCircuit(htlc),
+					testPreimage)
+				if err != nil {
+					t.Fatalf("unable to settle htlc: %v", err)
+				}
+			},
+			status: channeldb.PaymentSucceeded,
+		},
+		{
+			name:  "rejected by destination",
+			route: directRoute(),
+			resolve: func(t *testing.T, r *paymentRegistry,
+				htlc *lnwire.HTLCAddRequest) {
+
+				rejectHTLC(t, r, htlc,
+					lnwire.CodeIncorrectPaymentDetails)
+			},
+			err:    ErrPaymentRejected,
+			status: channeldb.PaymentFailed,
+		},
+		{
+			name:   "multi-hop route",
+			route:  multiHopRoute(),
+			err:    ErrMultiHopUnsupported,
+			status: channeldb.PaymentFailed,
+		},
+		{
+			name:   "no route",
+			err:    routing.ErrNoPathFound,
+			status: channeldb.PaymentFailed,
+		},
+		{
+			name:    "htlcs uncommitted",
+			route:   directRoute(),
+			sendErr: ErrHTLCsUncommitted,
+			err:     ErrHTLCsUncommitted,
+			status:  channeldb.PaymentFailed,
+		},
+		{
+			name:    "send fails until timeout",
+			route:   directRoute(),
+			sendErr: errTestSend,
+			timeout: 100 * time.Millisecond,
+			err:     ErrPaymentTimeout,
+			status:  channeldb.PaymentFailed,
+		},
+	}
+
+	for _, test := range tests {
+		registry, htlcs, cleanUp := createTestPaymentRegistry(t,
+			test.route, test.sendErr)
+
+		type sendResult struct {
+			preimage [20]byte
+			err      error
+		}
+		results := make(chan *sendResult, 1)
+		go func() {
+			preimage, err := registry.SendPayment(&paymentRequest{
+				dest:        testDestPub,
+				amt:         testPaymentAmt,
+				paymentHash: testPaymentHash,
+				timeout:     test.timeout,
+			})
+			results <- &sendResult{preimage, err}
+		}()
+
+		if test.resolve != nil {
+			test.resolve(t, registry, nextHTLC(t, htlcs))
+		}
+
+		var result *sendResult
+		select {
+		case result = <-results:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%v: payment didn't complete", test.name)
+		}
+		if result.err != test.err {
+			t.Fatalf("%v: expected error %v, instead %v", test.name,
+				test.err, result.err)
+		}
+		if result.err == nil && result.preimage != testPreimage {
+			t.Fatalf("%v: expected preimage %x, instead %x",
+				test.name, testPreimage, result.preimage)
+		}
+
+		payment, err := registry.cdb.FetchPayment(testPaymentHash)
+		if err != nil {
+			t.Fatalf("%v: unable to fetch payment: %v", test.name,
+				err)
+		}
+		if payment.Status != test.status {
+			t.Fatalf("%v: expected payment %v, instead %v",
+				test.name, test.status, payment.Status)
+		}
+
+		cleanUp()
+	}
+}
+
+// TestSendPaymentPayload asserts the HTLC sent over a direct route carries
+// the final hop payload, as there's no onion to carry it instead.
+func TestSendPaymentPayload(t *testing.T) {
+	registry, htlcs, cleanUp := createTestPaymentRegistry(t,
+		directRoute(), nil)
+	defer cleanUp()
+
+	secret := [32]byte{1, 2, 3}
+	go registry.SendPayment(&paymentRequest{
+		dest:          testDestPub,
+		amt:           testPaymentAmt,
+		paymentHash:   testPaymentHash,
+		paymentSecret: secret,
+	})
+
+	htlc := nextHTLC(t, htlcs)
+	if htlc.Amount != testPaymentAmt {
+		t.Fatalf("expected htlc of %v, instead %v", testPaymentAmt,
+			htlc.Amount)
+	}
+	if *htlc.RedemptionHashes[0] != testPaymentHash {
+		t.Fatalf("htlc locked to wrong payment hash %x",
+			htlc.RedemptionHashes[0][:])
+	}
+
+	payload := &lnwire.FinalHopPayload{}
+	if err := payload.Decode(bytes.NewReader(htlc.Blob)); err != nil {
+		t.Fatalf("unable to decode payload: %v", err)
+	}
+	if payload.PaymentSecret != secret ||
+		payload.TotalAmount != testPaymentAmt {
+
+		t.Fatalf("htlc carries wrong payload: %v", payload)
+	}
+}
+
+// TestSendPaymentOnce asserts a payment hash in flight can't be sent for
+// another amount, and that one which succeeded can't be sent again.
+func TestSendPaymentOnce(t *testing.T) {
+	registry, htlcs, cleanUp := createTestPaymentRegistry(t,
+		directRoute(), nil)
+	defer cleanUp()
+
+	req := &paymentRequest{
+		dest:        testDestPub,
+		amt:         testPaymentAmt,
+		paymentHash: testPaymentHash,
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := registry.SendPayment(req)
+		errChan <- err
+	}()
+	htlc := nextHTLC(t, htlcs)
+
+	if !registry.IsPending(testPaymentHash) {
+		t.Fatalf("payment not pending")
+	}
+	_, err := registry.SendPayment(&paymentRequest{
+		dest:        testDestPub,
+		amt:         testPaymentAmt + 1,
+		paymentHash: testPaymentHash,
+	})
+	if err != channeldb.ErrPaymentInFlight {
+		t.Fatalf("expected ErrPaymentInFlight, instead %v", err)
+	}
+
+	err = registry.SettleHTLC(testCircuit(htlc), testPreimage)
+	if err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("payment failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("payment didn't complete")
+	}
+
+	if _, err := registry.SendPayment(req); err != channeldb.ErrAlreadyPaid {
+		t.Fatalf("expected ErrAlreadyPaid, instead %v", err)
+	}
+}
+
+// TestSettleHTLCUnknownCircuit asserts an HTLC may only be resolved by the
+// peer it was offered to, over the channel it was offered over, both while
+// its payment is in flight and once it has completed.
+func TestSettleHTLCUnknownCircuit(t *testing.T) {
+	registry, htlcs, cleanUp := createTestPaymentRegistry(t,
+		directRoute(), nil)
+	defer cleanUp()
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := registry.SendPayment(&paymentRequest{
+			dest:        testDestPub,
+			amt:         testPaymentAmt,
+			paymentHash: testPaymentHash,
+		})
+		errChan <- err
+	}()
+	htlc := nextHTLC(t, htlcs)
+
+	circuits := []circuitKey{
+		newCircuitKey(testHopPub, lnwire.ShortChannelID{},
+			uint64(htlc.HTLCKey)),
+		newCircuitKey(testDestPub, lnwire.ShortChannelID{BlockHeight: 1},
+			uint64(htlc.HTLCKey)),
+	}
+	for _, circuit := range circuits {
+		err := registry.SettleHTLC(circuit, testPreimage)
+		if err != ErrUnknownHTLC {
+			t.Fatalf("expected ErrUnknownHTLC settling over %v, "+
+				"instead %v", circuit, err)
+		}
+		err = registry.FailHTLC(circuit, "htlc rejected by peer", nil)
+		if err != ErrUnknownHTLC {
+			t.Fatalf("expected ErrUnknownHTLC failing over %v, "+
+				"instead %v", circuit, err)
+		}
+	}
+	if !registry.IsPending(testPaymentHash) {
+		t.Fatalf("payment resolved over unknown circuit")
+	}
+
+	err := registry.SettleHTLC(testCircuit(htlc), testPreimage)
+	if err != nil {
+		t.Fatalf("unable to settle htlc: %v", err)
+	}
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatalf("payment failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("payment didn't complete")
+	}
+
+	// With the payment complete, the HTLC is resolved within the
+	// database, which also records the peer it was offered to.
+	err = registry.FailHTLC(circuits[0], "htlc rejected by peer", nil)
+	if err != ErrUnknownHTLC {
+		t.Fatalf("expected ErrUnknownHTLC, instead %v", err)
+	}
+}
@@ -1,20 +1,34 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"container/list"
+	"expvar"
+	"fmt"
 	"net"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
 	"github.com/lightningnetwork/lnd/lndc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionerr"
 )
 
 var (
 	numNodes int32
+
+	// The number of messages written to all peers, and the number of
+	// writes to their connections needed to do so, exposed under
+	// /debug/vars. Their ratio shows how well writes are coalesced.
+	peerMsgsWritten  = expvar.NewInt("peerMsgsWritten")
+	peerWriteFlushes = expvar.NewInt("peerWriteFlushes")
 )
 
 // channelState...
@@ -34,6 +48,42 @@ const (
 	pingInterval          = 1 * time.Minute
 
 	outgoingQueueLen = 50
+
+	// writeFlushInterval is the longest a message sent to the peer is
+	// held back, giving further messages the chance to be coalesced with
+	// it into a single write to the connection.
+	writeFlushInterval = 5 * time.Millisecond
+
+	// maxWriteBatchSize is the number of buffered bytes at which messages
+	// are written straight away, rather than waiting for the flush timer.
+	maxWriteBatchSize = 16 * 1024
+
+	// writeBufferSize is the size of the write buffer of each peer. It
+	// leaves room for a message larger than the rest of the batch.
+	writeBufferSize = 64 * 1024
+
+	// msgStreamBufferSize is the number of messages which may be queued
+	// on a message stream before reading further messages from the peer
+	// blocks.
+	msgStreamBufferSize = 100
+
+	// maxChanStreams is the number of channels of a peer whose messages
+	// are each processed on their own stream. It bounds the goroutines a
+	// peer launches by sending messages for made up channels.
+	maxChanStreams = 50
+
+	// commitStallTimeout is how long an update of the channel with the
+	// peer may await their signatures, or revocation, before the update
+	// is abandoned and the connection re-established.
+	commitStallTimeout = time.Minute
+
+	// stallCheckInterval is how often the channel is checked for a stalled
+	// update.
+	stallCheckInterval = 10 * time.Second
+
+	// stallErrorTimeout is how long we wait on the error sent to the peer
+	// about a stalled update to be written before disconnecting.
+	stallErrorTimeout = 5 * time.Second
 )
 
 // outgoinMsg...
@@ -42,6 +92,65 @@ type outgoinMsg struct {
 	sentChan chan struct{}
 }
 
+// msgHandler is a function which processes a single decoded lnwire message
+// received from the remote peer.
+type msgHandler func(lnwire.Message)
+
+// streamMsg is a message queued on a msgStream, along with its handler.
+type streamMsg struct {
+	msg     lnwire.Message
+	handler msgHandler
+}
+
+// msgStream processes the queued messages of a peer in order, on its own
+// goroutine. Messages of distinct streams, such as those of different
+// channels, are processed concurrently, so a slow message only holds up the
+// messages behind it in its own stream.
+type msgStream struct {
+	msgs chan streamMsg
+	quit chan struct{}
+}
+
+// newMsgStream creates a message stream, launching the goroutine processing
+// its messages. The goroutine exits once the quit channel is closed, and is
+// tracked by the passed wait group.
+func newMsgStream(quit chan struct{}, wg *sync.WaitGroup) *msgStream {
+	s := &msgStream{
+		msgs: make(chan streamMsg, msgStreamBufferSize),
+		quit: quit,
+	}
+
+	wg.Add(1)
+	go s.msgHandler(wg)
+
+	return s
+}
+
+// queue adds the message to the stream, to be handled once those ahead of
+// it have been. If the stream is full, it blocks until there's room.
+func (s *msgStream) queue(msg lnwire.Message, handler msgHandler) {
+	select {
+	case s.msgs <- streamMsg{msg, handler}:
+	case <-s.quit:
+	}
+}
+
+// msgHandler handles each message of the stream in turn.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *msgStream) msgHandler(wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		select {
+		case m := <-s.msgs:
+			m.handler(m.msg)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
 // peer...
 // inspired by btcd/peer.go
 type peer struct {
@@ -52,6 +161,17 @@ type peer struct {
 
 	conn net.Conn
 
+	// writeBuf batches messages written to the connection. It's only to
+	// be used by the outHandler.
+	writeBuf *bufio.Writer
+
+	// unflushed are the channels of messages written to the writeBuf but
+	// not yet flushed, to be signalled once they are. It's only to be
+	// used by the outHandler.
+	unflushed []chan struct{}
+
+	server *server
+
 	lightningAddr   lndc.LNAdr
 	inbound         bool
 	protocolVersion uint32
@@ -75,10 +195,45 @@ type peer struct {
 	sendQueue     chan outgoinMsg
 
 	// Only will be set if the channel is in the 'pending' state.
-	reservation *lnwallet.ChannelReservation
+	// reservationID is the ID the reservation was requested under on the
+	// wire.
+	reservation   *lnwallet.ChannelReservation
+	reservationID uint64
 
 	lnChannel *lnwallet.LightningChannel
 
+	// remoteAlias is the alias the remote peer handed out for our channel
+	// within their FundingLocked message. Until the channel confirms, it's
+	// used to forward HTLCs over the channel.
+	remoteAlias lnwire.ShortChannelID
+
+	// annExchange tracks the exchange of the signatures announcing our
+	// channel with the peer to the network, guarded by annMtx.
+	annMtx      sync.Mutex
+	annExchange annExchange
+
+	// quiescer tracks the quiescence handshake over our channel with the
+	// peer, which pauses updates to it.
+	quiescer quiescer
+
+	// dynPending is our proposal of new parameters for the channel with
+	// the peer, awaiting its reply, guarded by dynMtx.
+	dynMtx     sync.Mutex
+	dynPending *dynProposal
+
+	// msgHandlers is the per-command dispatch table used by the inHandler
+	// to route each incoming message to its handler. Each new message type
+	// the peer understands only needs to be added here.
+	msgHandlers map[uint32]msgHandler
+
+	// gossipStream processes the announcements, and gossip queries, of
+	// the peer, while chanStreams processes the messages of each of its
+	// channels. They're only to be used by the inHandler, which merely
+	// decodes each message before handing it off, so that neither
+	// gossip, nor a slow channel, holds up the rest of the connection.
+	gossipStream *msgStream
+	chanStreams  map[lnwire.ChannelID]*msgStream
+
 	queueQuit chan struct{}
 	quit      chan struct{}
 	wg        sync.WaitGroup
@@ -86,9 +241,11 @@ type peer struct {
 
 // newPeer...
 func newPeer(conn net.Conn, server *server) *peer {
-	return &peer{
-		conn:   conn,
-		peerID: atomic.AddInt32(&numNodes, 1),
+	p := &peer{
+		conn:     conn,
+		writeBuf: bufio.NewWriterSize(conn, writeBufferSize),
+		server:   server,
+		peerID:   atomic.AddInt32(&numNodes, 1),
 
 		lastNMessages: make(map[lnwire.Message]struct{}),
 
@@ -96,9 +253,46 @@ func newPeer(conn net.Conn, server *server) *peer {
 		sendQueue:     make(chan outgoinMsg, 1),
 		outgoingQueue: make(chan outgoinMsg, outgoingQueueLen),
 
+		chanStreams: make(map[lnwire.ChannelID]*msgStream),
+
 		queueQuit: make(chan struct{}),
 		quit:      make(chan struct{}),
 	}
+
+	p.msgHandlers = map[uint32]msgHandler{
+		lnwire.CmdErrorGeneric:  p.handleErrorGeneric,
+		lnwire.CmdFundingLocked: p.handleFundingLocked,
+		lnwire.CmdFundingCancel: p.handleFundingCancel,
+
+		// TODO: handle offered HTLCs with handleHTLCAdd once they're
+		// locked into both commitments by the channel, so no invoice
+		// is settled for an HTLC the peer may yet take back.
+		lnwire.CmdHTLCSettleRequest:  p.handleHTLCSettle,
+		lnwire.CmdHTLCAddReject:      p.handleHTLCFail,
+		lnwire.CmdHTLCTimeoutRequest: p.handleHTLCFail,
+
+		lnwire.CmdChannelAnnouncement:    p.handleAnnouncement,
+		lnwire.CmdChannelUpdate:          p.handleAnnouncement,
+		lnwire.CmdAnnouncementSignatures: p.handleAnnouncementSigs,
+		lnwire.CmdQueryChannelRange:      p.handleGossipQuery,
+		lnwire.CmdReplyChannelRange:      p.handleGossipQuery,
+		lnwire.CmdQueryShortChanIDs:      p.handleGossipQuery,
+		lnwire.CmdReplyShortChanIDsEnd:   p.handleGossipQuery,
+
+		lnwire.CmdPeerStorage:          p.handlePeerStorage,
+		lnwire.CmdPeerStorageRetrieval: p.handlePeerStorageRetrieval,
+
+		lnwire.CmdSignedData: p.handleSignedData,
+
+		lnwire.CmdStfu:       p.handleStfu,
+		lnwire.CmdSpliceInit: p.handleSpliceInit,
+		lnwire.CmdSpliceAck:  p.handleSpliceAck,
+		lnwire.CmdDynPropose: p.handleDynPropose,
+		lnwire.CmdDynAck:     p.handleDynReply,
+		lnwire.CmdDynReject:  p.handleDynReply,
+	}
+
+	return p
 }
 
 func (p *peer) Start() error {
@@ -108,10 +302,13 @@ func (p *peer) Start() error {
 
 	// TODO(roasbeef): version handshake
 
-	p.wg.Add(3)
+	p.gossipStream = newMsgStream(p.quit, &p.wg)
+
+	p.wg.Add(4)
 	go p.inHandler()
 	go p.queueHandler()
 	go p.outHandler()
+	go p.stallWatcher()
 
 	return nil
 }
@@ -133,6 +330,43 @@ func (p *peer) Stop() error {
 	return nil
 }
 
+// remotePub returns the identity public key of the peer, or nil if the
+// connection isn't authenticated.
+func (p *peer) remotePub() *btcec.PublicKey {
+	conn, ok := p.conn.(*lndc.LNDConn)
+	if !ok || !conn.Authed {
+		return nil
+	}
+	return conn.RemotePub
+}
+
+// hasChannel returns true if we have an open, or pending, channel with the
+// peer.
+func (p *peer) hasChannel() bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.lnChannel != nil || p.reservation != nil
+}
+
+// abandonChannel tears down our live channel with the peer, should it be with
+// the passed node, returning true if it was. The channel is detached from the
+// peer before it's stopped, so no further updates reach it, and its state
+// isn't written again once it's deleted.
+func (p *peer) abandonChannel(nodeID [32]byte) bool {
+	p.Lock()
+	channel := p.lnChannel
+	if channel == nil || channel.TheirLNID() != nodeID {
+		p.Unlock()
+		return false
+	}
+	p.lnChannel = nil
+	p.Unlock()
+
+	channel.Stop()
+	return true
+}
+
 // readNextMessage...
 func (p *peer) readNextMessage() (lnwire.Message, []byte, error) {
 	// TODO(roasbeef): use our own net magic?
@@ -153,29 +387,380 @@ out:
 	for atomic.LoadInt32(&p.disconnect) == 0 {
 		nextMsg, _, err := p.readNextMessage()
 		if err != nil {
+			if decodeErr, ok := err.(*lnwire.DecodeError); ok {
+				p.recordMisbehavior(
+					channeldb.MisbehaviorDecodeFailure,
+					"%v", decodeErr)
+			}
+
 			// TODO(roasbeef): log error
 			break out
 		}
 
 		// TODO(roasbeef): state-machine to track version exchange
-		switch msg := nextMsg.(type) {
-		// TODO(roasbeef): cases
+		handler, ok := p.msgHandlers[nextMsg.Command()]
+		if !ok {
+			// TODO: log unhandled message
+			continue
+		}
+
+		// Gossip, and channel messages, are handled on their own
+		// streams, leaving us free to read the next message.
+		if stream := p.streamFor(nextMsg); stream != nil {
+			stream.queue(nextMsg, handler)
+			continue
 		}
+		handler(nextMsg)
+	}
+
+	// Once the connection is gone, tear down the peer, letting the server
+	// know it's no longer active.
+	p.Stop()
+	select {
+	case p.server.donePeers <- p:
+	case <-p.server.quit:
 	}
 
 	p.wg.Done()
 }
 
-// writeMessage...
+// streamFor returns the stream the message is to be processed on, or nil if
+// it's cheap enough to be handled by the inHandler itself.
+//
+// NOTE: This is only to be used by the inHandler.
+func (p *peer) streamFor(msg lnwire.Message) *msgStream {
+	var chanID lnwire.ChannelID
+	switch msg := msg.(type) {
+	case *lnwire.FundingLocked:
+		chanID = msg.ChannelID
+	case *lnwire.CloseRequest:
+		chanID = msg.ChannelID
+	case *lnwire.CloseComplete:
+		chanID = msg.ChannelID
+	case *lnwire.AnnouncementSignatures:
+		chanID = msg.ChannelID
+	case *lnwire.Stfu:
+		chanID = msg.ChannelID
+	case *lnwire.SpliceInit:
+		chanID = msg.ChannelID
+	case *lnwire.SpliceAck:
+		chanID = msg.ChannelID
+	case *lnwire.DynPropose:
+		chanID = msg.ChannelID
+	case *lnwire.DynAck:
+		chanID = msg.ChannelID
+	case *lnwire.DynReject:
+		chanID = msg.ChannelID
+
+	case *lnwire.ChannelAnnouncement, *lnwire.ChannelUpdate,
+		*lnwire.QueryChannelRange, *lnwire.ReplyChannelRange,
+		*lnwire.QueryShortChanIDs, *lnwire.ReplyShortChanIDsEnd:
+		return p.gossipStream
+
+	default:
+		return nil
+	}
+
+	stream, ok := p.chanStreams[chanID]
+	if ok {
+		return stream
+	}
+
+	// Past the limit, the messages of further channels are handled in
+	// line rather than launching yet more streams.
+	if len(p.chanStreams) >= maxChanStreams {
+		return nil
+	}
+	stream = newMsgStream(p.quit, &p.wg)
+	p.chanStreams[chanID] = stream
+	return stream
+}
+
+// handleErrorGeneric processes an error sent by the remote peer.
+func (p *peer) handleErrorGeneric(msg lnwire.Message) {
+	errMsg := msg.(*lnwire.ErrorGeneric)
+
+	// TODO: log, and tear down the referenced channel
+	fmt.Printf("peer %v sent error for channel %v: %v\n", p.peerID,
+		errMsg.ChannelID, errMsg.Problem)
+}
+
+// handleFundingLocked processes the remote peer signalling that our channel
+// with them is ready for use, recording the alias they've handed out for it.
+func (p *peer) handleFundingLocked(msg lnwire.Message) {
+	lockedMsg := msg.(*lnwire.FundingLocked)
+
+	p.Lock()
+	p.remoteAlias = lockedMsg.AliasScid
+	p.Unlock()
+}
+
+// handleHTLCAdd processes an HTLC the remote peer offers us, for which we're
+// the final hop. Should the invoice registry accept the HTLC, the invoice it
+// pays to is settled, and the HTLC settled with the preimage of the invoice.
+// Otherwise the HTLC is rejected, with a failure only its sender can read.
+// The HTLCs of AMP payments are instead held until their set completes.
+//
+// NOTE: HTLCs don't yet carry an onion, so aren't forwarded: each HTLC we
+// receive is taken to pay to one of our own invoices. The HTLC MUST be locked
+// into both commitments of the channel before being handled, so the handler
+// is left unwired until the peer drives the channel's update protocol.
+func (p *peer) handleHTLCAdd(msg lnwire.Message) {
+	p.checkRemoteUpdate(msg)
+
+	htlc := msg.(*lnwire.HTLCAddRequest)
+	if len(htlc.RedemptionHashes) != 1 {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"offered htlc %v with %v redemption hashes",
+			htlc.HTLCKey, len(htlc.RedemptionHashes))
+		return
+	}
+	paymentHash := *htlc.RedemptionHashes[0]
+
+	// A blob which isn't a final hop payload carries no payment secret,
+	// so the HTLC is rejected like any other lacking one.
+	payload, err := lnwire.ParseFinalHopPayload(htlc.Blob)
+	if err != nil {
+		payload = nil
+	}
+
+	// The HTLCs of AMP payments are held until the rest of their set
+	// arrives.
+	if payload != nil && payload.AMP != nil {
+		p.server.ampHTLCs.acceptHTLC(p, htlc, payload)
+		return
+	}
+
+	invoice, err := p.server.invoices.AcceptHTLC(paymentHash, htlc.Amount,
+		payload)
+	if err == nil {
+		err = p.server.invoices.SettleInvoice(paymentHash)
+	}
+	switch {
+	// The HTLC is held, for testing, rather than settled.
+	case err == hodl.ErrHodl:
+		return
+
+	case err != nil:
+		p.failHTLC(htlc, failCode(err))
+		return
+	}
+
+	preimage := invoice.Preimage
+	p.queueMsg(&lnwire.HTLCSettleRequest{
+		ChannelID:        htlc.ChannelID,
+		HTLCKey:          htlc.HTLCKey,
+		RedemptionProofs: []*[20]byte{&preimage},
+	}, nil)
+}
+
+// failHTLC rejects the HTLC the remote peer offered us. The failure is
+// encrypted under the secret we share with the sender of the HTLC, derived
+// from the ephemeral key the HTLC carried, so only the sender can read it.
+func (p *peer) failHTLC(htlc *lnwire.HTLCAddRequest, code lnwire.FailCode) {
+	reason, err := p.encryptFailure(htlc.EphemeralKey,
+		&lnwire.FailureMessage{Code: code})
+	if err != nil {
+		// The HTLC is still rejected, albeit without a reason.
+		fmt.Printf("unable to encrypt failure of htlc %v from peer "+
+			"%v: %v\n", htlc.HTLCKey, p.peerID, err)
+	}
+
+	p.queueMsg(&lnwire.HTLCAddReject{
+		ChannelID: htlc.ChannelID,
+		HTLCKey:   htlc.HTLCKey,
+		Reason:    reason,
+	}, nil)
+}
+
+// encryptFailure returns the failure packet of the failure, encrypted to the
+// sender of the HTLC which carried the ephemeral key.
+func (p *peer) encryptFailure(ephemeralKey *btcec.PublicKey,
+	failure *lnwire.FailureMessage) ([]byte, error) {
+
+	if ephemeralKey == nil {
+		return nil, fmt.Errorf("htlc carries no ephemeral key")
+	}
+	ss, _, err := onionerr.DeriveSharedSecret(p.server.identity,
+		ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := failure.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return onionerr.NewErrorEncrypter(ss).EncryptError(b.Bytes())
+}
+
+// handleHTLCSettle resolves the outgoing payment whose HTLC the remote peer
+// settled.
+func (p *peer) handleHTLCSettle(msg lnwire.Message) {
+	p.checkRemoteUpdate(msg)
+
+	settleMsg := msg.(*lnwire.HTLCSettleRequest)
+	if len(settleMsg.RedemptionProofs) == 0 {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"settled htlc %v without a preimage", settleMsg.HTLCKey)
+		return
+	}
+
+	circuit, err := p.circuitFor(settleMsg.ChannelID,
+		settleMsg.HTLCKey)
+	if err == nil {
+		err = p.server.payments.SettleHTLC(circuit,
+			*settleMsg.RedemptionProofs[0])
+	}
+	if err != nil {
+		fmt.Printf("unable to settle htlc %v from peer %v: %v\n",
+			settleMsg.HTLCKey, p.peerID, err)
+	}
+}
+
+// handleHTLCFail fails the outgoing payment whose HTLC the remote peer either
+// rejected, or timed out. A rejection carries the encrypted failure packet of
+// the hop the HTLC failed at, which the payment is left to decrypt.
+func (p *peer) handleHTLCFail(msg lnwire.Message) {
+	p.checkRemoteUpdate(msg)
+
+	var (
+		chanID        lnwire.ShortChannelID
+		htlcKey       lnwire.HTLCKey
+		reason        string
+		failurePacket []byte
+	)
+	switch msg := msg.(type) {
+	case *lnwire.HTLCAddReject:
+		chanID, htlcKey = msg.ChannelID, msg.HTLCKey
+		reason = "htlc rejected by peer"
+		failurePacket = msg.Reason
+	case *lnwire.HTLCTimeoutRequest:
+		chanID, htlcKey = msg.ChannelID, msg.HTLCKey
+		reason = "htlc timed out"
+	}
+
+	circuit, err := p.circuitFor(chanID, htlcKey)
+	if err == nil {
+		err = p.server.payments.FailHTLC(circuit, reason,
+			failurePacket)
+	}
+	if err != nil {
+		fmt.Printf("unable to fail htlc %v from peer %v: %v\n",
+			htlcKey, p.peerID, err)
+	}
+}
+
+// circuitFor returns the circuit key of our HTLC the remote peer resolves
+// over the channel it refers to by the passed ID. The peer refers to our
+// channel by the alias it handed out for it, while we refer to the channel
+// by its short channel ID, as the routes of our payments do.
+func (p *peer) circuitFor(chanID lnwire.ShortChannelID,
+	htlcKey lnwire.HTLCKey) (circuitKey, error) {
+
+	p.RLock()
+	channel, alias := p.lnChannel, p.remoteAlias
+	p.RUnlock()
+
+	pubKey := p.remotePub()
+	if channel == nil || pubKey == nil || chanID != alias {
+		return circuitKey{}, ErrUnknownHTLC
+	}
+
+	return newCircuitKey(pubKey, channel.ShortChanID(), uint64(htlcKey)),
+		nil
+}
+
+// sendHTLC offers the HTLC to the remote peer over our channel with them.
+// ErrChannelQuiescent is returned while the channel is being quiesced.
+//
+// As the peer is yet to drive the update protocol of the channel, the HTLC
+// can't be locked into its commitments, so ErrHTLCsUncommitted is returned
+// rather than offering an HTLC whose outcome we couldn't rely on.
+//
+// TODO: add the HTLC to the commitment of the channel, then offer it to the
+// remote peer, once the update protocol is driven by the peer.
+func (p *peer) sendHTLC(htlc *lnwire.HTLCAddRequest) error {
+	p.RLock()
+	channel := p.lnChannel
+	p.RUnlock()
+
+	if channel == nil {
+		return fmt.Errorf("no open channel with peer %v", p.peerID)
+	}
+	if p.quiescer.updatesPaused() {
+		return ErrChannelQuiescent
+	}
+
+	return ErrHTLCsUncommitted
+}
+
+// handleAnnouncement hands a channel announcement, or update, from the
+// remote peer to the gossiper. Announcements are sent either in response to
+// our gossip queries, or as the peer learns of them.
+func (p *peer) handleAnnouncement(msg lnwire.Message) {
+	if err := p.server.gossiper.ProcessRemoteAnnouncement(msg, p.peerID); err != nil {
+		// TODO: log, and disconnect peers exceeding their
+		// rate limit
+		fmt.Printf("rejected %v from peer %v: %v\n", msg.Command(),
+			p.peerID, err)
+	}
+}
+
+// handleGossipQuery hands a gossip query, or reply, from the remote peer to
+// its gossip syncer.
+func (p *peer) handleGossipQuery(msg lnwire.Message) {
+	if err := p.server.syncMgr.ProcessQueryMsg(p.peerID, msg); err != nil {
+		fmt.Printf("unable to process gossip query from peer %v: %v\n",
+			p.peerID, err)
+	}
+}
+
+// queueMsg queues the message to be sent to the remote peer. If doneChan is
+// non-nil, it's signalled once the message has been written to the
+// connection.
+func (p *peer) queueMsg(msg lnwire.Message, doneChan chan struct{}) {
+	select {
+	case p.outgoingQueue <- outgoinMsg{msg, doneChan}:
+	case <-p.quit:
+	}
+}
+
+// writeMessage buffers the message to be written to the connection with the
+// next flush.
 func (p *peer) writeMessage(msg lnwire.Message) error {
 	// Simply exit if we're shutting down.
 	if atomic.LoadInt32(&p.disconnect) != 0 {
 		return nil
 	}
 
-	_, err := lnwire.WriteMessage(p.conn, msg, 0,
+	n, err := lnwire.WriteMessage(p.writeBuf, msg, 0,
 		wire.TestNet)
 
+	p.Lock()
+	p.bytesSent += uint64(n)
+	p.Unlock()
+	peerMsgsWritten.Add(1)
+
+	return err
+}
+
+// flushMessages writes all buffered messages to the connection in one go,
+// signalling each of them as sent.
+func (p *peer) flushMessages() error {
+	err := p.writeBuf.Flush()
+	peerWriteFlushes.Add(1)
+
+	p.Lock()
+	p.lastSend = time.Now()
+	p.Unlock()
+
+	for _, sentChan := range p.unflushed {
+		sentChan <- struct{}{}
+	}
+	p.unflushed = nil
+
 	return err
 }
 
@@ -185,6 +770,12 @@ func (p *peer) outHandler() {
 	pingTicker := time.NewTicker(pingInterval)
 	defer pingTicker.Stop()
 
+	// Rather than writing each message to the connection as it's sent,
+	// messages are buffered until the flush timer fires, coalescing bursts
+	// of small messages into fewer, larger writes. The timer is only armed
+	// while messages are buffered.
+	var flushTimer <-chan time.Time
+
 out:
 	for {
 		select {
@@ -196,9 +787,28 @@ out:
 			if err := p.writeMessage(outMsg.msg); err != nil {
 				// TODO(roasbeef): disconnect
 			}
+			if outMsg.sentChan != nil {
+				p.unflushed = append(p.unflushed, outMsg.sentChan)
+			}
+
+			// Once the buffer fills up there's nothing to gain by
+			// waiting any longer.
+			if p.writeBuf.Buffered() >= maxWriteBatchSize {
+				if err := p.flushMessages(); err != nil {
+					// TODO: disconnect
+				}
+				flushTimer = nil
+			} else if flushTimer == nil {
+				flushTimer = time.After(writeFlushInterval)
+			}
 
 			// Synchronize with the outHandler.
 			p.sendQueueSync <- struct{}{}
+		case <-flushTimer:
+			if err := p.flushMessages(); err != nil {
+				// TODO: disconnect
+			}
+			flushTimer = nil
 		case <-pingTicker.C:
 			// TODO(roasbeef): ping em
 		case <-p.quit:
@@ -207,6 +817,12 @@ out:
 		}
 	}
 
+	// Release anyone waiting on messages which were never flushed.
+	for _, sentChan := range p.unflushed {
+		sentChan <- struct{}{}
+	}
+	p.unflushed = nil
+
 	// Wait for the queueHandler to finish so we can empty out all pending
 	// messages avoiding a possible deadlock somewhere.
 	<-p.queueQuit
@@ -264,3 +880,78 @@ out:
 	close(p.queueQuit)
 	p.wg.Done()
 }
+
+// stallWatcher periodically checks whether an update of the channel with the
+// peer has stalled, recovering the channel if so.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *peer) stallWatcher() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(stallCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if p.recoverStalledUpdate() {
+				return
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// recoverStalledUpdate abandons the update in progress within the channel
+// with the peer if it has awaited their signatures, or revocation, for too
+// long. The peer is told of the stall with an error, then the connection is
+// re-established so the channel is resumed from its last committed state,
+// rather than being left silently stuck. It returns true if the update had
+// stalled.
+func (p *peer) recoverStalledUpdate() bool {
+	p.RLock()
+	channel := p.lnChannel
+	p.RUnlock()
+	if channel == nil {
+		return false
+	}
+
+	update := channel.PendingUpdate()
+	if update == nil || time.Since(update.Started) < commitStallTimeout {
+		return false
+	}
+
+	chanID := channel.ShortChanID()
+	fmt.Printf("update of channel %v with peer %v stalled for %v: "+
+		"htlc=%x, deletion=%v, update %v -> %v, our balance=%v, "+
+		"their balance=%v\n", chanID, p.peerID,
+		time.Since(update.Started), update.RHash[:], update.Deletion,
+		update.CurrentUpdateNum, update.PendingUpdateNum,
+		channel.OurBalance(), channel.TheirBalance())
+
+	errMsg := &lnwire.ErrorGeneric{
+		ChannelID: chanID,
+		Problem: fmt.Sprintf("commitment update %v stalled",
+			update.PendingUpdateNum),
+	}
+	sent := make(chan struct{}, 1)
+	p.queueMsg(errMsg, sent)
+	select {
+	case <-sent:
+	case <-time.After(stallErrorTimeout):
+	case <-p.quit:
+	}
+
+	if err := channel.CancelUpdate(); err != nil {
+		// The update completed in the meantime.
+		return false
+	}
+
+	// TODO: once channel reestablishment is implemented (see
+	// Start), the last committed state is to be exchanged with the peer
+	// upon reconnecting, retransmitting whatever they're missing.
+	p.server.reconnectPeer(p)
+
+	return true
+}
@@ -1 +1,479 @@
 package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
+	"github.com/lightningnetwork/lnd/amp"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/onionerr"
+)
+
+// createTestDB creates a new channeldb instance backed by a fresh database,
+// along with a function to clean up the database.
+func createTestDB(t *testing.T) (*channeldb.DB, func()) {
+	dirName, err := ioutil.TempDir("", "peertest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	db, err := walletdb.Create("bdb", filepath.Join(dirName, "peer.db"))
+	if err != nil {
+		os.RemoveAll(dirName)
+		t.Fatalf("unable to create db: %v", err)
+	}
+	lnNamespace, err := db.Namespace([]byte("ld"))
+	if err != nil {
+		db.Close()
+		os.RemoveAll(dirName)
+		t.Fatalf("unable to create namespace: %v", err)
+	}
+
+	cleanUp := func() {
+		db.Close()
+		os.RemoveAll(dirName)
+	}
+
+	return channeldb.New(nil, lnNamespace), cleanUp
+}
+
+// createTestPeer creates a peer of a server with just what's needed to
+// receive HTLCs: an identity key, and an invoice registry backed by a fresh
+// database. The messages the peer sends are left within its outgoing queue.
+func createTestPeer(t *testing.T, rejectZeroProbes bool) (*peer,
+	*channeldb.DB, func()) {
+
+	cdb, cleanUp := createTestDB(t)
+
+	nodeKey, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{1}, 32))
+	s := &server{
+		identity: keychain.NewPrivKeySigner(nodeKey),
+		invoices: newInvoiceRegistry(cdb, 0, hodl.MaskNone,
+			rejectZeroProbes, func(lnwire.MilliSatoshi) error {
+				return nil
+			}),
+		quit: make(chan struct{}),
+	}
+	s.ampHTLCs = newAMPHTLCs(s.invoices)
+
+	return newPeer(nil, s), cdb, func() {
+		s.invoices.Stop()
+		cleanUp()
+	}
+}
+
+// addTestInvoice adds an open invoice of the value to the registry of the
+// peer's server.
+func addTestInvoice(t *testing.T, p *peer, i byte,
+	value lnwire.MilliSatoshi) *channeldb.Invoice {
+
+	invoice := &channeldb.Invoice{
+		Preimage:      [20]byte{i},
+		PaymentSecret: [32]byte{i, i},
+		Value:         value,
+		CreationDate:  time.Now(),
+		Expiry:        time.Hour,
+		State:         channeldb.InvoiceOpen,
+	}
+	if err := p.server.invoices.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	return invoice
+}
+
+// offerHTLC has the peer process an HTLC of the amount, paying to the
+// payment hash, and carrying the payload if non-nil. The session key the
+// HTLC was sent under is returned.
+func offerHTLC(t *testing.T, p *peer, htlcKey lnwire.HTLCKey,
+	paymentHash [20]byte, amt lnwire.MilliSatoshi,
+	payload *lnwire.FinalHopPayload) *btcec.PrivateKey {
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate session key: %v", err)
+	}
+
+	htlc := &lnwire.HTLCAddRequest{
+		HTLCKey:          htlcKey,
+		Expiry:           500,
+		Amount:           amt,
+		ContractType:     htlcContractType,
+		HashType:         lnwire.HTLCHashTypeHash160,
+		RedemptionHashes: []*[20]byte{&paymentHash},
+		EphemeralKey:     sessionKey.PubKey(),
+	}
+	if payload != nil {
+		var b bytes.Buffer
+		if err := payload.Encode(&b); err != nil {
+			t.Fatalf("unable to encode payload: %v", err)
+		}
+		htlc.Blob = b.Bytes()
+	}
+
+	p.handleHTLCAdd(htlc)
+
+	return sessionKey
+}
+
+// nextReply returns the next message the peer sends.
+func nextReply(t *testing.T, p *peer) lnwire.Message {
+	select {
+	case out := <-p.outgoingQueue:
+		return out.msg
+	case <-time.After(time.Second):
+		t.Fatalf("peer didn't reply to htlc")
+	}
+
+	return nil
+}
+
+// assertNoReply asserts the peer has sent nothing.
+func assertNoReply(t *testing.T, p *peer) {
+	select {
+	case out := <-p.outgoingQueue:
+		t.Fatalf("unexpected reply %v", out.msg.Command())
+	default:
+	}
+}
+
+// assertHTLCSettled asserts the reply settles the HTLC with the preimage.
+func assertHTLCSettled(t *testing.T, reply lnwire.Message,
+	htlcKey lnwire.HTLCKey, preimage [20]byte) {
+
+	settle, ok := reply.(*lnwire.HTLCSettleRequest)
+	if !ok {
+		t.Fatalf("expected htlc to be settled, instead got %v",
+			reply.Command())
+	}
+	if settle.HTLCKey != htlcKey {
+		t.Fatalf("expected htlc %v to be settled, instead %v",
+			htlcKey, settle.HTLCKey)
+	}
+	if len(settle.RedemptionProofs) != 1 ||
+		*settle.RedemptionProofs[0] != preimage {
+
+		t.Fatalf("htlc %v settled with the wrong preimage", htlcKey)
+	}
+}
+
+// assertHTLCFailed asserts the reply is a rejection, whose failure the
+// sender is able to decrypt, carrying the failure code.
+func assertHTLCFailed(t *testing.T, p *peer, reply lnwire.Message,
+	sessionKey *btcec.PrivateKey, code lnwire.FailCode) {
+
+	reject, ok := reply.(*lnwire.HTLCAddReject)
+	if !ok {
+		t.Fatalf("expected htlc to be rejected, instead got %v",
+			reply.Command())
+	}
+
+	secrets := onionerr.GenerateSharedSecrets(sessionKey,
+		[]*btcec.PublicKey{p.server.identity.PubKey()})
+	decrypted, err := onionerr.NewErrorDecrypter(secrets).DecryptError(
+		reject.Reason)
+	if err != nil {
+		t.Fatalf("unable to decrypt failure: %v", err)
+	}
+	failure, err := lnwire.ParseFailureMessage(decrypted.Failure)
+	if err != nil {
+		t.Fatalf("unable to parse failure: %v", err)
+	}
+	if failure.Code != code {
+		t.Fatalf("expected failure %v, got %v", code, failure.Code)
+	}
+}
+
+// TestHTLCAddPaymentSecret asserts HTLCs are only settled if they carry the
+// payment secret of the invoice they pay to, and pay at least its value.
+func TestHTLCAddPaymentSecret(t *testing.T) {
+	p, cdb, cleanUp := createTestPeer(t, false)
+	defer cleanUp()
+
+	const value = lnwire.MilliSatoshi(100000)
+
+	tests := []struct {
+		name string
+
+		// payload returns the final hop payload the HTLC carries,
+		// given the invoice it pays to.
+		payload func(*channeldb.Invoice) *lnwire.FinalHopPayload
+		amt     lnwire.MilliSatoshi
+
+		settled bool
+	}{
+		{
+			name: "correct secret",
+			payload: func(i *channeldb.Invoice) *lnwire.FinalHopPayload {
+				return &lnwire.FinalHopPayload{
+					PaymentSecret: i.PaymentSecret,
+					TotalAmount:   value,
+				}
+			},
+			amt:     value,
+			settled: true,
+		},
+		{
+			name: "missing secret",
+			payload: func(*channeldb.Invoice) *lnwire.FinalHopPayload {
+				return nil
+			},
+			amt: value,
+		},
+		{
+			name: "wrong secret",
+			payload: func(i *channeldb.Invoice) *lnwire.FinalHopPayload {
+				secret := i.PaymentSecret
+				secret[0] ^= 1
+				return &lnwire.FinalHopPayload{
+					PaymentSecret: secret,
+					TotalAmount:   value,
+				}
+			},
+			amt: value,
+		},
+		{
+			name: "underpaid",
+			payload: func(i *channeldb.Invoice) *lnwire.FinalHopPayload {
+				return &lnwire.FinalHopPayload{
+					PaymentSecret: i.PaymentSecret,
+					TotalAmount:   value - 1,
+				}
+			},
+			amt: value - 1,
+		},
+	}
+
+	for i, test := range tests {
+		invoice := addTestInvoice(t, p, byte(i+1), value)
+		paymentHash := invoice.PaymentHash()
+
+		sessionKey := offerHTLC(t, p, 1, paymentHash, test.amt,
+			test.payload(invoice))
+		reply := nextReply(t, p)
+
+		stored, err := cdb.LookupInvoice(paymentHash)
+		if err != nil {
+			t.Fatalf("%v: unable to look up invoice: %v", test.name,
+				err)
+		}
+
+		if !test.settled {
+			assertHTLCFailed(t, p, reply, sessionKey,
+				lnwire.CodeIncorrectPaymentDetails)
+			if stored.State != channeldb.InvoiceOpen {
+				t.Fatalf("%v: expected invoice to remain open, "+
+					"instead %v", test.name, stored.State)
+			}
+			continue
+		}
+
+		assertHTLCSettled(t, reply, 1, invoice.Preimage)
+		if stored.State != channeldb.InvoiceSettled {
+			t.Fatalf("%v: expected invoice to be settled, "+
+				"instead %v", test.name, stored.State)
+		}
+	}
+}
+
+// TestHTLCAddAMP asserts the HTLCs of an AMP payment are held until the set
+// completes, then settled with the preimages derived from its root seed.
+func TestHTLCAddAMP(t *testing.T) {
+	p, cdb, cleanUp := createTestPeer(t, false)
+	defer cleanUp()
+
+	const value = lnwire.MilliSatoshi(100000)
+
+	invoice := &channeldb.Invoice{
+		Preimage:      [20]byte{1},
+		PaymentSecret: [32]byte{1, 1},
+		Value:         value,
+		CreationDate:  time.Now(),
+		Expiry:        time.Hour,
+		State:         channeldb.InvoiceOpen,
+		AMP:           true,
+	}
+	if err := p.server.invoices.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	root, err := amp.NewRoot()
+	if err != nil {
+		t.Fatalf("unable to generate root: %v", err)
+	}
+	setID := amp.NewSetID(root)
+	children, err := amp.Split(root, 2)
+	if err != nil {
+		t.Fatalf("unable to split root: %v", err)
+	}
+
+	ampPayload := func(child *amp.Child,
+		secret [32]byte) *lnwire.FinalHopPayload {
+
+		return &lnwire.FinalHopPayload{
+			PaymentSecret: secret,
+			TotalAmount:   value,
+			AMP: &lnwire.AMPRecord{
+				InvoiceHash: invoice.PaymentHash(),
+				SetID:       setID,
+				Share:       child.Share,
+				ChildIndex:  child.Index,
+			},
+		}
+	}
+
+	// An HTLC of the set lacking the payment secret is rejected outright.
+	sessionKey := offerHTLC(t, p, 1, children[0].Hash, value/2,
+		ampPayload(children[0], [32]byte{}))
+	assertHTLCFailed(t, p, nextReply(t, p), sessionKey,
+		lnwire.CodeIncorrectPaymentDetails)
+
+	// The first HTLC of the set is held, until the second completes the
+	// set, at which point both are settled.
+	offerHTLC(t, p, 2, children[0].Hash, value/2,
+		ampPayload(children[0], invoice.PaymentSecret))
+	assertNoReply(t, p)
+
+	offerHTLC(t, p, 3, children[1].Hash, value/2,
+		ampPayload(children[1], invoice.PaymentSecret))
+	assertHTLCSettled(t, nextReply(t, p), 2, children[0].Preimage)
+	assertHTLCSettled(t, nextReply(t, p), 3, children[1].Preimage)
+
+	stored, err := cdb.LookupInvoice(invoice.PaymentHash())
+	if err != nil {
+		t.Fatalf("unable to look up invoice: %v", err)
+	}
+	htlcSet, ok := stored.HTLCSets[setID]
+	if !ok || htlcSet.State != channeldb.InvoiceSettled {
+		t.Fatalf("expected htlc set to be settled")
+	}
+}
+
+// TestHTLCAddUniformFailure asserts every HTLC we reject as the final hop is
+// failed back with the same failure, whatever the reason, so that probing
+// nodes learn nothing about the payment hash.
+func TestHTLCAddUniformFailure(t *testing.T) {
+	p, _, cleanUp := createTestPeer(t, false)
+	defer cleanUp()
+
+	const value = lnwire.MilliSatoshi(100000)
+
+	expired := &channeldb.Invoice{
+		Preimage:      [20]byte{1},
+		PaymentSecret: [32]byte{1, 1},
+		Value:         value,
+		CreationDate:  time.Now().Add(-2 * time.Hour),
+		Expiry:        time.Hour,
+		State:         channeldb.InvoiceOpen,
+	}
+	if err := p.server.invoices.AddInvoice(expired); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	settled := addTestInvoice(t, p, 2, value)
+	if err := p.server.invoices.SettleInvoice(
+		settled.PaymentHash()); err != nil {
+
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	open := addTestInvoice(t, p, 3, value)
+	wrongSecret := open.PaymentSecret
+	wrongSecret[0] ^= 1
+
+	tests := []struct {
+		name        string
+		paymentHash [20]byte
+		secret      [32]byte
+	}{
+		{
+			name:        "unknown payment hash",
+			paymentHash: [20]byte{0xff},
+			secret:      open.PaymentSecret,
+		},
+		{
+			name:        "expired invoice",
+			paymentHash: expired.PaymentHash(),
+			secret:      expired.PaymentSecret,
+		},
+		{
+			name:        "settled invoice",
+			paymentHash: settled.PaymentHash(),
+			secret:      settled.PaymentSecret,
+		},
+		{
+			name:        "wrong secret",
+			paymentHash: open.PaymentHash(),
+			secret:      wrongSecret,
+		},
+	}
+
+	for i, test := range tests {
+		htlcKey := lnwire.HTLCKey(i + 1)
+		sessionKey := offerHTLC(t, p, htlcKey, test.paymentHash, value,
+			&lnwire.FinalHopPayload{
+				PaymentSecret: test.secret,
+				TotalAmount:   value,
+			})
+
+		reply := nextReply(t, p)
+		if reject, ok := reply.(*lnwire.HTLCAddReject); ok &&
+			reject.HTLCKey != htlcKey {
+
+			t.Fatalf("%v: expected htlc %v to be rejected, instead %v",
+				test.name, htlcKey, reject.HTLCKey)
+		}
+		assertHTLCFailed(t, p, reply, sessionKey,
+			lnwire.CodeIncorrectPaymentDetails)
+	}
+}
+
+// TestHTLCAddZeroProbes asserts HTLCs paying to zero-value invoices without
+// committing to a total amount are rejected only if probes are.
+func TestHTLCAddZeroProbes(t *testing.T) {
+	for _, rejectZeroProbes := range []bool{false, true} {
+		p, _, cleanUp := createTestPeer(t, rejectZeroProbes)
+
+		for i, totalAmt := range []lnwire.MilliSatoshi{0, 1000} {
+			invoice := addTestInvoice(t, p, byte(i+1), 0)
+			sessionKey := offerHTLC(t, p, 1, invoice.PaymentHash(),
+				1000, &lnwire.FinalHopPayload{
+					PaymentSecret: invoice.PaymentSecret,
+					TotalAmount:   totalAmt,
+				})
+			reply := nextReply(t, p)
+
+			if rejectZeroProbes && totalAmt == 0 {
+				assertHTLCFailed(t, p, reply, sessionKey,
+					lnwire.CodeIncorrectPaymentDetails)
+				continue
+			}
+			assertHTLCSettled(t, reply, 1, invoice.Preimage)
+		}
+
+		cleanUp()
+	}
+}
+
+// TestHTLCAddUnhandled asserts HTLCs the remote peer offers aren't handled,
+// so no invoice is settled, while they can't yet be locked into the
+// commitments of the channel.
+func TestHTLCAddUnhandled(t *testing.T) {
+	p, _, cleanUp := createTestPeer(t, false)
+	defer cleanUp()
+
+	if _, ok := p.msgHandlers[lnwire.CmdHTLCAddRequest]; ok {
+		t.Fatalf("offered htlcs handled before being committed")
+	}
+}
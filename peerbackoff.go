@@ -0,0 +1,331 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lndc"
+)
+
+const (
+	// minPeerBackoff is the delay before reconnecting to a persistent peer
+	// which hasn't flapped.
+	minPeerBackoff = time.Second
+
+	// maxPeerBackoff is the longest delay before reconnecting to a
+	// persistent peer, however often it has flapped.
+	maxPeerBackoff = time.Hour
+
+	// stableConnDuration is how long a connection must last to not be
+	// counted as a flap once it drops.
+	stableConnDuration = 10 * time.Minute
+
+	// flapForgiveInterval is how long a peer must go without flapping to
+	// have one of its flaps forgiven.
+	flapForgiveInterval = 30 * time.Minute
+)
+
+// persistentPeer is a peer we reconnect to whenever our connections to it
+// drop. The delay before reconnecting doubles with each recent flap, so that
+// constantly flapping peers don't tie up our dialing.
+//
+// NOTE: The fields are guarded by the persistentMtx of the server.
+type persistentPeer struct {
+	addr *lndc.LNAdr
+
+	// numConns is the number of connections we have to the peer.
+	numConns int
+
+	// connectedAt is the time our first current connection was made.
+	connectedAt time.Time
+
+	// flaps is the flap count of the peer, which is persisted so it
+	// survives restarts.
+	flaps *channeldb.FlapCount
+
+	// backoff is the delay before the next reconnection attempt, or the
+	// latest if we're connected.
+	backoff time.Duration
+}
+
+// forgiveFlaps returns the flap count, less a flap for each forgiveness
+// interval passed since the last flap.
+func forgiveFlaps(f *channeldb.FlapCount, now time.Time) uint32 {
+	forgiven := now.Sub(f.LastFlap) / flapForgiveInterval
+	if forgiven < 0 {
+		return f.Count
+	}
+	if uint64(forgiven) >= uint64(f.Count) {
+		return 0
+	}
+	return f.Count - uint32(forgiven)
+}
+
+// flapBackoff returns the delay before reconnecting to a peer with the
+// passed flap count, doubling from the minimum backoff with each flap up to
+// the maximum. As the backoff only depends on the flap count, it's the same
+// across restarts.
+func flapBackoff(flaps uint32) time.Duration {
+	backoff := minPeerBackoff
+	for i := uint32(0); i < flaps && backoff < maxPeerBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxPeerBackoff {
+		backoff = maxPeerBackoff
+	}
+	return backoff
+}
+
+// addPersistentPeer adds the peer to those we reconnect to, loading its flap
+// count from the database.
+func (s *server) addPersistentPeer(addr *lndc.LNAdr) error {
+	if addr.PubKey == nil {
+		return fmt.Errorf("persistent connections require the public " +
+			"key of the peer")
+	}
+	key := serializePubKey(addr.PubKey)
+
+	flaps, err := s.lnwallet.ChannelDB.FetchFlapCount(key)
+	if err != nil {
+		return err
+	}
+	if flaps == nil {
+		flaps = &channeldb.FlapCount{}
+	}
+
+	s.persistentMtx.Lock()
+	defer s.persistentMtx.Unlock()
+
+	if p, ok := s.persistentPeers[key]; ok {
+		p.addr = addr
+		return nil
+	}
+	s.persistentPeers[key] = &persistentPeer{
+		addr:    addr,
+		flaps:   flaps,
+		backoff: flapBackoff(forgiveFlaps(flaps, time.Now())),
+	}
+	return nil
+}
+
+// removePersistentPeer stops reconnecting to the peer.
+func (s *server) removePersistentPeer(pubKey *btcec.PublicKey) {
+	s.persistentMtx.Lock()
+	delete(s.persistentPeers, serializePubKey(pubKey))
+	s.persistentMtx.Unlock()
+}
+
+// persistentPeerOnline records a new connection to the peer, if it's
+// persistent.
+func (s *server) persistentPeerOnline(pubKey *btcec.PublicKey) {
+	s.persistentMtx.Lock()
+	defer s.persistentMtx.Unlock()
+
+	p, ok := s.persistentPeers[serializePubKey(pubKey)]
+	if !ok {
+		return
+	}
+	p.numConns++
+	if p.numConns == 1 {
+		p.connectedAt = time.Now()
+	}
+}
+
+// persistentPeerOffline records the closing of a connection to the peer, if
+// it's persistent. Once no connections remain, a reconnection attempt is
+// scheduled, with a connection which dropped soon after being made counted
+// as a flap.
+func (s *server) persistentPeerOffline(pubKey *btcec.PublicKey) {
+	key := serializePubKey(pubKey)
+
+	s.persistentMtx.Lock()
+	defer s.persistentMtx.Unlock()
+
+	p, ok := s.persistentPeers[key]
+	if !ok || p.numConns == 0 {
+		return
+	}
+	p.numConns--
+	if p.numConns > 0 {
+		return
+	}
+
+	now := time.Now()
+	flaps := forgiveFlaps(p.flaps, now)
+	if now.Sub(p.connectedAt) < stableConnDuration {
+		p.flaps = &channeldb.FlapCount{
+			Count:    flaps + 1,
+			LastFlap: now,
+		}
+		if err := s.lnwallet.ChannelDB.PutFlapCount(key, p.flaps); err != nil {
+			fmt.Printf("unable to store flap count of peer %x: %v\n",
+				key[:], err)
+		}
+		flaps++
+	}
+	p.backoff = flapBackoff(flaps)
+
+	s.scheduleReconnect(key, p.backoff)
+}
+
+// scheduleReconnect launches a goroutine reconnecting to the persistent peer
+// after the delay. If the attempt fails, another is scheduled, each doubling
+// the delay up to the maximum backoff.
+func (s *server) scheduleReconnect(key [33]byte, delay time.Duration) {
+	if atomic.LoadInt32(&s.shutdown) != 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		select {
+		case <-time.After(delay):
+		case <-s.quit:
+			return
+		}
+
+		// The peer may have connected to us in the meantime, or we
+		// may have been asked to disconnect from it.
+		s.persistentMtx.Lock()
+		p, ok := s.persistentPeers[key]
+		if !ok || p.numConns > 0 {
+			s.persistentMtx.Unlock()
+			return
+		}
+		addr := p.addr
+		s.persistentMtx.Unlock()
+
+		if err := s.dialKnownAddrs(key, addr); err == nil {
+			return
+		}
+
+		s.persistentMtx.Lock()
+		defer s.persistentMtx.Unlock()
+
+		if p, ok := s.persistentPeers[key]; ok && p.numConns == 0 {
+			p.backoff *= 2
+			if p.backoff > maxPeerBackoff {
+				p.backoff = maxPeerBackoff
+			}
+			s.scheduleReconnect(key, p.backoff)
+		}
+	}()
+}
+
+// dialKnownAddrs dials the peer at each address of it within our address
+// book in turn, from the most to the least likely to reach it, falling back
+// to the address we were given for it. Dialing stops at the first success,
+// or once we've run out of connection attempts.
+//
+// TODO: learn the addresses nodes advertise through gossip
+func (s *server) dialKnownAddrs(key [33]byte, addr *lndc.LNAdr) error {
+	nodeAddrs, err := s.lnwallet.ChannelDB.FetchNodeAddrs(key)
+	if err != nil {
+		fmt.Printf("unable to fetch addresses of peer %x: %v\n",
+			key[:], err)
+	}
+
+	candidates := make([]*lndc.LNAdr, 0, len(nodeAddrs)+1)
+	for _, nodeAddr := range nodeAddrs {
+		if addr.NetAddr != nil && nodeAddr.Addr == addr.NetAddr.String() {
+			continue
+		}
+		netAddr, err := net.ResolveTCPAddr("tcp", nodeAddr.Addr)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, &lndc.LNAdr{
+			PubKey:  addr.PubKey,
+			NetAddr: netAddr,
+		})
+	}
+	candidates = append(candidates, addr)
+
+	for _, candidate := range candidates {
+		err = s.dialPeer(candidate)
+		if err == nil || err == ErrDialRateLimited {
+			break
+		}
+	}
+
+	return err
+}
+
+// retryPersistentPeer schedules a reconnection attempt to the peer after
+// its current backoff, if it's persistent and we have no connection to it.
+// It's used when a connection is refused before the peer is ever online.
+func (s *server) retryPersistentPeer(pubKey *btcec.PublicKey) {
+	key := serializePubKey(pubKey)
+
+	s.persistentMtx.Lock()
+	defer s.persistentMtx.Unlock()
+
+	p, ok := s.persistentPeers[key]
+	if !ok || p.numConns > 0 {
+		return
+	}
+	s.scheduleReconnect(key, p.backoff)
+}
+
+// persistentPeerInfo returns the flap count, and current backoff, of the
+// peer, or false if it isn't persistent.
+func (s *server) persistentPeerInfo(pubKey *btcec.PublicKey) (uint32, time.Duration, bool) {
+	s.persistentMtx.Lock()
+	defer s.persistentMtx.Unlock()
+
+	p, ok := s.persistentPeers[serializePubKey(pubKey)]
+	if !ok {
+		return 0, 0, false
+	}
+	return forgiveFlaps(p.flaps, time.Now()), p.backoff, true
+}
+
+// serializePubKey returns the compressed serialization of the public key.
+func serializePubKey(pubKey *btcec.PublicKey) [33]byte {
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+	return key
+}
+
+// reconnectPeer drops the connection to the peer, then connects to it again.
+// Persistent peers are redialed with their usual backoff once the connection
+// is gone, while other peers we dialed are redialed after the minimum
+// backoff. Peers which connected to us are left to reconnect themselves.
+func (s *server) reconnectPeer(p *peer) {
+	p.Stop()
+
+	if p.inbound || p.lightningAddr.NetAddr == nil {
+		return
+	}
+	if pubKey := p.remotePub(); pubKey != nil {
+		if _, _, ok := s.persistentPeerInfo(pubKey); ok {
+			return
+		}
+	}
+	if atomic.LoadInt32(&s.shutdown) != 0 {
+		return
+	}
+
+	addr := p.lightningAddr
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		select {
+		case <-time.After(minPeerBackoff):
+		case <-s.quit:
+			return
+		}
+
+		if err := s.dialPeer(&addr); err != nil {
+			fmt.Printf("unable to reconnect to peer %v: %v\n",
+				addr.String(), err)
+		}
+	}()
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// TestForgiveFlaps asserts a flap is forgiven for each forgiveness interval
+// passed since the last flap, never forgiving more flaps than were counted.
+func TestForgiveFlaps(t *testing.T) {
+	lastFlap := time.Now()
+
+	tests := []struct {
+		name    string
+		count   uint32
+		elapsed time.Duration
+		flaps   uint32
+	}{
+		{
+			name:    "no flaps",
+			count:   0,
+			elapsed: 10 * flapForgiveInterval,
+			flaps:   0,
+		},
+		{
+			name:    "just flapped",
+			count:   3,
+			elapsed: 0,
+			flaps:   3,
+		},
+		{
+			name:    "within interval",
+			count:   3,
+			elapsed: flapForgiveInterval - time.Second,
+			flaps:   3,
+		},
+		{
+			name:    "one interval",
+			count:   3,
+			elapsed: flapForgiveInterval,
+			flaps:   2,
+		},
+		{
+			name:    "two and a half intervals",
+			count:   3,
+			elapsed: 5 * flapForgiveInterval / 2,
+			flaps:   1,
+		},
+		{
+			name:    "all forgiven",
+			count:   3,
+			elapsed: 3 * flapForgiveInterval,
+			flaps:   0,
+		},
+		{
+			name:    "more than counted",
+			count:   3,
+			elapsed: 100 * flapForgiveInterval,
+			flaps:   0,
+		},
+		{
+			name:    "clock moved backwards",
+			count:   3,
+			elapsed: -flapForgiveInterval,
+			flaps:   3,
+		},
+	}
+
+	for _, test := range tests {
+		f := &channeldb.FlapCount{
+			Count:    test.count,
+			LastFlap: lastFlap,
+		}
+		flaps := forgiveFlaps(f, lastFlap.Add(test.elapsed))
+		if flaps != test.flaps {
+			t.Fatalf("%v: expected %v flaps, instead %v", test.name,
+				test.flaps, flaps)
+		}
+	}
+}
+
+// TestFlapBackoff asserts the backoff doubles from the minimum with each
+// flap, up to the maximum.
+func TestFlapBackoff(t *testing.T) {
+	tests := []struct {
+		flaps   uint32
+		backoff time.Duration
+	}{
+		{0, minPeerBackoff},
+		{1, 2 * minPeerBackoff},
+		{2, 4 * minPeerBackoff},
+		{10, 1024 * minPeerBackoff},
+		{11, 2048 * minPeerBackoff},
+		{12, maxPeerBackoff},
+		{1 << 31, maxPeerBackoff},
+	}
+
+	for _, test := range tests {
+		backoff := flapBackoff(test.flaps)
+		if backoff != test.backoff {
+			t.Fatalf("%v flaps: expected backoff %v, instead %v",
+				test.flaps, test.backoff, backoff)
+		}
+	}
+}
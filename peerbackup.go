@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// peerBackupInterval is how often the backup of our channels is checked for
+// changes, being handed to our peers to store whenever it has.
+const peerBackupInterval = time.Minute
+
+// peerBackupManager has our peers store an encrypted backup of our
+// channels, which they hand back each time we connect. A node which has
+// lost all of its data besides its seed recovers the backup from its peers
+// by simply reconnecting to them. In turn, we store the backup of each of
+// our peers, handing it back as they connect.
+//
+// TODO: only store the backups of peers we have channels with,
+// once peers track their channels
+type peerBackupManager struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	identity keychain.SingleKeyECDH
+	db       *channeldb.DB
+
+	// broadcast sends the messages to each of our connected peers.
+	broadcast func(msgs ...lnwire.Message) error
+
+	mtx sync.Mutex
+
+	// ourBackup is the latest backup of our channels, packed for our
+	// peers to store.
+	ourBackup []byte
+
+	// lastBackup is the encoding of the latest backup, before it was
+	// packed, used to detect changes to our channels.
+	lastBackup []byte
+
+	// recovered holds the backup of our channels each peer has handed
+	// back since we started, keyed by its serialized public key.
+	recovered map[[33]byte]*chanbackup.Multi
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newPeerBackupManager creates a manager of the backups of our channels,
+// packed under our identity key.
+func newPeerBackupManager(identity keychain.SingleKeyECDH, db *channeldb.DB,
+	broadcast func(msgs ...lnwire.Message) error) *peerBackupManager {
+
+	return &peerBackupManager{
+		identity:  identity,
+		db:        db,
+		broadcast: broadcast,
+		recovered: make(map[[33]byte]*chanbackup.Multi),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine handing changes to the backup of our
+// channels to our peers.
+func (m *peerBackupManager) Start() {
+	if !atomic.CompareAndSwapUint32(&m.started, 0, 1) {
+		return
+	}
+
+	m.wg.Add(1)
+	go m.backupUpdater()
+}
+
+// Stop waits for the updater to exit.
+func (m *peerBackupManager) Stop() {
+	if !atomic.CompareAndSwapUint32(&m.stopped, 0, 1) {
+		return
+	}
+
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// backupUpdater packs the backup of our channels upon starting, then each
+// peerBackupInterval, sending it to our peers whenever our channels have
+// changed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *peerBackupManager) backupUpdater() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(peerBackupInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.updateBackup(); err != nil {
+			fmt.Printf("unable to update peer backup: %v\n", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// updateBackup packs a new backup of our channels, sending it to our peers
+// should it differ from the latest.
+func (m *peerBackupManager) updateBackup() error {
+	channels, err := m.db.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+	backup := chanbackup.NewMulti(channels)
+
+	var b bytes.Buffer
+	if err := backup.Encode(&b); err != nil {
+		return err
+	}
+
+	m.mtx.Lock()
+	unchanged := m.ourBackup != nil && bytes.Equal(b.Bytes(), m.lastBackup)
+	m.mtx.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	packed, err := chanbackup.PackMulti(backup, m.identity)
+	if err != nil {
+		return err
+	}
+	if len(packed) > lnwire.MaxPeerStorageSize {
+		return fmt.Errorf("backup of %v channels exceeds the %v bytes "+
+			"peers store", len(channels), lnwire.MaxPeerStorageSize)
+	}
+
+	m.mtx.Lock()
+	m.ourBackup = packed
+	m.lastBackup = b.Bytes()
+	m.mtx.Unlock()
+
+	return m.broadcast(&lnwire.PeerStorage{Blob: packed})
+}
+
+// peerConnected hands the peer back the backup it last asked us to store,
+// then sends it the latest backup of our own channels.
+func (m *peerBackupManager) peerConnected(pubKey *btcec.PublicKey,
+	sendToPeer func(msgs ...lnwire.Message) error) error {
+
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+
+	theirBackup, err := m.db.FetchPeerStorage(key)
+	if err != nil {
+		return err
+	}
+
+	var msgs []lnwire.Message
+	if theirBackup != nil {
+		msgs = append(msgs, &lnwire.PeerStorageRetrieval{
+			Blob: theirBackup,
+		})
+	}
+
+	m.mtx.Lock()
+	if m.ourBackup != nil {
+		msgs = append(msgs, &lnwire.PeerStorage{Blob: m.ourBackup})
+	}
+	m.mtx.Unlock()
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	return sendToPeer(msgs...)
+}
+
+// storeBackup stores the backup the peer sent us, replacing any it sent
+// before.
+func (m *peerBackupManager) storeBackup(pubKey *btcec.PublicKey,
+	blob []byte) error {
+
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+
+	return m.db.PutPeerStorage(key, blob)
+}
+
+// recoverBackup unpacks the backup of our channels the peer handed back.
+func (m *peerBackupManager) recoverBackup(pubKey *btcec.PublicKey,
+	blob []byte) error {
+
+	backup, err := chanbackup.UnpackMulti(blob, m.identity)
+	if err != nil {
+		return err
+	}
+
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+
+	m.mtx.Lock()
+	m.recovered[key] = backup
+	m.mtx.Unlock()
+
+	return nil
+}
+
+// RecoveredBackups returns the backup of our channels each peer has handed
+// back since we started, keyed by its serialized public key.
+func (m *peerBackupManager) RecoveredBackups() map[[33]byte]*chanbackup.Multi {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	recovered := make(map[[33]byte]*chanbackup.Multi, len(m.recovered))
+	for key, backup := range m.recovered {
+		recovered[key] = backup
+	}
+	return recovered
+}
+
+// handlePeerStorage stores the backup the peer asked us to store for it.
+func (p *peer) handlePeerStorage(msg lnwire.Message) {
+	pubKey := p.remotePub()
+	if pubKey == nil {
+		return
+	}
+
+	blob := msg.(*lnwire.PeerStorage).Blob
+	if err := p.server.peerBackups.storeBackup(pubKey, blob); err != nil {
+		fmt.Printf("unable to store backup of peer %v: %v\n",
+			p.peerID, err)
+	}
+}
+
+// handlePeerStorageRetrieval recovers the backup of our channels the peer
+// handed back.
+func (p *peer) handlePeerStorageRetrieval(msg lnwire.Message) {
+	pubKey := p.remotePub()
+	if pubKey == nil {
+		return
+	}
+
+	blob := msg.(*lnwire.PeerStorageRetrieval).Blob
+	if err := p.server.peerBackups.recoverBackup(pubKey, blob); err != nil {
+		fmt.Printf("unable to recover backup from peer %v: %v\n",
+			p.peerID, err)
+	}
+}
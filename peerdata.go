@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lndc"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// peerDataBuffer is the number of messages queued for each client of the
+// peerDataHub. A client falling further behind is cancelled.
+const peerDataBuffer = 20
+
+// signedDataTag prefixes the digest signed for each SignedData message, so
+// those signatures can't be passed off as our identity key's signature over
+// anything else, such as a channel update.
+var signedDataTag = []byte("lnd signed peer data")
+
+// signedDataDigest returns the digest the sender of a SignedData message
+// signs. It commits to the session the message is sent within, so the
+// signature is only valid between the two nodes, and only until they
+// reconnect.
+func signedDataDigest(sessionID [32]byte, dataType uint16,
+	data []byte) []byte {
+
+	var b bytes.Buffer
+	b.Write(signedDataTag)
+	b.Write(sessionID[:])
+
+	var typeBytes [2]byte
+	binary.BigEndian.PutUint16(typeBytes[:], dataType)
+	b.Write(typeBytes[:])
+	b.Write(data)
+
+	return wire.DoubleSha256(b.Bytes())
+}
+
+// peerSession returns the connected peer with the public key, along with the
+// ID of our session with it.
+func (s *server) peerSession(pubKey *btcec.PublicKey) (*peer, [32]byte, error) {
+	peers, err := s.ListPeers()
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	for _, p := range peers {
+		conn, ok := p.conn.(*lndc.LNDConn)
+		if !ok || !conn.Authed || !conn.RemotePub.IsEqual(pubKey) {
+			continue
+		}
+		return p, conn.SessionID, nil
+	}
+
+	return nil, [32]byte{}, ErrPeerNotConnected
+}
+
+// SignPeerData signs the data with our identity key, on behalf of an
+// application, within our session with the peer.
+func (s *server) SignPeerData(pubKey *btcec.PublicKey, dataType uint16,
+	data []byte) (*btcec.Signature, error) {
+
+	_, sessionID, err := s.peerSession(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.identity.SignHash(signedDataDigest(sessionID, dataType, data))
+}
+
+// VerifyPeerData returns true if the signature over the data was made by the
+// peer's identity key within our session with it.
+func (s *server) VerifyPeerData(pubKey *btcec.PublicKey, dataType uint16,
+	data []byte, sig *btcec.Signature) (bool, error) {
+
+	_, sessionID, err := s.peerSession(pubKey)
+	if err != nil {
+		return false, err
+	}
+
+	digest := signedDataDigest(sessionID, dataType, data)
+	return sig.Verify(digest, pubKey), nil
+}
+
+// SendSignedData signs the data on behalf of an application, sending it to
+// the peer within a SignedData message.
+func (s *server) SendSignedData(pubKey *btcec.PublicKey, dataType uint16,
+	data []byte) error {
+
+	if len(data) > lnwire.MaxSignedDataSize {
+		return fmt.Errorf("data of %v bytes exceeds the maximum of %v",
+			len(data), lnwire.MaxSignedDataSize)
+	}
+
+	p, sessionID, err := s.peerSession(pubKey)
+	if err != nil {
+		return err
+	}
+
+	sig, err := s.identity.SignHash(
+		signedDataDigest(sessionID, dataType, data),
+	)
+	if err != nil {
+		return err
+	}
+
+	p.queueMsg(&lnwire.SignedData{
+		Type:      dataType,
+		Data:      data,
+		Signature: sig,
+	}, nil)
+
+	return nil
+}
+
+// handleSignedData verifies the signature of the peer over the data, before
+// handing it to the applications subscribed to our peers' data. Data with
+// an invalid signature is dropped.
+func (p *peer) handleSignedData(msg lnwire.Message) {
+	conn, ok := p.conn.(*lndc.LNDConn)
+	if !ok || !conn.Authed {
+		return
+	}
+
+	signed := msg.(*lnwire.SignedData)
+	digest := signedDataDigest(conn.SessionID, signed.Type, signed.Data)
+	if !signed.Signature.Verify(digest, conn.RemotePub) {
+		p.recordMisbehavior(channeldb.MisbehaviorBadSignature,
+			"invalid signature over data of type %v", signed.Type)
+		return
+	}
+
+	p.server.peerData.deliver(&peerDataMsg{
+		pubKey: conn.RemotePub,
+		msg:    signed,
+	})
+}
+
+// peerDataMsg is a SignedData message received from a peer, its signature
+// having been verified.
+type peerDataMsg struct {
+	pubKey *btcec.PublicKey
+	msg    *lnwire.SignedData
+}
+
+// peerDataClient is sent each message with a valid signature received from
+// our peers after it subscribed.
+type peerDataClient struct {
+	// msgs is sent each message. It's closed once the client is
+	// cancelled, falls too far behind, or the hub is stopped.
+	msgs chan *peerDataMsg
+
+	cancelOnce sync.Once
+	cancel     func()
+}
+
+// Cancel unsubscribes the client.
+func (c *peerDataClient) Cancel() {
+	c.cancelOnce.Do(c.cancel)
+}
+
+// peerDataHub fans the SignedData messages received from our peers out to
+// clients, such as rpc streams, which come and go.
+type peerDataHub struct {
+	sync.Mutex
+	clients      map[uint64]*peerDataClient
+	nextClientID uint64
+	stopped      bool
+}
+
+// newPeerDataHub creates a new peerDataHub, without any clients.
+func newPeerDataHub() *peerDataHub {
+	return &peerDataHub{
+		clients: make(map[uint64]*peerDataClient),
+	}
+}
+
+// Stop cancels every client.
+func (h *peerDataHub) Stop() {
+	h.Lock()
+	defer h.Unlock()
+
+	h.stopped = true
+	for clientID, client := range h.clients {
+		delete(h.clients, clientID)
+		close(client.msgs)
+	}
+}
+
+// Subscribe returns a new client, sent each message received from now on.
+func (h *peerDataHub) Subscribe() *peerDataClient {
+	client := &peerDataClient{
+		msgs: make(chan *peerDataMsg, peerDataBuffer),
+	}
+
+	h.Lock()
+	clientID := h.nextClientID
+	h.nextClientID++
+
+	// Once stopped, the client won't be sent any messages.
+	if h.stopped {
+		close(client.msgs)
+	} else {
+		h.clients[clientID] = client
+	}
+	h.Unlock()
+
+	client.cancel = func() {
+		h.Lock()
+		defer h.Unlock()
+
+		if _, ok := h.clients[clientID]; ok {
+			delete(h.clients, clientID)
+			close(client.msgs)
+		}
+	}
+
+	return client
+}
+
+// deliver sends the message to every client, cancelling those whose queue is
+// full.
+func (h *peerDataHub) deliver(msg *peerDataMsg) {
+	h.Lock()
+	defer h.Unlock()
+
+	for clientID, client := range h.clients {
+		select {
+		case client.msgs <- msg:
+		default:
+			delete(h.clients, clientID)
+			close(client.msgs)
+		}
+	}
+}
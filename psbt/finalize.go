@@ -0,0 +1,167 @@
+package psbt
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+var (
+	// ErrNotFinalizable is returned when an input doesn't yet carry enough
+	// signatures to construct its final signature script.
+	ErrNotFinalizable = fmt.Errorf("input lacks the signatures required " +
+		"to finalize it")
+
+	// ErrUnsupportedScript is returned when finalizing an input spending
+	// a script we don't know how to satisfy.
+	ErrUnsupportedScript = fmt.Errorf("unsupported script type")
+
+	// ErrIncompletePacket is returned when extracting the transaction of
+	// a packet which hasn't been fully finalized.
+	ErrIncompletePacket = fmt.Errorf("psbt is not fully finalized")
+)
+
+// Finalize constructs the final signature script of the input at the passed
+// index from its partial signatures. P2PKH inputs, and P2SH inputs with a
+// multisig redeem script, are supported. Once finalized, the signing data of
+// the input is no longer required and is dropped.
+func Finalize(p *Packet, index int) error {
+	if index >= len(p.Inputs) {
+		return fmt.Errorf("packet has no input %d", index)
+	}
+	pIn := &p.Inputs[index]
+	if pIn.FinalScriptSig != nil {
+		return nil
+	}
+	if pIn.NonWitnessUtxo == nil {
+		return fmt.Errorf("input %d has no utxo", index)
+	}
+
+	prevOut := p.UnsignedTx.TxIn[index].PreviousOutPoint
+	pkScript := pIn.NonWitnessUtxo.TxOut[prevOut.Index].PkScript
+
+	var (
+		sigScript []byte
+		err       error
+	)
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.PubKeyHashTy:
+		sigScript, err = finalizePubKeyHash(pIn, pkScript)
+	case txscript.ScriptHashTy:
+		sigScript, err = finalizeMultiSig(pIn, pkScript)
+	default:
+		err = ErrUnsupportedScript
+	}
+	if err != nil {
+		return err
+	}
+
+	pIn.FinalScriptSig = sigScript
+	pIn.PartialSigs = nil
+	pIn.SighashType = 0
+	pIn.RedeemScript = nil
+	pIn.Bip32Derivation = nil
+
+	return nil
+}
+
+// MaybeFinalizeAll attempts to finalize every input of the packet, returning
+// an error if any input can't be finalized.
+func MaybeFinalizeAll(p *Packet) error {
+	if err := p.SanityCheck(); err != nil {
+		return err
+	}
+
+	for i := range p.Inputs {
+		if err := Finalize(p, i); err != nil {
+			return fmt.Errorf("unable to finalize input %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Extract returns the fully signed transaction of a finalized packet, ready
+// for broadcast.
+func Extract(p *Packet) (*wire.MsgTx, error) {
+	if !p.IsComplete() {
+		return nil, ErrIncompletePacket
+	}
+
+	tx := p.UnsignedTx.Copy()
+	for i, txIn := range tx.TxIn {
+		txIn.SignatureScript = p.Inputs[i].FinalScriptSig
+	}
+
+	return tx, nil
+}
+
+// finalizePubKeyHash builds the signature script of a P2PKH input from the
+// partial signature of the key hashed in the output script.
+func finalizePubKeyHash(pIn *PInput, pkScript []byte) ([]byte, error) {
+	// A P2PKH script is: OP_DUP OP_HASH160 <20 bytes> OP_EQUALVERIFY
+	// OP_CHECKSIG.
+	pkHash := pkScript[3:23]
+	for _, sig := range pIn.PartialSigs {
+		if !bytes.Equal(btcutil.Hash160(sig.PubKey), pkHash) {
+			continue
+		}
+
+		return txscript.NewScriptBuilder().
+			AddData(sig.Signature).
+			AddData(sig.PubKey).
+			Script()
+	}
+
+	return nil, ErrNotFinalizable
+}
+
+// finalizeMultiSig builds the signature script of a P2SH input with a
+// multisig redeem script. The signatures are ordered by the position of their
+// keys within the redeem script, as OP_CHECKMULTISIG requires.
+func finalizeMultiSig(pIn *PInput, pkScript []byte) ([]byte, error) {
+	redeemScript := pIn.RedeemScript
+	if redeemScript == nil {
+		return nil, fmt.Errorf("input has no redeem script")
+	}
+	if !bytes.Equal(btcutil.Hash160(redeemScript), pkScript[2:22]) {
+		return nil, fmt.Errorf("redeem script doesn't match utxo")
+	}
+	if txscript.GetScriptClass(redeemScript) != txscript.MultiSigTy {
+		return nil, ErrUnsupportedScript
+	}
+
+	_, numRequired, err := txscript.CalcMultiSigStats(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	pubKeys, err := txscript.PushedData(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// An extra item is consumed by OP_CHECKMULTISIG due to an off-by-one
+	// in the original implementation, so a dummy OP_0 comes first.
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_0)
+	numSigs := 0
+	for _, pubKey := range pubKeys {
+		if numSigs == numRequired {
+			break
+		}
+		for _, sig := range pIn.PartialSigs {
+			if bytes.Equal(sig.PubKey, pubKey) {
+				builder.AddData(sig.Signature)
+				numSigs++
+				break
+			}
+		}
+	}
+	if numSigs < numRequired {
+		return nil, ErrNotFinalizable
+	}
+
+	return builder.AddData(redeemScript).Script()
+}
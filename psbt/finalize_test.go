@@ -0,0 +1,147 @@
+package psbt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestFinalizeExtract(t *testing.T) {
+	privKey1, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{0x01}, 32))
+	privKey2, _ := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{0x02}, 32))
+	pub1 := privKey1.PubKey().SerializeCompressed()
+	pub2 := privKey2.PubKey().SerializeCompressed()
+
+	// The first output is P2PKH, the second a 2-of-2 multisig P2SH.
+	p2pkhScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+		AddData(btcutil.Hash160(pub1)).
+		AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	redeemScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_2).AddData(pub1).AddData(pub2).
+		AddOp(txscript.OP_2).AddOp(txscript.OP_CHECKMULTISIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	p2shScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_HASH160).AddData(btcutil.Hash160(redeemScript)).
+		AddOp(txscript.OP_EQUAL).Script()
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	prevTx := wire.NewMsgTx()
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, []byte{0x51}))
+	prevTx.AddTxOut(wire.NewTxOut(5000, p2pkhScript))
+	prevTx.AddTxOut(wire.NewTxOut(6000, p2shScript))
+	prevTxID := prevTx.TxSha()
+
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevTxID, 0), nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevTxID, 1), nil))
+	tx.AddTxOut(wire.NewTxOut(10000, p2pkhScript))
+
+	p, err := New(tx)
+	if err != nil {
+		t.Fatalf("unable to create packet: %v", err)
+	}
+	p.Inputs[0].NonWitnessUtxo = prevTx
+	p.Inputs[1].NonWitnessUtxo = prevTx
+	p.Inputs[1].RedeemScript = redeemScript
+
+	sign := func(index int, script []byte, priv *btcec.PrivateKey) []byte {
+		sig, err := txscript.RawTxInSignature(tx, index, script,
+			txscript.SigHashAll, priv)
+		if err != nil {
+			t.Fatalf("unable to sign input: %v", err)
+		}
+		return sig
+	}
+
+	// Without any signatures, the packet can't be finalized.
+	if err := MaybeFinalizeAll(p); err == nil {
+		t.Fatalf("finalized unsigned packet")
+	}
+	if _, err := Extract(p); err != ErrIncompletePacket {
+		t.Fatalf("expected ErrIncompletePacket, got %v", err)
+	}
+
+	p.Inputs[0].PartialSigs = []*PartialSig{
+		{PubKey: pub1, Signature: sign(0, p2pkhScript, privKey1)},
+	}
+
+	// A single signature isn't enough for the multisig input. The
+	// signatures are added out of order, which finalizing must correct.
+	sig1 := sign(1, redeemScript, privKey1)
+	sig2 := sign(1, redeemScript, privKey2)
+	p.Inputs[1].PartialSigs = []*PartialSig{
+		{PubKey: pub2, Signature: sig2},
+	}
+	if err := Finalize(p, 1); err != ErrNotFinalizable {
+		t.Fatalf("expected ErrNotFinalizable, got %v", err)
+	}
+	p.Inputs[1].PartialSigs = append(p.Inputs[1].PartialSigs,
+		&PartialSig{PubKey: pub1, Signature: sig1})
+
+	if err := MaybeFinalizeAll(p); err != nil {
+		t.Fatalf("unable to finalize packet: %v", err)
+	}
+	if p.Inputs[1].PartialSigs != nil || p.Inputs[1].RedeemScript != nil {
+		t.Fatalf("signing data not dropped once finalized")
+	}
+
+	signedTx, err := Extract(p)
+	if err != nil {
+		t.Fatalf("unable to extract transaction: %v", err)
+	}
+	if len(tx.TxIn[0].SignatureScript) != 0 {
+		t.Fatalf("unsigned transaction was modified")
+	}
+	for i, txIn := range signedTx.TxIn {
+		if txIn.PreviousOutPoint != tx.TxIn[i].PreviousOutPoint {
+			t.Fatalf("extracted transaction doesn't match")
+		}
+	}
+
+	pushes, err := txscript.PushedData(signedTx.TxIn[1].SignatureScript)
+	if err != nil {
+		t.Fatalf("unable to parse signature script: %v", err)
+	}
+	if len(pushes) != 4 || len(pushes[0]) != 0 ||
+		!bytes.Equal(pushes[1], sig1) || !bytes.Equal(pushes[2], sig2) ||
+		!bytes.Equal(pushes[3], redeemScript) {
+		t.Fatalf("multisig signature script is malformed")
+	}
+
+	pushes, err = txscript.PushedData(signedTx.TxIn[0].SignatureScript)
+	if err != nil {
+		t.Fatalf("unable to parse signature script: %v", err)
+	}
+	if len(pushes) != 2 || !bytes.Equal(pushes[1], pub1) {
+		t.Fatalf("p2pkh signature script is malformed")
+	}
+
+	// The packet must still survive a round trip once finalized.
+	b64, err := p.B64Encode()
+	if err != nil {
+		t.Fatalf("unable to encode packet: %v", err)
+	}
+	p2, err := ParseBase64(b64)
+	if err != nil {
+		t.Fatalf("unable to parse packet: %v", err)
+	}
+	if !p2.IsComplete() {
+		t.Fatalf("parsed packet should be complete")
+	}
+}
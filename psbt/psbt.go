@@ -0,0 +1,578 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// A partially signed bitcoin transaction (BIP174) carries an unsigned
+// transaction, along with all the information a signer requires in order to
+// sign its inputs, between the parties constructing and signing the
+// transaction. Each input and output has its own map of typed key-value
+// pairs. Pairs of unknown types are preserved, so that the packet survives a
+// round trip through software which doesn't understand them.
+//
+// The binary encoding is:
+//
+//   magic || global map || input map * num inputs || output map * num outputs
+//
+// with each map being a list of pairs terminated by a zero byte:
+//
+//   <keylen> <keytype> <keydata> <valuelen> <value> ... 0x00
+
+// magic is the prefix of all serialized packets: "psbt" followed by 0xff.
+var magic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+const (
+	// maxPsbtValueLength is the largest value we'll read for any single
+	// pair, guarding against excessive allocations.
+	maxPsbtValueLength = 4000000
+
+	// maxPsbtKeyLength is the largest key we'll read.
+	maxPsbtKeyLength = 10000
+)
+
+// Key types of the global map.
+const (
+	globalUnsignedTxType = 0x00
+)
+
+// Key types of the input maps.
+const (
+	inputNonWitnessUtxoType  = 0x00
+	inputPartialSigType      = 0x02
+	inputSighashType         = 0x03
+	inputRedeemScriptType    = 0x04
+	inputBip32DerivationType = 0x06
+	inputFinalScriptSigType  = 0x07
+)
+
+// Key types of the output maps.
+const (
+	outputRedeemScriptType    = 0x00
+	outputBip32DerivationType = 0x02
+)
+
+var (
+	// ErrInvalidMagic is returned when a packet doesn't begin with the
+	// expected magic bytes.
+	ErrInvalidMagic = fmt.Errorf("invalid psbt magic bytes")
+
+	// ErrDuplicateKey is returned when a map contains the same key twice.
+	ErrDuplicateKey = fmt.Errorf("duplicate key within psbt map")
+
+	// ErrInvalidKeyData is returned when a key carries key data of an
+	// unexpected length.
+	ErrInvalidKeyData = fmt.Errorf("invalid psbt key data")
+)
+
+// Unknown is a key-value pair of a type we don't understand.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// PartialSig is a signature for an input, along with the public key which
+// created it.
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// Bip32Derivation describes how the private key for a public key may be
+// derived from a master key, allowing a signer to find the keys it controls.
+type Bip32Derivation struct {
+	PubKey []byte
+
+	// MasterKeyFingerprint is the first 4 bytes of the hash160 of the
+	// master public key.
+	MasterKeyFingerprint uint32
+
+	// Path is the derivation path from the master key to the public key.
+	Path []uint32
+}
+
+// PInput holds the information required to sign a single input.
+type PInput struct {
+	// NonWitnessUtxo is the transaction whose output the input spends.
+	NonWitnessUtxo *wire.MsgTx
+
+	PartialSigs []*PartialSig
+
+	// SighashType is the sighash type signers must use. Zero indicates
+	// no sighash type has been requested.
+	SighashType uint32
+
+	RedeemScript    []byte
+	Bip32Derivation []*Bip32Derivation
+
+	// FinalScriptSig is the complete signature script of the input,
+	// populated once the input has been finalized.
+	FinalScriptSig []byte
+
+	Unknowns []*Unknown
+}
+
+// POutput holds the information describing an output, allowing signers to
+// recognize outputs which pay back to themselves, such as change.
+type POutput struct {
+	RedeemScript    []byte
+	Bip32Derivation []*Bip32Derivation
+
+	Unknowns []*Unknown
+}
+
+// Packet is a partially signed bitcoin transaction.
+type Packet struct {
+	// UnsignedTx is the transaction being signed. All of its signature
+	// scripts are empty.
+	UnsignedTx *wire.MsgTx
+
+	Inputs  []PInput
+	Outputs []POutput
+
+	Unknowns []*Unknown
+}
+
+// New creates a new packet for the passed unsigned transaction, with empty
+// input and output maps.
+func New(tx *wire.MsgTx) (*Packet, error) {
+	for _, txIn := range tx.TxIn {
+		if len(txIn.SignatureScript) != 0 {
+			return nil, fmt.Errorf("transaction must be unsigned")
+		}
+	}
+
+	return &Packet{
+		UnsignedTx: tx,
+		Inputs:     make([]PInput, len(tx.TxIn)),
+		Outputs:    make([]POutput, len(tx.TxOut)),
+	}, nil
+}
+
+// IsComplete returns true once every input of the packet has been finalized.
+func (p *Packet) IsComplete() bool {
+	for _, pIn := range p.Inputs {
+		if pIn.FinalScriptSig == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SanityCheck ensures the packet is internally consistent.
+func (p *Packet) SanityCheck() error {
+	if p.UnsignedTx == nil {
+		return fmt.Errorf("packet has no unsigned transaction")
+	}
+	if len(p.Inputs) != len(p.UnsignedTx.TxIn) {
+		return fmt.Errorf("packet has %d inputs, transaction has %d",
+			len(p.Inputs), len(p.UnsignedTx.TxIn))
+	}
+	if len(p.Outputs) != len(p.UnsignedTx.TxOut) {
+		return fmt.Errorf("packet has %d outputs, transaction has %d",
+			len(p.Outputs), len(p.UnsignedTx.TxOut))
+	}
+	for i, txIn := range p.UnsignedTx.TxIn {
+		if len(txIn.SignatureScript) != 0 {
+			return fmt.Errorf("transaction must be unsigned")
+		}
+
+		// The previous transaction must actually be the one spent by
+		// the input.
+		prevTx := p.Inputs[i].NonWitnessUtxo
+		if prevTx == nil {
+			continue
+		}
+		if prevTx.TxSha() != txIn.PreviousOutPoint.Hash {
+			return fmt.Errorf("utxo of input %d doesn't match its "+
+				"outpoint", i)
+		}
+		if int(txIn.PreviousOutPoint.Index) >= len(prevTx.TxOut) {
+			return fmt.Errorf("utxo of input %d has no output %d",
+				i, txIn.PreviousOutPoint.Index)
+		}
+	}
+
+	return nil
+}
+
+// Serialize writes the binary encoding of the packet to w.
+func (p *Packet) Serialize(w io.Writer) error {
+	if _, err := w.Write(magic); err != nil {
+		return err
+	}
+
+	var txBuf bytes.Buffer
+	if err := p.UnsignedTx.Serialize(&txBuf); err != nil {
+		return err
+	}
+	if err := writePair(w, globalUnsignedTxType, nil, txBuf.Bytes()); err != nil {
+		return err
+	}
+	if err := writeUnknowns(w, p.Unknowns); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{0x00}); err != nil {
+		return err
+	}
+
+	for _, pIn := range p.Inputs {
+		if err := pIn.serialize(w); err != nil {
+			return err
+		}
+	}
+	for _, pOut := range p.Outputs {
+		if err := pOut.serialize(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// B64Encode returns the base64 encoding of the packet, the format in which
+// packets are typically passed around.
+func (p *Packet) B64Encode() (string, error) {
+	var b bytes.Buffer
+	if err := p.Serialize(&b); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
+
+// Parse decodes a binary encoded packet from r.
+func Parse(r io.Reader) (*Packet, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(prefix[:], magic) {
+		return nil, ErrInvalidMagic
+	}
+
+	p := &Packet{}
+	err := readMap(r, func(keyType byte, keyData, value []byte) error {
+		switch keyType {
+		case globalUnsignedTxType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			tx := wire.NewMsgTx()
+			if err := tx.Deserialize(bytes.NewReader(value)); err != nil {
+				return err
+			}
+			p.UnsignedTx = tx
+
+		default:
+			p.Unknowns = append(p.Unknowns, newUnknown(keyType,
+				keyData, value))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.UnsignedTx == nil {
+		return nil, fmt.Errorf("packet has no unsigned transaction")
+	}
+
+	p.Inputs = make([]PInput, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		if err := p.Inputs[i].parse(r); err != nil {
+			return nil, err
+		}
+	}
+	p.Outputs = make([]POutput, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		if err := p.Outputs[i].parse(r); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.SanityCheck(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ParseBase64 decodes a base64 encoded packet.
+func ParseBase64(s string) (*Packet, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(bytes.NewReader(b))
+}
+
+// serialize writes the input map to w.
+func (pi *PInput) serialize(w io.Writer) error {
+	if pi.NonWitnessUtxo != nil {
+		var txBuf bytes.Buffer
+		if err := pi.NonWitnessUtxo.Serialize(&txBuf); err != nil {
+			return err
+		}
+		err := writePair(w, inputNonWitnessUtxoType, nil, txBuf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	// Pairs carrying a final script sig are stripped of the information
+	// used to create it, as it's no longer needed.
+	if pi.FinalScriptSig == nil {
+		for _, sig := range pi.PartialSigs {
+			err := writePair(w, inputPartialSigType, sig.PubKey,
+				sig.Signature)
+			if err != nil {
+				return err
+			}
+		}
+		if pi.SighashType != 0 {
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], pi.SighashType)
+			if err := writePair(w, inputSighashType, nil, b[:]); err != nil {
+				return err
+			}
+		}
+		if pi.RedeemScript != nil {
+			err := writePair(w, inputRedeemScriptType, nil,
+				pi.RedeemScript)
+			if err != nil {
+				return err
+			}
+		}
+		err := writeDerivations(w, inputBip32DerivationType,
+			pi.Bip32Derivation)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := writePair(w, inputFinalScriptSigType, nil,
+			pi.FinalScriptSig)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := writeUnknowns(w, pi.Unknowns); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+// parse reads the input map from r.
+func (pi *PInput) parse(r io.Reader) error {
+	return readMap(r, func(keyType byte, keyData, value []byte) error {
+		switch keyType {
+		case inputNonWitnessUtxoType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			tx := wire.NewMsgTx()
+			if err := tx.Deserialize(bytes.NewReader(value)); err != nil {
+				return err
+			}
+			pi.NonWitnessUtxo = tx
+
+		case inputPartialSigType:
+			if !validPubKey(keyData) {
+				return ErrInvalidKeyData
+			}
+			pi.PartialSigs = append(pi.PartialSigs, &PartialSig{
+				PubKey:    keyData,
+				Signature: value,
+			})
+
+		case inputSighashType:
+			if len(keyData) != 0 || len(value) != 4 {
+				return ErrInvalidKeyData
+			}
+			pi.SighashType = binary.LittleEndian.Uint32(value)
+
+		case inputRedeemScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			pi.RedeemScript = value
+
+		case inputBip32DerivationType:
+			d, err := parseDerivation(keyData, value)
+			if err != nil {
+				return err
+			}
+			pi.Bip32Derivation = append(pi.Bip32Derivation, d)
+
+		case inputFinalScriptSigType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			pi.FinalScriptSig = value
+
+		default:
+			pi.Unknowns = append(pi.Unknowns, newUnknown(keyType,
+				keyData, value))
+		}
+		return nil
+	})
+}
+
+// serialize writes the output map to w.
+func (po *POutput) serialize(w io.Writer) error {
+	if po.RedeemScript != nil {
+		err := writePair(w, outputRedeemScriptType, nil, po.RedeemScript)
+		if err != nil {
+			return err
+		}
+	}
+	err := writeDerivations(w, outputBip32DerivationType,
+		po.Bip32Derivation)
+	if err != nil {
+		return err
+	}
+
+	if err := writeUnknowns(w, po.Unknowns); err != nil {
+		return err
+	}
+	_, err = w.Write([]byte{0x00})
+	return err
+}
+
+// parse reads the output map from r.
+func (po *POutput) parse(r io.Reader) error {
+	return readMap(r, func(keyType byte, keyData, value []byte) error {
+		switch keyType {
+		case outputRedeemScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			po.RedeemScript = value
+
+		case outputBip32DerivationType:
+			d, err := parseDerivation(keyData, value)
+			if err != nil {
+				return err
+			}
+			po.Bip32Derivation = append(po.Bip32Derivation, d)
+
+		default:
+			po.Unknowns = append(po.Unknowns, newUnknown(keyType,
+				keyData, value))
+		}
+		return nil
+	})
+}
+
+// writePair writes a single key-value pair to w.
+func writePair(w io.Writer, keyType byte, keyData, value []byte) error {
+	key := append([]byte{keyType}, keyData...)
+	if err := wire.WriteVarBytes(w, 0, key); err != nil {
+		return err
+	}
+	return wire.WriteVarBytes(w, 0, value)
+}
+
+// writeUnknowns writes each of the unknown pairs to w.
+func writeUnknowns(w io.Writer, unknowns []*Unknown) error {
+	for _, u := range unknowns {
+		if err := wire.WriteVarBytes(w, 0, u.Key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, u.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDerivations writes a pair of the passed type for each of the
+// derivations.
+func writeDerivations(w io.Writer, keyType byte,
+	derivations []*Bip32Derivation) error {
+
+	for _, d := range derivations {
+		value := make([]byte, 4+4*len(d.Path))
+		binary.LittleEndian.PutUint32(value, d.MasterKeyFingerprint)
+		for i, index := range d.Path {
+			binary.LittleEndian.PutUint32(value[4+4*i:], index)
+		}
+		if err := writePair(w, keyType, d.PubKey, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseDerivation decodes a BIP32 derivation pair.
+func parseDerivation(keyData, value []byte) (*Bip32Derivation, error) {
+	if !validPubKey(keyData) {
+		return nil, ErrInvalidKeyData
+	}
+	if len(value) < 4 || len(value)%4 != 0 {
+		return nil, fmt.Errorf("invalid bip32 derivation")
+	}
+
+	d := &Bip32Derivation{
+		PubKey:               keyData,
+		MasterKeyFingerprint: binary.LittleEndian.Uint32(value),
+	}
+	for i := 4; i < len(value); i += 4 {
+		d.Path = append(d.Path, binary.LittleEndian.Uint32(value[i:]))
+	}
+	return d, nil
+}
+
+// readMap reads key-value pairs from r until the terminating zero byte,
+// calling handlePair for each. An error is returned if a key is repeated.
+func readMap(r io.Reader,
+	handlePair func(keyType byte, keyData, value []byte) error) error {
+
+	seen := make(map[string]struct{})
+	for {
+		key, err := wire.ReadVarBytes(r, 0, maxPsbtKeyLength, "key")
+		if err != nil {
+			return err
+		}
+
+		// A zero length key marks the end of the map.
+		if len(key) == 0 {
+			return nil
+		}
+		if _, ok := seen[string(key)]; ok {
+			return ErrDuplicateKey
+		}
+		seen[string(key)] = struct{}{}
+
+		value, err := wire.ReadVarBytes(r, 0, maxPsbtValueLength, "value")
+		if err != nil {
+			return err
+		}
+
+		if err := handlePair(key[0], key[1:], value); err != nil {
+			return err
+		}
+	}
+}
+
+// newUnknown creates an unknown pair from the parsed key and value.
+func newUnknown(keyType byte, keyData, value []byte) *Unknown {
+	return &Unknown{
+		Key:   append([]byte{keyType}, keyData...),
+		Value: value,
+	}
+}
+
+// validPubKey returns true if the key data has the length of a serialized
+// public key.
+func validPubKey(keyData []byte) bool {
+	return len(keyData) == 33 || len(keyData) == 65
+}
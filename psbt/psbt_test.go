@@ -0,0 +1,125 @@
+package psbt
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	testPubKey = append([]byte{0x02}, bytes.Repeat([]byte{0x11}, 32)...)
+)
+
+func makeTestPacket(t *testing.T) *Packet {
+	prevTx := wire.NewMsgTx()
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 7}, []byte{0x51}))
+	prevTx.AddTxOut(wire.NewTxOut(5000, []byte{0x76, 0xa9}))
+	prevTx.AddTxOut(wire.NewTxOut(6000, []byte{0x76, 0xa9, 0x14}))
+	prevTxID := prevTx.TxSha()
+
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevTxID, 1), nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevTxID, 0), nil))
+	tx.AddTxOut(wire.NewTxOut(10000, []byte{0xa9, 0x14}))
+
+	p, err := New(tx)
+	if err != nil {
+		t.Fatalf("unable to create packet: %v", err)
+	}
+
+	p.Inputs[0].NonWitnessUtxo = prevTx
+	p.Inputs[0].SighashType = 1
+	p.Inputs[0].RedeemScript = []byte{0x52, 0xae}
+	p.Inputs[0].PartialSigs = []*PartialSig{
+		{PubKey: testPubKey, Signature: []byte{0x30, 0x01}},
+	}
+	p.Inputs[0].Bip32Derivation = []*Bip32Derivation{
+		{
+			PubKey:               testPubKey,
+			MasterKeyFingerprint: 0xdeadbeef,
+			Path:                 []uint32{0x8000002c, 1, 5},
+		},
+	}
+	p.Inputs[1].NonWitnessUtxo = prevTx
+	p.Inputs[1].FinalScriptSig = []byte{0x01, 0x02}
+	p.Inputs[1].Unknowns = []*Unknown{
+		{Key: []byte{0xfc, 0x01}, Value: []byte{0x99}},
+	}
+	p.Outputs[0].Bip32Derivation = []*Bip32Derivation{
+		{PubKey: testPubKey, MasterKeyFingerprint: 1},
+	}
+	p.Unknowns = []*Unknown{{Key: []byte{0xfb}, Value: []byte{0x01}}}
+
+	return p
+}
+
+func TestPacketEncodeDecode(t *testing.T) {
+	p := makeTestPacket(t)
+
+	b64, err := p.B64Encode()
+	if err != nil {
+		t.Fatalf("unable to encode packet: %v", err)
+	}
+	p2, err := ParseBase64(b64)
+	if err != nil {
+		t.Fatalf("unable to parse packet: %v", err)
+	}
+
+	if p2.UnsignedTx.TxSha() != p.UnsignedTx.TxSha() {
+		t.Fatalf("unsigned transaction doesn't match")
+	}
+	p2.UnsignedTx, p.UnsignedTx = nil, nil
+	for i := range p.Inputs {
+		p.Inputs[i].NonWitnessUtxo = nil
+		p2.Inputs[i].NonWitnessUtxo = nil
+	}
+	if !reflect.DeepEqual(p, p2) {
+		t.Fatalf("packets don't match: %+v vs %+v", p, p2)
+	}
+
+	if p2.IsComplete() {
+		t.Fatalf("packet shouldn't be complete")
+	}
+}
+
+func TestPacketInvalid(t *testing.T) {
+	p := makeTestPacket(t)
+	var b bytes.Buffer
+	if err := p.Serialize(&b); err != nil {
+		t.Fatalf("unable to encode packet: %v", err)
+	}
+	raw := b.Bytes()
+
+	// Corrupting the magic bytes should be detected.
+	badMagic := append([]byte{}, raw...)
+	badMagic[4] = 0x00
+	if _, err := Parse(bytes.NewReader(badMagic)); err != ErrInvalidMagic {
+		t.Fatalf("expected ErrInvalidMagic, got %v", err)
+	}
+
+	// A truncated packet should fail to parse.
+	if _, err := Parse(bytes.NewReader(raw[:len(raw)-1])); err == nil {
+		t.Fatalf("parsed truncated packet")
+	}
+
+	// A utxo which doesn't match the outpoint of its input is rejected.
+	p.Inputs[0].NonWitnessUtxo = wire.NewMsgTx()
+	p.Inputs[0].NonWitnessUtxo.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil))
+	p.Inputs[0].NonWitnessUtxo.AddTxOut(wire.NewTxOut(1, nil))
+	b.Reset()
+	if err := p.Serialize(&b); err != nil {
+		t.Fatalf("unable to encode packet: %v", err)
+	}
+	if _, err := Parse(&b); err == nil {
+		t.Fatalf("parsed packet with mismatched utxo")
+	}
+
+	// Transactions with signatures can't be wrapped in a packet.
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, []byte{0x01}))
+	if _, err := New(tx); err == nil {
+		t.Fatalf("created packet from signed transaction")
+	}
+}
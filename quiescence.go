@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// quiescencePollInterval is how often we check whether the update pending
+// within our channel has completed, before sending our Stfu.
+const quiescencePollInterval = 100 * time.Millisecond
+
+// ErrChannelQuiescent is returned when attempting to update a channel which
+// is quiescent, or being quiesced.
+var ErrChannelQuiescent = errors.New("channel is quiescent")
+
+// quiescer tracks the quiescence handshake over our channel with the peer.
+// Once either side starts the handshake, we stop sending updates, sending
+// our Stfu as soon as the update pending within the channel, if any,
+// completes. The channel is quiescent once both sides have sent Stfu, until
+// the protocol requiring it completes, or we disconnect.
+type quiescer struct {
+	sync.Mutex
+
+	// sending is true once we've stopped sending updates, in order to
+	// send our Stfu. sent is true once we have.
+	sending bool
+	sent    bool
+
+	// received is true once the peer has sent us its Stfu.
+	received bool
+
+	// initiator is true if we requested quiescence.
+	initiator bool
+
+	// quiescent is closed once both sides have sent Stfu.
+	quiescent chan struct{}
+}
+
+// updatesPaused returns true if we've stopped sending updates, either as we
+// requested quiescence, or the peer did.
+func (q *quiescer) updatesPaused() bool {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.sending
+}
+
+// isQuiescent returns true once both sides have sent Stfu.
+func (q *quiescer) isQuiescent() bool {
+	q.Lock()
+	defer q.Unlock()
+
+	return q.sent && q.received
+}
+
+// quiesce requests the channel with the peer be quiesced, returning a
+// channel which is closed once it is. Should the handshake already be
+// underway, the channel of that handshake is returned.
+func (p *peer) quiesce() (<-chan struct{}, error) {
+	if _, err := p.channelID(); err != nil {
+		return nil, err
+	}
+
+	p.quiescer.Lock()
+	defer p.quiescer.Unlock()
+
+	if p.quiescer.quiescent == nil {
+		p.quiescer.quiescent = make(chan struct{})
+	}
+	if !p.quiescer.sending {
+		p.quiescer.sending = true
+		p.quiescer.initiator = true
+
+		p.wg.Add(1)
+		go p.sendStfu()
+	}
+
+	return p.quiescer.quiescent, nil
+}
+
+// resumeChannel ends the quiescence of the channel with the peer, once the
+// protocol which required it completes.
+func (p *peer) resumeChannel() {
+	p.quiescer.Lock()
+	defer p.quiescer.Unlock()
+
+	p.quiescer.sending = false
+	p.quiescer.sent = false
+	p.quiescer.received = false
+	p.quiescer.initiator = false
+	p.quiescer.quiescent = nil
+}
+
+// channelID returns the ID of our channel with the peer.
+func (p *peer) channelID() (lnwire.ChannelID, error) {
+	p.RLock()
+	channel := p.lnChannel
+	p.RUnlock()
+
+	if channel == nil {
+		return lnwire.ChannelID{}, fmt.Errorf("no open channel with "+
+			"peer %v", p.peerID)
+	}
+
+	chanPoint, err := channel.ChannelPoint()
+	if err != nil {
+		return lnwire.ChannelID{}, err
+	}
+	return lnwire.NewChanIDFromOutPoint(chanPoint), nil
+}
+
+// sendStfu sends our Stfu once the update pending within our channel with
+// the peer, if any, has completed, completing the handshake should the peer
+// have sent its Stfu already.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *peer) sendStfu() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(quiescencePollInterval)
+	defer ticker.Stop()
+
+	for {
+		p.RLock()
+		channel := p.lnChannel
+		p.RUnlock()
+		if channel != nil && channel.PendingUpdate() == nil {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+	}
+
+	chanID, err := p.channelID()
+	if err != nil {
+		fmt.Printf("unable to quiesce channel with peer %v: %v\n",
+			p.peerID, err)
+		return
+	}
+
+	p.quiescer.Lock()
+	defer p.quiescer.Unlock()
+
+	// The handshake was abandoned while we waited.
+	if !p.quiescer.sending {
+		return
+	}
+
+	p.queueMsg(&lnwire.Stfu{
+		ChannelID: chanID,
+		Initiator: p.quiescer.initiator,
+	}, nil)
+	p.quiescer.sent = true
+
+	if p.quiescer.received {
+		close(p.quiescer.quiescent)
+	}
+}
+
+// handleStfu processes the peer's Stfu, replying with our own once the
+// update pending within our channel, if any, completes.
+func (p *peer) handleStfu(msg lnwire.Message) {
+	stfu := msg.(*lnwire.Stfu)
+
+	chanID, err := p.channelID()
+	if err != nil || chanID != stfu.ChannelID {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent stfu for unknown channel %v", stfu.ChannelID)
+		return
+	}
+
+	p.quiescer.Lock()
+	defer p.quiescer.Unlock()
+
+	if p.quiescer.received {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent stfu for channel %v twice", stfu.ChannelID)
+		return
+	}
+	p.quiescer.received = true
+
+	if p.quiescer.quiescent == nil {
+		p.quiescer.quiescent = make(chan struct{})
+	}
+	if p.quiescer.sent {
+		close(p.quiescer.quiescent)
+		return
+	}
+	if !p.quiescer.sending {
+		p.quiescer.sending = true
+
+		p.wg.Add(1)
+		go p.sendStfu()
+	}
+}
+
+// checkRemoteUpdate records the update the peer sent as misbehavior should
+// it have sent its Stfu already, as it promised to send no further updates.
+// The update is still processed, as it may settle an HTLC of ours.
+func (p *peer) checkRemoteUpdate(msg lnwire.Message) {
+	p.quiescer.Lock()
+	received := p.quiescer.received
+	p.quiescer.Unlock()
+
+	if received {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent %v after stfu", msg.Command())
+	}
+}
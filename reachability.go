@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lndc"
+	"golang.org/x/net/proxy"
+)
+
+// reachabilityDialTimeout is how long each dial of one of our external
+// addresses is given to complete the handshake.
+const reachabilityDialTimeout = 30 * time.Second
+
+// addrReachability is the outcome of dialing back one of the addresses we
+// advertise.
+type addrReachability struct {
+	addr string
+
+	// checkedAt is the time the address was dialed, or zero if it has
+	// yet to be.
+	checkedAt time.Time
+
+	// err is set if the address couldn't be dialed, or didn't complete
+	// the handshake under our identity key.
+	err error
+}
+
+// reachabilityChecker dials back each of the external addresses we
+// advertise once we've started, so operators learn quickly should peers be
+// unable to reach us. An address is only reachable if the handshake
+// completes under our own identity key, ensuring it's our node which
+// answered. Dials are made directly, unless a SOCKS5 proxy is configured,
+// such as Tor, or a checker on another network. Dialing directly from
+// behind a NAT may succeed where outside peers wouldn't, so the proxy gives
+// the more faithful result.
+type reachabilityChecker struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	nodeKey keychain.SingleKeyECDH
+
+	// dial opens the TCP connection to an address.
+	dial func(network, address string) (net.Conn, error)
+
+	mtx     sync.Mutex
+	results []*addrReachability
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newReachabilityChecker creates a checker of the passed external
+// addresses, dialing them through the SOCKS5 proxy at proxyAddr, if set.
+func newReachabilityChecker(nodeKey keychain.SingleKeyECDH, addrs []string,
+	proxyAddr string) (*reachabilityChecker, error) {
+
+	dialer := &net.Dialer{Timeout: reachabilityDialTimeout}
+	dial := dialer.Dial
+	if proxyAddr != "" {
+		socksDialer, err := proxy.SOCKS5("tcp", proxyAddr, nil, dialer)
+		if err != nil {
+			return nil, err
+		}
+		dial = socksDialer.Dial
+	}
+
+	results := make([]*addrReachability, 0, len(addrs))
+	for _, addr := range addrs {
+		results = append(results, &addrReachability{addr: addr})
+	}
+
+	return &reachabilityChecker{
+		nodeKey: nodeKey,
+		dial:    dial,
+		results: results,
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// Start launches the goroutine dialing back each of our addresses.
+func (r *reachabilityChecker) Start() {
+	if !atomic.CompareAndSwapUint32(&r.started, 0, 1) {
+		return
+	}
+
+	r.wg.Add(1)
+	go r.checkAddrs()
+}
+
+// Stop waits for the dials in progress to complete.
+func (r *reachabilityChecker) Stop() {
+	if !atomic.CompareAndSwapUint32(&r.stopped, 0, 1) {
+		return
+	}
+
+	close(r.quit)
+	r.wg.Wait()
+}
+
+// Results returns the reachability of each of our addresses.
+func (r *reachabilityChecker) Results() []addrReachability {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	results := make([]addrReachability, 0, len(r.results))
+	for _, result := range r.results {
+		results = append(results, *result)
+	}
+	return results
+}
+
+// checkAddrs dials back each of our addresses in turn, logging those which
+// are unreachable.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *reachabilityChecker) checkAddrs() {
+	defer r.wg.Done()
+
+	r.mtx.Lock()
+	results := r.results
+	r.mtx.Unlock()
+
+	for _, result := range results {
+		select {
+		case <-r.quit:
+			return
+		default:
+		}
+
+		err := r.dialSelf(result.addr)
+		if err != nil {
+			fmt.Printf("WARNING: external address %v is "+
+				"unreachable: %v\n", result.addr, err)
+		}
+
+		r.mtx.Lock()
+		result.checkedAt = time.Now()
+		result.err = err
+		r.mtx.Unlock()
+	}
+}
+
+// dialSelf dials the address, completing the handshake only if it's our own
+// node which answers.
+func (r *reachabilityChecker) dialSelf(addr string) error {
+	// The deadline bounds the handshake, as well as the dial.
+	conn := lndc.NewConn(nil)
+	conn.Dialer = func(network, address string) (net.Conn, error) {
+		c, err := r.dial(network, address)
+		if err != nil {
+			return nil, err
+		}
+		c.SetDeadline(time.Now().Add(reachabilityDialTimeout))
+		return c, nil
+	}
+
+	ourID := r.nodeKey.PubKey().SerializeCompressed()
+	if err := conn.Dial(r.nodeKey, addr, ourID); err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// trackReservation records the reservation as the peer's pending channel,
+// requested under the passed ID on the wire. Should our side of the
+// reservation be cancelled before it completes, whether explicitly, by
+// expiring, or by the context it was created under being done, the peer is
+// sent a FundingCancel so it may release its side of the reservation rather
+// than waiting for it to expire.
+//
+// TODO: call once funding is driven by the peer
+func (p *peer) trackReservation(res *lnwallet.ChannelReservation,
+	reservationID uint64) {
+
+	p.Lock()
+	p.reservation = res
+	p.reservationID = reservationID
+	p.Unlock()
+
+	p.wg.Add(1)
+	go p.reservationWatcher(res, reservationID)
+}
+
+// reservationWatcher waits for the reservation to be no longer pending,
+// notifying the peer should our side of it have been cancelled.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *peer) reservationWatcher(res *lnwallet.ChannelReservation,
+	reservationID uint64) {
+
+	defer p.wg.Done()
+
+	select {
+	case <-res.Done():
+	case <-p.quit:
+		return
+	}
+
+	reason := res.Err()
+	if reason == nil {
+		return
+	}
+
+	// If the reservation is no longer the peer's, it was the peer which
+	// cancelled it, so there's no need to tell it.
+	p.Lock()
+	ours := p.reservation == res
+	if ours {
+		p.reservation = nil
+	}
+	p.Unlock()
+	if !ours {
+		return
+	}
+
+	p.queueMsg(&lnwire.FundingCancel{
+		ReservationID: reservationID,
+		Reason:        reason.Error(),
+	}, nil)
+}
+
+// handleFundingCancel cancels our side of the reservation the peer
+// abandoned, releasing the outputs we selected for its funding transaction.
+func (p *peer) handleFundingCancel(msg lnwire.Message) {
+	cancelMsg := msg.(*lnwire.FundingCancel)
+
+	p.Lock()
+	res := p.reservation
+	if res == nil || p.reservationID != cancelMsg.ReservationID {
+		p.Unlock()
+
+		fmt.Printf("peer %v cancelled unknown reservation %v\n",
+			p.peerID, cancelMsg.ReservationID)
+		return
+	}
+	p.reservation = nil
+	p.Unlock()
+
+	// The reservation may have completed, or expired, concurrently, in
+	// which case there's nothing left to release.
+	err := res.Cancel()
+	if err != nil && err != lnwallet.ErrReservationNotFound {
+		fmt.Printf("unable to cancel reservation %v of peer %v: %v\n",
+			cancelMsg.ReservationID, p.peerID, err)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// restPathPrefix is the path each method of the rpc server is served
+	// under by the REST proxy, followed by the name of the method.
+	restPathPrefix = "/v1/"
+
+	// restMaxBodySize is the largest request body the REST proxy reads,
+	// matching the largest message the gRPC server receives.
+	restMaxBodySize = 4 * 1024 * 1024
+
+	// corsAnyOrigin allows browsers to call the REST proxy from any
+	// origin.
+	corsAnyOrigin = "*"
+)
+
+var (
+	// lightningServerType is the interface each rpc method served by the
+	// REST proxy is looked up within.
+	lightningServerType = reflect.TypeOf((*lnrpc.LightningServer)(nil)).Elem()
+
+	// contextType is the type of the first argument of each unary rpc
+	// method.
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
+
+// restError is the JSON body the REST proxy writes back should a call fail.
+type restError struct {
+	Error string     `json:"error"`
+	Code  codes.Code `json:"code"`
+}
+
+// restProxy serves the unary methods of the rpc server as JSON over HTTP, so
+// clients unable to speak gRPC, such as browser-based wallets, may reach it.
+// Each method is called with a POST to its name under restPathPrefix. Calls
+// are passed through the same interceptors as those made over gRPC, while
+// streaming methods are only served over gRPC.
+type restProxy struct {
+	server      lnrpc.LightningServer
+	interceptor grpc.UnaryServerInterceptor
+
+	// corsOrigins are the origins browsers may call the proxy from. If
+	// it holds corsAnyOrigin, any origin may.
+	corsOrigins map[string]struct{}
+}
+
+// newRESTProxy creates a REST proxy calling through to the rpc server by
+// way of the interceptor, which browsers may call from the passed origins.
+func newRESTProxy(server lnrpc.LightningServer,
+	interceptor grpc.UnaryServerInterceptor,
+	corsOrigins []string) *restProxy {
+
+	origins := make(map[string]struct{}, len(corsOrigins))
+	for _, origin := range corsOrigins {
+		origins[strings.TrimSuffix(origin, "/")] = struct{}{}
+	}
+
+	return &restProxy{
+		server:      server,
+		interceptor: interceptor,
+		corsOrigins: origins,
+	}
+}
+
+// ServeHTTP calls the method named by the path of the request, decoding its
+// request from the JSON body, and writing back its response as JSON.
+//
+// NOTE: Part of the http.Handler interface.
+func (p *restProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	allowed := p.setCORSHeaders(w, r)
+
+	// Browsers check they may call us from their origin before making
+	// the call itself.
+	if r.Method == http.MethodOptions {
+		if !allowed {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		writeRESTError(w, http.StatusMethodNotAllowed, codes.Unimplemented,
+			fmt.Sprintf("method %v not allowed", r.Method))
+		return
+	}
+
+	var (
+		method reflect.Method
+		ok     bool
+		name   = strings.TrimPrefix(r.URL.Path, restPathPrefix)
+	)
+	if strings.HasPrefix(r.URL.Path, restPathPrefix) {
+		method, ok = unaryRPCMethod(name)
+	}
+	if !ok {
+		writeRESTError(w, http.StatusNotFound, codes.Unimplemented,
+			fmt.Sprintf("unknown unary rpc method %q", name))
+		return
+	}
+
+	// An empty body calls the method with an empty request.
+	req := reflect.New(method.Type.In(1).Elem()).Interface()
+	body := http.MaxBytesReader(w, r.Body, restMaxBodySize)
+	if err := json.NewDecoder(body).Decode(req); err != nil && err != io.EOF {
+		writeRESTError(w, http.StatusBadRequest, codes.InvalidArgument,
+			fmt.Sprintf("unable to decode request: %v", err))
+		return
+	}
+
+	call := reflect.ValueOf(p.server).MethodByName(name)
+	handler := func(ctx context.Context,
+		req interface{}) (interface{}, error) {
+
+		out := call.Call([]reflect.Value{
+			reflect.ValueOf(ctx), reflect.ValueOf(req),
+		})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return nil, err
+		}
+		return out[0].Interface(), nil
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     p.server,
+		FullMethod: rpcMethodPrefix + name,
+	}
+
+	resp, err := p.interceptor(r.Context(), req, info, handler)
+	if err != nil {
+		st, ok := status.FromError(err)
+		if !ok {
+			st = status.New(codes.Unknown, err.Error())
+		}
+		writeRESTError(w, httpStatusFromCode(st.Code()), st.Code(),
+			st.Message())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		fmt.Printf("unable to write rest response of %v: %v\n", name,
+			err)
+	}
+}
+
+// setCORSHeaders allows the browser to read our response should the request
+// come from one of the origins browsers may call us from, returning true if
+// it does.
+func (p *restProxy) setCORSHeaders(w http.ResponseWriter,
+	r *http.Request) bool {
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+
+	// The response differs by origin, so it mustn't be cached across
+	// origins.
+	w.Header().Add("Vary", "Origin")
+
+	_, anyOrigin := p.corsOrigins[corsAnyOrigin]
+	if _, ok := p.corsOrigins[origin]; !ok && !anyOrigin {
+		return false
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	return true
+}
+
+// unaryRPCMethod returns the unary method of the rpc server with the passed
+// name, and false if there's no such method, or it's streaming.
+func unaryRPCMethod(name string) (reflect.Method, bool) {
+	method, ok := lightningServerType.MethodByName(name)
+	if !ok {
+		return method, false
+	}
+
+	// Unary methods take a context and their request, while streaming
+	// ones take their stream in place of the context.
+	if method.Type.NumIn() != 2 || method.Type.In(0) != contextType {
+		return method, false
+	}
+	return method, true
+}
+
+// writeRESTError writes back the error of a failed call as JSON, under the
+// passed HTTP status.
+func writeRESTError(w http.ResponseWriter, httpStatus int, code codes.Code,
+	msg string) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(&restError{msg, code}); err != nil {
+		fmt.Printf("unable to write rest error: %v\n", err)
+	}
+}
+
+// httpStatusFromCode returns the HTTP status a call failing with the gRPC
+// status code is written back under.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return http.StatusRequestTimeout
+	case codes.InvalidArgument, codes.FailedPrecondition,
+		codes.OutOfRange:
+
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
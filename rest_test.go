@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// TestRESTProxyCalls asserts the REST proxy calls unary methods through its
+// interceptor, with the request decoded from the body, writing back either
+// the response, or the error the call failed with.
+func TestRESTProxyCalls(t *testing.T) {
+	preimage := []byte{1, 2, 3}
+
+	// The interceptor answers calls to SendPayment itself, to dest "ab",
+	// and fails those to "cd", handing any other to the rpc server.
+	interceptor := func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		payReq, ok := req.(*lnrpc.SendPaymentRequest)
+		if !ok || info.FullMethod != rpcMethodPrefix+"SendPayment" {
+			return handler(ctx, req)
+		}
+		switch payReq.Dest {
+		case "ab":
+			return &lnrpc.SendPaymentResponse{
+				PaymentPreimage: preimage,
+			}, nil
+		case "cd":
+			return nil, grpc.Errorf(codes.FailedPrecondition,
+				"rejected")
+		}
+		return handler(ctx, req)
+	}
+	proxy := newRESTProxy(&rpcServer{}, interceptor, nil)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		httpStatus int
+		code       codes.Code
+	}{
+		{
+			name:       "answered",
+			method:     http.MethodPost,
+			path:       "/v1/SendPayment",
+			body:       `{"dest": "ab"}`,
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "status error",
+			method:     http.MethodPost,
+			path:       "/v1/SendPayment",
+			body:       `{"dest": "cd"}`,
+			httpStatus: http.StatusBadRequest,
+			code:       codes.FailedPrecondition,
+		},
+		{
+			// The rpc server fails to decode the destination.
+			name:       "rpc server error",
+			method:     http.MethodPost,
+			path:       "/v1/SendPayment",
+			body:       `{"dest": "zz"}`,
+			httpStatus: http.StatusInternalServerError,
+			code:       codes.Unknown,
+		},
+		{
+			name:       "invalid body",
+			method:     http.MethodPost,
+			path:       "/v1/SendPayment",
+			body:       `{"dest": 1}`,
+			httpStatus: http.StatusBadRequest,
+			code:       codes.InvalidArgument,
+		},
+		{
+			name:       "unknown method",
+			method:     http.MethodPost,
+			path:       "/v1/Unknown",
+			httpStatus: http.StatusNotFound,
+			code:       codes.Unimplemented,
+		},
+		{
+			name:       "streaming method",
+			method:     http.MethodPost,
+			path:       "/v1/SubscribeInvoices",
+			httpStatus: http.StatusNotFound,
+			code:       codes.Unimplemented,
+		},
+		{
+			name:       "no prefix",
+			method:     http.MethodPost,
+			path:       "/SendPayment",
+			httpStatus: http.StatusNotFound,
+			code:       codes.Unimplemented,
+		},
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			path:       "/v1/SendPayment",
+			httpStatus: http.StatusMethodNotAllowed,
+			code:       codes.Unimplemented,
+		},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path,
+			strings.NewReader(test.body))
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+
+		if rec.Code != test.httpStatus {
+			t.Fatalf("%v: expected http status %v, instead %v",
+				test.name, test.httpStatus, rec.Code)
+		}
+
+		if test.httpStatus == http.StatusOK {
+			var resp lnrpc.SendPaymentResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("%v: unable to decode response: %v",
+					test.name, err)
+			}
+			if !bytes.Equal(resp.PaymentPreimage, preimage) {
+				t.Fatalf("%v: expected preimage %x, instead %x",
+					test.name, preimage, resp.PaymentPreimage)
+			}
+			continue
+		}
+
+		var restErr restError
+		if err := json.Unmarshal(rec.Body.Bytes(), &restErr); err != nil {
+			t.Fatalf("%v: unable to decode error: %v", test.name, err)
+		}
+		if restErr.Code != test.code {
+			t.Fatalf("%v: expected code %v, instead %v", test.name,
+				test.code, restErr.Code)
+		}
+	}
+}
+
+// TestRESTProxyCORS asserts browsers are only allowed to call the REST proxy
+// from the configured origins.
+func TestRESTProxyCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		origin  string
+		allowed bool
+	}{
+		{
+			name:    "no origins",
+			origin:  "https://wallet.example.com",
+			allowed: false,
+		},
+		{
+			name:    "allowed origin",
+			origins: []string{"https://wallet.example.com/"},
+			origin:  "https://wallet.example.com",
+			allowed: true,
+		},
+		{
+			name:    "other origin",
+			origins: []string{"https://wallet.example.com"},
+			origin:  "https://evil.example.com",
+			allowed: false,
+		},
+		{
+			name:    "any origin",
+			origins: []string{corsAnyOrigin},
+			origin:  "https://evil.example.com",
+			allowed: true,
+		},
+	}
+
+	for _, test := range tests {
+		proxy := newRESTProxy(&rpcServer{}, nil, test.origins)
+
+		req := httptest.NewRequest(http.MethodOptions,
+			"/v1/SendPayment", nil)
+		req.Header.Set("Origin", test.origin)
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+
+		allowedOrigin := rec.Header().Get("Access-Control-Allow-Origin")
+		switch {
+		case test.allowed && rec.Code != http.StatusNoContent:
+			t.Fatalf("%v: expected preflight to succeed, instead "+
+				"got status %v", test.name, rec.Code)
+		case test.allowed && allowedOrigin != test.origin:
+			t.Fatalf("%v: expected origin %v allowed, instead %q",
+				test.name, test.origin, allowedOrigin)
+		case !test.allowed && rec.Code != http.StatusForbidden:
+			t.Fatalf("%v: expected preflight to be refused, "+
+				"instead got status %v", test.name, rec.Code)
+		case !test.allowed && allowedOrigin != "":
+			t.Fatalf("%v: expected no origin allowed, instead %q",
+				test.name, allowedOrigin)
+		}
+	}
+}
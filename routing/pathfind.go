@@ -0,0 +1,308 @@
+package routing
+
+import (
+	"container/heap"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrNoPathFound is returned when no route to the destination exists
+	// within the channel graph which satisfies the restrictions of the
+	// payment.
+	ErrNoPathFound = errors.New("unable to find a path to destination")
+
+	// ErrSelfPayment is returned when the destination of the route is
+	// ourselves.
+	ErrSelfPayment = errors.New("route to self isn't possible")
+)
+
+// NoFeeLimit places no limit on the fees of a route.
+const NoFeeLimit = lnwire.MilliSatoshi(1<<64 - 1)
+
+// Graph is the channel graph a route is found within.
+type Graph interface {
+	// ForEachNodeChannel calls cb with each channel the node is a party
+	// to, stopping at the first error returned.
+	ForEachNodeChannel(nodeKey *btcec.PublicKey,
+		cb func(*channeldb.ChannelEdge) error) error
+}
+
+// LocalChannel is one of our own channels, which a route may leave over.
+// Our channels are taken from here, rather than the graph, as they may not
+// have been announced, and only we know how much we're able to send over
+// them.
+type LocalChannel struct {
+	// ChannelID is the ID the HTLCs sent over the channel are addressed
+	// to.
+	ChannelID lnwire.ShortChannelID
+
+	// Peer is the identity key of the node at the other end of the
+	// channel.
+	Peer *btcec.PublicKey
+
+	// Bandwidth is the largest HTLC we're able to send over the channel.
+	Bandwidth lnwire.MilliSatoshi
+}
+
+// RestrictParams are the limits a route must fall within.
+type RestrictParams struct {
+	// FeeLimit is the most the route may charge in fees. NoFeeLimit
+	// places no limit.
+	FeeLimit lnwire.MilliSatoshi
+
+	// CltvLimit is the furthest the time lock of the first hop may be
+	// from the current height, in blocks. If zero, there's no limit.
+	CltvLimit uint32
+
+	// OutgoingChannelIDs, if set, are the only channels of ours the
+	// route may leave over.
+	OutgoingChannelIDs map[lnwire.ShortChannelID]struct{}
+
+	// LastHop, if set, is the node the route must reach the destination
+	// through.
+	LastHop *btcec.PublicKey
+}
+
+// nodeDist is the cheapest known way for a node to reach the destination.
+type nodeDist struct {
+	pubKey *btcec.PublicKey
+
+	// amt is the amount the node must receive in order to forward the
+	// payment on to the destination, and timeLock is the time lock,
+	// relative to the current height, the node must receive the HTLC
+	// with.
+	amt      lnwire.MilliSatoshi
+	timeLock uint32
+
+	// chanID and next are the channel the node forwards the payment
+	// over, and the node at the other end of it. They're unset for the
+	// destination.
+	chanID lnwire.ShortChannelID
+	next   *nodeDist
+
+	index int
+}
+
+// distHeap orders nodes by the amount they must receive, then their time
+// lock, so the cheapest are visited first.
+type distHeap []*nodeDist
+
+func (h distHeap) Len() int { return len(h) }
+
+func (h distHeap) Less(i, j int) bool {
+	if h[i].amt != h[j].amt {
+		return h[i].amt < h[j].amt
+	}
+	return h[i].timeLock < h[j].timeLock
+}
+
+func (h distHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *distHeap) Push(x interface{}) {
+	dist := x.(*nodeDist)
+	dist.index = len(*h)
+	*h = append(*h, dist)
+}
+
+func (h *distHeap) Pop() interface{} {
+	old := *h
+	dist := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return dist
+}
+
+// FindRoute finds the cheapest route from ourselves to the target for the
+// amount, within the restrictions. The search walks backwards from the
+// target, so that the fees of each hop are known by the time the hop before
+// it is reached. The HTLC received by the target expires finalCltvDelta
+// blocks past the passed height.
+func FindRoute(graph Graph, localChans []*LocalChannel, source,
+	target *btcec.PublicKey, amt lnwire.MilliSatoshi, finalCltvDelta uint16,
+	height uint32, restrictions *RestrictParams) (*Route, error) {
+
+	if source.IsEqual(target) {
+		return nil, ErrSelfPayment
+	}
+
+	feeLimit := restrictions.FeeLimit
+	withinLimits := func(fwdAmt lnwire.MilliSatoshi, timeLock uint32) bool {
+		if fwdAmt < amt || fwdAmt-amt > feeLimit {
+			return false
+		}
+		if restrictions.CltvLimit != 0 &&
+			timeLock > restrictions.CltvLimit {
+			return false
+		}
+		return true
+	}
+
+	targetDist := &nodeDist{
+		pubKey:   target,
+		amt:      amt,
+		timeLock: uint32(finalCltvDelta),
+	}
+	if !withinLimits(targetDist.amt, targetDist.timeLock) {
+		return nil, ErrNoPathFound
+	}
+
+	dists := map[[33]byte]*nodeDist{nodeKey(target): targetDist}
+	visited := make(map[[33]byte]struct{})
+	queue := &distHeap{targetDist}
+
+	// relax records the route of the node through the passed channel, and
+	// queues the node to be visited, if cheaper than its current route.
+	relax := func(candidate *nodeDist) {
+		// Only the last hop may reach the destination directly.
+		lastHop := restrictions.LastHop
+		if lastHop != nil && candidate.next == targetDist &&
+			!candidate.pubKey.IsEqual(lastHop) {
+
+			return
+		}
+
+		key := nodeKey(candidate.pubKey)
+		if _, ok := visited[key]; ok {
+			return
+		}
+
+		dist, ok := dists[key]
+		if !ok {
+			dists[key] = candidate
+			heap.Push(queue, candidate)
+			return
+		}
+		if candidate.amt > dist.amt || (candidate.amt == dist.amt &&
+			candidate.timeLock >= dist.timeLock) {
+			return
+		}
+
+		candidate.index = dist.index
+		(*queue)[dist.index] = candidate
+		dists[key] = candidate
+		heap.Fix(queue, candidate.index)
+	}
+
+	for queue.Len() > 0 {
+		dist := heap.Pop(queue).(*nodeDist)
+		key := nodeKey(dist.pubKey)
+		visited[key] = struct{}{}
+
+		if dist.pubKey.IsEqual(source) {
+			return newRoute(dist, amt, finalCltvDelta, height), nil
+		}
+
+		// We don't charge ourselves fees, so we may reach the node
+		// over any of our channels with enough bandwidth.
+		outgoingChans := restrictions.OutgoingChannelIDs
+		for _, c := range localChans {
+			if !c.Peer.IsEqual(dist.pubKey) || c.Bandwidth < dist.amt {
+				continue
+			}
+			if _, ok := outgoingChans[c.ChannelID]; !ok &&
+				outgoingChans != nil {
+
+				continue
+			}
+
+			relax(&nodeDist{
+				pubKey:   source,
+				amt:      dist.amt,
+				timeLock: dist.timeLock,
+				chanID:   c.ChannelID,
+				next:     dist,
+			})
+		}
+
+		err := graph.ForEachNodeChannel(dist.pubKey,
+			func(edge *channeldb.ChannelEdge) error {
+				ann := edge.Announcement
+
+				// The policy applying to the channel is that of
+				// the node forwarding over it to this one.
+				prev, policy := ann.NodeID1, edge.Policy1
+				if ann.NodeID1.IsEqual(dist.pubKey) {
+					prev, policy = ann.NodeID2, edge.Policy2
+				}
+
+				// Our own channels are taken from localChans.
+				if prev.IsEqual(source) || policy == nil {
+					return nil
+				}
+				if policy.Flags&lnwire.ChanUpdateDisabled != 0 {
+					return nil
+				}
+				if dist.amt < policy.HtlcMinimumMsat ||
+					dist.amt > lnwire.NewMSatFromSatoshis(edge.Capacity) {
+					return nil
+				}
+
+				fwdAmt := dist.amt + computeFee(dist.amt, policy)
+				timeLock := dist.timeLock +
+					uint32(policy.TimeLockDelta)
+				if !withinLimits(fwdAmt, timeLock) {
+					return nil
+				}
+
+				relax(&nodeDist{
+					pubKey:   prev,
+					amt:      fwdAmt,
+					timeLock: timeLock,
+					chanID:   ann.ShortChannelID,
+					next:     dist,
+				})
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, ErrNoPathFound
+}
+
+// newRoute builds the route leading from the source to the destination.
+func newRoute(source *nodeDist, amt lnwire.MilliSatoshi,
+	finalCltvDelta uint16, height uint32) *Route {
+
+	route := &Route{
+		TotalAmount:   source.amt,
+		TotalFees:     source.amt - amt,
+		TotalTimeLock: height + source.timeLock,
+	}
+
+	for dist := source; dist.next != nil; dist = dist.next {
+		hop := &Hop{
+			PubKey:    dist.next.pubKey,
+			ChannelID: dist.chanID,
+		}
+
+		// The final hop receives the payment itself, while every
+		// other forwards the amount the next hop must receive.
+		if next := dist.next.next; next != nil {
+			hop.AmtToForward = next.amt
+			hop.OutgoingTimeLock = height + next.timeLock
+		} else {
+			hop.AmtToForward = amt
+			hop.OutgoingTimeLock = height + uint32(finalCltvDelta)
+		}
+
+		route.Hops = append(route.Hops, hop)
+	}
+
+	return route
+}
+
+// nodeKey returns the key a node is indexed by during the search.
+func nodeKey(pubKey *btcec.PublicKey) [33]byte {
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+	return key
+}
@@ -0,0 +1,207 @@
+package routing
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// testGraph is a channel graph held within a slice of channels.
+type testGraph []*channeldb.ChannelEdge
+
+func (g testGraph) ForEachNodeChannel(nodeKey *btcec.PublicKey,
+	cb func(*channeldb.ChannelEdge) error) error {
+
+	for _, edge := range g {
+		ann := edge.Announcement
+		if !ann.NodeID1.IsEqual(nodeKey) && !ann.NodeID2.IsEqual(nodeKey) {
+			continue
+		}
+		if err := cb(edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func testNodeKeys(n int) []*btcec.PublicKey {
+	var keys []*btcec.PublicKey
+	for i := 1; i <= n; i++ {
+		_, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{byte(i)}, 32))
+		keys = append(keys, pub)
+	}
+	return keys
+}
+
+// testEdge returns a channel between the two nodes, with the passed policy
+// applying in both directions.
+func testEdge(chanID uint64, node1, node2 *btcec.PublicKey, baseFee uint32,
+	timeLockDelta uint16) *channeldb.ChannelEdge {
+
+	shortChanID := lnwire.NewShortChanIDFromInt(chanID)
+	policy := func(flags uint16) *lnwire.ChannelUpdate {
+		return &lnwire.ChannelUpdate{
+			ShortChannelID: shortChanID,
+			Flags:          flags,
+			TimeLockDelta:  timeLockDelta,
+			BaseFee:        baseFee,
+		}
+	}
+
+	return &channeldb.ChannelEdge{
+		Capacity: 100000,
+		Announcement: &lnwire.ChannelAnnouncement{
+			ShortChannelID: shortChanID,
+			NodeID1:        node1,
+			NodeID2:        node2,
+		},
+		Policy1: policy(0),
+		Policy2: policy(lnwire.ChanUpdateDirection),
+	}
+}
+
+func TestFindRoute(t *testing.T) {
+	// We're the first node, with channels to the second and third. Each
+	// reaches the fourth, the second cheaply but slowly, and the third
+	// expensively but quickly.
+	keys := testNodeKeys(4)
+	source, cheap, fast, target := keys[0], keys[1], keys[2], keys[3]
+
+	graph := testGraph{
+		testEdge(1, cheap, target, 10, 100),
+		testEdge(2, fast, target, 100, 10),
+	}
+	localChans := []*LocalChannel{
+		{
+			ChannelID: lnwire.NewShortChanIDFromInt(10),
+			Peer:      cheap,
+			Bandwidth: 10000,
+		},
+		{
+			ChannelID: lnwire.NewShortChanIDFromInt(20),
+			Peer:      fast,
+			Bandwidth: 10000,
+		},
+	}
+
+	const (
+		amt            = 1000
+		finalCltvDelta = 9
+		height         = 500
+	)
+
+	findRoute := func(restrictions *RestrictParams) (*Route, error) {
+		return FindRoute(graph, localChans, source, target, amt,
+			finalCltvDelta, height, restrictions)
+	}
+
+	// Without limits, the cheapest route is taken.
+	route, err := findRoute(&RestrictParams{FeeLimit: NoFeeLimit})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 2 || !route.FirstHop().PubKey.IsEqual(cheap) {
+		t.Fatalf("expected route through cheap node, got %v hops",
+			len(route.Hops))
+	}
+	if route.FirstHop().ChannelID.ToUint64() != 10 ||
+		route.FinalHop().ChannelID.ToUint64() != 1 {
+		t.Fatalf("route takes the wrong channels")
+	}
+	if route.TotalFees != 10 || route.TotalAmount != amt+10 {
+		t.Fatalf("expected fees of 10, got %v", route.TotalFees)
+	}
+	if route.TotalTimeLock != height+finalCltvDelta+100 {
+		t.Fatalf("expected time lock %v, got %v",
+			height+finalCltvDelta+100, route.TotalTimeLock)
+	}
+	if route.FirstHop().AmtToForward != amt ||
+		route.FirstHop().OutgoingTimeLock != height+finalCltvDelta {
+		t.Fatalf("first hop forwards the wrong htlc")
+	}
+	if route.FinalHop().AmtToForward != amt ||
+		!route.FinalHop().PubKey.IsEqual(target) {
+		t.Fatalf("final hop receives the wrong amount")
+	}
+
+	// A cltv limit too tight for the cheap route forces the fast one.
+	route, err = findRoute(&RestrictParams{
+		FeeLimit:  NoFeeLimit,
+		CltvLimit: 50,
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if !route.FirstHop().PubKey.IsEqual(fast) || route.TotalFees != 100 {
+		t.Fatalf("expected route through fast node")
+	}
+
+	// Paired with a fee limit too tight for the fast route, no route
+	// remains.
+	_, err = findRoute(&RestrictParams{FeeLimit: 50, CltvLimit: 50})
+	if err != ErrNoPathFound {
+		t.Fatalf("expected ErrNoPathFound, got %v", err)
+	}
+
+	// Our own channels charge no fees, so a peer is reachable with a
+	// zero fee limit, but only with enough bandwidth.
+	route, err = FindRoute(graph, localChans, source, cheap, amt,
+		finalCltvDelta, height, &RestrictParams{})
+	if err != nil {
+		t.Fatalf("unable to find route to peer: %v", err)
+	}
+	if len(route.Hops) != 1 || route.TotalFees != 0 ||
+		route.TotalTimeLock != height+finalCltvDelta {
+		t.Fatalf("expected direct route to peer")
+	}
+	_, err = FindRoute(graph, localChans, source, cheap, 20000,
+		finalCltvDelta, height, &RestrictParams{FeeLimit: NoFeeLimit})
+	if err != ErrNoPathFound {
+		t.Fatalf("expected ErrNoPathFound, got %v", err)
+	}
+
+	// Pinning the outgoing channel, or the last hop, forces the route
+	// through the fast node.
+	route, err = findRoute(&RestrictParams{
+		FeeLimit: NoFeeLimit,
+		OutgoingChannelIDs: map[lnwire.ShortChannelID]struct{}{
+			lnwire.NewShortChanIDFromInt(20): {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if !route.FirstHop().PubKey.IsEqual(fast) {
+		t.Fatalf("expected route over pinned channel")
+	}
+	route, err = findRoute(&RestrictParams{
+		FeeLimit: NoFeeLimit,
+		LastHop:  fast,
+	})
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 2 || !route.Hops[0].PubKey.IsEqual(fast) {
+		t.Fatalf("expected route through last hop")
+	}
+
+	// A last hop unconnected to the destination leaves no route.
+	_, err = FindRoute(graph, localChans, source, cheap, amt,
+		finalCltvDelta, height, &RestrictParams{
+			FeeLimit: NoFeeLimit,
+			LastHop:  fast,
+		})
+	if err != ErrNoPathFound {
+		t.Fatalf("expected ErrNoPathFound, got %v", err)
+	}
+
+	_, err = FindRoute(graph, localChans, source, source, amt,
+		finalCltvDelta, height, &RestrictParams{FeeLimit: NoFeeLimit})
+	if err != ErrSelfPayment {
+		t.Fatalf("expected ErrSelfPayment, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package routing
+
+import (
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Hop is a single hop of a route: the channel an HTLC is sent over, and the
+// node it's sent to.
+type Hop struct {
+	// PubKey is the identity key of the node the HTLC is sent to.
+	PubKey *btcec.PublicKey
+
+	// ChannelID is the channel the HTLC reaches the node over.
+	ChannelID lnwire.ShortChannelID
+
+	// AmtToForward is the amount the node is to forward to the next hop,
+	// or, for the final hop, the amount it's to receive.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutgoingTimeLock is the time lock of the HTLC the node is to
+	// forward to the next hop, or, for the final hop, the time lock of the
+	// HTLC it's to receive.
+	OutgoingTimeLock uint32
+}
+
+// Route is a path through the channel graph, from ourselves to the
+// destination of a payment, along with the amount, and time lock, of the
+// HTLC to be sent out over the first hop for the destination to receive its
+// payment.
+type Route struct {
+	// TotalAmount is the amount of the HTLC sent over the first hop,
+	// including the fees of each hop.
+	TotalAmount lnwire.MilliSatoshi
+
+	// TotalFees is the sum of the fees of each hop.
+	TotalFees lnwire.MilliSatoshi
+
+	// TotalTimeLock is the time lock of the HTLC sent over the first hop.
+	TotalTimeLock uint32
+
+	// Hops are the hops of the route, starting with our own channel. The
+	// last hop is the destination.
+	Hops []*Hop
+}
+
+// FirstHop returns the hop the HTLC is sent out to by ourselves.
+func (r *Route) FirstHop() *Hop {
+	return r.Hops[0]
+}
+
+// FinalHop returns the destination of the route.
+func (r *Route) FinalHop() *Hop {
+	return r.Hops[len(r.Hops)-1]
+}
+
+// computeFee returns the fee charged by a node to forward the amount under
+// the passed channel policy.
+func computeFee(amt lnwire.MilliSatoshi,
+	policy *lnwire.ChannelUpdate) lnwire.MilliSatoshi {
+
+	return lnwire.MilliSatoshi(policy.BaseFee) +
+		amt*lnwire.MilliSatoshi(policy.FeeRate)/1000000
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+// rpcErrorInfo is the gRPC status code, and structured detail, an error is
+// returned to rpc clients with.
+type rpcErrorInfo struct {
+	code   codes.Code
+	detail *lnrpc.ErrorDetail
+}
+
+// errorInfo returns the info of an error identified by its code alone.
+func errorInfo(code codes.Code, errCode lnrpc.ErrorCode) rpcErrorInfo {
+	return rpcErrorInfo{code, &lnrpc.ErrorDetail{Code: errCode}}
+}
+
+// paymentFailureInfo returns the info of an error failing an HTLC for the
+// passed reason.
+func paymentFailureInfo(code codes.Code,
+	reason lnrpc.PaymentFailureReason) rpcErrorInfo {
+
+	return rpcErrorInfo{code, &lnrpc.ErrorDetail{
+		Code:           lnrpc.ErrorCode_ERROR_CODE_PAYMENT_FAILED,
+		PaymentFailure: reason,
+	}}
+}
+
+// channelConflictInfo returns the info of an error refusing a request which
+// conflicts with the state of a channel.
+func channelConflictInfo(code codes.Code,
+	conflict lnrpc.ChannelConflict) rpcErrorInfo {
+
+	return rpcErrorInfo{code, &lnrpc.ErrorDetail{
+		Code:            lnrpc.ErrorCode_ERROR_CODE_CHANNEL_CONFLICT,
+		ChannelConflict: conflict,
+	}}
+}
+
+// rpcErrors maps each error callers are expected to branch on to the status
+// code, and detail, it's returned to rpc clients with. Both are part of the
+// rpc interface, so once assigned they mustn't change.
+var rpcErrors = map[error]rpcErrorInfo{
+	ErrPeerAlreadyConnected: errorInfo(codes.AlreadyExists,
+		lnrpc.ErrorCode_ERROR_CODE_PEER_ALREADY_CONNECTED),
+	ErrPeerNotConnected: errorInfo(codes.NotFound,
+		lnrpc.ErrorCode_ERROR_CODE_PEER_NOT_CONNECTED),
+	ErrServerShuttingDown: errorInfo(codes.Unavailable,
+		lnrpc.ErrorCode_ERROR_CODE_SERVER_SHUTTING_DOWN),
+	ErrInsufficientOutbound: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_INSUFFICIENT_OUTBOUND),
+	ErrInsufficientInbound: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_INSUFFICIENT_INBOUND),
+
+	lnwallet.ErrInsufficientFunds: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_INSUFFICIENT_FUNDS),
+	lnwallet.ErrReservationNotFound: errorInfo(codes.NotFound,
+		lnrpc.ErrorCode_ERROR_CODE_RESERVATION_NOT_FOUND),
+	lnwallet.ErrMuSig2SessionNotFound: errorInfo(codes.NotFound,
+		lnrpc.ErrorCode_ERROR_CODE_MUSIG2_SESSION_NOT_FOUND),
+	lnwallet.ErrAccountExists: errorInfo(codes.AlreadyExists,
+		lnrpc.ErrorCode_ERROR_CODE_ACCOUNT_EXISTS),
+	lnwallet.ErrTxConfirmed: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_TX_CONFIRMED),
+	lnwallet.ErrNotSynced: errorInfo(codes.Unavailable,
+		lnrpc.ErrorCode_ERROR_CODE_NOT_SYNCED),
+	lnwallet.ErrDoubleSpend: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_DOUBLE_SPEND),
+	lnwallet.ErrFeeTooLow: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_FEE_TOO_LOW),
+	lnwallet.ErrMissingInputs: errorInfo(codes.FailedPrecondition,
+		lnrpc.ErrorCode_ERROR_CODE_MISSING_INPUTS),
+	lnwallet.ErrTxNotFound: errorInfo(codes.NotFound,
+		lnrpc.ErrorCode_ERROR_CODE_TX_NOT_FOUND),
+
+	channeldb.ErrPaymentInFlight: errorInfo(codes.AlreadyExists,
+		lnrpc.ErrorCode_ERROR_CODE_PAYMENT_IN_FLIGHT),
+	channeldb.ErrAlreadyPaid: errorInfo(codes.AlreadyExists,
+		lnrpc.ErrorCode_ERROR_CODE_ALREADY_PAID),
+
+	lnwallet.ErrMaxHTLCNumber: paymentFailureInfo(codes.ResourceExhausted,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_TOO_MANY_HTLCS),
+	lnwallet.ErrMaxPendingAmount: paymentFailureInfo(codes.ResourceExhausted,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_MAX_VALUE_IN_FLIGHT),
+	lnwallet.ErrBelowMinHTLC: paymentFailureInfo(codes.InvalidArgument,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_BELOW_MIN_HTLC),
+	lnwallet.ErrMaxDustExposure: paymentFailureInfo(codes.ResourceExhausted,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_MAX_DUST_EXPOSURE),
+	lnwallet.ErrUnknownPaymentHash: paymentFailureInfo(codes.NotFound,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_UNKNOWN_PAYMENT_HASH),
+	hodl.ErrHodl: paymentFailureInfo(codes.Unavailable,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_HELD),
+	ErrPaymentTimeout: paymentFailureInfo(codes.DeadlineExceeded,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_TIMEOUT),
+	routing.ErrNoPathFound: paymentFailureInfo(codes.NotFound,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_NO_ROUTE),
+	ErrPaymentRejected: paymentFailureInfo(codes.FailedPrecondition,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_INCORRECT_PAYMENT_DETAILS),
+	ErrMultiHopUnsupported: paymentFailureInfo(codes.Unimplemented,
+		lnrpc.PaymentFailureReason_PAYMENT_FAILURE_MULTI_HOP_UNSUPPORTED),
+
+	ErrPeerHasChannels: channelConflictInfo(codes.FailedPrecondition,
+		lnrpc.ChannelConflict_CHANNEL_CONFLICT_PEER_HAS_CHANNELS),
+	lnwallet.ErrChannelTypeMismatch: channelConflictInfo(codes.InvalidArgument,
+		lnrpc.ChannelConflict_CHANNEL_CONFLICT_TYPE_MISMATCH),
+	lnwallet.ErrUpdateCancelled: channelConflictInfo(codes.Aborted,
+		lnrpc.ChannelConflict_CHANNEL_CONFLICT_UPDATE_CANCELLED),
+	lnwallet.ErrNoPendingUpdate: channelConflictInfo(codes.FailedPrecondition,
+		lnrpc.ChannelConflict_CHANNEL_CONFLICT_NO_PENDING_UPDATE),
+}
+
+// rpcError returns the error as a gRPC status, carrying its code and detail,
+// should it be known, leaving any other error as is.
+func rpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	info, ok := rpcErrors[err]
+	if !ok {
+		return err
+	}
+
+	st, detailErr := status.New(info.code, err.Error()).WithDetails(info.detail)
+	if detailErr != nil {
+		return grpc.Errorf(info.code, "%v", err)
+	}
+	return st.Err()
+}
+
+// errorUnaryInterceptor converts any known error returned by a unary rpc
+// call to its status.
+func errorUnaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	resp, err := handler(ctx, req)
+	return resp, rpcError(err)
+}
+
+// errorStreamInterceptor converts any known error returned by a streaming
+// rpc call to its status.
+func errorStreamInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	return rpcError(handler(srv, stream))
+}
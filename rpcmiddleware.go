@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// middlewareTimeout is how long we wait on a middleware to accept, reject,
+// or modify a request before rejecting it ourselves.
+const middlewareTimeout = 5 * time.Second
+
+// registerMiddlewareMethod is the method middleware registers through,
+// which is never intercepted.
+const registerMiddlewareMethod = "/lnrpc.Lightning/RegisterRPCMiddleware"
+
+var (
+	// ErrMiddlewareTimeout is returned when a middleware fails to respond
+	// to a request in time.
+	ErrMiddlewareTimeout = fmt.Errorf("timed out waiting on rpc middleware")
+
+	// ErrMiddlewareExiting is returned when a middleware unregisters
+	// while a request awaits its response.
+	ErrMiddlewareExiting = fmt.Errorf("rpc middleware exiting")
+)
+
+// rpcMiddleware is an external process registered to inspect, and unless
+// read-only, modify or reject, each rpc request and response.
+type rpcMiddleware struct {
+	name     string
+	readOnly bool
+
+	stream  lnrpc.Lightning_RegisterRPCMiddlewareServer
+	sendMtx sync.Mutex
+
+	// pending maps the ID of each request sent to the middleware to the
+	// channel its response is delivered on.
+	pending map[uint64]chan *lnrpc.RPCMiddlewareResponse
+	sync.Mutex
+
+	quit chan struct{}
+}
+
+// middlewareRegistry tracks each registered middleware, passing each rpc
+// request, and response, through them in the order they registered.
+type middlewareRegistry struct {
+	nextRequestID uint64 // To be used atomically.
+
+	middlewares []*rpcMiddleware
+	sync.RWMutex
+}
+
+// newMiddlewareRegistry creates a new registry, without any middleware.
+func newMiddlewareRegistry() *middlewareRegistry {
+	return &middlewareRegistry{}
+}
+
+// register adds the middleware of the passed stream, whose first message
+// names it, then delivers its responses until the stream closes, at which
+// point it's removed.
+func (m *middlewareRegistry) register(stream lnrpc.Lightning_RegisterRPCMiddlewareServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if msg.MiddlewareName == "" {
+		return fmt.Errorf("middleware must be registered with a name")
+	}
+
+	middleware := &rpcMiddleware{
+		name:     msg.MiddlewareName,
+		readOnly: msg.ReadOnly,
+		stream:   stream,
+		pending:  make(map[uint64]chan *lnrpc.RPCMiddlewareResponse),
+		quit:     make(chan struct{}),
+	}
+
+	m.Lock()
+	for _, other := range m.middlewares {
+		if other.name == middleware.name {
+			m.Unlock()
+			return fmt.Errorf("middleware %v already registered",
+				middleware.name)
+		}
+	}
+	m.middlewares = append(m.middlewares, middleware)
+	m.Unlock()
+
+	defer m.unregister(middleware)
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		middleware.Lock()
+		respChan, ok := middleware.pending[resp.RequestID]
+		delete(middleware.pending, resp.RequestID)
+		middleware.Unlock()
+
+		if ok {
+			respChan <- resp
+		}
+	}
+}
+
+// unregister removes the middleware, failing any requests awaiting its
+// response.
+func (m *middlewareRegistry) unregister(middleware *rpcMiddleware) {
+	m.Lock()
+	defer m.Unlock()
+
+	for i, other := range m.middlewares {
+		if other == middleware {
+			m.middlewares = append(m.middlewares[:i],
+				m.middlewares[i+1:]...)
+			break
+		}
+	}
+	close(middleware.quit)
+}
+
+// intercept passes the message through each middleware in turn, returning
+// the message as modified by them, or an error if any rejected it. For
+// streaming rpcs, only the opening of the stream is intercepted, passing a
+// nil message.
+func (m *middlewareRegistry) intercept(fullMethod string, streamRPC,
+	isResponse bool, msg proto.Message) (proto.Message, error) {
+
+	m.RLock()
+	middlewares := make([]*rpcMiddleware, len(m.middlewares))
+	copy(middlewares, m.middlewares)
+	m.RUnlock()
+
+	for _, middleware := range middlewares {
+		req := &lnrpc.RPCMiddlewareRequest{
+			RequestID:  atomic.AddUint64(&m.nextRequestID, 1),
+			FullMethod: fullMethod,
+			StreamRPC:  streamRPC,
+			IsResponse: isResponse,
+		}
+		if msg != nil {
+			serialized, err := proto.Marshal(msg)
+			if err != nil {
+				return nil, err
+			}
+			req.TypeName = proto.MessageName(msg)
+			req.Serialized = serialized
+		}
+
+		resp, err := middleware.send(req)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %v failed: %v",
+				middleware.name, err)
+		}
+
+		if resp.Reject {
+			return nil, fmt.Errorf("rejected by middleware %v: %v",
+				middleware.name, resp.Error)
+		}
+		if len(resp.Replacement) == 0 {
+			continue
+		}
+		if middleware.readOnly || msg == nil {
+			return nil, fmt.Errorf("middleware %v can't modify %v",
+				middleware.name, fullMethod)
+		}
+
+		replacement := reflect.New(reflect.TypeOf(msg).Elem()).
+			Interface().(proto.Message)
+		if err := proto.Unmarshal(resp.Replacement, replacement); err != nil {
+			return nil, fmt.Errorf("invalid replacement from "+
+				"middleware %v: %v", middleware.name, err)
+		}
+		msg = replacement
+	}
+
+	return msg, nil
+}
+
+// send sends the request to the middleware, returning its response.
+func (r *rpcMiddleware) send(req *lnrpc.RPCMiddlewareRequest) (*lnrpc.RPCMiddlewareResponse, error) {
+	respChan := make(chan *lnrpc.RPCMiddlewareResponse, 1)
+
+	r.Lock()
+	r.pending[req.RequestID] = respChan
+	r.Unlock()
+
+	defer func() {
+		r.Lock()
+		delete(r.pending, req.RequestID)
+		r.Unlock()
+	}()
+
+	r.sendMtx.Lock()
+	err := r.stream.Send(req)
+	r.sendMtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respChan:
+		return resp, nil
+	case <-time.After(middlewareTimeout):
+		return nil, ErrMiddlewareTimeout
+	case <-r.quit:
+		return nil, ErrMiddlewareExiting
+	}
+}
+
+// unaryInterceptor passes each unary rpc request, and its response, through
+// the registered middleware.
+func (m *middlewareRegistry) unaryInterceptor(ctx context.Context,
+	req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	msg, err := m.intercept(info.FullMethod, false, false,
+		req.(proto.Message))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.intercept(info.FullMethod, false, true, resp.(proto.Message))
+}
+
+// streamInterceptor passes the opening of each streaming rpc through the
+// registered middleware.
+func (m *middlewareRegistry) streamInterceptor(srv interface{},
+	stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	if info.FullMethod == registerMiddlewareMethod {
+		return handler(srv, stream)
+	}
+
+	if _, err := m.intercept(info.FullMethod, true, false, nil); err != nil {
+		return err
+	}
+
+	return handler(srv, stream)
+}
@@ -1,21 +1,47 @@
 package main
 
 import (
+	"bytes"
+	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"sync"
 	"sync/atomic"
 
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/accounting"
+	"github.com/lightningnetwork/lnd/chainntfs"
+	"github.com/lightningnetwork/lnd/chanfitness"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/lndc"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/psbt"
+	"github.com/lightningnetwork/lnd/routing"
 	"golang.org/x/net/context"
 )
 
 var (
 	defaultAccount uint32 = waddrmgr.DefaultAccountNum
+
+	// defaultMaxPayments is the number of payments returned by
+	// ListPayments if the request doesn't specify a limit.
+	defaultMaxPayments uint64 = 100
+
+	// defaultInvoiceExpiry is how long invoices added over rpc remain
+	// payable if the request doesn't specify an expiry.
+	defaultInvoiceExpiry = time.Hour
 )
 
 // rpcServer...
@@ -25,6 +51,10 @@ type rpcServer struct {
 
 	server *server
 
+	// middleware passes each rpc through the external processes which
+	// have registered to intercept them.
+	middleware *middlewareRegistry
+
 	wg sync.WaitGroup
 
 	quit chan struct{}
@@ -34,7 +64,11 @@ var _ lnrpc.LightningServer = (*rpcServer)(nil)
 
 // newRPCServer...
 func newRPCServer(s *server) *rpcServer {
-	return &rpcServer{server: s, quit: make(chan struct{}, 1)}
+	return &rpcServer{
+		server:     s,
+		middleware: newMiddlewareRegistry(),
+		quit:       make(chan struct{}, 1),
+	}
 }
 
 // Start...
@@ -71,6 +105,118 @@ func (r *rpcServer) SendMany(ctx context.Context, in *lnrpc.SendManyRequest) (*l
 	return &lnrpc.SendManyResponse{Txid: hex.EncodeToString(txid[:])}, nil
 }
 
+// EstimateFee returns the fee a transaction paying the amounts to the
+// addresses would pay to confirm within the target number of blocks, by
+// selecting coins with at least the requested number of confirmations just
+// as a send would, without broadcasting anything.
+func (r *rpcServer) EstimateFee(ctx context.Context,
+	in *lnrpc.EstimateFeeRequest) (*lnrpc.EstimateFeeResponse, error) {
+
+	if len(in.AddrToAmount) == 0 {
+		return nil, fmt.Errorf("at least one output must be specified")
+	}
+	if in.MinConfs < 0 {
+		return nil, fmt.Errorf("minConfs must be non-negative")
+	}
+	minConfs := in.MinConfs
+	if minConfs == 0 {
+		minConfs = 1
+	}
+
+	outputs := make([]*wire.TxOut, 0, len(in.AddrToAmount))
+	for addrStr, amt := range in.AddrToAmount {
+		if amt <= 0 {
+			return nil, fmt.Errorf("amount paid to %v must be "+
+				"positive", addrStr)
+		}
+		addr, err := btcutil.DecodeAddress(addrStr, r.server.bitcoinNet)
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, wire.NewTxOut(amt, pkScript))
+	}
+
+	feePerKb, err := r.server.lnwallet.EstimateFeePerKb(in.TargetConf)
+	if err != nil {
+		return nil, err
+	}
+	estimate, err := r.server.lnwallet.EstimateSendFee(outputs, feePerKb,
+		minConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.EstimateFeeResponse{
+		FeeSat:    int64(estimate.Fee),
+		SatPerKb:  int64(estimate.FeePerKb),
+		NumInputs: uint32(estimate.NumInputs),
+	}, nil
+}
+
+// PublishTransaction publishes the raw transaction through our backend,
+// re-publishing it until it confirms. Transactions the backend rejects are
+// refused with ErrDoubleSpend, ErrFeeTooLow, or ErrMissingInputs for the
+// common reasons of rejection.
+func (r *rpcServer) PublishTransaction(ctx context.Context,
+	in *lnrpc.PublishTransactionRequest) (*lnrpc.PublishTransactionResponse, error) {
+
+	tx := wire.NewMsgTx()
+	if err := tx.Deserialize(bytes.NewReader(in.RawTx)); err != nil {
+		return nil, err
+	}
+
+	label := in.Label
+	if label == "" {
+		label = "external"
+	}
+	if err := r.server.lnwallet.PublishTransaction(tx, label); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.PublishTransactionResponse{
+		Txid: tx.TxSha().String(),
+	}, nil
+}
+
+// GetTransaction returns the details of a transaction paying to, or spending
+// from, the wallet.
+func (r *rpcServer) GetTransaction(ctx context.Context,
+	in *lnrpc.GetTransactionRequest) (*lnrpc.GetTransactionResponse, error) {
+
+	txid, err := wire.NewShaHashFromStr(in.Txid)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := r.server.lnwallet.GetTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.GetTransactionResponse{
+		Txid:          detail.Hash.String(),
+		Confirmations: detail.Confirmations,
+		BlockHash:     detail.BlockHash,
+		Timestamp:     detail.Timestamp.Unix(),
+		TotalFees:     int64(detail.Fee),
+		Amount:        int64(detail.NetAmount),
+		Label:         detail.Label,
+	}
+	for _, output := range detail.Outputs {
+		resp.Outputs = append(resp.Outputs, &lnrpc.TransactionOutput{
+			Address:     output.Address,
+			OutputIndex: output.OutputIndex,
+			Amount:      int64(output.Amount),
+		})
+	}
+
+	return resp, nil
+}
+
 // NewAddress...
 func (r *rpcServer) NewAddress(ctx context.Context, in *lnrpc.NewAddressRequest) (*lnrpc.NewAddressResponse, error) {
 
@@ -85,6 +231,189 @@ func (r *rpcServer) NewAddress(ctx context.Context, in *lnrpc.NewAddressRequest)
 	return &lnrpc.NewAddressResponse{Address: addr.String()}, nil
 }
 
+// GetRecoveryInfo returns the progress of the rescan recovering the wallet's
+// on-chain funds after being restored from its seed. If the rescan halted,
+// the error which halted it is returned.
+func (r *rpcServer) GetRecoveryInfo(ctx context.Context,
+	in *lnrpc.GetRecoveryInfoRequest) (*lnrpc.GetRecoveryInfoResponse, error) {
+
+	info := r.server.lnwallet.RecoveryInfo()
+	if info.Err != nil {
+		return nil, fmt.Errorf("recovery rescan halted: %v", info.Err)
+	}
+
+	return &lnrpc.GetRecoveryInfoResponse{
+		RecoveryMode:     info.RecoveryMode,
+		RecoveryFinished: info.RecoveryFinished,
+		Progress:         info.Progress,
+	}, nil
+}
+
+// WalletBalance returns the total value of the wallet's outputs with at least
+// the requested number of confirmations.
+func (r *rpcServer) WalletBalance(ctx context.Context,
+	in *lnrpc.WalletBalanceRequest) (*lnrpc.WalletBalanceResponse, error) {
+
+	if in.MinConfs < 0 {
+		return nil, fmt.Errorf("minConfs must be non-negative")
+	}
+
+	balance, err := r.server.lnwallet.CalculateBalance(in.MinConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.WalletBalanceResponse{Balance: int64(balance)}, nil
+}
+
+// ChannelBalance sums our balance, and the counterparty's, across our
+// channels, along with the HTLCs in flight within them, and the balances of
+// channels which are yet to open, both in total and for each commitment
+// type. Channels whose funding transaction is yet to confirm are pending,
+// unless they're zero-conf. The spendable, and receivable, balances are the
+// most we're able to send, and receive, over our channels with connected
+// peers.
+func (r *rpcServer) ChannelBalance(ctx context.Context,
+	in *lnrpc.ChannelBalanceRequest) (*lnrpc.ChannelBalanceResponse, error) {
+
+	channels, err := r.server.lnwallet.ChannelDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	peers, err := r.server.ListPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	// The HTLCs in flight are only known to the live channels of our
+	// connected peers, which are matched up with the stored channels by
+	// their funding txid.
+	live := make(map[[32]byte]*lnwallet.LightningChannel)
+	for _, p := range peers {
+		p.RLock()
+		channel := p.lnChannel
+		p.RUnlock()
+		if channel == nil {
+			continue
+		}
+		chanPoint, err := channel.ChannelPoint()
+		if err != nil {
+			return nil, err
+		}
+		live[[32]byte(chanPoint.Hash)] = channel
+	}
+
+	total := &lnrpc.ChannelTypeBalance{}
+	byType := make(map[channeldb.CommitmentType]*lnrpc.ChannelTypeBalance)
+	balanceOf := func(commitType channeldb.CommitmentType) *lnrpc.ChannelTypeBalance {
+		balance, ok := byType[commitType]
+		if !ok {
+			balance = &lnrpc.ChannelTypeBalance{
+				CommitmentType: commitType.String(),
+			}
+			byType[commitType] = balance
+		}
+		return balance
+	}
+
+	for _, channel := range channels {
+		local := uint64(channel.OurBalance)
+		remote := uint64(channel.TheirBalance)
+		pending := channel.ShortChanID == 0 && !channel.ZeroConf
+
+		var unsettledLocal, unsettledRemote uint64
+		if lnChannel, ok := live[channel.ChanID]; ok {
+			ours, theirs := lnChannel.UnsettledBalances()
+			unsettledLocal, unsettledRemote = uint64(ours), uint64(theirs)
+		}
+
+		for _, balance := range []*lnrpc.ChannelTypeBalance{
+			total, balanceOf(channel.CommitType),
+		} {
+			balance.NumChannels++
+			if pending {
+				balance.PendingOpenLocalMsat += local
+				balance.PendingOpenRemoteMsat += remote
+				continue
+			}
+			balance.LocalBalanceMsat += local
+			balance.RemoteBalanceMsat += remote
+			balance.UnsettledLocalMsat += unsettledLocal
+			balance.UnsettledRemoteMsat += unsettledRemote
+		}
+	}
+
+	// Reservations are yet to become channels, so they only add to the
+	// pending balances.
+	for _, res := range r.server.lnwallet.PendingReservations() {
+		local := uint64(lnwire.NewMSatFromSatoshis(res.FundingAmount))
+		remote := uint64(lnwire.NewMSatFromSatoshis(
+			res.Capacity - res.FundingAmount))
+
+		for _, balance := range []*lnrpc.ChannelTypeBalance{
+			total, balanceOf(res.CommitType),
+		} {
+			balance.PendingOpenLocalMsat += local
+			balance.PendingOpenRemoteMsat += remote
+		}
+	}
+
+	liquidity, err := r.server.channelLiquidity()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ChannelBalanceResponse{
+		Total:          total,
+		SpendableMsat:  uint64(liquidity.sendable),
+		ReceivableMsat: uint64(liquidity.receivable),
+	}
+	for _, commitType := range []channeldb.CommitmentType{
+		channeldb.CommitmentLegacy, channeldb.CommitmentAnchors,
+		channeldb.CommitmentStaticRemoteKey,
+	} {
+		if balance, ok := byType[commitType]; ok {
+			resp.ByType = append(resp.ByType, balance)
+		}
+	}
+
+	return resp, nil
+}
+
+// GetInfo returns our identity public key, along with the number of peers
+// we're connected to.
+func (r *rpcServer) GetInfo(ctx context.Context,
+	in *lnrpc.GetInfoRequest) (*lnrpc.GetInfoResponse, error) {
+
+	peers, err := r.server.ListPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	idPub := r.server.identity.PubKey().SerializeCompressed()
+	resp := &lnrpc.GetInfoResponse{
+		IdentityPubkey: hex.EncodeToString(idPub),
+		NumPeers:       uint32(len(peers)),
+		SyncedToChain:  r.server.lnwallet.IsSynced(),
+	}
+
+	// Addresses yet to be dialed back are reported as neither reachable,
+	// nor with an error.
+	for _, result := range r.server.reachability.Results() {
+		addr := &lnrpc.AddressReachability{Address: result.addr}
+		if !result.checkedAt.IsZero() {
+			addr.CheckedAt = result.checkedAt.Unix()
+			addr.Reachable = result.err == nil
+			if result.err != nil {
+				addr.Error = result.err.Error()
+			}
+		}
+		resp.ExternalAddresses = append(resp.ExternalAddresses, addr)
+	}
+
+	return resp, nil
+}
+
 // LNConnect...
 func (r *rpcServer) ConnectPeer(ctx context.Context,
 	in *lnrpc.ConnectPeerRequest) (*lnrpc.ConnectPeerResponse, error) {
@@ -98,9 +427,1874 @@ func (r *rpcServer) ConnectPeer(ctx context.Context,
 		return nil, err
 	}
 
-	if err := r.server.ConnectToPeer(peerAddr); err != nil {
+	if err := r.server.ConnectToPeer(peerAddr, in.Perm); err != nil {
 		return nil, err
 	}
 
 	return &lnrpc.ConnectPeerResponse{[]byte(peerAddr.String())}, nil
 }
+
+// DisconnectPeer disconnects from the peer with the passed public key. If we
+// have an open, or pending, channel with the peer, it's refused unless
+// forced.
+func (r *rpcServer) DisconnectPeer(ctx context.Context,
+	in *lnrpc.DisconnectPeerRequest) (*lnrpc.DisconnectPeerResponse, error) {
+
+	pubKeyBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.DisconnectPeer(pubKey, in.Force); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DisconnectPeerResponse{}, nil
+}
+
+// ListPeers returns each connected peer. For peers we maintain a persistent
+// connection to, the flap count, and the backoff in seconds before we
+// reconnect once the connection drops, are included.
+func (r *rpcServer) ListPeers(ctx context.Context,
+	in *lnrpc.ListPeersRequest) (*lnrpc.ListPeersResponse, error) {
+
+	peers, err := r.server.ListPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := r.server.lnwallet.ChannelDB.FetchPeerLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListPeersResponse{
+		Peers: make([]*lnrpc.Peer, 0, len(peers)),
+	}
+	for _, p := range peers {
+		rpcPeer := &lnrpc.Peer{
+			Address: p.conn.RemoteAddr().String(),
+			PeerId:  p.peerID,
+			Inbound: p.inbound,
+		}
+
+		if pubKey := p.remotePub(); pubKey != nil {
+			rpcPeer.PubKey = hex.EncodeToString(
+				pubKey.SerializeCompressed())
+
+			flaps, backoff, ok := r.server.persistentPeerInfo(pubKey)
+			rpcPeer.Perm = ok
+			rpcPeer.FlapCount = flaps
+			rpcPeer.Backoff = int64(backoff.Seconds())
+
+			var key [33]byte
+			copy(key[:], pubKey.SerializeCompressed())
+			rpcPeer.Label = labels[key]
+		}
+
+		resp.Peers = append(resp.Peers, rpcPeer)
+	}
+
+	return resp, nil
+}
+
+// SetPeerLabel attaches a label of the user's to the peer, shown alongside
+// it by ListPeers. The peer needn't be connected, and an empty label removes
+// it.
+func (r *rpcServer) SetPeerLabel(ctx context.Context,
+	in *lnrpc.SetPeerLabelRequest) (*lnrpc.SetPeerLabelResponse, error) {
+
+	pubKeyBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	var key [33]byte
+	copy(key[:], pubKey.SerializeCompressed())
+	if err := r.server.lnwallet.ChannelDB.PutPeerLabel(key, in.Label); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SetPeerLabelResponse{}, nil
+}
+
+// ListPeerBackups returns the backup of our channels each of our peers has
+// handed back since we started, for recovering our channels after losing
+// our data.
+func (r *rpcServer) ListPeerBackups(ctx context.Context,
+	in *lnrpc.ListPeerBackupsRequest) (*lnrpc.ListPeerBackupsResponse, error) {
+
+	resp := &lnrpc.ListPeerBackupsResponse{}
+	for key, backup := range r.server.peerBackups.RecoveredBackups() {
+		peerBackup := &lnrpc.PeerBackup{
+			PubKey: hex.EncodeToString(key[:]),
+		}
+		for _, single := range backup.Singles {
+			lnID := single.RemoteLNID
+			peerBackup.Channels = append(peerBackup.Channels,
+				&lnrpc.ChannelBackup{
+					LnID:        lnID[:],
+					FundingTxid: single.FundingTxid.String(),
+					ChanId:      single.ShortChanID.ToUint64(),
+					Capacity:    int64(single.Capacity),
+				})
+		}
+
+		resp.Backups = append(resp.Backups, peerBackup)
+	}
+
+	return resp, nil
+}
+
+// parsePeerData parses the hex encoded public key of the peer data is sent
+// to, or received from, along with the type of the data.
+func parsePeerData(pubKeyStr string, dataType uint32) (*btcec.PublicKey,
+	uint16, error) {
+
+	pubKeyBytes, err := hex.DecodeString(pubKeyStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, 0, err
+	}
+	if dataType > math.MaxUint16 {
+		return nil, 0, fmt.Errorf("data type %v exceeds the maximum "+
+			"of %v", dataType, math.MaxUint16)
+	}
+
+	return pubKey, uint16(dataType), nil
+}
+
+// SendSignedData sends the data to the connected peer, signed by our
+// identity key within our session with it, on behalf of an application
+// layered on top of the connection.
+func (r *rpcServer) SendSignedData(ctx context.Context,
+	in *lnrpc.SendSignedDataRequest) (*lnrpc.SendSignedDataResponse, error) {
+
+	pubKey, dataType, err := parsePeerData(in.PubKey, in.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.SendSignedData(pubKey, dataType, in.Data); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendSignedDataResponse{}, nil
+}
+
+// SubscribeSignedData sends each piece of data our peers send us from now
+// on, its signature having been verified against the identity key of the
+// peer. Data with an invalid signature is dropped.
+func (r *rpcServer) SubscribeSignedData(in *lnrpc.SubscribeSignedDataRequest,
+	updateStream lnrpc.Lightning_SubscribeSignedDataServer) error {
+
+	client := r.server.peerData.Subscribe()
+	defer client.Cancel()
+
+	for {
+		select {
+		case msg, ok := <-client.msgs:
+			if !ok {
+				return fmt.Errorf("signed data stream ended, " +
+					"client too slow or server shutting down")
+			}
+
+			err := updateStream.Send(&lnrpc.SignedPeerData{
+				PubKey: hex.EncodeToString(
+					msg.pubKey.SerializeCompressed(),
+				),
+				Type:      uint32(msg.msg.Type),
+				Data:      msg.msg.Data,
+				Signature: msg.msg.Signature.Serialize(),
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+		}
+	}
+}
+
+// SignPeerData signs the data with our identity key within our session with
+// the connected peer, for applications exchanging signed data with the peer
+// by other means. The signature is only valid until either of us
+// disconnects.
+func (r *rpcServer) SignPeerData(ctx context.Context,
+	in *lnrpc.SignPeerDataRequest) (*lnrpc.SignPeerDataResponse, error) {
+
+	pubKey, dataType, err := parsePeerData(in.PubKey, in.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := r.server.SignPeerData(pubKey, dataType, in.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SignPeerDataResponse{
+		Signature: sig.Serialize(),
+	}, nil
+}
+
+// VerifyPeerData checks that the signature over the data was made by the
+// identity key of the connected peer, within our current session with it.
+func (r *rpcServer) VerifyPeerData(ctx context.Context,
+	in *lnrpc.VerifyPeerDataRequest) (*lnrpc.VerifyPeerDataResponse, error) {
+
+	pubKey, dataType, err := parsePeerData(in.PubKey, in.Type)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := btcec.ParseDERSignature(in.Signature, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := r.server.VerifyPeerData(pubKey, dataType, in.Data, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.VerifyPeerDataResponse{Valid: valid}, nil
+}
+
+// ListPeerMisbehavior returns the protocol violations each peer has
+// committed, oldest first, or only those of the peer with the passed public
+// key if one is set.
+func (r *rpcServer) ListPeerMisbehavior(ctx context.Context,
+	in *lnrpc.ListPeerMisbehaviorRequest) (*lnrpc.ListPeerMisbehaviorResponse, error) {
+
+	logs, err := r.server.lnwallet.ChannelDB.FetchMisbehaviors()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListPeerMisbehaviorResponse{}
+	for key, misbehaviors := range logs {
+		pubKey := hex.EncodeToString(key[:])
+		if in.PubKey != "" && in.PubKey != pubKey {
+			continue
+		}
+
+		peerLog := &lnrpc.PeerMisbehavior{PubKey: pubKey}
+		for _, m := range misbehaviors {
+			peerLog.Misbehaviors = append(peerLog.Misbehaviors,
+				&lnrpc.Misbehavior{
+					Type:        m.Type.String(),
+					Timestamp:   m.Timestamp.Unix(),
+					Description: m.Description,
+				})
+		}
+		resp.Peers = append(resp.Peers, peerLog)
+	}
+
+	return resp, nil
+}
+
+// SendPayment pays the destination, returning the preimage of the payment
+// hash once the payment succeeds. Retrying the call with the same payment
+// hash attaches to the payment in flight, rather than paying twice.
+//
+// NOTE: Payments are limited for now. As we're yet to wrap the forwarding
+// instructions of each hop within an onion, only the peers we have channels
+// with are able to be paid. And as HTLCs can't yet be locked into the
+// commitments of our channels, every payment fails with ErrHTLCsUncommitted,
+// rather than succeeding without funds having moved.
+func (r *rpcServer) SendPayment(ctx context.Context,
+	in *lnrpc.SendPaymentRequest) (*lnrpc.SendPaymentResponse, error) {
+
+	destBytes, err := hex.DecodeString(in.Dest)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := btcec.ParsePubKey(destBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(in.PaymentHash) != 20 {
+		return nil, fmt.Errorf("payment hash must be 20 bytes, "+
+			"instead got %v", len(in.PaymentHash))
+	}
+	var paymentHash [20]byte
+	copy(paymentHash[:], in.PaymentHash)
+
+	// The payment secret is taken from the invoice being paid.
+	var paymentSecret [32]byte
+	if len(in.PaymentSecret) != 0 && len(in.PaymentSecret) != 32 {
+		return nil, fmt.Errorf("payment secret must be 32 bytes, "+
+			"instead got %v", len(in.PaymentSecret))
+	}
+	copy(paymentSecret[:], in.PaymentSecret)
+
+	amt := lnwire.MilliSatoshi(in.AmtMsat)
+	if amt == 0 {
+		amt = lnwire.NewMSatFromSatoshis(btcutil.Amount(in.Amt))
+	}
+	if amt == 0 {
+		return nil, fmt.Errorf("payment amount must be positive")
+	}
+
+	feeLimit, err := parseFeeLimit(in.FeeLimit, amt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Until we've synced to the chain, we can't tell whether the
+	// expiries of our HTLCs are safe, so no payments are sent.
+	if !r.server.lnwallet.IsSynced() {
+		return nil, lnwallet.ErrNotSynced
+	}
+
+	// Payments we're unable to offer over our channels would only fail
+	// once routed, so they're refused upfront. Those in flight have
+	// already taken up their liquidity, and are attached to as is.
+	if !r.server.payments.IsPending(paymentHash) {
+		liquidity, err := r.server.channelLiquidity()
+		if err != nil {
+			return nil, err
+		}
+		if err := liquidity.checkSend(amt, in.MaxParts); err != nil {
+			return nil, err
+		}
+	}
+
+	preimage, err := r.server.payments.SendPayment(&paymentRequest{
+		dest:            dest,
+		amt:             amt,
+		paymentHash:     paymentHash,
+		paymentSecret:   paymentSecret,
+		timeout:         time.Duration(in.TimeoutSeconds) * time.Second,
+		feeLimit:        feeLimit,
+		maxParts:        in.MaxParts,
+		cltvLimit:       in.CltvLimit,
+		outgoingChanIDs: parseOutgoingChanIDs(in.OutgoingChanIds),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendPaymentResponse{PaymentPreimage: preimage[:]}, nil
+}
+
+// parseFeeLimit returns the most a payment of the amount may pay in fees
+// under the passed limit, which may be either fixed, or a percentage of the
+// amount. Without a limit, there's no limit on fees.
+func parseFeeLimit(limit *lnrpc.FeeLimit,
+	amt lnwire.MilliSatoshi) (lnwire.MilliSatoshi, error) {
+
+	if limit == nil {
+		return routing.NoFeeLimit, nil
+	}
+
+	var numSet int
+	for _, v := range []int64{limit.Fixed, limit.FixedMsat, limit.Percent} {
+		switch {
+		case v < 0:
+			return 0, fmt.Errorf("fee limit must not be negative")
+		case v > 0:
+			numSet++
+		}
+	}
+	if numSet > 1 {
+		return 0, fmt.Errorf("only one of fixed, fixed_msat, or " +
+			"percent may be set as the fee limit")
+	}
+
+	switch {
+	case limit.FixedMsat != 0:
+		return lnwire.MilliSatoshi(limit.FixedMsat), nil
+	case limit.Percent != 0:
+		return amt * lnwire.MilliSatoshi(limit.Percent) / 100, nil
+	default:
+		return lnwire.NewMSatFromSatoshis(btcutil.Amount(limit.Fixed)), nil
+	}
+}
+
+// parseOutgoingChanIDs returns the set of channels a payment is pinned to
+// leave over, or nil if it isn't pinned.
+func parseOutgoingChanIDs(
+	chanIDs []uint64) map[lnwire.ShortChannelID]struct{} {
+
+	if len(chanIDs) == 0 {
+		return nil
+	}
+
+	outgoingChans := make(map[lnwire.ShortChannelID]struct{}, len(chanIDs))
+	for _, chanID := range chanIDs {
+		outgoingChans[lnwire.NewShortChanIDFromInt(chanID)] = struct{}{}
+	}
+	return outgoingChans
+}
+
+// parseLastHop parses the public key of the node a route is pinned to
+// arrive through, returning nil if it isn't pinned.
+func parseLastHop(pubKey []byte) (*btcec.PublicKey, error) {
+	if len(pubKey) == 0 {
+		return nil, nil
+	}
+
+	return btcec.ParsePubKey(pubKey, btcec.S256())
+}
+
+// QueryRoutes returns the route a payment of the amount to the destination
+// would take, within the passed restrictions, without sending it.
+func (r *rpcServer) QueryRoutes(ctx context.Context,
+	in *lnrpc.QueryRoutesRequest) (*lnrpc.QueryRoutesResponse, error) {
+
+	destBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := btcec.ParsePubKey(destBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	amt := lnwire.MilliSatoshi(in.AmtMsat)
+	if amt == 0 {
+		amt = lnwire.NewMSatFromSatoshis(btcutil.Amount(in.Amt))
+	}
+	if amt == 0 {
+		return nil, fmt.Errorf("route amount must be positive")
+	}
+
+	feeLimit, err := parseFeeLimit(in.FeeLimit, amt)
+	if err != nil {
+		return nil, err
+	}
+	lastHop, err := parseLastHop(in.LastHopPubkey)
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := r.server.findRoute(dest, amt, &routing.RestrictParams{
+		FeeLimit:           feeLimit,
+		CltvLimit:          in.CltvLimit,
+		OutgoingChannelIDs: parseOutgoingChanIDs(in.OutgoingChanIds),
+		LastHop:            lastHop,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.QueryRoutesResponse{
+		Routes: []*lnrpc.Route{marshalRoute(route)},
+	}, nil
+}
+
+// EstimateRouteFee returns the fee, and time lock, a payment of the amount to
+// the destination is expected to take, being those of the route pathfinding
+// finds. The time lock is the number of blocks the payment may be locked up
+// for.
+func (r *rpcServer) EstimateRouteFee(ctx context.Context,
+	in *lnrpc.EstimateRouteFeeRequest) (*lnrpc.EstimateRouteFeeResponse, error) {
+
+	destBytes, err := hex.DecodeString(in.Dest)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := btcec.ParsePubKey(destBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	amt := lnwire.MilliSatoshi(in.AmtMsat)
+	if amt == 0 {
+		amt = lnwire.NewMSatFromSatoshis(btcutil.Amount(in.Amt))
+	}
+	if amt == 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	_, height, err := r.server.lnwallet.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	route, err := r.server.findRoute(dest, amt,
+		&routing.RestrictParams{FeeLimit: routing.NoFeeLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.EstimateRouteFeeResponse{
+		RoutingFeeMsat: uint64(route.TotalFees),
+		NumHops:        uint32(len(route.Hops)),
+	}
+	if route.TotalTimeLock > uint32(height) {
+		resp.TimeLockDelay = route.TotalTimeLock - uint32(height)
+	}
+
+	return resp, nil
+}
+
+// ListPayments returns a page of outgoing payments, along with every attempt
+// made to complete each payment.
+func (r *rpcServer) ListPayments(ctx context.Context,
+	in *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
+
+	maxPayments := in.MaxPayments
+	if maxPayments == 0 {
+		maxPayments = defaultMaxPayments
+	}
+
+	payments, err := r.server.lnwallet.ChannelDB.FetchPayments(in.IndexOffset,
+		maxPayments)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListPaymentsResponse{
+		LastIndexOffset: in.IndexOffset,
+	}
+	for _, payment := range payments {
+		rpcPayment := &lnrpc.Payment{
+			PaymentIndex: payment.PaymentID,
+			PaymentHash:  payment.PaymentHash[:],
+			Amount:       int64(payment.Amount.ToSatoshis()),
+			AmountMsat:   uint64(payment.Amount),
+			CreationTime: payment.CreationTime.Unix(),
+			Status:       lnrpc.PaymentStatus(payment.Status),
+		}
+		for _, attempt := range payment.Attempts {
+			route := make([][]byte, 0, len(attempt.Route))
+			for _, hop := range attempt.Route {
+				hopID := hop
+				route = append(route, hopID[:])
+			}
+
+			rpcPayment.Attempts = append(rpcPayment.Attempts,
+				&lnrpc.PaymentAttempt{
+					HtlcKey:       attempt.HTLCKey,
+					Route:         route,
+					Amount:        int64(attempt.Amount.ToSatoshis()),
+					AmountMsat:    uint64(attempt.Amount),
+					AttemptTime:   attempt.AttemptTime.Unix(),
+					Status:        lnrpc.PaymentStatus(attempt.Status),
+					FailureReason: attempt.FailureReason,
+				})
+		}
+
+		resp.Payments = append(resp.Payments, rpcPayment)
+		resp.LastIndexOffset = payment.PaymentID
+	}
+
+	return resp, nil
+}
+
+// DeletePayment removes a single payment, or only its failed attempts, from
+// the payment database.
+func (r *rpcServer) DeletePayment(ctx context.Context,
+	in *lnrpc.DeletePaymentRequest) (*lnrpc.DeletePaymentResponse, error) {
+
+	if len(in.PaymentHash) != 20 {
+		return nil, fmt.Errorf("payment hash must be 20 bytes, "+
+			"instead got %v", len(in.PaymentHash))
+	}
+
+	var paymentHash [20]byte
+	copy(paymentHash[:], in.PaymentHash)
+
+	err := r.server.lnwallet.ChannelDB.DeletePayment(paymentHash,
+		in.FailedAttemptsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeletePaymentResponse{}, nil
+}
+
+// DeleteAllPayments removes all payments, optionally restricted to failed
+// payments or failed attempts, from the payment database.
+func (r *rpcServer) DeleteAllPayments(ctx context.Context,
+	in *lnrpc.DeleteAllPaymentsRequest) (*lnrpc.DeleteAllPaymentsResponse, error) {
+
+	err := r.server.lnwallet.ChannelDB.DeleteAllPayments(
+		in.FailedPaymentsOnly, in.FailedAttemptsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeleteAllPaymentsResponse{}, nil
+}
+
+// ListHtlcs returns each HTLC currently locked in within our channels,
+// optionally restricted to those of a single channel.
+//
+// TODO: only the HTLCs of our own payments are tracked for now,
+// include those we receive and forward once peers hand them to the invoice
+// registry and a switch
+func (r *rpcServer) ListHtlcs(ctx context.Context,
+	in *lnrpc.ListHtlcsRequest) (*lnrpc.ListHtlcsResponse, error) {
+
+	payments, err := r.server.lnwallet.ChannelDB.FetchPayments(0,
+		math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListHtlcsResponse{}
+	for _, payment := range payments {
+		if payment.Status != channeldb.PaymentInFlight {
+			continue
+		}
+
+		for _, attempt := range payment.Attempts {
+			if attempt.Status != channeldb.PaymentInFlight {
+				continue
+			}
+			if in.ChanId != 0 && attempt.ChanID.ToUint64() != in.ChanId {
+				continue
+			}
+
+			resp.Htlcs = append(resp.Htlcs,
+				marshalHtlc(payment, attempt))
+		}
+	}
+
+	return resp, nil
+}
+
+// LookupHtlcResolution returns the final resolution of an HTLC we sent out
+// over the channel, or reports the HTLC as still in flight.
+func (r *rpcServer) LookupHtlcResolution(ctx context.Context,
+	in *lnrpc.LookupHtlcResolutionRequest) (*lnrpc.LookupHtlcResolutionResponse, error) {
+
+	payment, err := r.server.lnwallet.ChannelDB.FetchPaymentByID(
+		in.HtlcKey >> attemptIndexBits)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt := findAttempt(payment, in.HtlcKey)
+	if attempt == nil || attempt.ChanID.ToUint64() != in.ChanId {
+		return nil, fmt.Errorf("no htlc with key %v within channel %v",
+			in.HtlcKey, lnwire.NewShortChanIDFromInt(in.ChanId))
+	}
+
+	resp := &lnrpc.LookupHtlcResolutionResponse{
+		Htlc:          marshalHtlc(payment, attempt),
+		Status:        lnrpc.PaymentStatus(attempt.Status),
+		FailureReason: attempt.FailureReason,
+	}
+	if !attempt.ResolveTime.IsZero() {
+		resp.ResolveTime = attempt.ResolveTime.Unix()
+	}
+
+	return resp, nil
+}
+
+// marshalHtlc converts the HTLC sent out for the payment attempt into its
+// rpc form.
+func marshalHtlc(payment *channeldb.Payment,
+	attempt *channeldb.PaymentAttempt) *lnrpc.Htlc {
+
+	return &lnrpc.Htlc{
+		ChanId:         attempt.ChanID.ToUint64(),
+		HtlcKey:        attempt.HTLCKey,
+		AmountMsat:     uint64(attempt.Amount + attempt.Fee),
+		Expiry:         attempt.Expiry,
+		PaymentHash:    payment.PaymentHash[:],
+		OutgoingChanId: attempt.ChanID.ToUint64(),
+	}
+}
+
+// ExportAccounting collates our on-chain transactions, forwarding fees,
+// settled invoices and succeeded payments into a single ledger, ordered by
+// time, and exports it as either CSV or JSON.
+func (r *rpcServer) ExportAccounting(ctx context.Context,
+	in *lnrpc.ExportAccountingRequest) (*lnrpc.ExportAccountingResponse, error) {
+
+	var start, end time.Time
+	if in.StartTime != 0 {
+		start = time.Unix(in.StartTime, 0)
+	}
+	if in.EndTime != 0 {
+		end = time.Unix(in.EndTime, 0)
+	}
+	if !end.IsZero() && !end.After(start) {
+		return nil, fmt.Errorf("end time must be after start time")
+	}
+
+	chanDB := r.server.lnwallet.ChannelDB
+	invoices, err := chanDB.FetchAllInvoices(false)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := chanDB.FetchPayments(0, math.MaxUint64)
+	if err != nil {
+		return nil, err
+	}
+	forwards, err := chanDB.FetchForwardingEvents(start, end)
+	if err != nil {
+		return nil, err
+	}
+	onChain, err := r.onChainEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]accounting.EntryType, 0, len(in.EntryTypes))
+	for _, entryType := range in.EntryTypes {
+		types = append(types, accounting.EntryType(entryType))
+	}
+
+	ledger := accounting.NewLedger(onChain,
+		accounting.InvoiceEntries(invoices),
+		accounting.PaymentEntries(payments),
+		accounting.ForwardEntries(forwards),
+	).Filter(start, end, types...)
+
+	var b bytes.Buffer
+	switch in.Format {
+	case lnrpc.AccountingFormat_ACCOUNTING_FORMAT_CSV:
+		err = ledger.WriteCSV(&b)
+	case lnrpc.AccountingFormat_ACCOUNTING_FORMAT_JSON:
+		err = ledger.WriteJSON(&b)
+	default:
+		return nil, fmt.Errorf("unknown accounting format: %v",
+			in.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExportAccountingResponse{Data: b.Bytes()}, nil
+}
+
+// onChainEntries returns an entry for each of the wallet's transactions,
+// crediting, or debiting, the net change in our balance. The wallet reports
+// a transaction once for each of our outputs it pays, or spends, so these
+// are summed up by txid.
+func (r *rpcServer) onChainEntries() ([]*accounting.Entry, error) {
+	txns, err := r.server.lnwallet.ListAllTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*accounting.Entry
+	entryIndex := make(map[string]*accounting.Entry)
+	for _, tx := range txns {
+		amt, err := btcutil.NewAmount(tx.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, ok := entryIndex[tx.TxID]
+		if !ok {
+			entry = &accounting.Entry{
+				Timestamp: time.Unix(tx.Time, 0),
+				Type:      accounting.EntryOnChain,
+				Reference: tx.TxID,
+			}
+			entryIndex[tx.TxID] = entry
+			entries = append(entries, entry)
+		}
+
+		// Amounts sent are negative, so can't be converted through
+		// lnwire.MilliSatoshi.
+		entry.AmountMsat += int64(amt) * 1000
+
+		// The fee is repeated for each output spent to, and reported
+		// as negative, so it's only recorded the once.
+		if tx.Fee != nil && entry.FeeMsat == 0 {
+			fee, err := btcutil.NewAmount(-*tx.Fee)
+			if err != nil {
+				return nil, err
+			}
+			entry.FeeMsat = int64(lnwire.NewMSatFromSatoshis(fee))
+		}
+	}
+
+	return entries, nil
+}
+
+// AddInvoice adds a new invoice to the invoice registry, returning the
+// payment hash and payment secret the payer needs to pay it. If no preimage
+// is passed, a random one is generated. Invoices larger than our peers are
+// able to pay us over our channels are refused.
+func (r *rpcServer) AddInvoice(ctx context.Context,
+	in *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+
+	invoice := &channeldb.Invoice{
+		Memo:         in.Memo,
+		CreationDate: time.Now(),
+		Expiry:       time.Duration(in.Expiry) * time.Second,
+		State:        channeldb.InvoiceOpen,
+		AMP:          in.Amp,
+	}
+
+	switch len(in.RPreimage) {
+	case 0:
+		if _, err := rand.Read(invoice.Preimage[:]); err != nil {
+			return nil, err
+		}
+	case 20:
+		copy(invoice.Preimage[:], in.RPreimage)
+	default:
+		return nil, fmt.Errorf("preimage must be 20 bytes, instead "+
+			"got %v", len(in.RPreimage))
+	}
+
+	if len(in.PaymentSecret) != 0 {
+		return nil, fmt.Errorf("payment secret is generated by the " +
+			"registry, and can't be set")
+	}
+
+	if in.Value < 0 || in.Expiry < 0 {
+		return nil, fmt.Errorf("invoice value and expiry mustn't be " +
+			"negative")
+	}
+	invoice.Value = lnwire.MilliSatoshi(in.ValueMsat)
+	if invoice.Value == 0 {
+		invoice.Value = lnwire.NewMSatFromSatoshis(
+			btcutil.Amount(in.Value),
+		)
+	}
+	if in.Expiry == 0 {
+		invoice.Expiry = defaultInvoiceExpiry
+	}
+
+	if err := r.server.invoices.AddInvoice(invoice); err != nil {
+		return nil, err
+	}
+
+	paymentHash := invoice.PaymentHash()
+	return &lnrpc.AddInvoiceResponse{
+		RHash:         paymentHash[:],
+		PaymentSecret: invoice.PaymentSecret[:],
+	}, nil
+}
+
+// SubscribeInvoices streams each invoice from now on as it's settled, or
+// canceled, whether manually or once it expires. For AMP invoices, each
+// payment to the invoice is streamed as it settles, or times out, along
+// with its set ID.
+func (r *rpcServer) SubscribeInvoices(in *lnrpc.InvoiceSubscription,
+	updateStream lnrpc.Lightning_SubscribeInvoicesServer) error {
+
+	client := r.server.invoices.SubscribeNotifications()
+	defer client.Cancel()
+
+	for {
+		select {
+		case event := <-client.Events:
+			invoice := marshalInvoice(event.invoice)
+			invoice.State = lnrpc.InvoiceState(event.state)
+			if event.setID != nil {
+				invoice.SetId = event.setID[:]
+			}
+
+			if err := updateStream.Send(invoice); err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+
+		case <-r.server.quit:
+			return ErrServerShuttingDown
+		}
+	}
+}
+
+// marshalInvoice converts the invoice into its rpc representation.
+func marshalInvoice(invoice *channeldb.Invoice) *lnrpc.Invoice {
+	paymentHash := invoice.PaymentHash()
+	return &lnrpc.Invoice{
+		Memo:          invoice.Memo,
+		RPreimage:     invoice.Preimage[:],
+		RHash:         paymentHash[:],
+		Value:         int64(invoice.Value.ToSatoshis()),
+		ValueMsat:     uint64(invoice.Value),
+		CreationDate:  invoice.CreationDate.Unix(),
+		Expiry:        int64(invoice.Expiry / time.Second),
+		State:         lnrpc.InvoiceState(invoice.State),
+		PaymentSecret: invoice.PaymentSecret[:],
+		Amp:           invoice.AMP,
+	}
+}
+
+// ImportAccount adds a watch-only account backed by an extended public key.
+func (r *rpcServer) ImportAccount(ctx context.Context,
+	in *lnrpc.ImportAccountRequest) (*lnrpc.ImportAccountResponse, error) {
+
+	if in.Name == "" {
+		return nil, fmt.Errorf("account name must be specified")
+	}
+
+	err := r.server.lnwallet.ImportAccount(in.Name, in.ExtendedPublicKey,
+		in.MasterKeyFingerprint, in.DerivationPath, in.Rescan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ImportAccountResponse{}, nil
+}
+
+// ImportPublicKey adds a single public key to a watch-only account.
+func (r *rpcServer) ImportPublicKey(ctx context.Context,
+	in *lnrpc.ImportPublicKeyRequest) (*lnrpc.ImportPublicKeyResponse, error) {
+
+	if in.Account == "" {
+		return nil, fmt.Errorf("account name must be specified")
+	}
+
+	pub, err := btcec.ParsePubKey(in.PublicKey, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.server.lnwallet.ImportPubKey(in.Account, pub, in.Rescan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ImportPublicKeyResponse{}, nil
+}
+
+// FundPsbt funds a PSBT, either from a serialized template packet, or from a
+// set of outputs to pay to.
+func (r *rpcServer) FundPsbt(ctx context.Context,
+	in *lnrpc.FundPsbtRequest) (*lnrpc.FundPsbtResponse, error) {
+
+	var packet *psbt.Packet
+	switch {
+	case len(in.Psbt) != 0 && len(in.Outputs) != 0:
+		return nil, fmt.Errorf("either a template psbt or outputs " +
+			"must be specified, not both")
+
+	case len(in.Psbt) != 0:
+		var err error
+		packet, err = psbt.Parse(bytes.NewReader(in.Psbt))
+		if err != nil {
+			return nil, err
+		}
+
+	case len(in.Outputs) != 0:
+		tx := wire.NewMsgTx()
+		for addrStr, amt := range in.Outputs {
+			addr, err := btcutil.DecodeAddress(addrStr,
+				r.server.bitcoinNet)
+			if err != nil {
+				return nil, err
+			}
+			pkScript, err := txscript.PayToAddrScript(addr)
+			if err != nil {
+				return nil, err
+			}
+			tx.AddTxOut(wire.NewTxOut(amt, pkScript))
+		}
+
+		var err error
+		packet, err = psbt.New(tx)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("a template psbt or outputs must be " +
+			"specified")
+	}
+
+	minConfs := in.MinConfs
+	if minConfs == 0 {
+		minConfs = 1
+	}
+
+	changeIndex, err := r.server.lnwallet.FundPsbt(packet, in.Account,
+		btcutil.Amount(in.SatPerKb), minConfs)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := packet.Serialize(&b); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.FundPsbtResponse{
+		FundedPsbt:        b.Bytes(),
+		ChangeOutputIndex: int32(changeIndex),
+	}, nil
+}
+
+// SignPsbt signs each input of a PSBT which the wallet holds the keys for.
+func (r *rpcServer) SignPsbt(ctx context.Context,
+	in *lnrpc.SignPsbtRequest) (*lnrpc.SignPsbtResponse, error) {
+
+	packet, err := psbt.Parse(bytes.NewReader(in.FundedPsbt))
+	if err != nil {
+		return nil, err
+	}
+
+	signedInputs, err := r.server.lnwallet.SignPsbt(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := packet.Serialize(&b); err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.SignPsbtResponse{SignedPsbt: b.Bytes()}
+	for _, index := range signedInputs {
+		resp.SignedInputs = append(resp.SignedInputs, uint32(index))
+	}
+
+	return resp, nil
+}
+
+// FinalizePsbt signs and finalizes a PSBT, returning the signed transaction,
+// and optionally broadcasting it.
+func (r *rpcServer) FinalizePsbt(ctx context.Context,
+	in *lnrpc.FinalizePsbtRequest) (*lnrpc.FinalizePsbtResponse, error) {
+
+	packet, err := psbt.Parse(bytes.NewReader(in.SignedPsbt))
+	if err != nil {
+		return nil, err
+	}
+
+	finalTx, err := r.server.lnwallet.FinalizePsbt(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Publish {
+		if err := r.server.lnwallet.PublishTransaction(finalTx, "psbt"); err != nil {
+			return nil, err
+		}
+	}
+
+	var packetBuf, txBuf bytes.Buffer
+	if err := packet.Serialize(&packetBuf); err != nil {
+		return nil, err
+	}
+	if err := finalTx.Serialize(&txBuf); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.FinalizePsbtResponse{
+		SignedPsbt: packetBuf.Bytes(),
+		RawFinalTx: txBuf.Bytes(),
+		Txid:       finalTx.TxSha().String(),
+	}, nil
+}
+
+// BumpFee raises the fee rate of an unconfirmed transaction by spending one
+// of its outputs controlled by the wallet with a high fee child transaction.
+func (r *rpcServer) BumpFee(ctx context.Context,
+	in *lnrpc.BumpFeeRequest) (*lnrpc.BumpFeeResponse, error) {
+
+	if in.SatPerKb <= 0 {
+		return nil, fmt.Errorf("a positive fee rate must be specified")
+	}
+
+	txid, err := wire.NewShaHashFromStr(in.Txid)
+	if err != nil {
+		return nil, err
+	}
+
+	childTx, err := r.server.lnwallet.BumpFee(
+		wire.NewOutPoint(txid, in.OutputIndex),
+		btcutil.Amount(in.SatPerKb))
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.BumpFeeResponse{
+		ChildTxid: childTx.TxSha().String(),
+	}, nil
+}
+
+// PendingSweeps returns each of our outputs yet to be swept back to the
+// wallet, along with the progress of its sweep.
+func (r *rpcServer) PendingSweeps(ctx context.Context,
+	in *lnrpc.PendingSweepsRequest) (*lnrpc.PendingSweepsResponse, error) {
+
+	resp := &lnrpc.PendingSweepsResponse{}
+	for _, pending := range r.server.sweeper.PendingSweeps() {
+		sweep := &lnrpc.PendingSweep{
+			Outpoint:            pending.OutPoint.String(),
+			Amount:              int64(pending.Value),
+			Deadline:            pending.Deadline,
+			Budget:              int64(pending.Budget),
+			SatPerKb:            int64(pending.FeeRate),
+			BroadcastAttempts:   pending.Attempts,
+			LastError:           pending.LastError,
+			NextBroadcastHeight: pending.NextBroadcastHeight,
+		}
+		if pending.SweepTxid != nil {
+			sweep.SweepTxid = pending.SweepTxid.String()
+		}
+		resp.PendingSweeps = append(resp.PendingSweeps, sweep)
+	}
+
+	return resp, nil
+}
+
+// ListSweeps returns each of our sweep transactions which has confirmed, in
+// the order they confirmed.
+func (r *rpcServer) ListSweeps(ctx context.Context,
+	in *lnrpc.ListSweepsRequest) (*lnrpc.ListSweepsResponse, error) {
+
+	completed, err := r.server.lnwallet.ChannelDB.FetchCompletedSweeps()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListSweepsResponse{}
+	for _, sweep := range completed {
+		var amount int64
+		for _, txOut := range sweep.Tx.TxOut {
+			amount += txOut.Value
+		}
+		var outpoints []string
+		for _, txIn := range sweep.Tx.TxIn {
+			outpoints = append(outpoints,
+				txIn.PreviousOutPoint.String())
+		}
+
+		resp.Sweeps = append(resp.Sweeps, &lnrpc.SweepTransaction{
+			Txid:              sweep.Tx.TxSha().String(),
+			Outpoints:         outpoints,
+			Amount:            amount,
+			Fee:               int64(sweep.Fee),
+			ConfirmedHeight:   sweep.ConfirmedHeight,
+			BroadcastAttempts: sweep.Attempts,
+		})
+	}
+
+	return resp, nil
+}
+
+// AbandonChannel removes the open channel with the node from the database,
+// without taking any action on-chain, for recovering from channels which are
+// corrupt, or have been resolved by other means. As any funds within the
+// channel are lost, it requires either --dev, or explicit confirmation. If
+// the funding txid is passed, the channel is only removed if it matches.
+func (r *rpcServer) AbandonChannel(ctx context.Context,
+	in *lnrpc.AbandonChannelRequest) (*lnrpc.AbandonChannelResponse, error) {
+
+	if !r.server.devMode && !in.IKnowWhatIAmDoing {
+		return nil, fmt.Errorf("abandoning a channel loses any funds " +
+			"within it, and requires either --dev, or " +
+			"iKnowWhatIAmDoing")
+	}
+
+	var nodeID [32]byte
+	if len(in.LnID) != len(nodeID) {
+		return nil, fmt.Errorf("lnID must be %v bytes", len(nodeID))
+	}
+	copy(nodeID[:], in.LnID)
+
+	chanDB := r.server.lnwallet.ChannelDB
+	if in.FundingTxid != "" {
+		fundingTxid, err := wire.NewShaHashFromStr(in.FundingTxid)
+		if err != nil {
+			return nil, err
+		}
+		channel, err := chanDB.FetchOpenChannel(nodeID)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(channel.ChanID[:], fundingTxid[:]) {
+			return nil, fmt.Errorf("open channel with node has "+
+				"funding txid %v, not %v",
+				wire.ShaHash(channel.ChanID), fundingTxid)
+		}
+	}
+
+	// The live channel is torn down first, as otherwise its next update
+	// would write it back to the database.
+	if err := r.server.abandonChannel(nodeID); err != nil {
+		return nil, err
+	}
+	if err := chanDB.DeleteOpenChannel(nodeID); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("abandoned channel with node %x\n", nodeID[:])
+
+	return &lnrpc.AbandonChannelResponse{}, nil
+}
+
+// UpdateChannelParams renegotiates the HTLC limits we accept, and the CSV
+// delay, of our channel with the connected peer, without closing it.
+// Parameters which aren't set keep their current value.
+func (r *rpcServer) UpdateChannelParams(ctx context.Context,
+	in *lnrpc.UpdateChannelParamsRequest) (*lnrpc.UpdateChannelParamsResponse, error) {
+
+	pubKeyBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	if in.MaxAcceptedHtlcs > lnwire.MaxHTLCNumber {
+		return nil, fmt.Errorf("max accepted htlcs exceeds the "+
+			"maximum of %v", lnwire.MaxHTLCNumber)
+	}
+
+	err = r.server.UpdateChannelParams(pubKey, &lnwallet.ChannelParams{
+		MaxAcceptedHtlcs: uint16(in.MaxAcceptedHtlcs),
+		MaxValueInFlight: lnwire.MilliSatoshi(in.MaxValueInFlightMsat),
+		CsvDelay:         in.CsvDelay,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateChannelParamsResponse{}, nil
+}
+
+// ListPendingReservations returns each channel reservation which is yet to
+// complete, along with the time it expires, in order to debug reservations
+// which fail to progress.
+func (r *rpcServer) ListPendingReservations(ctx context.Context,
+	in *lnrpc.ListPendingReservationsRequest) (*lnrpc.ListPendingReservationsResponse, error) {
+
+	resp := &lnrpc.ListPendingReservationsResponse{}
+	for _, res := range r.server.lnwallet.PendingReservations() {
+		lnID := res.TheirLNID
+		resp.Reservations = append(resp.Reservations,
+			&lnrpc.PendingReservation{
+				ReservationId:    res.ReservationID,
+				LnID:             lnID[:],
+				FundingAmount:    int64(res.FundingAmount),
+				Capacity:         int64(res.Capacity),
+				NumInputs:        uint32(res.NumInputs),
+				HaveContribution: res.HaveContribution,
+				CreationTime:     res.CreationTime.Unix(),
+				Expiry:           res.Expiry.Unix(),
+			})
+	}
+
+	return resp, nil
+}
+
+// GetBestBlock returns the hash, and height, of the tip of the main chain as
+// seen by our chain backend.
+func (r *rpcServer) GetBestBlock(ctx context.Context,
+	in *lnrpc.GetBestBlockRequest) (*lnrpc.GetBestBlockResponse, error) {
+
+	hash, height, err := r.server.lnwallet.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.GetBestBlockResponse{
+		BlockHash:   hash.String(),
+		BlockHeight: height,
+	}, nil
+}
+
+// SubscribeBlockEpochs streams the tip of the main chain, followed by each
+// block connected to it from then on. Should the client fall too far behind,
+// the stream is ended.
+func (r *rpcServer) SubscribeBlockEpochs(in *lnrpc.BlockEpochRequest,
+	updateStream lnrpc.Lightning_SubscribeBlockEpochsServer) error {
+
+	// Subscribing before fetching the tip ensures no block is missed,
+	// though the tip may be sent twice.
+	client := r.server.blockEpochs.Subscribe()
+	defer client.Cancel()
+
+	hash, height, err := r.server.lnwallet.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	err = updateStream.Send(&lnrpc.BlockEpoch{
+		Hash:   hash.String(),
+		Height: height,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case epoch, ok := <-client.epochs:
+			if !ok {
+				return fmt.Errorf("block epoch stream ended, " +
+					"client too slow or server shutting down")
+			}
+
+			err := updateStream.Send(&lnrpc.BlockEpoch{
+				Hash:   epoch.Hash.String(),
+				Height: epoch.Height,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+		}
+	}
+}
+
+// RegisterConfirmationsNtfn sends a single event once the transaction
+// reaches the requested number of confirmations, then ends the stream. The
+// script of one of the transaction's outputs is required, as our backend
+// only notifies us of transactions paying to scripts we've registered. Only
+// transactions mined after registering are detected.
+func (r *rpcServer) RegisterConfirmationsNtfn(in *lnrpc.ConfRequest,
+	updateStream lnrpc.Lightning_RegisterConfirmationsNtfnServer) error {
+
+	txid, err := wire.NewShaHashFromStr(in.Txid)
+	if err != nil {
+		return err
+	}
+	if len(in.Script) == 0 {
+		return fmt.Errorf("an output script of the transaction must " +
+			"be specified")
+	}
+	numConfs := in.NumConfs
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
+	// The trigger is buffered, so the notifier never blocks on it should
+	// the client go away first.
+	trigger := &chainntnfs.NotificationTrigger{
+		TriggerChan: make(chan struct{}, 1),
+	}
+	err = r.server.lnwallet.RegisterConfirmationsNtfn(txid, in.Script,
+		numConfs, trigger)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-trigger.TriggerChan:
+	case <-r.server.quit:
+		return ErrServerShuttingDown
+	case <-updateStream.Context().Done():
+		return updateStream.Context().Err()
+	}
+
+	_, height, err := r.server.lnwallet.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	return updateStream.Send(&lnrpc.ConfEvent{
+		Txid:        txid.String(),
+		NumConfs:    numConfs,
+		BlockHeight: height,
+	})
+}
+
+// RegisterSpendNtfn sends a single event once the outpoint is spent, then
+// ends the stream. Spends are detected as soon as the spending transaction
+// enters the mempool if requested, otherwise once it's mined.
+func (r *rpcServer) RegisterSpendNtfn(in *lnrpc.SpendRequest,
+	updateStream lnrpc.Lightning_RegisterSpendNtfnServer) error {
+
+	txid, err := wire.NewShaHashFromStr(in.Txid)
+	if err != nil {
+		return err
+	}
+	outpoint := wire.NewOutPoint(txid, in.OutputIndex)
+
+	// Both channels are buffered, so the notifier never blocks on them
+	// should the client go away first.
+	trigger := &chainntnfs.NotificationTrigger{
+		TriggerChan: make(chan struct{}, 1),
+		SpendChan:   make(chan *chainntnfs.SpendDetail, 1),
+	}
+	err = r.server.lnwallet.RegisterSpendNtfn(outpoint, in.Mempool,
+		trigger)
+	if err != nil {
+		return err
+	}
+
+	var detail *chainntnfs.SpendDetail
+	select {
+	case detail = <-trigger.SpendChan:
+	case <-r.server.quit:
+		return ErrServerShuttingDown
+	case <-updateStream.Context().Done():
+		return updateStream.Context().Err()
+	}
+
+	var rawTx bytes.Buffer
+	if err := detail.SpendingTx.Serialize(&rawTx); err != nil {
+		return err
+	}
+	return updateStream.Send(&lnrpc.SpendEvent{
+		SpendingTxid:       detail.SpendingTx.TxSha().String(),
+		RawSpendingTx:      rawTx.Bytes(),
+		SpendingInputIndex: detail.SpenderInputIndex,
+		SpendingHeight:     detail.SpendingHeight,
+	})
+}
+
+// DescribeGraph returns every channel within the channel graph, along with
+// the nodes they connect. Our private channels are only included if
+// requested, as they're otherwise unknown to the network.
+func (r *rpcServer) DescribeGraph(ctx context.Context,
+	in *lnrpc.ChannelGraphRequest) (*lnrpc.ChannelGraph, error) {
+
+	edges, err := r.server.lnwallet.ChannelDB.FetchAllChannelEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: include nodes without any channels once node
+	// announcements are stored
+	resp := &lnrpc.ChannelGraph{}
+	nodes := make(map[string]struct{})
+	for _, edge := range edges {
+		if edge.Private && !in.IncludeUnannounced {
+			continue
+		}
+		rpcEdge := marshalChannelEdge(edge)
+		resp.Edges = append(resp.Edges, rpcEdge)
+
+		for _, pubKey := range []string{rpcEdge.Node1Pub, rpcEdge.Node2Pub} {
+			if _, ok := nodes[pubKey]; ok {
+				continue
+			}
+			nodes[pubKey] = struct{}{}
+			resp.Nodes = append(resp.Nodes,
+				&lnrpc.LightningNode{PubKey: pubKey})
+		}
+	}
+
+	return resp, nil
+}
+
+// GetChanInfo returns a single channel within the channel graph, located by
+// either its channel ID, or its funding outpoint.
+func (r *rpcServer) GetChanInfo(ctx context.Context,
+	in *lnrpc.ChanInfoRequest) (*lnrpc.ChannelEdge, error) {
+
+	var (
+		edge *channeldb.ChannelEdge
+		err  error
+	)
+	switch {
+	case in.ChanPoint != "" && in.ChanId != 0:
+		return nil, fmt.Errorf("either a channel ID or channel point " +
+			"must be specified, not both")
+
+	case in.ChanPoint != "":
+		chanPoint, err := parseChanPoint(in.ChanPoint)
+		if err != nil {
+			return nil, err
+		}
+		edge, err = r.server.lnwallet.ChannelDB.FetchChannelEdgeByOutpoint(
+			chanPoint)
+		if err != nil {
+			return nil, err
+		}
+
+	case in.ChanId != 0:
+		chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+		edge, err = r.server.lnwallet.ChannelDB.FetchChannelEdgeInfo(chanID)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("a channel ID or channel point must be " +
+			"specified")
+	}
+
+	return marshalChannelEdge(edge), nil
+}
+
+// GetNodeInfo returns the channels within the channel graph the node is a
+// party to, along with their total capacity.
+func (r *rpcServer) GetNodeInfo(ctx context.Context,
+	in *lnrpc.NodeInfoRequest) (*lnrpc.NodeInfo, error) {
+
+	pubKeyBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := r.server.lnwallet.ChannelDB.FetchNodeChannelEdges(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(edges) == 0 {
+		return nil, fmt.Errorf("unable to locate node %v", in.PubKey)
+	}
+
+	resp := &lnrpc.NodeInfo{
+		Node: &lnrpc.LightningNode{
+			PubKey: hex.EncodeToString(pubKey.SerializeCompressed()),
+		},
+		NumChannels: uint32(len(edges)),
+	}
+	for _, edge := range edges {
+		resp.TotalCapacity += int64(edge.Capacity)
+		resp.Channels = append(resp.Channels, marshalChannelEdge(edge))
+	}
+
+	return resp, nil
+}
+
+// SubscribeChannelGraph streams each change made to the channel graph, from
+// newly announced channels, to channel updates, to closed channels.
+func (r *rpcServer) SubscribeChannelGraph(in *lnrpc.GraphTopologySubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelGraphServer) error {
+
+	client := r.server.topology.SubscribeTopology()
+	defer client.Cancel()
+
+	for {
+		select {
+		case change, ok := <-client.TopologyChanges:
+			// The channel is only closed once we're shutting
+			// down.
+			if !ok {
+				return ErrServerShuttingDown
+			}
+
+			err := updateStream.Send(marshalTopologyChange(change))
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+		}
+	}
+}
+
+// GetNetworkInfo returns statistics of the channel graph, such as its total
+// capacity, and the number of zombie channels within it.
+func (r *rpcServer) GetNetworkInfo(ctx context.Context,
+	in *lnrpc.NetworkInfoRequest) (*lnrpc.NetworkInfo, error) {
+
+	stats, err := r.server.lnwallet.ChannelDB.FetchNetworkStats(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.NetworkInfo{
+		NumNodes:             stats.NumNodes,
+		NumChannels:          stats.NumChannels,
+		TotalNetworkCapacity: int64(stats.TotalCapacity),
+		AvgChannelSize:       stats.AvgChannelSize,
+		MedianChannelSize:    int64(stats.MedianChannelSize),
+		MaxOutDegree:         stats.MaxOutDegree,
+		NumZombieChans:       stats.NumZombieChans,
+	}, nil
+}
+
+// UpdateChanStatus disables one of our channels, announcing it as such in a
+// new channel update, or re-enables it. Channels disabled this way remain so
+// until re-enabled, or handed back to the automatic disabler.
+func (r *rpcServer) UpdateChanStatus(ctx context.Context,
+	in *lnrpc.UpdateChanStatusRequest) (*lnrpc.UpdateChanStatusResponse, error) {
+
+	var action chanStatusAction
+	switch in.Action {
+	case lnrpc.ChanStatusAction_ENABLE:
+		action = chanStatusEnable
+	case lnrpc.ChanStatusAction_DISABLE:
+		action = chanStatusDisable
+	case lnrpc.ChanStatusAction_AUTO:
+		action = chanStatusAuto
+	default:
+		return nil, fmt.Errorf("unknown channel status action: %v",
+			in.Action)
+	}
+
+	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+	if err := r.server.chanStatus.UpdateChanStatus(chanID, action); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateChanStatusResponse{}, nil
+}
+
+// ChannelInsights returns the uptime, and flap count, of the peer of each of
+// our channels since we began tracking the channel, or of only the requested
+// channel if one is passed. Durations are in seconds. Channels the janitor
+// flagged as zombies carry the reason they were flagged.
+func (r *rpcServer) ChannelInsights(ctx context.Context,
+	in *lnrpc.ChannelInsightsRequest) (*lnrpc.ChannelInsightsResponse, error) {
+
+	var insights []*chanfitness.ChannelInsights
+	if in.ChanId != 0 {
+		chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+		chanInsights, err := r.server.chanEvents.GetChanInsights(chanID)
+		if err != nil {
+			return nil, err
+		}
+		insights = append(insights, chanInsights)
+	} else {
+		insights = r.server.chanEvents.GetAllInsights()
+	}
+
+	notes, err := r.server.lnwallet.ChannelDB.FetchChannelNotes()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ChannelInsightsResponse{
+		Channels: make([]*lnrpc.ChannelInsight, 0, len(insights)),
+	}
+	for _, chanInsights := range insights {
+		insight := &lnrpc.ChannelInsight{
+			ChanId: chanInsights.ChanID.ToUint64(),
+			RemotePubkey: hex.EncodeToString(
+				chanInsights.Peer.SerializeCompressed()),
+			Lifetime:  int64(chanInsights.Lifetime.Seconds()),
+			Uptime:    int64(chanInsights.Uptime.Seconds()),
+			FlapCount: chanInsights.FlapCount,
+			Online:    chanInsights.Online,
+			Note:      notes[chanInsights.ChanID],
+			ZombieReason: r.server.chanJanitor.ZombieReason(
+				chanInsights.ChanID),
+		}
+		if !chanInsights.LastFlap.IsZero() {
+			insight.LastFlap = chanInsights.LastFlap.Unix()
+		}
+		private, err := r.server.lnwallet.ChannelDB.IsPrivateChannel(
+			chanInsights.ChanID)
+		if err != nil {
+			return nil, err
+		}
+		insight.Private = private
+		resp.Channels = append(resp.Channels, insight)
+	}
+
+	return resp, nil
+}
+
+// SetChannelNote attaches a note of the user's to the channel, shown
+// alongside it by ChannelInsights. An empty note removes it.
+func (r *rpcServer) SetChannelNote(ctx context.Context,
+	in *lnrpc.SetChannelNoteRequest) (*lnrpc.SetChannelNoteResponse, error) {
+
+	if in.ChanId == 0 {
+		return nil, fmt.Errorf("a channel ID must be specified")
+	}
+
+	chanID := lnwire.NewShortChanIDFromInt(in.ChanId)
+	err := r.server.lnwallet.ChannelDB.PutChannelNote(chanID, in.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SetChannelNoteResponse{}, nil
+}
+
+// FeeReport returns the forwarding policy we currently advertise for each of
+// our channels, along with the fees we've earned by forwarding over the
+// last day, week and month. Channels we've yet to send an update for are
+// reported with the default policy.
+func (r *rpcServer) FeeReport(ctx context.Context,
+	in *lnrpc.FeeReportRequest) (*lnrpc.FeeReportResponse, error) {
+
+	chanDB := r.server.lnwallet.ChannelDB
+	ourKey := r.server.identity.PubKey()
+	edges, err := chanDB.FetchNodeChannelEdges(ourKey)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.FeeReportResponse{}
+	for _, edge := range edges {
+		ann := edge.Announcement
+		policy := edge.Policy1
+		if ann.NodeID2.IsEqual(ourKey) {
+			policy = edge.Policy2
+		}
+
+		report := &lnrpc.ChannelFeeReport{
+			ChanId:      ann.ShortChannelID.ToUint64(),
+			ChanPoint:   edge.ChannelPoint.String(),
+			BaseFeeMsat: defaultBaseFeeMsat,
+			FeePerMil:   defaultFeeRate,
+		}
+		if policy != nil {
+			report.BaseFeeMsat = policy.BaseFee
+			report.FeePerMil = policy.FeeRate
+			report.Disabled = policy.Flags&lnwire.ChanUpdateDisabled != 0
+		}
+		resp.ChannelFees = append(resp.ChannelFees, report)
+	}
+
+	now := time.Now()
+	var (
+		dayAgo   = now.AddDate(0, 0, -1)
+		weekAgo  = now.AddDate(0, 0, -7)
+		monthAgo = now.AddDate(0, -1, 0)
+	)
+	events, err := chanDB.FetchForwardingEvents(monthAgo, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		fee := uint64(event.Fee())
+		resp.MonthFeeSumMsat += fee
+		if !event.Timestamp.Before(weekAgo) {
+			resp.WeekFeeSumMsat += fee
+		}
+		if !event.Timestamp.Before(dayAgo) {
+			resp.DayFeeSumMsat += fee
+		}
+	}
+
+	return resp, nil
+}
+
+// parseChanPoint parses a channel point of the form txid:index.
+func parseChanPoint(s string) (*wire.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("channel point must be of the form " +
+			"txid:index")
+	}
+
+	txid, err := wire.NewShaHashFromStr(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewOutPoint(txid, uint32(index)), nil
+}
+
+// marshalChannelEdge converts a channel within the channel graph to its RPC
+// representation.
+func marshalChannelEdge(edge *channeldb.ChannelEdge) *lnrpc.ChannelEdge {
+	ann := edge.Announcement
+	return &lnrpc.ChannelEdge{
+		ChannelId:   ann.ShortChannelID.ToUint64(),
+		ChanPoint:   edge.ChannelPoint.String(),
+		Node1Pub:    hex.EncodeToString(ann.NodeID1.SerializeCompressed()),
+		Node2Pub:    hex.EncodeToString(ann.NodeID2.SerializeCompressed()),
+		Capacity:    int64(edge.Capacity),
+		Node1Policy: marshalRoutingPolicy(edge.Policy1),
+		Node2Policy: marshalRoutingPolicy(edge.Policy2),
+		Private:     edge.Private,
+	}
+}
+
+// marshalRoute converts the route to its RPC representation.
+func marshalRoute(route *routing.Route) *lnrpc.Route {
+	rpcRoute := &lnrpc.Route{
+		TotalTimeLock: route.TotalTimeLock,
+		TotalFees:     int64(route.TotalFees.ToSatoshis()),
+		TotalAmt:      int64(route.TotalAmount.ToSatoshis()),
+		TotalFeesMsat: uint64(route.TotalFees),
+		TotalAmtMsat:  uint64(route.TotalAmount),
+	}
+	for _, hop := range route.Hops {
+		rpcRoute.Hops = append(rpcRoute.Hops, &lnrpc.Hop{
+			ChanId: hop.ChannelID.ToUint64(),
+			PubKey: hex.EncodeToString(
+				hop.PubKey.SerializeCompressed()),
+			AmtToForward:     int64(hop.AmtToForward.ToSatoshis()),
+			AmtToForwardMsat: uint64(hop.AmtToForward),
+			Expiry:           hop.OutgoingTimeLock,
+		})
+	}
+
+	return rpcRoute
+}
+
+// marshalRoutingPolicy converts a channel update to its RPC representation,
+// returning nil if there's no update.
+func marshalRoutingPolicy(update *lnwire.ChannelUpdate) *lnrpc.RoutingPolicy {
+	if update == nil {
+		return nil
+	}
+
+	return &lnrpc.RoutingPolicy{
+		TimeLockDelta:    uint32(update.TimeLockDelta),
+		MinHtlcMsat:      uint64(update.HtlcMinimumMsat),
+		FeeBaseMsat:      update.BaseFee,
+		FeeRateMilliMsat: update.FeeRate,
+		Disabled:         update.Flags&lnwire.ChanUpdateDisabled != 0,
+		LastUpdate:       int64(update.Timestamp),
+	}
+}
+
+// marshalTopologyChange converts a change to the channel graph to its RPC
+// representation.
+func marshalTopologyChange(change *discovery.TopologyChange) *lnrpc.GraphTopologyUpdate {
+	resp := &lnrpc.GraphTopologyUpdate{}
+	for _, newChan := range change.NewChannels {
+		resp.NewChannels = append(resp.NewChannels,
+			marshalChannelEdge(&channeldb.ChannelEdge{
+				ChannelPoint: newChan.ChannelPoint,
+				Capacity:     newChan.Capacity,
+				Announcement: newChan.Announcement,
+			}))
+	}
+
+	for _, edgeUpdate := range change.ChannelUpdates {
+		ann, update := edgeUpdate.Announcement, edgeUpdate.Update
+		advertisingNode, connectingNode := ann.NodeID1, ann.NodeID2
+		if update.Direction() == 1 {
+			advertisingNode, connectingNode = connectingNode,
+				advertisingNode
+		}
+
+		resp.ChannelUpdates = append(resp.ChannelUpdates,
+			&lnrpc.ChannelEdgeUpdate{
+				ChanId: update.ShortChannelID.ToUint64(),
+				AdvertisingNode: hex.EncodeToString(
+					advertisingNode.SerializeCompressed()),
+				ConnectingNode: hex.EncodeToString(
+					connectingNode.SerializeCompressed()),
+				RoutingPolicy: marshalRoutingPolicy(update),
+			})
+	}
+
+	for _, closedChan := range change.ClosedChannels {
+		resp.ClosedChannels = append(resp.ClosedChannels,
+			&lnrpc.ClosedChannelUpdate{
+				ChanId:       closedChan.ChanID.ToUint64(),
+				ClosedHeight: closedChan.ClosedHeight,
+			})
+	}
+
+	return resp
+}
+
+// RegisterRPCMiddleware registers the calling process as middleware, which
+// is then sent each rpc request, and response, to accept, reject, or unless
+// registered as read-only, modify. The first message sent names the
+// middleware, with each subsequent message responding to a request. The
+// middleware is removed once the stream closes.
+//
+// TODO: restrict to callers authorized to intercept requests once
+// we have macaroons
+func (r *rpcServer) RegisterRPCMiddleware(stream lnrpc.Lightning_RegisterRPCMiddlewareServer) error {
+	return r.middleware.register(stream)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"golang.org/x/net/context"
+)
+
+// createTestChannelDB creates a channeldb instance backed by a fresh
+// database, along with the unlocked addrmgr needed to store open channels.
+func createTestChannelDB(t *testing.T) (*channeldb.DB, func()) {
+	dirName, err := ioutil.TempDir("", "rpctest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+
+	db, err := walletdb.Create("bdb", filepath.Join(dirName, "rpc.db"))
+	if err != nil {
+		os.RemoveAll(dirName)
+		t.Fatalf("unable to create db: %v", err)
+	}
+	cleanUp := func() {
+		db.Close()
+		os.RemoveAll(dirName)
+	}
+
+	addrNamespace, err := db.Namespace([]byte("waddr"))
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create namespace: %v", err)
+	}
+	lnNamespace, err := db.Namespace([]byte("ld"))
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create namespace: %v", err)
+	}
+
+	seed := bytes.Repeat([]byte{2}, 32)
+	mgr, err := waddrmgr.Create(addrNamespace, seed, []byte("test"),
+		[]byte("test"), &chaincfg.SimNetParams, nil)
+	if err != nil {
+		cleanUp()
+		t.Fatalf("unable to create addrmgr: %v", err)
+	}
+	if err := mgr.Unlock([]byte("test")); err != nil {
+		mgr.Close()
+		cleanUp()
+		t.Fatalf("unable to unlock addrmgr: %v", err)
+	}
+
+	return channeldb.New(mgr, lnNamespace), func() {
+		mgr.Close()
+		cleanUp()
+	}
+}
+
+// createTestChannelState returns the state of an open channel with the
+// node, complete enough to be stored.
+func createTestChannelState(t *testing.T,
+	nodeID [32]byte) *channeldb.OpenChannel {
+
+	privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{3}, 32))
+	addr, err := btcutil.NewAddressPubKey(pubKey.SerializeCompressed(),
+		&chaincfg.SimNetParams)
+	if err != nil {
+		t.Fatalf("unable to create delivery address: %v", err)
+	}
+	script, err := txscript.MultiSigScript(
+		[]*btcutil.AddressPubKey{addr, addr}, 2)
+	if err != nil {
+		t.Fatalf("unable to create redeem script: %v", err)
+	}
+
+	return &channeldb.OpenChannel{
+		TheirLNID:            nodeID,
+		ChanID:               [wire.HashSize]byte{4},
+		OurCommitKey:         privKey,
+		TheirCommitKey:       pubKey,
+		Capacity:             btcutil.Amount(10000),
+		OurBalance:           lnwire.MilliSatoshi(5000000),
+		TheirBalance:         lnwire.MilliSatoshi(5000000),
+		TheirCommitTx:        wire.NewMsgTx(),
+		OurCommitTx:          wire.NewMsgTx(),
+		FundingTx:            wire.NewMsgTx(),
+		MultiSigKey:          privKey,
+		FundingRedeemScript:  script,
+		OurDeliveryAddress:   addr,
+		TheirDeliveryAddress: addr,
+		CsvDelay:             5,
+		CreationTime:         time.Now(),
+	}
+}
+
+// TestAbandonChannel asserts abandoning a channel tears down the live
+// channel of the peer it's with, as well as removing it from the database.
+func TestAbandonChannel(t *testing.T) {
+	cdb, cleanUp := createTestChannelDB(t)
+	defer cleanUp()
+
+	nodeID := [32]byte{5}
+	state := createTestChannelState(t, nodeID)
+	if err := cdb.PutOpenChannel(state); err != nil {
+		t.Fatalf("unable to put open channel: %v", err)
+	}
+
+	s := &server{
+		lnwallet:     &lnwallet.LightningWallet{ChannelDB: cdb},
+		peerListings: make(chan chan []*peer),
+		quit:         make(chan struct{}),
+	}
+	defer close(s.quit)
+
+	channel, err := lnwallet.NewLightningChannel(s.lnwallet, nil, cdb,
+		state)
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+	p := newPeer(nil, s)
+	p.lnChannel = channel
+
+	// Stand in for the server's peer handler, which answers listings
+	// with the connected peers.
+	go func() {
+		select {
+		case reply := <-s.peerListings:
+			reply <- []*peer{p}
+		case <-s.quit:
+		}
+	}()
+
+	r := &rpcServer{server: s}
+	_, err = r.AbandonChannel(context.Background(),
+		&lnrpc.AbandonChannelRequest{
+			LnID:              nodeID[:],
+			FundingTxid:       wire.ShaHash(state.ChanID).String(),
+			IKnowWhatIAmDoing: true,
+		})
+	if err != nil {
+		t.Fatalf("unable to abandon channel: %v", err)
+	}
+
+	if p.hasChannel() {
+		t.Fatalf("channel wasn't torn down on the peer")
+	}
+	channels, err := cdb.FetchAllChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(channels) != 0 {
+		t.Fatalf("expected no open channels, instead %v",
+			len(channels))
+	}
+}
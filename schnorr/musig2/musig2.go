@@ -0,0 +1,549 @@
+package musig2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+// MuSig2 (BIP327) allows a group of signers to jointly produce a single
+// schnorr signature under the aggregate of their public keys. The aggregate
+// key, and signatures under it, are indistinguishable from those of a single
+// signer. Signing takes two rounds: first each signer generates and shares a
+// pair of public nonces, then once all nonces are known each signer produces
+// a partial signature. The partial signatures sum up to the final signature.
+//
+// Each pair of nonces MUST only ever be used to sign a single message,
+// otherwise the private key of the signer can be recovered.
+
+const (
+	// PubNonceSize is the size of a serialized public nonce, comprised of
+	// two compressed points.
+	PubNonceSize = 66
+
+	// SecNonceSize is the size of a secret nonce: the two nonce scalars,
+	// followed by the compressed public key of the signer.
+	SecNonceSize = 97
+
+	// PartialSigSize is the size of a serialized partial signature.
+	PartialSigSize = 32
+)
+
+var (
+	// curve is the secp256k1 curve which all operations take place over.
+	curve = btcec.S256()
+
+	// ErrNonceReuse is returned when attempting to sign with a secret
+	// nonce which has already been used.
+	ErrNonceReuse = fmt.Errorf("secret nonce has already been used")
+)
+
+// KeyTweak is a tweak to be added to an aggregate key. If IsXOnly is set, the
+// tweak is applied to the x-only form of the key, as is done by BIP341 when
+// committing to a taproot script tree.
+type KeyTweak struct {
+	Tweak   [32]byte
+	IsXOnly bool
+}
+
+// AggregateKey is the aggregate of the public keys of a set of signers, along
+// with any tweaks which have been applied to it.
+type AggregateKey struct {
+	// keys are the compressed public keys of the signers.
+	keys [][]byte
+
+	// keysHash commits to the full list of signers.
+	keysHash [32]byte
+
+	// secondKey is the first key in the list which differs from the
+	// first key. Its coefficient is fixed to one.
+	secondKey []byte
+
+	// qx and qy are the coordinates of the aggregate key.
+	qx, qy *big.Int
+
+	// gacc and tacc accumulate the negations and tweaks applied to the
+	// key, which must be accounted for when signing.
+	gacc, tacc *big.Int
+}
+
+// SortKeys sorts the passed keys by their compressed serialization, so that
+// the aggregate key doesn't depend upon the order in which the signers were
+// given.
+func SortKeys(keys []*btcec.PublicKey) []*btcec.PublicKey {
+	sorted := make([]*btcec.PublicKey, len(keys))
+	copy(sorted, keys)
+	sort.Sort(keysByBytes(sorted))
+	return sorted
+}
+
+// keysByBytes sorts public keys by their compressed serialization.
+type keysByBytes []*btcec.PublicKey
+
+func (k keysByBytes) Len() int      { return len(k) }
+func (k keysByBytes) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k keysByBytes) Less(i, j int) bool {
+	return bytes.Compare(k[i].SerializeCompressed(),
+		k[j].SerializeCompressed()) < 0
+}
+
+// AggregateKeys computes the aggregate of the passed public keys. The order
+// of the keys matters, so signers must agree upon it beforehand, or sort the
+// keys with SortKeys.
+func AggregateKeys(keys []*btcec.PublicKey) (*AggregateKey, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys to aggregate")
+	}
+
+	a := &AggregateKey{
+		keys: make([][]byte, len(keys)),
+		gacc: big.NewInt(1),
+		tacc: big.NewInt(0),
+	}
+	for i, key := range keys {
+		a.keys[i] = key.SerializeCompressed()
+	}
+	a.keysHash = schnorr.TaggedHash("KeyAgg list", a.keys...)
+	for _, key := range a.keys[1:] {
+		if !bytes.Equal(key, a.keys[0]) {
+			a.secondKey = key
+			break
+		}
+	}
+
+	// Q = a_1*P_1 + ... + a_n*P_n
+	a.qx, a.qy = new(big.Int), new(big.Int)
+	for i, key := range keys {
+		coeff := a.coefficient(a.keys[i])
+		x, y := curve.ScalarMult(key.X, key.Y, schnorr.PadScalar(coeff))
+		a.qx, a.qy = curve.Add(a.qx, a.qy, x, y)
+	}
+	if isInfinity(a.qx, a.qy) {
+		return nil, fmt.Errorf("aggregate key is the point at infinity")
+	}
+
+	return a, nil
+}
+
+// coefficient returns the coefficient the passed signer's key is multiplied
+// by within the aggregate. Weighting the keys prevents a signer from choosing
+// their key so as to cancel out the keys of the others.
+func (a *AggregateKey) coefficient(key []byte) *big.Int {
+	if a.secondKey != nil && bytes.Equal(key, a.secondKey) {
+		return big.NewInt(1)
+	}
+
+	h := schnorr.TaggedHash("KeyAgg coefficient", a.keysHash[:], key)
+	coeff := new(big.Int).SetBytes(h[:])
+	return coeff.Mod(coeff, curve.N)
+}
+
+// hasSigner returns true if the passed key is one of the signers.
+func (a *AggregateKey) hasSigner(key []byte) bool {
+	for _, k := range a.keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// PubKey returns the aggregate public key, with all tweaks applied.
+func (a *AggregateKey) PubKey() *btcec.PublicKey {
+	return &btcec.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).Set(a.qx),
+		Y:     new(big.Int).Set(a.qy),
+	}
+}
+
+// ApplyTweak adds the passed tweak to the aggregate key.
+func (a *AggregateKey) ApplyTweak(tweak KeyTweak) error {
+	t := new(big.Int).SetBytes(tweak.Tweak[:])
+	if t.Cmp(curve.N) >= 0 {
+		return fmt.Errorf("tweak exceeds the group order")
+	}
+
+	// An x-only tweak is applied to the point with an even y coordinate,
+	// so negate the key if required.
+	g := big.NewInt(1)
+	qx, qy := a.qx, a.qy
+	if tweak.IsXOnly && a.qy.Bit(0) == 1 {
+		g.Sub(curve.N, g)
+		qy = new(big.Int).Sub(curve.P, qy)
+	}
+
+	// Q' = g*Q + t*G
+	tx, ty := curve.ScalarBaseMult(tweak.Tweak[:])
+	qx, qy = curve.Add(qx, qy, tx, ty)
+	if isInfinity(qx, qy) {
+		return fmt.Errorf("tweaked key is the point at infinity")
+	}
+	a.qx, a.qy = qx, qy
+
+	a.gacc.Mul(a.gacc, g)
+	a.gacc.Mod(a.gacc, curve.N)
+	a.tacc.Mul(a.tacc, g)
+	a.tacc.Add(a.tacc, t)
+	a.tacc.Mod(a.tacc, curve.N)
+
+	return nil
+}
+
+// ApplyTaprootTweak tweaks the aggregate key so that it may be used as the
+// output key of a taproot output committing to the passed script root, which
+// may be nil for an output with no scripts.
+func (a *AggregateKey) ApplyTaprootTweak(scriptRoot []byte) error {
+	return a.ApplyTweak(KeyTweak{
+		Tweak:   schnorr.TapTweak(a.PubKey(), scriptRoot),
+		IsXOnly: true,
+	})
+}
+
+// Nonces is a pair of nonces to be used for a single signing session. The
+// public half is shared with the other signers, while the secret half MUST
+// only ever be used once.
+type Nonces struct {
+	PubNonce [PubNonceSize]byte
+	SecNonce [SecNonceSize]byte
+}
+
+// GenNonces generates a fresh pair of nonces for the signer with the passed
+// public key. The private key, aggregate key and message may optionally be
+// passed, in which case they're mixed into the nonces as additional
+// protection against a faulty random number generator.
+func GenNonces(pub *btcec.PublicKey, priv *btcec.PrivateKey,
+	aggKey *btcec.PublicKey, msg []byte) (*Nonces, error) {
+
+	var randBytes [32]byte
+	if _, err := rand.Read(randBytes[:]); err != nil {
+		return nil, err
+	}
+	if priv != nil {
+		auxHash := schnorr.TaggedHash("MuSig/aux", randBytes[:])
+		privBytes := schnorr.PadScalar(priv.D)
+		for i := range randBytes {
+			randBytes[i] = privBytes[i] ^ auxHash[i]
+		}
+	}
+
+	pubBytes := pub.SerializeCompressed()
+	var aggKeyBytes []byte
+	if aggKey != nil {
+		aggKeyBytes = schnorr.SerializePubKey(aggKey)
+	}
+	var msgPrefixed []byte
+	if msg == nil {
+		msgPrefixed = []byte{0}
+	} else {
+		msgPrefixed = make([]byte, 9, 9+len(msg))
+		msgPrefixed[0] = 1
+		binary.BigEndian.PutUint64(msgPrefixed[1:], uint64(len(msg)))
+		msgPrefixed = append(msgPrefixed, msg...)
+	}
+	var extraLen [4]byte
+
+	nonces := &Nonces{}
+	for i := 0; i < 2; i++ {
+		h := schnorr.TaggedHash("MuSig/nonce",
+			randBytes[:],
+			[]byte{byte(len(pubBytes))}, pubBytes,
+			[]byte{byte(len(aggKeyBytes))}, aggKeyBytes,
+			msgPrefixed,
+			extraLen[:],
+			[]byte{byte(i)},
+		)
+		k := new(big.Int).SetBytes(h[:])
+		k.Mod(k, curve.N)
+		if k.Sign() == 0 {
+			return nil, fmt.Errorf("nonce is zero")
+		}
+
+		kBytes := schnorr.PadScalar(k)
+		copy(nonces.SecNonce[i*32:], kBytes)
+
+		rx, ry := curve.ScalarBaseMult(kBytes)
+		r := &btcec.PublicKey{Curve: curve, X: rx, Y: ry}
+		copy(nonces.PubNonce[i*33:], r.SerializeCompressed())
+	}
+	copy(nonces.SecNonce[64:], pubBytes)
+
+	return nonces, nil
+}
+
+// AggregateNonces combines the public nonces of all signers into a single
+// aggregate nonce.
+func AggregateNonces(pubNonces [][PubNonceSize]byte) ([PubNonceSize]byte, error) {
+	var aggNonce [PubNonceSize]byte
+	for j := 0; j < 2; j++ {
+		rx, ry := new(big.Int), new(big.Int)
+		for _, nonce := range pubNonces {
+			r, err := btcec.ParsePubKey(nonce[j*33:(j+1)*33], curve)
+			if err != nil {
+				return aggNonce, fmt.Errorf("invalid public "+
+					"nonce: %v", err)
+			}
+			rx, ry = curve.Add(rx, ry, r.X, r.Y)
+		}
+
+		// The point at infinity is encoded as all zeroes.
+		if !isInfinity(rx, ry) {
+			r := &btcec.PublicKey{Curve: curve, X: rx, Y: ry}
+			copy(aggNonce[j*33:], r.SerializeCompressed())
+		}
+	}
+
+	return aggNonce, nil
+}
+
+// sessionValues are the values derived from the aggregate key, aggregate nonce
+// and message which are common to all signers.
+type sessionValues struct {
+	// b is the coefficient of the second nonce.
+	b *big.Int
+
+	// rx and ry are the coordinates of the final nonce point.
+	rx, ry *big.Int
+
+	// e is the signature challenge.
+	e *big.Int
+}
+
+// computeSessionValues derives the values shared by all signers of a session.
+func computeSessionValues(aggNonce [PubNonceSize]byte, aggKey *AggregateKey,
+	msg [32]byte) (*sessionValues, error) {
+
+	qBytes := schnorr.PadScalar(aggKey.qx)
+	h := schnorr.TaggedHash("MuSig/noncecoef", aggNonce[:], qBytes, msg[:])
+	b := new(big.Int).SetBytes(h[:])
+	b.Mod(b, curve.N)
+
+	r1x, r1y, err := parseNoncePoint(aggNonce[:33])
+	if err != nil {
+		return nil, err
+	}
+	r2x, r2y, err := parseNoncePoint(aggNonce[33:])
+	if err != nil {
+		return nil, err
+	}
+
+	// R = R_1 + b*R_2, falling back to the generator if the result is the
+	// point at infinity.
+	bx, by := scalarMult(r2x, r2y, b)
+	rx, ry := curve.Add(r1x, r1y, bx, by)
+	if isInfinity(rx, ry) {
+		rx, ry = curve.Gx, curve.Gy
+	}
+
+	h = schnorr.TaggedHash("BIP0340/challenge", schnorr.PadScalar(rx), qBytes,
+		msg[:])
+	e := new(big.Int).SetBytes(h[:])
+	e.Mod(e, curve.N)
+
+	return &sessionValues{b: b, rx: rx, ry: ry, e: e}, nil
+}
+
+// PartialSignature is a single signer's share of the final signature.
+type PartialSignature struct {
+	S *big.Int
+}
+
+// Serialize returns the 32-byte encoding of the partial signature.
+func (p *PartialSignature) Serialize() []byte {
+	return schnorr.PadScalar(p.S)
+}
+
+// ParsePartialSignature parses a 32-byte partial signature.
+func ParsePartialSignature(sig []byte) (*PartialSignature, error) {
+	if len(sig) != PartialSigSize {
+		return nil, fmt.Errorf("partial signature must be %d bytes",
+			PartialSigSize)
+	}
+
+	s := new(big.Int).SetBytes(sig)
+	if s.Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("partial signature is not less than " +
+			"the group order")
+	}
+
+	return &PartialSignature{S: s}, nil
+}
+
+// Sign creates a partial signature of the message with the passed private key
+// and secret nonce. The secret nonce is zeroed once used, so that it can't
+// accidentally be used to sign a second time.
+func Sign(secNonce *[SecNonceSize]byte, priv *btcec.PrivateKey,
+	aggNonce [PubNonceSize]byte, aggKey *AggregateKey,
+	msg [32]byte) (*PartialSignature, error) {
+
+	if *secNonce == [SecNonceSize]byte{} {
+		return nil, ErrNonceReuse
+	}
+
+	k1 := new(big.Int).SetBytes(secNonce[:32])
+	k2 := new(big.Int).SetBytes(secNonce[32:64])
+	nonceKey := make([]byte, 33)
+	copy(nonceKey, secNonce[64:])
+	for i := range secNonce {
+		secNonce[i] = 0
+	}
+	if k1.Sign() == 0 || k1.Cmp(curve.N) >= 0 ||
+		k2.Sign() == 0 || k2.Cmp(curve.N) >= 0 {
+
+		return nil, fmt.Errorf("invalid secret nonce")
+	}
+
+	pubBytes := priv.PubKey().SerializeCompressed()
+	if !bytes.Equal(pubBytes, nonceKey) {
+		return nil, fmt.Errorf("secret nonce was generated for a " +
+			"different key")
+	}
+	if !aggKey.hasSigner(pubBytes) {
+		return nil, fmt.Errorf("key is not one of the signers")
+	}
+
+	values, err := computeSessionValues(aggNonce, aggKey, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The final nonce must have an even y coordinate, so negate our nonces
+	// if required.
+	if values.ry.Bit(0) == 1 {
+		k1.Sub(curve.N, k1)
+		k2.Sub(curve.N, k2)
+	}
+
+	// Likewise the aggregate key must have an even y coordinate. We also
+	// account for any negations of the key made while tweaking it.
+	d := new(big.Int).Mul(keyParity(aggKey), aggKey.gacc)
+	d.Mul(d, priv.D)
+	d.Mod(d, curve.N)
+
+	// s = k_1 + b*k_2 + e*a*d
+	s := new(big.Int).Mul(values.b, k2)
+	s.Add(s, k1)
+	ead := new(big.Int).Mul(values.e, aggKey.coefficient(pubBytes))
+	ead.Mul(ead, d)
+	s.Add(s, ead)
+	s.Mod(s, curve.N)
+
+	return &PartialSignature{S: s}, nil
+}
+
+// VerifyPartialSig checks that the partial signature was created by the
+// signer with the passed public key and public nonce.
+func VerifyPartialSig(sig *PartialSignature, pubNonce [PubNonceSize]byte,
+	pub *btcec.PublicKey, aggNonce [PubNonceSize]byte,
+	aggKey *AggregateKey, msg [32]byte) bool {
+
+	pubBytes := pub.SerializeCompressed()
+	if !aggKey.hasSigner(pubBytes) {
+		return false
+	}
+
+	values, err := computeSessionValues(aggNonce, aggKey, msg)
+	if err != nil {
+		return false
+	}
+
+	// The signer's effective nonce is R_1 + b*R_2, negated along with the
+	// final nonce.
+	r1, err := btcec.ParsePubKey(pubNonce[:33], curve)
+	if err != nil {
+		return false
+	}
+	r2, err := btcec.ParsePubKey(pubNonce[33:], curve)
+	if err != nil {
+		return false
+	}
+	bx, by := scalarMult(r2.X, r2.Y, values.b)
+	rx, ry := curve.Add(r1.X, r1.Y, bx, by)
+	if values.ry.Bit(0) == 1 {
+		ry = new(big.Int).Sub(curve.P, ry)
+	}
+
+	// s*G = R + (e*a*g*gacc)*P
+	c := new(big.Int).Mul(values.e, aggKey.coefficient(pubBytes))
+	c.Mul(c, keyParity(aggKey))
+	c.Mul(c, aggKey.gacc)
+	c.Mod(c, curve.N)
+	px, py := scalarMult(pub.X, pub.Y, c)
+	ex, ey := curve.Add(rx, ry, px, py)
+
+	sx, sy := curve.ScalarBaseMult(schnorr.PadScalar(sig.S))
+	return sx.Cmp(ex) == 0 && sy.Cmp(ey) == 0
+}
+
+// CombineSigs sums the partial signatures of all signers into the final
+// schnorr signature under the aggregate key.
+func CombineSigs(partialSigs []*PartialSignature, aggNonce [PubNonceSize]byte,
+	aggKey *AggregateKey, msg [32]byte) (*schnorr.Signature, error) {
+
+	values, err := computeSessionValues(aggNonce, aggKey, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := new(big.Int)
+	for _, sig := range partialSigs {
+		if sig.S.Cmp(curve.N) >= 0 {
+			return nil, fmt.Errorf("partial signature is not less " +
+				"than the group order")
+		}
+		s.Add(s, sig.S)
+	}
+
+	// Finally, add in the contribution of any tweaks to the key:
+	// s = s_1 + ... + s_n + e*g*tacc
+	t := new(big.Int).Mul(values.e, keyParity(aggKey))
+	t.Mul(t, aggKey.tacc)
+	s.Add(s, t)
+	s.Mod(s, curve.N)
+
+	return &schnorr.Signature{R: values.rx, S: s}, nil
+}
+
+// keyParity returns 1 if the aggregate key has an even y coordinate, and -1
+// otherwise.
+func keyParity(aggKey *AggregateKey) *big.Int {
+	if aggKey.qy.Bit(0) == 1 {
+		return new(big.Int).Sub(curve.N, big.NewInt(1))
+	}
+	return big.NewInt(1)
+}
+
+// parseNoncePoint parses a single compressed point of an aggregate nonce,
+// which may be the point at infinity.
+func parseNoncePoint(b []byte) (*big.Int, *big.Int, error) {
+	if bytes.Equal(b, make([]byte, 33)) {
+		return new(big.Int), new(big.Int), nil
+	}
+
+	p, err := btcec.ParsePubKey(b, curve)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid aggregate nonce: %v", err)
+	}
+	return p.X, p.Y, nil
+}
+
+// scalarMult multiplies the point by the scalar, handling the point at
+// infinity.
+func scalarMult(x, y, k *big.Int) (*big.Int, *big.Int) {
+	if isInfinity(x, y) {
+		return new(big.Int), new(big.Int)
+	}
+	return curve.ScalarMult(x, y, schnorr.PadScalar(k))
+}
+
+// isInfinity returns true if the coordinates are those of the point at
+// infinity.
+func isInfinity(x, y *big.Int) bool {
+	return x.Sign() == 0 && y.Sign() == 0
+}
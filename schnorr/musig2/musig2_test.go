@@ -0,0 +1,178 @@
+package musig2
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+func makeSigners(n int) ([]*btcec.PrivateKey, []*btcec.PublicKey) {
+	privs := make([]*btcec.PrivateKey, n)
+	pubs := make([]*btcec.PublicKey, n)
+	for i := 0; i < n; i++ {
+		privs[i], pubs[i] = btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{byte(i + 1)}, 32))
+	}
+	return privs, pubs
+}
+
+// TestSessionSign runs a full signing session between several signers, with
+// various tweaks, checking the final signature is valid under the aggregate
+// key.
+func TestSessionSign(t *testing.T) {
+	msg := [32]byte{0x01, 0x02, 0x03}
+	privs, pubs := makeSigners(3)
+
+	optSets := []*SessionOptions{
+		nil,
+		{TaprootTweak: true},
+		{TaprootTweak: true, TaprootScriptRoot: bytes.Repeat([]byte{9}, 32)},
+		{
+			Tweaks: []KeyTweak{
+				{Tweak: [32]byte{0x05}},
+				{Tweak: [32]byte{0x06}, IsXOnly: true},
+			},
+			TaprootTweak: true,
+		},
+	}
+
+	for i, opts := range optSets {
+		sessions := make([]*Session, len(privs))
+		for j, priv := range privs {
+			session, err := NewSession(priv, pubs, opts)
+			if err != nil {
+				t.Fatalf("#%d: unable to create session: %v", i, err)
+			}
+			sessions[j] = session
+		}
+
+		// Exchange nonces between all signers.
+		for j, session := range sessions {
+			var haveAll bool
+			for k, other := range sessions {
+				if j == k {
+					continue
+				}
+				var err error
+				haveAll, err = session.RegisterPubNonce(other.PublicNonce())
+				if err != nil {
+					t.Fatalf("#%d: unable to register nonce: %v",
+						i, err)
+				}
+			}
+			if !haveAll {
+				t.Fatalf("#%d: session should have all nonces", i)
+			}
+		}
+
+		partialSigs := make([]*PartialSignature, len(sessions))
+		for j, session := range sessions {
+			sig, err := session.Sign(msg)
+			if err != nil {
+				t.Fatalf("#%d: unable to sign: %v", i, err)
+			}
+			partialSigs[j] = sig
+
+			if !VerifyPartialSig(sig, session.PublicNonce(), pubs[j],
+				session.aggNonce, session.AggregateKey(), msg) {
+
+				t.Fatalf("#%d: partial signature %d invalid", i, j)
+			}
+		}
+
+		// Each signer should arrive at the same valid signature.
+		for j, session := range sessions {
+			var done bool
+			for k, sig := range partialSigs {
+				if j == k {
+					continue
+				}
+				var err error
+				done, err = session.CombineSig(sig)
+				if err != nil {
+					t.Fatalf("#%d: unable to combine: %v", i, err)
+				}
+			}
+			if !done {
+				t.Fatalf("#%d: session should be complete", i)
+			}
+
+			finalSig := session.FinalSig()
+			aggKey := session.AggregateKey().PubKey()
+			if !schnorr.Verify(finalSig, msg[:], aggKey) {
+				t.Fatalf("#%d: final signature invalid", i)
+			}
+			if !bytes.Equal(finalSig.Serialize(),
+				sessions[0].FinalSig().Serialize()) {
+
+				t.Fatalf("#%d: signers disagree on signature", i)
+			}
+		}
+	}
+}
+
+// TestTaprootAggregateKey ensures the taproot tweak of the aggregate key
+// matches that of a single signer's key.
+func TestTaprootAggregateKey(t *testing.T) {
+	_, pubs := makeSigners(2)
+
+	aggKey, err := AggregateKeys(SortKeys(pubs))
+	if err != nil {
+		t.Fatalf("unable to aggregate keys: %v", err)
+	}
+	internalKey := aggKey.PubKey()
+	if err := aggKey.ApplyTaprootTweak(nil); err != nil {
+		t.Fatalf("unable to tweak: %v", err)
+	}
+
+	outputKey, err := schnorr.ComputeTaprootOutputKey(internalKey, nil)
+	if err != nil {
+		t.Fatalf("unable to compute output key: %v", err)
+	}
+	if !bytes.Equal(schnorr.SerializePubKey(outputKey),
+		schnorr.SerializePubKey(aggKey.PubKey())) {
+
+		t.Fatalf("output keys don't match")
+	}
+}
+
+// TestNonceReuse ensures a session refuses to sign twice, and that a secret
+// nonce can't be used more than once.
+func TestNonceReuse(t *testing.T) {
+	privs, pubs := makeSigners(2)
+
+	sessions := make([]*Session, 2)
+	for i, priv := range privs {
+		session, err := NewSession(priv, pubs, nil)
+		if err != nil {
+			t.Fatalf("unable to create session: %v", err)
+		}
+		sessions[i] = session
+	}
+	if _, err := sessions[0].RegisterPubNonce(sessions[1].PublicNonce()); err != nil {
+		t.Fatalf("unable to register nonce: %v", err)
+	}
+	if _, err := sessions[0].RegisterPubNonce(sessions[1].PublicNonce()); err == nil {
+		t.Fatalf("nonces of all signers are known, should fail")
+	}
+
+	if _, err := sessions[0].Sign([32]byte{1}); err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	if _, err := sessions[0].Sign([32]byte{2}); err != ErrNonceReuse {
+		t.Fatalf("expected ErrNonceReuse, got %v", err)
+	}
+
+	// Signing without the nonces of all signers should fail.
+	if _, err := sessions[1].Sign([32]byte{1}); err == nil {
+		t.Fatalf("signed without all nonces")
+	}
+
+	// A session can't be created for a key which isn't a signer.
+	_, otherPubs := makeSigners(3)
+	if _, err := NewSession(privs[0], otherPubs[1:], nil); err == nil {
+		t.Fatalf("created session for a non-signer")
+	}
+}
@@ -0,0 +1,189 @@
+package musig2
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/lightningnetwork/lnd/schnorr"
+)
+
+// SessionOptions configures the aggregate key of a signing session.
+type SessionOptions struct {
+	// Tweaks are applied to the aggregate key in order.
+	Tweaks []KeyTweak
+
+	// TaprootTweak, if set, tweaks the aggregate key after all other
+	// tweaks, such that it becomes the output key of a taproot output
+	// committing to TaprootScriptRoot.
+	TaprootTweak bool
+
+	// TaprootScriptRoot is the merkle root of the taproot script tree. It
+	// may be nil for an output which can only be spent via the key.
+	TaprootScriptRoot []byte
+}
+
+// Session tracks the state of a single signer through one MuSig2 signing
+// session: gathering the public nonces of the other signers, creating our
+// partial signature, then combining the partial signatures of all signers.
+// A session may only be used to sign a single message.
+type Session struct {
+	priv    *btcec.PrivateKey
+	signers []*btcec.PublicKey
+	aggKey  *AggregateKey
+
+	nonces    *Nonces
+	pubNonces [][PubNonceSize]byte
+	aggNonce  [PubNonceSize]byte
+
+	msg         [32]byte
+	ourSig      *PartialSignature
+	partialSigs []*PartialSignature
+	finalSig    *schnorr.Signature
+}
+
+// NewSession creates a new signing session for the passed private key, which
+// must belong to one of the signers. The signers are sorted before their keys
+// are aggregated, so all signers arrive at the same key regardless of the
+// order the keys are given in. A fresh pair of nonces is generated for the
+// session.
+func NewSession(priv *btcec.PrivateKey, signers []*btcec.PublicKey,
+	opts *SessionOptions) (*Session, error) {
+
+	signers = SortKeys(signers)
+	aggKey, err := AggregateKeys(signers)
+	if err != nil {
+		return nil, err
+	}
+	if !aggKey.hasSigner(priv.PubKey().SerializeCompressed()) {
+		return nil, fmt.Errorf("key is not one of the signers")
+	}
+
+	if opts != nil {
+		for _, tweak := range opts.Tweaks {
+			if err := aggKey.ApplyTweak(tweak); err != nil {
+				return nil, err
+			}
+		}
+		if opts.TaprootTweak {
+			err := aggKey.ApplyTaprootTweak(opts.TaprootScriptRoot)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	nonces, err := GenNonces(priv.PubKey(), priv, aggKey.PubKey(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		priv:      priv,
+		signers:   signers,
+		aggKey:    aggKey,
+		nonces:    nonces,
+		pubNonces: [][PubNonceSize]byte{nonces.PubNonce},
+	}, nil
+}
+
+// AggregateKey returns the aggregate key of the session, with all tweaks
+// applied.
+func (s *Session) AggregateKey() *AggregateKey {
+	return s.aggKey
+}
+
+// PublicNonce returns our public nonce, which must be sent to all other
+// signers.
+func (s *Session) PublicNonce() [PubNonceSize]byte {
+	return s.nonces.PubNonce
+}
+
+// RegisterPubNonce records the public nonce of another signer. True is
+// returned once the nonces of all signers are known, at which point we're
+// able to sign.
+func (s *Session) RegisterPubNonce(nonce [PubNonceSize]byte) (bool, error) {
+	if len(s.pubNonces) == len(s.signers) {
+		return false, fmt.Errorf("already have nonces of all signers")
+	}
+	for _, n := range s.pubNonces {
+		if n == nonce {
+			return false, fmt.Errorf("nonce already registered")
+		}
+	}
+
+	s.pubNonces = append(s.pubNonces, nonce)
+	if len(s.pubNonces) != len(s.signers) {
+		return false, nil
+	}
+
+	aggNonce, err := AggregateNonces(s.pubNonces)
+	if err != nil {
+		return false, err
+	}
+	s.aggNonce = aggNonce
+
+	return true, nil
+}
+
+// Sign creates our partial signature of the message. The nonces of all
+// signers must have been registered beforehand.
+func (s *Session) Sign(msg [32]byte) (*PartialSignature, error) {
+	switch {
+	case len(s.pubNonces) != len(s.signers):
+		return nil, fmt.Errorf("missing nonces of %d signers",
+			len(s.signers)-len(s.pubNonces))
+	case s.ourSig != nil:
+		return nil, ErrNonceReuse
+	}
+
+	sig, err := Sign(&s.nonces.SecNonce, s.priv, s.aggNonce, s.aggKey, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.msg = msg
+	s.ourSig = sig
+	s.partialSigs = append(s.partialSigs, sig)
+
+	return sig, nil
+}
+
+// CombineSig records the partial signature of another signer. True is
+// returned once the partial signatures of all signers are known, at which
+// point the final signature has been created, and can be fetched with
+// FinalSig.
+func (s *Session) CombineSig(sig *PartialSignature) (bool, error) {
+	switch {
+	case s.ourSig == nil:
+		return false, fmt.Errorf("must sign before combining " +
+			"signatures")
+	case s.finalSig != nil:
+		return false, fmt.Errorf("already have signatures of all " +
+			"signers")
+	}
+
+	s.partialSigs = append(s.partialSigs, sig)
+	if len(s.partialSigs) != len(s.signers) {
+		return false, nil
+	}
+
+	// If the final signature is invalid, drop the offending partial
+	// signature so that a valid one may be combined in its place.
+	finalSig, err := CombineSigs(s.partialSigs, s.aggNonce, s.aggKey, s.msg)
+	if err == nil && !schnorr.Verify(finalSig, s.msg[:], s.aggKey.PubKey()) {
+		err = fmt.Errorf("combined signature is invalid")
+	}
+	if err != nil {
+		s.partialSigs = s.partialSigs[:len(s.partialSigs)-1]
+		return false, err
+	}
+	s.finalSig = finalSig
+
+	return true, nil
+}
+
+// FinalSig returns the final signature under the aggregate key, or nil if
+// the partial signatures of all signers aren't yet known.
+func (s *Session) FinalSig() *schnorr.Signature {
+	return s.finalSig
+}
@@ -0,0 +1,308 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	secp "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/fastsha256"
+)
+
+// This package implements BIP340 schnorr signatures over secp256k1, along with
+// the BIP341 key tweak used to commit to a taproot output. Public keys are
+// x-only: only the x coordinate of the point is serialized, and the point
+// with an even y coordinate is implied. The signature of a message m under
+// the private key d, with public key P = d*G and nonce k, is:
+//
+//   R = k*G
+//   e = tagged_hash("BIP0340/challenge", x(R) || x(P) || m)
+//   s = k + e*d
+//   sig = x(R) || s
+//
+// where d and k are negated as required so that both P and R have an even y
+// coordinate.
+//
+// The private key and nonce are only ever operated on as secp.ModNScalar,
+// whose arithmetic is constant time, rather than as big integers, which leak
+// their values through timing.
+//
+// TODO: btcec only offers variable time point multiplication, so deriving the
+// public key and nonce point still leaks the private key and nonce through
+// timing.
+
+const (
+	// PubKeyBytesLen is the length of a serialized x-only public key.
+	PubKeyBytesLen = 32
+
+	// SignatureSize is the length of a serialized signature.
+	SignatureSize = 64
+)
+
+var (
+	// curve is the secp256k1 curve which all operations take place over.
+	curve = btcec.S256()
+
+	// ErrSignatureWrongSize is returned when attempting to parse a
+	// signature which isn't exactly SignatureSize bytes.
+	ErrSignatureWrongSize = fmt.Errorf("signature must be %d bytes",
+		SignatureSize)
+
+	// ErrPubKeyNotOnCurve is returned when an x-only public key doesn't
+	// correspond to a point on the curve.
+	ErrPubKeyNotOnCurve = fmt.Errorf("public key is not on the curve")
+)
+
+// TaggedHash computes sha256(sha256(tag) || sha256(tag) || msg...), as
+// defined by BIP340. Tagging hashes ensures hashes computed for one purpose
+// can never collide with those computed for another.
+func TaggedHash(tag string, msgs ...[]byte) [32]byte {
+	tagHash := fastsha256.Sum256([]byte(tag))
+
+	h := fastsha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, msg := range msgs {
+		h.Write(msg)
+	}
+
+	var digest [32]byte
+	copy(digest[:], h.Sum(nil))
+	return digest
+}
+
+// Signature is a BIP340 schnorr signature.
+type Signature struct {
+	// R is the x coordinate of the nonce point.
+	R *big.Int
+
+	// S is the signature scalar.
+	S *big.Int
+}
+
+// Serialize returns the 64-byte encoding of the signature: x(R) || s.
+func (sig *Signature) Serialize() []byte {
+	var b [SignatureSize]byte
+	copy(b[:32], PadScalar(sig.R))
+	copy(b[32:], PadScalar(sig.S))
+	return b[:]
+}
+
+// ParseSignature parses a 64-byte signature, ensuring both of its components
+// are within range.
+func ParseSignature(sig []byte) (*Signature, error) {
+	if len(sig) != SignatureSize {
+		return nil, ErrSignatureWrongSize
+	}
+
+	var r secp.FieldVal
+	if overflow := r.SetByteSlice(sig[:32]); overflow {
+		return nil, fmt.Errorf("signature R is not a field element")
+	}
+	var s secp.ModNScalar
+	if overflow := s.SetByteSlice(sig[32:]); overflow {
+		return nil, fmt.Errorf("signature S is not less than the group " +
+			"order")
+	}
+
+	return &Signature{
+		R: new(big.Int).SetBytes(sig[:32]),
+		S: new(big.Int).SetBytes(sig[32:]),
+	}, nil
+}
+
+// SerializePubKey returns the x-only encoding of the public key.
+func SerializePubKey(pub *btcec.PublicKey) []byte {
+	return PadScalar(pub.X)
+}
+
+// ParsePubKey parses an x-only public key, returning the point with an even y
+// coordinate.
+func ParsePubKey(pubKey []byte) (*btcec.PublicKey, error) {
+	if len(pubKey) != PubKeyBytesLen {
+		return nil, fmt.Errorf("x-only public key must be %d bytes",
+			PubKeyBytesLen)
+	}
+
+	p, err := liftX(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &btcec.PublicKey{
+		Curve: curve,
+		X:     fieldToBig(&p.X),
+		Y:     fieldToBig(&p.Y),
+	}, nil
+}
+
+// Sign creates a schnorr signature of the message with the passed private
+// key. Fresh randomness is mixed into the nonce as recommended by BIP340,
+// though the security of the signature doesn't depend upon it.
+func Sign(priv *btcec.PrivateKey, msg []byte) (*Signature, error) {
+	var auxRand [32]byte
+	if _, err := rand.Read(auxRand[:]); err != nil {
+		return nil, err
+	}
+
+	return SignWithAux(priv, msg, auxRand)
+}
+
+// SignWithAux creates a schnorr signature of the message with the passed
+// private key, using the passed auxiliary randomness when deriving the nonce.
+// BIP340 allows messages of any length, though those signed here are
+// typically 32-byte digests.
+func SignWithAux(priv *btcec.PrivateKey, msg []byte,
+	auxRand [32]byte) (*Signature, error) {
+
+	var d secp.ModNScalar
+	defer d.Zero()
+	if overflow := d.SetByteSlice(priv.Serialize()); overflow || d.IsZero() {
+		return nil, fmt.Errorf("invalid private key")
+	}
+
+	// Negate the private key if required, so that it corresponds to the
+	// x-only public key, which always has an even y coordinate.
+	var p secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&d, &p)
+	p.ToAffine()
+	if p.Y.IsOdd() {
+		d.Negate()
+	}
+	pubBytes := p.X.Bytes()
+
+	// The nonce is derived from the private key, public key and message,
+	// masked with the auxiliary randomness.
+	auxHash := TaggedHash("BIP0340/aux", auxRand[:])
+	t := d.Bytes()
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+	nonceHash := TaggedHash("BIP0340/nonce", t[:], pubBytes[:], msg)
+
+	var k secp.ModNScalar
+	defer k.Zero()
+	k.SetBytes(&nonceHash)
+	if k.IsZero() {
+		return nil, fmt.Errorf("nonce is zero")
+	}
+
+	var r secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&k, &r)
+	r.ToAffine()
+	if r.Y.IsOdd() {
+		k.Negate()
+	}
+	rBytes := r.X.Bytes()
+
+	e := challenge(rBytes[:], pubBytes[:], msg)
+
+	// s = k + e*d
+	var s secp.ModNScalar
+	s.Mul2(e, &d).Add(&k)
+	sBytes := s.Bytes()
+
+	sig := &Signature{
+		R: new(big.Int).SetBytes(rBytes[:]),
+		S: new(big.Int).SetBytes(sBytes[:]),
+	}
+
+	// Verify the signature before returning it, guarding against
+	// computational errors leaking the private key.
+	pub := &btcec.PublicKey{
+		Curve: curve,
+		X:     fieldToBig(&p.X),
+		Y:     fieldToBig(&p.Y),
+	}
+	if !Verify(sig, msg, pub) {
+		return nil, fmt.Errorf("created signature is invalid")
+	}
+
+	return sig, nil
+}
+
+// Verify returns true if the signature is a valid signature of the message
+// under the x-only form of the passed public key.
+func Verify(sig *Signature, msg []byte, pub *btcec.PublicKey) bool {
+	if sig.R.Cmp(curve.P) >= 0 || sig.S.Cmp(curve.N) >= 0 {
+		return false
+	}
+
+	// Only the x coordinate of the public key is committed to, so use the
+	// point with an even y coordinate.
+	pubBytes := PadScalar(pub.X)
+	p, err := liftX(pubBytes)
+	if err != nil {
+		return false
+	}
+
+	rBytes := PadScalar(sig.R)
+	var s secp.ModNScalar
+	s.SetByteSlice(PadScalar(sig.S))
+
+	e := challenge(rBytes, pubBytes, msg)
+
+	// R = s*G - e*P
+	var sG, eP, r secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&s, &sG)
+	secp.ScalarMultNonConst(e.Negate(), p, &eP)
+	secp.AddNonConst(&sG, &eP, &r)
+
+	if (r.X.IsZero() && r.Y.IsZero()) || r.Z.IsZero() {
+		return false
+	}
+	r.ToAffine()
+	if r.Y.IsOdd() {
+		return false
+	}
+
+	var rx secp.FieldVal
+	rx.SetByteSlice(rBytes)
+	return r.X.Equals(&rx)
+}
+
+// challenge computes the challenge scalar committing to the x coordinates of
+// the nonce point and public key, and the message.
+func challenge(rx, px, msg []byte) *secp.ModNScalar {
+	h := TaggedHash("BIP0340/challenge", rx, px, msg)
+
+	var e secp.ModNScalar
+	e.SetBytes(&h)
+	return &e
+}
+
+// liftX returns the point with the passed 32-byte x coordinate and an even y
+// coordinate.
+func liftX(xBytes []byte) (*secp.JacobianPoint, error) {
+	var x, y secp.FieldVal
+	if overflow := x.SetByteSlice(xBytes); overflow {
+		return nil, ErrPubKeyNotOnCurve
+	}
+	if !secp.DecompressY(&x, false, &y) {
+		return nil, ErrPubKeyNotOnCurve
+	}
+	y.Normalize()
+
+	var z secp.FieldVal
+	z.SetInt(1)
+
+	p := secp.MakeJacobianPoint(&x, &y, &z)
+	return &p, nil
+}
+
+// fieldToBig returns the passed field element as a big integer, normalizing
+// it first.
+func fieldToBig(f *secp.FieldVal) *big.Int {
+	f.Normalize()
+	b := f.Bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// PadScalar returns the 32-byte big endian encoding of the passed integer.
+func PadScalar(n *big.Int) []byte {
+	var b [32]byte
+	nBytes := n.Bytes()
+	copy(b[32-len(nBytes):], nBytes)
+	return b[:]
+}
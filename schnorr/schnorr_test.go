@@ -0,0 +1,404 @@
+package schnorr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// TestBIP340Vectors checks signing and verification against the test vectors
+// of BIP340. Vectors without a private key are only verified, with those which
+// are invalid each failing for a different reason.
+func TestBIP340Vectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		privKey string
+		pubKey  string
+		auxRand string
+		msg     string
+		sig     string
+		valid   bool
+	}{
+		{
+			name: "vector 0",
+			privKey: "00000000000000000000000000000000" +
+				"00000000000000000000000000000003",
+			pubKey: "f9308a019258c31049344f85f89d5229" +
+				"b531c845836f99b08601f113bce036f9",
+			auxRand: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000",
+			msg: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000",
+			sig: "e907831f80848d1069a5371b40241036" +
+				"4bdf1c5f8307b0084c55f1ce2dca8215" +
+				"25f66a4a85ea8b71e482a74f382d2ce5" +
+				"ebeee8fdb2172f477df4900d310536c0",
+			valid: true,
+		},
+		{
+			name: "vector 1",
+			privKey: "b7e151628aed2a6abf7158809cf4f3c7" +
+				"62e7160f38b4da56a784d9045190cfef",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			auxRand: "00000000000000000000000000000000" +
+				"00000000000000000000000000000001",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "6896bd60eeae296db48a229ff71dfe07" +
+				"1bde413e6d43f917dc8dcf8c78de3341" +
+				"8906d11ac976abccb20b091292bff4ea" +
+				"897efcb639ea871cfa95f6de339e4b0a",
+			valid: true,
+		},
+		{
+			name: "vector 2",
+			privKey: "c90fdaa22168c234c4c6628b80dc1cd1" +
+				"29024e088a67cc74020bbea63b14e5c9",
+			pubKey: "dd308afec5777e13121fa72b9cc1b7cc" +
+				"0139715309b086c960e18fd969774eb8",
+			auxRand: "c87aa53824b4d7ae2eb035a2b5bbbccc" +
+				"080e76cdc6d1692c4b0b62d798e6d906",
+			msg: "7e2d58d8b3bcdf1abadec7829054f90d" +
+				"da9805aab56c77333024b9d0a508b75c",
+			sig: "5831aaeed7b44bb74e5eab94ba9d4294" +
+				"c49bcf2a60728d8b4c200f50dd313c1b" +
+				"ab745879a5ad954a72c45a91c3a51d3c" +
+				"7adea98d82f8481e0e1e03674a6f3fb7",
+			valid: true,
+		},
+		{
+			name: "vector 3: msg not reduced mod p or n",
+			privKey: "0b432b2677937381aef05bb02a66ecd0" +
+				"12773062cf3fa2549e44f58ed2401710",
+			pubKey: "25d1dff95105f5253c4022f628a996ad" +
+				"3a0d95fbf21d468a1b33f8c160d8f517",
+			auxRand: "ffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffff",
+			msg: "ffffffffffffffffffffffffffffffff" +
+				"ffffffffffffffffffffffffffffffff",
+			sig: "7eb0509757e246f19449885651611cb9" +
+				"65ecc1a187dd51b64fda1edc9637d5ec" +
+				"97582b9cb13db3933705b32ba982af5a" +
+				"f25fd78881ebb32771fc5922efc66ea3",
+			valid: true,
+		},
+		{
+			name: "vector 4",
+			pubKey: "d69c3509bb99e412e68b0fe8544e7283" +
+				"7dfa30746d8be2aa65975f29d22dc7b9",
+			msg: "4df3c3f68fcc83b27e9d42c90431a724" +
+				"99f17875c81a599b566c9889b9696703",
+			sig: "00000000000000000000003b78ce563f" +
+				"89a0ed9414f5aa28ad0d96d6795f9c63" +
+				"76afb1548af603b3eb45c9f8207dee10" +
+				"60cb71c04e80f593060b07d28308d7f4",
+			valid: true,
+		},
+		{
+			name: "vector 5: pubkey not on the curve",
+			pubKey: "eefdea4cdb677750a420fee807eacf21" +
+				"eb9898ae79b9768766e4faa04a2d4a34",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "6cff5c3ba86c69ea4b7376f31a9bcb4f" +
+				"74c1976089b2d9963da2e5543e177769" +
+				"69e89b4c5564d00349106b8497785dd7" +
+				"d1d713a8ae82b32fa79d5f7fc407d39b",
+			valid: false,
+		},
+		{
+			name: "vector 6: R has an odd y",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "fff97bd5755eeea420453a14355235d3" +
+				"82f6472f8568a18b2f057a1460297556" +
+				"3cc27944640ac607cd107ae10923d9ef" +
+				"7a73c643e166be5ebeafa34b1ac553e2",
+			valid: false,
+		},
+		{
+			name: "vector 7: negated message",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "1fa62e331edbc21c394792d2ab1100a7" +
+				"b432b013df3f6ff4f99fcb33e0e1515f" +
+				"28890b3edb6e7189b630448b515ce4f8" +
+				"622a954cfe545735aaea5134fccdb2bd",
+			valid: false,
+		},
+		{
+			name: "vector 8: negated s",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "6cff5c3ba86c69ea4b7376f31a9bcb4f" +
+				"74c1976089b2d9963da2e5543e177769" +
+				"961764b3aa9b2ffcb6ef947b6887a226" +
+				"e8d7c93e00c5ed0c1834ff0d0c2e6da6",
+			valid: false,
+		},
+		{
+			name: "vector 9: R at infinity, x(R) = 0",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000" +
+				"123dda8328af9c23a94c1feecfd123ba" +
+				"4fb73476f0d594dcb65c6425bd186051",
+			valid: false,
+		},
+		{
+			name: "vector 10: R at infinity, x(R) = 1",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "00000000000000000000000000000000" +
+				"00000000000000000000000000000001" +
+				"7615fbaf5ae28864013c099742deadb4" +
+				"dba87f11ac6754f93780d5a1837cf197",
+			valid: false,
+		},
+		{
+			name: "vector 11: x(R) not on the curve",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "4a298dacae57395a15d0795ddbfd1dcb" +
+				"564da82b0f269bc70a74f8220429ba1d" +
+				"69e89b4c5564d00349106b8497785dd7" +
+				"d1d713a8ae82b32fa79d5f7fc407d39b",
+			valid: false,
+		},
+		{
+			name: "vector 12: x(R) equal to the field size",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "ffffffffffffffffffffffffffffffff" +
+				"fffffffffffffffffffffffefffffc2f" +
+				"69e89b4c5564d00349106b8497785dd7" +
+				"d1d713a8ae82b32fa79d5f7fc407d39b",
+			valid: false,
+		},
+		{
+			name: "vector 13: s equal to the group order",
+			pubKey: "dff1d77f2a671c5f36183726db2341be" +
+				"58feae1da2deced843240f7b502ba659",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "6cff5c3ba86c69ea4b7376f31a9bcb4f" +
+				"74c1976089b2d9963da2e5543e177769" +
+				"fffffffffffffffffffffffffffffffe" +
+				"baaedce6af48a03bbfd25e8cd0364141",
+			valid: false,
+		},
+		{
+			name: "vector 14: pubkey exceeds the field size",
+			pubKey: "ffffffffffffffffffffffffffffffff" +
+				"fffffffffffffffffffffffefffffc30",
+			msg: "243f6a8885a308d313198a2e03707344" +
+				"a4093822299f31d0082efa98ec4e6c89",
+			sig: "6cff5c3ba86c69ea4b7376f31a9bcb4f" +
+				"74c1976089b2d9963da2e5543e177769" +
+				"69e89b4c5564d00349106b8497785dd7" +
+				"d1d713a8ae82b32fa79d5f7fc407d39b",
+			valid: false,
+		},
+		{
+			name: "vector 15: empty message",
+			privKey: "03400340034003400340034003400340" +
+				"03400340034003400340034003400340",
+			pubKey: "778caa53b4393ac467774d09497a8722" +
+				"4bf9fab6f6e68b23086497324d6fd117",
+			auxRand: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000",
+			msg: "",
+			sig: "71535db165ecd9fbbc046e5ffaea6118" +
+				"6bb6ad436732fccc25291a55895464cf" +
+				"6069ce26bf03466228f19a3a62db8a64" +
+				"9f2d560fac652827d1af0574e427ab63",
+			valid: true,
+		},
+		{
+			name: "vector 16: 1-byte message",
+			privKey: "03400340034003400340034003400340" +
+				"03400340034003400340034003400340",
+			pubKey: "778caa53b4393ac467774d09497a8722" +
+				"4bf9fab6f6e68b23086497324d6fd117",
+			auxRand: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000",
+			msg: "11",
+			sig: "08a20a0afef64124649232e0693c583a" +
+				"b1b9934ae63b4c3511f3ae1134c6a303" +
+				"ea3173bfea6683bd101fa5aa5dbc1996" +
+				"fe7cacfc5a577d33ec14564cec2bacbf",
+			valid: true,
+		},
+		{
+			name: "vector 17: 17-byte message",
+			privKey: "03400340034003400340034003400340" +
+				"03400340034003400340034003400340",
+			pubKey: "778caa53b4393ac467774d09497a8722" +
+				"4bf9fab6f6e68b23086497324d6fd117",
+			auxRand: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000",
+			msg: "0102030405060708090a0b0c0d0e0f10" +
+				"11",
+			sig: "5130f39a4059b43bc7cac09a19ece52b" +
+				"5d8699d1a71e3c52da9afdb6b50ac370" +
+				"c4a482b77bf960f8681540e25b6771ec" +
+				"e1e5a37fd80e5a51897c5566a97ea5a5",
+			valid: true,
+		},
+		{
+			name: "vector 18: 100-byte message",
+			privKey: "03400340034003400340034003400340" +
+				"03400340034003400340034003400340",
+			pubKey: "778caa53b4393ac467774d09497a8722" +
+				"4bf9fab6f6e68b23086497324d6fd117",
+			auxRand: "00000000000000000000000000000000" +
+				"00000000000000000000000000000000",
+			msg: strings.Repeat("99", 100),
+			sig: "403b12b0d8555a344175ea7ec7465663" +
+				"03321e5dbfa8be6f091635163eca79a8" +
+				"585ed3e3170807e7c03b720fc54c7b23" +
+				"897fcba0e9d0b4a06894cfd249f22367",
+			valid: true,
+		},
+	}
+
+	for _, test := range tests {
+		pubKey := hexToBytes(test.pubKey)
+		msg := hexToBytes(test.msg)
+		sig := hexToBytes(test.sig)
+
+		if test.privKey != "" {
+			priv, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+				hexToBytes(test.privKey))
+			if !bytes.Equal(SerializePubKey(pub), pubKey) {
+				t.Fatalf("%v: expected public key %x, instead %x",
+					test.name, pubKey, SerializePubKey(pub))
+			}
+
+			var auxRand [32]byte
+			copy(auxRand[:], hexToBytes(test.auxRand))
+			signed, err := SignWithAux(priv, msg, auxRand)
+			if err != nil {
+				t.Fatalf("%v: unable to sign: %v", test.name, err)
+			}
+			if !bytes.Equal(signed.Serialize(), sig) {
+				t.Fatalf("%v: expected signature %x, instead %x",
+					test.name, sig, signed.Serialize())
+			}
+		}
+
+		// Invalid public keys and signatures may be refused as they're
+		// parsed, rather than when verifying.
+		valid := false
+		parsedPub, err := ParsePubKey(pubKey)
+		if err == nil {
+			parsedSig, err := ParseSignature(sig)
+			if err == nil {
+				valid = Verify(parsedSig, msg, parsedPub)
+			}
+		}
+		if valid != test.valid {
+			t.Fatalf("%v: expected valid %v, instead %v", test.name,
+				test.valid, valid)
+		}
+	}
+}
+
+// TestSignOddKey ensures keys with an odd y coordinate sign correctly, as they
+// must be negated to match their x-only public key.
+func TestSignOddKey(t *testing.T) {
+	msg := bytes.Repeat([]byte{0xaa}, 32)
+
+	var sawOdd, sawEven bool
+	for i := byte(1); !(sawOdd && sawEven); i++ {
+		priv, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+			bytes.Repeat([]byte{i}, 32))
+		if pub.Y.Bit(0) == 1 {
+			sawOdd = true
+		} else {
+			sawEven = true
+		}
+
+		sig, err := Sign(priv, msg)
+		if err != nil {
+			t.Fatalf("unable to sign: %v", err)
+		}
+		xOnly, err := ParsePubKey(SerializePubKey(pub))
+		if err != nil {
+			t.Fatalf("unable to parse public key: %v", err)
+		}
+		if !Verify(sig, msg, xOnly) {
+			t.Fatalf("signature by key %d should be valid", i)
+		}
+	}
+}
+
+// TestTaprootKeySpend ensures a signature by the tweaked private key is valid
+// under the output key of the taproot output.
+func TestTaprootKeySpend(t *testing.T) {
+	priv, pub := btcec.PrivKeyFromBytes(btcec.S256(),
+		bytes.Repeat([]byte{0x42}, 32))
+	scriptRoot := bytes.Repeat([]byte{0x01}, 32)
+	msg := bytes.Repeat([]byte{0x02}, 32)
+
+	for _, root := range [][]byte{nil, scriptRoot} {
+		outputKey, err := ComputeTaprootOutputKey(pub, root)
+		if err != nil {
+			t.Fatalf("unable to compute output key: %v", err)
+		}
+		tweakedPriv, err := TweakTaprootPrivKey(priv, root)
+		if err != nil {
+			t.Fatalf("unable to tweak private key: %v", err)
+		}
+		if !bytes.Equal(SerializePubKey(tweakedPriv.PubKey()),
+			SerializePubKey(outputKey)) {
+
+			t.Fatalf("tweaked private key doesn't match output key")
+		}
+
+		sig, err := Sign(tweakedPriv, msg)
+		if err != nil {
+			t.Fatalf("unable to sign: %v", err)
+		}
+		if !Verify(sig, msg, outputKey) {
+			t.Fatalf("key spend signature should be valid")
+		}
+
+		pkScript, err := PayToTaprootScript(outputKey)
+		if err != nil {
+			t.Fatalf("unable to create script: %v", err)
+		}
+		if len(pkScript) != 34 || pkScript[0] != 0x51 ||
+			pkScript[1] != 0x20 {
+
+			t.Fatalf("malformed taproot script: %x", pkScript)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package schnorr
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	secp "github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// A taproot output commits to an internal key P, and optionally the merkle
+// root of a tree of scripts, via a tweak of the internal key:
+//
+//   t = tagged_hash("TapTweak", x(P) || script_root)
+//   Q = P + t*G
+//
+// The output can then be spent with a schnorr signature under the output key
+// Q, or by revealing one of the committed scripts. An output with no script
+// tree can only be spent via the key, as specified by BIP86.
+
+// TapTweak computes the tweak committing to the passed script root, which may
+// be nil if the output has no scripts.
+func TapTweak(internalKey *btcec.PublicKey, scriptRoot []byte) [32]byte {
+	return TaggedHash("TapTweak", SerializePubKey(internalKey), scriptRoot)
+}
+
+// ComputeTaprootOutputKey tweaks the passed internal key with the script root,
+// returning the output key of the resulting taproot output.
+func ComputeTaprootOutputKey(internalKey *btcec.PublicKey,
+	scriptRoot []byte) (*btcec.PublicKey, error) {
+
+	// The tweak is applied to the x-only form of the internal key.
+	p, err := ParsePubKey(SerializePubKey(internalKey))
+	if err != nil {
+		return nil, err
+	}
+
+	tweak := TapTweak(p, scriptRoot)
+	if new(big.Int).SetBytes(tweak[:]).Cmp(curve.N) >= 0 {
+		return nil, fmt.Errorf("tweak exceeds the group order")
+	}
+
+	tx, ty := curve.ScalarBaseMult(tweak[:])
+	qx, qy := curve.Add(p.X, p.Y, tx, ty)
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, fmt.Errorf("output key is the point at infinity")
+	}
+
+	return &btcec.PublicKey{Curve: curve, X: qx, Y: qy}, nil
+}
+
+// TweakTaprootPrivKey returns the private key which signs for the output key
+// derived from the passed private key and script root, allowing the output to
+// be spent via the key path.
+func TweakTaprootPrivKey(priv *btcec.PrivateKey,
+	scriptRoot []byte) (*btcec.PrivateKey, error) {
+
+	var d secp.ModNScalar
+	defer d.Zero()
+	if overflow := d.SetByteSlice(priv.Serialize()); overflow || d.IsZero() {
+		return nil, fmt.Errorf("invalid private key")
+	}
+
+	// Negate the key if required so that it matches the x-only internal
+	// key the tweak was applied to.
+	if priv.PubKey().Y.Bit(0) == 1 {
+		d.Negate()
+	}
+
+	tweak := TapTweak(priv.PubKey(), scriptRoot)
+	var t secp.ModNScalar
+	if overflow := t.SetBytes(&tweak); overflow != 0 {
+		return nil, fmt.Errorf("tweak exceeds the group order")
+	}
+	d.Add(&t)
+	if d.IsZero() {
+		return nil, fmt.Errorf("tweaked private key is zero")
+	}
+
+	dBytes := d.Bytes()
+	tweaked, _ := btcec.PrivKeyFromBytes(curve, dBytes[:])
+	return tweaked, nil
+}
+
+// PayToTaprootScript creates a new witness v1 script paying to the passed
+// taproot output key: OP_1 <x(Q)>.
+func PayToTaprootScript(outputKey *btcec.PublicKey) ([]byte, error) {
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_1)
+	builder.AddData(SerializePubKey(outputKey))
+	return builder.Script()
+}
@@ -2,27 +2,62 @@ package main
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/chanfitness"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/hodl"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lndc"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/sweep"
 
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
 )
 
+var (
+	// ErrPeerAlreadyConnected is returned when connecting to a peer we've
+	// already dialed.
+	ErrPeerAlreadyConnected = errors.New("already connected to peer")
+
+	// ErrPeerNotConnected is returned when disconnecting from a peer we
+	// have no connection to.
+	ErrPeerNotConnected = errors.New("not connected to peer")
+
+	// ErrPeerHasChannels is returned when disconnecting, without force,
+	// from a peer we have an open, or pending, channel with.
+	ErrPeerHasChannels = errors.New("peer has an open, or pending, " +
+		"channel, and may only be disconnected with force")
+
+	// ErrServerShuttingDown is returned by requests made of the server
+	// once it's stopping.
+	ErrServerShuttingDown = errors.New("server shutting down")
+)
+
 // server...
 type server struct {
 	started  int32 // atomic
 	shutdown int32 // atomic
+	numPeers int32 // atomic
 
-	longTermPriv *btcec.PrivateKey
-	bitcoinNet   *chaincfg.Params
+	// identity performs each operation requiring our identity key,
+	// which may be kept within a hardware security module.
+	identity   keychain.NodeSigner
+	bitcoinNet *chaincfg.Params
 
 	listeners []net.Listener
 	peers     map[int32]*peer
@@ -30,47 +65,297 @@ type server struct {
 	rpcServer *rpcServer
 	lnwallet  *lnwallet.LightningWallet
 	db        walletdb.DB
+	invoices  *invoiceRegistry
+	ampHTLCs  *ampHTLCs
+	payments  *paymentRegistry
+	aliases   *aliasManager
+
+	// syncMgr synchronizes our view of the channel graph with that of our
+	// peers.
+	syncMgr *discovery.SyncManager
+
+	// gossiper validates the channel announcements, and updates, of our
+	// peers, rebroadcasting those accepted.
+	gossiper *discovery.Gossiper
+
+	// graphPruner removes channels from the channel graph once they've
+	// been closed.
+	graphPruner *discovery.GraphPruner
+
+	// topology notifies subscribers of each change made to the channel
+	// graph.
+	topology *discovery.TopologyNotifier
+
+	// sweeper sweeps our outputs back to the wallet, raising their fees
+	// as their deadlines approach.
+	sweeper *sweep.Sweeper
+
+	// chanEvents tracks the uptime, and flap rate, of the peers of our
+	// channels.
+	chanEvents *chanfitness.ChannelEventStore
+
+	// chanStatus announces whether each of our channels is enabled,
+	// disabling those of peers which have been offline for too long.
+	chanStatus *chanStatusManager
+
+	// chanJanitor flags, or closes, our zombie channels.
+	chanJanitor *chanJanitor
+
+	// blockEpochs fans the blocks we're notified of out to rpc clients.
+	blockEpochs *blockEpochHub
 
-	newPeers  chan *peer
-	donePeers chan *peer
-	queries   chan interface{}
+	// reachability dials back the external addresses we advertise, to
+	// check peers are able to reach us.
+	reachability *reachabilityChecker
+
+	// connLimits bounds the number of peers we're connected to, and the
+	// rate at which we dial them.
+	connLimits *connLimits
+
+	// peerBackups has our peers store the backup of our channels, and
+	// stores the backups of our peers in turn.
+	peerBackups *peerBackupManager
+
+	// peerData fans the signed data received from our peers out to rpc
+	// clients.
+	peerData *peerDataHub
+
+	// zeroConfPeers is the set of peers, keyed by their hex encoded public
+	// key, trusted enough to open zero-conf channels with.
+	zeroConfPeers map[string]struct{}
+
+	// devMode enables the developer, and recovery, RPCs, which may lose
+	// funds if misused.
+	devMode bool
+
+	newPeers   chan *peer
+	donePeers  chan *peer
+	broadcasts chan *broadcastMsg
+	queries    chan interface{}
+
+	// disconnects are requests to disconnect from a peer, handled by the
+	// peerManager.
+	disconnects chan *disconnectPeerMsg
+
+	// peerListings are requests for the connected peers, handled by the
+	// peerManager.
+	peerListings chan chan []*peer
+
+	// persistentPeers are the peers we reconnect to whenever our
+	// connections to them drop, keyed by their serialized public key.
+	persistentPeers map[[33]byte]*persistentPeer
+	persistentMtx   sync.Mutex
 
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
 
-// newServer...
-func newServer(listenAddrs []string, bitcoinNet *chaincfg.Params,
-	wallet *lnwallet.LightningWallet) (*server, error) {
-	privKey, err := getIdentityPrivKey(wallet)
-	if err != nil {
-		return nil, err
+// serverConfig holds the configuration of the server.
+type serverConfig struct {
+	// ListenAddrs are the addresses we accept peer connections on.
+	ListenAddrs []net.Addr
+
+	// ChainParams are the parameters of the network we're on.
+	ChainParams *chaincfg.Params
+
+	// Wallet is the wallet our channels are funded from, and backed by.
+	Wallet *lnwallet.LightningWallet
+
+	// Identity performs each operation requiring our identity key. If
+	// nil, our identity key is read out of the wallet.
+	Identity keychain.NodeSigner
+
+	// InvoiceRetention is how long canceled invoices are kept before
+	// being deleted. If zero, they're kept forever.
+	InvoiceRetention time.Duration
+
+	// ZeroConfPeers are the hex encoded public keys of the peers trusted
+	// to open zero-conf channels with us.
+	ZeroConfPeers []string
+
+	// NumActiveSyncers is the number of peers we actively sync the channel
+	// graph with.
+	NumActiveSyncers int
+
+	// TrickleDelay is the interval at which the announcements we've
+	// accepted are batched up to be rebroadcast.
+	TrickleDelay time.Duration
+
+	// ChanDisableTimeout, and ChanEnableTimeout, are how long the peer of
+	// a channel must remain offline, or online, before the channel is
+	// announced as disabled, or enabled again. A zero ChanDisableTimeout
+	// never disables channels.
+	ChanDisableTimeout time.Duration
+	ChanEnableTimeout  time.Duration
+
+	// DevMode enables the developer, and recovery, RPCs, which may lose
+	// funds if misused.
+	DevMode bool
+
+	// HodlMask is the set of steps of the HTLC pipeline to drop.
+	HodlMask hodl.Mask
+
+	// RejectZeroProbes rejects HTLCs paying to zero-value invoices which
+	// don't commit to a total amount.
+	RejectZeroProbes bool
+
+	// ExternalAddrs are the addresses we advertise as reachable at, and
+	// ReachabilityProxy the proxy they're checked through, if set.
+	ExternalAddrs     []string
+	ReachabilityProxy string
+
+	// MaxPeers is the most peers we're connected to at once, of which
+	// ReservedChanPeers may only be taken up by those we have channels
+	// with. If zero, there's no limit.
+	MaxPeers          int
+	ReservedChanPeers int
+
+	// MaxDialsPerMinute is the most outbound connections attempted each
+	// minute. If zero, there's no limit.
+	MaxDialsPerMinute int
+
+	// ZombiePolicy is what's done with channels whose peer has been
+	// offline for longer than ZombieOfflineTimeout, or which haven't
+	// forwarded an HTLC for longer than ZombieInactiveTimeout. A zero
+	// timeout disables its check.
+	ZombiePolicy          zombiePolicy
+	ZombieOfflineTimeout  time.Duration
+	ZombieInactiveTimeout time.Duration
+}
+
+// newServer creates a new server from the passed config.
+func newServer(cfg *serverConfig) (*server, error) {
+	wallet := cfg.Wallet
+
+	identity := cfg.Identity
+	if identity == nil {
+		privKey, err := getIdentityPrivKey(wallet)
+		if err != nil {
+			return nil, err
+		}
+		identity = keychain.NewPrivKeySigner(privKey)
 	}
 
-	listeners := make([]net.Listener, len(listenAddrs))
-	for i, addr := range listenAddrs {
-		listeners[i], err = lndc.NewListener(privKey, addr)
+	var err error
+	listeners := make([]net.Listener, len(cfg.ListenAddrs))
+	for i, addr := range cfg.ListenAddrs {
+		listeners[i], err = lndc.NewAddrListener(identity, addr)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	s := &server{
-		longTermPriv: privKey,
+		identity:     identity,
+		bitcoinNet:   cfg.ChainParams,
 		listeners:    listeners,
 		peers:        make(map[int32]*peer),
 		newPeers:     make(chan *peer, 100),
 		donePeers:    make(chan *peer, 100),
+		broadcasts:   make(chan *broadcastMsg),
+		disconnects:  make(chan *disconnectPeerMsg),
+		peerListings: make(chan chan []*peer),
 		lnwallet:     wallet,
-		queries:      make(chan interface{}),
-		quit:         make(chan struct{}),
+		aliases:      newAliasManager(wallet.ChannelDB),
+		connLimits: newConnLimits(cfg.MaxPeers, cfg.ReservedChanPeers,
+			cfg.MaxDialsPerMinute),
+		queries: make(chan interface{}),
+		devMode: cfg.DevMode,
+		quit:    make(chan struct{}),
+	}
+
+	s.persistentPeers = make(map[[33]byte]*persistentPeer)
+
+	s.invoices = newInvoiceRegistry(wallet.ChannelDB, cfg.InvoiceRetention,
+		cfg.HodlMask, cfg.RejectZeroProbes, s.checkInboundLiquidity)
+	s.ampHTLCs = newAMPHTLCs(s.invoices)
+
+	s.payments = newPaymentRegistry(wallet.ChannelDB, s.sendHTLC,
+		s.findRoute)
+
+	s.zeroConfPeers = make(map[string]struct{}, len(cfg.ZeroConfPeers))
+	for _, peerKey := range cfg.ZeroConfPeers {
+		s.zeroConfPeers[peerKey] = struct{}{}
 	}
 
+	s.syncMgr = discovery.NewSyncManager(&discovery.SyncManagerCfg{
+		ChanGraph:        wallet.ChannelDB,
+		NumActiveSyncers: cfg.NumActiveSyncers,
+	})
+	s.topology = discovery.NewTopologyNotifier()
+	s.gossiper = discovery.NewGossiper(&discovery.GossiperCfg{
+		Graph:              wallet.ChannelDB,
+		FetchFundingOutput: s.fetchFundingOutput,
+		Broadcast:          s.BroadcastMessage,
+		SendToPeer:         s.SendToPeer,
+		Notifier:           s.topology,
+		TrickleDelay:       cfg.TrickleDelay,
+		UpdateRate:         discovery.DefaultUpdateRate,
+		UpdateBurst:        discovery.DefaultUpdateBurst,
+		SigPool:            wallet.SigPool,
+		ChainSynced:        wallet.ChainSynced(),
+	})
+	s.chanEvents = chanfitness.NewChannelEventStore(&chanfitness.Config{
+		OurKey:            identity.PubKey(),
+		GetOpenChannels:   s.fetchOurChannels,
+		SubscribeTopology: s.topology.SubscribeTopology,
+	})
+	s.chanStatus = newChanStatusManager(identity, wallet.ChannelDB,
+		s.gossiper, s.chanEvents, cfg.ChanDisableTimeout,
+		cfg.ChanEnableTimeout)
+	s.chanJanitor = newChanJanitor(cfg.ZombiePolicy,
+		cfg.ZombieOfflineTimeout, cfg.ZombieInactiveTimeout,
+		wallet.ChannelDB, s.chanEvents, s.coopCloseChannel)
+	s.blockEpochs = newBlockEpochHub(wallet)
+	s.reachability, err = newReachabilityChecker(identity,
+		cfg.ExternalAddrs, cfg.ReachabilityProxy)
+	if err != nil {
+		return nil, err
+	}
+	s.peerBackups = newPeerBackupManager(identity, wallet.ChannelDB,
+		func(msgs ...lnwire.Message) error {
+			return s.BroadcastMessage(nil, msgs...)
+		})
+	s.peerData = newPeerDataHub()
+	s.graphPruner = discovery.NewGraphPruner(wallet.ChannelDB, wallet,
+		s.topology)
+	s.sweeper = sweep.NewSweeper(&sweep.SweeperCfg{
+		Chain:              wallet,
+		PublishTransaction: wallet.PublishTransaction,
+		GenSweepScript: func() ([]byte, error) {
+			addr, err := wallet.NewChangeAddress(waddrmgr.DefaultAccountNum)
+			if err != nil {
+				return nil, err
+			}
+			return txscript.PayToAddrScript(addr)
+		},
+		RecordSweep: func(sweepTx *wire.MsgTx, fee btcutil.Amount,
+			height int32, attempts uint32) error {
+
+			return wallet.ChannelDB.PutCompletedSweep(
+				&channeldb.CompletedSweep{
+					Tx:              sweepTx,
+					Fee:             fee,
+					ConfirmedHeight: uint32(height),
+					Attempts:        attempts,
+				})
+		},
+		MinFeeRate: sweep.DefaultMinFeeRate,
+		MaxFeeRate: sweep.DefaultMaxFeeRate,
+	})
+
 	s.rpcServer = newRPCServer(s)
 
 	return s, nil
 }
 
+// isZeroConfPeer returns true if the peer with the passed public key is
+// trusted to open zero-conf channels with.
+func (s *server) isZeroConfPeer(pubKey *btcec.PublicKey) bool {
+	_, ok := s.zeroConfPeers[hex.EncodeToString(pubKey.SerializeCompressed())]
+	return ok
+}
+
 // addPeer...
 func (s *server) addPeer(p *peer) {
 	if p == nil {
@@ -83,11 +368,184 @@ func (s *server) addPeer(p *peer) {
 		return
 	}
 
+	// Beyond our limit of peers, the peer is disconnected straight away.
+	// Persistent peers are retried after their usual backoff.
+	isChanPeer := func() bool { return s.isChannelPeer(p) }
+	err := s.connLimits.admitPeer(len(s.peers), p.inbound, isChanPeer)
+	if err != nil {
+		fmt.Printf("disconnecting peer %v: %v\n", p.peerID, err)
+		p.Stop()
+		if pubKey := p.remotePub(); pubKey != nil && !p.inbound {
+			s.retryPersistentPeer(pubKey)
+		}
+		return
+	}
+
 	s.peers[p.peerID] = p
+	atomic.StoreInt32(&s.numPeers, int32(len(s.peers)))
+	if pubKey := p.remotePub(); pubKey != nil {
+		s.chanEvents.PeerOnline(pubKey)
+		s.persistentPeerOnline(pubKey)
+	}
+
+	// Each peer gets a gossip syncer, so we can synchronize our channel
+	// graph with theirs.
+	sendToPeer := func(msgs ...lnwire.Message) error {
+		for _, msg := range msgs {
+			p.queueMsg(msg, nil)
+		}
+		return nil
+	}
+	if err := s.syncMgr.InitSyncState(p.peerID, sendToPeer); err != nil {
+		fmt.Printf("unable to init gossip sync with peer %v: %v\n",
+			p.peerID, err)
+	}
+
+	if pubKey := p.remotePub(); pubKey != nil {
+		err := s.peerBackups.peerConnected(pubKey, sendToPeer)
+		if err != nil {
+			fmt.Printf("unable to exchange backups with peer "+
+				"%v: %v\n", p.peerID, err)
+		}
+	}
 }
 
 // removePeer...
 func (s *server) removePeer(p *peer) {
+	if p == nil {
+		return
+	}
+
+	if _, ok := s.peers[p.peerID]; ok {
+		if pubKey := p.remotePub(); pubKey != nil {
+			s.chanEvents.PeerOffline(pubKey)
+			s.persistentPeerOffline(pubKey)
+		}
+	}
+
+	delete(s.peers, p.peerID)
+	atomic.StoreInt32(&s.numPeers, int32(len(s.peers)))
+	s.syncMgr.PruneSyncState(p.peerID)
+	s.gossiper.RemovePeer(p.peerID)
+}
+
+// fetchOurChannels returns each of our channels within the graph, along with
+// the peer of each.
+func (s *server) fetchOurChannels() ([]*chanfitness.Channel, error) {
+	ourKey := s.identity.PubKey()
+	edges, err := s.lnwallet.ChannelDB.FetchNodeChannelEdges(ourKey)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make([]*chanfitness.Channel, 0, len(edges))
+	for _, edge := range edges {
+		ann := edge.Announcement
+		peer := ann.NodeID1
+		if peer.IsEqual(ourKey) {
+			peer = ann.NodeID2
+		}
+		channels = append(channels, &chanfitness.Channel{
+			ChanID: ann.ShortChannelID,
+			Peer:   peer,
+		})
+	}
+	return channels, nil
+}
+
+// fetchFundingOutput locates the funding output of the channel within the
+// chain, using the block height, transaction index, and output index encoded
+// within its ShortChannelID, returning its outpoint and value. An error is
+// returned if the output doesn't exist, or has been spent.
+func (s *server) fetchFundingOutput(chanID lnwire.ShortChannelID) (*wire.OutPoint, btcutil.Amount, error) {
+	blockHash, err := s.lnwallet.GetBlockHash(int64(chanID.BlockHeight))
+	if err != nil {
+		return nil, 0, err
+	}
+	block, err := s.lnwallet.GetBlock(blockHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if int(chanID.TxIndex) >= len(block.Transactions) {
+		return nil, 0, fmt.Errorf("channel %v: block has no tx %d",
+			chanID, chanID.TxIndex)
+	}
+	fundingTx := block.Transactions[chanID.TxIndex]
+	if int(chanID.TxPosition) >= len(fundingTx.TxOut) {
+		return nil, 0, fmt.Errorf("channel %v: funding tx has no output "+
+			"%d", chanID, chanID.TxPosition)
+	}
+
+	fundingTxID := fundingTx.TxSha()
+	chanPoint := wire.NewOutPoint(&fundingTxID, uint32(chanID.TxPosition))
+
+	unspent, err := s.lnwallet.IsUnspent(chanPoint)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !unspent {
+		return nil, 0, fmt.Errorf("channel %v is closed", chanID)
+	}
+
+	capacity := btcutil.Amount(fundingTx.TxOut[chanID.TxPosition].Value)
+	return chanPoint, capacity, nil
+}
+
+// broadcastMsg is a request to send messages to all connected peers, other
+// than those within the skip set.
+type broadcastMsg struct {
+	skip map[int32]struct{}
+	msgs []lnwire.Message
+}
+
+// broadcast queues the messages to be sent to every peer not within the
+// skip set.
+func (s *server) broadcast(b *broadcastMsg) {
+	for peerID, p := range s.peers {
+		if _, ok := b.skip[peerID]; ok {
+			continue
+		}
+		for _, msg := range b.msgs {
+			p.queueMsg(msg, nil)
+		}
+	}
+}
+
+// disconnectPeerMsg is a request to disconnect from the peer with the
+// passed public key.
+type disconnectPeerMsg struct {
+	pubKey *btcec.PublicKey
+	force  bool
+	reply  chan error
+}
+
+// disconnectPeer disconnects from the requested peer, refusing if we have an
+// open, or pending, channel with them, unless forced. Disconnecting leaves
+// the state of the channel untouched within the database, so it's resumed
+// once the peer reconnects.
+func (s *server) disconnectPeer(d *disconnectPeerMsg) error {
+	var found bool
+	for _, p := range s.peers {
+		pubKey := p.remotePub()
+		if pubKey == nil || !pubKey.IsEqual(d.pubKey) {
+			continue
+		}
+		if !d.force && p.hasChannel() {
+			return ErrPeerHasChannels
+		}
+
+		// The peer is removed once its inHandler exits, and we no
+		// longer reconnect to it.
+		s.removePersistentPeer(d.pubKey)
+		p.Stop()
+		found = true
+	}
+
+	if !found {
+		return ErrPeerNotConnected
+	}
+	return nil
 }
 
 // peerManager...
@@ -101,6 +559,19 @@ out:
 		// Finished peers.
 		case p := <-s.donePeers:
 			s.removePeer(p)
+		// Messages to broadcast.
+		case b := <-s.broadcasts:
+			s.broadcast(b)
+		// Peers to disconnect from.
+		case d := <-s.disconnects:
+			d.reply <- s.disconnectPeer(d)
+		// Requests for the connected peers.
+		case reply := <-s.peerListings:
+			peers := make([]*peer, 0, len(s.peers))
+			for _, p := range s.peers {
+				peers = append(peers, p)
+			}
+			reply <- peers
 		case <-s.quit:
 			break out
 		}
@@ -111,6 +582,7 @@ out:
 // connectPeerMsg...
 type connectPeerMsg struct {
 	addr  *lndc.LNAdr
+	perm  bool
 	reply chan error
 }
 
@@ -125,49 +597,51 @@ out:
 				addr := msg.addr
 
 				// Ensure we're not already connected to this
-				// peer.
+				// peer. Only the peers we dialed have their
+				// address recorded.
 				for _, peer := range s.peers {
+					if peer.lightningAddr.NetAddr == nil {
+						continue
+					}
 					if peer.lightningAddr.String() ==
 						addr.String() {
-						msg.reply <- fmt.Errorf(
-							"already connected to peer: %v",
-							peer.lightningAddr,
-						)
+						msg.reply <- ErrPeerAlreadyConnected
+						continue out
 					}
 				}
 
-				// Launch a goroutine to connect to the requested
-				// peer so we can continue to handle queries.
-				go func() {
-					// For the lndc crypto handshake, we
-					// either need a compressed pubkey, or a
-					// 20-byte pkh.
-					var remoteID []byte
-					if addr.PubKey == nil {
-						remoteID = addr.Base58Addr.ScriptAddress()
-					} else {
-						remoteID = addr.PubKey.SerializeCompressed()
-					}
+				// Only persistent peers may be dialed once
+				// we're at our limit of peers, as they're
+				// retried until a slot frees up.
+				numPeers := int(atomic.LoadInt32(&s.numPeers))
+				if !msg.perm && s.connLimits.atPeerLimit(numPeers) {
+					msg.reply <- ErrTooManyPeers
+					continue
+				}
 
-					// Attempt to connect to the remote
-					// node. If the we can't make the
-					// connection, or the crypto negotation
-					// breaks down, then return an error to the
-					// caller.
-					ipAddr := addr.NetAddr.String()
-					conn := lndc.NewConn(nil)
-					if err := conn.Dial(
-						s.longTermPriv, ipAddr, remoteID); err != nil {
+				if msg.perm {
+					if err := s.addPersistentPeer(addr); err != nil {
 						msg.reply <- err
+						continue
 					}
+				}
 
-					// Now that we've established a connection,
-					// create a peer, and it to the set of
-					// currently active peers.
-					peer := newPeer(conn, s)
-					s.newPeers <- peer
+				// Launch a goroutine to connect to the requested
+				// peer so we can continue to handle queries.
+				go func() {
+					err := s.dialPeer(addr)
+					if err != nil && msg.perm {
+						// We keep trying to reach
+						// persistent peers.
+						key := serializePubKey(addr.PubKey)
+						s.persistentMtx.Lock()
+						if p, ok := s.persistentPeers[key]; ok {
+							s.scheduleReconnect(key, p.backoff)
+						}
+						s.persistentMtx.Unlock()
+					}
 
-					msg.reply <- nil
+					msg.reply <- err
 				}()
 			}
 		case <-s.quit:
@@ -178,15 +652,217 @@ out:
 	s.wg.Done()
 }
 
-// ConnectToPeer...
-func (s *server) ConnectToPeer(addr *lndc.LNAdr) error {
+// dialPeer connects to the peer at the passed address, adding it to the set
+// of active peers once connected.
+func (s *server) dialPeer(addr *lndc.LNAdr) error {
+	if err := s.connLimits.allowDial(time.Now()); err != nil {
+		return err
+	}
+
+	// For the lndc crypto handshake, we either need a compressed pubkey,
+	// or a 20-byte pkh.
+	var remoteID []byte
+	if addr.PubKey == nil {
+		remoteID = addr.Base58Addr.ScriptAddress()
+	} else {
+		remoteID = addr.PubKey.SerializeCompressed()
+	}
+
+	// Attempt to connect to the remote node. If the we can't make the
+	// connection, or the crypto negotation breaks down, then return an
+	// error to the caller.
+	ipAddr := addr.NetAddr.String()
+	conn := lndc.NewConn(nil)
+	if err := conn.Dial(s.identity, ipAddr, remoteID); err != nil {
+		return err
+	}
+
+	// The address is recorded within our address book, so we're able to
+	// reach the peer there again.
+	if conn.Authed {
+		err := s.lnwallet.ChannelDB.MarkNodeAddrSuccess(
+			serializePubKey(conn.RemotePub), ipAddr, time.Now())
+		if err != nil {
+			fmt.Printf("unable to record address %v: %v\n", ipAddr,
+				err)
+		}
+	}
+
+	// Now that we've established a connection, create a peer, and it to
+	// the set of currently active peers.
+	peer := newPeer(conn, s)
+	peer.lightningAddr = *addr
+	peer.Start()
+	s.newPeers <- peer
+
+	return nil
+}
+
+// ConnectToPeer connects to the peer at the passed address. If perm is set,
+// we reconnect to the peer whenever the connection drops, or fails to be
+// made, backing off according to how often it has flapped.
+func (s *server) ConnectToPeer(addr *lndc.LNAdr, perm bool) error {
+	if addr.PubKey != nil && addr.NetAddr != nil {
+		err := s.lnwallet.ChannelDB.AddNodeAddr(
+			serializePubKey(addr.PubKey), addr.NetAddr.String(),
+			time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
 	reply := make(chan error, 1)
 
-	s.queries <- &connectPeerMsg{addr, reply}
+	s.queries <- &connectPeerMsg{addr, perm, reply}
 
 	return <-reply
 }
 
+// ListPeers returns each connected peer.
+func (s *server) ListPeers() ([]*peer, error) {
+	reply := make(chan []*peer, 1)
+
+	select {
+	case s.peerListings <- reply:
+	case <-s.quit:
+		return nil, ErrServerShuttingDown
+	}
+
+	return <-reply, nil
+}
+
+// abandonChannel tears down the live channel with the node, if any of our
+// peers has one.
+func (s *server) abandonChannel(nodeID [32]byte) error {
+	peers, err := s.ListPeers()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range peers {
+		if p.abandonChannel(nodeID) {
+			fmt.Printf("tore down channel with node %x of peer %v\n",
+				nodeID[:], p.peerID)
+		}
+	}
+
+	return nil
+}
+
+// DisconnectPeer disconnects from the peer with the passed public key. If we
+// have an open, or pending, channel with the peer, it's an error unless
+// forced.
+func (s *server) DisconnectPeer(pubKey *btcec.PublicKey, force bool) error {
+	reply := make(chan error, 1)
+
+	select {
+	case s.disconnects <- &disconnectPeerMsg{pubKey, force, reply}:
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+
+	return <-reply
+}
+
+// sendHTLC offers the HTLC to the peer with the passed public key, over our
+// channel with them.
+func (s *server) sendHTLC(dest *btcec.PublicKey,
+	htlc *lnwire.HTLCAddRequest) error {
+
+	peers, err := s.ListPeers()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range peers {
+		if pubKey := p.remotePub(); pubKey != nil && pubKey.IsEqual(dest) {
+			return p.sendHTLC(htlc)
+		}
+	}
+
+	return ErrPeerNotConnected
+}
+
+// findRoute finds a route through the channel graph to the destination for
+// the amount, leaving over one of our channels with a connected peer.
+func (s *server) findRoute(dest *btcec.PublicKey, amt lnwire.MilliSatoshi,
+	restrictions *routing.RestrictParams) (*routing.Route, error) {
+
+	graph, err := s.lnwallet.ChannelDB.GraphCache()
+	if err != nil {
+		return nil, err
+	}
+
+	_, height, err := s.lnwallet.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	peers, err := s.ListPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	var localChans []*routing.LocalChannel
+	for _, p := range peers {
+		pubKey := p.remotePub()
+		if pubKey == nil {
+			continue
+		}
+
+		p.RLock()
+		channel, alias := p.lnChannel, p.remoteAlias
+		p.RUnlock()
+		if channel == nil {
+			continue
+		}
+
+		localChans = append(localChans, &routing.LocalChannel{
+			ChannelID: alias,
+			Peer:      pubKey,
+			Bandwidth: channel.OurBalance(),
+		})
+	}
+
+	return routing.FindRoute(graph, localChans, s.identity.PubKey(),
+		dest, amt, paymentFinalCltvDelta, uint32(height), restrictions)
+}
+
+// BroadcastMessage sends the messages to all connected peers, other than
+// those within the skip set.
+func (s *server) BroadcastMessage(skip map[int32]struct{},
+	msgs ...lnwire.Message) error {
+
+	select {
+	case s.broadcasts <- &broadcastMsg{skip, msgs}:
+		return nil
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+}
+
+// SendToPeer sends the messages to the peer with the passed public key. If
+// we aren't connected to the peer, the messages are dropped.
+func (s *server) SendToPeer(dest *btcec.PublicKey,
+	msgs ...lnwire.Message) error {
+
+	peers, err := s.ListPeers()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range peers {
+		if pubKey := p.remotePub(); pubKey != nil && pubKey.IsEqual(dest) {
+			for _, msg := range msgs {
+				p.queueMsg(msg, nil)
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
 // AddPeer...
 func (s *server) AddPeer(p *peer) {
 	s.newPeers <- p
@@ -202,8 +878,19 @@ func (s *server) listener(l net.Listener) {
 			continue
 		}
 
+		// Connections authenticated under our own key were dialed by
+		// the reachability checker, which only needs the handshake to
+		// complete.
+		lnConn, ok := conn.(*lndc.LNDConn)
+		if ok && lnConn.RemotePub.IsEqual(s.identity.PubKey()) {
+			conn.Close()
+			continue
+		}
+
 		peer := newPeer(conn, s)
+		peer.inbound = true
 		peer.Start()
+		s.newPeers <- peer
 	}
 
 	s.wg.Done()
@@ -222,6 +909,29 @@ func (s *server) Start() {
 		go s.listener(l)
 	}
 
+	s.invoices.Start()
+	s.ampHTLCs.Start()
+	s.gossiper.Start()
+	if err := s.chanEvents.Start(); err != nil {
+		fmt.Printf("unable to start channel event store: %v\n", err)
+	}
+	if err := s.chanStatus.Start(); err != nil {
+		fmt.Printf("unable to start channel status manager: %v\n", err)
+	}
+	s.chanJanitor.Start()
+	if err := s.blockEpochs.Start(); err != nil {
+		fmt.Printf("unable to start block epoch hub: %v\n", err)
+	}
+	if err := s.graphPruner.Start(); err != nil {
+		fmt.Printf("unable to start graph pruner: %v\n", err)
+	}
+	if err := s.sweeper.Start(); err != nil {
+		fmt.Printf("unable to start sweeper: %v\n", err)
+	}
+
+	s.reachability.Start()
+	s.peerBackups.Start()
+
 	s.wg.Add(2)
 	go s.peerManager()
 	go s.queryHandler()
@@ -242,6 +952,21 @@ func (s *server) Stop() error {
 	}
 
 	s.rpcServer.Stop()
+	s.ampHTLCs.Stop()
+	s.invoices.Stop()
+	s.payments.Stop()
+	s.syncMgr.Stop()
+	s.reachability.Stop()
+	s.peerBackups.Stop()
+	s.peerData.Stop()
+	s.chanJanitor.Stop()
+	s.blockEpochs.Stop()
+	s.chanStatus.Stop()
+	s.chanEvents.Stop()
+	s.gossiper.Stop()
+	s.graphPruner.Stop()
+	s.sweeper.Stop()
+	s.topology.Stop()
 	s.lnwallet.Stop()
 
 	// Signal all the lingering goroutines to quit.
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"sync"
@@ -62,8 +63,14 @@ func NewFromSeed(seed *[32]byte, deriveTo uint64) (*HyperShaChain, error) {
 
 	curHash := derive(start, stop, shaSeed)
 
+	// The seed is kept as the root of the chain, from which the hash of
+	// every index may be derived.
 	// TODO(roasbeef): from/to or static size?
-	return &HyperShaChain{lastChainIndex: deriveTo, lastHash: curHash}, nil
+	h := &HyperShaChain{lastChainIndex: deriveTo, lastHash: curHash}
+	h.chainBranches[0] = chainBranch{index: start, hash: shaSeed}
+	h.numValid = 1
+
+	return h, nil
 }
 
 // derive...
@@ -76,7 +83,6 @@ func derive(from, to uint64, startingHash [32]byte) [32]byte {
 	toDerive := 0
 	for ; numBranches>>uint(toDerive) > 0; toDerive++ {
 	}
-	toDerive-- // needed?
 
 	for i := int(toDerive - 1); i >= 0; i-- {
 		if (numBranches>>uint(i))&1 == 1 {
@@ -90,9 +96,12 @@ func derive(from, to uint64, startingHash [32]byte) [32]byte {
 	return nextHash
 }
 
-// canDerive...
+// canDerive returns true if the hash of index to may be derived from that of
+// index from. Hashes are derived by clearing the bits of the index from the
+// highest down, so to may only differ from within the trailing ones of from.
 func canDerive(from, to uint64) bool {
-	return ^from&to == 1
+	trailingOnes := (^from & (from + 1)) - 1
+	return (from^to)&^trailingOnes == 0
 }
 
 // GetHash ...
@@ -115,31 +124,47 @@ func (h *HyperShaChain) GetHash(index uint64) (*[32]byte, error) {
 }
 
 // AddNextHash ...
+// The hashes of a remote chain are added in order, starting from index zero.
+// Each is stored in place of those it can derive, once they're verified to
+// derive from it.
 func (h *HyperShaChain) AddNextHash(hash [32]byte) error {
-	// Hashes for a remote chain must be added in order.
 	nextIdx := h.lastChainIndex + 1
-	if nextIdx != h.lastChainIndex+1 || nextIdx == 0 && h.numValid != 0 {
-		return fmt.Errorf("shachain values must be added in order, attempted"+
-			"to add index %v, chain is at %v", nextIdx, h.lastChainIndex)
-	}
-
-	i := uint64(0)
-	for ; i < h.numValid; i++ {
-		if canDerive(nextIdx, h.chainBranches[i].index) {
-			// Ensure we can actually derive this value.
-			derivation := derive(nextIdx, h.chainBranches[i].index, hash)
-			if !bytes.Equal(derivation[:], h.chainBranches[i].hash[:]) {
-				// TODO(roasbeef): better err message
-				return fmt.Errorf("chain corruption")
-			}
-			break
+	if h.numValid == 0 {
+		nextIdx = 0
+	}
+	if nextIdx == maxIndex {
+		return fmt.Errorf("shachain exhausted at index %v",
+			h.lastChainIndex)
+	}
+
+	// The hash is stored at the position of the number of trailing ones
+	// of its index, as it's able to derive each of those stored below.
+	pos := uint64(0)
+	for (nextIdx>>pos)&1 == 1 {
+		pos++
+	}
+
+	for i := uint64(0); i < pos && i < h.numValid; i++ {
+		if !canDerive(nextIdx, h.chainBranches[i].index) {
+			return fmt.Errorf("shachain index %v can't derive "+
+				"index %v", nextIdx, h.chainBranches[i].index)
+		}
+
+		// Ensure we can actually derive this value.
+		derivation := derive(nextIdx, h.chainBranches[i].index, hash)
+		if !bytes.Equal(derivation[:], h.chainBranches[i].hash[:]) {
+			return fmt.Errorf("chain corruption: hash of index %v "+
+				"doesn't derive index %v", nextIdx,
+				h.chainBranches[i].index)
 		}
 	}
 
-	h.chainBranches[i].index = nextIdx
-	copy(h.chainBranches[i].hash[:], hash[:])
+	h.chainBranches[pos].index = nextIdx
+	copy(h.chainBranches[pos].hash[:], hash[:])
 	copy(h.lastHash[:], hash[:])
-	h.numValid = i + 1
+	if pos+1 > h.numValid {
+		h.numValid = pos + 1
+	}
 	h.lastChainIndex = nextIdx
 	return nil
 }
@@ -172,12 +197,56 @@ func (h *HyperShaChain) LocatePreImage(outputScript []byte) (uint64, *[32]byte)
 
 // Encode ...
 // (MarshallBinary ...)
+// The chain is written as its last index, followed by each of its valid
+// branches, then the last hash added to, or derived by, it.
 func (h *HyperShaChain) Encode(b io.Writer) error {
-	return nil
+	h.RLock()
+	defer h.RUnlock()
+
+	if err := binary.Write(b, binary.BigEndian, h.lastChainIndex); err != nil {
+		return err
+	}
+	if err := binary.Write(b, binary.BigEndian, h.numValid); err != nil {
+		return err
+	}
+	for i := uint64(0); i < h.numValid; i++ {
+		branch := h.chainBranches[i]
+		if err := binary.Write(b, binary.BigEndian, branch.index); err != nil {
+			return err
+		}
+		if _, err := b.Write(branch.hash[:]); err != nil {
+			return err
+		}
+	}
+	_, err := b.Write(h.lastHash[:])
+	return err
 }
 
 // Decode ...
 // UnmarshallBinary ...
 func (h *HyperShaChain) Decode(b io.Reader) error {
-	return nil
+	h.Lock()
+	defer h.Unlock()
+
+	if err := binary.Read(b, binary.BigEndian, &h.lastChainIndex); err != nil {
+		return err
+	}
+	if err := binary.Read(b, binary.BigEndian, &h.numValid); err != nil {
+		return err
+	}
+	if h.numValid > uint64(len(h.chainBranches)) {
+		return fmt.Errorf("shachain has %v branches, more than the "+
+			"maximum of %v", h.numValid, len(h.chainBranches))
+	}
+	for i := uint64(0); i < h.numValid; i++ {
+		branch := &h.chainBranches[i]
+		if err := binary.Read(b, binary.BigEndian, &branch.index); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(b, branch.hash[:]); err != nil {
+			return err
+		}
+	}
+	_, err := io.ReadFull(b, h.lastHash[:])
+	return err
 }
@@ -1 +1,187 @@
 package shachain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestShaChainVectors asserts the hash of each index is derived from the
+// seed by flipping, then hashing, each bit the index doesn't have set, from
+// the highest down.
+func TestShaChainVectors(t *testing.T) {
+	tests := []struct {
+		seed  [32]byte
+		index uint64
+		hash  string
+	}{
+		{
+			seed:  [32]byte{1, 2, 3},
+			index: 0,
+			hash:  "b7f998c388da8d38ed08f9099478095d7e1a9c76e5c241ff4c7e0ea735aa4c3c",
+		},
+		{
+			seed:  [32]byte{1, 2, 3},
+			index: 1,
+			hash:  "584a986271d0eef0ad340e3eda82aa1c7f916d3105814df0ea2a625873ecf1d6",
+		},
+		{
+			seed:  [32]byte{1, 2, 3},
+			index: 2,
+			hash:  "c0dfff05c28c0773478bfaa61b02edab60946d43d1395146282ddd30ed85de4d",
+		},
+		{
+			seed:  [32]byte{1, 2, 3},
+			index: 1000,
+			hash:  "29d8a16c8d53a2d9057ac466f5a868f52252650d63305a0b3df5b9d758955e3d",
+		},
+		{
+			seed:  [32]byte{1, 2, 3},
+			index: maxIndex,
+			hash:  "0102030000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			seed: [32]byte{
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			index: 0,
+			hash:  "c4d7e31db24cd876e2f6fb2203d8f7fd534b3d1489aa1a9dc494b305fe6b6626",
+		},
+	}
+
+	for _, test := range tests {
+		chain, err := NewFromSeed(&test.seed, 0)
+		if err != nil {
+			t.Fatalf("unable to create chain: %v", err)
+		}
+		hash, err := chain.GetHash(test.index)
+		if err != nil {
+			t.Fatalf("index %v: unable to derive hash: %v", test.index,
+				err)
+		}
+		if hex.EncodeToString(hash[:]) != test.hash {
+			t.Fatalf("index %v: expected hash %v, instead %x",
+				test.index, test.hash, hash[:])
+		}
+	}
+}
+
+// TestShaChainDerivation asserts the hashes of a chain derived from a seed
+// may be added, in order, to a remote chain, which is then able to derive
+// each hash it was given.
+func TestShaChainDerivation(t *testing.T) {
+	seed := [32]byte{1, 2, 3}
+	sender, err := NewFromSeed(&seed, 0)
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	receiver := New()
+
+	const numHashes = 1000
+	for i := uint64(0); i < numHashes; i++ {
+		hash, err := sender.GetHash(i)
+		if err != nil {
+			t.Fatalf("unable to derive hash #%v: %v", i, err)
+		}
+		if err := receiver.AddNextHash(*hash); err != nil {
+			t.Fatalf("unable to add hash #%v: %v", i, err)
+		}
+	}
+
+	for i := uint64(0); i < numHashes; i++ {
+		expected, err := sender.GetHash(i)
+		if err != nil {
+			t.Fatalf("unable to derive hash #%v: %v", i, err)
+		}
+		hash, err := receiver.GetHash(i)
+		if err != nil {
+			t.Fatalf("receiver unable to derive hash #%v: %v", i, err)
+		}
+		if !bytes.Equal(hash[:], expected[:]) {
+			t.Fatalf("hash #%v: expected %x, instead %x", i,
+				expected[:], hash[:])
+		}
+	}
+}
+
+// TestShaChainCorruption asserts a hash which doesn't derive those already
+// added to a remote chain is refused.
+func TestShaChainCorruption(t *testing.T) {
+	seed := [32]byte{1, 2, 3}
+	sender, err := NewFromSeed(&seed, 0)
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	receiver := New()
+
+	hash, err := sender.GetHash(0)
+	if err != nil {
+		t.Fatalf("unable to derive hash: %v", err)
+	}
+	if err := receiver.AddNextHash(*hash); err != nil {
+		t.Fatalf("unable to add hash: %v", err)
+	}
+
+	// The hash of index one derives that of index zero, so a wrong one
+	// is detected.
+	if err := receiver.AddNextHash([32]byte{0xff}); err == nil {
+		t.Fatalf("corrupt hash added to chain")
+	}
+}
+
+// TestShaChainEncoding asserts a remote chain decodes to one able to derive
+// each hash given to the original, and to accept the next one.
+func TestShaChainEncoding(t *testing.T) {
+	seed := [32]byte{1, 2, 3}
+	sender, err := NewFromSeed(&seed, 0)
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	receiver := New()
+
+	const numHashes = 100
+	for i := uint64(0); i < numHashes; i++ {
+		hash, err := sender.GetHash(i)
+		if err != nil {
+			t.Fatalf("unable to derive hash #%v: %v", i, err)
+		}
+		if err := receiver.AddNextHash(*hash); err != nil {
+			t.Fatalf("unable to add hash #%v: %v", i, err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := receiver.Encode(&b); err != nil {
+		t.Fatalf("unable to encode chain: %v", err)
+	}
+	decoded := New()
+	if err := decoded.Decode(&b); err != nil {
+		t.Fatalf("unable to decode chain: %v", err)
+	}
+
+	for i := uint64(0); i < numHashes; i++ {
+		expected, _ := sender.GetHash(i)
+		hash, err := decoded.GetHash(i)
+		if err != nil {
+			t.Fatalf("decoded chain unable to derive hash #%v: %v",
+				i, err)
+		}
+		if !bytes.Equal(hash[:], expected[:]) {
+			t.Fatalf("hash #%v: expected %x, instead %x", i,
+				expected[:], hash[:])
+		}
+	}
+	if !bytes.Equal(decoded.CurrentPreImage()[:],
+		receiver.CurrentPreImage()[:]) {
+
+		t.Fatalf("decoded chain has a different last hash")
+	}
+
+	next, _ := sender.GetHash(numHashes)
+	if err := decoded.AddNextHash(*next); err != nil {
+		t.Fatalf("decoded chain unable to add next hash: %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package sigpool
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// jobBuffer is the number of jobs of each kind which may be queued before
+// submitting further jobs blocks.
+const jobBuffer = 100
+
+var (
+	// ErrInvalidSig is returned in response to a VerifyJob whose
+	// signature is invalid.
+	ErrInvalidSig = errors.New("invalid signature")
+
+	// ErrPoolExiting is returned in response to jobs submitted once the
+	// pool has been stopped.
+	ErrPoolExiting = errors.New("sig pool exiting")
+)
+
+// VerifyJob is a request to verify a signature over a message.
+type VerifyJob struct {
+	// PubKey is the key the message should be signed by.
+	PubKey *btcec.PublicKey
+
+	// Sig is the signature to verify.
+	Sig *btcec.Signature
+
+	// SigMsg returns the digest signed. It's run by the worker, so that
+	// serializing, and hashing, the message is done in parallel too.
+	SigMsg func() ([]byte, error)
+
+	// Cancel, if closed, skips the job if it's yet to be run.
+	Cancel chan struct{}
+
+	// ErrResp is sent nil if the signature is valid, and an error
+	// otherwise. It must be buffered.
+	ErrResp chan error
+}
+
+// SignJobResp is the result of a SignJob.
+type SignJobResp struct {
+	// Sig is the signature, serialized with its sighash type appended,
+	// ready for use within a sigScript.
+	Sig []byte
+
+	// Err is any error encountered while signing.
+	Err error
+}
+
+// SignJob is a request to sign an input of a transaction.
+type SignJob struct {
+	// Tx is the transaction to sign.
+	Tx *wire.MsgTx
+
+	// InputIndex is the index of the input to sign.
+	InputIndex int
+
+	// SubScript is the script being spent by the input.
+	SubScript []byte
+
+	// HashType is the sighash type of the signature.
+	HashType txscript.SigHashType
+
+	// PrivKey is the key to sign with.
+	PrivKey *btcec.PrivateKey
+
+	// Cancel, if closed, skips the job if it's yet to be run.
+	Cancel chan struct{}
+
+	// Resp is sent the result of the job. It must be buffered.
+	Resp chan SignJobResp
+}
+
+// SigPool is a pool of workers generating, and verifying, signatures in
+// parallel. Rather than producing each signature of a batch in turn, the
+// batch is handed to the pool, and its results collected once ready.
+type SigPool struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	numWorkers int
+
+	verifyJobs chan *VerifyJob
+	signJobs   chan *SignJob
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSigPool creates a pool of the passed number of workers. If zero, a
+// worker is launched for each CPU usable at once, as set by GOMAXPROCS.
+func NewSigPool(numWorkers int) *SigPool {
+	if numWorkers <= 0 {
+		numWorkers = runtime.GOMAXPROCS(0)
+	}
+
+	return &SigPool{
+		numWorkers: numWorkers,
+		verifyJobs: make(chan *VerifyJob, jobBuffer),
+		signJobs:   make(chan *SignJob, jobBuffer),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start launches the workers of the pool.
+func (s *SigPool) Start() error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return nil
+	}
+
+	for i := 0; i < s.numWorkers; i++ {
+		s.wg.Add(1)
+		go s.poolWorker()
+	}
+
+	return nil
+}
+
+// Stop signals the workers to exit, and waits for them to do so. Jobs yet to
+// be run are failed with ErrPoolExiting.
+func (s *SigPool) Stop() error {
+	if !atomic.CompareAndSwapUint32(&s.stopped, 0, 1) {
+		return nil
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+
+	return nil
+}
+
+// SubmitVerifyBatch queues each job to be verified. The result of each is
+// sent on its ErrResp channel.
+//
+// NOTE: Jobs submitted concurrently with Stop may never be responded to, so
+// the pool should only be stopped once its users have been.
+func (s *SigPool) SubmitVerifyBatch(jobs []*VerifyJob) {
+	for _, job := range jobs {
+		if atomic.LoadUint32(&s.stopped) != 0 {
+			job.ErrResp <- ErrPoolExiting
+			continue
+		}
+
+		select {
+		case s.verifyJobs <- job:
+		case <-job.Cancel:
+			job.ErrResp <- ErrPoolExiting
+		case <-s.quit:
+			job.ErrResp <- ErrPoolExiting
+		}
+	}
+}
+
+// SubmitSignBatch queues each job to be signed. The result of each is sent on
+// its Resp channel.
+//
+// NOTE: As with SubmitVerifyBatch, the pool should only be stopped once its
+// users have been.
+func (s *SigPool) SubmitSignBatch(jobs []*SignJob) {
+	for _, job := range jobs {
+		if atomic.LoadUint32(&s.stopped) != 0 {
+			job.Resp <- SignJobResp{Err: ErrPoolExiting}
+			continue
+		}
+
+		select {
+		case s.signJobs <- job:
+		case <-job.Cancel:
+			job.Resp <- SignJobResp{Err: ErrPoolExiting}
+		case <-s.quit:
+			job.Resp <- SignJobResp{Err: ErrPoolExiting}
+		}
+	}
+}
+
+// VerifySigs verifies each of the jobs in parallel, returning the first error
+// encountered, if any. The ErrResp channel of each job is created if not set.
+func (s *SigPool) VerifySigs(jobs []*VerifyJob) error {
+	for _, job := range jobs {
+		if job.ErrResp == nil {
+			job.ErrResp = make(chan error, 1)
+		}
+	}
+
+	s.SubmitVerifyBatch(jobs)
+
+	var firstErr error
+	for _, job := range jobs {
+		if err := <-job.ErrResp; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// poolWorker runs jobs until the pool is stopped.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *SigPool) poolWorker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case job := <-s.verifyJobs:
+			select {
+			case <-job.Cancel:
+				job.ErrResp <- ErrPoolExiting
+				continue
+			default:
+			}
+
+			job.ErrResp <- verify(job)
+
+		case job := <-s.signJobs:
+			select {
+			case <-job.Cancel:
+				job.Resp <- SignJobResp{Err: ErrPoolExiting}
+				continue
+			default:
+			}
+
+			sig, err := txscript.RawTxInSignature(job.Tx,
+				job.InputIndex, job.SubScript, job.HashType,
+				job.PrivKey)
+			job.Resp <- SignJobResp{Sig: sig, Err: err}
+
+		case <-s.quit:
+			s.drain()
+			return
+		}
+	}
+}
+
+// drain fails all queued jobs with ErrPoolExiting.
+func (s *SigPool) drain() {
+	for {
+		select {
+		case job := <-s.verifyJobs:
+			job.ErrResp <- ErrPoolExiting
+		case job := <-s.signJobs:
+			job.Resp <- SignJobResp{Err: ErrPoolExiting}
+		default:
+			return
+		}
+	}
+}
+
+// verify checks the signature of the job.
+func verify(job *VerifyJob) error {
+	msg, err := job.SigMsg()
+	if err != nil {
+		return err
+	}
+	if !job.Sig.Verify(msg, job.PubKey) {
+		return ErrInvalidSig
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package sigpool
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var testPriv, testPub = btcec.PrivKeyFromBytes(btcec.S256(),
+	bytes.Repeat([]byte{0x01}, 32))
+
+// verifyJob returns a job verifying a signature over the passed message. If
+// valid is false, the signature is made over a different message.
+func verifyJob(t *testing.T, msg byte, valid bool) *VerifyJob {
+	digest := sha256.Sum256([]byte{msg})
+	signed := digest
+	if !valid {
+		signed = sha256.Sum256([]byte{msg + 1})
+	}
+
+	sig, err := testPriv.Sign(signed[:])
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+
+	return &VerifyJob{
+		PubKey: testPub,
+		Sig:    sig,
+		SigMsg: func() ([]byte, error) {
+			return digest[:], nil
+		},
+		ErrResp: make(chan error, 1),
+	}
+}
+
+// TestVerifyBatch ensures each job of a batch is verified, with the result of
+// each sent on its own channel.
+func TestVerifyBatch(t *testing.T) {
+	pool := NewSigPool(4)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start pool: %v", err)
+	}
+	defer pool.Stop()
+
+	const numJobs = 50
+	var jobs []*VerifyJob
+	for i := 0; i < numJobs; i++ {
+		jobs = append(jobs, verifyJob(t, byte(i), i%5 != 0))
+	}
+	pool.SubmitVerifyBatch(jobs)
+
+	for i, job := range jobs {
+		err := <-job.ErrResp
+		switch {
+		case i%5 == 0 && err != ErrInvalidSig:
+			t.Fatalf("job %d: expected invalid sig, got %v", i, err)
+		case i%5 != 0 && err != nil:
+			t.Fatalf("job %d: unable to verify: %v", i, err)
+		}
+	}
+
+	// VerifySigs should report the invalid signature within a batch.
+	if err := pool.VerifySigs(jobs[1:5]); err != nil {
+		t.Fatalf("unable to verify sigs: %v", err)
+	}
+	if err := pool.VerifySigs(jobs[:5]); err != ErrInvalidSig {
+		t.Fatalf("expected invalid sig, got %v", err)
+	}
+}
+
+// TestSignBatch ensures signing through the pool produces the same
+// signatures as signing directly.
+func TestSignBatch(t *testing.T) {
+	pool := NewSigPool(0)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start pool: %v", err)
+	}
+	defer pool.Stop()
+
+	subScript := []byte{txscript.OP_TRUE}
+	var jobs []*SignJob
+	for i := 0; i < 10; i++ {
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: uint32(i)}, nil))
+		tx.AddTxOut(wire.NewTxOut(int64(i), subScript))
+
+		jobs = append(jobs, &SignJob{
+			Tx:        tx,
+			SubScript: subScript,
+			HashType:  txscript.SigHashAll,
+			PrivKey:   testPriv,
+			Resp:      make(chan SignJobResp, 1),
+		})
+	}
+	pool.SubmitSignBatch(jobs)
+
+	for i, job := range jobs {
+		resp := <-job.Resp
+		if resp.Err != nil {
+			t.Fatalf("job %d: unable to sign: %v", i, resp.Err)
+		}
+
+		expected, err := txscript.RawTxInSignature(job.Tx, 0,
+			subScript, txscript.SigHashAll, testPriv)
+		if err != nil {
+			t.Fatalf("unable to sign: %v", err)
+		}
+		if !bytes.Equal(resp.Sig, expected) {
+			t.Fatalf("job %d: sig doesn't match", i)
+		}
+	}
+}
+
+// TestCancelAndStop ensures cancelled jobs, and those submitted once the pool
+// is stopped, are failed rather than left waiting.
+func TestCancelAndStop(t *testing.T) {
+	pool := NewSigPool(1)
+
+	// The pool isn't started yet, so the cancelled job can't be run.
+	job := verifyJob(t, 0, true)
+	job.Cancel = make(chan struct{})
+	close(job.Cancel)
+	pool.SubmitVerifyBatch([]*VerifyJob{job})
+	if err := pool.Start(); err != nil {
+		t.Fatalf("unable to start pool: %v", err)
+	}
+	if err := <-job.ErrResp; err != ErrPoolExiting {
+		t.Fatalf("expected cancelled job to fail, got %v", err)
+	}
+
+	if err := pool.Stop(); err != nil {
+		t.Fatalf("unable to stop pool: %v", err)
+	}
+	job = verifyJob(t, 0, true)
+	if err := pool.VerifySigs([]*VerifyJob{job}); err != ErrPoolExiting {
+		t.Fatalf("expected job to fail once stopped, got %v", err)
+	}
+}
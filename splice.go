@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// handleSpliceInit processes the peer's proposal to splice funds into, or
+// out of, our channel with them, which must be quiescent so no HTLCs are
+// added, or removed, while the commitments are re-anchored.
+//
+// TODO: the splice is declined until the wallet is able to build
+// the splice transaction, spending the current funding output, and the
+// channel is able to re-anchor its commitments to it. Until the splice
+// confirms, updates are then to be signed for both the current and the
+// splice funding outputs.
+func (p *peer) handleSpliceInit(msg lnwire.Message) {
+	splice := msg.(*lnwire.SpliceInit)
+
+	chanID, err := p.channelID()
+	if err != nil || chanID != splice.ChannelID {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent splice_init for unknown channel %v",
+			splice.ChannelID)
+		return
+	}
+	if !p.quiescer.isQuiescent() {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent splice_init for channel %v which isn't quiescent",
+			splice.ChannelID)
+		return
+	}
+
+	p.RLock()
+	channel := p.lnChannel
+	p.RUnlock()
+
+	// No more than the supply of bitcoin may be spliced out, which also
+	// keeps the amount from overflowing once negated.
+	if splice.FundingContribution < -btcutil.MaxSatoshi {
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent splice_init contributing %v, beyond the "+
+				"supply of bitcoin", splice.FundingContribution)
+		return
+	}
+
+	// The peer may only splice out funds it owns.
+	spliceOut := -splice.FundingContribution
+	if spliceOut > 0 &&
+		lnwire.NewMSatFromSatoshis(spliceOut) > channel.TheirBalance() {
+
+		p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+			"sent splice_init splicing out %v, exceeding its "+
+				"balance of %v", spliceOut,
+			channel.TheirBalance())
+		return
+	}
+
+	p.queueMsg(&lnwire.ErrorGeneric{
+		ChannelID: channel.ShortChanID(),
+		Problem: fmt.Sprintf("splicing of channel %v not supported",
+			splice.ChannelID),
+	}, nil)
+
+	// With the splice declined, the channel no longer needs to be
+	// quiescent.
+	p.resumeChannel()
+}
+
+// handleSpliceAck processes the peer's acceptance of a splice. As we don't
+// yet propose splices, it's unsolicited.
+func (p *peer) handleSpliceAck(msg lnwire.Message) {
+	ack := msg.(*lnwire.SpliceAck)
+
+	p.recordMisbehavior(channeldb.MisbehaviorInvalidState,
+		"sent splice_ack for channel %v without a splice_init",
+		ack.ChannelID)
+}
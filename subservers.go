@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// rpcMethodPrefix is the prefix of the full name of each rpc method.
+const rpcMethodPrefix = "/lnrpc.Lightning/"
+
+// rpcSubServer is a part of the rpc surface which may be disabled by
+// deployments that don't need it.
+type rpcSubServer struct {
+	name    string
+	methods []string
+}
+
+// rpcCoreMethods make up the core of the rpc server, managing our peers and
+// channels, and are always enabled. Every method of the rpc server is either
+// one of these, or part of exactly one sub-server.
+var rpcCoreMethods = []string{
+	"GetInfo", "ConnectPeer", "DisconnectPeer", "ListPeers",
+	"SetPeerLabel", "ListPeerBackups", "SendSignedData",
+	"SubscribeSignedData", "ListPeerMisbehavior", "ChannelBalance",
+	"AbandonChannel", "UpdateChannelParams", "ListPendingReservations",
+	"ChannelInsights", "SetChannelNote", "ExportAccounting",
+	"RegisterRPCMiddleware",
+}
+
+// rpcSubServers are each of the sub-servers of the rpc server.
+var rpcSubServers = []*rpcSubServer{
+	{
+		name: "walletkit",
+		methods: []string{
+			"SendMany", "EstimateFee", "PublishTransaction",
+			"GetTransaction", "NewAddress", "GetRecoveryInfo",
+			"WalletBalance", "ImportAccount", "ImportPublicKey",
+			"FundPsbt", "FinalizePsbt", "BumpFee", "PendingSweeps",
+			"ListSweeps",
+		},
+	},
+	{
+		name:    "signer",
+		methods: []string{"SignPsbt", "SignPeerData", "VerifyPeerData"},
+	},
+	{
+		name: "router",
+		methods: []string{
+			"SendPayment", "QueryRoutes", "EstimateRouteFee",
+			"ListPayments",
+			"DeletePayment", "DeleteAllPayments", "ListHtlcs",
+			"LookupHtlcResolution", "DescribeGraph",
+			"GetChanInfo", "GetNodeInfo", "SubscribeChannelGraph",
+			"GetNetworkInfo", "UpdateChanStatus", "FeeReport",
+		},
+	},
+	{
+		name:    "invoices",
+		methods: []string{"AddInvoice", "SubscribeInvoices"},
+	},
+	{
+		name: "chainnotifier",
+		methods: []string{
+			"GetBestBlock", "SubscribeBlockEpochs",
+			"RegisterConfirmationsNtfn", "RegisterSpendNtfn",
+		},
+	},
+}
+
+// subServerFilter rejects calls to the methods of disabled sub-servers.
+type subServerFilter struct {
+	// disabled maps the full name of each disabled method to the
+	// sub-server it's part of.
+	disabled map[string]string
+}
+
+// newSubServerFilter creates a filter enabling only the named sub-servers.
+// If no names are passed, all sub-servers are enabled.
+func newSubServerFilter(enabled []string) (*subServerFilter, error) {
+	f := &subServerFilter{disabled: make(map[string]string)}
+	if len(enabled) == 0 {
+		return f, nil
+	}
+
+	enabledSet := make(map[string]struct{})
+	for _, name := range enabled {
+		enabledSet[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
+	}
+
+	for _, subServer := range rpcSubServers {
+		if _, ok := enabledSet[subServer.name]; ok {
+			delete(enabledSet, subServer.name)
+			continue
+		}
+		for _, method := range subServer.methods {
+			f.disabled[rpcMethodPrefix+method] = subServer.name
+		}
+	}
+
+	for name := range enabledSet {
+		return nil, fmt.Errorf("unknown rpc sub-server: %v", name)
+	}
+
+	return f, nil
+}
+
+// checkMethod returns an error if the method is part of a disabled
+// sub-server.
+func (f *subServerFilter) checkMethod(fullMethod string) error {
+	if subServer, ok := f.disabled[fullMethod]; ok {
+		return fmt.Errorf("%v is part of the %v rpc sub-server, which "+
+			"is disabled", strings.TrimPrefix(fullMethod,
+			rpcMethodPrefix), subServer)
+	}
+	return nil
+}
+
+// unaryInterceptor rejects unary calls to disabled sub-servers.
+func (f *subServerFilter) unaryInterceptor(ctx context.Context,
+	req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	if err := f.checkMethod(info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor rejects streaming calls to disabled sub-servers.
+func (f *subServerFilter) streamInterceptor(srv interface{},
+	stream grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	if err := f.checkMethod(info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, stream)
+}
+
+// chainUnaryInterceptors combines the interceptors into one, each passing
+// the call on to the next in turn.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context,
+				req interface{}) (interface{}, error) {
+
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors combines the interceptors into one, each passing
+// the call on to the next in turn.
+func chainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{},
+				stream grpc.ServerStream) error {
+
+				return interceptor(srv, stream, info, next)
+			}
+		}
+		return chained(srv, stream)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// TestRPCMethodsClassified asserts every method of the rpc server is either
+// one of the core methods, or part of exactly one sub-server, so that new
+// methods can't be left out of the sub-server they belong to.
+func TestRPCMethodsClassified(t *testing.T) {
+	classified := make(map[string]string)
+	classify := func(method, group string) {
+		if other, ok := classified[method]; ok {
+			t.Fatalf("%v is part of both %v and %v", method, other,
+				group)
+		}
+		classified[method] = group
+	}
+	for _, method := range rpcCoreMethods {
+		classify(method, "the core")
+	}
+	for _, subServer := range rpcSubServers {
+		for _, method := range subServer.methods {
+			classify(method, subServer.name)
+		}
+	}
+
+	server := reflect.TypeOf((*lnrpc.LightningServer)(nil)).Elem()
+	methods := make(map[string]struct{}, server.NumMethod())
+	for i := 0; i < server.NumMethod(); i++ {
+		name := server.Method(i).Name
+		methods[name] = struct{}{}
+
+		if _, ok := classified[name]; !ok {
+			t.Fatalf("%v is neither a core method, nor part of a "+
+				"sub-server", name)
+		}
+	}
+
+	for method := range classified {
+		if _, ok := methods[method]; !ok {
+			t.Fatalf("%v isn't a method of the rpc server", method)
+		}
+	}
+}
+
+// TestSubServerFilter asserts only the methods of disabled sub-servers are
+// rejected.
+func TestSubServerFilter(t *testing.T) {
+	if _, err := newSubServerFilter([]string{"unknown"}); err == nil {
+		t.Fatalf("expected unknown sub-server to be refused")
+	}
+
+	f, err := newSubServerFilter([]string{" Router", "invoices"})
+	if err != nil {
+		t.Fatalf("unable to create filter: %v", err)
+	}
+
+	tests := []struct {
+		method  string
+		allowed bool
+	}{
+		{method: "GetInfo", allowed: true},
+		{method: "SendPayment", allowed: true},
+		{method: "AddInvoice", allowed: true},
+		{method: "SendMany", allowed: false},
+		{method: "SignPsbt", allowed: false},
+		{method: "GetBestBlock", allowed: false},
+	}
+	for _, test := range tests {
+		err := f.checkMethod(rpcMethodPrefix + test.method)
+		if test.allowed != (err == nil) {
+			t.Fatalf("%v: expected allowed=%v, got %v", test.method,
+				test.allowed, err)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/chainntfs"
+)
+
+const (
+	// DefaultMinFeeRate is the default fee rate, in satoshis per kB, paid
+	// to sweep inputs whose deadline is still far off.
+	DefaultMinFeeRate = btcutil.Amount(10000)
+
+	// DefaultMaxFeeRate is the default fee rate, in satoshis per kB, paid
+	// to sweep inputs whose deadline has been reached, unless their budget
+	// allows for less.
+	DefaultMaxFeeRate = btcutil.Amount(200000)
+
+	// DefaultBudgetRatio is the share of its value an input may pay in
+	// fees if it doesn't specify a budget of its own.
+	DefaultBudgetRatio = 0.5
+
+	// dustLimit is the smallest sweep output we'll create. No input may
+	// spend so much on fees that its output falls below it.
+	dustLimit = btcutil.Amount(5460)
+
+	// The sizes used to estimate the size of a sweep transaction, each of
+	// which pays to a single P2PKH output.
+	txOverheadSize  = 10
+	p2pkhOutputSize = 34
+)
+
+var (
+	// ErrDustInput is returned when an input is too small to be swept.
+	ErrDustInput = errors.New("input value too small to sweep")
+
+	// ErrSweeperExiting is returned when an input is passed to a stopped
+	// sweeper.
+	ErrSweeperExiting = errors.New("sweeper exiting")
+)
+
+// ChainView is the access to the chain required to sweep inputs.
+type ChainView interface {
+	// GetBestBlock returns the hash, and height, of the tip of the main
+	// chain.
+	GetBestBlock() (*wire.ShaHash, int32, error)
+
+	// GetBlock returns the block of the passed hash.
+	GetBlock(hash *wire.ShaHash) (*wire.MsgBlock, error)
+
+	// RegisterBlockEpochNotification registers a channel which is sent
+	// each new block as it's connected to the main chain.
+	RegisterBlockEpochNotification(epochChan chan *chainntnfs.BlockEpoch) error
+}
+
+// SweeperCfg houses the resources required by the sweeper.
+type SweeperCfg struct {
+	// Chain is used to track the height of the chain, and detect the
+	// spends of pending inputs.
+	Chain ChainView
+
+	// PublishTransaction broadcasts each sweep transaction, labelled
+	// "sweep".
+	PublishTransaction func(tx *wire.MsgTx, label string) error
+
+	// GenSweepScript returns a new output script of the wallet for a
+	// swept input to be paid to.
+	GenSweepScript func() ([]byte, error)
+
+	// RecordSweep records each of our sweep transactions once confirmed,
+	// along with the fee it paid, the height it confirmed at, and the
+	// number of sweep transactions published for its input.
+	RecordSweep func(sweepTx *wire.MsgTx, fee btcutil.Amount,
+		height int32, attempts uint32) error
+
+	// MinFeeRate is the fee rate, in satoshis per kB, paid to sweep an
+	// input when it's first added.
+	MinFeeRate btcutil.Amount
+
+	// MaxFeeRate is the fee rate, in satoshis per kB, paid to sweep an
+	// input once its deadline is reached, unless its budget allows for
+	// less.
+	MaxFeeRate btcutil.Amount
+}
+
+// Input is an output of ours to be swept back to the wallet, such as the
+// output of an HTLC we're able to claim.
+type Input struct {
+	OutPoint wire.OutPoint
+	Value    btcutil.Amount
+
+	// Size is the serialized size of the input once signed, used to
+	// estimate the fee of its sweep transaction.
+	Size int
+
+	// Deadline is the height the input must be swept by, e.g. the expiry
+	// of an HTLC after which our counterparty may claim it instead.
+	Deadline int32
+
+	// Budget is the most that will be paid in fees to sweep the input. If
+	// zero, the budget is DefaultBudgetRatio of its value. The budget is
+	// capped so the sweep output is never dust.
+	Budget btcutil.Amount
+
+	// SignInput returns the signature script spending the input, at the
+	// passed index of the sweep transaction.
+	SignInput func(tx *wire.MsgTx, idx int) ([]byte, error)
+}
+
+// pendingInput is an input yet to be swept, along with the transaction
+// last published to sweep it.
+type pendingInput struct {
+	*Input
+
+	budget      btcutil.Amount
+	startHeight int32
+	sweepScript []byte
+
+	// sweepTx is the last transaction published to sweep the input,
+	// paying feeRate. As any of those published may confirm, the fee of
+	// each is kept by txid.
+	sweepTx   *wire.MsgTx
+	feeRate   btcutil.Amount
+	published map[wire.ShaHash]btcutil.Amount
+
+	// attempts is the number of sweep transactions published, with
+	// lastErr the error returned by the last attempt.
+	attempts uint32
+	lastErr  error
+}
+
+// PendingSweep describes an input yet to be swept.
+type PendingSweep struct {
+	OutPoint wire.OutPoint
+	Value    btcutil.Amount
+	Deadline int32
+	Budget   btcutil.Amount
+
+	// FeeRate is the fee rate, in satoshis per kB, paid by the last sweep
+	// transaction published, SweepTxid.
+	FeeRate   btcutil.Amount
+	SweepTxid *wire.ShaHash
+
+	// Attempts is the number of sweep transactions published, with
+	// LastError the error returned by the last attempt, if it failed.
+	Attempts  uint32
+	LastError string
+
+	// NextBroadcastHeight is the height at which the input is next swept
+	// at a higher fee rate, or zero if its fee rate is at its maximum.
+	NextBroadcastHeight int32
+}
+
+// txSize returns the estimated size of the input's sweep transaction.
+func (p *pendingInput) txSize() int {
+	return txOverheadSize + p.Size + p2pkhOutputSize
+}
+
+// Sweeper sweeps our outputs back to the wallet, raising the fee paid to
+// sweep each as its deadline approaches. With each new block, an input yet
+// to be swept is swept again at a higher fee rate, up to the rate at which
+// its entire budget is spent, reached at its deadline.
+//
+// Pending inputs aren't persisted, so they must be passed to the sweeper
+// again after a restart.
+//
+// TODO: batch inputs with similar deadlines into a single sweep
+// transaction
+type Sweeper struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *SweeperCfg
+
+	// The mutex guards the fields below.
+	sync.Mutex
+	height  int32
+	pending map[wire.OutPoint]*pendingInput
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSweeper returns a new Sweeper, which must be started before any inputs
+// are passed to it.
+func NewSweeper(cfg *SweeperCfg) *Sweeper {
+	return &Sweeper{
+		cfg:     cfg,
+		pending: make(map[wire.OutPoint]*pendingInput),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start launches the goroutine raising the fees of pending inputs with each
+// new block.
+func (s *Sweeper) Start() error {
+	if !atomic.CompareAndSwapUint32(&s.started, 0, 1) {
+		return nil
+	}
+
+	epochChan := make(chan *chainntnfs.BlockEpoch, 20)
+	if err := s.cfg.Chain.RegisterBlockEpochNotification(epochChan); err != nil {
+		return err
+	}
+
+	_, height, err := s.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	s.height = height
+	s.Unlock()
+
+	s.wg.Add(1)
+	go s.sweepHandler(epochChan)
+
+	return nil
+}
+
+// Stop signals the sweeper to exit, and waits for it to do so.
+func (s *Sweeper) Stop() error {
+	if !atomic.CompareAndSwapUint32(&s.stopped, 0, 1) {
+		return nil
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+
+	return nil
+}
+
+// SweepInput adds the input to be swept by its deadline, publishing its
+// first sweep transaction. Adding an input which is already pending has no
+// effect.
+func (s *Sweeper) SweepInput(input *Input) error {
+	if atomic.LoadUint32(&s.stopped) == 1 {
+		return ErrSweeperExiting
+	}
+	if input.Value <= dustLimit {
+		return ErrDustInput
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.pending[input.OutPoint]; ok {
+		return nil
+	}
+
+	budget := input.Budget
+	if budget == 0 {
+		budget = btcutil.Amount(float64(input.Value) * DefaultBudgetRatio)
+	}
+	if maxBudget := input.Value - dustLimit; budget > maxBudget {
+		budget = maxBudget
+	}
+
+	sweepScript, err := s.cfg.GenSweepScript()
+	if err != nil {
+		return err
+	}
+
+	p := &pendingInput{
+		Input:       input,
+		budget:      budget,
+		startHeight: s.height,
+		sweepScript: sweepScript,
+		published:   make(map[wire.ShaHash]btcutil.Amount),
+	}
+	s.pending[input.OutPoint] = p
+
+	// If the sweep can't be published, it's retried with the next block.
+	if err := s.sweep(p); err != nil {
+		fmt.Printf("unable to sweep %v: %v\n", input.OutPoint, err)
+	}
+
+	return nil
+}
+
+// sweepHandler sweeps each pending input again with each new block.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *Sweeper) sweepHandler(epochChan chan *chainntnfs.BlockEpoch) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case epoch := <-epochChan:
+			if err := s.processBlock(epoch); err != nil {
+				fmt.Printf("unable to process block %v: %v\n",
+					epoch.Height, err)
+			}
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// processBlock removes the inputs spent within the block, then sweeps each
+// of those remaining at the fee rate for the new height.
+func (s *Sweeper) processBlock(epoch *chainntnfs.BlockEpoch) error {
+	block, err := s.cfg.Chain.GetBlock(&epoch.Hash)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.height = epoch.Height
+
+	for _, tx := range block.Transactions {
+		for _, txIn := range tx.TxIn {
+			p, ok := s.pending[txIn.PreviousOutPoint]
+			if !ok {
+				continue
+			}
+			delete(s.pending, txIn.PreviousOutPoint)
+
+			txid := tx.TxSha()
+			fee, ok := p.published[txid]
+			if !ok {
+				fmt.Printf("input %v spent by foreign tx %v\n",
+					p.OutPoint, txid)
+				continue
+			}
+
+			err := s.cfg.RecordSweep(tx, fee, epoch.Height,
+				p.attempts)
+			if err != nil {
+				fmt.Printf("unable to record sweep %v: %v\n",
+					txid, err)
+			}
+		}
+	}
+
+	for _, p := range s.pending {
+		if err := s.sweep(p); err != nil {
+			fmt.Printf("unable to sweep %v: %v\n", p.OutPoint, err)
+		}
+	}
+
+	return nil
+}
+
+// sweep publishes a transaction sweeping the input at the fee rate for the
+// current height, unless the last one published already pays as much.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (s *Sweeper) sweep(p *pendingInput) error {
+	feeRate := s.feeRate(p)
+	if p.sweepTx != nil && p.lastErr == nil && feeRate <= p.feeRate {
+		return nil
+	}
+
+	sweepTx, err := createSweepTx(p, feeRate)
+	if err != nil {
+		return err
+	}
+
+	p.attempts++
+	if err := s.cfg.PublishTransaction(sweepTx, "sweep"); err != nil {
+		p.lastErr = err
+		return err
+	}
+	p.sweepTx, p.feeRate, p.lastErr = sweepTx, feeRate, nil
+	p.published[sweepTx.TxSha()] = p.Value -
+		btcutil.Amount(sweepTx.TxOut[0].Value)
+
+	return nil
+}
+
+// PendingSweeps returns each input yet to be swept.
+func (s *Sweeper) PendingSweeps() []*PendingSweep {
+	s.Lock()
+	defer s.Unlock()
+
+	pending := make([]*PendingSweep, 0, len(s.pending))
+	for _, p := range s.pending {
+		sweep := &PendingSweep{
+			OutPoint: p.OutPoint,
+			Value:    p.Value,
+			Deadline: p.Deadline,
+			Budget:   p.budget,
+			FeeRate:  p.feeRate,
+			Attempts: p.attempts,
+		}
+		if p.sweepTx != nil {
+			txid := p.sweepTx.TxSha()
+			sweep.SweepTxid = &txid
+		}
+		if p.lastErr != nil {
+			sweep.LastError = p.lastErr.Error()
+		}
+
+		// Failed attempts are retried with the next block, as are
+		// those whose fee rate is yet to reach its maximum.
+		nextHeight := s.height + 1
+		if p.sweepTx == nil || p.lastErr != nil ||
+			s.feeRateAt(p, nextHeight) > p.feeRate {
+
+			sweep.NextBroadcastHeight = nextHeight
+		}
+
+		pending = append(pending, sweep)
+	}
+
+	return pending
+}
+
+// feeRate returns the fee rate to sweep the input at the current height,
+// capped by the input's budget.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (s *Sweeper) feeRate(p *pendingInput) btcutil.Amount {
+	return s.feeRateAt(p, s.height)
+}
+
+// feeRateAt returns the fee rate to sweep the input at the passed height,
+// capped by the input's budget.
+func (s *Sweeper) feeRateAt(p *pendingInput, height int32) btcutil.Amount {
+	maxRate := s.cfg.MaxFeeRate
+	budgetRate := p.budget * 1000 / btcutil.Amount(p.txSize())
+	if budgetRate < maxRate {
+		maxRate = budgetRate
+	}
+
+	return deadlineFeeRate(s.cfg.MinFeeRate, maxRate, p.startHeight,
+		p.Deadline, height)
+}
+
+// deadlineFeeRate returns the fee rate at the passed height, rising linearly
+// from minRate at the start height, to maxRate at the deadline. The rate
+// never exceeds maxRate, even if minRate does.
+func deadlineFeeRate(minRate, maxRate btcutil.Amount, start, deadline,
+	height int32) btcutil.Amount {
+
+	if minRate >= maxRate || height >= deadline || start >= deadline {
+		return maxRate
+	}
+	if height <= start {
+		return minRate
+	}
+
+	elapsed := btcutil.Amount(height - start)
+	total := btcutil.Amount(deadline - start)
+	return minRate + (maxRate-minRate)*elapsed/total
+}
+
+// createSweepTx returns a signed transaction sweeping the input to its
+// sweep script, paying the passed fee rate.
+func createSweepTx(p *pendingInput, feeRate btcutil.Amount) (*wire.MsgTx, error) {
+	fee := feeRate * btcutil.Amount(p.txSize()) / 1000
+	if fee > p.budget {
+		fee = p.budget
+	}
+
+	sweepTx := wire.NewMsgTx()
+	sweepTx.AddTxIn(wire.NewTxIn(&p.OutPoint, nil))
+	sweepTx.AddTxOut(wire.NewTxOut(int64(p.Value-fee), p.sweepScript))
+
+	sigScript, err := p.SignInput(sweepTx, 0)
+	if err != nil {
+		return nil, err
+	}
+	sweepTx.TxIn[0].SignatureScript = sigScript
+
+	return sweepTx, nil
+}
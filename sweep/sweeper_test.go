@@ -0,0 +1,198 @@
+package sweep
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/chainntfs"
+)
+
+// mockChain is an in-memory ChainView, whose blocks are passed to the
+// sweeper by the test.
+type mockChain struct {
+	sync.Mutex
+	height int32
+	blocks map[wire.ShaHash]*wire.MsgBlock
+}
+
+// addBlock adds a block of the passed transactions, returning its epoch.
+func (c *mockChain) addBlock(txs ...*wire.MsgTx) *chainntnfs.BlockEpoch {
+	c.Lock()
+	defer c.Unlock()
+
+	c.height++
+	block := wire.NewMsgBlock(&wire.BlockHeader{Nonce: uint32(c.height)})
+	for _, tx := range txs {
+		block.AddTransaction(tx)
+	}
+
+	hash := block.BlockSha()
+	c.blocks[hash] = block
+	return &chainntnfs.BlockEpoch{Hash: hash, Height: c.height}
+}
+
+func (c *mockChain) GetBestBlock() (*wire.ShaHash, int32, error) {
+	c.Lock()
+	defer c.Unlock()
+	return &wire.ShaHash{}, c.height, nil
+}
+
+func (c *mockChain) GetBlock(hash *wire.ShaHash) (*wire.MsgBlock, error) {
+	c.Lock()
+	defer c.Unlock()
+	block, ok := c.blocks[*hash]
+	if !ok {
+		return nil, fmt.Errorf("unknown block %v", hash)
+	}
+	return block, nil
+}
+
+func (c *mockChain) RegisterBlockEpochNotification(chan *chainntnfs.BlockEpoch) error {
+	return nil
+}
+
+func TestDeadlineFeeRate(t *testing.T) {
+	tests := []struct {
+		height int32
+		rate   btcutil.Amount
+	}{
+		{90, 1000},
+		{100, 1000},
+		{105, 3000},
+		{110, 5000},
+		{120, 5000},
+	}
+	for _, test := range tests {
+		rate := deadlineFeeRate(1000, 5000, 100, 110, test.height)
+		if rate != test.rate {
+			t.Fatalf("expected rate %v at height %v, got %v",
+				test.rate, test.height, rate)
+		}
+	}
+
+	// The maximum rate is never exceeded.
+	if rate := deadlineFeeRate(6000, 5000, 100, 110, 100); rate != 5000 {
+		t.Fatalf("expected rate capped at 5000, got %v", rate)
+	}
+}
+
+// TestSweeperDeadline ensures an input is swept at a rising fee rate as its
+// deadline approaches, without ever exceeding its budget, until swept.
+func TestSweeperDeadline(t *testing.T) {
+	chain := &mockChain{
+		height: 100,
+		blocks: make(map[wire.ShaHash]*wire.MsgBlock),
+	}
+
+	type record struct {
+		txid     wire.ShaHash
+		fee      btcutil.Amount
+		height   int32
+		attempts uint32
+	}
+	var (
+		published []*wire.MsgTx
+		records   []record
+	)
+	s := NewSweeper(&SweeperCfg{
+		Chain: chain,
+		PublishTransaction: func(tx *wire.MsgTx, label string) error {
+			published = append(published, tx)
+			return nil
+		},
+		GenSweepScript: func() ([]byte, error) {
+			return []byte{0x00}, nil
+		},
+		RecordSweep: func(tx *wire.MsgTx, fee btcutil.Amount,
+			height int32, attempts uint32) error {
+
+			records = append(records, record{tx.TxSha(), fee,
+				height, attempts})
+			return nil
+		},
+		MinFeeRate: 1000,
+		MaxFeeRate: 1000000,
+	})
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start sweeper: %v", err)
+	}
+	defer s.Stop()
+
+	input := &Input{
+		OutPoint: wire.OutPoint{Index: 1},
+		Value:    100000,
+		Size:     206,
+		Deadline: 104,
+		Budget:   20000,
+		SignInput: func(*wire.MsgTx, int) ([]byte, error) {
+			return []byte{0x01}, nil
+		},
+	}
+	if err := s.SweepInput(input); err != nil {
+		t.Fatalf("unable to sweep input: %v", err)
+	}
+	if err := s.SweepInput(&Input{Value: dustLimit}); err != ErrDustInput {
+		t.Fatalf("expected ErrDustInput, got %v", err)
+	}
+
+	// The fee rises with each block, reaching the budget at the deadline,
+	// beyond which it's never raised.
+	for i := 0; i < 5; i++ {
+		if err := s.processBlock(chain.addBlock()); err != nil {
+			t.Fatalf("unable to process block: %v", err)
+		}
+	}
+	if len(published) != 5 {
+		t.Fatalf("expected 5 sweep attempts, got %v", len(published))
+	}
+	var lastFee btcutil.Amount
+	for i, tx := range published {
+		fee := input.Value - btcutil.Amount(tx.TxOut[0].Value)
+		if fee <= lastFee {
+			t.Fatalf("fee of attempt %v didn't rise: %v", i, fee)
+		}
+		if fee > input.Budget {
+			t.Fatalf("fee %v exceeds budget", fee)
+		}
+		lastFee = fee
+	}
+	if lastFee != input.Budget {
+		t.Fatalf("expected the full budget spent at the deadline, "+
+			"spent %v", lastFee)
+	}
+
+	// With the fee at the budget, the input won't be swept again.
+	pending := s.PendingSweeps()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending sweep, got %v", len(pending))
+	}
+	if pending[0].Attempts != 5 || pending[0].NextBroadcastHeight != 0 ||
+		*pending[0].SweepTxid != published[4].TxSha() {
+
+		t.Fatalf("unexpected pending sweep: %v", pending[0])
+	}
+
+	// Any of the sweep transactions may confirm, with the fee it paid
+	// being recorded, after which the input is no longer pending.
+	confirmed := published[1]
+	epoch := chain.addBlock(confirmed)
+	if err := s.processBlock(epoch); err != nil {
+		t.Fatalf("unable to process block: %v", err)
+	}
+	if len(s.PendingSweeps()) != 0 {
+		t.Fatalf("swept input still pending")
+	}
+	expected := record{
+		txid:     confirmed.TxSha(),
+		fee:      input.Value - btcutil.Amount(confirmed.TxOut[0].Value),
+		height:   epoch.Height,
+		attempts: 5,
+	}
+	if len(records) != 1 || records[0] != expected {
+		t.Fatalf("expected sweep %v recorded, got %v", expected,
+			records)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// defaultWalletPass is the private passphrase the wallet is created
+	// with, and unlocked by, should none be given.
+	defaultWalletPass = "hello"
+
+	// walletPassEnv is the environment variable the private passphrase of
+	// the wallet may be passed in, rather than on the command line.
+	walletPassEnv = "LND_WALLET_PASS"
+)
+
+// errDefaultWalletPass is returned when encrypting the channel database
+// under the default private passphrase, which anyone may unlock it with.
+var errDefaultWalletPass = errors.New("the channel database may not be " +
+	"encrypted under the default wallet passphrase, pass another with " +
+	"-walletpass, or " + walletPassEnv)
+
+// walletPassphrase returns the private passphrase of the wallet, taken from
+// the flag if set, otherwise the environment. If neither is set, then the
+// passphrase is prompted for should the channel database be encrypted, as
+// it protects the key of the database, or else the default is used. An
+// encrypted channel database refuses the default passphrase.
+func walletPassphrase(flagPass string, encrypt bool,
+	prompt func() ([]byte, error)) ([]byte, error) {
+
+	var pass []byte
+	switch {
+	case flagPass != "":
+		pass = []byte(flagPass)
+
+	case os.Getenv(walletPassEnv) != "":
+		pass = []byte(os.Getenv(walletPassEnv))
+
+	case encrypt:
+		var err error
+		pass, err = prompt()
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return []byte(defaultWalletPass), nil
+	}
+
+	if encrypt && bytes.Equal(pass, []byte(defaultWalletPass)) {
+		return nil, errDefaultWalletPass
+	}
+
+	return pass, nil
+}
+
+// promptWalletPassphrase prompts for the private passphrase of the wallet
+// on the terminal, until a non-empty one is entered.
+func promptWalletPassphrase() ([]byte, error) {
+	for {
+		fmt.Print("Enter the private passphrase of your wallet: ")
+		pass, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return nil, err
+		}
+		fmt.Print("\n")
+
+		pass = bytes.TrimSpace(pass)
+		if len(pass) != 0 {
+			return pass, nil
+		}
+	}
+}
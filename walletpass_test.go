@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// TestWalletPassphrase asserts the private passphrase of the wallet is taken
+// from the flag, the environment, or the prompt, in that order, and that an
+// encrypted channel database refuses the default.
+func TestWalletPassphrase(t *testing.T) {
+	errPrompt := errors.New("no terminal")
+
+	tests := []struct {
+		name     string
+		flagPass string
+		envPass  string
+		encrypt  bool
+		prompted string
+
+		pass string
+		err  error
+	}{
+		{
+			name: "default",
+			pass: defaultWalletPass,
+		},
+		{
+			name:     "flag",
+			flagPass: "flag",
+			envPass:  "env",
+			encrypt:  true,
+			pass:     "flag",
+		},
+		{
+			name:    "environment",
+			envPass: "env",
+			encrypt: true,
+			pass:    "env",
+		},
+		{
+			name:     "prompted when encrypted",
+			encrypt:  true,
+			prompted: "prompted",
+			pass:     "prompted",
+		},
+		{
+			name:    "prompt fails",
+			encrypt: true,
+			err:     errPrompt,
+		},
+		{
+			name:     "default refused when encrypted",
+			flagPass: defaultWalletPass,
+			encrypt:  true,
+			err:      errDefaultWalletPass,
+		},
+		{
+			name:     "default prompted when encrypted",
+			encrypt:  true,
+			prompted: defaultWalletPass,
+			err:      errDefaultWalletPass,
+		},
+	}
+
+	defer os.Unsetenv(walletPassEnv)
+	for _, test := range tests {
+		os.Setenv(walletPassEnv, test.envPass)
+
+		prompt := func() ([]byte, error) {
+			if test.prompted == "" {
+				return nil, errPrompt
+			}
+			return []byte(test.prompted), nil
+		}
+		pass, err := walletPassphrase(test.flagPass, test.encrypt,
+			prompt)
+		if err != test.err {
+			t.Fatalf("%v: expected error %v, instead %v", test.name,
+				test.err, err)
+		}
+		if !bytes.Equal(pass, []byte(test.pass)) {
+			t.Fatalf("%v: expected passphrase %q, instead %q",
+				test.name, test.pass, pass)
+		}
+	}
+}